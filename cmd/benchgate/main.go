@@ -0,0 +1,225 @@
+// benchgate compares hot-path benchmarks of the working tree against a base revision and fails
+// when any of them regressed, so performance regressions are caught before merge.
+//
+// Usage:
+//
+//	go run ./cmd/benchgate [-base main] [-count 6] [-threshold 10] [packages...]
+//
+// The base revision is checked out into a temporary git worktree. Results are compared with
+// benchstat, which must be on PATH:
+//
+//	go install golang.org/x/perf/cmd/benchstat@latest
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// hotPath lists the packages holding the hot-path benchmarks
+var hotPath = []string{
+	"./internal/api/jsonrpcx",
+	"./internal/api/handlers",
+	"./internal/domain/trainer",
+	"./internal/app/service",
+	"./pkg/autorouter",
+	"./pkg/sse",
+}
+
+// gatedUnits are the benchmark units where an increase is a regression
+var gatedUnits = map[string]bool{
+	"sec/op":    true,
+	"B/op":      true,
+	"allocs/op": true,
+}
+
+// regression is a statistically significant slowdown reported by benchstat
+type regression struct {
+	Benchmark string
+	Unit      string
+	Delta     float64 // Percent
+}
+
+func main() {
+	base := flag.String("base", "main", "git revision to compare against")
+	count := flag.Int("count", 6, "runs per benchmark; benchstat needs several to judge significance")
+	benchtime := flag.String("benchtime", "", "go test -benchtime for each run")
+	threshold := flag.Float64("threshold", 10, "percent increase tolerated before failing")
+	out := flag.String("out", "", "directory to keep base.txt and head.txt in (default: a temporary directory)")
+	flag.Parse()
+
+	packages := flag.Args()
+	if len(packages) == 0 {
+		packages = hotPath
+	}
+
+	regressions, err := run(*base, *count, *benchtime, *threshold, *out, packages)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "benchgate: %v\n", err)
+		os.Exit(2)
+	}
+
+	if len(regressions) > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d benchmark(s) regressed by more than %.1f%% against %s:\n", len(regressions), *threshold, *base)
+		for _, r := range regressions {
+			fmt.Fprintf(os.Stderr, "  %s %s +%.2f%%\n", r.Benchmark, r.Unit, r.Delta)
+		}
+		os.Exit(1)
+	}
+
+	fmt.Printf("\nNo benchmark regressed by more than %.1f%% against %s\n", *threshold, *base)
+}
+
+// run benchmarks the base revision and the working tree and returns the regressions
+func run(base string, count int, benchtime string, threshold float64, out string, packages []string) ([]regression, error) {
+	if _, err := exec.LookPath("benchstat"); err != nil {
+		return nil, fmt.Errorf("benchstat not found, install it with: go install golang.org/x/perf/cmd/benchstat@latest")
+	}
+
+	if out == "" {
+		dir, err := os.MkdirTemp("", "benchgate-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+		out = dir
+	} else if err := os.MkdirAll(out, 0o755); err != nil {
+		return nil, err
+	}
+
+	worktree, err := os.MkdirTemp("", "benchgate-base-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(worktree)
+
+	if err := command("", os.Stderr, "git", "worktree", "add", "--detach", worktree, base); err != nil {
+		return nil, fmt.Errorf("failed to check out %s: %w", base, err)
+	}
+	defer func() {
+		_ = command("", os.Stderr, "git", "worktree", "remove", "--force", worktree)
+	}()
+
+	baseFile := filepath.Join(out, "base.txt")
+	headFile := filepath.Join(out, "head.txt")
+
+	fmt.Fprintf(os.Stderr, "Benchmarking %s...\n", base)
+	if err := bench(worktree, baseFile, count, benchtime, packages); err != nil {
+		return nil, fmt.Errorf("failed to benchmark %s: %w", base, err)
+	}
+
+	fmt.Fprintln(os.Stderr, "Benchmarking the working tree...")
+	if err := bench("", headFile, count, benchtime, packages); err != nil {
+		return nil, fmt.Errorf("failed to benchmark the working tree: %w", err)
+	}
+
+	if err := command("", os.Stdout, "benchstat", baseFile, headFile); err != nil {
+		return nil, err
+	}
+
+	var report bytes.Buffer
+	if err := command("", &report, "benchstat", "-format", "csv", baseFile, headFile); err != nil {
+		return nil, err
+	}
+
+	return parseRegressions(&report, threshold)
+}
+
+// bench runs the benchmarks of the packages in dir and writes the results to file. Packages
+// missing in dir, such as ones added since the base revision, are skipped.
+func bench(dir, file string, count int, benchtime string, packages []string) error {
+	var present []string
+	for _, pkg := range packages {
+		if _, err := os.Stat(filepath.Join(dir, pkg)); err == nil {
+			present = append(present, pkg)
+		}
+	}
+
+	args := []string{"test", "-run", "^$", "-bench", ".", "-benchmem", "-count", strconv.Itoa(count)}
+	if benchtime != "" {
+		args = append(args, "-benchtime", benchtime)
+	}
+	args = append(args, present...)
+
+	f, err := os.Create(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return command(dir, f, "go", args...)
+}
+
+// command runs a program with its output going to stdout and its errors to stderr
+func command(dir string, stdout io.Writer, name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Dir = dir
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// parseRegressions reads benchstat's CSV report. Each table starts with a header row naming its
+// unit and a "vs base" column, which holds the change of each benchmark or "~" when the change
+// isn't significant.
+func parseRegressions(report io.Reader, threshold float64) ([]regression, error) {
+	reader := csv.NewReader(report)
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	var regressions []regression
+	unit, vsBase := "", -1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read benchstat report: %w", err)
+		}
+		if len(record) < 2 {
+			continue // goos, pkg and other context lines
+		}
+
+		if record[0] == "" {
+			for i, column := range record {
+				if column == "vs base" {
+					unit, vsBase = record[1], i
+				}
+			}
+			continue
+		}
+
+		if record[0] == "geomean" || vsBase < 0 || vsBase >= len(record) || !gatedUnits[unit] {
+			continue
+		}
+
+		delta, ok := parsePercent(record[vsBase])
+		if ok && delta > threshold {
+			regressions = append(regressions, regression{Benchmark: record[0], Unit: unit, Delta: delta})
+		}
+	}
+
+	return regressions, nil
+}
+
+// parsePercent parses a change such as "+12.34%"; "~" and other values aren't changes
+func parsePercent(value string) (float64, bool) {
+	if !strings.HasSuffix(value, "%") {
+		return 0, false
+	}
+
+	delta, err := strconv.ParseFloat(strings.TrimSuffix(value, "%"), 64)
+	if err != nil {
+		return 0, false
+	}
+	return delta, true
+}
@@ -0,0 +1,661 @@
+// lifectl is the operator tool for maintenance tasks against a LIFE deployment's Redis and
+// Postgres.
+//
+// Usage:
+//
+//	lifectl pii-rotate         Re-encrypt emails and device IDs with the first key in CRYPTO_PII_KEYS
+//	lifectl retention-report   Report what the retention rules would purge, without deleting
+//	lifectl trainer-list-index Add trainers stored before the list index existed to it
+//	lifectl migrate            Apply pending Postgres schema migrations
+//	lifectl migrate-status     List Postgres schema migrations not applied yet
+//	lifectl storage-copy       Copy accounts and activity timelines from Redis to Postgres
+//	lifectl archive-replay     Read archived game events back from object storage
+//	lifectl world-inspect      Print world tiles
+//	lifectl world-paint        Set the terrain of a region of the world
+//	lifectl world-place        Place or remove a static entity
+//	lifectl world-validate     Check that every walkable tile can be reached
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill-redisstream/pkg/redisstream"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/app/service"
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/world"
+	"github.com/danghamo/life/internal/storage/migrations"
+	"github.com/danghamo/life/pkg/config"
+	"github.com/danghamo/life/pkg/eventarchive"
+	"github.com/danghamo/life/pkg/fieldcrypt"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/objstore"
+	"github.com/danghamo/life/pkg/pgsqlx"
+	"github.com/danghamo/life/pkg/redisx"
+	"github.com/danghamo/life/pkg/requestid"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+const usage = `Usage: lifectl <command>
+
+Commands:
+  pii-rotate   Re-encrypt personal data with the newest key in CRYPTO_PII_KEYS.
+               Run after adding a key or enabling encryption; remove the old key
+               only after it completes.
+  retention-report
+               Print what the RETENTION_* rules would purge right now. Nothing
+               is deleted.
+  trainer-list-index
+               Add every trainer to the index trainer.List pages through. Run
+               once after upgrading; trainers saved since are indexed already.
+  migrate      Apply pending schema migrations to STORAGE_POSTGRES_URL.
+  migrate-status
+               Print the schema migrations not applied yet.
+  storage-copy Copy accounts and account activity timelines of every tenant
+               from Redis to Postgres, then set STORAGE_DRIVER=postgres. Stop
+               servers first; records already copied are skipped.
+  archive-replay <stream> <from> <to> [target-stream]
+               Print the default game's events of <stream> archived between
+               <from> and <to> (RFC 3339) as JSON lines, or add them to
+               <target-stream> in Redis for consumers to process again.
+  world-inspect <x> <y> [<x2> <y2>]
+               Print the tile at <x> <y> as JSON, or the region up to <x2> <y2>
+               as a map: . grassland, f forest, ~ water, ^ mountain, and
+               T tree, R rock, # fence, S sign.
+  world-paint <terrain> <x1> <y1> <x2> <y2>
+               Set the terrain of every tile in the region to grassland,
+               forest, water or mountain.
+  world-place <kind> <x> <y>
+               Place a tree, rock, fence or sign on a tile; none removes it.
+  world-validate
+               Check that trainers can walk between every two walkable tiles.
+               Edits warn when they break this. Running servers reload the
+               chunks an edit changed.
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	cfg, log, err := config.Initialize()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to initialize: %v\n", err)
+		os.Exit(1)
+	}
+	defer func() {
+		_ = log.Sync()
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	switch os.Args[1] {
+	case "pii-rotate":
+		err = rotatePII(ctx, cfg, log)
+	case "retention-report":
+		err = retentionReport(ctx, cfg, log)
+	case "trainer-list-index":
+		err = rebuildTrainerListIndex(ctx, log)
+	case "migrate":
+		err = migrate(ctx, cfg, log)
+	case "migrate-status":
+		err = migrateStatus(ctx, cfg, log)
+	case "storage-copy":
+		err = storageCopy(ctx, cfg, log)
+	case "archive-replay":
+		err = archiveReplay(ctx, cfg, log, os.Args[2:])
+	case "world-inspect":
+		err = worldInspect(ctx, log, os.Args[2:])
+	case "world-paint":
+		err = worldPaint(ctx, log, os.Args[2:])
+	case "world-place":
+		err = worldPlace(ctx, log, os.Args[2:])
+	case "world-validate":
+		err = worldValidate(ctx, log)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+
+	if err != nil {
+		log.Fatal("Command failed", zap.String("command", os.Args[1]), zap.Error(err))
+	}
+}
+
+// rotatePII rewrites every record holding personal data with the current key
+func rotatePII(ctx context.Context, cfg *config.Config, log *logger.Logger) error {
+	cipher, err := fieldcrypt.New(fieldcrypt.Config{
+		Keys:     cfg.Crypto.PIIKeys,
+		IndexKey: cfg.Crypto.PIIIndexKey,
+	})
+	if err != nil {
+		return err
+	}
+	if !cipher.Enabled() {
+		return fmt.Errorf("CRYPTO_PII_KEYS is empty, nothing to encrypt with")
+	}
+
+	redisClient, err := connectRedis(log)
+	if err != nil {
+		return err
+	}
+	defer redisClient.Close()
+
+	stats, err := account.RotatePII(ctx, redisClient.Client, cipher)
+	log.Info("PII rotation finished",
+		zap.Int("accounts", stats.Accounts),
+		zap.Int("emails", stats.Emails),
+		zap.Bool("complete", err == nil))
+	if err != nil || !cfg.Storage.UsesPostgres() {
+		return err
+	}
+
+	pool, err := connectPostgres(ctx, cfg, log)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	stats, err = account.RotatePostgresPII(ctx, pool, cipher)
+	log.Info("Postgres PII rotation finished",
+		zap.Int("accounts", stats.Accounts),
+		zap.Bool("complete", err == nil))
+
+	return err
+}
+
+// retentionReport runs the retention rules as a dry run and prints the results as JSON
+func retentionReport(ctx context.Context, cfg *config.Config, log *logger.Logger) error {
+	redisClient, err := connectRedis(log)
+	if err != nil {
+		return err
+	}
+	defer redisClient.Close()
+
+	activities := account.NewRedisActivityRepository(redisClient.Client)
+	if cfg.Storage.UsesPostgres() {
+		pool, err := connectPostgres(ctx, cfg, log)
+		if err != nil {
+			return err
+		}
+		defer pool.Close()
+		activities = account.NewPostgresActivityRepository(pool)
+	}
+
+	retention := service.NewRetentionService(log, redisClient.Client, activities, service.RetentionPolicy{
+		EventStreams: cfg.Retention.EventStreams,
+		AuditLog:     cfg.Retention.AuditLog,
+	})
+
+	results, err := retention.Run(ctx, true)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(results)
+}
+
+// rebuildTrainerListIndex indexes every stored trainer for paged listing
+func rebuildTrainerListIndex(ctx context.Context, log *logger.Logger) error {
+	redisClient, err := connectRedis(log)
+	if err != nil {
+		return err
+	}
+	defer redisClient.Close()
+
+	count, err := trainer.RebuildListIndex(ctx, redisClient.Client)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Trainer list index rebuilt", zap.Int("trainers", count))
+	return nil
+}
+
+// migrate applies the pending schema migrations
+func migrate(ctx context.Context, cfg *config.Config, log *logger.Logger) error {
+	pool, err := connectPostgres(ctx, cfg, log)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	applied, err := pgsqlx.Migrate(ctx, pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Postgres schema migrated", zap.Strings("applied", applied))
+	return nil
+}
+
+// migrateStatus prints the versions of the migrations not applied yet
+func migrateStatus(ctx context.Context, cfg *config.Config, log *logger.Logger) error {
+	pool, err := connectPostgres(ctx, cfg, log)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	pending, err := pgsqlx.Pending(ctx, pool, migrations.FS)
+	if err != nil {
+		return err
+	}
+
+	versions := make([]string, 0, len(pending))
+	for _, migration := range pending {
+		versions = append(versions, migration.Version)
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(map[string][]string{"pending": versions})
+}
+
+// storageCopy copies every tenant's durable data from Redis to Postgres
+func storageCopy(ctx context.Context, cfg *config.Config, log *logger.Logger) error {
+	cipher, err := fieldcrypt.New(fieldcrypt.Config{
+		Keys:     cfg.Crypto.PIIKeys,
+		IndexKey: cfg.Crypto.PIIIndexKey,
+	})
+	if err != nil {
+		return err
+	}
+
+	redisClient, err := connectRedis(log)
+	if err != nil {
+		return err
+	}
+	defer redisClient.Close()
+
+	pool, err := connectPostgres(ctx, cfg, log)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	if _, err := pgsqlx.Migrate(ctx, pool, migrations.FS); err != nil {
+		return err
+	}
+
+	// Each tenant's keys are read through its prefix, as the server does
+	tenants := []*tenant.Tenant{{ID: tenant.Default}}
+	tenantIDs := []tenant.ID{tenant.Default}
+	for _, t := range cfg.Tenants {
+		tenants = append(tenants, &tenant.Tenant{ID: tenant.ID(t.ID)})
+		tenantIDs = append(tenantIDs, tenant.ID(t.ID))
+	}
+	if len(tenants) > 1 {
+		redisClient.AddHook(redisx.Namespace(tenantIDs...))
+	}
+
+	for _, t := range tenants {
+		stats, err := account.CopyToPostgres(tenant.WithTenant(ctx, t), redisClient.Client, pool, cipher)
+		log.Info("Storage copy finished",
+			zap.String("tenant", t.ID.String()),
+			zap.Int("accounts", stats.Accounts),
+			zap.Int("accountsSkipped", stats.AccountsSkipped),
+			zap.Int("timelines", stats.Timelines),
+			zap.Int("timelinesSkipped", stats.TimelinesSkipped),
+			zap.Bool("complete", err == nil))
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// archiveReplay reads archived events of a stream, printing them or adding them to a stream
+func archiveReplay(ctx context.Context, cfg *config.Config, log *logger.Logger, args []string) error {
+	if len(args) < 3 {
+		return fmt.Errorf("usage: lifectl archive-replay <stream> <from> <to> [target-stream]")
+	}
+	from, err := time.Parse(time.RFC3339, args[1])
+	if err != nil {
+		return fmt.Errorf("invalid from time: %w", err)
+	}
+	to, err := time.Parse(time.RFC3339, args[2])
+	if err != nil {
+		return fmt.Errorf("invalid to time: %w", err)
+	}
+	if cfg.Archive.Store == "" {
+		return fmt.Errorf("ARCHIVE_STORE is empty, nothing was archived")
+	}
+
+	store, err := objstore.New(objstore.Config{
+		Kind: cfg.Archive.Store,
+		Dir:  cfg.Archive.Dir,
+		S3: objstore.S3Config{
+			Endpoint:  cfg.Archive.S3.Endpoint,
+			Region:    cfg.Archive.S3.Region,
+			Bucket:    cfg.Archive.S3.Bucket,
+			AccessKey: cfg.Archive.S3.AccessKey,
+			SecretKey: cfg.Archive.S3.SecretKey,
+			PathStyle: cfg.Archive.S3.PathStyle,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Without a target the events go to stdout
+	handle := func(event eventarchive.Event) error {
+		return json.NewEncoder(os.Stdout).Encode(event)
+	}
+	if len(args) > 3 {
+		redisClient, err := connectRedis(log)
+		if err != nil {
+			return err
+		}
+		defer redisClient.Close()
+
+		target := args[3]
+		handle = func(event eventarchive.Event) error {
+			// The fields Watermill reads, so the events are consumed as when first published
+			return redisClient.XAdd(ctx, &redis.XAddArgs{
+				Stream: target,
+				Values: map[string]interface{}{
+					"_watermill_message_uuid": event.UUID,
+					"metadata":                event.Metadata,
+					"payload":                 event.Payload,
+				},
+			}).Err()
+		}
+	}
+
+	var replayed int
+	err = eventarchive.Read(ctx, store, cfg.Archive.Prefix, args[0], from, to, func(event eventarchive.Event) error {
+		replayed++
+		return handle(event)
+	})
+	log.Info("Archive replay finished",
+		zap.String("stream", args[0]),
+		zap.Int("events", replayed),
+		zap.Bool("complete", err == nil))
+
+	return err
+}
+
+// worldInspect prints a tile as JSON or a region as a map
+func worldInspect(ctx context.Context, log *logger.Logger, args []string) error {
+	if len(args) != 2 && len(args) != 4 {
+		return fmt.Errorf("usage: lifectl world-inspect <x> <y> [<x2> <y2>]")
+	}
+	coords, err := parseCoordinates(args)
+	if err != nil {
+		return err
+	}
+
+	w, err := loadStoredWorld(ctx, log)
+	if err != nil {
+		return err
+	}
+
+	if len(coords) == 2 {
+		tile, err := w.GetTile(shared.NewPosition(float64(coords[0]), float64(coords[1])))
+		if err != nil {
+			return err
+		}
+
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(map[string]interface{}{
+			"tile":     tile,
+			"walkable": tile.IsWalkable(),
+			"chunk":    world.ChunkOf(coords[0], coords[1]),
+		})
+	}
+
+	region := world.NewRegion(coords[0], coords[1], coords[2], coords[3])
+	for y := region.MinY; y <= region.MaxY; y++ {
+		var line strings.Builder
+		for x := region.MinX; x <= region.MaxX; x++ {
+			tile, err := w.GetTile(shared.NewPosition(float64(x), float64(y)))
+			if err != nil {
+				return err
+			}
+			line.WriteByte(tileGlyph(tile))
+		}
+		fmt.Println(line.String())
+	}
+	return nil
+}
+
+// worldPaint sets the terrain of a region
+func worldPaint(ctx context.Context, log *logger.Logger, args []string) error {
+	if len(args) != 5 {
+		return fmt.Errorf("usage: lifectl world-paint <terrain> <x1> <y1> <x2> <y2>")
+	}
+	coords, err := parseCoordinates(args[1:])
+	if err != nil {
+		return err
+	}
+	terrain := world.TerrainType(args[0])
+	region := world.NewRegion(coords[0], coords[1], coords[2], coords[3])
+
+	var changed int
+	err = editWorld(ctx, log, "paint", region.Chunks(), func(w *world.World) error {
+		changed, err = w.Paint(region, terrain)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info("World painted",
+		zap.String("terrain", terrain.String()),
+		zap.Int("tiles", region.Tiles()),
+		zap.Int("changed", changed))
+	return nil
+}
+
+// worldPlace places a static entity on a tile, or removes it
+func worldPlace(ctx context.Context, log *logger.Logger, args []string) error {
+	if len(args) != 3 {
+		return fmt.Errorf("usage: lifectl world-place <kind> <x> <y>")
+	}
+	coords, err := parseCoordinates(args[1:])
+	if err != nil {
+		return err
+	}
+	position := shared.NewPosition(float64(coords[0]), float64(coords[1]))
+	chunks := []world.Chunk{world.ChunkOf(coords[0], coords[1])}
+
+	if args[0] == "none" {
+		err = editWorld(ctx, log, "remove_static", chunks, func(w *world.World) error {
+			return w.RemoveStatic(position)
+		})
+		if err != nil {
+			return err
+		}
+
+		log.Info("Static entity removed", zap.Int("x", coords[0]), zap.Int("y", coords[1]))
+		return nil
+	}
+
+	var static *world.StaticEntity
+	err = editWorld(ctx, log, "place_static", chunks, func(w *world.World) error {
+		static, err = w.PlaceStatic(position, world.StaticKind(args[0]))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Info("Static entity placed",
+		zap.String("id", static.ID.String()),
+		zap.String("kind", string(static.Kind)),
+		zap.Int("x", coords[0]),
+		zap.Int("y", coords[1]))
+	return nil
+}
+
+// worldValidate prints the world's connectivity, failing when walkable tiles can't be reached
+func worldValidate(ctx context.Context, log *logger.Logger) error {
+	w, err := loadStoredWorld(ctx, log)
+	if err != nil {
+		return err
+	}
+
+	connectivity := w.Connectivity()
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(connectivity); err != nil {
+		return err
+	}
+
+	if !connectivity.Connected() {
+		return fmt.Errorf("%d walkable tiles can't be reached from the largest region", connectivity.Walkable-connectivity.Largest)
+	}
+	return nil
+}
+
+// loadStoredWorld reads the world the servers load
+func loadStoredWorld(ctx context.Context, log *logger.Logger) (*world.World, error) {
+	redisClient, err := connectRedis(log)
+	if err != nil {
+		return nil, err
+	}
+	defer redisClient.Close()
+
+	w, err := world.NewRedisRepository(redisClient.Client).GetByID(ctx, world.DefaultID)
+	if err != nil {
+		return nil, err
+	}
+	if w == nil {
+		return nil, fmt.Errorf("no world is stored yet, start a server to generate it")
+	}
+	return w, nil
+}
+
+// editWorld applies an edit to the stored world and tells running servers to reload the
+// chunks it touched
+func editWorld(ctx context.Context, log *logger.Logger, change string, chunks []world.Chunk, edit func(*world.World) error) error {
+	redisClient, err := connectRedis(log)
+	if err != nil {
+		return err
+	}
+	defer redisClient.Close()
+
+	var connectivity world.Connectivity
+	err = world.NewRedisRepository(redisClient.Client).FindOneAndUpdate(ctx, world.DefaultID, func(w *world.World) (*world.World, error) {
+		if err := edit(w); err != nil {
+			return nil, err
+		}
+		connectivity = w.Connectivity()
+		return w, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !connectivity.Connected() {
+		log.Warn("Edit left walkable tiles unreachable",
+			zap.Int("regions", connectivity.Regions),
+			zap.Int("unreachable", connectivity.Walkable-connectivity.Largest))
+	}
+
+	// Published to the topic of the server's event bus
+	publisher, err := redisstream.NewPublisher(redisstream.PublisherConfig{Client: redisClient.Client}, watermill.NopLogger{})
+	if err != nil {
+		return err
+	}
+	eventBus, err := cqrs.NewEventBusWithConfig(publisher, cqrs.EventBusConfig{
+		GeneratePublishTopic: func(params cqrs.GenerateEventPublishTopicParams) (string, error) {
+			return fmt.Sprintf("game-events.%s", params.EventName), nil
+		},
+		Marshaler: cqrscommands.JSONMarshaler{Schemas: cqrscommands.DefaultSchemas()},
+	})
+	if err != nil {
+		return err
+	}
+
+	return eventBus.Publish(ctx, &cqrscommands.WorldUpdatedEvent{
+		WorldID:   world.DefaultID.String(),
+		Chunks:    chunks,
+		Change:    change,
+		Timestamp: time.Now(),
+		RequestID: requestid.FromContextOrNew(ctx),
+	})
+}
+
+// parseCoordinates parses tile coordinates
+func parseCoordinates(args []string) ([]int, error) {
+	coords := make([]int, len(args))
+	for i, arg := range args {
+		coord, err := strconv.Atoi(arg)
+		if err != nil {
+			return nil, fmt.Errorf("invalid coordinate %q", arg)
+		}
+		coords[i] = coord
+	}
+	return coords, nil
+}
+
+// tileGlyph returns the map character of a tile
+func tileGlyph(tile *world.Tile) byte {
+	if tile.Static != nil {
+		switch tile.Static.Kind {
+		case world.Tree:
+			return 'T'
+		case world.Rock:
+			return 'R'
+		case world.Fence:
+			return '#'
+		case world.Sign:
+			return 'S'
+		}
+	}
+
+	switch tile.Terrain {
+	case world.Forest:
+		return 'f'
+	case world.Water:
+		return '~'
+	case world.Mountain:
+		return '^'
+	default:
+		return '.'
+	}
+}
+
+// connectPostgres connects to the Postgres of the storage config, even while the server still
+// keeps durable data in Redis
+func connectPostgres(ctx context.Context, cfg *config.Config, log *logger.Logger) (*pgxpool.Pool, error) {
+	if cfg.Storage.PostgresURL == "" {
+		return nil, fmt.Errorf("STORAGE_POSTGRES_URL is empty")
+	}
+
+	return pgsqlx.NewPool(ctx, cfg.Storage.PostgresURL, cfg.Storage.MaxConns, log)
+}
+
+// connectRedis connects to the same Redis as the server
+func connectRedis(log *logger.Logger) (*redisx.Client, error) {
+	redisURL := os.Getenv("REDIS_URL")
+	if redisURL == "" {
+		redisURL = "redis://localhost:6379/0"
+	}
+
+	return redisx.NewClient(redisURL, log)
+}
@@ -29,12 +29,24 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
 	_ "github.com/danghamo/life/docs/api"
 	"github.com/danghamo/life/internal/api"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/app/service"
+	"github.com/danghamo/life/internal/storage/migrations"
+	"github.com/danghamo/life/pkg/captcha"
 	"github.com/danghamo/life/pkg/config"
+	"github.com/danghamo/life/pkg/fieldcrypt"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/mailer"
+	"github.com/danghamo/life/pkg/objstore"
+	"github.com/danghamo/life/pkg/pgsqlx"
 	"github.com/danghamo/life/pkg/redisx"
+	"github.com/danghamo/life/pkg/sse"
+	"github.com/danghamo/life/pkg/tenant"
 )
 
 func main() {
@@ -67,13 +79,163 @@ func main() {
 	}
 	defer redisClient.Close()
 
+	// Durable data moves to Postgres with the postgres storage driver
+	var postgres *pgxpool.Pool
+	if cfg.Storage.UsesPostgres() {
+		postgres, err = pgsqlx.NewPool(context.Background(), cfg.Storage.PostgresURL, cfg.Storage.MaxConns, log)
+		if err != nil {
+			log.Fatal("Failed to initialize Postgres pool", zap.Error(err))
+		}
+		defer postgres.Close()
+
+		if cfg.Storage.AutoMigrate {
+			applied, err := pgsqlx.Migrate(context.Background(), postgres, migrations.FS)
+			if err != nil {
+				log.Fatal("Failed to migrate Postgres schema", zap.Error(err))
+			}
+			log.Info("Postgres schema up to date", zap.Strings("applied", applied))
+		}
+	}
+
+	methodTimeouts, err := cfg.Timeouts.MethodTimeouts()
+	if err != nil {
+		log.Fatal("Invalid request timeouts", zap.Error(err))
+	}
+
+	rateLimit, err := rateLimitConfig(cfg.RateLimit)
+	if err != nil {
+		log.Fatal("Invalid rate limits", zap.Error(err))
+	}
+
+	deprecations, err := middleware.ParseDeprecations(cfg.Deprecations.Methods)
+	if err != nil {
+		log.Fatal("Invalid deprecations", zap.Error(err))
+	}
+
+	envBanner := cfg.Server.EnvBanner
+	if envBanner == "" {
+		envBanner = cfg.Server.Environment
+	}
+
 	// Create API server
 	serverConfig := api.ServerConfig{
 		Port:         cfg.Server.Port,
+		GRPCPort:     cfg.Server.GRPCPort,
 		Host:         cfg.Server.Host,
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
+
+		WarmupTimeout: cfg.Server.WarmupTimeout,
+		Timeouts: middleware.TimeoutConfig{
+			Default: cfg.Timeouts.Default,
+			Methods: methodTimeouts,
+		},
+		RateLimit: rateLimit,
+
+		JWTSecret:     cfg.Auth.JWTSecret,
+		JWTExpiration: cfg.Auth.JWTExpiration,
+		OAuth: api.OAuthClients{
+			Google:  oauthClient(cfg.Auth.OAuth.Google),
+			GitHub:  oauthClient(cfg.Auth.OAuth.GitHub),
+			Discord: oauthClient(cfg.Auth.OAuth.Discord),
+		},
+		Captcha: captcha.Config{
+			Provider:  cfg.Auth.Captcha.Provider,
+			Secret:    cfg.Auth.Captcha.Secret,
+			SiteKey:   cfg.Auth.Captcha.SiteKey,
+			VerifyURL: cfg.Auth.Captcha.VerifyURL,
+			Timeout:   cfg.Auth.Captcha.Timeout,
+		},
+
+		EnvBanner:        envBanner,
+		StaticDir:        cfg.Server.StaticDir,
+		Deprecations:     deprecations,
+		AdminUserIDs:     cfg.Admin.UserIDs,
+		ModeratorUserIDs: cfg.Admin.ModeratorIDs,
+		SingleSession:    cfg.Auth.SingleSession,
+		Degradation: middleware.DegradationConfig{
+			Enabled:          cfg.Degraded.Enabled,
+			ProbeInterval:    cfg.Degraded.ProbeInterval,
+			FailureThreshold: cfg.Degraded.FailureThreshold,
+			WALPath:          cfg.Degraded.WALPath,
+			SnapshotTTL:      cfg.Degraded.SnapshotTTL,
+			SnapshotLimit:    cfg.Degraded.SnapshotLimit,
+			SnapshotMethods:  cfg.Degraded.SnapshotMethods,
+		},
+		Replay: sse.ReplayConfig{
+			Size:        cfg.SSE.ReplaySize,
+			TTL:         cfg.SSE.ReplayTTL,
+			SkipMethods: cfg.SSE.ReplaySkipMethods,
+		},
+
+		LootDeliveryMode: cfg.Game.LootDeliveryMode,
+		TaskConcurrency:  cfg.Asynq.Concurrency,
+
+		Movement: service.MovementConfig{
+			Shards:        cfg.Game.MovementShards,
+			SnapshotTicks: cfg.Game.SnapshotTicks,
+			BroadcastRate: cfg.Game.BroadcastRate,
+		},
+
+		InterestChunkSize: cfg.Game.InterestChunkSize,
+		InterestRadius:    cfg.Game.InterestRadius,
+		AFKTimeout:        cfg.Game.AFKTimeout,
+
+		MapWidth:        cfg.Game.MapWidth,
+		MapHeight:       cfg.Game.MapHeight,
+		AnimalSpawnRate: cfg.Game.AnimalSpawnRate,
+		DayLength:       cfg.Game.DayLength,
+
+		Respawn: service.RespawnConfig{
+			MoneyPenalty: cfg.Game.DeathMoneyPenalty,
+			Delay:        cfg.Game.RespawnDelay,
+		},
+
+		InviteBaseURL: cfg.Game.InviteBaseURL,
+
+		Mail: mailer.Config{
+			Host:     cfg.Mail.SMTPHost,
+			Port:     cfg.Mail.SMTPPort,
+			Username: cfg.Mail.SMTPUsername,
+			Password: cfg.Mail.SMTPPassword,
+			From:     cfg.Mail.From,
+		},
+		EmailVerifyURL: cfg.Mail.VerifyURL,
+
+		Retention: service.RetentionPolicy{
+			Interval:     cfg.Retention.Interval,
+			DryRun:       cfg.Retention.DryRun,
+			EventStreams: cfg.Retention.EventStreams,
+			AuditLog:     cfg.Retention.AuditLog,
+			// Event stream entries wait for the archive before being purged
+			KeepUnarchived: cfg.Archive.Store != "",
+		},
+
+		Analytics: service.AnalyticsExportPolicy{
+			Interval:        cfg.Analytics.Interval,
+			PseudonymSecret: cfg.Analytics.PseudonymSecret,
+			Sinks:           analyticsSinks(cfg.Analytics.Sinks),
+		},
+
+		Archive: service.EventArchivePolicy{
+			Interval:       cfg.Archive.Interval,
+			Events:         cfg.Archive.Events,
+			BatchSize:      cfg.Archive.BatchSize,
+			Prefix:         cfg.Archive.Prefix,
+			RedisRetention: cfg.Archive.RedisRetention,
+		},
+		ArchiveStore: archiveStore(cfg.Archive),
+
+		PIIEncryption: fieldcrypt.Config{
+			Keys:     cfg.Crypto.PIIKeys,
+			IndexKey: cfg.Crypto.PIIIndexKey,
+		},
+
+		Branding: branding(cfg.Branding),
+		Tenants:  tenants(cfg.Tenants),
+
+		Postgres: postgres,
 	}
 
 	apiServer, err := api.NewServer(serverConfig, log, redisClient)
@@ -95,6 +257,16 @@ func main() {
 		cancel()
 	}()
 
+	// Reload the settings that can change without dropping connections on SIGHUP
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+
+	go func() {
+		for range hangup {
+			reloadConfig(log, apiServer)
+		}
+	}()
+
 	// Start server
 	if err := apiServer.Start(ctx); err != nil {
 		log.Error("Server error", zap.Error(err))
@@ -103,3 +275,103 @@ func main() {
 
 	log.Info("Server gracefully stopped")
 }
+
+// reloadConfig rereads the configuration and applies the log level and runtime settings to the
+// running server. An invalid configuration is rejected whole, keeping the current one.
+func reloadConfig(log *logger.Logger, apiServer *api.Server) {
+	cfg, err := config.Reload()
+	if err != nil {
+		log.Error("Failed to reload configuration, keeping the current one", zap.Error(err))
+		return
+	}
+
+	rateLimit, err := rateLimitConfig(cfg.RateLimit)
+	if err != nil {
+		log.Error("Invalid rate limits, keeping the current configuration", zap.Error(err))
+		return
+	}
+
+	log.SetLevel(logger.ParseLevel(cfg.Log.Level))
+	apiServer.Reload(api.RuntimeConfig{
+		RateLimit:       rateLimit,
+		AnimalSpawnRate: cfg.Game.AnimalSpawnRate,
+		BroadcastRate:   cfg.Game.BroadcastRate,
+	})
+	log.Info("Configuration reloaded", zap.String("log_level", cfg.Log.Level))
+}
+
+// rateLimitConfig parses the configured rate limits; without limiting every method is unlimited
+func rateLimitConfig(c config.RateLimitConfig) (middleware.RateLimitConfig, error) {
+	if !c.Enabled {
+		return middleware.RateLimitConfig{}, nil
+	}
+	return middleware.ParseRateLimitConfig(c.Default, c.Methods)
+}
+
+// oauthClient converts a configured OAuth app
+func oauthClient(c config.OAuthProviderConfig) api.OAuthClient {
+	return api.OAuthClient{
+		ClientID:     c.ClientID,
+		ClientSecret: c.ClientSecret,
+		RedirectURI:  c.RedirectURI,
+	}
+}
+
+// analyticsSinks converts configured analytics sinks for the export service
+func analyticsSinks(configs []config.AnalyticsSinkConfig) []service.AnalyticsSink {
+	sinks := make([]service.AnalyticsSink, 0, len(configs))
+	for _, c := range configs {
+		sinks = append(sinks, service.AnalyticsSink{
+			Name:         c.Name,
+			Events:       c.Events,
+			SaltRotation: c.SaltRotation,
+			HashFields:   c.HashFields,
+			DropFields:   c.DropFields,
+			MaxLen:       c.MaxLen,
+		})
+	}
+	return sinks
+}
+
+// branding converts configured branding for tenants
+func branding(c config.BrandingConfig) tenant.Branding {
+	return tenant.Branding{
+		Name:         c.Name,
+		LogoURL:      c.LogoURL,
+		PrimaryColor: c.PrimaryColor,
+		SupportEmail: c.SupportEmail,
+	}
+}
+
+// tenants converts configured tenants for the tenant registry
+func tenants(configs []config.TenantConfig) []tenant.Tenant {
+	list := make([]tenant.Tenant, 0, len(configs))
+	for _, c := range configs {
+		list = append(list, tenant.Tenant{
+			ID:       tenant.ID(c.ID),
+			Hosts:    c.Hosts,
+			Branding: branding(c.Branding),
+			Balance: tenant.Balance{
+				LootDeliveryMode: c.Balance.LootDeliveryMode,
+				InviteBaseURL:    c.Balance.InviteBaseURL,
+			},
+		})
+	}
+	return list
+}
+
+// archiveStore converts the configured archive store; an empty kind disables archiving
+func archiveStore(c config.ArchiveConfig) objstore.Config {
+	return objstore.Config{
+		Kind: c.Store,
+		Dir:  c.Dir,
+		S3: objstore.S3Config{
+			Endpoint:  c.S3.Endpoint,
+			Region:    c.S3.Region,
+			Bucket:    c.S3.Bucket,
+			AccessKey: c.S3.AccessKey,
+			SecretKey: c.S3.SecretKey,
+			PathStyle: c.S3.PathStyle,
+		},
+	}
+}
@@ -0,0 +1,8 @@
+package main
+
+// Gameplay plugins compiled into the server. Each module registers itself with
+// pkg/plugin from its init function, so enabling one only takes a blank import here:
+//
+//	import _ "example.com/lifemods/treasurehunt"
+//
+// and adding its module to go.mod.
@@ -0,0 +1,77 @@
+package grpcapi
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/lifepb"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// accountServer implements lifepb.AccountServiceServer on the account repositories and the
+// account deletion service
+type accountServer struct {
+	lifepb.UnimplementedAccountServiceServer
+	logger      *logger.Logger
+	accounts    account.Repository
+	revocations account.RevocationRepository
+	deletion    AccountDeletionService
+}
+
+// GetAccount returns the sign-in methods linked to a user. Emails, provider user IDs and
+// device IDs are personal data and left out.
+func (s *accountServer) GetAccount(ctx context.Context, req *lifepb.GetAccountRequest) (*lifepb.Account, error) {
+	if err := requireUserID(req.UserId); err != nil {
+		return nil, err
+	}
+
+	linked, err := s.accounts.ListByUserID(ctx, account.UserID(req.UserId))
+	if err != nil {
+		return nil, statusError(s.logger, err, "Failed to get account")
+	}
+	if len(linked) == 0 {
+		return nil, statusError(s.logger, shared.ErrNotFound("Account"), "Failed to get account")
+	}
+	sort.Slice(linked, func(i, j int) bool {
+		return linked[i].CreatedAt.Value().Before(linked[j].CreatedAt.Value())
+	})
+
+	msg := &lifepb.Account{UserId: req.UserId, Linked: make([]*lifepb.LinkedAccount, len(linked))}
+	for i, a := range linked {
+		msg.Linked[i] = &lifepb.LinkedAccount{
+			Id:        a.ID.String(),
+			Provider:  string(a.Provider),
+			CreatedAt: timestamppb.New(a.CreatedAt.Value()),
+		}
+	}
+	return msg, nil
+}
+
+// RevokeTokens signs a user out of every session
+func (s *accountServer) RevokeTokens(ctx context.Context, req *lifepb.RevokeTokensRequest) (*lifepb.RevokeTokensResponse, error) {
+	if err := requireUserID(req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := s.revocations.RevokeTokens(ctx, account.UserID(req.UserId), time.Now()); err != nil {
+		return nil, statusError(s.logger, err, "Failed to revoke tokens")
+	}
+	return &lifepb.RevokeTokensResponse{}, nil
+}
+
+// DeleteAccount deletes a user's accounts and schedules their data for purging
+func (s *accountServer) DeleteAccount(ctx context.Context, req *lifepb.DeleteAccountRequest) (*lifepb.DeleteAccountResponse, error) {
+	if err := requireUserID(req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := s.deletion.Delete(ctx, account.UserID(req.UserId)); err != nil {
+		return nil, statusError(s.logger, err, "Failed to delete account")
+	}
+	return &lifepb.DeleteAccountResponse{}, nil
+}
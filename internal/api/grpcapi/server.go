@@ -0,0 +1,184 @@
+// Package grpcapi serves the game to other backend services, such as analytics and
+// matchmaking, over gRPC. Its services share the domain layer and services behind the JSON-RPC
+// API and authenticate calls with the same tokens.
+package grpcapi
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/samber/oops"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/admin"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/world"
+	"github.com/danghamo/life/pkg/lifepb"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+// AdminService interface for operational interventions on players
+type AdminService interface {
+	OnlinePlayers(ctx context.Context) ([]admin.OnlinePlayer, int, error)
+	Kick(ctx context.Context, adminID, userID, reason string) error
+	Teleport(ctx context.Context, adminID, userID string, position shared.Position) (*trainer.Trainer, error)
+	Grant(ctx context.Context, adminID, userID string, money int, items []admin.GrantItem) (*trainer.Trainer, error)
+	Stats(ctx context.Context) (*admin.ServerStats, error)
+}
+
+// WorldClock interface for reading the game time and weather
+type WorldClock interface {
+	Now(ctx context.Context) (*world.ClockReading, error)
+}
+
+// ChunkSource interface for reading the tiles of world chunks
+type ChunkSource interface {
+	GetChunk(cx, cy int) (*world.ChunkTiles, error)
+}
+
+// AccountDeletionService interface for deleting accounts
+type AccountDeletionService interface {
+	Delete(ctx context.Context, userID account.UserID) error
+}
+
+// Services are what the gRPC services are built on
+type Services struct {
+	Trainers    trainer.Repository
+	Admin       AdminService
+	Clock       WorldClock
+	Chunks      ChunkSource
+	Accounts    account.Repository
+	Revocations account.RevocationRepository
+	Deletion    AccountDeletionService
+}
+
+// methodRoles are the roles tokens need to call each method; methods not listed need the
+// admin role. They follow the /admin/v1/ API's, which moderators may read and act on
+// players through.
+var methodRoles = map[string]account.Role{
+	lifepb.TrainerService_GetTrainer_FullMethodName:        account.RoleModerator,
+	lifepb.TrainerService_ListOnlinePlayers_FullMethodName: account.RoleModerator,
+	lifepb.TrainerService_Teleport_FullMethodName:          account.RoleModerator,
+	lifepb.TrainerService_Kick_FullMethodName:              account.RoleModerator,
+	lifepb.WorldService_GetClock_FullMethodName:            account.RolePlayer,
+	lifepb.WorldService_GetChunk_FullMethodName:            account.RolePlayer,
+	lifepb.WorldService_GetStats_FullMethodName:            account.RoleModerator,
+}
+
+// MethodRole returns the role a token needs to call a gRPC method
+func MethodRole(fullMethod string) account.Role {
+	if role, ok := methodRoles[fullMethod]; ok {
+		return role
+	}
+	return account.RoleAdmin
+}
+
+// NewServer creates a gRPC server with the trainer, world and account services registered.
+// Calls are logged, authenticated by auth and checked against MethodRole.
+func NewServer(logger *logger.Logger, auth *middleware.AuthMiddleware, services Services) *grpc.Server {
+	log := logger.WithComponent("grpc")
+
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(
+		logCalls(log),
+		auth.UnaryServerInterceptor(),
+		auth.UnaryRoleInterceptor(MethodRole),
+	))
+	lifepb.RegisterTrainerServiceServer(server, &trainerServer{logger: log, trainers: services.Trainers, admin: services.Admin})
+	lifepb.RegisterWorldServiceServer(server, &worldServer{logger: log, clock: services.Clock, chunks: services.Chunks, admin: services.Admin})
+	lifepb.RegisterAccountServiceServer(server, &accountServer{logger: log, accounts: services.Accounts, revocations: services.Revocations, deletion: services.Deletion})
+	return server
+}
+
+// logCalls assigns every call a request ID, taken from its x-request-id metadata when valid,
+// logs the call with its outcome and recovers from panics in handlers
+func logCalls(log *logger.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		var id string
+		if values := md.Get(requestid.Header); len(values) > 0 && requestid.Valid(values[0]) {
+			id = values[0]
+		} else {
+			id = requestid.New()
+		}
+		ctx = requestid.With(ctx, id)
+		callLog := log.WithRequestID(id)
+
+		start := time.Now()
+		defer func() {
+			if p := recover(); p != nil {
+				callLog.Error("gRPC handler panic",
+					zap.Any("error", p),
+					zap.String("method", info.FullMethod))
+				err = status.Error(codes.Internal, "Internal server error")
+			}
+
+			callLog.Info("gRPC call",
+				zap.String("method", info.FullMethod),
+				zap.String("code", status.Code(err).String()),
+				zap.Duration("duration", time.Since(start)))
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// statusError maps domain errors to a gRPC status carrying an ErrorInfo detail with the
+// domain code and reason, e.g. TRAINER_DEAD. Other errors are internal and their message is
+// not exposed.
+func statusError(log *logger.Logger, err error, internalMessage string) error {
+	code, ok := shared.DomainErrorCode(err)
+	if !ok {
+		log.Error(internalMessage, zap.Error(err))
+		return status.Error(codes.Internal, internalMessage)
+	}
+
+	var grpcCode codes.Code
+	switch code {
+	case shared.ErrCodeNotFound, shared.ErrCodeItemNotFound:
+		grpcCode = codes.NotFound
+	case shared.ErrCodeAlreadyExists:
+		grpcCode = codes.AlreadyExists
+	case shared.ErrCodeInvalidInput:
+		grpcCode = codes.InvalidArgument
+	default:
+		grpcCode = codes.FailedPrecondition
+	}
+
+	reason := ""
+	if oopsErr, ok := oops.AsOops(err); ok {
+		reason = oopsErr.Code()
+	}
+	st, detailErr := status.New(grpcCode, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   "life",
+		Metadata: map[string]string{"code": strconv.Itoa(code)},
+	})
+	if detailErr != nil {
+		return status.Error(grpcCode, err.Error())
+	}
+	return st.Err()
+}
+
+// actorID returns the user the call was authenticated as
+func actorID(ctx context.Context) string {
+	userID, _ := middleware.GetUserID(ctx)
+	return userID
+}
+
+// requireUserID checks a request names the user it is about
+func requireUserID(userID string) error {
+	if userID == "" {
+		return status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/world"
+	"github.com/danghamo/life/pkg/lifepb"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+func TestMethodRole(t *testing.T) {
+	assert.Equal(t, account.RolePlayer, MethodRole(lifepb.WorldService_GetClock_FullMethodName))
+	assert.Equal(t, account.RoleModerator, MethodRole(lifepb.TrainerService_Kick_FullMethodName))
+	assert.Equal(t, account.RoleAdmin, MethodRole(lifepb.TrainerService_Grant_FullMethodName))
+	assert.Equal(t, account.RoleAdmin, MethodRole(lifepb.AccountService_DeleteAccount_FullMethodName))
+	assert.Equal(t, account.RoleAdmin, MethodRole("/life.v1.Unknown/Method"), "unlisted methods need the admin role")
+}
+
+func TestStatusError(t *testing.T) {
+	log := logger.GetGlobalLogger()
+
+	err := statusError(log, shared.ErrNotFound("Trainer"), "Failed")
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	err = statusError(log, shared.NewDomainError(shared.ErrCodeTrainerDead, "Trainer is dead"), "Failed")
+	st := status.Convert(err)
+	assert.Equal(t, codes.FailedPrecondition, st.Code())
+	require.Len(t, st.Details(), 1)
+	info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+	require.True(t, ok)
+	assert.Equal(t, "TRAINER_DEAD", info.Reason)
+
+	err = statusError(log, errors.New("redis: connection refused"), "Failed to get trainer")
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Equal(t, "Failed to get trainer", status.Convert(err).Message(), "internal errors are not exposed")
+}
+
+type fakeChunks struct{}
+
+func (fakeChunks) GetChunk(cx, cy int) (*world.ChunkTiles, error) {
+	return &world.ChunkTiles{
+		Chunk:   world.Chunk{X: cx, Y: cy},
+		Size:    2,
+		Terrain: [][]world.TerrainType{{world.Grassland, world.Forest}, {world.Water, world.Grassland}},
+	}, nil
+}
+
+func TestWorldServer_GetChunk(t *testing.T) {
+	s := &worldServer{logger: logger.GetGlobalLogger(), chunks: fakeChunks{}}
+
+	chunk, err := s.GetChunk(context.Background(), &lifepb.GetChunkRequest{X: 1, Y: 2})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), chunk.X)
+	assert.Equal(t, int32(2), chunk.Y)
+	assert.Equal(t, []string{"grassland", "forest", "water", "grassland"}, chunk.Terrain)
+}
@@ -0,0 +1,130 @@
+package grpcapi
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/danghamo/life/internal/domain/admin"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/lifepb"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// trainerServer implements lifepb.TrainerServiceServer on the trainer repository and the
+// admin service
+type trainerServer struct {
+	lifepb.UnimplementedTrainerServiceServer
+	logger   *logger.Logger
+	trainers trainer.Repository
+	admin    AdminService
+}
+
+// GetTrainer returns a trainer
+func (s *trainerServer) GetTrainer(ctx context.Context, req *lifepb.GetTrainerRequest) (*lifepb.Trainer, error) {
+	if err := requireUserID(req.UserId); err != nil {
+		return nil, err
+	}
+
+	t, err := s.trainers.GetByID(ctx, trainer.UserID(req.UserId))
+	if err != nil {
+		return nil, statusError(s.logger, err, "Failed to get trainer")
+	}
+	if t == nil {
+		return nil, statusError(s.logger, shared.ErrNotFound("Trainer"), "Failed to get trainer")
+	}
+	return trainerMessage(t), nil
+}
+
+// ListOnlinePlayers returns the players online on any server
+func (s *trainerServer) ListOnlinePlayers(ctx context.Context, req *lifepb.ListOnlinePlayersRequest) (*lifepb.ListOnlinePlayersResponse, error) {
+	players, total, err := s.admin.OnlinePlayers(ctx)
+	if err != nil {
+		return nil, statusError(s.logger, err, "Failed to list online players")
+	}
+
+	resp := &lifepb.ListOnlinePlayersResponse{
+		Players: make([]*lifepb.OnlinePlayer, len(players)),
+		Total:   int32(total),
+	}
+	for i, p := range players {
+		resp.Players[i] = &lifepb.OnlinePlayer{
+			UserId:   p.UserID,
+			Nickname: p.Nickname,
+			Level:    int32(p.Level),
+			Position: positionMessage(p.Position),
+			IsMoving: p.IsMoving,
+		}
+	}
+	return resp, nil
+}
+
+// Teleport moves a trainer to a walkable position
+func (s *trainerServer) Teleport(ctx context.Context, req *lifepb.TeleportRequest) (*lifepb.Trainer, error) {
+	if err := requireUserID(req.UserId); err != nil {
+		return nil, err
+	}
+
+	position := shared.Position{X: req.GetPosition().GetX(), Y: req.GetPosition().GetY()}
+	t, err := s.admin.Teleport(ctx, actorID(ctx), req.UserId, position)
+	if err != nil {
+		return nil, statusError(s.logger, err, "Failed to teleport trainer")
+	}
+	return trainerMessage(t), nil
+}
+
+// Grant gives a trainer money and items
+func (s *trainerServer) Grant(ctx context.Context, req *lifepb.GrantRequest) (*lifepb.Trainer, error) {
+	if err := requireUserID(req.UserId); err != nil {
+		return nil, err
+	}
+
+	items := make([]admin.GrantItem, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = admin.GrantItem{ItemType: item.ItemType, Name: item.Name, Quantity: int(item.Quantity)}
+	}
+
+	t, err := s.admin.Grant(ctx, actorID(ctx), req.UserId, int(req.Money), items)
+	if err != nil {
+		return nil, statusError(s.logger, err, "Failed to grant")
+	}
+	return trainerMessage(t), nil
+}
+
+// Kick signs a player out everywhere
+func (s *trainerServer) Kick(ctx context.Context, req *lifepb.KickRequest) (*lifepb.KickResponse, error) {
+	if err := requireUserID(req.UserId); err != nil {
+		return nil, err
+	}
+
+	if err := s.admin.Kick(ctx, actorID(ctx), req.UserId, req.Reason); err != nil {
+		return nil, statusError(s.logger, err, "Failed to kick player")
+	}
+	return &lifepb.KickResponse{}, nil
+}
+
+// trainerMessage converts a trainer to its protobuf message
+func trainerMessage(t *trainer.Trainer) *lifepb.Trainer {
+	msg := &lifepb.Trainer{
+		UserId:          t.ID.String(),
+		Nickname:        t.Nickname,
+		Level:           int32(t.Level.Value()),
+		Experience:      int64(t.Experience.Current()),
+		TotalExperience: int64(t.Experience.Total()),
+		Hp:              int32(t.Condition.HP),
+		Position:        positionMessage(t.Position),
+		Money:           int64(t.Money.Amount()),
+		CreatedAt:       timestamppb.New(t.CreatedAt.Value()),
+		UpdatedAt:       timestamppb.New(t.UpdatedAt.Value()),
+	}
+	if t.Death != nil {
+		msg.RespawnAt = timestamppb.New(t.Death.RespawnAt)
+	}
+	return msg
+}
+
+// positionMessage converts a position to its protobuf message
+func positionMessage(p shared.Position) *lifepb.Position {
+	return &lifepb.Position{X: p.X, Y: p.Y}
+}
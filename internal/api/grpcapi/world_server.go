@@ -0,0 +1,75 @@
+package grpcapi
+
+import (
+	"context"
+
+	"github.com/danghamo/life/pkg/lifepb"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// worldServer implements lifepb.WorldServiceServer on the world clock, the world's chunks and
+// the admin service
+type worldServer struct {
+	lifepb.UnimplementedWorldServiceServer
+	logger *logger.Logger
+	clock  WorldClock
+	chunks ChunkSource
+	admin  AdminService
+}
+
+// GetClock returns the game time and weather
+func (s *worldServer) GetClock(ctx context.Context, req *lifepb.GetClockRequest) (*lifepb.Clock, error) {
+	reading, err := s.clock.Now(ctx)
+	if err != nil {
+		return nil, statusError(s.logger, err, "Failed to read world clock")
+	}
+
+	return &lifepb.Clock{
+		Minute:           reading.Minute,
+		Day:              reading.Day,
+		Hour:             int32(reading.Hour),
+		MinuteOfHour:     int32(reading.MinuteOfHour),
+		Phase:            string(reading.Phase),
+		Weather:          string(reading.Weather),
+		DayLengthSeconds: reading.DayLengthSeconds,
+	}, nil
+}
+
+// GetChunk returns the tiles of a chunk
+func (s *worldServer) GetChunk(ctx context.Context, req *lifepb.GetChunkRequest) (*lifepb.Chunk, error) {
+	tiles, err := s.chunks.GetChunk(int(req.X), int(req.Y))
+	if err != nil {
+		return nil, statusError(s.logger, err, "Failed to get chunk")
+	}
+
+	chunk := &lifepb.Chunk{
+		X:       int32(tiles.X),
+		Y:       int32(tiles.Y),
+		Size:    int32(tiles.Size),
+		Terrain: make([]string, 0, tiles.Size*tiles.Size),
+		Statics: make(map[string]*lifepb.StaticEntity, len(tiles.Statics)),
+	}
+	for _, row := range tiles.Terrain {
+		for _, terrain := range row {
+			chunk.Terrain = append(chunk.Terrain, string(terrain))
+		}
+	}
+	for key, static := range tiles.Statics {
+		chunk.Statics[key] = &lifepb.StaticEntity{Id: static.ID.String(), Kind: string(static.Kind)}
+	}
+	return chunk, nil
+}
+
+// GetStats returns the connection counters of the server answering
+func (s *worldServer) GetStats(ctx context.Context, req *lifepb.GetStatsRequest) (*lifepb.ServerStats, error) {
+	stats, err := s.admin.Stats(ctx)
+	if err != nil {
+		return nil, statusError(s.logger, err, "Failed to get server stats")
+	}
+
+	return &lifepb.ServerStats{
+		SseClients:       int32(stats.SSEClients),
+		WebsocketClients: int32(stats.WebSocketClients),
+		OnlinePlayers:    int32(stats.OnlinePlayers),
+	}, nil
+}
@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/accessibility"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// AccessibilityService interface for players' accessibility settings
+type AccessibilityService interface {
+	Get(ctx context.Context, accountID trainer.UserID) (accessibility.Settings, error)
+	Update(ctx context.Context, accountID trainer.UserID, settings accessibility.Settings) (accessibility.Settings, error)
+}
+
+// AccessibilityHandler handles accessibility settings HTTP requests with JSON-RPC 2.0 format
+type AccessibilityHandler struct {
+	logger               *logger.Logger
+	accessibilityService AccessibilityService
+}
+
+// NewAccessibilityHandler creates a new accessibility handler
+func NewAccessibilityHandler(logger *logger.Logger, accessibilityService AccessibilityService) *AccessibilityHandler {
+	return &AccessibilityHandler{
+		logger:               logger.WithComponent("accessibility-handler"),
+		accessibilityService: accessibilityService,
+	}
+}
+
+// Request parameter structures
+type AccessibilityUpdateRequest struct {
+	Settings accessibility.Settings `json:"settings"`
+}
+
+// Response structures for Swagger documentation
+type AccessibilitySettingsResponse struct {
+	Settings accessibility.Settings `json:"settings"`
+}
+
+// HandleGet handles POST /api/v1/accessibility.Get
+// @Summary Get accessibility settings
+// @Description Get your accessibility settings, shared by all your characters: the rate positions of moving trainers are sent at (full or reduced), the palette your trainers' colors come from (standard or high_contrast) and the aim assistance level (0 to 3)
+// @Tags accessibility
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[AccessibilitySettingsResponse] "Settings"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/accessibility.Get [post]
+func (h *AccessibilityHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	accountID, req, ok := h.parseAccessibilityRequest(r)
+	if !ok {
+		return
+	}
+
+	settings, err := h.accessibilityService.Get(r.Context(), trainer.UserID(accountID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get accessibility settings",
+			zap.String("accountId", accountID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to get settings")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, AccessibilitySettingsResponse{Settings: settings})
+}
+
+// HandleUpdate handles POST /api/v1/accessibility.Update
+// @Summary Change accessibility settings
+// @Description Replace your accessibility settings. On the reduced broadcast rate the positions of moving trainers arrive four times a second rather than with every frame; stops are always sent. Choosing a palette moves the colors of all your characters into it. Aim assistance lets your shots pass further wide of targets and still hit.
+// @Tags accessibility
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[AccessibilityUpdateRequest] true "JSON-RPC request with AccessibilityUpdateRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[AccessibilitySettingsResponse] "Settings updated"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Unknown rate or palette, or aim assistance out of range (-32602)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/accessibility.Update [post]
+func (h *AccessibilityHandler) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	accountID, req, ok := h.parseAccessibilityRequest(r)
+	if !ok {
+		return
+	}
+
+	var params AccessibilityUpdateRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	settings, err := h.accessibilityService.Update(r.Context(), trainer.UserID(accountID), params.Settings)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to update accessibility settings",
+			zap.String("accountId", accountID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to update settings")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, AccessibilitySettingsResponse{Settings: settings})
+}
+
+// parseAccessibilityRequest reads the caller's account and the JSON-RPC request, answering
+// invalid requests itself
+func (h *AccessibilityHandler) parseAccessibilityRequest(r *http.Request) (string, *jsonrpcx.Request, bool) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return "", nil, false
+	}
+
+	// Settings belong to the account, whichever character is played
+	accountID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return "", nil, false
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return "", nil, false
+	}
+
+	return accountID, req, true
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Get handles getting accessibility settings (autorouter compatible)
+func (h *AccessibilityHandler) Get(w http.ResponseWriter, r *http.Request) {
+	h.HandleGet(w, r)
+}
+
+// Update handles changing accessibility settings (autorouter compatible)
+func (h *AccessibilityHandler) Update(w http.ResponseWriter, r *http.Request) {
+	h.HandleUpdate(w, r)
+}
@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// AccountDeletionService interface for deleting a player's account and game data
+type AccountDeletionService interface {
+	Delete(ctx context.Context, userID account.UserID) error
+}
+
+// AccountHandler handles account lifecycle HTTP requests with JSON-RPC 2.0 format. Its
+// methods are served under the auth. prefix next to AuthHandler's.
+type AccountHandler struct {
+	logger          *logger.Logger
+	deletionService AccountDeletionService
+}
+
+// NewAccountHandler creates a new account handler
+func NewAccountHandler(logger *logger.Logger, deletionService AccountDeletionService) *AccountHandler {
+	return &AccountHandler{
+		logger:          logger.WithComponent("account-handler"),
+		deletionService: deletionService,
+	}
+}
+
+// DeleteAccountRequest represents an account deletion request
+type DeleteAccountRequest struct {
+	Confirm bool `json:"confirm" validate:"required"` // Must be true; guards against accidental calls
+}
+
+// DeleteAccountResponse represents an accepted account deletion
+type DeleteAccountResponse struct {
+	Deleted bool `json:"deleted"`
+}
+
+// HandleDeleteAccount handles POST /api/v1/auth.DeleteAccount
+// @Summary Delete account
+// @Description Permanently delete the player's account: every linked sign-in method, the trainer, owned animals, equipment, vault, stats, social, referral and login history. All issued tokens stop working immediately; game data is purged in the background.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[DeleteAccountRequest] true "JSON-RPC request with DeleteAccountRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[DeleteAccountResponse] "Account deleted"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Deletion not confirmed"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/auth.DeleteAccount [post]
+func (h *AccountHandler) HandleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params DeleteAccountRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	if err := h.deletionService.Delete(r.Context(), account.UserID(userID)); err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to delete account",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to delete account")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, DeleteAccountResponse{Deleted: true})
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// DeleteAccount handles account deletion (autorouter compatible)
+func (h *AccountHandler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	h.HandleDeleteAccount(w, r)
+}
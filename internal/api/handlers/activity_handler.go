@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// ActivityService interface for reading a player's account activity timeline
+type ActivityService interface {
+	List(ctx context.Context, userID string, limit int) ([]*account.Activity, error)
+}
+
+// ActivityHandler handles account activity HTTP requests with JSON-RPC 2.0 format. Its
+// methods are served under the auth. prefix next to AuthHandler's.
+type ActivityHandler struct {
+	logger          *logger.Logger
+	activityService ActivityService
+}
+
+// NewActivityHandler creates a new activity handler
+func NewActivityHandler(logger *logger.Logger, activityService ActivityService) *ActivityHandler {
+	return &ActivityHandler{
+		logger:          logger.WithComponent("activity-handler"),
+		activityService: activityService,
+	}
+}
+
+// ActivityLogRequest represents an activity timeline request
+type ActivityLogRequest struct {
+	Limit int `json:"limit,omitempty"` // Defaults to and is capped at the 100 kept entries
+}
+
+// ActivityLogResponse represents the player's recent account activity, newest first
+type ActivityLogResponse struct {
+	Activities []*account.Activity `json:"activities"`
+}
+
+// HandleActivityLog handles POST /api/v1/auth.ActivityLog
+// @Summary Get account activity
+// @Description Get recent logins, login challenges, paired devices, linked and unlinked providers and email changes on the player's account, so they can spot activity that wasn't theirs
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[ActivityLogRequest] true "JSON-RPC request with ActivityLogRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[ActivityLogResponse] "Recent account activity"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/auth.ActivityLog [post]
+func (h *ActivityHandler) HandleActivityLog(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params ActivityLogRequest
+	if len(req.Params) > 0 {
+		if !jsonrpcx.BindParams(r, req, &params) {
+			return
+		}
+	}
+
+	activities, err := h.activityService.List(r.Context(), userID, params.Limit)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list account activity",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to get account activity")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, ActivityLogResponse{Activities: activities})
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// ActivityLog handles account activity retrieval (autorouter compatible)
+func (h *ActivityHandler) ActivityLog(w http.ResponseWriter, r *http.Request) {
+	h.HandleActivityLog(w, r)
+}
@@ -0,0 +1,695 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/admin"
+	"github.com/danghamo/life/internal/domain/liveops"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/world"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// WorldEditor interface for live edits of the stored world
+type WorldEditor interface {
+	EditTiles(ctx context.Context, adminID string, edits []world.TileEdit) (*world.EditResult, error)
+}
+
+// LiveOpsService interface for live-ops event scripts
+type LiveOpsService interface {
+	UploadScript(ctx context.Context, adminID, name, source string) (*liveops.Script, error)
+	ListScripts(ctx context.Context) ([]*liveops.Script, error)
+	DeleteScript(ctx context.Context, adminID, name string) error
+	RunScript(ctx context.Context, adminID, name string, dryRun bool) (*liveops.Run, error)
+}
+
+// AdminService interface for operational interventions on players and the world
+type AdminService interface {
+	OnlinePlayers(ctx context.Context) ([]admin.OnlinePlayer, int, error)
+	Kick(ctx context.Context, adminID, userID, reason string) error
+	Teleport(ctx context.Context, adminID, userID string, position shared.Position) (*trainer.Trainer, error)
+	Grant(ctx context.Context, adminID, userID string, money int, items []admin.GrantItem) (*trainer.Trainer, error)
+	GrantGems(ctx context.Context, adminID, userID string, gems int) (int, error)
+	DespawnAnimal(ctx context.Context, adminID, animalID string) error
+	Stats(ctx context.Context) (*admin.ServerStats, error)
+}
+
+// moderatorMethods are the admin methods moderators may call; the others need the admin role
+var moderatorMethods = map[string]bool{
+	"OnlinePlayers": true,
+	"Kick":          true,
+	"Teleport":      true,
+	"Stats":         true,
+}
+
+// AdminMethodRole returns the role a token needs to call an admin method
+func AdminMethodRole(methodName string) account.Role {
+	if moderatorMethods[methodName] {
+		return account.RoleModerator
+	}
+	return account.RoleAdmin
+}
+
+// AdminHandler handles admin HTTP requests with JSON-RPC 2.0 format under /admin/v1/; only
+// tokens with the role AdminMethodRole names reach each method
+type AdminHandler struct {
+	logger       *logger.Logger
+	worldEditor  WorldEditor
+	liveOps      LiveOpsService
+	adminService AdminService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(logger *logger.Logger, worldEditor WorldEditor, liveOps LiveOpsService, adminService AdminService) *AdminHandler {
+	return &AdminHandler{
+		logger:       logger.WithComponent("admin-handler"),
+		worldEditor:  worldEditor,
+		liveOps:      liveOps,
+		adminService: adminService,
+	}
+}
+
+// Request parameter structures
+type EditTilesRequest struct {
+	Edits []world.TileEdit `json:"edits"`
+}
+
+type UploadScriptRequest struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+}
+
+type ScriptNameRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+type RunScriptRequest struct {
+	Name   string `json:"name" validate:"required"`
+	DryRun bool   `json:"dry_run"`
+}
+
+type KickPlayerRequest struct {
+	UserID string `json:"user_id" validate:"required"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type TeleportRequest struct {
+	UserID string  `json:"user_id" validate:"required"`
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+}
+
+type GrantRequest struct {
+	UserID string            `json:"user_id" validate:"required"`
+	Money  int               `json:"money,omitempty"`
+	Items  []admin.GrantItem `json:"items,omitempty"`
+}
+
+type GrantGemsRequest struct {
+	UserID string `json:"user_id" validate:"required"` // The user or any of their characters
+	Gems   int    `json:"gems"`
+}
+
+type DespawnAnimalRequest struct {
+	AnimalID string `json:"animal_id" validate:"required"`
+}
+
+// Response structures for Swagger documentation
+type EditTilesResponse struct {
+	*world.EditResult
+}
+
+type UploadScriptResponse struct {
+	Script *liveops.Script `json:"script"`
+}
+
+type ListScriptsResponse struct {
+	Scripts []*liveops.Script `json:"scripts"`
+}
+
+type DeleteScriptResponse struct {
+	Success bool `json:"success"`
+}
+
+type RunScriptResponse struct {
+	Run *liveops.Run `json:"run"`
+}
+
+type OnlinePlayersResponse struct {
+	Players []admin.OnlinePlayer `json:"players"`
+	Total   int                  `json:"total"` // Online players, including those beyond the listed 1000
+}
+
+type KickPlayerResponse struct {
+	Success bool `json:"success"`
+}
+
+type TeleportResponse struct {
+	Position shared.Position `json:"position"`
+}
+
+type GrantResponse struct {
+	Money     int `json:"money"`
+	UsedSlots int `json:"used_slots"`
+}
+
+type GrantGemsResponse struct {
+	Gems int `json:"gems"` // The user's new balance
+}
+
+type DespawnAnimalResponse struct {
+	Success bool `json:"success"`
+}
+
+type StatsResponse struct {
+	*admin.ServerStats
+}
+
+// HandleEditTiles handles POST /admin/v1/admin.EditTiles
+// @Summary Edit world tiles
+// @Description Change the terrain and static entities (tree, rock, fence, sign; none removes them) of regions of the world, e.g. to open gates or block areas during events. Edits apply in order and together cover at most 4096 tiles. Every server reloads the changed chunks, and players who can see them are sent world.chunks_invalidated. The response reports whether every walkable tile can still be reached.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[EditTilesRequest] true "JSON-RPC request with EditTilesRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[EditTilesResponse] "Tiles edited"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid edits (-32602) or not an admin (-32006)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/v1/admin.EditTiles [post]
+func (h *AdminHandler) HandleEditTiles(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params EditTilesRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	result, err := h.worldEditor.EditTiles(r.Context(), userID, params.Edits)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to edit world tiles",
+			zap.String("userId", userID),
+			zap.Int("edits", len(params.Edits)),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to edit world tiles")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, EditTilesResponse{EditResult: result})
+}
+
+// HandleUploadScript handles POST /admin/v1/admin.UploadScript
+// @Summary Upload a live-ops script
+// @Description Store a Starlark event script of up to 64 KiB, replacing the script with the same name. Scripts can call grant_item(nickname, item_type, name, quantity=1), spawn_animal(animal_type, x, y, level=1), notify(message, nicknames=None), set_spawn_table(rate=0, min_level=0, max_level=0, weights=None, hours=24) and reset_spawn_table(), and print output. Scripts that don't compile are rejected.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[UploadScriptRequest] true "JSON-RPC request with UploadScriptRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[UploadScriptResponse] "Script stored"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid name or source, or the script doesn't compile (-32602), or not an admin (-32006)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/v1/admin.UploadScript [post]
+func (h *AdminHandler) HandleUploadScript(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseAdminRequest(r)
+	if !ok {
+		return
+	}
+
+	var params UploadScriptRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	script, err := h.liveOps.UploadScript(r.Context(), userID, params.Name, params.Source)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to upload live-ops script",
+			zap.String("userId", userID),
+			zap.String("script", params.Name),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to upload script")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, UploadScriptResponse{Script: script})
+}
+
+// HandleListScripts handles POST /admin/v1/admin.ListScripts
+// @Summary List live-ops scripts
+// @Description List the stored event scripts with their source and last run.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[ListScriptsResponse] "Stored scripts"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Not an admin (-32006)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/v1/admin.ListScripts [post]
+func (h *AdminHandler) HandleListScripts(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseAdminRequest(r)
+	if !ok {
+		return
+	}
+
+	scripts, err := h.liveOps.ListScripts(r.Context())
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list live-ops scripts",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to list scripts")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, ListScriptsResponse{Scripts: scripts})
+}
+
+// HandleDeleteScript handles POST /admin/v1/admin.DeleteScript
+// @Summary Delete a live-ops script
+// @Description Remove a stored event script. Changes its runs made stay.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[ScriptNameRequest] true "JSON-RPC request with ScriptNameRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[DeleteScriptResponse] "Script deleted"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Script not found (-32004) or not an admin (-32006)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/v1/admin.DeleteScript [post]
+func (h *AdminHandler) HandleDeleteScript(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseAdminRequest(r)
+	if !ok {
+		return
+	}
+
+	var params ScriptNameRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	if err := h.liveOps.DeleteScript(r.Context(), userID, params.Name); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to delete live-ops script",
+			zap.String("userId", userID),
+			zap.String("script", params.Name),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to delete script")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, DeleteScriptResponse{Success: true})
+}
+
+// HandleRunScript handles POST /admin/v1/admin.RunScript
+// @Summary Run a live-ops script
+// @Description Run a stored event script in the sandbox, which stops it after 1,000,000 steps, 10 seconds or 1000 changes. A dry run checks every call without changing anything. The run lists the changes made, the printed output and the error if the script failed; changes made before a failure stay.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[RunScriptRequest] true "JSON-RPC request with RunScriptRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[RunScriptResponse] "Script run, possibly with an error"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Script not found (-32004) or not an admin (-32006)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/v1/admin.RunScript [post]
+func (h *AdminHandler) HandleRunScript(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseAdminRequest(r)
+	if !ok {
+		return
+	}
+
+	var params RunScriptRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	run, err := h.liveOps.RunScript(r.Context(), userID, params.Name, params.DryRun)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to run live-ops script",
+			zap.String("userId", userID),
+			zap.String("script", params.Name),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to run script")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, RunScriptResponse{Run: run})
+}
+
+// HandleOnlinePlayers handles POST /admin/v1/admin.OnlinePlayers
+// @Summary List online players
+// @Description List the players connected to any server, sorted by nickname, with their level and current position. At most 1000 players are listed; total counts all of them.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[OnlinePlayersResponse] "Online players"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Not an admin (-32006)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/v1/admin.OnlinePlayers [post]
+func (h *AdminHandler) HandleOnlinePlayers(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseAdminRequest(r)
+	if !ok {
+		return
+	}
+
+	players, total, err := h.adminService.OnlinePlayers(r.Context())
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list online players",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to list online players")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, OnlinePlayersResponse{Players: players, Total: total})
+}
+
+// HandleKick handles POST /admin/v1/admin.Kick
+// @Summary Kick a player
+// @Description Sign a player out everywhere: their tokens are revoked and their SSE and WebSocket connections on every server are closed, so they have to sign in again.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[KickPlayerRequest] true "JSON-RPC request with KickPlayerRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[KickPlayerResponse] "Player kicked"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Trainer not found (-32004) or not an admin (-32006)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/v1/admin.Kick [post]
+func (h *AdminHandler) HandleKick(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseAdminRequest(r)
+	if !ok {
+		return
+	}
+
+	var params KickPlayerRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	if err := h.adminService.Kick(r.Context(), userID, params.UserID, params.Reason); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to kick player",
+			zap.String("userId", userID),
+			zap.String("targetUserId", params.UserID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to kick player")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, KickPlayerResponse{Success: true})
+}
+
+// HandleTeleport handles POST /admin/v1/admin.Teleport
+// @Summary Teleport a trainer
+// @Description Put a trainer on a walkable position and stop it there. Players nearby see it stop at the new position.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[TeleportRequest] true "JSON-RPC request with TeleportRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[TeleportResponse] "Trainer teleported"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Position not walkable (-32602), trainer not found (-32004) or not an admin (-32006)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/v1/admin.Teleport [post]
+func (h *AdminHandler) HandleTeleport(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseAdminRequest(r)
+	if !ok {
+		return
+	}
+
+	var params TeleportRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	moved, err := h.adminService.Teleport(r.Context(), userID, params.UserID, shared.Position{X: params.X, Y: params.Y})
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to teleport trainer",
+			zap.String("userId", userID),
+			zap.String("targetUserId", params.UserID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to teleport trainer")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, TeleportResponse{Position: moved.Position})
+}
+
+// HandleGrant handles POST /admin/v1/admin.Grant
+// @Summary Grant money and items
+// @Description Add money and item stacks (up to 1000 items each) to a trainer in one update; nothing is granted if any item is invalid or the inventory is full. The player is sent trainer.granted.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[GrantRequest] true "JSON-RPC request with GrantRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[GrantResponse] "Granted; the trainer's new balance and used inventory slots"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid grant or inventory full (-32602), trainer not found (-32004) or not an admin (-32006)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/v1/admin.Grant [post]
+func (h *AdminHandler) HandleGrant(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseAdminRequest(r)
+	if !ok {
+		return
+	}
+
+	var params GrantRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	updated, err := h.adminService.Grant(r.Context(), userID, params.UserID, params.Money, params.Items)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to grant to trainer",
+			zap.String("userId", userID),
+			zap.String("targetUserId", params.UserID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to grant")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, GrantResponse{Money: updated.Money.Amount(), UsedSlots: updated.Inventory.GetUsedSlots()})
+}
+
+// HandleGrantGems handles POST /admin/v1/admin.GrantGems
+// @Summary Grant gems
+// @Description Credit gems, the premium currency spent on character slots, to a user. Gems belong to the user, so any of their characters identifies them.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[GrantGemsRequest] true "JSON-RPC request with GrantGemsRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[GrantGemsResponse] "Granted; the user's new balance"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Gems not positive (-32602) or not an admin (-32006)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/v1/admin.GrantGems [post]
+func (h *AdminHandler) HandleGrantGems(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseAdminRequest(r)
+	if !ok {
+		return
+	}
+
+	var params GrantGemsRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	balance, err := h.adminService.GrantGems(r.Context(), userID, params.UserID, params.Gems)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to grant gems",
+			zap.String("userId", userID),
+			zap.String("targetUserId", params.UserID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to grant gems")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, GrantGemsResponse{Gems: balance})
+}
+
+// HandleDespawnAnimal handles POST /admin/v1/admin.DespawnAnimal
+// @Summary Despawn a wild animal
+// @Description Remove a wild animal from the world; every player is sent animal.despawned. Animals trainers own can't be despawned.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[DespawnAnimalRequest] true "JSON-RPC request with DespawnAnimalRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[DespawnAnimalResponse] "Animal despawned"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Animal not wild (-32602), not found (-32004) or not an admin (-32006)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/v1/admin.DespawnAnimal [post]
+func (h *AdminHandler) HandleDespawnAnimal(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseAdminRequest(r)
+	if !ok {
+		return
+	}
+
+	var params DespawnAnimalRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	if err := h.adminService.DespawnAnimal(r.Context(), userID, params.AnimalID); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to despawn animal",
+			zap.String("userId", userID),
+			zap.String("animalId", params.AnimalID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to despawn animal")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, DespawnAnimalResponse{Success: true})
+}
+
+// HandleStats handles POST /admin/v1/admin.Stats
+// @Summary Server stats
+// @Description Get the SSE and WebSocket clients connected to the answering server, the movement simulation counters (persistence lag, dropped frames, rejected moves) and the players online across all servers.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[StatsResponse] "Server stats"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Not an admin (-32006)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /admin/v1/admin.Stats [post]
+func (h *AdminHandler) HandleStats(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseAdminRequest(r)
+	if !ok {
+		return
+	}
+
+	stats, err := h.adminService.Stats(r.Context())
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get server stats",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to get server stats")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, StatsResponse{ServerStats: stats})
+}
+
+// parseAdminRequest reads the caller and the JSON-RPC request, answering invalid requests itself
+func (h *AdminHandler) parseAdminRequest(r *http.Request) (string, *jsonrpcx.Request, bool) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return "", nil, false
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return "", nil, false
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return "", nil, false
+	}
+
+	return userID, req, true
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// EditTiles handles world tile edits (autorouter compatible)
+func (h *AdminHandler) EditTiles(w http.ResponseWriter, r *http.Request) {
+	h.HandleEditTiles(w, r)
+}
+
+// UploadScript handles uploading a live-ops script (autorouter compatible)
+func (h *AdminHandler) UploadScript(w http.ResponseWriter, r *http.Request) {
+	h.HandleUploadScript(w, r)
+}
+
+// ListScripts handles listing live-ops scripts (autorouter compatible)
+func (h *AdminHandler) ListScripts(w http.ResponseWriter, r *http.Request) {
+	h.HandleListScripts(w, r)
+}
+
+// DeleteScript handles deleting a live-ops script (autorouter compatible)
+func (h *AdminHandler) DeleteScript(w http.ResponseWriter, r *http.Request) {
+	h.HandleDeleteScript(w, r)
+}
+
+// RunScript handles running a live-ops script (autorouter compatible)
+func (h *AdminHandler) RunScript(w http.ResponseWriter, r *http.Request) {
+	h.HandleRunScript(w, r)
+}
+
+// OnlinePlayers handles listing online players (autorouter compatible)
+func (h *AdminHandler) OnlinePlayers(w http.ResponseWriter, r *http.Request) {
+	h.HandleOnlinePlayers(w, r)
+}
+
+// Kick handles kicking a player (autorouter compatible)
+func (h *AdminHandler) Kick(w http.ResponseWriter, r *http.Request) {
+	h.HandleKick(w, r)
+}
+
+// Teleport handles teleporting a trainer (autorouter compatible)
+func (h *AdminHandler) Teleport(w http.ResponseWriter, r *http.Request) {
+	h.HandleTeleport(w, r)
+}
+
+// Grant handles granting money and items (autorouter compatible)
+func (h *AdminHandler) Grant(w http.ResponseWriter, r *http.Request) {
+	h.HandleGrant(w, r)
+}
+
+// GrantGems handles granting gems (autorouter compatible)
+func (h *AdminHandler) GrantGems(w http.ResponseWriter, r *http.Request) {
+	h.HandleGrantGems(w, r)
+}
+
+// DespawnAnimal handles despawning a wild animal (autorouter compatible)
+func (h *AdminHandler) DespawnAnimal(w http.ResponseWriter, r *http.Request) {
+	h.HandleDespawnAnimal(w, r)
+}
+
+// Stats handles reading server stats (autorouter compatible)
+func (h *AdminHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	h.HandleStats(w, r)
+}
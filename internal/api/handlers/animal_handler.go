@@ -1,23 +1,38 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 
+	"go.uber.org/zap"
+
 	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/animal"
 	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
 	"github.com/danghamo/life/pkg/logger"
 )
 
+// CaptureService interface for capturing and releasing animals
+type CaptureService interface {
+	Capture(ctx context.Context, userID trainer.UserID, animalID animal.AnimalID, itemID trainer.ItemID) (*trainer.CaptureResult, error)
+	Release(ctx context.Context, userID trainer.UserID, animalID animal.AnimalID) (*animal.Animal, error)
+}
+
 // AnimalHandler handles animal-related HTTP requests with JSON-RPC 2.0 format
 type AnimalHandler struct {
-	logger *logger.Logger
+	logger         *logger.Logger
+	captureService CaptureService
+	onboarding     Onboarding
 }
 
 // NewAnimalHandler creates a new animal handler
-func NewAnimalHandler(logger *logger.Logger) *AnimalHandler {
+func NewAnimalHandler(logger *logger.Logger, captureService CaptureService, onboarding Onboarding) *AnimalHandler {
 	return &AnimalHandler{
-		logger: logger.WithComponent("animal-handler"),
+		logger:         logger.WithComponent("animal-handler"),
+		captureService: captureService,
+		onboarding:     onboarding,
 	}
 }
 
@@ -34,8 +49,12 @@ type GetAnimalParams struct {
 }
 
 type CaptureAnimalParams struct {
-	AnimalID  string `json:"animalId"`
-	TrainerID string `json:"trainerId"`
+	AnimalID string `json:"animal_id" validate:"required"`
+	ItemID   string `json:"item_id" validate:"required"` // Capture net to throw
+}
+
+type ReleaseAnimalParams struct {
+	AnimalID string `json:"animal_id" validate:"required"`
 }
 
 // HandleSpawn handles POST /api/v1/animal.Spawn
@@ -52,8 +71,7 @@ func (h *AnimalHandler) HandleSpawn(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var params SpawnAnimalParams
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
@@ -85,8 +103,7 @@ func (h *AnimalHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var params GetAnimalParams
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
@@ -111,12 +128,29 @@ func (h *AnimalHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 }
 
 // HandleCapture handles POST /api/v1/animal.Capture
+// @Summary Throw a capture net at a wild animal
+// @Description Use up one capture net from the inventory and roll to capture an animal within capture range. Weakened animals are easier to catch. Caught animals join the party, or storage when the party is full.
+// @Tags animal
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[CaptureAnimalParams] true "JSON-RPC request with CaptureAnimalParams params"
+// @Success 200 {object} jsonrpcx.ResponseT[trainer.CaptureResult] "Capture attempt outcome"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Animal not capturable, out of capture range, or item is not a capture net"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/animal.Capture [post]
 func (h *AnimalHandler) HandleCapture(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
 		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
 		return
 	}
 
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
 	req, err := jsonrpcx.ParseRequest(r)
 	if err != nil {
 		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
@@ -124,24 +158,72 @@ func (h *AnimalHandler) HandleCapture(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var params CaptureAnimalParams
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
-	// TODO: Implement actual animal capture using command handler
-	// For now, return a mock response
-	result := map[string]any{
-		"animalId":  params.AnimalID,
-		"trainerId": params.TrainerID,
-		"status":    "captured",
-		"success":   true,
-		"message":   "Animal captured successfully!",
+	result, err := h.captureService.Capture(r.Context(), trainer.UserID(userID), animal.AnimalID(params.AnimalID), trainer.ItemID(params.ItemID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to capture animal",
+			zap.String("userId", userID),
+			zap.String("animalId", params.AnimalID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to capture animal")
+		return
 	}
+	h.onboarding.Captured(r.Context(), trainer.UserID(userID), result)
 
 	jsonrpcx.Success(w, req.ID, result)
 }
 
+// HandleRelease handles POST /api/v1/animal.Release
+// @Summary Release an owned animal
+// @Description Return one of the trainer's animals to the wild at the trainer's position
+// @Tags animal
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[ReleaseAnimalParams] true "JSON-RPC request with ReleaseAnimalParams params"
+// @Success 200 {object} jsonrpcx.ResponseT[animal.Animal] "Released animal"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Animal not owned or still equipped"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/animal.Release [post]
+func (h *AnimalHandler) HandleRelease(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params ReleaseAnimalParams
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	released, err := h.captureService.Release(r.Context(), trainer.UserID(userID), animal.AnimalID(params.AnimalID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to release animal",
+			zap.String("userId", userID),
+			zap.String("animalId", params.AnimalID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to release animal")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, released)
+}
+
 // HandleList handles POST /api/v1/animal.List
 func (h *AnimalHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -198,6 +280,11 @@ func (h *AnimalHandler) Capture(w http.ResponseWriter, r *http.Request) {
 	h.HandleCapture(w, r)
 }
 
+// Release handles animal release (autorouter compatible)
+func (h *AnimalHandler) Release(w http.ResponseWriter, r *http.Request) {
+	h.HandleRelease(w, r)
+}
+
 // List handles animal listing (autorouter compatible)
 func (h *AnimalHandler) List(w http.ResponseWriter, r *http.Request) {
 	h.HandleList(w, r)
@@ -5,6 +5,7 @@ import (
 	"crypto/rand"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	mathrand "math/rand"
 	"net/http"
@@ -16,6 +17,10 @@ import (
 	"github.com/danghamo/life/internal/api/jsonrpcx"
 	"github.com/danghamo/life/internal/api/middleware"
 	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/referral"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/captcha"
 	"github.com/danghamo/life/pkg/logger"
 )
 
@@ -37,13 +42,49 @@ type ProviderConfig struct {
 	Scopes       string `json:"scopes"`
 }
 
+// ReferralTracker interface for crediting signups to the players who invited them
+type ReferralTracker interface {
+	Attribute(ctx context.Context, referredID, code string, fingerprint referral.Fingerprint) error
+	RecordLogin(ctx context.Context, userID string, fingerprint referral.Fingerprint)
+}
+
+// LoginGuard interface for challenging logins from new devices or locations
+type LoginGuard interface {
+	Check(ctx context.Context, acc *account.Account, fingerprint account.LoginFingerprint) (*account.LoginStepUp, error)
+	Verify(ctx context.Context, challengeID, code string) (account.AccountID, error)
+	Trust(ctx context.Context, userID account.UserID, fingerprint account.LoginFingerprint)
+}
+
+// ActivityRecorder interface for adding events to a player's account activity timeline
+type ActivityRecorder interface {
+	Record(ctx context.Context, userID account.UserID, activity *account.Activity)
+}
+
+// CaptchaVerifier interface for checking that new players are not scripts
+type CaptchaVerifier interface {
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// AccountMerger interface for joining a guest with the user owning the social account they link
+type AccountMerger interface {
+	Start(ctx context.Context, userID, otherUserID account.UserID, provider account.Provider, profile account.OAuthProfile) (*account.Merge, error)
+	Preview(ctx context.Context, userID account.UserID, token string) (*trainer.MergePlan, error)
+	Confirm(ctx context.Context, userID account.UserID, token string) (*trainer.MergePlan, error)
+}
+
 // AuthHandler handles OAuth authentication
 type AuthHandler struct {
-	logger      *logger.Logger
-	accountRepo account.Repository
-	jwtService  *account.JWTService
-	oauthConfig OAuthConfig
-	httpClient  *http.Client
+	logger          *logger.Logger
+	accountRepo     account.Repository
+	jwtService      *account.JWTService
+	oauthConfig     OAuthConfig
+	referralTracker ReferralTracker
+	pairingRepo     account.PairingRepository
+	loginGuard      LoginGuard
+	activity        ActivityRecorder
+	merger          AccountMerger
+	captcha         CaptchaVerifier
+	httpClient      *http.Client
 }
 
 // NewAuthHandler creates a new auth handler
@@ -52,20 +93,36 @@ func NewAuthHandler(
 	accountRepo account.Repository,
 	jwtService *account.JWTService,
 	oauthConfig OAuthConfig,
+	referralTracker ReferralTracker,
+	pairingRepo account.PairingRepository,
+	loginGuard LoginGuard,
+	activity ActivityRecorder,
+	merger AccountMerger,
+	captchaVerifier CaptchaVerifier,
 ) *AuthHandler {
 	return &AuthHandler{
-		logger:      logger.WithComponent("auth-handler"),
-		accountRepo: accountRepo,
-		jwtService:  jwtService,
-		oauthConfig: oauthConfig,
-		httpClient:  &http.Client{},
+		logger:          logger.WithComponent("auth-handler"),
+		accountRepo:     accountRepo,
+		jwtService:      jwtService,
+		oauthConfig:     oauthConfig,
+		referralTracker: referralTracker,
+		pairingRepo:     pairingRepo,
+		loginGuard:      loginGuard,
+		activity:        activity,
+		merger:          merger,
+		captcha:         captchaVerifier,
+		httpClient:      &http.Client{},
 	}
 }
 
 // OAuthStartRequest represents OAuth start request
+// Public clients that can't hold a secret send a PKCE code_challenge here and the matching
+// code_verifier to auth.OAuthCallback.
 type OAuthStartRequest struct {
-	Provider string `json:"provider"`
-	State    string `json:"state,omitempty"`
+	Provider            string `json:"provider"`
+	State               string `json:"state,omitempty"`
+	CodeChallenge       string `json:"code_challenge,omitempty"`
+	CodeChallengeMethod string `json:"code_challenge_method,omitempty"` // Only S256 is supported
 }
 
 // OAuthStartResponse represents OAuth start response
@@ -76,43 +133,149 @@ type OAuthStartResponse struct {
 
 // OAuthCallbackRequest represents OAuth callback request
 type OAuthCallbackRequest struct {
-	Provider string `json:"provider"`
-	Code     string `json:"code"`
-	State    string `json:"state"`
+	Provider     string `json:"provider"`
+	Code         string `json:"code"`
+	State        string `json:"state"`
+	CodeVerifier string `json:"code_verifier,omitempty"` // PKCE verifier for the code_challenge sent to auth.OAuthStart
+	DeviceID     string `json:"device_id,omitempty"`     // Used to detect referral abuse
+	ReferralCode string `json:"referral_code,omitempty"` // Credits a new player's signup to the inviter
+	CaptchaToken string `json:"captcha_token,omitempty"` // Token of the server.Info captcha widget, needed for new players
 }
 
-// OAuthCallbackResponse represents OAuth callback response
+// OAuthCallbackResponse represents OAuth callback response. When StepUp is set the login
+// is suspicious: no token is issued until auth.VerifyLogin succeeds.
 type OAuthCallbackResponse struct {
-	JWTToken  string `json:"jwt_token"`
-	UserID    string `json:"user_id"`
-	ExpiresIn int64  `json:"expires_in"`
+	JWTToken        string               `json:"jwt_token"`
+	UserID          string               `json:"user_id"`
+	ExpiresIn       int64                `json:"expires_in"`
+	ReferralApplied bool                 `json:"referral_applied,omitempty"`
+	StepUp          *account.LoginStepUp `json:"step_up,omitempty"`
 }
 
 // GuestLoginRequest represents guest login request
 type GuestLoginRequest struct {
-	DeviceID string `json:"device_id"`
+	DeviceID     string `json:"device_id" validate:"required"`
+	ReferralCode string `json:"referral_code,omitempty"` // Credits a new player's signup to the inviter
+	CaptchaToken string `json:"captcha_token,omitempty"` // Token of the server.Info captcha widget, needed for new devices
 }
 
-// GuestLoginResponse represents guest login response
+// GuestLoginResponse represents guest login response. When StepUp is set the login is
+// suspicious: no token is issued until auth.VerifyLogin succeeds.
 type GuestLoginResponse struct {
-	JWTToken  string `json:"jwt_token"`
-	UserID    string `json:"user_id"`
-	IsGuest   bool   `json:"is_guest"`
-	ExpiresIn int64  `json:"expires_in"`
+	JWTToken        string               `json:"jwt_token"`
+	UserID          string               `json:"user_id"`
+	IsGuest         bool                 `json:"is_guest"`
+	ExpiresIn       int64                `json:"expires_in"`
+	ReferralApplied bool                 `json:"referral_applied,omitempty"`
+	StepUp          *account.LoginStepUp `json:"step_up,omitempty"`
+}
+
+// VerifyLoginRequest represents the code entered to complete a suspicious login
+type VerifyLoginRequest struct {
+	ChallengeID string `json:"challenge_id" validate:"required"`
+	Code        string `json:"code"`
+}
+
+// ProviderLink represents one way a user can sign in: a social provider or a guest device
+type ProviderLink struct {
+	AccountID string            `json:"account_id"`
+	Provider  string            `json:"provider"`
+	Email     string            `json:"email,omitempty"`
+	Name      string            `json:"name,omitempty"`
+	LinkedAt  *shared.Timestamp `json:"linked_at,omitempty"`
+	CreatedAt shared.Timestamp  `json:"created_at"`
+}
+
+// ListProvidersResponse represents the sign-in methods of the current user
+type ListProvidersResponse struct {
+	Providers []ProviderLink `json:"providers"`
+}
+
+// UnlinkSocialRequest represents a social account being unlinked
+type UnlinkSocialRequest struct {
+	AccountID string `json:"account_id" validate:"required"`
 }
 
 // LinkSocialRequest represents social account linking request
 type LinkSocialRequest struct {
-	Provider string `json:"provider"`
-	Code     string `json:"code"`
-	State    string `json:"state"`
+	Provider     string `json:"provider"`
+	Code         string `json:"code"`
+	State        string `json:"state"`
+	CodeVerifier string `json:"code_verifier,omitempty"`
 }
 
-// LinkSocialResponse represents response after linking social account
+// LinkSocialResponse represents response after linking social account. When MergeToken is
+// set the social account already belongs to another user: nothing is linked and no token is
+// issued until auth.ConfirmMerge succeeds. When StepUp is set the user has a second factor:
+// the account is linked but no token is issued until auth.VerifyLogin succeeds.
 type LinkSocialResponse struct {
+	JWTToken   string               `json:"jwt_token"`
+	UserID     string               `json:"user_id"`
+	Provider   string               `json:"provider"`
+	ExpiresIn  int64                `json:"expires_in"`
+	MergeToken string               `json:"merge_token,omitempty"`
+	StepUp     *account.LoginStepUp `json:"step_up,omitempty"`
+}
+
+// MergeRequest represents a pending merge being previewed or confirmed
+type MergeRequest struct {
+	MergeToken string `json:"merge_token" validate:"required"`
+}
+
+// MergeTrainer summarizes one of the trainers in a merge
+type MergeTrainer struct {
+	UserID    string `json:"user_id"`
+	Nickname  string `json:"nickname"`
+	Level     int    `json:"level"`
+	Money     int    `json:"money"`
+	Items     int    `json:"items"` // Item stacks in the inventory
+	PartySize int    `json:"party_size"`
+}
+
+// MergePreviewResponse represents what a merge does. Kept is the trainer as it is after the
+// merge; either trainer is left out for a user who has none.
+type MergePreviewResponse struct {
+	KeptUserID    string                  `json:"kept_user_id"`
+	Kept          *MergeTrainer           `json:"kept,omitempty"`
+	Absorbed      *MergeTrainer           `json:"absorbed,omitempty"`
+	MoneyAdded    int                     `json:"money_added"`
+	ItemsAdded    int                     `json:"items_added"`   // Item stacks moved into the inventory
+	ItemsVaulted  int                     `json:"items_vaulted"` // Item stacks moved into the vault
+	ItemsLost     int                     `json:"items_lost"`
+	AnimalsJoined int                     `json:"animals_joined"` // Party members that joined the kept party
+	AnimalsStored int                     `json:"animals_stored"` // Party members moved to storage
+	Conflicts     []trainer.MergeConflict `json:"conflicts"`
+}
+
+// ConfirmMergeResponse represents the login of the merged user. When StepUp is set the
+// merged user has a second factor: no token is issued until auth.VerifyLogin succeeds.
+type ConfirmMergeResponse struct {
+	JWTToken  string               `json:"jwt_token"`
+	UserID    string               `json:"user_id"`
+	ExpiresIn int64                `json:"expires_in"`
+	Merge     MergePreviewResponse `json:"merge"`
+	StepUp    *account.LoginStepUp `json:"step_up,omitempty"`
+}
+
+// GeneratePairCodeRequest represents pairing code generation request
+type GeneratePairCodeRequest struct{}
+
+// GeneratePairCodeResponse represents a pairing code to enter on another device
+type GeneratePairCodeResponse struct {
+	Code      string `json:"code"`
+	ExpiresIn int64  `json:"expires_in"`
+}
+
+// RedeemPairCodeRequest represents pairing code redemption on a new device
+type RedeemPairCodeRequest struct {
+	Code     string `json:"code"`
+	DeviceID string `json:"device_id" validate:"required"`
+}
+
+// RedeemPairCodeResponse represents the login on a newly paired device
+type RedeemPairCodeResponse struct {
 	JWTToken  string `json:"jwt_token"`
 	UserID    string `json:"user_id"`
-	Provider  string `json:"provider"`
 	ExpiresIn int64  `json:"expires_in"`
 }
 
@@ -133,7 +296,7 @@ type UserProfile struct {
 
 // HandleOAuthStart handles POST /api/v1/auth.OAuthStart
 // @Summary Start OAuth authentication flow
-// @Description Initiate OAuth authentication with a supported provider (google, github, discord)
+// @Description Initiate OAuth authentication with a supported provider (google, github, discord). Public clients should send a PKCE code_challenge (S256).
 // @Tags authentication
 // @Accept json
 // @Produce json
@@ -155,8 +318,7 @@ func (h *AuthHandler) HandleOAuthStart(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var params OAuthStartRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
@@ -168,36 +330,44 @@ func (h *AuthHandler) HandleOAuthStart(w http.ResponseWriter, r *http.Request) {
 
 	config, err := h.getProviderConfig(provider)
 	if err != nil {
-		h.logger.Error("Provider configuration error", zap.Error(err))
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Provider configuration error")
+		h.logger.WithContext(r.Context()).Error("Provider configuration error", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Provider configuration error")
 		return
 	}
 
+	if params.CodeChallenge != "" {
+		if err := account.ValidateCodeChallenge(params.CodeChallenge, params.CodeChallengeMethod); err != nil {
+			jsonrpcx.WithDomainError(r, req.ID, err, "Invalid PKCE parameters")
+			return
+		}
+	}
+
 	state := params.State
 	if state == "" {
 		state = generateRandomState()
 	}
 
-	authURL := h.buildAuthURL(config, state)
+	authURL := h.buildAuthURL(config, state, params.CodeChallenge)
 
 	response := OAuthStartResponse{
 		AuthURL: authURL,
 		State:   state,
 	}
 
-	h.logger.Info("OAuth flow started", zap.String("provider", params.Provider))
+	h.logger.WithContext(r.Context()).Info("OAuth flow started", zap.String("provider", params.Provider))
 	jsonrpcx.Success(w, req.ID, response)
 }
 
 // HandleOAuthCallback handles POST /api/v1/auth.OAuthCallback
 // @Summary Complete OAuth authentication flow
-// @Description Complete OAuth authentication with authorization code and receive JWT token
+// @Description Complete OAuth authentication with authorization code and receive JWT token. Flows started with a code_challenge must send the code_verifier. A referral_code on a new player's first signup credits the inviter. Signing up with a social account not seen before needs the captcha_token of the server.Info captcha widget, when one is listed.
 // @Tags authentication
 // @Accept json
 // @Produce json
 // @Param request body jsonrpcx.RequestT[OAuthCallbackRequest] true "JSON-RPC request with OAuthCallbackRequest params"
 // @Success 200 {object} jsonrpcx.ResponseT[OAuthCallbackResponse] "JWT token and user information"
 // @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid request parameters or authorization code"
+// @Failure 403 {object} jsonrpcx.ErrorResponse "Missing or failed captcha (CAPTCHA_FAILED)"
 // @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
 // @Router /api/v1/auth.OAuthCallback [post]
 func (h *AuthHandler) HandleOAuthCallback(w http.ResponseWriter, r *http.Request) {
@@ -213,8 +383,7 @@ func (h *AuthHandler) HandleOAuthCallback(w http.ResponseWriter, r *http.Request
 	}
 
 	var params OAuthCallbackRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
@@ -226,15 +395,22 @@ func (h *AuthHandler) HandleOAuthCallback(w http.ResponseWriter, r *http.Request
 
 	config, err := h.getProviderConfig(provider)
 	if err != nil {
-		h.logger.Error("Provider configuration error", zap.Error(err))
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Provider configuration error")
+		h.logger.WithContext(r.Context()).Error("Provider configuration error", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Provider configuration error")
 		return
 	}
 
+	if params.CodeVerifier != "" {
+		if err := account.ValidateCodeVerifier(params.CodeVerifier); err != nil {
+			jsonrpcx.WithDomainError(r, req.ID, err, "Invalid PKCE parameters")
+			return
+		}
+	}
+
 	// Exchange code for access token
-	token, err := h.exchangeCodeForToken(config, params.Code)
+	token, err := h.exchangeCodeForToken(config, params.Code, params.CodeVerifier)
 	if err != nil {
-		h.logger.Error("Failed to exchange code for token", zap.Error(err))
+		h.logger.WithContext(r.Context()).Error("Failed to exchange code for token", zap.Error(err))
 		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Failed to exchange code for token")
 		return
 	}
@@ -242,34 +418,61 @@ func (h *AuthHandler) HandleOAuthCallback(w http.ResponseWriter, r *http.Request
 	// Get user profile
 	profile, err := h.getUserProfile(config, token.AccessToken)
 	if err != nil {
-		h.logger.Error("Failed to get user profile", zap.Error(err))
+		h.logger.WithContext(r.Context()).Error("Failed to get user profile", zap.Error(err))
 		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Failed to get user profile")
 		return
 	}
 
+	// Signing up needs the captcha; returning players don't solve it again
+	existing, err := h.accountRepo.GetByProvider(r.Context(), provider, profile.ID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get account by provider", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Internal error")
+		return
+	}
+	if existing == nil && !h.verifyCaptcha(r, req.ID, params.CaptchaToken) {
+		return
+	}
+
 	// Create or get account
-	acc, err := h.getOrCreateAccount(r.Context(), provider, profile)
+	acc, newUser, err := h.getOrCreateAccount(r.Context(), provider, profile)
 	if err != nil {
-		h.logger.Error("Failed to create account", zap.Error(err))
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to create account")
+		h.logger.WithContext(r.Context()).Error("Failed to create account", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to create account")
+		return
+	}
+
+	fingerprint := referral.Fingerprint{DeviceID: params.DeviceID, IP: middleware.ClientIP(r)}
+	referralApplied := h.applyReferral(r.Context(), acc.UserID.String(), newUser, params.ReferralCode, fingerprint)
+
+	// Logins from new devices or locations wait for the code sent to the verified email
+	stepUp, err := h.loginGuard.Check(r.Context(), acc, account.NewLoginFingerprint(params.DeviceID, middleware.ClientIP(r)))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to check login", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to check login")
+		return
+	}
+	if stepUp != nil {
+		jsonrpcx.Success(w, req.ID, OAuthCallbackResponse{ReferralApplied: referralApplied, StepUp: stepUp})
 		return
 	}
 
 	// Generate JWT token
-	jwtToken, err := h.jwtService.GenerateToken(acc)
+	jwtToken, err := h.jwtService.GenerateToken(r.Context(), acc)
 	if err != nil {
-		h.logger.Error("Failed to generate JWT token", zap.Error(err))
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to generate JWT token")
+		h.logger.WithContext(r.Context()).Error("Failed to generate JWT token", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to generate JWT token")
 		return
 	}
 
 	response := OAuthCallbackResponse{
-		JWTToken:  jwtToken,
-		UserID:    acc.UserID.String(),
-		ExpiresIn: 86400, // 24 hours
+		JWTToken:        jwtToken,
+		UserID:          acc.UserID.String(),
+		ExpiresIn:       86400, // 24 hours
+		ReferralApplied: referralApplied,
 	}
 
-	h.logger.Info("User authenticated successfully",
+	h.logger.WithContext(r.Context()).Info("User authenticated successfully",
 		zap.String("userId", acc.UserID.String()),
 		zap.String("provider", string(provider)))
 	jsonrpcx.Success(w, req.ID, response)
@@ -277,13 +480,14 @@ func (h *AuthHandler) HandleOAuthCallback(w http.ResponseWriter, r *http.Request
 
 // HandleGuestLogin handles guest login
 // @Summary Guest login with device ID
-// @Description Login as guest user using device identifier for immediate game access
+// @Description Login as guest user using device identifier for immediate game access. A referral_code on a new device's first login credits the inviter. A new device needs the captcha_token of the server.Info captcha widget, when one is listed.
 // @Tags authentication
 // @Accept json
 // @Produce json
 // @Param request body jsonrpcx.RequestT[GuestLoginRequest] true "JSON-RPC request with GuestLoginRequest params"
 // @Success 200 {object} jsonrpcx.ResponseT[GuestLoginResponse] "JWT token for guest user"
 // @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid request parameters"
+// @Failure 403 {object} jsonrpcx.ErrorResponse "Missing or failed captcha (CAPTCHA_FAILED)"
 // @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
 // @Router /api/v1/auth.GuestLogin [post]
 func (h *AuthHandler) HandleGuestLogin(w http.ResponseWriter, r *http.Request) {
@@ -299,35 +503,35 @@ func (h *AuthHandler) HandleGuestLogin(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var params GuestLoginRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
-		return
-	}
-
-	if params.DeviceID == "" {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Device ID is required")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
 	// Try to find existing guest account
 	existingAccount, err := h.accountRepo.GetByDeviceID(r.Context(), params.DeviceID)
 	if err != nil {
-		h.logger.Error("Failed to get account by device ID", zap.Error(err))
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Internal error")
+		h.logger.WithContext(r.Context()).Error("Failed to get account by device ID", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Internal error")
 		return
 	}
 
 	var guestAccount *account.Account
+	newUser := existingAccount == nil
 
 	if existingAccount != nil {
 		guestAccount = existingAccount
-		h.logger.Info("Existing guest account found", zap.String("deviceId", params.DeviceID))
+		h.logger.WithContext(r.Context()).Info("Existing guest account found", zap.String("deviceId", params.DeviceID))
 	} else {
+		// Scripted account farms create guests for made-up devices
+		if !h.verifyCaptcha(r, req.ID, params.CaptchaToken) {
+			return
+		}
+
 		// Create new guest account
 		newAccount, err := account.NewGuestAccount(params.DeviceID)
 		if err != nil {
-			h.logger.Error("Failed to create guest account", zap.Error(err))
-			jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to create guest account")
+			h.logger.WithContext(r.Context()).Error("Failed to create guest account", zap.Error(err))
+			jsonrpcx.WithDomainError(r, req.ID, err, "Failed to create guest account")
 			return
 		}
 
@@ -335,30 +539,46 @@ func (h *AuthHandler) HandleGuestLogin(w http.ResponseWriter, r *http.Request) {
 			return newAccount, nil
 		})
 		if err != nil {
-			h.logger.Error("Failed to save guest account", zap.Error(err))
-			jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to save guest account")
+			h.logger.WithContext(r.Context()).Error("Failed to save guest account", zap.Error(err))
+			jsonrpcx.WithDomainError(r, req.ID, err, "Failed to save guest account")
 			return
 		}
 
 		guestAccount = newAccount
-		h.logger.Info("New guest account created",
+		h.logger.WithContext(r.Context()).Info("New guest account created",
 			zap.String("deviceId", params.DeviceID),
 			zap.String("userId", guestAccount.UserID.String()))
 	}
 
+	fingerprint := referral.Fingerprint{DeviceID: params.DeviceID, IP: middleware.ClientIP(r)}
+	referralApplied := h.applyReferral(r.Context(), guestAccount.UserID.String(), newUser, params.ReferralCode, fingerprint)
+
+	// A paired device account may be signing in from a new location
+	stepUp, err := h.loginGuard.Check(r.Context(), guestAccount, account.NewLoginFingerprint(params.DeviceID, middleware.ClientIP(r)))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to check login", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to check login")
+		return
+	}
+	if stepUp != nil {
+		jsonrpcx.Success(w, req.ID, GuestLoginResponse{IsGuest: true, ReferralApplied: referralApplied, StepUp: stepUp})
+		return
+	}
+
 	// Generate JWT token
-	jwtToken, err := h.jwtService.GenerateToken(guestAccount)
+	jwtToken, err := h.jwtService.GenerateToken(r.Context(), guestAccount)
 	if err != nil {
-		h.logger.Error("Failed to generate JWT token", zap.Error(err))
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to generate JWT token")
+		h.logger.WithContext(r.Context()).Error("Failed to generate JWT token", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to generate JWT token")
 		return
 	}
 
 	response := GuestLoginResponse{
-		JWTToken:  jwtToken,
-		UserID:    guestAccount.UserID.String(),
-		IsGuest:   true,
-		ExpiresIn: 86400, // 24 hours
+		JWTToken:        jwtToken,
+		UserID:          guestAccount.UserID.String(),
+		IsGuest:         true,
+		ExpiresIn:       86400, // 24 hours
+		ReferralApplied: referralApplied,
 	}
 
 	jsonrpcx.Success(w, req.ID, response)
@@ -366,13 +586,13 @@ func (h *AuthHandler) HandleGuestLogin(w http.ResponseWriter, r *http.Request) {
 
 // HandleLinkSocial handles linking guest account to social provider
 // @Summary Link guest account to social provider
-// @Description Convert guest account to social account by linking with OAuth provider
+// @Description Convert guest account to social account by linking with OAuth provider. A user with a second factor gets step_up instead of a token, completed through auth.VerifyLogin.
 // @Tags authentication
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body jsonrpcx.RequestT[LinkSocialRequest] true "JSON-RPC request with LinkSocialRequest params"
-// @Success 200 {object} jsonrpcx.ResponseT[LinkSocialResponse] "Updated JWT token with social account"
+// @Success 200 {object} jsonrpcx.ResponseT[LinkSocialResponse] "Updated JWT token with social account, or a merge token when the account belongs to another user"
 // @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid request parameters"
 // @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
 // @Failure 403 {object} jsonrpcx.ErrorResponse "Not a guest account or already linked"
@@ -385,7 +605,7 @@ func (h *AuthHandler) HandleLinkSocial(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// JWT에서 사용자 정보 가져오기
-	userID, ok := middleware.GetUserID(r.Context())
+	userID, ok := middleware.GetAccountID(r.Context())
 	if !ok {
 		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
 		return
@@ -398,8 +618,7 @@ func (h *AuthHandler) HandleLinkSocial(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var params LinkSocialRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
@@ -412,8 +631,8 @@ func (h *AuthHandler) HandleLinkSocial(w http.ResponseWriter, r *http.Request) {
 	// Get existing account
 	existingAccount, err := h.accountRepo.GetByUserID(r.Context(), account.UserID(userID))
 	if err != nil {
-		h.logger.Error("Failed to get account", zap.Error(err))
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Internal error")
+		h.logger.WithContext(r.Context()).Error("Failed to get account", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Internal error")
 		return
 	}
 
@@ -425,21 +644,28 @@ func (h *AuthHandler) HandleLinkSocial(w http.ResponseWriter, r *http.Request) {
 	// OAuth 플로우와 동일하게 토큰 교환 및 사용자 정보 가져오기
 	config, err := h.getProviderConfig(provider)
 	if err != nil {
-		h.logger.Error("Provider configuration error", zap.Error(err))
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Provider configuration error")
+		h.logger.WithContext(r.Context()).Error("Provider configuration error", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Provider configuration error")
 		return
 	}
 
-	token, err := h.exchangeCodeForToken(config, params.Code)
+	if params.CodeVerifier != "" {
+		if err := account.ValidateCodeVerifier(params.CodeVerifier); err != nil {
+			jsonrpcx.WithDomainError(r, req.ID, err, "Invalid PKCE parameters")
+			return
+		}
+	}
+
+	token, err := h.exchangeCodeForToken(config, params.Code, params.CodeVerifier)
 	if err != nil {
-		h.logger.Error("Failed to exchange code for token", zap.Error(err))
+		h.logger.WithContext(r.Context()).Error("Failed to exchange code for token", zap.Error(err))
 		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Failed to exchange code for token")
 		return
 	}
 
 	profile, err := h.getUserProfile(config, token.AccessToken)
 	if err != nil {
-		h.logger.Error("Failed to get user profile", zap.Error(err))
+		h.logger.WithContext(r.Context()).Error("Failed to get user profile", zap.Error(err))
 		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Failed to get user profile")
 		return
 	}
@@ -450,49 +676,697 @@ func (h *AuthHandler) HandleLinkSocial(w http.ResponseWriter, r *http.Request) {
 		oauthProfile.AvatarURL = profile.AvatarURL
 	}
 
+	// Linking a social account another user signs in with would leave one of the two
+	// trainers behind, so the users are merged once the player confirms instead
+	owner, err := h.socialOwner(r.Context(), provider, profile)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to look up social account", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Internal error")
+		return
+	}
+
+	if owner != nil && owner.UserID != existingAccount.UserID {
+		merge, err := h.merger.Start(r.Context(), existingAccount.UserID, owner.UserID, provider, oauthProfile)
+		if err != nil {
+			h.logger.WithContext(r.Context()).Error("Failed to start account merge", zap.Error(err))
+			jsonrpcx.WithDomainError(r, req.ID, err, "Failed to start account merge")
+			return
+		}
+
+		jsonrpcx.Success(w, req.ID, LinkSocialResponse{
+			UserID:     userID,
+			Provider:   string(provider),
+			MergeToken: merge.Token,
+		})
+		return
+	}
+
 	err = h.accountRepo.FindOneAndUpdate(r.Context(), existingAccount.ID, func(acc *account.Account) (*account.Account, error) {
 		return acc, acc.LinkToSocialProvider(provider, oauthProfile)
 	})
 	if err != nil {
-		h.logger.Error("Failed to link social account", zap.Error(err))
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to link social account")
+		h.logger.WithContext(r.Context()).Error("Failed to link social account", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to link social account")
 		return
 	}
 
 	// 새로운 JWT 토큰 생성
 	updatedAccount, err := h.accountRepo.GetByID(r.Context(), existingAccount.ID)
 	if err != nil {
-		h.logger.Error("Failed to get updated account", zap.Error(err))
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Internal error")
+		h.logger.WithContext(r.Context()).Error("Failed to get updated account", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Internal error")
 		return
 	}
 
-	jwtToken, err := h.jwtService.GenerateToken(updatedAccount)
+	linked := account.NewActivity(account.ActivityProviderLinked)
+	linked.Provider = provider
+	h.activity.Record(r.Context(), updatedAccount.UserID, linked)
+
+	stepUp, err := h.secondFactorStepUp(r, updatedAccount)
 	if err != nil {
-		h.logger.Error("Failed to generate JWT token", zap.Error(err))
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to generate JWT token")
+		h.logger.WithContext(r.Context()).Error("Failed to check second factor", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to check login")
+		return
+	}
+	if stepUp != nil {
+		jsonrpcx.Success(w, req.ID, LinkSocialResponse{
+			UserID:   updatedAccount.UserID.String(),
+			Provider: string(provider),
+			StepUp:   stepUp,
+		})
 		return
 	}
 
-	response := OAuthCallbackResponse{
+	jwtToken, err := h.jwtService.GenerateToken(r.Context(), updatedAccount)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to generate JWT token", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to generate JWT token")
+		return
+	}
+
+	response := LinkSocialResponse{
 		JWTToken:  jwtToken,
 		UserID:    updatedAccount.UserID.String(),
+		Provider:  string(provider),
 		ExpiresIn: 86400,
 	}
 
-	h.logger.Info("Guest account linked to social provider",
+	h.logger.WithContext(r.Context()).Info("Guest account linked to social provider",
 		zap.String("userId", userID),
 		zap.String("provider", string(provider)))
 
 	jsonrpcx.Success(w, req.ID, response)
 }
 
-// getOrCreateAccount gets existing account or creates new one with N:1 UserID linking
-func (h *AuthHandler) getOrCreateAccount(ctx context.Context, provider account.Provider, profile *UserProfile) (*account.Account, error) {
+// HandlePreviewMerge handles POST /api/v1/auth.PreviewMerge
+// @Summary Preview an account merge
+// @Description Show what confirming the merge started by auth.LinkSocial does: the higher level trainer is kept and the other one's money, items and party move over. Conflicts list what the player gives up or what moves to the vault or storage.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body jsonrpcx.RequestT[MergeRequest] true "JSON-RPC request with MergeRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[MergePreviewResponse] "Merge outcome and conflicts"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid or expired merge token"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Router /api/v1/auth.PreviewMerge [post]
+func (h *AuthHandler) HandlePreviewMerge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params MergeRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	plan, err := h.merger.Preview(r.Context(), account.UserID(userID), params.MergeToken)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to preview account merge", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to preview account merge")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, newMergePreviewResponse(plan))
+}
+
+// HandleConfirmMerge handles POST /api/v1/auth.ConfirmMerge
+// @Summary Confirm an account merge
+// @Description Merge the guest with the user owning the social account it tried to link. The kept user signs in with both; the other user's tokens are revoked and its remaining data is deleted. The merge token can only be used once. A merged user with a second factor gets step_up instead of a token, completed through auth.VerifyLogin.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body jsonrpcx.RequestT[MergeRequest] true "JSON-RPC request with MergeRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[ConfirmMergeResponse] "JWT token for the merged user and the merge outcome"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid or expired merge token"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Router /api/v1/auth.ConfirmMerge [post]
+func (h *AuthHandler) HandleConfirmMerge(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params MergeRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	// The caller's own account keeps signing in, under whichever user is kept
+	existingAccount, err := h.accountRepo.GetByUserID(r.Context(), account.UserID(userID))
+	if err != nil || existingAccount == nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get account", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Internal error")
+		return
+	}
+
+	plan, err := h.merger.Confirm(r.Context(), account.UserID(userID), params.MergeToken)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to merge accounts", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to merge accounts")
+		return
+	}
+
+	mergedAccount, err := h.accountRepo.GetByID(r.Context(), existingAccount.ID)
+	if err != nil || mergedAccount == nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get merged account", zap.String("accountId", existingAccount.ID.String()), zap.Error(err))
+		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Internal error")
+		return
+	}
+
+	// Merging into a user with a second factor signs in to that user, which takes the second
+	// factor like every other sign-in
+	stepUp, err := h.secondFactorStepUp(r, mergedAccount)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to check second factor", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to check login")
+		return
+	}
+	if stepUp != nil {
+		jsonrpcx.Success(w, req.ID, ConfirmMergeResponse{
+			UserID: mergedAccount.UserID.String(),
+			Merge:  newMergePreviewResponse(plan),
+			StepUp: stepUp,
+		})
+		return
+	}
+
+	jwtToken, err := h.jwtService.GenerateToken(r.Context(), mergedAccount)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to generate JWT token", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to generate JWT token")
+		return
+	}
+
+	response := ConfirmMergeResponse{
+		JWTToken:  jwtToken,
+		UserID:    mergedAccount.UserID.String(),
+		ExpiresIn: 86400, // 24 hours
+		Merge:     newMergePreviewResponse(plan),
+	}
+
+	jsonrpcx.Success(w, req.ID, response)
+}
+
+// maxPairCodeAttempts bounds retries when a freshly generated pairing code is already taken
+const maxPairCodeAttempts = 3
+
+// HandleGeneratePairCode handles POST /api/v1/auth.GeneratePairCode
+// @Summary Generate a device pairing code
+// @Description Issue a short-lived code that signs another device (console, mobile) into the current user without OAuth on that device. Generating a new code invalidates the previous one.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body jsonrpcx.RequestT[GeneratePairCodeRequest] true "JSON-RPC request with GeneratePairCodeRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[GeneratePairCodeResponse] "Pairing code and seconds until it expires"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid request parameters"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Router /api/v1/auth.GeneratePairCode [post]
+func (h *AuthHandler) HandleGeneratePairCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var pairing *account.Pairing
+	for attempt := 0; attempt < maxPairCodeAttempts; attempt++ {
+		pairing, err = account.NewPairing(account.UserID(userID))
+		if err != nil {
+			break
+		}
+
+		if err = h.pairingRepo.Save(r.Context(), pairing); err == nil {
+			break
+		}
+	}
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to generate pairing code", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to generate pairing code")
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("Pairing code generated", zap.String("userId", userID))
+
+	response := GeneratePairCodeResponse{
+		Code:      pairing.Code.String(),
+		ExpiresIn: int64(account.PairCodeTTL.Seconds()),
+	}
+
+	jsonrpcx.Success(w, req.ID, response)
+}
+
+// HandleRedeemPairCode handles POST /api/v1/auth.RedeemPairCode
+// @Summary Sign a new device in with a pairing code
+// @Description Redeem a pairing code generated on a signed-in device. The device account is linked to that user; a device that was playing as a different guest switches over to the pairing user.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[RedeemPairCodeRequest] true "JSON-RPC request with RedeemPairCodeRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[RedeemPairCodeResponse] "JWT token for the paired user"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid, expired or already used pairing code"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Router /api/v1/auth.RedeemPairCode [post]
+func (h *AuthHandler) HandleRedeemPairCode(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params RedeemPairCodeRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	code, err := account.ParsePairCode(params.Code)
+	if err != nil {
+		jsonrpcx.WithDomainError(r, req.ID, err, "Invalid pairing code")
+		return
+	}
+
+	// Codes are single use, so a redeemed code can't be replayed from another device
+	pairing, err := h.pairingRepo.Redeem(r.Context(), code)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to redeem pairing code", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Internal error")
+		return
+	}
+	if pairing == nil {
+		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Pairing code is invalid or expired")
+		return
+	}
+
+	deviceAccount, err := h.pairDevice(r.Context(), params.DeviceID, pairing.UserID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to pair device",
+			zap.String("deviceId", params.DeviceID),
+			zap.String("userId", pairing.UserID.String()),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to pair device")
+		return
+	}
+
+	// The signed-in device vouched for this one
+	h.loginGuard.Trust(r.Context(), deviceAccount.UserID, account.NewLoginFingerprint(params.DeviceID, middleware.ClientIP(r)))
+
+	jwtToken, err := h.jwtService.GenerateToken(r.Context(), deviceAccount)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to generate JWT token", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to generate JWT token")
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("Device paired",
+		zap.String("deviceId", params.DeviceID),
+		zap.String("userId", deviceAccount.UserID.String()))
+
+	response := RedeemPairCodeResponse{
+		JWTToken:  jwtToken,
+		UserID:    deviceAccount.UserID.String(),
+		ExpiresIn: 86400, // 24 hours
+	}
+
+	jsonrpcx.Success(w, req.ID, response)
+}
+
+// HandleVerifyLogin handles POST /api/v1/auth.VerifyLogin
+// @Summary Complete a suspicious login
+// @Description Enter the code emailed when a login from a new device or location returned step_up, or for step_up method totp a code from the authenticator app or a backup code. Wrong codes count against a small attempt limit.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[VerifyLoginRequest] true "JSON-RPC request with VerifyLoginRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[OAuthCallbackResponse] "JWT token and user information"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Incorrect code or expired challenge"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Router /api/v1/auth.VerifyLogin [post]
+func (h *AuthHandler) HandleVerifyLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params VerifyLoginRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	accountID, err := h.loginGuard.Verify(r.Context(), params.ChallengeID, params.Code)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Login verification failed", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Login verification failed")
+		return
+	}
+
+	acc, err := h.accountRepo.GetByID(r.Context(), accountID)
+	if err != nil || acc == nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get verified account", zap.String("accountId", accountID.String()), zap.Error(err))
+		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Internal error")
+		return
+	}
+
+	jwtToken, err := h.jwtService.GenerateToken(r.Context(), acc)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to generate JWT token", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to generate JWT token")
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("Suspicious login verified", zap.String("userId", acc.UserID.String()))
+
+	response := OAuthCallbackResponse{
+		JWTToken:  jwtToken,
+		UserID:    acc.UserID.String(),
+		ExpiresIn: 86400, // 24 hours
+	}
+
+	jsonrpcx.Success(w, req.ID, response)
+}
+
+// HandleListProviders handles POST /api/v1/auth.ListProviders
+// @Summary List linked sign-in methods
+// @Description List the social providers and guest devices the current user can sign in with
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[ListProvidersResponse] "Linked sign-in methods"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Router /api/v1/auth.ListProviders [post]
+func (h *AuthHandler) HandleListProviders(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	accounts, err := h.accountRepo.ListByUserID(r.Context(), account.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list accounts", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Internal error")
+		return
+	}
+
+	response := ListProvidersResponse{Providers: make([]ProviderLink, 0, len(accounts))}
+	for _, acc := range accounts {
+		response.Providers = append(response.Providers, newProviderLink(acc))
+	}
+
+	jsonrpcx.Success(w, req.ID, response)
+}
+
+// HandleUnlinkSocial handles POST /api/v1/auth.UnlinkSocial
+// @Summary Unlink a social provider
+// @Description Remove a social provider from the current user. The user's last sign-in method cannot be removed, and guest devices are not unlinked here.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body jsonrpcx.RequestT[UnlinkSocialRequest] true "JSON-RPC request with UnlinkSocialRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[ListProvidersResponse] "Remaining sign-in methods"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Unknown account or last sign-in method"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Router /api/v1/auth.UnlinkSocial [post]
+func (h *AuthHandler) HandleUnlinkSocial(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params UnlinkSocialRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	accounts, err := h.accountRepo.ListByUserID(r.Context(), account.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list accounts", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Internal error")
+		return
+	}
+
+	target, err := account.FindUnlinkable(accounts, account.AccountID(params.AccountID))
+	if err != nil {
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to unlink account")
+		return
+	}
+
+	// The second factor stays with the user on one of their remaining accounts
+	remaining := make([]*account.Account, 0, len(accounts)-1)
+	for _, acc := range accounts {
+		if acc.ID != target.ID {
+			remaining = append(remaining, acc)
+		}
+	}
+	if successor := account.MFASuccessor(remaining, target); successor != nil {
+		err := h.accountRepo.FindOneAndUpdate(r.Context(), successor.ID, func(a *account.Account) (*account.Account, error) {
+			a.AdoptMFA(target.MFA)
+			return a, nil
+		})
+		if err != nil {
+			h.logger.WithContext(r.Context()).Error("Failed to move second factor", zap.String("userId", userID), zap.Error(err))
+			jsonrpcx.WithDomainError(r, req.ID, err, "Failed to unlink account")
+			return
+		}
+	}
+
+	if err := h.accountRepo.Delete(r.Context(), target.ID); err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to unlink account", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to unlink account")
+		return
+	}
+
+	unlinked := account.NewActivity(account.ActivityProviderUnlinked)
+	unlinked.Provider = target.Provider
+	h.activity.Record(r.Context(), target.UserID, unlinked)
+
+	h.logger.WithContext(r.Context()).Info("Social provider unlinked",
+		zap.String("userId", userID),
+		zap.String("provider", string(target.Provider)))
+
+	response := ListProvidersResponse{Providers: make([]ProviderLink, 0, len(remaining))}
+	for _, acc := range remaining {
+		response.Providers = append(response.Providers, newProviderLink(acc))
+	}
+
+	jsonrpcx.Success(w, req.ID, response)
+}
+
+// newProviderLink converts an account; guest device IDs are never exposed
+func newProviderLink(acc *account.Account) ProviderLink {
+	return ProviderLink{
+		AccountID: acc.ID.String(),
+		Provider:  string(acc.Provider),
+		Email:     acc.Profile.Email,
+		Name:      acc.Profile.Name,
+		LinkedAt:  acc.LinkedAt,
+		CreatedAt: acc.CreatedAt,
+	}
+}
+
+// newMergePreviewResponse converts a merge plan
+func newMergePreviewResponse(plan *trainer.MergePlan) MergePreviewResponse {
+	response := MergePreviewResponse{
+		KeptUserID:   plan.KeptUserID.String(),
+		Kept:         newMergeTrainer(plan.Kept),
+		Absorbed:     newMergeTrainer(plan.Absorbed),
+		ItemsVaulted: plan.Vaulted,
+		ItemsLost:    plan.Lost,
+		Conflicts:    plan.Conflicts,
+	}
+	if response.Conflicts == nil {
+		response.Conflicts = []trainer.MergeConflict{}
+	}
+
+	if plan.Result != nil {
+		response.MoneyAdded = plan.Result.Money
+		response.ItemsAdded = len(plan.Result.Items)
+		response.AnimalsJoined = len(plan.Result.Joined)
+		response.AnimalsStored = len(plan.Result.Benched)
+	}
+
+	return response
+}
+
+// newMergeTrainer summarizes a trainer in a merge
+func newMergeTrainer(t *trainer.Trainer) *MergeTrainer {
+	if t == nil {
+		return nil
+	}
+
+	return &MergeTrainer{
+		UserID:    t.ID.String(),
+		Nickname:  t.Nickname,
+		Level:     t.Level.Value(),
+		Money:     t.Money.Amount(),
+		Items:     t.Inventory.GetUsedSlots(),
+		PartySize: t.Party.Size(),
+	}
+}
+
+// secondFactorStepUp returns the step-up a token issued for acc waits for when its user has
+// a second factor, or nil when the token may be issued right away. Tokens issued into a user
+// outside of a sign-in, such as after linking or merging, would otherwise skip the second
+// factor.
+func (h *AuthHandler) secondFactorStepUp(r *http.Request, acc *account.Account) (*account.LoginStepUp, error) {
+	accounts, err := h.accountRepo.ListByUserID(r.Context(), acc.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if holder := account.FindMFA(accounts); holder == nil || !holder.MFAEnabled() {
+		return nil, nil
+	}
+
+	return h.loginGuard.Check(r.Context(), acc, account.NewLoginFingerprint("", middleware.ClientIP(r)))
+}
+
+// socialOwner returns the account a social profile already signs in to, found by the provider
+// or, like new sign-ins are linked, by email. It returns nil for a social account nobody has.
+func (h *AuthHandler) socialOwner(ctx context.Context, provider account.Provider, profile *UserProfile) (*account.Account, error) {
+	owner, err := h.accountRepo.GetByProvider(ctx, provider, profile.ID)
+	if err != nil || owner != nil || profile.Email == "" {
+		return owner, err
+	}
+
+	return h.accountRepo.GetByEmail(ctx, profile.Email)
+}
+
+// pairDevice links the device account to userID, creating the account on first use
+func (h *AuthHandler) pairDevice(ctx context.Context, deviceID string, userID account.UserID) (*account.Account, error) {
+	existing, err := h.accountRepo.GetByDeviceID(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing == nil {
+		newAccount, err := account.NewGuestAccountWithUserID(deviceID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		err = h.accountRepo.FindOneAndInsert(ctx, newAccount.ID, func() (*account.Account, error) {
+			return newAccount, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		return newAccount, nil
+	}
+
+	if existing.UserID == userID {
+		return existing, nil
+	}
+
+	var paired *account.Account
+	err = h.accountRepo.FindOneAndUpdate(ctx, existing.ID, func(acc *account.Account) (*account.Account, error) {
+		if err := acc.PairTo(userID); err != nil {
+			return nil, err
+		}
+		paired = acc
+		return acc, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	h.logger.WithContext(ctx).Info("Device switched to paired user",
+		zap.String("deviceId", deviceID),
+		zap.String("previousUserId", existing.UserID.String()),
+		zap.String("userId", userID.String()))
+
+	return paired, nil
+}
+
+// getOrCreateAccount gets existing account or creates new one with N:1 UserID linking.
+// It reports whether the account belongs to a brand new user.
+func (h *AuthHandler) getOrCreateAccount(ctx context.Context, provider account.Provider, profile *UserProfile) (*account.Account, bool, error) {
 	// Try to find existing account for this specific provider
 	existing, err := h.accountRepo.GetByProvider(ctx, provider, profile.ID)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	if existing != nil {
@@ -506,10 +1380,10 @@ func (h *AuthHandler) getOrCreateAccount(ctx context.Context, provider account.P
 			return acc, acc.UpdateProfile(oauthProfile)
 		})
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 
-		return existing, nil
+		return existing, false, nil
 	}
 
 	// Create OAuth profile for new account
@@ -520,15 +1394,17 @@ func (h *AuthHandler) getOrCreateAccount(ctx context.Context, provider account.P
 
 	// Check if any account with same email exists (for N:1 UserID linking)
 	var newAccount *account.Account
+	newUser := true
 	if profile.Email != "" {
 		existingByEmail, err := h.accountRepo.GetByEmail(ctx, profile.Email)
 		if err != nil {
-			return nil, err
+			return nil, false, err
 		}
 
 		if existingByEmail != nil {
+			newUser = false
 			// Link to existing UserID (N:1 relationship)
-			h.logger.Info("Linking new provider to existing UserID",
+			h.logger.WithContext(ctx).Info("Linking new provider to existing UserID",
 				zap.String("email", profile.Email),
 				zap.String("newProvider", string(provider)),
 				zap.String("existingUserID", existingByEmail.UserID.String()))
@@ -544,50 +1420,107 @@ func (h *AuthHandler) getOrCreateAccount(ctx context.Context, provider account.P
 	}
 
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
 	err = h.accountRepo.FindOneAndInsert(ctx, newAccount.ID, func() (*account.Account, error) {
 		return newAccount, nil
 	})
 	if err != nil {
-		return nil, err
+		return nil, false, err
+	}
+
+	return newAccount, newUser, nil
+}
+
+// applyReferral credits a new user's signup to the referral code's owner and records where
+// the user logged in from. Referral problems never block the login.
+func (h *AuthHandler) applyReferral(ctx context.Context, userID string, newUser bool, code string, fingerprint referral.Fingerprint) bool {
+	if h.referralTracker == nil {
+		return false
+	}
+
+	applied := false
+	if newUser && code != "" {
+		if err := h.referralTracker.Attribute(ctx, userID, code, fingerprint); err != nil {
+			h.logger.WithContext(ctx).Warn("Referral not applied",
+				zap.String("userId", userID),
+				zap.String("code", code),
+				zap.Error(err))
+		} else {
+			applied = true
+		}
 	}
 
-	return newAccount, nil
+	// Recorded after attribution so a signup is not compared against its own fingerprint
+	h.referralTracker.RecordLogin(ctx, userID, fingerprint)
+	return applied
+}
+
+// verifyCaptcha checks the captcha token of a request signing up a new player, answering the
+// request itself when it fails. Provider outages refuse sign-ups rather than let scripts in.
+func (h *AuthHandler) verifyCaptcha(r *http.Request, id any, token string) bool {
+	err := h.captcha.Verify(r.Context(), token, middleware.ClientIP(r))
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, captcha.ErrRejected):
+		h.logger.WithContext(r.Context()).Warn("Captcha rejected", zap.String("ip", middleware.ClientIP(r)), zap.Error(err))
+		jsonrpcx.WithDomainError(r, id, shared.NewDomainError(shared.ErrCodeCaptchaFailed, "Captcha failed, solve it again"), "Captcha failed")
+	default:
+		h.logger.WithContext(r.Context()).Error("Failed to verify captcha", zap.Error(err))
+		jsonrpcx.WithError(r, id, jsonrpcx.InternalError, "Captcha verification is unavailable, try again later")
+	}
+	return false
 }
 
-// getProviderConfig gets OAuth configuration for provider
+// getProviderConfig gets OAuth configuration for provider, failing for providers without a
+// registered app
 func (h *AuthHandler) getProviderConfig(provider account.Provider) (*ProviderConfig, error) {
+	var config *ProviderConfig
 	switch provider {
 	case account.ProviderGoogle:
-		return &h.oauthConfig.Google, nil
+		config = &h.oauthConfig.Google
 	case account.ProviderGitHub:
-		return &h.oauthConfig.GitHub, nil
+		config = &h.oauthConfig.GitHub
 	case account.ProviderDiscord:
-		return &h.oauthConfig.Discord, nil
+		config = &h.oauthConfig.Discord
 	default:
 		return nil, fmt.Errorf("unsupported provider: %s", provider)
 	}
+	if config.ClientID == "" {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidOperation, "sign-in with %s is not enabled", provider)
+	}
+	return config, nil
 }
 
-// buildAuthURL builds OAuth authorization URL
-func (h *AuthHandler) buildAuthURL(config *ProviderConfig, state string) string {
+// buildAuthURL builds OAuth authorization URL, binding it to a PKCE challenge when one is given
+func (h *AuthHandler) buildAuthURL(config *ProviderConfig, state, codeChallenge string) string {
 	params := url.Values{}
 	params.Set("client_id", config.ClientID)
 	params.Set("redirect_uri", config.RedirectURI)
 	params.Set("response_type", "code")
 	params.Set("scope", config.Scopes)
 	params.Set("state", state)
+	if codeChallenge != "" {
+		params.Set("code_challenge", codeChallenge)
+		params.Set("code_challenge_method", account.CodeChallengeMethodS256)
+	}
 
 	return config.AuthURL + "?" + params.Encode()
 }
 
-// exchangeCodeForToken exchanges authorization code for access token
-func (h *AuthHandler) exchangeCodeForToken(config *ProviderConfig, code string) (*OAuthTokenResponse, error) {
+// exchangeCodeForToken exchanges authorization code for access token. Providers registered
+// as public clients have no secret and rely on the PKCE verifier instead.
+func (h *AuthHandler) exchangeCodeForToken(config *ProviderConfig, code, codeVerifier string) (*OAuthTokenResponse, error) {
 	data := url.Values{}
 	data.Set("client_id", config.ClientID)
-	data.Set("client_secret", config.ClientSecret)
+	if config.ClientSecret != "" {
+		data.Set("client_secret", config.ClientSecret)
+	}
+	if codeVerifier != "" {
+		data.Set("code_verifier", codeVerifier)
+	}
 	data.Set("code", code)
 	data.Set("grant_type", "authorization_code")
 	data.Set("redirect_uri", config.RedirectURI)
@@ -670,3 +1603,38 @@ func (h *AuthHandler) OAuthStart(w http.ResponseWriter, r *http.Request) {
 func (h *AuthHandler) OAuthCallback(w http.ResponseWriter, r *http.Request) {
 	h.HandleOAuthCallback(w, r)
 }
+
+// GeneratePairCode handles pairing code generation (autorouter compatible)
+func (h *AuthHandler) GeneratePairCode(w http.ResponseWriter, r *http.Request) {
+	h.HandleGeneratePairCode(w, r)
+}
+
+// RedeemPairCode handles pairing code redemption (autorouter compatible)
+func (h *AuthHandler) RedeemPairCode(w http.ResponseWriter, r *http.Request) {
+	h.HandleRedeemPairCode(w, r)
+}
+
+// VerifyLogin handles suspicious login verification (autorouter compatible)
+func (h *AuthHandler) VerifyLogin(w http.ResponseWriter, r *http.Request) {
+	h.HandleVerifyLogin(w, r)
+}
+
+// ListProviders handles linked sign-in method listing (autorouter compatible)
+func (h *AuthHandler) ListProviders(w http.ResponseWriter, r *http.Request) {
+	h.HandleListProviders(w, r)
+}
+
+// UnlinkSocial handles social provider unlinking (autorouter compatible)
+func (h *AuthHandler) UnlinkSocial(w http.ResponseWriter, r *http.Request) {
+	h.HandleUnlinkSocial(w, r)
+}
+
+// PreviewMerge handles account merge previews (autorouter compatible)
+func (h *AuthHandler) PreviewMerge(w http.ResponseWriter, r *http.Request) {
+	h.HandlePreviewMerge(w, r)
+}
+
+// ConfirmMerge handles account merge confirmation (autorouter compatible)
+func (h *AuthHandler) ConfirmMerge(w http.ResponseWriter, r *http.Request) {
+	h.HandleConfirmMerge(w, r)
+}
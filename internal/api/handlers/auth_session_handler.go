@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// AuthSessionService interface for the sessions a user is signed in with
+type AuthSessionService interface {
+	List(ctx context.Context, userID account.UserID) ([]*account.Session, error)
+	RevokeOther(ctx context.Context, userID account.UserID, current string) (int, error)
+}
+
+// AuthSessionHandler handles signed-in session HTTP requests with JSON-RPC 2.0 format. Its
+// methods are served under the auth.Sessions. prefix.
+type AuthSessionHandler struct {
+	logger         *logger.Logger
+	sessionService AuthSessionService
+}
+
+// NewAuthSessionHandler creates a new auth session handler
+func NewAuthSessionHandler(logger *logger.Logger, sessionService AuthSessionService) *AuthSessionHandler {
+	return &AuthSessionHandler{
+		logger:         logger.WithComponent("auth-session-handler"),
+		sessionService: sessionService,
+	}
+}
+
+// Response structures for Swagger documentation
+type SessionSummary struct {
+	ID        string    `json:"id"`
+	UserAgent string    `json:"user_agent,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Current   bool      `json:"current"` // The session of the request's token
+}
+
+type SessionListResponse struct {
+	Sessions []SessionSummary `json:"sessions"`
+}
+
+type SessionRevokeOtherResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+// HandleList handles POST /api/v1/auth.Sessions.List
+// @Summary List signed-in sessions
+// @Description Get the sessions the user is signed in with, oldest first, marking the one of the request's token
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[SessionListResponse] "Signed-in sessions"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/auth.Sessions.List [post]
+func (h *AuthSessionHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	userID, current, req, ok := h.parseSessionRequest(r)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.sessionService.List(r.Context(), account.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list sessions",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to list sessions")
+		return
+	}
+
+	response := SessionListResponse{Sessions: make([]SessionSummary, 0, len(sessions))}
+	for _, session := range sessions {
+		response.Sessions = append(response.Sessions, SessionSummary{
+			ID:        session.ID,
+			UserAgent: session.UserAgent,
+			CreatedAt: session.CreatedAt,
+			ExpiresAt: session.ExpiresAt,
+			Current:   session.ID == current,
+		})
+	}
+	jsonrpcx.Success(w, req.ID, response)
+}
+
+// HandleRevokeOther handles POST /api/v1/auth.Sessions.RevokeOther
+// @Summary Sign out other sessions
+// @Description Revoke every session of the user but the one of the request's token; their tokens stop being accepted. Connections are closed for all of the user's characters, so reconnect the event stream with the current token.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[SessionRevokeOtherResponse] "Number of sessions revoked"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Token predates sessions (-32600)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/auth.Sessions.RevokeOther [post]
+func (h *AuthSessionHandler) HandleRevokeOther(w http.ResponseWriter, r *http.Request) {
+	userID, current, req, ok := h.parseSessionRequest(r)
+	if !ok {
+		return
+	}
+	if current == "" {
+		// Without a session of its own the token would be revoked along with the others
+		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidRequest, "Sign in again to manage sessions")
+		return
+	}
+
+	revoked, err := h.sessionService.RevokeOther(r.Context(), account.UserID(userID), current)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to revoke sessions",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to revoke sessions")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, SessionRevokeOtherResponse{Revoked: revoked})
+}
+
+// parseSessionRequest reads the user, the session of their token and the JSON-RPC request,
+// answering invalid requests itself
+func (h *AuthSessionHandler) parseSessionRequest(r *http.Request) (string, string, *jsonrpcx.Request, bool) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return "", "", nil, false
+	}
+
+	// Sessions belong to the user, whichever character the token plays
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return "", "", nil, false
+	}
+	sessionID, _ := middleware.GetSessionID(r.Context())
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return "", "", nil, false
+	}
+
+	return userID, sessionID, req, true
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// List handles session listing (autorouter compatible)
+func (h *AuthSessionHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.HandleList(w, r)
+}
+
+// RevokeOther handles revoking the other sessions (autorouter compatible)
+func (h *AuthSessionHandler) RevokeOther(w http.ResponseWriter, r *http.Request) {
+	h.HandleRevokeOther(w, r)
+}
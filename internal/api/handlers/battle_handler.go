@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/battle"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// BattleService interface for battles between party animals and wild animals
+type BattleService interface {
+	Start(ctx context.Context, userID trainer.UserID, animalID, wildID animal.AnimalID) (*battle.Result, error)
+	Attack(ctx context.Context, userID trainer.UserID) (*battle.Result, error)
+	Flee(ctx context.Context, userID trainer.UserID) (*battle.Result, error)
+	Get(ctx context.Context, userID trainer.UserID) (*battle.Result, error)
+}
+
+// BattleHandler handles battle-related HTTP requests with JSON-RPC 2.0 format
+type BattleHandler struct {
+	logger        *logger.Logger
+	battleService BattleService
+}
+
+// NewBattleHandler creates a new battle handler
+func NewBattleHandler(logger *logger.Logger, battleService BattleService) *BattleHandler {
+	return &BattleHandler{
+		logger:        logger.WithComponent("battle-handler"),
+		battleService: battleService,
+	}
+}
+
+// Request parameter structures
+type StartBattleRequest struct {
+	AnimalID string `json:"animal_id" validate:"required"` // Party animal to fight with
+	WildID   string `json:"wild_id" validate:"required"`   // Wild animal to challenge
+}
+
+// HandleStart handles POST /api/v1/battle.Start
+// @Summary Start a battle
+// @Description Challenge a nearby wild animal with one of the trainer's party animals
+// @Tags battle
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[StartBattleRequest] true "JSON-RPC request with StartBattleRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[battle.Result] "Started battle"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Animal not in party, target not wild, out of range or already battling"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/battle.Start [post]
+func (h *BattleHandler) HandleStart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params StartBattleRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	result, err := h.battleService.Start(r.Context(), trainer.UserID(userID), animal.AnimalID(params.AnimalID), animal.AnimalID(params.WildID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to start battle",
+			zap.String("userId", userID),
+			zap.String("wildId", params.WildID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to start battle")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// HandleAttack handles POST /api/v1/battle.Attack
+// @Summary Attack in the active battle
+// @Description Play one turn: both animals strike, the faster one first. Winning awards experience and loot.
+// @Tags battle
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[battle.Result] "Battle after the turn"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Not in a battle"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/battle.Attack [post]
+func (h *BattleHandler) HandleAttack(w http.ResponseWriter, r *http.Request) {
+	h.handleTurn(w, r, "attack", h.battleService.Attack)
+}
+
+// HandleFlee handles POST /api/v1/battle.Flee
+// @Summary Flee the active battle
+// @Description Try to escape; faster animals escape more often and a failed attempt gives the wild animal a free strike
+// @Tags battle
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[battle.Result] "Battle after the attempt"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Not in a battle"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/battle.Flee [post]
+func (h *BattleHandler) HandleFlee(w http.ResponseWriter, r *http.Request) {
+	h.handleTurn(w, r, "flee", h.battleService.Flee)
+}
+
+// HandleGet handles POST /api/v1/battle.Get
+// @Summary Get the active battle
+// @Description Get the trainer's active battle with both animals, for resuming after a reconnect
+// @Tags battle
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[battle.Result] "Active battle"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Not in a battle"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/battle.Get [post]
+func (h *BattleHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	h.handleTurn(w, r, "get", h.battleService.Get)
+}
+
+// handleTurn runs a battle action that only needs the authenticated trainer
+func (h *BattleHandler) handleTurn(w http.ResponseWriter, r *http.Request, action string, apply func(ctx context.Context, userID trainer.UserID) (*battle.Result, error)) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	result, err := apply(r.Context(), trainer.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Battle action failed",
+			zap.String("userId", userID),
+			zap.String("action", action),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Battle action failed")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Start handles battle start (autorouter compatible)
+func (h *BattleHandler) Start(w http.ResponseWriter, r *http.Request) {
+	h.HandleStart(w, r)
+}
+
+// Attack handles battle attacks (autorouter compatible)
+func (h *BattleHandler) Attack(w http.ResponseWriter, r *http.Request) {
+	h.HandleAttack(w, r)
+}
+
+// Flee handles fleeing battles (autorouter compatible)
+func (h *BattleHandler) Flee(w http.ResponseWriter, r *http.Request) {
+	h.HandleFlee(w, r)
+}
+
+// Get handles active battle retrieval (autorouter compatible)
+func (h *BattleHandler) Get(w http.ResponseWriter, r *http.Request) {
+	h.HandleGet(w, r)
+}
@@ -0,0 +1,455 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/bullet"
+	"github.com/danghamo/life/internal/domain/room"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// Bullets listed around the trainer, in world units
+const bulletViewRadius = 50.0
+
+// AmmoService interface for firing and loading ammo
+type AmmoService interface {
+	Stats(ctx context.Context, userID trainer.UserID) (*bullet.PlayerStats, error)
+	Fire(ctx context.Context, userID trainer.UserID, position shared.Position, direction bullet.Direction) (*bullet.Bullet, *bullet.PlayerStats, error)
+	Reload(ctx context.Context, userID trainer.UserID) (*bullet.PlayerStats, error)
+	BuyAmmo(ctx context.Context, userID trainer.UserID, boxes int) (*bullet.PlayerStats, int, error)
+}
+
+// HitRegistration follows fired bullets until they hit a trainer or expire
+type HitRegistration interface {
+	Track(ctx context.Context, fired *bullet.Bullet, members []string)
+}
+
+// RoomScope scopes a player's actions to the room they are in
+type RoomScope interface {
+	Scope(ctx context.Context, userID string) (context.Context, *room.Room, error)
+}
+
+// BulletHandler handles bullet-related HTTP requests with JSON-RPC 2.0 format
+type BulletHandler struct {
+	logger      *logger.Logger
+	trainerRepo trainer.Repository
+	bulletRepo  bullet.Repository
+	ammo        AmmoService
+	hits        HitRegistration
+	rooms       RoomScope
+	eventBus    *cqrs.EventBus
+	onboarding  Onboarding
+}
+
+// NewBulletHandler creates a new bullet handler
+func NewBulletHandler(
+	logger *logger.Logger,
+	trainerRepo trainer.Repository,
+	bulletRepo bullet.Repository,
+	ammo AmmoService,
+	hits HitRegistration,
+	rooms RoomScope,
+	eventBus *cqrs.EventBus,
+	onboarding Onboarding,
+) *BulletHandler {
+	return &BulletHandler{
+		logger:      logger.WithComponent("bullet-handler"),
+		trainerRepo: trainerRepo,
+		bulletRepo:  bulletRepo,
+		ammo:        ammo,
+		hits:        hits,
+		rooms:       rooms,
+		eventBus:    eventBus,
+		onboarding:  onboarding,
+	}
+}
+
+// Request parameter structures
+type FireBulletRequest struct {
+	DirectionX float64 `json:"direction_x"`
+	DirectionY float64 `json:"direction_y"`
+}
+
+type BuyAmmoRequest struct {
+	Boxes int `json:"boxes"`
+}
+
+// Response structures for Swagger documentation
+type FireBulletResponse struct {
+	Bullet *bullet.Bullet      `json:"bullet"`
+	Stats  *bullet.PlayerStats `json:"stats"`
+}
+
+type BulletListResponse struct {
+	Bullets []*bullet.Bullet `json:"bullets"`
+	Total   int              `json:"total"`
+}
+
+type BulletStatsResponse struct {
+	Stats        *bullet.PlayerStats `json:"stats"`
+	CooldownMs   int                 `json:"cooldown_ms"`
+	CanFire      bool                `json:"can_fire"`
+	MagazineSize int                 `json:"magazine_size"`
+	ReloadMs     int                 `json:"reload_ms"`
+}
+
+type BuyAmmoResponse struct {
+	BulletStatsResponse
+	Money int `json:"money"`
+}
+
+// newBulletStatsResponse describes the firing state of stats at now
+func newBulletStatsResponse(stats *bullet.PlayerStats, now time.Time) BulletStatsResponse {
+	return BulletStatsResponse{
+		Stats:        stats,
+		CooldownMs:   stats.WeaponType.GetCooldownMs(),
+		CanFire:      stats.CanFire(now),
+		MagazineSize: stats.WeaponType.MagazineSize(),
+		ReloadMs:     int(stats.WeaponType.ReloadTime().Milliseconds()),
+	}
+}
+
+// HandleFire handles POST /api/v1/bullet.Fire
+// @Summary Fire a bullet
+// @Description Fire a bullet from the authenticated trainer's position; ammo and weapon cooldown are enforced
+// @Tags bullet
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[FireBulletRequest] true "JSON-RPC request with FireBulletRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[FireBulletResponse] "Fired bullet"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid direction, no ammo, weapon reloading or cooling down"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/bullet.Fire [post]
+func (h *BulletHandler) HandleFire(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params FireBulletRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	direction := bullet.NewDirection(params.DirectionX, params.DirectionY)
+	if direction.IsZero() {
+		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Direction cannot be zero")
+		return
+	}
+
+	trainerEntity, err := h.trainerRepo.GetByID(r.Context(), trainer.UserID(userID))
+	if err != nil || trainerEntity == nil {
+		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to retrieve trainer")
+		return
+	}
+	trainerEntity.UpdatePositionFromMovement()
+
+	fired, stats, err := h.ammo.Fire(r.Context(), trainer.UserID(userID), trainerEntity.Position, direction)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Debug("Failed to fire bullet", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to fire bullet")
+		return
+	}
+	now := fired.FiredAt
+
+	// Bullets fired in a room are kept with the room and only reach its members
+	roomCtx, joined, err := h.rooms.Scope(r.Context(), userID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to load room", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve room")
+		return
+	}
+	var roomID string
+	var members []string
+	if joined != nil {
+		roomID, members = joined.ID.String(), joined.Members
+	}
+
+	if err := h.bulletRepo.Save(roomCtx, fired); err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to save bullet", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to save bullet")
+		return
+	}
+	h.hits.Track(roomCtx, fired, members)
+
+	// Publish event for SSE broadcasting
+	event := &cqrscommands.BulletFiredEvent{
+		UserID:     userID,
+		BulletID:   fired.ID.String(),
+		WeaponType: fired.WeaponType,
+		StartPos:   fired.StartPos,
+		Velocity:   fired.Velocity,
+		MaxRange:   fired.MaxRange,
+		FiredAt:    fired.FiredAt,
+		ExpiresAt:  fired.ExpiresAt,
+		RoomID:     roomID,
+		Recipients: members,
+		Timestamp:  now,
+		RequestID:  fmt.Sprintf("%s-%d", userID, now.UnixNano()),
+	}
+	if err := h.eventBus.Publish(r.Context(), event); err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to publish bullet fired event",
+			zap.String("userId", userID),
+			zap.String("bulletId", fired.ID.String()),
+			zap.Error(err))
+	}
+	h.onboarding.Fired(r.Context(), fired)
+
+	jsonrpcx.Success(w, req.ID, FireBulletResponse{Bullet: fired, Stats: stats})
+}
+
+// HandleList handles POST /api/v1/bullet.List
+// @Summary List active bullets
+// @Description Get active bullets around the authenticated trainer with their current positions
+// @Tags bullet
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[BulletListResponse] "Active bullets"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/bullet.List [post]
+func (h *BulletHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	trainerEntity, err := h.trainerRepo.GetByID(r.Context(), trainer.UserID(userID))
+	if err != nil || trainerEntity == nil {
+		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to retrieve trainer")
+		return
+	}
+	trainerEntity.UpdatePositionFromMovement()
+
+	center := trainerEntity.Position
+	topLeft := shared.NewPosition(center.X-bulletViewRadius, center.Y-bulletViewRadius)
+	bottomRight := shared.NewPosition(center.X+bulletViewRadius, center.Y+bulletViewRadius)
+
+	roomCtx, _, err := h.rooms.Scope(r.Context(), userID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to load room", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve room")
+		return
+	}
+
+	stored, err := h.bulletRepo.LoadInArea(roomCtx, topLeft, bottomRight)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list bullets", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve bullets")
+		return
+	}
+
+	// Stored positions are from fire time, so advance them to now
+	now := time.Now()
+	bullets := make([]*bullet.Bullet, 0, len(stored))
+	for _, b := range stored {
+		if err := b.UpdatePosition(now); err != nil || !b.IsActive() {
+			continue
+		}
+		bullets = append(bullets, b)
+	}
+
+	jsonrpcx.Success(w, req.ID, BulletListResponse{Bullets: bullets, Total: len(bullets)})
+}
+
+// HandleStats handles POST /api/v1/bullet.Stats
+// @Summary Get firing stats
+// @Description Get the authenticated trainer's weapon, ammo and cooldown state
+// @Tags bullet
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[BulletStatsResponse] "Firing stats"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/bullet.Stats [post]
+func (h *BulletHandler) HandleStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	stats, err := h.ammo.Stats(r.Context(), trainer.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to load player stats", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve player stats")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, newBulletStatsResponse(stats, time.Now()))
+}
+
+// HandleReload handles POST /api/v1/bullet.Reload
+// @Summary Reload weapon
+// @Description Fill the authenticated trainer's magazine from the reserve ammo, unpacking carried ammo boxes when the reserve runs short. The weapon cannot fire until the reload time has passed.
+// @Tags bullet
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[BulletStatsResponse] "Firing stats after reload"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Magazine full, already reloading or no ammo"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/bullet.Reload [post]
+func (h *BulletHandler) HandleReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	stats, err := h.ammo.Reload(r.Context(), trainer.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to reload weapon", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to reload weapon")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, newBulletStatsResponse(stats, time.Now()))
+}
+
+// HandleBuyAmmo handles POST /api/v1/bullet.BuyAmmo
+// @Summary Buy ammo
+// @Description Buy ammo boxes straight into the authenticated trainer's reserve ammo; all boxes have to fit the reserve
+// @Tags bullet
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[BuyAmmoRequest] true "JSON-RPC request with BuyAmmoRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[BuyAmmoResponse] "Firing stats and money left after the purchase"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid box count, reserve full or insufficient funds"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/bullet.BuyAmmo [post]
+func (h *BulletHandler) HandleBuyAmmo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params BuyAmmoRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	stats, money, err := h.ammo.BuyAmmo(r.Context(), trainer.UserID(userID), params.Boxes)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to buy ammo", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to buy ammo")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, BuyAmmoResponse{
+		BulletStatsResponse: newBulletStatsResponse(stats, time.Now()),
+		Money:               money,
+	})
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Fire handles bullet firing (autorouter compatible)
+func (h *BulletHandler) Fire(w http.ResponseWriter, r *http.Request) {
+	h.HandleFire(w, r)
+}
+
+// List handles active bullet listing (autorouter compatible)
+func (h *BulletHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.HandleList(w, r)
+}
+
+// Stats handles firing stats retrieval (autorouter compatible)
+func (h *BulletHandler) Stats(w http.ResponseWriter, r *http.Request) {
+	h.HandleStats(w, r)
+}
+
+// Reload handles weapon reload (autorouter compatible)
+func (h *BulletHandler) Reload(w http.ResponseWriter, r *http.Request) {
+	h.HandleReload(w, r)
+}
+
+// BuyAmmo handles ammo purchases (autorouter compatible)
+func (h *BulletHandler) BuyAmmo(w http.ResponseWriter, r *http.Request) {
+	h.HandleBuyAmmo(w, r)
+}
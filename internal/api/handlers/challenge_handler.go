@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/challenge"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// ChallengeService interface for the weekly challenges
+type ChallengeService interface {
+	List(ctx context.Context, userID trainer.UserID) (*challenge.Board, error)
+	Claim(ctx context.Context, userID trainer.UserID, id challenge.ChallengeID) (*challenge.ClaimResult, error)
+}
+
+// ChallengeHandler handles challenge-related HTTP requests with JSON-RPC 2.0 format
+type ChallengeHandler struct {
+	logger           *logger.Logger
+	challengeService ChallengeService
+}
+
+// NewChallengeHandler creates a new challenge handler
+func NewChallengeHandler(logger *logger.Logger, challengeService ChallengeService) *ChallengeHandler {
+	return &ChallengeHandler{
+		logger:           logger.WithComponent("challenge-handler"),
+		challengeService: challengeService,
+	}
+}
+
+// Request parameter structures
+type ClaimChallengeRequest struct {
+	ChallengeID string `json:"challenge_id" validate:"required"`
+}
+
+// HandleList handles POST /api/v1/challenges.List
+// @Summary List weekly challenges
+// @Description List the challenges of the current week with the trainer's progress and battle pass XP. Challenges rotate every Monday 00:00 UTC; progress is counted by the server from captures and won battles and pushed as challenge.completed when a challenge is done.
+// @Tags challenges
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[challenge.Board] "Challenges of the week"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/challenges.List [post]
+func (h *ChallengeHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	board, err := h.challengeService.List(r.Context(), trainer.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list challenges", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to list challenges")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, board)
+}
+
+// HandleClaim handles POST /api/v1/challenges.Claim
+// @Summary Claim a weekly challenge
+// @Description Claim a completed challenge of the current week, granting its battle pass XP. Each challenge is claimed once.
+// @Tags challenges
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[ClaimChallengeRequest] true "JSON-RPC request with ClaimChallengeRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[challenge.ClaimResult] "Claimed challenge and battle pass XP"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid params (-32602), challenge not active this week (-32004), or not completed or already claimed (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/challenges.Claim [post]
+func (h *ChallengeHandler) HandleClaim(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params ClaimChallengeRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	result, err := h.challengeService.Claim(r.Context(), trainer.UserID(userID), challenge.ChallengeID(params.ChallengeID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to claim challenge",
+			zap.String("userId", userID),
+			zap.String("challengeId", params.ChallengeID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to claim challenge")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// List handles listing weekly challenges (autorouter compatible)
+func (h *ChallengeHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.HandleList(w, r)
+}
+
+// Claim handles claiming a weekly challenge (autorouter compatible)
+func (h *ChallengeHandler) Claim(w http.ResponseWriter, r *http.Request) {
+	h.HandleClaim(w, r)
+}
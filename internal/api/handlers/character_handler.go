@@ -0,0 +1,412 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/character"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// CharacterService interface for the trainers a user plays
+type CharacterService interface {
+	List(ctx context.Context, userID trainer.UserID) ([]*trainer.Trainer, *character.Roster, error)
+	Create(ctx context.Context, userID trainer.UserID, nickname string, appearance character.Appearance) (*trainer.Trainer, error)
+	Select(ctx context.Context, userID, characterID, playing trainer.UserID) (*trainer.Trainer, error)
+	Delete(ctx context.Context, userID, characterID, playing trainer.UserID) (time.Time, error)
+	Restore(ctx context.Context, userID, characterID trainer.UserID) error
+	BuySlot(ctx context.Context, userID trainer.UserID) (*character.Roster, error)
+}
+
+// CharacterHandler handles character HTTP requests with JSON-RPC 2.0 format
+type CharacterHandler struct {
+	logger           *logger.Logger
+	characterService CharacterService
+	jwtService       *account.JWTService
+}
+
+// NewCharacterHandler creates a new character handler issuing tokens for selected characters
+// with jwtService
+func NewCharacterHandler(logger *logger.Logger, characterService CharacterService, jwtService *account.JWTService) *CharacterHandler {
+	return &CharacterHandler{
+		logger:           logger.WithComponent("character-handler"),
+		characterService: characterService,
+		jwtService:       jwtService,
+	}
+}
+
+// Request parameter structures
+type CreateCharacterRequest struct {
+	Nickname   string               `json:"nickname" validate:"required"`
+	Appearance character.Appearance `json:"appearance"`
+}
+
+type SelectCharacterRequest struct {
+	CharacterID string `json:"character_id" validate:"required"`
+}
+
+type DeleteCharacterRequest struct {
+	CharacterID string `json:"character_id" validate:"required"`
+}
+
+type RestoreCharacterRequest struct {
+	CharacterID string `json:"character_id" validate:"required"`
+}
+
+// Response structures for Swagger documentation
+type CharacterSummary struct {
+	ID       string `json:"id"`
+	Nickname string `json:"nickname"`
+	Color    string `json:"color"`
+	Level    int    `json:"level"`
+	Active   bool   `json:"active"` // Played by new sessions
+
+	PurgeAt *time.Time `json:"purge_at,omitempty"` // When a deleted character is gone for good; restorable until then
+}
+
+type CharacterListResponse struct {
+	Characters []CharacterSummary `json:"characters"`
+	Slots      int                `json:"slots"`     // Characters the user can have, pending deletions included
+	MaxSlots   int                `json:"max_slots"` // Slots the user can own at most
+	SlotPrice  int                `json:"slot_price"`
+	Gems       int                `json:"gems"`
+}
+
+type CharacterCreateResponse struct {
+	Character CharacterSummary `json:"character"`
+}
+
+type CharacterSelectResponse struct {
+	JWTToken  string           `json:"jwt_token"`
+	ExpiresIn int64            `json:"expires_in"`
+	Character CharacterSummary `json:"character"`
+	Money     int              `json:"money"` // The user's money, now carried by the character
+}
+
+type CharacterDeleteResponse struct {
+	CharacterID string    `json:"character_id"`
+	PurgeAt     time.Time `json:"purge_at"`
+}
+
+type CharacterRestoreResponse struct {
+	CharacterID string `json:"character_id"`
+}
+
+type CharacterBuySlotResponse struct {
+	Slots int `json:"slots"`
+	Gems  int `json:"gems"`
+}
+
+// HandleList handles POST /api/v1/character.List
+// @Summary List characters
+// @Description Get the trainers the user can play, the first one first, which one new sessions play, the characters pending deletion, and the user's slots and gems
+// @Tags character
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[CharacterListResponse] "Characters"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/character.List [post]
+func (h *CharacterHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseCharacterRequest(r)
+	if !ok {
+		return
+	}
+
+	characters, roster, err := h.characterService.List(r.Context(), trainer.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list characters",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to list characters")
+		return
+	}
+
+	response := CharacterListResponse{
+		Characters: make([]CharacterSummary, 0, len(characters)),
+		Slots:      roster.Slots(),
+		MaxSlots:   character.MaxSlots,
+		SlotPrice:  character.SlotPrice,
+		Gems:       roster.Gems,
+	}
+	for _, t := range characters {
+		summary := newCharacterSummary(t, roster.ActiveCharacter())
+		if purgeAt, ok := roster.Deleting[t.ID]; ok {
+			summary.PurgeAt = &purgeAt
+		}
+		response.Characters = append(response.Characters, summary)
+	}
+	jsonrpcx.Success(w, req.ID, response)
+}
+
+// HandleCreate handles POST /api/v1/character.Create
+// @Summary Create a character
+// @Description Add a trainer with its own position, inventory and progress to the user, optionally with a color from the trainer palette. It shares the user's vault and money and starts without money of its own; select it to play it. Error data carries the domain code and reason, e.g. CHARACTER_LIMIT when every slot is taken.
+// @Tags character
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[CreateCharacterRequest] true "JSON-RPC request with CreateCharacterRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[CharacterCreateResponse] "Created character"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid nickname or color, nickname taken or too many characters (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/character.Create [post]
+func (h *CharacterHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseCharacterRequest(r)
+	if !ok {
+		return
+	}
+
+	var params CreateCharacterRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	created, err := h.characterService.Create(r.Context(), trainer.UserID(userID), params.Nickname, params.Appearance)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to create character",
+			zap.String("userId", userID),
+			zap.String("nickname", params.Nickname),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to create character")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, CharacterCreateResponse{Character: newCharacterSummary(created, "")})
+}
+
+// HandleSelect handles POST /api/v1/character.Select
+// @Summary Select a character
+// @Description Play another of the user's trainers. The response carries a token for it, which later sign-ins also get; reconnect the event stream with it. The user's money is carried over to the selected character, and the connections of the character played before are closed.
+// @Tags character
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[SelectCharacterRequest] true "JSON-RPC request with SelectCharacterRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[CharacterSelectResponse] "Token for the selected character"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Character not found (-32004)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/character.Select [post]
+func (h *CharacterHandler) HandleSelect(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseCharacterRequest(r)
+	if !ok {
+		return
+	}
+	playing, _ := middleware.GetUserID(r.Context())
+
+	var params SelectCharacterRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	selected, err := h.characterService.Select(r.Context(), trainer.UserID(userID), trainer.UserID(params.CharacterID), trainer.UserID(playing))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to select character",
+			zap.String("userId", userID),
+			zap.String("characterId", params.CharacterID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to select character")
+		return
+	}
+
+	email, _ := middleware.GetUserEmail(r.Context())
+	name, _ := middleware.GetUserName(r.Context())
+	sessionID, _ := middleware.GetSessionID(r.Context())
+	jwtToken, err := h.jwtService.GenerateCharacterToken(r.Context(), account.UserID(userID), email, name, selected.ID.String(), sessionID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to generate JWT token", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to generate JWT token")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, CharacterSelectResponse{
+		JWTToken:  jwtToken,
+		ExpiresIn: 86400, // 24 hours
+		Character: newCharacterSummary(selected, selected.ID),
+		Money:     selected.Money.Amount(),
+	})
+}
+
+// HandleDelete handles POST /api/v1/character.Delete
+// @Summary Delete a character
+// @Description Schedule one of the user's additional characters for deletion. It can be restored for 7 days, keeping its slot meanwhile, and is then deleted for good with its animals, equipment and progress. Its money goes to the first character, which new sessions play if the deleted one was selected. The first character can't be deleted, nor the one the caller plays; sessions playing it elsewhere are disconnected.
+// @Tags character
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[DeleteCharacterRequest] true "JSON-RPC request with DeleteCharacterRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[CharacterDeleteResponse] "Character pending deletion"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Character not found (-32004) or can't be deleted"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/character.Delete [post]
+func (h *CharacterHandler) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseCharacterRequest(r)
+	if !ok {
+		return
+	}
+	playing, _ := middleware.GetUserID(r.Context())
+
+	var params DeleteCharacterRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	purgeAt, err := h.characterService.Delete(r.Context(), trainer.UserID(userID), trainer.UserID(params.CharacterID), trainer.UserID(playing))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to delete character",
+			zap.String("userId", userID),
+			zap.String("characterId", params.CharacterID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to delete character")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, CharacterDeleteResponse{CharacterID: params.CharacterID, PurgeAt: purgeAt})
+}
+
+// HandleRestore handles POST /api/v1/character.Restore
+// @Summary Restore a deleted character
+// @Description Cancel the deletion of a character while its 7-day grace period lasts. It comes back as it was, without money; select it to play it.
+// @Tags character
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[RestoreCharacterRequest] true "JSON-RPC request with RestoreCharacterRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[CharacterRestoreResponse] "Character restored"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "No such character pending deletion (-32004) or grace period over"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/character.Restore [post]
+func (h *CharacterHandler) HandleRestore(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseCharacterRequest(r)
+	if !ok {
+		return
+	}
+
+	var params RestoreCharacterRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	if err := h.characterService.Restore(r.Context(), trainer.UserID(userID), trainer.UserID(params.CharacterID)); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to restore character",
+			zap.String("userId", userID),
+			zap.String("characterId", params.CharacterID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to restore character")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, CharacterRestoreResponse{CharacterID: params.CharacterID})
+}
+
+// HandleBuySlot handles POST /api/v1/character.BuySlot
+// @Summary Buy a character slot
+// @Description Spend gems, the premium currency, on one more character slot. The price and the most slots a user can own are in character.List.
+// @Tags character
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[CharacterBuySlotResponse] "Slots and gems after the purchase"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Not enough gems, purchase refused or most slots owned (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/character.BuySlot [post]
+func (h *CharacterHandler) HandleBuySlot(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseCharacterRequest(r)
+	if !ok {
+		return
+	}
+
+	roster, err := h.characterService.BuySlot(r.Context(), trainer.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to buy character slot",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to buy character slot")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, CharacterBuySlotResponse{Slots: roster.Slots(), Gems: roster.Gems})
+}
+
+// parseCharacterRequest reads the user and the JSON-RPC request, answering invalid requests
+// itself
+func (h *CharacterHandler) parseCharacterRequest(r *http.Request) (string, *jsonrpcx.Request, bool) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return "", nil, false
+	}
+
+	// Characters belong to the user, whichever one the token plays
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return "", nil, false
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return "", nil, false
+	}
+
+	return userID, req, true
+}
+
+// newCharacterSummary describes a character for the character screen
+func newCharacterSummary(t *trainer.Trainer, active trainer.UserID) CharacterSummary {
+	return CharacterSummary{
+		ID:       t.ID.String(),
+		Nickname: t.Nickname,
+		Color:    t.Color,
+		Level:    t.Level.Value(),
+		Active:   t.ID == active,
+	}
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// List handles character listing (autorouter compatible)
+func (h *CharacterHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.HandleList(w, r)
+}
+
+// Create handles character creation (autorouter compatible)
+func (h *CharacterHandler) Create(w http.ResponseWriter, r *http.Request) {
+	h.HandleCreate(w, r)
+}
+
+// Select handles character selection (autorouter compatible)
+func (h *CharacterHandler) Select(w http.ResponseWriter, r *http.Request) {
+	h.HandleSelect(w, r)
+}
+
+// Delete handles character deletion (autorouter compatible)
+func (h *CharacterHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	h.HandleDelete(w, r)
+}
+
+// Restore handles restoring a deleted character (autorouter compatible)
+func (h *CharacterHandler) Restore(w http.ResponseWriter, r *http.Request) {
+	h.HandleRestore(w, r)
+}
+
+// BuySlot handles buying a character slot (autorouter compatible)
+func (h *CharacterHandler) BuySlot(w http.ResponseWriter, r *http.Request) {
+	h.HandleBuySlot(w, r)
+}
@@ -0,0 +1,200 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/chat"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// ChatService interface for chat messages between players
+type ChatService interface {
+	SendGlobal(ctx context.Context, userID trainer.UserID, text string) (*chat.Message, error)
+	SendNearby(ctx context.Context, userID trainer.UserID, text string) (*chat.Message, error)
+	SendWhisper(ctx context.Context, userID trainer.UserID, nickname, text string) (*chat.Message, error)
+}
+
+// ChatHandler handles chat HTTP requests with JSON-RPC 2.0 format
+type ChatHandler struct {
+	logger      *logger.Logger
+	chatService ChatService
+}
+
+// NewChatHandler creates a new chat handler
+func NewChatHandler(logger *logger.Logger, chatService ChatService) *ChatHandler {
+	return &ChatHandler{
+		logger:      logger.WithComponent("chat-handler"),
+		chatService: chatService,
+	}
+}
+
+// Request parameter structures
+type ChatSendRequest struct {
+	Text string `json:"text"`
+}
+
+type ChatWhisperRequest struct {
+	Nickname string `json:"nickname" validate:"required"`
+	Text     string `json:"text"`
+}
+
+// Response structures for Swagger documentation
+type ChatSendResponse struct {
+	Message *chat.Message `json:"message"`
+}
+
+// HandleSendGlobal handles POST /api/v1/chat.SendGlobal
+// @Summary Send a global chat message
+// @Description Send a message of up to 200 characters to every connected player, delivered as chat.message notifications. Players may send 5 messages per 10 seconds and not repeat their previous message.
+// @Tags chat
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[ChatSendRequest] true "JSON-RPC request with ChatSendRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[ChatSendResponse] "Message sent"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Empty or too long message (-32602), trainer not found (-32004) or sending too fast (-32002)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/chat.SendGlobal [post]
+func (h *ChatHandler) HandleSendGlobal(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseChatRequest(r)
+	if !ok {
+		return
+	}
+
+	var params ChatSendRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	message, err := h.chatService.SendGlobal(r.Context(), trainer.UserID(userID), params.Text)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to send global chat message",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to send message")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, ChatSendResponse{Message: message})
+}
+
+// HandleSendNearby handles POST /api/v1/chat.SendNearby
+// @Summary Send a nearby chat message
+// @Description Send a message to the trainers within 15 units of your trainer, delivered as chat.message notifications with the position it was said at. Same limits as chat.SendGlobal.
+// @Tags chat
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[ChatSendRequest] true "JSON-RPC request with ChatSendRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[ChatSendResponse] "Message sent"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Empty or too long message (-32602), trainer not found (-32004) or sending too fast (-32002)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/chat.SendNearby [post]
+func (h *ChatHandler) HandleSendNearby(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseChatRequest(r)
+	if !ok {
+		return
+	}
+
+	var params ChatSendRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	message, err := h.chatService.SendNearby(r.Context(), trainer.UserID(userID), params.Text)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to send nearby chat message",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to send message")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, ChatSendResponse{Message: message})
+}
+
+// HandleSendWhisper handles POST /api/v1/chat.SendWhisper
+// @Summary Whisper to a player
+// @Description Send a message to the trainer with a nickname only. Both of you receive it as a chat.message notification. Same limits as chat.SendGlobal.
+// @Tags chat
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[ChatWhisperRequest] true "JSON-RPC request with ChatWhisperRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[ChatSendResponse] "Message sent"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Empty or too long message or whispering to yourself (-32602), trainer not found (-32004) or sending too fast (-32002)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/chat.SendWhisper [post]
+func (h *ChatHandler) HandleSendWhisper(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseChatRequest(r)
+	if !ok {
+		return
+	}
+
+	var params ChatWhisperRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	message, err := h.chatService.SendWhisper(r.Context(), trainer.UserID(userID), params.Nickname, params.Text)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to send whisper",
+			zap.String("userId", userID),
+			zap.String("nickname", params.Nickname),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to send message")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, ChatSendResponse{Message: message})
+}
+
+// parseChatRequest reads the caller and the JSON-RPC request, answering invalid requests itself
+func (h *ChatHandler) parseChatRequest(r *http.Request) (string, *jsonrpcx.Request, bool) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return "", nil, false
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return "", nil, false
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return "", nil, false
+	}
+
+	return userID, req, true
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// SendGlobal handles sending a global message (autorouter compatible)
+func (h *ChatHandler) SendGlobal(w http.ResponseWriter, r *http.Request) {
+	h.HandleSendGlobal(w, r)
+}
+
+// SendNearby handles sending a nearby message (autorouter compatible)
+func (h *ChatHandler) SendNearby(w http.ResponseWriter, r *http.Request) {
+	h.HandleSendNearby(w, r)
+}
+
+// SendWhisper handles whispering to a player (autorouter compatible)
+func (h *ChatHandler) SendWhisper(w http.ResponseWriter, r *http.Request) {
+	h.HandleSendWhisper(w, r)
+}
@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/crafting"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// CraftingService interface for crafting items and equipment from materials
+type CraftingService interface {
+	ListRecipes() []*crafting.Recipe
+	GetJobs(ctx context.Context, trainerID trainer.UserID) ([]*crafting.Job, error)
+	StartCraft(ctx context.Context, trainerID trainer.UserID, recipeID crafting.RecipeID) (*crafting.Job, error)
+	Collect(ctx context.Context, trainerID trainer.UserID, jobID crafting.JobID) (*crafting.Result, error)
+}
+
+// CraftHandler handles crafting-related HTTP requests with JSON-RPC 2.0 format
+type CraftHandler struct {
+	logger          *logger.Logger
+	craftingService CraftingService
+}
+
+// NewCraftHandler creates a new crafting handler
+func NewCraftHandler(logger *logger.Logger, craftingService CraftingService) *CraftHandler {
+	return &CraftHandler{
+		logger:          logger.WithComponent("craft-handler"),
+		craftingService: craftingService,
+	}
+}
+
+// Request parameter structures
+type StartCraftRequest struct {
+	RecipeID string `json:"recipe_id" validate:"required"`
+}
+
+type CollectCraftRequest struct {
+	JobID string `json:"job_id" validate:"required"`
+}
+
+// Response structures for Swagger documentation
+type RecipesResponse struct {
+	Recipes []*crafting.Recipe `json:"recipes"`
+	Total   int                `json:"total"`
+}
+
+type StartCraftResponse struct {
+	Job *crafting.Job `json:"job"`
+}
+
+type CraftJobsResponse struct {
+	Jobs  []*crafting.Job `json:"jobs"`
+	Total int             `json:"total"`
+}
+
+// HandleRecipes handles POST /api/v1/craft.Recipes
+// @Summary List crafting recipes
+// @Description Get all recipes with their materials, output, craft time and level requirement
+// @Tags craft
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[RecipesResponse] "Crafting recipes"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid request"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/craft.Recipes [post]
+func (h *CraftHandler) HandleRecipes(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	recipes := h.craftingService.ListRecipes()
+
+	result := RecipesResponse{
+		Recipes: recipes,
+		Total:   len(recipes),
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// HandleStart handles POST /api/v1/craft.Start
+// @Summary Start crafting
+// @Description Consume the recipe materials from the authenticated trainer's inventory and start a timed crafting job
+// @Tags craft
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[StartCraftRequest] true "JSON-RPC request with StartCraftRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[StartCraftResponse] "Started crafting job"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Unknown recipe, level too low or missing materials"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/craft.Start [post]
+func (h *CraftHandler) HandleStart(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params StartCraftRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	job, err := h.craftingService.StartCraft(r.Context(), trainer.UserID(userID), crafting.RecipeID(params.RecipeID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to start craft",
+			zap.String("userId", userID),
+			zap.String("recipeId", params.RecipeID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to start craft")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, StartCraftResponse{Job: job})
+}
+
+// HandleCollect handles POST /api/v1/craft.Collect
+// @Summary Collect a crafting result
+// @Description Collect a finished crafting job; successful crafts add the item to the inventory or create the equipment
+// @Tags craft
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[CollectCraftRequest] true "JSON-RPC request with CollectCraftRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[crafting.Result] "Collected crafting result"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Job not found, not finished or already collected"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/craft.Collect [post]
+func (h *CraftHandler) HandleCollect(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params CollectCraftRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	result, err := h.craftingService.Collect(r.Context(), trainer.UserID(userID), crafting.JobID(params.JobID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to collect craft",
+			zap.String("userId", userID),
+			zap.String("jobId", params.JobID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to collect craft")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// HandleJobs handles POST /api/v1/craft.Jobs
+// @Summary List crafting jobs
+// @Description Get the authenticated trainer's uncollected crafting jobs
+// @Tags craft
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[CraftJobsResponse] "Crafting jobs"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/craft.Jobs [post]
+func (h *CraftHandler) HandleJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	jobs, err := h.craftingService.GetJobs(r.Context(), trainer.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list crafting jobs", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve crafting jobs")
+		return
+	}
+
+	if jobs == nil {
+		jobs = []*crafting.Job{}
+	}
+
+	result := CraftJobsResponse{
+		Jobs:  jobs,
+		Total: len(jobs),
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Recipes handles recipe listing (autorouter compatible)
+func (h *CraftHandler) Recipes(w http.ResponseWriter, r *http.Request) {
+	h.HandleRecipes(w, r)
+}
+
+// Start handles starting a crafting job (autorouter compatible)
+func (h *CraftHandler) Start(w http.ResponseWriter, r *http.Request) {
+	h.HandleStart(w, r)
+}
+
+// Collect handles collecting a crafting result (autorouter compatible)
+func (h *CraftHandler) Collect(w http.ResponseWriter, r *http.Request) {
+	h.HandleCollect(w, r)
+}
+
+// Jobs handles crafting job listing (autorouter compatible)
+func (h *CraftHandler) Jobs(w http.ResponseWriter, r *http.Request) {
+	h.HandleJobs(w, r)
+}
@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+)
+
+// DeprecationHandler lists deprecated JSON-RPC methods, so client teams can track removals
+type DeprecationHandler struct {
+	deprecations *middleware.Deprecations
+}
+
+// NewDeprecationHandler creates a new deprecation handler
+func NewDeprecationHandler(deprecations *middleware.Deprecations) *DeprecationHandler {
+	return &DeprecationHandler{deprecations: deprecations}
+}
+
+// DeprecationInfo describes a deprecated method
+type DeprecationInfo struct {
+	Method      string `json:"method"`
+	Sunset      string `json:"sunset"` // YYYY-MM-DD
+	DaysLeft    int    `json:"days_left"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// DeprecationListResponse represents the deprecated methods, soonest sunset first
+type DeprecationListResponse struct {
+	Deprecations []DeprecationInfo `json:"deprecations"`
+}
+
+// HandleList handles POST /api/v1/deprecations.List
+func (h *DeprecationHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	now := time.Now()
+	deprecations := h.deprecations.List()
+	response := DeprecationListResponse{Deprecations: make([]DeprecationInfo, 0, len(deprecations))}
+	for _, d := range deprecations {
+		response.Deprecations = append(response.Deprecations, DeprecationInfo{
+			Method:      d.Method,
+			Sunset:      d.Sunset.Format(middleware.SunsetLayout),
+			DaysLeft:    max(0, int(d.Sunset.Sub(now).Hours()/24)),
+			Replacement: d.Replacement,
+		})
+	}
+
+	jsonrpcx.Success(w, req.ID, response)
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// List handles deprecation listing (autorouter compatible)
+func (h *DeprecationHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.HandleList(w, r)
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// EmailService interface for contact addresses and their verification
+type EmailService interface {
+	Get(ctx context.Context, userID string) (*account.Email, error)
+	Change(ctx context.Context, userID, address string) (*account.Email, error)
+	ResendVerification(ctx context.Context, userID string) (*account.Email, error)
+	Verify(ctx context.Context, token string) (*account.Email, error)
+}
+
+// EmailHandler handles email HTTP requests with JSON-RPC 2.0 format. Its methods are served
+// under the auth. prefix next to AuthHandler's.
+type EmailHandler struct {
+	logger       *logger.Logger
+	emailService EmailService
+}
+
+// NewEmailHandler creates a new email handler
+func NewEmailHandler(logger *logger.Logger, emailService EmailService) *EmailHandler {
+	return &EmailHandler{
+		logger:       logger.WithComponent("email-handler"),
+		emailService: emailService,
+	}
+}
+
+// ChangeEmailRequest represents an email address change
+type ChangeEmailRequest struct {
+	Email string `json:"email"`
+}
+
+// VerifyEmailRequest represents a verification link being opened
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// EmailResponse represents the player's email address and whether it is verified
+type EmailResponse struct {
+	Email    string `json:"email,omitempty"`
+	Verified bool   `json:"verified"`
+}
+
+// HandleGetEmail handles POST /api/v1/auth.GetEmail
+// @Summary Get email address
+// @Description Get the player's email address and whether it is verified.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[EmailResponse] "Email address and verification state"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/auth.GetEmail [post]
+func (h *EmailHandler) HandleGetEmail(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, "get", func(ctx context.Context, userID string, _ json.RawMessage) (*account.Email, error) {
+		return h.emailService.Get(ctx, userID)
+	})
+}
+
+// HandleChangeEmail handles POST /api/v1/auth.ChangeEmail
+// @Summary Set or change email address
+// @Description Set the player's email address and send a verification link to it. The address stays unverified until the link is opened, and a previous address is notified of the change.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[ChangeEmailRequest] true "JSON-RPC request with ChangeEmailRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[EmailResponse] "Updated email address"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid or unchanged email address"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/auth.ChangeEmail [post]
+func (h *EmailHandler) HandleChangeEmail(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, "change", func(ctx context.Context, userID string, raw json.RawMessage) (*account.Email, error) {
+		var params ChangeEmailRequest
+		if err := json.Unmarshal(raw, &params); err != nil {
+			return nil, err
+		}
+		return h.emailService.Change(ctx, userID, params.Email)
+	})
+}
+
+// HandleResendEmailVerification handles POST /api/v1/auth.ResendEmailVerification
+// @Summary Resend email verification
+// @Description Send a new verification link to the player's unverified email address
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[EmailResponse] "Email address the link was sent to"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "No address set or already verified"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/auth.ResendEmailVerification [post]
+func (h *EmailHandler) HandleResendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, "resend", func(ctx context.Context, userID string, _ json.RawMessage) (*account.Email, error) {
+		return h.emailService.ResendVerification(ctx, userID)
+	})
+}
+
+// HandleVerifyEmail handles POST /api/v1/auth.VerifyEmail
+// @Summary Verify email address
+// @Description Verify an email address with the token from a verification link. No sign-in is needed, so the link works from any device.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[VerifyEmailRequest] true "JSON-RPC request with VerifyEmailRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[EmailResponse] "Verified email address"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid, expired or outdated verification link"
+// @Router /api/v1/auth.VerifyEmail [post]
+func (h *EmailHandler) HandleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params VerifyEmailRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	email, err := h.emailService.Verify(r.Context(), params.Token)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Email verification failed", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Email verification failed")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, newEmailResponse(email))
+}
+
+// handle runs an email action for the authenticated player
+func (h *EmailHandler) handle(w http.ResponseWriter, r *http.Request, action string, apply func(ctx context.Context, userID string, params json.RawMessage) (*account.Email, error)) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	email, err := apply(r.Context(), userID, req.Params)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Email action failed",
+			zap.String("userId", userID),
+			zap.String("action", action),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Email action failed")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, newEmailResponse(email))
+}
+
+// newEmailResponse converts an email, which is nil when none is set
+func newEmailResponse(email *account.Email) EmailResponse {
+	if email == nil {
+		return EmailResponse{}
+	}
+	return EmailResponse{
+		Email:    email.Address,
+		Verified: email.IsVerified(),
+	}
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// GetEmail handles email retrieval (autorouter compatible)
+func (h *EmailHandler) GetEmail(w http.ResponseWriter, r *http.Request) {
+	h.HandleGetEmail(w, r)
+}
+
+// ChangeEmail handles email changes (autorouter compatible)
+func (h *EmailHandler) ChangeEmail(w http.ResponseWriter, r *http.Request) {
+	h.HandleChangeEmail(w, r)
+}
+
+// ResendEmailVerification handles verification resends (autorouter compatible)
+func (h *EmailHandler) ResendEmailVerification(w http.ResponseWriter, r *http.Request) {
+	h.HandleResendEmailVerification(w, r)
+}
+
+// VerifyEmail handles email verification (autorouter compatible)
+func (h *EmailHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	h.HandleVerifyEmail(w, r)
+}
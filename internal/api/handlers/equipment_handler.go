@@ -0,0 +1,286 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/crafting"
+	"github.com/danghamo/life/internal/domain/equipment"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// EquipmentService interface for crafting equipment and equipping it on animals
+type EquipmentService interface {
+	List(ctx context.Context, trainerID trainer.UserID) ([]*equipment.Equipment, error)
+	Craft(ctx context.Context, trainerID trainer.UserID, recipeID crafting.RecipeID) (*crafting.Job, error)
+	Equip(ctx context.Context, trainerID trainer.UserID, equipmentID equipment.EquipmentID, animalID animal.AnimalID) (*animal.Animal, error)
+	Unequip(ctx context.Context, trainerID trainer.UserID, animalID animal.AnimalID) (*animal.Animal, error)
+}
+
+// EquipmentHandler handles equipment-related HTTP requests with JSON-RPC 2.0 format
+type EquipmentHandler struct {
+	logger           *logger.Logger
+	equipmentService EquipmentService
+}
+
+// NewEquipmentHandler creates a new equipment handler
+func NewEquipmentHandler(logger *logger.Logger, equipmentService EquipmentService) *EquipmentHandler {
+	return &EquipmentHandler{
+		logger:           logger.WithComponent("equipment-handler"),
+		equipmentService: equipmentService,
+	}
+}
+
+// Request parameter structures
+type CraftEquipmentRequest struct {
+	RecipeID string `json:"recipe_id" validate:"required"`
+}
+
+type EquipRequest struct {
+	EquipmentID string `json:"equipment_id" validate:"required"`
+	AnimalID    string `json:"animal_id" validate:"required"`
+}
+
+type UnequipRequest struct {
+	AnimalID string `json:"animal_id" validate:"required"`
+}
+
+// Response structures for Swagger documentation
+type EquipmentListResponse struct {
+	Equipment []*equipment.Equipment `json:"equipment"`
+	Total     int                    `json:"total"`
+}
+
+type EquipResponse struct {
+	Animal *animal.Animal `json:"animal"`
+}
+
+// HandleList handles POST /api/v1/equipment.List
+// @Summary List equipment
+// @Description Get the authenticated trainer's equipment; equipped pieces have the animal in owner_id
+// @Tags equipment
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[EquipmentListResponse] "Equipment"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/equipment.List [post]
+func (h *EquipmentHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	items, err := h.equipmentService.List(r.Context(), trainer.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list equipment", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve equipment")
+		return
+	}
+
+	if items == nil {
+		items = []*equipment.Equipment{}
+	}
+
+	result := EquipmentListResponse{
+		Equipment: items,
+		Total:     len(items),
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// HandleCraft handles POST /api/v1/equipment.Craft
+// @Summary Craft equipment
+// @Description Consume an equipment recipe's materials, such as animal hides and rare gems, from the authenticated trainer's inventory and start a timed crafting job; collect it with craft.Collect
+// @Tags equipment
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[CraftEquipmentRequest] true "JSON-RPC request with CraftEquipmentRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[StartCraftResponse] "Started crafting job"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Unknown or non-equipment recipe, level too low or missing materials"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/equipment.Craft [post]
+func (h *EquipmentHandler) HandleCraft(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params CraftEquipmentRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	job, err := h.equipmentService.Craft(r.Context(), trainer.UserID(userID), crafting.RecipeID(params.RecipeID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to craft equipment",
+			zap.String("userId", userID),
+			zap.String("recipeId", params.RecipeID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to craft equipment")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, StartCraftResponse{Job: job})
+}
+
+// HandleEquip handles POST /api/v1/equipment.Equip
+// @Summary Equip an animal
+// @Description Put a piece of the authenticated trainer's equipment on one of their animals, adding its stats; the animal's previous equipment is taken off
+// @Tags equipment
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[EquipRequest] true "JSON-RPC request with EquipRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[EquipResponse] "Equipped animal"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Equipment or animal not found, or equipment worn by another animal"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/equipment.Equip [post]
+func (h *EquipmentHandler) HandleEquip(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params EquipRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	equipped, err := h.equipmentService.Equip(r.Context(), trainer.UserID(userID), equipment.EquipmentID(params.EquipmentID), animal.AnimalID(params.AnimalID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to equip animal",
+			zap.String("userId", userID),
+			zap.String("equipmentId", params.EquipmentID),
+			zap.String("animalId", params.AnimalID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to equip animal")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, EquipResponse{Animal: equipped})
+}
+
+// HandleUnequip handles POST /api/v1/equipment.Unequip
+// @Summary Unequip an animal
+// @Description Take the equipment off one of the authenticated trainer's animals, removing its stats
+// @Tags equipment
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[UnequipRequest] true "JSON-RPC request with UnequipRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[EquipResponse] "Unequipped animal"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Animal not found or not wearing equipment"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/equipment.Unequip [post]
+func (h *EquipmentHandler) HandleUnequip(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params UnequipRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	unequipped, err := h.equipmentService.Unequip(r.Context(), trainer.UserID(userID), animal.AnimalID(params.AnimalID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to unequip animal",
+			zap.String("userId", userID),
+			zap.String("animalId", params.AnimalID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to unequip animal")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, EquipResponse{Animal: unequipped})
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// List handles equipment listing (autorouter compatible)
+func (h *EquipmentHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.HandleList(w, r)
+}
+
+// Craft handles equipment crafting (autorouter compatible)
+func (h *EquipmentHandler) Craft(w http.ResponseWriter, r *http.Request) {
+	h.HandleCraft(w, r)
+}
+
+// Equip handles equipping an animal (autorouter compatible)
+func (h *EquipmentHandler) Equip(w http.ResponseWriter, r *http.Request) {
+	h.HandleEquip(w, r)
+}
+
+// Unequip handles unequipping an animal (autorouter compatible)
+func (h *EquipmentHandler) Unequip(w http.ResponseWriter, r *http.Request) {
+	h.HandleUnequip(w, r)
+}
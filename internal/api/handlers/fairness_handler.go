@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/fairness"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// RandomnessService interface for auditing game rolls
+type RandomnessService interface {
+	Commitments(ctx context.Context) (*fairness.Commitments, error)
+	History(ctx context.Context, userID string, limit int) ([]*fairness.Roll, error)
+	Verify(ctx context.Context, userID string, rollID fairness.RollID) (*fairness.Verification, error)
+}
+
+// FairnessHandler handles roll audit HTTP requests with JSON-RPC 2.0 format
+type FairnessHandler struct {
+	logger            *logger.Logger
+	randomnessService RandomnessService
+}
+
+// NewFairnessHandler creates a new fairness handler
+func NewFairnessHandler(logger *logger.Logger, randomnessService RandomnessService) *FairnessHandler {
+	return &FairnessHandler{
+		logger:            logger.WithComponent("fairness-handler"),
+		randomnessService: randomnessService,
+	}
+}
+
+// Request parameter structures
+type RollHistoryRequest struct {
+	Limit int `json:"limit,omitempty"`
+}
+
+type VerifyRollRequest struct {
+	RollID string `json:"roll_id" validate:"required"`
+}
+
+// Response structures for Swagger documentation
+type RollHistoryResponse struct {
+	Rolls []*fairness.Roll `json:"rolls"`
+}
+
+// HandleCommitment handles POST /api/v1/rng.Commitment
+// @Summary Get seed commitments
+// @Description Get the commitment of the server seed in use and the previous seed with its revealed secret
+// @Tags rng
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[fairness.Commitments] "Seed commitments"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/rng.Commitment [post]
+func (h *FairnessHandler) HandleCommitment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	commitments, err := h.randomnessService.Commitments(r.Context())
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get seed commitments", zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve seed commitments")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, commitments)
+}
+
+// HandleHistory handles POST /api/v1/rng.History
+// @Summary List recent rolls
+// @Description Get the authenticated user's recent rolls with their inputs, raw draws and outcomes
+// @Tags rng
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[RollHistoryRequest] true "JSON-RPC request with RollHistoryRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[RollHistoryResponse] "Recent rolls"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/rng.History [post]
+func (h *FairnessHandler) HandleHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params RollHistoryRequest
+	if len(req.Params) > 0 {
+		if !jsonrpcx.BindParams(r, req, &params) {
+			return
+		}
+	}
+
+	rolls, err := h.randomnessService.History(r.Context(), userID, params.Limit)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get roll history", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve roll history")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, RollHistoryResponse{Rolls: rolls})
+}
+
+// HandleVerify handles POST /api/v1/rng.Verify
+// @Summary Verify a roll
+// @Description Check one of the user's rolls against its revealed server seed
+// @Tags rng
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[VerifyRollRequest] true "JSON-RPC request with VerifyRollRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[fairness.Verification] "Verification result"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid params or roll not found"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/rng.Verify [post]
+func (h *FairnessHandler) HandleVerify(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params VerifyRollRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	verification, err := h.randomnessService.Verify(r.Context(), userID, fairness.RollID(params.RollID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Roll verification failed",
+			zap.String("userId", userID),
+			zap.String("rollId", params.RollID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Roll verification failed")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, verification)
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Commitment handles seed commitment retrieval (autorouter compatible)
+func (h *FairnessHandler) Commitment(w http.ResponseWriter, r *http.Request) {
+	h.HandleCommitment(w, r)
+}
+
+// History handles roll history retrieval (autorouter compatible)
+func (h *FairnessHandler) History(w http.ResponseWriter, r *http.Request) {
+	h.HandleHistory(w, r)
+}
+
+// Verify handles roll verification (autorouter compatible)
+func (h *FairnessHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	h.HandleVerify(w, r)
+}
@@ -0,0 +1,243 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/friend"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// FriendService interface for friendships between trainers
+type FriendService interface {
+	Request(ctx context.Context, userID trainer.UserID, nickname string) (friend.RequestStatus, error)
+	Accept(ctx context.Context, userID trainer.UserID, nickname string) error
+	Remove(ctx context.Context, userID trainer.UserID, nickname string) error
+	List(ctx context.Context, userID trainer.UserID) (*friend.Friends, error)
+}
+
+// FriendHandler handles friend HTTP requests with JSON-RPC 2.0 format
+type FriendHandler struct {
+	logger        *logger.Logger
+	friendService FriendService
+}
+
+// NewFriendHandler creates a new friend handler
+func NewFriendHandler(logger *logger.Logger, friendService FriendService) *FriendHandler {
+	return &FriendHandler{
+		logger:        logger.WithComponent("friend-handler"),
+		friendService: friendService,
+	}
+}
+
+// Request parameter structures
+type FriendRequest struct {
+	Nickname string `json:"nickname" validate:"required"`
+}
+
+// Response structures for Swagger documentation
+type FriendRequestResponse struct {
+	Status friend.RequestStatus `json:"status"`
+}
+
+type FriendRemoveResponse struct {
+	Removed bool `json:"removed"`
+}
+
+type FriendListResponse struct {
+	*friend.Friends
+}
+
+// HandleRequest handles POST /api/v1/friend.Request
+// @Summary Send a friend request
+// @Description Ask the trainer with a nickname to be friends; they are notified with friend.request. If they already asked, both become friends right away and the status is accepted. Error data carries the domain code and reason, e.g. FRIEND_LIMIT.
+// @Tags friend
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[FriendRequest] true "JSON-RPC request with FriendRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[FriendRequestResponse] "Request status"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Trainer not found (-32004), already friends or requested, or too many friends or requests (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/friend.Request [post]
+func (h *FriendHandler) HandleRequest(w http.ResponseWriter, r *http.Request) {
+	userID, req, params, ok := h.parseFriendRequest(r)
+	if !ok {
+		return
+	}
+
+	status, err := h.friendService.Request(r.Context(), trainer.UserID(userID), params.Nickname)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to send friend request",
+			zap.String("userId", userID),
+			zap.String("nickname", params.Nickname),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to send friend request")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, FriendRequestResponse{Status: status})
+}
+
+// HandleAccept handles POST /api/v1/friend.Accept
+// @Summary Accept a friend request
+// @Description Accept the friend request of the trainer with a nickname; they are notified with friend.accepted
+// @Tags friend
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[FriendRequest] true "JSON-RPC request with FriendRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[FriendRequestResponse] "Friend request accepted"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Trainer or request not found (-32004), already friends or too many friends (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/friend.Accept [post]
+func (h *FriendHandler) HandleAccept(w http.ResponseWriter, r *http.Request) {
+	userID, req, params, ok := h.parseFriendRequest(r)
+	if !ok {
+		return
+	}
+
+	if err := h.friendService.Accept(r.Context(), trainer.UserID(userID), params.Nickname); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to accept friend request",
+			zap.String("userId", userID),
+			zap.String("nickname", params.Nickname),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to accept friend request")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, FriendRequestResponse{Status: friend.RequestAccepted})
+}
+
+// HandleRemove handles POST /api/v1/friend.Remove
+// @Summary Remove a friend
+// @Description End the friendship with the trainer with a nickname, or withdraw or decline a pending request between you
+// @Tags friend
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[FriendRequest] true "JSON-RPC request with FriendRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[FriendRemoveResponse] "Friend removed"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Trainer, friendship or request not found (-32004)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/friend.Remove [post]
+func (h *FriendHandler) HandleRemove(w http.ResponseWriter, r *http.Request) {
+	userID, req, params, ok := h.parseFriendRequest(r)
+	if !ok {
+		return
+	}
+
+	if err := h.friendService.Remove(r.Context(), trainer.UserID(userID), params.Nickname); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to remove friend",
+			zap.String("userId", userID),
+			zap.String("nickname", params.Nickname),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to remove friend")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, FriendRemoveResponse{Removed: true})
+}
+
+// HandleList handles POST /api/v1/friend.List
+// @Summary List friends
+// @Description Get the player's friends and pending requests, each with whether the trainer is online. Presence changes of friends arrive as friend.online and friend.offline notifications.
+// @Tags friend
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[FriendListResponse] "Friends and pending requests"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/friend.List [post]
+func (h *FriendHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	friends, err := h.friendService.List(r.Context(), trainer.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list friends",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to list friends")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, FriendListResponse{Friends: friends})
+}
+
+// parseFriendRequest reads the caller and the nickname of the other trainer, answering
+// invalid requests itself
+func (h *FriendHandler) parseFriendRequest(r *http.Request) (string, *jsonrpcx.Request, FriendRequest, bool) {
+	var params FriendRequest
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return "", nil, params, false
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return "", nil, params, false
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return "", nil, params, false
+	}
+
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return "", nil, params, false
+	}
+
+	return userID, req, params, true
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Request handles sending a friend request (autorouter compatible)
+func (h *FriendHandler) Request(w http.ResponseWriter, r *http.Request) {
+	h.HandleRequest(w, r)
+}
+
+// Accept handles accepting a friend request (autorouter compatible)
+func (h *FriendHandler) Accept(w http.ResponseWriter, r *http.Request) {
+	h.HandleAccept(w, r)
+}
+
+// Remove handles removing a friend (autorouter compatible)
+func (h *FriendHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	h.HandleRemove(w, r)
+}
+
+// List handles friend listing (autorouter compatible)
+func (h *FriendHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.HandleList(w, r)
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// IdleService interface for telling the server a player is still there
+type IdleService interface {
+	Active(ctx context.Context, userID string)
+}
+
+// IdleHandler handles AFK-related HTTP requests with JSON-RPC 2.0 format
+type IdleHandler struct {
+	logger      *logger.Logger
+	idleService IdleService
+}
+
+// NewIdleHandler creates a new idle handler
+func NewIdleHandler(logger *logger.Logger, idleService IdleService) *IdleHandler {
+	return &IdleHandler{
+		logger:      logger.WithComponent("idle-handler"),
+		idleService: idleService,
+	}
+}
+
+// Response structures for Swagger documentation
+type AckResponse struct {
+	Acknowledged bool `json:"acknowledged"`
+}
+
+// HandleAck handles POST /api/v1/idle.Ack
+// @Summary Acknowledge notifications
+// @Description Tell the server the player is watching the notifications they receive, e.g. on each SSE heartbeat while the game has focus. Players who neither send inputs nor acknowledge for a while go AFK: they are left out of encounters and receive position broadcasts on the reduced rate. Going AFK and coming back is pushed as trainer.afk.
+// @Tags idle
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[AckResponse] "Acknowledged"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/idle.Ack [post]
+func (h *IdleHandler) HandleAck(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	h.idleService.Active(r.Context(), userID)
+	jsonrpcx.Success(w, req.ID, AckResponse{Acknowledged: true})
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Ack handles acknowledging notifications (autorouter compatible)
+func (h *IdleHandler) Ack(w http.ResponseWriter, r *http.Request) {
+	h.HandleAck(w, r)
+}
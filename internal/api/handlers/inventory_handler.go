@@ -0,0 +1,368 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// InventoryService interface for inventory queries and bulk actions
+type InventoryService interface {
+	Query(ctx context.Context, userID trainer.UserID, source string, query trainer.InventoryQuery) (*trainer.InventoryPage, error)
+	Sell(ctx context.Context, userID trainer.UserID, itemIDs []trainer.ItemID, filter trainer.InventoryQuery) (*trainer.BulkResult, error)
+	MoveToVault(ctx context.Context, userID trainer.UserID, itemIDs []trainer.ItemID) (*trainer.BulkResult, error)
+	Drop(ctx context.Context, userID trainer.UserID, drops []trainer.ItemDrop) (*trainer.BulkResult, error)
+	Give(ctx context.Context, userID, recipientID trainer.UserID, itemIDs []trainer.ItemID) (*trainer.BulkResult, error)
+}
+
+// InventoryHandler handles inventory-related HTTP requests with JSON-RPC 2.0 format
+type InventoryHandler struct {
+	logger           *logger.Logger
+	inventoryService InventoryService
+}
+
+// NewInventoryHandler creates a new inventory handler
+func NewInventoryHandler(logger *logger.Logger, inventoryService InventoryService) *InventoryHandler {
+	return &InventoryHandler{
+		logger:           logger.WithComponent("inventory-handler"),
+		inventoryService: inventoryService,
+	}
+}
+
+// Request parameter structures
+type InventoryQueryRequest struct {
+	Source string `json:"source,omitempty"` // "inventory" (default) or "vault"
+	trainer.InventoryQuery
+}
+
+type InventorySellRequest struct {
+	ItemIDs []string `json:"item_ids,omitempty" validate:"max=100"` // Up to 100 stacks per request
+	trainer.InventoryQuery
+}
+
+type InventoryMoveRequest struct {
+	ItemIDs []string `json:"item_ids" validate:"min=1,max=100"` // Up to 100 stacks per request
+}
+
+type InventoryDropRequest struct {
+	Items []trainer.ItemDrop `json:"items" validate:"min=1,max=100"` // Up to 100 stacks; quantity 0 drops the whole stack
+}
+
+type InventoryGiveRequest struct {
+	RecipientID string   `json:"recipient_id" validate:"required"`
+	ItemIDs     []string `json:"item_ids" validate:"min=1,max=100"` // Up to 100 stacks per request
+}
+
+// Response structures for Swagger documentation
+type ItemDefinitionsResponse struct {
+	Items []trainer.ItemDefinition `json:"items"`
+}
+
+// HandleDefinitions handles POST /api/v1/inventory.Definitions
+// @Summary List item definitions
+// @Description Get rarity, description, icon, content reference and binding rules for every item type
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[ItemDefinitionsResponse] "Item definitions"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid request"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/inventory.Definitions [post]
+func (h *InventoryHandler) HandleDefinitions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, ItemDefinitionsResponse{Items: trainer.ItemDefinitions()})
+}
+
+// HandleQuery handles POST /api/v1/inventory.Query
+// @Summary Query inventory items
+// @Description Filter, sort and paginate item stacks in the carried inventory or the vault
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[InventoryQueryRequest] true "JSON-RPC request with InventoryQueryRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[trainer.InventoryPage] "Page of items"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid query"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/inventory.Query [post]
+func (h *InventoryHandler) HandleQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params InventoryQueryRequest
+	if len(req.Params) > 0 {
+		if !jsonrpcx.BindParams(r, req, &params) {
+			return
+		}
+	}
+
+	page, err := h.inventoryService.Query(r.Context(), trainer.UserID(userID), params.Source, params.InventoryQuery)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Inventory query failed", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Inventory query failed")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, page)
+}
+
+// HandleSell handles POST /api/v1/inventory.Sell
+// @Summary Sell items in bulk
+// @Description Sell the selected stacks, or every stack matching a type/category filter (e.g. all materials), in one atomic update
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[InventorySellRequest] true "JSON-RPC request with InventorySellRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[trainer.BulkResult] "Summary of sold items"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid items or filter"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/inventory.Sell [post]
+func (h *InventoryHandler) HandleSell(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params InventorySellRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	result, err := h.inventoryService.Sell(r.Context(), trainer.UserID(userID), toItemIDs(params.ItemIDs), params.InventoryQuery)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Bulk sell failed", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Bulk sell failed")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// HandleMoveToVault handles POST /api/v1/inventory.MoveToVault
+// @Summary Move items to the vault in bulk
+// @Description Deposit the selected stacks into the vault in one atomic transfer; the trainer must stand at a vault location
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[InventoryMoveRequest] true "JSON-RPC request with InventoryMoveRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[trainer.BulkResult] "Summary of moved items"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid items, vault full or not at a vault location"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/inventory.MoveToVault [post]
+func (h *InventoryHandler) HandleMoveToVault(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params InventoryMoveRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	result, err := h.inventoryService.MoveToVault(r.Context(), trainer.UserID(userID), toItemIDs(params.ItemIDs))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Bulk move to vault failed", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Bulk move to vault failed")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// HandleDrop handles POST /api/v1/inventory.Drop
+// @Summary Drop items
+// @Description Discard the selected stacks, or part of them, in one atomic update. Dropped items are destroyed.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[InventoryDropRequest] true "JSON-RPC request with InventoryDropRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[trainer.BulkResult] "Summary of dropped items"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid items or quantities"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/inventory.Drop [post]
+func (h *InventoryHandler) HandleDrop(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params InventoryDropRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	result, err := h.inventoryService.Drop(r.Context(), trainer.UserID(userID), params.Items)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Drop failed", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Drop failed")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// HandleGive handles POST /api/v1/inventory.Give
+// @Summary Give items to another trainer
+// @Description Hand the selected stacks to another trainer in one transfer. The giver needs a verified email and bound items can't be given.
+// @Tags inventory
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[InventoryGiveRequest] true "JSON-RPC request with InventoryGiveRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[trainer.BulkResult] "Summary of given items"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid items, bound items or recipient inventory full"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 403 {object} jsonrpcx.ErrorResponse "Email not verified"
+// @Failure 404 {object} jsonrpcx.ErrorResponse "Recipient not found"
+// @Security BearerAuth
+// @Router /api/v1/inventory.Give [post]
+func (h *InventoryHandler) HandleGive(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params InventoryGiveRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	result, err := h.inventoryService.Give(r.Context(), trainer.UserID(userID), trainer.UserID(params.RecipientID), toItemIDs(params.ItemIDs))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Give failed", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Give failed")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// toItemIDs converts raw item ID strings
+func toItemIDs(ids []string) []trainer.ItemID {
+	itemIDs := make([]trainer.ItemID, len(ids))
+	for i, id := range ids {
+		itemIDs[i] = trainer.ItemID(id)
+	}
+	return itemIDs
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Definitions handles item definition listing (autorouter compatible)
+func (h *InventoryHandler) Definitions(w http.ResponseWriter, r *http.Request) {
+	h.HandleDefinitions(w, r)
+}
+
+// Query handles inventory queries (autorouter compatible)
+func (h *InventoryHandler) Query(w http.ResponseWriter, r *http.Request) {
+	h.HandleQuery(w, r)
+}
+
+// Sell handles bulk selling (autorouter compatible)
+func (h *InventoryHandler) Sell(w http.ResponseWriter, r *http.Request) {
+	h.HandleSell(w, r)
+}
+
+// MoveToVault handles bulk vault deposits (autorouter compatible)
+func (h *InventoryHandler) MoveToVault(w http.ResponseWriter, r *http.Request) {
+	h.HandleMoveToVault(w, r)
+}
+
+// Drop handles discarding items (autorouter compatible)
+func (h *InventoryHandler) Drop(w http.ResponseWriter, r *http.Request) {
+	h.HandleDrop(w, r)
+}
+
+// Give handles giving items to another trainer (autorouter compatible)
+func (h *InventoryHandler) Give(w http.ResponseWriter, r *http.Request) {
+	h.HandleGive(w, r)
+}
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/loot"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// LootService interface for collecting world pickups
+type LootService interface {
+	ClaimPickup(ctx context.Context, trainerID trainer.UserID, pickupID loot.PickupID) (*loot.Pickup, error)
+	GetNearbyPickups(ctx context.Context, center shared.Position, radius float64) ([]*loot.Pickup, error)
+}
+
+const (
+	defaultPickupRadius = 5.0
+	maxPickupRadius     = 20.0
+	// Trainers must stand this close to a pickup to collect it
+	pickupClaimRange = 2.0
+)
+
+// LootHandler handles loot-related HTTP requests with JSON-RPC 2.0 format
+type LootHandler struct {
+	logger      *logger.Logger
+	repository  trainer.Repository
+	lootService LootService
+}
+
+// NewLootHandler creates a new loot handler
+func NewLootHandler(logger *logger.Logger, repository trainer.Repository, lootService LootService) *LootHandler {
+	return &LootHandler{
+		logger:      logger.WithComponent("loot-handler"),
+		repository:  repository,
+		lootService: lootService,
+	}
+}
+
+// Request parameter structures
+type NearbyPickupsRequest struct {
+	Radius float64 `json:"radius,omitempty"` // Defaults to 5, capped at 20
+}
+
+type ClaimPickupRequest struct {
+	PickupID string `json:"pickup_id" validate:"required"`
+}
+
+// Response structures for Swagger documentation
+type NearbyPickupsResponse struct {
+	Pickups []*loot.Pickup `json:"pickups"`
+	Total   int            `json:"total"`
+}
+
+type ClaimPickupResponse struct {
+	Pickup *loot.Pickup `json:"pickup"`
+}
+
+// HandleNearby handles POST /api/v1/loot.Nearby
+// @Summary List nearby loot pickups
+// @Description Get loot pickups lying in the world around the authenticated trainer
+// @Tags loot
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[NearbyPickupsRequest] true "JSON-RPC request with NearbyPickupsRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[NearbyPickupsResponse] "Nearby pickups"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid request parameters"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/loot.Nearby [post]
+func (h *LootHandler) HandleNearby(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params NearbyPickupsRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	radius := params.Radius
+	if radius <= 0 {
+		radius = defaultPickupRadius
+	}
+	if radius > maxPickupRadius {
+		radius = maxPickupRadius
+	}
+
+	trainerEntity, err := h.repository.GetByID(r.Context(), trainer.UserID(userID))
+	if err != nil || trainerEntity == nil {
+		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to retrieve trainer")
+		return
+	}
+	trainerEntity.UpdatePositionFromMovement()
+
+	pickups, err := h.lootService.GetNearbyPickups(r.Context(), trainerEntity.Position, radius)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list nearby pickups", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve pickups")
+		return
+	}
+
+	if pickups == nil {
+		pickups = []*loot.Pickup{}
+	}
+
+	result := NearbyPickupsResponse{
+		Pickups: pickups,
+		Total:   len(pickups),
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// HandleClaim handles POST /api/v1/loot.Claim
+// @Summary Collect a loot pickup
+// @Description Collect a nearby loot pickup into the authenticated trainer's inventory
+// @Tags loot
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[ClaimPickupRequest] true "JSON-RPC request with ClaimPickupRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[ClaimPickupResponse] "Collected pickup"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid request parameters, pickup out of range or reserved"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/loot.Claim [post]
+func (h *LootHandler) HandleClaim(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params ClaimPickupRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	trainerEntity, err := h.repository.GetByID(r.Context(), trainer.UserID(userID))
+	if err != nil || trainerEntity == nil {
+		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to retrieve trainer")
+		return
+	}
+	trainerEntity.UpdatePositionFromMovement()
+
+	// Only pickups within claim range of the trainer can be collected
+	nearby, err := h.lootService.GetNearbyPickups(r.Context(), trainerEntity.Position, pickupClaimRange)
+	if err != nil {
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve pickups")
+		return
+	}
+
+	inRange := false
+	for _, p := range nearby {
+		if p.ID.String() == params.PickupID {
+			inRange = true
+			break
+		}
+	}
+	if !inRange {
+		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Pickup not found or out of range")
+		return
+	}
+
+	pickup, err := h.lootService.ClaimPickup(r.Context(), trainer.UserID(userID), loot.PickupID(params.PickupID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to claim pickup",
+			zap.String("userId", userID),
+			zap.String("pickupId", params.PickupID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to claim pickup")
+		return
+	}
+
+	h.logger.WithContext(r.Context()).Info("Pickup claimed",
+		zap.String("userId", userID),
+		zap.String("pickupId", params.PickupID),
+		zap.String("itemType", pickup.Drop.ItemType.String()),
+		zap.Int("quantity", pickup.Drop.Quantity))
+
+	jsonrpcx.Success(w, req.ID, ClaimPickupResponse{Pickup: pickup})
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Nearby handles nearby pickup listing (autorouter compatible)
+func (h *LootHandler) Nearby(w http.ResponseWriter, r *http.Request) {
+	h.HandleNearby(w, r)
+}
+
+// Claim handles pickup collection (autorouter compatible)
+func (h *LootHandler) Claim(w http.ResponseWriter, r *http.Request) {
+	h.HandleClaim(w, r)
+}
@@ -0,0 +1,211 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/match"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// MatchService interface for FPS matches played in rooms
+type MatchService interface {
+	Start(ctx context.Context, userID string, settings match.Settings) (*match.Match, error)
+	Scoreboard(ctx context.Context, userID string) (*match.Match, error)
+	History(ctx context.Context, userID string, limit int) ([]match.HistoryEntry, error)
+}
+
+// MatchHandler handles match HTTP requests with JSON-RPC 2.0 format
+type MatchHandler struct {
+	logger       *logger.Logger
+	matchService MatchService
+}
+
+// NewMatchHandler creates a new match handler
+func NewMatchHandler(logger *logger.Logger, matchService MatchService) *MatchHandler {
+	return &MatchHandler{
+		logger:       logger.WithComponent("match-handler"),
+		matchService: matchService,
+	}
+}
+
+// Request parameter structures
+type MatchStartRequest struct {
+	Mode            string `json:"mode" validate:"required,oneof=deathmatch team"`
+	DurationSeconds int    `json:"duration_seconds,omitempty" validate:"omitempty,min=60,max=1800"` // The mode's default when omitted
+	ScoreLimit      *int   `json:"score_limit,omitempty" validate:"min=0,max=200"`                  // 0 plays the full duration, the mode's default when omitted
+}
+
+type MatchHistoryRequest struct {
+	Limit int `json:"limit,omitempty" validate:"min=0,max=50"` // All kept matches when omitted
+}
+
+// Response structures for Swagger documentation
+type MatchResponse struct {
+	Match *match.Match `json:"match"`
+}
+
+type MatchHistoryResponse struct {
+	Matches []match.HistoryEntry `json:"matches"`
+}
+
+// HandleStart handles POST /api/v1/match.Start
+// @Summary Start a match
+// @Description Start a match between the members of your room, which only its owner can do. Deathmatch lasts 5 minutes to 20 kills by default and team mode 10 minutes to 50 team kills; players are notified with match.started, match.score after every kill and match.ended with the results. Error data carries the domain code and reason, e.g. MATCH_IN_PROGRESS.
+// @Tags match
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[MatchStartRequest] true "JSON-RPC request with MatchStartRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[MatchResponse] "Match started"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid mode, duration or score limit, or not the room owner (-32602), not in a room, a match already running or too few players (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/match.Start [post]
+func (h *MatchHandler) HandleStart(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseMatchRequest(r)
+	if !ok {
+		return
+	}
+
+	var params MatchStartRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	mode := match.Mode(params.Mode)
+	settings := match.DefaultSettings(mode)
+	settings.Mode = mode
+	if params.DurationSeconds != 0 {
+		settings.Duration = time.Duration(params.DurationSeconds) * time.Second
+	}
+	if params.ScoreLimit != nil {
+		settings.ScoreLimit = *params.ScoreLimit
+	}
+
+	started, err := h.matchService.Start(r.Context(), userID, settings)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to start match",
+			zap.String("userId", userID),
+			zap.String("mode", params.Mode),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to start match")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, MatchResponse{Match: started})
+}
+
+// HandleScoreboard handles POST /api/v1/match.Scoreboard
+// @Summary Get the match scoreboard
+// @Description Get the match running in your room with every player's kills and deaths and, in team mode, their team
+// @Tags match
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[MatchResponse] "Running match"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Not in a room or no match running (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/match.Scoreboard [post]
+func (h *MatchHandler) HandleScoreboard(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseMatchRequest(r)
+	if !ok {
+		return
+	}
+
+	running, err := h.matchService.Scoreboard(r.Context(), userID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to get match scoreboard",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to get scoreboard")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, MatchResponse{Match: running})
+}
+
+// HandleHistory handles POST /api/v1/match.History
+// @Summary Get your match history
+// @Description Get your results in your last 50 matches, newest first
+// @Tags match
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[MatchHistoryRequest] true "JSON-RPC request with MatchHistoryRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[MatchHistoryResponse] "Past matches"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/match.History [post]
+func (h *MatchHandler) HandleHistory(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseMatchRequest(r)
+	if !ok {
+		return
+	}
+
+	var params MatchHistoryRequest
+	if len(req.Params) > 0 {
+		if !jsonrpcx.BindParams(r, req, &params) {
+			return
+		}
+	}
+
+	matches, err := h.matchService.History(r.Context(), userID, params.Limit)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get match history",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to get match history")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, MatchHistoryResponse{Matches: matches})
+}
+
+// parseMatchRequest reads the caller and the JSON-RPC request, answering invalid requests itself
+func (h *MatchHandler) parseMatchRequest(r *http.Request) (string, *jsonrpcx.Request, bool) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return "", nil, false
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return "", nil, false
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return "", nil, false
+	}
+
+	return userID, req, true
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Start handles starting a match (autorouter compatible)
+func (h *MatchHandler) Start(w http.ResponseWriter, r *http.Request) {
+	h.HandleStart(w, r)
+}
+
+// Scoreboard handles the match scoreboard (autorouter compatible)
+func (h *MatchHandler) Scoreboard(w http.ResponseWriter, r *http.Request) {
+	h.HandleScoreboard(w, r)
+}
+
+// History handles the match history (autorouter compatible)
+func (h *MatchHandler) History(w http.ResponseWriter, r *http.Request) {
+	h.HandleHistory(w, r)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// MFAService interface for setting up a user's second factor
+type MFAService interface {
+	Enroll(ctx context.Context, userID string) (*account.MFAEnrollment, error)
+	Verify(ctx context.Context, userID, code string) error
+}
+
+// MFAHandler handles two-factor authentication HTTP requests with JSON-RPC 2.0 format. Its
+// methods are served under the auth.MFA. prefix.
+type MFAHandler struct {
+	logger     *logger.Logger
+	mfaService MFAService
+}
+
+// NewMFAHandler creates a new MFA handler
+func NewMFAHandler(logger *logger.Logger, mfaService MFAService) *MFAHandler {
+	return &MFAHandler{
+		logger:     logger.WithComponent("mfa-handler"),
+		mfaService: mfaService,
+	}
+}
+
+// Request/Response structures for Swagger documentation
+type MFAVerifyRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+type MFAVerifyResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
+// HandleEnroll handles POST /api/v1/auth.MFA.Enroll
+// @Summary Start two-factor authentication setup
+// @Description Create an authenticator secret and single-use backup codes. Show provisioning_uri as a QR code and the backup codes to the player; neither is returned again. Sign-ins aren't affected until auth.MFA.Verify confirms the setup, and enrolling again before that replaces the secret.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[account.MFAEnrollment] "Secret, provisioning URI and backup codes"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Two-factor authentication already enabled"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/auth.MFA.Enroll [post]
+func (h *MFAHandler) HandleEnroll(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseMFARequest(r)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.mfaService.Enroll(r.Context(), userID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to enroll in two-factor authentication",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to enroll in two-factor authentication")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, enrollment)
+}
+
+// HandleVerify handles POST /api/v1/auth.MFA.Verify
+// @Summary Enable two-factor authentication
+// @Description Confirm the setup started by auth.MFA.Enroll with a code from the authenticator app. From then on every sign-in returns step_up with method totp, completed through auth.VerifyLogin with an authenticator or backup code.
+// @Tags authentication
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[MFAVerifyRequest] true "JSON-RPC request with MFAVerifyRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[MFAVerifyResponse] "Two-factor authentication enabled"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Incorrect code or no pending setup"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/auth.MFA.Verify [post]
+func (h *MFAHandler) HandleVerify(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseMFARequest(r)
+	if !ok {
+		return
+	}
+
+	var params MFAVerifyRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	if err := h.mfaService.Verify(r.Context(), userID, params.Code); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to enable two-factor authentication",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to enable two-factor authentication")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, MFAVerifyResponse{Enabled: true})
+}
+
+// parseMFARequest reads the user and the JSON-RPC request, answering invalid requests itself
+func (h *MFAHandler) parseMFARequest(r *http.Request) (string, *jsonrpcx.Request, bool) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return "", nil, false
+	}
+
+	// The second factor protects the user, whichever character the token plays
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return "", nil, false
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return "", nil, false
+	}
+
+	return userID, req, true
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Enroll handles starting two-factor authentication setup (autorouter compatible)
+func (h *MFAHandler) Enroll(w http.ResponseWriter, r *http.Request) {
+	h.HandleEnroll(w, r)
+}
+
+// Verify handles enabling two-factor authentication (autorouter compatible)
+func (h *MFAHandler) Verify(w http.ResponseWriter, r *http.Request) {
+	h.HandleVerify(w, r)
+}
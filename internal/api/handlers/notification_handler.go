@@ -0,0 +1,146 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/notification"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// NotificationService interface for players' notification preferences
+type NotificationService interface {
+	Preferences(ctx context.Context, userID string) (notification.Preferences, error)
+	UpdatePreferences(ctx context.Context, userID string, changes notification.Preferences) (notification.Preferences, error)
+}
+
+// NotificationHandler handles notification preference HTTP requests with JSON-RPC 2.0 format
+type NotificationHandler struct {
+	logger              *logger.Logger
+	notificationService NotificationService
+}
+
+// NewNotificationHandler creates a new notification handler
+func NewNotificationHandler(logger *logger.Logger, notificationService NotificationService) *NotificationHandler {
+	return &NotificationHandler{
+		logger:              logger.WithComponent("notification-handler"),
+		notificationService: notificationService,
+	}
+}
+
+// Request parameter structures
+type NotificationUpdatePreferencesRequest struct {
+	// Channels to turn on or off by category, e.g. {"chat": {"sse": false}}
+	Preferences notification.Preferences `json:"preferences" validate:"min=1"`
+}
+
+// Response structures for Swagger documentation
+type NotificationPreferencesResponse struct {
+	Preferences notification.Preferences `json:"preferences"`
+}
+
+// HandlePreferences handles POST /api/v1/notifications.Preferences
+// @Summary Get notification preferences
+// @Description Get which channels (sse, push, email) may reach you for each notification category (chat, trade, guild, combat, marketing). Everything is on by default except marketing outside the game. Notifications of other kinds, such as positions and server notices, are always sent.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[NotificationPreferencesResponse] "Preferences"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/notifications.Preferences [post]
+func (h *NotificationHandler) HandlePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseNotificationRequest(r)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.notificationService.Preferences(r.Context(), userID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get notification preferences",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to get preferences")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, NotificationPreferencesResponse{Preferences: preferences})
+}
+
+// HandleUpdatePreferences handles POST /api/v1/notifications.UpdatePreferences
+// @Summary Change notification preferences
+// @Description Turn channels on or off for notification categories. Only the listed channels change; the response holds all preferences. Live (sse) preferences apply to SSE and WebSocket notifications right away.
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[NotificationUpdatePreferencesRequest] true "JSON-RPC request with NotificationUpdatePreferencesRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[NotificationPreferencesResponse] "Preferences updated"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Unknown category or channel (-32602)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/notifications.UpdatePreferences [post]
+func (h *NotificationHandler) HandleUpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseNotificationRequest(r)
+	if !ok {
+		return
+	}
+
+	var params NotificationUpdatePreferencesRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	preferences, err := h.notificationService.UpdatePreferences(r.Context(), userID, params.Preferences)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to update notification preferences",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to update preferences")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, NotificationPreferencesResponse{Preferences: preferences})
+}
+
+// parseNotificationRequest reads the caller and the JSON-RPC request, answering invalid requests itself
+func (h *NotificationHandler) parseNotificationRequest(r *http.Request) (string, *jsonrpcx.Request, bool) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return "", nil, false
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return "", nil, false
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return "", nil, false
+	}
+
+	return userID, req, true
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Preferences handles getting notification preferences (autorouter compatible)
+func (h *NotificationHandler) Preferences(w http.ResponseWriter, r *http.Request) {
+	h.HandlePreferences(w, r)
+}
+
+// UpdatePreferences handles changing notification preferences (autorouter compatible)
+func (h *NotificationHandler) UpdatePreferences(w http.ResponseWriter, r *http.Request) {
+	h.HandleUpdatePreferences(w, r)
+}
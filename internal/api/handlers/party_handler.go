@@ -0,0 +1,219 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// PartyService interface for moving animals between the party and storage
+type PartyService interface {
+	Add(ctx context.Context, trainerID trainer.UserID, animalID animal.AnimalID) (trainer.AnimalParty, error)
+	Remove(ctx context.Context, trainerID trainer.UserID, animalID animal.AnimalID) (trainer.AnimalParty, error)
+	Swap(ctx context.Context, trainerID trainer.UserID, outID, inID animal.AnimalID) (trainer.AnimalParty, error)
+}
+
+// PartyHandler handles trainer party HTTP requests with JSON-RPC 2.0 format
+type PartyHandler struct {
+	logger       *logger.Logger
+	partyService PartyService
+}
+
+// NewPartyHandler creates a new party handler
+func NewPartyHandler(logger *logger.Logger, partyService PartyService) *PartyHandler {
+	return &PartyHandler{
+		logger:       logger.WithComponent("party-handler"),
+		partyService: partyService,
+	}
+}
+
+// Request parameter structures
+type PartyAnimalRequest struct {
+	AnimalID string `json:"animal_id" validate:"required"`
+}
+
+type PartySwapRequest struct {
+	OutAnimalID string `json:"out_animal_id" validate:"required"`
+	InAnimalID  string `json:"in_animal_id" validate:"required"`
+}
+
+// Response structures for Swagger documentation
+type PartyResponse struct {
+	Party trainer.AnimalParty `json:"party"`
+}
+
+// HandleAdd handles POST /api/v1/trainer.Party.Add
+// @Summary Add an animal to the party
+// @Description Move one of the authenticated trainer's stored animals into their party. Error data carries the domain code and reason, e.g. PARTY_FULL.
+// @Tags trainer
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[PartyAnimalRequest] true "JSON-RPC request with PartyAnimalRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[PartyResponse] "Updated party"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Animal not found (-32004), party full or animal not in storage (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/trainer.Party.Add [post]
+func (h *PartyHandler) HandleAdd(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params PartyAnimalRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	party, err := h.partyService.Add(r.Context(), trainer.UserID(userID), animal.AnimalID(params.AnimalID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to add animal to party",
+			zap.String("userId", userID),
+			zap.String("animalId", params.AnimalID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to add animal to party")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, PartyResponse{Party: party})
+}
+
+// HandleRemove handles POST /api/v1/trainer.Party.Remove
+// @Summary Remove an animal from the party
+// @Description Move one of the authenticated trainer's party members into storage. Error data carries the domain code and reason, e.g. ANIMAL_NOT_IN_PARTY.
+// @Tags trainer
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[PartyAnimalRequest] true "JSON-RPC request with PartyAnimalRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[PartyResponse] "Updated party"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Animal not found (-32004) or not in the party (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/trainer.Party.Remove [post]
+func (h *PartyHandler) HandleRemove(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params PartyAnimalRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	party, err := h.partyService.Remove(r.Context(), trainer.UserID(userID), animal.AnimalID(params.AnimalID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to remove animal from party",
+			zap.String("userId", userID),
+			zap.String("animalId", params.AnimalID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to remove animal from party")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, PartyResponse{Party: party})
+}
+
+// HandleSwap handles POST /api/v1/trainer.Party.Swap
+// @Summary Swap a party member with a stored animal
+// @Description Move a party member into storage and a stored animal into its slot, also when the party is full. Error data carries the domain code and reason.
+// @Tags trainer
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[PartySwapRequest] true "JSON-RPC request with PartySwapRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[PartyResponse] "Updated party"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Animal not found (-32004), or animals not in the party and storage respectively (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/trainer.Party.Swap [post]
+func (h *PartyHandler) HandleSwap(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params PartySwapRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	party, err := h.partyService.Swap(r.Context(), trainer.UserID(userID), animal.AnimalID(params.OutAnimalID), animal.AnimalID(params.InAnimalID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to swap party animals",
+			zap.String("userId", userID),
+			zap.String("outAnimalId", params.OutAnimalID),
+			zap.String("inAnimalId", params.InAnimalID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to swap party animals")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, PartyResponse{Party: party})
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Add handles adding an animal to the party (autorouter compatible)
+func (h *PartyHandler) Add(w http.ResponseWriter, r *http.Request) {
+	h.HandleAdd(w, r)
+}
+
+// Remove handles removing an animal from the party (autorouter compatible)
+func (h *PartyHandler) Remove(w http.ResponseWriter, r *http.Request) {
+	h.HandleRemove(w, r)
+}
+
+// Swap handles swapping party animals (autorouter compatible)
+func (h *PartyHandler) Swap(w http.ResponseWriter, r *http.Request) {
+	h.HandleSwap(w, r)
+}
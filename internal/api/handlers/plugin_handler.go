@@ -0,0 +1,68 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/plugin"
+)
+
+// PluginHandler serves the JSON-RPC methods that gameplay plugins add
+type PluginHandler struct {
+	logger *logger.Logger
+}
+
+// NewPluginHandler creates a new plugin handler
+func NewPluginHandler(logger *logger.Logger) *PluginHandler {
+	return &PluginHandler{
+		logger: logger.WithComponent("plugin-handler"),
+	}
+}
+
+// Serve returns the endpoint of a plugin method, which parses the JSON-RPC request for
+// the method and answers with its result
+func (h *PluginHandler) Serve(method plugin.Method) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+			return
+		}
+
+		// Get user info from context
+		userID, ok := middleware.GetUserID(r.Context())
+		if !ok {
+			jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+			return
+		}
+
+		req, err := jsonrpcx.ParseRequest(r)
+		if err != nil {
+			jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+			return
+		}
+
+		result, err := method.Func(r.Context(), userID, req.Params)
+		if err != nil {
+			var paramsErr *plugin.ParamsError
+			if errors.As(err, &paramsErr) {
+				jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, paramsErr.Message)
+				return
+			}
+
+			h.logger.Error("Plugin method failed",
+				zap.String("module", method.Module),
+				zap.String("method", method.Name),
+				zap.String("userId", userID),
+				zap.Error(err))
+			jsonrpcx.WithDomainError(r, req.ID, err, "Internal error")
+			return
+		}
+
+		jsonrpcx.Success(w, req.ID, result)
+	}
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/referral"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// ReferralService interface for invitations and referral rewards
+type ReferralService interface {
+	Invite(ctx context.Context, userID string) (*referral.Invite, error)
+	Summary(ctx context.Context, userID string) (*referral.Summary, error)
+	Claim(ctx context.Context, userID string) ([]referral.Reward, error)
+}
+
+// ReferralHandler handles referral HTTP requests with JSON-RPC 2.0 format
+type ReferralHandler struct {
+	logger          *logger.Logger
+	referralService ReferralService
+}
+
+// NewReferralHandler creates a new referral handler
+func NewReferralHandler(logger *logger.Logger, referralService ReferralService) *ReferralHandler {
+	return &ReferralHandler{
+		logger:          logger.WithComponent("referral-handler"),
+		referralService: referralService,
+	}
+}
+
+// ClaimReferralResponse lists the rewards paid to the player
+type ClaimReferralResponse struct {
+	Rewards []referral.Reward `json:"rewards"`
+}
+
+// HandleInvite handles POST /api/v1/referral.Invite
+// @Summary Get an invitation link
+// @Description Get the player's referral code and invitation link, creating the code on first use
+// @Tags referral
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[referral.Invite] "Referral code and link"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/referral.Invite [post]
+func (h *ReferralHandler) HandleInvite(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, "invite", func(ctx context.Context, userID string) (any, error) {
+		return h.referralService.Invite(ctx, userID)
+	})
+}
+
+// HandleSummary handles POST /api/v1/referral.Summary
+// @Summary Get referral progress
+// @Description Get the player's invite, who referred them, the players they brought in and the milestone rewards
+// @Tags referral
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[referral.Summary] "Referral summary"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/referral.Summary [post]
+func (h *ReferralHandler) HandleSummary(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, "summary", func(ctx context.Context, userID string) (any, error) {
+		return h.referralService.Summary(ctx, userID)
+	})
+}
+
+// HandleClaim handles POST /api/v1/referral.Claim
+// @Summary Claim referral rewards
+// @Description Pay out milestones reached by referred players. Both the referrer and the referred player are paid.
+// @Tags referral
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[ClaimReferralResponse] "Rewards paid to the player"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/referral.Claim [post]
+func (h *ReferralHandler) HandleClaim(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, "claim", func(ctx context.Context, userID string) (any, error) {
+		rewards, err := h.referralService.Claim(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return ClaimReferralResponse{Rewards: rewards}, nil
+	})
+}
+
+// handle runs a referral action for the authenticated player
+func (h *ReferralHandler) handle(w http.ResponseWriter, r *http.Request, action string, apply func(ctx context.Context, userID string) (any, error)) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetAccountID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	result, err := apply(r.Context(), userID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Referral action failed",
+			zap.String("userId", userID),
+			zap.String("action", action),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Referral action failed")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Invite handles invitation link retrieval (autorouter compatible)
+func (h *ReferralHandler) Invite(w http.ResponseWriter, r *http.Request) {
+	h.HandleInvite(w, r)
+}
+
+// Summary handles referral summary retrieval (autorouter compatible)
+func (h *ReferralHandler) Summary(w http.ResponseWriter, r *http.Request) {
+	h.HandleSummary(w, r)
+}
+
+// Claim handles referral reward claims (autorouter compatible)
+func (h *ReferralHandler) Claim(w http.ResponseWriter, r *http.Request) {
+	h.HandleClaim(w, r)
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/room"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// RoomService interface for the rooms players play in apart from the rest of the world
+type RoomService interface {
+	Create(ctx context.Context, userID, name string, capacity int) (*room.Room, error)
+	Join(ctx context.Context, userID string, roomID room.ID) (*room.Room, error)
+	Leave(ctx context.Context, userID string) error
+	List(ctx context.Context) ([]*room.Room, error)
+}
+
+// RoomHandler handles room HTTP requests with JSON-RPC 2.0 format
+type RoomHandler struct {
+	logger      *logger.Logger
+	roomService RoomService
+}
+
+// NewRoomHandler creates a new room handler
+func NewRoomHandler(logger *logger.Logger, roomService RoomService) *RoomHandler {
+	return &RoomHandler{
+		logger:      logger.WithComponent("room-handler"),
+		roomService: roomService,
+	}
+}
+
+// Request parameter structures
+type RoomCreateRequest struct {
+	Name     string `json:"name" validate:"required,max=32"`
+	Capacity int    `json:"capacity,omitempty" validate:"omitempty,min=2,max=16"` // 8 players when omitted
+}
+
+type RoomJoinRequest struct {
+	RoomID string `json:"room_id" validate:"required"`
+}
+
+// Response structures for Swagger documentation
+type RoomResponse struct {
+	Room *room.Room `json:"room"`
+}
+
+type RoomLeaveResponse struct {
+	Left bool `json:"left"`
+}
+
+type RoomListResponse struct {
+	Rooms []*room.Room `json:"rooms"`
+}
+
+// HandleCreate handles POST /api/v1/room.Create
+// @Summary Create a room
+// @Description Create a room for 2 to 16 players, 8 by default, and join it as its owner. Bullets fired in a room only fly, hit and show in it. Error data carries the domain code and reason, e.g. ALREADY_IN_ROOM.
+// @Tags room
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[RoomCreateRequest] true "JSON-RPC request with RoomCreateRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[RoomResponse] "Room created"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid name or capacity (-32602) or already in a room (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/room.Create [post]
+func (h *RoomHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseRoomRequest(r)
+	if !ok {
+		return
+	}
+
+	var params RoomCreateRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	created, err := h.roomService.Create(r.Context(), userID, params.Name, params.Capacity)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to create room",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to create room")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, RoomResponse{Room: created})
+}
+
+// HandleJoin handles POST /api/v1/room.Join
+// @Summary Join a room
+// @Description Join a room that has a place left; its members are notified with room.joined. Error data carries the domain code and reason, e.g. ROOM_FULL.
+// @Tags room
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[RoomJoinRequest] true "JSON-RPC request with RoomJoinRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[RoomResponse] "Room joined"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Room not found (-32004), full or already in a room (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/room.Join [post]
+func (h *RoomHandler) HandleJoin(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseRoomRequest(r)
+	if !ok {
+		return
+	}
+
+	var params RoomJoinRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	joined, err := h.roomService.Join(r.Context(), userID, room.ID(params.RoomID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to join room",
+			zap.String("userId", userID),
+			zap.String("roomId", params.RoomID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to join room")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, RoomResponse{Room: joined})
+}
+
+// HandleLeave handles POST /api/v1/room.Leave
+// @Summary Leave your room
+// @Description Leave the room you are in; the remaining members are notified with room.left. The next member owns the room when its owner leaves, and the room closes when its last member leaves.
+// @Tags room
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[RoomLeaveResponse] "Room left"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Not in a room (-32005)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/room.Leave [post]
+func (h *RoomHandler) HandleLeave(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseRoomRequest(r)
+	if !ok {
+		return
+	}
+
+	if err := h.roomService.Leave(r.Context(), userID); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to leave room",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to leave room")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, RoomLeaveResponse{Left: true})
+}
+
+// HandleList handles POST /api/v1/room.List
+// @Summary List rooms
+// @Description Get the open rooms with their members and capacity, oldest first
+// @Tags room
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[RoomListResponse] "Open rooms"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/room.List [post]
+func (h *RoomHandler) HandleList(w http.ResponseWriter, r *http.Request) {
+	userID, req, ok := h.parseRoomRequest(r)
+	if !ok {
+		return
+	}
+
+	rooms, err := h.roomService.List(r.Context())
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list rooms",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to list rooms")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, RoomListResponse{Rooms: rooms})
+}
+
+// parseRoomRequest reads the caller and the JSON-RPC request, answering invalid requests itself
+func (h *RoomHandler) parseRoomRequest(r *http.Request) (string, *jsonrpcx.Request, bool) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return "", nil, false
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return "", nil, false
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return "", nil, false
+	}
+
+	return userID, req, true
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Create handles room creation (autorouter compatible)
+func (h *RoomHandler) Create(w http.ResponseWriter, r *http.Request) {
+	h.HandleCreate(w, r)
+}
+
+// Join handles joining a room (autorouter compatible)
+func (h *RoomHandler) Join(w http.ResponseWriter, r *http.Request) {
+	h.HandleJoin(w, r)
+}
+
+// Leave handles leaving a room (autorouter compatible)
+func (h *RoomHandler) Leave(w http.ResponseWriter, r *http.Request) {
+	h.HandleLeave(w, r)
+}
+
+// List handles room listing (autorouter compatible)
+func (h *RoomHandler) List(w http.ResponseWriter, r *http.Request) {
+	h.HandleList(w, r)
+}
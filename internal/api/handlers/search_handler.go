@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/search"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// SearchService interface for searching across document types
+type SearchService interface {
+	Query(ctx context.Context, q search.Query) (*search.Result, error)
+}
+
+// SearchHandler handles search HTTP requests with JSON-RPC 2.0 format
+type SearchHandler struct {
+	logger        *logger.Logger
+	searchService SearchService
+}
+
+// NewSearchHandler creates a new search handler
+func NewSearchHandler(logger *logger.Logger, searchService SearchService) *SearchHandler {
+	return &SearchHandler{
+		logger:        logger.WithComponent("search-handler"),
+		searchService: searchService,
+	}
+}
+
+// HandleQuery handles POST /api/v1/search.Query
+// @Summary Search trainers
+// @Description Search by free text with fuzzy nickname matching. Results are grouped by type, and offset and limit apply to each type.
+// @Tags search
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[search.Query] true "JSON-RPC request with search.Query params"
+// @Success 200 {object} jsonrpcx.ResponseT[search.Result] "Hits grouped by type"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Empty text or unsupported type"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/search.Query [post]
+func (h *SearchHandler) HandleQuery(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params search.Query
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	result, err := h.searchService.Query(r.Context(), params)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Search failed",
+			zap.String("userId", userID),
+			zap.String("text", params.Text),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Search failed")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Query handles search queries (autorouter compatible)
+func (h *SearchHandler) Query(w http.ResponseWriter, r *http.Request) {
+	h.HandleQuery(w, r)
+}
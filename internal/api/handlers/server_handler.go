@@ -6,14 +6,20 @@ import (
 	"os"
 
 	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/pkg/captcha"
+	"github.com/danghamo/life/pkg/tenant"
 )
 
 // ServerHandler handles server information requests
-type ServerHandler struct{}
+type ServerHandler struct {
+	tenants *tenant.Registry
+	captcha *captcha.Widget
+}
 
-// NewServerHandler creates a new server handler
-func NewServerHandler() *ServerHandler {
-	return &ServerHandler{}
+// NewServerHandler creates a new server handler. Clients are told to render the captcha
+// widget when one is given.
+func NewServerHandler(tenants *tenant.Registry, widget *captcha.Widget) *ServerHandler {
+	return &ServerHandler{tenants: tenants, captcha: widget}
 }
 
 // ServerInfoResponse represents server information
@@ -21,6 +27,13 @@ type ServerInfoResponse struct {
 	Host string `json:"host"`
 	Port string `json:"port"`
 	URL  string `json:"url"`
+
+	// Branding of the tenant the request was made for
+	Branding *tenant.Branding `json:"branding,omitempty"`
+
+	// Captcha is the widget whose token auth.GuestLogin and auth.OAuthCallback need for new
+	// players, absent when bot protection is off
+	Captcha *captcha.Widget `json:"captcha,omitempty"`
 }
 
 // HandleServerInfo handles POST /api/v1/server.Info
@@ -43,9 +56,15 @@ func (h *ServerHandler) HandleServerInfo(w http.ResponseWriter, r *http.Request)
 	}
 
 	response := ServerInfoResponse{
-		Host: "localhost", // For local development
-		Port: serverPort,
-		URL:  fmt.Sprintf("http://localhost:%s", serverPort),
+		Host:    "localhost", // For local development
+		Port:    serverPort,
+		URL:     fmt.Sprintf("http://localhost:%s", serverPort),
+		Captcha: h.captcha,
+	}
+	if t, ok := tenant.FromContext(r.Context()); ok {
+		response.Branding = &t.Branding
+	} else if t, ok := h.tenants.Get(tenant.Default); ok {
+		response.Branding = &t.Branding
 	}
 
 	jsonrpcx.Success(w, req.ID, response)
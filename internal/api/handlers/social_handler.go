@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/social"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// SocialService interface for player-to-player features
+type SocialService interface {
+	RecentPlayers(ctx context.Context, userID trainer.UserID) ([]social.RecentPlayer, error)
+}
+
+// SocialHandler handles social HTTP requests with JSON-RPC 2.0 format
+type SocialHandler struct {
+	logger        *logger.Logger
+	socialService SocialService
+}
+
+// NewSocialHandler creates a new social handler
+func NewSocialHandler(logger *logger.Logger, socialService SocialService) *SocialHandler {
+	return &SocialHandler{
+		logger:        logger.WithComponent("social-handler"),
+		socialService: socialService,
+	}
+}
+
+// RecentPlayersResponse lists recently met players
+type RecentPlayersResponse struct {
+	Players []social.RecentPlayer `json:"players"`
+}
+
+// HandleRecentPlayers handles POST /api/v1/social.RecentPlayers
+// @Summary List recently met players
+// @Description Get the trainers the player recently spent a few seconds near, newest first, so they can be friended
+// @Tags social
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[RecentPlayersResponse] "Recently met players"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/social.RecentPlayers [post]
+func (h *SocialHandler) HandleRecentPlayers(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	players, err := h.socialService.RecentPlayers(r.Context(), trainer.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to list recent players",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to list recent players")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, RecentPlayersResponse{Players: players})
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// RecentPlayers handles recent player listing (autorouter compatible)
+func (h *SocialHandler) RecentPlayers(w http.ResponseWriter, r *http.Request) {
+	h.HandleRecentPlayers(w, r)
+}
@@ -2,13 +2,12 @@ package handlers
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"math"
 	"net/http"
 	"time"
 
 	"github.com/ThreeDotsLabs/watermill/components/cqrs"
-	jsonpatch "github.com/evanphx/json-patch/v5"
 	"go.uber.org/zap"
 
 	"github.com/danghamo/life/internal/api/jsonrpcx"
@@ -17,14 +16,54 @@ import (
 	"github.com/danghamo/life/internal/domain/shared"
 	"github.com/danghamo/life/internal/domain/trainer"
 	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/plugin"
 )
 
-// MovementBroadcaster interface for broadcasting moving trainer positions
+// MovementBroadcaster interface for the movement simulation, which owns trainer positions
 type MovementBroadcaster interface {
-	AddMovingTrainer(userID, displayName, color string) // displayName can be userID or nickname
-	RemoveMovingTrainer(userID string)
-	UpdateTrainerActivity(userID string)
+	Move(ctx context.Context, userID string, apply func(*trainer.Trainer) error) (*trainer.Trainer, error)
+	Position(ctx context.Context, userID string) (*trainer.Trainer, error)
 	GetCurrentOnlineTrainers(ctx context.Context) []cqrscommands.TrainerMovedEvent
+	BroadcastAge(ctx context.Context) time.Duration
+}
+
+// LatencyTracker estimates users' round trips to the server and the time moves take on it
+type LatencyTracker interface {
+	Observe(userID string, rtt time.Duration)
+	Estimate(userID string) time.Duration
+	Processed(elapsed time.Duration)
+}
+
+// InterestTracker records where trainers are so position updates reach nearby trainers
+type InterestTracker interface {
+	UpdatePosition(ctx context.Context, userID string, position shared.Position) error
+}
+
+// MovementValidator checks movement commands against the server's speed and debounce rules
+type MovementValidator interface {
+	Validate(ctx context.Context, userID string, t *trainer.Trainer, now time.Time) (corrected bool, err error)
+}
+
+// Pathfinder finds walkable paths across the world for click-to-move
+type Pathfinder interface {
+	FindPath(from, to shared.Position) ([]shared.Position, error)
+}
+
+// maxMoveToDistance is the farthest a trainer.MoveTo target may be, in tiles along either axis;
+// it bounds the search each request runs
+const maxMoveToDistance = 64
+
+// ConsumableService interface for using consumable items
+type ConsumableService interface {
+	UseItem(ctx context.Context, userID trainer.UserID, itemID trainer.ItemID, animalID string) (*trainer.ItemUseResult, error)
+}
+
+// ProfileService interface for public trainer profiles
+type ProfileService interface {
+	PublicProfile(ctx context.Context, nickname string) (*trainer.PublicProfile, error)
+	UpdateProfileSettings(ctx context.Context, userID trainer.UserID, hidden []trainer.ProfileField, showcase []string) (*trainer.ProfileSettings, error)
+	PinAnimal(ctx context.Context, userID trainer.UserID, animalID string) (*trainer.ProfileSettings, error)
+	UnpinAnimal(ctx context.Context, userID trainer.UserID, animalID string) (*trainer.ProfileSettings, error)
 }
 
 // TrainerHandler handles trainer-related HTTP requests with JSON-RPC 2.0 format
@@ -32,7 +71,17 @@ type TrainerHandler struct {
 	logger              *logger.Logger
 	repository          trainer.Repository
 	eventBus            *cqrs.EventBus
+	outbox              *cqrscommands.Outbox
 	movementBroadcaster MovementBroadcaster
+	interestTracker     InterestTracker
+	consumableService   ConsumableService
+	profileService      ProfileService
+	terrain             trainer.Terrain
+	pathfinder          Pathfinder
+	plugins             *plugin.Hooks
+	movementValidator   MovementValidator
+	onboarding          Onboarding
+	latency             LatencyTracker
 }
 
 // getOrCreateTrainer gets an existing trainer or creates a default one for the user
@@ -70,7 +119,7 @@ func (h *TrainerHandler) getOrCreateTrainer(ctx context.Context, userID string,
 		return nil, err
 	}
 
-	h.logger.Info("Auto-created trainer for new user",
+	h.logger.WithContext(ctx).Info("Auto-created trainer for new user",
 		zap.String("userId", userID),
 		zap.String("nickname", nickname))
 
@@ -78,12 +127,22 @@ func (h *TrainerHandler) getOrCreateTrainer(ctx context.Context, userID string,
 }
 
 // NewTrainerHandler creates a new trainer handler
-func NewTrainerHandler(logger *logger.Logger, repository trainer.Repository, eventBus *cqrs.EventBus, movementBroadcaster MovementBroadcaster) *TrainerHandler {
+func NewTrainerHandler(logger *logger.Logger, repository trainer.Repository, eventBus *cqrs.EventBus, outbox *cqrscommands.Outbox, movementBroadcaster MovementBroadcaster, interestTracker InterestTracker, consumableService ConsumableService, profileService ProfileService, terrain trainer.Terrain, pathfinder Pathfinder, plugins *plugin.Hooks, movementValidator MovementValidator, onboarding Onboarding, latency LatencyTracker) *TrainerHandler {
 	return &TrainerHandler{
 		logger:              logger.WithComponent("trainer-handler"),
 		repository:          repository,
 		eventBus:            eventBus,
+		outbox:              outbox,
 		movementBroadcaster: movementBroadcaster,
+		interestTracker:     interestTracker,
+		consumableService:   consumableService,
+		profileService:      profileService,
+		terrain:             terrain,
+		pathfinder:          pathfinder,
+		plugins:             plugins,
+		movementValidator:   movementValidator,
+		onboarding:          onboarding,
+		latency:             latency,
 	}
 }
 
@@ -100,16 +159,49 @@ type MoveTrainerRequest struct {
 	DirectionX float64 `json:"direction_x"` // -1, 0, or 1
 	DirectionY float64 `json:"direction_y"` // -1, 0, or 1
 	Action     string  `json:"action"`      // "start" or "stop"
+
+	// Round trip measurement: the server_time of the previous move's response, and how long
+	// the client held it before sending this request, in milliseconds
+	Echo       int64 `json:"echo,omitempty"`
+	HeldMillis int64 `json:"held_ms,omitempty"`
+}
+
+// MoveToTrainerRequest names the tile to walk the trainer to
+type MoveToTrainerRequest struct {
+	X int `json:"x"`
+	Y int `json:"y"`
 }
 
 type ListTrainerRequest struct {
-	OnlineOnly bool `json:"online_only,omitempty"` // Filter to show only currently online trainers
+	OnlineOnly bool   `json:"online_only,omitempty"` // Filter to show only currently online trainers
+	Limit      int    `json:"limit,omitempty"`       // Page size, 50 by default and at most 200
+	Cursor     string `json:"cursor,omitempty"`      // next_cursor of the previous page
+	Sort       string `json:"sort,omitempty"`        // "nickname" (default) or "level"
+	Order      string `json:"order,omitempty"`       // "asc" (default) or "desc"
 }
 
 type FetchPositionRequest struct {
 	// No ID needed - we get it from JWT context
 }
 
+type UseItemRequest struct {
+	ItemID   string `json:"item_id" validate:"required"`
+	AnimalID string `json:"animal_id,omitempty"` // Use on an owned animal instead of the trainer
+}
+
+type PublicProfileRequest struct {
+	Nickname string `json:"nickname" validate:"required"`
+}
+
+type UpdateProfileRequest struct {
+	HiddenFields []trainer.ProfileField `json:"hidden_fields"` // Fields other players cannot see
+	Showcase     []string               `json:"showcase"`      // Owned animal IDs in display order
+}
+
+type ShowcaseAnimalRequest struct {
+	AnimalID string `json:"animal_id" validate:"required"`
+}
+
 type FetchPositionResponse struct {
 	Position shared.Position       `json:"position"`
 	Movement trainer.MovementState `json:"movement"`
@@ -121,12 +213,30 @@ type GetTrainerResponse = trainer.Trainer
 type MoveTrainerResponse struct {
 	Changes              map[string]interface{} `json:"changes"`
 	NextRequestAllowedAt int64                  `json:"next_request_allowed_at"` // Unix timestamp in milliseconds
+	Latency              MoveLatency            `json:"latency"`
+}
+
+// MoveToTrainerResponse is the path the server walks the trainer along
+type MoveToTrainerResponse struct {
+	Path                 []shared.Position     `json:"path"`                    // Centers of the tiles walked through, from the trainer's tile
+	Movement             trainer.MovementState `json:"movement"`                // Current leg and the waypoints left
+	NextRequestAllowedAt int64                 `json:"next_request_allowed_at"` // Unix timestamp in milliseconds
+}
+
+// MoveLatency tells the client about its connection, for showing its quality and sizing
+// prediction buffers. Echoing ServerTime with the next move measures its round trip.
+type MoveLatency struct {
+	RTTEstimateMillis      float64 `json:"rtt_estimate_ms"`      // 0 until a round trip was measured
+	ServerProcessingMillis float64 `json:"server_processing_ms"` // Time spent on this move
+	BroadcastAgeMillis     float64 `json:"broadcast_age_ms"`     // Age of the newest positions broadcast
+	ServerTime             int64   `json:"server_time"`          // Unix timestamp in milliseconds
 }
 type StatusTrainerResponse = trainer.Trainer
 
 type ListTrainerResponse struct {
-	Trainers []TrainerSummary `json:"trainers"`
-	Total    int              `json:"total"`
+	Trainers   []TrainerSummary `json:"trainers"`
+	Total      int              `json:"total"`
+	NextCursor string           `json:"next_cursor,omitempty"` // Empty on the last page
 }
 
 type TrainerSummary struct {
@@ -170,15 +280,14 @@ func (h *TrainerHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var params CreateTrainerRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
 	// Create trainer domain entity
 	nickname := params.Nickname
 	if err := trainer.ValidateNickname(nickname); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, err.Error())
+		jsonrpcx.WithDomainError(r, req.ID, err, "Invalid nickname")
 		return
 	}
 
@@ -189,20 +298,20 @@ func (h *TrainerHandler) HandleCreate(w http.ResponseWriter, r *http.Request) {
 		return trainer.NewTrainer(trainerUserID, nickname)
 	})
 	if err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to create trainer")
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to create trainer")
 		return
 	}
 
 	// Get created trainer for response
 	createdTrainer, err = h.repository.GetByID(r.Context(), trainerUserID)
 	if err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to retrieve created trainer")
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve created trainer")
 		return
 	}
 
 	result := createdTrainer
 
-	h.logger.Info("Trainer created successfully",
+	h.logger.WithContext(r.Context()).Info("Trainer created successfully",
 		zap.String("userId", userID),
 		zap.String("nickname", createdTrainer.Nickname))
 
@@ -242,18 +351,23 @@ func (h *TrainerHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var params GetTrainerRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
 	// Get trainer from repository using UserID from JWT (auto-create if not exists)
 	trainerEntity, err := h.getOrCreateTrainer(r.Context(), userID, "NewPlayer")
 	if err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to retrieve trainer")
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve trainer")
 		return
 	}
 
+	// Register the trainer's position so it receives movement updates from nearby trainers
+	// even before it moves itself
+	if err := h.interestTracker.UpdatePosition(r.Context(), userID, trainerEntity.Movement.CalculateCurrentPosition()); err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to register trainer interest position", zap.String("userId", userID), zap.Error(err))
+	}
+
 	result := trainerEntity
 
 	jsonrpcx.Success(w, req.ID, result)
@@ -273,6 +387,7 @@ func (h *TrainerHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 // @Security BearerAuth
 // @Router /api/v1/trainer.Move [post]
 func (h *TrainerHandler) HandleMove(w http.ResponseWriter, r *http.Request) {
+	received := time.Now()
 	if r.Method != http.MethodPost {
 		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
 		return
@@ -292,11 +407,16 @@ func (h *TrainerHandler) HandleMove(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var params MoveTrainerRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
+	// The time since the echoed response, less what the client held it, is a round trip
+	if params.Echo > 0 {
+		held := time.Duration(params.HeldMillis) * time.Millisecond
+		h.latency.Observe(userID, received.Sub(time.UnixMilli(params.Echo))-held)
+	}
+
 	// Validate direction
 	if err := h.validateDirection(params.DirectionX, params.DirectionY); err != nil {
 		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, err.Error())
@@ -306,49 +426,53 @@ func (h *TrainerHandler) HandleMove(w http.ResponseWriter, r *http.Request) {
 	// Get or create trainer first
 	_, err = h.getOrCreateTrainer(r.Context(), userID, "NewPlayer")
 	if err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to get trainer")
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to get trainer")
 		return
 	}
 
-	// Get original trainer state for comparison
-	originalTrainer, err := h.repository.GetByID(r.Context(), trainer.UserID(userID))
-	if err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to get original trainer state")
-		return
-	}
-	
-
-	// Handle movement command
-	var updatedTrainer *trainer.Trainer
-	trainerUserID := trainer.UserID(userID)
-
-	err = h.repository.FindOneAndUpdate(r.Context(), trainerUserID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
-		if t == nil {
-			return nil, fmt.Errorf("trainer not found")
+	// Handle movement command on the simulation's state, keeping the original for comparison
+	var originalTrainer *trainer.Trainer
+	var moveErr error
+
+	updatedTrainer, err := h.movementBroadcaster.Move(r.Context(), userID, func(t *trainer.Trainer) error {
+		original := *t
+		originalTrainer = &original
+
+		// Check the command against where the simulation puts the trainer now. A trainer found
+		// too far away is put back and stopped, which is kept although the command is rejected.
+		t.UpdatePositionWithin(h.terrain)
+		corrected, err := h.movementValidator.Validate(r.Context(), userID, t, time.Now())
+		if corrected {
+			moveErr = err
+			return nil
+		}
+		if err != nil {
+			moveErr = err
+			return moveErr
 		}
 
-		// Update position from current movement before new command
-		t.UpdatePositionFromMovement()
-
-		// Handle movement action
+		// Handle movement action, keeping the trainer on walkable terrain
 		if params.Action == "start" {
-			if err := t.StartMovement(params.DirectionX, params.DirectionY); err != nil {
-				return nil, err
-			}
+			moveErr = t.StartMovementWithin(params.DirectionX, params.DirectionY, h.terrain)
 		} else if params.Action == "stop" {
-			if err := t.StopMovement(); err != nil {
-				return nil, err
-			}
+			moveErr = t.StopMovementWithin(h.terrain)
 		} else {
-			return nil, fmt.Errorf("invalid action: %s (must be 'start' or 'stop')", params.Action)
+			return fmt.Errorf("invalid action: %s (must be 'start' or 'stop')", params.Action)
 		}
-
-		updatedTrainer = t
-		return t, nil
+		return moveErr
 	})
 
-	if err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, fmt.Sprintf("Failed to move trainer: %v", err))
+	if err != nil && moveErr == nil {
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to move trainer")
+		return
+	}
+
+	if moveErr != nil {
+		if err == nil {
+			// The trainer was put back where it last was; let everyone see it stop there
+			h.publishCorrection(r.Context(), userID, originalTrainer, updatedTrainer)
+		}
+		jsonrpcx.WithDomainError(r, req.ID, moveErr, "Failed to move trainer")
 		return
 	}
 
@@ -357,7 +481,7 @@ func (h *TrainerHandler) HandleMove(w http.ResponseWriter, r *http.Request) {
 	if originalTrainer != nil {
 		changes, err = h.createTrainerChanges(originalTrainer, updatedTrainer)
 		if err != nil {
-			h.logger.Warn("Failed to create changes patch", zap.Error(err))
+			h.logger.WithContext(r.Context()).Warn("Failed to create changes patch", zap.Error(err))
 			// Continue with empty changes rather than failing the request
 			changes = make(map[string]interface{})
 		}
@@ -371,13 +495,11 @@ func (h *TrainerHandler) HandleMove(w http.ResponseWriter, r *http.Request) {
 	var event interface{}
 
 	if params.Action == "start" {
-		// Add to movement broadcaster for periodic position updates
-		h.movementBroadcaster.AddMovingTrainer(userID, userID, updatedTrainer.Color)
-		
 		event = &cqrscommands.TrainerMovedEvent{
 			UserID:    userID,
 			Nickname:  updatedTrainer.Nickname,
 			Color:     updatedTrainer.Color,
+			Showcase:  updatedTrainer.NameplateShowcase(),
 			Position:  updatedTrainer.Position,
 			Movement:  updatedTrainer.Movement,
 			Timestamp: time.Now(),
@@ -385,13 +507,11 @@ func (h *TrainerHandler) HandleMove(w http.ResponseWriter, r *http.Request) {
 			Changes:   changes,
 		}
 	} else {
-		// Remove from movement broadcaster when stopped
-		h.movementBroadcaster.RemoveMovingTrainer(userID)
-		
 		event = &cqrscommands.TrainerStoppedEvent{
 			UserID:    userID,
 			Nickname:  updatedTrainer.Nickname,
 			Color:     updatedTrainer.Color,
+			Showcase:  updatedTrainer.NameplateShowcase(),
 			Position:  updatedTrainer.Position,
 			Movement:  updatedTrainer.Movement,
 			Timestamp: time.Now(),
@@ -400,25 +520,47 @@ func (h *TrainerHandler) HandleMove(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// Publish event for SSE broadcasting
-	if err := h.eventBus.Publish(r.Context(), event); err != nil {
-		h.logger.Error("Failed to publish trainer movement event",
+	// Record the event for SSE broadcasting; once in the outbox it's retried until published.
+	// The movement itself is written behind, so there's no write to record it with.
+	if err := h.outbox.Publish(r.Context(), event); err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to record trainer movement event",
 			zap.Error(err),
 			zap.String("userId", userID),
 			zap.String("action", params.Action))
 		// Don't fail the request if event publishing fails
 	}
 
+	h.plugins.PlayerMoved(r.Context(), plugin.PlayerMove{
+		UserID:     userID,
+		Action:     params.Action,
+		Position:   plugin.Position{X: updatedTrainer.Position.X, Y: updatedTrainer.Position.Y},
+		DirectionX: params.DirectionX,
+		DirectionY: params.DirectionY,
+		At:         time.Now(),
+	})
+	if params.Action == "stop" {
+		h.onboarding.Stopped(r.Context(), trainer.UserID(userID), updatedTrainer.Position)
+	}
+
 	// Calculate next request allowed timestamp (100ms debounce)
 	const debounceMillis = 100
 	nextAllowedAt := time.Now().Add(debounceMillis * time.Millisecond).UnixMilli()
 
+	processing := time.Since(received)
+	h.latency.Processed(processing)
+
 	result := MoveTrainerResponse{
 		Changes:              changes,
 		NextRequestAllowedAt: nextAllowedAt,
+		Latency: MoveLatency{
+			RTTEstimateMillis:      milliseconds(h.latency.Estimate(userID)),
+			ServerProcessingMillis: milliseconds(processing),
+			BroadcastAgeMillis:     milliseconds(h.movementBroadcaster.BroadcastAge(r.Context())),
+			ServerTime:             time.Now().UnixMilli(),
+		},
 	}
 
-	h.logger.Info("Trainer movement command",
+	h.logger.WithContext(r.Context()).Info("Trainer movement command",
 		zap.String("userId", userID),
 		zap.String("action", params.Action),
 		zap.Float64("directionX", params.DirectionX),
@@ -430,9 +572,171 @@ func (h *TrainerHandler) HandleMove(w http.ResponseWriter, r *http.Request) {
 	jsonrpcx.Success(w, req.ID, result)
 }
 
+// HandleMoveTo handles POST /api/v1/trainer.MoveTo
+// @Summary Walk trainer to a tile
+// @Description Find the cheapest walkable path to a tile, avoiding slow terrain, and have the server walk the trainer along it. Progress is broadcast like any movement: positions batches carry the trainer's movement with the waypoints left, and a stop is broadcast when it arrives. A later trainer.Move or trainer.MoveTo replaces the walk. Targets more than 64 tiles away are rejected.
+// @Tags trainer
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[MoveToTrainerRequest] true "JSON-RPC request with MoveToTrainerRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[MoveToTrainerResponse] "Path the trainer walks"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid params, target too far, not walkable or unreachable"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/trainer.MoveTo [post]
+func (h *TrainerHandler) HandleMoveTo(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params MoveToTrainerRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	if _, err := h.getOrCreateTrainer(r.Context(), userID, "NewPlayer"); err != nil {
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to get trainer")
+		return
+	}
+
+	// Search from where the simulation puts the trainer now, so the path starts at its tile
+	target := shared.NewPosition(float64(params.X), float64(params.Y))
+	var originalTrainer *trainer.Trainer
+	var path []shared.Position
+	var moveErr error
+
+	updatedTrainer, err := h.movementBroadcaster.Move(r.Context(), userID, func(t *trainer.Trainer) error {
+		original := *t
+		originalTrainer = &original
+
+		t.UpdatePositionWithin(h.terrain)
+		corrected, err := h.movementValidator.Validate(r.Context(), userID, t, time.Now())
+		if corrected {
+			moveErr = err
+			return nil
+		}
+		if err != nil {
+			moveErr = err
+			return moveErr
+		}
+
+		if math.Max(math.Abs(target.X-t.Position.X), math.Abs(target.Y-t.Position.Y)) > maxMoveToDistance {
+			moveErr = shared.NewDomainErrorf(shared.ErrCodeInvalidMove, "Destination is more than %d tiles away", maxMoveToDistance)
+			return moveErr
+		}
+		path, moveErr = h.pathfinder.FindPath(t.Position, target)
+		if moveErr != nil {
+			return moveErr
+		}
+		moveErr = t.MoveAlong(path, h.terrain)
+		return moveErr
+	})
+
+	if err != nil && moveErr == nil {
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to move trainer")
+		return
+	}
+
+	if moveErr != nil {
+		if err == nil {
+			h.publishCorrection(r.Context(), userID, originalTrainer, updatedTrainer)
+		}
+		jsonrpcx.WithDomainError(r, req.ID, moveErr, "Failed to move trainer")
+		return
+	}
+
+	changes, err := h.createTrainerChanges(originalTrainer, updatedTrainer)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to create changes patch", zap.Error(err))
+		changes = make(map[string]interface{})
+	}
+
+	// The walk is broadcast like a started movement; the simulation follows its waypoints
+	event := &cqrscommands.TrainerMovedEvent{
+		UserID:    userID,
+		Nickname:  updatedTrainer.Nickname,
+		Color:     updatedTrainer.Color,
+		Showcase:  updatedTrainer.NameplateShowcase(),
+		Position:  updatedTrainer.Position,
+		Movement:  updatedTrainer.Movement,
+		Timestamp: time.Now(),
+		RequestID: fmt.Sprintf("%s-%d", userID, time.Now().UnixNano()),
+		Changes:   changes,
+	}
+	if err := h.outbox.Publish(r.Context(), event); err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to record trainer movement event",
+			zap.Error(err),
+			zap.String("userId", userID),
+			zap.String("action", "move_to"))
+	}
+
+	h.plugins.PlayerMoved(r.Context(), plugin.PlayerMove{
+		UserID:     userID,
+		Action:     "start",
+		Position:   plugin.Position{X: updatedTrainer.Position.X, Y: updatedTrainer.Position.Y},
+		DirectionX: updatedTrainer.Movement.Direction.X,
+		DirectionY: updatedTrainer.Movement.Direction.Y,
+		At:         time.Now(),
+	})
+
+	h.logger.WithContext(r.Context()).Info("Trainer walking to destination",
+		zap.String("userId", userID),
+		zap.Int("targetX", params.X),
+		zap.Int("targetY", params.Y),
+		zap.Int("pathLength", len(path)),
+		zap.Int("waypoints", len(updatedTrainer.Movement.Waypoints)))
+
+	const debounceMillis = 100
+	jsonrpcx.Success(w, req.ID, MoveToTrainerResponse{
+		Path:                 path,
+		Movement:             updatedTrainer.Movement,
+		NextRequestAllowedAt: time.Now().Add(debounceMillis * time.Millisecond).UnixMilli(),
+	})
+}
+
+// publishCorrection announces a trainer that was put back by the movement validator as
+// stopped at its corrected position
+func (h *TrainerHandler) publishCorrection(ctx context.Context, userID string, original, t *trainer.Trainer) {
+	changes, err := h.createTrainerChanges(original, t)
+	if err != nil {
+		changes = make(map[string]interface{})
+	}
+
+	event := &cqrscommands.TrainerStoppedEvent{
+		UserID:    userID,
+		Nickname:  t.Nickname,
+		Color:     t.Color,
+		Showcase:  t.NameplateShowcase(),
+		Position:  t.Position,
+		Movement:  t.Movement,
+		Timestamp: time.Now(),
+		RequestID: fmt.Sprintf("%s-%d", userID, time.Now().UnixNano()),
+		Changes:   changes,
+	}
+	if err := h.eventBus.Publish(ctx, event); err != nil {
+		h.logger.WithContext(ctx).Error("Failed to publish trainer position correction",
+			zap.Error(err),
+			zap.String("userId", userID))
+	}
+}
+
 // HandleList handles POST /api/v1/trainer.List
-// @Summary List all trainers
-// @Description Get a list of all trainers in the game world (currently returns mock data)
+// @Summary List trainers
+// @Description Get one page of trainers sorted by nickname or level, excluding the caller. Pass next_cursor as cursor to get the following page; total counts all trainers. With online_only, all online trainers are returned unpaged.
 // @Tags trainer
 // @Accept json
 // @Produce json
@@ -457,8 +761,7 @@ func (h *TrainerHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 
 	// Parse request parameters
 	var params ListTrainerRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid request parameters")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
@@ -470,10 +773,12 @@ func (h *TrainerHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var trainerSummaries []TrainerSummary
+	var total int
+	var nextCursor string
 
 	if params.OnlineOnly {
 		// Get only currently online trainers from movement broadcaster
-		h.logger.Debug("Filtering for online trainers only")
+		h.logger.WithContext(r.Context()).Debug("Filtering for online trainers only")
 		onlineEvents := h.movementBroadcaster.GetCurrentOnlineTrainers(r.Context())
 		
 		for _, event := range onlineEvents {
@@ -490,17 +795,25 @@ func (h *TrainerHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 				})
 			}
 		}
+		total = len(trainerSummaries)
 	} else {
-		// Get all trainers from repository (original behavior)
-		trainers, err := h.repository.GetAll(r.Context())
+		query, err := trainer.NewListQuery(params.Sort, params.Order, params.Cursor, params.Limit)
+		if err != nil {
+			jsonrpcx.WithDomainError(r, req.ID, err, "Invalid list query")
+			return
+		}
+
+		page, err := h.repository.List(r.Context(), query)
 		if err != nil {
-			h.logger.Error("Failed to list trainers", zap.Error(err))
-			jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to retrieve trainers")
+			h.logger.WithContext(r.Context()).Error("Failed to list trainers", zap.Error(err))
+			jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve trainers")
 			return
 		}
+		total = page.Total
+		nextCursor = page.NextCursor
 
 		// Convert to response format, excluding current user
-		for _, t := range trainers {
+		for _, t := range page.Trainers {
 			if string(t.ID) != currentUserID {
 				// Update position from movement before returning
 				t.UpdatePositionFromMovement()
@@ -520,8 +833,9 @@ func (h *TrainerHandler) HandleList(w http.ResponseWriter, r *http.Request) {
 	}
 
 	result := ListTrainerResponse{
-		Trainers: trainerSummaries,
-		Total:    len(trainerSummaries),
+		Trainers:   trainerSummaries,
+		Total:      total,
+		NextCursor: nextCursor,
 	}
 
 	jsonrpcx.Success(w, req.ID, result)
@@ -560,15 +874,14 @@ func (h *TrainerHandler) HandleStatus(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var params GetTrainerRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
 	// Get trainer status from repository using UserID from JWT (auto-create if not exists)
 	trainerEntity, err := h.getOrCreateTrainer(r.Context(), userID, "NewPlayer")
 	if err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to retrieve trainer status")
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve trainer status")
 		return
 	}
 
@@ -577,6 +890,217 @@ func (h *TrainerHandler) HandleStatus(w http.ResponseWriter, r *http.Request) {
 	jsonrpcx.Success(w, req.ID, result)
 }
 
+// HandleUseItem handles POST /api/v1/trainer.UseItem
+// @Summary Use a consumable item
+// @Description Consume one item from a stack and apply its effect to the trainer or to one of the trainer's animals
+// @Tags trainer
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[UseItemRequest] true "JSON-RPC request with UseItemRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[trainer.ItemUseResult] "Applied effect"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Item not usable, on cooldown or invalid target"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/trainer.UseItem [post]
+func (h *TrainerHandler) HandleUseItem(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params UseItemRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	result, err := h.consumableService.UseItem(r.Context(), trainer.UserID(userID), trainer.ItemID(params.ItemID), params.AnimalID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to use item",
+			zap.String("userId", userID),
+			zap.String("itemId", params.ItemID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to use item")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, result)
+}
+
+// HandlePublicProfile handles POST /api/v1/trainer.PublicProfile
+// @Summary Get another trainer's public profile
+// @Description Get the profile of a trainer by nickname, leaving out the fields the trainer has hidden
+// @Tags trainer
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[PublicProfileRequest] true "JSON-RPC request with PublicProfileRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[trainer.PublicProfile] "Public profile"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid request parameters or trainer not found"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/trainer.PublicProfile [post]
+func (h *TrainerHandler) HandlePublicProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	if _, ok := middleware.GetUserID(r.Context()); !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params PublicProfileRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	profile, err := h.profileService.PublicProfile(r.Context(), params.Nickname)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to get public profile",
+			zap.String("nickname", params.Nickname),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to get public profile")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, profile)
+}
+
+// HandleUpdateProfile handles POST /api/v1/trainer.UpdateProfile
+// @Summary Update public profile settings
+// @Description Choose which profile fields other players can see and which owned animals are showcased
+// @Tags trainer
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[UpdateProfileRequest] true "JSON-RPC request with UpdateProfileRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[trainer.ProfileSettings] "Updated profile settings"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Unknown field, too many or unowned showcased animals"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/trainer.UpdateProfile [post]
+func (h *TrainerHandler) HandleUpdateProfile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params UpdateProfileRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	settings, err := h.profileService.UpdateProfileSettings(r.Context(), trainer.UserID(userID), params.HiddenFields, params.Showcase)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to update profile settings",
+			zap.String("userId", userID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to update profile settings")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, settings)
+}
+
+// HandlePinAnimal handles POST /api/v1/trainer.PinAnimal
+// @Summary Pin an animal to the showcase
+// @Description Show one of the trainer's animals on their public profile and nameplate
+// @Tags trainer
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[ShowcaseAnimalRequest] true "JSON-RPC request with ShowcaseAnimalRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[trainer.ProfileSettings] "Updated profile settings"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Animal not owned or showcase full"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/trainer.PinAnimal [post]
+func (h *TrainerHandler) HandlePinAnimal(w http.ResponseWriter, r *http.Request) {
+	h.handleShowcase(w, r, h.profileService.PinAnimal)
+}
+
+// HandleUnpinAnimal handles POST /api/v1/trainer.UnpinAnimal
+// @Summary Unpin an animal from the showcase
+// @Description Remove an animal from the trainer's public profile and nameplate
+// @Tags trainer
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[ShowcaseAnimalRequest] true "JSON-RPC request with ShowcaseAnimalRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[trainer.ProfileSettings] "Updated profile settings"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Animal is not showcased"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/trainer.UnpinAnimal [post]
+func (h *TrainerHandler) HandleUnpinAnimal(w http.ResponseWriter, r *http.Request) {
+	h.handleShowcase(w, r, h.profileService.UnpinAnimal)
+}
+
+// handleShowcase parses a showcase request and applies a pin or unpin
+func (h *TrainerHandler) handleShowcase(w http.ResponseWriter, r *http.Request, apply func(ctx context.Context, userID trainer.UserID, animalID string) (*trainer.ProfileSettings, error)) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params ShowcaseAnimalRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	settings, err := apply(r.Context(), trainer.UserID(userID), params.AnimalID)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to update showcase",
+			zap.String("userId", userID),
+			zap.String("animalId", params.AnimalID),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to update showcase")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, settings)
+}
+
 // HandleFetchPosition handles POST /api/v1/trainer.FetchPosition
 // @Summary Fetch trainer position and movement state
 // @Description Get current position and movement state for synchronization fallback
@@ -610,35 +1134,21 @@ func (h *TrainerHandler) HandleFetchPosition(w http.ResponseWriter, r *http.Requ
 	}
 
 	var params FetchPositionRequest
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
-	// Get trainer and update position from movement
-	trainerUserID := trainer.UserID(userID)
-	var currentTrainer *trainer.Trainer
-
-	err = h.repository.FindOneAndUpdate(r.Context(), trainerUserID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
-		if t == nil {
-			return nil, fmt.Errorf("trainer not found")
-		}
-
-		// Update position from current movement state
-		t.UpdatePositionFromMovement()
-		currentTrainer = t
-		return t, nil
-	})
-
+	// Get the trainer's position from the movement simulation
+	currentTrainer, err := h.movementBroadcaster.Position(r.Context(), userID)
 	if err != nil {
 		// Try to get trainer without update (fallback for read-only access)
 		currentTrainer, err = h.getOrCreateTrainer(r.Context(), userID, "NewPlayer")
 		if err != nil {
-			jsonrpcx.WithError(r, req.ID, jsonrpcx.InternalError, "Failed to fetch trainer position")
+			jsonrpcx.WithDomainError(r, req.ID, err, "Failed to fetch trainer position")
 			return
 		}
 		// Update position calculation for response
-		currentTrainer.UpdatePositionFromMovement()
+		currentTrainer.UpdatePositionWithin(h.terrain)
 	}
 
 	result := FetchPositionResponse{
@@ -646,7 +1156,7 @@ func (h *TrainerHandler) HandleFetchPosition(w http.ResponseWriter, r *http.Requ
 		Movement: currentTrainer.Movement,
 	}
 
-	h.logger.Debug("Fetched trainer position",
+	h.logger.WithContext(r.Context()).Debug("Fetched trainer position",
 		zap.String("userId", userID),
 		zap.Float64("x", currentTrainer.Position.X),
 		zap.Float64("y", currentTrainer.Position.Y),
@@ -687,64 +1197,71 @@ func (h *TrainerHandler) validateDirection(dirX, dirY float64) error {
 	return nil
 }
 
-// validateMovement validates trainer movement coordinates
-func (h *TrainerHandler) validateMovement(x, y float64) error {
-	// Map boundaries validation (assuming 30x20 map from world handler)
-	const maxX, maxY = 30, 20
-
-	if x < 0 || x >= maxX {
-		return fmt.Errorf("invalid X coordinate: %f (must be 0-%d)", x, maxX-1)
+// createTrainerChanges creates a JSON merge patch containing only changed fields.
+// A move only changes the position and movement, so those are compared field by field instead of
+// diffing the whole marshaled trainer. updated_at is left out: Timestamp marshals as {}, so it never
+// differed in the patch either.
+func (h *TrainerHandler) createTrainerChanges(original, updated *trainer.Trainer) (map[string]interface{}, error) {
+	if original == nil || updated == nil {
+		return nil, fmt.Errorf("original or updated trainer is nil")
 	}
 
-	if y < 0 || y >= maxY {
-		return fmt.Errorf("invalid Y coordinate: %f (must be 0-%d)", y, maxY-1)
-	}
+	changes := make(map[string]interface{})
 
-	// TODO: Add obstacle checking logic from world data
-	// For now, just check for basic water tiles at specific coordinates
-	waterTiles := []struct{ x, y float64 }{
-		{2, 0}, {0, 2}, // From world handler mock data
+	if position := xyChanges(original.Position.X, original.Position.Y, updated.Position.X, updated.Position.Y); position != nil {
+		changes["position"] = position
 	}
-
-	for _, tile := range waterTiles {
-		if x == tile.x && y == tile.y {
-			return fmt.Errorf("cannot move to water tile at (%f, %f)", x, y)
-		}
+	if movement := movementChanges(original.Movement, updated.Movement); movement != nil {
+		changes["movement"] = movement
 	}
 
-	return nil
+	return changes, nil
 }
 
-// createTrainerChanges creates a JSON merge patch containing only changed fields
-func (h *TrainerHandler) createTrainerChanges(original, updated *trainer.Trainer) (map[string]interface{}, error) {
-	if original == nil || updated == nil {
-		return nil, fmt.Errorf("original or updated trainer is nil")
+// xyChanges returns the merge patch of an {x, y} object such as a position, or nil if it didn't change
+func xyChanges(originalX, originalY, updatedX, updatedY float64) map[string]interface{} {
+	var changes map[string]interface{}
+	if originalX != updatedX {
+		changes = map[string]interface{}{"x": updatedX}
 	}
-
-	// Marshal both trainers to JSON
-	originalJSON, err := json.Marshal(original)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal original trainer: %w", err)
+	if originalY != updatedY {
+		if changes == nil {
+			changes = make(map[string]interface{}, 1)
+		}
+		changes["y"] = updatedY
 	}
+	return changes
+}
 
-	updatedJSON, err := json.Marshal(updated)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal updated trainer: %w", err)
-	}
+// movementChanges returns the merge patch of a movement state, or nil if it didn't change
+func movementChanges(original, updated trainer.MovementState) map[string]interface{} {
+	changes := make(map[string]interface{})
 
-	// Create merge patch using json-patch library
-	mergePatch, err := jsonpatch.CreateMergePatch(originalJSON, updatedJSON)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create merge patch: %w", err)
+	if direction := xyChanges(original.Direction.X, original.Direction.Y, updated.Direction.X, updated.Direction.Y); direction != nil {
+		changes["direction"] = direction
+	}
+	if original.Speed != updated.Speed {
+		changes["speed"] = updated.Speed
+	}
+	if !original.StartTime.Equal(updated.StartTime) {
+		changes["start_time"] = updated.StartTime.Format(time.RFC3339Nano)
+	}
+	if startPos := xyChanges(original.StartPos.X, original.StartPos.Y, updated.StartPos.X, updated.StartPos.Y); startPos != nil {
+		changes["start_pos"] = startPos
+	}
+	if original.IsMoving != updated.IsMoving {
+		changes["is_moving"] = updated.IsMoving
 	}
 
-	// Unmarshal merge patch to map
-	var changes map[string]interface{}
-	if err := json.Unmarshal(mergePatch, &changes); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal merge patch: %w", err)
+	if len(changes) == 0 {
+		return nil
 	}
+	return changes
+}
 
-	return changes, nil
+// milliseconds returns a duration in milliseconds with a tenth of a millisecond precision
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Round(100*time.Microsecond)) / float64(time.Millisecond)
 }
 
 // === AutoRouter Compatible Methods ===
@@ -765,6 +1282,11 @@ func (h *TrainerHandler) Move(w http.ResponseWriter, r *http.Request) {
 	h.HandleMove(w, r)
 }
 
+// MoveTo handles walking the trainer to a tile (autorouter compatible)
+func (h *TrainerHandler) MoveTo(w http.ResponseWriter, r *http.Request) {
+	h.HandleMoveTo(w, r)
+}
+
 // FetchPosition handles position fetching (autorouter compatible)
 func (h *TrainerHandler) FetchPosition(w http.ResponseWriter, r *http.Request) {
 	h.HandleFetchPosition(w, r)
@@ -780,3 +1302,28 @@ func (h *TrainerHandler) Status(w http.ResponseWriter, r *http.Request) {
 	h.HandleStatus(w, r)
 }
 
+// UseItem handles consumable use (autorouter compatible)
+func (h *TrainerHandler) UseItem(w http.ResponseWriter, r *http.Request) {
+	h.HandleUseItem(w, r)
+}
+
+// PublicProfile handles public profile retrieval (autorouter compatible)
+func (h *TrainerHandler) PublicProfile(w http.ResponseWriter, r *http.Request) {
+	h.HandlePublicProfile(w, r)
+}
+
+// UpdateProfile handles profile settings updates (autorouter compatible)
+func (h *TrainerHandler) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	h.HandleUpdateProfile(w, r)
+}
+
+// PinAnimal handles showcase pinning (autorouter compatible)
+func (h *TrainerHandler) PinAnimal(w http.ResponseWriter, r *http.Request) {
+	h.HandlePinAnimal(w, r)
+}
+
+// UnpinAnimal handles showcase unpinning (autorouter compatible)
+func (h *TrainerHandler) UnpinAnimal(w http.ResponseWriter, r *http.Request) {
+	h.HandleUnpinAnimal(w, r)
+}
+
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// mergePatchChanges is the reference createTrainerChanges is checked against: a JSON merge patch
+// of the whole marshaled trainers
+func mergePatchChanges(t *testing.T, original, updated *trainer.Trainer) map[string]interface{} {
+	originalJSON, err := json.Marshal(original)
+	require.NoError(t, err)
+	updatedJSON, err := json.Marshal(updated)
+	require.NoError(t, err)
+
+	patch, err := jsonpatch.CreateMergePatch(originalJSON, updatedJSON)
+	require.NoError(t, err)
+
+	var changes map[string]interface{}
+	require.NoError(t, json.Unmarshal(patch, &changes))
+	return changes
+}
+
+func TestCreateTrainerChanges_MatchesMergePatch(t *testing.T) {
+	moving, err := trainer.NewTrainer("user-1", "Tester")
+	require.NoError(t, err)
+	require.NoError(t, moving.StartMovement(1, 0))
+	moving.Movement.StartTime = time.Now().Add(-2 * time.Second)
+
+	tests := []struct {
+		name  string
+		start func() *trainer.Trainer
+		apply func(*trainer.Trainer)
+	}{
+		{"start from rest", func() *trainer.Trainer {
+			tr, err := trainer.NewTrainer("user-1", "Tester")
+			require.NoError(t, err)
+			return tr
+		}, func(tr *trainer.Trainer) { require.NoError(t, tr.StartMovement(0, -1)) }},
+		{"turn while moving", func() *trainer.Trainer { c := *moving; return &c },
+			func(tr *trainer.Trainer) { require.NoError(t, tr.StartMovement(0, 1)) }},
+		{"stop", func() *trainer.Trainer { c := *moving; return &c },
+			func(tr *trainer.Trainer) { require.NoError(t, tr.StopMovement()) }},
+		{"teleport", func() *trainer.Trainer { c := *moving; return &c },
+			func(tr *trainer.Trainer) { require.NoError(t, tr.MoveTo(shared.Position{X: 3, Y: 10})) }},
+		{"no change", func() *trainer.Trainer { c := *moving; return &c },
+			func(tr *trainer.Trainer) {}},
+	}
+
+	h := &TrainerHandler{}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := tt.start()
+			updated := *original
+			tt.apply(&updated)
+
+			changes, err := h.createTrainerChanges(original, &updated)
+			require.NoError(t, err)
+			assert.Equal(t, mergePatchChanges(t, original, &updated), changes)
+		})
+	}
+}
+
+func BenchmarkCreateTrainerChanges(b *testing.B) {
+	original, err := trainer.NewTrainer("user-1", "Tester")
+	require.NoError(b, err)
+
+	// A start command changes only the movement, like most moves
+	updated := *original
+	require.NoError(b, updated.StartMovement(1, 0))
+	updated.Movement.StartTime = original.Movement.StartTime.Add(time.Second)
+
+	h := &TrainerHandler{}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := h.createTrainerChanges(original, &updated); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/bullet"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/tutorial"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// TutorialService interface for the onboarding tutorial
+type TutorialService interface {
+	Start(ctx context.Context, userID trainer.UserID) (*tutorial.Progress, error)
+	Get(ctx context.Context, userID trainer.UserID) (*tutorial.Progress, error)
+	Skip(ctx context.Context, userID trainer.UserID) (*tutorial.Progress, error)
+}
+
+// Onboarding follows players through the mechanics the tutorial teaches. Handlers report
+// what a player did once it succeeded; following never fails the request.
+type Onboarding interface {
+	Stopped(ctx context.Context, userID trainer.UserID, position shared.Position)
+	Fired(ctx context.Context, fired *bullet.Bullet)
+	Captured(ctx context.Context, userID trainer.UserID, result *trainer.CaptureResult)
+}
+
+// TutorialHandler handles tutorial-related HTTP requests with JSON-RPC 2.0 format
+type TutorialHandler struct {
+	logger          *logger.Logger
+	tutorialService TutorialService
+}
+
+// NewTutorialHandler creates a new tutorial handler
+func NewTutorialHandler(logger *logger.Logger, tutorialService TutorialService) *TutorialHandler {
+	return &TutorialHandler{
+		logger:          logger.WithComponent("tutorial-handler"),
+		tutorialService: tutorialService,
+	}
+}
+
+// Response structures for Swagger documentation
+type TutorialResponse struct {
+	Progress *tutorial.Progress `json:"progress"`
+}
+
+// HandleStart handles POST /api/v1/tutorial.Start
+// @Summary Start the tutorial
+// @Description Open a practice range around the trainer, with stationary targets and a coach bot, and start the first step: walk to the marker, then knock the targets down, then catch an animal with the net the coach hands out. Progress is pushed as tutorial.progress. A tutorial under way is returned as it is.
+// @Tags tutorial
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[TutorialResponse] "Tutorial progress"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Tutorial already over (-32602)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 404 {object} jsonrpcx.ErrorResponse "Trainer not found"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/tutorial.Start [post]
+func (h *TutorialHandler) HandleStart(w http.ResponseWriter, r *http.Request) {
+	h.handleProgress(w, r, "start", h.tutorialService.Start)
+}
+
+// HandleGet handles POST /api/v1/tutorial.Get
+// @Summary Get tutorial progress
+// @Description Get the trainer's step in the tutorial along with their practice range and what the coach last said
+// @Tags tutorial
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[TutorialResponse] "Tutorial progress"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 404 {object} jsonrpcx.ErrorResponse "Tutorial not started"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/tutorial.Get [post]
+func (h *TutorialHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	h.handleProgress(w, r, "get", h.tutorialService.Get)
+}
+
+// HandleSkip handles POST /api/v1/tutorial.Skip
+// @Summary Skip the tutorial
+// @Description End the tutorial without going through the remaining steps and close the practice range. It can't be started again afterwards.
+// @Tags tutorial
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[TutorialResponse] "Tutorial progress"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Tutorial already over (-32602)"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/tutorial.Skip [post]
+func (h *TutorialHandler) HandleSkip(w http.ResponseWriter, r *http.Request) {
+	h.handleProgress(w, r, "skip", h.tutorialService.Skip)
+}
+
+// handleProgress answers a request with the trainer's progress as changed by action
+func (h *TutorialHandler) handleProgress(w http.ResponseWriter, r *http.Request, name string, action func(context.Context, trainer.UserID) (*tutorial.Progress, error)) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	progress, err := action(r.Context(), trainer.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Tutorial request failed",
+			zap.String("userId", userID),
+			zap.String("action", name),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to "+name+" tutorial")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, TutorialResponse{Progress: progress})
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Start handles starting the tutorial (autorouter compatible)
+func (h *TutorialHandler) Start(w http.ResponseWriter, r *http.Request) {
+	h.HandleStart(w, r)
+}
+
+// Get handles getting tutorial progress (autorouter compatible)
+func (h *TutorialHandler) Get(w http.ResponseWriter, r *http.Request) {
+	h.HandleGet(w, r)
+}
+
+// Skip handles skipping the tutorial (autorouter compatible)
+func (h *TutorialHandler) Skip(w http.ResponseWriter, r *http.Request) {
+	h.HandleSkip(w, r)
+}
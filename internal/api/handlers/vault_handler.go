@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/vault"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// VaultService interface for account vault storage
+type VaultService interface {
+	Locations() []vault.Location
+	GetVault(ctx context.Context, userID trainer.UserID) (*vault.Vault, error)
+	Deposit(ctx context.Context, userID trainer.UserID, itemIDs []trainer.ItemID) (*vault.Vault, error)
+	Withdraw(ctx context.Context, userID trainer.UserID, itemIDs []trainer.ItemID) (*vault.Vault, error)
+}
+
+// VaultHandler handles vault-related HTTP requests with JSON-RPC 2.0 format
+type VaultHandler struct {
+	logger       *logger.Logger
+	vaultService VaultService
+}
+
+// NewVaultHandler creates a new vault handler
+func NewVaultHandler(logger *logger.Logger, vaultService VaultService) *VaultHandler {
+	return &VaultHandler{
+		logger:       logger.WithComponent("vault-handler"),
+		vaultService: vaultService,
+	}
+}
+
+// Request parameter structures
+type VaultTransferRequest struct {
+	ItemIDs []string `json:"item_ids" validate:"min=1,max=50"` // Up to 50 stacks per transfer
+}
+
+// Response structures for Swagger documentation
+type VaultLocationsResponse struct {
+	Locations []vault.Location `json:"locations"`
+}
+
+type VaultResponse struct {
+	Vault     *vault.Vault `json:"vault"`
+	UsedSlots int          `json:"used_slots"`
+}
+
+// HandleLocations handles POST /api/v1/vault.Locations
+// @Summary List vault locations
+// @Description Get the bases and outposts where the vault can be accessed
+// @Tags vault
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[VaultLocationsResponse] "Vault locations"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid request"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/vault.Locations [post]
+func (h *VaultHandler) HandleLocations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, VaultLocationsResponse{Locations: h.vaultService.Locations()})
+}
+
+// HandleGet handles POST /api/v1/vault.Get
+// @Summary Get vault contents
+// @Description Get the items stored in the authenticated user's vault
+// @Tags vault
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[VaultResponse] "Vault contents"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/vault.Get [post]
+func (h *VaultHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	v, err := h.vaultService.GetVault(r.Context(), trainer.UserID(userID))
+	if err != nil {
+		h.logger.WithContext(r.Context()).Error("Failed to get vault", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to retrieve vault")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, VaultResponse{Vault: v, UsedSlots: v.GetUsedSlots()})
+}
+
+// HandleDeposit handles POST /api/v1/vault.Deposit
+// @Summary Deposit items into the vault
+// @Description Move items from the carried inventory into the vault; the trainer must stand at a vault location
+// @Tags vault
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[VaultTransferRequest] true "JSON-RPC request with VaultTransferRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[VaultResponse] "Updated vault"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid items, vault full or not at a vault location"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/vault.Deposit [post]
+func (h *VaultHandler) HandleDeposit(w http.ResponseWriter, r *http.Request) {
+	h.handleTransfer(w, r, "deposit", h.vaultService.Deposit)
+}
+
+// HandleWithdraw handles POST /api/v1/vault.Withdraw
+// @Summary Withdraw items from the vault
+// @Description Move items from the vault into the carried inventory; the trainer must stand at a vault location
+// @Tags vault
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[VaultTransferRequest] true "JSON-RPC request with VaultTransferRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[VaultResponse] "Updated vault"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid items, inventory full or not at a vault location"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/vault.Withdraw [post]
+func (h *VaultHandler) HandleWithdraw(w http.ResponseWriter, r *http.Request) {
+	h.handleTransfer(w, r, "withdraw", h.vaultService.Withdraw)
+}
+
+// handleTransfer runs a deposit or withdrawal request
+func (h *VaultHandler) handleTransfer(
+	w http.ResponseWriter,
+	r *http.Request,
+	operation string,
+	transfer func(ctx context.Context, userID trainer.UserID, itemIDs []trainer.ItemID) (*vault.Vault, error),
+) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	// Get user info from context
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params VaultTransferRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	itemIDs := make([]trainer.ItemID, len(params.ItemIDs))
+	for i, id := range params.ItemIDs {
+		itemIDs[i] = trainer.ItemID(id)
+	}
+
+	v, err := transfer(r.Context(), trainer.UserID(userID), itemIDs)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Vault transfer failed",
+			zap.String("userId", userID),
+			zap.String("operation", operation),
+			zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Vault transfer failed")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, VaultResponse{Vault: v, UsedSlots: v.GetUsedSlots()})
+}
+
+// === AutoRouter Compatible Methods ===
+// These methods are designed to work with the autorouter package
+
+// Locations handles vault location listing (autorouter compatible)
+func (h *VaultHandler) Locations(w http.ResponseWriter, r *http.Request) {
+	h.HandleLocations(w, r)
+}
+
+// Get handles vault retrieval (autorouter compatible)
+func (h *VaultHandler) Get(w http.ResponseWriter, r *http.Request) {
+	h.HandleGet(w, r)
+}
+
+// Deposit handles vault deposits (autorouter compatible)
+func (h *VaultHandler) Deposit(w http.ResponseWriter, r *http.Request) {
+	h.HandleDeposit(w, r)
+}
+
+// Withdraw handles vault withdrawals (autorouter compatible)
+func (h *VaultHandler) Withdraw(w http.ResponseWriter, r *http.Request) {
+	h.HandleWithdraw(w, r)
+}
@@ -1,22 +1,41 @@
 package handlers
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 
+	"go.uber.org/zap"
+
 	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/domain/world"
 	"github.com/danghamo/life/pkg/logger"
 )
 
+// ChunkStreamService interface for streaming the world chunk by chunk
+type ChunkStreamService interface {
+	GetChunk(cx, cy int) (*world.ChunkTiles, error)
+	Subscribe(ctx context.Context, userID string, chunks []world.Chunk) ([]world.Chunk, error)
+}
+
+// WorldClock interface for reading the game time and weather
+type WorldClock interface {
+	Now(ctx context.Context) (*world.ClockReading, error)
+}
+
 // WorldHandler handles world-related HTTP requests with JSON-RPC 2.0 format
 type WorldHandler struct {
-	logger *logger.Logger
+	logger      *logger.Logger
+	chunkStream ChunkStreamService
+	clock       WorldClock
 }
 
 // NewWorldHandler creates a new world handler
-func NewWorldHandler(logger *logger.Logger) *WorldHandler {
+func NewWorldHandler(logger *logger.Logger, chunkStream ChunkStreamService, clock WorldClock) *WorldHandler {
 	return &WorldHandler{
-		logger: logger.WithComponent("world-handler"),
+		logger:      logger.WithComponent("world-handler"),
+		chunkStream: chunkStream,
+		clock:       clock,
 	}
 }
 
@@ -25,6 +44,21 @@ type GetWorldParams struct {
 	ID string `json:"id"`
 }
 
+type GetChunkRequest struct {
+	CX int `json:"cx"`
+	CY int `json:"cy"`
+}
+
+type SubscribeChunksRequest struct {
+	Chunks []world.Chunk `json:"chunks"` // Replaces the chunks subscribed to before; empty unsubscribes
+}
+
+// SubscribeChunksResponse lists the chunks subscribed to
+type SubscribeChunksResponse struct {
+	Chunks    []world.Chunk `json:"chunks"`
+	ChunkSize int           `json:"chunk_size"`
+}
+
 // HandleGet handles POST /api/v1/world.Get
 func (h *WorldHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -39,8 +73,7 @@ func (h *WorldHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 	}
 
 	var params GetWorldParams
-	if err := json.Unmarshal(req.Params, &params); err != nil {
-		jsonrpcx.WithError(r, req.ID, jsonrpcx.InvalidParams, "Invalid params")
+	if !jsonrpcx.BindParams(r, req, &params) {
 		return
 	}
 
@@ -67,6 +100,124 @@ func (h *WorldHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 	jsonrpcx.Success(w, req.ID, result)
 }
 
+// HandleGetChunk handles POST /api/v1/world.GetChunk
+// @Summary Get the tiles of a world chunk
+// @Description Get the terrain and static entities of one chunk of 16 by 16 tiles. Clients stream the world by fetching the chunks around their trainer as it moves; terrain rows always have chunk_size tiles, empty past the world's edge.
+// @Tags world
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[GetChunkRequest] true "JSON-RPC request with GetChunkRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[world.ChunkTiles] "Tiles of the chunk"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid params or chunk outside the world"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Security BearerAuth
+// @Router /api/v1/world.GetChunk [post]
+func (h *WorldHandler) HandleGetChunk(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params GetChunkRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	chunk, err := h.chunkStream.GetChunk(params.CX, params.CY)
+	if err != nil {
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to get chunk")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, chunk)
+}
+
+// HandleSubscribeChunks handles POST /api/v1/world.SubscribeChunks
+// @Summary Subscribe to world chunks
+// @Description Replace the chunks the player streams, at most 25. Trainers and animals entering or leaving a subscribed chunk are pushed as world.chunk.entity_entered and world.chunk.entity_left. Subscriptions last 10 minutes; subscribe again as the trainer moves to keep them. Chunks outside the world are left out of the response.
+// @Tags world
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.RequestT[SubscribeChunksRequest] true "JSON-RPC request with SubscribeChunksRequest params"
+// @Success 200 {object} jsonrpcx.ResponseT[SubscribeChunksResponse] "Chunks subscribed to"
+// @Failure 400 {object} jsonrpcx.ErrorResponse "Invalid params or too many chunks"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/world.SubscribeChunks [post]
+func (h *WorldHandler) HandleSubscribeChunks(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "User not authenticated")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	var params SubscribeChunksRequest
+	if !jsonrpcx.BindParams(r, req, &params) {
+		return
+	}
+
+	chunks, err := h.chunkStream.Subscribe(r.Context(), userID, params.Chunks)
+	if err != nil {
+		h.logger.WithContext(r.Context()).Warn("Failed to subscribe to chunks", zap.String("userId", userID), zap.Error(err))
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to subscribe to chunks")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, SubscribeChunksResponse{Chunks: chunks, ChunkSize: world.ChunkSize})
+}
+
+// HandleGetTime handles POST /api/v1/world.GetTime
+// @Summary Get the game time and weather
+// @Description Get the game time, phase of the day and weather for lighting. Changes of the hour or weather are pushed as world.time_changed; interpolate the minutes between them with day_length_seconds.
+// @Tags world
+// @Accept json
+// @Produce json
+// @Param request body jsonrpcx.Request true "JSON-RPC request"
+// @Success 200 {object} jsonrpcx.ResponseT[world.ClockReading] "Game time and weather"
+// @Failure 401 {object} jsonrpcx.ErrorResponse "Authentication required"
+// @Failure 404 {object} jsonrpcx.ErrorResponse "The clock hasn't started yet"
+// @Failure 500 {object} jsonrpcx.ErrorResponse "Internal server error"
+// @Security BearerAuth
+// @Router /api/v1/world.GetTime [post]
+func (h *WorldHandler) HandleGetTime(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+		return
+	}
+
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+
+	reading, err := h.clock.Now(r.Context())
+	if err != nil {
+		jsonrpcx.WithDomainError(r, req.ID, err, "Failed to get world time")
+		return
+	}
+
+	jsonrpcx.Success(w, req.ID, reading)
+}
+
 // === AutoRouter Compatible Methods ===
 // These methods are designed to work with the autorouter package
 
@@ -74,3 +225,18 @@ func (h *WorldHandler) HandleGet(w http.ResponseWriter, r *http.Request) {
 func (h *WorldHandler) Get(w http.ResponseWriter, r *http.Request) {
 	h.HandleGet(w, r)
 }
+
+// GetChunk handles fetching a world chunk (autorouter compatible)
+func (h *WorldHandler) GetChunk(w http.ResponseWriter, r *http.Request) {
+	h.HandleGetChunk(w, r)
+}
+
+// SubscribeChunks handles subscribing to world chunks (autorouter compatible)
+func (h *WorldHandler) SubscribeChunks(w http.ResponseWriter, r *http.Request) {
+	h.HandleSubscribeChunks(w, r)
+}
+
+// GetTime handles reading the game time and weather (autorouter compatible)
+func (h *WorldHandler) GetTime(w http.ResponseWriter, r *http.Request) {
+	h.HandleGetTime(w, r)
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	// sharedConsumerGroup is the consumer group the SSE event handlers read in, each event
+	// handled once across the cluster
+	sharedConsumerGroup = "game-servers"
+
+	// healthStream is the event stream readiness checks this instance consumes in the shared
+	// group; every instance subscribes to it
+	healthStream = "game-events.TrainerMovedEvent"
+
+	// healthCheckTimeout bounds each dependency check of a readiness probe
+	healthCheckTimeout = 2 * time.Second
+
+	// maxBroadcastAge is how far behind the movement broadcaster may fall before the
+	// instance stops being ready; it broadcasts 60 times a second when keeping up
+	maxBroadcastAge = time.Second
+)
+
+// Statuses of a dependency check
+const (
+	checkUp       = "up"
+	checkDown     = "down"
+	checkDegraded = "degraded" // Unreachable, but served around in degraded mode
+)
+
+// dependencyCheck is the outcome of checking one dependency
+type dependencyCheck struct {
+	Status    string  `json:"status"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+	Detail    any     `json:"detail,omitempty"`
+}
+
+// healthReport is the body of the health endpoints
+type healthReport struct {
+	Status   string                     `json:"status"`
+	Checks   map[string]dependencyCheck `json:"checks,omitempty"`
+	Degraded any                        `json:"degraded,omitempty"`
+}
+
+// livenessHandler tells orchestrators whether the process should be restarted. It checks no
+// dependency: an instance that can't reach Redis is not ready, but restarting it won't help.
+func (s *Server) livenessHandler(w http.ResponseWriter, r *http.Request) {
+	writeHealth(w, http.StatusOK, healthReport{Status: "alive"})
+}
+
+// readinessProbeHandler tells orchestrators whether the instance can serve players, checking
+// warm-up, Redis, the Watermill router, this instance's consumer in the shared group and the
+// movement broadcaster, with the latency of each. While Redis is down the instance is ready
+// but degraded, since it keeps serving.
+func (s *Server) readinessProbeHandler(w http.ResponseWriter, r *http.Request) {
+	degradation := s.degradation.Status()
+
+	checks := map[string]dependencyCheck{
+		"warmup":   s.checkWarmup(),
+		"router":   s.checkRouter(),
+		"movement": s.checkMovement(),
+	}
+	if degradation.Degraded {
+		checks["redis"] = dependencyCheck{Status: checkDegraded}
+		checks["consumer_group"] = dependencyCheck{Status: checkDegraded}
+	} else {
+		checks["redis"] = timeCheck(r.Context(), func(ctx context.Context) (any, error) {
+			return nil, s.redisClient.Ping(ctx).Err()
+		})
+		checks["consumer_group"] = timeCheck(r.Context(), s.checkConsumerGroup)
+	}
+
+	report := healthReport{Status: "ready", Checks: checks}
+	code := http.StatusOK
+	for name, check := range checks {
+		if check.Status == checkDown {
+			s.logger.WithContext(r.Context()).Warn("Readiness check failed",
+				zap.String("check", name),
+				zap.String("error", check.Error))
+			report.Status = "not_ready"
+			code = http.StatusServiceUnavailable
+		}
+	}
+	if degradation.Degraded {
+		report.Degraded = degradation
+		if code == http.StatusOK {
+			report.Status = "degraded"
+		}
+	}
+
+	writeHealth(w, code, report)
+}
+
+// checkWarmup checks warm-up finished and shutdown hasn't begun
+func (s *Server) checkWarmup() dependencyCheck {
+	if !s.ready.Load() {
+		return dependencyCheck{Status: checkDown, Error: "warming up or shutting down"}
+	}
+	return dependencyCheck{Status: checkUp}
+}
+
+// checkRouter checks the Watermill router is running its handlers
+func (s *Server) checkRouter() dependencyCheck {
+	if s.router == nil || !s.router.IsRunning() || s.router.IsClosed() {
+		return dependencyCheck{Status: checkDown, Error: "router is not running"}
+	}
+	return dependencyCheck{Status: checkUp}
+}
+
+// checkMovement checks the movement broadcaster is ticking and publishing keeps up with it
+func (s *Server) checkMovement() dependencyCheck {
+	age := s.movementBroadcaster.MaxBroadcastAge()
+	check := dependencyCheck{
+		Status: checkUp,
+		Detail: map[string]float64{"broadcast_age_ms": milliseconds(age)},
+	}
+	if age > maxBroadcastAge {
+		check.Status = checkDown
+		check.Error = fmt.Sprintf("positions broadcast %s ago", age.Round(time.Millisecond))
+	}
+	return check
+}
+
+// checkConsumerGroup checks this instance is a consumer of the shared group, returning the
+// messages it has pending
+func (s *Server) checkConsumerGroup(ctx context.Context) (any, error) {
+	consumers, err := s.redisClient.XInfoConsumers(ctx, healthStream, sharedConsumerGroup).Result()
+	if err != nil {
+		return nil, err
+	}
+	index := slices.IndexFunc(consumers, func(c redis.XInfoConsumer) bool { return c.Name == s.consumerName })
+	if index < 0 {
+		return nil, fmt.Errorf("consumer %s is not attached to group %s", s.consumerName, sharedConsumerGroup)
+	}
+	return map[string]int64{"pending": consumers[index].Pending}, nil
+}
+
+// timeCheck runs a dependency check within healthCheckTimeout, timing it
+func timeCheck(ctx context.Context, check func(ctx context.Context) (any, error)) dependencyCheck {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check(ctx)
+	result := dependencyCheck{Status: checkUp, LatencyMs: milliseconds(time.Since(start)), Detail: detail}
+	if err != nil {
+		result.Status = checkDown
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// milliseconds converts a duration to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// writeHealth writes a health report
+func writeHealth(w http.ResponseWriter, code int, report healthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(report)
+}
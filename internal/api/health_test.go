@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeCheck(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(ctx context.Context) (any, error)
+		want  dependencyCheck
+	}{
+		{
+			name:  "up with detail",
+			check: func(ctx context.Context) (any, error) { return map[string]int64{"pending": 2}, nil },
+			want:  dependencyCheck{Status: checkUp, Detail: map[string]int64{"pending": 2}},
+		},
+		{
+			name:  "down with the error",
+			check: func(ctx context.Context) (any, error) { return nil, errors.New("connection refused") },
+			want:  dependencyCheck{Status: checkDown, Error: "connection refused"},
+		},
+		{
+			name: "bounded by the check timeout",
+			check: func(ctx context.Context) (any, error) {
+				_, ok := ctx.Deadline()
+				return ok, nil
+			},
+			want: dependencyCheck{Status: checkUp, Detail: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := timeCheck(context.Background(), tt.check)
+			assert.GreaterOrEqual(t, got.LatencyMs, 0.0)
+			got.LatencyMs = 0
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestServer_LocalChecks(t *testing.T) {
+	warm := &Server{}
+	warm.ready.Store(true)
+
+	tests := []struct {
+		name  string
+		check dependencyCheck
+		want  string
+	}{
+		{name: "warming up", check: (&Server{}).checkWarmup(), want: checkDown},
+		{name: "warmed up", check: warm.checkWarmup(), want: checkUp},
+		{name: "no router", check: (&Server{}).checkRouter(), want: checkDown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.check.Status)
+		})
+	}
+}
+
+func TestServer_LivenessHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	(&Server{}).livenessHandler(w, httptest.NewRequest(http.MethodGet, "/health/live", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var report healthReport
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&report))
+	assert.Equal(t, "alive", report.Status)
+	assert.Empty(t, report.Checks, "liveness checks no dependency")
+}
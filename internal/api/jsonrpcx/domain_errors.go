@@ -0,0 +1,108 @@
+package jsonrpcx
+
+import (
+	"net/http"
+
+	"github.com/samber/oops"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// DomainErrorData is the data of a JSON-RPC error caused by a domain rule. Clients react to
+// the reason, e.g. PARTY_FULL, rather than to the message, which is meant for people.
+type DomainErrorData struct {
+	Code   int    `json:"code" example:"2003"`
+	Reason string `json:"reason" example:"PARTY_FULL"`
+}
+
+// domainErrorCodes maps domain error codes to JSON-RPC codes; other domain errors are
+// answered with InvalidParams
+var domainErrorCodes = map[int]int{
+	shared.ErrCodeNotFound:               NotFound,
+	shared.ErrCodeAlreadyExists:          Conflict,
+	shared.ErrCodeInsufficientFunds:      Conflict,
+	shared.ErrCodeVersionConflict:        Conflict,
+	shared.ErrCodeInventoryFull:          Conflict,
+	shared.ErrCodePartyFull:              Conflict,
+	shared.ErrCodeAnimalNotInParty:       Conflict,
+	shared.ErrCodeAnimalAlreadyInParty:   Conflict,
+	shared.ErrCodeInsufficientItems:      Conflict,
+	shared.ErrCodeMaxLevel:               Conflict,
+	shared.ErrCodeItemNotFound:           NotFound,
+	shared.ErrCodeItemBound:              Conflict,
+	shared.ErrCodeItemOnCooldown:         Conflict,
+	shared.ErrCodeMoveThrottled:          RateLimited,
+	shared.ErrCodeMoveRejected:           Conflict,
+	shared.ErrCodeTrainerDead:            Conflict,
+	shared.ErrCodeInvalidState:           Conflict,
+	shared.ErrCodeNotCaptured:            Conflict,
+	shared.ErrCodeAlreadyFainted:         Conflict,
+	shared.ErrCodeInvalidStateTransition: Conflict,
+	shared.ErrCodeAlreadyEquipped:        Conflict,
+	shared.ErrCodeNotEquipped:            Conflict,
+	shared.ErrCodeTileNotFound:           NotFound,
+	shared.ErrCodeEntityAlreadyOnTile:    Conflict,
+	shared.ErrCodePickupNotClaimable:     Conflict,
+	shared.ErrCodePickupExpired:          Conflict,
+	shared.ErrCodeUnknownRecipe:          NotFound,
+	shared.ErrCodeCraftNotReady:          Conflict,
+	shared.ErrCodeCraftAlreadyClaimed:    Conflict,
+	shared.ErrCodeVaultFull:              Conflict,
+	shared.ErrCodeNotAtVault:             Conflict,
+	shared.ErrCodeItemNotInVault:         NotFound,
+	shared.ErrCodeAlreadyInBattle:        Conflict,
+	shared.ErrCodeNotInBattle:            Conflict,
+	shared.ErrCodeEmailNotVerified:       Forbidden,
+	shared.ErrCodeReferralAbuse:          Forbidden,
+	shared.ErrCodeCaptchaFailed:          Forbidden,
+	shared.ErrCodeFriendLimit:            Conflict,
+	shared.ErrCodeFriendRequestLimit:     Conflict,
+	shared.ErrCodeChatFlood:              RateLimited,
+	shared.ErrCodeCharacterLimit:         Conflict,
+	shared.ErrCodeUnknownChallenge:       NotFound,
+	shared.ErrCodeChallengeIncomplete:    Conflict,
+	shared.ErrCodeChallengeClaimed:       Conflict,
+	shared.ErrCodeNoAmmo:                 Conflict,
+	shared.ErrCodeReloading:              Conflict,
+	shared.ErrCodeMagazineFull:           Conflict,
+	shared.ErrCodeReserveFull:            Conflict,
+	shared.ErrCodeCoolingDown:            Conflict,
+	shared.ErrCodeRoomFull:               Conflict,
+	shared.ErrCodeAlreadyInRoom:          Conflict,
+	shared.ErrCodeNotInRoom:              Conflict,
+	shared.ErrCodeMatchInProgress:        Conflict,
+	shared.ErrCodeNotEnoughPlayers:       Conflict,
+	shared.ErrCodeNoMatch:                Conflict,
+}
+
+// MapDomainError returns the JSON-RPC code and data a domain error is answered with, and
+// false when err is not a domain error
+func MapDomainError(err error) (int, DomainErrorData, bool) {
+	code, ok := shared.DomainErrorCode(err)
+	if !ok {
+		return 0, DomainErrorData{}, false
+	}
+
+	rpcCode, ok := domainErrorCodes[code]
+	if !ok {
+		rpcCode = InvalidParams
+	}
+
+	reason := ""
+	if oopsErr, ok := oops.AsOops(err); ok {
+		reason = oopsErr.Code()
+	}
+	return rpcCode, DomainErrorData{Code: code, Reason: reason}, true
+}
+
+// WithDomainError attaches err to the request, mapping domain errors to a JSON-RPC code and
+// DomainErrorData. Other errors are internal: they are answered with InternalError and
+// internalMessage, and their own message is not exposed.
+func WithDomainError(r *http.Request, id any, err error, internalMessage string) {
+	rpcCode, data, ok := MapDomainError(err)
+	if !ok {
+		WithError(r, id, InternalError, internalMessage)
+		return
+	}
+	WithErrorData(r, id, rpcCode, err.Error(), data)
+}
@@ -0,0 +1,95 @@
+package jsonrpcx
+
+import (
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestWithDomainError(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		code    int
+		message string
+		data    any
+	}{
+		{
+			name:    "conflict",
+			err:     shared.NewDomainError(shared.ErrCodePartyFull, "Animal party is full"),
+			code:    Conflict,
+			message: "Animal party is full",
+			data:    DomainErrorData{Code: shared.ErrCodePartyFull, Reason: "PARTY_FULL"},
+		},
+		{
+			name:    "full inventory is a conflict",
+			err:     shared.NewDomainError(shared.ErrCodeInventoryFull, "Inventory is full"),
+			code:    Conflict,
+			message: "Inventory is full",
+			data:    DomainErrorData{Code: shared.ErrCodeInventoryFull, Reason: "INVENTORY_FULL"},
+		},
+		{
+			name:    "flooding is rate limited",
+			err:     shared.NewDomainError(shared.ErrCodeChatFlood, "Slow down"),
+			code:    RateLimited,
+			message: "Slow down",
+			data:    DomainErrorData{Code: shared.ErrCodeChatFlood, Reason: "CHAT_FLOOD"},
+		},
+		{
+			name:    "not found",
+			err:     shared.ErrNotFound("Animal"),
+			code:    NotFound,
+			message: "Animal not found",
+			data:    DomainErrorData{Code: shared.ErrCodeNotFound, Reason: "NOT_FOUND"},
+		},
+		{
+			name:    "wrapped domain errors keep their code",
+			err:     fmt.Errorf("sell: %w", shared.NewDomainError(shared.ErrCodeInvalidNickname, "Nickname is taken")),
+			code:    InvalidParams,
+			message: "sell: Nickname is taken",
+			data:    DomainErrorData{Code: shared.ErrCodeInvalidNickname, Reason: "INVALID_NICKNAME"},
+		},
+		{
+			name:    "other domain errors are invalid params",
+			err:     shared.ErrInvalidInput("Cannot swap an animal with itself"),
+			code:    InvalidParams,
+			message: "Cannot swap an animal with itself",
+			data:    DomainErrorData{Code: shared.ErrCodeInvalidInput, Reason: "INVALID_INPUT"},
+		},
+		{
+			name:    "internal errors are hidden",
+			err:     errors.New("redis: connection refused"),
+			code:    InternalError,
+			message: "Failed to update party",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/api/v1/trainer.Party.Add", nil)
+			WithDomainError(r, 1, tt.err, "Failed to update party")
+
+			response := AttachedError(r)
+			require.NotNil(t, response)
+			assert.Equal(t, tt.code, response.Error.Code)
+			assert.Equal(t, tt.message, response.Error.Message)
+			assert.Equal(t, tt.data, response.Error.Data)
+		})
+	}
+}
+
+func TestMapDomainError(t *testing.T) {
+	_, _, ok := MapDomainError(errors.New("redis: connection refused"))
+	assert.False(t, ok)
+
+	code, data, ok := MapDomainError(shared.NewDomainError(shared.ErrCodeMatchInProgress, "A match is already running in this room"))
+	require.True(t, ok)
+	assert.Equal(t, Conflict, code)
+	assert.Equal(t, DomainErrorData{Code: shared.ErrCodeMatchInProgress, Reason: "MATCH_IN_PROGRESS"}, data)
+}
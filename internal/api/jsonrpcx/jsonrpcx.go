@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
+
+	"github.com/danghamo/life/pkg/jsonx"
 )
 
+// Hot types get generated marshalers in builds tagged easyjson; see pkg/jsonx
+//go:generate go run github.com/mailru/easyjson/easyjson -build_tags easyjson -no_std_marshalers jsonrpcx.go
+
 // RequestT represents a typed JSON-RPC 2.0 request
 type RequestT[T any] struct {
 	JSONRPC string `json:"jsonrpc" example:"2.0"`
@@ -24,13 +29,26 @@ type ResponseT[T any] struct {
 }
 
 // JsonRpcNotification represents a JSON-RPC 2.0 notification (no ID, no response expected)
+//
+//easyjson:json
 type JsonRpcNotification struct {
 	Jsonrpc string      `json:"jsonrpc"`
 	Method  string      `json:"method"`
 	Params  interface{} `json:"params,omitempty"`
+
+	// RequestID is the ID of the request whose action the notification reports, for clients
+	// to match notifications to their calls; empty for notifications no request set off.
+	// Not a JSON-RPC member; clients that do not know it ignore it.
+	RequestID string `json:"request_id,omitempty"`
+
+	// EventID numbers the notification among those sent to its recipient, for SSE clients
+	// to resume from; zero when it isn't numbered. Not part of the JSON-RPC message.
+	EventID uint64 `json:"-"`
 }
 
 // Request represents a JSON-RPC 2.0 request
+//
+//easyjson:json
 type Request struct {
 	JSONRPC string          `json:"jsonrpc" example:"2.0"`
 	Method  string          `json:"method"`
@@ -39,6 +57,8 @@ type Request struct {
 }
 
 // Response represents a JSON-RPC 2.0 response
+//
+//easyjson:json
 type Response struct {
 	JSONRPC string        `json:"jsonrpc" example:"2.0"`
 	Result  any           `json:"result,omitempty"`
@@ -67,8 +87,57 @@ const (
 	MethodNotFound = -32601
 	InvalidParams  = -32602
 	InternalError  = -32603
+
+	// Timeout is a server error: the request ran past its deadline
+	Timeout = -32001
+	// RateLimited is a server error: the caller sent too many requests; see Retry-After
+	RateLimited = -32002
+	// Degraded is a server error: the method needs storage that is currently unreachable
+	Degraded = -32003
+	// NotFound is a server error: the resource does not exist or belongs to someone else
+	NotFound = -32004
+	// Conflict is a server error: the request does not fit the resource's current state
+	Conflict = -32005
+	// Forbidden is a server error: the caller is authenticated but may not use the method
+	Forbidden = -32006
 )
 
+// errorSlotKey is the context key of the error slot shared by every copy of a request
+type errorSlotKey struct{}
+
+// errorSlot holds the error a handler attached to its request
+type errorSlot struct {
+	response *Response
+}
+
+// WithErrorSlot returns a request whose copies, such as those middleware make with
+// WithContext, all report errors attached by WithError or SetError to the returned function
+func WithErrorSlot(r *http.Request) (*http.Request, func() *Response) {
+	slot := &errorSlot{}
+	ctx := context.WithValue(r.Context(), errorSlotKey{}, slot)
+	return r.WithContext(ctx), func() *Response {
+		return slot.response
+	}
+}
+
+// recordError stores an error in the request's error slot, if it has one
+func recordError(ctx context.Context, response *Response) {
+	if slot, ok := ctx.Value(errorSlotKey{}).(*errorSlot); ok {
+		slot.response = response
+	}
+}
+
+// AttachedError returns the error attached to a request with WithError or SetError, if any
+func AttachedError(r *http.Request) *Response {
+	if slot, ok := r.Context().Value(errorSlotKey{}).(*errorSlot); ok && slot.response != nil {
+		return slot.response
+	}
+	if response, ok := r.Context().Value("jsonrpc_error").(*Response); ok {
+		return response
+	}
+	return nil
+}
+
 // ParseRequest parses JSON-RPC 2.0 request from HTTP request body
 func ParseRequest(r *http.Request) (*Request, error) {
 	body, err := io.ReadAll(r.Body)
@@ -78,7 +147,7 @@ func ParseRequest(r *http.Request) (*Request, error) {
 	defer r.Body.Close()
 
 	var req Request
-	if err := json.Unmarshal(body, &req); err != nil {
+	if err := jsonx.Unmarshal(body, &req); err != nil {
 		return nil, err
 	}
 
@@ -103,16 +172,23 @@ func Success(w http.ResponseWriter, id any, result any) {
 
 // WithError attaches an error to the request context for middleware processing
 func WithError(r *http.Request, id any, code int, message string) {
+	WithErrorData(r, id, code, message, nil)
+}
+
+// WithErrorData attaches an error carrying additional data to the request context
+func WithErrorData(r *http.Request, id any, code int, message string, data any) {
 	response := &Response{
 		JSONRPC: "2.0",
 		Error: &JSONRPCError{
 			Code:    code,
 			Message: message,
+			Data:    data,
 		},
 		ID: id,
 	}
 
 	// Store the JSON-RPC response in request context and overwrite the request pointer
+	recordError(r.Context(), response)
 	ctx := context.WithValue(r.Context(), "jsonrpc_error", response)
 	*r = *r.WithContext(ctx)
 }
@@ -155,6 +231,7 @@ func SetError(r *http.Request, id any, code int, message string) *http.Request {
 		ID: id,
 	}
 
+	recordError(r.Context(), response)
 	ctx := context.WithValue(r.Context(), "jsonrpc_error", response)
 	return r.WithContext(ctx)
 }
@@ -165,5 +242,5 @@ func Write(w http.ResponseWriter, response Response) {
 	w.WriteHeader(http.StatusOK) // JSON-RPC always returns HTTP 200
 
 	// Encode response - if error occurs, it will be logged by middleware
-	json.NewEncoder(w).Encode(response)
+	jsonx.Encode(w, response)
 }
@@ -0,0 +1,73 @@
+//go:build easyjson
+
+package jsonrpcx
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/danghamo/life/pkg/jsonx"
+)
+
+func TestEnvelopes_EasyJSONMatchesStdlib(t *testing.T) {
+	envelopes := []any{
+		&Request{JSONRPC: "2.0", Method: "trainer.Move", Params: json.RawMessage(`{"action":"start","direction_x":1}`), ID: float64(42)},
+		&Request{JSONRPC: "2.0", Method: "trainer.Get", ID: "abc"},
+		&Response{JSONRPC: "2.0", Result: map[string]any{"ok": true, "count": 3}, ID: float64(1)},
+		&Response{JSONRPC: "2.0", Error: &JSONRPCError{Code: InvalidParams, Message: "bad <params> & more"}, ID: "x"},
+		&JsonRpcNotification{Jsonrpc: "2.0", Method: "inventory.changed", Params: map[string]any{"used_slots": 4}},
+		&JsonRpcNotification{Jsonrpc: "2.0", Method: "ping"},
+	}
+
+	for _, v := range envelopes {
+		if !jsonx.Generated(v) {
+			t.Fatalf("%T has no generated marshaler", v)
+		}
+
+		want, err := json.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := jsonx.Marshal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertJSONEq(t, want, got)
+	}
+}
+
+func TestRequest_EasyJSONDecodeMatchesStdlib(t *testing.T) {
+	body := []byte(`{"jsonrpc":"2.0","method":"trainer.Move","params":{"action":"start","direction_x":1},"id":42}`)
+
+	var want, got Request
+	if err := json.Unmarshal(body, &want); err != nil {
+		t.Fatal(err)
+	}
+	if err := jsonx.Unmarshal(body, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.JSONRPC != want.JSONRPC || got.Method != want.Method || got.ID != want.ID {
+		t.Fatalf("decoded %+v, stdlib decoded %+v", got, want)
+	}
+	assertJSONEq(t, want.Params, got.Params)
+}
+
+// assertJSONEq compares two documents ignoring object key order, which differs for maps
+func assertJSONEq(t *testing.T, want, got []byte) {
+	t.Helper()
+
+	var w, g any
+	if err := json.Unmarshal(want, &w); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+
+	wantNorm, _ := json.Marshal(w)
+	gotNorm, _ := json.Marshal(g)
+	if string(wantNorm) != string(gotNorm) {
+		t.Fatalf("got %s, want %s", got, want)
+	}
+}
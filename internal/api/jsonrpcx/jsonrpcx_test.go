@@ -0,0 +1,20 @@
+package jsonrpcx
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func BenchmarkParseRequest(b *testing.B) {
+	body := `{"jsonrpc":"2.0","method":"trainer.Move","params":{"action":"start","direction_x":1,"direction_y":0},"id":42}`
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/trainer.Move", strings.NewReader(body))
+		if _, err := ParseRequest(r); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
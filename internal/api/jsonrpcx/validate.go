@@ -0,0 +1,286 @@
+package jsonrpcx
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Violation is a param that broke a validation rule
+type Violation struct {
+	Field   string `json:"field" example:"items[0].quantity"` // JSON path of the param
+	Rule    string `json:"rule" example:"min"`                // required, min, max, oneof or type
+	Message string `json:"message" example:"must be at least 1"`
+}
+
+// ValidationErrorData is the data of an InvalidParams error, listing every violation
+type ValidationErrorData struct {
+	Violations []Violation `json:"violations"`
+}
+
+// BindParams unmarshals the request's params into v, a pointer to a struct, and checks them
+// against the validate tags of its fields. When the params are missing, malformed or break a
+// rule, the request is answered with InvalidParams listing every violation and BindParams
+// returns false.
+//
+// Tags list comma separated rules:
+//
+//	required     the param must be set to a non-zero value
+//	omitempty    the other rules are skipped when the param is zero
+//	min=n, max=n bounds numbers, and the length of strings, arrays and objects
+//	oneof=a b c  the param must be one of the space separated values
+//
+// Nested structs, and the structs in arrays, are checked too.
+func BindParams(r *http.Request, req *Request, v any) bool {
+	var violations []Violation
+	if len(req.Params) == 0 {
+		violations = []Violation{{Field: "params", Rule: "required", Message: "is required"}}
+	} else if err := json.Unmarshal(req.Params, v); err != nil {
+		violations = []Violation{unmarshalViolation(err)}
+	} else {
+		violations = Validate(v)
+	}
+
+	if len(violations) > 0 {
+		WithErrorData(r, req.ID, InvalidParams, "Invalid params", ValidationErrorData{Violations: violations})
+		return false
+	}
+	return true
+}
+
+// Validate checks a struct, or a pointer to one, against the validate tags of its fields and
+// returns the violations, nil when every rule holds
+func Validate(v any) []Violation {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var violations []Violation
+	validateStruct(value, "", &violations)
+	return violations
+}
+
+// unmarshalViolation describes why params couldn't be unmarshaled
+func unmarshalViolation(err error) Violation {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		field := typeErr.Field
+		if field == "" {
+			field = "params"
+		}
+		return Violation{Field: field, Rule: "type", Message: "must be " + jsonTypeName(typeErr.Type)}
+	}
+	return Violation{Field: "params", Rule: "type", Message: "must be a JSON object"}
+}
+
+// jsonTypeName names the JSON type a Go type is unmarshaled from
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	default:
+		return "an object"
+	}
+}
+
+// fieldRules are the validation rules of a struct field
+type fieldRules struct {
+	index     []int
+	name      string // JSON name
+	required  bool
+	omitempty bool
+	min, max  *float64
+	oneof     []string
+}
+
+// structRules caches the rules of each struct type, compiled from its tags on first use
+var structRules sync.Map // reflect.Type -> []fieldRules
+
+// rulesOf returns the rules of a struct type's fields
+func rulesOf(t reflect.Type) []fieldRules {
+	if cached, ok := structRules.Load(t); ok {
+		return cached.([]fieldRules)
+	}
+	rules := compileRules(t)
+	structRules.Store(t, rules)
+	return rules
+}
+
+// compileRules reads the rules of a struct type's exported fields from their tags. Fields of
+// embedded structs are promoted, as they are in JSON.
+func compileRules(t reflect.Type) []fieldRules {
+	var rules []fieldRules
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		rule := fieldRules{index: field.Index, name: name}
+		for _, part := range strings.Split(field.Tag.Get("validate"), ",") {
+			key, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+			switch key {
+			case "":
+			case "required":
+				rule.required = true
+			case "omitempty":
+				rule.omitempty = true
+			case "min", "max":
+				bound, err := strconv.ParseFloat(arg, 64)
+				if err != nil {
+					panic(fmt.Sprintf("jsonrpcx: invalid %s bound %q on %s.%s", key, arg, t.Name(), field.Name))
+				}
+				if key == "min" {
+					rule.min = &bound
+				} else {
+					rule.max = &bound
+				}
+			case "oneof":
+				rule.oneof = strings.Fields(arg)
+			default:
+				panic(fmt.Sprintf("jsonrpcx: unknown validation rule %q on %s.%s", key, t.Name(), field.Name))
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// validateStruct checks the fields of a struct value, naming them under prefix
+func validateStruct(value reflect.Value, prefix string, violations *[]Violation) {
+	for _, rule := range rulesOf(value.Type()) {
+		field, err := value.FieldByIndexErr(rule.index)
+		if err != nil {
+			continue // Behind a nil embedded pointer
+		}
+		validateField(field, rule, prefix+rule.name, violations)
+	}
+}
+
+// validateField checks a field against its rules, then the structs it holds
+func validateField(field reflect.Value, rule fieldRules, path string, violations *[]Violation) {
+	for field.Kind() == reflect.Pointer || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			if rule.required {
+				*violations = append(*violations, Violation{Field: path, Rule: "required", Message: "is required"})
+			}
+			return
+		}
+		field = field.Elem()
+	}
+
+	if field.IsZero() {
+		if rule.required {
+			*violations = append(*violations, Violation{Field: path, Rule: "required", Message: "is required"})
+			return
+		}
+		if rule.omitempty {
+			return
+		}
+	}
+
+	if size, unit, ok := sizeOf(field); ok {
+		if rule.min != nil && size < *rule.min {
+			*violations = append(*violations, Violation{Field: path, Rule: "min", Message: "must be at least " + formatBound(*rule.min, unit)})
+		}
+		if rule.max != nil && size > *rule.max {
+			*violations = append(*violations, Violation{Field: path, Rule: "max", Message: "must be at most " + formatBound(*rule.max, unit)})
+		}
+	}
+
+	if len(rule.oneof) > 0 {
+		current := fmt.Sprint(field.Interface())
+		allowed := false
+		for _, option := range rule.oneof {
+			if current == option {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			*violations = append(*violations, Violation{Field: path, Rule: "oneof", Message: "must be one of " + strings.Join(rule.oneof, ", ")})
+		}
+	}
+
+	switch field.Kind() {
+	case reflect.Struct:
+		validateStruct(field, path+".", violations)
+	case reflect.Slice, reflect.Array:
+		elementType := field.Type().Elem()
+		for elementType.Kind() == reflect.Pointer {
+			elementType = elementType.Elem()
+		}
+		if elementType.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < field.Len(); i++ {
+			element := field.Index(i)
+			for element.Kind() == reflect.Pointer && !element.IsNil() {
+				element = element.Elem()
+			}
+			if element.Kind() == reflect.Struct {
+				validateStruct(element, fmt.Sprintf("%s[%d].", path, i), violations)
+			}
+		}
+	}
+}
+
+// sizeOf returns what min and max bound for a value: numbers themselves, the characters of
+// strings and the elements of arrays and objects
+func sizeOf(value reflect.Value) (float64, string, bool) {
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(value.Int()), "", true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(value.Uint()), "", true
+	case reflect.Float32, reflect.Float64:
+		return value.Float(), "", true
+	case reflect.String:
+		return float64(utf8.RuneCountInString(value.String())), "character", true
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(value.Len()), "item", true
+	default:
+		return 0, "", false
+	}
+}
+
+// formatBound formats a rule's bound without a needless fraction, followed by its unit
+func formatBound(bound float64, unit string) string {
+	formatted := strconv.FormatFloat(bound, 'f', -1, 64)
+	switch {
+	case unit == "":
+		return formatted
+	case bound == 1:
+		return formatted + " " + unit
+	default:
+		return formatted + " " + unit + "s"
+	}
+}
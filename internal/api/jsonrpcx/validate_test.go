@@ -0,0 +1,71 @@
+package jsonrpcx
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testItem struct {
+	Name     string `json:"name" validate:"required"`
+	Quantity int    `json:"quantity" validate:"min=1,max=99"`
+}
+
+type testParams struct {
+	Mode     string     `json:"mode" validate:"required,oneof=deathmatch team"`
+	Capacity int        `json:"capacity,omitempty" validate:"omitempty,min=2,max=16"`
+	Limit    *int       `json:"limit,omitempty" validate:"min=0,max=50"`
+	Nickname string     `json:"nickname" validate:"max=4"`
+	Items    []testItem `json:"items" validate:"max=2"`
+}
+
+func TestValidate(t *testing.T) {
+	limit := 60
+	violations := Validate(&testParams{
+		Capacity: 20,
+		Limit:    &limit,
+		Nickname: "길동이에요",
+		Items:    []testItem{{Name: "potion", Quantity: 1}, {Quantity: 0}},
+	})
+
+	assert.Equal(t, []Violation{
+		{Field: "mode", Rule: "required", Message: "is required"},
+		{Field: "capacity", Rule: "max", Message: "must be at most 16"},
+		{Field: "limit", Rule: "max", Message: "must be at most 50"},
+		{Field: "nickname", Rule: "max", Message: "must be at most 4 characters"},
+		{Field: "items[1].name", Rule: "required", Message: "is required"},
+		{Field: "items[1].quantity", Rule: "min", Message: "must be at least 1"},
+	}, violations)
+
+	assert.Empty(t, Validate(&testParams{Mode: "team"}), "zero values skip omitempty and unset pointers")
+	assert.Equal(t, "oneof", Validate(&testParams{Mode: "capture"})[0].Rule)
+}
+
+func TestBindParams(t *testing.T) {
+	bind := func(params string) (*Response, bool) {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/room.Create", nil)
+		r, attached := WithErrorSlot(r)
+		var p testParams
+		ok := BindParams(r, &Request{JSONRPC: "2.0", Params: json.RawMessage(params), ID: 1}, &p)
+		return attached(), ok
+	}
+
+	_, ok := bind(`{"mode":"team","capacity":4}`)
+	assert.True(t, ok)
+
+	response, ok := bind(`{"mode":"team","capacity":"four"}`)
+	assert.False(t, ok)
+	require.NotNil(t, response)
+	assert.Equal(t, InvalidParams, response.Error.Code)
+	assert.Equal(t, ValidationErrorData{Violations: []Violation{
+		{Field: "capacity", Rule: "type", Message: "must be an integer"},
+	}}, response.Error.Data)
+
+	response, ok = bind(``)
+	assert.False(t, ok)
+	assert.Equal(t, "params", response.Error.Data.(ValidationErrorData).Violations[0].Field)
+}
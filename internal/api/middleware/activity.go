@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danghamo/life/internal/domain/idle"
+)
+
+// ActivityRecorder is told when signed-in players do something
+type ActivityRecorder interface {
+	Active(ctx context.Context, userID string)
+}
+
+// Activity tells recorder about the requests of signed-in players that are their input, so
+// players who stop playing can be told apart from ones who are there. It must run after
+// authentication.
+func Activity(recorder ActivityRecorder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if method, ok := rpcMethod(r.URL.Path); ok && idle.IsInput(method) {
+				if userID, ok := GetUserID(r.Context()); ok {
+					recorder.Active(r.Context(), userID)
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"strings"
 
@@ -9,42 +10,123 @@ import (
 
 	"github.com/danghamo/life/internal/api/jsonrpcx"
 	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/trainer"
 	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/tenant"
 )
 
 // UserContextKey is the key for storing user info in request context
 type UserContextKey string
 
 const (
-	// UserIDContextKey stores the user ID in context
+	// UserIDContextKey stores the ID of the trainer the user plays in context, which is the
+	// user's own ID unless they selected another character
 	UserIDContextKey UserContextKey = "user_id"
 	// UserEmailContextKey stores the user email in context  
 	UserEmailContextKey UserContextKey = "user_email"
 	// UserNameContextKey stores the user name in context  
 	UserNameContextKey UserContextKey = "user_name"
+	// UserRoleContextKey stores the token's role in context
+	UserRoleContextKey UserContextKey = "user_role"
+	// SessionIDContextKey stores the session the token was issued for in context
+	SessionIDContextKey UserContextKey = "session_id"
 )
 
 // AuthMiddleware provides JWT authentication middleware
 type AuthMiddleware struct {
-	jwtService *account.JWTService
-	logger     *logger.Logger
+	jwtService  *account.JWTService
+	revocations account.RevocationRepository
+	sessions    account.SessionRepository
+	degradation *Degradation
+	tenants     *tenant.Registry
+	logger      *logger.Logger
 }
 
-// NewAuthMiddleware creates a new auth middleware
-func NewAuthMiddleware(jwtService *account.JWTService, logger *logger.Logger) *AuthMiddleware {
+// NewAuthMiddleware creates a new auth middleware accepting tokens while their session is in
+// sessions. While degradation reports Redis down, tokens are accepted on their signature alone.
+func NewAuthMiddleware(jwtService *account.JWTService, revocations account.RevocationRepository, sessions account.SessionRepository, degradation *Degradation, tenants *tenant.Registry, logger *logger.Logger) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtService: jwtService,
-		logger:     logger.WithComponent("auth-middleware"),
+		jwtService:  jwtService,
+		revocations: revocations,
+		sessions:    sessions,
+		degradation: degradation,
+		tenants:     tenants,
+		logger:      logger.WithComponent("auth-middleware"),
 	}
 }
 
+// validateToken validates a JWT token and rejects it if the user's tokens were revoked
+// after it was issued, e.g. because the account was deleted, or its session was revoked. The
+// returned context is scoped to the token's tenant.
+func (m *AuthMiddleware) validateToken(ctx context.Context, tokenString string) (context.Context, *account.JWTClaims, error) {
+	claims, err := m.jwtService.ValidateToken(tokenString)
+	if err != nil {
+		return nil, nil, err
+	}
+	if trainer.UserID(claims.PlayerID()).AccountID().String() != claims.UserID {
+		return nil, nil, fmt.Errorf("character %q is not one of the user's", claims.CharacterID)
+	}
+
+	ctx, err = m.scopeTenant(ctx, claims)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Revocations live in Redis; don't lock every player out while it is unreachable
+	if m.degradation != nil && m.degradation.Degraded() {
+		return ctx, claims, nil
+	}
+
+	revokedAt, err := m.revocations.RevokedAt(ctx, account.UserID(claims.UserID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	if account.IsRevoked(claims, revokedAt) {
+		return nil, nil, fmt.Errorf("token was revoked")
+	}
+
+	// Tokens issued before sessions were registered carry none
+	if claims.SessionID != "" {
+		active, err := m.sessions.Exists(ctx, account.UserID(claims.UserID), claims.SessionID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to check session: %w", err)
+		}
+		if !active {
+			return nil, nil, fmt.Errorf("session was revoked")
+		}
+	}
+
+	return ctx, claims, nil
+}
+
+// scopeTenant checks that a token was issued by the tenant whose host the request came to.
+// Requests on hosts of no tenant are scoped to the token's tenant.
+func (m *AuthMiddleware) scopeTenant(ctx context.Context, claims *account.JWTClaims) (context.Context, error) {
+	issuer := tenant.ID(claims.TenantID)
+	if current, ok := tenant.FromContext(ctx); ok {
+		if current.ID != issuer {
+			return nil, fmt.Errorf("token of tenant %q used for tenant %q", issuer, current.ID)
+		}
+		return ctx, nil
+	}
+
+	t, ok := m.tenants.Get(issuer)
+	if !ok {
+		if issuer == tenant.Default {
+			return ctx, nil
+		}
+		return nil, fmt.Errorf("token of unknown tenant %q", issuer)
+	}
+	return tenant.WithTenant(ctx, t), nil
+}
+
 // RequireAuth returns a middleware that requires JWT authentication
 func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Extract token from Authorization header
 		authHeader := r.Header.Get("Authorization")
 		if authHeader == "" {
-			m.logger.Debug("Missing Authorization header")
+			m.logger.WithContext(r.Context()).Debug("Missing Authorization header")
 			jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "Missing Authorization header")
 			return
 		}
@@ -52,7 +134,7 @@ func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 		// Check Bearer token format
 		parts := strings.SplitN(authHeader, " ", 2)
 		if len(parts) != 2 || parts[0] != "Bearer" {
-			m.logger.Debug("Invalid Authorization header format")
+			m.logger.WithContext(r.Context()).Debug("Invalid Authorization header format")
 			jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "Invalid Authorization header format")
 			return
 		}
@@ -60,20 +142,22 @@ func (m *AuthMiddleware) RequireAuth(next http.Handler) http.Handler {
 		tokenString := parts[1]
 
 		// Validate JWT token
-		claims, err := m.jwtService.ValidateToken(tokenString)
+		ctx, claims, err := m.validateToken(r.Context(), tokenString)
 		if err != nil {
-			m.logger.Debug("Invalid JWT token", zap.Error(err))
+			m.logger.WithContext(r.Context()).Debug("Invalid JWT token", zap.Error(err))
 			jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "Invalid or expired token")
 			return
 		}
 
 		// Add user info to request context
-		ctx := context.WithValue(r.Context(), UserIDContextKey, claims.UserID)
+		ctx = context.WithValue(ctx, UserIDContextKey, claims.PlayerID())
 		ctx = context.WithValue(ctx, UserEmailContextKey, claims.Email)
 		ctx = context.WithValue(ctx, UserNameContextKey, claims.Name)
+		ctx = context.WithValue(ctx, UserRoleContextKey, claims.Role)
+		ctx = context.WithValue(ctx, SessionIDContextKey, claims.SessionID)
 
 		// Log successful authentication
-		m.logger.Debug("JWT authentication successful", 
+		m.logger.WithContext(r.Context()).Debug("JWT authentication successful", 
 			zap.String("userId", claims.UserID),
 			zap.String("email", claims.Email),
 			zap.String("name", claims.Name))
@@ -105,20 +189,22 @@ func (m *AuthMiddleware) OptionalAuth(next http.Handler) http.Handler {
 		tokenString := parts[1]
 
 		// Validate JWT token
-		claims, err := m.jwtService.ValidateToken(tokenString)
+		ctx, claims, err := m.validateToken(r.Context(), tokenString)
 		if err != nil {
 			// Invalid token, continue without user context
-			m.logger.Debug("Optional auth failed", zap.Error(err))
+			m.logger.WithContext(r.Context()).Debug("Optional auth failed", zap.Error(err))
 			next.ServeHTTP(w, r)
 			return
 		}
 
 		// Add user info to request context
-		ctx := context.WithValue(r.Context(), UserIDContextKey, claims.UserID)
+		ctx = context.WithValue(ctx, UserIDContextKey, claims.PlayerID())
 		ctx = context.WithValue(ctx, UserEmailContextKey, claims.Email)
 		ctx = context.WithValue(ctx, UserNameContextKey, claims.Name)
+		ctx = context.WithValue(ctx, UserRoleContextKey, claims.Role)
+		ctx = context.WithValue(ctx, SessionIDContextKey, claims.SessionID)
 
-		m.logger.Debug("Optional JWT authentication successful", 
+		m.logger.WithContext(r.Context()).Debug("Optional JWT authentication successful", 
 			zap.String("userId", claims.UserID),
 			zap.String("email", claims.Email),
 			zap.String("name", claims.Name))
@@ -134,6 +220,30 @@ func GetUserID(ctx context.Context) (string, bool) {
 	return userID, ok
 }
 
+// GetAccountID extracts the ID of the user from request context. It differs from GetUserID,
+// which identifies the character played, for state the user's characters share.
+func GetAccountID(ctx context.Context) (string, bool) {
+	userID, ok := GetUserID(ctx)
+	if !ok {
+		return "", false
+	}
+	return trainer.UserID(userID).AccountID().String(), true
+}
+
+// GetSessionID extracts the session the token was issued for from request context, empty
+// for tokens issued before sessions were registered
+func GetSessionID(ctx context.Context) (string, bool) {
+	sessionID, ok := ctx.Value(SessionIDContextKey).(string)
+	return sessionID, ok
+}
+
+// UserAgent records the client of requests in their context, for the sessions they sign in
+func UserAgent(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(account.WithUserAgent(r.Context(), r.UserAgent())))
+	})
+}
+
 // GetUserEmail extracts user email from request context
 func GetUserEmail(ctx context.Context) (string, bool) {
 	email, ok := ctx.Value(UserEmailContextKey).(string)
@@ -146,6 +256,12 @@ func GetUserName(ctx context.Context) (string, bool) {
 	return name, ok
 }
 
+// GetUserRole extracts the token's role from request context
+func GetUserRole(ctx context.Context) (account.Role, bool) {
+	role, ok := ctx.Value(UserRoleContextKey).(account.Role)
+	return role, ok
+}
+
 // GetUserInfo extracts user ID, email, and name from request context
 func GetUserInfo(ctx context.Context) (userID, email, name string, ok bool) {
 	userID, hasUserID := GetUserID(ctx)
@@ -156,6 +272,21 @@ func GetUserInfo(ctx context.Context) (userID, email, name string, ok bool) {
 
 // RequireSSEAuth returns a middleware specifically for SSE endpoints that supports multiple token sources
 func (m *AuthMiddleware) RequireSSEAuth(next http.Handler) http.Handler {
+	return m.requireStreamAuth(next, true)
+}
+
+// RequireWebSocketAuth returns a middleware for the WebSocket endpoint that takes the token
+// from the Authorization header or the token query parameter only. Browsers send cookies
+// along with WebSocket handshakes from any site, and the same-origin policy doesn't stop
+// those pages from using the connection, so a cookie doesn't prove the player's own client
+// connected.
+func (m *AuthMiddleware) RequireWebSocketAuth(next http.Handler) http.Handler {
+	return m.requireStreamAuth(next, false)
+}
+
+// requireStreamAuth authenticates long-lived stream connections, which browsers can't open
+// with custom headers, also by the token query parameter and optionally the auth_token cookie
+func (m *AuthMiddleware) requireStreamAuth(next http.Handler, allowCookie bool) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		var tokenString string
 		
@@ -174,7 +305,7 @@ func (m *AuthMiddleware) RequireSSEAuth(next http.Handler) http.Handler {
 		}
 		
 		// 3. Cookie (alternative for web clients)
-		if tokenString == "" {
+		if tokenString == "" && allowCookie {
 			if cookie, err := r.Cookie("auth_token"); err == nil {
 				tokenString = cookie.Value
 			}
@@ -182,26 +313,28 @@ func (m *AuthMiddleware) RequireSSEAuth(next http.Handler) http.Handler {
 		
 		// No token found
 		if tokenString == "" {
-			m.logger.Debug("No authentication token found in SSE request")
+			m.logger.WithContext(r.Context()).Debug("No authentication token found in SSE request")
 			http.Error(w, "Unauthorized: Authentication required", http.StatusUnauthorized)
 			return
 		}
 		
 		// Validate JWT token
-		claims, err := m.jwtService.ValidateToken(tokenString)
+		ctx, claims, err := m.validateToken(r.Context(), tokenString)
 		if err != nil {
-			m.logger.Debug("Invalid JWT token in SSE request", zap.Error(err))
+			m.logger.WithContext(r.Context()).Debug("Invalid JWT token in SSE request", zap.Error(err))
 			http.Error(w, "Unauthorized: Invalid or expired token", http.StatusUnauthorized)
 			return
 		}
 		
 		// Add user info to request context
-		ctx := context.WithValue(r.Context(), UserIDContextKey, claims.UserID)
+		ctx = context.WithValue(ctx, UserIDContextKey, claims.PlayerID())
 		ctx = context.WithValue(ctx, UserEmailContextKey, claims.Email)
 		ctx = context.WithValue(ctx, UserNameContextKey, claims.Name)
+		ctx = context.WithValue(ctx, UserRoleContextKey, claims.Role)
+		ctx = context.WithValue(ctx, SessionIDContextKey, claims.SessionID)
 		
 		// Log successful authentication
-		m.logger.Debug("SSE JWT authentication successful", 
+		m.logger.WithContext(r.Context()).Debug("SSE JWT authentication successful", 
 			zap.String("userId", claims.UserID),
 			zap.String("email", claims.Email),
 			zap.String("name", claims.Name))
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// noRevocations never revokes tokens
+type noRevocations struct {
+	account.RevocationRepository
+}
+
+func (noRevocations) RevokedAt(ctx context.Context, userID account.UserID) (time.Time, error) {
+	return time.Time{}, nil
+}
+
+func TestAuthMiddleware_StreamTokenSources(t *testing.T) {
+	jwtService := account.NewJWTService("secret", "life", time.Hour)
+	auth := NewAuthMiddleware(jwtService, noRevocations{}, nil, nil, nil, logger.NewDefault())
+	token, err := jwtService.GenerateCharacterToken(context.Background(), "player-1", "", "", "", "")
+	require.NoError(t, err)
+
+	connect := func(middleware func(http.Handler) http.Handler, authenticate func(r *http.Request)) int {
+		handler := middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			userID, ok := GetUserID(r.Context())
+			assert.True(t, ok)
+			assert.Equal(t, "player-1", userID)
+		}))
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/ws", nil)
+		authenticate(r)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+	bearer := func(r *http.Request) { r.Header.Set("Authorization", "Bearer "+token) }
+	query := func(r *http.Request) { r.URL.RawQuery = "token=" + token }
+	cookie := func(r *http.Request) { r.AddCookie(&http.Cookie{Name: "auth_token", Value: token}) }
+
+	assert.Equal(t, http.StatusOK, connect(auth.RequireSSEAuth, bearer))
+	assert.Equal(t, http.StatusOK, connect(auth.RequireSSEAuth, query))
+	assert.Equal(t, http.StatusOK, connect(auth.RequireSSEAuth, cookie))
+
+	assert.Equal(t, http.StatusOK, connect(auth.RequireWebSocketAuth, bearer))
+	assert.Equal(t, http.StatusOK, connect(auth.RequireWebSocketAuth, query))
+	assert.Equal(t, http.StatusUnauthorized, connect(auth.RequireWebSocketAuth, cookie),
+		"cross-site pages can make browsers send the cookie")
+}
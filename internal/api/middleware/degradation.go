@@ -0,0 +1,500 @@
+package middleware
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/wal"
+)
+
+// replayTimeout bounds each queued write replayed after recovery
+const replayTimeout = 5 * time.Second
+
+// DegradationConfig sets how the server keeps serving while Redis is unreachable
+type DegradationConfig struct {
+	Enabled          bool
+	ProbeInterval    time.Duration // How often Redis is pinged
+	FailureThreshold int           // Consecutive failed pings before degrading
+	WALPath          string        // Local file holding writes queued while degraded
+	SnapshotTTL      time.Duration // How long a read result may be served from cache
+	SnapshotLimit    int           // Most read results kept in memory
+	SnapshotMethods  []string      // Read methods whose last result is served while degraded
+}
+
+// DegradationStatus describes whether the server is running degraded
+type DegradationStatus struct {
+	Degraded     bool       `json:"degraded"`
+	Since        *time.Time `json:"since,omitempty"`
+	QueuedWrites int        `json:"queued_writes"`
+}
+
+// QueuedResult answers a write that was queued for replay instead of applied
+type QueuedResult struct {
+	Queued bool `json:"queued"`
+}
+
+// StatusBroadcaster tells connected clients when the server degrades or recovers
+type StatusBroadcaster interface {
+	BroadcastToAll(notification jsonrpcx.JsonRpcNotification)
+}
+
+// queuedWrite is a WAL record of a write received while degraded
+type queuedWrite struct {
+	Method   string          `json:"method"`
+	UserID   string          `json:"user_id"`
+	Request  json.RawMessage `json:"request"`
+	QueuedAt time.Time       `json:"queued_at"`
+}
+
+// snapshot is the last result of a read method for one user and set of params
+type snapshot struct {
+	key    string
+	result json.RawMessage
+	at     time.Time
+}
+
+// Degradation keeps the API partly available while Redis is down. Reads listed in the config
+// are served from their last result, writes registered with Handle are queued in a local WAL
+// and replayed once Redis is back, and everything else fails fast with a Degraded error.
+type Degradation struct {
+	logger      *logger.Logger
+	config      DegradationConfig
+	probe       func(ctx context.Context) error
+	broadcaster StatusBroadcaster
+	wal         *wal.Log
+	reads       map[string]bool
+	writes      map[string]http.Handler
+
+	mutex    sync.Mutex
+	degraded bool
+	since    time.Time
+	failures int
+
+	snapshotMutex sync.Mutex
+	snapshots     map[string]*list.Element
+	recent        *list.List
+
+	stopChan chan struct{}
+	ticker   *time.Ticker
+}
+
+// NewDegradation creates the degradation guard. probe reports whether Redis is reachable.
+func NewDegradation(logger *logger.Logger, config DegradationConfig, probe func(ctx context.Context) error, broadcaster StatusBroadcaster) (*Degradation, error) {
+	d := &Degradation{
+		logger:      logger.WithComponent("degradation"),
+		config:      config,
+		probe:       probe,
+		broadcaster: broadcaster,
+		reads:       make(map[string]bool, len(config.SnapshotMethods)),
+		writes:      make(map[string]http.Handler),
+		snapshots:   make(map[string]*list.Element),
+		recent:      list.New(),
+		stopChan:    make(chan struct{}),
+	}
+
+	for _, method := range config.SnapshotMethods {
+		d.reads[method] = true
+	}
+
+	if config.Enabled {
+		log, err := wal.Open(config.WALPath)
+		if err != nil {
+			return nil, err
+		}
+		d.wal = log
+	}
+
+	return d, nil
+}
+
+// Handle registers a write that may be queued while degraded and replayed on recovery. Only
+// register writes that are still correct when applied late. Call it before serving requests.
+func (d *Degradation) Handle(method string, handler http.HandlerFunc) {
+	d.writes[method] = handler
+}
+
+// Degraded reports whether Redis is currently considered unreachable
+func (d *Degradation) Degraded() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	return d.degraded
+}
+
+// Status returns the current degradation status
+func (d *Degradation) Status() DegradationStatus {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	status := DegradationStatus{Degraded: d.degraded}
+	if d.degraded {
+		since := d.since
+		status.Since = &since
+	}
+	if d.wal != nil {
+		status.QueuedWrites = d.wal.Len()
+	}
+	return status
+}
+
+// Start begins probing Redis. Writes left in the WAL by a previous run are replayed once
+// Redis answers.
+func (d *Degradation) Start(ctx context.Context) {
+	if !d.config.Enabled {
+		d.logger.Info("Degradation mode disabled")
+		return
+	}
+
+	d.ticker = time.NewTicker(d.config.ProbeInterval)
+
+	d.logger.Info("Starting Redis availability probe",
+		zap.Duration("interval", d.config.ProbeInterval),
+		zap.Int("failure_threshold", d.config.FailureThreshold),
+		zap.Int("queued_writes", d.wal.Len()))
+
+	go d.probeLoop(ctx)
+}
+
+// Stop stops probing and closes the WAL
+func (d *Degradation) Stop() {
+	if d.ticker == nil {
+		return
+	}
+
+	d.logger.Info("Stopping Redis availability probe")
+	d.ticker.Stop()
+	close(d.stopChan)
+
+	if err := d.wal.Close(); err != nil {
+		d.logger.Error("Failed to close degradation WAL", zap.Error(err))
+	}
+}
+
+// probeLoop probes Redis on every tick
+func (d *Degradation) probeLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.stopChan:
+			return
+		case <-d.ticker.C:
+			d.probeTick(ctx)
+		}
+	}
+}
+
+// probeTick pings Redis once and degrades or recovers accordingly
+func (d *Degradation) probeTick(ctx context.Context) {
+	err := d.ping(ctx)
+	if err != nil {
+		d.mutex.Lock()
+		d.failures++
+		entering := !d.degraded && d.failures >= d.config.FailureThreshold
+		if entering {
+			d.degraded = true
+			d.since = time.Now()
+		}
+		d.mutex.Unlock()
+
+		if entering {
+			d.logger.Warn("Redis unreachable, entering degraded mode", zap.Error(err))
+			d.broadcast("server.degraded", d.Status())
+		}
+		return
+	}
+
+	d.mutex.Lock()
+	d.failures = 0
+	recovering := d.degraded
+	d.mutex.Unlock()
+
+	if recovering || d.wal.Len() > 0 {
+		d.recover(ctx)
+	}
+}
+
+// ping probes Redis within one probe interval
+func (d *Degradation) ping(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, d.config.ProbeInterval)
+	defer cancel()
+
+	return d.probe(ctx)
+}
+
+// recover replays queued writes until the WAL is empty, then leaves degraded mode. Writes keep
+// being queued during replay so they apply after the ones queued before them.
+func (d *Degradation) recover(ctx context.Context) {
+	replayed, dropped := 0, 0
+
+	for {
+		d.mutex.Lock()
+		records, err := d.wal.Drain()
+		if err != nil {
+			d.mutex.Unlock()
+			d.logger.Error("Failed to read degradation WAL", zap.Error(err))
+			return
+		}
+
+		if len(records) == 0 {
+			wasDegraded := d.degraded
+			d.degraded = false
+			d.mutex.Unlock()
+
+			if wasDegraded {
+				d.logger.Info("Redis reachable again, leaving degraded mode",
+					zap.Int("replayed", replayed),
+					zap.Int("dropped", dropped))
+				d.broadcast("server.recovered", d.Status())
+			}
+			return
+		}
+		d.mutex.Unlock()
+
+		for i, record := range records {
+			var write queuedWrite
+			if err := json.Unmarshal(record, &write); err != nil {
+				d.logger.Warn("Dropping unreadable queued write", zap.Error(err))
+				dropped++
+				continue
+			}
+
+			if err := d.replay(ctx, write); err != nil {
+				// Redis failing again is retried on the next recovery; anything else is the
+				// write itself being rejected and won't succeed later either
+				if d.ping(ctx) != nil {
+					d.requeue(records[i:])
+					return
+				}
+				d.logger.Warn("Dropping queued write rejected on replay",
+					zap.String("method", write.Method),
+					zap.String("user_id", write.UserID),
+					zap.Time("queued_at", write.QueuedAt),
+					zap.Error(err))
+				dropped++
+				continue
+			}
+			replayed++
+		}
+	}
+}
+
+// replay runs a queued write through its handler as the user who sent it
+func (d *Degradation) replay(ctx context.Context, write queuedWrite) error {
+	handler, ok := d.writes[write.Method]
+	if !ok {
+		return errors.New("method is not registered for replay")
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithValue(ctx, UserIDContextKey, write.UserID), replayTimeout)
+	defer cancel()
+
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, "/api/v1/"+write.Method, bytes.NewReader(write.Request))
+	if err != nil {
+		return err
+	}
+	r.Header.Set("Content-Type", "application/json")
+	r, attachedError := jsonrpcx.WithErrorSlot(r)
+
+	handler.ServeHTTP(&discardWriter{header: make(http.Header)}, r)
+
+	if response := attachedError(); response != nil && response.Error != nil {
+		return fmt.Errorf("%s (code %d)", response.Error.Message, response.Error.Code)
+	}
+	return nil
+}
+
+// requeue puts writes that could not be replayed back into the WAL
+func (d *Degradation) requeue(records []json.RawMessage) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, record := range records {
+		if err := d.wal.Append(record); err != nil {
+			d.logger.Error("Failed to requeue write", zap.Error(err))
+		}
+	}
+}
+
+// broadcast notifies clients on this server; every instance detects the outage itself
+func (d *Degradation) broadcast(method string, status DegradationStatus) {
+	if d.broadcaster == nil {
+		return
+	}
+
+	d.broadcaster.BroadcastToAll(jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  method,
+		Params:  status,
+	})
+}
+
+// Guard returns a middleware that applies the degradation policy. Apply it after
+// authentication, since snapshots and queued writes belong to the signed-in user.
+func (d *Degradation) Guard(next http.Handler) http.Handler {
+	if !d.config.Enabled {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, ok := rpcMethod(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		userID, _ := GetUserID(r.Context())
+		_, queueable := d.writes[method]
+		cached := d.reads[method] && userID != ""
+		if !cached && !d.Degraded() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+
+		var req jsonrpcx.Request
+		if err := json.Unmarshal(body, &req); err != nil {
+			jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+			return
+		}
+		key := snapshotKey(method, userID, req.Params)
+
+		if !d.Degraded() {
+			capture := &captureWriter{ResponseWriter: w}
+			next.ServeHTTP(capture, r)
+			d.storeSnapshot(key, capture)
+			return
+		}
+
+		switch {
+		case queueable && userID != "":
+			d.queue(w, r, next, queuedWrite{Method: method, UserID: userID, Request: body, QueuedAt: time.Now()}, req.ID)
+		case cached:
+			d.serveSnapshot(w, r, key, req.ID)
+		default:
+			jsonrpcx.WithError(r, req.ID, jsonrpcx.Degraded, "Service is temporarily degraded, please try again later")
+		}
+	})
+}
+
+// queue appends a write to the WAL, or runs it if the server recovered in the meantime
+func (d *Degradation) queue(w http.ResponseWriter, r *http.Request, next http.Handler, write queuedWrite, id any) {
+	d.mutex.Lock()
+	if !d.degraded {
+		d.mutex.Unlock()
+		next.ServeHTTP(w, r)
+		return
+	}
+	err := d.wal.Append(write)
+	d.mutex.Unlock()
+
+	if err != nil {
+		d.logger.Error("Failed to queue write", zap.String("method", write.Method), zap.Error(err))
+		jsonrpcx.WithError(r, id, jsonrpcx.Degraded, "Service is temporarily degraded, please try again later")
+		return
+	}
+
+	w.Header().Set("X-Degraded", "queued")
+	jsonrpcx.Success(w, id, QueuedResult{Queued: true})
+}
+
+// serveSnapshot answers a read with its last result, if that is recent enough
+func (d *Degradation) serveSnapshot(w http.ResponseWriter, r *http.Request, key string, id any) {
+	d.snapshotMutex.Lock()
+	var found *snapshot
+	if element, ok := d.snapshots[key]; ok {
+		found = element.Value.(*snapshot)
+	}
+	d.snapshotMutex.Unlock()
+
+	if found == nil || time.Since(found.at) > d.config.SnapshotTTL {
+		jsonrpcx.WithError(r, id, jsonrpcx.Degraded, "Service is temporarily degraded, please try again later")
+		return
+	}
+
+	w.Header().Set("X-Degraded", "snapshot")
+	w.Header().Set("X-Snapshot-Time", found.at.UTC().Format(time.RFC3339))
+	jsonrpcx.Success(w, id, found.result)
+}
+
+// storeSnapshot keeps the result of a successful read, evicting the least recently stored
+func (d *Degradation) storeSnapshot(key string, capture *captureWriter) {
+	if capture.status != 0 && capture.status != http.StatusOK {
+		return
+	}
+
+	var response struct {
+		Result json.RawMessage        `json:"result"`
+		Error  *jsonrpcx.JSONRPCError `json:"error"`
+	}
+	if err := json.Unmarshal(capture.body.Bytes(), &response); err != nil || response.Error != nil || len(response.Result) == 0 {
+		return
+	}
+
+	d.snapshotMutex.Lock()
+	defer d.snapshotMutex.Unlock()
+
+	if element, ok := d.snapshots[key]; ok {
+		d.recent.Remove(element)
+	}
+	d.snapshots[key] = d.recent.PushFront(&snapshot{key: key, result: response.Result, at: time.Now()})
+
+	for d.recent.Len() > d.config.SnapshotLimit {
+		oldest := d.recent.Back()
+		d.recent.Remove(oldest)
+		delete(d.snapshots, oldest.Value.(*snapshot).key)
+	}
+}
+
+// snapshotKey identifies a read by method, user and params
+func snapshotKey(method, userID string, params json.RawMessage) string {
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, params); err != nil {
+		compact.Write(params)
+	}
+	return method + "\x00" + userID + "\x00" + compact.String()
+}
+
+// captureWriter passes a response through while keeping a copy of it
+type captureWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (c *captureWriter) WriteHeader(status int) {
+	c.status = status
+	c.ResponseWriter.WriteHeader(status)
+}
+
+func (c *captureWriter) Write(b []byte) (int, error) {
+	c.body.Write(b)
+	return c.ResponseWriter.Write(b)
+}
+
+// discardWriter drops the response of a replayed write; nobody is waiting for it
+type discardWriter struct {
+	header http.Header
+}
+
+func (d *discardWriter) Header() http.Header         { return d.header }
+func (d *discardWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (d *discardWriter) WriteHeader(int)             {}
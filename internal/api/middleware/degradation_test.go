@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// recordingBroadcaster keeps the notifications sent to clients
+type recordingBroadcaster struct {
+	methods []string
+}
+
+func (b *recordingBroadcaster) BroadcastToAll(notification jsonrpcx.JsonRpcNotification) {
+	b.methods = append(b.methods, notification.Method)
+}
+
+func TestDegradation(t *testing.T) {
+	log := logger.GetGlobalLogger()
+	redisDown := false
+	broadcaster := &recordingBroadcaster{}
+
+	d, err := NewDegradation(log, DegradationConfig{
+		Enabled:          true,
+		ProbeInterval:    time.Second,
+		FailureThreshold: 2,
+		WALPath:          filepath.Join(t.TempDir(), "writes.wal"),
+		SnapshotTTL:      time.Minute,
+		SnapshotLimit:    10,
+		SnapshotMethods:  []string{"trainer.Get"},
+	}, func(ctx context.Context) error {
+		if redisDown {
+			return errors.New("connection refused")
+		}
+		return nil
+	}, broadcaster)
+	require.NoError(t, err)
+	defer d.wal.Close()
+
+	var replayedBy []string
+	d.Handle("trainer.UpdateProfile", func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := GetUserID(r.Context())
+		replayedBy = append(replayedBy, userID)
+		jsonrpcx.Success(w, nil, "ok")
+	})
+
+	handled := 0
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handled++
+		req, err := jsonrpcx.ParseRequest(r)
+		require.NoError(t, err)
+		jsonrpcx.Success(w, req.ID, map[string]int{"level": 7})
+	})
+
+	// Stands in for auth putting the signed-in user on the request
+	signedIn := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), UserIDContextKey, "user-1")))
+		})
+	}
+	server := Chain(ErrorAdapter(log), signedIn)(d.Guard(handler))
+
+	call := func(method string, id int) (jsonrpcx.Response, http.Header) {
+		body := fmt.Sprintf(`{"jsonrpc":"2.0","method":%q,"params":{"id":"t1"},"id":%d}`, method, id)
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/"+method, strings.NewReader(body)))
+
+		var response jsonrpcx.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response, w.Header()
+	}
+
+	response, _ := call("trainer.Get", 1)
+	require.Nil(t, response.Error)
+
+	redisDown = true
+	d.probeTick(context.Background())
+	assert.False(t, d.Degraded(), "a single failed ping doesn't degrade")
+	d.probeTick(context.Background())
+	require.True(t, d.Degraded())
+
+	response, header := call("trainer.Get", 2)
+	require.Nil(t, response.Error)
+	assert.Equal(t, map[string]any{"level": float64(7)}, response.Result)
+	assert.EqualValues(t, 2, response.ID)
+	assert.Equal(t, "snapshot", header.Get("X-Degraded"))
+
+	response, _ = call("trainer.Status", 3)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, jsonrpcx.Degraded, response.Error.Code)
+
+	response, _ = call("trainer.UpdateProfile", 4)
+	require.Nil(t, response.Error)
+	assert.Equal(t, map[string]any{"queued": true}, response.Result)
+	assert.Equal(t, 1, d.Status().QueuedWrites)
+	assert.Equal(t, 1, handled, "degraded requests never reach the handler")
+
+	redisDown = false
+	d.probeTick(context.Background())
+	assert.False(t, d.Degraded())
+	assert.Equal(t, []string{"user-1"}, replayedBy, "queued writes replay as their user")
+	assert.Equal(t, 0, d.Status().QueuedWrites)
+	assert.Equal(t, []string{"server.degraded", "server.recovered"}, broadcaster.methods)
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SunsetLayout is the date format of deprecation sunsets
+const SunsetLayout = "2006-01-02"
+
+// Deprecation announces that a JSON-RPC method will stop being served
+type Deprecation struct {
+	Method      string
+	Sunset      time.Time // Day the method is removed, UTC
+	Replacement string    // Method to call instead, if any
+}
+
+// Deprecations is the registry of deprecated JSON-RPC methods
+type Deprecations struct {
+	methods map[string]Deprecation
+}
+
+// ParseDeprecations parses "<method>=<sunset>" or "<method>=<sunset>:<replacement>" entries,
+// sunsets as YYYY-MM-DD, e.g. "trainer.FetchPosition=2026-12-31:trainer.Position"
+func ParseDeprecations(entries []string) (*Deprecations, error) {
+	d := &Deprecations{methods: make(map[string]Deprecation, len(entries))}
+	for _, entry := range entries {
+		method, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || method == "" {
+			return nil, fmt.Errorf("deprecation %q must be formatted as <method>=<sunset>[:<replacement>]", entry)
+		}
+
+		date, replacement, _ := strings.Cut(value, ":")
+		sunset, err := time.Parse(SunsetLayout, date)
+		if err != nil {
+			return nil, fmt.Errorf("deprecation %q has an invalid sunset date, use YYYY-MM-DD", entry)
+		}
+
+		d.methods[method] = Deprecation{Method: method, Sunset: sunset, Replacement: replacement}
+	}
+	return d, nil
+}
+
+// Lookup returns the deprecation of a method, if it is deprecated
+func (d *Deprecations) Lookup(method string) (Deprecation, bool) {
+	if d == nil {
+		return Deprecation{}, false
+	}
+	deprecation, ok := d.methods[method]
+	return deprecation, ok
+}
+
+// List returns every deprecation, soonest sunset first
+func (d *Deprecations) List() []Deprecation {
+	if d == nil {
+		return []Deprecation{}
+	}
+
+	list := make([]Deprecation, 0, len(d.methods))
+	for _, deprecation := range d.methods {
+		list = append(list, deprecation)
+	}
+	slices.SortFunc(list, func(a, b Deprecation) int {
+		if c := a.Sunset.Compare(b.Sunset); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Method, b.Method)
+	})
+	return list
+}
+
+// headerValue formats the deprecation for the X-API-Deprecation header
+func (d Deprecation) headerValue() string {
+	value := d.Method + "; sunset=" + d.Sunset.Format(SunsetLayout)
+	if d.Replacement != "" {
+		value += "; replacement=" + d.Replacement
+	}
+	return value
+}
+
+// APIHeaders tags responses with the environment in X-Env, unless it is empty, and responses
+// of deprecated methods with X-API-Deprecation and a Sunset header (RFC 8594). Place it after
+// Gateway so methods called through the gateway are recognized.
+func APIHeaders(environment string, deprecations *Deprecations) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			header := w.Header()
+			if environment != "" {
+				header.Set("X-Env", environment)
+			}
+
+			if method, ok := rpcMethod(r.URL.Path); ok {
+				if deprecation, ok := deprecations.Lookup(method); ok {
+					header.Set("X-API-Deprecation", deprecation.headerValue())
+					header.Set("Sunset", deprecation.Sunset.Format(http.TimeFormat))
+				}
+			}
+
+			// Browser clients can only read headers they are allowed to
+			header.Set("Access-Control-Expose-Headers", "X-Env, X-API-Deprecation, Sunset, X-Request-ID")
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDeprecations(t *testing.T) {
+	deprecations, err := ParseDeprecations([]string{
+		"trainer.FetchPosition=2026-12-31:trainer.Position",
+		" world.Get=2026-11-01 ",
+	})
+	require.NoError(t, err)
+
+	list := deprecations.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, "world.Get", list[0].Method, "soonest sunset first")
+	assert.Equal(t, "trainer.Position", list[1].Replacement)
+
+	_, err = ParseDeprecations([]string{"trainer.Get"})
+	assert.Error(t, err)
+	_, err = ParseDeprecations([]string{"trainer.Get=31/12/2026"})
+	assert.Error(t, err)
+
+	var none *Deprecations
+	assert.Empty(t, none.List())
+}
+
+func TestAPIHeaders(t *testing.T) {
+	deprecations, err := ParseDeprecations([]string{"trainer.FetchPosition=2026-12-31:trainer.Position"})
+	require.NoError(t, err)
+
+	handler := APIHeaders("staging", deprecations)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	call := func(path string) http.Header {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		return w.Header()
+	}
+
+	header := call("/api/v1/trainer.FetchPosition")
+	assert.Equal(t, "staging", header.Get("X-Env"))
+	assert.Equal(t, "trainer.FetchPosition; sunset=2026-12-31; replacement=trainer.Position", header.Get("X-API-Deprecation"))
+	assert.Equal(t, "Thu, 31 Dec 2026 00:00:00 GMT", header.Get("Sunset"))
+
+	header = call("/api/v1/trainer.Get")
+	assert.Equal(t, "staging", header.Get("X-Env"))
+	assert.Empty(t, header.Get("X-API-Deprecation"))
+	assert.Empty(t, header.Get("Sunset"))
+}
@@ -0,0 +1,100 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/pkg/autorouter"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// GatewayPath is the single endpoint accepting every JSON-RPC method
+const GatewayPath = "/api/v1/rpc"
+
+// DiscoverMethod lists the methods the gateway dispatches, as in OpenRPC's rpc.discover
+const DiscoverMethod = "rpc.discover"
+
+// DiscoveredMethod describes a method the gateway dispatches
+type DiscoveredMethod struct {
+	Name string `json:"name"`
+	Path string `json:"path"` // The method's own endpoint
+}
+
+// Gateway lets standard JSON-RPC clients call every method on GatewayPath. A request there is
+// routed to the endpoint its "method" names, as if it had been posted to it, so per-method
+// timeouts, rate limits and auth apply unchanged. Place it before those middlewares.
+func Gateway(logger *logger.Logger, registry *autorouter.Registry) Middleware {
+	l := logger.WithComponent("rpc-gateway")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != GatewayPath {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Method != http.MethodPost {
+				jsonrpcx.WithError(r, nil, jsonrpcx.MethodNotFound, "Method not allowed")
+				return
+			}
+
+			body, err := io.ReadAll(r.Body)
+			if err != nil {
+				jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+				return
+			}
+			r.Body.Close()
+
+			var envelope struct {
+				Method string `json:"method"`
+				ID     any    `json:"id"`
+			}
+			if err := json.Unmarshal(body, &envelope); err != nil {
+				if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '[' {
+					jsonrpcx.WithError(r, nil, jsonrpcx.InvalidRequest, "Batch requests are not supported")
+					return
+				}
+				jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+				return
+			}
+
+			if envelope.Method == DiscoverMethod {
+				jsonrpcx.Success(w, envelope.ID, discover(registry))
+				return
+			}
+
+			info, ok := registry.Lookup(envelope.Method)
+			if !ok {
+				l.WithContext(r.Context()).Debug("Unknown JSON-RPC method", zap.String("method", envelope.Method))
+				jsonrpcx.WithError(r, envelope.ID, jsonrpcx.MethodNotFound, fmt.Sprintf("Method not found: %s", envelope.Method))
+				return
+			}
+
+			routed := r.Clone(r.Context())
+			routed.URL.Path = info.URLPath
+			routed.URL.RawPath = ""
+			routed.RequestURI = info.URLPath
+			routed.Body = io.NopCloser(bytes.NewReader(body))
+			routed.ContentLength = int64(len(body))
+
+			next.ServeHTTP(w, routed)
+		})
+	}
+}
+
+// discover lists the registered methods
+func discover(registry *autorouter.Registry) map[string][]DiscoveredMethod {
+	names := registry.Methods()
+	methods := make([]DiscoveredMethod, 0, len(names))
+	for _, name := range names {
+		info, _ := registry.Lookup(name)
+		methods = append(methods, DiscoveredMethod{Name: name, Path: info.URLPath})
+	}
+	return map[string][]DiscoveredMethod{"methods": methods}
+}
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/pkg/autorouter"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// echoHandler answers with the path and method it was reached through
+type echoHandler struct{}
+
+func (echoHandler) Get(w http.ResponseWriter, r *http.Request) {
+	req, err := jsonrpcx.ParseRequest(r)
+	if err != nil {
+		jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+		return
+	}
+	jsonrpcx.Success(w, req.ID, map[string]string{"path": r.URL.Path, "method": req.Method})
+}
+
+func TestGateway(t *testing.T) {
+	log := logger.GetGlobalLogger()
+	mux := http.NewServeMux()
+	registry := autorouter.NewRegistry()
+	router := autorouter.NewAutoRouter(mux, autorouter.RegistrationOptions{
+		Prefix:       "/api/v1/",
+		MethodPrefix: "trainer.",
+		Registry:     registry,
+	})
+	require.NoError(t, router.RegisterHandlers(echoHandler{}))
+
+	server := Chain(ErrorAdapter(log), Gateway(log, registry))(mux)
+
+	call := func(body string) jsonrpcx.Response {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, GatewayPath, strings.NewReader(body)))
+
+		var response jsonrpcx.Response
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response
+	}
+
+	response := call(`{"jsonrpc":"2.0","method":"trainer.Get","params":{},"id":1}`)
+	require.Nil(t, response.Error)
+	assert.Equal(t, map[string]any{"path": "/api/v1/trainer.Get", "method": "trainer.Get"}, response.Result,
+		"the method's own endpoint serves it with the full body")
+
+	response = call(`{"jsonrpc":"2.0","method":"trainer.Fly","id":2}`)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, jsonrpcx.MethodNotFound, response.Error.Code)
+	assert.EqualValues(t, 2, response.ID)
+
+	response = call(`[{"jsonrpc":"2.0","method":"trainer.Get","id":3}]`)
+	require.NotNil(t, response.Error)
+	assert.Equal(t, jsonrpcx.InvalidRequest, response.Error.Code)
+
+	response = call(`{"jsonrpc":"2.0","method":"rpc.discover","id":4}`)
+	require.Nil(t, response.Error)
+	assert.Equal(t, map[string]any{
+		"methods": []any{map[string]any{"name": "trainer.Get", "path": "/api/v1/trainer.Get"}},
+	}, response.Result)
+}
@@ -0,0 +1,68 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/danghamo/life/internal/domain/account"
+)
+
+// UnaryServerInterceptor returns a gRPC interceptor that requires JWT authentication the way
+// RequireAuth does, reading the bearer token from the call's authorization metadata. Calls
+// are scoped to the token's tenant and carry the same user info in their context.
+func (m *AuthMiddleware) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		values := md.Get("authorization")
+		if len(values) == 0 {
+			m.logger.WithContext(ctx).Debug("Missing authorization metadata", zap.String("method", info.FullMethod))
+			return nil, status.Error(codes.Unauthenticated, "Missing authorization metadata")
+		}
+
+		scheme, tokenString, ok := strings.Cut(values[0], " ")
+		if !ok || scheme != "Bearer" {
+			m.logger.WithContext(ctx).Debug("Invalid authorization metadata format", zap.String("method", info.FullMethod))
+			return nil, status.Error(codes.Unauthenticated, "Invalid authorization metadata format")
+		}
+
+		ctx, claims, err := m.validateToken(ctx, tokenString)
+		if err != nil {
+			m.logger.WithContext(ctx).Debug("Invalid JWT token", zap.String("method", info.FullMethod), zap.Error(err))
+			return nil, status.Error(codes.Unauthenticated, "Invalid or expired token")
+		}
+
+		ctx = context.WithValue(ctx, UserIDContextKey, claims.PlayerID())
+		ctx = context.WithValue(ctx, UserEmailContextKey, claims.Email)
+		ctx = context.WithValue(ctx, UserNameContextKey, claims.Name)
+		ctx = context.WithValue(ctx, UserRoleContextKey, claims.Role)
+		ctx = context.WithValue(ctx, SessionIDContextKey, claims.SessionID)
+
+		return handler(ctx, req)
+	}
+}
+
+// UnaryRoleInterceptor returns a gRPC interceptor that lets only tokens whose role includes
+// the role roleOf returns for the called method through. It runs after
+// UnaryServerInterceptor, which puts the token's role in the context.
+func (m *AuthMiddleware) UnaryRoleInterceptor(roleOf func(fullMethod string) account.Role) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		required := roleOf(info.FullMethod)
+		current, _ := GetUserRole(ctx)
+		if !current.Includes(required) {
+			userID, _ := GetUserID(ctx)
+			m.logger.WithContext(ctx).Warn("Method refused to role",
+				zap.String("userId", userID),
+				zap.String("role", string(current)),
+				zap.String("required", string(required)),
+				zap.String("method", info.FullMethod))
+			return nil, status.Error(codes.PermissionDenied, "Role "+string(required)+" required")
+		}
+		return handler(ctx, req)
+	}
+}
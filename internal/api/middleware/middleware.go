@@ -5,11 +5,9 @@ import (
 	"fmt"
 	"net"
 	"net/http"
-	"sync"
 	"time"
 
 	"go.uber.org/zap"
-	"golang.org/x/time/rate"
 
 	"github.com/danghamo/life/internal/api/jsonrpcx"
 	"github.com/danghamo/life/pkg/logger"
@@ -46,7 +44,7 @@ func Logging(logger *logger.Logger) Middleware {
 
 			duration := time.Since(start)
 
-			l.Info("HTTP request",
+			l.WithContext(r.Context()).Info("HTTP request",
 				zap.String("method", r.Method),
 				zap.String("path", r.URL.Path),
 				zap.String("remote_addr", r.RemoteAddr),
@@ -65,7 +63,7 @@ func CORS() Middleware {
 			// Set CORS headers
 			w.Header().Set("Access-Control-Allow-Origin", "*")
 			w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+			w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID")
 			w.Header().Set("Access-Control-Max-Age", "86400")
 
 			// Handle preflight request
@@ -88,7 +86,7 @@ func Recovery(logger *logger.Logger) Middleware {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			defer func() {
 				if err := recover(); err != nil {
-					l.Error("HTTP handler panic",
+					l.WithContext(r.Context()).Error("HTTP handler panic",
 						zap.Any("error", err),
 						zap.String("path", r.URL.Path),
 						zap.String("method", r.Method),
@@ -111,9 +109,16 @@ func ErrorAdapter(logger *logger.Logger) Middleware {
 
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Errors attached behind middleware that copy the request land in the shared slot
+			r, attachedError := jsonrpcx.WithErrorSlot(r)
+
 			next.ServeHTTP(w, r)
 
 			// Check if there's a JSON-RPC error in the context
+			if rpcResponse := attachedError(); rpcResponse != nil {
+				jsonrpcx.Write(w, *rpcResponse)
+				return
+			}
 			if rpcResponse, ok := r.Context().Value("jsonrpc_error").(*jsonrpcx.Response); ok {
 				jsonrpcx.Write(w, *rpcResponse)
 				return
@@ -122,75 +127,13 @@ func ErrorAdapter(logger *logger.Logger) Middleware {
 			// Check if there's a generic error in the context
 			if err, ok := r.Context().Value("error").(error); ok {
 				// Non-specific error - return 500
-				l.Error("Error encountered", zap.Error(err))
+				l.WithContext(r.Context()).Error("Error encountered", zap.Error(err))
 				errorAdapter.SendError(w, nil, jsonrpcx.InternalError, "Internal server error")
 			}
 		})
 	}
 }
 
-// RateLimit middleware
-func RateLimit(logger *logger.Logger) Middleware {
-	l := logger.WithComponent("ratelimit-middleware")
-
-	type client struct {
-		limiter  *rate.Limiter
-		lastSeen time.Time
-	}
-
-	var (
-		mu      sync.Mutex
-		clients = make(map[string]*client)
-	)
-
-	// Cleanup old clients every minute
-	go func() {
-		ticker := time.NewTicker(time.Minute)
-		defer ticker.Stop()
-
-		for range ticker.C {
-			mu.Lock()
-			for ip, client := range clients {
-				if time.Since(client.lastSeen) > 3*time.Minute {
-					delete(clients, ip)
-				}
-			}
-			mu.Unlock()
-		}
-	}()
-
-	return func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			ip := getClientIP(r)
-
-			mu.Lock()
-			if _, exists := clients[ip]; !exists {
-				// Allow 600 requests per minute per IP (10 per second)
-				clients[ip] = &client{
-					limiter: rate.NewLimiter(rate.Every(time.Second/10), 50),
-				}
-			}
-			clients[ip].lastSeen = time.Now()
-			limiter := clients[ip].limiter
-			mu.Unlock()
-
-			if !limiter.Allow() {
-				l.Warn("Rate limit exceeded",
-					zap.String("ip", ip),
-					zap.String("path", r.URL.Path),
-				)
-
-				w.Header().Set("Content-Type", "application/json")
-				w.WriteHeader(http.StatusTooManyRequests)
-				w.Write([]byte(`{"error": "Rate limit exceeded"}`))
-				return
-			}
-
-			next.ServeHTTP(w, r)
-		})
-	}
-}
-
 // responseWriter wraps http.ResponseWriter to capture status code while preserving interfaces
 type responseWriter struct {
 	http.ResponseWriter
@@ -217,6 +160,11 @@ func (rw *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
 	return nil, nil, fmt.Errorf("responseWriter does not implement http.Hijacker")
 }
 
+// ClientIP returns the address a request came from, preferring proxy headers
+func ClientIP(r *http.Request) string {
+	return getClientIP(r)
+}
+
 // getClientIP extracts the client IP from the request
 func getClientIP(r *http.Request) string {
 	// Check X-Forwarded-For header first
@@ -0,0 +1,198 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// RatePolicy is a token bucket that refills Rate tokens per second up to Burst. Each request
+// takes one token. A zero Rate means unlimited.
+type RatePolicy struct {
+	Rate  float64
+	Burst int
+}
+
+// Unlimited reports whether the policy lets every request through
+func (p RatePolicy) Unlimited() bool {
+	return p.Rate <= 0
+}
+
+// RateLimitConfig sets the rate limits of JSON-RPC methods
+type RateLimitConfig struct {
+	Default RatePolicy            // Per method, for methods without a rule
+	Methods map[string]RatePolicy // Rules by method name, or by "<service>.*" for a whole service
+}
+
+// For returns the bucket name and policy that apply to a method. Methods under a service rule
+// share one bucket, so the rule caps the service as a whole.
+func (c RateLimitConfig) For(method string) (string, RatePolicy) {
+	if policy, ok := c.Methods[method]; ok {
+		return method, policy
+	}
+
+	if service, _, ok := strings.Cut(method, "."); ok {
+		if policy, ok := c.Methods[service+".*"]; ok {
+			return service + ".*", policy
+		}
+	}
+
+	return method, c.Default
+}
+
+// ParseRatePolicy parses "<requests>/<period>", e.g. "10/1m", allowing bursts of the full
+// count. "unlimited" and "0" turn limiting off.
+func ParseRatePolicy(value string) (RatePolicy, error) {
+	value = strings.TrimSpace(value)
+	if value == "unlimited" || value == "0" {
+		return RatePolicy{}, nil
+	}
+
+	count, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return RatePolicy{}, fmt.Errorf("rate limit %q must be formatted as <requests>/<period> or unlimited", value)
+	}
+
+	requests, err := strconv.Atoi(count)
+	if err != nil || requests < 1 {
+		return RatePolicy{}, fmt.Errorf("rate limit %q needs a positive request count", value)
+	}
+	duration, err := time.ParseDuration(period)
+	if err != nil || duration <= 0 {
+		return RatePolicy{}, fmt.Errorf("rate limit %q needs a positive period", value)
+	}
+
+	return RatePolicy{Rate: float64(requests) / duration.Seconds(), Burst: requests}, nil
+}
+
+// ParseRateLimitConfig parses the default policy and "<method>=<policy>" rules
+func ParseRateLimitConfig(defaultPolicy string, methods []string) (RateLimitConfig, error) {
+	def, err := ParseRatePolicy(defaultPolicy)
+	if err != nil {
+		return RateLimitConfig{}, err
+	}
+
+	config := RateLimitConfig{Default: def, Methods: make(map[string]RatePolicy, len(methods))}
+	for _, entry := range methods {
+		method, value, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || method == "" {
+			return RateLimitConfig{}, fmt.Errorf("rate limit rule %q must be formatted as <method>=<policy>", entry)
+		}
+
+		policy, err := ParseRatePolicy(value)
+		if err != nil {
+			return RateLimitConfig{}, err
+		}
+		config.Methods[method] = policy
+	}
+
+	return config, nil
+}
+
+// tokenBucket takes a token from the bucket at KEYS[1], refilling it by elapsed Redis time.
+// ARGV is the rate per second and the burst. Returns {allowed, milliseconds until a token}.
+var tokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {allowed, wait}
+`)
+
+// RateLimiter limits JSON-RPC requests per user, or per client IP when signed out. Buckets
+// live in Redis so limits hold across server instances.
+type RateLimiter struct {
+	logger *logger.Logger
+	client *redis.Client
+	config atomic.Pointer[RateLimitConfig]
+}
+
+// NewRateLimiter creates a new Redis-backed rate limiter
+func NewRateLimiter(logger *logger.Logger, client *redis.Client, config RateLimitConfig) *RateLimiter {
+	l := &RateLimiter{
+		logger: logger.WithComponent("ratelimit-middleware"),
+		client: client,
+	}
+	l.config.Store(&config)
+	return l
+}
+
+// SetConfig replaces the limits of the running limiter. Buckets keep their tokens; a changed
+// policy refills them at its own rate from there.
+func (l *RateLimiter) SetConfig(config RateLimitConfig) {
+	l.config.Store(&config)
+}
+
+// Limit returns a middleware that rejects requests over their method's limit. Apply it after
+// authentication so signed-in players are limited by user instead of by address.
+func (l *RateLimiter) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, ok := rpcMethod(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		bucket, policy := l.config.Load().For(method)
+		if policy.Unlimited() {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		subject := "ip:" + ClientIP(r)
+		if userID, ok := GetAccountID(r.Context()); ok {
+			subject = "user:" + userID
+		}
+
+		key := fmt.Sprintf("ratelimit:%s:%s", bucket, subject)
+		result, err := tokenBucket.Run(r.Context(), l.client, []string{key}, policy.Rate, policy.Burst).Int64Slice()
+		if err != nil {
+			// Limits protect the game, they shouldn't take it down with Redis
+			l.logger.WithContext(r.Context()).Warn("Rate limit check failed, allowing request",
+				zap.String("method", method),
+				zap.Error(err))
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if result[0] == 0 {
+			retryAfter := time.Duration(result[1]) * time.Millisecond
+			l.logger.WithContext(r.Context()).Debug("Rate limit exceeded",
+				zap.String("method", method),
+				zap.String("subject", subject),
+				zap.Duration("retry_after", retryAfter))
+
+			w.Header().Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+			jsonrpcx.WithError(r, nil, jsonrpcx.RateLimited, "Rate limit exceeded")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimitConfig(t *testing.T) {
+	config, err := ParseRateLimitConfig("20/1s", []string{"trainer.Move=unlimited", "auth.*=30/1m"})
+	require.NoError(t, err)
+
+	bucket, policy := config.For("trainer.Move")
+	assert.Equal(t, "trainer.Move", bucket)
+	assert.True(t, policy.Unlimited())
+
+	bucket, policy = config.For("auth.GetMe")
+	assert.Equal(t, "auth.*", bucket, "methods under a service rule share its bucket")
+	assert.Equal(t, 30, policy.Burst)
+	assert.InDelta(t, 0.5, policy.Rate, 1e-9)
+
+	bucket, policy = config.For("vault.List")
+	assert.Equal(t, "vault.List", bucket)
+	assert.Equal(t, RatePolicy{Rate: 20, Burst: 20}, policy)
+
+	for _, invalid := range []string{"20", "0/1s", "5/0s", "many/1m"} {
+		_, err := ParseRatePolicy(invalid)
+		assert.Error(t, err, invalid)
+	}
+	_, err = ParseRateLimitConfig("20/1s", []string{"auth.*"})
+	assert.Error(t, err)
+}
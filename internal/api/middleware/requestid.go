@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+// RequestID assigns every request an ID, echoed back in the X-Request-ID response header.
+// Clients may choose the ID by sending the header themselves; IDs that are too long or
+// carry other characters than letters, digits and ._:- are replaced.
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(requestid.Header)
+			if !requestid.Valid(id) {
+				id = requestid.New()
+			}
+
+			w.Header().Set(requestid.Header, id)
+			next.ServeHTTP(w, r.WithContext(requestid.With(r.Context(), id)))
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+func TestRequestID(t *testing.T) {
+	var seen string
+	handler := RequestID()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = requestid.FromContext(r.Context())
+	}))
+	call := func(header string) string {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/trainer.Get", nil)
+		if header != "" {
+			r.Header.Set(requestid.Header, header)
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Header().Get(requestid.Header)
+	}
+
+	echoed := call("client-7f3a")
+	assert.Equal(t, "client-7f3a", echoed, "client IDs are kept")
+	assert.Equal(t, "client-7f3a", seen)
+
+	echoed = call("")
+	assert.True(t, requestid.Valid(echoed), "requests without an ID are assigned one")
+	assert.Equal(t, echoed, seen)
+
+	echoed = call("bad id\r\n")
+	assert.NotEqual(t, "bad id\r\n", echoed, "invalid IDs are replaced")
+	assert.Equal(t, echoed, seen)
+}
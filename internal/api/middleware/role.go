@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/domain/account"
+)
+
+// RequireRole returns a middleware that lets only tokens whose role includes role through. It
+// runs after RequireAuth, which puts the token's role in the context. Return it from an
+// autorouter RegistrationOptions.Guard to require roles per method.
+func (m *AuthMiddleware) RequireRole(role account.Role) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			current, _ := GetUserRole(r.Context())
+			if !current.Includes(role) {
+				userID, _ := GetUserID(r.Context())
+				m.logger.WithContext(r.Context()).Warn("Method refused to role",
+					zap.String("userId", userID),
+					zap.String("role", string(current)),
+					zap.String("required", string(role)),
+					zap.String("path", r.URL.Path))
+				jsonrpcx.WithError(r, nil, jsonrpcx.Forbidden, "Role "+string(role)+" required")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
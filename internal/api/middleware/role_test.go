@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+func TestRequireRole(t *testing.T) {
+	m := &AuthMiddleware{logger: logger.GetGlobalLogger()}
+	handler := m.RequireRole(account.RoleModerator)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for role, allowed := range map[account.Role]bool{
+		account.RoleAdmin:     true,
+		account.RoleModerator: true,
+		account.RolePlayer:    false,
+		"":                    false, // Tokens issued before roles existed
+	} {
+		r := httptest.NewRequest(http.MethodPost, "/admin/v1/admin.Kick", nil)
+		r = r.WithContext(context.WithValue(r.Context(), UserRoleContextKey, role))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		assert.Equal(t, allowed, w.Code == http.StatusNoContent, role)
+	}
+}
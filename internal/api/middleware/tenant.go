@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// Tenant scopes requests to the tenant their Host belongs to. Requests on other hosts stay
+// unscoped; authenticated ones are then served for the tenant their token was issued by.
+func Tenant(tenants *tenant.Registry) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if t, ok := tenants.Resolve(r.Host); ok {
+				r = r.WithContext(tenant.WithTenant(r.Context(), t))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
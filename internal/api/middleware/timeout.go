@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// TimeoutConfig sets how long JSON-RPC methods may run before their context is cancelled
+type TimeoutConfig struct {
+	Default time.Duration            // Applies to methods without their own timeout; zero disables it
+	Methods map[string]time.Duration // Per-method timeouts by name, e.g. "auth.DeleteAccount"
+}
+
+// For returns the timeout of a method, or zero if it has none
+func (c TimeoutConfig) For(method string) time.Duration {
+	if timeout, ok := c.Methods[method]; ok {
+		return timeout
+	}
+	return c.Default
+}
+
+// Timeout middleware gives JSON-RPC requests a context deadline, so Redis calls stop instead
+// of pinning the handler past the write timeout. A handler that fails after its deadline
+// passed answers with a Timeout error instead of its own. Streaming endpoints are left alone.
+func Timeout(logger *logger.Logger, config TimeoutConfig) Middleware {
+	l := logger.WithComponent("timeout-middleware")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method, ok := rpcMethod(r.URL.Path)
+			timeout := config.For(method)
+			if !ok || timeout <= 0 {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx, cancel := context.WithTimeout(r.Context(), timeout)
+			defer cancel()
+			r = r.WithContext(ctx)
+			tw := &timeoutWriter{ResponseWriter: w}
+
+			next.ServeHTTP(tw, r)
+
+			if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return
+			}
+
+			l.WithContext(r.Context()).Warn("Request deadline exceeded",
+				zap.String("method", method),
+				zap.Duration("timeout", timeout))
+
+			if attached := jsonrpcx.AttachedError(r); attached != nil {
+				jsonrpcx.WithError(r, attached.ID, jsonrpcx.Timeout, "Request timed out")
+			} else if !tw.wrote {
+				jsonrpcx.WithError(r, nil, jsonrpcx.Timeout, "Request timed out")
+			}
+		})
+	}
+}
+
+// timeoutWriter records whether the handler wrote a response
+type timeoutWriter struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+func (tw *timeoutWriter) WriteHeader(code int) {
+	tw.wrote = true
+	tw.ResponseWriter.WriteHeader(code)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.wrote = true
+	return tw.ResponseWriter.Write(b)
+}
+
+// rpcMethod extracts the JSON-RPC method from an /api/v1/<service>.<Method> path
+func rpcMethod(path string) (string, bool) {
+	method, ok := strings.CutPrefix(path, "/api/v1/")
+	if !ok || strings.Contains(method, "/") || !strings.Contains(method, ".") {
+		return "", false
+	}
+	return method, true
+}
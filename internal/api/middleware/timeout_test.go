@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+func TestTimeout(t *testing.T) {
+	log := logger.GetGlobalLogger()
+	config := TimeoutConfig{
+		Default: 50 * time.Millisecond,
+		Methods: map[string]time.Duration{"slow.Call": 10 * time.Millisecond},
+	}
+
+	// Stands in for a handler whose Redis call fails at the deadline, behind a middleware
+	// that copies the request the way auth does
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(context.WithValue(r.Context(), UserIDContextKey, "user-1"))
+		<-r.Context().Done()
+		jsonrpcx.WithError(r, 7, jsonrpcx.InternalError, "Failed to load trainer")
+	})
+	server := Chain(ErrorAdapter(log), Timeout(log, config))(handler)
+
+	call := func(path string) *jsonrpcx.JSONRPCError {
+		w := httptest.NewRecorder()
+		server.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader("{}")))
+
+		var response struct {
+			Error *jsonrpcx.JSONRPCError `json:"error"`
+		}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		return response.Error
+	}
+
+	err := call("/api/v1/slow.Call")
+	require.NotNil(t, err)
+	assert.Equal(t, jsonrpcx.Timeout, err.Code)
+
+	start := time.Now()
+	err = call("/api/v1/other.Call")
+	require.NotNil(t, err)
+	assert.Equal(t, jsonrpcx.Timeout, err.Code)
+	assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond, "methods without their own timeout get the default")
+
+	method, ok := rpcMethod("/api/v1/stream/positions")
+	assert.False(t, ok, "streaming endpoints get no deadline: %q", method)
+}
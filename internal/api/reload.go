@@ -0,0 +1,39 @@
+package api
+
+import (
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/internal/app/service"
+)
+
+// RuntimeConfig holds the settings a running server changes without a restart, which would
+// drop every SSE and WebSocket connection
+type RuntimeConfig struct {
+	// RateLimit caps requests per method, per user or client IP
+	RateLimit middleware.RateLimitConfig
+	// AnimalSpawnRate is the chance per tick for a spawn area below its cap to spawn an animal
+	AnimalSpawnRate float64
+	// BroadcastRate is how many times a second positions of moving trainers are broadcast
+	BroadcastRate int
+}
+
+// Reload applies new runtime settings to the running server, in every tenant. Requests and
+// ticks in flight finish with the previous settings.
+func (s *Server) Reload(config RuntimeConfig) {
+	s.rateLimiter.SetConfig(config.RateLimit)
+
+	s.spawnManager.SetRate(config.AnimalSpawnRate)
+	for _, l := range s.tenantLoops {
+		if spawner, ok := l.loop.(*service.SpawnManager); ok {
+			spawner.SetRate(config.AnimalSpawnRate)
+		}
+	}
+
+	s.movementBroadcaster.SetBroadcastRate(config.BroadcastRate)
+
+	s.logger.Info("Runtime configuration reloaded",
+		zap.Float64("animal_spawn_rate", config.AnimalSpawnRate),
+		zap.Int("broadcast_rate", config.BroadcastRate),
+		zap.Int("rate_limit_rules", len(config.RateLimit.Methods)))
+}
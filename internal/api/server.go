@@ -2,53 +2,174 @@ package api
 
 import (
 	"context"
+	"expvar"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill-redisstream/pkg/redisstream"
 	"github.com/ThreeDotsLabs/watermill/components/cqrs"
 	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/hibiken/asynq"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 	"github.com/samber/oops"
 	httpSwagger "github.com/swaggo/http-swagger"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
 
+	"github.com/danghamo/life/internal/api/grpcapi"
 	"github.com/danghamo/life/internal/api/handlers"
 	"github.com/danghamo/life/internal/api/jsonrpcx"
 	"github.com/danghamo/life/internal/api/middleware"
 	"github.com/danghamo/life/internal/app/service"
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
 	cqrshandlers "github.com/danghamo/life/internal/cqrs/handlers"
+	"github.com/danghamo/life/internal/domain/accessibility"
 	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/battle"
+	"github.com/danghamo/life/internal/domain/bullet"
+	"github.com/danghamo/life/internal/domain/challenge"
+	"github.com/danghamo/life/internal/domain/character"
+	"github.com/danghamo/life/internal/domain/chat"
+	"github.com/danghamo/life/internal/domain/crafting"
+	"github.com/danghamo/life/internal/domain/equipment"
+	"github.com/danghamo/life/internal/domain/fairness"
+	"github.com/danghamo/life/internal/domain/friend"
+	"github.com/danghamo/life/internal/domain/idle"
+	"github.com/danghamo/life/internal/domain/liveops"
+	"github.com/danghamo/life/internal/domain/notification"
+	"github.com/danghamo/life/internal/domain/loot"
+	"github.com/danghamo/life/internal/domain/match"
+	"github.com/danghamo/life/internal/domain/referral"
+	"github.com/danghamo/life/internal/domain/room"
+	"github.com/danghamo/life/internal/domain/session"
+	"github.com/danghamo/life/internal/domain/social"
 	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/tutorial"
+	"github.com/danghamo/life/internal/domain/vault"
+	"github.com/danghamo/life/internal/domain/world"
+	"github.com/danghamo/life/pkg/assets"
 	"github.com/danghamo/life/pkg/autorouter"
+	"github.com/danghamo/life/pkg/captcha"
 	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/fieldcrypt"
+	"github.com/danghamo/life/pkg/mailer"
+	"github.com/danghamo/life/pkg/objstore"
+	"github.com/danghamo/life/pkg/plugin"
 	"github.com/danghamo/life/pkg/redisx"
 	"github.com/danghamo/life/pkg/sse"
+	"github.com/danghamo/life/pkg/tenant"
+	"github.com/danghamo/life/pkg/ws"
+	"github.com/danghamo/life/web"
 )
 
 // Server represents the HTTP server
 type Server struct {
 	httpServer     *http.Server
+	grpcServer     *grpc.Server // nil unless GRPCPort is set
+	grpcAddr       string
 	logger         *logger.Logger
 	redisClient    *redisx.Client
 	mux            *http.ServeMux
+	rpcMethods     *autorouter.Registry // JSON-RPC methods the /api/v1/rpc gateway dispatches
+	tenants        *tenant.Registry
 	trainerHandler *handlers.TrainerHandler
 	animalHandler  *handlers.AnimalHandler
 	worldHandler   *handlers.WorldHandler
 	authHandler    *handlers.AuthHandler
 	serverHandler  *handlers.ServerHandler
+	lootHandler    *handlers.LootHandler
+	craftHandler   *handlers.CraftHandler
+	equipmentHandler *handlers.EquipmentHandler
+	partyHandler   *handlers.PartyHandler
+	vaultHandler   *handlers.VaultHandler
+	inventoryHandler *handlers.InventoryHandler
+	bulletHandler  *handlers.BulletHandler
+	roomHandler    *handlers.RoomHandler
+	matchHandler   *handlers.MatchHandler
+	fairnessHandler *handlers.FairnessHandler
+	battleHandler  *handlers.BattleHandler
+	searchHandler  *handlers.SearchHandler
+	socialHandler  *handlers.SocialHandler
+	friendHandler  *handlers.FriendHandler
+	characterHandler *handlers.CharacterHandler
+	chatHandler    *handlers.ChatHandler
+	notificationHandler *handlers.NotificationHandler
+	accessibilityHandler *handlers.AccessibilityHandler
+	idleHandler          *handlers.IdleHandler
+	adminHandler   *handlers.AdminHandler
+	pluginHandler  *handlers.PluginHandler
+	plugins        *plugin.Hooks
+	referralHandler *handlers.ReferralHandler
+	emailHandler    *handlers.EmailHandler
+	activityHandler *handlers.ActivityHandler
+	accountHandler  *handlers.AccountHandler
+	authSessionHandler *handlers.AuthSessionHandler
+	mfaHandler      *handlers.MFAHandler
+	tutorialHandler *handlers.TutorialHandler
+	challengeHandler *handlers.ChallengeHandler
+	deprecationHandler *handlers.DeprecationHandler
 	authMiddleware *middleware.AuthMiddleware
 	sseBroadcaster    *sse.SSEBroadcaster
-	movementBroadcaster *service.MovementBroadcaster
+	wsHub             *ws.Hub
+	sseFanout         *sse.RedisFanout
+	movementBroadcaster *service.TenantMovement
+	spawnManager        *service.SpawnManager
+	animalAIService     *service.AnimalAIService
+	hitRegistration     *service.HitRegistrationService
+	worldClockService   *service.WorldClockService
+	tenantLoops         []tenantLoop // Background loops of tenants other than the default one
+	socialService       *service.SocialService
+	idleService         *service.IdleService
+	friendService       *service.FriendService
+	challengeService    *service.ChallengeService
+	outboxRelay         *service.OutboxRelay
+	retentionService    *service.RetentionService
+	timeouts            middleware.TimeoutConfig
+	envBanner           string
+	static              *assets.Handler
+	deprecations        *middleware.Deprecations
+	rateLimiter         *middleware.RateLimiter
+	degradation         *middleware.Degradation
+	analyticsExport     *service.AnalyticsExportService
+	eventArchive        *service.EventArchiveService
 	// Watermill CQRS components
 	commandBus       *cqrs.CommandBus
 	eventBus         *cqrs.EventBus
 	commandProcessor *cqrs.CommandProcessor
 	eventProcessor   *cqrs.EventProcessor
 	router          *message.Router
+	consumerName    string // This instance's consumer name in the stream consumer groups
 	sseEventHandler *cqrshandlers.SSEEventHandler
+	// Asynq components for delayed game tasks
+	taskServer *asynq.Server
+	taskMux    *asynq.ServeMux
+	taskRedis  *redis.Client // Unnamespaced connection of the task queue; nil when shared
+	// Warm-up runs before the listener opens; /ready reports unavailable until it finishes
+	warmupSteps   []warmupStep
+	warmupTimeout time.Duration
+	ready         atomic.Bool
+}
+
+// OAuthClients holds the apps registered with the social sign-in providers
+type OAuthClients struct {
+	Google  OAuthClient
+	GitHub  OAuthClient
+	Discord OAuthClient
+}
+
+// OAuthClient is the app registered with one provider; an empty ClientID disables it
+type OAuthClient struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURI  string
 }
 
 // ServerConfig holds server configuration
@@ -58,6 +179,89 @@ type ServerConfig struct {
 	ReadTimeout  time.Duration `json:"read_timeout"`
 	WriteTimeout time.Duration `json:"write_timeout"`
 	IdleTimeout  time.Duration `json:"idle_timeout"`
+	// Timeouts are the context deadlines of JSON-RPC methods; keep them below WriteTimeout
+	Timeouts middleware.TimeoutConfig `json:"timeouts"`
+	// RateLimit caps requests per method, per user or client IP
+	RateLimit middleware.RateLimitConfig `json:"rate_limit"`
+	// Degradation keeps reads and queued writes working while Redis is unreachable
+	Degradation middleware.DegradationConfig `json:"degradation"`
+	// Replay keeps recent notifications per user for SSE clients resuming with a Last-Event-ID
+	Replay sse.ReplayConfig `json:"replay"`
+	// LootDeliveryMode selects whether drops go to inventory or become world pickups
+	LootDeliveryMode string `json:"loot_delivery_mode"`
+	// GRPCPort serves the gRPC API to other backend services; zero disables it
+	GRPCPort int `json:"grpc_port"`
+	// TaskConcurrency is the number of asynq workers processing delayed tasks
+	TaskConcurrency int `json:"task_concurrency"`
+	// Movement shards the movement simulation and sets how often it broadcasts and persists
+	// positions
+	Movement service.MovementConfig `json:"movement"`
+	// InterestChunkSize and InterestRadius control which trainers receive movement updates
+	InterestChunkSize float64 `json:"interest_chunk_size"`
+	InterestRadius    float64 `json:"interest_radius"`
+	// AFKTimeout is how long players may go without input before they are AFK
+	AFKTimeout time.Duration `json:"afk_timeout"`
+	// MapWidth, MapHeight and AnimalSpawnRate shape the world wild animals spawn into
+	MapWidth        int     `json:"map_width"`
+	MapHeight       int     `json:"map_height"`
+	AnimalSpawnRate float64 `json:"animal_spawn_rate"`
+	// DayLength is how long a game day of the day/night cycle lasts in real time
+	DayLength time.Duration `json:"day_length"`
+	// Respawn sets the money dead trainers lose and how long they wait to respawn
+	Respawn service.RespawnConfig `json:"respawn"`
+	// InviteBaseURL is the page referral invitation links point at
+	InviteBaseURL string `json:"invite_base_url"`
+
+	// Mail sends account emails; EmailVerifyURL is the page verification links point at
+	Mail           mailer.Config `json:"mail"`
+	EmailVerifyURL string        `json:"email_verify_url"`
+
+	// Retention says how long event streams and audit logs are kept before being purged
+	Retention service.RetentionPolicy `json:"retention"`
+
+	// Analytics exports pseudonymized game events to the configured sink streams
+	Analytics service.AnalyticsExportPolicy `json:"analytics"`
+
+	// Archive drains game event streams into ArchiveStore; an empty store kind disables it
+	Archive      service.EventArchivePolicy `json:"archive"`
+	ArchiveStore objstore.Config            `json:"-"`
+
+	// JWTSecret signs the access tokens, which are valid for JWTExpiration
+	JWTSecret     string        `json:"-"`
+	JWTExpiration time.Duration `json:"jwt_expiration"`
+	// OAuth holds the apps registered with the social sign-in providers
+	OAuth OAuthClients `json:"-"`
+	// Captcha is the bot protection new guests and social sign-ups solve; no provider
+	// disables it
+	Captcha captcha.Config `json:"-"`
+
+	// EnvBanner is sent in the X-Env header of every response; empty omits it
+	EnvBanner string `json:"env_banner"`
+	// StaticDir overrides the embedded browser client with a directory reread per request
+	StaticDir string `json:"static_dir"`
+	// Deprecations lists methods scheduled for removal, announced in response headers
+	Deprecations *middleware.Deprecations `json:"-"`
+	// AdminUserIDs are the players whose tokens carry the admin role of the /admin/v1/ API
+	AdminUserIDs []string `json:"-"`
+	// ModeratorUserIDs are the players whose tokens carry the moderator role, allowed the
+	// moderation methods of the /admin/v1/ API
+	ModeratorUserIDs []string `json:"-"`
+	// SingleSession makes a sign-in revoke the user's other sessions
+	SingleSession bool `json:"single_session"`
+
+	// WarmupTimeout bounds the warm-up before the listener opens; zero uses 30 seconds
+	WarmupTimeout time.Duration `json:"warmup_timeout"`
+
+	// PIIEncryption encrypts emails and device IDs at rest; empty keys store them in plaintext
+	PIIEncryption fieldcrypt.Config `json:"-"`
+
+	// Branding is how the default game presents itself, returned by server.Info
+	Branding tenant.Branding `json:"branding"`
+	// Tenants are further games served on their own hosts, each with its own Redis keys
+	Tenants []tenant.Tenant `json:"tenants"`
+
+	// Postgres stores accounts, the account audit log and the loot ledger; nil keeps them in Redis
+	Postgres *pgxpool.Pool `json:"-"`
 }
 
 // NewServer creates a new HTTP server
@@ -65,44 +269,97 @@ func NewServer(config ServerConfig, logger *logger.Logger, redisClient *redisx.C
 	mux := http.NewServeMux()
 	apiLogger := logger.WithComponent("api")
 
+	// Gameplay modules compiled into the binary register their hooks before services use them
+	plugins, err := plugin.Load(apiLogger, plugin.Modules())
+	if err != nil {
+		return nil, oops.With("component", "plugins").With("operation", "load_plugins").Hint("Failed to set up a gameplay plugin").Wrap(err)
+	}
+
+	piiCipher, err := fieldcrypt.New(config.PIIEncryption)
+	if err != nil {
+		return nil, oops.With("component", "pii_cipher").With("operation", "create_cipher").Hint("Failed to create PII cipher, check CRYPTO_PII_KEYS and CRYPTO_PII_INDEX_KEY").Wrap(err)
+	}
+
+	botCheck, err := captcha.New(config.Captcha)
+	if err != nil {
+		return nil, oops.With("component", "captcha").With("operation", "create_verifier").Hint("Invalid bot protection, check AUTH_CAPTCHA_PROVIDER and auth.captcha.secret").Wrap(err)
+	}
+
+	// Tenants inherit the default game's branding and balance settings they don't override
+	tenants, err := tenant.NewRegistry(tenant.Tenant{
+		Branding: config.Branding,
+		Balance: tenant.Balance{
+			LootDeliveryMode: config.LootDeliveryMode,
+			InviteBaseURL:    config.InviteBaseURL,
+		},
+	}, config.Tenants)
+	if err != nil {
+		return nil, oops.With("component", "tenants").With("operation", "create_registry").Hint("Invalid tenants, check tenants in config.yaml").Wrap(err)
+	}
+
+	// Commands run for a tenant or in a room use their own keys; installed before anything
+	// touches Redis, and even for a single tenant since rooms rely on it
+	var tenantIDs []tenant.ID
+	for _, t := range tenants.List() {
+		tenantIDs = append(tenantIDs, t.ID)
+	}
+	redisClient.AddHook(redisx.Namespace(tenantIDs...))
+
 	// Create repositories
-	trainerRepo := trainer.NewRedisRepository(redisClient.Client)
-	accountRepo := account.NewRedisRepository(redisClient.Client)
+	// Trainer positions are stored as snapshots the movement simulation persists on its own
+	positionRepo := trainer.NewRedisPositionRepository(redisClient.Client)
+	trainerRepo := trainer.NewPositionedRepository(trainer.NewRedisRepository(redisClient.Client), positionRepo)
+	accountRepo := account.NewRedisRepository(redisClient.Client, piiCipher)
+	activityRepo := account.NewRedisActivityRepository(redisClient.Client)
+	var lootLedger loot.LedgerRepository // The loot event stream is the only ledger in Redis
+	if config.Postgres != nil {
+		accountRepo = account.NewPostgresRepository(config.Postgres, piiCipher)
+		activityRepo = account.NewPostgresActivityRepository(config.Postgres)
+		lootLedger = loot.NewPostgresLedgerRepository(config.Postgres)
+	}
+	animalRepo := animal.NewRedisRepository(redisClient.Client)
+	spawnTableRepo := animal.NewRedisSpawnTableRepository(redisClient.Client)
+	clockRepo := world.NewRedisClockRepository(redisClient.Client)
+	pickupRepo := loot.NewRedisRepository(redisClient.Client)
+	equipmentRepo := equipment.NewRedisRepository(redisClient.Client)
+	craftingRepo := crafting.NewRedisRepository(redisClient.Client)
+	vaultRepo := vault.NewRedisRepository(redisClient.Client)
+	bulletRepo := bullet.NewRedisRepository(redisClient.Client)
+	bulletStatsRepo := bullet.NewRedisPlayerStatsRepository(redisClient.Client)
+	battleRepo := battle.NewRedisRepository(redisClient.Client)
 
 	// Create JWT service
-	jwtService := account.NewJWTService(
-		"your-secret-key-here", // TODO: Move to config
-		"life-game-server",
-		24*time.Hour, // Token expires in 24 hours
-	)
+	jwtService := account.NewJWTService(config.JWTSecret, "life-game-server", config.JWTExpiration)
+	jwtService.SetRoles(config.AdminUserIDs, config.ModeratorUserIDs)
 
-	// Create auth middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtService, apiLogger)
+	// Revocations only need to outlive the tokens they invalidate
+	revocationRepo := account.NewRedisRevocationRepository(redisClient.Client, config.JWTExpiration)
+	authSessionRepo := account.NewRedisSessionRepository(redisClient.Client)
 
-	// Create OAuth configuration (TODO: move to config file)
+	// Create OAuth configuration; providers without a client ID are disabled
 	oauthConfig := handlers.OAuthConfig{
 		Google: handlers.ProviderConfig{
-			ClientID:     "your-google-client-id",
-			ClientSecret: "your-google-client-secret",
-			RedirectURI:  "http://localhost:8080/auth/google/callback",
+			ClientID:     config.OAuth.Google.ClientID,
+			ClientSecret: config.OAuth.Google.ClientSecret,
+			RedirectURI:  config.OAuth.Google.RedirectURI,
 			AuthURL:      "https://accounts.google.com/o/oauth2/v2/auth",
 			TokenURL:     "https://oauth2.googleapis.com/token",
 			UserInfoURL:  "https://www.googleapis.com/oauth2/v2/userinfo",
 			Scopes:       "openid profile email",
 		},
 		GitHub: handlers.ProviderConfig{
-			ClientID:     "your-github-client-id",
-			ClientSecret: "your-github-client-secret",
-			RedirectURI:  "http://localhost:8080/auth/github/callback",
+			ClientID:     config.OAuth.GitHub.ClientID,
+			ClientSecret: config.OAuth.GitHub.ClientSecret,
+			RedirectURI:  config.OAuth.GitHub.RedirectURI,
 			AuthURL:      "https://github.com/login/oauth/authorize",
 			TokenURL:     "https://github.com/login/oauth/access_token",
 			UserInfoURL:  "https://api.github.com/user",
 			Scopes:       "user:email",
 		},
 		Discord: handlers.ProviderConfig{
-			ClientID:     "your-discord-client-id",
-			ClientSecret: "your-discord-client-secret",
-			RedirectURI:  "http://localhost:8080/auth/discord/callback",
+			ClientID:     config.OAuth.Discord.ClientID,
+			ClientSecret: config.OAuth.Discord.ClientSecret,
+			RedirectURI:  config.OAuth.Discord.RedirectURI,
 			AuthURL:      "https://discord.com/api/oauth2/authorize",
 			TokenURL:     "https://discord.com/api/oauth2/token",
 			UserInfoURL:  "https://discord.com/api/users/@me",
@@ -120,6 +377,13 @@ func NewServer(config ServerConfig, logger *logger.Logger, redisClient *redisx.C
 	// Create Watermill logger
 	watermillLogger := watermill.NewStdLogger(false, false)
 
+	// Events on the bus carry versioned contracts; a build changing one incompatibly without
+	// bumping its version refuses to start
+	eventSchemas := cqrscommands.DefaultSchemas()
+	if err := eventSchemas.CheckLocked(); err != nil {
+		return nil, oops.With("component", "event_schemas").With("operation", "check_contracts").Hint("Event contract changed incompatibly, bump the event's version and add an upcaster").Wrap(err)
+	}
+
 	// Create Redis publisher and subscriber
 	publisher, err := redisstream.NewPublisher(
 		redisstream.PublisherConfig{
@@ -134,7 +398,10 @@ func NewServer(config ServerConfig, logger *logger.Logger, redisClient *redisx.C
 	subscriber, err := redisstream.NewSubscriber(
 		redisstream.SubscriberConfig{
 			Client:        redisClient.Client,
-			ConsumerGroup: fmt.Sprintf("game-server-%s", serverID),
+			// Shared group: each event is handled once across the cluster; notifications
+			// then reach every instance through the Redis fan-out
+			ConsumerGroup: sharedConsumerGroup,
+			Consumer:      serverID,
 		},
 		watermillLogger,
 	)
@@ -142,6 +409,9 @@ func NewServer(config ServerConfig, logger *logger.Logger, redisClient *redisx.C
 		return nil, oops.With("component", "subscriber").With("operation", "create_subscriber").Hint("Failed to create Redis stream subscriber").Wrap(err)
 	}
 
+	// Handle every tenant's events, each in its own tenant
+	tenantSubscriber := cqrscommands.NewTenantSubscriber(subscriber, tenants.List())
+
 	// Create message router with short close timeout
 	router, err := message.NewRouter(message.RouterConfig{
 		CloseTimeout: 5 * time.Second, // Short timeout for graceful shutdown
@@ -150,6 +420,9 @@ func NewServer(config ServerConfig, logger *logger.Logger, redisClient *redisx.C
 		return nil, oops.With("component", "router").With("operation", "create_router").Hint("Failed to create message router").Wrap(err)
 	}
 
+	// Handle every message with the ID of the request that published it
+	router.AddMiddleware(cqrscommands.RequestIDMiddleware)
+
 	// Create command bus
 	commandBus, err := cqrs.NewCommandBusWithConfig(
 		publisher,
@@ -157,7 +430,8 @@ func NewServer(config ServerConfig, logger *logger.Logger, redisClient *redisx.C
 			GeneratePublishTopic: func(params cqrs.CommandBusGeneratePublishTopicParams) (string, error) {
 				return fmt.Sprintf("game-commands.%s", params.CommandName), nil
 			},
-			Marshaler: cqrs.JSONMarshaler{},
+			OnSend:    cqrscommands.CommandRequestID,
+			Marshaler: cqrscommands.JSONMarshaler{Schemas: eventSchemas},
 			Logger:    watermillLogger,
 		},
 	)
@@ -172,7 +446,8 @@ func NewServer(config ServerConfig, logger *logger.Logger, redisClient *redisx.C
 			GeneratePublishTopic: func(params cqrs.GenerateEventPublishTopicParams) (string, error) {
 				return fmt.Sprintf("game-events.%s", params.EventName), nil
 			},
-			Marshaler: cqrs.JSONMarshaler{},
+			OnPublish: cqrscommands.EventRequestID,
+			Marshaler: cqrscommands.JSONMarshaler{Schemas: eventSchemas},
 			Logger:    watermillLogger,
 		},
 	)
@@ -180,6 +455,12 @@ func NewServer(config ServerConfig, logger *logger.Logger, redisClient *redisx.C
 		return nil, oops.With("component", "event_bus").With("operation", "create_event_bus").Hint("Failed to create CQRS event bus").Wrap(err)
 	}
 
+	// Events that must not be lost go through the outbox, which the relays publish to the bus
+	outbox := cqrscommands.NewOutbox(redisClient.Client, cqrscommands.JSONMarshaler{Schemas: eventSchemas}, func(eventName string) string {
+		return fmt.Sprintf("game-events.%s", eventName)
+	})
+	outboxRelay := service.NewOutboxRelay(apiLogger, redisClient.Client, publisher, serverID)
+
 	// Create command processor
 	commandProcessor, err := cqrs.NewCommandProcessorWithConfig(
 		router,
@@ -188,9 +469,9 @@ func NewServer(config ServerConfig, logger *logger.Logger, redisClient *redisx.C
 				return fmt.Sprintf("game-commands.%s", params.CommandName), nil
 			},
 			SubscriberConstructor: func(params cqrs.CommandProcessorSubscriberConstructorParams) (message.Subscriber, error) {
-				return subscriber, nil
+				return tenantSubscriber, nil
 			},
-			Marshaler: cqrs.JSONMarshaler{},
+			Marshaler: cqrscommands.JSONMarshaler{Schemas: eventSchemas},
 			Logger:    watermillLogger,
 		},
 	)
@@ -206,9 +487,9 @@ func NewServer(config ServerConfig, logger *logger.Logger, redisClient *redisx.C
 				return fmt.Sprintf("game-events.%s", params.EventName), nil
 			},
 			SubscriberConstructor: func(params cqrs.EventProcessorSubscriberConstructorParams) (message.Subscriber, error) {
-				return subscriber, nil
+				return tenantSubscriber, nil
 			},
-			Marshaler: cqrs.JSONMarshaler{},
+			Marshaler: cqrscommands.JSONMarshaler{Schemas: eventSchemas},
 			Logger:    watermillLogger,
 		},
 	)
@@ -216,15 +497,490 @@ func NewServer(config ServerConfig, logger *logger.Logger, redisClient *redisx.C
 		return nil, oops.With("component", "event_processor").With("operation", "create_event_processor").Hint("Failed to create CQRS event processor").Wrap(err)
 	}
 
-	// Create SSE broadcaster
+	// Create a processor for events every instance must handle, reading the streams without
+	// a consumer group so each instance receives every event
+	instanceSubscriber, err := redisstream.NewSubscriber(
+		redisstream.SubscriberConfig{
+			Client: redisClient.Client,
+		},
+		watermillLogger,
+	)
+	if err != nil {
+		return nil, oops.With("component", "subscriber").With("operation", "create_instance_subscriber").Hint("Failed to create Redis stream fan-out subscriber").Wrap(err)
+	}
+
+	instanceEventProcessor, err := cqrs.NewEventProcessorWithConfig(
+		router,
+		cqrs.EventProcessorConfig{
+			GenerateSubscribeTopic: func(params cqrs.EventProcessorGenerateSubscribeTopicParams) (string, error) {
+				return fmt.Sprintf("game-events.%s", params.EventName), nil
+			},
+			SubscriberConstructor: func(params cqrs.EventProcessorSubscriberConstructorParams) (message.Subscriber, error) {
+				return instanceSubscriber, nil
+			},
+			Marshaler: cqrscommands.JSONMarshaler{Schemas: eventSchemas},
+			Logger:    watermillLogger,
+		},
+	)
+	if err != nil {
+		return nil, oops.With("component", "event_processor").With("operation", "create_instance_event_processor").Hint("Failed to create CQRS fan-out event processor").Wrap(err)
+	}
+
+	// Create a processor for events counted toward challenges. It reads in a consumer group of
+	// its own, so each event is counted once across the cluster and still reaches the SSE
+	// handlers of the shared group.
+	challengeSubscriber, err := redisstream.NewSubscriber(
+		redisstream.SubscriberConfig{
+			Client:        redisClient.Client,
+			ConsumerGroup: "challenge-progress",
+			Consumer:      serverID,
+		},
+		watermillLogger,
+	)
+	if err != nil {
+		return nil, oops.With("component", "subscriber").With("operation", "create_challenge_subscriber").Hint("Failed to create Redis stream challenge subscriber").Wrap(err)
+	}
+	challengeTenantSubscriber := cqrscommands.NewTenantSubscriber(challengeSubscriber, tenants.List())
+
+	challengeEventProcessor, err := cqrs.NewEventProcessorWithConfig(
+		router,
+		cqrs.EventProcessorConfig{
+			GenerateSubscribeTopic: func(params cqrs.EventProcessorGenerateSubscribeTopicParams) (string, error) {
+				return fmt.Sprintf("game-events.%s", params.EventName), nil
+			},
+			SubscriberConstructor: func(params cqrs.EventProcessorSubscriberConstructorParams) (message.Subscriber, error) {
+				return challengeTenantSubscriber, nil
+			},
+			Marshaler: cqrscommands.JSONMarshaler{Schemas: eventSchemas},
+			Logger:    watermillLogger,
+		},
+	)
+	if err != nil {
+		return nil, oops.With("component", "event_processor").With("operation", "create_challenge_event_processor").Hint("Failed to create CQRS challenge event processor").Wrap(err)
+	}
+
+	// Create a processor for tracking entities across world chunks, in a consumer group of its
+	// own so it sees the events the SSE handlers of the shared group handle too
+	chunkSubscriber, err := redisstream.NewSubscriber(
+		redisstream.SubscriberConfig{
+			Client:        redisClient.Client,
+			ConsumerGroup: "chunk-stream",
+			Consumer:      serverID,
+		},
+		watermillLogger,
+	)
+	if err != nil {
+		return nil, oops.With("component", "subscriber").With("operation", "create_chunk_subscriber").Hint("Failed to create Redis stream chunk subscriber").Wrap(err)
+	}
+	chunkTenantSubscriber := cqrscommands.NewTenantSubscriber(chunkSubscriber, tenants.List())
+
+	chunkEventProcessor, err := cqrs.NewEventProcessorWithConfig(
+		router,
+		cqrs.EventProcessorConfig{
+			GenerateSubscribeTopic: func(params cqrs.EventProcessorGenerateSubscribeTopicParams) (string, error) {
+				return fmt.Sprintf("game-events.%s", params.EventName), nil
+			},
+			SubscriberConstructor: func(params cqrs.EventProcessorSubscriberConstructorParams) (message.Subscriber, error) {
+				return chunkTenantSubscriber, nil
+			},
+			Marshaler: cqrscommands.JSONMarshaler{Schemas: eventSchemas},
+			Logger:    watermillLogger,
+		},
+	)
+	if err != nil {
+		return nil, oops.With("component", "event_processor").With("operation", "create_chunk_event_processor").Hint("Failed to create CQRS chunk event processor").Wrap(err)
+	}
+
+	// Create a processor for kills counted toward matches, in a consumer group of its own so
+	// each kill is counted once across the cluster
+	matchSubscriber, err := redisstream.NewSubscriber(
+		redisstream.SubscriberConfig{
+			Client:        redisClient.Client,
+			ConsumerGroup: "match-scoring",
+			Consumer:      serverID,
+		},
+		watermillLogger,
+	)
+	if err != nil {
+		return nil, oops.With("component", "subscriber").With("operation", "create_match_subscriber").Hint("Failed to create Redis stream match subscriber").Wrap(err)
+	}
+	matchTenantSubscriber := cqrscommands.NewTenantSubscriber(matchSubscriber, tenants.List())
+
+	matchEventProcessor, err := cqrs.NewEventProcessorWithConfig(
+		router,
+		cqrs.EventProcessorConfig{
+			GenerateSubscribeTopic: func(params cqrs.EventProcessorGenerateSubscribeTopicParams) (string, error) {
+				return fmt.Sprintf("game-events.%s", params.EventName), nil
+			},
+			SubscriberConstructor: func(params cqrs.EventProcessorSubscriberConstructorParams) (message.Subscriber, error) {
+				return matchTenantSubscriber, nil
+			},
+			Marshaler: cqrscommands.JSONMarshaler{Schemas: eventSchemas},
+			Logger:    watermillLogger,
+		},
+	)
+	if err != nil {
+		return nil, oops.With("component", "event_processor").With("operation", "create_match_event_processor").Hint("Failed to create CQRS match event processor").Wrap(err)
+	}
+
+	// Create SSE broadcaster; reconnecting clients catch up from the replay buffer
 	sseBroadcaster := sse.NewSSEBroadcaster(apiLogger)
+	replayBuffer := sse.NewReplayBuffer(redisClient.Client, config.Replay)
+	sseBroadcaster.SetReplayBuffer(replayBuffer)
 
-	// Create movement broadcaster with Redis client
-	movementBroadcaster := service.NewMovementBroadcaster(apiLogger, trainerRepo, eventBus, redisClient.Client)
+	// Create WebSocket hub for bidirectional clients
+	wsHub := ws.NewHub(apiLogger)
+
+	// Fan notifications out to clients on every server instance
+	localBroadcaster := cqrshandlers.NewMultiBroadcaster(sseBroadcaster, wsHub)
+	sseFanout := sse.NewRedisFanout(apiLogger, redisClient.Client, localBroadcaster, replayBuffer)
+
+	// Keep serving while Redis is down; each instance tells its own clients
+	degradation, err := middleware.NewDegradation(apiLogger, config.Degradation, func(ctx context.Context) error {
+		return redisClient.Ping(ctx).Err()
+	}, localBroadcaster)
+	if err != nil {
+		return nil, oops.With("component", "degradation").With("operation", "open_wal").Hint("Failed to open the degraded mode WAL, check DEGRADED_WAL_PATH").Wrap(err)
+	}
+
+	// Create auth middleware
+	authMiddleware := middleware.NewAuthMiddleware(jwtService, revocationRepo, authSessionRepo, degradation, tenants, apiLogger)
+
+	// Load the game world shared by movement collision and animal spawning; operators edit
+	// the stored world with lifectl and every server reloads the chunks that changed
+	worldRepo := world.NewRedisRepository(redisClient.Client)
+	gameWorld, err := service.LoadWorld(context.Background(), worldRepo, config.MapWidth, config.MapHeight)
+	if err != nil {
+		return nil, oops.With("component", "world").With("operation", "load_world").Hint("Failed to load game world").Wrap(err)
+	}
+	if width, height := gameWorld.Dimensions(); width != config.MapWidth || height != config.MapHeight {
+		apiLogger.Warn("Stored world size differs from the configured map size",
+			zap.Int("width", width),
+			zap.Int("height", height))
+	}
+
+	// Create a movement simulation per tenant, so trainers only meet trainers of their game
+	movementBroadcaster := service.NewTenantMovement(tenants.List(), func() *service.MovementBroadcaster {
+		return service.NewMovementBroadcaster(apiLogger, trainerRepo, positionRepo, eventBus, redisClient.Client, gameWorld, config.Movement)
+	})
+
+	// Tell clients about their connection in move responses and heartbeats
+	latencyService := service.NewLatencyService(apiLogger, movementBroadcaster)
+	heartbeatLatency := func(userID string) any {
+		return latencyService.Report(userID)
+	}
+	sseBroadcaster.SetHeartbeatLatency(heartbeatLatency)
+	wsHub.SetHeartbeatLatency(heartbeatLatency)
+
+	// Create interest manager so movement updates only reach nearby trainers
+	interestManager := service.NewInterestManager(apiLogger, redisClient.Client, config.InterestChunkSize, config.InterestRadius)
+
+	// Players without input for a while go AFK until they come back
+	idleRepo := idle.NewRedisRepository(redisClient.Client)
+	idleService := service.NewIdleService(apiLogger, idleRepo, interestManager, movementBroadcaster, cqrscommands.NewSSEBroadcastHelper(eventBus), redisClient.Client, config.AFKTimeout)
+
+	// Notification preferences are enforced by the gateway every notification goes through
+	notificationRepo := notification.NewRedisRepository(redisClient.Client)
+	notificationService := service.NewNotificationService(apiLogger, notificationRepo)
+
+	// Accessibility settings are honored by position broadcasts, hit checks and trainer colors
+	accessibilityRepo := accessibility.NewRedisRepository(redisClient.Client)
+
+	// Create friend service; players are online while connected or moving
+	friendRepo := friend.NewRedisRepository(redisClient.Client)
+	presenceRepo := friend.NewRedisPresenceRepository(redisClient.Client)
+	friendService := service.NewFriendService(apiLogger, friendRepo, presenceRepo, trainerRepo, cqrscommands.NewSSEBroadcastHelper(eventBus))
+
+	// Remember where players left off, to sum up what changed when they come back
+	sessionRepo := session.NewRedisRepository(redisClient.Client)
+	sessionService := service.NewSessionService(apiLogger, sessionRepo, trainerRepo, craftingRepo, friendRepo, cqrscommands.NewSSEBroadcastHelper(eventBus))
+
+	// Announce a user to their friends once they have a connection
+	login := func(userID string) {
+		friendService.Connected(context.Background(), userID)
+		sessionService.Started(context.Background(), userID)
+		idleService.Active(context.Background(), userID)
+	}
+	sseBroadcaster.OnConnect(login)
+	wsHub.OnConnect(login)
+
+	// Check movement commands against the trainers' speed and the client debounce
+	movementValidator := service.NewMovementValidator(apiLogger, service.DefaultMovementRules(), trainer.NewRedisCheckpointRepository(redisClient.Client))
+
+	// Persist and drop a trainer's simulated position once the user has no connection left
+	logout := func(userID string) {
+		if !sseBroadcaster.IsConnected(userID) && !wsHub.IsConnected(userID) {
+			movementBroadcaster.Logout(context.Background(), userID)
+			movementValidator.Forget(context.Background(), userID)
+			latencyService.Forget(userID)
+			idleService.Forget(context.Background(), userID)
+			friendService.Disconnected(context.Background(), userID)
+			sessionService.Ended(context.Background(), userID)
+		}
+	}
+	sseBroadcaster.OnDisconnect(logout)
+	wsHub.OnDisconnect(logout)
+
+	// Create randomness service shared by every game roll
+	fairnessRepo := fairness.NewRedisRepository(redisClient.Client)
+	randomnessService := service.NewRandomnessService(apiLogger, fairnessRepo)
+
+	// Create loot service for animal drops
+	lootService := service.NewLootService(
+		apiLogger,
+		loot.NewDefaultRegistry(),
+		loot.DeliveryMode(config.LootDeliveryMode),
+		trainerRepo,
+		pickupRepo,
+		lootLedger,
+		eventBus,
+		randomnessService,
+	)
+
+	// Create asynq client and worker sharing the game Redis connection. With tenants the queue
+	// gets its own connection, as its keys are shared; tenants' task types carry their prefix.
+	taskRedis := redisClient.Client
+	var ownTaskRedis *redis.Client
+	if len(tenantIDs) > 1 {
+		ownTaskRedis = redis.NewClient(redisClient.Options())
+		taskRedis = ownTaskRedis
+	}
+	taskClient := asynq.NewClientFromRedisClient(taskRedis)
+	taskServer := asynq.NewServerFromRedisClient(taskRedis, asynq.Config{
+		Concurrency: config.TaskConcurrency,
+	})
+	taskMux := asynq.NewServeMux()
+	taskMux.Handle(tenantTaskPrefix, tenantTasks(tenants, taskMux))
+
+	// Let users play several trainers; sign-ins resume the character last selected
+	characterRepo := character.NewRedisRepository(redisClient.Client)
+	characterService := service.NewCharacterService(apiLogger, characterRepo, trainerRepo, sseFanout, taskClient, plugins, accessibilityRepo)
+	accessibilityService := service.NewAccessibilityService(apiLogger, accessibilityRepo, characterRepo, trainerRepo)
+	jwtService.SetCharacters(characterService.Active)
+
+	// Register the sessions tokens are issued for; in single-session mode a sign-in revokes the others
+	authSessionService := service.NewAuthSessionService(apiLogger, authSessionRepo, characterRepo, sseFanout, config.SingleSession)
+	jwtService.SetSessions(authSessionService)
+
+	// Create crafting service for timed recipes
+	recipes := crafting.NewDefaultRegistry()
+	craftingService := service.NewCraftingService(
+		apiLogger,
+		recipes,
+		craftingRepo,
+		trainerRepo,
+		equipmentRepo,
+		taskClient,
+		eventBus,
+		randomnessService,
+	)
+	taskMux.HandleFunc(service.TypeCraftComplete, craftingService.HandleCraftCompleteTask)
+
+	// Create equipment service for crafting gear and equipping animals
+	equipmentService := service.NewEquipmentService(apiLogger, recipes, craftingService, equipmentRepo, animalRepo)
+
+	// Create vault service for account storage at bases
+	vaultService := service.NewVaultService(apiLogger, vault.DefaultLocations(), vaultRepo, trainerRepo, randomnessService)
+
+	// Create respawn service for trainer deaths; dead trainers come back at the bases
+	respawnService := service.NewRespawnService(apiLogger, config.Respawn, vault.DefaultLocations(), trainerRepo, movementBroadcaster, movementValidator, vaultService, gameWorld, taskClient, eventBus)
+	taskMux.HandleFunc(service.TypeTrainerRespawn, respawnService.HandleRespawnTask)
+
+	// Create ammo service for reloads and ammo purchases
+	ammoService := service.NewAmmoService(apiLogger, trainerRepo, bulletStatsRepo, eventBus)
+
+	// Create room service; bullets fired in a room are kept under the room's instance
+	roomRepo := room.NewRedisRepository(redisClient.Client)
+	roomService := service.NewRoomService(apiLogger, redisClient.Client, roomRepo, eventBus)
+
+	// Create match service for FPS matches in rooms, ended by a task when their time is up
+	matchRepo := match.NewRedisRepository(redisClient.Client)
+	matchService := service.NewMatchService(apiLogger, matchRepo, roomRepo, taskClient, eventBus)
+	taskMux.HandleFunc(service.TypeMatchEnd, matchService.HandleMatchEndTask)
+
+	// Create hit registration checking bullets against trainers, rewound by the shooter's lag
+	hitRegistration := service.NewHitRegistrationService(apiLogger, movementBroadcaster, latencyService, interestManager, positionRepo, bulletRepo, respawnService, eventBus)
+
+	// Create consumable service for item effects
+	consumableService := service.NewConsumableService(apiLogger, trainerRepo, animalRepo, eventBus, outbox)
+	profileService := service.NewProfileService(apiLogger, trainerRepo, animalRepo)
+	captureService := service.NewCaptureService(apiLogger, trainerRepo, animalRepo, clockRepo, randomnessService, eventBus, plugins)
+
+	// Create party service for moving animals between the party and storage
+	partyService := service.NewPartyService(apiLogger, trainerRepo, animalRepo)
+	battleService := service.NewBattleService(apiLogger, battleRepo, trainerRepo, animalRepo, lootService, randomnessService, eventBus)
+
+	// Create search service over the RediSearch indexes
+	trainerSearchIndex := trainer.NewSearchIndex(trainerRepo)
+	searchService := service.NewSearchService(apiLogger, trainerSearchIndex)
+
+	// Create social service to remember players who met in the same interest chunk
+	socialRepo := social.NewRedisRepository(redisClient.Client)
+	socialService := service.NewSocialService(apiLogger, socialRepo, trainerRepo, interestManager, redisClient.Client)
+
+	// Create world service reloading edited chunks and applying admin edits
+	worldService := service.NewWorldService(apiLogger, worldRepo, gameWorld, interestManager, eventBus, cqrscommands.NewSSEBroadcastHelper(eventBus))
+
+	// Create chunk stream service letting clients stream the world chunk by chunk
+	chunkStreamService := service.NewChunkStreamService(apiLogger, redisClient.Client, gameWorld, cqrscommands.NewSSEBroadcastHelper(eventBus))
+
+	// Create chat service delivering messages through the event bus
+	chatService := service.NewChatService(apiLogger, chat.NewRedisFloodRepository(redisClient.Client), trainerRepo, positionRepo, interestManager, eventBus)
+
+	// Create referral service for invitation links and milestone rewards
+	referralRepo := referral.NewRedisRepository(redisClient.Client)
+	referralService := service.NewReferralService(apiLogger, referralRepo, trainerRepo, config.InviteBaseURL)
+
+	// Create activity service for the account activity timeline
+	activityService := service.NewActivityService(apiLogger, activityRepo)
+
+	// Create email service for address verification
+	emailRepo := account.NewRedisEmailRepository(redisClient.Client, piiCipher)
+	accountMailer := mailer.New(config.Mail, apiLogger)
+	emailService := service.NewEmailService(apiLogger, emailRepo, accountMailer, config.EmailVerifyURL, activityService)
+
+	// Create inventory service for queries and bulk actions; giving items needs a verified email
+	inventoryService := service.NewInventoryService(apiLogger, trainerRepo, vaultRepo, vaultService, emailService, eventBus)
+
+	// Create login guard for step-up verification of suspicious logins
+	loginRepo := account.NewRedisLoginRepository(redisClient.Client)
+	loginGuard := service.NewLoginGuardService(apiLogger, accountRepo, loginRepo, emailRepo, accountMailer, cqrscommands.NewSSEBroadcastHelper(eventBus), activityService)
+
+	// Create analytics export so events reach analytics sinks without raw user identifiers
+	analyticsExport, err := service.NewAnalyticsExportService(apiLogger, redisClient.Client, config.Analytics)
+	if err != nil {
+		return nil, oops.With("component", "analytics_export").With("operation", "create_service").Hint("Failed to create analytics export, check ANALYTICS_PSEUDONYM_SECRET and analytics.sinks").Wrap(err)
+	}
+
+	// Create event archive so events outlive the Redis streams in object storage
+	var archiveStore objstore.Store
+	if config.ArchiveStore.Kind != "" {
+		archiveStore, err = objstore.New(config.ArchiveStore)
+		if err != nil {
+			return nil, oops.With("component", "event_archive").With("operation", "create_store").Hint("Failed to create archive store, check ARCHIVE_STORE and the ARCHIVE_S3_* settings").Wrap(err)
+		}
+	}
+	eventArchive := service.NewEventArchiveService(apiLogger, redisClient.Client, archiveStore, config.Archive)
+
+	// Create account deletion service; the purge of game data runs as a retried task
+	pairingRepo := account.NewRedisPairingRepository(redisClient.Client)
+	tutorialRepo := tutorial.NewRedisRepository(redisClient.Client)
+	challengeRepo := challenge.NewRedisRepository(redisClient.Client)
+	accountDeletionService := service.NewAccountDeletionService(apiLogger, service.UserDataRepositories{
+		Accounts:    accountRepo,
+		Revocations: revocationRepo,
+		Emails:      emailRepo,
+		Logins:      loginRepo,
+		Activities:  activityRepo,
+		Pairings:    pairingRepo,
+		Trainers:    trainerRepo,
+		Animals:     animalRepo,
+		Equipment:   equipmentRepo,
+		Crafting:    craftingRepo,
+		Vaults:      vaultRepo,
+		BulletStats: bulletStatsRepo,
+		Social:      socialRepo,
+		Friends:     friendRepo,
+		Referrals:   referralRepo,
+		Fairness:    fairnessRepo,
+		LootLedger:  lootLedger,
+		Notifications: notificationRepo,
+		Sessions: sessionRepo,
+		Characters: characterRepo,
+		AuthSessions: authSessionRepo,
+		Tutorials: tutorialRepo,
+		Accessibility: accessibilityRepo,
+		Challenges: challengeRepo,
+		Matches: matchRepo,
+	}, taskClient)
+	taskMux.HandleFunc(service.TypeAccountPurge, accountDeletionService.HandleAccountPurgeTask)
+	taskMux.HandleFunc(service.TypeCharacterPurge, accountDeletionService.HandleCharacterPurgeTask)
+
+	// Create account merge service joining a guest with the user owning the social account it links
+	accountMergeService := service.NewAccountMergeService(apiLogger, service.MergeRepositories{
+		Accounts:   accountRepo,
+		Merges:     account.NewRedisMergeRepository(redisClient.Client, piiCipher),
+		Trainers:   trainerRepo,
+		Animals:    animalRepo,
+		Vaults:     vaultRepo,
+		Characters: characterRepo,
+	}, accountDeletionService, activityService)
+
+	// Create spawn manager for wild animals on the game map terrain
+	spawnManager := service.NewSpawnManager(
+		apiLogger,
+		animalRepo,
+		spawnTableRepo,
+		clockRepo,
+		gameWorld,
+		animal.DefaultSpawnConfig(config.AnimalSpawnRate),
+		randomnessService,
+		eventBus,
+		redisClient.Client,
+	)
+
+	// Create animal AI service moving the wild animals around their spawn areas
+	animalAIService := service.NewAnimalAIService(apiLogger, animalRepo, battleRepo, positionRepo, interestManager, respawnService, gameWorld, animal.DefaultSpawnConfig(config.AnimalSpawnRate), eventBus, redisClient.Client)
+
+	// Create world clock service cycling day and night and the weather
+	worldClockService := service.NewWorldClockService(apiLogger, clockRepo, eventBus, redisClient.Client, config.DayLength)
+
+	// Create tutorial service walking new players through the mechanics in practice ranges
+	tutorialService := service.NewTutorialService(apiLogger, tutorialRepo, trainerRepo, gameWorld, spawnManager, cqrscommands.NewSSEBroadcastHelper(eventBus), accessibilityService)
+
+	// Create challenge service rotating the weekly challenges and counting progress from events
+	challenges := challenge.NewDefaultRegistry()
+	challengeService := service.NewChallengeService(apiLogger, challenges, challengeRepo, cqrscommands.NewSSEBroadcastHelper(eventBus))
+
+	// Create live-ops service running admin event scripts; it spawns through the spawn manager
+	liveOpsService := service.NewLiveOpsService(apiLogger, liveops.NewRedisRepository(redisClient.Client), trainerRepo, spawnTableRepo, spawnManager, cqrscommands.NewSSEBroadcastHelper(eventBus))
+
+	// Create admin service for operational interventions; kicks disconnect on every server
+	adminService := service.NewAdminService(apiLogger, service.AdminRepositories{
+		Trainers:    trainerRepo,
+		Animals:     animalRepo,
+		Presence:    presenceRepo,
+		Revocations: revocationRepo,
+		Characters:  characterRepo,
+	}, service.AdminTransports{
+		SSE:       sseBroadcaster,
+		WebSocket: wsHub,
+		Fanout:    sseFanout,
+	}, movementBroadcaster, movementValidator, gameWorld, eventBus, cqrscommands.NewSSEBroadcastHelper(eventBus))
+
+	// Spawning, animal AI, the world clock, encounters, AFK detection, purging, archiving, challenge rotation and the outbox relay run on each tenant's data by loops of their own
+	var tenantLoops []tenantLoop
+	for _, t := range tenants.List() {
+		if t.ID == tenant.Default {
+			continue
+		}
+		tenantLoops = append(tenantLoops,
+			tenantLoop{tenant: t, loop: service.NewSpawnManager(apiLogger, animalRepo, spawnTableRepo, clockRepo, gameWorld, animal.DefaultSpawnConfig(config.AnimalSpawnRate), randomnessService, eventBus, redisClient.Client)},
+			tenantLoop{tenant: t, loop: service.NewAnimalAIService(apiLogger, animalRepo, battleRepo, positionRepo, interestManager, respawnService, gameWorld, animal.DefaultSpawnConfig(config.AnimalSpawnRate), eventBus, redisClient.Client)},
+			tenantLoop{tenant: t, loop: service.NewWorldClockService(apiLogger, clockRepo, eventBus, redisClient.Client, config.DayLength)},
+			tenantLoop{tenant: t, loop: service.NewSocialService(apiLogger, socialRepo, trainerRepo, interestManager, redisClient.Client)},
+			tenantLoop{tenant: t, loop: service.NewIdleService(apiLogger, idleRepo, interestManager, movementBroadcaster, cqrscommands.NewSSEBroadcastHelper(eventBus), redisClient.Client, config.AFKTimeout)},
+			tenantLoop{tenant: t, loop: service.NewRetentionService(apiLogger, redisClient.Client, activityRepo, config.Retention)},
+			tenantLoop{tenant: t, loop: service.NewEventArchiveService(apiLogger, redisClient.Client, archiveStore, config.Archive)},
+			tenantLoop{tenant: t, loop: service.NewChallengeService(apiLogger, challenges, challengeRepo, cqrscommands.NewSSEBroadcastHelper(eventBus))},
+			tenantLoop{tenant: t, loop: service.NewOutboxRelay(apiLogger, redisClient.Client, publisher, serverID)},
+		)
+	}
+
+	// The browser client is embedded in the binary unless a directory overrides it
+	static, err := assets.New(web.FS, web.Index, false)
+	if config.StaticDir != "" {
+		static, err = assets.New(os.DirFS(config.StaticDir), web.Index, true)
+	}
+	if err != nil {
+		return nil, oops.With("component", "static").With("operation", "load_assets").Hint("Failed to load the browser client").Wrap(err)
+	}
 
 	// Create event handlers
 	sseEventHandler := cqrshandlers.NewSSEEventHandler(
-		sseBroadcaster, // SSEBroadcaster interface
+		cqrshandlers.NewPreferenceGateway(apiLogger, notificationRepo, sseFanout), // NotificationGateway interface
+		interestManager, // InterestFilter interface
+		cqrshandlers.AnyReduced{accessibilityService, idleService}, // BroadcastRates interface
 		eventBus,       // EventPublisher interface
 		apiLogger,
 	)
@@ -240,45 +996,203 @@ func NewServer(config ServerConfig, logger *logger.Logger, redisClient *redisx.C
 		logger:            apiLogger,
 		redisClient:       redisClient,
 		mux:               mux,
-		trainerHandler:    handlers.NewTrainerHandler(apiLogger, trainerRepo, eventBus, movementBroadcaster),
-		animalHandler:     handlers.NewAnimalHandler(apiLogger),
-		worldHandler:      handlers.NewWorldHandler(apiLogger),
-		authHandler:       handlers.NewAuthHandler(apiLogger, accountRepo, jwtService, oauthConfig),
-		serverHandler:     handlers.NewServerHandler(),
+		rpcMethods:        autorouter.NewRegistry(),
+		tenants:           tenants,
+		trainerHandler:    handlers.NewTrainerHandler(apiLogger, trainerRepo, eventBus, outbox, movementBroadcaster, interestManager, consumableService, profileService, gameWorld, gameWorld, plugins, movementValidator, tutorialService, latencyService),
+		animalHandler:     handlers.NewAnimalHandler(apiLogger, captureService, tutorialService),
+		worldHandler:      handlers.NewWorldHandler(apiLogger, chunkStreamService, worldClockService),
+		authHandler:       handlers.NewAuthHandler(apiLogger, accountRepo, jwtService, oauthConfig, referralService, pairingRepo, loginGuard, activityService, accountMergeService, botCheck),
+		serverHandler:     handlers.NewServerHandler(tenants, botCheck.Widget()),
+		lootHandler:       handlers.NewLootHandler(apiLogger, trainerRepo, lootService),
+		craftHandler:      handlers.NewCraftHandler(apiLogger, craftingService),
+		equipmentHandler:  handlers.NewEquipmentHandler(apiLogger, equipmentService),
+		partyHandler:      handlers.NewPartyHandler(apiLogger, partyService),
+		vaultHandler:      handlers.NewVaultHandler(apiLogger, vaultService),
+		inventoryHandler:  handlers.NewInventoryHandler(apiLogger, inventoryService),
+		fairnessHandler:   handlers.NewFairnessHandler(apiLogger, randomnessService),
+		battleHandler:     handlers.NewBattleHandler(apiLogger, battleService),
+		searchHandler:     handlers.NewSearchHandler(apiLogger, searchService),
+		socialHandler:     handlers.NewSocialHandler(apiLogger, socialService),
+		friendHandler:     handlers.NewFriendHandler(apiLogger, friendService),
+		characterHandler:  handlers.NewCharacterHandler(apiLogger, characterService, jwtService),
+		chatHandler:       handlers.NewChatHandler(apiLogger, chatService),
+		notificationHandler: handlers.NewNotificationHandler(apiLogger, notificationService),
+		accessibilityHandler: handlers.NewAccessibilityHandler(apiLogger, accessibilityService),
+		idleHandler:          handlers.NewIdleHandler(apiLogger, idleService),
+		pluginHandler:     handlers.NewPluginHandler(apiLogger),
+		plugins:           plugins,
+		adminHandler:      handlers.NewAdminHandler(apiLogger, worldService, liveOpsService, adminService),
+		referralHandler:   handlers.NewReferralHandler(apiLogger, referralService),
+		emailHandler:      handlers.NewEmailHandler(apiLogger, emailService),
+		activityHandler:   handlers.NewActivityHandler(apiLogger, activityService),
+		accountHandler:    handlers.NewAccountHandler(apiLogger, accountDeletionService),
+		authSessionHandler: handlers.NewAuthSessionHandler(apiLogger, authSessionService),
+		mfaHandler:         handlers.NewMFAHandler(apiLogger, service.NewMFAService(apiLogger, accountRepo, activityService)),
+		tutorialHandler:    handlers.NewTutorialHandler(apiLogger, tutorialService),
+		challengeHandler:   handlers.NewChallengeHandler(apiLogger, challengeService),
+		deprecationHandler: handlers.NewDeprecationHandler(config.Deprecations),
+		bulletHandler:     handlers.NewBulletHandler(apiLogger, trainerRepo, bulletRepo, ammoService, hitRegistration, roomService, eventBus, tutorialService),
+		roomHandler:       handlers.NewRoomHandler(apiLogger, roomService),
+		matchHandler:      handlers.NewMatchHandler(apiLogger, matchService),
 		authMiddleware:    authMiddleware,
 		sseBroadcaster:      sseBroadcaster,
+		wsHub:               wsHub,
+		sseFanout:           sseFanout,
 		movementBroadcaster: movementBroadcaster,
+		spawnManager:        spawnManager,
+		animalAIService:     animalAIService,
+		hitRegistration:     hitRegistration,
+		worldClockService:   worldClockService,
+		tenantLoops:         tenantLoops,
+		socialService:       socialService,
+		idleService:         idleService,
+		friendService:       friendService,
+		challengeService:    challengeService,
+		outboxRelay:         outboxRelay,
+		retentionService:    service.NewRetentionService(apiLogger, redisClient.Client, activityRepo, config.Retention),
+		timeouts:            config.Timeouts,
+		envBanner:           config.EnvBanner,
+		static:              static,
+		deprecations:        config.Deprecations,
+		rateLimiter:         middleware.NewRateLimiter(apiLogger, redisClient.Client, config.RateLimit),
+		degradation:         degradation,
+		analyticsExport:     analyticsExport,
+		eventArchive:        eventArchive,
 		commandBus:          commandBus,
 		eventBus:            eventBus,
 		commandProcessor:    commandProcessor,
 		eventProcessor:      eventProcessor,
 		router:              router,
+		consumerName:        serverID,
 		sseEventHandler:     sseEventHandler,
+		taskServer:          taskServer,
+		taskMux:             taskMux,
+		taskRedis:           ownTaskRedis,
+		warmupTimeout:       config.WarmupTimeout,
+		warmupSteps: []warmupStep{
+			{name: "redis_pool", run: func(ctx context.Context) error { return redisClient.Warm(ctx, warmConnections) }},
+			{name: "trainer_search_index", run: trainerSearchIndex.Wait},
+			{name: "bullet_search_index", run: bulletRepo.WaitIndexed},
+		},
+	}
+	if config.Postgres != nil {
+		server.warmupSteps = append(server.warmupSteps, warmupStep{name: "postgres", run: config.Postgres.Ping})
 	}
 
 	// Register only event handlers for SSE broadcasting
 	err = eventProcessor.AddHandlers(
 		cqrs.NewEventHandler("TrainerMovedEvent", sseEventHandler.HandleTrainerMovedEvent),
+		cqrs.NewEventHandler("PositionsBatchEvent", sseEventHandler.HandlePositionsBatchEvent),
 		cqrs.NewEventHandler("TrainerStoppedEvent", sseEventHandler.HandleTrainerStoppedEvent),
 		cqrs.NewEventHandler("TrainerCreatedEvent", sseEventHandler.HandleTrainerCreatedEvent),
+		cqrs.NewEventHandler("TrainerDiedEvent", sseEventHandler.HandleTrainerDiedEvent),
+		cqrs.NewEventHandler("TrainerRespawnedEvent", sseEventHandler.HandleTrainerRespawnedEvent),
 		cqrs.NewEventHandler("SSENotificationEvent", sseEventHandler.HandleSSENotificationEvent),
+		cqrs.NewEventHandler("LootDroppedEvent", sseEventHandler.HandleLootDroppedEvent),
+		cqrs.NewEventHandler("CraftCompletedEvent", sseEventHandler.HandleCraftCompletedEvent),
+		cqrs.NewEventHandler("BulletFiredEvent", sseEventHandler.HandleBulletFiredEvent),
+		cqrs.NewEventHandler("BulletHitEvent", sseEventHandler.HandleBulletHitEvent),
+		cqrs.NewEventHandler("AmmoChangedEvent", sseEventHandler.HandleAmmoChangedEvent),
+		cqrs.NewEventHandler("RoomJoinedEvent", sseEventHandler.HandleRoomJoinedEvent),
+		cqrs.NewEventHandler("RoomLeftEvent", sseEventHandler.HandleRoomLeftEvent),
+		cqrs.NewEventHandler("MatchStartedEvent", sseEventHandler.HandleMatchStartedEvent),
+		cqrs.NewEventHandler("MatchScoreChangedEvent", sseEventHandler.HandleMatchScoreChangedEvent),
+		cqrs.NewEventHandler("MatchEndedEvent", sseEventHandler.HandleMatchEndedEvent),
+		cqrs.NewEventHandler("ItemConsumedEvent", sseEventHandler.HandleItemConsumedEvent),
+		cqrs.NewEventHandler("InventoryChangedEvent", sseEventHandler.HandleInventoryChangedEvent),
+		cqrs.NewEventHandler("ChatMessageEvent", sseEventHandler.HandleChatMessageEvent),
+		cqrs.NewEventHandler("WorldTimeChangedEvent", sseEventHandler.HandleWorldTimeChangedEvent),
+		cqrs.NewEventHandler("AnimalSpawnedEvent", sseEventHandler.HandleAnimalSpawnedEvent),
+		cqrs.NewEventHandler("AnimalMovedEvent", sseEventHandler.HandleAnimalMovedEvent),
+		cqrs.NewEventHandler("AnimalCapturedEvent", sseEventHandler.HandleAnimalCapturedEvent),
+		cqrs.NewEventHandler("BattleStartedEvent", sseEventHandler.HandleBattleStartedEvent),
+		cqrs.NewEventHandler("BattleTurnEvent", sseEventHandler.HandleBattleTurnEvent),
+		cqrs.NewEventHandler("BattleEndedEvent", sseEventHandler.HandleBattleEndedEvent),
 	)
 	if err != nil {
 		return nil, oops.With("component", "event_handlers").With("operation", "register_event_handlers").Hint("Failed to register CQRS event handlers for SSE broadcasting").Wrap(err)
 	}
 
+	// The world is shared by every tenant, so edits reach every instance's copy
+	err = instanceEventProcessor.AddHandlers(
+		cqrs.NewEventHandler("WorldUpdatedEvent", worldService.HandleWorldUpdatedEvent),
+	)
+	if err != nil {
+		return nil, oops.With("component", "event_handlers").With("operation", "register_instance_event_handlers").Hint("Failed to register CQRS fan-out event handlers").Wrap(err)
+	}
+
+	// Challenge progress is counted from what trainers did, never from what clients report
+	err = challengeEventProcessor.AddHandlers(
+		cqrs.NewEventHandler("AnimalCapturedEvent", challengeService.HandleAnimalCapturedEvent),
+		cqrs.NewEventHandler("BattleEndedEvent", challengeService.HandleBattleEndedEvent),
+	)
+	if err != nil {
+		return nil, oops.With("component", "event_handlers").With("operation", "register_challenge_event_handlers").Hint("Failed to register CQRS challenge event handlers").Wrap(err)
+	}
+
+	// Match scoreboards count the kills hit registration reported in rooms
+	err = matchEventProcessor.AddHandlers(
+		cqrs.NewEventHandler("BulletHitEvent", matchService.HandleBulletHitEvent),
+	)
+	if err != nil {
+		return nil, oops.With("component", "event_handlers").With("operation", "register_match_event_handlers").Hint("Failed to register CQRS match event handlers").Wrap(err)
+	}
+
+	// Players streaming world chunks are told about trainers and animals entering and leaving them
+	err = chunkEventProcessor.AddHandlers(
+		cqrs.NewEventHandler("PositionsBatchEvent", chunkStreamService.HandlePositionsBatchEvent),
+		cqrs.NewEventHandler("TrainerStoppedEvent", chunkStreamService.HandleTrainerStoppedEvent),
+		cqrs.NewEventHandler("AnimalSpawnedEvent", chunkStreamService.HandleAnimalSpawnedEvent),
+		cqrs.NewEventHandler("AnimalMovedEvent", chunkStreamService.HandleAnimalMovedEvent),
+		cqrs.NewEventHandler("AnimalCapturedEvent", chunkStreamService.HandleAnimalCapturedEvent),
+		cqrs.NewEventHandler("BattleEndedEvent", chunkStreamService.HandleBattleEndedEvent),
+	)
+	if err != nil {
+		return nil, oops.With("component", "event_handlers").With("operation", "register_chunk_event_handlers").Hint("Failed to register CQRS chunk event handlers").Wrap(err)
+	}
+
+	// Serve the gRPC API to other backend services on its own port
+	if config.GRPCPort != 0 {
+		server.grpcAddr = fmt.Sprintf("%s:%d", config.Host, config.GRPCPort)
+		server.grpcServer = grpcapi.NewServer(apiLogger, authMiddleware, grpcapi.Services{
+			Trainers:    trainerRepo,
+			Admin:       adminService,
+			Clock:       worldClockService,
+			Chunks:      chunkStreamService,
+			Accounts:    accountRepo,
+			Revocations: revocationRepo,
+			Deletion:    accountDeletionService,
+		})
+	}
+
 	if err := server.setupRoutes(); err != nil {
 		return nil, oops.With("component", "server").With("operation", "setup_routes").Hint("Failed to setup HTTP routes during server initialization").Wrap(err)
 	}
 	server.setupMiddleware()
 
+	// A deprecation of a method that isn't served is most likely a typo
+	for _, deprecation := range config.Deprecations.List() {
+		if _, ok := server.rpcMethods.Lookup(deprecation.Method); !ok {
+			apiLogger.Warn("Deprecated method is not registered", zap.String("method", deprecation.Method))
+		}
+	}
+
 	return server, nil
 }
 
 // setupRoutes configures the server routes
 func (s *Server) setupRoutes() error {
-	// Health check endpoint (pure REST)
-	s.mux.HandleFunc("/health", s.healthCheckHandler)
+	// Health check endpoints for orchestrator probes (pure REST); /health is readiness, as
+	// probes set up before the split expect
+	s.mux.HandleFunc("/health/live", s.livenessHandler)
+	s.mux.HandleFunc("/health/ready", s.readinessProbeHandler)
+	s.mux.HandleFunc("/health", s.readinessProbeHandler)
+
+	// Readiness endpoint for load balancers, unavailable while warming up or shutting down
+	s.mux.HandleFunc("/ready", s.readinessHandler)
+
+	// Runtime metrics, including retention purge counts (expvar JSON)
+	s.mux.Handle("/debug/vars", expvar.Handler())
 
 	// Swagger documentation endpoint
 	s.mux.HandleFunc("/swagger/", httpSwagger.WrapHandler)
@@ -287,44 +1201,231 @@ func (s *Server) setupRoutes() error {
 	s.mux.HandleFunc("/api/v1/ping", s.handlePing)
 
 	// Static file serving for client
-	s.mux.HandleFunc("/", s.handleStaticFiles)
+	s.mux.Handle("/", s.static)
 
 	// SSE endpoint for real-time updates (uses dedicated SSE auth middleware)
 	s.mux.Handle("/api/v1/stream/positions", s.authMiddleware.RequireSSEAuth(http.HandlerFunc(s.sseBroadcaster.HandleSSE)))
 
+	// WebSocket endpoint: same notifications as SSE plus JSON-RPC commands (token auth like SSE)
+	s.wsHub.Handle("trainer.Move", middleware.Activity(s.idleService)(http.HandlerFunc(s.trainerHandler.Move)).ServeHTTP)
+	s.wsHub.Handle("bullet.Fire", middleware.Activity(s.idleService)(http.HandlerFunc(s.bulletHandler.Fire)).ServeHTTP)
+	s.mux.Handle("/api/v1/ws", s.authMiddleware.RequireWebSocketAuth(http.HandlerFunc(s.wsHub.HandleWebSocket)))
+
+	// Writes that stay correct when applied late are queued while degraded and replayed later
+	s.degradation.Handle("trainer.UpdateProfile", s.trainerHandler.UpdateProfile)
+	s.degradation.Handle("trainer.PinAnimal", s.trainerHandler.PinAnimal)
+	s.degradation.Handle("trainer.UnpinAnimal", s.trainerHandler.UnpinAnimal)
+
 	// === Auto-Router Registration ===
 	s.logger.Info("Setting up auto-router endpoints...")
 
-	// Convert middleware to autorouter.Middleware type
+	// Convert middleware to autorouter.Middleware type; rate limits apply after auth so
+	// signed-in players are limited per user, and after the degradation guard so requests it
+	// answers while Redis is down don't wait on the limiter. Requests let through count as
+	// the player's activity.
+	activity := middleware.Activity(s.idleService)
 	authMiddleware := func(next http.Handler) http.Handler {
-		return s.authMiddleware.RequireAuth(next)
+		return s.authMiddleware.RequireAuth(s.degradation.Guard(s.rateLimiter.Limit(activity(next))))
+	}
+
+	// Every method gets its own endpoint and is recorded for the /api/v1/rpc gateway
+	register := func(methodPrefix string, handler *autorouter.Bound, middlewares ...autorouter.Middleware) error {
+		router := autorouter.NewAutoRouter(s.mux, autorouter.RegistrationOptions{
+			Prefix:       "/api/v1/",
+			MethodPrefix: methodPrefix,
+			Middleware:   middlewares,
+			Registry:     s.rpcMethods,
+		})
+		return router.RegisterHandlers(handler)
 	}
 
 	// Server endpoints (no auth required)
-	if err := autorouter.QuickRegister(s.mux, "/api/v1/", "server.", s.serverHandler); err != nil {
+	if err := register("server.", autorouter.Bind(s.serverHandler)); err != nil {
 		return oops.With("handler", "server").With("operation", "register_routes").Hint("Failed to register server handler endpoints").Wrap(err)
 	}
 
-	// Auth endpoints (no auth required)
-	if err := autorouter.QuickRegister(s.mux, "/api/v1/", "auth.", s.authHandler); err != nil {
+	// Deprecation endpoints (no auth required, so client teams can track removals)
+	if err := register("deprecations.", autorouter.Bind(s.deprecationHandler)); err != nil {
+		return oops.With("handler", "deprecations").With("operation", "register_routes").Hint("Failed to register deprecation handler endpoints").Wrap(err)
+	}
+
+	// Auth endpoints (auth optional; linking and pairing act on the signed-in user)
+	optionalAuthMiddleware := func(next http.Handler) http.Handler {
+		return s.authMiddleware.OptionalAuth(s.degradation.Guard(s.rateLimiter.Limit(next)))
+	}
+	if err := register("auth.", autorouter.Bind(s.authHandler), optionalAuthMiddleware, middleware.UserAgent); err != nil {
 		return oops.With("handler", "auth").With("operation", "register_routes").Hint("Failed to register auth handler endpoints").Wrap(err)
 	}
 
+	// Email endpoints share the auth. prefix (auth optional; verification links work signed out)
+	if err := register("auth.", autorouter.Bind(s.emailHandler), optionalAuthMiddleware); err != nil {
+		return oops.With("handler", "email").With("operation", "register_routes").Hint("Failed to register email handler endpoints").Wrap(err)
+	}
+
+	// Account activity endpoints share the auth. prefix (auth required)
+	if err := register("auth.", autorouter.Bind(s.activityHandler), authMiddleware); err != nil {
+		return oops.With("handler", "activity").With("operation", "register_routes_with_auth").Hint("Failed to register activity handler endpoints with authentication").Wrap(err)
+	}
+
+	// Account lifecycle endpoints share the auth. prefix (auth required)
+	if err := register("auth.", autorouter.Bind(s.accountHandler), authMiddleware); err != nil {
+		return oops.With("handler", "account").With("operation", "register_routes_with_auth").Hint("Failed to register account handler endpoints with authentication").Wrap(err)
+	}
+
+	// Signed-in session endpoints (auth required)
+	if err := register("auth.Sessions.", autorouter.Bind(s.authSessionHandler), authMiddleware); err != nil {
+		return oops.With("handler", "auth_session").With("operation", "register_routes_with_auth").Hint("Failed to register auth session handler endpoints with authentication").Wrap(err)
+	}
+
+	// Two-factor authentication endpoints (auth required)
+	if err := register("auth.MFA.", autorouter.Bind(s.mfaHandler), authMiddleware); err != nil {
+		return oops.With("handler", "mfa").With("operation", "register_routes_with_auth").Hint("Failed to register MFA handler endpoints with authentication").Wrap(err)
+	}
+
 	// Trainer endpoints (auth required)
-	if err := autorouter.QuickRegisterWithAuth(s.mux, "/api/v1/", "trainer.", s.trainerHandler, authMiddleware); err != nil {
+	if err := register("trainer.", autorouter.Bind(s.trainerHandler), authMiddleware); err != nil {
 		return oops.With("handler", "trainer").With("operation", "register_routes_with_auth").Hint("Failed to register trainer handler endpoints with authentication").Wrap(err)
 	}
 
+	// Party endpoints (auth required)
+	if err := register("trainer.Party.", autorouter.Bind(s.partyHandler), authMiddleware); err != nil {
+		return oops.With("handler", "party").With("operation", "register_routes_with_auth").Hint("Failed to register party handler endpoints with authentication").Wrap(err)
+	}
+
 	// Animal endpoints (auth required)
-	if err := autorouter.QuickRegisterWithAuth(s.mux, "/api/v1/", "animal.", s.animalHandler, authMiddleware); err != nil {
+	if err := register("animal.", autorouter.Bind(s.animalHandler), authMiddleware); err != nil {
 		return oops.With("handler", "animal").With("operation", "register_routes_with_auth").Hint("Failed to register animal handler endpoints with authentication").Wrap(err)
 	}
 
 	// World endpoints (auth required)
-	if err := autorouter.QuickRegisterWithAuth(s.mux, "/api/v1/", "world.", s.worldHandler, authMiddleware); err != nil {
+	if err := register("world.", autorouter.Bind(s.worldHandler), authMiddleware); err != nil {
 		return oops.With("handler", "world").With("operation", "register_routes_with_auth").Hint("Failed to register world handler endpoints with authentication").Wrap(err)
 	}
 
+	// Loot endpoints (auth required)
+	if err := register("loot.", autorouter.Bind(s.lootHandler), authMiddleware); err != nil {
+		return oops.With("handler", "loot").With("operation", "register_routes_with_auth").Hint("Failed to register loot handler endpoints with authentication").Wrap(err)
+	}
+
+	// Craft endpoints (auth required)
+	if err := register("craft.", autorouter.Bind(s.craftHandler), authMiddleware); err != nil {
+		return oops.With("handler", "craft").With("operation", "register_routes_with_auth").Hint("Failed to register craft handler endpoints with authentication").Wrap(err)
+	}
+
+	// Equipment endpoints (auth required)
+	if err := register("equipment.", autorouter.Bind(s.equipmentHandler), authMiddleware); err != nil {
+		return oops.With("handler", "equipment").With("operation", "register_routes_with_auth").Hint("Failed to register equipment handler endpoints with authentication").Wrap(err)
+	}
+
+	// Vault endpoints (auth required)
+	if err := register("vault.", autorouter.Bind(s.vaultHandler), authMiddleware); err != nil {
+		return oops.With("handler", "vault").With("operation", "register_routes_with_auth").Hint("Failed to register vault handler endpoints with authentication").Wrap(err)
+	}
+
+	// Inventory endpoints (auth required)
+	if err := register("inventory.", autorouter.Bind(s.inventoryHandler), authMiddleware); err != nil {
+		return oops.With("handler", "inventory").With("operation", "register_routes_with_auth").Hint("Failed to register inventory handler endpoints with authentication").Wrap(err)
+	}
+
+	// Bullet endpoints (auth required)
+	if err := register("bullet.", autorouter.Bind(s.bulletHandler), authMiddleware); err != nil {
+		return oops.With("handler", "bullet").With("operation", "register_routes_with_auth").Hint("Failed to register bullet handler endpoints with authentication").Wrap(err)
+	}
+
+	// Room endpoints (auth required)
+	if err := register("room.", autorouter.Bind(s.roomHandler), authMiddleware); err != nil {
+		return oops.With("handler", "room").With("operation", "register_routes_with_auth").Hint("Failed to register room handler endpoints with authentication").Wrap(err)
+	}
+
+	// Match endpoints (auth required)
+	if err := register("match.", autorouter.Bind(s.matchHandler), authMiddleware); err != nil {
+		return oops.With("handler", "match").With("operation", "register_routes_with_auth").Hint("Failed to register match handler endpoints with authentication").Wrap(err)
+	}
+
+	// Roll audit endpoints (auth required)
+	if err := register("rng.", autorouter.Bind(s.fairnessHandler), authMiddleware); err != nil {
+		return oops.With("handler", "fairness").With("operation", "register_routes_with_auth").Hint("Failed to register fairness handler endpoints with authentication").Wrap(err)
+	}
+
+	// Battle endpoints (auth required)
+	if err := register("battle.", autorouter.Bind(s.battleHandler), authMiddleware); err != nil {
+		return oops.With("handler", "battle").With("operation", "register_routes_with_auth").Hint("Failed to register battle handler endpoints with authentication").Wrap(err)
+	}
+
+	// Search endpoints (auth required)
+	if err := register("search.", autorouter.Bind(s.searchHandler), authMiddleware); err != nil {
+		return oops.With("handler", "search").With("operation", "register_routes_with_auth").Hint("Failed to register search handler endpoints with authentication").Wrap(err)
+	}
+
+	// Social endpoints (auth required)
+	if err := register("social.", autorouter.Bind(s.socialHandler), authMiddleware); err != nil {
+		return oops.With("handler", "social").With("operation", "register_routes_with_auth").Hint("Failed to register social handler endpoints with authentication").Wrap(err)
+	}
+
+	// Friend endpoints (auth required)
+	if err := register("friend.", autorouter.Bind(s.friendHandler), authMiddleware); err != nil {
+		return oops.With("handler", "friend").With("operation", "register_routes_with_auth").Hint("Failed to register friend handler endpoints with authentication").Wrap(err)
+	}
+
+	// Character endpoints (auth required)
+	if err := register("character.", autorouter.Bind(s.characterHandler), authMiddleware); err != nil {
+		return oops.With("handler", "character").With("operation", "register_routes_with_auth").Hint("Failed to register character handler endpoints with authentication").Wrap(err)
+	}
+
+	// Tutorial endpoints (auth required)
+	if err := register("tutorial.", autorouter.Bind(s.tutorialHandler), authMiddleware); err != nil {
+		return oops.With("handler", "tutorial").With("operation", "register_routes_with_auth").Hint("Failed to register tutorial handler endpoints with authentication").Wrap(err)
+	}
+
+	// Challenge endpoints (auth required)
+	if err := register("challenges.", autorouter.Bind(s.challengeHandler), authMiddleware); err != nil {
+		return oops.With("handler", "challenge").With("operation", "register_routes_with_auth").Hint("Failed to register challenge handler endpoints with authentication").Wrap(err)
+	}
+
+	// Chat endpoints (auth required)
+	if err := register("chat.", autorouter.Bind(s.chatHandler), authMiddleware); err != nil {
+		return oops.With("handler", "chat").With("operation", "register_routes_with_auth").Hint("Failed to register chat handler endpoints with authentication").Wrap(err)
+	}
+
+	// Notification preference endpoints (auth required)
+	if err := register("notifications.", autorouter.Bind(s.notificationHandler), authMiddleware); err != nil {
+		return oops.With("handler", "notification").With("operation", "register_routes_with_auth").Hint("Failed to register notification handler endpoints with authentication").Wrap(err)
+	}
+
+	// Accessibility settings endpoints (auth required)
+	if err := register("accessibility.", autorouter.Bind(s.accessibilityHandler), authMiddleware); err != nil {
+		return oops.With("handler", "accessibility").With("operation", "register_routes_with_auth").Hint("Failed to register accessibility handler endpoints with authentication").Wrap(err)
+	}
+
+	// AFK endpoints (auth required)
+	if err := register("idle.", autorouter.Bind(s.idleHandler), authMiddleware); err != nil {
+		return oops.With("handler", "idle").With("operation", "register_routes_with_auth").Hint("Failed to register idle handler endpoints with authentication").Wrap(err)
+	}
+
+	// Admin endpoints live under /admin/v1/, outside the /api/v1/rpc gateway (auth required;
+	// moderation methods need the moderator role, the others the admin role)
+	adminRouter := autorouter.NewAutoRouter(s.mux, autorouter.RegistrationOptions{
+		Prefix:       "/admin/v1/",
+		MethodPrefix: "admin.",
+		Middleware:   []autorouter.Middleware{authMiddleware},
+		Guard: func(methodName string) autorouter.Middleware {
+			return s.authMiddleware.RequireRole(handlers.AdminMethodRole(methodName))
+		},
+	})
+	if err := adminRouter.RegisterHandlers(autorouter.Bind(s.adminHandler)); err != nil {
+		return oops.With("handler", "admin").With("operation", "register_routes_with_auth").Hint("Failed to register admin handler endpoints with authentication").Wrap(err)
+	}
+
+	// Referral endpoints (auth required)
+	if err := register("referral.", autorouter.Bind(s.referralHandler), authMiddleware); err != nil {
+		return oops.With("handler", "referral").With("operation", "register_routes_with_auth").Hint("Failed to register referral handler endpoints with authentication").Wrap(err)
+	}
+
+	// Plugin methods (auth required), each in a namespace the server doesn't use
+	if err := s.registerPluginMethods(authMiddleware); err != nil {
+		return oops.With("handler", "plugin").With("operation", "register_routes_with_auth").Hint("Failed to register plugin methods").Wrap(err)
+	}
+
 	// Print all registered handlers for debugging
 	s.printAutoRegisteredHandlers()
 	
@@ -332,6 +1433,40 @@ func (s *Server) setupRoutes() error {
 	return nil
 }
 
+// registerPluginMethods registers the JSON-RPC methods of the loaded plugins. It runs after
+// the server's own handlers so a plugin can't take over one of their namespaces.
+func (s *Server) registerPluginMethods(middlewares ...autorouter.Middleware) error {
+	reserved := make(map[string]bool)
+	for _, name := range s.rpcMethods.Methods() {
+		namespace, _, _ := strings.Cut(name, ".")
+		reserved[namespace] = true
+	}
+
+	for _, method := range s.plugins.Methods() {
+		namespace, methodName, _ := strings.Cut(method.Name, ".")
+		if reserved[namespace] {
+			return fmt.Errorf("plugin %s: namespace %s is used by the server", method.Module, namespace)
+		}
+
+		router := autorouter.NewAutoRouter(s.mux, autorouter.RegistrationOptions{
+			Prefix:       "/api/v1/",
+			MethodPrefix: namespace + ".",
+			Middleware:   middlewares,
+			Registry:     s.rpcMethods,
+		})
+		if err := router.RegisterFunc(methodName, s.pluginHandler.Serve(method)); err != nil {
+			return err
+		}
+	}
+
+	if modules := s.plugins.Modules(); len(modules) > 0 {
+		s.logger.Info("Gameplay plugins loaded",
+			zap.Strings("modules", modules),
+			zap.Int("methods", len(s.plugins.Methods())))
+	}
+	return nil
+}
+
 // printAutoRegisteredHandlers prints all auto-registered handlers for debugging
 func (s *Server) printAutoRegisteredHandlers() {
 	router := autorouter.NewAutoRouter(s.mux, autorouter.RegistrationOptions{
@@ -347,10 +1482,39 @@ func (s *Server) printAutoRegisteredHandlers() {
 		hasAuth bool
 	}{
 		{"Server", s.serverHandler, false},
+		{"Deprecations", s.deprecationHandler, false},
 		{"Auth", s.authHandler, false},
 		{"Trainer", s.trainerHandler, true},
+		{"Party", s.partyHandler, true},
 		{"Animal", s.animalHandler, true},
 		{"World", s.worldHandler, true},
+		{"Loot", s.lootHandler, true},
+		{"Craft", s.craftHandler, true},
+		{"Equipment", s.equipmentHandler, true},
+		{"Vault", s.vaultHandler, true},
+		{"Inventory", s.inventoryHandler, true},
+		{"Bullet", s.bulletHandler, true},
+		{"Room", s.roomHandler, true},
+		{"Match", s.matchHandler, true},
+		{"Fairness", s.fairnessHandler, true},
+		{"Battle", s.battleHandler, true},
+		{"Search", s.searchHandler, true},
+		{"Social", s.socialHandler, true},
+		{"Friend", s.friendHandler, true},
+		{"Character", s.characterHandler, true},
+		{"Tutorial", s.tutorialHandler, true},
+		{"Challenges", s.challengeHandler, true},
+		{"Chat", s.chatHandler, true},
+		{"Notifications", s.notificationHandler, true},
+		{"Accessibility", s.accessibilityHandler, true},
+		{"Idle", s.idleHandler, true},
+		{"Admin", s.adminHandler, true},
+		{"Referral", s.referralHandler, true},
+		{"Email", s.emailHandler, false},
+		{"Activity", s.activityHandler, true},
+		{"Account", s.accountHandler, true},
+		{"Sessions", s.authSessionHandler, true},
+		{"MFA", s.mfaHandler, true},
 	}
 
 	for _, h := range handlers {
@@ -373,11 +1537,15 @@ func (s *Server) printAutoRegisteredHandlers() {
 func (s *Server) setupMiddleware() {
 	// Apply middleware chain using functional composition
 	middlewareChain := middleware.Chain(
-		// middleware.RateLimit(s.logger), // Disabled for real-time movement
+		middleware.RequestID(),
 		middleware.Recovery(s.logger),
 		middleware.ErrorAdapter(s.logger),
 		middleware.CORS(),
+		middleware.Tenant(s.tenants),
+		middleware.Gateway(s.logger, s.rpcMethods),
+		middleware.APIHeaders(s.envBanner, s.deprecations),
 		middleware.Logging(s.logger),
+		middleware.Timeout(s.logger, s.timeouts),
 	)
 
 	s.httpServer.Handler = middlewareChain(s.mux)
@@ -388,6 +1556,14 @@ func (s *Server) Start(ctx context.Context) error {
 	s.logger.Info("Starting HTTP server",
 		zap.String("address", s.httpServer.Addr))
 
+	// Load what the first requests need before accepting any
+	s.warmUp(ctx)
+
+	// Subscribe to the notification fan-out before events start flowing
+	if err := s.sseFanout.Start(ctx); err != nil {
+		return oops.With("component", "sse_fanout").With("operation", "start").Hint("Failed to subscribe to SSE fan-out channel").Wrap(err)
+	}
+
 	// Start Watermill router first
 	go func() {
 		if err := s.router.Run(ctx); err != nil {
@@ -395,8 +1571,57 @@ func (s *Server) Start(ctx context.Context) error {
 		}
 	}()
 
-	// Start movement broadcaster
-	go s.movementBroadcaster.Start(ctx)
+	// Start the movement simulations
+	s.movementBroadcaster.Start(ctx)
+
+	// Start spawning wild animals
+	s.spawnManager.Start(ctx)
+
+	// Start moving the wild animals
+	s.animalAIService.Start(ctx)
+
+	// Start checking bullets for hits
+	s.hitRegistration.Start(ctx)
+
+	// Start cycling day and night and the weather
+	s.worldClockService.Start(ctx)
+
+	// Start recording encounters between nearby trainers
+	s.socialService.Start(ctx)
+
+	// Start putting players who stopped playing into the AFK state
+	s.idleService.Start(ctx)
+
+	// Start keeping connected players online for their friends
+	s.friendService.Start(ctx)
+
+	// Start rotating the weekly challenges
+	s.challengeService.Start(ctx)
+
+	// Start publishing the events recorded in the outbox
+	s.outboxRelay.Start(ctx)
+
+	// Start purging data past its retention period
+	s.retentionService.Start(ctx)
+
+	// Start the loops of the other tenants on their own data
+	for _, l := range s.tenantLoops {
+		l.loop.Start(tenant.WithTenant(ctx, l.tenant))
+	}
+
+	// Start exporting pseudonymized events to analytics sinks
+	s.analyticsExport.Start(ctx)
+
+	// Start archiving game events to object storage
+	s.eventArchive.Start(ctx)
+
+	// Start watching Redis availability, replaying writes a previous run queued
+	s.degradation.Start(ctx)
+
+	// Start asynq worker for delayed game tasks
+	if err := s.taskServer.Start(s.taskMux); err != nil {
+		return oops.With("component", "task_server").With("operation", "start").Hint("Failed to start asynq task server").Wrap(err)
+	}
 
 	// Start server in goroutine
 	go func() {
@@ -405,6 +1630,21 @@ func (s *Server) Start(ctx context.Context) error {
 		}
 	}()
 
+	// Start the gRPC server for other backend services
+	if s.grpcServer != nil {
+		listener, err := net.Listen("tcp", s.grpcAddr)
+		if err != nil {
+			return oops.With("component", "grpc_server").With("operation", "listen").Hint("Failed to listen on the gRPC port").Wrap(err)
+		}
+		s.logger.Info("Starting gRPC server", zap.String("address", s.grpcAddr))
+		go func() {
+			if err := s.grpcServer.Serve(listener); err != nil {
+				s.logger.Error("gRPC server error", zap.Error(err))
+			}
+		}()
+	}
+	s.ready.Store(true)
+
 	// Wait for context cancellation
 	<-ctx.Done()
 
@@ -415,18 +1655,112 @@ func (s *Server) Start(ctx context.Context) error {
 func (s *Server) Shutdown() error {
 	s.logger.Info("Shutting down HTTP server")
 
+	// Stop load balancers from routing new traffic here
+	s.ready.Store(false)
+
 	// Stop movement broadcaster first
 	if s.movementBroadcaster != nil {
 		s.logger.Debug("Stopping movement broadcaster")
 		s.movementBroadcaster.Stop()
 	}
 
+	// Stop spawning wild animals
+	if s.spawnManager != nil {
+		s.spawnManager.Stop()
+	}
+
+	// Stop moving the wild animals
+	if s.animalAIService != nil {
+		s.animalAIService.Stop()
+	}
+
+	// Stop checking bullets for hits
+	if s.hitRegistration != nil {
+		s.hitRegistration.Stop()
+	}
+
+	// Stop the world clock
+	if s.worldClockService != nil {
+		s.worldClockService.Stop()
+	}
+
+	// Stop recording encounters
+	if s.socialService != nil {
+		s.socialService.Stop()
+	}
+
+	// Stop AFK detection
+	if s.idleService != nil {
+		s.idleService.Stop()
+	}
+
+	// Stop refreshing presence
+	if s.friendService != nil {
+		s.friendService.Stop()
+	}
+
+	// Stop rotating the weekly challenges
+	if s.challengeService != nil {
+		s.challengeService.Stop()
+	}
+
+	// Stop relaying the outbox
+	if s.outboxRelay != nil {
+		s.outboxRelay.Stop()
+	}
+
+	// Stop retention purging
+	if s.retentionService != nil {
+		s.retentionService.Stop()
+	}
+
+	// Stop the other tenants' loops
+	for _, l := range s.tenantLoops {
+		l.loop.Stop()
+	}
+
+	// Stop analytics export
+	if s.analyticsExport != nil {
+		s.analyticsExport.Stop()
+	}
+
+	// Stop event archive
+	if s.eventArchive != nil {
+		s.eventArchive.Stop()
+	}
+
 	// Shutdown SSE broadcaster to close client connections
 	if s.sseBroadcaster != nil {
 		s.logger.Debug("Closing SSE broadcaster")
 		s.sseBroadcaster.Close()
 	}
 
+	// Stop receiving fan-out notifications
+	if s.sseFanout != nil {
+		s.sseFanout.Close()
+	}
+
+	// Close WebSocket connections
+	if s.wsHub != nil {
+		s.logger.Debug("Closing WebSocket hub")
+		s.wsHub.Close()
+	}
+
+	// Let gRPC calls in flight finish, cutting them off after the HTTP server's timeout
+	if s.grpcServer != nil {
+		s.logger.Debug("Stopping gRPC server")
+		stopped := make(chan struct{})
+		go func() {
+			s.grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			s.grpcServer.Stop()
+		}
+	}
+
 	// Shutdown HTTP server with shorter timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -436,6 +1770,21 @@ func (s *Server) Shutdown() error {
 		return err
 	}
 
+	// Stop degraded mode once no request can queue another write
+	if s.degradation != nil {
+		s.degradation.Stop()
+	}
+
+	// Stop asynq worker, pending tasks stay queued in Redis
+	// The asynq client shares the Redis connection, which main closes, unless it has its own
+	if s.taskServer != nil {
+		s.logger.Debug("Stopping asynq task server")
+		s.taskServer.Shutdown()
+	}
+	if s.taskRedis != nil {
+		s.taskRedis.Close()
+	}
+
 	// Shutdown Watermill router (with CloseTimeout already configured)
 	if s.router != nil {
 		s.logger.Info("Closing Watermill router")
@@ -454,23 +1803,6 @@ func (s *Server) GetAddr() string {
 	return s.httpServer.Addr
 }
 
-// healthCheckHandler handles health check requests
-func (s *Server) healthCheckHandler(w http.ResponseWriter, r *http.Request) {
-	// Check Redis connection
-	if err := s.redisClient.HealthCheck(r.Context()); err != nil {
-		s.logger.Error("Redis health check failed", zap.Error(err))
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusServiceUnavailable)
-		w.Write([]byte(`{"status":"unhealthy","checks":{"redis":{"status":"down","error":"` + err.Error() + `"}}}`))
-		return
-	}
-
-	// All checks passed
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"healthy","checks":{"redis":{"status":"up"}}}`))
-}
-
 // handlePing handles ping requests (hybrid JSON-RPC)
 func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost {
@@ -487,27 +1819,3 @@ func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
 	result := map[string]string{"message": "pong"}
 	jsonrpcx.Success(w, req.ID, result)
 }
-
-// handleStaticFiles serves static files with proper MIME types
-func (s *Server) handleStaticFiles(w http.ResponseWriter, r *http.Request) {
-	switch r.URL.Path {
-	case "/":
-		// Serve the trainer client HTML file
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		http.ServeFile(w, r, "trainer-client.html")
-		return
-	case "/trainer-client.js":
-		// Serve JavaScript file with correct MIME type
-		w.Header().Set("Content-Type", "application/javascript; charset=utf-8")
-		http.ServeFile(w, r, "trainer-client.js")
-		return
-	case "/trainer-client.html":
-		// Also allow direct access to HTML file
-		w.Header().Set("Content-Type", "text/html; charset=utf-8")
-		http.ServeFile(w, r, "trainer-client.html")
-		return
-	default:
-		// 404 for other paths
-		http.NotFound(w, r)
-	}
-}
@@ -0,0 +1,38 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hibiken/asynq"
+
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// tenantTaskPrefix matches the types of tasks enqueued for a tenant other than the default one
+const tenantTaskPrefix = "t:"
+
+// tenantTasks runs the tasks tenants enqueued, whose types carry the tenant's prefix, with the
+// handler of the unprefixed type and the tenant in the context
+func tenantTasks(tenants *tenant.Registry, mux *asynq.ServeMux) asynq.Handler {
+	return asynq.HandlerFunc(func(ctx context.Context, task *asynq.Task) error {
+		id, taskType := tenant.Split(task.Type())
+		t, ok := tenants.Get(id)
+		if !ok || id == tenant.Default {
+			return fmt.Errorf("task type %q belongs to no tenant: %w", task.Type(), asynq.SkipRetry)
+		}
+		return mux.ProcessTask(tenant.WithTenant(ctx, t), asynq.NewTask(taskType, task.Payload()))
+	})
+}
+
+// backgroundLoop is a periodic job the server runs for every tenant
+type backgroundLoop interface {
+	Start(ctx context.Context)
+	Stop()
+}
+
+// tenantLoop is a background loop run on a tenant's data
+type tenantLoop struct {
+	tenant *tenant.Tenant
+	loop   backgroundLoop
+}
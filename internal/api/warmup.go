@@ -0,0 +1,73 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// defaultWarmupTimeout bounds warm-up when ServerConfig.WarmupTimeout is unset
+	defaultWarmupTimeout = 30 * time.Second
+
+	// warmConnections is how many Redis connections are opened before the first requests,
+	// enough for a burst of them without each dialing its own
+	warmConnections = 16
+)
+
+// warmupStep loads one piece of state before the listener opens
+type warmupStep struct {
+	name string
+	run  func(ctx context.Context) error
+}
+
+// warmUp runs every warm-up step concurrently, so the first requests don't pay for cold
+// connections or search indexes still scanning existing documents. A step that fails or runs
+// out of time is logged and skipped: like the repositories falling back when an index is
+// missing, the server then serves slower rather than not at all.
+func (s *Server) warmUp(ctx context.Context) {
+	timeout := s.warmupTimeout
+	if timeout <= 0 {
+		timeout = defaultWarmupTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for _, step := range s.warmupSteps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			stepStart := time.Now()
+			if err := step.run(ctx); err != nil {
+				s.logger.Warn("Warm-up step failed, serving without it",
+					zap.String("step", step.name), zap.Error(err))
+				return
+			}
+			s.logger.Debug("Warm-up step finished",
+				zap.String("step", step.name), zap.Duration("duration", time.Since(stepStart)))
+		}()
+	}
+	wg.Wait()
+
+	s.logger.Info("Warm-up finished", zap.Duration("duration", time.Since(start)))
+}
+
+// readinessHandler tells load balancers whether to route traffic here: unavailable until
+// warm-up finishes and again once shutdown begins. Unlike /health/ready it checks no
+// dependency, so a slow dependency doesn't take every instance out of rotation at once.
+func (s *Server) readinessHandler(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !s.ready.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte(`{"status":"not_ready"}`))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(`{"status":"ready"}`))
+}
@@ -48,14 +48,13 @@ func (h *TrainerCommandHandler) Handle(ctx context.Context, cmd command.Command)
 }
 
 func (h *TrainerCommandHandler) handleCreateTrainer(ctx context.Context, cmd command.CreateTrainerCommand) error {
-	nickname, err := trainer.NewNickname(cmd.Nickname)
-	if err != nil {
+	if err := trainer.ValidateNickname(cmd.Nickname); err != nil {
 		return err
 	}
 
 	userID := trainer.UserID(cmd.CommandID())
 	return h.trainerRepo.FindOneAndInsert(ctx, userID, func() (*trainer.Trainer, error) {
-		return trainer.NewTrainer(userID, nickname)
+		return trainer.NewTrainer(userID, cmd.Nickname)
 	})
 }
 
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/accessibility"
+	"github.com/danghamo/life/internal/domain/character"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// reducedRateRefresh is how long the accounts on the reduced broadcast rate are reused before
+// being read again. Positions are sent 60 times a second, far too often to read them for
+// each frame, and a changed setting taking a few seconds to apply goes unnoticed.
+const reducedRateRefresh = 5 * time.Second
+
+// reducedRate is the accounts of a tenant on the reduced broadcast rate as last read
+type reducedRate struct {
+	accounts map[trainer.UserID]bool
+	readAt   time.Time
+}
+
+// AccessibilityService reads and changes players' accessibility settings and answers the
+// subsystems honoring them: position broadcasts ask who is on the reduced rate, hit checks
+// ask how much aim assistance a player has, and changing the palette recolors the player's
+// characters.
+type AccessibilityService struct {
+	logger      *logger.Logger
+	settings    accessibility.Repository
+	rosters     character.Repository
+	trainerRepo trainer.Repository
+
+	mutex   sync.Mutex
+	reduced map[tenant.ID]*reducedRate
+}
+
+// NewAccessibilityService creates a new accessibility service
+func NewAccessibilityService(logger *logger.Logger, settings accessibility.Repository, rosters character.Repository, trainerRepo trainer.Repository) *AccessibilityService {
+	return &AccessibilityService{
+		logger:      logger.WithComponent("accessibility-service"),
+		settings:    settings,
+		rosters:     rosters,
+		trainerRepo: trainerRepo,
+		reduced:     make(map[tenant.ID]*reducedRate),
+	}
+}
+
+// Get returns an account's settings
+func (s *AccessibilityService) Get(ctx context.Context, accountID trainer.UserID) (accessibility.Settings, error) {
+	return s.settings.Get(ctx, accountID)
+}
+
+// Update replaces an account's settings, moving the colors of its characters into the chosen
+// palette
+func (s *AccessibilityService) Update(ctx context.Context, accountID trainer.UserID, settings accessibility.Settings) (accessibility.Settings, error) {
+	if err := settings.Validate(); err != nil {
+		return accessibility.Settings{}, err
+	}
+
+	var updated accessibility.Settings
+	err := s.settings.FindOneAndUpdate(ctx, accountID, func(current *accessibility.Settings) error {
+		now := time.Now()
+		*current = settings
+		current.UpdatedAt = &now
+		updated = *current
+		return nil
+	})
+	if err != nil {
+		return accessibility.Settings{}, err
+	}
+
+	// Apply a change of rate on this server right away
+	s.mutex.Lock()
+	delete(s.reduced, tenant.IDFromContext(ctx))
+	s.mutex.Unlock()
+
+	s.recolor(ctx, accountID, updated.Palette)
+
+	s.logger.WithContext(ctx).Info("Accessibility settings updated",
+		zap.String("accountId", accountID.String()),
+		zap.String("broadcastRate", updated.BroadcastRate.String()),
+		zap.String("palette", updated.Palette.String()),
+		zap.Int("aimAssist", updated.AimAssist))
+	return updated, nil
+}
+
+// Palette returns the palette an account's characters take their colors from
+func (s *AccessibilityService) Palette(ctx context.Context, accountID trainer.UserID) trainer.PaletteName {
+	settings, err := s.settings.Get(ctx, accountID)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to get accessibility settings, using the standard palette",
+			zap.String("accountId", accountID.String()),
+			zap.Error(err))
+		return trainer.StandardPalette
+	}
+	return settings.Palette
+}
+
+// HitTolerance returns how far wide of a target the bullets of a character may pass and
+// still hit, from the aim assistance of its account
+func (s *AccessibilityService) HitTolerance(ctx context.Context, userID trainer.UserID) float64 {
+	settings, err := s.settings.Get(ctx, userID.AccountID())
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to get accessibility settings, aiming without assistance",
+			zap.String("userId", userID.String()),
+			zap.Error(err))
+		return 0
+	}
+	return settings.HitTolerance()
+}
+
+// Reduced checks if the account of a character receives positions on the reduced rate
+func (s *AccessibilityService) Reduced(ctx context.Context, userID string) bool {
+	return s.reducedAccounts(ctx)[trainer.UserID(userID).AccountID()]
+}
+
+// reducedAccounts returns the accounts of the context's tenant on the reduced rate, read again
+// once reducedRateRefresh has passed. When reading fails the accounts last read are kept.
+func (s *AccessibilityService) reducedAccounts(ctx context.Context) map[trainer.UserID]bool {
+	tenantID := tenant.IDFromContext(ctx)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cached := s.reduced[tenantID]
+	if cached != nil && time.Since(cached.readAt) < reducedRateRefresh {
+		return cached.accounts
+	}
+
+	ids, err := s.settings.ReducedRate(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to read accounts on the reduced broadcast rate", zap.Error(err))
+		if cached == nil {
+			return nil
+		}
+		cached.readAt = time.Now()
+		return cached.accounts
+	}
+
+	accounts := make(map[trainer.UserID]bool, len(ids))
+	for _, id := range ids {
+		accounts[trainer.UserID(id)] = true
+	}
+	s.reduced[tenantID] = &reducedRate{accounts: accounts, readAt: time.Now()}
+	return accounts
+}
+
+// recolor moves the colors of an account's characters into a palette. Characters keep their
+// color when recoloring fails, which is logged rather than undoing the settings.
+func (s *AccessibilityService) recolor(ctx context.Context, accountID trainer.UserID, palette trainer.PaletteName) {
+	roster, err := s.rosters.Get(ctx, accountID)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to get characters to recolor", zap.String("accountId", accountID.String()), zap.Error(err))
+		return
+	}
+
+	for _, id := range roster.Characters() {
+		err := s.trainerRepo.FindOneAndUpdate(ctx, id, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+			if _, err := t.Recolor(palette); err != nil {
+				return nil, err
+			}
+			return t, nil
+		})
+		if err != nil {
+			s.logger.WithContext(ctx).Warn("Failed to recolor character",
+				zap.String("characterId", id.String()),
+				zap.String("palette", palette.String()),
+				zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,372 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/accessibility"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/bullet"
+	"github.com/danghamo/life/internal/domain/challenge"
+	"github.com/danghamo/life/internal/domain/character"
+	"github.com/danghamo/life/internal/domain/crafting"
+	"github.com/danghamo/life/internal/domain/equipment"
+	"github.com/danghamo/life/internal/domain/fairness"
+	"github.com/danghamo/life/internal/domain/friend"
+	"github.com/danghamo/life/internal/domain/loot"
+	"github.com/danghamo/life/internal/domain/match"
+	"github.com/danghamo/life/internal/domain/notification"
+	"github.com/danghamo/life/internal/domain/referral"
+	"github.com/danghamo/life/internal/domain/session"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/social"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/tutorial"
+	"github.com/danghamo/life/internal/domain/vault"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// TypeAccountPurge is the asynq task type that deletes a user's game data
+const TypeAccountPurge = "account:purge"
+
+// TypeCharacterPurge is the asynq task type that deletes a character once its deletion grace
+// period is over
+const TypeCharacterPurge = "character:purge"
+
+// accountPurgePayload is the asynq payload for TypeAccountPurge
+type accountPurgePayload struct {
+	UserID string `json:"user_id"`
+}
+
+// characterPurgePayload is the asynq payload for TypeCharacterPurge
+type characterPurgePayload struct {
+	UserID      string `json:"user_id"`
+	CharacterID string `json:"character_id"`
+}
+
+// purgeStep deletes one kind of data about a user or character
+type purgeStep struct {
+	name string
+	run  func(ctx context.Context, userID account.UserID) error
+}
+
+// UserDataRepositories are the stores holding data about a user that deletion must clear
+type UserDataRepositories struct {
+	Accounts    account.Repository
+	Revocations account.RevocationRepository
+	Emails      account.EmailRepository
+	Logins      account.LoginRepository
+	Activities  account.ActivityRepository
+	Pairings    account.PairingRepository
+	Trainers    trainer.Repository
+	Animals     animal.Repository
+	Equipment   equipment.Repository
+	Crafting    crafting.Repository
+	Vaults      vault.Repository
+	BulletStats bullet.PlayerStatsRepository
+	Social      social.Repository
+	Friends     friend.Repository
+	Referrals   referral.Repository
+	Fairness    fairness.Repository
+	LootLedger  loot.LedgerRepository // Nil without durable storage
+
+	Notifications notification.Repository
+	Sessions      session.Repository
+	Characters    character.Repository
+	AuthSessions  account.SessionRepository
+	Tutorials     tutorial.Repository
+	Accessibility accessibility.Repository
+	Challenges    challenge.Repository
+	Matches       match.Repository
+}
+
+// AccountDeletionService deletes a user's accounts and everything the game stores about them
+type AccountDeletionService struct {
+	logger     *logger.Logger
+	repos      UserDataRepositories
+	taskClient *asynq.Client
+}
+
+// NewAccountDeletionService creates a new account deletion service
+func NewAccountDeletionService(logger *logger.Logger, repos UserDataRepositories, taskClient *asynq.Client) *AccountDeletionService {
+	return &AccountDeletionService{
+		logger:     logger.WithComponent("account-deletion-service"),
+		repos:      repos,
+		taskClient: taskClient,
+	}
+}
+
+// Delete revokes the user's tokens, removes their sign-in methods and schedules the purge of
+// their game data. Once it returns the user is signed out everywhere and can't sign back in
+// to the deleted account; the purge finishes in the background and is retried until it does.
+func (s *AccountDeletionService) Delete(ctx context.Context, userID account.UserID) error {
+	if err := s.repos.Revocations.RevokeTokens(ctx, userID, time.Now()); err != nil {
+		return fmt.Errorf("failed to revoke tokens: %w", err)
+	}
+
+	payload, err := json.Marshal(accountPurgePayload{UserID: userID.String()})
+	if err != nil {
+		return err
+	}
+
+	task := asynq.NewTask(tenant.IDFromContext(ctx).Key(TypeAccountPurge), payload)
+	_, err = s.taskClient.EnqueueContext(ctx, task,
+		asynq.TaskID("account-purge:"+userID.String()),
+		asynq.MaxRetry(25),
+	)
+	if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		return fmt.Errorf("failed to schedule account purge: %w", err)
+	}
+
+	// The purge deletes accounts too; doing it now stops sign-ins while it is queued
+	if err := s.deleteAccounts(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete accounts: %w", err)
+	}
+
+	s.logger.WithContext(ctx).Info("Account deleted", zap.String("userID", userID.String()))
+	return nil
+}
+
+// HandleAccountPurgeTask processes TypeAccountPurge tasks
+func (s *AccountDeletionService) HandleAccountPurgeTask(ctx context.Context, task *asynq.Task) error {
+	var payload accountPurgePayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil || payload.UserID == "" {
+		return fmt.Errorf("invalid account purge payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return s.purge(ctx, account.UserID(payload.UserID))
+}
+
+// purge deletes everything stored about a user and each of their characters. Every step only
+// deletes what is still there, so a retry after a failure picks up where the last attempt
+// stopped; the character roster goes last so retries still find every character.
+func (s *AccountDeletionService) purge(ctx context.Context, userID account.UserID) error {
+	roster, err := s.repos.Characters.Get(ctx, trainer.UserID(userID))
+	if err != nil {
+		return fmt.Errorf("account purge step characters: %w", err)
+	}
+	steps := []purgeStep{{"accounts", s.deleteAccounts}}
+	for _, step := range s.characterSteps() {
+		run := step.run
+		steps = append(steps, purgeStep{step.name, func(ctx context.Context, _ account.UserID) error {
+			for _, id := range roster.Characters() {
+				if err := run(ctx, account.UserID(id)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}})
+	}
+	steps = append(steps,
+		purgeStep{"vault", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.Vaults.Delete(ctx, trainer.UserID(userID))
+		}},
+		purgeStep{"referrals", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.Referrals.DeleteUser(ctx, userID.String())
+		}},
+		purgeStep{"email", s.repos.Emails.Delete},
+		purgeStep{"login_history", s.repos.Logins.DeleteHistory},
+		purgeStep{"activity", s.repos.Activities.DeleteByUserID},
+		purgeStep{"pairing", s.repos.Pairings.DeleteByUserID},
+		purgeStep{"auth_sessions", s.repos.AuthSessions.DeleteUser},
+		purgeStep{"accessibility", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.Accessibility.DeleteUser(ctx, trainer.UserID(userID))
+		}},
+		purgeStep{"characters", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.Characters.DeleteUser(ctx, trainer.UserID(userID))
+		}},
+	)
+
+	for _, step := range steps {
+		if err := step.run(ctx, userID); err != nil {
+			s.logger.WithContext(ctx).Error("Account purge step failed",
+				zap.String("userID", userID.String()),
+				zap.String("step", step.name),
+				zap.Error(err))
+			return fmt.Errorf("account purge step %s: %w", step.name, err)
+		}
+	}
+
+	s.logger.WithContext(ctx).Info("Account data purged", zap.String("userID", userID.String()))
+	return nil
+}
+
+// HandleCharacterPurgeTask processes TypeCharacterPurge tasks. A character restored or
+// deleted again since the task was scheduled isn't due, and the task does nothing.
+func (s *AccountDeletionService) HandleCharacterPurgeTask(ctx context.Context, task *asynq.Task) error {
+	var payload characterPurgePayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil || payload.UserID == "" || payload.CharacterID == "" {
+		return fmt.Errorf("invalid character purge payload: %v: %w", err, asynq.SkipRetry)
+	}
+	userID := trainer.UserID(payload.UserID)
+	characterID := trainer.UserID(payload.CharacterID)
+
+	roster, err := s.repos.Characters.Get(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("character purge step characters: %w", err)
+	}
+	if !roster.PurgeDue(characterID, time.Now()) {
+		return nil
+	}
+
+	// Past the grace period the character can't be restored, so its data goes for good
+	for _, step := range s.characterSteps() {
+		if err := step.run(ctx, account.UserID(characterID)); err != nil {
+			s.logger.WithContext(ctx).Error("Character purge step failed",
+				zap.String("userID", userID.String()),
+				zap.String("characterId", characterID.String()),
+				zap.String("step", step.name),
+				zap.Error(err))
+			return fmt.Errorf("character purge step %s: %w", step.name, err)
+		}
+	}
+
+	// The slot is freed last so retries still find the character
+	err = s.repos.Characters.FindOneAndUpdate(ctx, userID, func(r *character.Roster) error {
+		r.RemoveCharacter(characterID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("character purge step characters: %w", err)
+	}
+
+	s.logger.WithContext(ctx).Info("Character data purged",
+		zap.String("userID", userID.String()),
+		zap.String("characterId", characterID.String()))
+	return nil
+}
+
+// characterSteps are the purge steps for data each character has of its own; they take the
+// character's ID
+func (s *AccountDeletionService) characterSteps() []purgeStep {
+	return []purgeStep{
+		{"crafting_jobs", s.deleteCraftingJobs},
+		{"animals", s.deleteAnimals},
+		{"equipment", s.deleteEquipment},
+		{"bullet_stats", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.BulletStats.DeleteStats(ctx, bullet.PlayerID(userID))
+		}},
+		{"trainer", s.deleteTrainer},
+		{"social", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.Social.DeleteRecent(ctx, userID.String())
+		}},
+		{"friends", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.Friends.DeleteUser(ctx, userID.String())
+		}},
+		{"rolls", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.Fairness.DeleteRollsByUser(ctx, userID.String())
+		}},
+		{"loot_ledger", func(ctx context.Context, userID account.UserID) error {
+			if s.repos.LootLedger == nil {
+				return nil
+			}
+			return s.repos.LootLedger.DeleteByUser(ctx, userID.String())
+		}},
+		{"notification_preferences", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.Notifications.DeleteUser(ctx, userID.String())
+		}},
+		{"session_snapshot", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.Sessions.DeleteUser(ctx, userID.String())
+		}},
+		{"tutorial", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.Tutorials.DeleteUser(ctx, trainer.UserID(userID))
+		}},
+		{"challenges", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.Challenges.DeleteUser(ctx, trainer.UserID(userID))
+		}},
+		{"match_history", func(ctx context.Context, userID account.UserID) error {
+			return s.repos.Matches.DeleteUser(ctx, userID.String())
+		}},
+	}
+}
+
+// deleteAccounts removes every sign-in method of the user
+func (s *AccountDeletionService) deleteAccounts(ctx context.Context, userID account.UserID) error {
+	accounts, err := s.repos.Accounts.ListByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, acc := range accounts {
+		if err := s.repos.Accounts.Delete(ctx, acc.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteCraftingJobs removes the trainer's uncollected crafting jobs
+func (s *AccountDeletionService) deleteCraftingJobs(ctx context.Context, userID account.UserID) error {
+	jobs, err := s.repos.Crafting.GetByTrainer(ctx, trainer.UserID(userID))
+	if err != nil {
+		return err
+	}
+
+	for _, job := range jobs {
+		if err := s.repos.Crafting.Delete(ctx, job.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteAnimals removes the trainer's captured animals and the equipment they wear
+func (s *AccountDeletionService) deleteAnimals(ctx context.Context, userID account.UserID) error {
+	animals, err := s.repos.Animals.GetByOwner(ctx, shared.ID(userID))
+	if err != nil {
+		return err
+	}
+
+	for _, a := range animals {
+		worn, err := s.repos.Equipment.GetByOwner(ctx, shared.ID(a.ID))
+		if err != nil {
+			return err
+		}
+		for _, e := range worn {
+			if err := s.repos.Equipment.Delete(ctx, e.ID); err != nil {
+				return err
+			}
+		}
+
+		if err := s.repos.Animals.Delete(ctx, a.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteEquipment removes equipment the trainer holds
+func (s *AccountDeletionService) deleteEquipment(ctx context.Context, userID account.UserID) error {
+	held, err := s.repos.Equipment.GetByTrainer(ctx, shared.ID(userID))
+	if err != nil {
+		return err
+	}
+
+	for _, e := range held {
+		if err := s.repos.Equipment.Delete(ctx, e.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteTrainer removes the trainer with its inventory and indices
+func (s *AccountDeletionService) deleteTrainer(ctx context.Context, userID account.UserID) error {
+	t, err := s.repos.Trainers.GetByID(ctx, trainer.UserID(userID))
+	if err != nil {
+		return err
+	}
+	if t == nil {
+		return nil
+	}
+
+	return s.repos.Trainers.Delete(ctx, trainer.UserID(userID))
+}
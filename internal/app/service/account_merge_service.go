@@ -0,0 +1,330 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/character"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/vault"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// MergeRepositories are the stores a merge of two users reads and changes
+type MergeRepositories struct {
+	Accounts   account.Repository
+	Merges     account.MergeRepository
+	Trainers   trainer.Repository
+	Animals    animal.Repository
+	Vaults     vault.Repository
+	Characters character.Repository
+}
+
+// pendingMerge is a merge being previewed or confirmed
+type pendingMerge struct {
+	merge         *account.Merge
+	plan          *trainer.MergePlan
+	absorbedVault *vault.Vault
+	alts          int // Additional characters of the absorbed user
+}
+
+// AccountMergeService joins two users when a guest links a social account that already
+// belongs to another user. The higher level trainer is kept and the other one's money, items
+// and party are folded into it; the absorbed user's sign-in methods move to the kept user and
+// the rest of their data is purged like a deleted account's.
+type AccountMergeService struct {
+	logger   *logger.Logger
+	repos    MergeRepositories
+	deletion *AccountDeletionService
+	activity *ActivityService
+}
+
+// NewAccountMergeService creates a new account merge service
+func NewAccountMergeService(logger *logger.Logger, repos MergeRepositories, deletion *AccountDeletionService, activity *ActivityService) *AccountMergeService {
+	return &AccountMergeService{
+		logger:   logger.WithComponent("account-merge-service"),
+		repos:    repos,
+		deletion: deletion,
+		activity: activity,
+	}
+}
+
+// Start records a pending merge of a guest with the user owning the social account they
+// tried to link
+func (s *AccountMergeService) Start(ctx context.Context, userID, otherUserID account.UserID, provider account.Provider, profile account.OAuthProfile) (*account.Merge, error) {
+	merge, err := account.NewMerge(userID, otherUserID, provider, profile)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repos.Merges.Save(ctx, merge); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Account merge started",
+		zap.String("userID", userID.String()),
+		zap.String("otherUserID", otherUserID.String()),
+		zap.String("provider", provider.String()))
+
+	return merge, nil
+}
+
+// Preview returns what confirming a pending merge would do, without changing anything
+func (s *AccountMergeService) Preview(ctx context.Context, userID account.UserID, token string) (*trainer.MergePlan, error) {
+	merge, err := s.pending(ctx, userID, token)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := s.plan(ctx, merge)
+	if err != nil {
+		return nil, err
+	}
+
+	var keptVault *vault.Vault
+	if p.plan.Kept != nil {
+		if keptVault, err = s.repos.Vaults.GetByUserID(ctx, p.plan.Kept.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.fold(p.plan.Kept, keptVault); err != nil {
+		return nil, err
+	}
+
+	return p.plan, nil
+}
+
+// Confirm merges the users of a pending merge. The token is consumed, so a merge is only
+// ever applied once.
+func (s *AccountMergeService) Confirm(ctx context.Context, userID account.UserID, token string) (*trainer.MergePlan, error) {
+	if _, err := s.pending(ctx, userID, token); err != nil {
+		return nil, err
+	}
+
+	merge, err := s.repos.Merges.Take(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if merge == nil {
+		return nil, invalidMergeToken()
+	}
+
+	p, err := s.plan(ctx, merge)
+	if err != nil {
+		return nil, err
+	}
+	plan := p.plan
+
+	// The kept trainer and vault are saved together, so items are never lost between them
+	if plan.Kept != nil {
+		err = s.repos.Vaults.Transfer(ctx, plan.Kept.ID, func(t *trainer.Trainer, v *vault.Vault) error {
+			if err := p.fold(t, v); err != nil {
+				return err
+			}
+			plan.Kept = t
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to merge trainers: %w", err)
+		}
+	} else if err := p.fold(nil, nil); err != nil {
+		return nil, err
+	}
+
+	if plan.Absorbed != nil {
+		if err := s.transferAnimals(ctx, plan); err != nil {
+			return nil, fmt.Errorf("failed to transfer animals: %w", err)
+		}
+	}
+
+	if err := s.moveAccounts(ctx, merge, plan); err != nil {
+		return nil, fmt.Errorf("failed to move accounts: %w", err)
+	}
+
+	// Nothing signs in to the absorbed user anymore; its tokens are revoked and what is left
+	// of its data goes
+	if err := s.deletion.Delete(ctx, account.UserID(plan.AbsorbedUserID)); err != nil {
+		return nil, fmt.Errorf("failed to delete absorbed user: %w", err)
+	}
+
+	merged := account.NewActivity(account.ActivityAccountsMerged)
+	merged.Provider = merge.Provider
+	s.activity.Record(ctx, account.UserID(plan.KeptUserID), merged)
+
+	s.logger.WithContext(ctx).Info("Accounts merged",
+		zap.String("keptUserID", plan.KeptUserID.String()),
+		zap.String("absorbedUserID", plan.AbsorbedUserID.String()),
+		zap.Int("conflicts", len(plan.Conflicts)))
+
+	return plan, nil
+}
+
+// pending retrieves a merge the user started
+func (s *AccountMergeService) pending(ctx context.Context, userID account.UserID, token string) (*account.Merge, error) {
+	merge, err := s.repos.Merges.Get(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if merge == nil || merge.UserID != userID {
+		return nil, invalidMergeToken()
+	}
+	return merge, nil
+}
+
+// plan loads both users' trainers and picks the one to keep, along with the absorbed user's
+// vault and characters
+func (s *AccountMergeService) plan(ctx context.Context, merge *account.Merge) (*pendingMerge, error) {
+	guestID, otherID := trainer.UserID(merge.UserID), trainer.UserID(merge.OtherUserID)
+	trainers, err := s.repos.Trainers.GetMany(ctx, []trainer.UserID{guestID, otherID})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &trainer.MergePlan{}
+	plan.Kept, plan.Absorbed = trainer.KeepOnMerge(trainers[otherID], trainers[guestID])
+
+	// Without trainers the social account's user is kept
+	plan.KeptUserID, plan.AbsorbedUserID = otherID, guestID
+	if plan.Kept != nil && plan.Kept.ID == guestID {
+		plan.KeptUserID, plan.AbsorbedUserID = guestID, otherID
+	}
+
+	absorbedVault, err := s.repos.Vaults.GetByUserID(ctx, plan.AbsorbedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	roster, err := s.repos.Characters.Get(ctx, plan.AbsorbedUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pendingMerge{
+		merge:         merge,
+		plan:          plan,
+		absorbedVault: absorbedVault,
+		alts:          len(roster.Characters()) - 1,
+	}, nil
+}
+
+// fold absorbs the absorbed trainer into kept and stores what doesn't fit, along with the
+// absorbed user's vault, in the kept user's vault. Without a kept trainer there is nothing to
+// fold and only the conflicts are reported. A retried fold starts over.
+func (p *pendingMerge) fold(kept *trainer.Trainer, keptVault *vault.Vault) error {
+	plan := p.plan
+	plan.Result, plan.Vaulted, plan.Lost, plan.Conflicts = nil, 0, 0, nil
+
+	if kept != nil {
+		var stored []*trainer.Item
+		if plan.Absorbed != nil {
+			result, err := kept.Absorb(plan.Absorbed)
+			if err != nil {
+				return err
+			}
+			plan.Result = result
+			plan.Conflicts = append(plan.Conflicts, result.Conflicts...)
+			stored = append(stored, result.Overflow...)
+		}
+
+		for _, id := range slices.Sorted(maps.Keys(p.absorbedVault.Items)) {
+			stored = append(stored, p.absorbedVault.Items[id])
+		}
+
+		for _, item := range stored {
+			if err := keptVault.Deposit(item); err != nil {
+				plan.Lost++
+				continue
+			}
+			plan.Vaulted++
+		}
+	}
+
+	if plan.Lost > 0 {
+		plan.Conflicts = append(plan.Conflicts, trainer.MergeConflict{
+			Kind:   trainer.ConflictVaultFull,
+			Detail: fmt.Sprintf("%d item stacks don't fit in the vault and are lost", plan.Lost),
+		})
+	}
+	if p.alts > 0 {
+		plan.Conflicts = append(plan.Conflicts, trainer.MergeConflict{
+			Kind:   trainer.ConflictCharacters,
+			Detail: fmt.Sprintf("%d additional characters are deleted", p.alts),
+		})
+	}
+
+	return nil
+}
+
+// transferAnimals hands the absorbed trainer's animals to the kept trainer, moving the party
+// members that didn't fit in the party to storage
+func (s *AccountMergeService) transferAnimals(ctx context.Context, plan *trainer.MergePlan) error {
+	animals, err := s.repos.Animals.GetByOwner(ctx, shared.ID(plan.Absorbed.ID))
+	if err != nil {
+		return err
+	}
+
+	for _, owned := range animals {
+		err := s.repos.Animals.FindOneAndUpdate(ctx, owned.ID, func(a *animal.Animal) (*animal.Animal, error) {
+			if err := a.TransferTo(shared.ID(plan.Kept.ID)); err != nil {
+				return nil, err
+			}
+			if plan.Result != nil && slices.Contains(plan.Result.Benched, shared.ID(a.ID)) {
+				if err := a.ChangeState(animal.InStorage); err != nil {
+					return nil, err
+				}
+			}
+			return a, nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// moveAccounts moves the absorbed user's sign-in methods to the kept user and adds the social
+// account the guest linked, unless it is one of them already
+func (s *AccountMergeService) moveAccounts(ctx context.Context, merge *account.Merge, plan *trainer.MergePlan) error {
+	accounts, err := s.repos.Accounts.ListByUserID(ctx, account.UserID(plan.AbsorbedUserID))
+	if err != nil {
+		return err
+	}
+
+	for _, acc := range accounts {
+		err := s.repos.Accounts.FindOneAndUpdate(ctx, acc.ID, func(a *account.Account) (*account.Account, error) {
+			return a, a.MergeInto(account.UserID(plan.KeptUserID))
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	existing, err := s.repos.Accounts.GetByProvider(ctx, merge.Provider, merge.Profile.ProviderUserID)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return nil
+	}
+
+	linked, err := account.NewAccountWithUserID(merge.Provider, merge.Profile, account.UserID(plan.KeptUserID))
+	if err != nil {
+		return err
+	}
+	return s.repos.Accounts.FindOneAndInsert(ctx, linked.ID, func() (*account.Account, error) {
+		return linked, nil
+	})
+}
+
+// invalidMergeToken is returned for merge tokens that are unknown, expired or someone else's
+func invalidMergeToken() error {
+	return shared.NewDomainError(shared.ErrCodeInvalidMergeToken, "Merge is invalid or expired, link the account again")
+}
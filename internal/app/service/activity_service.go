@@ -0,0 +1,40 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// ActivityService keeps the account activity timeline players review through auth.ActivityLog
+type ActivityService struct {
+	logger       *logger.Logger
+	activityRepo account.ActivityRepository
+}
+
+// NewActivityService creates a new activity service
+func NewActivityService(logger *logger.Logger, activityRepo account.ActivityRepository) *ActivityService {
+	return &ActivityService{
+		logger:       logger.WithComponent("activity-service"),
+		activityRepo: activityRepo,
+	}
+}
+
+// Record adds an activity to the user's timeline. Failures are logged rather than returned,
+// so a timeline outage never blocks the action being recorded.
+func (s *ActivityService) Record(ctx context.Context, userID account.UserID, activity *account.Activity) {
+	if err := s.activityRepo.Record(ctx, userID, activity); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to record account activity",
+			zap.String("userID", userID.String()),
+			zap.String("type", string(activity.Type)),
+			zap.Error(err))
+	}
+}
+
+// List returns the user's recent activities, newest first
+func (s *ActivityService) List(ctx context.Context, userID string, limit int) ([]*account.Activity, error) {
+	return s.activityRepo.ListByUserID(ctx, account.UserID(userID), limit)
+}
@@ -0,0 +1,326 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/admin"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/character"
+	"github.com/danghamo/life/internal/domain/friend"
+	"github.com/danghamo/life/internal/domain/liveops"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// AdminRepositories are the stores the admin API inspects and changes
+type AdminRepositories struct {
+	Trainers    trainer.Repository
+	Animals     animal.Repository
+	Presence    friend.PresenceRepository
+	Revocations account.RevocationRepository
+	Characters  character.Repository
+}
+
+// ConnectionCounter counts the clients connected to this server over one transport
+type ConnectionCounter interface {
+	GetClientCount() int
+}
+
+// Disconnector closes users' connections on every server
+type Disconnector interface {
+	Disconnect(userIDs []string)
+}
+
+// AdminTransports are the live connections admins inspect and cut
+type AdminTransports struct {
+	SSE       ConnectionCounter
+	WebSocket ConnectionCounter
+	Fanout    Disconnector
+}
+
+// AdminService carries out the operational interventions of the admin API: finding, kicking
+// and teleporting players, granting them items and money, removing wild animals and reading
+// server counters. Every change is logged with the admin who made it.
+type AdminService struct {
+	logger     *logger.Logger
+	repos      AdminRepositories
+	transports AdminTransports
+	movement   *TenantMovement
+	validator  *MovementValidator
+	terrain    trainer.Terrain
+	eventBus   *cqrs.EventBus
+	push       *cqrscommands.SSEBroadcastHelper
+}
+
+// NewAdminService creates a new admin service moving trainers through movement
+func NewAdminService(logger *logger.Logger, repos AdminRepositories, transports AdminTransports, movement *TenantMovement, validator *MovementValidator, terrain trainer.Terrain, eventBus *cqrs.EventBus, push *cqrscommands.SSEBroadcastHelper) *AdminService {
+	return &AdminService{
+		logger:     logger.WithComponent("admin-service"),
+		repos:      repos,
+		transports: transports,
+		movement:   movement,
+		validator:  validator,
+		terrain:    terrain,
+		eventBus:   eventBus,
+		push:       push,
+	}
+}
+
+// OnlinePlayers returns the players connected to any server, by nickname, along with how many
+// there are. At most admin.MaxListedPlayers are listed.
+func (s *AdminService) OnlinePlayers(ctx context.Context) ([]admin.OnlinePlayer, int, error) {
+	userIDs, err := s.repos.Presence.ListOnline(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	players := make([]admin.OnlinePlayer, 0, min(len(userIDs), admin.MaxListedPlayers))
+	for _, userID := range userIDs[:min(len(userIDs), admin.MaxListedPlayers)] {
+		t, err := s.movement.Position(ctx, userID)
+		if err != nil {
+			// Presence outlives deleted accounts by a few seconds
+			s.logger.WithContext(ctx).Debug("Skipping online player without trainer",
+				zap.String("userId", userID),
+				zap.Error(err))
+			continue
+		}
+		players = append(players, admin.OnlinePlayer{
+			UserID:   userID,
+			Nickname: t.Nickname,
+			Level:    t.Level.Value(),
+			Position: t.Position,
+			IsMoving: t.Movement.IsMoving,
+		})
+	}
+
+	slices.SortFunc(players, func(a, b admin.OnlinePlayer) int {
+		return strings.Compare(a.Nickname, b.Nickname)
+	})
+	return players, len(userIDs), nil
+}
+
+// Kick signs a player out everywhere: their tokens are revoked, so clients can't reconnect
+// without signing in again, and their connections to every server are closed
+func (s *AdminService) Kick(ctx context.Context, adminID, userID, reason string) error {
+	if _, err := s.findTrainer(ctx, userID); err != nil {
+		return err
+	}
+
+	if err := s.repos.Revocations.RevokeTokens(ctx, account.UserID(userID), time.Now()); err != nil {
+		return err
+	}
+	s.transports.Fanout.Disconnect([]string{userID})
+
+	s.logger.WithContext(ctx).Info("Player kicked",
+		zap.String("adminID", adminID),
+		zap.String("userId", userID),
+		zap.String("reason", reason))
+	return nil
+}
+
+// Teleport puts a trainer on a walkable position, stopped, and returns the moved trainer
+func (s *AdminService) Teleport(ctx context.Context, adminID, userID string, position shared.Position) (*trainer.Trainer, error) {
+	if _, err := s.findTrainer(ctx, userID); err != nil {
+		return nil, err
+	}
+	if !s.terrain.IsWalkablePosition(position) {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidMove, "Destination is not walkable")
+	}
+
+	moved, err := s.movement.Move(ctx, userID, func(t *trainer.Trainer) error {
+		return t.MoveTo(position)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The jump must not count against the trainer's speed on its next command
+	s.validator.Forget(ctx, userID)
+
+	event := &cqrscommands.TrainerStoppedEvent{
+		UserID:    userID,
+		Nickname:  moved.Nickname,
+		Color:     moved.Color,
+		Showcase:  moved.NameplateShowcase(),
+		Position:  moved.Position,
+		Movement:  moved.Movement,
+		Timestamp: time.Now(),
+		RequestID: fmt.Sprintf("teleport-%s-%d", userID, time.Now().UnixNano()),
+		Changes:   map[string]interface{}{"position": moved.Position},
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish teleport stop",
+			zap.String("userId", userID),
+			zap.Error(err))
+	}
+
+	s.logger.WithContext(ctx).Info("Trainer teleported",
+		zap.String("adminID", adminID),
+		zap.String("userId", userID),
+		zap.Float64("x", position.X),
+		zap.Float64("y", position.Y))
+	return moved, nil
+}
+
+// Grant adds money and item stacks to a trainer in one update and tells the player. Nothing
+// is granted when any item is invalid or doesn't fit.
+func (s *AdminService) Grant(ctx context.Context, adminID, userID string, money int, items []admin.GrantItem) (*trainer.Trainer, error) {
+	if money < 0 {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidAmount, "Money must not be negative")
+	}
+	if money == 0 && len(items) == 0 {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Nothing to grant")
+	}
+	for _, item := range items {
+		if item.Quantity > liveops.MaxGrantQuantity {
+			return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidAmount, "Cannot grant more than %d items at once", liveops.MaxGrantQuantity)
+		}
+	}
+
+	if _, err := s.findTrainer(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	var updated *trainer.Trainer
+	err := s.repos.Trainers.FindOneAndUpdate(ctx, trainer.UserID(userID), func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		if money > 0 {
+			if err := t.EarnMoney(money); err != nil {
+				return nil, err
+			}
+		}
+		for _, grant := range items {
+			item, err := trainer.NewItemStack(trainer.ItemType(grant.ItemType), grant.Name, grant.Quantity)
+			if err != nil {
+				return nil, err
+			}
+			if err := t.Inventory.AddItem(item); err != nil {
+				return nil, err
+			}
+		}
+
+		t.UpdatedAt = shared.NewTimestamp()
+		updated = t
+		return t, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	params := map[string]any{
+		"money": money,
+		"items": items,
+	}
+	if err := s.push.BroadcastToUsers(ctx, []string{userID}, "trainer.granted", params); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to notify granted player",
+			zap.String("userId", userID),
+			zap.Error(err))
+	}
+
+	s.logger.WithContext(ctx).Info("Granted to trainer",
+		zap.String("adminID", adminID),
+		zap.String("userId", userID),
+		zap.Int("money", money),
+		zap.Int("items", len(items)))
+	return updated, nil
+}
+
+// GrantGems credits premium currency to the user owning a character, e.g. to make up for an
+// outage, and returns their new balance
+func (s *AdminService) GrantGems(ctx context.Context, adminID, userID string, gems int) (int, error) {
+	owner := trainer.UserID(userID).AccountID()
+
+	var balance int
+	err := s.repos.Characters.FindOneAndUpdate(ctx, owner, func(r *character.Roster) error {
+		if err := r.AddGems(gems); err != nil {
+			return err
+		}
+		balance = r.Gems
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.logger.WithContext(ctx).Info("Granted gems to user",
+		zap.String("adminID", adminID),
+		zap.String("userId", owner.String()),
+		zap.Int("gems", gems))
+	return balance, nil
+}
+
+// DespawnAnimal removes a wild animal from the world. Animals trainers own can't be despawned.
+func (s *AdminService) DespawnAnimal(ctx context.Context, adminID, animalID string) error {
+	wild, err := s.repos.Animals.GetByID(ctx, animal.AnimalID(animalID))
+	if err != nil {
+		return err
+	}
+	if wild == nil {
+		return shared.ErrNotFound("Animal")
+	}
+	if !wild.IsWild() {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "Only wild animals can be despawned")
+	}
+
+	if err := s.repos.Animals.Delete(ctx, wild.ID); err != nil {
+		return err
+	}
+
+	params := map[string]any{
+		"animal_id": animalID,
+		"position":  wild.Position,
+	}
+	if err := s.push.BroadcastToAll(ctx, "animal.despawned", params); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to announce despawned animal",
+			zap.String("animalID", animalID),
+			zap.Error(err))
+	}
+
+	s.logger.WithContext(ctx).Info("Animal despawned",
+		zap.String("adminID", adminID),
+		zap.String("animalID", animalID),
+		zap.String("animalType", wild.AnimalType.String()))
+	return nil
+}
+
+// Stats returns the counters of this server; only the online player count covers every server
+func (s *AdminService) Stats(ctx context.Context) (*admin.ServerStats, error) {
+	userIDs, err := s.repos.Presence.ListOnline(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &admin.ServerStats{
+		SSEClients:       s.transports.SSE.GetClientCount(),
+		WebSocketClients: s.transports.WebSocket.GetClientCount(),
+		OnlinePlayers:    len(userIDs),
+		Movement:         make(map[string]json.RawMessage),
+	}
+	movementStats.Do(func(kv expvar.KeyValue) {
+		stats.Movement[kv.Key] = json.RawMessage(kv.Value.String())
+	})
+	return stats, nil
+}
+
+// findTrainer checks that a user has a trainer
+func (s *AdminService) findTrainer(ctx context.Context, userID string) (*trainer.Trainer, error) {
+	t, err := s.repos.Trainers.GetByID(ctx, trainer.UserID(userID))
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, shared.ErrNotFound("Trainer")
+	}
+	return t, nil
+}
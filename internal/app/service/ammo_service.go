@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/bullet"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+// Reasons a trainer's ammo changed, as announced to clients
+const (
+	AmmoReasonReload   = "reload"
+	AmmoReasonPurchase = "purchase"
+)
+
+// AmmoService reloads weapons and sells ammo. Reloads fill the magazine from the reserve
+// ammo, unpacking ammo boxes carried in the inventory when the reserve runs short.
+type AmmoService struct {
+	logger      *logger.Logger
+	trainerRepo trainer.Repository
+	statsRepo   bullet.PlayerStatsRepository
+	eventBus    *cqrs.EventBus
+}
+
+// NewAmmoService creates a new ammo service
+func NewAmmoService(logger *logger.Logger, trainerRepo trainer.Repository, statsRepo bullet.PlayerStatsRepository, eventBus *cqrs.EventBus) *AmmoService {
+	return &AmmoService{
+		logger:      logger.WithComponent("ammo-service"),
+		trainerRepo: trainerRepo,
+		statsRepo:   statsRepo,
+		eventBus:    eventBus,
+	}
+}
+
+// Stats returns the trainer's firing stats, giving players who have never fired the default
+// weapon with a full magazine
+func (s *AmmoService) Stats(ctx context.Context, userID trainer.UserID) (*bullet.PlayerStats, error) {
+	return s.statsRepo.LoadStatsWithDefaults(ctx, bullet.PlayerID(userID), bullet.DefaultWeapon.MagazineSize(), bullet.DefaultWeapon)
+}
+
+// Fire fires a bullet from the trainer's weapon. The ammo, reload and cooldown checks and
+// the shot itself happen in one update of the stats, so concurrent shots can't both take the
+// last round or skip the cooldown.
+func (s *AmmoService) Fire(ctx context.Context, userID trainer.UserID, position shared.Position, direction bullet.Direction) (*bullet.Bullet, *bullet.PlayerStats, error) {
+	now := time.Now()
+	var fired *bullet.Bullet
+	var stats *bullet.PlayerStats
+
+	err := s.statsRepo.FindOneAndUpdate(ctx, bullet.PlayerID(userID), func(current *bullet.PlayerStats) (*bullet.PlayerStats, error) {
+		shot, err := bullet.NewBullet(bullet.PlayerID(userID), current.WeaponType, position, direction)
+		if err != nil {
+			return nil, err
+		}
+		if err := current.Fire(now); err != nil {
+			return nil, err
+		}
+		fired, stats = shot, current
+		return current, nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return fired, stats, nil
+}
+
+// Reload reloads the trainer's weapon. Ammo boxes are unpacked into the reserve first when it
+// cannot fill the magazine; boxes are given back if the reload fails.
+func (s *AmmoService) Reload(ctx context.Context, userID trainer.UserID) (*bullet.PlayerStats, error) {
+	stats, err := s.Stats(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var unpacked []*trainer.Item
+	boxes := 0
+	if short := stats.WeaponType.MagazineSize() - stats.AmmoCount - stats.ReserveAmmo; short > 0 && !stats.IsReloading(now) {
+		wanted := min((short+bullet.AmmoBoxRounds-1)/bullet.AmmoBoxRounds, stats.ReserveSpace()/bullet.AmmoBoxRounds)
+		unpacked, boxes, err = s.unpackBoxes(ctx, userID, wanted)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// The stats read above only sized the unpacking; the reload applies to the stats as they
+	// are now, so shots and purchases in between aren't lost
+	var reloaded *bullet.PlayerStats
+	err = s.statsRepo.FindOneAndUpdate(ctx, bullet.PlayerID(userID), func(current *bullet.PlayerStats) (*bullet.PlayerStats, error) {
+		if err := addBoxes(current, boxes); err != nil {
+			return nil, err
+		}
+		if err := current.Reload(now); err != nil {
+			return nil, err
+		}
+		reloaded = current
+		return current, nil
+	})
+	if err != nil {
+		s.refundBoxes(ctx, userID, unpacked)
+		return nil, err
+	}
+
+	s.publishAmmoChanged(ctx, userID, reloaded, AmmoReasonReload, boxes)
+
+	s.logger.WithContext(ctx).Debug("Weapon reloaded",
+		zap.String("userID", userID.String()),
+		zap.Int("magazine", reloaded.AmmoCount),
+		zap.Int("reserve", reloaded.ReserveAmmo),
+		zap.Int("boxesUsed", boxes))
+
+	return reloaded, nil
+}
+
+// addBoxes adds the rounds of ammo boxes to the reserve, failing when they don't all fit
+func addBoxes(stats *bullet.PlayerStats, boxes int) error {
+	rounds := boxes * bullet.AmmoBoxRounds
+	if rounds > stats.ReserveSpace() {
+		return shared.NewDomainErrorf(shared.ErrCodeReserveFull, "Reserve ammo has room for %d more rounds", stats.ReserveSpace())
+	}
+	stats.AddReserveAmmo(rounds)
+	return nil
+}
+
+// unpackBoxes takes up to wanted ammo boxes out of the trainer's inventory, returning the
+// removed stacks and how many boxes they hold
+func (s *AmmoService) unpackBoxes(ctx context.Context, userID trainer.UserID, wanted int) ([]*trainer.Item, int, error) {
+	var removed []*trainer.Item
+	var changed *trainer.BulkResult
+	count := 0
+
+	err := s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		removed, changed, count = nil, nil, min(wanted, t.Inventory.CountItemsByType(trainer.AmmoBox))
+		if count <= 0 {
+			return nil, nil
+		}
+
+		items, err := t.Inventory.RemoveItemsByType(trainer.AmmoBox, count)
+		if err != nil {
+			return nil, err
+		}
+		removed = items
+		changed = trainer.NewBulkResult(BulkActionUse, t, items)
+		return t, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if changed != nil {
+		publishInventoryChanged(ctx, s.eventBus, s.logger, userID, changed)
+	}
+	return removed, max(count, 0), nil
+}
+
+// refundBoxes puts unpacked ammo boxes back into the inventory when the reload failed
+func (s *AmmoService) refundBoxes(ctx context.Context, userID trainer.UserID, boxes []*trainer.Item) {
+	if len(boxes) == 0 {
+		return
+	}
+
+	err := s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		for _, box := range boxes {
+			if err := t.Inventory.AddItem(box); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to refund ammo boxes",
+			zap.String("userID", userID.String()),
+			zap.Error(err))
+	}
+}
+
+// BuyAmmo buys ammo boxes straight into the trainer's reserve ammo and returns the stats with
+// the money left. All boxes have to fit the reserve.
+func (s *AmmoService) BuyAmmo(ctx context.Context, userID trainer.UserID, boxes int) (*bullet.PlayerStats, int, error) {
+	if boxes <= 0 {
+		return nil, 0, shared.NewDomainError(shared.ErrCodeInvalidAmount, "Boxes must be positive")
+	}
+
+	stats, err := s.Stats(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	// Checked again when the rounds are added, but most full reserves are turned down here
+	// without charging
+	if boxes*bullet.AmmoBoxRounds > stats.ReserveSpace() {
+		return nil, 0, shared.NewDomainErrorf(shared.ErrCodeReserveFull, "Reserve ammo has room for %d more rounds", stats.ReserveSpace())
+	}
+
+	cost := boxes * bullet.AmmoBoxPrice
+	money := 0
+	err = s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		if !t.Money.CanAfford(cost) {
+			return nil, shared.ErrInsufficientFunds()
+		}
+		if err := t.SpendMoney(cost); err != nil {
+			return nil, err
+		}
+		money = t.Money.Amount()
+		return t, nil
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	err = s.statsRepo.FindOneAndUpdate(ctx, bullet.PlayerID(userID), func(current *bullet.PlayerStats) (*bullet.PlayerStats, error) {
+		if err := addBoxes(current, boxes); err != nil {
+			return nil, err
+		}
+		stats = current
+		return current, nil
+	})
+	if err != nil {
+		s.refundMoney(ctx, userID, cost)
+		return nil, 0, err
+	}
+
+	s.publishAmmoChanged(ctx, userID, stats, AmmoReasonPurchase, boxes)
+
+	s.logger.WithContext(ctx).Info("Ammo bought",
+		zap.String("userID", userID.String()),
+		zap.Int("boxes", boxes),
+		zap.Int("cost", cost))
+
+	return stats, money, nil
+}
+
+// refundMoney gives the money for ammo back when it could not be added to the reserve
+func (s *AmmoService) refundMoney(ctx context.Context, userID trainer.UserID, amount int) {
+	err := s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		if err := t.EarnMoney(amount); err != nil {
+			return nil, err
+		}
+		return t, nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to refund ammo purchase",
+			zap.String("userID", userID.String()),
+			zap.Int("amount", amount),
+			zap.Error(err))
+	}
+}
+
+// publishAmmoChanged tells the trainer's clients about its new ammo
+func (s *AmmoService) publishAmmoChanged(ctx context.Context, userID trainer.UserID, stats *bullet.PlayerStats, reason string, boxes int) {
+	event := &cqrscommands.AmmoChangedEvent{
+		UserID:         userID.String(),
+		WeaponType:     stats.WeaponType,
+		Magazine:       stats.AmmoCount,
+		Reserve:        stats.ReserveAmmo,
+		ReloadingUntil: stats.ReloadingUntil,
+		Reason:         reason,
+		BoxesUsed:      boxes,
+		Timestamp:      time.Now(),
+		RequestID:      requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish ammo changed event",
+			zap.String("userID", userID.String()),
+			zap.Error(err))
+	}
+}
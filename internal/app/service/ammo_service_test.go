@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/bullet"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// newTestEventBus publishes to an in-memory channel nobody reads
+func newTestEventBus(t *testing.T) *cqrs.EventBus {
+	t.Helper()
+
+	publisher := gochannel.NewGoChannel(gochannel.Config{}, watermill.NopLogger{})
+	t.Cleanup(func() { publisher.Close() })
+
+	eventBus, err := cqrs.NewEventBusWithConfig(publisher, cqrs.EventBusConfig{
+		GeneratePublishTopic: func(params cqrs.GenerateEventPublishTopicParams) (string, error) {
+			return params.EventName, nil
+		},
+		Marshaler: cqrs.JSONMarshaler{},
+	})
+	require.NoError(t, err)
+	return eventBus
+}
+
+// memoryStats keeps player stats in a map. beforeUpdate runs before every update, standing
+// in for a request that changes the stats concurrently; failUpdate makes updates fail.
+type memoryStats struct {
+	bullet.PlayerStatsRepository
+	stats        map[bullet.PlayerID]bullet.PlayerStats
+	beforeUpdate func(*bullet.PlayerStats)
+	failUpdate   error
+}
+
+func newMemoryStats() *memoryStats {
+	return &memoryStats{stats: map[bullet.PlayerID]bullet.PlayerStats{}}
+}
+
+func (m *memoryStats) current(playerID bullet.PlayerID) bullet.PlayerStats {
+	if stats, ok := m.stats[playerID]; ok {
+		return stats
+	}
+	return *bullet.NewPlayerStats(playerID, bullet.DefaultWeapon.MagazineSize(), bullet.DefaultWeapon)
+}
+
+func (m *memoryStats) LoadStatsWithDefaults(ctx context.Context, playerID bullet.PlayerID, defaultAmmo int, defaultWeapon bullet.WeaponType) (*bullet.PlayerStats, error) {
+	stats := m.current(playerID)
+	return &stats, nil
+}
+
+func (m *memoryStats) FindOneAndUpdate(ctx context.Context, playerID bullet.PlayerID, callback func(*bullet.PlayerStats) (*bullet.PlayerStats, error)) error {
+	if m.beforeUpdate != nil {
+		stats := m.current(playerID)
+		m.beforeUpdate(&stats)
+		m.stats[playerID] = stats
+	}
+	if m.failUpdate != nil {
+		return m.failUpdate
+	}
+
+	stats := m.current(playerID)
+	updated, err := callback(&stats)
+	if err != nil || updated == nil {
+		return err
+	}
+	m.stats[playerID] = *updated
+	return nil
+}
+
+func newAmmoTest(t *testing.T) (*AmmoService, *memoryTrainers, *memoryStats, *trainer.Trainer) {
+	t.Helper()
+
+	tr, err := trainer.NewTrainer("shooter", "Shooter")
+	require.NoError(t, err)
+	trainers := &memoryTrainers{trainers: map[trainer.UserID]*trainer.Trainer{tr.ID: tr}}
+	stats := newMemoryStats()
+
+	return NewAmmoService(logger.NewDefault(), trainers, stats, newTestEventBus(t)), trainers, stats, tr
+}
+
+func TestAmmoService_BuyAmmo(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("keeps shots fired meanwhile", func(t *testing.T) {
+		s, trainers, stats, tr := newAmmoTest(t)
+		magazine := bullet.DefaultWeapon.MagazineSize()
+		stats.beforeUpdate = func(current *bullet.PlayerStats) {
+			require.NoError(t, current.Fire(time.Now()))
+		}
+
+		bought, money, err := s.BuyAmmo(ctx, tr.ID, 1)
+		require.NoError(t, err)
+		assert.Equal(t, magazine-1, bought.AmmoCount)
+		assert.Equal(t, bullet.DefaultWeapon.StartingReserveAmmo()+bullet.AmmoBoxRounds, bought.ReserveAmmo)
+		assert.Equal(t, 1000-bullet.AmmoBoxPrice, money)
+		assert.Equal(t, money, trainers.trainers[tr.ID].Money.Amount())
+	})
+
+	t.Run("refunds the money when the rounds can't be added", func(t *testing.T) {
+		s, trainers, stats, tr := newAmmoTest(t)
+		stats.failUpdate = redis.TxFailedErr
+
+		_, _, err := s.BuyAmmo(ctx, tr.ID, 1)
+		assert.ErrorIs(t, err, redis.TxFailedErr)
+		assert.Equal(t, 1000, trainers.trainers[tr.ID].Money.Amount())
+	})
+
+	t.Run("refunds the money when the reserve filled up meanwhile", func(t *testing.T) {
+		s, trainers, stats, tr := newAmmoTest(t)
+		stats.beforeUpdate = func(current *bullet.PlayerStats) {
+			current.AddReserveAmmo(current.ReserveSpace())
+		}
+
+		_, _, err := s.BuyAmmo(ctx, tr.ID, 1)
+		code, ok := shared.DomainErrorCode(err)
+		require.True(t, ok)
+		assert.Equal(t, shared.ErrCodeReserveFull, code)
+		assert.Equal(t, 1000, trainers.trainers[tr.ID].Money.Amount())
+	})
+}
+
+func TestAmmoService_Reload(t *testing.T) {
+	ctx := context.Background()
+	playerID := bullet.PlayerID("shooter")
+
+	// An empty magazine and reserve, so reloading has to unpack the boxes
+	empty := *bullet.NewPlayerStats(playerID, 0, bullet.DefaultWeapon)
+	empty.ReserveAmmo = 0
+
+	t.Run("unpacks ammo boxes", func(t *testing.T) {
+		s, trainers, stats, tr := newAmmoTest(t)
+		boxes, err := trainer.NewItemStack(trainer.AmmoBox, "Ammo Box", 2)
+		require.NoError(t, err)
+		require.NoError(t, tr.Inventory.AddItem(boxes))
+		stats.stats[playerID] = empty
+
+		reloaded, err := s.Reload(ctx, tr.ID)
+		require.NoError(t, err)
+		assert.Equal(t, bullet.DefaultWeapon.MagazineSize(), reloaded.AmmoCount)
+		assert.Equal(t, bullet.AmmoBoxRounds-bullet.DefaultWeapon.MagazineSize(), reloaded.ReserveAmmo)
+		assert.True(t, reloaded.IsReloading(time.Now()))
+		assert.Equal(t, 1, trainers.trainers[tr.ID].Inventory.CountItemsByType(trainer.AmmoBox))
+		assert.Equal(t, *reloaded, stats.stats[playerID])
+	})
+
+	t.Run("gives the boxes back when the reload fails", func(t *testing.T) {
+		s, trainers, stats, tr := newAmmoTest(t)
+		boxes, err := trainer.NewItemStack(trainer.AmmoBox, "Ammo Box", 1)
+		require.NoError(t, err)
+		require.NoError(t, tr.Inventory.AddItem(boxes))
+		stats.stats[playerID] = empty
+		stats.failUpdate = redis.TxFailedErr
+
+		_, err = s.Reload(ctx, tr.ID)
+		assert.ErrorIs(t, err, redis.TxFailedErr)
+		assert.Equal(t, 1, trainers.trainers[tr.ID].Inventory.CountItemsByType(trainer.AmmoBox))
+	})
+}
+
+func TestAmmoService_Fire(t *testing.T) {
+	ctx := context.Background()
+	playerID := bullet.PlayerID("shooter")
+	position := shared.NewPosition(3, 4)
+	direction := bullet.NewDirection(1, 0)
+
+	reloading := *bullet.NewPlayerStats(playerID, 5, bullet.DefaultWeapon)
+	until := time.Now().Add(time.Minute)
+	reloading.ReloadingUntil = &until
+
+	cooling := *bullet.NewPlayerStats(playerID, 5, bullet.DefaultWeapon)
+	cooling.LastFireTime = time.Now()
+
+	rejections := []struct {
+		name  string
+		stats bullet.PlayerStats
+		code  int
+	}{
+		{"empty magazine", *bullet.NewPlayerStats(playerID, 0, bullet.DefaultWeapon), shared.ErrCodeNoAmmo},
+		{"reloading", reloading, shared.ErrCodeReloading},
+		{"cooling down", cooling, shared.ErrCodeCoolingDown},
+	}
+	for _, tt := range rejections {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, stats, tr := newAmmoTest(t)
+			stats.stats[playerID] = tt.stats
+
+			_, _, err := s.Fire(ctx, tr.ID, position, direction)
+			code, ok := shared.DomainErrorCode(err)
+			require.True(t, ok)
+			assert.Equal(t, tt.code, code)
+			assert.Equal(t, tt.stats, stats.stats[playerID], "rejected shots leave the stats alone")
+		})
+	}
+
+	t.Run("takes a round", func(t *testing.T) {
+		s, _, stats, tr := newAmmoTest(t)
+
+		fired, fireStats, err := s.Fire(ctx, tr.ID, position, direction)
+		require.NoError(t, err)
+		assert.Equal(t, position, fired.StartPos)
+		assert.Equal(t, bullet.DefaultWeapon, fired.WeaponType)
+		assert.Equal(t, bullet.DefaultWeapon.MagazineSize()-1, fireStats.AmmoCount)
+		assert.Equal(t, *fireStats, stats.stats[playerID])
+
+		_, _, err = s.Fire(ctx, tr.ID, position, direction)
+		code, _ := shared.DomainErrorCode(err)
+		assert.Equal(t, shared.ErrCodeCoolingDown, code, "the next shot waits for the cooldown")
+	})
+
+	t.Run("checks the stats it updates", func(t *testing.T) {
+		s, _, stats, tr := newAmmoTest(t)
+		stats.stats[playerID] = *bullet.NewPlayerStats(playerID, 1, bullet.DefaultWeapon)
+		stats.beforeUpdate = func(current *bullet.PlayerStats) {
+			current.AmmoCount = 0 // A concurrent shot took the last round
+		}
+
+		_, _, err := s.Fire(ctx, tr.ID, position, direction)
+		code, _ := shared.DomainErrorCode(err)
+		assert.Equal(t, shared.ErrCodeNoAmmo, code)
+	})
+}
@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/pseudonym"
+)
+
+const (
+	// analyticsBatch is how many stream entries are exported per transaction
+	analyticsBatch = 500
+	// analyticsLockKey makes only one server instance export per tick
+	analyticsLockKey = "lock:analytics:export"
+	// notificationStream carries per-client deliveries, not game activity
+	notificationStream = "game-events.SSENotificationEvent"
+)
+
+// analyticsExported counts exported events per sink, served at /debug/vars
+var analyticsExported = expvar.NewMap("analytics_exported")
+
+// AnalyticsSink is one analytics consumer. It reads pseudonymized copies of game events from
+// the analytics.<Name> stream instead of the raw game streams.
+type AnalyticsSink struct {
+	Name         string        `json:"name"`
+	Events       []string      `json:"events"`        // Stream key patterns, defaults to game-events.*
+	SaltRotation time.Duration `json:"salt_rotation"` // How often pseudonyms change; zero never
+	HashFields   []string      `json:"hash_fields"`   // Defaults to pseudonym.DefaultHashFields
+	DropFields   []string      `json:"drop_fields"`   // Defaults to pseudonym.DefaultDropFields
+	MaxLen       int64         `json:"max_len"`       // Approximate cap of the sink stream; zero is unbounded
+}
+
+// AnalyticsExportPolicy configures the analytics export
+type AnalyticsExportPolicy struct {
+	Interval        time.Duration   `json:"interval"`
+	PseudonymSecret string          `json:"-"`
+	Sinks           []AnalyticsSink `json:"sinks"`
+}
+
+// analyticsSink is a configured sink with its scrubber
+type analyticsSink struct {
+	AnalyticsSink
+	scrubber *pseudonym.Scrubber
+}
+
+// AnalyticsExportService copies game events to analytics sink streams with user identifiers
+// pseudonymized and direct identifiers removed, so events never leave the server raw
+type AnalyticsExportService struct {
+	logger      *logger.Logger
+	redisClient *redis.Client
+	interval    time.Duration
+	sinks       []analyticsSink
+	stopChan    chan struct{}
+	ticker      *time.Ticker
+}
+
+// NewAnalyticsExportService creates a new analytics export service
+func NewAnalyticsExportService(logger *logger.Logger, redisClient *redis.Client, policy AnalyticsExportPolicy) (*AnalyticsExportService, error) {
+	sinks := make([]analyticsSink, 0, len(policy.Sinks))
+	seen := make(map[string]bool, len(policy.Sinks))
+
+	for _, sink := range policy.Sinks {
+		if seen[sink.Name] {
+			return nil, fmt.Errorf("duplicate analytics sink %q", sink.Name)
+		}
+		seen[sink.Name] = true
+
+		scrubber, err := pseudonym.New(pseudonym.Config{
+			Secret:     policy.PseudonymSecret,
+			Sink:       sink.Name,
+			Rotation:   sink.SaltRotation,
+			HashFields: sink.HashFields,
+			DropFields: sink.DropFields,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("analytics sink %q: %w", sink.Name, err)
+		}
+
+		if len(sink.Events) == 0 {
+			sink.Events = []string{"game-events.*"}
+		}
+		sinks = append(sinks, analyticsSink{AnalyticsSink: sink, scrubber: scrubber})
+	}
+
+	return &AnalyticsExportService{
+		logger:      logger.WithComponent("analytics-export-service"),
+		redisClient: redisClient,
+		interval:    policy.Interval,
+		sinks:       sinks,
+		stopChan:    make(chan struct{}),
+	}, nil
+}
+
+// Start begins periodic exporting
+func (s *AnalyticsExportService) Start(ctx context.Context) {
+	if len(s.sinks) == 0 || s.interval <= 0 {
+		s.logger.WithContext(ctx).Info("Analytics export disabled")
+		return
+	}
+
+	s.ticker = time.NewTicker(s.interval)
+
+	s.logger.WithContext(ctx).Info("Starting analytics export",
+		zap.Duration("interval", s.interval),
+		zap.Int("sinks", len(s.sinks)))
+
+	go s.exportLoop(ctx)
+}
+
+// Stop stops periodic exporting
+func (s *AnalyticsExportService) Stop() {
+	s.logger.Info("Stopping analytics export")
+
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+
+	close(s.stopChan)
+}
+
+// exportLoop exports on every tick
+func (s *AnalyticsExportService) exportLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-s.ticker.C:
+			s.exportTick(ctx)
+		}
+	}
+}
+
+// exportTick exports new events to every sink
+func (s *AnalyticsExportService) exportTick(ctx context.Context) {
+	// Every server runs the loop; only the one holding the lock exports this tick
+	acquired, err := s.redisClient.SetNX(ctx, analyticsLockKey, "1", s.interval/2).Result()
+	if err != nil || !acquired {
+		return
+	}
+
+	for _, sink := range s.sinks {
+		exported, err := s.exportSink(ctx, sink)
+		analyticsExported.Add(sink.Name, exported)
+		if err != nil {
+			s.logger.WithContext(ctx).Error("Analytics export failed",
+				zap.String("sink", sink.Name),
+				zap.Int64("exported", exported),
+				zap.Error(err))
+		}
+	}
+}
+
+// exportSink exports new entries of every stream the sink receives
+func (s *AnalyticsExportService) exportSink(ctx context.Context, sink analyticsSink) (int64, error) {
+	var exported int64
+
+	for _, pattern := range sink.Events {
+		iter := s.redisClient.ScanType(ctx, 0, pattern, 100, "stream").Iterator()
+		for iter.Next(ctx) {
+			key := iter.Val()
+			if key == notificationStream || strings.HasPrefix(key, "analytics.") {
+				continue
+			}
+
+			n, err := s.exportStream(ctx, sink, key)
+			exported += n
+			if err != nil {
+				return exported, err
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return exported, err
+		}
+	}
+
+	return exported, nil
+}
+
+// exportStream copies a stream's entries after the sink's cursor, in batches that each append
+// to the sink stream and advance the cursor atomically
+func (s *AnalyticsExportService) exportStream(ctx context.Context, sink analyticsSink, key string) (int64, error) {
+	cursorKey := fmt.Sprintf("analytics:cursor:%s", sink.Name)
+	sinkKey := fmt.Sprintf("analytics.%s", sink.Name)
+	var exported int64
+
+	for {
+		var batch int
+		err := s.redisClient.Watch(ctx, func(tx *redis.Tx) error {
+			start := "-"
+			cursor, err := tx.HGet(ctx, cursorKey, key).Result()
+			if err != nil && err != redis.Nil {
+				return err
+			}
+			if cursor != "" {
+				start = "(" + cursor
+			}
+
+			entries, err := tx.XRangeN(ctx, key, start, "+", analyticsBatch).Result()
+			if err != nil {
+				return err
+			}
+			batch = len(entries)
+			if batch == 0 {
+				return nil
+			}
+
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				for _, entry := range entries {
+					values, ok := s.scrubEntry(sink, key, entry)
+					if !ok {
+						continue
+					}
+					pipe.XAdd(ctx, &redis.XAddArgs{
+						Stream: sinkKey,
+						MaxLen: sink.MaxLen,
+						Approx: sink.MaxLen > 0,
+						Values: values,
+					})
+				}
+				pipe.HSet(ctx, cursorKey, key, entries[batch-1].ID)
+				return nil
+			})
+			return err
+		}, cursorKey)
+
+		if errors.Is(err, redis.TxFailedErr) {
+			return exported, nil // Another instance is exporting this stream
+		}
+		if err != nil {
+			return exported, err
+		}
+
+		exported += int64(batch)
+		if batch < analyticsBatch {
+			return exported, nil
+		}
+	}
+}
+
+// scrubEntry builds the sink copy of a stream entry. Entries whose payload can't be scrubbed
+// are skipped rather than exported raw.
+func (s *AnalyticsExportService) scrubEntry(sink analyticsSink, key string, entry redis.XMessage) (map[string]interface{}, bool) {
+	// Stream IDs start with the entry's creation time in milliseconds
+	millis, err := strconv.ParseInt(strings.SplitN(entry.ID, "-", 2)[0], 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	at := time.UnixMilli(millis)
+
+	payload, _ := entry.Values["payload"].(string)
+	scrubbed, err := sink.scrubber.Scrub([]byte(payload), at)
+	if err != nil {
+		s.logger.Warn("Skipping analytics event that could not be scrubbed",
+			zap.String("sink", sink.Name),
+			zap.String("stream", key),
+			zap.String("id", entry.ID),
+			zap.Error(err))
+		return nil, false
+	}
+
+	return map[string]interface{}{
+		"stream":      key,
+		"occurred_at": millis,
+		"payload":     string(scrubbed),
+	}, true
+}
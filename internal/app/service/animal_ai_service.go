@@ -0,0 +1,270 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/battle"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/world"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+const (
+	// How often wild animals act; each act moves an animal by at most one tile
+	animalAIInterval = 2 * time.Second
+	// Lock key so only one server instance moves the animals per tick
+	animalAILockKey = "lock:animal:ai"
+)
+
+// Behaviors of a wild animal's step, as announced to clients
+const (
+	BehaviorWander = "wander"
+	BehaviorChase  = "chase"
+)
+
+// AnimalAIService periodically moves the wild animals. Animals stay in the spawn area they
+// roam and on the terrain they stand on, wandering randomly; aggressive types chase the
+// nearest trainer coming within their aggro radius instead, and attack it once in reach.
+type AnimalAIService struct {
+	logger       *logger.Logger
+	animalRepo   animal.Repository
+	battleRepo   battle.Repository
+	positionRepo trainer.PositionRepository
+	interest     *InterestManager
+	respawn      *RespawnService
+	world        *world.World
+	config       animal.SpawnConfig
+	eventBus     *cqrs.EventBus
+	redisClient  *redis.Client
+	rng          *rand.Rand
+	stopChan     chan struct{}
+	ticker       *time.Ticker
+}
+
+// NewAnimalAIService creates a new animal AI service for the spawn areas of config
+func NewAnimalAIService(
+	logger *logger.Logger,
+	animalRepo animal.Repository,
+	battleRepo battle.Repository,
+	positionRepo trainer.PositionRepository,
+	interest *InterestManager,
+	respawn *RespawnService,
+	gameWorld *world.World,
+	config animal.SpawnConfig,
+	eventBus *cqrs.EventBus,
+	redisClient *redis.Client,
+) *AnimalAIService {
+	return &AnimalAIService{
+		logger:       logger.WithComponent("animal-ai-service"),
+		animalRepo:   animalRepo,
+		battleRepo:   battleRepo,
+		positionRepo: positionRepo,
+		interest:     interest,
+		respawn:      respawn,
+		world:        gameWorld,
+		config:       config,
+		eventBus:     eventBus,
+		redisClient:  redisClient,
+		rng:          rand.New(rand.NewSource(time.Now().UnixNano())),
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Start begins moving the wild animals
+func (s *AnimalAIService) Start(ctx context.Context) {
+	s.ticker = time.NewTicker(animalAIInterval)
+
+	s.logger.WithContext(ctx).Info("Starting animal AI", zap.Duration("interval", animalAIInterval))
+
+	go s.aiLoop(ctx)
+}
+
+// Stop stops moving the wild animals
+func (s *AnimalAIService) Stop() {
+	s.logger.Info("Stopping animal AI")
+
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+
+	close(s.stopChan)
+}
+
+// aiLoop lets the animals act on every tick
+func (s *AnimalAIService) aiLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-s.ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick lets every wild animal of every spawn area act once
+func (s *AnimalAIService) tick(ctx context.Context) {
+	// Every server runs the loop; only the one holding the lock moves the animals this tick
+	acquired, err := s.redisClient.SetNX(ctx, animalAILockKey, "1", animalAIInterval/2).Result()
+	if err != nil || !acquired {
+		return
+	}
+
+	width, height := s.world.Dimensions()
+	for _, area := range s.config.Areas(width, height) {
+		ids, err := s.redisClient.SMembers(ctx, spawnAreaKey(area)).Result()
+		if err != nil {
+			s.logger.WithContext(ctx).Error("Failed to list spawn area animals", zap.String("area", area.String()), zap.Error(err))
+			continue
+		}
+
+		for _, id := range ids {
+			if err := s.act(ctx, area, animal.AnimalID(id)); err != nil {
+				s.logger.WithContext(ctx).Warn("Failed to move wild animal", zap.String("animalID", id), zap.Error(err))
+			}
+		}
+	}
+}
+
+// act moves one wild animal a tile, chasing or wandering, and announces the step. Animals
+// next to the trainer they chase attack it instead.
+func (s *AnimalAIService) act(ctx context.Context, area animal.SpawnArea, id animal.AnimalID) error {
+	wild, err := s.animalRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	// The spawn manager drops animals that left the wild from the area when it next counts it
+	if wild == nil || !wild.IsWild() || !wild.IsAlive() || s.config.AreaOf(wild.Position) != area {
+		return nil
+	}
+
+	// Animals hold still while a trainer fights them
+	fight, err := s.battleRepo.GetActiveByWild(ctx, id)
+	if err != nil {
+		return err
+	}
+	if fight != nil {
+		return nil
+	}
+
+	canEnter := s.territory(area, wild.Position)
+
+	behavior, targetID := BehaviorWander, ""
+	next, ok := shared.Position{}, false
+	if wild.AnimalType.IsAggressive() {
+		userID, position, found, err := s.nearestTrainer(ctx, wild.Position, wild.AnimalType.AggroRadius())
+		if err != nil {
+			return err
+		}
+		if found && wild.InReach(position) {
+			_, err := s.respawn.Damage(ctx, trainer.UserID(userID), wild.CurrentStats.ATK, trainer.DeathByAnimal, id.String())
+			return err
+		}
+		if found {
+			behavior, targetID = BehaviorChase, userID
+			next, ok = wild.ChaseStep(position, canEnter)
+		}
+	}
+	if behavior == BehaviorWander {
+		next, ok = wild.Wander(canEnter, s.rng)
+	}
+	if !ok {
+		return nil
+	}
+
+	from := wild.Position
+	moved := false
+	err = s.animalRepo.FindOneAndUpdate(ctx, id, func(a *animal.Animal) (*animal.Animal, error) {
+		// Leave animals alone that were captured or moved since they were read
+		if !a.IsWild() || a.Position != from {
+			return nil, nil
+		}
+		if err := a.MoveTo(next); err != nil {
+			return nil, err
+		}
+		moved = true
+		return a, nil
+	})
+	if err != nil || !moved {
+		return err
+	}
+
+	event := &cqrscommands.AnimalMovedEvent{
+		AnimalID:   id.String(),
+		AnimalType: wild.AnimalType.String(),
+		From:       from,
+		To:         next,
+		Behavior:   behavior,
+		TargetID:   targetID,
+		Timestamp:  time.Now(),
+		RequestID:  requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish animal moved event",
+			zap.String("animalID", id.String()),
+			zap.Error(err))
+	}
+
+	return nil
+}
+
+// territory returns whether an animal standing on a position may step onto another: it stays
+// in its spawn area and on walkable tiles of the terrain it stands on, its biome
+func (s *AnimalAIService) territory(area animal.SpawnArea, position shared.Position) func(shared.Position) bool {
+	tile, err := s.world.GetTile(position)
+	if err != nil {
+		return func(shared.Position) bool { return false }
+	}
+	biome := tile.Terrain
+
+	return func(next shared.Position) bool {
+		if s.config.AreaOf(next) != area {
+			return false
+		}
+		nextTile, err := s.world.GetTile(next)
+		return err == nil && nextTile.IsWalkable() && nextTile.Terrain == biome
+	}
+}
+
+// nearestTrainer finds the trainer closest to a position within radius, narrowing the
+// trainers in the surrounding interest chunks down by their last saved positions
+func (s *AnimalAIService) nearestTrainer(ctx context.Context, position shared.Position, radius float64) (string, shared.Position, bool, error) {
+	candidates, err := s.interest.UsersNear(ctx, position)
+	if err != nil || len(candidates) == 0 {
+		return "", shared.Position{}, false, err
+	}
+
+	ids := make([]trainer.UserID, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = trainer.UserID(candidate)
+	}
+	snapshots, err := s.positionRepo.GetMany(ctx, ids)
+	if err != nil {
+		return "", shared.Position{}, false, err
+	}
+
+	nearestID, nearest, found := "", shared.Position{}, false
+	for id, snapshot := range snapshots {
+		current := snapshot.Position
+		if snapshot.Movement.IsMoving {
+			current = snapshot.Movement.CalculateCurrentPosition()
+		}
+
+		if current.WithinRange(position, radius) && (!found || current.DistanceTo(position) < nearest.DistanceTo(position)) {
+			nearestID, nearest, found = id.String(), current, true
+		}
+	}
+	return nearestID, nearest, found, nil
+}
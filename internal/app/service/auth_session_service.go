@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/character"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// AuthSessionService keeps the registry of users' signed-in sessions, which tokens are only
+// accepted for while registered. In single-session mode a sign-in revokes the user's other
+// sessions, so only the newest one keeps working.
+type AuthSessionService struct {
+	logger        *logger.Logger
+	sessions      account.SessionRepository
+	rosters       character.Repository
+	fanout        Disconnector
+	singleSession bool
+}
+
+// NewAuthSessionService creates a new auth session service closing the connections of revoked
+// sessions through fanout
+func NewAuthSessionService(logger *logger.Logger, sessions account.SessionRepository, rosters character.Repository, fanout Disconnector, singleSession bool) *AuthSessionService {
+	return &AuthSessionService{
+		logger:        logger.WithComponent("auth-session-service"),
+		sessions:      sessions,
+		rosters:       rosters,
+		fanout:        fanout,
+		singleSession: singleSession,
+	}
+}
+
+// Start registers the session of a sign-in; it fits account.SessionRegistry. In
+// single-session mode the user's other sessions are revoked.
+func (s *AuthSessionService) Start(ctx context.Context, userID account.UserID, session *account.Session) error {
+	if err := s.sessions.Add(ctx, userID, session); err != nil {
+		return err
+	}
+	if !s.singleSession {
+		return nil
+	}
+
+	_, err := s.RevokeOther(ctx, userID, session.ID)
+	return err
+}
+
+// Extend keeps a session registered as long as a token issued for it later; it fits
+// account.SessionRegistry
+func (s *AuthSessionService) Extend(ctx context.Context, userID account.UserID, sessionID string, expiresAt time.Time) error {
+	return s.sessions.Extend(ctx, userID, sessionID, expiresAt)
+}
+
+// List returns the user's active sessions, oldest first
+func (s *AuthSessionService) List(ctx context.Context, userID account.UserID) ([]*account.Session, error) {
+	return s.sessions.List(ctx, userID)
+}
+
+// RevokeOther revokes every session of the user but current and returns how many were
+// revoked. Connections are per character rather than per session, so when any were revoked
+// every connection of the user's characters is closed; current reconnects with its token.
+func (s *AuthSessionService) RevokeOther(ctx context.Context, userID account.UserID, current string) (int, error) {
+	revoked, err := s.sessions.RevokeOthers(ctx, userID, current)
+	if err != nil {
+		return 0, err
+	}
+	if revoked == 0 {
+		return 0, nil
+	}
+
+	playerIDs := []string{userID.String()}
+	roster, err := s.rosters.Get(ctx, trainer.UserID(userID))
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to get character roster, disconnecting the first character only",
+			zap.String("userId", userID.String()),
+			zap.Error(err))
+	} else {
+		playerIDs = playerIDs[:0]
+		for _, id := range roster.Characters() {
+			playerIDs = append(playerIDs, id.String())
+		}
+	}
+	s.fanout.Disconnect(playerIDs)
+
+	s.logger.WithContext(ctx).Info("Sessions revoked",
+		zap.String("userId", userID.String()),
+		zap.Int("revoked", revoked))
+	return revoked, nil
+}
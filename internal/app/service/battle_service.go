@@ -0,0 +1,332 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/battle"
+	"github.com/danghamo/life/internal/domain/fairness"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+// BattleService runs turn-based battles between party animals and wild animals
+type BattleService struct {
+	logger      *logger.Logger
+	battleRepo  battle.Repository
+	trainerRepo trainer.Repository
+	animalRepo  animal.Repository
+	lootService *LootService
+	randomness  *RandomnessService
+	eventBus    *cqrs.EventBus
+}
+
+// NewBattleService creates a new battle service
+func NewBattleService(
+	logger *logger.Logger,
+	battleRepo battle.Repository,
+	trainerRepo trainer.Repository,
+	animalRepo animal.Repository,
+	lootService *LootService,
+	randomness *RandomnessService,
+	eventBus *cqrs.EventBus,
+) *BattleService {
+	return &BattleService{
+		logger:      logger.WithComponent("battle-service"),
+		battleRepo:  battleRepo,
+		trainerRepo: trainerRepo,
+		animalRepo:  animalRepo,
+		lootService: lootService,
+		randomness:  randomness,
+		eventBus:    eventBus,
+	}
+}
+
+// Start challenges a nearby wild animal with one of the trainer's party animals
+func (s *BattleService) Start(ctx context.Context, userID trainer.UserID, animalID, wildID animal.AnimalID) (*battle.Result, error) {
+	t, err := s.trainerRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, shared.ErrNotFound("Trainer")
+	}
+	if !t.Party.Contains(shared.ID(animalID)) {
+		return nil, shared.NewDomainError(shared.ErrCodeAnimalNotInParty, "Animal is not in the trainer's party")
+	}
+
+	own, err := s.animalRepo.GetByID(ctx, animalID)
+	if err != nil {
+		return nil, err
+	}
+	wild, err := s.animalRepo.GetByID(ctx, wildID)
+	if err != nil {
+		return nil, err
+	}
+	if own == nil || wild == nil {
+		return nil, shared.ErrNotFound("Animal")
+	}
+
+	if !battle.InRange(t.Movement.CalculateCurrentPosition(), wild.Position) {
+		return nil, shared.NewDomainError(shared.ErrCodeOutOfRange, "Wild animal is too far away to challenge")
+	}
+
+	var b *battle.Battle
+	err = s.battleRepo.FindOneAndInsert(ctx, battle.NewBattleID(), func() (*battle.Battle, error) {
+		created, err := battle.NewBattle(userID, own, wild)
+		if err != nil {
+			return nil, err
+		}
+		b = created
+		return created, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	event := &cqrscommands.BattleStartedEvent{
+		BattleID:   b.ID.String(),
+		UserID:     userID.String(),
+		AnimalID:   own.ID.String(),
+		AnimalType: own.AnimalType.String(),
+		WildID:     wild.ID.String(),
+		WildType:   wild.AnimalType.String(),
+		WildLevel:  wild.Level.Value(),
+		Position:   b.Position,
+		Timestamp:  time.Now(),
+		RequestID:  requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish battle started event",
+			zap.String("battleID", b.ID.String()),
+			zap.Error(err))
+	}
+
+	s.logger.WithContext(ctx).Info("Battle started",
+		zap.String("battleID", b.ID.String()),
+		zap.String("userID", userID.String()),
+		zap.String("wildID", wildID.String()))
+
+	return &battle.Result{Battle: b, Animal: own, Wild: wild}, nil
+}
+
+// Attack plays one attack turn in the trainer's active battle
+func (s *BattleService) Attack(ctx context.Context, userID trainer.UserID) (*battle.Result, error) {
+	return s.play(ctx, userID, battle.ActionAttack)
+}
+
+// Flee tries to escape the trainer's active battle
+func (s *BattleService) Flee(ctx context.Context, userID trainer.UserID) (*battle.Result, error) {
+	return s.play(ctx, userID, battle.ActionFlee)
+}
+
+// Get returns the trainer's active battle
+func (s *BattleService) Get(ctx context.Context, userID trainer.UserID) (*battle.Result, error) {
+	b, err := s.battleRepo.GetActiveByTrainer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, shared.NewDomainError(shared.ErrCodeNotInBattle, "Trainer is not in a battle")
+	}
+
+	own, err := s.animalRepo.GetByID(ctx, b.AnimalID)
+	if err != nil {
+		return nil, err
+	}
+	wild, err := s.animalRepo.GetByID(ctx, b.WildID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &battle.Result{Battle: b, Animal: own, Wild: wild}, nil
+}
+
+// play resolves one turn and persists its outcome
+func (s *BattleService) play(ctx context.Context, userID trainer.UserID, action battle.Action) (*battle.Result, error) {
+	b, err := s.battleRepo.GetActiveByTrainer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if b == nil {
+		return nil, shared.NewDomainError(shared.ErrCodeNotInBattle, "Trainer is not in a battle")
+	}
+
+	own, err := s.animalRepo.GetByID(ctx, b.AnimalID)
+	if err != nil {
+		return nil, err
+	}
+	if own == nil {
+		return nil, shared.ErrNotFound("Animal")
+	}
+
+	wild, err := s.animalRepo.GetByID(ctx, b.WildID)
+	if err != nil {
+		return nil, err
+	}
+	if wild == nil || !wild.IsWild() {
+		// Captured or removed since the battle started
+		b.Interrupt()
+		if err := s.saveTurn(ctx, b, b.Turns); err != nil {
+			return nil, err
+		}
+		s.publishEnded(ctx, b)
+		return &battle.Result{Battle: b, Animal: own}, nil
+	}
+
+	// Each turn draws from its own stream, so a battle replays identically from its seed
+	session, err := s.randomness.Begin(ctx, fairness.PurposeBattle, userID.String(), fmt.Sprintf("%s:%d", b.ID, b.Turns+1), map[string]any{
+		"battle_id": b.ID.String(),
+		"action":    string(action),
+		"animal_hp": own.CurrentHP,
+		"wild_hp":   wild.CurrentHP,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ownHP, wildHP, played := own.CurrentHP, wild.CurrentHP, b.Turns
+	var turn *battle.Turn
+	switch action {
+	case battle.ActionFlee:
+		turn, err = b.Flee(own, wild, session.Rand())
+	default:
+		turn, err = b.Attack(own, wild, session.Rand())
+	}
+	if err != nil {
+		return nil, err
+	}
+	s.randomness.Record(ctx, session, turn)
+
+	if err := s.saveTurn(ctx, b, played); err != nil {
+		return nil, err
+	}
+
+	// Apply the HP change rather than the whole animal so an item used meanwhile is kept
+	own, err = s.applyDamage(ctx, own.ID, own.CurrentHP-ownHP, b.Experience)
+	if err != nil {
+		return nil, err
+	}
+	wild, err = s.applyDamage(ctx, wild.ID, wild.CurrentHP-wildHP, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &battle.Result{Battle: b, Animal: own, Wild: wild}
+
+	s.publishTurn(ctx, b, turn)
+	if b.IsOver() {
+		if b.Status == battle.StatusWon {
+			if dropped := s.defeat(ctx, userID, wild); dropped != nil {
+				result.Drops, result.Pickups = dropped.Drops, dropped.Pickups
+			}
+			result.Wild = nil
+		}
+		s.publishEnded(ctx, b)
+
+		s.logger.WithContext(ctx).Info("Battle ended",
+			zap.String("battleID", b.ID.String()),
+			zap.String("userID", userID.String()),
+			zap.String("status", b.Status.String()),
+			zap.Int("turns", b.Turns))
+	}
+
+	return result, nil
+}
+
+// saveTurn stores the battle, failing if another turn was played since it was loaded
+func (s *BattleService) saveTurn(ctx context.Context, b *battle.Battle, played int) error {
+	return s.battleRepo.FindOneAndUpdate(ctx, b.ID, func(current *battle.Battle) (*battle.Battle, error) {
+		if current.IsOver() || current.Turns != played {
+			return nil, shared.NewDomainError(shared.ErrCodeInvalidOperation, "Battle changed, try again")
+		}
+		return b, nil
+	})
+}
+
+// applyDamage changes an animal's HP by delta and awards experience
+func (s *BattleService) applyDamage(ctx context.Context, id animal.AnimalID, delta int, experience int) (*animal.Animal, error) {
+	var updated *animal.Animal
+	err := s.animalRepo.FindOneAndUpdate(ctx, id, func(a *animal.Animal) (*animal.Animal, error) {
+		a.CurrentHP = max(0, min(a.MaxHP, a.CurrentHP+delta))
+		a.UpdatedAt = shared.NewTimestamp()
+
+		if experience > 0 {
+			if err := a.GainExperience(experience); err != nil {
+				s.logger.WithContext(ctx).Warn("Failed to award battle experience",
+					zap.String("animalID", id.String()),
+					zap.Error(err))
+			}
+		}
+
+		updated = a
+		return a, nil
+	})
+
+	return updated, err
+}
+
+// defeat rolls loot for a defeated wild animal and removes it from the world
+func (s *BattleService) defeat(ctx context.Context, userID trainer.UserID, wild *animal.Animal) *LootResult {
+	result, err := s.lootService.HandleAnimalDefeated(ctx, userID, wild)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to roll battle loot",
+			zap.String("userID", userID.String()),
+			zap.String("animalID", wild.ID.String()),
+			zap.Error(err))
+	}
+
+	if err := s.animalRepo.Delete(ctx, wild.ID); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to remove defeated animal",
+			zap.String("animalID", wild.ID.String()),
+			zap.Error(err))
+	}
+
+	return result
+}
+
+// publishTurn announces a played turn
+func (s *BattleService) publishTurn(ctx context.Context, b *battle.Battle, turn *battle.Turn) {
+	event := &cqrscommands.BattleTurnEvent{
+		BattleID:  b.ID.String(),
+		UserID:    b.TrainerID.String(),
+		Turn:      *turn,
+		Status:    b.Status.String(),
+		Position:  b.Position,
+		Timestamp: time.Now(),
+		RequestID: requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish battle turn event",
+			zap.String("battleID", b.ID.String()),
+			zap.Error(err))
+	}
+}
+
+// publishEnded announces a finished battle
+func (s *BattleService) publishEnded(ctx context.Context, b *battle.Battle) {
+	event := &cqrscommands.BattleEndedEvent{
+		BattleID:   b.ID.String(),
+		UserID:     b.TrainerID.String(),
+		AnimalID:   b.AnimalID.String(),
+		WildID:     b.WildID.String(),
+		Status:     b.Status.String(),
+		Experience: b.Experience,
+		Position:   b.Position,
+		Timestamp:  time.Now(),
+		RequestID:  requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish battle ended event",
+			zap.String("battleID", b.ID.String()),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,290 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/fairness"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/world"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/plugin"
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+// Where a captured animal is placed
+const (
+	PlacementParty   = "party"
+	PlacementStorage = "storage"
+)
+
+// CaptureService captures wild animals with nets and releases them back to the wild
+type CaptureService struct {
+	logger      *logger.Logger
+	trainerRepo trainer.Repository
+	animalRepo  animal.Repository
+	clockRepo   world.ClockRepository
+	randomness  *RandomnessService
+	eventBus    *cqrs.EventBus
+	plugins     *plugin.Hooks
+}
+
+// NewCaptureService creates a new capture service
+func NewCaptureService(logger *logger.Logger, trainerRepo trainer.Repository, animalRepo animal.Repository, clockRepo world.ClockRepository, randomness *RandomnessService, eventBus *cqrs.EventBus, plugins *plugin.Hooks) *CaptureService {
+	return &CaptureService{
+		logger:      logger.WithComponent("capture-service"),
+		trainerRepo: trainerRepo,
+		animalRepo:  animalRepo,
+		clockRepo:   clockRepo,
+		randomness:  randomness,
+		eventBus:    eventBus,
+		plugins:     plugins,
+	}
+}
+
+// captureOutput is the audited outcome of a capture roll
+type captureOutput struct {
+	Chance  float64 `json:"chance"`
+	Success bool    `json:"success"`
+}
+
+// Capture throws a net at a wild animal within capture range of where the trainer stands now.
+// The net is used up whether or not the animal is caught; a caught animal joins the party, or
+// storage when the party is full.
+func (s *CaptureService) Capture(ctx context.Context, userID trainer.UserID, animalID animal.AnimalID, itemID trainer.ItemID) (*trainer.CaptureResult, error) {
+	target, err := s.animalRepo.GetByID(ctx, animalID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, shared.ErrNotFound("Animal")
+	}
+	if !target.CanBeCaptured() {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidState, "Only living wild animals can be captured")
+	}
+
+	var used *trainer.Item
+	var net trainer.CaptureNet
+	err = s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		item, itemNet, err := t.UseCaptureNet(itemID, target.Position)
+		if err != nil {
+			return nil, err
+		}
+		used, net = item, itemNet
+		return t, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The time of day and weather sway the chance; captures carry on without them if the
+	// clock can't be read
+	clock, err := s.clockRepo.Get(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to load world clock", zap.Error(err))
+		clock = nil
+	}
+	chance := target.CaptureChanceDuring(net.Effectiveness, clock)
+	if net.Guaranteed {
+		chance = 1.0
+	}
+
+	// Each throw gets its own stream; keying by animal would repeat the same draw for
+	// every throw at that animal until the seed rotates
+	session, err := s.randomness.Begin(ctx, fairness.PurposeCapture, userID.String(), "", map[string]any{
+		"animal_id": animalID.String(),
+		"net":       used.Type.String(),
+		"chance":    chance,
+	})
+	if err != nil {
+		s.refundNet(ctx, userID, used)
+		return nil, err
+	}
+	success := session.Rand().Float64() < chance
+
+	s.randomness.Record(ctx, session, captureOutput{Chance: chance, Success: success})
+
+	result := &trainer.CaptureResult{
+		AnimalID: animalID.String(),
+		Item:     used,
+		Chance:   chance,
+		Success:  success,
+		RollID:   session.ID().String(),
+	}
+
+	if !success {
+		s.logger.WithContext(ctx).Info("Capture failed",
+			zap.String("userID", userID.String()),
+			zap.String("animalID", animalID.String()),
+			zap.Float64("chance", chance))
+		s.plugins.Captured(ctx, captureHookEvent(userID, target, used, result))
+		return result, nil
+	}
+
+	var captured *animal.Animal
+	err = s.animalRepo.FindOneAndUpdate(ctx, animalID, func(a *animal.Animal) (*animal.Animal, error) {
+		c, err := animal.NewCapturedAnimal(a, shared.ID(userID))
+		if err != nil {
+			return nil, err
+		}
+		captured = c
+		return c, nil
+	})
+	if err != nil {
+		// Someone else caught it first; the throw never landed
+		s.refundNet(ctx, userID, used)
+		return nil, err
+	}
+
+	placement, err := s.place(ctx, userID, captured.ID)
+	if err != nil {
+		return nil, err
+	}
+	result.Placement = placement
+
+	event := &cqrscommands.AnimalCapturedEvent{
+		UserID:     userID.String(),
+		AnimalID:   captured.ID.String(),
+		AnimalType: captured.AnimalType.String(),
+		Level:      captured.Level.Value(),
+		NetType:    used.Type.String(),
+		Placement:  placement,
+		Position:   captured.Position,
+		Timestamp:  time.Now(),
+		RequestID:  requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish animal captured event",
+			zap.String("animalID", captured.ID.String()),
+			zap.Error(err))
+	}
+
+	s.logger.WithContext(ctx).Info("Animal captured",
+		zap.String("userID", userID.String()),
+		zap.String("animalID", animalID.String()),
+		zap.String("placement", placement))
+
+	s.plugins.Captured(ctx, captureHookEvent(userID, captured, used, result))
+	return result, nil
+}
+
+// captureHookEvent describes a throw for the OnCapture plugin hooks
+func captureHookEvent(userID trainer.UserID, target *animal.Animal, net *trainer.Item, result *trainer.CaptureResult) plugin.Capture {
+	return plugin.Capture{
+		UserID:     userID.String(),
+		AnimalID:   target.ID.String(),
+		AnimalType: target.AnimalType.String(),
+		Level:      target.Level.Value(),
+		NetType:    net.Type.String(),
+		Success:    result.Success,
+		Placement:  result.Placement,
+		Position:   plugin.Position{X: target.Position.X, Y: target.Position.Y},
+		At:         time.Now(),
+	}
+}
+
+// Release returns one of the trainer's animals to the wild where the trainer stands
+func (s *CaptureService) Release(ctx context.Context, userID trainer.UserID, animalID animal.AnimalID) (*animal.Animal, error) {
+	t, err := s.trainerRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, shared.ErrNotFound("Trainer")
+	}
+	position := t.Movement.CalculateCurrentPosition()
+
+	var released *animal.Animal
+	err = s.animalRepo.FindOneAndUpdate(ctx, animalID, func(a *animal.Animal) (*animal.Animal, error) {
+		if a.OwnerID != shared.ID(userID) {
+			return nil, shared.NewDomainError(shared.ErrCodeNotCaptured, "Animal is not owned by this trainer")
+		}
+		if err := a.Release(position); err != nil {
+			return nil, err
+		}
+		released = a
+		return a, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		inParty := t.RemoveAnimalFromParty(shared.ID(animalID)) == nil // Fails when the animal was in storage
+		unpinned := t.UnpinAnimal(animalID.String())
+		if !inParty && !unpinned {
+			return nil, nil
+		}
+		return t, nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to remove released animal from party and showcase",
+			zap.String("userID", userID.String()),
+			zap.String("animalID", animalID.String()),
+			zap.Error(err))
+	}
+
+	s.logger.WithContext(ctx).Info("Animal released",
+		zap.String("userID", userID.String()),
+		zap.String("animalID", animalID.String()))
+
+	return released, nil
+}
+
+// place adds a newly captured animal to the trainer's party, or to storage when it is full
+func (s *CaptureService) place(ctx context.Context, userID trainer.UserID, animalID animal.AnimalID) (string, error) {
+	placement := PlacementStorage
+	err := s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		if t.Party.IsFull() {
+			placement = PlacementStorage
+			return nil, nil
+		}
+		if err := t.AddAnimalToParty(shared.ID(animalID)); err != nil {
+			return nil, err
+		}
+		placement = PlacementParty
+		return t, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	state := animal.InStorage
+	if placement == PlacementParty {
+		state = animal.InParty
+	}
+
+	err = s.animalRepo.FindOneAndUpdate(ctx, animalID, func(a *animal.Animal) (*animal.Animal, error) {
+		if err := a.ChangeState(state); err != nil {
+			return nil, err
+		}
+		return a, nil
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return placement, nil
+}
+
+// refundNet returns a net whose throw never resolved
+func (s *CaptureService) refundNet(ctx context.Context, userID trainer.UserID, item *trainer.Item) {
+	err := s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		if err := t.Inventory.AddItem(item); err != nil {
+			return nil, err
+		}
+		return t, nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to refund capture net",
+			zap.String("userID", userID.String()),
+			zap.String("itemID", item.ID.String()),
+			zap.Error(err))
+	}
+}
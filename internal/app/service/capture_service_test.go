@@ -0,0 +1,46 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// memoryAnimals serves animals from a map. Methods the tests don't use panic.
+type memoryAnimals struct {
+	animal.Repository
+	animals map[animal.AnimalID]*animal.Animal
+}
+
+func (m *memoryAnimals) GetByID(ctx context.Context, id animal.AnimalID) (*animal.Animal, error) {
+	return m.animals[id], nil
+}
+
+func TestCaptureService_Capture_OutOfRange(t *testing.T) {
+	tr, err := trainer.NewTrainer("catcher", "Catcher")
+	require.NoError(t, err)
+	tr.Movement.StopMovement(shared.NewPosition(10, 10))
+	nets, err := trainer.NewItemStack(trainer.BasicNet, "Basic Net", 1)
+	require.NoError(t, err)
+	require.NoError(t, tr.Inventory.AddItem(nets))
+
+	wild, err := animal.NewWildAnimal(animal.Lion, 1, shared.NewPosition(10+trainer.CaptureRange, 10.5))
+	require.NoError(t, err)
+
+	trainers := &memoryTrainers{trainers: map[trainer.UserID]*trainer.Trainer{tr.ID: tr}}
+	animals := &memoryAnimals{animals: map[animal.AnimalID]*animal.Animal{wild.ID: wild}}
+	s := NewCaptureService(logger.NewDefault(), trainers, animals, nil, nil, nil, nil)
+
+	_, err = s.Capture(context.Background(), tr.ID, wild.ID, nets.ID)
+	code, ok := shared.DomainErrorCode(err)
+	require.True(t, ok, "expected a domain error, got %v", err)
+	assert.Equal(t, shared.ErrCodeOutOfRange, code)
+	assert.Equal(t, 1, tr.Inventory.CountItemsByType(trainer.BasicNet), "the net is kept")
+}
@@ -0,0 +1,262 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/battle"
+	"github.com/danghamo/life/internal/domain/challenge"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// challengeRotateInterval is how often the current week is checked for its rotation. Rotating
+// is idempotent, so every server instance checks without a lock.
+const challengeRotateInterval = time.Minute
+
+// ChallengeService rotates the weekly challenges and tracks trainers' progress on them.
+// Progress is counted from the domain events of captures and won battles, so clients can't
+// report it; claiming a completed challenge grants its battle pass XP.
+type ChallengeService struct {
+	logger   *logger.Logger
+	registry *challenge.Registry
+	repo     challenge.Repository
+	push     *cqrscommands.SSEBroadcastHelper
+	stopChan chan struct{}
+	ticker   *time.Ticker
+}
+
+// NewChallengeService creates a new challenge service
+func NewChallengeService(
+	logger *logger.Logger,
+	registry *challenge.Registry,
+	repo challenge.Repository,
+	push *cqrscommands.SSEBroadcastHelper,
+) *ChallengeService {
+	return &ChallengeService{
+		logger:   logger.WithComponent("challenge-service"),
+		registry: registry,
+		repo:     repo,
+		push:     push,
+		stopChan: make(chan struct{}),
+	}
+}
+
+// Start begins rotating the challenges when a week starts
+func (s *ChallengeService) Start(ctx context.Context) {
+	s.ticker = time.NewTicker(challengeRotateInterval)
+
+	s.logger.WithContext(ctx).Info("Starting weekly challenge rotation", zap.Duration("interval", challengeRotateInterval))
+
+	go s.rotateLoop(ctx)
+}
+
+// Stop stops rotating the challenges
+func (s *ChallengeService) Stop() {
+	s.logger.Info("Stopping weekly challenge rotation")
+
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+
+	close(s.stopChan)
+}
+
+// rotateLoop rotates the current week right away and then on every tick
+func (s *ChallengeService) rotateLoop(ctx context.Context) {
+	s.rotate(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-s.ticker.C:
+			s.rotate(ctx)
+		}
+	}
+}
+
+// rotate makes sure the current week has its challenges
+func (s *ChallengeService) rotate(ctx context.Context) {
+	if _, err := s.active(ctx, challenge.WeekOf(time.Now())); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to rotate weekly challenges", zap.Error(err))
+	}
+}
+
+// active returns the challenges of a week, rotating the week if nobody did yet. The stored
+// rotation wins over the registry's pick, so editing the content doesn't reshuffle a running
+// week; challenges removed from the registry since are left out.
+func (s *ChallengeService) active(ctx context.Context, week challenge.Week) ([]*challenge.Definition, error) {
+	ids, err := s.repo.Rotation(ctx, week)
+	if err != nil {
+		return nil, err
+	}
+	if ids == nil {
+		if ids, err = s.repo.SetRotation(ctx, week, s.registry.Rotate(week, challenge.ActivePerWeek)); err != nil {
+			return nil, err
+		}
+	}
+
+	definitions := make([]*challenge.Definition, 0, len(ids))
+	for _, id := range ids {
+		def, err := s.registry.Get(id)
+		if err != nil {
+			s.logger.WithContext(ctx).Warn("Rotated challenge is no longer defined", zap.String("challengeId", id.String()))
+			continue
+		}
+		definitions = append(definitions, def)
+	}
+	return definitions, nil
+}
+
+// List returns the trainer's challenges of the current week with their progress
+func (s *ChallengeService) List(ctx context.Context, userID trainer.UserID) (*challenge.Board, error) {
+	week := challenge.WeekOf(time.Now())
+	definitions, err := s.active(ctx, week)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := s.repo.Progress(ctx, week, userID)
+	if err != nil {
+		return nil, err
+	}
+	passXP, err := s.repo.PassXP(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	board := &challenge.Board{
+		Week:       week.String(),
+		EndsAt:     week.End(),
+		Challenges: make([]challenge.Status, len(definitions)),
+		PassXP:     passXP,
+	}
+	for i, def := range definitions {
+		board.Challenges[i] = challenge.NewStatus(def, progress)
+	}
+	return board, nil
+}
+
+// Claim grants the battle pass XP of a challenge of the current week the trainer completed
+func (s *ChallengeService) Claim(ctx context.Context, userID trainer.UserID, id challenge.ChallengeID) (*challenge.ClaimResult, error) {
+	week := challenge.WeekOf(time.Now())
+	def, err := s.activeDefinition(ctx, week, id)
+	if err != nil {
+		return nil, err
+	}
+
+	progress, err := s.repo.Progress(ctx, week, userID)
+	if err != nil {
+		return nil, err
+	}
+	status := challenge.NewStatus(def, progress)
+	if err := status.CheckClaimable(); err != nil {
+		return nil, err
+	}
+
+	// The repository refuses a second claim racing this one
+	passXP, err := s.repo.Claim(ctx, week, userID, id, def.PassXP)
+	if err != nil {
+		return nil, err
+	}
+	status.Claimed = true
+
+	s.logger.WithContext(ctx).Info("Challenge claimed",
+		zap.String("userId", userID.String()),
+		zap.String("challengeId", id.String()),
+		zap.Int("passXp", passXP))
+
+	return &challenge.ClaimResult{Challenge: status, PassXP: passXP}, nil
+}
+
+// activeDefinition returns a challenge of the week, failing with ErrCodeUnknownChallenge for
+// challenges that aren't part of it
+func (s *ChallengeService) activeDefinition(ctx context.Context, week challenge.Week, id challenge.ChallengeID) (*challenge.Definition, error) {
+	definitions, err := s.active(ctx, week)
+	if err != nil {
+		return nil, err
+	}
+	for _, def := range definitions {
+		if def.ID == id {
+			return def, nil
+		}
+	}
+	return nil, shared.NewDomainErrorf(shared.ErrCodeUnknownChallenge, "Challenge not active this week: %s", id)
+}
+
+// HandleAnimalCapturedEvent counts a capture toward the trainer's capture challenges
+func (s *ChallengeService) HandleAnimalCapturedEvent(ctx context.Context, event *cqrscommands.AnimalCapturedEvent) error {
+	return s.record(ctx, event.UserID, event.RequestID, event.Timestamp,
+		challenge.ObjectiveCapture, animal.AnimalType(event.AnimalType))
+}
+
+// HandleBattleEndedEvent counts a won battle toward the trainer's battle challenges
+func (s *ChallengeService) HandleBattleEndedEvent(ctx context.Context, event *cqrscommands.BattleEndedEvent) error {
+	if event.Status != string(battle.StatusWon) {
+		return nil
+	}
+	return s.record(ctx, event.UserID, event.RequestID, event.Timestamp, challenge.ObjectiveWinBattle, "")
+}
+
+// record counts an occurrence toward the matching challenges of the week it happened in, and
+// tells the trainer about challenges it completed
+func (s *ChallengeService) record(ctx context.Context, userID, eventID string, at time.Time, kind challenge.ObjectiveKind, animalType animal.AnimalType) error {
+	if userID == "" || eventID == "" {
+		return nil
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	week := challenge.WeekOf(at)
+	definitions, err := s.active(ctx, week)
+	if err != nil {
+		return err
+	}
+
+	var matching []challenge.ChallengeID
+	for _, def := range definitions {
+		if def.Objective.Matches(kind, animalType) {
+			matching = append(matching, def.ID)
+		}
+	}
+	if len(matching) == 0 {
+		return nil
+	}
+
+	counts, err := s.repo.Record(ctx, week, trainer.UserID(userID), eventID, matching)
+	if err != nil {
+		return err
+	}
+
+	for _, def := range definitions {
+		// Only the event reaching the count reports it, so completion is told once
+		if count, ok := counts[def.ID]; ok && count == def.Objective.Count {
+			s.completed(ctx, userID, def)
+		}
+	}
+	return nil
+}
+
+// completed pushes a challenge.completed message to the trainer's clients
+func (s *ChallengeService) completed(ctx context.Context, userID string, def *challenge.Definition) {
+	s.logger.WithContext(ctx).Info("Challenge completed",
+		zap.String("userId", userID),
+		zap.String("challengeId", def.ID.String()))
+
+	err := s.push.BroadcastToUsers(ctx, []string{userID}, "challenge.completed", map[string]interface{}{
+		"challenge_id": def.ID,
+		"name":         def.Name,
+		"pass_xp":      def.PassXP,
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to send challenge completion", zap.String("userId", userID), zap.Error(err))
+	}
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/accessibility"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/character"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/plugin"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// CharacterService lets a user play several trainers. Each character has its own position,
+// inventory and progress; the vault and money belong to the user. Selecting a character
+// carries the money over to it and takes the character played before out of the world.
+// Deleted characters can be restored for a grace period before a task purges them.
+type CharacterService struct {
+	logger      *logger.Logger
+	rosters     character.Repository
+	trainerRepo trainer.Repository
+	fanout      Disconnector
+	taskClient  *asynq.Client
+	plugins     *plugin.Hooks
+	settings    accessibility.Repository
+}
+
+// NewCharacterService creates a new character service closing connections through fanout and
+// scheduling purges of deleted characters with taskClient. New characters take their colors
+// from the palette chosen in the user's accessibility settings.
+func NewCharacterService(logger *logger.Logger, rosters character.Repository, trainerRepo trainer.Repository, fanout Disconnector, taskClient *asynq.Client, plugins *plugin.Hooks, settings accessibility.Repository) *CharacterService {
+	return &CharacterService{
+		logger:      logger.WithComponent("character-service"),
+		rosters:     rosters,
+		trainerRepo: trainerRepo,
+		fanout:      fanout,
+		taskClient:  taskClient,
+		plugins:     plugins,
+		settings:    settings,
+	}
+}
+
+// List returns the trainers of a user's characters, the first one first, along with their
+// roster
+func (s *CharacterService) List(ctx context.Context, userID trainer.UserID) ([]*trainer.Trainer, *character.Roster, error) {
+	roster, err := s.rosters.Get(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	characters := make([]*trainer.Trainer, 0, roster.Slots())
+	for _, id := range roster.Characters() {
+		t, err := s.trainerRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		if t != nil {
+			characters = append(characters, t)
+		}
+	}
+	return characters, roster, nil
+}
+
+// Create adds a character with a nickname and appearance to a user. It starts without money,
+// since money belongs to the user.
+func (s *CharacterService) Create(ctx context.Context, userID trainer.UserID, nickname string, appearance character.Appearance) (*trainer.Trainer, error) {
+	if err := trainer.ValidateNickname(nickname); err != nil {
+		return nil, err
+	}
+	if err := appearance.Validate(); err != nil {
+		return nil, err
+	}
+	existing, err := s.trainerRepo.FindByNickname(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, shared.ErrAlreadyExists("Nickname")
+	}
+	settings, err := s.settings.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var id trainer.UserID
+	err = s.rosters.FindOneAndUpdate(ctx, userID, func(r *character.Roster) error {
+		id, err = r.AddCharacter()
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var created *trainer.Trainer
+	err = s.trainerRepo.FindOneAndInsert(ctx, id, func() (*trainer.Trainer, error) {
+		t, err := trainer.NewTrainer(id, nickname)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.SpendMoney(t.Money.Amount()); err != nil {
+			return nil, err
+		}
+		if err := appearance.Apply(t); err != nil {
+			return nil, err
+		}
+		if appearance.Color == "" {
+			if _, err := t.Recolor(settings.Palette); err != nil {
+				return nil, err
+			}
+		}
+		created = t
+		return t, nil
+	})
+	if err != nil {
+		// Give the reserved slot back
+		if rollbackErr := s.rosters.FindOneAndUpdate(ctx, userID, func(r *character.Roster) error {
+			r.RemoveCharacter(id)
+			return nil
+		}); rollbackErr != nil {
+			s.logger.WithContext(ctx).Error("Failed to release character slot",
+				zap.String("userId", userID.String()),
+				zap.String("characterId", id.String()),
+				zap.Error(rollbackErr))
+		}
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Character created",
+		zap.String("userId", userID.String()),
+		zap.String("characterId", id.String()),
+		zap.String("nickname", nickname))
+	return created, nil
+}
+
+// Select makes one of a user's characters the one they play and carries their money over to
+// it. playing is the character of the session selecting; unless it was selected again, its
+// connections are closed so it leaves the world and its position is saved.
+func (s *CharacterService) Select(ctx context.Context, userID, characterID, playing trainer.UserID) (*trainer.Trainer, error) {
+	var selected *trainer.Trainer
+	err := s.rosters.Transfer(ctx, userID, func(r *character.Roster, characters []*trainer.Trainer) error {
+		var err error
+		selected, err = r.Select(characterID, characters)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if playing != characterID {
+		s.fanout.Disconnect([]string{playing.String()})
+	}
+
+	s.logger.WithContext(ctx).Info("Character selected",
+		zap.String("userId", userID.String()),
+		zap.String("characterId", characterID.String()))
+	return selected, nil
+}
+
+// Delete schedules one of a user's additional characters for deletion and returns when it is
+// purged; until then it can be restored. Its money goes to the first character. playing is
+// the character of the session deleting, which must play another one; other sessions playing
+// the deleted character are disconnected.
+func (s *CharacterService) Delete(ctx context.Context, userID, characterID, playing trainer.UserID) (time.Time, error) {
+	if playing == characterID {
+		return time.Time{}, shared.NewDomainError(shared.ErrCodeInvalidOperation, "Select another character before deleting this one")
+	}
+
+	var purgeAt time.Time
+	err := s.rosters.Transfer(ctx, userID, func(r *character.Roster, characters []*trainer.Trainer) error {
+		var err error
+		purgeAt, err = r.Delete(characterID, characters, time.Now())
+		return err
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := s.schedulePurge(ctx, userID, characterID, purgeAt); err != nil {
+		return time.Time{}, err
+	}
+	s.fanout.Disconnect([]string{characterID.String()})
+
+	s.logger.WithContext(ctx).Info("Character deleted",
+		zap.String("userId", userID.String()),
+		zap.String("characterId", characterID.String()),
+		zap.Time("purgeAt", purgeAt))
+	return purgeAt, nil
+}
+
+// Restore cancels the deletion of one of a user's characters during its grace period. The
+// scheduled purge finds the character isn't due and leaves it alone.
+func (s *CharacterService) Restore(ctx context.Context, userID, characterID trainer.UserID) error {
+	err := s.rosters.FindOneAndUpdate(ctx, userID, func(r *character.Roster) error {
+		return r.Restore(characterID, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.WithContext(ctx).Info("Character restored",
+		zap.String("userId", userID.String()),
+		zap.String("characterId", characterID.String()))
+	return nil
+}
+
+// BuySlot spends a user's gems on one more character slot, unless a plugin vetoes the
+// purchase, and returns their updated roster
+func (s *CharacterService) BuySlot(ctx context.Context, userID trainer.UserID) (*character.Roster, error) {
+	if err := s.plugins.Purchasing(ctx, plugin.Purchase{
+		UserID:   userID.String(),
+		ItemType: "character_slot",
+		Quantity: 1,
+		Price:    character.SlotPrice,
+	}); err != nil {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidOperation, err.Error())
+	}
+
+	var updated *character.Roster
+	err := s.rosters.FindOneAndUpdate(ctx, userID, func(r *character.Roster) error {
+		updated = r
+		return r.BuySlot()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Character slot bought",
+		zap.String("userId", userID.String()),
+		zap.Int("slots", updated.Slots()),
+		zap.Int("gems", updated.Gems))
+	return updated, nil
+}
+
+// schedulePurge enqueues the purge of a deleted character for when its grace period ends.
+// The task ID carries the date, so deleting a restored character again schedules a new purge.
+func (s *CharacterService) schedulePurge(ctx context.Context, userID, characterID trainer.UserID, purgeAt time.Time) error {
+	payload, err := json.Marshal(characterPurgePayload{
+		UserID:      userID.String(),
+		CharacterID: characterID.String(),
+	})
+	if err != nil {
+		return err
+	}
+
+	task := asynq.NewTask(tenant.IDFromContext(ctx).Key(TypeCharacterPurge), payload)
+	_, err = s.taskClient.EnqueueContext(ctx, task,
+		asynq.TaskID(fmt.Sprintf("character-purge:%s:%d", characterID, purgeAt.Unix())),
+		asynq.ProcessAt(purgeAt),
+		asynq.MaxRetry(25),
+	)
+	if err != nil && !errors.Is(err, asynq.ErrTaskIDConflict) {
+		return fmt.Errorf("failed to schedule character purge: %w", err)
+	}
+	return nil
+}
+
+// Active returns the character a user plays after signing in, empty for their first one. It
+// fits account.CharacterResolver; when the roster can't be read the first character is played.
+func (s *CharacterService) Active(ctx context.Context, userID account.UserID) string {
+	roster, err := s.rosters.Get(ctx, trainer.UserID(userID))
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to get character roster, signing in with the first character",
+			zap.String("userId", userID.String()),
+			zap.Error(err))
+		return ""
+	}
+	if roster.Active == "" {
+		return ""
+	}
+	return roster.Active.String()
+}
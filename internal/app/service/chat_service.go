@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/chat"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+// ChatService sends chat messages to every player, to trainers nearby or to one player.
+// Messages travel as ChatMessageEvent over the event bus so every server instance delivers
+// them to its own connected clients.
+type ChatService struct {
+	logger       *logger.Logger
+	floodRepo    chat.FloodRepository
+	trainerRepo  trainer.Repository
+	positionRepo trainer.PositionRepository
+	interest     *InterestManager
+	eventBus     *cqrs.EventBus
+}
+
+// NewChatService creates a new chat service
+func NewChatService(logger *logger.Logger, floodRepo chat.FloodRepository, trainerRepo trainer.Repository, positionRepo trainer.PositionRepository, interest *InterestManager, eventBus *cqrs.EventBus) *ChatService {
+	return &ChatService{
+		logger:       logger.WithComponent("chat-service"),
+		floodRepo:    floodRepo,
+		trainerRepo:  trainerRepo,
+		positionRepo: positionRepo,
+		interest:     interest,
+		eventBus:     eventBus,
+	}
+}
+
+// SendGlobal sends a message to every connected player
+func (s *ChatService) SendGlobal(ctx context.Context, userID trainer.UserID, text string) (*chat.Message, error) {
+	sender, message, err := s.compose(ctx, userID, chat.ChannelGlobal, text)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.publish(ctx, sender, message, nil); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// SendNearby sends a message to the trainers within chat.NearbyRadius of the sender
+func (s *ChatService) SendNearby(ctx context.Context, userID trainer.UserID, text string) (*chat.Message, error) {
+	sender, message, err := s.compose(ctx, userID, chat.ChannelNearby, text)
+	if err != nil {
+		return nil, err
+	}
+
+	sender.UpdatePositionFromMovement()
+	position := sender.Position
+	message.Position = &position
+
+	recipients, err := s.usersWithin(ctx, position, chat.NearbyRadius)
+	if err != nil {
+		return nil, err
+	}
+	recipients = appendMissing(recipients, userID.String())
+
+	if err := s.publish(ctx, sender, message, recipients); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// SendWhisper sends a message to the trainer with a nickname; the sender gets a copy so
+// all of their clients show the conversation
+func (s *ChatService) SendWhisper(ctx context.Context, userID trainer.UserID, nickname, text string) (*chat.Message, error) {
+	target, err := s.trainerRepo.FindByNickname(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, shared.ErrNotFound("Trainer")
+	}
+	if target.ID == userID {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Cannot whisper to yourself")
+	}
+
+	sender, message, err := s.compose(ctx, userID, chat.ChannelWhisper, text)
+	if err != nil {
+		return nil, err
+	}
+	message.To = target.Nickname
+
+	if err := s.publish(ctx, sender, message, []string{target.ID.String(), userID.String()}); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// compose validates a message, applies flood protection and loads the sender
+func (s *ChatService) compose(ctx context.Context, userID trainer.UserID, channel chat.Channel, text string) (*trainer.Trainer, *chat.Message, error) {
+	sender, err := s.trainerRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sender == nil {
+		return nil, nil, shared.ErrNotFound("Trainer")
+	}
+
+	message, err := chat.NewMessage(channel, sender.Nickname, text)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	flood, err := s.floodRepo.Record(ctx, userID.String(), message.Text)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := flood.Check(); err != nil {
+		return nil, nil, err
+	}
+
+	return sender, message, nil
+}
+
+// usersWithin returns the trainers within radius of a position, narrowing the trainers in
+// the surrounding interest chunks down by their last saved positions
+func (s *ChatService) usersWithin(ctx context.Context, position shared.Position, radius float64) ([]string, error) {
+	candidates, err := s.interest.UsersNear(ctx, position)
+	if err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]trainer.UserID, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = trainer.UserID(candidate)
+	}
+	snapshots, err := s.positionRepo.GetMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshotsWithin(snapshots, position, radius), nil
+}
+
+// snapshotsWithin returns the trainers whose snapshots put them within radius of a position
+func snapshotsWithin(snapshots map[trainer.UserID]trainer.PositionSnapshot, position shared.Position, radius float64) []string {
+	userIDs := make([]string, 0, len(snapshots))
+	for id, snapshot := range snapshots {
+		current := snapshot.Position
+		if snapshot.Movement.IsMoving {
+			current = snapshot.Movement.CalculateCurrentPosition()
+		}
+		if current.WithinRange(position, radius) {
+			userIDs = append(userIDs, id.String())
+		}
+	}
+	return userIDs
+}
+
+// publish sends a message to its recipients, or to every player when there are none
+func (s *ChatService) publish(ctx context.Context, sender *trainer.Trainer, message *chat.Message, recipients []string) error {
+	event := &cqrscommands.ChatMessageEvent{
+		SenderID:   sender.ID.String(),
+		Message:    message,
+		Recipients: recipients,
+		Timestamp:  time.Now(),
+		RequestID:  requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish chat message event",
+			zap.String("userID", sender.ID.String()),
+			zap.String("channel", string(message.Channel)),
+			zap.Error(err))
+		return err
+	}
+
+	s.logger.WithContext(ctx).Debug("Chat message sent",
+		zap.String("userID", sender.ID.String()),
+		zap.String("channel", string(message.Channel)),
+		zap.Int("recipients", len(recipients)))
+	return nil
+}
+
+// appendMissing appends a user ID unless the list already holds it
+func appendMissing(userIDs []string, userID string) []string {
+	for _, id := range userIDs {
+		if id == userID {
+			return userIDs
+		}
+	}
+	return append(userIDs, userID)
+}
@@ -0,0 +1,24 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/danghamo/life/internal/domain/chat"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+func TestSnapshotsWithin(t *testing.T) {
+	snapshots := map[trainer.UserID]trainer.PositionSnapshot{
+		"beside": {ID: "beside", Position: shared.NewPosition(1, 1)},
+		"edge":   {ID: "edge", Position: shared.NewPosition(9, 12)},
+		"inside": {ID: "inside", Position: shared.NewPosition(10, 10)},
+		"beyond": {ID: "beyond", Position: shared.NewPosition(0, 15.5)},
+	}
+
+	userIDs := snapshotsWithin(snapshots, shared.NewPosition(0, 0), chat.NearbyRadius)
+	assert.ElementsMatch(t, []string{"beside", "edge", "inside"}, userIDs,
+		"radius is compared against the squared distance, edge included")
+}
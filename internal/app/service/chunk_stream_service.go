@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/battle"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/world"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+const (
+	// chunkSubscriptionTTL is how long a chunk subscription lasts; clients subscribe again as
+	// their trainer moves, which renews it
+	chunkSubscriptionTTL = 10 * time.Minute
+	// chunkEntityTTL drops the chunk remembered for an entity that stopped being seen, e.g. a
+	// trainer who went offline
+	chunkEntityTTL = time.Hour
+)
+
+// ChunkStreamService lets clients stream the world chunk by chunk. Players subscribe to the
+// chunks around their trainer, fetch their tiles with GetChunk, and are told when trainers
+// and animals enter or leave them. Subscriptions and the chunk each entity is in are kept in
+// Redis, so every server instance agrees on them.
+type ChunkStreamService struct {
+	logger      *logger.Logger
+	redisClient *redis.Client
+	world       *world.World
+	push        *cqrscommands.SSEBroadcastHelper
+}
+
+// NewChunkStreamService creates a new chunk stream service over the server's copy of the world
+func NewChunkStreamService(logger *logger.Logger, redisClient *redis.Client, gameWorld *world.World, push *cqrscommands.SSEBroadcastHelper) *ChunkStreamService {
+	return &ChunkStreamService{
+		logger:      logger.WithComponent("chunk-stream-service"),
+		redisClient: redisClient,
+		world:       gameWorld,
+		push:        push,
+	}
+}
+
+// GetChunk returns the tiles of a chunk
+func (s *ChunkStreamService) GetChunk(cx, cy int) (*world.ChunkTiles, error) {
+	return s.world.GetChunk(cx, cy)
+}
+
+// Subscribe replaces the chunks a player streams, returning those subscribed to. Chunks
+// outside the world are left out.
+func (s *ChunkStreamService) Subscribe(ctx context.Context, userID string, chunks []world.Chunk) ([]world.Chunk, error) {
+	if len(chunks) > world.MaxSubscribedChunks {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "At most %d chunks can be subscribed to", world.MaxSubscribedChunks)
+	}
+
+	subscribed := make([]world.Chunk, 0, len(chunks))
+	seen := make(map[world.Chunk]bool, len(chunks))
+	for _, chunk := range chunks {
+		if s.world.Contains(chunk) && !seen[chunk] {
+			seen[chunk] = true
+			subscribed = append(subscribed, chunk)
+		}
+	}
+
+	userKey := chunkSubscriptionsKey(userID)
+	previous, err := s.redisClient.SMembers(ctx, userKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	expiresAt := float64(time.Now().Add(chunkSubscriptionTTL).UnixMilli())
+	_, err = s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, member := range previous {
+			if chunk, ok := parseChunkMember(member); ok {
+				pipe.ZRem(ctx, chunkSubscribersKey(chunk), userID)
+			}
+		}
+		pipe.Del(ctx, userKey)
+
+		for _, chunk := range subscribed {
+			key := chunkSubscribersKey(chunk)
+			pipe.ZRemRangeByScore(ctx, key, "-inf", now) // Subscriptions that ran out
+			pipe.ZAdd(ctx, key, redis.Z{Score: expiresAt, Member: userID})
+			pipe.Expire(ctx, key, chunkSubscriptionTTL)
+			pipe.SAdd(ctx, userKey, chunkMember(chunk))
+		}
+		pipe.Expire(ctx, userKey, chunkSubscriptionTTL)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return subscribed, nil
+}
+
+// HandlePositionsBatchEvent tracks the trainers the movement simulation advanced
+func (s *ChunkStreamService) HandlePositionsBatchEvent(ctx context.Context, event *cqrscommands.PositionsBatchEvent) error {
+	for _, delta := range event.Trainers {
+		s.track(ctx, delta.UserID, world.EntityTrainer, shared.NewPosition(delta.X, delta.Y), true)
+	}
+	return nil
+}
+
+// HandleTrainerStoppedEvent tracks a stopped trainer, which may have been put elsewhere, e.g.
+// by a teleport or a position correction
+func (s *ChunkStreamService) HandleTrainerStoppedEvent(ctx context.Context, event *cqrscommands.TrainerStoppedEvent) error {
+	s.track(ctx, event.UserID, world.EntityTrainer, event.Position, true)
+	return nil
+}
+
+// HandleAnimalSpawnedEvent tracks a wild animal entering the world
+func (s *ChunkStreamService) HandleAnimalSpawnedEvent(ctx context.Context, event *cqrscommands.AnimalSpawnedEvent) error {
+	s.track(ctx, event.AnimalID, world.EntityAnimal, event.Position, true)
+	return nil
+}
+
+// HandleAnimalMovedEvent tracks a wild animal walking around
+func (s *ChunkStreamService) HandleAnimalMovedEvent(ctx context.Context, event *cqrscommands.AnimalMovedEvent) error {
+	s.track(ctx, event.AnimalID, world.EntityAnimal, event.To, true)
+	return nil
+}
+
+// HandleAnimalCapturedEvent tracks a captured animal leaving the world
+func (s *ChunkStreamService) HandleAnimalCapturedEvent(ctx context.Context, event *cqrscommands.AnimalCapturedEvent) error {
+	s.track(ctx, event.AnimalID, world.EntityAnimal, event.Position, false)
+	return nil
+}
+
+// HandleBattleEndedEvent tracks a defeated wild animal leaving the world
+func (s *ChunkStreamService) HandleBattleEndedEvent(ctx context.Context, event *cqrscommands.BattleEndedEvent) error {
+	if event.Status == string(battle.StatusWon) {
+		s.track(ctx, event.WildID, world.EntityAnimal, event.Position, false)
+	}
+	return nil
+}
+
+// track records the chunk an entity is in, or that it left the world when present is false,
+// and tells the subscribers of the chunks it left and entered
+func (s *ChunkStreamService) track(ctx context.Context, entityID, kind string, position shared.Position, present bool) {
+	key := chunkEntityKey(entityID)
+	previous, err := s.redisClient.GetEx(ctx, key, chunkEntityTTL).Result()
+	if err != nil && err != redis.Nil {
+		s.logger.WithContext(ctx).Warn("Failed to read entity chunk", zap.String("entityId", entityID), zap.Error(err))
+		return
+	}
+
+	chunk := world.ChunkAt(position)
+	current := ""
+	if present {
+		current = chunkMember(chunk)
+	}
+	if previous == current {
+		return
+	}
+
+	if present {
+		err = s.redisClient.Set(ctx, key, current, chunkEntityTTL).Err()
+	} else {
+		err = s.redisClient.Del(ctx, key).Err()
+	}
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to store entity chunk", zap.String("entityId", entityID), zap.Error(err))
+		return
+	}
+
+	if left, ok := parseChunkMember(previous); ok {
+		s.notify(ctx, "world.chunk.entity_left", world.ChunkEntityChange{Chunk: left, EntityID: entityID, Kind: kind, Position: position})
+	}
+	if present {
+		s.notify(ctx, "world.chunk.entity_entered", world.ChunkEntityChange{Chunk: chunk, EntityID: entityID, Kind: kind, Position: position})
+	}
+}
+
+// notify pushes a change to the players subscribed to its chunk
+func (s *ChunkStreamService) notify(ctx context.Context, method string, change world.ChunkEntityChange) {
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	userIDs, err := s.redisClient.ZRangeByScore(ctx, chunkSubscribersKey(change.Chunk), &redis.ZRangeBy{Min: now, Max: "+inf"}).Result()
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to read chunk subscribers", zap.Error(err))
+		return
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+
+	if err := s.push.BroadcastToUsers(ctx, userIDs, method, change); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to push chunk change",
+			zap.String("method", method),
+			zap.String("entityId", change.EntityID),
+			zap.Error(err))
+	}
+}
+
+// chunkSubscribersKey returns the sorted set of players subscribed to a chunk, scored by when
+// their subscription expires
+func chunkSubscribersKey(chunk world.Chunk) string {
+	return "world:chunk:subscribers:" + chunkMember(chunk)
+}
+
+// chunkSubscriptionsKey returns the set of chunks a player is subscribed to
+func chunkSubscriptionsKey(userID string) string {
+	return "world:chunk:subscriptions:" + userID
+}
+
+// chunkEntityKey returns the key holding the chunk an entity was last seen in
+func chunkEntityKey(entityID string) string {
+	return "world:chunk:entity:" + entityID
+}
+
+// chunkMember formats a chunk for Redis keys and members
+func chunkMember(chunk world.Chunk) string {
+	return fmt.Sprintf("%d:%d", chunk.X, chunk.Y)
+}
+
+// parseChunkMember parses a chunk formatted by chunkMember
+func parseChunkMember(member string) (world.Chunk, bool) {
+	var chunk world.Chunk
+	if _, err := fmt.Sscanf(member, "%d:%d", &chunk.X, &chunk.Y); err != nil {
+		return world.Chunk{}, false
+	}
+	return chunk, true
+}
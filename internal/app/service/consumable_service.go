@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/redisx"
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+// Consumable target types
+const (
+	TargetTrainer = "trainer"
+	TargetAnimal  = "animal"
+)
+
+// ConsumableService uses consumable items and applies their effects
+type ConsumableService struct {
+	logger      *logger.Logger
+	trainerRepo trainer.Repository
+	animalRepo  animal.Repository
+	eventBus    *cqrs.EventBus
+	outbox      *cqrscommands.Outbox
+}
+
+// NewConsumableService creates a new consumable service
+func NewConsumableService(logger *logger.Logger, trainerRepo trainer.Repository, animalRepo animal.Repository, eventBus *cqrs.EventBus, outbox *cqrscommands.Outbox) *ConsumableService {
+	return &ConsumableService{
+		logger:      logger.WithComponent("consumable-service"),
+		trainerRepo: trainerRepo,
+		animalRepo:  animalRepo,
+		eventBus:    eventBus,
+		outbox:      outbox,
+	}
+}
+
+// UseItem consumes one item and applies its effect to the trainer, or to one of the
+// trainer's animals when animalID is set. The item consumed event is recorded in the outbox
+// with the write applying the effect, so it's published exactly when the effect happened.
+func (s *ConsumableService) UseItem(ctx context.Context, userID trainer.UserID, itemID trainer.ItemID, animalID string) (*trainer.ItemUseResult, error) {
+	now := time.Now()
+	result := &trainer.ItemUseResult{TargetType: TargetTrainer, TargetID: userID.String()}
+	staged := redisx.WithStage(ctx)
+
+	var used *trainer.Item
+	var effect trainer.ConsumableEffect
+	var changed *trainer.BulkResult
+
+	err := s.trainerRepo.FindOneAndUpdate(staged, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		item, itemEffect, err := t.ConsumeItem(itemID, now)
+		if err != nil {
+			return nil, err
+		}
+		used, effect = item, itemEffect
+		changed = trainer.NewBulkResult(BulkActionUse, t, []*trainer.Item{item})
+
+		if animalID != "" {
+			if !effect.TargetsAnimal {
+				return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidOperation, "%s cannot be used on animals", item.Name)
+			}
+			return t, nil
+		}
+
+		outcome, err := trainer.DispatchEffect(effect, item.Type, t, now)
+		if err != nil {
+			return nil, err
+		}
+		result.EffectOutcome = outcome
+		condition := t.Condition
+		result.Condition = &condition
+		if err := s.recordConsumed(staged, userID, item, result, now.Add(effect.Cooldown), now); err != nil {
+			return nil, err
+		}
+		return t, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if animalID != "" {
+		result.TargetType = TargetAnimal
+		result.TargetID = animalID
+		outcome, err := s.applyToAnimal(staged, userID, animal.AnimalID(animalID), used, effect, result, now)
+		if err != nil {
+			s.refundItem(ctx, userID, used)
+			return nil, err
+		}
+		result.EffectOutcome = outcome
+	}
+
+	result.Item = used
+	result.CooldownEnds = now.Add(effect.Cooldown)
+
+	publishInventoryChanged(ctx, s.eventBus, s.logger, userID, changed)
+
+	s.logger.WithContext(ctx).Info("Item used",
+		zap.String("userID", userID.String()),
+		zap.String("itemType", used.Type.String()),
+		zap.String("target", result.TargetType))
+
+	return result, nil
+}
+
+// recordConsumed records the item consumed event in the outbox, staged with the write of ctx
+func (s *ConsumableService) recordConsumed(ctx context.Context, userID trainer.UserID, item *trainer.Item, result *trainer.ItemUseResult, cooldownEnds, now time.Time) error {
+	event := &cqrscommands.ItemConsumedEvent{
+		UserID:       userID.String(),
+		ItemID:       item.ID.String(),
+		ItemType:     item.Type,
+		TargetType:   result.TargetType,
+		TargetID:     result.TargetID,
+		Healed:       result.Healed,
+		ManaRestored: result.ManaRestored,
+		Effect:       result.Effect,
+		CooldownEnds: cooldownEnds,
+		Timestamp:    now,
+		RequestID:    requestid.FromContextOrNew(ctx),
+	}
+	return s.outbox.Publish(ctx, event)
+}
+
+// applyToAnimal applies a consumable effect to an animal owned by the trainer, recording the
+// item consumed event with the animal's write
+func (s *ConsumableService) applyToAnimal(ctx context.Context, userID trainer.UserID, animalID animal.AnimalID, item *trainer.Item, effect trainer.ConsumableEffect, result *trainer.ItemUseResult, now time.Time) (trainer.EffectOutcome, error) {
+	var outcome trainer.EffectOutcome
+
+	err := s.animalRepo.FindOneAndUpdate(ctx, animalID, func(a *animal.Animal) (*animal.Animal, error) {
+		if a.OwnerID != shared.ID(userID) {
+			return nil, shared.NewDomainError(shared.ErrCodeNotCaptured, "Animal is not owned by this trainer")
+		}
+		if a.IsFainted() {
+			return nil, shared.NewDomainError(shared.ErrCodeAlreadyFainted, "Fainted animals cannot be healed with items")
+		}
+
+		var err error
+		outcome, err = trainer.DispatchEffect(effect, item.Type, a, now)
+		if err != nil {
+			return nil, err
+		}
+
+		recorded := *result
+		recorded.EffectOutcome = outcome
+		if err := s.recordConsumed(ctx, userID, item, &recorded, now.Add(effect.Cooldown), now); err != nil {
+			return nil, err
+		}
+		return a, nil
+	})
+
+	return outcome, err
+}
+
+// refundItem returns a consumed item and clears its cooldown when the effect could not be applied
+func (s *ConsumableService) refundItem(ctx context.Context, userID trainer.UserID, item *trainer.Item) {
+	err := s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		if err := t.Inventory.AddItem(item); err != nil {
+			return nil, err
+		}
+		delete(t.Condition.Cooldowns, item.Type)
+		return t, nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to refund consumed item",
+			zap.String("userID", userID.String()),
+			zap.String("itemID", item.ID.String()),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,355 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/crafting"
+	"github.com/danghamo/life/internal/domain/equipment"
+	"github.com/danghamo/life/internal/domain/fairness"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// TypeCraftComplete is the asynq task type that finishes a crafting job
+const TypeCraftComplete = "craft:complete"
+
+// craftCompletePayload is the asynq payload for TypeCraftComplete
+type craftCompletePayload struct {
+	JobID string `json:"job_id"`
+}
+
+// CraftingService turns materials into items and equipment over time
+type CraftingService struct {
+	logger        *logger.Logger
+	recipes       *crafting.Registry
+	jobRepo       crafting.Repository
+	trainerRepo   trainer.Repository
+	equipmentRepo equipment.Repository
+	taskClient    *asynq.Client
+	eventBus      *cqrs.EventBus
+	randomness    *RandomnessService
+}
+
+// NewCraftingService creates a new crafting service
+func NewCraftingService(
+	logger *logger.Logger,
+	recipes *crafting.Registry,
+	jobRepo crafting.Repository,
+	trainerRepo trainer.Repository,
+	equipmentRepo equipment.Repository,
+	taskClient *asynq.Client,
+	eventBus *cqrs.EventBus,
+	randomness *RandomnessService,
+) *CraftingService {
+	return &CraftingService{
+		logger:        logger.WithComponent("crafting-service"),
+		recipes:       recipes,
+		jobRepo:       jobRepo,
+		trainerRepo:   trainerRepo,
+		equipmentRepo: equipmentRepo,
+		taskClient:    taskClient,
+		eventBus:      eventBus,
+		randomness:    randomness,
+	}
+}
+
+// ListRecipes returns all known recipes
+func (s *CraftingService) ListRecipes() []*crafting.Recipe {
+	return s.recipes.List()
+}
+
+// GetJobs returns the trainer's uncollected crafting jobs
+func (s *CraftingService) GetJobs(ctx context.Context, trainerID trainer.UserID) ([]*crafting.Job, error) {
+	return s.jobRepo.GetByTrainer(ctx, trainerID)
+}
+
+// StartCraft consumes the recipe materials and schedules the job completion
+func (s *CraftingService) StartCraft(ctx context.Context, trainerID trainer.UserID, recipeID crafting.RecipeID) (*crafting.Job, error) {
+	recipe, err := s.recipes.Get(recipeID)
+	if err != nil {
+		return nil, err
+	}
+
+	var job *crafting.Job
+	var consumed []*trainer.Item
+
+	// Materials are consumed in a single trainer update, so a missing ingredient leaves the inventory untouched
+	err = s.trainerRepo.FindOneAndUpdate(ctx, trainerID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		newJob, err := crafting.NewJob(trainerID, recipe, t.Level.Value())
+		if err != nil {
+			return nil, err
+		}
+
+		removed := make([]*trainer.Item, 0)
+		for _, ingredient := range recipe.Ingredients {
+			items, err := t.Inventory.RemoveItemsByType(ingredient.ItemType, ingredient.Quantity)
+			if err != nil {
+				return nil, err
+			}
+			removed = append(removed, items...)
+		}
+
+		job = newJob
+		consumed = removed
+		t.UpdatedAt = shared.NewTimestamp()
+		return t, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.jobRepo.FindOneAndInsert(ctx, job.ID, func() (*crafting.Job, error) {
+		return job, nil
+	})
+	if err != nil {
+		s.refundMaterials(ctx, trainerID, consumed)
+		return nil, err
+	}
+
+	s.scheduleCompletion(ctx, job, recipe.CraftTime())
+
+	event := &cqrscommands.CraftStartedEvent{
+		UserID:    trainerID.String(),
+		JobID:     job.ID.String(),
+		RecipeID:  recipeID.String(),
+		ReadyAt:   job.ReadyAt,
+		Timestamp: time.Now(),
+		RequestID: requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish craft started event",
+			zap.String("jobID", job.ID.String()),
+			zap.Error(err))
+	}
+
+	s.logger.WithContext(ctx).Info("Craft started",
+		zap.String("userID", trainerID.String()),
+		zap.String("jobID", job.ID.String()),
+		zap.String("recipeID", recipeID.String()),
+		zap.Time("readyAt", job.ReadyAt))
+
+	return job, nil
+}
+
+// HandleCraftCompleteTask processes TypeCraftComplete tasks
+func (s *CraftingService) HandleCraftCompleteTask(ctx context.Context, task *asynq.Task) error {
+	var payload craftCompletePayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("invalid craft complete payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	_, err := s.completeJob(ctx, crafting.JobID(payload.JobID))
+	return err
+}
+
+// Collect hands a finished crafting result to the trainer
+func (s *CraftingService) Collect(ctx context.Context, trainerID trainer.UserID, jobID crafting.JobID) (*crafting.Result, error) {
+	// Jobs never change hands, so other trainers are turned away before anything is written
+	job, err := s.jobRepo.GetByID(ctx, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if job == nil || job.TrainerID != trainerID {
+		return nil, shared.ErrNotFound("crafting job")
+	}
+
+	// Complete lazily in case the scheduled task has not run yet
+	if _, err := s.completeJob(ctx, jobID); err != nil {
+		return nil, err
+	}
+
+	err = s.jobRepo.FindOneAndUpdate(ctx, jobID, func(j *crafting.Job) (*crafting.Job, error) {
+		if j.TrainerID != trainerID {
+			return nil, shared.ErrNotFound("crafting job")
+		}
+
+		if err := j.Collect(time.Now()); err != nil {
+			return nil, err
+		}
+
+		job = j
+		return j, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &crafting.Result{Job: job}
+	if job.Succeeded {
+		if err := s.deliverOutput(ctx, trainerID, job, result); err != nil {
+			s.revertCollect(ctx, jobID)
+			return nil, err
+		}
+	}
+
+	s.logger.WithContext(ctx).Info("Craft collected",
+		zap.String("userID", trainerID.String()),
+		zap.String("jobID", jobID.String()),
+		zap.Bool("succeeded", job.Succeeded))
+
+	return result, nil
+}
+
+// completeJob finishes a job whose craft time has elapsed, returning whether it changed
+func (s *CraftingService) completeJob(ctx context.Context, jobID crafting.JobID) (bool, error) {
+	var completed *crafting.Job
+	var session *fairness.Session
+
+	err := s.jobRepo.FindOneAndUpdate(ctx, jobID, func(j *crafting.Job) (*crafting.Job, error) {
+		if j.Status != crafting.JobInProgress || !j.IsReady(time.Now()) {
+			return nil, nil // Nothing to do yet
+		}
+
+		// Keep one roll across retries so a conflicting write cannot reroll the outcome
+		if session == nil {
+			var err error
+			session, err = s.randomness.Begin(ctx, fairness.PurposeCraft, j.TrainerID.String(), j.ID.String(), map[string]any{
+				"job_id":         j.ID.String(),
+				"recipe_id":      j.RecipeID.String(),
+				"success_chance": j.SuccessChance,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if err := j.Complete(time.Now(), session.Rand()); err != nil {
+			return nil, err
+		}
+
+		completed = j
+		return j, nil
+	})
+	if err != nil || completed == nil {
+		return false, err
+	}
+
+	s.randomness.Record(ctx, session, map[string]bool{"succeeded": completed.Succeeded})
+
+	event := &cqrscommands.CraftCompletedEvent{
+		UserID:    completed.TrainerID.String(),
+		JobID:     completed.ID.String(),
+		RecipeID:  completed.RecipeID.String(),
+		Succeeded: completed.Succeeded,
+		Timestamp: time.Now(),
+		RequestID: requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish craft completed event",
+			zap.String("jobID", completed.ID.String()),
+			zap.Error(err))
+	}
+
+	return true, nil
+}
+
+// deliverOutput grants the recipe output to the trainer
+func (s *CraftingService) deliverOutput(ctx context.Context, trainerID trainer.UserID, job *crafting.Job, result *crafting.Result) error {
+	recipe, err := s.recipes.Get(job.RecipeID)
+	if err != nil {
+		return err
+	}
+
+	switch recipe.Output.Kind {
+	case crafting.OutputEquipment:
+		crafted, err := equipment.NewEquipment(recipe.Output.Name, recipe.Output.EquipmentType, recipe.Output.Rarity, recipe.Output.BaseStats)
+		if err != nil {
+			return err
+		}
+		if err := crafted.AssignToTrainer(shared.ID(trainerID)); err != nil {
+			return err
+		}
+
+		err = s.equipmentRepo.FindOneAndInsert(ctx, crafted.ID, func() (*equipment.Equipment, error) {
+			return crafted, nil
+		})
+		if err != nil {
+			return err
+		}
+		result.Equipment = crafted
+
+	default:
+		item, err := trainer.NewItemStack(recipe.Output.ItemType, recipe.Output.Name, recipe.Output.Quantity)
+		if err != nil {
+			return err
+		}
+
+		err = s.trainerRepo.FindOneAndUpdate(ctx, trainerID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+			if err := t.Inventory.AddItem(item); err != nil {
+				return nil, err
+			}
+
+			t.UpdatedAt = shared.NewTimestamp()
+			return t, nil
+		})
+		if err != nil {
+			return err
+		}
+		result.Items = []*trainer.Item{item}
+	}
+
+	return nil
+}
+
+// scheduleCompletion enqueues the completion task; Collect still completes lazily if this fails
+func (s *CraftingService) scheduleCompletion(ctx context.Context, job *crafting.Job, craftTime time.Duration) {
+	payload, err := json.Marshal(craftCompletePayload{JobID: job.ID.String()})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to marshal craft complete payload", zap.Error(err))
+		return
+	}
+
+	task := asynq.NewTask(tenant.IDFromContext(ctx).Key(TypeCraftComplete), payload)
+	if _, err := s.taskClient.EnqueueContext(ctx, task,
+		asynq.ProcessIn(craftTime),
+		asynq.TaskID("craft:"+job.ID.String()),
+		asynq.MaxRetry(5),
+	); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to schedule craft completion",
+			zap.String("jobID", job.ID.String()),
+			zap.Error(err))
+	}
+}
+
+// refundMaterials puts consumed materials back when a job could not be stored
+func (s *CraftingService) refundMaterials(ctx context.Context, trainerID trainer.UserID, items []*trainer.Item) {
+	err := s.trainerRepo.FindOneAndUpdate(ctx, trainerID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		for _, item := range items {
+			if err := t.Inventory.AddItem(item); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to refund crafting materials",
+			zap.String("userID", trainerID.String()),
+			zap.Int("items", len(items)),
+			zap.Error(err))
+	}
+}
+
+// revertCollect reopens a job when its output could not be delivered
+func (s *CraftingService) revertCollect(ctx context.Context, jobID crafting.JobID) {
+	err := s.jobRepo.FindOneAndUpdate(ctx, jobID, func(j *crafting.Job) (*crafting.Job, error) {
+		j.Status = crafting.JobCompleted
+		j.CollectedAt = time.Time{}
+		return j, nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to reopen crafting job after delivery failure",
+			zap.String("jobID", jobID.String()),
+			zap.Error(err))
+	}
+}
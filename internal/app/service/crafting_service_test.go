@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/crafting"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// memoryJobs serves crafting jobs from a map, returning nil for unknown jobs
+type memoryJobs struct {
+	crafting.Repository
+	jobs    map[crafting.JobID]*crafting.Job
+	updates int
+}
+
+func (m *memoryJobs) GetByID(ctx context.Context, id crafting.JobID) (*crafting.Job, error) {
+	return m.jobs[id], nil
+}
+
+func (m *memoryJobs) FindOneAndUpdate(ctx context.Context, id crafting.JobID, callback func(*crafting.Job) (*crafting.Job, error)) error {
+	m.updates++
+	if _, err := callback(m.jobs[id]); err != nil {
+		return err
+	}
+	return nil
+}
+
+func TestCraftingService_Collect_OtherTrainer(t *testing.T) {
+	recipe := &crafting.Recipe{ID: "test", RequiredLevel: 1, BaseSuccessChance: 1}
+	job, err := crafting.NewJob("owner", recipe, 1)
+	require.NoError(t, err)
+	job.ReadyAt = time.Now().Add(-time.Second)
+
+	jobs := &memoryJobs{jobs: map[crafting.JobID]*crafting.Job{job.ID: job}}
+	s := NewCraftingService(logger.NewDefault(), nil, jobs, nil, nil, nil, nil, nil)
+
+	for name, jobID := range map[string]crafting.JobID{"other trainer's job": job.ID, "unknown job": "missing"} {
+		t.Run(name, func(t *testing.T) {
+			_, err := s.Collect(context.Background(), "intruder", jobID)
+			code, ok := shared.DomainErrorCode(err)
+			require.True(t, ok, "expected a domain error, got %v", err)
+			assert.Equal(t, shared.ErrCodeNotFound, code)
+		})
+	}
+
+	assert.Equal(t, crafting.JobInProgress, job.Status, "a ready job isn't completed for another trainer")
+	assert.Zero(t, jobs.updates)
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/mailer"
+)
+
+// EmailService manages players' contact addresses and their verification
+type EmailService struct {
+	logger    *logger.Logger
+	emailRepo account.EmailRepository
+	mailer    mailer.Mailer
+	verifyURL string
+	activity  *ActivityService
+}
+
+// NewEmailService creates a new email service. Verification links point at verifyURL with
+// the token in the token query parameter.
+func NewEmailService(logger *logger.Logger, emailRepo account.EmailRepository, mailer mailer.Mailer, verifyURL string, activity *ActivityService) *EmailService {
+	return &EmailService{
+		logger:    logger.WithComponent("email-service"),
+		emailRepo: emailRepo,
+		mailer:    mailer,
+		verifyURL: verifyURL,
+		activity:  activity,
+	}
+}
+
+// Get returns the user's email, or nil when none is set
+func (s *EmailService) Get(ctx context.Context, userID string) (*account.Email, error) {
+	return s.emailRepo.GetByUserID(ctx, account.UserID(userID))
+}
+
+// Change sets or changes the user's address. The new address gets a verification link and a
+// previous address is told about the change, so a hijacked session can't quietly take over.
+func (s *EmailService) Change(ctx context.Context, userID, rawAddress string) (*account.Email, error) {
+	address, err := account.ParseEmailAddress(rawAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated *account.Email
+	var previous string
+	err = s.emailRepo.FindOneAndUpdate(ctx, account.UserID(userID), func(current *account.Email) (*account.Email, error) {
+		if current == nil {
+			current = &account.Email{UserID: account.UserID(userID)}
+		}
+
+		changedFrom, err := current.Change(address)
+		if err != nil {
+			return nil, err
+		}
+
+		previous = changedFrom
+		updated = current
+		return current, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.sendVerification(ctx, updated); err != nil {
+		return nil, err
+	}
+
+	if previous != "" {
+		s.send(ctx, mailer.Message{
+			To:      previous,
+			Subject: "Your email address was changed",
+			Body: fmt.Sprintf("The email address on your account was changed to %s.\n\n"+
+				"If you didn't make this change, sign in and change it back, then secure your linked accounts.", address),
+		})
+	}
+
+	changed := account.NewActivity(account.ActivityEmailChanged)
+	changed.Email = updated.MaskedAddress()
+	s.activity.Record(ctx, updated.UserID, changed)
+
+	s.logger.WithContext(ctx).Info("Email address changed", zap.String("userID", userID))
+
+	return updated, nil
+}
+
+// ResendVerification sends a fresh verification link for the user's current address
+func (s *EmailService) ResendVerification(ctx context.Context, userID string) (*account.Email, error) {
+	email, err := s.emailRepo.GetByUserID(ctx, account.UserID(userID))
+	if err != nil {
+		return nil, err
+	}
+	if email == nil {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidOperation, "No email address is set")
+	}
+	if email.IsVerified() {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidOperation, "Email address is already verified")
+	}
+
+	if err := s.sendVerification(ctx, email); err != nil {
+		return nil, err
+	}
+
+	return email, nil
+}
+
+// Verify consumes a verification token and marks its address as verified
+func (s *EmailService) Verify(ctx context.Context, token string) (*account.Email, error) {
+	verification, err := s.emailRepo.TakeVerification(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	if verification == nil {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidVerificationToken, "Verification link is invalid or expired")
+	}
+
+	var verified *account.Email
+	err = s.emailRepo.FindOneAndUpdate(ctx, verification.UserID, func(current *account.Email) (*account.Email, error) {
+		if current == nil {
+			return nil, shared.NewDomainError(shared.ErrCodeInvalidVerificationToken, "Verification link is for a previous email address")
+		}
+
+		if err := current.Verify(verification.Address); err != nil {
+			return nil, err
+		}
+
+		verified = current
+		return current, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	verifiedActivity := account.NewActivity(account.ActivityEmailVerified)
+	verifiedActivity.Email = verified.MaskedAddress()
+	s.activity.Record(ctx, verified.UserID, verifiedActivity)
+
+	s.logger.WithContext(ctx).Info("Email address verified", zap.String("userID", verification.UserID.String()))
+
+	return verified, nil
+}
+
+// RequireVerified returns an error unless the user has a verified email. Giving items to
+// another player goes through it, so throwaway accounts can't be used to move items around.
+func (s *EmailService) RequireVerified(ctx context.Context, userID string) error {
+	email, err := s.emailRepo.GetByUserID(ctx, account.UserID(userID))
+	if err != nil {
+		return err
+	}
+	return account.RequireVerifiedEmail(email)
+}
+
+// sendVerification stores a new verification for the email's address and mails the link
+func (s *EmailService) sendVerification(ctx context.Context, email *account.Email) error {
+	verification, err := account.NewEmailVerification(email.UserID, email.Address)
+	if err != nil {
+		return err
+	}
+
+	if err := s.emailRepo.SaveVerification(ctx, verification); err != nil {
+		return err
+	}
+
+	link, err := url.Parse(s.verifyURL)
+	if err != nil {
+		return err
+	}
+	query := link.Query()
+	query.Set("token", verification.Token)
+	link.RawQuery = query.Encode()
+
+	s.send(ctx, mailer.Message{
+		To:      email.Address,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Open this link to verify your email address:\n\n%s\n\n"+
+			"The link expires in %d hours.", link.String(), int(account.EmailVerificationTTL.Hours())),
+	})
+
+	return nil
+}
+
+// send delivers a message, logging failures. The player can always request another link.
+func (s *EmailService) send(ctx context.Context, msg mailer.Message) {
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to send email",
+			zap.String("subject", msg.Subject),
+			zap.Error(err))
+	}
+}
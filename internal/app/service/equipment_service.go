@@ -0,0 +1,158 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/crafting"
+	"github.com/danghamo/life/internal/domain/equipment"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// EquipmentService crafts equipment and puts it on the trainer's animals
+type EquipmentService struct {
+	logger        *logger.Logger
+	recipes       *crafting.Registry
+	crafting      *CraftingService
+	equipmentRepo equipment.Repository
+	animalRepo    animal.Repository
+}
+
+// NewEquipmentService creates a new equipment service
+func NewEquipmentService(
+	logger *logger.Logger,
+	recipes *crafting.Registry,
+	craftingService *CraftingService,
+	equipmentRepo equipment.Repository,
+	animalRepo animal.Repository,
+) *EquipmentService {
+	return &EquipmentService{
+		logger:        logger.WithComponent("equipment-service"),
+		recipes:       recipes,
+		crafting:      craftingService,
+		equipmentRepo: equipmentRepo,
+		animalRepo:    animalRepo,
+	}
+}
+
+// List returns the equipment the trainer holds, equipped or not
+func (s *EquipmentService) List(ctx context.Context, trainerID trainer.UserID) ([]*equipment.Equipment, error) {
+	return s.equipmentRepo.GetByTrainer(ctx, shared.ID(trainerID))
+}
+
+// Craft starts crafting an equipment recipe, consuming its materials from the inventory. Like
+// any craft the equipment is created when the job is collected.
+func (s *EquipmentService) Craft(ctx context.Context, trainerID trainer.UserID, recipeID crafting.RecipeID) (*crafting.Job, error) {
+	recipe, err := s.recipes.Get(recipeID)
+	if err != nil {
+		return nil, err
+	}
+	if recipe.Output.Kind != crafting.OutputEquipment {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeUnknownRecipe, "Recipe %s does not craft equipment", recipeID)
+	}
+
+	return s.crafting.StartCraft(ctx, trainerID, recipeID)
+}
+
+// Equip puts the trainer's equipment on one of their animals, adding its stats. Equipment the
+// animal wore before is taken off; equipment worn by another animal must be unequipped first.
+func (s *EquipmentService) Equip(ctx context.Context, trainerID trainer.UserID, equipmentID equipment.EquipmentID, animalID animal.AnimalID) (*animal.Animal, error) {
+	var bonus shared.Stats
+	err := s.equipmentRepo.FindOneAndUpdate(ctx, equipmentID, func(e *equipment.Equipment) (*equipment.Equipment, error) {
+		if e.TrainerID != shared.ID(trainerID) {
+			return nil, shared.ErrNotFound("Equipment")
+		}
+		if err := e.EquipTo(shared.ID(animalID)); err != nil {
+			return nil, err
+		}
+		bonus = e.GetEffectiveStats()
+		return e, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var equipped *animal.Animal
+	var previous shared.ID
+	err = s.animalRepo.FindOneAndUpdate(ctx, animalID, func(a *animal.Animal) (*animal.Animal, error) {
+		if a.OwnerID != shared.ID(trainerID) {
+			return nil, shared.NewDomainError(shared.ErrCodeNotCaptured, "Animal is not owned by this trainer")
+		}
+
+		previous, _ = a.GetEquippedItemID()
+		if err := a.EquipItem(shared.ID(equipmentID), bonus); err != nil {
+			return nil, err
+		}
+		equipped = a
+		return a, nil
+	})
+	if err != nil {
+		s.release(ctx, equipmentID)
+		return nil, err
+	}
+
+	if previous != "" {
+		s.release(ctx, equipment.EquipmentID(previous))
+	}
+
+	s.logger.WithContext(ctx).Info("Equipment equipped",
+		zap.String("userID", trainerID.String()),
+		zap.String("equipmentID", equipmentID.String()),
+		zap.String("animalID", animalID.String()))
+
+	return equipped, nil
+}
+
+// Unequip takes the equipment off one of the trainer's animals, removing its stats
+func (s *EquipmentService) Unequip(ctx context.Context, trainerID trainer.UserID, animalID animal.AnimalID) (*animal.Animal, error) {
+	var unequipped *animal.Animal
+	var equipmentID shared.ID
+	err := s.animalRepo.FindOneAndUpdate(ctx, animalID, func(a *animal.Animal) (*animal.Animal, error) {
+		if a.OwnerID != shared.ID(trainerID) {
+			return nil, shared.NewDomainError(shared.ErrCodeNotCaptured, "Animal is not owned by this trainer")
+		}
+
+		id, err := a.UnequipItem()
+		if err != nil {
+			return nil, err
+		}
+		equipmentID = id
+		unequipped = a
+		return a, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.release(ctx, equipment.EquipmentID(equipmentID))
+
+	s.logger.WithContext(ctx).Info("Equipment unequipped",
+		zap.String("userID", trainerID.String()),
+		zap.String("equipmentID", string(equipmentID)),
+		zap.String("animalID", animalID.String()))
+
+	return unequipped, nil
+}
+
+// release marks equipment as no longer worn. The animal's slot decides what is worn, so a
+// failure only leaves the equipment looking equipped and is logged.
+func (s *EquipmentService) release(ctx context.Context, equipmentID equipment.EquipmentID) {
+	err := s.equipmentRepo.FindOneAndUpdate(ctx, equipmentID, func(e *equipment.Equipment) (*equipment.Equipment, error) {
+		if !e.IsEquipped() {
+			return nil, nil
+		}
+		if err := e.Unequip(); err != nil {
+			return nil, err
+		}
+		return e, nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to release equipment",
+			zap.String("equipmentID", equipmentID.String()),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/pkg/eventarchive"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/objstore"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+const (
+	// archiveLockKey makes only one server instance archive per tick
+	archiveLockKey = "lock:archive:events"
+	// archiveCursorKey maps each stream to the ID of the last entry archived
+	archiveCursorKey = "archive:cursor"
+	// messageUUIDField is the stream entry field holding the message UUID
+	messageUUIDField = "_watermill_message_uuid"
+)
+
+// eventsArchived counts archived events per stream, served at /debug/vars
+var eventsArchived = expvar.NewMap("events_archived")
+
+// EventArchivePolicy configures the event archive
+type EventArchivePolicy struct {
+	Interval  time.Duration `json:"interval"`
+	Events    []string      `json:"events"`     // Stream key patterns, defaults to game-events.*
+	BatchSize int           `json:"batch_size"` // Entries read per stream per round
+	Prefix    string        `json:"prefix"`     // Object key prefix of the archive
+	// RedisRetention trims archived entries older than this from Redis; zero leaves trimming
+	// to the retention rules
+	RedisRetention time.Duration `json:"redis_retention"`
+}
+
+// EventArchiveService drains game event streams into compressed, partitioned files in object
+// storage, for replay and offline analytics beyond what Redis keeps
+type EventArchiveService struct {
+	logger      *logger.Logger
+	redisClient *redis.Client
+	store       objstore.Store
+	policy      EventArchivePolicy
+	stopChan    chan struct{}
+	ticker      *time.Ticker
+}
+
+// NewEventArchiveService creates a new event archive service; a nil store disables it
+func NewEventArchiveService(logger *logger.Logger, redisClient *redis.Client, store objstore.Store, policy EventArchivePolicy) *EventArchiveService {
+	if len(policy.Events) == 0 {
+		policy.Events = []string{"game-events.*"}
+	}
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = 5000
+	}
+
+	return &EventArchiveService{
+		logger:      logger.WithComponent("event-archive-service"),
+		redisClient: redisClient,
+		store:       store,
+		policy:      policy,
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start begins periodic archiving
+func (s *EventArchiveService) Start(ctx context.Context) {
+	if s.store == nil || s.policy.Interval <= 0 {
+		s.logger.WithContext(ctx).Info("Event archive disabled")
+		return
+	}
+
+	s.ticker = time.NewTicker(s.policy.Interval)
+
+	s.logger.WithContext(ctx).Info("Starting event archive",
+		zap.Duration("interval", s.policy.Interval),
+		zap.Strings("events", s.policy.Events),
+		zap.Duration("redisRetention", s.policy.RedisRetention))
+
+	go s.archiveLoop(ctx)
+}
+
+// Stop stops periodic archiving
+func (s *EventArchiveService) Stop() {
+	s.logger.Info("Stopping event archive")
+
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+
+	close(s.stopChan)
+}
+
+// archiveLoop archives on every tick
+func (s *EventArchiveService) archiveLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-s.ticker.C:
+			s.archiveTick(ctx)
+		}
+	}
+}
+
+// archiveTick archives new entries of every matching stream
+func (s *EventArchiveService) archiveTick(ctx context.Context) {
+	// Every server runs the loop; only the one holding the lock archives this tick
+	acquired, err := s.redisClient.SetNX(ctx, archiveLockKey, "1", s.policy.Interval).Result()
+	if err != nil || !acquired {
+		return
+	}
+	defer s.redisClient.Del(ctx, archiveLockKey)
+
+	for _, pattern := range s.policy.Events {
+		iter := s.redisClient.ScanType(ctx, 0, pattern, 100, "stream").Iterator()
+		for iter.Next(ctx) {
+			key := iter.Val()
+			if key == notificationStream || strings.HasPrefix(key, "analytics.") {
+				continue
+			}
+
+			archived, err := s.archiveStream(ctx, key)
+			eventsArchived.Add(key, archived)
+			if err != nil {
+				s.logger.WithContext(ctx).Error("Event archive failed",
+					zap.String("stream", key),
+					zap.Int64("archived", archived),
+					zap.Error(err))
+			}
+		}
+		if err := iter.Err(); err != nil {
+			s.logger.WithContext(ctx).Error("Event archive scan failed", zap.String("pattern", pattern), zap.Error(err))
+		}
+	}
+}
+
+// archiveStream writes a stream's entries after its cursor, one file per hour, advancing the
+// cursor after each file. A failure leaves the cursor at the last stored file, so the next
+// tick retries from there.
+func (s *EventArchiveService) archiveStream(ctx context.Context, key string) (int64, error) {
+	base := eventarchive.TenantBase(s.policy.Prefix, tenant.IDFromContext(ctx).String())
+	var archived int64
+
+	for {
+		start := "-"
+		cursor, err := archivedThrough(ctx, s.redisClient, key)
+		if err != nil {
+			return archived, err
+		}
+		if cursor != "" {
+			start = "(" + cursor
+		}
+
+		entries, err := s.redisClient.XRangeN(ctx, key, start, "+", int64(s.policy.BatchSize)).Result()
+		if err != nil {
+			return archived, err
+		}
+
+		events := make([]eventarchive.Event, 0, len(entries))
+		for _, entry := range entries {
+			events = append(events, archivedEvent(key, entry))
+		}
+
+		for _, group := range eventarchive.Partition(events) {
+			if _, err := eventarchive.Write(ctx, s.store, base, group); err != nil {
+				return archived, err
+			}
+			if err := s.redisClient.HSet(ctx, archiveCursorKey, key, group[len(group)-1].ID).Err(); err != nil {
+				return archived, err
+			}
+			archived += int64(len(group))
+		}
+
+		if err := s.trimArchived(ctx, key); err != nil {
+			return archived, err
+		}
+
+		if len(entries) < s.policy.BatchSize {
+			return archived, nil
+		}
+	}
+}
+
+// trimArchived drops archived entries older than the Redis retention from a stream
+func (s *EventArchiveService) trimArchived(ctx context.Context, key string) error {
+	if s.policy.RedisRetention <= 0 {
+		return nil
+	}
+
+	cursor, err := archivedThrough(ctx, s.redisClient, key)
+	if err != nil || cursor == "" {
+		return err
+	}
+
+	minID := fmt.Sprintf("%d-0", time.Now().Add(-s.policy.RedisRetention).UnixMilli())
+	if compareStreamIDs(cursor, minID) < 0 {
+		minID = cursor // Keeps entries after the cursor, which aren't archived yet
+	}
+	return s.redisClient.XTrimMinID(ctx, key, minID).Err()
+}
+
+// archivedEvent converts a stream entry to its archived form
+func archivedEvent(key string, entry redis.XMessage) eventarchive.Event {
+	// Stream IDs start with the entry's creation time in milliseconds
+	millis, _ := strconv.ParseInt(strings.SplitN(entry.ID, "-", 2)[0], 10, 64)
+
+	event := eventarchive.Event{
+		Stream:     key,
+		ID:         entry.ID,
+		OccurredAt: millis,
+	}
+	event.UUID, _ = entry.Values[messageUUIDField].(string)
+	event.Payload, _ = entry.Values["payload"].(string)
+	if metadata, _ := entry.Values["metadata"].(string); metadata != "" {
+		event.Metadata = []byte(metadata)
+	}
+	return event
+}
+
+// archivedThrough returns the ID of the last archived entry of a stream, empty if the
+// archive hasn't stored any
+func archivedThrough(ctx context.Context, client *redis.Client, key string) (string, error) {
+	cursor, err := client.HGet(ctx, archiveCursorKey, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return cursor, err
+}
+
+// compareStreamIDs orders two stream IDs of the form <milliseconds>-<sequence>
+func compareStreamIDs(a, b string) int {
+	aMillis, aSeq := splitStreamID(a)
+	bMillis, bSeq := splitStreamID(b)
+	switch {
+	case aMillis != bMillis:
+		if aMillis < bMillis {
+			return -1
+		}
+		return 1
+	case aSeq != bSeq:
+		if aSeq < bSeq {
+			return -1
+		}
+		return 1
+	default:
+		return 0
+	}
+}
+
+// splitStreamID parses the two parts of a stream ID
+func splitStreamID(id string) (uint64, uint64) {
+	millis, seq, _ := strings.Cut(id, "-")
+	m, _ := strconv.ParseUint(millis, 10, 64)
+	n, _ := strconv.ParseUint(seq, 10, 64)
+	return m, n
+}
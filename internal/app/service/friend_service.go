@@ -0,0 +1,303 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/friend"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// presenceRefreshInterval is how often the presence of connected players is refreshed
+const presenceRefreshInterval = friend.PresenceTTL / 3
+
+// FriendService manages friendships between trainers and tells players when their friends
+// come online or go offline. Players are online while connected over SSE or WebSocket to
+// any server instance, or while their trainer moves in the world.
+type FriendService struct {
+	logger       *logger.Logger
+	friendRepo   friend.Repository
+	presenceRepo friend.PresenceRepository
+	trainerRepo  trainer.Repository
+	push         *cqrscommands.SSEBroadcastHelper
+
+	connected map[string]bool // Players connected to this instance
+	mutex     sync.Mutex
+	ticker    *time.Ticker
+	stopChan  chan struct{}
+}
+
+// NewFriendService creates a new friend service
+func NewFriendService(logger *logger.Logger, friendRepo friend.Repository, presenceRepo friend.PresenceRepository, trainerRepo trainer.Repository, push *cqrscommands.SSEBroadcastHelper) *FriendService {
+	return &FriendService{
+		logger:       logger.WithComponent("friend-service"),
+		friendRepo:   friendRepo,
+		presenceRepo: presenceRepo,
+		trainerRepo:  trainerRepo,
+		push:         push,
+		connected:    make(map[string]bool),
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Request asks the trainer with a nickname to be friends
+func (s *FriendService) Request(ctx context.Context, userID trainer.UserID, nickname string) (friend.RequestStatus, error) {
+	sender, other, err := s.trainers(ctx, userID, nickname)
+	if err != nil {
+		return "", err
+	}
+
+	var status friend.RequestStatus
+	err = s.friendRepo.FindLinkAndUpdate(ctx, userID.String(), other.ID.String(), func(link *friend.Link) error {
+		var requestErr error
+		status, requestErr = link.Request()
+		return requestErr
+	})
+	if err != nil {
+		return "", err
+	}
+
+	method := "friend.request"
+	if status == friend.RequestAccepted {
+		method = "friend.accepted"
+	}
+	s.notify(ctx, other.ID.String(), method, sender.PublicNameplate())
+
+	s.logger.WithContext(ctx).Info("Friend request sent",
+		zap.String("userID", userID.String()),
+		zap.String("otherID", other.ID.String()),
+		zap.String("status", string(status)))
+	return status, nil
+}
+
+// Accept accepts the friend request of the trainer with a nickname
+func (s *FriendService) Accept(ctx context.Context, userID trainer.UserID, nickname string) error {
+	accepter, other, err := s.trainers(ctx, userID, nickname)
+	if err != nil {
+		return err
+	}
+
+	err = s.friendRepo.FindLinkAndUpdate(ctx, userID.String(), other.ID.String(), func(link *friend.Link) error {
+		return link.Accept()
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notify(ctx, other.ID.String(), "friend.accepted", accepter.PublicNameplate())
+
+	s.logger.WithContext(ctx).Info("Friend request accepted",
+		zap.String("userID", userID.String()),
+		zap.String("otherID", other.ID.String()))
+	return nil
+}
+
+// Remove ends the friendship with the trainer with a nickname, or withdraws or declines a
+// pending request between them
+func (s *FriendService) Remove(ctx context.Context, userID trainer.UserID, nickname string) error {
+	_, other, err := s.trainers(ctx, userID, nickname)
+	if err != nil {
+		return err
+	}
+
+	return s.friendRepo.FindLinkAndUpdate(ctx, userID.String(), other.ID.String(), func(link *friend.Link) error {
+		return link.Remove()
+	})
+}
+
+// List returns the trainer's friends and pending requests with whether each is online
+func (s *FriendService) List(ctx context.Context, userID trainer.UserID) (*friend.Friends, error) {
+	list, err := s.friendRepo.GetList(ctx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	friends, err := s.describe(ctx, list.Friends)
+	if err != nil {
+		return nil, err
+	}
+	incoming, err := s.describe(ctx, list.Incoming)
+	if err != nil {
+		return nil, err
+	}
+	outgoing, err := s.describe(ctx, list.Outgoing)
+	if err != nil {
+		return nil, err
+	}
+
+	return &friend.Friends{Friends: friends, Incoming: incoming, Outgoing: outgoing}, nil
+}
+
+// Start begins refreshing the presence of players connected to this instance
+func (s *FriendService) Start(ctx context.Context) {
+	s.ticker = time.NewTicker(presenceRefreshInterval)
+
+	s.logger.WithContext(ctx).Info("Starting presence tracking",
+		zap.Duration("interval", presenceRefreshInterval),
+		zap.Duration("ttl", friend.PresenceTTL))
+
+	go s.presenceLoop(ctx)
+}
+
+// Stop stops refreshing presence; players still connected expire after the presence TTL
+func (s *FriendService) Stop() {
+	s.logger.Info("Stopping presence tracking")
+
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+
+	close(s.stopChan)
+}
+
+// Connected marks a player whose first client connected to this instance as online
+func (s *FriendService) Connected(ctx context.Context, userID string) {
+	s.mutex.Lock()
+	s.connected[userID] = true
+	s.mutex.Unlock()
+
+	cameOnline, err := s.presenceRepo.SetOnline(ctx, []string{userID})
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to set presence", zap.String("userID", userID), zap.Error(err))
+		return
+	}
+	if cameOnline[0] {
+		s.notifyFriends(ctx, userID, true)
+	}
+}
+
+// Disconnected marks a player whose last client left this instance as offline
+func (s *FriendService) Disconnected(ctx context.Context, userID string) {
+	s.mutex.Lock()
+	delete(s.connected, userID)
+	s.mutex.Unlock()
+
+	if err := s.presenceRepo.SetOffline(ctx, userID); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to clear presence", zap.String("userID", userID), zap.Error(err))
+		return
+	}
+	s.notifyFriends(ctx, userID, false)
+}
+
+// presenceLoop refreshes presence on every tick
+func (s *FriendService) presenceLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-s.ticker.C:
+			s.refreshPresence(ctx)
+		}
+	}
+}
+
+// refreshPresence keeps connected players online, announcing those whose presence had
+// expired, e.g. while Redis was unreachable
+func (s *FriendService) refreshPresence(ctx context.Context) {
+	s.mutex.Lock()
+	userIDs := make([]string, 0, len(s.connected))
+	for userID := range s.connected {
+		userIDs = append(userIDs, userID)
+	}
+	s.mutex.Unlock()
+
+	cameOnline, err := s.presenceRepo.SetOnline(ctx, userIDs)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to refresh presence", zap.Int("players", len(userIDs)), zap.Error(err))
+		return
+	}
+	for i, userID := range userIDs {
+		if cameOnline[i] {
+			s.notifyFriends(ctx, userID, true)
+		}
+	}
+}
+
+// notifyFriends tells a player's friends that they came online or went offline
+func (s *FriendService) notifyFriends(ctx context.Context, userID string, online bool) {
+	friendIDs, err := s.friendRepo.GetFriendIDs(ctx, userID)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to get friends to notify", zap.String("userID", userID), zap.Error(err))
+		return
+	}
+	if len(friendIDs) == 0 {
+		return
+	}
+
+	t, err := s.trainerRepo.GetByID(ctx, trainer.UserID(userID))
+	if err != nil || t == nil {
+		// Players without a trainer have no nickname to announce
+		return
+	}
+
+	method := "friend.offline"
+	if online {
+		method = "friend.online"
+	}
+	change := friend.PresenceChange{Nickname: t.Nickname, Online: online}
+	if err := s.push.BroadcastToUsers(ctx, friendIDs, method, change); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to push presence change",
+			zap.String("userID", userID),
+			zap.Error(err))
+	}
+}
+
+// notify pushes a friend notification to a player's connected clients
+func (s *FriendService) notify(ctx context.Context, userID, method string, params interface{}) {
+	if err := s.push.BroadcastToUsers(ctx, []string{userID}, method, params); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to push friend notification",
+			zap.String("userID", userID),
+			zap.String("method", method),
+			zap.Error(err))
+	}
+}
+
+// trainers returns the player's trainer and the trainer with a nickname
+func (s *FriendService) trainers(ctx context.Context, userID trainer.UserID, nickname string) (*trainer.Trainer, *trainer.Trainer, error) {
+	user, err := s.trainerRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if user == nil {
+		return nil, nil, shared.ErrNotFound("Trainer")
+	}
+
+	other, err := s.trainerRepo.FindByNickname(ctx, nickname)
+	if err != nil {
+		return nil, nil, err
+	}
+	if other == nil {
+		return nil, nil, shared.ErrNotFound("Trainer")
+	}
+
+	return user, other, nil
+}
+
+// describe returns the nameplates and presence of trainers by user ID
+func (s *FriendService) describe(ctx context.Context, userIDs []string) ([]friend.Friend, error) {
+	online, err := s.presenceRepo.AreOnline(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	friends := make([]friend.Friend, 0, len(userIDs))
+	for i, userID := range userIDs {
+		t, err := s.trainerRepo.GetByID(ctx, trainer.UserID(userID))
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			continue // Deleted before their friendships were purged
+		}
+		friends = append(friends, friend.Friend{Profile: t.PublicNameplate(), Online: online[i]})
+	}
+	return friends, nil
+}
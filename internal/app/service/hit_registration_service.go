@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/bullet"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/instance"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+const (
+	// How often bullets in flight are checked for hits
+	hitRegistrationInterval = 50 * time.Millisecond
+	// hitCheckStep is the stretch of a bullet's flight checked at once, short enough that
+	// targets barely move within it
+	hitCheckStep = 5 * time.Millisecond
+)
+
+// bulletFlight is a bullet fired through this server that has not hit or expired yet
+type bulletFlight struct {
+	bullet  *bullet.Bullet
+	ctx     context.Context // Scoped to the shooter's tenant
+	room    instance.ID     // Instance of the room the bullet was fired in, which keeps the bullet
+	members []string        // Members of that room, nil in the open world
+	rewind  time.Duration   // How far behind the server the shooter saw the other trainers
+	checked time.Time       // Time of the flight checked up to
+}
+
+// HitRegistrationService checks the bullets fired through this server for hits on trainers.
+// Shooters see other trainers where the latest broadcast put them, a round trip and the
+// broadcast's age behind the server, so each bullet is checked against where its targets were
+// that long ago, up to trainer.PositionHistoryWindow. Hits damage their target. Bullets fired in
+// a room only hit its members.
+type HitRegistrationService struct {
+	logger       *logger.Logger
+	movement     *TenantMovement
+	latency      *LatencyService
+	interest     *InterestManager
+	positionRepo trainer.PositionRepository
+	bulletRepo   bullet.Repository
+	respawn      *RespawnService
+	eventBus     *cqrs.EventBus
+	mutex        sync.Mutex
+	flights      map[bullet.BulletID]*bulletFlight
+	stopChan     chan struct{}
+	ticker       *time.Ticker
+}
+
+// NewHitRegistrationService creates a new hit registration service
+func NewHitRegistrationService(
+	logger *logger.Logger,
+	movement *TenantMovement,
+	latency *LatencyService,
+	interest *InterestManager,
+	positionRepo trainer.PositionRepository,
+	bulletRepo bullet.Repository,
+	respawn *RespawnService,
+	eventBus *cqrs.EventBus,
+) *HitRegistrationService {
+	return &HitRegistrationService{
+		logger:       logger.WithComponent("hit-registration-service"),
+		movement:     movement,
+		latency:      latency,
+		interest:     interest,
+		positionRepo: positionRepo,
+		bulletRepo:   bulletRepo,
+		respawn:      respawn,
+		eventBus:     eventBus,
+		flights:      make(map[bullet.BulletID]*bulletFlight),
+		stopChan:     make(chan struct{}),
+	}
+}
+
+// Start begins checking bullets in flight
+func (s *HitRegistrationService) Start(ctx context.Context) {
+	s.ticker = time.NewTicker(hitRegistrationInterval)
+
+	s.logger.WithContext(ctx).Info("Starting hit registration",
+		zap.Duration("interval", hitRegistrationInterval),
+		zap.Duration("max_rewind", trainer.PositionHistoryWindow))
+
+	go s.checkLoop(ctx)
+}
+
+// Stop stops checking bullets in flight
+func (s *HitRegistrationService) Stop() {
+	s.logger.Info("Stopping hit registration")
+
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+
+	close(s.stopChan)
+}
+
+// Track follows a bullet just fired until it hits a trainer or expires. The shooter's lag is
+// taken when it fires. members are the players of the room it was fired in, nil outside rooms.
+func (s *HitRegistrationService) Track(ctx context.Context, fired *bullet.Bullet, members []string) {
+	shooter := fired.PlayerID.String()
+	rewind := min(s.latency.Estimate(shooter)+s.movement.BroadcastAge(ctx), trainer.PositionHistoryWindow)
+
+	flightCtx := context.Background()
+	if t, ok := tenant.FromContext(ctx); ok {
+		flightCtx = tenant.WithTenant(flightCtx, t)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.flights[fired.ID] = &bulletFlight{bullet: fired, ctx: flightCtx, room: instance.IDFromContext(ctx), members: members, rewind: rewind, checked: fired.FiredAt}
+}
+
+// checkLoop checks the bullets in flight on every tick
+func (s *HitRegistrationService) checkLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-s.ticker.C:
+			s.checkFlights()
+		}
+	}
+}
+
+// checkFlights checks every bullet in flight up to now, dropping those that hit or expired
+func (s *HitRegistrationService) checkFlights() {
+	s.mutex.Lock()
+	flights := make([]*bulletFlight, 0, len(s.flights))
+	for _, flight := range s.flights {
+		flights = append(flights, flight)
+	}
+	s.mutex.Unlock()
+
+	now := time.Now()
+	for _, flight := range flights {
+		done, err := s.check(flight, now)
+		if err != nil {
+			s.logger.Warn("Failed to check bullet for hits",
+				zap.String("bulletID", flight.bullet.ID.String()),
+				zap.Error(err))
+		}
+		if done || err != nil {
+			s.mutex.Lock()
+			delete(s.flights, flight.bullet.ID)
+			s.mutex.Unlock()
+		}
+	}
+}
+
+// check follows a bullet's flight from where it was last checked up to now. The result
+// reports whether the bullet is done, having hit a trainer or expired.
+func (s *HitRegistrationService) check(flight *bulletFlight, now time.Time) (bool, error) {
+	fired := flight.bullet
+	until := now
+	if fired.ExpiresAt.Before(until) {
+		until = fired.ExpiresAt
+	}
+	if !until.After(flight.checked) {
+		return !now.Before(fired.ExpiresAt), nil
+	}
+
+	targets, err := s.targetsAlong(flight, until)
+	if err != nil {
+		return false, err
+	}
+
+	for from := flight.checked; from.Before(until); from = from.Add(hitCheckStep) {
+		to := from.Add(hitCheckStep)
+		if to.After(until) {
+			to = until
+		}
+
+		// Of the trainers the bullet reaches within this step, it hits the first in its path
+		targetID, along, found := "", 0.0, false
+		for id, standing := range targets {
+			position, ok := s.targetAt(flight, id, to.Add(-flight.rewind), standing)
+			if !ok {
+				continue
+			}
+			if at, hit := fired.HitBetween(from, to, position); hit && (!found || at < along) {
+				targetID, along, found = id, at, true
+			}
+		}
+		if found {
+			hitAt := from.Add(time.Duration(float64(to.Sub(from)) * along))
+			s.hit(flight, targetID, hitAt)
+			return true, nil
+		}
+	}
+
+	flight.checked = until
+	return !now.Before(fired.ExpiresAt), nil
+}
+
+// targetsAlong returns the trainers near the bullet's path up to until, other than the
+// shooter, with the positions of those standing still
+func (s *HitRegistrationService) targetsAlong(flight *bulletFlight, until time.Time) (map[string]*shared.Position, error) {
+	fired := flight.bullet
+	candidates, err := s.interest.UsersInArea(flight.ctx, fired.PositionAt(flight.checked), fired.PositionAt(until))
+	if err != nil {
+		return nil, err
+	}
+
+	targets := make(map[string]*shared.Position, len(candidates))
+	var standing []trainer.UserID
+	for _, id := range candidates {
+		if id == fired.PlayerID.String() || (flight.members != nil && !slices.Contains(flight.members, id)) {
+			continue
+		}
+		targets[id] = nil
+		// Trainers the simulation doesn't have stand still where they were last saved
+		if _, ok := s.movement.PositionAt(flight.ctx, id, time.Now()); !ok {
+			standing = append(standing, trainer.UserID(id))
+		}
+	}
+	if len(standing) == 0 {
+		return targets, nil
+	}
+
+	snapshots, err := s.positionRepo.GetMany(flight.ctx, standing)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range standing {
+		snapshot, ok := snapshots[id]
+		if !ok {
+			delete(targets, id.String())
+			continue
+		}
+		position := snapshot.Position
+		targets[id.String()] = &position
+	}
+	return targets, nil
+}
+
+// targetAt returns where a target was at a point in time, as the shooter saw it. It returns
+// false for moving targets whose positions that long ago are no longer known.
+func (s *HitRegistrationService) targetAt(flight *bulletFlight, userID string, at time.Time, standing *shared.Position) (shared.Position, bool) {
+	if standing != nil {
+		return *standing, true
+	}
+	return s.movement.PositionAt(flight.ctx, userID, at)
+}
+
+// hit ends a bullet's flight on a trainer, damages the trainer and tells all players
+func (s *HitRegistrationService) hit(flight *bulletFlight, targetID string, hitAt time.Time) {
+	fired := flight.bullet
+	ctx := flight.ctx
+	position := fired.PositionAt(hitAt)
+
+	if err := fired.Hit(position); err != nil {
+		s.logger.Warn("Failed to mark bullet hit", zap.String("bulletID", fired.ID.String()), zap.Error(err))
+		return
+	}
+	if err := s.bulletRepo.Save(instance.WithInstance(ctx, flight.room), fired); err != nil {
+		s.logger.Error("Failed to save hit bullet", zap.String("bulletID", fired.ID.String()), zap.Error(err))
+	}
+
+	damage := fired.WeaponType.GetDamage()
+	killed, err := s.respawn.Damage(ctx, trainer.UserID(targetID), damage, trainer.DeathByBullet, fired.PlayerID.String())
+	if err != nil {
+		s.logger.Error("Failed to damage hit trainer",
+			zap.String("bulletID", fired.ID.String()),
+			zap.String("targetID", targetID),
+			zap.Error(err))
+		return
+	}
+
+	event := &cqrscommands.BulletHitEvent{
+		UserID:     fired.PlayerID.String(),
+		BulletID:   fired.ID.String(),
+		WeaponType: fired.WeaponType,
+		TargetID:   targetID,
+		Position:   position,
+		Damage:     damage,
+		Killed:     killed,
+		RewindMs:   flight.rewind.Milliseconds(),
+		HitAt:      hitAt,
+		RoomID:     flight.room.String(),
+		Recipients: flight.members,
+		Timestamp:  time.Now(),
+		RequestID:  requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.Error("Failed to publish bullet hit event",
+			zap.String("bulletID", fired.ID.String()),
+			zap.Error(err))
+	}
+
+	s.logger.Debug("Bullet hit",
+		zap.String("bulletID", fired.ID.String()),
+		zap.String("shooterID", fired.PlayerID.String()),
+		zap.String("targetID", targetID),
+		zap.Duration("rewind", flight.rewind),
+		zap.Bool("killed", killed))
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/idle"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+const (
+	// idleSweepInterval is how often players are checked for having gone AFK
+	idleSweepInterval = 30 * time.Second
+	// idleSweepLockKey makes only one server instance check per tick
+	idleSweepLockKey = "lock:idle:sweep"
+)
+
+// afkPlayers is the AFK players of a tenant as last read
+type afkPlayers struct {
+	users  map[string]bool
+	readAt time.Time
+}
+
+// IdleService puts players who stopped playing into the AFK state and takes them out again
+// when they come back. Players count as active while they send inputs, which are requests
+// other than reads, or acknowledge the notifications they receive. AFK players leave the
+// present trainers of their interest chunk, so they don't meet players in encounters, and
+// receive position broadcasts on the reduced rate.
+type IdleService struct {
+	logger      *logger.Logger
+	idle        idle.Repository
+	interest    *InterestManager
+	movement    *TenantMovement
+	push        *cqrscommands.SSEBroadcastHelper
+	redisClient *redis.Client
+	timeout     time.Duration
+	stopChan    chan struct{}
+	ticker      *time.Ticker
+
+	mutex    sync.Mutex
+	activeAt map[string]time.Time // When each player's activity was last recorded from here
+	afk      map[tenant.ID]*afkPlayers
+}
+
+// NewIdleService creates a new idle service marking players AFK after timeout without
+// activity, or idle.DefaultTimeout when it isn't positive
+func NewIdleService(
+	logger *logger.Logger,
+	idleRepo idle.Repository,
+	interest *InterestManager,
+	movement *TenantMovement,
+	push *cqrscommands.SSEBroadcastHelper,
+	redisClient *redis.Client,
+	timeout time.Duration,
+) *IdleService {
+	if timeout <= 0 {
+		timeout = idle.DefaultTimeout
+	}
+
+	return &IdleService{
+		logger:      logger.WithComponent("idle-service"),
+		idle:        idleRepo,
+		interest:    interest,
+		movement:    movement,
+		push:        push,
+		redisClient: redisClient,
+		timeout:     timeout,
+		stopChan:    make(chan struct{}),
+		activeAt:    make(map[string]time.Time),
+		afk:         make(map[tenant.ID]*afkPlayers),
+	}
+}
+
+// Start begins checking players for having gone AFK
+func (s *IdleService) Start(ctx context.Context) {
+	s.ticker = time.NewTicker(idleSweepInterval)
+
+	s.logger.WithContext(ctx).Info("Starting AFK detection",
+		zap.Duration("interval", idleSweepInterval),
+		zap.Duration("timeout", s.timeout))
+
+	go s.sweepLoop(ctx)
+}
+
+// Stop stops checking players for having gone AFK
+func (s *IdleService) Stop() {
+	s.logger.Info("Stopping AFK detection")
+
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+
+	close(s.stopChan)
+}
+
+// Active records that a player did something, bringing them back if they were AFK. It is
+// recorded once per idle.ActivityResolution at most; failures are logged, as they must not
+// fail what the player did.
+func (s *IdleService) Active(ctx context.Context, userID string) {
+	now := time.Now()
+	key := tenant.IDFromContext(ctx).String() + ":" + userID
+
+	s.mutex.Lock()
+	if now.Sub(s.activeAt[key]) < idle.ActivityResolution {
+		s.mutex.Unlock()
+		return
+	}
+	s.activeAt[key] = now
+	s.mutex.Unlock()
+
+	returned, err := s.idle.Active(ctx, userID, now)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to record activity", zap.String("userId", userID), zap.Error(err))
+		return
+	}
+	if returned {
+		s.back(ctx, userID)
+	}
+}
+
+// Forget drops what is known about a player's activity once they have no connection left
+func (s *IdleService) Forget(ctx context.Context, userID string) {
+	key := tenant.IDFromContext(ctx).String() + ":" + userID
+
+	s.mutex.Lock()
+	delete(s.activeAt, key)
+	s.mutex.Unlock()
+
+	if err := s.idle.DeleteUser(ctx, userID); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to forget activity", zap.String("userId", userID), zap.Error(err))
+	}
+}
+
+// Reduced reports whether a player is AFK, which puts them on the reduced broadcast rate
+func (s *IdleService) Reduced(ctx context.Context, userID string) bool {
+	return s.afkPlayers(ctx)[userID]
+}
+
+// afkPlayers returns the AFK players of the context's tenant, read again once
+// reducedRateRefresh has passed. When reading fails the players last read are kept.
+func (s *IdleService) afkPlayers(ctx context.Context) map[string]bool {
+	tenantID := tenant.IDFromContext(ctx)
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	cached := s.afk[tenantID]
+	if cached != nil && time.Since(cached.readAt) < reducedRateRefresh {
+		return cached.users
+	}
+
+	userIDs, err := s.idle.AFK(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to read AFK players", zap.Error(err))
+		if cached == nil {
+			return nil
+		}
+		cached.readAt = time.Now()
+		return cached.users
+	}
+
+	users := make(map[string]bool, len(userIDs))
+	for _, userID := range userIDs {
+		users[userID] = true
+	}
+	s.afk[tenantID] = &afkPlayers{users: users, readAt: time.Now()}
+	return users
+}
+
+// sweepLoop checks players for having gone AFK on every tick
+func (s *IdleService) sweepLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-s.ticker.C:
+			s.prune(time.Now())
+			s.sweep(ctx)
+		}
+	}
+}
+
+// prune drops the times activity was recorded from here that are too old to hold back a new
+// record, so players who left without disconnecting don't stay in memory
+func (s *IdleService) prune(now time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for key, at := range s.activeAt {
+		if now.Sub(at) >= idle.ActivityResolution {
+			delete(s.activeAt, key)
+		}
+	}
+}
+
+// sweep puts the players without activity for the timeout into the AFK state
+func (s *IdleService) sweep(ctx context.Context) {
+	// Every server runs the loop; only the one holding the lock checks this tick
+	acquired, err := s.redisClient.SetNX(ctx, idleSweepLockKey, "1", idleSweepInterval/2).Result()
+	if err != nil || !acquired {
+		return
+	}
+
+	now := time.Now()
+	userIDs, err := s.idle.MarkIdle(ctx, now.Add(-s.timeout), now)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to mark idle players", zap.Error(err))
+		return
+	}
+
+	for _, userID := range userIDs {
+		if err := s.interest.Away(ctx, userID); err != nil {
+			s.logger.WithContext(ctx).Warn("Failed to take AFK trainer out of its chunk",
+				zap.String("userId", userID),
+				zap.Error(err))
+		}
+		s.send(ctx, userID, idle.Change{AFK: true, Since: &now})
+	}
+
+	if len(userIDs) > 0 {
+		s.invalidate(ctx)
+		s.logger.WithContext(ctx).Info("Players went AFK", zap.Int("count", len(userIDs)))
+	}
+}
+
+// back makes a player who was AFK present in their chunk again
+func (s *IdleService) back(ctx context.Context, userID string) {
+	s.invalidate(ctx)
+
+	t, err := s.movement.Position(ctx, userID)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to get position of returning trainer", zap.String("userId", userID), zap.Error(err))
+	} else if t != nil {
+		if err := s.interest.UpdatePosition(ctx, userID, t.Position); err != nil {
+			s.logger.WithContext(ctx).Warn("Failed to put returning trainer in its chunk", zap.String("userId", userID), zap.Error(err))
+		}
+	}
+
+	s.send(ctx, userID, idle.Change{AFK: false})
+	s.logger.WithContext(ctx).Info("Player came back from AFK", zap.String("userId", userID))
+}
+
+// invalidate drops this server's AFK players of the context's tenant, so a change applies
+// on it right away
+func (s *IdleService) invalidate(ctx context.Context) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.afk, tenant.IDFromContext(ctx))
+}
+
+// send pushes a trainer.afk message to the player's clients
+func (s *IdleService) send(ctx context.Context, userID string, change idle.Change) {
+	if err := s.push.BroadcastToUsers(ctx, []string{userID}, "trainer.afk", change); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to send AFK change", zap.String("userId", userID), zap.Error(err))
+	}
+}
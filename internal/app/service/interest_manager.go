@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/social"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+const (
+	// DefaultInterestChunkSize is the side length of one interest chunk in world units
+	DefaultInterestChunkSize = 10.0
+	// DefaultInterestRadius is how far away a trainer can see other trainers move
+	DefaultInterestRadius = 15.0
+	// interestPresenceTTL is how long a trainer counts as present without position updates
+	interestPresenceTTL = 2 * time.Minute
+)
+
+// InterestManager tracks which world chunk each trainer is in so position updates only
+// reach trainers close enough to see them. Chunk membership is kept in Redis so every
+// server instance agrees on who is where.
+type InterestManager struct {
+	logger      *logger.Logger
+	redisClient *redis.Client
+	chunkSize   float64
+	radius      float64
+}
+
+// NewInterestManager creates a new chunk-based interest manager
+func NewInterestManager(logger *logger.Logger, redisClient *redis.Client, chunkSize, radius float64) *InterestManager {
+	if chunkSize <= 0 {
+		chunkSize = DefaultInterestChunkSize
+	}
+	if radius <= 0 {
+		radius = DefaultInterestRadius
+	}
+
+	return &InterestManager{
+		logger:      logger.WithComponent("interest-manager"),
+		redisClient: redisClient,
+		chunkSize:   chunkSize,
+		radius:      radius,
+	}
+}
+
+// UpdatePosition records the chunk a trainer is in
+func (m *InterestManager) UpdatePosition(ctx context.Context, userID string, position shared.Position) error {
+	_, err := m.move(ctx, userID, position)
+	return err
+}
+
+// Audience records the trainer's position and returns the users who can see it. Users
+// around the previous chunk are included when the trainer changes chunk, so they see it leave.
+func (m *InterestManager) Audience(ctx context.Context, userID string, position shared.Position) ([]string, error) {
+	previous, err := m.move(ctx, userID, position)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := m.chunkKeysAround(position)
+	if previous != nil {
+		keys = append(keys, m.chunkKeysAround(*previous)...)
+	}
+
+	users, err := m.redisClient.SUnion(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
+// UsersNear returns the users whose trainers can see a position
+func (m *InterestManager) UsersNear(ctx context.Context, position shared.Position) ([]string, error) {
+	return m.redisClient.SUnion(ctx, m.chunkKeysAround(position)...).Result()
+}
+
+// UsersInArea returns the users whose trainers can see any position in the rectangle between
+// two corners
+func (m *InterestManager) UsersInArea(ctx context.Context, from, to shared.Position) ([]string, error) {
+	return m.redisClient.SUnion(ctx, m.chunkKeysBetween(from, to)...).Result()
+}
+
+// Away takes a trainer out of the present trainers of their chunk, e.g. while their player is
+// AFK. They keep receiving the chunk's updates, and are present again with their next
+// position update.
+func (m *InterestManager) Away(ctx context.Context, userID string) error {
+	return m.redisClient.Del(ctx, m.seenKey(userID), m.sinceKey(userID)).Err()
+}
+
+// SharedChunks returns the present trainers of every chunk holding more than one of them.
+// Trainers without a recent position update are left out, since chunks are not cleaned up
+// when trainers go offline.
+func (m *InterestManager) SharedChunks(ctx context.Context) ([][]social.Presence, error) {
+	groups := make([][]social.Presence, 0)
+
+	iter := m.redisClient.Scan(ctx, 0, "idx:interest:chunk:*", 100).Iterator()
+	for iter.Next(ctx) {
+		members, err := m.redisClient.SMembers(ctx, iter.Val()).Result()
+		if err != nil {
+			return nil, err
+		}
+		if len(members) < 2 {
+			continue
+		}
+
+		present, err := m.presence(ctx, members)
+		if err != nil {
+			return nil, err
+		}
+		if len(present) > 1 {
+			groups = append(groups, present)
+		}
+	}
+
+	return groups, iter.Err()
+}
+
+// presence returns which of the users are present and when they entered their chunk
+func (m *InterestManager) presence(ctx context.Context, userIDs []string) ([]social.Presence, error) {
+	keys := make([]string, 0, len(userIDs)*2)
+	for _, userID := range userIDs {
+		keys = append(keys, m.seenKey(userID), m.sinceKey(userID))
+	}
+
+	values, err := m.redisClient.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	present := make([]social.Presence, 0, len(userIDs))
+	for i, userID := range userIDs {
+		since, ok := values[i*2+1].(string)
+		if values[i*2] == nil || !ok {
+			continue
+		}
+
+		millis, err := strconv.ParseInt(since, 10, 64)
+		if err != nil {
+			continue
+		}
+		present = append(present, social.Presence{UserID: userID, Since: time.UnixMilli(millis)})
+	}
+
+	return present, nil
+}
+
+// move stores the trainer's chunk, returning the center of its previous chunk if it changed
+func (m *InterestManager) move(ctx context.Context, userID string, position shared.Position) (*shared.Position, error) {
+	trainerKey := m.trainerKey(userID)
+	chunk := m.chunkKey(m.chunkOf(position))
+
+	current, err := m.redisClient.Get(ctx, trainerKey).Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+
+	if current == chunk {
+		// A trainer back from being away entered the chunk as of now
+		_, err := m.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, m.seenKey(userID), 1, interestPresenceTTL)
+			pipe.SetNX(ctx, m.sinceKey(userID), time.Now().UnixMilli(), 0)
+			return nil
+		})
+		return nil, err
+	}
+
+	_, err = m.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if current != "" {
+			pipe.SRem(ctx, current, userID)
+		}
+		pipe.SAdd(ctx, chunk, userID)
+		pipe.Set(ctx, trainerKey, chunk, 0)
+		pipe.Set(ctx, m.sinceKey(userID), time.Now().UnixMilli(), 0)
+		pipe.Set(ctx, m.seenKey(userID), 1, interestPresenceTTL)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.logger.WithContext(ctx).Debug("Trainer changed interest chunk",
+		zap.String("userID", userID),
+		zap.String("from", current),
+		zap.String("to", chunk))
+
+	if current == "" {
+		return nil, nil
+	}
+
+	var cx, cy int
+	if _, err := fmt.Sscanf(current, "idx:interest:chunk:%d:%d", &cx, &cy); err != nil {
+		return nil, nil // Unknown format; only notify around the new chunk
+	}
+	previous := shared.NewPosition((float64(cx)+0.5)*m.chunkSize, (float64(cy)+0.5)*m.chunkSize)
+	return &previous, nil
+}
+
+// chunkKeysAround returns the keys of every chunk overlapping the interest radius
+func (m *InterestManager) chunkKeysAround(position shared.Position) []string {
+	return m.chunkKeysBetween(position, position)
+}
+
+// chunkKeysBetween returns the chunk keys within the interest radius of the rectangle between
+// two corners
+func (m *InterestManager) chunkKeysBetween(from, to shared.Position) []string {
+	minX, minY := m.chunkOf(shared.NewPosition(math.Min(from.X, to.X)-m.radius, math.Min(from.Y, to.Y)-m.radius))
+	maxX, maxY := m.chunkOf(shared.NewPosition(math.Max(from.X, to.X)+m.radius, math.Max(from.Y, to.Y)+m.radius))
+
+	keys := make([]string, 0, (maxX-minX+1)*(maxY-minY+1))
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			keys = append(keys, m.chunkKey(x, y))
+		}
+	}
+	return keys
+}
+
+// chunkOf returns the chunk coordinates containing position
+func (m *InterestManager) chunkOf(position shared.Position) (int, int) {
+	return int(math.Floor(position.X / m.chunkSize)), int(math.Floor(position.Y / m.chunkSize))
+}
+
+// chunkKey returns the Redis set of trainers in a chunk
+func (m *InterestManager) chunkKey(x, y int) string {
+	return fmt.Sprintf("idx:interest:chunk:%d:%d", x, y)
+}
+
+// trainerKey returns the Redis key holding a trainer's current chunk
+func (m *InterestManager) trainerKey(userID string) string {
+	return fmt.Sprintf("interest:trainer:%s", userID)
+}
+
+// sinceKey returns the Redis key holding when a trainer entered their current chunk
+func (m *InterestManager) sinceKey(userID string) string {
+	return fmt.Sprintf("interest:since:%s", userID)
+}
+
+// seenKey returns the Redis key that exists while a trainer has recently updated their position
+func (m *InterestManager) seenKey(userID string) string {
+	return fmt.Sprintf("interest:seen:%s", userID)
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+func TestInterestManager_ChunkKeysBetween(t *testing.T) {
+	m := NewInterestManager(logger.NewDefault(), nil, 10, 4)
+
+	tests := []struct {
+		name     string
+		from, to shared.Position
+		want     []string
+	}{
+		{
+			name: "radius within one chunk",
+			from: shared.NewPosition(15, 15),
+			to:   shared.NewPosition(15, 15),
+			want: []string{"idx:interest:chunk:1:1"},
+		},
+		{
+			name: "radius reaching the next chunks",
+			from: shared.NewPosition(16, 12),
+			to:   shared.NewPosition(16, 12),
+			want: []string{"idx:interest:chunk:1:0", "idx:interest:chunk:1:1", "idx:interest:chunk:2:0", "idx:interest:chunk:2:1"},
+		},
+		{
+			name: "negative coordinates",
+			from: shared.NewPosition(-5, -5),
+			to:   shared.NewPosition(-5, -5),
+			want: []string{"idx:interest:chunk:-1:-1"},
+		},
+		{
+			name: "corners in either order",
+			from: shared.NewPosition(35, 15),
+			to:   shared.NewPosition(15, 15),
+			want: []string{"idx:interest:chunk:1:1", "idx:interest:chunk:2:1", "idx:interest:chunk:3:1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, m.chunkKeysBetween(tt.from, tt.to))
+		})
+	}
+}
+
+func TestNewInterestManager_Defaults(t *testing.T) {
+	m := NewInterestManager(logger.NewDefault(), nil, 0, -1)
+	assert.Equal(t, DefaultInterestChunkSize, m.chunkSize)
+	assert.Equal(t, DefaultInterestRadius, m.radius)
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/vault"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+// Inventory sources that can be queried
+const (
+	InventorySourceCarried = "inventory"
+	InventorySourceVault   = "vault"
+)
+
+// Inventory action names reported in bulk results and inventory change events
+const (
+	BulkActionSell        = "sell"
+	BulkActionMoveToVault = "move_to_vault"
+	BulkActionDrop        = "drop"
+	BulkActionUse         = "use"
+	BulkActionGive        = "give"
+)
+
+// InventoryService queries item stacks and runs bulk inventory actions
+type InventoryService struct {
+	logger       *logger.Logger
+	trainerRepo  trainer.Repository
+	vaultRepo    vault.Repository
+	vaultService *VaultService
+	emails       *EmailService
+	eventBus     *cqrs.EventBus
+}
+
+// NewInventoryService creates a new inventory service
+func NewInventoryService(logger *logger.Logger, trainerRepo trainer.Repository, vaultRepo vault.Repository, vaultService *VaultService, emails *EmailService, eventBus *cqrs.EventBus) *InventoryService {
+	return &InventoryService{
+		logger:       logger.WithComponent("inventory-service"),
+		trainerRepo:  trainerRepo,
+		vaultRepo:    vaultRepo,
+		vaultService: vaultService,
+		emails:       emails,
+		eventBus:     eventBus,
+	}
+}
+
+// Query returns one page of items from the carried inventory or the vault
+func (s *InventoryService) Query(ctx context.Context, userID trainer.UserID, source string, query trainer.InventoryQuery) (*trainer.InventoryPage, error) {
+	if err := query.Validate(); err != nil {
+		return nil, err
+	}
+
+	var items []*trainer.Item
+	switch source {
+	case "", InventorySourceCarried:
+		t, err := s.trainerRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			return nil, shared.ErrNotFound("Trainer")
+		}
+		items = t.Inventory.GetAllItems()
+	case InventorySourceVault:
+		v, err := s.vaultRepo.GetByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		items = make([]*trainer.Item, 0, len(v.Items))
+		for _, item := range v.Items {
+			items = append(items, item)
+		}
+	default:
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Invalid inventory source: %s", source)
+	}
+
+	page, total := query.Apply(items)
+	return &trainer.InventoryPage{
+		Items:  page,
+		Total:  total,
+		Offset: query.Offset,
+		Limit:  query.Limit,
+	}, nil
+}
+
+// Sell sells the selected stacks, or every stack matching the filter when no IDs are given
+func (s *InventoryService) Sell(ctx context.Context, userID trainer.UserID, itemIDs []trainer.ItemID, filter trainer.InventoryQuery) (*trainer.BulkResult, error) {
+	if len(itemIDs) == 0 {
+		if len(filter.Types) == 0 && len(filter.Rarities) == 0 && filter.Category == "" {
+			return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Item IDs or a filter are required")
+		}
+		if err := filter.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	var result *trainer.BulkResult
+
+	err := s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		ids := itemIDs
+		if len(ids) == 0 {
+			for _, item := range t.Inventory.GetAllItems() {
+				if filter.Matches(item) {
+					ids = append(ids, item.ID)
+				}
+			}
+		}
+
+		sold, earned, err := t.SellItems(ids)
+		if err != nil {
+			return nil, err
+		}
+
+		result = trainer.NewBulkResult(BulkActionSell, t, sold)
+		result.MoneyEarned = earned
+
+		if len(sold) == 0 {
+			return nil, nil // Nothing to sell
+		}
+		return t, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if result.StacksAffected > 0 {
+		publishInventoryChanged(ctx, s.eventBus, s.logger, userID, result)
+	}
+
+	s.logger.WithContext(ctx).Info("Items sold",
+		zap.String("userID", userID.String()),
+		zap.Int("stacks", result.StacksAffected),
+		zap.Int("earned", result.MoneyEarned))
+
+	return result, nil
+}
+
+// MoveToVault deposits the selected stacks into the vault in a single transfer
+func (s *InventoryService) MoveToVault(ctx context.Context, userID trainer.UserID, itemIDs []trainer.ItemID) (*trainer.BulkResult, error) {
+	var result *trainer.BulkResult
+
+	err := s.vaultRepo.Transfer(ctx, userID, func(t *trainer.Trainer, v *vault.Vault) error {
+		if err := s.vaultService.requireLocation(t); err != nil {
+			return err
+		}
+
+		moved, err := t.Inventory.TakeTransferable(itemIDs)
+		if err != nil {
+			return err
+		}
+		for _, item := range moved {
+			if err := v.Deposit(item); err != nil {
+				return err
+			}
+		}
+
+		t.UpdatedAt = shared.NewTimestamp()
+		result = trainer.NewBulkResult(BulkActionMoveToVault, t, moved)
+		result.VaultUsedSlots = v.GetUsedSlots()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishInventoryChanged(ctx, s.eventBus, s.logger, userID, result)
+
+	s.logger.WithContext(ctx).Info("Items moved to vault",
+		zap.String("userID", userID.String()),
+		zap.Int("stacks", result.StacksAffected))
+
+	return result, nil
+}
+
+// Drop discards the selected items, whole stacks or part of them, in one atomic update
+func (s *InventoryService) Drop(ctx context.Context, userID trainer.UserID, drops []trainer.ItemDrop) (*trainer.BulkResult, error) {
+	if len(drops) == 0 {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Items to drop are required")
+	}
+
+	var result *trainer.BulkResult
+
+	err := s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		dropped, err := t.DropItems(drops)
+		if err != nil {
+			return nil, err
+		}
+
+		result = trainer.NewBulkResult(BulkActionDrop, t, dropped)
+		return t, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	publishInventoryChanged(ctx, s.eventBus, s.logger, userID, result)
+
+	s.logger.WithContext(ctx).Info("Items dropped",
+		zap.String("userID", userID.String()),
+		zap.Int("stacks", result.StacksAffected),
+		zap.Int("quantity", result.Quantity))
+
+	return result, nil
+}
+
+// Give hands the selected stacks to another trainer. Bound items are refused; when the
+// recipient can't take the items they are returned to the giver.
+func (s *InventoryService) Give(ctx context.Context, userID, recipientID trainer.UserID, itemIDs []trainer.ItemID) (*trainer.BulkResult, error) {
+	if recipientID == userID {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Items can't be given to yourself")
+	}
+
+	if err := s.emails.RequireVerified(ctx, userID.AccountID().String()); err != nil {
+		return nil, err
+	}
+
+	recipient, err := s.trainerRepo.GetByID(ctx, recipientID)
+	if err != nil {
+		return nil, err
+	}
+	if recipient == nil {
+		return nil, shared.ErrNotFound("Trainer")
+	}
+
+	var result *trainer.BulkResult
+	err = s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		given, err := t.Inventory.TakeTransferable(itemIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		t.UpdatedAt = shared.NewTimestamp()
+		result = trainer.NewBulkResult(BulkActionGive, t, given)
+		return t, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.trainerRepo.FindOneAndUpdate(ctx, recipientID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		for _, item := range result.Items {
+			received := *item // Picking it up may bind the recipient's copy
+			if err := t.Inventory.AddItem(&received); err != nil {
+				return nil, err
+			}
+		}
+		t.UpdatedAt = shared.NewTimestamp()
+		return t, nil
+	})
+	if err != nil {
+		s.returnItems(ctx, userID, result.Items)
+		return nil, err
+	}
+
+	publishInventoryChanged(ctx, s.eventBus, s.logger, userID, result)
+
+	s.logger.WithContext(ctx).Info("Items given",
+		zap.String("userID", userID.String()),
+		zap.String("recipientID", recipientID.String()),
+		zap.Int("stacks", result.StacksAffected))
+
+	return result, nil
+}
+
+// returnItems puts back items a gift couldn't deliver
+func (s *InventoryService) returnItems(ctx context.Context, userID trainer.UserID, items []*trainer.Item) {
+	err := s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		for _, item := range items {
+			if err := t.Inventory.AddItem(item); err != nil {
+				return nil, err
+			}
+		}
+		return t, nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to return undelivered items",
+			zap.String("userID", userID.String()),
+			zap.Int("stacks", len(items)),
+			zap.Error(err))
+	}
+}
+
+// publishInventoryChanged tells the trainer's clients which items an action removed
+func publishInventoryChanged(ctx context.Context, eventBus *cqrs.EventBus, logger *logger.Logger, userID trainer.UserID, result *trainer.BulkResult) {
+	event := &cqrscommands.InventoryChangedEvent{
+		UserID:    userID.String(),
+		Action:    result.Action,
+		Items:     result.Items,
+		UsedSlots: result.UsedSlots,
+		Timestamp: time.Now(),
+		RequestID: requestid.FromContextOrNew(ctx),
+	}
+	if err := eventBus.Publish(ctx, event); err != nil {
+		logger.Error("Failed to publish inventory changed event",
+			zap.String("userID", userID.String()),
+			zap.String("action", result.Action),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/vault"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// memoryTrainers serves trainers from a map. Like the Redis repository it returns nil
+// without an error for unknown trainers. Methods the tests don't use panic.
+type memoryTrainers struct {
+	trainer.Repository
+	trainers map[trainer.UserID]*trainer.Trainer
+}
+
+func (m *memoryTrainers) GetByID(ctx context.Context, id trainer.UserID) (*trainer.Trainer, error) {
+	return m.trainers[id], nil
+}
+
+func (m *memoryTrainers) FindOneAndUpdate(ctx context.Context, id trainer.UserID, callback func(*trainer.Trainer) (*trainer.Trainer, error)) error {
+	t, ok := m.trainers[id]
+	if !ok {
+		return shared.ErrNotFound("Trainer")
+	}
+	updated, err := callback(t)
+	if err != nil || updated == nil {
+		return err
+	}
+	m.trainers[id] = updated
+	return nil
+}
+
+func TestInventoryService_Query(t *testing.T) {
+	tr, err := trainer.NewTrainer("carrier", "Carrier")
+	require.NoError(t, err)
+	trainers := &memoryTrainers{trainers: map[trainer.UserID]*trainer.Trainer{tr.ID: tr}}
+	s := NewInventoryService(logger.NewDefault(), trainers, nil, nil, nil, nil)
+	ctx := context.Background()
+
+	page, err := s.Query(ctx, tr.ID, InventorySourceCarried, trainer.InventoryQuery{})
+	require.NoError(t, err)
+	assert.Equal(t, len(tr.Inventory.GetAllItems()), page.Total)
+
+	_, err = s.Query(ctx, "missing", InventorySourceCarried, trainer.InventoryQuery{})
+	code, ok := shared.DomainErrorCode(err)
+	require.True(t, ok, "unknown trainers are reported, not dereferenced")
+	assert.Equal(t, shared.ErrCodeNotFound, code)
+}
+
+// memoryVaults keeps one vault per user next to the trainers of a memoryTrainers
+type memoryVaults struct {
+	vault.Repository
+	trainers *memoryTrainers
+	vaults   map[trainer.UserID]*vault.Vault
+}
+
+func (m *memoryVaults) Transfer(ctx context.Context, userID trainer.UserID, callback func(*trainer.Trainer, *vault.Vault) error) error {
+	t := m.trainers.trainers[userID]
+	if t == nil {
+		return shared.ErrNotFound("Trainer")
+	}
+	v, ok := m.vaults[userID]
+	if !ok {
+		v = vault.NewVault(userID)
+		m.vaults[userID] = v
+	}
+	return callback(t, v)
+}
+
+// newBindingTest gives a trainer standing at a vault one free and one bound stack
+// memoryEmails is an account.EmailRepository holding emails in a map
+type memoryEmails struct {
+	account.EmailRepository
+	emails map[account.UserID]*account.Email
+}
+
+func (m *memoryEmails) GetByUserID(ctx context.Context, userID account.UserID) (*account.Email, error) {
+	return m.emails[userID], nil
+}
+
+// verifiedEmails returns an email service where only the given users have verified addresses
+func verifiedEmails(userIDs ...string) *EmailService {
+	emails := &memoryEmails{emails: map[account.UserID]*account.Email{}}
+	for _, userID := range userIDs {
+		verifiedAt := shared.NewTimestamp()
+		emails.emails[account.UserID(userID)] = &account.Email{UserID: account.UserID(userID), Address: userID + "@example.com", VerifiedAt: &verifiedAt}
+	}
+	return NewEmailService(logger.NewDefault(), emails, nil, "", nil)
+}
+
+func newBindingTest(t *testing.T) (*memoryTrainers, *trainer.Trainer, *trainer.Item, *trainer.Item) {
+	t.Helper()
+
+	tr, err := trainer.NewTrainer("giver", "Giver")
+	require.NoError(t, err)
+	tr.Movement.StopMovement(vault.DefaultLocations()[0].Position)
+
+	gem, err := trainer.NewItem(trainer.RareGem, "Rare Gem")
+	require.NoError(t, err)
+	require.NoError(t, tr.Inventory.AddItem(gem))
+	crystal, err := trainer.NewItem(trainer.MagicCrystal, "Magic Crystal")
+	require.NoError(t, err)
+	require.NoError(t, tr.Inventory.AddItem(crystal)) // Binds on pickup
+
+	recipient, err := trainer.NewTrainer("recipient", "Recipient")
+	require.NoError(t, err)
+
+	trainers := &memoryTrainers{trainers: map[trainer.UserID]*trainer.Trainer{tr.ID: tr, recipient.ID: recipient}}
+	return trainers, tr, gem, crystal
+}
+
+func TestInventoryService_BoundItems(t *testing.T) {
+	transfers := map[string]func(s *InventoryService, vaults *VaultService, tr *trainer.Trainer, items []trainer.ItemID) error{
+		"give": func(s *InventoryService, vaults *VaultService, tr *trainer.Trainer, items []trainer.ItemID) error {
+			_, err := s.Give(context.Background(), tr.ID, "recipient", items)
+			return err
+		},
+		"move to vault": func(s *InventoryService, vaults *VaultService, tr *trainer.Trainer, items []trainer.ItemID) error {
+			_, err := s.MoveToVault(context.Background(), tr.ID, items)
+			return err
+		},
+		"vault deposit": func(s *InventoryService, vaults *VaultService, tr *trainer.Trainer, items []trainer.ItemID) error {
+			_, err := vaults.Deposit(context.Background(), tr.ID, items)
+			return err
+		},
+		"drop": func(s *InventoryService, vaults *VaultService, tr *trainer.Trainer, items []trainer.ItemID) error {
+			drops := make([]trainer.ItemDrop, len(items))
+			for i, id := range items {
+				drops[i] = trainer.ItemDrop{ItemID: id}
+			}
+			_, err := s.Drop(context.Background(), tr.ID, drops)
+			return err
+		},
+	}
+
+	for name, transfer := range transfers {
+		t.Run(name, func(t *testing.T) {
+			trainers, tr, gem, crystal := newBindingTest(t)
+			vaultRepo := &memoryVaults{trainers: trainers, vaults: map[trainer.UserID]*vault.Vault{}}
+			vaults := NewVaultService(logger.NewDefault(), vault.DefaultLocations(), vaultRepo, trainers, nil)
+			s := NewInventoryService(logger.NewDefault(), trainers, vaultRepo, vaults, verifiedEmails("giver"), newTestEventBus(t))
+
+			err := transfer(s, vaults, tr, []trainer.ItemID{gem.ID, crystal.ID})
+			code, ok := shared.DomainErrorCode(err)
+			require.True(t, ok, "expected a domain error, got %v", err)
+			assert.Equal(t, shared.ErrCodeItemBound, code)
+			assert.Equal(t, 2, tr.Inventory.GetUsedSlots(), "nothing moves when a bound item is selected")
+
+			require.NoError(t, transfer(s, vaults, tr, []trainer.ItemID{gem.ID}))
+			assert.Equal(t, 1, tr.Inventory.GetUsedSlots())
+			assert.Equal(t, 1, tr.Inventory.CountItemsByType(trainer.MagicCrystal))
+		})
+	}
+}
+
+func TestInventoryService_Give(t *testing.T) {
+	trainers, tr, gem, _ := newBindingTest(t)
+	s := NewInventoryService(logger.NewDefault(), trainers, nil, nil, verifiedEmails("giver"), newTestEventBus(t))
+	ctx := context.Background()
+
+	_, err := s.Give(ctx, tr.ID, tr.ID, []trainer.ItemID{gem.ID})
+	assert.Error(t, err, "items can't be given to yourself")
+	_, err = s.Give(ctx, tr.ID, "missing", []trainer.ItemID{gem.ID})
+	code, ok := shared.DomainErrorCode(err)
+	require.True(t, ok)
+	assert.Equal(t, shared.ErrCodeNotFound, code)
+
+	// A full recipient gets nothing and the giver keeps the items
+	recipient := trainers.trainers["recipient"]
+	recipient.Inventory.MaxSlots = 0
+	_, err = s.Give(ctx, tr.ID, recipient.ID, []trainer.ItemID{gem.ID})
+	assert.Error(t, err)
+	assert.Equal(t, 1, tr.Inventory.CountItemsByType(trainer.RareGem), "undelivered items are returned")
+
+	recipient.Inventory.MaxSlots = 10
+	result, err := s.Give(ctx, tr.ID, recipient.ID, []trainer.ItemID{gem.ID})
+	require.NoError(t, err)
+	assert.Equal(t, BulkActionGive, result.Action)
+	assert.Equal(t, 0, tr.Inventory.CountItemsByType(trainer.RareGem))
+	assert.Equal(t, 1, recipient.Inventory.CountItemsByType(trainer.RareGem))
+}
+
+func TestInventoryService_Give_RequiresVerifiedEmail(t *testing.T) {
+	trainers, tr, gem, _ := newBindingTest(t)
+	ctx := context.Background()
+
+	for name, emails := range map[string]*EmailService{
+		"no email":   verifiedEmails(),
+		"unverified": NewEmailService(logger.NewDefault(), &memoryEmails{emails: map[account.UserID]*account.Email{"giver": {UserID: "giver", Address: "giver@example.com"}}}, nil, "", nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			s := NewInventoryService(logger.NewDefault(), trainers, nil, nil, emails, newTestEventBus(t))
+
+			_, err := s.Give(ctx, tr.ID, "recipient", []trainer.ItemID{gem.ID})
+			code, ok := shared.DomainErrorCode(err)
+			require.True(t, ok, "expected a domain error, got %v", err)
+			assert.Equal(t, shared.ErrCodeEmailNotVerified, code)
+			assert.Equal(t, 1, tr.Inventory.CountItemsByType(trainer.RareGem), "the giver keeps the items")
+		})
+	}
+}
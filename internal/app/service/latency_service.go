@@ -0,0 +1,106 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"github.com/danghamo/life/pkg/logger"
+)
+
+const (
+	// rttSmoothing is the weight of the estimate against a new sample, as TCP smooths its RTT
+	rttSmoothing = 8
+	// maxRTTSample is the longest round trip taken as a measurement; longer ones come from
+	// clients that were suspended or echoed a stale time
+	maxRTTSample = 10 * time.Second
+)
+
+// LatencyReport is what a client is told about its connection: the estimated round trip
+// of its requests, how long the server takes on them and how old the newest positions
+// broadcast is
+type LatencyReport struct {
+	RTTEstimateMillis      float64 `json:"rtt_estimate_ms"`
+	ServerProcessingMillis float64 `json:"server_processing_ms"`
+	BroadcastAgeMillis     float64 `json:"broadcast_age_ms"`
+}
+
+// LatencyService estimates each user's round trip to the server from the samples their moves
+// carry, and how long the server takes on moves. Estimates are kept per server instance,
+// where the user's requests are measured, and forgotten when the user disconnects.
+type LatencyService struct {
+	logger     *logger.Logger
+	movement   *TenantMovement
+	mutex      sync.Mutex
+	rtts       map[string]time.Duration // Smoothed round trip per user
+	processing time.Duration            // Smoothed time spent on a move
+}
+
+// NewLatencyService creates a new latency service reporting the broadcast age of movement
+func NewLatencyService(logger *logger.Logger, movement *TenantMovement) *LatencyService {
+	return &LatencyService{
+		logger:   logger.WithComponent("latency-service"),
+		movement: movement,
+		rtts:     make(map[string]time.Duration),
+	}
+}
+
+// Observe takes a measured round trip of a user's request into their estimate. Samples out
+// of range are ignored.
+func (s *LatencyService) Observe(userID string, rtt time.Duration) {
+	if rtt < 0 || rtt > maxRTTSample {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	estimate, ok := s.rtts[userID]
+	if !ok {
+		s.rtts[userID] = rtt
+		return
+	}
+	s.rtts[userID] = estimate + (rtt-estimate)/rttSmoothing
+}
+
+// Estimate returns a user's estimated round trip, 0 before their first sample
+func (s *LatencyService) Estimate(userID string) time.Duration {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.rtts[userID]
+}
+
+// Processed takes the time the server spent on a move into its estimate
+func (s *LatencyService) Processed(elapsed time.Duration) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.processing == 0 {
+		s.processing = elapsed
+		return
+	}
+	s.processing += (elapsed - s.processing) / rttSmoothing
+}
+
+// Report returns what a user's heartbeats tell about their connection. Connections don't
+// know their tenant, so the broadcast age is that of the simulation furthest behind.
+func (s *LatencyService) Report(userID string) LatencyReport {
+	s.mutex.Lock()
+	rtt, processing := s.rtts[userID], s.processing
+	s.mutex.Unlock()
+
+	return LatencyReport{
+		RTTEstimateMillis:      millis(rtt),
+		ServerProcessingMillis: millis(processing),
+		BroadcastAgeMillis:     millis(s.movement.MaxBroadcastAge()),
+	}
+}
+
+// Forget drops a user's estimate once they have no connection left
+func (s *LatencyService) Forget(userID string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.rtts, userID)
+}
+
+// millis returns a duration in milliseconds with a tenth of a millisecond precision
+func millis(d time.Duration) float64 {
+	return float64(d.Round(100*time.Microsecond)) / float64(time.Millisecond)
+}
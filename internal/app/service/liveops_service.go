@@ -0,0 +1,409 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/liveops"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/sandbox"
+)
+
+// LiveOpsService stores the event scripts admins upload and runs them in a sandbox, so
+// live-ops can grant items, spawn animals, message players and change spawn tables without
+// a deploy. Scripts are Starlark and only reach the game through the builtins below:
+//
+//	grant_item(nickname, item_type, name, quantity=1)
+//	spawn_animal(animal_type, x, y, level=1) -> animal ID
+//	notify(message, nicknames=None)            # every player when nicknames is None
+//	set_spawn_table(rate=0, min_level=0, max_level=0, weights=None, hours=24)
+//	reset_spawn_table()
+//
+// A dry run checks every call the same way but changes nothing.
+type LiveOpsService struct {
+	logger       *logger.Logger
+	scriptRepo   liveops.Repository
+	trainerRepo  trainer.Repository
+	tableRepo    animal.SpawnTableRepository
+	spawnManager *SpawnManager
+	push         *cqrscommands.SSEBroadcastHelper
+	limits       sandbox.Limits
+}
+
+// NewLiveOpsService creates a new live-ops service spawning animals through spawnManager
+func NewLiveOpsService(logger *logger.Logger, scriptRepo liveops.Repository, trainerRepo trainer.Repository, tableRepo animal.SpawnTableRepository, spawnManager *SpawnManager, push *cqrscommands.SSEBroadcastHelper) *LiveOpsService {
+	return &LiveOpsService{
+		logger:       logger.WithComponent("liveops-service"),
+		scriptRepo:   scriptRepo,
+		trainerRepo:  trainerRepo,
+		tableRepo:    tableRepo,
+		spawnManager: spawnManager,
+		push:         push,
+		limits:       sandbox.DefaultLimits(),
+	}
+}
+
+// UploadScript stores a script, replacing the script with the same name. Scripts that don't
+// compile are rejected.
+func (s *LiveOpsService) UploadScript(ctx context.Context, adminID, name, source string) (*liveops.Script, error) {
+	uploaded, err := liveops.NewScript(name, source, adminID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sandbox.Check(s.fileName(name), source, s.builtins(nil)); err != nil {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeScriptInvalid, "Script does not compile: %v", err)
+	}
+
+	var stored *liveops.Script
+	err = s.scriptRepo.FindOneAndUpsert(ctx, name, func(current *liveops.Script) (*liveops.Script, error) {
+		if current == nil {
+			stored = uploaded
+			return uploaded, nil
+		}
+		current.Replace(uploaded)
+		stored = current
+		return current, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Live-ops script uploaded",
+		zap.String("adminID", adminID),
+		zap.String("script", name),
+		zap.Int("version", stored.Version))
+	return stored, nil
+}
+
+// ListScripts returns every stored script
+func (s *LiveOpsService) ListScripts(ctx context.Context) ([]*liveops.Script, error) {
+	return s.scriptRepo.List(ctx)
+}
+
+// DeleteScript removes a stored script
+func (s *LiveOpsService) DeleteScript(ctx context.Context, adminID, name string) error {
+	script, err := s.scriptRepo.GetByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if script == nil {
+		return shared.ErrNotFound("Script")
+	}
+
+	if err := s.scriptRepo.Delete(ctx, name); err != nil {
+		return err
+	}
+
+	s.logger.WithContext(ctx).Info("Live-ops script deleted",
+		zap.String("adminID", adminID),
+		zap.String("script", name))
+	return nil
+}
+
+// RunScript runs a stored script and records the run on it. A script that fails still
+// returns its run, holding the error and the changes made before it stopped.
+func (s *LiveOpsService) RunScript(ctx context.Context, adminID, name string, dryRun bool) (*liveops.Run, error) {
+	script, err := s.scriptRepo.GetByName(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if script == nil {
+		return nil, shared.ErrNotFound("Script")
+	}
+
+	run := script.NewRun(adminID, dryRun)
+	result, err := sandbox.Run(ctx, s.fileName(name), script.Source, s.builtins(run), s.limits)
+	run.Output = result.Output
+	run.Truncated = result.Truncated
+	if err != nil {
+		run.Error = err.Error()
+	}
+
+	// Recorded even when the script ran into the request deadline
+	err = s.scriptRepo.FindOneAndUpdate(context.WithoutCancel(ctx), name, func(current *liveops.Script) (*liveops.Script, error) {
+		current.LastRun = run
+		return current, nil
+	})
+	if err != nil {
+		// The run's changes are already made; only its record is missing
+		s.logger.WithContext(ctx).Error("Failed to record live-ops script run",
+			zap.String("script", name),
+			zap.Error(err))
+	}
+
+	s.logger.WithContext(ctx).Info("Live-ops script run",
+		zap.String("adminID", adminID),
+		zap.String("script", name),
+		zap.Int("version", script.Version),
+		zap.Bool("dryRun", dryRun),
+		zap.Int("effects", len(run.Effects)),
+		zap.String("error", run.Error))
+	return run, nil
+}
+
+// fileName returns the name a script runs under in error backtraces
+func (s *LiveOpsService) fileName(name string) string {
+	return name + ".star"
+}
+
+// builtins returns the functions scripts may call, recording their changes on run. Each
+// call checks the run's limit first and records its change once made. Compiling only needs
+// the names, so run may be nil then.
+func (s *LiveOpsService) builtins(run *liveops.Run) starlark.StringDict {
+	bind := func(name string, fn func(ctx context.Context, run *liveops.Run, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error)) *starlark.Builtin {
+		return starlark.NewBuiltin(name, func(thread *starlark.Thread, b *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			if err := run.CheckLimit(); err != nil {
+				return nil, err
+			}
+			return fn(sandbox.Context(thread), run, args, kwargs)
+		})
+	}
+
+	return starlark.StringDict{
+		"grant_item":        bind("grant_item", s.grantItem),
+		"spawn_animal":      bind("spawn_animal", s.spawnAnimal),
+		"notify":            bind("notify", s.notify),
+		"set_spawn_table":   bind("set_spawn_table", s.setSpawnTable),
+		"reset_spawn_table": bind("reset_spawn_table", s.resetSpawnTable),
+	}
+}
+
+// grantItem adds a stack of items to a player's inventory
+func (s *LiveOpsService) grantItem(ctx context.Context, run *liveops.Run, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var nickname, itemType, name string
+	quantity := 1
+	if err := starlark.UnpackArgs("grant_item", args, kwargs, "nickname", &nickname, "item_type", &itemType, "name", &name, "quantity?", &quantity); err != nil {
+		return nil, err
+	}
+	if quantity > liveops.MaxGrantQuantity {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidAmount, "Cannot grant more than %d items at once", liveops.MaxGrantQuantity)
+	}
+
+	recipient, err := s.findTrainer(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+	effect := fmt.Sprintf("granted %d x %s %q to %s", quantity, itemType, name, recipient.Nickname)
+	if run.DryRun {
+		if _, err := trainer.NewItemStack(trainer.ItemType(itemType), name, quantity); err != nil {
+			return nil, err
+		}
+		run.AddEffect(effect)
+		return starlark.None, nil
+	}
+
+	err = s.trainerRepo.FindOneAndUpdate(ctx, recipient.ID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		item, err := trainer.NewItemStack(trainer.ItemType(itemType), name, quantity)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.Inventory.AddItem(item); err != nil {
+			return nil, err
+		}
+
+		t.UpdatedAt = shared.NewTimestamp()
+		return t, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	run.AddEffect(effect)
+	return starlark.None, nil
+}
+
+// spawnAnimal places a wild animal on a walkable tile and returns its ID
+func (s *LiveOpsService) spawnAnimal(ctx context.Context, run *liveops.Run, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var animalType string
+	var xValue, yValue starlark.Value
+	level := 1
+	if err := starlark.UnpackArgs("spawn_animal", args, kwargs, "animal_type", &animalType, "x", &xValue, "y", &yValue, "level?", &level); err != nil {
+		return nil, err
+	}
+	x, err := floatOf("spawn_animal", "x", xValue)
+	if err != nil {
+		return nil, err
+	}
+	y, err := floatOf("spawn_animal", "y", yValue)
+	if err != nil {
+		return nil, err
+	}
+
+	position := shared.NewPosition(x, y)
+	effect := fmt.Sprintf("spawned level %d %s at (%g, %g)", level, animalType, x, y)
+	if run.DryRun {
+		if err := s.spawnManager.CheckSpawn(animal.AnimalType(animalType), level, position); err != nil {
+			return nil, err
+		}
+		run.AddEffect(effect)
+		return starlark.String(""), nil
+	}
+
+	wild, err := s.spawnManager.SpawnAt(ctx, animal.AnimalType(animalType), level, position)
+	if err != nil {
+		return nil, err
+	}
+	run.AddEffect(effect)
+	return starlark.String(wild.ID.String()), nil
+}
+
+// notify sends a notice to the named players, or to every player
+func (s *LiveOpsService) notify(ctx context.Context, run *liveops.Run, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var message string
+	var nicknames starlark.Value = starlark.None
+	if err := starlark.UnpackArgs("notify", args, kwargs, "message", &message, "nicknames?", &nicknames); err != nil {
+		return nil, err
+	}
+	if message == "" || len([]rune(message)) > liveops.MaxNoticeLength {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Notice must be between 1 and %d characters", liveops.MaxNoticeLength)
+	}
+
+	var userIDs []string
+	audience := "every player"
+	if nicknames != starlark.None {
+		names, err := stringsOf("notify", "nicknames", nicknames)
+		if err != nil {
+			return nil, err
+		}
+		for _, nickname := range names {
+			recipient, err := s.findTrainer(ctx, nickname)
+			if err != nil {
+				return nil, err
+			}
+			userIDs = append(userIDs, recipient.ID.String())
+		}
+		audience = fmt.Sprintf("%d players", len(userIDs))
+	}
+
+	effect := fmt.Sprintf("notified %s: %q", audience, message)
+	if run.DryRun {
+		run.AddEffect(effect)
+		return starlark.None, nil
+	}
+
+	params := map[string]any{
+		"message": message,
+		"script":  run.Script,
+	}
+	var err error
+	if userIDs == nil {
+		err = s.push.BroadcastToAll(ctx, "liveops.notice", params)
+	} else if len(userIDs) > 0 {
+		err = s.push.BroadcastToUsers(ctx, userIDs, "liveops.notice", params)
+	}
+	if err != nil {
+		return nil, err
+	}
+	run.AddEffect(effect)
+	return starlark.None, nil
+}
+
+// setSpawnTable overrides the spawn settings for a number of hours
+func (s *LiveOpsService) setSpawnTable(ctx context.Context, run *liveops.Run, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var rateValue, hoursValue starlark.Value = starlark.MakeInt(0), starlark.MakeInt(24)
+	var minLevel, maxLevel int
+	var weights *starlark.Dict
+	if err := starlark.UnpackArgs("set_spawn_table", args, kwargs, "rate?", &rateValue, "min_level?", &minLevel, "max_level?", &maxLevel, "weights?", &weights, "hours?", &hoursValue); err != nil {
+		return nil, err
+	}
+	rate, err := floatOf("set_spawn_table", "rate", rateValue)
+	if err != nil {
+		return nil, err
+	}
+	hours, err := floatOf("set_spawn_table", "hours", hoursValue)
+	if err != nil {
+		return nil, err
+	}
+
+	var typeWeights map[animal.AnimalType]int
+	if weights != nil {
+		typeWeights = make(map[animal.AnimalType]int, weights.Len())
+		for _, item := range weights.Items() {
+			animalType, ok := starlark.AsString(item[0])
+			if !ok {
+				return nil, fmt.Errorf("set_spawn_table: weights keys must be animal types, got %s", item[0].Type())
+			}
+			weight, err := starlark.AsInt32(item[1])
+			if err != nil {
+				return nil, fmt.Errorf("set_spawn_table: weight of %s: %w", animalType, err)
+			}
+			typeWeights[animal.AnimalType(animalType)] = weight
+		}
+	}
+
+	table, err := animal.NewSpawnTable(rate, minLevel, maxLevel, typeWeights, run.RunBy, time.Duration(hours*float64(time.Hour)))
+	if err != nil {
+		return nil, err
+	}
+	if !run.DryRun {
+		if err := s.tableRepo.Set(ctx, table); err != nil {
+			return nil, err
+		}
+	}
+	run.AddEffect(fmt.Sprintf("set spawn table until %s", table.ExpiresAt.UTC().Format(time.RFC3339)))
+	return starlark.None, nil
+}
+
+// resetSpawnTable removes the spawn table override
+func (s *LiveOpsService) resetSpawnTable(ctx context.Context, run *liveops.Run, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := starlark.UnpackArgs("reset_spawn_table", args, kwargs); err != nil {
+		return nil, err
+	}
+	if !run.DryRun {
+		if err := s.tableRepo.Clear(ctx); err != nil {
+			return nil, err
+		}
+	}
+	run.AddEffect("reset spawn table")
+	return starlark.None, nil
+}
+
+// findTrainer looks up a trainer by nickname
+func (s *LiveOpsService) findTrainer(ctx context.Context, nickname string) (*trainer.Trainer, error) {
+	t, err := s.trainerRepo.FindByNickname(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeNotFound, "Trainer %q not found", nickname)
+	}
+	return t, nil
+}
+
+// floatOf converts a Starlark int or float argument
+func floatOf(fn, param string, value starlark.Value) (float64, error) {
+	f, ok := starlark.AsFloat(value)
+	if !ok {
+		return 0, fmt.Errorf("%s: for parameter %s: got %s, want number", fn, param, value.Type())
+	}
+	return f, nil
+}
+
+// stringsOf collects the strings of a Starlark list or tuple argument
+func stringsOf(fn, param string, values starlark.Value) ([]string, error) {
+	iterable, ok := values.(starlark.Iterable)
+	if !ok {
+		return nil, fmt.Errorf("%s: for parameter %s: got %s, want list", fn, param, values.Type())
+	}
+	iter := iterable.Iterate()
+	defer iter.Done()
+
+	var strs []string
+	var value starlark.Value
+	for iter.Next(&value) {
+		str, ok := starlark.AsString(value)
+		if !ok {
+			return nil, fmt.Errorf("%s: %s must hold strings, got %s", fn, param, value.Type())
+		}
+		strs = append(strs, str)
+	}
+	return strs, nil
+}
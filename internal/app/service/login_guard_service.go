@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/mailer"
+)
+
+// LoginGuardService flags logins from new devices or locations and holds them until the user
+// enters a code sent to their verified email. Users with a second factor enter a code from
+// their authenticator app on every login instead. The user's signed-in sessions are notified
+// over SSE and the verified address by mail.
+type LoginGuardService struct {
+	logger      *logger.Logger
+	accountRepo account.Repository
+	loginRepo   account.LoginRepository
+	emailRepo   account.EmailRepository
+	mailer      mailer.Mailer
+	push        *cqrscommands.SSEBroadcastHelper
+	activity    *ActivityService
+}
+
+// NewLoginGuardService creates a new login guard service
+func NewLoginGuardService(logger *logger.Logger, accountRepo account.Repository, loginRepo account.LoginRepository, emailRepo account.EmailRepository, mailer mailer.Mailer, push *cqrscommands.SSEBroadcastHelper, activity *ActivityService) *LoginGuardService {
+	return &LoginGuardService{
+		logger:      logger.WithComponent("login-guard"),
+		accountRepo: accountRepo,
+		loginRepo:   loginRepo,
+		emailRepo:   emailRepo,
+		mailer:      mailer,
+		push:        push,
+		activity:    activity,
+	}
+}
+
+// Check assesses a login. It returns nil when the login may proceed, or the step-up the
+// client must complete through Verify. Suspicious logins of users without a verified email
+// can't be challenged; they proceed and are only reported.
+func (s *LoginGuardService) Check(ctx context.Context, acc *account.Account, fingerprint account.LoginFingerprint) (*account.LoginStepUp, error) {
+	history, err := s.loginRepo.GetHistory(ctx, acc.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	risk := history.Assess(fingerprint)
+
+	accounts, err := s.accountRepo.ListByUserID(ctx, acc.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if holder := account.FindMFA(accounts); holder != nil && holder.MFAEnabled() {
+		return s.challengeMFA(ctx, acc, fingerprint, risk)
+	}
+
+	if !risk.IsSuspicious() {
+		s.recordLogin(ctx, acc.UserID, acc.Provider, risk)
+		return nil, s.loginRepo.Remember(ctx, acc.UserID, fingerprint)
+	}
+
+	email, err := s.emailRepo.GetByUserID(ctx, acc.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !email.IsVerified() {
+		s.logger.WithContext(ctx).Warn("Suspicious login without a verified email to challenge",
+			zap.String("userID", acc.UserID.String()),
+			zap.Bool("newDevice", risk.NewDevice),
+			zap.Bool("newLocation", risk.NewLocation))
+		s.notifySessions(ctx, acc.UserID, "security.new_login", risk)
+		s.recordLogin(ctx, acc.UserID, acc.Provider, risk)
+		return nil, s.loginRepo.Remember(ctx, acc.UserID, fingerprint)
+	}
+
+	challenge, code, err := account.NewLoginChallenge(acc, fingerprint, risk)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loginRepo.SaveChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	s.send(ctx, mailer.Message{
+		To:      email.Address,
+		Subject: "Your sign-in code",
+		Body: fmt.Sprintf("Someone is signing in to your account from %s.\n\n"+
+			"If it's you, enter this code: %s\n\n"+
+			"The code expires in %d minutes. If it isn't you, ignore this email and change the password of your linked accounts.",
+			describeRisk(risk), code, int(account.LoginChallengeTTL.Minutes())),
+	})
+	s.notifySessions(ctx, acc.UserID, "security.login_challenge", risk)
+	challenged := account.NewActivity(account.ActivityLoginChallenged).WithRisk(risk)
+	challenged.Provider = acc.Provider
+	s.activity.Record(ctx, acc.UserID, challenged)
+
+	s.logger.WithContext(ctx).Info("Suspicious login challenged",
+		zap.String("userID", acc.UserID.String()),
+		zap.Bool("newDevice", risk.NewDevice),
+		zap.Bool("newLocation", risk.NewLocation))
+
+	return &account.LoginStepUp{
+		ChallengeID: challenge.ID,
+		Method:      challenge.Method,
+		Email:       email.MaskedAddress(),
+		Risk:        risk,
+		ExpiresIn:   int64(account.LoginChallengeTTL.Seconds()),
+	}, nil
+}
+
+// challengeMFA holds a login of a user with a second factor until they enter a code from
+// their authenticator app. Known devices are challenged too; nothing is emailed.
+func (s *LoginGuardService) challengeMFA(ctx context.Context, acc *account.Account, fingerprint account.LoginFingerprint, risk account.LoginRisk) (*account.LoginStepUp, error) {
+	challenge, err := account.NewMFAChallenge(acc, fingerprint, risk)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loginRepo.SaveChallenge(ctx, challenge); err != nil {
+		return nil, err
+	}
+
+	if risk.IsSuspicious() {
+		s.notifySessions(ctx, acc.UserID, "security.login_challenge", risk)
+	}
+	challenged := account.NewActivity(account.ActivityLoginChallenged).WithRisk(risk)
+	challenged.Provider = acc.Provider
+	s.activity.Record(ctx, acc.UserID, challenged)
+
+	return &account.LoginStepUp{
+		ChallengeID: challenge.ID,
+		Method:      challenge.Method,
+		Risk:        risk,
+		ExpiresIn:   int64(account.LoginChallengeTTL.Seconds()),
+	}, nil
+}
+
+// Verify completes a step-up with the emailed code, or the user's authenticator or backup
+// code, and returns the account to sign in. The device and network are remembered, so the
+// next login from them isn't challenged by email.
+func (s *LoginGuardService) Verify(ctx context.Context, challengeID, code string) (account.AccountID, error) {
+	now := time.Now()
+	var completed *account.LoginChallenge
+	var holder *account.Account
+	var attemptErr error
+	err := s.loginRepo.FindChallengeAndUpdate(ctx, challengeID, func(challenge *account.LoginChallenge) (*account.LoginChallenge, error) {
+		// Failed attempts are stored too, so they count against the limit
+		if challenge.Method == account.LoginMethodTOTP {
+			var err error
+			holder, err = s.mfaHolder(ctx, challenge.UserID)
+			if err != nil {
+				return nil, err
+			}
+			attemptErr = challenge.AttemptMFA(holder.MFA, code, now)
+		} else {
+			attemptErr = challenge.Attempt(code)
+		}
+		if attemptErr == nil {
+			completed = challenge
+		}
+		return challenge, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if attemptErr != nil {
+		return "", attemptErr
+	}
+
+	// The backup code or time step is consumed only once the attempt is saved, so a retried
+	// challenge update can't use it up. A code accepted by a concurrent login is refused here.
+	if holder != nil {
+		err := s.accountRepo.FindOneAndUpdate(ctx, holder.ID, func(a *account.Account) (*account.Account, error) {
+			if !a.MFA.Verify(code, now) {
+				return nil, shared.NewDomainError(shared.ErrCodeInvalidLoginCode, "Login code was already used")
+			}
+			return a, nil
+		})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	if err := s.loginRepo.DeleteChallenge(ctx, challengeID); err != nil {
+		return "", err
+	}
+
+	if err := s.loginRepo.Remember(ctx, completed.UserID, completed.Fingerprint); err != nil {
+		return "", err
+	}
+
+	if email, err := s.emailRepo.GetByUserID(ctx, completed.UserID); err == nil && email.IsVerified() {
+		s.send(ctx, mailer.Message{
+			To:      email.Address,
+			Subject: "New sign-in to your account",
+			Body: fmt.Sprintf("Your account was signed in from %s at %s.\n\n"+
+				"If this wasn't you, change the password of your linked accounts.",
+				describeRisk(completed.Risk), time.Now().UTC().Format(time.RFC1123)),
+		})
+	}
+	s.notifySessions(ctx, completed.UserID, "security.new_login", completed.Risk)
+	s.recordLogin(ctx, completed.UserID, "", completed.Risk)
+
+	return completed.AccountID, nil
+}
+
+// mfaHolder returns the account holding a user's second factor
+func (s *LoginGuardService) mfaHolder(ctx context.Context, userID account.UserID) (*account.Account, error) {
+	accounts, err := s.accountRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	holder := account.FindMFA(accounts)
+	if holder == nil {
+		return nil, shared.ErrNotFound("second factor")
+	}
+	return holder, nil
+}
+
+// Trust remembers a login that was authorized another way, such as a pairing code
+func (s *LoginGuardService) Trust(ctx context.Context, userID account.UserID, fingerprint account.LoginFingerprint) {
+	s.activity.Record(ctx, userID, account.NewActivity(account.ActivityDevicePaired))
+
+	if err := s.loginRepo.Remember(ctx, userID, fingerprint); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to remember trusted login",
+			zap.String("userID", userID.String()),
+			zap.Error(err))
+	}
+}
+
+// recordLogin adds a completed login to the user's activity timeline
+func (s *LoginGuardService) recordLogin(ctx context.Context, userID account.UserID, provider account.Provider, risk account.LoginRisk) {
+	activity := account.NewActivity(account.ActivityLogin).WithRisk(risk)
+	activity.Provider = provider
+	s.activity.Record(ctx, userID, activity)
+}
+
+// notifySessions pushes a security notice to the user's connected clients
+func (s *LoginGuardService) notifySessions(ctx context.Context, userID account.UserID, method string, risk account.LoginRisk) {
+	params := map[string]interface{}{
+		"new_device":   risk.NewDevice,
+		"new_location": risk.NewLocation,
+		"timestamp":    time.Now().Format(time.RFC3339),
+	}
+
+	if err := s.push.BroadcastToUsers(ctx, []string{userID.String()}, method, params); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to push login notification",
+			zap.String("userID", userID.String()),
+			zap.Error(err))
+	}
+}
+
+// send delivers a message, logging failures
+func (s *LoginGuardService) send(ctx context.Context, msg mailer.Message) {
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to send email",
+			zap.String("subject", msg.Subject),
+			zap.Error(err))
+	}
+}
+
+// describeRisk phrases a login risk for emails
+func describeRisk(risk account.LoginRisk) string {
+	switch {
+	case risk.NewDevice && risk.NewLocation:
+		return "a new device and location"
+	case risk.NewDevice:
+		return "a new device"
+	case risk.NewLocation:
+		return "a new location"
+	default:
+		return "a known device"
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// memoryAccounts serves accounts from a map
+type memoryAccounts struct {
+	account.Repository
+	accounts map[account.AccountID]*account.Account
+	updates  int
+}
+
+func (m *memoryAccounts) ListByUserID(ctx context.Context, userID account.UserID) ([]*account.Account, error) {
+	var accounts []*account.Account
+	for _, acc := range m.accounts {
+		if acc.UserID == userID {
+			accounts = append(accounts, acc)
+		}
+	}
+	return accounts, nil
+}
+
+func (m *memoryAccounts) FindOneAndUpdate(ctx context.Context, id account.AccountID, callback func(*account.Account) (*account.Account, error)) error {
+	current := *m.accounts[id]
+	updated, err := callback(&current)
+	if err != nil {
+		return err
+	}
+	m.updates++
+	m.accounts[id] = updated
+	return nil
+}
+
+// retryingLogins runs every challenge update twice, throwing the first result away the way a
+// WATCH conflict makes the Redis repository run it again
+type retryingLogins struct {
+	account.LoginRepository
+	challenges map[string]*account.LoginChallenge
+}
+
+func (m *retryingLogins) FindChallengeAndUpdate(ctx context.Context, id string, callback func(*account.LoginChallenge) (*account.LoginChallenge, error)) error {
+	var result *account.LoginChallenge
+	for range 2 {
+		current := *m.challenges[id]
+		var err error
+		if result, err = callback(&current); err != nil {
+			return err
+		}
+	}
+	m.challenges[id] = result
+	return nil
+}
+
+func (m *retryingLogins) DeleteChallenge(ctx context.Context, id string) error {
+	delete(m.challenges, id)
+	return nil
+}
+
+func (m *retryingLogins) Remember(ctx context.Context, userID account.UserID, fingerprint account.LoginFingerprint) error {
+	return nil
+}
+
+// discardActivities is an account.ActivityRepository that records nothing
+type discardActivities struct {
+	account.ActivityRepository
+}
+
+func (discardActivities) Record(ctx context.Context, userID account.UserID, activity *account.Activity) error {
+	return nil
+}
+
+func TestLoginGuardService_Verify_BackupCode(t *testing.T) {
+	acc, err := account.NewGuestAccount("device-1")
+	require.NoError(t, err)
+	enrollment, err := acc.EnrollMFA(acc.UserID.String())
+	require.NoError(t, err)
+	enabledAt := shared.NewTimestamp()
+	acc.MFA.EnabledAt = &enabledAt
+
+	accounts := &memoryAccounts{accounts: map[account.AccountID]*account.Account{acc.ID: acc}}
+	logins := &retryingLogins{challenges: map[string]*account.LoginChallenge{}}
+	s := NewLoginGuardService(logger.NewDefault(), accounts, logins, &memoryEmails{}, nil,
+		cqrscommands.NewSSEBroadcastHelper(newTestEventBus(t)), NewActivityService(logger.NewDefault(), discardActivities{}))
+	ctx := context.Background()
+
+	challenge := func() string {
+		challenge, err := account.NewMFAChallenge(acc, account.NewLoginFingerprint("device-2", ""), account.LoginRisk{NewDevice: true})
+		require.NoError(t, err)
+		logins.challenges[challenge.ID] = challenge
+		return challenge.ID
+	}
+
+	accountID, err := s.Verify(ctx, challenge(), enrollment.BackupCodes[0])
+	require.NoError(t, err, "a retried challenge update doesn't use up the code")
+	assert.Equal(t, acc.ID, accountID)
+	assert.Len(t, accounts.accounts[acc.ID].MFA.BackupCodes, account.BackupCodeCount-1)
+	assert.Equal(t, 1, accounts.updates, "the account is written once")
+
+	_, err = s.Verify(ctx, challenge(), enrollment.BackupCodes[0])
+	code, ok := shared.DomainErrorCode(err)
+	require.True(t, ok, "expected a domain error, got %v", err)
+	assert.Equal(t, shared.ErrCodeInvalidLoginCode, code, "backup codes are single use")
+	assert.Len(t, accounts.accounts[acc.ID].MFA.BackupCodes, account.BackupCodeCount-1)
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/fairness"
+	"github.com/danghamo/life/internal/domain/loot"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+const (
+	// How long only the trainer who defeated the animal may collect its pickups
+	pickupOwnerExclusive = 30 * time.Second
+	// How long a pickup stays in the world before despawning
+	pickupTTL = 5 * time.Minute
+)
+
+// LootResult describes what a defeat produced
+type LootResult struct {
+	Mode    loot.DeliveryMode `json:"mode"`
+	Drops   []loot.Drop       `json:"drops"`
+	Pickups []*loot.Pickup    `json:"pickups,omitempty"`
+}
+
+// LootService rolls loot tables for defeated animals and delivers the drops
+type LootService struct {
+	logger      *logger.Logger
+	registry    *loot.Registry
+	mode        loot.DeliveryMode
+	trainerRepo trainer.Repository
+	pickupRepo  loot.Repository
+	ledger      loot.LedgerRepository // Nil keeps the event stream as the only ledger
+	eventBus    *cqrs.EventBus
+	randomness  *RandomnessService
+}
+
+// NewLootService creates a new loot service
+func NewLootService(
+	logger *logger.Logger,
+	registry *loot.Registry,
+	mode loot.DeliveryMode,
+	trainerRepo trainer.Repository,
+	pickupRepo loot.Repository,
+	ledger loot.LedgerRepository,
+	eventBus *cqrs.EventBus,
+	randomness *RandomnessService,
+) *LootService {
+	if !mode.IsValid() {
+		mode = loot.DeliverToInventory
+	}
+
+	return &LootService{
+		logger:      logger.WithComponent("loot-service"),
+		registry:    registry,
+		mode:        mode,
+		trainerRepo: trainerRepo,
+		pickupRepo:  pickupRepo,
+		ledger:      ledger,
+		eventBus:    eventBus,
+		randomness:  randomness,
+	}
+}
+
+// modeFor returns the delivery mode of the context's tenant, which may override the server's
+func (s *LootService) modeFor(ctx context.Context) loot.DeliveryMode {
+	if t, ok := tenant.FromContext(ctx); ok {
+		if mode := loot.DeliveryMode(t.Balance.LootDeliveryMode); mode.IsValid() {
+			return mode
+		}
+	}
+	return s.mode
+}
+
+// HandleAnimalDefeated rolls loot for a defeated animal and delivers it to the trainer
+func (s *LootService) HandleAnimalDefeated(ctx context.Context, trainerID trainer.UserID, defeated *animal.Animal) (*LootResult, error) {
+	session, err := s.randomness.Begin(ctx, fairness.PurposeLootDrop, trainerID.String(), defeated.ID.String(), map[string]any{
+		"animal_id":   defeated.ID.String(),
+		"animal_type": defeated.AnimalType.String(),
+		"level":       defeated.Level.Value(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	drops, err := s.registry.Roll(defeated, session.Rand())
+	if err != nil {
+		return nil, err
+	}
+	s.randomness.Record(ctx, session, drops)
+
+	mode := s.modeFor(ctx)
+	result := &LootResult{
+		Mode:  mode,
+		Drops: drops,
+	}
+
+	if len(drops) == 0 {
+		return result, nil
+	}
+
+	switch mode {
+	case loot.DeliverAsPickup:
+		pickups, err := s.placePickups(ctx, trainerID, defeated, drops)
+		if err != nil {
+			return nil, err
+		}
+		result.Pickups = pickups
+	default:
+		if err := s.grantDrops(ctx, trainerID, drops); err != nil {
+			return nil, err
+		}
+	}
+
+	s.recordDrop(ctx, trainerID, defeated, result)
+
+	return result, nil
+}
+
+// ClaimPickup collects a world pickup into the trainer's inventory
+func (s *LootService) ClaimPickup(ctx context.Context, trainerID trainer.UserID, pickupID loot.PickupID) (*loot.Pickup, error) {
+	var claimed *loot.Pickup
+
+	err := s.pickupRepo.FindOneAndDelete(ctx, pickupID, func(p *loot.Pickup) error {
+		if err := p.CanBeClaimedBy(trainerID, time.Now()); err != nil {
+			return err
+		}
+
+		claimed = p
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The pickup is removed first so two trainers can never both collect it;
+	// if the inventory update fails the pickup is put back into the world
+	if err := s.grantDrops(ctx, trainerID, []loot.Drop{claimed.Drop}); err != nil {
+		restoreErr := s.pickupRepo.FindOneAndInsert(ctx, claimed.ID, func() (*loot.Pickup, error) {
+			return claimed, nil
+		})
+		if restoreErr != nil {
+			s.logger.WithContext(ctx).Error("Failed to restore pickup after claim failure",
+				zap.String("pickupID", claimed.ID.String()),
+				zap.Error(restoreErr))
+		}
+		return nil, err
+	}
+
+	event := &cqrscommands.LootPickedUpEvent{
+		UserID:    trainerID.String(),
+		PickupID:  pickupID.String(),
+		Drop:      claimed.Drop,
+		Timestamp: time.Now(),
+		RequestID: requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish loot picked up event",
+			zap.String("userID", trainerID.String()),
+			zap.String("pickupID", pickupID.String()),
+			zap.Error(err))
+	}
+
+	return claimed, nil
+}
+
+// GetNearbyPickups returns pickups around a position
+func (s *LootService) GetNearbyPickups(ctx context.Context, center shared.Position, radius float64) ([]*loot.Pickup, error) {
+	return s.pickupRepo.GetNearby(ctx, center, radius)
+}
+
+// grantDrops adds drops to the trainer inventory in a single atomic update
+func (s *LootService) grantDrops(ctx context.Context, trainerID trainer.UserID, drops []loot.Drop) error {
+	return s.trainerRepo.FindOneAndUpdate(ctx, trainerID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		for _, drop := range drops {
+			item, err := trainer.NewItemStack(drop.ItemType, drop.Name, drop.Quantity)
+			if err != nil {
+				return nil, err
+			}
+			if err := t.Inventory.AddItem(item); err != nil {
+				return nil, err
+			}
+		}
+
+		t.UpdatedAt = shared.NewTimestamp()
+		return t, nil
+	})
+}
+
+// placePickups stores one world pickup per drop at the animal's position
+func (s *LootService) placePickups(ctx context.Context, trainerID trainer.UserID, defeated *animal.Animal, drops []loot.Drop) ([]*loot.Pickup, error) {
+	pickups := make([]*loot.Pickup, 0, len(drops))
+
+	for _, drop := range drops {
+		pickup := loot.NewPickup(drop, defeated, trainerID, pickupOwnerExclusive, pickupTTL)
+
+		err := s.pickupRepo.FindOneAndInsert(ctx, pickup.ID, func() (*loot.Pickup, error) {
+			return pickup, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		pickups = append(pickups, pickup)
+	}
+
+	return pickups, nil
+}
+
+// recordDrop publishes the drop to the event stream, which doubles as the loot ledger, and
+// records it in the durable ledger if there is one
+func (s *LootService) recordDrop(ctx context.Context, trainerID trainer.UserID, defeated *animal.Animal, result *LootResult) {
+	pickupIDs := make([]string, 0, len(result.Pickups))
+	for _, pickup := range result.Pickups {
+		pickupIDs = append(pickupIDs, pickup.ID.String())
+	}
+
+	event := &cqrscommands.LootDroppedEvent{
+		UserID:     trainerID.String(),
+		AnimalID:   defeated.ID.String(),
+		AnimalType: defeated.AnimalType.String(),
+		Level:      defeated.Level.Value(),
+		Mode:       result.Mode.String(),
+		Drops:      result.Drops,
+		PickupIDs:  pickupIDs,
+		Position:   defeated.Position,
+		Timestamp:  time.Now(),
+		RequestID:  requestid.FromContextOrNew(ctx),
+	}
+
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish loot dropped event",
+			zap.String("userID", trainerID.String()),
+			zap.String("animalID", defeated.ID.String()),
+			zap.Error(err))
+	}
+
+	if s.ledger == nil {
+		return
+	}
+
+	err := s.ledger.Record(ctx, &loot.LedgerEntry{
+		UserID:     event.UserID,
+		AnimalID:   event.AnimalID,
+		AnimalType: event.AnimalType,
+		Level:      event.Level,
+		Mode:       result.Mode,
+		Drops:      result.Drops,
+		PickupIDs:  pickupIDs,
+		At:         event.Timestamp,
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to record loot drop in ledger",
+			zap.String("userID", trainerID.String()),
+			zap.String("animalID", defeated.ID.String()),
+			zap.Error(err))
+	}
+}
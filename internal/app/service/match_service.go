@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/match"
+	"github.com/danghamo/life/internal/domain/room"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// TypeMatchEnd is the asynq task type that ends a match when its time is up
+const TypeMatchEnd = "match:end"
+
+// matchEndPayload is the asynq payload for TypeMatchEnd
+type matchEndPayload struct {
+	MatchID string `json:"match_id"`
+}
+
+// MatchService runs FPS matches between the members of a room. The room's owner starts a
+// match; kills are counted from bullet hit events and the match ends at its score limit or
+// when its time is up, adding the results to every player's history.
+type MatchService struct {
+	logger     *logger.Logger
+	matchRepo  match.Repository
+	roomRepo   room.Repository
+	taskClient *asynq.Client
+	eventBus   *cqrs.EventBus
+}
+
+// NewMatchService creates a new match service
+func NewMatchService(logger *logger.Logger, matchRepo match.Repository, roomRepo room.Repository, taskClient *asynq.Client, eventBus *cqrs.EventBus) *MatchService {
+	return &MatchService{
+		logger:     logger.WithComponent("match-service"),
+		matchRepo:  matchRepo,
+		roomRepo:   roomRepo,
+		taskClient: taskClient,
+		eventBus:   eventBus,
+	}
+}
+
+// Start starts a match between the members of the player's room. Only the room's owner can
+// start one, and only one runs in a room at a time.
+func (s *MatchService) Start(ctx context.Context, userID string, settings match.Settings) (*match.Match, error) {
+	current, err := s.memberRoom(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if current.OwnerID != userID {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidOperation, "Only the room owner can start a match")
+	}
+
+	running, err := s.matchRepo.RoomMatch(ctx, current.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if running != "" {
+		return nil, shared.NewDomainError(shared.ErrCodeMatchInProgress, "A match is already running in this room")
+	}
+
+	started, err := match.NewMatch(current.ID.String(), current.Members, settings, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.matchRepo.FindOneAndInsert(ctx, started.ID, func() (*match.Match, error) {
+		return started, nil
+	}); err != nil {
+		return nil, err
+	}
+	s.scheduleEnd(ctx, started)
+
+	s.publish(ctx, &cqrscommands.MatchStartedEvent{
+		MatchID:    started.ID,
+		RoomID:     started.RoomID,
+		Mode:       started.Settings.Mode,
+		ScoreLimit: started.Settings.ScoreLimit,
+		Players:    started.Players,
+		EndsAt:     started.EndsAt,
+		Timestamp:  started.StartedAt,
+		RequestID:  requestid.FromContextOrNew(ctx),
+	})
+
+	s.logger.WithContext(ctx).Info("Match started",
+		zap.String("matchID", started.ID),
+		zap.String("roomID", started.RoomID),
+		zap.String("mode", string(started.Settings.Mode)),
+		zap.Int("players", len(started.Players)))
+
+	return started, nil
+}
+
+// Scoreboard returns the match running in the player's room
+func (s *MatchService) Scoreboard(ctx context.Context, userID string) (*match.Match, error) {
+	current, err := s.memberRoom(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	matchID, err := s.matchRepo.RoomMatch(ctx, current.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	if matchID == "" {
+		return nil, shared.NewDomainError(shared.ErrCodeNoMatch, "No match is running in this room")
+	}
+
+	running, err := s.matchRepo.GetByID(ctx, matchID)
+	if err != nil {
+		return nil, err
+	}
+	if running == nil {
+		return nil, shared.NewDomainError(shared.ErrCodeNoMatch, "No match is running in this room")
+	}
+	return running, nil
+}
+
+// History returns the player's most recent matches, newest first
+func (s *MatchService) History(ctx context.Context, userID string, limit int) ([]match.HistoryEntry, error) {
+	return s.matchRepo.History(ctx, userID, limit)
+}
+
+// HandleBulletHitEvent counts a kill in a room toward the match running in it
+func (s *MatchService) HandleBulletHitEvent(ctx context.Context, event *cqrscommands.BulletHitEvent) error {
+	if !event.Killed || event.RoomID == "" {
+		return nil
+	}
+
+	matchID, err := s.matchRepo.RoomMatch(ctx, event.RoomID)
+	if err != nil || matchID == "" {
+		return err
+	}
+
+	var scored *match.Match
+	err = s.matchRepo.FindOneAndUpdate(ctx, matchID, func(m *match.Match) (*match.Match, error) {
+		scored = nil
+		if !m.RecordKill(event.BulletID, event.UserID, event.TargetID, event.HitAt) {
+			return nil, nil
+		}
+		scored = m
+		return m, nil
+	})
+	if err != nil || scored == nil {
+		return err
+	}
+
+	s.publish(ctx, &cqrscommands.MatchScoreChangedEvent{
+		MatchID:    scored.ID,
+		RoomID:     scored.RoomID,
+		KillerID:   event.UserID,
+		VictimID:   event.TargetID,
+		Players:    scored.Players,
+		TeamScores: scored.TeamScores(),
+		Timestamp:  time.Now(),
+		RequestID:  requestid.FromContextOrNew(ctx),
+	})
+	if !scored.IsRunning() {
+		s.announceEnd(ctx, scored)
+	}
+	return nil
+}
+
+// scheduleEnd enqueues the task ending the match when its time is up
+func (s *MatchService) scheduleEnd(ctx context.Context, m *match.Match) {
+	payload, err := json.Marshal(matchEndPayload{MatchID: m.ID})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to marshal match end payload", zap.Error(err))
+		return
+	}
+
+	task := asynq.NewTask(tenant.IDFromContext(ctx).Key(TypeMatchEnd), payload)
+	if _, err := s.taskClient.EnqueueContext(ctx, task,
+		asynq.ProcessIn(time.Until(m.EndsAt)),
+		asynq.TaskID(fmt.Sprintf("match-end:%s", m.ID)),
+		asynq.MaxRetry(5),
+	); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to schedule match end",
+			zap.String("matchID", m.ID),
+			zap.Error(err))
+	}
+}
+
+// HandleMatchEndTask processes TypeMatchEnd tasks. Matches that already ended at their score
+// limit, or expired since, are left alone.
+func (s *MatchService) HandleMatchEndTask(ctx context.Context, task *asynq.Task) error {
+	var payload matchEndPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("invalid match end payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	var ended *match.Match
+	err := s.matchRepo.FindOneAndUpdate(ctx, payload.MatchID, func(m *match.Match) (*match.Match, error) {
+		ended = nil
+		if !m.End(time.Now()) {
+			return nil, nil
+		}
+		ended = m
+		return m, nil
+	})
+	if code, ok := shared.DomainErrorCode(err); ok && code == shared.ErrCodeNotFound {
+		return nil
+	}
+	if err != nil || ended == nil {
+		return err
+	}
+
+	s.announceEnd(ctx, ended)
+	return nil
+}
+
+// announceEnd publishes the results of a match that just ended
+func (s *MatchService) announceEnd(ctx context.Context, m *match.Match) {
+	s.publish(ctx, &cqrscommands.MatchEndedEvent{
+		MatchID:   m.ID,
+		RoomID:    m.RoomID,
+		Mode:      m.Settings.Mode,
+		Result:    m.Result,
+		Timestamp: time.Now(),
+		RequestID: requestid.FromContextOrNew(ctx),
+	})
+
+	s.logger.WithContext(ctx).Info("Match ended",
+		zap.String("matchID", m.ID),
+		zap.String("roomID", m.RoomID),
+		zap.String("winnerID", m.Result.WinnerID),
+		zap.String("winnerTeam", string(m.Result.WinnerTeam)),
+		zap.Bool("draw", m.Result.Draw))
+}
+
+// memberRoom returns the room the player is in
+func (s *MatchService) memberRoom(ctx context.Context, userID string) (*room.Room, error) {
+	roomID, err := s.roomRepo.MemberRoom(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if roomID == "" {
+		return nil, shared.NewDomainError(shared.ErrCodeNotInRoom, "Not in a room")
+	}
+
+	current, err := s.roomRepo.GetByID(ctx, roomID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil || !current.HasMember(userID) {
+		return nil, shared.NewDomainError(shared.ErrCodeNotInRoom, "Not in a room")
+	}
+	return current, nil
+}
+
+// publish publishes a match event, logging failures
+func (s *MatchService) publish(ctx context.Context, event interface{}) {
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish match event", zap.Error(err))
+	}
+}
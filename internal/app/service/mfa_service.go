@@ -0,0 +1,91 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// MFAService sets up users' second factors. Once enabled, LoginGuardService asks for it on
+// every sign-in.
+type MFAService struct {
+	logger      *logger.Logger
+	accountRepo account.Repository
+	activity    *ActivityService
+}
+
+// NewMFAService creates a new MFA service
+func NewMFAService(logger *logger.Logger, accountRepo account.Repository, activity *ActivityService) *MFAService {
+	return &MFAService{
+		logger:      logger.WithComponent("mfa-service"),
+		accountRepo: accountRepo,
+		activity:    activity,
+	}
+}
+
+// Enroll starts setting up a second factor for the user, replacing an enrollment they didn't
+// confirm. It returns the secret and backup codes, which are never shown again.
+func (s *MFAService) Enroll(ctx context.Context, userID string) (*account.MFAEnrollment, error) {
+	accounts, err := s.accountRepo.ListByUserID(ctx, account.UserID(userID))
+	if err != nil {
+		return nil, err
+	}
+	holder := account.MFAHolder(accounts)
+	if holder == nil {
+		return nil, shared.ErrNotFound("account")
+	}
+
+	var enrollment *account.MFAEnrollment
+	err = s.accountRepo.FindOneAndUpdate(ctx, holder.ID, func(a *account.Account) (*account.Account, error) {
+		var err error
+		enrollment, err = a.EnrollMFA(mfaLabel(accounts, userID))
+		return a, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Two-factor authentication enrollment started", zap.String("userID", userID))
+
+	return enrollment, nil
+}
+
+// Verify enables the user's pending second factor with a code from their authenticator app
+func (s *MFAService) Verify(ctx context.Context, userID, code string) error {
+	accounts, err := s.accountRepo.ListByUserID(ctx, account.UserID(userID))
+	if err != nil {
+		return err
+	}
+	holder := account.FindMFA(accounts)
+	if holder == nil {
+		return shared.NewDomainError(shared.ErrCodeInvalidOperation, "Enroll in two-factor authentication first")
+	}
+
+	err = s.accountRepo.FindOneAndUpdate(ctx, holder.ID, func(a *account.Account) (*account.Account, error) {
+		return a, a.ConfirmMFA(code, time.Now())
+	})
+	if err != nil {
+		return err
+	}
+
+	s.activity.Record(ctx, account.UserID(userID), account.NewActivity(account.ActivityMFAEnabled))
+	s.logger.WithContext(ctx).Info("Two-factor authentication enabled", zap.String("userID", userID))
+
+	return nil
+}
+
+// mfaLabel names the user in their authenticator app: the email of a linked social account,
+// or the user ID for guests
+func mfaLabel(accounts []*account.Account, userID string) string {
+	for _, acc := range accounts {
+		if acc.Profile.Email != "" {
+			return acc.Profile.Email
+		}
+	}
+	return userID
+}
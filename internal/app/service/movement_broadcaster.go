@@ -2,8 +2,14 @@ package service
 
 import (
 	"context"
+	"expvar"
 	"fmt"
-	"strings"
+	"hash/fnv"
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ThreeDotsLabs/watermill/components/cqrs"
@@ -11,119 +17,430 @@ import (
 	"go.uber.org/zap"
 
 	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/accessibility"
+	"github.com/danghamo/life/internal/domain/shared"
 	"github.com/danghamo/life/internal/domain/trainer"
 	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/tenant"
 )
 
-// MovementBroadcaster handles periodic broadcasting of moving trainer positions using Redis
+// MovementConfig shapes the movement simulation
+type MovementConfig struct {
+	// Shards splits resident trainers so moves of different trainers rarely wait on each other
+	Shards int `json:"shards"`
+	// SnapshotTicks is how many broadcast ticks pass between position snapshots of moving trainers
+	SnapshotTicks int `json:"snapshot_ticks"`
+	// BroadcastRate is how many times a second positions are broadcast, 60 when unset
+	BroadcastRate int `json:"broadcast_rate"`
+}
+
+// MovementBroadcaster runs the movement simulation and broadcasts positions of moving trainers
+// at the broadcast rate, 60Hz by default. Position and movement of trainers moved through this server live in memory and are
+// authoritative there; they are persisted as snapshots every few ticks, on every move command
+// and on logout. Redis is written and read by a separate sync loop, so a Redis latency spike
+// delays persistence instead of stalling movement. The simulation only keeps each trainer's
+// movement record; inventory, money and other fields keep going through the transactional
+// repository, which is read on move commands but never by the loops.
 type MovementBroadcaster struct {
 	logger          *logger.Logger
 	repository      trainer.Repository
+	positions       trainer.PositionRepository
 	eventBus        *cqrs.EventBus
 	redisClient     *redis.Client
+	terrain         trainer.Terrain
+	config          MovementConfig
+	tenant          *tenant.Tenant // Tenant whose trainers are simulated, nil for the default one
 	stopChan        chan struct{}
 	broadcastTicker *time.Ticker
+	syncTicker      *time.Ticker
+	frames          chan []interface{}
+	shards          []*movementShard
+	ticks           int
+	broadcastAt     atomic.Int64 // When the tick of the newest positions clients got ran, Unix nanoseconds
+	interval        atomic.Int64 // Time between broadcast ticks, changed by SetBroadcastRate
+
+	pendingMutex sync.Mutex               // Taken after a shard's mutex, never before
+	pending      map[string]movementWrite // Changes not yet persisted, latest per user
+	lagging      bool
+	persistMutex sync.Mutex // Keeps persists in order
+}
+
+// movementShard holds the resident trainers of one slice of users
+type movementShard struct {
+	mutex    sync.Mutex
+	resident map[string]*residentTrainer
+}
+
+// residentTrainer is the simulation state of one trainer
+type residentTrainer struct {
+	record      trainer.MovementRecord
+	owned       bool      // Moved through this server, which persists its position
+	savedAt     time.Time // When its latest snapshot was taken here
+	refreshedAt time.Time // When its moving key's TTL was last refreshed
+
+	// Where it was over the last moments, for rewinding hit checks to what shooters saw
+	history trainer.PositionHistory
+
+	// What positions batches last carried of the trainer, for sending only what changed
+	sent           bool
+	sentMovement   trainer.MovementState
+	sentAppearance cqrscommands.TrainerAppearance
 }
 
+// delta returns the trainer's entry in a positions batch, with its movement and appearance
+// when they changed since the last batch or all is sent
+func (r *residentTrainer) delta(userID string, all bool) cqrscommands.PositionDelta {
+	delta := cqrscommands.PositionDelta{
+		UserID: userID,
+		X:      math.Round(r.record.Position.X*positionPrecision) / positionPrecision,
+		Y:      math.Round(r.record.Position.Y*positionPrecision) / positionPrecision,
+	}
+
+	if all || !r.sent || !r.record.Movement.Equal(r.sentMovement) {
+		movement := r.record.Movement
+		delta.Movement = &movement
+		r.sentMovement = movement
+	}
+	appearance := cqrscommands.TrainerAppearance{Color: r.record.Color, Showcase: r.record.Showcase}
+	if all || !r.sent || appearance.Color != r.sentAppearance.Color || !slices.Equal(appearance.Showcase, r.sentAppearance.Showcase) {
+		delta.Appearance = &appearance
+		r.sentAppearance = appearance
+	}
+	r.sent = true
+	return delta
+}
+
+// movingKeyOp says what to do with a trainer's moving key
+type movingKeyOp int
+
 const (
-	// Redis key pattern for moving trainers: "moving:trainer:{userID}"
+	movingKeyKeep movingKeyOp = iota
+	movingKeySet
+	movingKeyDelete
+)
+
+// movementWrite is a change to persist for one trainer
+type movementWrite struct {
+	record *trainer.MovementRecord // Position snapshot to store and record for the moving key
+	key    movingKeyOp
+	since  time.Time // When the oldest change coalesced into this write was made
+}
+
+// merge applies a newer write on top of this one
+func (w movementWrite) merge(newer movementWrite) movementWrite {
+	if newer.record != nil {
+		w.record = newer.record
+	}
+	if newer.key != movingKeyKeep {
+		w.key = newer.key
+	}
+	return w
+}
+
+const (
+	// Redis key pattern for moving trainers: "moving:trainer:{userID}". Each is a hash
+	// holding the trainer's movement record, so other servers simulate it from the key alone.
 	movingTrainerKeyPrefix = "moving:trainer:"
+	// movingTrainersKey indexes the moving keys in a sorted set scored by when they expire, so
+	// servers find moving trainers without scanning the keyspace. Members of servers that
+	// stopped are pruned once their score passes, like the keys they index.
+	movingTrainersKey = "moving:trainers"
 	// TTL for moving trainer keys (30 seconds)
 	movingTrainerTTL = 30 * time.Second
+	// movementSyncInterval is how often buffered changes are persisted and other servers'
+	// moving trainers are picked up
+	movementSyncInterval = 100 * time.Millisecond
+	// movementMaxLag is how far persistence may fall behind before it is reported
+	movementMaxLag = 2 * time.Second
+	// movementRedisTimeout bounds each persist and each refresh
+	movementRedisTimeout = time.Second
+	// movementFrameBuffer is how many broadcast frames may wait to be published; older
+	// position updates are dropped first since newer ones supersede them
+	movementFrameBuffer = 4
+	// positionKeyframeInterval is how often positions batches carry every trainer in full. It
+	// is a multiple of accessibility.ReducedInterval, and keyframes are picked from the tick
+	// time the same way, so players on the reduced rate receive every keyframe.
+	positionKeyframeInterval = time.Second
+	// positionPrecision rounds positions in batches to hundredths of a tile
+	positionPrecision = 100
+	// defaultBroadcastRate is how many times a second positions are broadcast when unset
+	defaultBroadcastRate = 60
 )
 
-// NewMovementBroadcaster creates a new Redis-based movement broadcaster
+// movementStats exposes persistence lag and dropped frames, served at /debug/vars
+var movementStats = expvar.NewMap("movement")
+
+// NewMovementBroadcaster creates a new movement broadcaster
 func NewMovementBroadcaster(
 	logger *logger.Logger,
 	repository trainer.Repository,
+	positions trainer.PositionRepository,
 	eventBus *cqrs.EventBus,
 	redisClient *redis.Client,
+	terrain trainer.Terrain,
+	config MovementConfig,
 ) *MovementBroadcaster {
-	return &MovementBroadcaster{
+	if config.Shards < 1 {
+		config.Shards = 1
+	}
+	if config.SnapshotTicks < 1 {
+		config.SnapshotTicks = 1
+	}
+
+	shards := make([]*movementShard, config.Shards)
+	for i := range shards {
+		shards[i] = &movementShard{resident: make(map[string]*residentTrainer)}
+	}
+
+	mb := &MovementBroadcaster{
 		logger:      logger.WithComponent("movement-broadcaster"),
 		repository:  repository,
+		positions:   positions,
 		eventBus:    eventBus,
 		redisClient: redisClient,
+		terrain:     terrain,
+		config:      config,
 		stopChan:    make(chan struct{}),
+		frames:      make(chan []interface{}, movementFrameBuffer),
+		shards:      shards,
+		pending:     make(map[string]movementWrite),
 	}
+	mb.SetBroadcastRate(config.BroadcastRate)
+	return mb
+}
+
+// SetBroadcastRate changes how many times a second positions are broadcast, from the next
+// tick on; rates below 1 restore the default. Clients interpolate between batches, so they
+// keep moving trainers smoothly at any rate.
+func (mb *MovementBroadcaster) SetBroadcastRate(rate int) {
+	if rate < 1 {
+		rate = defaultBroadcastRate
+	}
+	mb.interval.Store(int64(time.Second / time.Duration(rate)))
+}
+
+// frameInterval returns the time between broadcast ticks
+func (mb *MovementBroadcaster) frameInterval() time.Duration {
+	return time.Duration(mb.interval.Load())
+}
+
+// scope returns ctx scoped to the simulated tenant, so the loops read and write its keys
+func (mb *MovementBroadcaster) scope(ctx context.Context) context.Context {
+	if mb.tenant == nil {
+		return ctx
+	}
+	return tenant.WithTenant(ctx, mb.tenant)
 }
 
 // Start begins the periodic broadcasting
 func (mb *MovementBroadcaster) Start(ctx context.Context) {
-	// Broadcast moving trainers at 60Hz for ultra smooth movement (16.67ms)
-	mb.broadcastTicker = time.NewTicker(time.Second / 60)
-
-	mb.logger.Info("Starting Redis-based movement broadcaster",
-		zap.Duration("broadcast_interval", time.Second/60), // ~16.67ms for 60Hz
-		zap.String("frequency", "60Hz"),
+	ctx = mb.scope(ctx)
+	// Broadcast moving trainers at the broadcast rate, 16.67ms apart at the default 60Hz
+	interval := mb.frameInterval()
+	mb.broadcastTicker = time.NewTicker(interval)
+	mb.syncTicker = time.NewTicker(movementSyncInterval)
+
+	mb.logger.Info("Starting movement broadcaster",
+		zap.Duration("broadcast_interval", interval),
+		zap.Int("shards", mb.config.Shards),
+		zap.Int("snapshot_ticks", mb.config.SnapshotTicks),
+		zap.Duration("sync_interval", movementSyncInterval),
 		zap.Duration("ttl", movingTrainerTTL))
 
+	// Pick up trainers already moving before the first tick
+	mb.refresh(ctx)
+
+	go mb.publishLoop(ctx)
+	go mb.syncLoop(ctx)
 	go mb.broadcastLoop(ctx)
 }
 
-// Stop stops the periodic broadcasting
+// Stop stops the periodic broadcasting and persists the position of every owned trainer
 func (mb *MovementBroadcaster) Stop() {
-	mb.logger.Info("Stopping Redis-based movement broadcaster")
-	
+	mb.logger.Info("Stopping movement broadcaster")
+
 	if mb.broadcastTicker != nil {
 		mb.broadcastTicker.Stop()
 	}
-	
+	if mb.syncTicker != nil {
+		mb.syncTicker.Stop()
+	}
+
 	close(mb.stopChan)
+
+	for _, shard := range mb.shards {
+		shard.mutex.Lock()
+		for userID, r := range shard.resident {
+			if r.owned {
+				mb.queueSnapshot(userID, r, movingKeyKeep)
+			}
+		}
+		shard.mutex.Unlock()
+	}
+	mb.persist(mb.scope(context.Background()))
+}
+
+// shard returns the shard a user's trainer lives in
+func (mb *MovementBroadcaster) shard(userID string) *movementShard {
+	h := fnv.New32a()
+	h.Write([]byte(userID))
+	return mb.shards[h.Sum32()%uint32(len(mb.shards))]
 }
 
-// AddMovingTrainer adds a trainer to Redis with TTL
-func (mb *MovementBroadcaster) AddMovingTrainer(userID, _, color string) {
-	key := movingTrainerKeyPrefix + userID
-	value := fmt.Sprintf("%s:%s", userID, color) // Store userID:color
-	
-	mb.logger.Info("AddMovingTrainer called",
-		zap.String("userID", userID),
-		zap.String("color", color))
-	
-	err := mb.redisClient.Set(context.Background(), key, value, movingTrainerTTL).Err()
+// Move applies a movement command to the trainer, positioned where the simulation has it,
+// and returns the moved trainer. The trainer is read from the repository; only its movement
+// record stays resident, and the change is persisted on the next sync.
+func (mb *MovementBroadcaster) Move(ctx context.Context, userID string, apply func(*trainer.Trainer) error) (*trainer.Trainer, error) {
+	t, err := mb.load(ctx, userID)
 	if err != nil {
-		mb.logger.Error("Failed to add moving trainer to Redis",
-			zap.String("userID", userID),
-			zap.Error(err))
-		return
+		return nil, err
+	}
+
+	shard := mb.shard(userID)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	r, ok := shard.resident[userID]
+	if ok {
+		t.ApplyPosition(r.record.PositionSnapshot())
+	} else {
+		// The stored movement may be long over, e.g. after a restart
+		t.UpdatePositionWithin(mb.terrain)
 	}
 
-	mb.logger.Info("Successfully added moving trainer to Redis",
-		zap.String("userID", userID),
-		zap.String("key", key))
+	if err := apply(t); err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		r = &residentTrainer{}
+		shard.resident[userID] = r
+	}
+	r.record = t.MovementRecord()
+	r.owned = true
+	r.refreshedAt = time.Now()
+	r.history.Record(r.refreshedAt, r.record.Position)
+
+	key := movingKeyDelete
+	if t.Movement.IsMoving {
+		key = movingKeySet
+	}
+	mb.queueSnapshot(userID, r, key)
+
+	return t, nil
 }
 
-// RemoveMovingTrainer removes a trainer from Redis
-func (mb *MovementBroadcaster) RemoveMovingTrainer(userID string) {
-	key := movingTrainerKeyPrefix + userID
-	
-	err := mb.redisClient.Del(context.Background(), key).Err()
+// Position returns the trainer with its current position
+func (mb *MovementBroadcaster) Position(ctx context.Context, userID string) (*trainer.Trainer, error) {
+	t, err := mb.load(ctx, userID)
 	if err != nil {
-		mb.logger.Error("Failed to remove moving trainer from Redis",
-			zap.String("userID", userID),
-			zap.Error(err))
+		return nil, err
+	}
+
+	shard := mb.shard(userID)
+	shard.mutex.Lock()
+	r, ok := shard.resident[userID]
+	if ok {
+		t.ApplyPosition(r.record.PositionSnapshot())
+	}
+	shard.mutex.Unlock()
+
+	if !ok {
+		t.UpdatePositionWithin(mb.terrain)
+	}
+	return t, nil
+}
+
+// PositionAt returns where a resident trainer was at a recent point in time. It returns false
+// for trainers not simulated here, which stand still, and for times older than their history.
+func (mb *MovementBroadcaster) PositionAt(userID string, at time.Time) (shared.Position, bool) {
+	shard := mb.shard(userID)
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	r, ok := shard.resident[userID]
+	if !ok {
+		return shared.Position{}, false
+	}
+	return r.history.At(at)
+}
+
+// Logout stops the trainer, persists its position right away and drops it from memory
+func (mb *MovementBroadcaster) Logout(ctx context.Context, userID string) {
+	shard := mb.shard(userID)
+
+	shard.mutex.Lock()
+	r, ok := shard.resident[userID]
+	if !ok || !r.owned {
+		shard.mutex.Unlock()
 		return
 	}
 
-	mb.logger.Debug("Removed moving trainer from Redis", zap.String("userID", userID))
+	var stopped *cqrscommands.TrainerStoppedEvent
+	if r.record.Movement.IsMoving {
+		r.record.StopWithin(mb.terrain)
+		stopped = mb.stoppedEvent(userID, r.record, "logout-", time.Now())
+	}
+	mb.queueSnapshot(userID, r, movingKeyDelete)
+	shard.mutex.Unlock()
+
+	if stopped != nil {
+		if err := mb.eventBus.Publish(ctx, stopped); err != nil {
+			mb.logger.Error("Failed to publish logout stop", zap.Error(err))
+		}
+	}
+
+	mb.persist(ctx)
+
+	mb.logger.Debug("Trainer logged out of the movement simulation", zap.String("userID", userID))
 }
 
-// UpdateTrainerActivity refreshes the TTL for a moving trainer
-func (mb *MovementBroadcaster) UpdateTrainerActivity(userID string) {
-	key := movingTrainerKeyPrefix + userID
-	
-	// Refresh TTL to keep trainer active
-	err := mb.redisClient.Expire(context.Background(), key, movingTrainerTTL).Err()
+// load reads a trainer from the repository, outside any shard's lock
+func (mb *MovementBroadcaster) load(ctx context.Context, userID string) (*trainer.Trainer, error) {
+	t, err := mb.repository.GetByID(ctx, trainer.UserID(userID))
 	if err != nil {
-		mb.logger.Debug("Failed to refresh moving trainer TTL",
-			zap.String("userID", userID),
-			zap.Error(err))
+		return nil, err
+	}
+	if t == nil {
+		return nil, fmt.Errorf("trainer not found")
 	}
+	return t, nil
 }
 
-// broadcastLoop periodically broadcasts positions of moving trainers
+// queueSnapshot queues the resident's position to be persisted. Callers hold the shard's mutex.
+func (mb *MovementBroadcaster) queueSnapshot(userID string, r *residentTrainer, key movingKeyOp) {
+	r.record.SavedAt = time.Now()
+	r.savedAt = r.record.SavedAt
+	record := r.record
+	mb.queueWrite(userID, movementWrite{record: &record, key: key})
+}
+
+// queueWrite merges a write into the trainer's pending one, keeping when its oldest change was
+// made
+func (mb *MovementBroadcaster) queueWrite(userID string, write movementWrite) {
+	mb.pendingMutex.Lock()
+	defer mb.pendingMutex.Unlock()
+
+	write.since = time.Now()
+	if previous, ok := mb.pending[userID]; ok {
+		write = previous.merge(write)
+	}
+	mb.pending[userID] = write
+}
+
+// hasPending reports whether a trainer has changes not yet persisted
+func (mb *MovementBroadcaster) hasPending(userID string) bool {
+	mb.pendingMutex.Lock()
+	defer mb.pendingMutex.Unlock()
+
+	_, ok := mb.pending[userID]
+	return ok
+}
+
+// broadcastLoop periodically broadcasts positions of moving trainers, following changes of
+// the broadcast rate
 func (mb *MovementBroadcaster) broadcastLoop(ctx context.Context) {
+	interval := mb.frameInterval()
 	for {
 		select {
 		case <-ctx.Done():
@@ -131,172 +448,517 @@ func (mb *MovementBroadcaster) broadcastLoop(ctx context.Context) {
 		case <-mb.stopChan:
 			return
 		case <-mb.broadcastTicker.C:
-			mb.broadcastMovingTrainers(ctx)
+			if next := mb.frameInterval(); next != interval {
+				interval = next
+				mb.broadcastTicker.Reset(interval)
+			}
+			mb.broadcastMovingTrainers()
 		}
 	}
 }
 
-// broadcastMovingTrainers discovers moving trainers from Redis and broadcasts their positions
-func (mb *MovementBroadcaster) broadcastMovingTrainers(ctx context.Context) {
-	// Scan Redis for all moving trainer keys
-	keys, err := mb.redisClient.Keys(ctx, movingTrainerKeyPrefix+"*").Result()
-	if err != nil {
-		mb.logger.Error("Failed to scan Redis for moving trainers", zap.Error(err))
-		return
+// broadcastMovingTrainers advances every moving trainer and queues the frame for publishing:
+// the tick's stops, then one positions batch for the trainers still moving. Every
+// SnapshotTicks ticks the owned moving trainers' positions are queued for persisting.
+func (mb *MovementBroadcaster) broadcastMovingTrainers() {
+	now := time.Now()
+	interval := mb.frameInterval()
+	mb.ticks++
+	snapshot := mb.ticks%mb.config.SnapshotTicks == 0
+	batch := &cqrscommands.PositionsBatchEvent{
+		Timestamp:   now,
+		Keyframe:    now.UnixNano()%int64(positionKeyframeInterval) < int64(interval),
+		ReducedBeat: accessibility.OnReducedBeat(now, interval),
 	}
 
-	if len(keys) == 0 {
+	var frame []interface{}
+	for _, shard := range mb.shards {
+		shard.mutex.Lock()
+		for userID, r := range shard.resident {
+			if !r.record.Movement.IsMoving {
+				continue
+			}
+
+			// Stop trainers walking to a destination once they reach it
+			if r.record.Arrive(now) {
+				r.history.Record(now, r.record.Position)
+				if r.owned {
+					mb.queueSnapshot(userID, r, movingKeyDelete)
+				}
+				frame = append(frame, mb.stoppedEvent(userID, r.record, "arrived-", now))
+				continue
+			}
+
+			// Update position from movement, stopping trainers that ran into blocked terrain
+			blocked := r.record.UpdatePositionWithin(mb.terrain)
+			r.history.Record(now, r.record.Position)
+			if blocked {
+				if r.owned {
+					mb.queueSnapshot(userID, r, movingKeyDelete)
+				}
+				frame = append(frame, mb.stoppedEvent(userID, r.record, "blocked-", now))
+				continue
+			}
+
+			if snapshot && r.owned {
+				key := movingKeyKeep
+				// Keep moving keys alive while trainers move without new commands
+				if now.Sub(r.refreshedAt) > movingTrainerTTL/3 {
+					r.refreshedAt = now
+					key = movingKeySet
+				}
+				mb.queueSnapshot(userID, r, key)
+			}
+
+			batch.Trainers = append(batch.Trainers, r.delta(userID, batch.Keyframe))
+		}
+		shard.mutex.Unlock()
+	}
+
+	if len(batch.Trainers) > 0 {
+		frame = append(frame, batch)
+	}
+	if len(frame) == 0 {
+		// Nothing to send, so clients are as up to date as this tick once earlier frames are out
+		if len(mb.frames) == 0 {
+			mb.broadcasted(now)
+		}
 		return
 	}
 
-	broadcastCount := 0
-	for _, key := range keys {
-		// Extract userID from key
-		userID := strings.TrimPrefix(key, movingTrainerKeyPrefix)
-		
-		// Get trainer info from Redis
-		value, err := mb.redisClient.Get(ctx, key).Result()
-		if err != nil {
-			if err != redis.Nil {
-				mb.logger.Debug("Failed to get moving trainer value",
-					zap.String("userID", userID),
-					zap.Error(err))
-			}
-			continue
+	// Publishing goes through Redis too; when it falls behind, drop the oldest frame's
+	// positions rather than block the tick, but keep its stops and the changes its batch
+	// carried
+	select {
+	case mb.frames <- frame:
+	default:
+		select {
+		case dropped := <-mb.frames:
+			movementStats.Add("frames_dropped", 1)
+			carryChanges(dropped, batch)
+			frame = append(stopEvents(dropped), frame...)
+		default:
 		}
+		mb.frames <- frame // The tick is the only sender, so there is room now
+	}
+}
 
-		// Parse userID:color from value (userID for display, color for rendering)
-		parts := strings.SplitN(value, ":", 2)
-		if len(parts) != 2 {
-			mb.logger.Debug("Invalid trainer value format",
-				zap.String("userID", userID),
-				zap.String("value", value))
+// carryChanges copies the movement and appearance changes of a dropped frame's batch into the
+// next batch, for the trainers the next batch has without them
+func carryChanges(dropped []interface{}, next *cqrscommands.PositionsBatchEvent) {
+	for _, event := range dropped {
+		older, ok := event.(*cqrscommands.PositionsBatchEvent)
+		if !ok {
 			continue
 		}
-		_, color := parts[0], parts[1] // parts[0] is userID which we already have
 
-		// Get current trainer state from repository
-		trainerEntity, err := mb.repository.GetByID(ctx, trainer.UserID(userID))
-		if err != nil {
-			mb.logger.Debug("Failed to get moving trainer",
-				zap.String("userID", userID),
-				zap.Error(err))
-			// Remove stale key
-			mb.RemoveMovingTrainer(userID)
-			continue
+		changed := make(map[string]cqrscommands.PositionDelta, len(older.Trainers))
+		for _, delta := range older.Trainers {
+			if delta.Movement != nil || delta.Appearance != nil {
+				changed[delta.UserID] = delta
+			}
 		}
+		for i := range next.Trainers {
+			delta := &next.Trainers[i]
+			if carried, ok := changed[delta.UserID]; ok {
+				if delta.Movement == nil {
+					delta.Movement = carried.Movement
+				}
+				if delta.Appearance == nil {
+					delta.Appearance = carried.Appearance
+				}
+			}
+		}
+	}
+}
 
-		// Update position from movement
-		trainerEntity.UpdatePositionFromMovement()
+// stoppedEvent describes a stop the simulation made on its own
+func (mb *MovementBroadcaster) stoppedEvent(userID string, record trainer.MovementRecord, reason string, now time.Time) *cqrscommands.TrainerStoppedEvent {
+	return &cqrscommands.TrainerStoppedEvent{
+		UserID:    userID,
+		Nickname:  userID,
+		Showcase:  record.Showcase,
+		Color:     record.Color,
+		Position:  record.Position,
+		Movement:  record.Movement,
+		Timestamp: now,
+		RequestID: reason + userID + "-" + now.Format("150405.000"),
+		Changes:   nil,
+	}
+}
 
-		// Check if trainer is still moving
-		if !trainerEntity.Movement.IsMoving {
-			// Remove from Redis since trainer stopped moving
-			mb.RemoveMovingTrainer(userID)
-			continue
+// stopEvents returns the stop events of a frame
+func stopEvents(frame []interface{}) []interface{} {
+	var stops []interface{}
+	for _, event := range frame {
+		if _, ok := event.(*cqrscommands.TrainerStoppedEvent); ok {
+			stops = append(stops, event)
 		}
+	}
+	return stops
+}
 
-		// Create and publish movement event using userID as display name
-		event := &cqrscommands.TrainerMovedEvent{
-			UserID:    userID,
-			Nickname:  userID, // Use userID as display identifier
-			Color:     color,
-			Position:  trainerEntity.Position,
-			Movement:  trainerEntity.Movement,
-			Timestamp: time.Now(),
-			RequestID: "broadcast-" + userID + "-" + time.Now().Format("150405.000"),
-			Changes:   nil, // No changes needed for position broadcasts
+// publishLoop publishes broadcast frames to the event bus
+func (mb *MovementBroadcaster) publishLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-mb.stopChan:
+			return
+		case frame := <-mb.frames:
+			for _, event := range frame {
+				if err := mb.eventBus.Publish(ctx, event); err != nil {
+					mb.logger.Error("Failed to publish movement broadcast", zap.Error(err))
+				}
+			}
+			mb.broadcasted(frameTime(frame))
 		}
+	}
+}
 
-		if err := mb.eventBus.Publish(ctx, event); err != nil {
-			mb.logger.Error("Failed to publish movement broadcast",
-				zap.String("userID", userID),
-				zap.Error(err))
-		} else {
-			broadcastCount++
-		}
+// frameTime returns when a frame's tick ran, which its last event carries; the stops of
+// dropped frames come before it
+func frameTime(frame []interface{}) time.Time {
+	switch event := frame[len(frame)-1].(type) {
+	case *cqrscommands.PositionsBatchEvent:
+		return event.Timestamp
+	case *cqrscommands.TrainerStoppedEvent:
+		return event.Timestamp
 	}
+	return time.Time{}
+}
 
-	if broadcastCount > 0 {
-		mb.logger.Debug("Broadcasted positions from Redis",
-			zap.Int("moving_trainers", broadcastCount))
+// broadcasted records that clients got the positions of the tick run at, unless they already
+// got newer ones
+func (mb *MovementBroadcaster) broadcasted(at time.Time) {
+	for {
+		newest := mb.broadcastAt.Load()
+		if at.UnixNano() <= newest || mb.broadcastAt.CompareAndSwap(newest, at.UnixNano()) {
+			return
+		}
 	}
 }
 
-// GetMovingTrainersCount returns the number of currently moving trainers from Redis
-func (mb *MovementBroadcaster) GetMovingTrainersCount() int {
-	keys, err := mb.redisClient.Keys(context.Background(), movingTrainerKeyPrefix+"*").Result()
-	if err != nil {
-		mb.logger.Error("Failed to count moving trainers", zap.Error(err))
+// BroadcastAge returns how old the newest positions clients got are at now: about a tick
+// while publishing keeps up, growing as it falls behind. It is 0 before the first tick.
+func (mb *MovementBroadcaster) BroadcastAge(now time.Time) time.Duration {
+	at := mb.broadcastAt.Load()
+	if at == 0 {
 		return 0
 	}
-	return len(keys)
+	return max(now.Sub(time.Unix(0, at)), 0)
 }
 
-// GetCurrentOnlineTrainers returns current positions of all online trainers
-func (mb *MovementBroadcaster) GetCurrentOnlineTrainers(ctx context.Context) []cqrscommands.TrainerMovedEvent {
-	// Get all moving trainers from Redis
-	keys, err := mb.redisClient.Keys(ctx, movingTrainerKeyPrefix+"*").Result()
-	if err != nil {
-		mb.logger.Error("Failed to get online trainers", zap.Error(err))
+// syncLoop persists buffered changes and picks up trainers moving on other servers
+func (mb *MovementBroadcaster) syncLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-mb.stopChan:
+			return
+		case <-mb.syncTicker.C:
+			mb.persist(ctx)
+			mb.refresh(ctx)
+		}
+	}
+}
+
+// persist writes pending changes to Redis in one batch. A failed batch stays pending for the
+// next sync, under any newer changes made meanwhile. Once persisted, trainers that stand still
+// are dropped from memory.
+func (mb *MovementBroadcaster) persist(ctx context.Context) {
+	mb.persistMutex.Lock()
+	defer mb.persistMutex.Unlock()
+
+	now := time.Now()
+
+	mb.pendingMutex.Lock()
+	writes := mb.pending
+	mb.pending = make(map[string]movementWrite, len(writes))
+	mb.pendingMutex.Unlock()
+
+	if err := mb.persistWrites(ctx, writes); err != nil {
+		mb.logger.Debug("Failed to persist movement, retrying next sync",
+			zap.Int("trainers", len(writes)),
+			zap.Error(err))
+
+		mb.pendingMutex.Lock()
+		for userID, write := range writes {
+			if newer, ok := mb.pending[userID]; ok {
+				write = write.merge(newer)
+			}
+			mb.pending[userID] = write
+		}
+		mb.pendingMutex.Unlock()
+	} else {
+		mb.evictIdle(writes)
+	}
+
+	mb.reportLag(now)
+}
+
+// persistWrites stores the snapshots and moving keys of a batch of writes
+func (mb *MovementBroadcaster) persistWrites(ctx context.Context, writes map[string]movementWrite) error {
+	if len(writes) == 0 {
 		return nil
 	}
 
-	var onlineTrainers []cqrscommands.TrainerMovedEvent
-	
-	for _, key := range keys {
-		// Extract userID from key
-		userID := strings.TrimPrefix(key, movingTrainerKeyPrefix)
-		
-		// Get trainer info from Redis
-		value, err := mb.redisClient.Get(ctx, key).Result()
-		if err != nil {
-			if err != redis.Nil {
-				mb.logger.Debug("Failed to get moving trainer value",
-					zap.String("userID", userID),
-					zap.Error(err))
+	ctx, cancel := context.WithTimeout(ctx, movementRedisTimeout)
+	defer cancel()
+
+	snapshots := make([]trainer.PositionSnapshot, 0, len(writes))
+	for _, write := range writes {
+		if write.record != nil {
+			snapshots = append(snapshots, write.record.PositionSnapshot())
+		}
+	}
+	if err := mb.positions.SaveAll(ctx, snapshots); err != nil {
+		return err
+	}
+
+	expiresAt := float64(time.Now().Add(movingTrainerTTL).UnixMilli())
+	_, err := mb.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for userID, write := range writes {
+			key := movingTrainerKeyPrefix + userID
+			switch write.key {
+			case movingKeySet:
+				fields, err := write.record.HashFields()
+				if err != nil {
+					return err
+				}
+				pipe.HSet(ctx, key, fields)
+				pipe.Expire(ctx, key, movingTrainerTTL)
+				pipe.ZAdd(ctx, movingTrainersKey, redis.Z{Score: expiresAt, Member: userID})
+			case movingKeyDelete:
+				pipe.Del(ctx, key)
+				pipe.ZRem(ctx, movingTrainersKey, userID)
 			}
-			continue
 		}
+		return nil
+	})
+	return err
+}
 
-		// Parse userID:color from value
-		parts := strings.SplitN(value, ":", 2)
-		if len(parts) != 2 {
-			mb.logger.Debug("Invalid trainer value format",
-				zap.String("userID", userID),
-				zap.String("value", value))
-			continue
+// evictIdle drops persisted trainers that stand still and changed no further
+func (mb *MovementBroadcaster) evictIdle(writes map[string]movementWrite) {
+	for userID := range writes {
+		shard := mb.shard(userID)
+
+		shard.mutex.Lock()
+		if r, ok := shard.resident[userID]; ok && !r.record.Movement.IsMoving && !mb.hasPending(userID) {
+			delete(shard.resident, userID)
+		}
+		shard.mutex.Unlock()
+	}
+}
+
+// reportLag publishes how far persistence is behind and warns once while it exceeds the bound
+func (mb *MovementBroadcaster) reportLag(now time.Time) {
+	mb.pendingMutex.Lock()
+	var lag time.Duration
+	for _, write := range mb.pending {
+		if age := now.Sub(write.since); age > lag {
+			lag = age
+		}
+	}
+	wasLagging := mb.lagging
+	mb.lagging = lag > movementMaxLag
+	mb.pendingMutex.Unlock()
+
+	lagMillis := new(expvar.Int)
+	lagMillis.Set(lag.Milliseconds())
+	movementStats.Set("persist_lag_ms", lagMillis)
+
+	if mb.lagging && !wasLagging {
+		mb.logger.Warn("Movement persistence is falling behind, gameplay continues from memory",
+			zap.Duration("lag", lag))
+	} else if !mb.lagging && wasLagging {
+		mb.logger.Info("Movement persistence caught up")
+	}
+}
+
+// refresh picks up trainers moving through other servers and the changes other servers made to
+// resident trainers, from the movement records in moving keys and the position snapshots. A
+// stored record or position replaces the resident one only if it was saved after the
+// resident's latest snapshot here and nothing is waiting to be persisted. Reads are batched,
+// so a refresh takes the same few round trips however many trainers there are.
+func (mb *MovementBroadcaster) refresh(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, movementRedisTimeout)
+	defer cancel()
+
+	var members *redis.StringSliceCmd
+	_, err := mb.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		expired := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		pipe.ZRemRangeByScore(ctx, movingTrainersKey, "-inf", expired)
+		members = pipe.ZRange(ctx, movingTrainersKey, 0, -1)
+		return nil
+	})
+	if err != nil {
+		mb.logger.Debug("Failed to list moving trainers", zap.Error(err))
+		return
+	}
+
+	records, err := mb.movementRecords(ctx, members.Val())
+	if err != nil {
+		mb.logger.Debug("Failed to get movement records", zap.Error(err))
+		return
+	}
+
+	userIDs := make([]string, 0, len(records))
+	for userID := range records {
+		userIDs = append(userIDs, userID)
+	}
+	for _, shard := range mb.shards {
+		shard.mutex.Lock()
+		for userID := range shard.resident {
+			if _, ok := records[userID]; !ok {
+				userIDs = append(userIDs, userID)
+			}
+		}
+		shard.mutex.Unlock()
+	}
+
+	ids := make([]trainer.UserID, len(userIDs))
+	for i, userID := range userIDs {
+		ids[i] = trainer.UserID(userID)
+	}
+	snapshots, err := mb.positions.GetMany(ctx, ids)
+	if err != nil {
+		mb.logger.Debug("Failed to get position snapshots", zap.Error(err))
+		return
+	}
+
+	for _, userID := range userIDs {
+		stored, found := snapshots[trainer.UserID(userID)]
+		var record *trainer.MovementRecord
+		if moving, ok := records[userID]; ok {
+			record = &moving
 		}
-		_, color := parts[0], parts[1]
+		mb.merge(userID, record, stored, found)
+	}
+}
+
+// movementRecords reads the records of the moving keys of users, leaving out keys that expired
+// since being listed
+func (mb *MovementBroadcaster) movementRecords(ctx context.Context, userIDs []string) (map[string]trainer.MovementRecord, error) {
+	records := make(map[string]trainer.MovementRecord, len(userIDs))
+	if len(userIDs) == 0 {
+		return records, nil
+	}
+
+	hashes := make([]*redis.MapStringStringCmd, len(userIDs))
+	_, err := mb.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, userID := range userIDs {
+			hashes[i] = pipe.HGetAll(ctx, movingTrainerKeyPrefix+userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		// Get current trainer state from repository
-		trainerEntity, err := mb.repository.GetByID(ctx, trainer.UserID(userID))
+	for i, userID := range userIDs {
+		fields := hashes[i].Val()
+		if len(fields) == 0 {
+			continue
+		}
+		record, err := trainer.ParseMovementRecord(trainer.UserID(userID), fields)
 		if err != nil {
-			mb.logger.Debug("Failed to get trainer for initial sync",
-				zap.String("userID", userID),
-				zap.Error(err))
+			mb.logger.Debug("Skipping unreadable movement record", zap.String("userID", userID), zap.Error(err))
 			continue
 		}
+		records[userID] = record
+	}
+	return records, nil
+}
+
+// merge reconciles a resident trainer with its stored movement record, nil unless it moves,
+// and its position snapshot
+func (mb *MovementBroadcaster) merge(userID string, record *trainer.MovementRecord, stored trainer.PositionSnapshot, found bool) {
+	shard := mb.shard(userID)
+
+	shard.mutex.Lock()
+	defer shard.mutex.Unlock()
+
+	r, ok := shard.resident[userID]
+	if !ok {
+		// Trainers that only stand still elsewhere aren't simulated here
+		if record != nil && record.Movement.IsMoving {
+			resident := *record
+			if found && stored.SavedAt.After(resident.SavedAt) {
+				resident.ApplyPosition(stored)
+			}
+			shard.resident[userID] = &residentTrainer{record: resident, savedAt: resident.SavedAt}
+		}
+		return
+	}
+
+	if record == nil && !found {
+		delete(shard.resident, userID) // Deleted elsewhere
+		return
+	}
+
+	pending := mb.hasPending(userID)
+	if record != nil && record.SavedAt.After(r.savedAt) && !pending {
+		r.record = *record
+		r.savedAt = record.SavedAt
+	}
+	if found && stored.SavedAt.After(r.savedAt) && !pending {
+		r.record.ApplyPosition(stored)
+		r.savedAt = stored.SavedAt
+	}
+
+	// Stopped trainers are read from the repository again once their stop is persisted
+	if !r.record.Movement.IsMoving && !pending {
+		delete(shard.resident, userID)
+	}
+}
+
+// GetMovingTrainersCount returns the number of currently moving trainers
+func (mb *MovementBroadcaster) GetMovingTrainersCount() int {
+	count := 0
+	for _, shard := range mb.shards {
+		shard.mutex.Lock()
+		for _, r := range shard.resident {
+			if r.record.Movement.IsMoving {
+				count++
+			}
+		}
+		shard.mutex.Unlock()
+	}
+	return count
+}
 
-		// Update position from movement
-		trainerEntity.UpdatePositionFromMovement()
+// GetCurrentOnlineTrainers returns current positions of all moving trainers
+func (mb *MovementBroadcaster) GetCurrentOnlineTrainers(ctx context.Context) []cqrscommands.TrainerMovedEvent {
+	now := time.Now()
 
-		// Add to online trainers list
-		onlineTrainers = append(onlineTrainers, cqrscommands.TrainerMovedEvent{
-			UserID:    userID,
-			Nickname:  userID,
-			Color:     color,
-			Position:  trainerEntity.Position,
-			Movement:  trainerEntity.Movement,
-			Timestamp: time.Now(),
-			RequestID: "initial-sync-" + userID,
-			Changes:   nil,
-		})
+	var onlineTrainers []cqrscommands.TrainerMovedEvent
+	for _, shard := range mb.shards {
+		shard.mutex.Lock()
+		for userID, r := range shard.resident {
+			if !r.record.Movement.IsMoving {
+				continue
+			}
+			onlineTrainers = append(onlineTrainers, cqrscommands.TrainerMovedEvent{
+				UserID:    userID,
+				Nickname:  userID,
+				Showcase:  r.record.Showcase,
+				Color:     r.record.Color,
+				Position:  r.record.Position,
+				Movement:  r.record.Movement,
+				Timestamp: now,
+				RequestID: "initial-sync-" + userID,
+				Changes:   nil,
+			})
+		}
+		shard.mutex.Unlock()
 	}
 
 	mb.logger.Debug("Retrieved online trainers for initial sync",
 		zap.Int("count", len(onlineTrainers)))
-	
+
 	return onlineTrainers
 }
-
@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// openTerrain lets trainers walk anywhere
+type openTerrain struct{}
+
+func (openTerrain) IsWalkablePosition(shared.Position) bool { return true }
+
+func TestResidentTrainer_Delta(t *testing.T) {
+	tr, err := trainer.NewTrainer("mover", "Mover")
+	require.NoError(t, err)
+	tr.Position = shared.NewPosition(1.234, 5.678)
+	resident := &residentTrainer{record: tr.MovementRecord(), owned: true}
+
+	first := resident.delta("mover", false)
+	assert.Equal(t, 1.23, first.X)
+	assert.Equal(t, 5.68, first.Y)
+	assert.NotNil(t, first.Movement, "first send carries everything")
+	assert.NotNil(t, first.Appearance)
+
+	resident.record.Position = shared.NewPosition(2, 6)
+	unchanged := resident.delta("mover", false)
+	assert.Nil(t, unchanged.Movement)
+	assert.Nil(t, unchanged.Appearance)
+
+	resident.record.Color = "#000000"
+	recolored := resident.delta("mover", false)
+	assert.Nil(t, recolored.Movement)
+	require.NotNil(t, recolored.Appearance)
+	assert.Equal(t, "#000000", recolored.Appearance.Color)
+
+	keyframe := resident.delta("mover", true)
+	assert.NotNil(t, keyframe.Movement, "keyframes carry everything")
+	assert.NotNil(t, keyframe.Appearance)
+}
+
+func TestMovementBroadcaster_SetBroadcastRate(t *testing.T) {
+	mb := NewMovementBroadcaster(logger.NewDefault(), nil, nil, nil, nil, openTerrain{}, MovementConfig{})
+	assert.Equal(t, time.Second/60, mb.frameInterval(), "60Hz when unset")
+
+	mb.SetBroadcastRate(20)
+	assert.Equal(t, 50*time.Millisecond, mb.frameInterval())
+
+	mb.SetBroadcastRate(0)
+	assert.Equal(t, time.Second/60, mb.frameInterval())
+}
+
+// memoryPositions keeps position snapshots in a map; the next failures saves fail. Methods
+// the tests don't use panic.
+type memoryPositions struct {
+	trainer.PositionRepository
+	snapshots map[trainer.UserID]trainer.PositionSnapshot
+	failures  int
+}
+
+func newMemoryPositions() *memoryPositions {
+	return &memoryPositions{snapshots: map[trainer.UserID]trainer.PositionSnapshot{}}
+}
+
+func (m *memoryPositions) SaveAll(ctx context.Context, snapshots []trainer.PositionSnapshot) error {
+	if m.failures > 0 {
+		m.failures--
+		return errors.New("redis unreachable")
+	}
+	for _, snapshot := range snapshots {
+		m.snapshots[snapshot.ID] = snapshot
+	}
+	return nil
+}
+
+func (m *memoryPositions) GetMany(ctx context.Context, ids []trainer.UserID) (map[trainer.UserID]trainer.PositionSnapshot, error) {
+	found := make(map[trainer.UserID]trainer.PositionSnapshot)
+	for _, id := range ids {
+		if snapshot, ok := m.snapshots[id]; ok {
+			found[id] = snapshot
+		}
+	}
+	return found, nil
+}
+
+func (m *memoryPositions) Delete(ctx context.Context, id trainer.UserID) error {
+	delete(m.snapshots, id)
+	return nil
+}
+
+// unreachableRedis is never dialed: writes that keep the moving keys send an empty pipeline
+func unreachableRedis() *redis.Client {
+	return redis.NewClient(&redis.Options{Addr: "127.0.0.1:1"})
+}
+
+// residentAt puts an owned trainer into the simulation, moving right when moving is set
+func residentAt(t testing.TB, mb *MovementBroadcaster, userID string, position shared.Position, moving bool) *residentTrainer {
+	t.Helper()
+
+	tr, err := trainer.NewTrainer(trainer.UserID(userID), "Tester")
+	require.NoError(t, err)
+	tr.Position = position
+	if moving {
+		tr.Movement.StartMovement(trainer.MovementDirection{X: 1}, position)
+	}
+	r := &residentTrainer{record: tr.MovementRecord(), owned: true}
+	mb.shard(userID).resident[userID] = r
+	return r
+}
+
+func TestMovementBroadcaster_DropOldestFrame(t *testing.T) {
+	mb := NewMovementBroadcaster(logger.NewDefault(), nil, nil, nil, nil, openTerrain{}, MovementConfig{})
+	mover := residentAt(t, mb, "mover", shared.NewPosition(0, 0), true)
+	mover.delta("mover", true) // Its movement went out with the dropped frame only
+
+	// Publishing fell behind: the buffer is full, its oldest frame holding a stop
+	stop := &cqrscommands.TrainerStoppedEvent{UserID: "stopper", Timestamp: time.Now()}
+	movement := trainer.MovementState{IsMoving: true}
+	oldest := []interface{}{stop, &cqrscommands.PositionsBatchEvent{Trainers: []cqrscommands.PositionDelta{{UserID: "mover", Movement: &movement}}}}
+	mb.frames <- oldest
+	for len(mb.frames) < cap(mb.frames) {
+		mb.frames <- []interface{}{&cqrscommands.PositionsBatchEvent{}}
+	}
+
+	mb.broadcastMovingTrainers()
+	require.Len(t, mb.frames, cap(mb.frames), "the tick never blocks")
+
+	var newest []interface{}
+	for len(mb.frames) > 0 {
+		newest = <-mb.frames
+	}
+	require.Len(t, newest, 2)
+	assert.Same(t, stop, newest[0], "stops of the dropped frame are still sent")
+	batch, ok := newest[1].(*cqrscommands.PositionsBatchEvent)
+	require.True(t, ok)
+	require.Len(t, batch.Trainers, 1)
+	assert.NotNil(t, batch.Trainers[0].Movement, "the dropped batch's movement change is carried over")
+}
+
+func TestMovementBroadcaster_PersistRetry(t *testing.T) {
+	positions := newMemoryPositions()
+	mb := NewMovementBroadcaster(logger.NewDefault(), nil, positions, nil, unreachableRedis(), openTerrain{}, MovementConfig{})
+	r := residentAt(t, mb, "mover", shared.NewPosition(1, 1), true)
+
+	positions.failures = 1
+	mb.shard("mover").mutex.Lock()
+	mb.queueSnapshot("mover", r, movingKeyKeep)
+	mb.shard("mover").mutex.Unlock()
+	mb.persist(context.Background())
+	assert.Empty(t, positions.snapshots)
+	assert.True(t, mb.hasPending("mover"), "failed writes stay pending")
+
+	// A change made before the next sync is persisted over the failed one
+	r.record.Position = shared.NewPosition(2, 1)
+	mb.shard("mover").mutex.Lock()
+	mb.queueSnapshot("mover", r, movingKeyKeep)
+	mb.shard("mover").mutex.Unlock()
+	mb.persist(context.Background())
+	assert.False(t, mb.hasPending("mover"))
+	assert.Equal(t, shared.NewPosition(2, 1), positions.snapshots["mover"].Position)
+}
+
+func TestMovementBroadcaster_MergeKeepsUnpersistedState(t *testing.T) {
+	mb := NewMovementBroadcaster(logger.NewDefault(), nil, newMemoryPositions(), nil, unreachableRedis(), openTerrain{}, MovementConfig{})
+	r := residentAt(t, mb, "mover", shared.NewPosition(1, 1), true)
+	mb.shard("mover").mutex.Lock()
+	mb.queueSnapshot("mover", r, movingKeyKeep)
+	mb.shard("mover").mutex.Unlock()
+
+	remote := r.record
+	remote.Position = shared.NewPosition(40, 40)
+	remote.SavedAt = r.savedAt.Add(time.Second)
+	stored := remote.PositionSnapshot()
+
+	mb.merge("mover", &remote, stored, true)
+	assert.Equal(t, shared.NewPosition(1, 1), r.record.Position, "local changes waiting to be persisted win")
+
+	mb.pending = make(map[string]movementWrite)
+	mb.merge("mover", &remote, stored, true)
+	assert.Equal(t, shared.NewPosition(40, 40), r.record.Position, "newer remote changes are taken once nothing is pending")
+}
+
+func TestMovementBroadcaster_StopFlushes(t *testing.T) {
+	positions := newMemoryPositions()
+	mb := NewMovementBroadcaster(logger.NewDefault(), nil, positions, nil, unreachableRedis(), openTerrain{}, MovementConfig{Shards: 4})
+	residentAt(t, mb, "owned", shared.NewPosition(3, 4), false)
+	residentAt(t, mb, "elsewhere", shared.NewPosition(5, 6), true).owned = false
+
+	mb.Stop()
+	assert.Equal(t, shared.NewPosition(3, 4), positions.snapshots["owned"].Position, "owned trainers are persisted")
+	assert.NotContains(t, positions.snapshots, trainer.UserID("elsewhere"), "other servers persist their own trainers")
+}
+
+func BenchmarkMovementBroadcaster_Tick(b *testing.B) {
+	// Logging to stdout would interleave with the benchmark results
+	quiet, err := logger.New(logger.Config{Level: logger.ErrorLevel})
+	require.NoError(b, err)
+
+	for _, moving := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("moving=%d", moving), func(b *testing.B) {
+			mb := NewMovementBroadcaster(quiet, nil, nil, nil, nil, openTerrain{}, MovementConfig{Shards: 16, SnapshotTicks: 30})
+			for i := 0; i < moving; i++ {
+				userID := fmt.Sprintf("user-%d", i)
+				tr, err := trainer.NewTrainer(trainer.UserID(userID), "Tester")
+				require.NoError(b, err)
+				require.NoError(b, tr.StartMovement(1, 0))
+				mb.shard(userID).resident[userID] = &residentTrainer{record: tr.MovementRecord(), owned: true}
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				mb.broadcastMovingTrainers()
+				<-mb.frames // Stands in for the publish loop
+			}
+		})
+	}
+}
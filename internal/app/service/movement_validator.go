@@ -0,0 +1,139 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// MovementRules are the limits movement commands are held to on the server
+type MovementRules struct {
+	// MinInterval is the shortest time between two commands of a trainer. Clients are asked
+	// to wait 100ms; half of that leaves room for network jitter.
+	MinInterval time.Duration
+	// MaxSpeed is the fastest a trainer may cover ground, in units per second
+	MaxSpeed float64
+	// Tolerance is how much further than MaxSpeed allows a trainer may be found, for clock
+	// differences between server instances
+	Tolerance float64
+}
+
+// DefaultMovementRules returns the rules for trainers walking at the default speed
+func DefaultMovementRules() MovementRules {
+	return MovementRules{
+		MinInterval: 50 * time.Millisecond,
+		MaxSpeed:    trainer.DefaultSpeed * math.Sqrt2,
+		Tolerance:   1.0,
+	}
+}
+
+// movementCheckpointTTL is how long a trainer's last command is remembered. After that the
+// distance it may have covered is so large that the checkpoint rules nothing out.
+const movementCheckpointTTL = time.Minute
+
+// MovementValidator enforces MovementRules on movement commands: commands may not come
+// faster than MinInterval, and between two commands a trainer may not cover more ground than
+// MaxSpeed allows. Checkpoints are kept in Redis next to the position snapshots, so the rules
+// hold whichever server instance a command reaches.
+type MovementValidator struct {
+	logger      *logger.Logger
+	rules       MovementRules
+	checkpoints trainer.CheckpointRepository
+}
+
+// NewMovementValidator creates a new movement validator
+func NewMovementValidator(logger *logger.Logger, rules MovementRules, checkpoints trainer.CheckpointRepository) *MovementValidator {
+	return &MovementValidator{
+		logger:      logger.WithComponent("movement-validator"),
+		rules:       rules,
+		checkpoints: checkpoints,
+	}
+}
+
+// Validate checks a movement command about to be applied to t, whose position must be where
+// the simulation puts the trainer at now. A command sent too soon is rejected without changing
+// anything. A trainer found further away than it could have walked is put back where it was at
+// its last command and stopped; corrected is true then, and the change must be kept although
+// the command is rejected. Commands are let through while Redis is unreachable, so players
+// keep moving.
+func (v *MovementValidator) Validate(ctx context.Context, userID string, t *trainer.Trainer, now time.Time) (corrected bool, err error) {
+	var putBack *shared.Position
+	err = v.checkpoints.FindOneAndUpsert(ctx, trainer.UserID(userID), movementCheckpointTTL, func(last *trainer.MovementCheckpoint) (*trainer.MovementCheckpoint, error) {
+		putBack = nil
+		if last == nil {
+			return &trainer.MovementCheckpoint{At: now, Position: t.Position}, nil
+		}
+
+		elapsed := now.Sub(last.At)
+		if elapsed < v.rules.MinInterval {
+			movementStats.Add("rejected_throttled", 1)
+			v.logger.Warn("Movement command sent too soon",
+				zap.String("userId", userID),
+				zap.Duration("elapsed", elapsed),
+				zap.Duration("minInterval", v.rules.MinInterval))
+			return nil, v.throttled()
+		}
+
+		distance := math.Hypot(t.Position.X-last.Position.X, t.Position.Y-last.Position.Y)
+		allowed := v.rules.MaxSpeed*elapsed.Seconds() + v.rules.Tolerance
+		if distance > allowed {
+			movementStats.Add("rejected_teleport", 1)
+			v.logger.Warn("Trainer moved further than its speed allows, putting it back",
+				zap.String("userId", userID),
+				zap.Float64("distance", distance),
+				zap.Float64("allowed", allowed),
+				zap.Duration("elapsed", elapsed),
+				zap.Float64("fromX", last.Position.X),
+				zap.Float64("fromY", last.Position.Y),
+				zap.Float64("toX", t.Position.X),
+				zap.Float64("toY", t.Position.Y))
+
+			putBack = &last.Position
+			return &trainer.MovementCheckpoint{At: now, Position: last.Position}, nil
+		}
+
+		return &trainer.MovementCheckpoint{At: now, Position: t.Position}, nil
+	})
+	if errors.Is(err, redis.TxFailedErr) {
+		// Another command of the trainer was checked at the same moment
+		movementStats.Add("rejected_throttled", 1)
+		return false, v.throttled()
+	}
+	if _, ok := shared.DomainErrorCode(err); err != nil && !ok {
+		v.logger.Warn("Failed to check movement command, letting it through",
+			zap.String("userId", userID),
+			zap.Error(err))
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if putBack != nil {
+		t.MoveTo(*putBack)
+		return true, shared.NewDomainError(shared.ErrCodeMoveRejected, "Position out of sync with the server, movement stopped")
+	}
+	return false, nil
+}
+
+// throttled is the error of commands sent too soon after the previous one
+func (v *MovementValidator) throttled() error {
+	return shared.NewDomainErrorf(shared.ErrCodeMoveThrottled, "Movement commands must be at least %s apart", v.rules.MinInterval)
+}
+
+// Forget drops a trainer's checkpoint, e.g. when it logs out or is moved by the server
+func (v *MovementValidator) Forget(ctx context.Context, userID string) {
+	if err := v.checkpoints.Delete(ctx, trainer.UserID(userID)); err != nil {
+		v.logger.Warn("Failed to drop movement checkpoint",
+			zap.String("userId", userID),
+			zap.Error(err))
+	}
+}
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// memoryCheckpoints keeps movement checkpoints in a map, standing in for the Redis keys
+// every server instance shares. err makes every call fail.
+type memoryCheckpoints struct {
+	checkpoints map[trainer.UserID]trainer.MovementCheckpoint
+	err         error
+}
+
+func (m *memoryCheckpoints) FindOneAndUpsert(ctx context.Context, id trainer.UserID, ttl time.Duration, callback func(*trainer.MovementCheckpoint) (*trainer.MovementCheckpoint, error)) error {
+	if m.err != nil {
+		return m.err
+	}
+
+	var current *trainer.MovementCheckpoint
+	if checkpoint, ok := m.checkpoints[id]; ok {
+		current = &checkpoint
+	}
+	updated, err := callback(current)
+	if err != nil || updated == nil {
+		return err
+	}
+	m.checkpoints[id] = *updated
+	return nil
+}
+
+func (m *memoryCheckpoints) Delete(ctx context.Context, id trainer.UserID) error {
+	delete(m.checkpoints, id)
+	return m.err
+}
+
+func TestMovementValidator_Validate(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	origin := shared.NewPosition(10, 10)
+
+	tests := []struct {
+		name       string
+		checkpoint *trainer.MovementCheckpoint
+		storeErr   error
+		elapsed    time.Duration
+		position   shared.Position
+		code       int // Expected domain error code, 0 when accepted
+		corrected  bool
+		kept       shared.Position // Checkpoint position afterwards
+	}{
+		{
+			name:     "first command",
+			elapsed:  0,
+			position: origin,
+			kept:     origin,
+		},
+		{
+			name:       "walked within the speed limit",
+			checkpoint: &trainer.MovementCheckpoint{At: start, Position: origin},
+			elapsed:    time.Second,
+			position:   shared.NewPosition(10+trainer.DefaultSpeed, 10),
+			kept:       shared.NewPosition(10+trainer.DefaultSpeed, 10),
+		},
+		{
+			name:       "sent too soon",
+			checkpoint: &trainer.MovementCheckpoint{At: start, Position: origin},
+			elapsed:    10 * time.Millisecond,
+			position:   origin,
+			code:       shared.ErrCodeMoveThrottled,
+			kept:       origin,
+		},
+		{
+			name:       "teleported",
+			checkpoint: &trainer.MovementCheckpoint{At: start, Position: origin},
+			elapsed:    time.Second,
+			position:   shared.NewPosition(60, 10),
+			code:       shared.ErrCodeMoveRejected,
+			corrected:  true,
+			kept:       origin,
+		},
+		{
+			name:       "checked at the same moment on another instance",
+			checkpoint: &trainer.MovementCheckpoint{At: start, Position: origin},
+			storeErr:   redis.TxFailedErr,
+			elapsed:    time.Second,
+			position:   origin,
+			code:       shared.ErrCodeMoveThrottled,
+			kept:       origin,
+		},
+		{
+			name:       "Redis unreachable",
+			checkpoint: &trainer.MovementCheckpoint{At: start, Position: origin},
+			storeErr:   errors.New("connection refused"),
+			elapsed:    10 * time.Millisecond,
+			position:   shared.NewPosition(60, 10),
+			kept:       origin,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkpoints := &memoryCheckpoints{checkpoints: map[trainer.UserID]trainer.MovementCheckpoint{}, err: tt.storeErr}
+			if tt.checkpoint != nil {
+				checkpoints.checkpoints["mover"] = *tt.checkpoint
+			}
+			v := NewMovementValidator(logger.NewDefault(), DefaultMovementRules(), checkpoints)
+
+			tr, err := trainer.NewTrainer("mover", "Mover")
+			require.NoError(t, err)
+			tr.Position = tt.position
+
+			corrected, err := v.Validate(context.Background(), "mover", tr, start.Add(tt.elapsed))
+			if tt.code == 0 {
+				assert.NoError(t, err)
+			} else {
+				code, ok := shared.DomainErrorCode(err)
+				require.True(t, ok, "expected a domain error, got %v", err)
+				assert.Equal(t, tt.code, code)
+			}
+			assert.Equal(t, tt.corrected, corrected)
+			if tt.corrected {
+				assert.Equal(t, tt.kept, tr.Position, "teleported trainers are put back")
+			}
+			assert.Equal(t, tt.kept, checkpoints.checkpoints["mover"].Position)
+		})
+	}
+}
+
+func TestMovementValidator_Forget(t *testing.T) {
+	checkpoints := &memoryCheckpoints{checkpoints: map[trainer.UserID]trainer.MovementCheckpoint{}}
+	v := NewMovementValidator(logger.NewDefault(), DefaultMovementRules(), checkpoints)
+	tr, err := trainer.NewTrainer("mover", "Mover")
+	require.NoError(t, err)
+	now := time.Now()
+
+	_, err = v.Validate(context.Background(), "mover", tr, now)
+	require.NoError(t, err)
+
+	// A respawn on another instance jumps the trainer across the map
+	v.Forget(context.Background(), "mover")
+	tr.Position = shared.NewPosition(500, 500)
+	corrected, err := v.Validate(context.Background(), "mover", tr, now.Add(time.Second))
+	assert.NoError(t, err)
+	assert.False(t, corrected)
+}
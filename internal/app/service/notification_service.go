@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/notification"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// NotificationService reads and changes players' notification preferences. They are
+// enforced where notifications are sent, by the notification gateway.
+type NotificationService struct {
+	logger          *logger.Logger
+	preferencesRepo notification.Repository
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(logger *logger.Logger, preferencesRepo notification.Repository) *NotificationService {
+	return &NotificationService{
+		logger:          logger.WithComponent("notification-service"),
+		preferencesRepo: preferencesRepo,
+	}
+}
+
+// Preferences returns a player's preferences for every category and channel
+func (s *NotificationService) Preferences(ctx context.Context, userID string) (notification.Preferences, error) {
+	return s.preferencesRepo.Get(ctx, userID)
+}
+
+// UpdatePreferences turns the listed channels on and off, returning the resulting preferences
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID string, changes notification.Preferences) (notification.Preferences, error) {
+	var updated notification.Preferences
+	err := s.preferencesRepo.FindOneAndUpdate(ctx, userID, func(preferences notification.Preferences) error {
+		if err := preferences.Apply(changes); err != nil {
+			return err
+		}
+		updated = preferences
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Notification preferences updated",
+		zap.String("userId", userID),
+		zap.Any("changes", changes))
+	return updated, nil
+}
@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+const (
+	// outboxRelayGroup is the consumer group the relays of every server instance share, so
+	// each outbox entry is published by one of them
+	outboxRelayGroup = "outbox-relay"
+	// outboxRelayBatch is how many entries a relay reads at once
+	outboxRelayBatch = 100
+	// outboxRelayBlock is how long a read waits for new entries
+	outboxRelayBlock = time.Second
+	// outboxClaimIdle is how long an entry stays unpublished before another relay takes it
+	// over, e.g. after the instance reading it stopped or its publish failed
+	outboxClaimIdle = 30 * time.Second
+)
+
+// OutboxRelay publishes the events recorded in the outbox through Watermill. An entry is only
+// removed once it was published, and entries left pending are retried, so every recorded
+// event is delivered at least once; consumers recognize redeliveries by the message UUID.
+type OutboxRelay struct {
+	logger      *logger.Logger
+	redisClient *redis.Client
+	publisher   message.Publisher
+	consumer    string
+	stopChan    chan struct{}
+}
+
+// NewOutboxRelay creates a relay reading the outbox as consumer, which names the server
+// instance in the relays' consumer group
+func NewOutboxRelay(logger *logger.Logger, redisClient *redis.Client, publisher message.Publisher, consumer string) *OutboxRelay {
+	return &OutboxRelay{
+		logger:      logger.WithComponent("outbox-relay"),
+		redisClient: redisClient,
+		publisher:   publisher,
+		consumer:    consumer,
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start begins relaying the outbox
+func (r *OutboxRelay) Start(ctx context.Context) {
+	err := r.redisClient.XGroupCreateMkStream(ctx, cqrscommands.OutboxStream, outboxRelayGroup, "0").Err()
+	if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		r.logger.Error("Failed to create outbox consumer group", zap.Error(err))
+		return
+	}
+
+	r.logger.Info("Starting outbox relay", zap.String("consumer", r.consumer))
+
+	go r.relayLoop(ctx)
+}
+
+// Stop stops relaying the outbox; entries being read stay pending for the next relay
+func (r *OutboxRelay) Stop() {
+	r.logger.Info("Stopping outbox relay")
+
+	close(r.stopChan)
+}
+
+// relayLoop takes over abandoned entries and publishes new ones until stopped
+func (r *OutboxRelay) relayLoop(ctx context.Context) {
+	claimTicker := time.NewTicker(outboxClaimIdle)
+	defer claimTicker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.stopChan:
+			return
+		case <-claimTicker.C:
+			r.claimAbandoned(ctx)
+		default:
+		}
+
+		streams, err := r.redisClient.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    outboxRelayGroup,
+			Consumer: r.consumer,
+			Streams:  []string{cqrscommands.OutboxStream, ">"},
+			Count:    outboxRelayBatch,
+			Block:    outboxRelayBlock,
+		}).Result()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			r.logger.Error("Failed to read outbox", zap.Error(err))
+			r.wait(ctx, outboxRelayBlock)
+			continue
+		}
+
+		for _, stream := range streams {
+			r.relay(ctx, stream.Messages)
+		}
+	}
+}
+
+// claimAbandoned publishes the entries pending longer than outboxClaimIdle
+func (r *OutboxRelay) claimAbandoned(ctx context.Context) {
+	start := "0-0"
+	for {
+		entries, next, err := r.redisClient.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   cqrscommands.OutboxStream,
+			Group:    outboxRelayGroup,
+			Consumer: r.consumer,
+			MinIdle:  outboxClaimIdle,
+			Start:    start,
+			Count:    outboxRelayBatch,
+		}).Result()
+		if err != nil {
+			r.logger.Error("Failed to claim abandoned outbox entries", zap.Error(err))
+			return
+		}
+
+		r.relay(ctx, entries)
+
+		if next == "0-0" {
+			return
+		}
+		start = next
+	}
+}
+
+// relay publishes entries in order and removes the published ones. An entry failing to
+// publish stays pending, to be claimed again once idle.
+func (r *OutboxRelay) relay(ctx context.Context, entries []redis.XMessage) {
+	for _, entry := range entries {
+		topic, msg, err := cqrscommands.OutboxMessage(entry)
+		if err != nil {
+			// It would fail the same way on every retry
+			r.logger.Error("Dropping invalid outbox entry", zap.String("entryId", entry.ID), zap.Error(err))
+			r.remove(ctx, entry.ID)
+			continue
+		}
+
+		msg.SetContext(ctx)
+		if err := r.publisher.Publish(topic, msg); err != nil {
+			r.logger.Warn("Failed to publish outbox entry, retrying later",
+				zap.String("entryId", entry.ID),
+				zap.String("topic", topic),
+				zap.Error(err))
+			continue
+		}
+		r.remove(ctx, entry.ID)
+	}
+}
+
+// remove acknowledges an entry and deletes it from the outbox
+func (r *OutboxRelay) remove(ctx context.Context, id string) {
+	pipe := r.redisClient.TxPipeline()
+	pipe.XAck(ctx, cqrscommands.OutboxStream, outboxRelayGroup, id)
+	pipe.XDel(ctx, cqrscommands.OutboxStream, id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		r.logger.Warn("Failed to remove published outbox entry", zap.String("entryId", id), zap.Error(err))
+	}
+}
+
+// wait pauses the loop after a failed read
+func (r *OutboxRelay) wait(ctx context.Context, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+	case <-r.stopChan:
+	case <-timer.C:
+	}
+}
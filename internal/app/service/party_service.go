@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// PartyService moves the trainer's animals between their party and storage. The trainer's
+// party and the animals' states live in separate repositories, so each move updates the
+// animals first and puts them back when the party update fails.
+type PartyService struct {
+	logger      *logger.Logger
+	trainerRepo trainer.Repository
+	animalRepo  animal.Repository
+}
+
+// NewPartyService creates a new party service
+func NewPartyService(logger *logger.Logger, trainerRepo trainer.Repository, animalRepo animal.Repository) *PartyService {
+	return &PartyService{
+		logger:      logger.WithComponent("party-service"),
+		trainerRepo: trainerRepo,
+		animalRepo:  animalRepo,
+	}
+}
+
+// Add moves an animal from storage into the party
+func (s *PartyService) Add(ctx context.Context, trainerID trainer.UserID, animalID animal.AnimalID) (trainer.AnimalParty, error) {
+	if err := s.moveAnimal(ctx, trainerID, animalID, animal.InStorage, animal.InParty); err != nil {
+		return trainer.AnimalParty{}, err
+	}
+
+	party, err := s.updateParty(ctx, trainerID, func(t *trainer.Trainer) error {
+		return t.AddAnimalToParty(shared.ID(animalID))
+	})
+	if err != nil {
+		s.restore(ctx, animalID, animal.InStorage)
+		return trainer.AnimalParty{}, err
+	}
+
+	s.logger.WithContext(ctx).Info("Animal added to party",
+		zap.String("userID", trainerID.String()),
+		zap.String("animalID", animalID.String()))
+
+	return party, nil
+}
+
+// Remove moves a party member into storage
+func (s *PartyService) Remove(ctx context.Context, trainerID trainer.UserID, animalID animal.AnimalID) (trainer.AnimalParty, error) {
+	if err := s.moveAnimal(ctx, trainerID, animalID, animal.InParty, animal.InStorage); err != nil {
+		return trainer.AnimalParty{}, err
+	}
+
+	party, err := s.updateParty(ctx, trainerID, func(t *trainer.Trainer) error {
+		return t.RemoveAnimalFromParty(shared.ID(animalID))
+	})
+	if err != nil {
+		s.restore(ctx, animalID, animal.InParty)
+		return trainer.AnimalParty{}, err
+	}
+
+	s.logger.WithContext(ctx).Info("Animal removed from party",
+		zap.String("userID", trainerID.String()),
+		zap.String("animalID", animalID.String()))
+
+	return party, nil
+}
+
+// Swap moves a party member into storage and a stored animal into its party slot, which
+// works even when the party is full
+func (s *PartyService) Swap(ctx context.Context, trainerID trainer.UserID, outID, inID animal.AnimalID) (trainer.AnimalParty, error) {
+	if outID == inID {
+		return trainer.AnimalParty{}, shared.ErrInvalidInput("Cannot swap an animal with itself")
+	}
+
+	if err := s.moveAnimal(ctx, trainerID, inID, animal.InStorage, animal.InParty); err != nil {
+		return trainer.AnimalParty{}, err
+	}
+	if err := s.moveAnimal(ctx, trainerID, outID, animal.InParty, animal.InStorage); err != nil {
+		s.restore(ctx, inID, animal.InStorage)
+		return trainer.AnimalParty{}, err
+	}
+
+	party, err := s.updateParty(ctx, trainerID, func(t *trainer.Trainer) error {
+		return t.SwapPartyAnimal(shared.ID(outID), shared.ID(inID))
+	})
+	if err != nil {
+		s.restore(ctx, inID, animal.InStorage)
+		s.restore(ctx, outID, animal.InParty)
+		return trainer.AnimalParty{}, err
+	}
+
+	s.logger.WithContext(ctx).Info("Party animals swapped",
+		zap.String("userID", trainerID.String()),
+		zap.String("outID", outID.String()),
+		zap.String("inID", inID.String()))
+
+	return party, nil
+}
+
+// moveAnimal changes one of the trainer's animals from one placement to the other
+func (s *PartyService) moveAnimal(ctx context.Context, trainerID trainer.UserID, animalID animal.AnimalID, from, to animal.AnimalState) error {
+	return s.animalRepo.FindOneAndUpdate(ctx, animalID, func(a *animal.Animal) (*animal.Animal, error) {
+		if a.OwnerID != shared.ID(trainerID) {
+			return nil, shared.NewDomainError(shared.ErrCodeNotCaptured, "Animal is not owned by this trainer")
+		}
+
+		if a.State != from {
+			switch {
+			case a.State == animal.InParty:
+				return nil, shared.NewDomainError(shared.ErrCodeAnimalAlreadyInParty, "Animal is already in party")
+			case from == animal.InParty:
+				return nil, shared.NewDomainError(shared.ErrCodeAnimalNotInParty, "Animal is not in party")
+			default:
+				return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidState, "Animal is %s, not in storage", a.State)
+			}
+		}
+
+		if err := a.ChangeState(to); err != nil {
+			return nil, err
+		}
+		return a, nil
+	})
+}
+
+// updateParty applies a change to the trainer's party and returns the updated party
+func (s *PartyService) updateParty(ctx context.Context, trainerID trainer.UserID, change func(t *trainer.Trainer) error) (trainer.AnimalParty, error) {
+	var party trainer.AnimalParty
+	err := s.trainerRepo.FindOneAndUpdate(ctx, trainerID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		if err := change(t); err != nil {
+			return nil, err
+		}
+		party = t.Party
+		return t, nil
+	})
+	return party, err
+}
+
+// restore puts an animal back in the placement it had before a failed move. A failure leaves
+// the animal's state out of step with the party and is logged.
+func (s *PartyService) restore(ctx context.Context, animalID animal.AnimalID, state animal.AnimalState) {
+	err := s.animalRepo.FindOneAndUpdate(ctx, animalID, func(a *animal.Animal) (*animal.Animal, error) {
+		if err := a.ChangeState(state); err != nil {
+			return nil, err
+		}
+		return a, nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to restore animal placement",
+			zap.String("animalID", animalID.String()),
+			zap.String("state", string(state)),
+			zap.Error(err))
+	}
+}
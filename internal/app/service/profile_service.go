@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// ProfileService builds trainer profiles for other players and manages their visibility
+type ProfileService struct {
+	logger      *logger.Logger
+	trainerRepo trainer.Repository
+	animalRepo  animal.Repository
+}
+
+// NewProfileService creates a new profile service
+func NewProfileService(logger *logger.Logger, trainerRepo trainer.Repository, animalRepo animal.Repository) *ProfileService {
+	return &ProfileService{
+		logger:      logger.WithComponent("profile-service"),
+		trainerRepo: trainerRepo,
+		animalRepo:  animalRepo,
+	}
+}
+
+// PublicProfile returns the privacy-filtered profile of the trainer with a nickname
+func (s *ProfileService) PublicProfile(ctx context.Context, nickname string) (*trainer.PublicProfile, error) {
+	t, err := s.trainerRepo.FindByNickname(ctx, nickname)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, shared.ErrNotFound("Trainer")
+	}
+
+	if !t.Profile.IsVisible(trainer.ProfileFieldAnimals) {
+		return t.PublicProfile(nil, 0), nil
+	}
+
+	owned, err := s.animalRepo.GetByOwner(ctx, shared.ID(t.ID))
+	if err != nil {
+		return nil, err
+	}
+
+	// Pinned snapshots can be out of date, so the profile shows the animals as they are now
+	showcase := make([]trainer.ShowcasedAnimal, 0, len(t.Profile.Showcase))
+	for _, pinned := range t.Profile.Showcase {
+		a, err := s.animalRepo.GetByID(ctx, animal.AnimalID(pinned.ID))
+		if err != nil {
+			return nil, err
+		}
+		// Showcased animals may have been released or traded since they were pinned
+		if a == nil || a.OwnerID != shared.ID(t.ID) {
+			continue
+		}
+		showcase = append(showcase, showcasedAnimal(a))
+	}
+
+	return t.PublicProfile(showcase, len(owned)), nil
+}
+
+// UpdateProfileSettings changes which profile fields are hidden and which animals are showcased
+func (s *ProfileService) UpdateProfileSettings(ctx context.Context, userID trainer.UserID, hidden []trainer.ProfileField, showcase []string) (*trainer.ProfileSettings, error) {
+	pinned := make([]trainer.ShowcasedAnimal, 0, len(showcase))
+	for _, animalID := range showcase {
+		a, err := s.ownedAnimal(ctx, userID, animalID)
+		if err != nil {
+			return nil, err
+		}
+		pinned = append(pinned, showcasedAnimal(a))
+	}
+
+	settings, err := s.updateProfile(ctx, userID, func(t *trainer.Trainer) error {
+		return t.UpdateProfileSettings(hidden, pinned)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Profile settings updated",
+		zap.String("userID", userID.String()),
+		zap.Int("hidden", len(settings.HiddenFields)),
+		zap.Int("showcase", len(settings.Showcase)))
+
+	return settings, nil
+}
+
+// PinAnimal adds one of the trainer's animals to their showcase
+func (s *ProfileService) PinAnimal(ctx context.Context, userID trainer.UserID, animalID string) (*trainer.ProfileSettings, error) {
+	a, err := s.ownedAnimal(ctx, userID, animalID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.updateProfile(ctx, userID, func(t *trainer.Trainer) error {
+		return t.PinAnimal(showcasedAnimal(a))
+	})
+}
+
+// UnpinAnimal removes an animal from the trainer's showcase
+func (s *ProfileService) UnpinAnimal(ctx context.Context, userID trainer.UserID, animalID string) (*trainer.ProfileSettings, error) {
+	return s.updateProfile(ctx, userID, func(t *trainer.Trainer) error {
+		if !t.UnpinAnimal(animalID) {
+			return shared.NewDomainError(shared.ErrCodeInvalidInput, "Animal is not showcased")
+		}
+		return nil
+	})
+}
+
+// updateProfile applies a change to the trainer's profile settings and returns the result
+func (s *ProfileService) updateProfile(ctx context.Context, userID trainer.UserID, change func(t *trainer.Trainer) error) (*trainer.ProfileSettings, error) {
+	var settings trainer.ProfileSettings
+	err := s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		if err := change(t); err != nil {
+			return nil, err
+		}
+		settings = t.Profile
+		return t, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &settings, nil
+}
+
+// ownedAnimal loads an animal, checking that it belongs to the trainer
+func (s *ProfileService) ownedAnimal(ctx context.Context, userID trainer.UserID, animalID string) (*animal.Animal, error) {
+	a, err := s.animalRepo.GetByID(ctx, animal.AnimalID(animalID))
+	if err != nil {
+		return nil, err
+	}
+	if a == nil || a.OwnerID != shared.ID(userID) {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeNotCaptured, "Animal %s is not owned by this trainer", animalID)
+	}
+	return a, nil
+}
+
+// showcasedAnimal returns the public view of an animal
+func showcasedAnimal(a *animal.Animal) trainer.ShowcasedAnimal {
+	return trainer.ShowcasedAnimal{
+		ID:    a.ID.String(),
+		Type:  a.AnimalType.String(),
+		Level: a.Level.Value(),
+	}
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/fairness"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// Upper bound on rolls returned by a history request
+const maxRollHistory = 100
+
+// RandomnessService is the single source of game randomness. Every roll draws from the
+// current committed server seed and is recorded so its outcome can be audited and verified.
+type RandomnessService struct {
+	logger *logger.Logger
+	repo   fairness.Repository
+}
+
+// NewRandomnessService creates a new randomness service
+func NewRandomnessService(logger *logger.Logger, repo fairness.Repository) *RandomnessService {
+	return &RandomnessService{
+		logger: logger.WithComponent("randomness-service"),
+		repo:   repo,
+	}
+}
+
+// Begin starts a roll for a user against the current seed. Rolls about a specific entity
+// draw from that entity's stream so they can be reproduced by re-simulation. The caller
+// draws from session.Rand() and passes the outcome to Record.
+func (s *RandomnessService) Begin(ctx context.Context, purpose fairness.Purpose, userID string, entityID string, inputs map[string]any) (*fairness.Session, error) {
+	now := time.Now()
+
+	seed, err := s.currentSeed(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return fairness.NewSession(seed, purpose, userID, entityID, inputs, now), nil
+}
+
+// Record stores the audit record of a finished roll. The outcome has already been applied,
+// so a failure to record is logged rather than returned.
+func (s *RandomnessService) Record(ctx context.Context, session *fairness.Session, output any) {
+	roll, err := session.Finish(output)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to encode roll outcome",
+			zap.String("rollID", session.ID().String()),
+			zap.Error(err))
+		return
+	}
+
+	if err := s.repo.SaveRoll(ctx, roll); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to save roll",
+			zap.String("rollID", roll.ID.String()),
+			zap.Error(err))
+	}
+
+	s.logger.WithContext(ctx).Info("Roll",
+		zap.String("rollID", roll.ID.String()),
+		zap.String("purpose", roll.Purpose.String()),
+		zap.String("userID", roll.UserID),
+		zap.String("seedID", roll.SeedID.String()),
+		zap.String("stream", roll.Stream),
+		zap.Any("inputs", roll.Inputs),
+		zap.Strings("draws", roll.Draws),
+		zap.ByteString("output", roll.Output))
+}
+
+// Commitments returns the commitment of the seed in use and the previous, revealed seed
+func (s *RandomnessService) Commitments(ctx context.Context) (*fairness.Commitments, error) {
+	now := time.Now()
+
+	current, err := s.currentSeed(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &fairness.Commitments{Current: current.Public(now)}
+
+	previous, err := s.repo.GetSeed(ctx, fairness.SeedIDAt(now.Add(-fairness.SeedPeriod)))
+	if err != nil {
+		return nil, err
+	}
+	if previous != nil {
+		result.Previous = previous.Public(now)
+	}
+
+	return result, nil
+}
+
+// History returns a user's most recent rolls
+func (s *RandomnessService) History(ctx context.Context, userID string, limit int) ([]*fairness.Roll, error) {
+	if limit <= 0 || limit > maxRollHistory {
+		limit = maxRollHistory
+	}
+	return s.repo.GetRollsByUser(ctx, userID, limit)
+}
+
+// Verify checks one of the user's rolls against its seed. Rolls made with a seed that
+// is still in use cannot be verified until the seed is revealed.
+func (s *RandomnessService) Verify(ctx context.Context, userID string, rollID fairness.RollID) (*fairness.Verification, error) {
+	roll, err := s.repo.GetRoll(ctx, rollID)
+	if err != nil {
+		return nil, err
+	}
+	if roll == nil || roll.UserID != userID {
+		return nil, shared.ErrNotFound("roll")
+	}
+
+	seed, err := s.repo.GetSeed(ctx, roll.SeedID)
+	if err != nil {
+		return nil, err
+	}
+	if seed == nil {
+		return nil, shared.ErrNotFound("seed")
+	}
+
+	now := time.Now()
+	result := &fairness.Verification{
+		Roll:     roll,
+		Seed:     seed.Public(now),
+		Revealed: seed.IsRevealed(now),
+	}
+
+	if !result.Revealed {
+		result.Reason = "Seed has not been revealed yet"
+		return result, nil
+	}
+
+	if err := fairness.Verify(roll, seed); err != nil {
+		result.Reason = err.Error()
+		return result, nil
+	}
+
+	result.Verified = true
+	return result, nil
+}
+
+// currentSeed returns the seed for the current period, creating it on first use
+func (s *RandomnessService) currentSeed(ctx context.Context, now time.Time) (*fairness.Seed, error) {
+	id := fairness.SeedIDAt(now)
+	return s.repo.FindOrCreateSeed(ctx, id, func() (*fairness.Seed, error) {
+		return fairness.NewSeed(id)
+	})
+}
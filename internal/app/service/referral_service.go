@@ -0,0 +1,254 @@
+package service
+
+import (
+	"context"
+	"net/url"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/referral"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// ReferralService manages invite codes, attributes signups to referrers and pays milestone rewards
+type ReferralService struct {
+	logger        *logger.Logger
+	referralRepo  referral.Repository
+	trainerRepo   trainer.Repository
+	inviteBaseURL string
+}
+
+// NewReferralService creates a new referral service. Invite links point at inviteBaseURL
+// with the code in the ref query parameter.
+func NewReferralService(logger *logger.Logger, referralRepo referral.Repository, trainerRepo trainer.Repository, inviteBaseURL string) *ReferralService {
+	return &ReferralService{
+		logger:        logger.WithComponent("referral-service"),
+		referralRepo:  referralRepo,
+		trainerRepo:   trainerRepo,
+		inviteBaseURL: inviteBaseURL,
+	}
+}
+
+// Invite returns the user's referral code and invitation link, creating the code on first use
+func (s *ReferralService) Invite(ctx context.Context, userID string) (*referral.Invite, error) {
+	code, err := s.referralRepo.GetOrCreateCode(ctx, userID, referral.NewCode)
+	if err != nil {
+		return nil, err
+	}
+
+	baseURL := s.inviteBaseURL
+	if t, ok := tenant.FromContext(ctx); ok && t.Balance.InviteBaseURL != "" {
+		baseURL = t.Balance.InviteBaseURL
+	}
+	link, err := url.Parse(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	query := link.Query()
+	query.Set("ref", code.String())
+	link.RawQuery = query.Encode()
+
+	return &referral.Invite{Code: code, Link: link.String()}, nil
+}
+
+// Attribute credits a new player's signup to the owner of a referral code. Signups from a
+// device or network the referrer uses, or that already backed another referral, are rejected.
+func (s *ReferralService) Attribute(ctx context.Context, referredID, rawCode string, fingerprint referral.Fingerprint) error {
+	code, err := referral.ParseCode(rawCode)
+	if err != nil {
+		return err
+	}
+
+	referrerID, err := s.referralRepo.GetReferrer(ctx, code)
+	if err != nil {
+		return err
+	}
+	if referrerID == "" {
+		return shared.NewDomainError(shared.ErrCodeInvalidReferralCode, "Unknown referral code")
+	}
+
+	selfReferral, err := s.referralRepo.SharesFingerprint(ctx, referrerID, fingerprint.Hashes())
+	if err != nil {
+		return err
+	}
+	if selfReferral {
+		s.logger.WithContext(ctx).Warn("Rejected referral from the referrer's own device or network",
+			zap.String("referrerID", referrerID),
+			zap.String("referredID", referredID))
+		return shared.NewDomainError(shared.ErrCodeReferralAbuse, "Referrals from the referrer's own device or network are not allowed")
+	}
+
+	err = s.referralRepo.FindOneAndInsert(ctx, referredID, func() (*referral.Referral, error) {
+		return referral.NewReferral(referrerID, referredID, code, fingerprint)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.logger.WithContext(ctx).Info("Signup attributed to referrer",
+		zap.String("referrerID", referrerID),
+		zap.String("referredID", referredID))
+
+	return nil
+}
+
+// RecordLogin remembers where the user logs in from, so they cannot refer themselves later
+func (s *ReferralService) RecordLogin(ctx context.Context, userID string, fingerprint referral.Fingerprint) {
+	if err := s.referralRepo.RecordFingerprint(ctx, userID, fingerprint.Hashes()); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to record login fingerprint",
+			zap.String("userID", userID),
+			zap.Error(err))
+	}
+}
+
+// Summary returns the user's invite, who referred them and the players they brought in
+func (s *ReferralService) Summary(ctx context.Context, userID string) (*referral.Summary, error) {
+	invite, err := s.Invite(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &referral.Summary{
+		Invite:     *invite,
+		Referrals:  make([]referral.ReferredPlayer, 0),
+		Milestones: referral.Milestones,
+	}
+
+	own, err := s.referralRepo.GetByReferred(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if own != nil {
+		referrer, err := s.trainerRepo.GetByID(ctx, trainer.UserID(own.ReferrerID))
+		if err != nil {
+			return nil, err
+		}
+		if referrer != nil {
+			summary.ReferredBy = referrer.PublicNameplate()
+		}
+	}
+
+	referrals, err := s.referralRepo.GetByReferrer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range referrals {
+		referred, err := s.trainerRepo.GetByID(ctx, trainer.UserID(ref.ReferredID))
+		if err != nil {
+			return nil, err
+		}
+		if referred == nil {
+			continue // Signed up but has not created a trainer yet
+		}
+
+		summary.Referrals = append(summary.Referrals, referral.ReferredPlayer{
+			Profile:       referred.PublicNameplate(),
+			ClaimedLevels: ref.ClaimedLevels,
+			JoinedAt:      ref.CreatedAt,
+		})
+	}
+
+	return summary, nil
+}
+
+// Claim pays out every milestone reached by the user's referrals and by the user themselves.
+// Both sides are paid whoever claims; the returned rewards are the user's own share.
+func (s *ReferralService) Claim(ctx context.Context, userID string) ([]referral.Reward, error) {
+	referrals, err := s.referralRepo.GetByReferrer(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	own, err := s.referralRepo.GetByReferred(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if own != nil {
+		referrals = append(referrals, own)
+	}
+
+	rewards := make([]referral.Reward, 0)
+	for _, ref := range referrals {
+		paid, err := s.claimReferral(ctx, ref.ReferredID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, reward := range paid {
+			if (reward.Role == referral.RoleReferrer) == (ref.ReferrerID == userID) {
+				rewards = append(rewards, reward)
+			}
+		}
+	}
+
+	return rewards, nil
+}
+
+// claimReferral marks the milestones the referred trainer has reached as claimed, then pays both sides
+func (s *ReferralService) claimReferral(ctx context.Context, referredID string) ([]referral.Reward, error) {
+	referred, err := s.trainerRepo.GetByID(ctx, trainer.UserID(referredID))
+	if err != nil {
+		return nil, err
+	}
+	if referred == nil {
+		return nil, nil
+	}
+
+	var (
+		referrerID string
+		reached    []referral.Milestone
+	)
+	err = s.referralRepo.FindOneAndUpdate(ctx, referredID, func(ref *referral.Referral) (*referral.Referral, error) {
+		referrerID = ref.ReferrerID
+		reached = ref.Claim(referred.Level.Value())
+		if len(reached) == 0 {
+			return nil, nil // Nothing new
+		}
+		return ref, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Milestones are marked first so a retry never pays twice
+	rewards := make([]referral.Reward, 0, len(reached)*2)
+	for _, m := range reached {
+		for _, payout := range []struct {
+			userID string
+			role   referral.Role
+			money  int
+		}{
+			{referrerID, referral.RoleReferrer, m.ReferrerMoney},
+			{referredID, referral.RoleReferred, m.ReferredMoney},
+		} {
+			err := s.trainerRepo.FindOneAndUpdate(ctx, trainer.UserID(payout.userID), func(t *trainer.Trainer) (*trainer.Trainer, error) {
+				return t, t.EarnMoney(payout.money)
+			})
+			if err != nil {
+				s.logger.WithContext(ctx).Error("Failed to pay referral reward",
+					zap.String("userID", payout.userID),
+					zap.String("role", string(payout.role)),
+					zap.Int("level", m.Level),
+					zap.Error(err))
+				continue
+			}
+
+			rewards = append(rewards, referral.Reward{
+				Role:     payout.role,
+				Nickname: referred.Nickname,
+				Level:    m.Level,
+				Money:    payout.money,
+			})
+		}
+
+		s.logger.WithContext(ctx).Info("Referral milestone rewarded",
+			zap.String("referrerID", referrerID),
+			zap.String("referredID", referredID),
+			zap.Int("level", m.Level))
+	}
+
+	return rewards, nil
+}
@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/hibiken/asynq"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/vault"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// TypeTrainerRespawn is the asynq task type that brings a dead trainer back
+const TypeTrainerRespawn = "trainer:respawn"
+
+// trainerRespawnPayload is the asynq payload for TypeTrainerRespawn
+type trainerRespawnPayload struct {
+	UserID string `json:"user_id"`
+}
+
+// RespawnConfig sets what dying costs a trainer
+type RespawnConfig struct {
+	MoneyPenalty int           `json:"money_penalty"` // Money lost on death, at most what the trainer carries
+	Delay        time.Duration `json:"delay"`         // Time a dead trainer waits before it respawns
+}
+
+// RespawnService deals damage to trainers and handles their deaths: a trainer whose HP runs
+// out drops money and part of its carried items where it fell, and comes back at full health
+// at the spawn point nearest to it once the respawn delay has passed
+type RespawnService struct {
+	logger       *logger.Logger
+	config       RespawnConfig
+	spawnPoints  []vault.Location
+	trainerRepo  trainer.Repository
+	movement     *TenantMovement
+	validator    *MovementValidator
+	vaultService *VaultService
+	terrain      trainer.Terrain
+	taskClient   *asynq.Client
+	eventBus     *cqrs.EventBus
+}
+
+// NewRespawnService creates a new respawn service bringing trainers back at spawnPoints
+func NewRespawnService(
+	logger *logger.Logger,
+	config RespawnConfig,
+	spawnPoints []vault.Location,
+	trainerRepo trainer.Repository,
+	movement *TenantMovement,
+	validator *MovementValidator,
+	vaultService *VaultService,
+	terrain trainer.Terrain,
+	taskClient *asynq.Client,
+	eventBus *cqrs.EventBus,
+) *RespawnService {
+	return &RespawnService{
+		logger:       logger.WithComponent("respawn-service"),
+		config:       config,
+		spawnPoints:  spawnPoints,
+		trainerRepo:  trainerRepo,
+		movement:     movement,
+		validator:    validator,
+		vaultService: vaultService,
+		terrain:      terrain,
+		taskClient:   taskClient,
+		eventBus:     eventBus,
+	}
+}
+
+// Damage deals damage to a trainer and handles its death when its HP runs out. The result
+// reports whether the trainer died; trainers already dead take no more damage.
+func (s *RespawnService) Damage(ctx context.Context, userID trainer.UserID, damage int, cause trainer.DeathCause, sourceID string) (bool, error) {
+	var death *trainer.Death
+	err := s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		death = nil
+		if t.IsDead() {
+			return nil, nil
+		}
+
+		died, err := t.TakeDamage(damage)
+		if err != nil {
+			return nil, err
+		}
+		if died {
+			death = t.Die(cause, sourceID, s.config.MoneyPenalty, s.config.Delay, time.Now())
+		}
+		return t, nil
+	})
+	if err != nil || death == nil {
+		return false, err
+	}
+
+	s.died(ctx, userID, death)
+	return true, nil
+}
+
+// died stops a trainer that just died where it fell, drops part of its carried items, schedules
+// its respawn and tells the players around
+func (s *RespawnService) died(ctx context.Context, userID trainer.UserID, death *trainer.Death) {
+	fallen, err := s.movement.Move(ctx, userID.String(), func(t *trainer.Trainer) error {
+		return t.StopMovementWithin(s.terrain)
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to stop dead trainer",
+			zap.String("userID", userID.String()),
+			zap.Error(err))
+		return
+	}
+
+	var lostIDs []string
+	lost, err := s.vaultService.ApplyDeathLoss(ctx, userID)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to apply death loss",
+			zap.String("userID", userID.String()),
+			zap.Error(err))
+	}
+	for _, item := range lost {
+		lostIDs = append(lostIDs, item.ID.String())
+	}
+
+	s.scheduleRespawn(ctx, userID, death)
+
+	event := &cqrscommands.TrainerDiedEvent{
+		UserID:    userID.String(),
+		Nickname:  fallen.Nickname,
+		Position:  fallen.Position,
+		Cause:     death.Cause,
+		SourceID:  death.SourceID,
+		MoneyLost: death.MoneyLost,
+		ItemsLost: lostIDs,
+		RespawnAt: death.RespawnAt,
+		Timestamp: time.Now(),
+		RequestID: requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish trainer died event",
+			zap.String("userID", userID.String()),
+			zap.Error(err))
+	}
+
+	s.logger.WithContext(ctx).Info("Trainer died",
+		zap.String("userID", userID.String()),
+		zap.String("cause", death.Cause.String()),
+		zap.String("sourceID", death.SourceID),
+		zap.Int("moneyLost", death.MoneyLost),
+		zap.Int("itemsLost", len(lostIDs)))
+}
+
+// scheduleRespawn enqueues the task bringing the trainer back once its respawn time has come
+func (s *RespawnService) scheduleRespawn(ctx context.Context, userID trainer.UserID, death *trainer.Death) {
+	payload, err := json.Marshal(trainerRespawnPayload{UserID: userID.String()})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to marshal trainer respawn payload", zap.Error(err))
+		return
+	}
+
+	task := asynq.NewTask(tenant.IDFromContext(ctx).Key(TypeTrainerRespawn), payload)
+	if _, err := s.taskClient.EnqueueContext(ctx, task,
+		asynq.ProcessIn(time.Until(death.RespawnAt)),
+		asynq.TaskID(fmt.Sprintf("respawn:%s:%d", userID, death.DiedAt.UnixNano())),
+		asynq.MaxRetry(5),
+	); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to schedule trainer respawn",
+			zap.String("userID", userID.String()),
+			zap.Error(err))
+	}
+}
+
+// HandleRespawnTask processes TypeTrainerRespawn tasks
+func (s *RespawnService) HandleRespawnTask(ctx context.Context, task *asynq.Task) error {
+	var payload trainerRespawnPayload
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("invalid trainer respawn payload: %v: %w", err, asynq.SkipRetry)
+	}
+
+	return s.respawn(ctx, trainer.UserID(payload.UserID))
+}
+
+// respawn brings a dead trainer back at the spawn point nearest to where it fell. Trainers
+// that already came back are left alone.
+func (s *RespawnService) respawn(ctx context.Context, userID trainer.UserID) error {
+	fallen, err := s.movement.Position(ctx, userID.String())
+	if err != nil {
+		return err
+	}
+	if !fallen.IsDead() {
+		return nil
+	}
+	spawn := s.spawnPointNear(fallen.Position)
+
+	respawned := false
+	err = s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		respawned = false
+		if !t.IsDead() {
+			return nil, nil
+		}
+
+		if err := t.Respawn(spawn.Position, time.Now()); err != nil {
+			return nil, err
+		}
+		respawned = true
+		return t, nil
+	})
+	if err != nil || !respawned {
+		return err
+	}
+
+	// Put the simulation's trainer at the spawn point too; the jump must not count against the
+	// trainer's speed on its next command
+	moved, err := s.movement.Move(ctx, userID.String(), func(t *trainer.Trainer) error {
+		return t.MoveTo(spawn.Position)
+	})
+	if err != nil {
+		return err
+	}
+	s.validator.Forget(ctx, userID.String())
+
+	now := time.Now()
+	stopped := &cqrscommands.TrainerStoppedEvent{
+		UserID:    userID.String(),
+		Nickname:  moved.Nickname,
+		Color:     moved.Color,
+		Showcase:  moved.NameplateShowcase(),
+		Position:  moved.Position,
+		Movement:  moved.Movement,
+		Timestamp: now,
+		RequestID: fmt.Sprintf("respawn-%s-%d", userID, now.UnixNano()),
+		Changes:   map[string]interface{}{"position": moved.Position, "condition": moved.Condition},
+	}
+	if err := s.eventBus.Publish(ctx, stopped); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish respawn stop",
+			zap.String("userID", userID.String()),
+			zap.Error(err))
+	}
+
+	event := &cqrscommands.TrainerRespawnedEvent{
+		UserID:    userID.String(),
+		Nickname:  moved.Nickname,
+		Color:     moved.Color,
+		Position:  moved.Position,
+		SpawnID:   spawn.ID,
+		HP:        moved.Condition.HP,
+		Timestamp: now,
+		RequestID: requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish trainer respawned event",
+			zap.String("userID", userID.String()),
+			zap.Error(err))
+	}
+
+	s.logger.WithContext(ctx).Info("Trainer respawned",
+		zap.String("userID", userID.String()),
+		zap.String("spawnID", spawn.ID))
+	return nil
+}
+
+// spawnPointNear returns the walkable spawn point closest to a position. Without one the
+// trainer comes back where it fell.
+func (s *RespawnService) spawnPointNear(position shared.Position) vault.Location {
+	nearest, found := vault.Location{ID: "fallen", Name: "Where it fell", Position: position}, false
+	for _, point := range s.spawnPoints {
+		if !s.terrain.IsWalkablePosition(point.Position) {
+			continue
+		}
+		if !found || point.Position.DistanceTo(position) < nearest.Position.DistanceTo(position) {
+			nearest, found = point, true
+		}
+	}
+	return nearest
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// Retention rules, also the keys of the purge metrics
+const (
+	RetentionEventStreams = "event_streams"
+	RetentionAuditLog     = "audit_log"
+)
+
+// streamCountBatch is how many stream entries a dry run counts per XRANGE
+const streamCountBatch = 1000
+
+var (
+	// retentionPurged counts removed records per rule, served at /debug/vars
+	retentionPurged = expvar.NewMap("retention_purged")
+	// retentionPending counts what dry runs found expired per rule, as of the last run
+	retentionPending = expvar.NewMap("retention_pending")
+)
+
+// RetentionPolicy says how long each kind of data is kept. A zero duration keeps it forever.
+type RetentionPolicy struct {
+	Interval     time.Duration // How often expired data is purged
+	DryRun       bool          // Only report what would be purged
+	EventStreams time.Duration // Game event and command streams, kept as analytics history
+	AuditLog     time.Duration // Account activity timelines
+	// KeepUnarchived never purges event stream entries the event archive hasn't stored yet,
+	// on streams it has started archiving
+	KeepUnarchived bool
+}
+
+// RetentionResult reports one rule of a purge run
+type RetentionResult struct {
+	Rule   string    `json:"rule"`
+	Cutoff time.Time `json:"cutoff"`
+	Purged int64     `json:"purged"` // Would-be purged in a dry run
+	DryRun bool      `json:"dry_run"`
+}
+
+// RetentionService periodically deletes data older than its retention period
+type RetentionService struct {
+	logger      *logger.Logger
+	redisClient *redis.Client
+	activities  account.ActivityRepository
+	policy      RetentionPolicy
+	stopChan    chan struct{}
+	ticker      *time.Ticker
+}
+
+// NewRetentionService creates a new retention service
+func NewRetentionService(logger *logger.Logger, redisClient *redis.Client, activities account.ActivityRepository, policy RetentionPolicy) *RetentionService {
+	return &RetentionService{
+		logger:      logger.WithComponent("retention-service"),
+		redisClient: redisClient,
+		activities:  activities,
+		policy:      policy,
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start begins periodic purging. Every instance runs it; purges are idempotent.
+func (s *RetentionService) Start(ctx context.Context) {
+	if s.policy.Interval <= 0 {
+		s.logger.WithContext(ctx).Info("Retention purging disabled")
+		return
+	}
+
+	s.ticker = time.NewTicker(s.policy.Interval)
+
+	s.logger.WithContext(ctx).Info("Starting retention purging",
+		zap.Duration("interval", s.policy.Interval),
+		zap.Bool("dry_run", s.policy.DryRun),
+		zap.Duration("event_streams", s.policy.EventStreams),
+		zap.Duration("audit_log", s.policy.AuditLog))
+
+	go s.purgeLoop(ctx)
+}
+
+// Stop stops periodic purging
+func (s *RetentionService) Stop() {
+	s.logger.Info("Stopping retention purging")
+
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+
+	close(s.stopChan)
+}
+
+// Run applies every rule once. With dryRun nothing is deleted and the results report what
+// would be.
+func (s *RetentionService) Run(ctx context.Context, dryRun bool) ([]RetentionResult, error) {
+	now := time.Now()
+	var results []RetentionResult
+
+	rules := []struct {
+		name  string
+		keep  time.Duration
+		purge func(ctx context.Context, before time.Time, dryRun bool) (int64, error)
+	}{
+		{RetentionEventStreams, s.policy.EventStreams, s.purgeStreams},
+		{RetentionAuditLog, s.policy.AuditLog, s.activities.PurgeBefore},
+	}
+
+	for _, rule := range rules {
+		if rule.keep <= 0 {
+			continue
+		}
+
+		cutoff := now.Add(-rule.keep)
+		purged, err := rule.purge(ctx, cutoff, dryRun)
+		if err != nil {
+			return results, fmt.Errorf("retention rule %s: %w", rule.name, err)
+		}
+
+		if dryRun {
+			retentionPending.Set(rule.name, intVar(purged))
+		} else {
+			retentionPurged.Add(rule.name, purged)
+		}
+
+		results = append(results, RetentionResult{
+			Rule:   rule.name,
+			Cutoff: cutoff,
+			Purged: purged,
+			DryRun: dryRun,
+		})
+	}
+
+	return results, nil
+}
+
+// purgeLoop purges on every tick
+func (s *RetentionService) purgeLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-s.ticker.C:
+			s.purgeTick(ctx)
+		}
+	}
+}
+
+// purgeTick runs the rules and logs the report
+func (s *RetentionService) purgeTick(ctx context.Context) {
+	results, err := s.Run(ctx, s.policy.DryRun)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Retention purge failed", zap.Error(err))
+	}
+
+	for _, result := range results {
+		s.logger.WithContext(ctx).Info("Retention rule applied",
+			zap.String("rule", result.Rule),
+			zap.Time("cutoff", result.Cutoff),
+			zap.Int64("purged", result.Purged),
+			zap.Bool("dry_run", result.DryRun))
+	}
+}
+
+// purgeStreams trims entries older than before from the game event and command streams.
+// Stream IDs start with their creation time in milliseconds.
+func (s *RetentionService) purgeStreams(ctx context.Context, before time.Time, dryRun bool) (int64, error) {
+	minID := fmt.Sprintf("%d-0", before.UnixMilli())
+	var purged int64
+
+	for _, pattern := range []string{"game-events.*", "game-commands.*"} {
+		iter := s.redisClient.ScanType(ctx, 0, pattern, 100, "stream").Iterator()
+		for iter.Next(ctx) {
+			streamMinID, err := s.streamMinID(ctx, iter.Val(), minID)
+			if err != nil {
+				return purged, err
+			}
+
+			var n int64
+			if dryRun {
+				n, err = s.countStreamBefore(ctx, iter.Val(), streamMinID)
+			} else {
+				n, err = s.redisClient.XTrimMinID(ctx, iter.Val(), streamMinID).Result()
+			}
+			if err != nil {
+				return purged, err
+			}
+			purged += n
+		}
+		if err := iter.Err(); err != nil {
+			return purged, err
+		}
+	}
+
+	return purged, nil
+}
+
+// streamMinID lowers a stream's purge cutoff to its archive cursor, so entries the event
+// archive hasn't stored survive
+func (s *RetentionService) streamMinID(ctx context.Context, key, minID string) (string, error) {
+	if !s.policy.KeepUnarchived {
+		return minID, nil
+	}
+
+	cursor, err := archivedThrough(ctx, s.redisClient, key)
+	if err != nil || cursor == "" {
+		return minID, err
+	}
+	if compareStreamIDs(cursor, minID) < 0 {
+		return cursor, nil
+	}
+	return minID, nil
+}
+
+// countStreamBefore counts the entries of a stream with IDs below minID
+func (s *RetentionService) countStreamBefore(ctx context.Context, key, minID string) (int64, error) {
+	var count int64
+	start := "-"
+
+	for {
+		entries, err := s.redisClient.XRangeN(ctx, key, start, "("+minID, streamCountBatch).Result()
+		if err != nil {
+			return count, err
+		}
+
+		count += int64(len(entries))
+		if len(entries) < streamCountBatch {
+			return count, nil
+		}
+		start = "(" + entries[len(entries)-1].ID
+	}
+}
+
+// intVar wraps a value for expvar.Map.Set
+func intVar(value int64) *expvar.Int {
+	v := new(expvar.Int)
+	v.Set(value)
+	return v
+}
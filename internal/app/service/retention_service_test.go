@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/account"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// purgingActivities reports a fixed number of expired activities and records the cutoff
+type purgingActivities struct {
+	account.ActivityRepository
+	expired int64
+	err     error
+	before  time.Time
+	dryRun  bool
+}
+
+func (p *purgingActivities) PurgeBefore(ctx context.Context, before time.Time, dryRun bool) (int64, error) {
+	p.before = before
+	p.dryRun = dryRun
+	return p.expired, p.err
+}
+
+func TestRetentionService_Run(t *testing.T) {
+	tests := []struct {
+		name       string
+		policy     RetentionPolicy
+		activities *purgingActivities
+		dryRun     bool
+		want       []RetentionResult
+		wantErr    bool
+	}{
+		{
+			name:       "purges the audit log",
+			policy:     RetentionPolicy{AuditLog: time.Hour},
+			activities: &purgingActivities{expired: 3},
+			want:       []RetentionResult{{Rule: RetentionAuditLog, Purged: 3}},
+		},
+		{
+			name:       "dry run reports without purging",
+			policy:     RetentionPolicy{AuditLog: time.Hour},
+			activities: &purgingActivities{expired: 3},
+			dryRun:     true,
+			want:       []RetentionResult{{Rule: RetentionAuditLog, Purged: 3, DryRun: true}},
+		},
+		{
+			name:       "zero period keeps data forever",
+			policy:     RetentionPolicy{},
+			activities: &purgingActivities{expired: 3},
+			want:       nil,
+		},
+		{
+			name:       "failing rule",
+			policy:     RetentionPolicy{AuditLog: time.Hour},
+			activities: &purgingActivities{err: errors.New("redis down")},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewRetentionService(logger.NewDefault(), nil, tt.activities, tt.policy)
+
+			start := time.Now()
+			results, err := s.Run(context.Background(), tt.dryRun)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, results, len(tt.want))
+
+			for i, want := range tt.want {
+				assert.Equal(t, want.Rule, results[i].Rule)
+				assert.Equal(t, want.Purged, results[i].Purged)
+				assert.Equal(t, want.DryRun, results[i].DryRun)
+				assert.Equal(t, results[i].Cutoff, tt.activities.before)
+				assert.WithinDuration(t, start.Add(-tt.policy.AuditLog), results[i].Cutoff, time.Second)
+				assert.Equal(t, tt.dryRun, tt.activities.dryRun)
+			}
+		})
+	}
+}
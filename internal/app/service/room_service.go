@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/room"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/instance"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+// RoomService puts players in rooms. Rooms are kept with the tenant's data while the data of
+// what happens in a room, such as its bullets, is kept under the room's instance, which Scope
+// puts in a member's context. A player is in one room at most; the room closes and its
+// instance data is removed when its last member leaves.
+type RoomService struct {
+	logger   *logger.Logger
+	client   *redis.Client
+	roomRepo room.Repository
+	eventBus *cqrs.EventBus
+}
+
+// NewRoomService creates a new room service
+func NewRoomService(logger *logger.Logger, client *redis.Client, roomRepo room.Repository, eventBus *cqrs.EventBus) *RoomService {
+	return &RoomService{
+		logger:   logger.WithComponent("room-service"),
+		client:   client,
+		roomRepo: roomRepo,
+		eventBus: eventBus,
+	}
+}
+
+// Create makes a room owned by the player, who must not be in another room
+func (s *RoomService) Create(ctx context.Context, userID, name string, capacity int) (*room.Room, error) {
+	if err := s.checkNotInRoom(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	created, err := room.NewRoom(userID, name, capacity)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.roomRepo.FindOneAndInsert(ctx, created.ID, func() (*room.Room, error) {
+		return created, nil
+	}); err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Room created",
+		zap.String("roomID", created.ID.String()),
+		zap.String("userID", userID),
+		zap.Int("capacity", created.Capacity))
+
+	return created, nil
+}
+
+// Join adds the player to a room that has a place left
+func (s *RoomService) Join(ctx context.Context, userID string, roomID room.ID) (*room.Room, error) {
+	if err := s.checkNotInRoom(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	var joined *room.Room
+	err := s.roomRepo.FindOneAndUpdate(ctx, roomID, func(r *room.Room) (*room.Room, error) {
+		// The last member left and the room is being closed
+		if r.IsEmpty() {
+			return nil, shared.ErrNotFound("room")
+		}
+		if err := r.Join(userID); err != nil {
+			return nil, err
+		}
+		joined = r
+		return r, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(ctx, &cqrscommands.RoomJoinedEvent{
+		RoomID:    roomID.String(),
+		UserID:    userID,
+		Members:   joined.Members,
+		Timestamp: time.Now(),
+		RequestID: requestid.FromContextOrNew(ctx),
+	})
+
+	s.logger.WithContext(ctx).Info("Player joined room",
+		zap.String("roomID", roomID.String()),
+		zap.String("userID", userID),
+		zap.Int("members", len(joined.Members)))
+
+	return joined, nil
+}
+
+// Leave takes the player out of their room, closing it when they were its last member
+func (s *RoomService) Leave(ctx context.Context, userID string) error {
+	roomID, err := s.roomRepo.MemberRoom(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if roomID == "" {
+		return shared.NewDomainError(shared.ErrCodeNotInRoom, "Not in a room")
+	}
+
+	var left *room.Room
+	err = s.roomRepo.FindOneAndUpdate(ctx, roomID, func(r *room.Room) (*room.Room, error) {
+		if err := r.Leave(userID); err != nil {
+			return nil, err
+		}
+		left = r
+		return r, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if left.IsEmpty() {
+		s.close(ctx, roomID)
+	}
+
+	s.publish(ctx, &cqrscommands.RoomLeftEvent{
+		RoomID:    roomID.String(),
+		UserID:    userID,
+		OwnerID:   left.OwnerID,
+		Members:   left.Members,
+		Closed:    left.IsEmpty(),
+		Timestamp: time.Now(),
+		RequestID: requestid.FromContextOrNew(ctx),
+	})
+
+	s.logger.WithContext(ctx).Info("Player left room",
+		zap.String("roomID", roomID.String()),
+		zap.String("userID", userID),
+		zap.Bool("closed", left.IsEmpty()))
+
+	return nil
+}
+
+// List returns the open rooms, oldest first
+func (s *RoomService) List(ctx context.Context) ([]*room.Room, error) {
+	return s.roomRepo.List(ctx)
+}
+
+// Scope returns the context for the player's actions in their room, along with the room. A
+// player in no room gets the context back unchanged and a nil room.
+func (s *RoomService) Scope(ctx context.Context, userID string) (context.Context, *room.Room, error) {
+	roomID, err := s.roomRepo.MemberRoom(ctx, userID)
+	if err != nil || roomID == "" {
+		return ctx, nil, err
+	}
+
+	current, err := s.roomRepo.GetByID(ctx, roomID)
+	if err != nil || current == nil || !current.HasMember(userID) {
+		return ctx, nil, err
+	}
+	return instance.WithInstance(ctx, roomID.Instance()), current, nil
+}
+
+// checkNotInRoom refuses players who are already in a room
+func (s *RoomService) checkNotInRoom(ctx context.Context, userID string) error {
+	roomID, err := s.roomRepo.MemberRoom(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if roomID != "" {
+		return shared.NewDomainError(shared.ErrCodeAlreadyInRoom, "Already in a room, leave it first")
+	}
+	return nil
+}
+
+// close deletes an emptied room and the keys of its instance. Failures leave keys that
+// nothing reads any more and are logged.
+func (s *RoomService) close(ctx context.Context, roomID room.ID) {
+	if err := s.roomRepo.Delete(ctx, roomID); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to delete empty room", zap.String("roomID", roomID.String()), zap.Error(err))
+	}
+
+	scoped := instance.WithInstance(ctx, roomID.Instance())
+	iter := s.client.Scan(scoped, 0, "*", 0).Iterator()
+	var keys []string
+	for iter.Next(scoped) {
+		keys = append(keys, iter.Val())
+	}
+	if err := iter.Err(); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to scan room keys", zap.String("roomID", roomID.String()), zap.Error(err))
+		return
+	}
+	if len(keys) > 0 {
+		if err := s.client.Unlink(scoped, keys...).Err(); err != nil {
+			s.logger.WithContext(ctx).Error("Failed to remove room keys", zap.String("roomID", roomID.String()), zap.Error(err))
+		}
+	}
+}
+
+// publish publishes a room event, logging failures
+func (s *RoomService) publish(ctx context.Context, event interface{}) {
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish room event", zap.Error(err))
+	}
+}
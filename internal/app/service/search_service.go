@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/search"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// SearchService runs one query across the search indexes of several document types
+type SearchService struct {
+	logger  *logger.Logger
+	indexes []search.Index
+}
+
+// NewSearchService creates a new search service over the given indexes
+func NewSearchService(logger *logger.Logger, indexes ...search.Index) *SearchService {
+	return &SearchService{
+		logger:  logger.WithComponent("search-service"),
+		indexes: indexes,
+	}
+}
+
+// Types returns the searchable document types
+func (s *SearchService) Types() []search.Type {
+	types := make([]search.Type, 0, len(s.indexes))
+	for _, index := range s.indexes {
+		types = append(types, index.Type())
+	}
+	return types
+}
+
+// Query searches each requested type and returns one page of hits per type
+func (s *SearchService) Query(ctx context.Context, q search.Query) (*search.Result, error) {
+	if err := q.Validate(s.Types()); err != nil {
+		return nil, err
+	}
+
+	expression, err := search.MatchExpression(q.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &search.Result{
+		Text:   q.Text,
+		Groups: make([]*search.Group, 0, len(q.Types)),
+	}
+	for _, t := range q.Types {
+		group, err := s.index(t).Search(ctx, expression, q.Offset, q.Limit)
+		if err != nil {
+			s.logger.WithContext(ctx).Error("Search failed",
+				zap.String("type", t.String()),
+				zap.String("expression", expression),
+				zap.Error(err))
+			return nil, err
+		}
+		result.Groups = append(result.Groups, group)
+	}
+
+	s.logger.WithContext(ctx).Debug("Search completed",
+		zap.String("text", q.Text),
+		zap.String("types", joinTypes(q.Types)))
+
+	return result, nil
+}
+
+// index returns the index for a validated type
+func (s *SearchService) index(t search.Type) search.Index {
+	for _, index := range s.indexes {
+		if index.Type() == t {
+			return index
+		}
+	}
+	return nil
+}
+
+// joinTypes formats types for logging
+func joinTypes(types []search.Type) string {
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, t.String())
+	}
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/crafting"
+	"github.com/danghamo/life/internal/domain/friend"
+	"github.com/danghamo/life/internal/domain/session"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// SessionService remembers where players left off and, when they come back, sends them a
+// session.welcome_back summary of what changed while they were away: levels, experience and
+// money gained, crafting jobs that finished and new friend requests. The summary follows the
+// connected message of their first connection, so clients can show a catch-up screen.
+type SessionService struct {
+	logger      *logger.Logger
+	snapshots   session.Repository
+	trainerRepo trainer.Repository
+	jobRepo     crafting.Repository
+	friendRepo  friend.Repository
+	push        *cqrscommands.SSEBroadcastHelper
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(logger *logger.Logger, snapshots session.Repository, trainerRepo trainer.Repository, jobRepo crafting.Repository, friendRepo friend.Repository, push *cqrscommands.SSEBroadcastHelper) *SessionService {
+	return &SessionService{
+		logger:      logger.WithComponent("session-service"),
+		snapshots:   snapshots,
+		trainerRepo: trainerRepo,
+		jobRepo:     jobRepo,
+		friendRepo:  friendRepo,
+		push:        push,
+	}
+}
+
+// Started sends a player who just connected the summary of what changed since their last
+// session, if anything did. The snapshot is used up, so connecting on a second device or
+// server sends nothing more.
+func (s *SessionService) Started(ctx context.Context, userID string) {
+	last, err := s.snapshots.Take(ctx, userID)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to take session snapshot", zap.String("userId", userID), zap.Error(err))
+		return
+	}
+	if last == nil {
+		return
+	}
+
+	current, err := s.snapshot(ctx, userID)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to snapshot returning player", zap.String("userId", userID), zap.Error(err))
+		return
+	}
+	if current == nil {
+		return
+	}
+
+	jobs, err := s.jobRepo.GetByTrainer(ctx, trainer.UserID(userID))
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to get crafting jobs for session summary", zap.String("userId", userID), zap.Error(err))
+	}
+
+	summary := session.Summarize(last, current, jobs)
+	if summary.IsEmpty() {
+		return
+	}
+
+	for i, request := range summary.FriendRequests {
+		if requester, err := s.trainerRepo.GetByID(ctx, trainer.UserID(request.UserID)); err == nil && requester != nil {
+			summary.FriendRequests[i].Nickname = requester.Nickname
+		}
+	}
+
+	if err := s.push.BroadcastToUsers(ctx, []string{userID}, "session.welcome_back", summary); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to send session summary", zap.String("userId", userID), zap.Error(err))
+		return
+	}
+
+	s.logger.WithContext(ctx).Debug("Sent session summary",
+		zap.String("userId", userID),
+		zap.Duration("away", time.Since(last.EndedAt)),
+		zap.Int("levelsGained", summary.LevelsGained),
+		zap.Int("completedCrafts", len(summary.CompletedCrafts)),
+		zap.Int("friendRequests", len(summary.FriendRequests)))
+}
+
+// Ended remembers a player's progress when their last connection closed
+func (s *SessionService) Ended(ctx context.Context, userID string) {
+	snapshot, err := s.snapshot(ctx, userID)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to snapshot leaving player", zap.String("userId", userID), zap.Error(err))
+		return
+	}
+	if snapshot == nil {
+		return
+	}
+
+	if err := s.snapshots.Save(ctx, userID, snapshot); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to save session snapshot", zap.String("userId", userID), zap.Error(err))
+	}
+}
+
+// snapshot reads a player's current progress, or nil if they have no trainer yet
+func (s *SessionService) snapshot(ctx context.Context, userID string) (*session.Snapshot, error) {
+	t, err := s.trainerRepo.GetByID(ctx, trainer.UserID(userID))
+	if err != nil || t == nil {
+		return nil, err
+	}
+
+	list, err := s.friendRepo.GetList(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return session.TakeSnapshot(t, list.Incoming, time.Now()), nil
+}
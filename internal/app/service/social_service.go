@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/social"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+const (
+	// How often shared interest chunks are checked for encounters
+	encounterInterval = 5 * time.Second
+	// Lock key so only one server instance records encounters per tick
+	encounterLockKey = "lock:social:encounters"
+)
+
+// SocialService records which trainers recently played near each other and lists them
+type SocialService struct {
+	logger      *logger.Logger
+	socialRepo  social.Repository
+	trainerRepo trainer.Repository
+	interest    *InterestManager
+	redisClient *redis.Client
+	stopChan    chan struct{}
+	ticker      *time.Ticker
+}
+
+// NewSocialService creates a new social service
+func NewSocialService(
+	logger *logger.Logger,
+	socialRepo social.Repository,
+	trainerRepo trainer.Repository,
+	interest *InterestManager,
+	redisClient *redis.Client,
+) *SocialService {
+	return &SocialService{
+		logger:      logger.WithComponent("social-service"),
+		socialRepo:  socialRepo,
+		trainerRepo: trainerRepo,
+		interest:    interest,
+		redisClient: redisClient,
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start begins periodic encounter tracking
+func (s *SocialService) Start(ctx context.Context) {
+	s.ticker = time.NewTicker(encounterInterval)
+
+	s.logger.WithContext(ctx).Info("Starting encounter tracking",
+		zap.Duration("interval", encounterInterval),
+		zap.Duration("min_duration", social.EncounterMinDuration))
+
+	go s.encounterLoop(ctx)
+}
+
+// Stop stops periodic encounter tracking
+func (s *SocialService) Stop() {
+	s.logger.Info("Stopping encounter tracking")
+
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+
+	close(s.stopChan)
+}
+
+// RecentPlayers returns the trainers the user most recently played near, newest first
+func (s *SocialService) RecentPlayers(ctx context.Context, userID trainer.UserID) ([]social.RecentPlayer, error) {
+	encounters, err := s.socialRepo.GetRecent(ctx, userID.String(), social.MaxRecentPlayers)
+	if err != nil {
+		return nil, err
+	}
+
+	players := make([]social.RecentPlayer, 0, len(encounters))
+	for _, encounter := range encounters {
+		t, err := s.trainerRepo.GetByID(ctx, trainer.UserID(encounter.UserID))
+		if err != nil {
+			return nil, err
+		}
+		if t == nil {
+			continue // Deleted since they met
+		}
+
+		players = append(players, social.RecentPlayer{
+			Profile: t.PublicNameplate(),
+			MetAt:   encounter.MetAt,
+		})
+	}
+
+	return players, nil
+}
+
+// encounterLoop records encounters on every tick
+func (s *SocialService) encounterLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-s.ticker.C:
+			s.encounterTick(ctx)
+		}
+	}
+}
+
+// encounterTick records an encounter for every pair of trainers who have shared a chunk long enough
+func (s *SocialService) encounterTick(ctx context.Context) {
+	// Every server runs the loop; only the one holding the lock records this tick
+	acquired, err := s.redisClient.SetNX(ctx, encounterLockKey, "1", encounterInterval/2).Result()
+	if err != nil || !acquired {
+		return
+	}
+
+	chunks, err := s.interest.SharedChunks(ctx)
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to load shared interest chunks", zap.Error(err))
+		return
+	}
+
+	now := time.Now()
+	recorded := 0
+	for _, present := range chunks {
+		for _, pair := range social.MetPairs(present, now) {
+			// Meeting again moves the other player back to the top of the list
+			for _, users := range [][2]string{pair, {pair[1], pair[0]}} {
+				if err := s.socialRepo.RecordEncounter(ctx, users[0], users[1], now); err != nil {
+					s.logger.WithContext(ctx).Error("Failed to record encounter",
+						zap.String("userID", users[0]),
+						zap.String("otherID", users[1]),
+						zap.Error(err))
+				}
+			}
+			recorded++
+		}
+	}
+
+	if recorded > 0 {
+		s.logger.WithContext(ctx).Debug("Recorded encounters", zap.Int("pairs", recorded))
+	}
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/fairness"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/world"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+const (
+	// How often spawn areas are rolled
+	spawnInterval = 10 * time.Second
+	// Lock key so only one server instance spawns per tick
+	spawnLockKey = "lock:animal:spawn"
+)
+
+// SpawnManager periodically spawns wild animals into the world, keeping each
+// spawn area under its cap and only placing animals on walkable terrain
+type SpawnManager struct {
+	logger      *logger.Logger
+	animalRepo  animal.Repository
+	tableRepo   animal.SpawnTableRepository
+	clockRepo   world.ClockRepository
+	world       *world.World
+	config      animal.SpawnConfig
+	rate        atomic.Uint64 // Bits of the configured spawn rate, which SetRate changes while running
+	randomness  *RandomnessService
+	eventBus    *cqrs.EventBus
+	redisClient *redis.Client
+	stopChan    chan struct{}
+	ticker      *time.Ticker
+}
+
+// spawnRecord summarizes one spawned animal for the roll audit
+type spawnRecord struct {
+	AnimalID   string           `json:"animal_id"`
+	AnimalType string           `json:"animal_type"`
+	Level      int              `json:"level"`
+	Area       animal.SpawnArea `json:"area"`
+}
+
+// NewSpawnManager creates a new spawn manager for the given world terrain
+func NewSpawnManager(
+	logger *logger.Logger,
+	animalRepo animal.Repository,
+	tableRepo animal.SpawnTableRepository,
+	clockRepo world.ClockRepository,
+	gameWorld *world.World,
+	config animal.SpawnConfig,
+	randomness *RandomnessService,
+	eventBus *cqrs.EventBus,
+	redisClient *redis.Client,
+) *SpawnManager {
+	m := &SpawnManager{
+		logger:      logger.WithComponent("spawn-manager"),
+		animalRepo:  animalRepo,
+		tableRepo:   tableRepo,
+		clockRepo:   clockRepo,
+		world:       gameWorld,
+		config:      config,
+		randomness:  randomness,
+		eventBus:    eventBus,
+		redisClient: redisClient,
+		stopChan:    make(chan struct{}),
+	}
+	m.SetRate(config.Rate)
+	return m
+}
+
+// SetRate changes the spawn rate from the next tick on. Live event overrides still take
+// precedence while they run.
+func (m *SpawnManager) SetRate(rate float64) {
+	m.rate.Store(math.Float64bits(rate))
+}
+
+// baseConfig returns the spawn settings at the current rate
+func (m *SpawnManager) baseConfig() animal.SpawnConfig {
+	config := m.config
+	config.Rate = math.Float64frombits(m.rate.Load())
+	return config
+}
+
+// Start begins periodic spawning
+func (m *SpawnManager) Start(ctx context.Context) {
+	m.ticker = time.NewTicker(spawnInterval)
+
+	m.logger.WithContext(ctx).Info("Starting animal spawn manager",
+		zap.Duration("interval", spawnInterval),
+		zap.Float64("rate", m.baseConfig().Rate),
+		zap.Int("area_cap", m.config.AreaCap))
+
+	go m.spawnLoop(ctx)
+}
+
+// Stop stops periodic spawning
+func (m *SpawnManager) Stop() {
+	m.logger.Info("Stopping animal spawn manager")
+
+	if m.ticker != nil {
+		m.ticker.Stop()
+	}
+
+	close(m.stopChan)
+}
+
+// spawnLoop rolls spawns on every tick
+func (m *SpawnManager) spawnLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-m.stopChan:
+			return
+		case <-m.ticker.C:
+			m.spawnTick(ctx)
+		}
+	}
+}
+
+// spawnTick rolls every spawn area once
+func (m *SpawnManager) spawnTick(ctx context.Context) {
+	// Every server runs the loop; only the one holding the lock spawns this tick
+	acquired, err := m.redisClient.SetNX(ctx, spawnLockKey, "1", spawnInterval/2).Result()
+	if err != nil || !acquired {
+		return
+	}
+
+	config, table := m.currentConfig(ctx)
+	width, height := m.world.Dimensions()
+	areas := config.Areas(width, height)
+
+	inputs := map[string]any{
+		"rate":     config.Rate,
+		"area_cap": config.AreaCap,
+	}
+	if table != nil {
+		inputs["spawn_table"] = table
+	}
+	if config.Weights != nil {
+		inputs["weights"] = config.Weights
+	}
+	session, err := m.randomness.Begin(ctx, fairness.PurposeSpawn, "", "", inputs)
+	if err != nil {
+		m.logger.WithContext(ctx).Error("Failed to start spawn roll", zap.Error(err))
+		return
+	}
+	rng := session.Rand()
+
+	spawned := make([]spawnRecord, 0)
+	for _, area := range areas {
+		population, err := m.population(ctx, area)
+		if err != nil {
+			m.logger.WithContext(ctx).Error("Failed to count spawn area population",
+				zap.String("area", area.String()),
+				zap.Error(err))
+			continue
+		}
+
+		wild, err := config.RollSpawn(area, population, width, height, m.world.IsWalkablePosition, rng)
+		if err != nil {
+			m.logger.WithContext(ctx).Error("Failed to roll spawn", zap.String("area", area.String()), zap.Error(err))
+			continue
+		}
+		if wild == nil {
+			continue
+		}
+
+		if err := m.place(ctx, area, wild); err != nil {
+			m.logger.WithContext(ctx).Error("Failed to place spawned animal",
+				zap.String("area", area.String()),
+				zap.Error(err))
+			continue
+		}
+
+		spawned = append(spawned, spawnRecord{
+			AnimalID:   wild.ID.String(),
+			AnimalType: wild.AnimalType.String(),
+			Level:      wild.Level.Value(),
+			Area:       area,
+		})
+	}
+
+	// Ticks where nothing spawned are not worth an audit record
+	if len(spawned) > 0 {
+		m.randomness.Record(ctx, session, spawned)
+		m.logger.WithContext(ctx).Debug("Spawned wild animals", zap.Int("count", len(spawned)))
+	}
+}
+
+// SpawnAt places a wild animal of a type and level on a walkable tile, e.g. for a live
+// event. It counts towards its spawn area's population like rolled spawns.
+func (m *SpawnManager) SpawnAt(ctx context.Context, animalType animal.AnimalType, level int, position shared.Position) (*animal.Animal, error) {
+	if err := m.CheckSpawn(animalType, level, position); err != nil {
+		return nil, err
+	}
+
+	wild, err := animal.NewWildAnimal(animalType, level, position)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.place(ctx, m.config.AreaOf(position), wild); err != nil {
+		return nil, err
+	}
+	return wild, nil
+}
+
+// CheckSpawn checks that SpawnAt could place an animal without placing it
+func (m *SpawnManager) CheckSpawn(animalType animal.AnimalType, level int, position shared.Position) error {
+	if !animalType.IsValid() {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidAnimalType, "Invalid animal type: %s", animalType)
+	}
+	if level < 1 || level > 100 {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "Level must be between 1 and 100")
+	}
+	if !m.world.IsWalkablePosition(position) {
+		return shared.NewDomainError(shared.ErrCodeInvalidPosition, "Animals can only spawn on walkable tiles")
+	}
+	return nil
+}
+
+// currentConfig returns the spawn settings with the live event override applied and adjusted
+// for the time of day and weather, and the override if there is one. Spawning carries on
+// without the override or the clock when they can't be read.
+func (m *SpawnManager) currentConfig(ctx context.Context) (animal.SpawnConfig, *animal.SpawnTable) {
+	clock, err := m.clockRepo.Get(ctx)
+	if err != nil {
+		m.logger.WithContext(ctx).Warn("Failed to load world clock", zap.Error(err))
+		clock = nil
+	}
+
+	table, err := m.tableRepo.Get(ctx)
+	if err != nil {
+		m.logger.WithContext(ctx).Warn("Failed to load spawn table override", zap.Error(err))
+		return m.baseConfig().During(clock), nil
+	}
+	return m.baseConfig().Apply(table).During(clock), table
+}
+
+// place stores a spawned animal, indexes it under its area and announces it
+func (m *SpawnManager) place(ctx context.Context, area animal.SpawnArea, wild *animal.Animal) error {
+	err := m.animalRepo.FindOneAndInsert(ctx, wild.ID, func() (*animal.Animal, error) {
+		return wild, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := m.redisClient.SAdd(ctx, m.areaKey(area), wild.ID.String()).Err(); err != nil {
+		return err
+	}
+
+	event := &cqrscommands.AnimalSpawnedEvent{
+		AnimalID:   wild.ID.String(),
+		AnimalType: wild.AnimalType.String(),
+		Level:      wild.Level.Value(),
+		Position:   wild.Position,
+		Timestamp:  time.Now(),
+		RequestID:  requestid.FromContextOrNew(ctx),
+	}
+	if err := m.eventBus.Publish(ctx, event); err != nil {
+		m.logger.WithContext(ctx).Error("Failed to publish animal spawned event",
+			zap.String("animalID", wild.ID.String()),
+			zap.Error(err))
+	}
+
+	return nil
+}
+
+// population counts the wild animals still roaming an area, dropping animals that
+// were captured or removed since they spawned
+func (m *SpawnManager) population(ctx context.Context, area animal.SpawnArea) (int, error) {
+	key := m.areaKey(area)
+
+	ids, err := m.redisClient.SMembers(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, id := range ids {
+		a, err := m.animalRepo.GetByID(ctx, animal.AnimalID(id))
+		if err != nil {
+			return 0, err
+		}
+
+		if a == nil || !a.IsWild() || m.config.AreaOf(a.Position) != area {
+			m.redisClient.SRem(ctx, key, id)
+			continue
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// areaKey returns the Redis set of wild animals spawned in an area
+func (m *SpawnManager) areaKey(area animal.SpawnArea) string {
+	return spawnAreaKey(area)
+}
+
+// spawnAreaKey returns the Redis set of wild animals spawned in an area
+func spawnAreaKey(area animal.SpawnArea) string {
+	return fmt.Sprintf("idx:animal:spawn_area:%s", area.String())
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// TenantMovement runs a movement simulation per tenant, so trainers only see and meet trainers
+// of their own game, and routes each call to the simulation of the context's tenant
+type TenantMovement struct {
+	simulations map[tenant.ID]*MovementBroadcaster
+	list        []*MovementBroadcaster
+}
+
+// NewTenantMovement creates a simulation for every tenant with newSimulation
+func NewTenantMovement(tenants []*tenant.Tenant, newSimulation func() *MovementBroadcaster) *TenantMovement {
+	m := &TenantMovement{simulations: make(map[tenant.ID]*MovementBroadcaster, len(tenants))}
+	for _, t := range tenants {
+		simulation := newSimulation()
+		simulation.tenant = t
+		m.simulations[t.ID] = simulation
+		m.list = append(m.list, simulation)
+	}
+	if _, ok := m.simulations[tenant.Default]; !ok {
+		simulation := newSimulation()
+		m.simulations[tenant.Default] = simulation
+		m.list = append(m.list, simulation)
+	}
+	return m
+}
+
+// For returns the simulation of the context's tenant
+func (m *TenantMovement) For(ctx context.Context) *MovementBroadcaster {
+	if simulation, ok := m.simulations[tenant.IDFromContext(ctx)]; ok {
+		return simulation
+	}
+	return m.simulations[tenant.Default]
+}
+
+// Start starts every simulation
+func (m *TenantMovement) Start(ctx context.Context) {
+	for _, simulation := range m.list {
+		go simulation.Start(ctx)
+	}
+}
+
+// Stop stops every simulation, persisting the positions of their trainers
+func (m *TenantMovement) Stop() {
+	for _, simulation := range m.list {
+		simulation.Stop()
+	}
+}
+
+// SetBroadcastRate changes the broadcast rate of every simulation
+func (m *TenantMovement) SetBroadcastRate(rate int) {
+	for _, simulation := range m.list {
+		simulation.SetBroadcastRate(rate)
+	}
+}
+
+// Move applies a movement command in the simulation of the context's tenant
+func (m *TenantMovement) Move(ctx context.Context, userID string, apply func(*trainer.Trainer) error) (*trainer.Trainer, error) {
+	return m.For(ctx).Move(ctx, userID, apply)
+}
+
+// Position returns the trainer with its current position in the context's tenant
+func (m *TenantMovement) Position(ctx context.Context, userID string) (*trainer.Trainer, error) {
+	return m.For(ctx).Position(ctx, userID)
+}
+
+// PositionAt returns where a trainer of the context's tenant was at a recent point in time
+func (m *TenantMovement) PositionAt(ctx context.Context, userID string, at time.Time) (shared.Position, bool) {
+	return m.For(ctx).PositionAt(userID, at)
+}
+
+// GetCurrentOnlineTrainers returns the moving trainers of the context's tenant
+func (m *TenantMovement) GetCurrentOnlineTrainers(ctx context.Context) []cqrscommands.TrainerMovedEvent {
+	return m.For(ctx).GetCurrentOnlineTrainers(ctx)
+}
+
+// BroadcastAge returns how old the newest positions of the context's tenant clients got are
+func (m *TenantMovement) BroadcastAge(ctx context.Context) time.Duration {
+	return m.For(ctx).BroadcastAge(time.Now())
+}
+
+// MaxBroadcastAge returns the broadcast age of the simulation furthest behind
+func (m *TenantMovement) MaxBroadcastAge() time.Duration {
+	now := time.Now()
+	var oldest time.Duration
+	for _, simulation := range m.list {
+		oldest = max(oldest, simulation.BroadcastAge(now))
+	}
+	return oldest
+}
+
+// Logout drops the user's trainer from every simulation it is in. Connections don't know
+// their tenant, and a user only has a trainer in their own.
+func (m *TenantMovement) Logout(ctx context.Context, userID string) {
+	for _, simulation := range m.list {
+		simulation.Logout(simulation.scope(ctx), userID)
+	}
+}
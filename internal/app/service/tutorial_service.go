@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/bullet"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/tutorial"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+const (
+	// Animal placed in the practice range for the capture step
+	practiceAnimalType  = animal.Elephant
+	practiceAnimalLevel = 1
+)
+
+// rangeDirections are tried in order for laying out a practice range
+var rangeDirections = []shared.Position{
+	shared.NewPosition(1, 0),
+	shared.NewPosition(-1, 0),
+	shared.NewPosition(0, 1),
+	shared.NewPosition(0, -1),
+}
+
+// tutorialUpdate is the tutorial.progress message sent to a player's clients
+type tutorialUpdate struct {
+	Progress *tutorial.Progress `json:"progress"`
+	Hit      *tutorial.Target   `json:"hit,omitempty"` // Target the bullet just fired hit
+	Line     string             `json:"line,omitempty"`
+}
+
+// TutorialService walks new players through moving, firing and capturing. Starting the
+// tutorial opens a practice range around the trainer, a room with stationary targets and a
+// scripted coach bot. Handlers report what players do, which moves their progress through
+// the steps; each change is sent to the player's clients as tutorial.progress.
+type TutorialService struct {
+	logger       *logger.Logger
+	progress     tutorial.Repository
+	trainerRepo  trainer.Repository
+	terrain      trainer.Terrain
+	spawnManager *SpawnManager
+	push         *cqrscommands.SSEBroadcastHelper
+	aimAssist    *AccessibilityService
+}
+
+// NewTutorialService creates a new tutorial service placing practice animals through
+// spawnManager and widening targets for players by their aim assistance
+func NewTutorialService(logger *logger.Logger, progress tutorial.Repository, trainerRepo trainer.Repository, terrain trainer.Terrain, spawnManager *SpawnManager, push *cqrscommands.SSEBroadcastHelper, aimAssist *AccessibilityService) *TutorialService {
+	return &TutorialService{
+		logger:       logger.WithComponent("tutorial-service"),
+		progress:     progress,
+		trainerRepo:  trainerRepo,
+		terrain:      terrain,
+		spawnManager: spawnManager,
+		push:         push,
+		aimAssist:    aimAssist,
+	}
+}
+
+// Start opens a practice range where the trainer stands and starts them on the first step.
+// A tutorial already under way is returned as it is; one that is over can't be started again.
+func (s *TutorialService) Start(ctx context.Context, userID trainer.UserID) (*tutorial.Progress, error) {
+	existing, err := s.progress.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if existing.IsComplete() {
+			return nil, shared.NewDomainError(shared.ErrCodeInvalidOperation, "Tutorial is already over")
+		}
+		return existing, nil
+	}
+
+	t, err := s.trainerRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if t == nil {
+		return nil, shared.ErrNotFound("Trainer")
+	}
+	t.UpdatePositionFromMovement()
+
+	room := tutorial.NewPracticeRange(t.Position, s.rangeDirection(t.Position))
+	progress := tutorial.NewProgress(userID, room, time.Now())
+	if err := s.progress.Insert(ctx, progress); err != nil {
+		if code, ok := shared.DomainErrorCode(err); ok && code == shared.ErrCodeAlreadyExists {
+			// Started by another request meanwhile
+			return s.progress.Get(ctx, userID)
+		}
+		return nil, err
+	}
+
+	s.send(ctx, tutorialUpdate{Progress: progress, Line: room.Bot.Line})
+	s.logger.WithContext(ctx).Info("Tutorial started",
+		zap.String("userId", userID.String()),
+		zap.String("roomId", room.ID.String()))
+	return progress, nil
+}
+
+// Get returns a trainer's progress through the tutorial
+func (s *TutorialService) Get(ctx context.Context, userID trainer.UserID) (*tutorial.Progress, error) {
+	progress, err := s.progress.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if progress == nil {
+		return nil, shared.ErrNotFound("Tutorial progress")
+	}
+	return progress, nil
+}
+
+// Skip ends the tutorial for a trainer who doesn't need it, closing their practice range.
+// Trainers who never started it can skip it too.
+func (s *TutorialService) Skip(ctx context.Context, userID trainer.UserID) (*tutorial.Progress, error) {
+	now := time.Now()
+	err := s.progress.Insert(ctx, &tutorial.Progress{
+		UserID:        userID,
+		Step:          tutorial.StepComplete,
+		StartedAt:     now,
+		StepStartedAt: now,
+		CompletedAt:   &now,
+		Skipped:       true,
+	})
+	if code, ok := shared.DomainErrorCode(err); ok && code == shared.ErrCodeAlreadyExists {
+		err = s.progress.FindOneAndUpdate(ctx, userID, func(p *tutorial.Progress) error {
+			return p.Skip(now)
+		})
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Tutorial skipped", zap.String("userId", userID.String()))
+	return s.Get(ctx, userID)
+}
+
+// Stopped follows a trainer coming to a stop at position
+func (s *TutorialService) Stopped(ctx context.Context, userID trainer.UserID, position shared.Position) {
+	s.follow(ctx, userID, tutorial.StepMove, func(p *tutorial.Progress) *tutorial.Target {
+		p.Stopped(position, time.Now())
+		return nil
+	})
+}
+
+// Fired follows a bullet a trainer fired, which may hit a practice target
+func (s *TutorialService) Fired(ctx context.Context, fired *bullet.Bullet) {
+	userID := trainer.UserID(fired.PlayerID)
+	s.follow(ctx, userID, tutorial.StepFire, func(p *tutorial.Progress) *tutorial.Target {
+		hit, _ := p.Fired(fired, s.aimAssist.HitTolerance(ctx, userID), time.Now())
+		return hit
+	})
+}
+
+// Captured follows a net a trainer threw at a wild animal
+func (s *TutorialService) Captured(ctx context.Context, userID trainer.UserID, result *trainer.CaptureResult) {
+	s.follow(ctx, userID, tutorial.StepCapture, func(p *tutorial.Progress) *tutorial.Target {
+		p.Captured(result.Success, time.Now())
+		return nil
+	})
+}
+
+// follow records what a trainer did when they are at the step it matters to. Progress is read
+// before updating so the many players past the tutorial cost one read; failures are logged,
+// as they must not fail what the player did.
+func (s *TutorialService) follow(ctx context.Context, userID trainer.UserID, step tutorial.Step, record func(*tutorial.Progress) *tutorial.Target) {
+	current, err := s.progress.Get(ctx, userID)
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to get tutorial progress", zap.String("userId", userID.String()), zap.Error(err))
+		return
+	}
+	if current == nil || current.Step != step {
+		return
+	}
+
+	var updated *tutorial.Progress
+	var hit *tutorial.Target
+	err = s.progress.FindOneAndUpdate(ctx, userID, func(p *tutorial.Progress) error {
+		updated = p
+		hit = record(p)
+		return nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to update tutorial progress",
+			zap.String("userId", userID.String()),
+			zap.String("step", step.String()),
+			zap.Error(err))
+		return
+	}
+
+	if updated.Step == step {
+		if hit != nil {
+			s.send(ctx, tutorialUpdate{Progress: updated, Hit: hit})
+		}
+		return
+	}
+
+	s.logger.WithContext(ctx).Info("Tutorial step completed",
+		zap.String("userId", userID.String()),
+		zap.String("step", step.String()))
+	if updated.Step == tutorial.StepCapture {
+		s.prepareCapture(ctx, updated)
+	}
+	s.send(ctx, tutorialUpdate{Progress: updated, Hit: hit, Line: tutorial.Line(updated.Step)})
+}
+
+// prepareCapture gives the trainer a net that can't miss and places an animal in the
+// practice range to throw it at. Catching any other wild animal ends the step as well, so
+// failing either is logged rather than blocking the tutorial.
+func (s *TutorialService) prepareCapture(ctx context.Context, progress *tutorial.Progress) {
+	userID := progress.UserID
+	err := s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		net, err := trainer.NewItem(trainer.MasterNet, "Master Net")
+		if err != nil {
+			return nil, err
+		}
+		if err := t.Inventory.AddItem(net); err != nil {
+			return nil, err
+		}
+		t.UpdatedAt = shared.NewTimestamp()
+		return t, nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to give tutorial net", zap.String("userId", userID.String()), zap.Error(err))
+	}
+
+	wild, err := s.spawnManager.SpawnAt(ctx, practiceAnimalType, practiceAnimalLevel, progress.Room.AnimalSpot())
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to place practice animal", zap.String("userId", userID.String()), zap.Error(err))
+		return
+	}
+
+	err = s.progress.FindOneAndUpdate(ctx, userID, func(p *tutorial.Progress) error {
+		if p.Room != nil {
+			p.Room.AnimalID = wild.ID.String()
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to record practice animal", zap.String("userId", userID.String()), zap.Error(err))
+		return
+	}
+	progress.Room.AnimalID = wild.ID.String()
+}
+
+// rangeDirection picks a direction from position whose way to the marker is walkable, so the
+// move step can be done; without one the range opens along the first direction
+func (s *TutorialService) rangeDirection(position shared.Position) shared.Position {
+	for _, direction := range rangeDirections {
+		walkable := true
+		for along := 1.0; along <= tutorial.MarkerDistance && walkable; along++ {
+			walkable = s.terrain.IsWalkablePosition(shared.NewPosition(position.X+direction.X*along, position.Y+direction.Y*along))
+		}
+		if walkable {
+			return direction
+		}
+	}
+	return rangeDirections[0]
+}
+
+// send pushes a tutorial.progress message to the trainer's clients
+func (s *TutorialService) send(ctx context.Context, update tutorialUpdate) {
+	userID := update.Progress.UserID.String()
+	if err := s.push.BroadcastToUsers(ctx, []string{userID}, "tutorial.progress", update); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to send tutorial progress", zap.String("userId", userID), zap.Error(err))
+	}
+}
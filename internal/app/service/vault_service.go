@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/domain/fairness"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/vault"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// VaultService moves items between the carried inventory and the account vault
+type VaultService struct {
+	logger      *logger.Logger
+	locations   []vault.Location
+	vaultRepo   vault.Repository
+	trainerRepo trainer.Repository
+	randomness  *RandomnessService
+}
+
+// NewVaultService creates a new vault service
+func NewVaultService(logger *logger.Logger, locations []vault.Location, vaultRepo vault.Repository, trainerRepo trainer.Repository, randomness *RandomnessService) *VaultService {
+	return &VaultService{
+		logger:      logger.WithComponent("vault-service"),
+		locations:   locations,
+		vaultRepo:   vaultRepo,
+		trainerRepo: trainerRepo,
+		randomness:  randomness,
+	}
+}
+
+// Locations returns the places where the vault can be accessed
+func (s *VaultService) Locations() []vault.Location {
+	return s.locations
+}
+
+// GetVault returns the user's vault
+func (s *VaultService) GetVault(ctx context.Context, userID trainer.UserID) (*vault.Vault, error) {
+	return s.vaultRepo.GetByUserID(ctx, userID)
+}
+
+// Deposit moves items from the carried inventory into the vault
+func (s *VaultService) Deposit(ctx context.Context, userID trainer.UserID, itemIDs []trainer.ItemID) (*vault.Vault, error) {
+	var result *vault.Vault
+
+	err := s.vaultRepo.Transfer(ctx, userID, func(t *trainer.Trainer, v *vault.Vault) error {
+		if err := s.requireLocation(t); err != nil {
+			return err
+		}
+
+		items, err := t.Inventory.TakeTransferable(itemIDs)
+		if err != nil {
+			return err
+		}
+		for _, item := range items {
+			if err := v.Deposit(item); err != nil {
+				return err
+			}
+		}
+
+		t.UpdatedAt = shared.NewTimestamp()
+		result = v
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Items deposited",
+		zap.String("userID", userID.String()),
+		zap.Int("items", len(itemIDs)))
+
+	return result, nil
+}
+
+// Withdraw moves items from the vault into the carried inventory
+func (s *VaultService) Withdraw(ctx context.Context, userID trainer.UserID, itemIDs []trainer.ItemID) (*vault.Vault, error) {
+	var result *vault.Vault
+
+	err := s.vaultRepo.Transfer(ctx, userID, func(t *trainer.Trainer, v *vault.Vault) error {
+		if err := s.requireLocation(t); err != nil {
+			return err
+		}
+
+		for _, itemID := range itemIDs {
+			item, err := v.Withdraw(itemID)
+			if err != nil {
+				return err
+			}
+			if err := t.Inventory.AddItem(item); err != nil {
+				return err
+			}
+		}
+
+		t.UpdatedAt = shared.NewTimestamp()
+		result = v
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithContext(ctx).Info("Items withdrawn",
+		zap.String("userID", userID.String()),
+		zap.Int("items", len(itemIDs)))
+
+	return result, nil
+}
+
+// ApplyDeathLoss drops part of a dead trainer's carried inventory; vaulted items are kept
+func (s *VaultService) ApplyDeathLoss(ctx context.Context, userID trainer.UserID) ([]*trainer.Item, error) {
+	var lost []*trainer.Item
+
+	session, err := s.randomness.Begin(ctx, fairness.PurposeDeathLoss, userID.String(), "", map[string]any{
+		"loss_chance": vault.DeathLossChance,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.trainerRepo.FindOneAndUpdate(ctx, userID, func(t *trainer.Trainer) (*trainer.Trainer, error) {
+		lost = vault.ApplyDeathLoss(&t.Inventory, vault.DeathLossChance, session.Rand())
+
+		if len(lost) == 0 {
+			return nil, nil // Nothing lost
+		}
+
+		t.UpdatedAt = shared.NewTimestamp()
+		return t, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	lostIDs := make([]string, len(lost))
+	for i, item := range lost {
+		lostIDs[i] = item.ID.String()
+	}
+	s.randomness.Record(ctx, session, map[string][]string{"lost_item_ids": lostIDs})
+
+	s.logger.WithContext(ctx).Info("Death item loss applied",
+		zap.String("userID", userID.String()),
+		zap.Int("lost", len(lost)))
+
+	return lost, nil
+}
+
+// requireLocation checks the trainer is standing at a vault location
+func (s *VaultService) requireLocation(t *trainer.Trainer) error {
+	position := t.Movement.CalculateCurrentPosition()
+
+	if _, ok := vault.FindLocation(s.locations, position); !ok {
+		return shared.NewDomainError(shared.ErrCodeNotAtVault, "Vault can only be accessed at a base or outpost")
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/world"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+const (
+	// How often the world clock is advanced. A game minute lasts dayLength/1440, two seconds
+	// by default, so the stored clock trails real time by a few game minutes at most.
+	clockInterval = 5 * time.Second
+	// Lock key so only one server instance advances the clock per tick
+	clockLockKey = "lock:world:clock"
+)
+
+// WorldClockService advances the world clock, cycling day and night and rolling the weather,
+// and announces every new hour and weather to clients
+type WorldClockService struct {
+	logger      *logger.Logger
+	clockRepo   world.ClockRepository
+	eventBus    *cqrs.EventBus
+	redisClient *redis.Client
+	dayLength   time.Duration
+	rng         *rand.Rand
+	stopChan    chan struct{}
+	ticker      *time.Ticker
+}
+
+// NewWorldClockService creates a new world clock service running a game day per dayLength
+func NewWorldClockService(logger *logger.Logger, clockRepo world.ClockRepository, eventBus *cqrs.EventBus, redisClient *redis.Client, dayLength time.Duration) *WorldClockService {
+	if dayLength <= 0 {
+		dayLength = world.DefaultDayLength
+	}
+
+	return &WorldClockService{
+		logger:      logger.WithComponent("world-clock-service"),
+		clockRepo:   clockRepo,
+		eventBus:    eventBus,
+		redisClient: redisClient,
+		dayLength:   dayLength,
+		rng:         rand.New(rand.NewSource(time.Now().UnixNano())),
+		stopChan:    make(chan struct{}),
+	}
+}
+
+// Start begins advancing the clock
+func (s *WorldClockService) Start(ctx context.Context) {
+	s.ticker = time.NewTicker(clockInterval)
+
+	s.logger.WithContext(ctx).Info("Starting world clock",
+		zap.Duration("interval", clockInterval),
+		zap.Duration("day_length", s.dayLength))
+
+	go s.clockLoop(ctx)
+}
+
+// Stop stops advancing the clock
+func (s *WorldClockService) Stop() {
+	s.logger.Info("Stopping world clock")
+
+	if s.ticker != nil {
+		s.ticker.Stop()
+	}
+
+	close(s.stopChan)
+}
+
+// clockLoop advances the clock on every tick
+func (s *WorldClockService) clockLoop(ctx context.Context) {
+	s.tick(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.stopChan:
+			return
+		case <-s.ticker.C:
+			s.tick(ctx)
+		}
+	}
+}
+
+// tick advances the stored clock to now, starting it if the world has none yet, and
+// announces the change when the hour or weather turned
+func (s *WorldClockService) tick(ctx context.Context) {
+	// Every server runs the loop; only the one holding the lock advances the clock this tick
+	acquired, err := s.redisClient.SetNX(ctx, clockLockKey, "1", clockInterval/2).Result()
+	if err != nil || !acquired {
+		return
+	}
+
+	var changed *world.Clock
+	err = s.clockRepo.FindOneAndUpsert(ctx, func(clock *world.Clock) (*world.Clock, error) {
+		now := time.Now()
+		if clock == nil {
+			changed = world.NewClock(now, s.rng)
+			return changed, nil
+		}
+
+		changed = nil
+		if clock.Advance(now, s.dayLength, s.rng) {
+			changed = clock
+		}
+		return clock, nil
+	})
+	if err != nil {
+		s.logger.WithContext(ctx).Error("Failed to advance world clock", zap.Error(err))
+		return
+	}
+	if changed == nil {
+		return
+	}
+
+	event := &cqrscommands.WorldTimeChangedEvent{
+		Time:      changed.Reading(s.dayLength),
+		Timestamp: time.Now(),
+		RequestID: requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(ctx, event); err != nil {
+		s.logger.WithContext(ctx).Error("Failed to publish world time changed event", zap.Error(err))
+	}
+}
+
+// Now returns the current game time and weather as last advanced
+func (s *WorldClockService) Now(ctx context.Context) (*world.ClockReading, error) {
+	clock, err := s.clockRepo.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if clock == nil {
+		return nil, shared.ErrNotFound("World clock")
+	}
+
+	reading := clock.Reading(s.dayLength)
+	return &reading, nil
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"go.uber.org/zap"
+
+	cqrscommands "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/world"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// WorldService keeps a server's copy of the world in line with the stored world, which
+// admins edit live and operators edit with lifectl. The world is shared by every tenant and
+// stored with the default tenant's keys.
+type WorldService struct {
+	logger    *logger.Logger
+	worldRepo world.Repository
+	world     *world.World
+	interest  *InterestManager
+	eventBus  *cqrs.EventBus
+	push      *cqrscommands.SSEBroadcastHelper
+}
+
+// NewWorldService creates a new world service for the server's copy of the world
+func NewWorldService(logger *logger.Logger, worldRepo world.Repository, gameWorld *world.World, interest *InterestManager, eventBus *cqrs.EventBus, push *cqrscommands.SSEBroadcastHelper) *WorldService {
+	return &WorldService{
+		logger:    logger.WithComponent("world-service"),
+		worldRepo: worldRepo,
+		world:     gameWorld,
+		interest:  interest,
+		eventBus:  eventBus,
+		push:      push,
+	}
+}
+
+// LoadWorld returns the stored default world, generating and storing it with the given size
+// when it doesn't exist yet. A stored world keeps its size when the configured one changes.
+func LoadWorld(ctx context.Context, worldRepo world.Repository, width, height int) (*world.World, error) {
+	var loaded *world.World
+	err := worldRepo.FindOneAndUpsert(sharedWorld(ctx), world.DefaultID, func(current *world.World) (*world.World, error) {
+		if current != nil {
+			loaded = current
+			return nil, nil // Nothing to store
+		}
+
+		created, err := world.NewWorld(world.DefaultName, width, height)
+		if err != nil {
+			return nil, err
+		}
+		created.ID = world.DefaultID
+		loaded = created
+		return created, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return loaded, nil
+}
+
+// EditTiles applies an admin's edits to the stored world. Every server then reloads the
+// changed chunks, and players who can see them are told to refetch them.
+func (s *WorldService) EditTiles(ctx context.Context, adminID string, edits []world.TileEdit) (*world.EditResult, error) {
+	var result *world.EditResult
+	err := s.worldRepo.FindOneAndUpdate(sharedWorld(ctx), s.world.ID, func(w *world.World) (*world.World, error) {
+		var err error
+		result, err = w.ApplyEdits(edits)
+		if err != nil {
+			return nil, err
+		}
+		if result.Changed == 0 {
+			return nil, nil // No changes
+		}
+		return w, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if result.Changed == 0 {
+		return result, nil
+	}
+
+	event := &cqrscommands.WorldUpdatedEvent{
+		WorldID:   s.world.ID.String(),
+		Chunks:    result.Chunks,
+		Change:    "admin_edit",
+		Timestamp: time.Now(),
+		RequestID: requestid.FromContextOrNew(ctx),
+	}
+	if err := s.eventBus.Publish(sharedWorld(ctx), event); err != nil {
+		// The edit is stored; servers pick it up when they next start
+		s.logger.WithContext(ctx).Error("Failed to publish world updated event",
+			zap.String("adminID", adminID),
+			zap.Error(err))
+	}
+
+	s.notifyChunks(ctx, result.Chunks)
+
+	s.logger.WithContext(ctx).Info("World edited by admin",
+		zap.String("adminID", adminID),
+		zap.Int("edits", len(edits)),
+		zap.Int("changed", result.Changed),
+		zap.Int("chunks", len(result.Chunks)),
+		zap.Bool("connected", result.Connectivity.Connected()))
+	return result, nil
+}
+
+// HandleWorldUpdatedEvent replaces the chunks an edit touched with the stored ones
+func (s *WorldService) HandleWorldUpdatedEvent(ctx context.Context, event *cqrscommands.WorldUpdatedEvent) error {
+	if event.WorldID != s.world.ID.String() {
+		return nil
+	}
+
+	stored, err := s.worldRepo.GetByID(sharedWorld(ctx), world.WorldID(event.WorldID))
+	if err != nil {
+		return err
+	}
+	if stored == nil {
+		s.logger.WithContext(ctx).Warn("Updated world is no longer stored", zap.String("worldID", event.WorldID))
+		return nil
+	}
+
+	replaced := s.world.ReplaceChunks(stored, event.Chunks)
+
+	s.logger.WithContext(ctx).Info("World chunks reloaded",
+		zap.String("worldID", event.WorldID),
+		zap.String("change", event.Change),
+		zap.Int("chunks", len(event.Chunks)),
+		zap.Int("tiles", replaced),
+		zap.String("requestId", event.RequestID))
+	return nil
+}
+
+// notifyChunks pushes world.chunks_invalidated to the players who can see any of the chunks
+func (s *WorldService) notifyChunks(ctx context.Context, chunks []world.Chunk) {
+	recipients := make(map[string]bool)
+	for _, chunk := range chunks {
+		from := shared.NewPosition(float64(chunk.X*world.ChunkSize), float64(chunk.Y*world.ChunkSize))
+		to := shared.NewPosition(from.X+world.ChunkSize, from.Y+world.ChunkSize)
+
+		userIDs, err := s.interest.UsersInArea(ctx, from, to)
+		if err != nil {
+			s.logger.WithContext(ctx).Warn("Failed to find players near edited chunks", zap.Error(err))
+			return
+		}
+		for _, userID := range userIDs {
+			recipients[userID] = true
+		}
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	userIDs := make([]string, 0, len(recipients))
+	for userID := range recipients {
+		userIDs = append(userIDs, userID)
+	}
+	invalidated := world.ChunksInvalidated{Chunks: chunks, ChunkSize: world.ChunkSize}
+	if err := s.push.BroadcastToUsers(ctx, userIDs, "world.chunks_invalidated", invalidated); err != nil {
+		s.logger.WithContext(ctx).Warn("Failed to push chunk invalidation",
+			zap.Int("players", len(userIDs)),
+			zap.Error(err))
+	}
+}
+
+// sharedWorld scopes a context to the default tenant, whose keys hold the shared world and
+// the streams every server subscribes to for world updates
+func sharedWorld(ctx context.Context) context.Context {
+	return tenant.WithTenant(ctx, &tenant.Tenant{ID: tenant.Default})
+}
@@ -3,32 +3,72 @@ package cqrs
 import (
 	"time"
 
+	"github.com/danghamo/life/internal/domain/battle"
+	"github.com/danghamo/life/internal/domain/bullet"
+	"github.com/danghamo/life/internal/domain/chat"
+	"github.com/danghamo/life/internal/domain/loot"
+	"github.com/danghamo/life/internal/domain/match"
 	"github.com/danghamo/life/internal/domain/shared"
 	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/internal/domain/world"
 )
 
+// Hot types get generated marshalers in builds tagged easyjson; see pkg/jsonx
+//go:generate go run github.com/mailru/easyjson/easyjson -build_tags easyjson -no_std_marshalers events.go
+
 // TrainerMovedEvent represents a domain event when a trainer moves
+//
+//easyjson:json
 type TrainerMovedEvent struct {
-	UserID    string                 `json:"user_id"`
-	Nickname  string                 `json:"nickname"`
-	Color     string                 `json:"color"`
-	Position  shared.Position        `json:"position"`
-	Movement  trainer.MovementState  `json:"movement"`
-	Timestamp time.Time              `json:"timestamp"`
-	RequestID string                 `json:"request_id"`
-	Changes   map[string]interface{} `json:"changes,omitempty"`
+	UserID    string                    `json:"user_id"`
+	Nickname  string                    `json:"nickname"`
+	Color     string                    `json:"color"`
+	Showcase  []trainer.ShowcasedAnimal `json:"showcase,omitempty"` // Pinned animals shown on the nameplate
+	Position  shared.Position           `json:"position"`
+	Movement  trainer.MovementState     `json:"movement"`
+	Timestamp time.Time                 `json:"timestamp"`
+	RequestID string                    `json:"request_id"`
+	Changes   map[string]interface{}    `json:"changes,omitempty"`
+}
+
+// PositionsBatchEvent carries the positions of every trainer the movement simulation advanced
+// in one tick, in place of an event per trainer. Entries are deltas: a trainer's movement and
+// appearance are only included when they changed since the previous batch, except in
+// keyframes, which include everything so clients that missed batches catch up.
+type PositionsBatchEvent struct {
+	Timestamp   time.Time       `json:"timestamp"`
+	Keyframe    bool            `json:"keyframe,omitempty"`
+	ReducedBeat bool            `json:"reduced_beat,omitempty"` // Also sent to players on the reduced broadcast rate
+	Trainers    []PositionDelta `json:"trainers"`
+}
+
+// PositionDelta is a trainer's entry in a positions batch, with short keys since a batch is
+// sent up to 60 times a second
+type PositionDelta struct {
+	UserID     string                 `json:"u"`
+	X          float64                `json:"x"`
+	Y          float64                `json:"y"`
+	Movement   *trainer.MovementState `json:"m,omitempty"` // Only when changed
+	Appearance *TrainerAppearance     `json:"a,omitempty"` // Only when changed
+}
+
+// TrainerAppearance is how a trainer looks on the map
+type TrainerAppearance struct {
+	Color    string                    `json:"color"`
+	Showcase []trainer.ShowcasedAnimal `json:"showcase,omitempty"` // Pinned animals shown on the nameplate
 }
 
 // TrainerStoppedEvent represents a domain event when a trainer stops moving
 type TrainerStoppedEvent struct {
-	UserID    string                 `json:"user_id"`
-	Nickname  string                 `json:"nickname"`
-	Color     string                 `json:"color"`
-	Position  shared.Position        `json:"position"`
-	Movement  trainer.MovementState  `json:"movement"`
-	Timestamp time.Time              `json:"timestamp"`
-	RequestID string                 `json:"request_id"`
-	Changes   map[string]interface{} `json:"changes,omitempty"`
+	UserID    string                    `json:"user_id"`
+	Nickname  string                    `json:"nickname"`
+	Color     string                    `json:"color"`
+	Showcase  []trainer.ShowcasedAnimal `json:"showcase,omitempty"` // Pinned animals shown on the nameplate
+	Position  shared.Position           `json:"position"`
+	Movement  trainer.MovementState     `json:"movement"`
+	Timestamp time.Time                 `json:"timestamp"`
+	RequestID string                    `json:"request_id"`
+	Changes   map[string]interface{}    `json:"changes,omitempty"`
 }
 
 // TrainerCreatedEvent represents a domain event when a trainer is created
@@ -39,6 +79,33 @@ type TrainerCreatedEvent struct {
 	RequestID string           `json:"request_id"`
 }
 
+// TrainerDiedEvent represents a trainer's HP running out; it lies where it fell until it
+// respawns
+type TrainerDiedEvent struct {
+	UserID    string             `json:"user_id"`
+	Nickname  string             `json:"nickname"`
+	Position  shared.Position    `json:"position"`
+	Cause     trainer.DeathCause `json:"cause"`
+	SourceID  string             `json:"source_id,omitempty"` // Animal or player that dealt the final blow
+	MoneyLost int                `json:"money_lost"`
+	ItemsLost []string           `json:"items_lost,omitempty"` // IDs of the carried item stacks dropped
+	RespawnAt time.Time          `json:"respawn_at"`
+	Timestamp time.Time          `json:"timestamp"`
+	RequestID string             `json:"request_id"`
+}
+
+// TrainerRespawnedEvent represents a dead trainer coming back at a spawn point at full health
+type TrainerRespawnedEvent struct {
+	UserID    string          `json:"user_id"`
+	Nickname  string          `json:"nickname"`
+	Color     string          `json:"color"`
+	Position  shared.Position `json:"position"`
+	SpawnID   string          `json:"spawn_id"` // Spawn point the trainer came back at
+	HP        int             `json:"hp"`
+	Timestamp time.Time       `json:"timestamp"`
+	RequestID string          `json:"request_id"`
+}
+
 // SSENotificationEvent represents an event to send SSE notifications
 type SSENotificationEvent struct {
 	Type        string      `json:"type"`
@@ -54,3 +121,275 @@ const (
 	SSENotificationTypeBroadcast = "broadcast" // Send to all users
 	SSENotificationTypeUsers     = "users"     // Send to specific list of users
 )
+
+// AnimalSpawnedEvent represents a wild animal appearing in the world
+type AnimalSpawnedEvent struct {
+	AnimalID   string          `json:"animal_id"`
+	AnimalType string          `json:"animal_type"`
+	Level      int             `json:"level"`
+	Position   shared.Position `json:"position"`
+	Timestamp  time.Time       `json:"timestamp"`
+	RequestID  string          `json:"request_id"`
+}
+
+// AnimalMovedEvent represents a wild animal stepping to a neighboring tile, wandering or
+// chasing a trainer
+type AnimalMovedEvent struct {
+	AnimalID   string          `json:"animal_id"`
+	AnimalType string          `json:"animal_type"`
+	From       shared.Position `json:"from"`
+	To         shared.Position `json:"to"`
+	Behavior   string          `json:"behavior"`            // wander or chase
+	TargetID   string          `json:"target_id,omitempty"` // Trainer being chased
+	Timestamp  time.Time       `json:"timestamp"`
+	RequestID  string          `json:"request_id"`
+}
+
+// AnimalCapturedEvent represents a wild animal being captured by a trainer
+type AnimalCapturedEvent struct {
+	UserID     string          `json:"user_id"`
+	AnimalID   string          `json:"animal_id"`
+	AnimalType string          `json:"animal_type"`
+	Level      int             `json:"level"`
+	NetType    string          `json:"net_type"`
+	Placement  string          `json:"placement"` // "party" or "storage"
+	Position   shared.Position `json:"position"`  // Where the animal was captured
+	Timestamp  time.Time       `json:"timestamp"`
+	RequestID  string          `json:"request_id"`
+}
+
+// BattleStartedEvent represents a trainer's animal challenging a wild animal
+type BattleStartedEvent struct {
+	BattleID   string          `json:"battle_id"`
+	UserID     string          `json:"user_id"`
+	AnimalID   string          `json:"animal_id"`
+	AnimalType string          `json:"animal_type"`
+	WildID     string          `json:"wild_id"`
+	WildType   string          `json:"wild_type"`
+	WildLevel  int             `json:"wild_level"`
+	Position   shared.Position `json:"position"`
+	Timestamp  time.Time       `json:"timestamp"`
+	RequestID  string          `json:"request_id"`
+}
+
+// BattleTurnEvent represents one played battle turn
+type BattleTurnEvent struct {
+	BattleID  string          `json:"battle_id"`
+	UserID    string          `json:"user_id"`
+	Turn      battle.Turn     `json:"turn"`
+	Status    string          `json:"status"`
+	Position  shared.Position `json:"position"`
+	Timestamp time.Time       `json:"timestamp"`
+	RequestID string          `json:"request_id"`
+}
+
+// BattleEndedEvent represents a battle finishing
+type BattleEndedEvent struct {
+	BattleID   string          `json:"battle_id"`
+	UserID     string          `json:"user_id"`
+	AnimalID   string          `json:"animal_id"`
+	WildID     string          `json:"wild_id"`
+	Status     string          `json:"status"`
+	Experience int             `json:"experience,omitempty"`
+	Position   shared.Position `json:"position"`
+	Timestamp  time.Time       `json:"timestamp"`
+	RequestID  string          `json:"request_id"`
+}
+
+// LootDroppedEvent records loot rolled from a defeated animal for the drop ledger
+type LootDroppedEvent struct {
+	UserID     string          `json:"user_id"`
+	AnimalID   string          `json:"animal_id"`
+	AnimalType string          `json:"animal_type"`
+	Level      int             `json:"level"`
+	Mode       string          `json:"mode"`
+	Drops      []loot.Drop     `json:"drops"`
+	PickupIDs  []string        `json:"pickup_ids,omitempty"`
+	Position   shared.Position `json:"position"`
+	Timestamp  time.Time       `json:"timestamp"`
+	RequestID  string          `json:"request_id"`
+}
+
+// LootPickedUpEvent records a world pickup being collected
+type LootPickedUpEvent struct {
+	UserID    string    `json:"user_id"`
+	PickupID  string    `json:"pickup_id"`
+	Drop      loot.Drop `json:"drop"`
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"request_id"`
+}
+
+// CraftStartedEvent records materials being consumed into a crafting job
+type CraftStartedEvent struct {
+	UserID    string    `json:"user_id"`
+	JobID     string    `json:"job_id"`
+	RecipeID  string    `json:"recipe_id"`
+	ReadyAt   time.Time `json:"ready_at"`
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"request_id"`
+}
+
+// CraftCompletedEvent records the outcome of a finished crafting job
+type CraftCompletedEvent struct {
+	UserID    string    `json:"user_id"`
+	JobID     string    `json:"job_id"`
+	RecipeID  string    `json:"recipe_id"`
+	Succeeded bool      `json:"succeeded"`
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"request_id"`
+}
+
+// ItemConsumedEvent records a consumable item being used on a trainer or animal
+type ItemConsumedEvent struct {
+	UserID       string                `json:"user_id"`
+	ItemID       string                `json:"item_id"`
+	ItemType     trainer.ItemType      `json:"item_type"`
+	TargetType   string                `json:"target_type"` // "trainer" or "animal"
+	TargetID     string                `json:"target_id"`
+	Healed       int                   `json:"healed"`
+	ManaRestored int                   `json:"mana_restored"`
+	Effect       *trainer.StatusEffect `json:"effect,omitempty"`
+	CooldownEnds time.Time             `json:"cooldown_ends"`
+	Timestamp    time.Time             `json:"timestamp"`
+	RequestID    string                `json:"request_id"`
+}
+
+// InventoryChangedEvent records items leaving a trainer's inventory through an inventory action
+type InventoryChangedEvent struct {
+	UserID    string          `json:"user_id"`
+	Action    string          `json:"action"` // "use", "drop", "sell", "move_to_vault" or "give"
+	Items     []*trainer.Item `json:"items"`  // The removed items with the quantities removed
+	UsedSlots int             `json:"used_slots"`
+	Timestamp time.Time       `json:"timestamp"`
+	RequestID string          `json:"request_id"`
+}
+
+// BulletFiredEvent represents a shot fired by a trainer so clients can render it
+type BulletFiredEvent struct {
+	UserID     string            `json:"user_id"`
+	BulletID   string            `json:"bullet_id"`
+	WeaponType bullet.WeaponType `json:"weapon_type"`
+	StartPos   shared.Position   `json:"start_position"`
+	Velocity   bullet.Velocity   `json:"velocity"`
+	MaxRange   float64           `json:"max_range"`
+	FiredAt    time.Time         `json:"fired_at"`
+	ExpiresAt  time.Time         `json:"expires_at"`
+	RoomID     string            `json:"room_id,omitempty"`    // Room the bullet was fired in
+	Recipients []string          `json:"recipients,omitempty"` // Room members (empty for every player)
+	Timestamp  time.Time         `json:"timestamp"`
+	RequestID  string            `json:"request_id"`
+}
+
+// BulletHitEvent records a bullet hitting a trainer. The hit is checked against where the
+// target was when the shooter saw it, rewound by the shooter's lag.
+type BulletHitEvent struct {
+	UserID     string            `json:"user_id"` // Shooter
+	BulletID   string            `json:"bullet_id"`
+	WeaponType bullet.WeaponType `json:"weapon_type"`
+	TargetID   string            `json:"target_id"`
+	Position   shared.Position   `json:"position"` // Where the bullet hit
+	Damage     int               `json:"damage"`
+	Killed     bool              `json:"killed"`
+	RewindMs   int64             `json:"rewind_ms"` // How far the target was rewound
+	HitAt      time.Time         `json:"hit_at"`
+	RoomID     string            `json:"room_id,omitempty"`    // Room the bullet was fired in
+	Recipients []string          `json:"recipients,omitempty"` // Room members (empty for every player)
+	Timestamp  time.Time         `json:"timestamp"`
+	RequestID  string            `json:"request_id"`
+}
+
+// AmmoChangedEvent records a trainer's magazine or reserve ammo changing outside of firing,
+// so every client of the trainer shows the same ammo
+type AmmoChangedEvent struct {
+	UserID         string            `json:"user_id"`
+	WeaponType     bullet.WeaponType `json:"weapon_type"`
+	Magazine       int               `json:"magazine"`
+	Reserve        int               `json:"reserve"`
+	ReloadingUntil *time.Time        `json:"reloading_until,omitempty"`
+	Reason         string            `json:"reason"`               // reload or purchase
+	BoxesUsed      int               `json:"boxes_used,omitempty"` // Ammo boxes unpacked or bought
+	Timestamp      time.Time         `json:"timestamp"`
+	RequestID      string            `json:"request_id"`
+}
+
+// RoomJoinedEvent records a player joining a room
+type RoomJoinedEvent struct {
+	RoomID    string    `json:"room_id"`
+	UserID    string    `json:"user_id"`
+	Members   []string  `json:"members"` // Members after joining, the player included
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"request_id"`
+}
+
+// RoomLeftEvent records a player leaving a room. The room closes when its last member leaves.
+type RoomLeftEvent struct {
+	RoomID    string    `json:"room_id"`
+	UserID    string    `json:"user_id"`
+	OwnerID   string    `json:"owner_id"` // Owner after leaving, who changes when the owner leaves
+	Members   []string  `json:"members"`  // Members left in the room
+	Closed    bool      `json:"closed"`
+	Timestamp time.Time `json:"timestamp"`
+	RequestID string    `json:"request_id"`
+}
+
+// MatchStartedEvent records a match starting between the members of a room
+type MatchStartedEvent struct {
+	MatchID    string          `json:"match_id"`
+	RoomID     string          `json:"room_id"`
+	Mode       match.Mode      `json:"mode"`
+	ScoreLimit int             `json:"score_limit"`
+	Players    []*match.Player `json:"players"`
+	EndsAt     time.Time       `json:"ends_at"`
+	Timestamp  time.Time       `json:"timestamp"`
+	RequestID  string          `json:"request_id"`
+}
+
+// MatchScoreChangedEvent records a kill changing a match's scoreboard
+type MatchScoreChangedEvent struct {
+	MatchID    string             `json:"match_id"`
+	RoomID     string             `json:"room_id"`
+	KillerID   string             `json:"killer_id"`
+	VictimID   string             `json:"victim_id"`
+	Players    []*match.Player    `json:"players"`
+	TeamScores map[match.Team]int `json:"team_scores,omitempty"` // Only in team mode
+	Timestamp  time.Time          `json:"timestamp"`
+	RequestID  string             `json:"request_id"`
+}
+
+// MatchEndedEvent records a match ending, on time or at its score limit, with its results
+type MatchEndedEvent struct {
+	MatchID   string        `json:"match_id"`
+	RoomID    string        `json:"room_id"`
+	Mode      match.Mode    `json:"mode"`
+	Result    *match.Result `json:"result"`
+	Timestamp time.Time     `json:"timestamp"`
+	RequestID string        `json:"request_id"`
+}
+
+// ChatMessageEvent represents a chat message to deliver to its recipients
+type ChatMessageEvent struct {
+	SenderID   string        `json:"sender_id"`
+	Message    *chat.Message `json:"message"`
+	Recipients []string      `json:"recipients,omitempty"` // UserIDs to deliver to (empty for every player)
+	Timestamp  time.Time     `json:"timestamp"`
+	RequestID  string        `json:"request_id"`
+}
+
+// WorldUpdatedEvent announces an edit of a stored world so every server reloads the chunks
+// it touched. Servers receive it through their own subscription rather than the shared
+// consumer group.
+type WorldUpdatedEvent struct {
+	WorldID   string        `json:"world_id"`
+	Chunks    []world.Chunk `json:"chunks"`
+	Change    string        `json:"change"` // The editing command, e.g. "paint"
+	Timestamp time.Time     `json:"timestamp"`
+	RequestID string        `json:"request_id"`
+}
+
+// WorldTimeChangedEvent announces the game hour or weather changing so clients can update
+// their lighting and weather effects
+type WorldTimeChangedEvent struct {
+	Time      world.ClockReading `json:"time"`
+	Timestamp time.Time          `json:"timestamp"`
+	RequestID string             `json:"request_id"`
+}
@@ -0,0 +1,471 @@
+//go:build easyjson
+// +build easyjson
+
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package cqrs
+
+import (
+	json "encoding/json"
+	shared "github.com/danghamo/life/internal/domain/shared"
+	trainer "github.com/danghamo/life/internal/domain/trainer"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson692db02bDecodeGithubComDanghamoLifeInternalCqrs(in *jlexer.Lexer, out *TrainerMovedEvent) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "user_id":
+			out.UserID = string(in.String())
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "color":
+			out.Color = string(in.String())
+		case "showcase":
+			if in.IsNull() {
+				in.Skip()
+				out.Showcase = nil
+			} else {
+				in.Delim('[')
+				if out.Showcase == nil {
+					if !in.IsDelim(']') {
+						out.Showcase = make([]trainer.ShowcasedAnimal, 0, 1)
+					} else {
+						out.Showcase = []trainer.ShowcasedAnimal{}
+					}
+				} else {
+					out.Showcase = (out.Showcase)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v1 trainer.ShowcasedAnimal
+					easyjson692db02bDecodeGithubComDanghamoLifeInternalDomainTrainer(in, &v1)
+					out.Showcase = append(out.Showcase, v1)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "position":
+			easyjson692db02bDecodeGithubComDanghamoLifeInternalDomainShared(in, &out.Position)
+		case "movement":
+			easyjson692db02bDecodeGithubComDanghamoLifeInternalDomainTrainer1(in, &out.Movement)
+		case "timestamp":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Timestamp).UnmarshalJSON(data))
+			}
+		case "request_id":
+			out.RequestID = string(in.String())
+		case "changes":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				if !in.IsDelim('}') {
+					out.Changes = make(map[string]interface{})
+				} else {
+					out.Changes = nil
+				}
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v2 interface{}
+					if m, ok := v2.(easyjson.Unmarshaler); ok {
+						m.UnmarshalEasyJSON(in)
+					} else if m, ok := v2.(json.Unmarshaler); ok {
+						_ = m.UnmarshalJSON(in.Raw())
+					} else {
+						v2 = in.Interface()
+					}
+					(out.Changes)[key] = v2
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson692db02bEncodeGithubComDanghamoLifeInternalCqrs(out *jwriter.Writer, in TrainerMovedEvent) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"user_id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.UserID))
+	}
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix)
+		out.String(string(in.Nickname))
+	}
+	{
+		const prefix string = ",\"color\":"
+		out.RawString(prefix)
+		out.String(string(in.Color))
+	}
+	if len(in.Showcase) != 0 {
+		const prefix string = ",\"showcase\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v3, v4 := range in.Showcase {
+				if v3 > 0 {
+					out.RawByte(',')
+				}
+				easyjson692db02bEncodeGithubComDanghamoLifeInternalDomainTrainer(out, v4)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"position\":"
+		out.RawString(prefix)
+		easyjson692db02bEncodeGithubComDanghamoLifeInternalDomainShared(out, in.Position)
+	}
+	{
+		const prefix string = ",\"movement\":"
+		out.RawString(prefix)
+		easyjson692db02bEncodeGithubComDanghamoLifeInternalDomainTrainer1(out, in.Movement)
+	}
+	{
+		const prefix string = ",\"timestamp\":"
+		out.RawString(prefix)
+		out.Raw((in.Timestamp).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"request_id\":"
+		out.RawString(prefix)
+		out.String(string(in.RequestID))
+	}
+	if len(in.Changes) != 0 {
+		const prefix string = ",\"changes\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('{')
+			v5First := true
+			for v5Name, v5Value := range in.Changes {
+				if v5First {
+					v5First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v5Name))
+				out.RawByte(':')
+				if m, ok := v5Value.(easyjson.Marshaler); ok {
+					m.MarshalEasyJSON(out)
+				} else if m, ok := v5Value.(json.Marshaler); ok {
+					out.Raw(m.MarshalJSON())
+				} else {
+					out.Raw(json.Marshal(v5Value))
+				}
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v TrainerMovedEvent) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson692db02bEncodeGithubComDanghamoLifeInternalCqrs(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *TrainerMovedEvent) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson692db02bDecodeGithubComDanghamoLifeInternalCqrs(l, v)
+}
+func easyjson692db02bDecodeGithubComDanghamoLifeInternalDomainTrainer1(in *jlexer.Lexer, out *trainer.MovementState) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "direction":
+			easyjson692db02bDecodeGithubComDanghamoLifeInternalDomainTrainer2(in, &out.Direction)
+		case "speed":
+			out.Speed = float64(in.Float64())
+		case "start_time":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.StartTime).UnmarshalJSON(data))
+			}
+		case "start_pos":
+			easyjson692db02bDecodeGithubComDanghamoLifeInternalDomainShared(in, &out.StartPos)
+		case "is_moving":
+			out.IsMoving = bool(in.Bool())
+		case "waypoints":
+			if in.IsNull() {
+				in.Skip()
+				out.Waypoints = nil
+			} else {
+				in.Delim('[')
+				if out.Waypoints == nil {
+					if !in.IsDelim(']') {
+						out.Waypoints = make([]shared.Position, 0, 4)
+					} else {
+						out.Waypoints = []shared.Position{}
+					}
+				} else {
+					out.Waypoints = (out.Waypoints)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v6 shared.Position
+					easyjson692db02bDecodeGithubComDanghamoLifeInternalDomainShared(in, &v6)
+					out.Waypoints = append(out.Waypoints, v6)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson692db02bEncodeGithubComDanghamoLifeInternalDomainTrainer1(out *jwriter.Writer, in trainer.MovementState) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"direction\":"
+		out.RawString(prefix[1:])
+		easyjson692db02bEncodeGithubComDanghamoLifeInternalDomainTrainer2(out, in.Direction)
+	}
+	{
+		const prefix string = ",\"speed\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Speed))
+	}
+	{
+		const prefix string = ",\"start_time\":"
+		out.RawString(prefix)
+		out.Raw((in.StartTime).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"start_pos\":"
+		out.RawString(prefix)
+		easyjson692db02bEncodeGithubComDanghamoLifeInternalDomainShared(out, in.StartPos)
+	}
+	{
+		const prefix string = ",\"is_moving\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.IsMoving))
+	}
+	if len(in.Waypoints) != 0 {
+		const prefix string = ",\"waypoints\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v7, v8 := range in.Waypoints {
+				if v7 > 0 {
+					out.RawByte(',')
+				}
+				easyjson692db02bEncodeGithubComDanghamoLifeInternalDomainShared(out, v8)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson692db02bDecodeGithubComDanghamoLifeInternalDomainTrainer2(in *jlexer.Lexer, out *trainer.MovementDirection) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "x":
+			out.X = float64(in.Float64())
+		case "y":
+			out.Y = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson692db02bEncodeGithubComDanghamoLifeInternalDomainTrainer2(out *jwriter.Writer, in trainer.MovementDirection) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"x\":"
+		out.RawString(prefix[1:])
+		out.Float64(float64(in.X))
+	}
+	{
+		const prefix string = ",\"y\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Y))
+	}
+	out.RawByte('}')
+}
+func easyjson692db02bDecodeGithubComDanghamoLifeInternalDomainShared(in *jlexer.Lexer, out *shared.Position) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "x":
+			out.X = float64(in.Float64())
+		case "y":
+			out.Y = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson692db02bEncodeGithubComDanghamoLifeInternalDomainShared(out *jwriter.Writer, in shared.Position) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"x\":"
+		out.RawString(prefix[1:])
+		out.Float64(float64(in.X))
+	}
+	{
+		const prefix string = ",\"y\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Y))
+	}
+	out.RawByte('}')
+}
+func easyjson692db02bDecodeGithubComDanghamoLifeInternalDomainTrainer(in *jlexer.Lexer, out *trainer.ShowcasedAnimal) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		case "level":
+			out.Level = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson692db02bEncodeGithubComDanghamoLifeInternalDomainTrainer(out *jwriter.Writer, in trainer.ShowcasedAnimal) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"level\":"
+		out.RawString(prefix)
+		out.Int(int(in.Level))
+	}
+	out.RawByte('}')
+}
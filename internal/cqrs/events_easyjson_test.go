@@ -0,0 +1,50 @@
+//go:build easyjson
+
+package cqrs
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/jsonx"
+)
+
+func TestTrainerMovedEvent_EasyJSONMatchesStdlib(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	event := &TrainerMovedEvent{
+		UserID:   "user-1",
+		Nickname: "Ash <&>",
+		Color:    "#ff0000",
+		Showcase: []trainer.ShowcasedAnimal{{ID: "a1", Type: "cat", Level: 3}},
+		Position: shared.Position{X: 1.5, Y: -2.25},
+		Movement: trainer.MovementState{
+			Direction: trainer.MovementDirection{X: 1, Y: 0},
+			Speed:     5,
+			StartTime: now,
+			StartPos:  shared.Position{X: 1, Y: -2},
+			IsMoving:  true,
+		},
+		Timestamp: now,
+		RequestID: "req-1",
+		Changes:   map[string]interface{}{"speed": 5.0, "direction": "east"},
+	}
+	require.True(t, jsonx.Generated(event))
+
+	want, err := json.Marshal(event)
+	require.NoError(t, err)
+	got, err := jsonx.Marshal(event)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(want), string(got))
+
+	var decoded TrainerMovedEvent
+	require.NoError(t, jsonx.Unmarshal(got, &decoded))
+	var stdDecoded TrainerMovedEvent
+	require.NoError(t, json.Unmarshal(want, &stdDecoded))
+	assert.Equal(t, stdDecoded, decoded)
+}
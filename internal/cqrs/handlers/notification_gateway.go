@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/domain/notification"
+	"github.com/danghamo/life/pkg/logger"
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+// PreferenceGateway is the single place notification preferences are enforced. Every
+// notification to players goes through it, so subsystems send without checking settings
+// themselves; notifications outside the preference categories pass straight through. It also
+// stamps notifications with the ID of the request that set them off.
+type PreferenceGateway struct {
+	logger      *logger.Logger
+	preferences notification.Repository
+	broadcaster SSEBroadcaster
+}
+
+// NewPreferenceGateway creates a gateway delivering through broadcaster
+func NewPreferenceGateway(logger *logger.Logger, preferences notification.Repository, broadcaster SSEBroadcaster) *PreferenceGateway {
+	return &PreferenceGateway{
+		logger:      logger.WithComponent("notification-gateway"),
+		preferences: preferences,
+		broadcaster: broadcaster,
+	}
+}
+
+// BroadcastToUsers sends a notification to the target users who allow its category
+func (g *PreferenceGateway) BroadcastToUsers(ctx context.Context, targetUsers []string, n jsonrpcx.JsonRpcNotification) {
+	n = withRequestID(ctx, n)
+	category, ok := notification.CategoryOf(n.Method)
+	if !ok || len(targetUsers) == 0 {
+		g.broadcaster.BroadcastToUsers(targetUsers, n)
+		return
+	}
+
+	allowed, err := g.preferences.Allowed(ctx, category, notification.ChannelSSE, targetUsers)
+	if err != nil {
+		// A missed chat message is worse than one a player had muted
+		g.logger.WithContext(ctx).Warn("Failed to check notification preferences, sending to all targets",
+			zap.String("method", n.Method),
+			zap.Error(err))
+		g.broadcaster.BroadcastToUsers(targetUsers, n)
+		return
+	}
+
+	recipients := make([]string, 0, len(targetUsers))
+	for i, userID := range targetUsers {
+		if allowed[i] {
+			recipients = append(recipients, userID)
+		}
+	}
+	if len(recipients) > 0 {
+		g.broadcaster.BroadcastToUsers(recipients, n)
+	}
+}
+
+// BroadcastToAll sends a notification to every user who allows its category
+func (g *PreferenceGateway) BroadcastToAll(ctx context.Context, n jsonrpcx.JsonRpcNotification) {
+	n = withRequestID(ctx, n)
+	category, ok := notification.CategoryOf(n.Method)
+	if !ok {
+		g.broadcaster.BroadcastToAll(n)
+		return
+	}
+
+	changed, err := g.preferences.Changed(ctx, category, notification.ChannelSSE)
+	if err != nil {
+		g.logger.WithContext(ctx).Warn("Failed to check notification preferences, sending to all",
+			zap.String("method", n.Method),
+			zap.Error(err))
+		g.broadcaster.BroadcastToAll(n)
+		return
+	}
+
+	// Players who changed a setting that is on by default turned it off, and the other way round
+	switch {
+	case !notification.Default(category, notification.ChannelSSE):
+		if len(changed) > 0 {
+			g.broadcaster.BroadcastToUsers(changed, n)
+		}
+	case len(changed) > 0:
+		g.broadcaster.BroadcastToAllExcept(changed, n)
+	default:
+		g.broadcaster.BroadcastToAll(n)
+	}
+}
+
+// withRequestID stamps a notification with the request ID ctx carries, unless it has one
+func withRequestID(ctx context.Context, n jsonrpcx.JsonRpcNotification) jsonrpcx.JsonRpcNotification {
+	if n.RequestID == "" {
+		n.RequestID = requestid.FromContext(ctx)
+	}
+	return n
+}
@@ -2,12 +2,16 @@ package handlers
 
 import (
 	"context"
+	"fmt"
+	"slices"
 	"time"
 
 	"go.uber.org/zap"
 
 	"github.com/danghamo/life/internal/api/jsonrpcx"
 	cqrsevents "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/match"
+	"github.com/danghamo/life/internal/domain/shared"
 	"github.com/danghamo/life/pkg/logger"
 )
 
@@ -15,6 +19,76 @@ import (
 type SSEBroadcaster interface {
 	BroadcastToUsers(targetUsers []string, notification jsonrpcx.JsonRpcNotification)
 	BroadcastToAll(notification jsonrpcx.JsonRpcNotification)
+	BroadcastToAllExcept(excludedUsers []string, notification jsonrpcx.JsonRpcNotification)
+	Disconnect(userIDs []string)
+}
+
+// MultiBroadcaster fans notifications out to several transports (e.g. SSE and WebSocket)
+type MultiBroadcaster []SSEBroadcaster
+
+// NewMultiBroadcaster creates a broadcaster that forwards to all given broadcasters
+func NewMultiBroadcaster(broadcasters ...SSEBroadcaster) MultiBroadcaster {
+	return MultiBroadcaster(broadcasters)
+}
+
+// BroadcastToUsers forwards the notification to every transport
+func (m MultiBroadcaster) BroadcastToUsers(targetUsers []string, notification jsonrpcx.JsonRpcNotification) {
+	for _, b := range m {
+		b.BroadcastToUsers(targetUsers, notification)
+	}
+}
+
+// BroadcastToAll forwards the notification to every transport
+func (m MultiBroadcaster) BroadcastToAll(notification jsonrpcx.JsonRpcNotification) {
+	for _, b := range m {
+		b.BroadcastToAll(notification)
+	}
+}
+
+// BroadcastToAllExcept forwards the notification to every transport
+func (m MultiBroadcaster) BroadcastToAllExcept(excludedUsers []string, notification jsonrpcx.JsonRpcNotification) {
+	for _, b := range m {
+		b.BroadcastToAllExcept(excludedUsers, notification)
+	}
+}
+
+// Disconnect closes the users' connections on every transport
+func (m MultiBroadcaster) Disconnect(userIDs []string) {
+	for _, b := range m {
+		b.Disconnect(userIDs)
+	}
+}
+
+// NotificationGateway delivers notifications to players, leaving out those who turned their
+// category off
+type NotificationGateway interface {
+	BroadcastToUsers(ctx context.Context, targetUsers []string, notification jsonrpcx.JsonRpcNotification)
+	BroadcastToAll(ctx context.Context, notification jsonrpcx.JsonRpcNotification)
+}
+
+// InterestFilter decides which users receive position-based notifications
+type InterestFilter interface {
+	Audience(ctx context.Context, userID string, position shared.Position) ([]string, error)
+	UsersNear(ctx context.Context, position shared.Position) ([]string, error)
+}
+
+// BroadcastRates tells which users receive the positions batches of the movement simulation
+// on the reduced rate
+type BroadcastRates interface {
+	Reduced(ctx context.Context, userID string) bool
+}
+
+// AnyReduced puts users on the reduced rate when any of its rates does
+type AnyReduced []BroadcastRates
+
+// Reduced reports whether any of the rates puts the user on the reduced rate
+func (r AnyReduced) Reduced(ctx context.Context, userID string) bool {
+	for _, rates := range r {
+		if rates.Reduced(ctx, userID) {
+			return true
+		}
+	}
+	return false
 }
 
 // EventPublisher interface for publishing events
@@ -24,27 +98,55 @@ type EventPublisher interface {
 
 // SSEEventHandler handles events and converts them to SSE notifications
 type SSEEventHandler struct {
-	sseBroadcaster SSEBroadcaster
+	gateway        NotificationGateway
+	interest       InterestFilter
+	rates          BroadcastRates
 	eventPublisher EventPublisher
 	logger         *logger.Logger
 }
 
-// NewSSEEventHandler creates a new SSE event handler
+// NewSSEEventHandler creates a new SSE event handler. Position broadcasts go to the
+// users selected by interest, or to everyone when interest is nil. Users rates reports on
+// the reduced rate are left out of positions batches off its beat; with rates nil every
+// user receives every batch.
 func NewSSEEventHandler(
-	sseBroadcaster SSEBroadcaster,
+	gateway NotificationGateway,
+	interest InterestFilter,
+	rates BroadcastRates,
 	eventPublisher EventPublisher,
 	logger *logger.Logger,
 ) *SSEEventHandler {
 	return &SSEEventHandler{
-		sseBroadcaster: sseBroadcaster,
+		gateway:        gateway,
+		interest:       interest,
+		rates:          rates,
 		eventPublisher: eventPublisher,
 		logger:         logger.WithComponent("sse-event-handler"),
 	}
 }
 
+// broadcastNearby sends a position notification to the users who can see the trainer
+func (h *SSEEventHandler) broadcastNearby(ctx context.Context, userID string, position shared.Position, notification jsonrpcx.JsonRpcNotification) {
+	if h.interest == nil {
+		h.gateway.BroadcastToAll(ctx, notification)
+		return
+	}
+
+	audience, err := h.interest.Audience(ctx, userID, position)
+	if err != nil {
+		h.logger.WithContext(ctx).Warn("Failed to resolve position audience, broadcasting to all",
+			zap.String("userId", userID),
+			zap.Error(err))
+		h.gateway.BroadcastToAll(ctx, notification)
+		return
+	}
+
+	h.gateway.BroadcastToUsers(ctx, audience, notification)
+}
+
 // HandleTrainerMovedEvent handles TrainerMovedEvent and broadcasts to SSE clients
 func (h *SSEEventHandler) HandleTrainerMovedEvent(ctx context.Context, event *cqrsevents.TrainerMovedEvent) error {
-	h.logger.Debug("Handling trainer moved event",
+	h.logger.WithContext(ctx).Debug("Handling trainer moved event",
 		zap.String("userId", event.UserID),
 		zap.String("requestId", event.RequestID))
 
@@ -61,7 +163,7 @@ func (h *SSEEventHandler) HandleTrainerMovedEvent(ctx context.Context, event *cq
 	}
 
 	// Send changes to the user who initiated the move
-	h.sseBroadcaster.BroadcastToUsers([]string{event.UserID}, userNotification)
+	h.gateway.BroadcastToUsers(ctx, []string{event.UserID}, userNotification)
 
 	// Create JSON-RPC notification for other users (send full position data)
 	broadcastNotification := jsonrpcx.JsonRpcNotification{
@@ -71,25 +173,103 @@ func (h *SSEEventHandler) HandleTrainerMovedEvent(ctx context.Context, event *cq
 			"user_id":   event.UserID,
 			"nickname":  event.Nickname,
 			"color":     event.Color,
+			"showcase":  event.Showcase,
 			"position":  event.Position,
 			"movement":  event.Movement,
 			"timestamp": event.Timestamp.Format(time.RFC3339),
 		},
 	}
 
-	// Broadcast position to all other users for real-time sync
-	h.sseBroadcaster.BroadcastToAll(broadcastNotification)
+	// Broadcast position to users close enough to see the trainer
+	h.broadcastNearby(ctx, event.UserID, event.Position, broadcastNotification)
 
-	h.logger.Debug("Trainer moved event handled and broadcast",
+	h.logger.WithContext(ctx).Debug("Trainer moved event handled and broadcast",
 		zap.String("userId", event.UserID),
 		zap.String("requestId", event.RequestID))
 
 	return nil
 }
 
+// HandlePositionsBatchEvent handles PositionsBatchEvent, sending every user the part of the
+// batch they can see, their own trainer included, as one trainer.positions.batch
+// notification. Users who see the same trainers share a notification. Trainers whose
+// audience can't be resolved are sent to everyone.
+func (h *SSEEventHandler) HandlePositionsBatchEvent(ctx context.Context, event *cqrsevents.PositionsBatchEvent) error {
+	if h.interest == nil {
+		h.gateway.BroadcastToAll(ctx, h.positionsBatch(event, event.Trainers))
+		return nil
+	}
+
+	// Players on the reduced rate only receive the batches on its beat
+	offBeat := h.rates != nil && !event.ReducedBeat
+
+	visible := make(map[string][]int) // Indexes of the trainers each user sees
+	var unresolved []cqrsevents.PositionDelta
+	for i, delta := range event.Trainers {
+		audience, err := h.interest.Audience(ctx, delta.UserID, shared.NewPosition(delta.X, delta.Y))
+		if err != nil {
+			h.logger.WithContext(ctx).Warn("Failed to resolve position audience, broadcasting to all",
+				zap.String("userId", delta.UserID),
+				zap.Error(err))
+			unresolved = append(unresolved, delta)
+			continue
+		}
+		if !slices.Contains(audience, delta.UserID) {
+			audience = append(audience, delta.UserID)
+		}
+		for _, userID := range audience {
+			visible[userID] = append(visible[userID], i)
+		}
+	}
+
+	type group struct {
+		trainers []int
+		users    []string
+	}
+	groups := make(map[string]*group)
+	for userID, trainers := range visible {
+		if offBeat && h.rates.Reduced(ctx, userID) {
+			continue
+		}
+
+		key := fmt.Sprint(trainers)
+		g, ok := groups[key]
+		if !ok {
+			g = &group{trainers: trainers}
+			groups[key] = g
+		}
+		g.users = append(g.users, userID)
+	}
+
+	for _, g := range groups {
+		deltas := make([]cqrsevents.PositionDelta, len(g.trainers))
+		for i, index := range g.trainers {
+			deltas[i] = event.Trainers[index]
+		}
+		h.gateway.BroadcastToUsers(ctx, g.users, h.positionsBatch(event, deltas))
+	}
+	if len(unresolved) > 0 {
+		h.gateway.BroadcastToAll(ctx, h.positionsBatch(event, unresolved))
+	}
+	return nil
+}
+
+// positionsBatch creates the trainer.positions.batch notification of some of a batch's trainers
+func (h *SSEEventHandler) positionsBatch(event *cqrsevents.PositionsBatchEvent, trainers []cqrsevents.PositionDelta) jsonrpcx.JsonRpcNotification {
+	return jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "trainer.positions.batch",
+		Params: map[string]interface{}{
+			"timestamp": event.Timestamp.Format(time.RFC3339Nano),
+			"keyframe":  event.Keyframe,
+			"trainers":  trainers,
+		},
+	}
+}
+
 // HandleTrainerStoppedEvent handles TrainerStoppedEvent and broadcasts to SSE clients
 func (h *SSEEventHandler) HandleTrainerStoppedEvent(ctx context.Context, event *cqrsevents.TrainerStoppedEvent) error {
-	h.logger.Debug("Handling trainer stopped event",
+	h.logger.WithContext(ctx).Debug("Handling trainer stopped event",
 		zap.String("userId", event.UserID),
 		zap.String("requestId", event.RequestID))
 
@@ -106,7 +286,7 @@ func (h *SSEEventHandler) HandleTrainerStoppedEvent(ctx context.Context, event *
 	}
 
 	// Send changes to the user who initiated the stop
-	h.sseBroadcaster.BroadcastToUsers([]string{event.UserID}, userNotification)
+	h.gateway.BroadcastToUsers(ctx, []string{event.UserID}, userNotification)
 
 	// Create JSON-RPC notification for other users (send full movement state)
 	broadcastNotification := jsonrpcx.JsonRpcNotification{
@@ -116,16 +296,17 @@ func (h *SSEEventHandler) HandleTrainerStoppedEvent(ctx context.Context, event *
 			"user_id":   event.UserID,
 			"nickname":  event.Nickname,
 			"color":     event.Color,
+			"showcase":  event.Showcase,
 			"position":  event.Position,
 			"movement":  event.Movement,
 			"timestamp": event.Timestamp.Format(time.RFC3339),
 		},
 	}
 
-	// Broadcast movement state to all other users
-	h.sseBroadcaster.BroadcastToAll(broadcastNotification)
+	// Broadcast movement state to users close enough to see the trainer
+	h.broadcastNearby(ctx, event.UserID, event.Position, broadcastNotification)
 
-	h.logger.Debug("Trainer stopped event handled and broadcast",
+	h.logger.WithContext(ctx).Debug("Trainer stopped event handled and broadcast",
 		zap.String("userId", event.UserID),
 		zap.String("requestId", event.RequestID))
 
@@ -134,7 +315,7 @@ func (h *SSEEventHandler) HandleTrainerStoppedEvent(ctx context.Context, event *
 
 // HandleTrainerCreatedEvent handles TrainerCreatedEvent and broadcasts to SSE clients
 func (h *SSEEventHandler) HandleTrainerCreatedEvent(ctx context.Context, event *cqrsevents.TrainerCreatedEvent) error {
-	h.logger.Debug("Handling trainer created event",
+	h.logger.WithContext(ctx).Debug("Handling trainer created event",
 		zap.String("userId", event.UserID),
 		zap.String("requestId", event.RequestID))
 
@@ -153,18 +334,70 @@ func (h *SSEEventHandler) HandleTrainerCreatedEvent(ctx context.Context, event *
 	}
 
 	// Broadcast trainer creation to all users
-	h.sseBroadcaster.BroadcastToAll(notification)
+	h.gateway.BroadcastToAll(ctx, notification)
+
+	h.logger.WithContext(ctx).Debug("Trainer created event handled and broadcast",
+		zap.String("userId", event.UserID),
+		zap.String("requestId", event.RequestID))
+
+	return nil
+}
+
+// HandleTrainerDiedEvent tells the dead trainer and those around it that it fell
+func (h *SSEEventHandler) HandleTrainerDiedEvent(ctx context.Context, event *cqrsevents.TrainerDiedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling trainer died event",
+		zap.String("userId", event.UserID),
+		zap.String("cause", event.Cause.String()),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "trainer.died",
+		Params: map[string]interface{}{
+			"user_id":    event.UserID,
+			"nickname":   event.Nickname,
+			"position":   event.Position,
+			"cause":      event.Cause,
+			"source_id":  event.SourceID,
+			"money_lost": event.MoneyLost,
+			"items_lost": event.ItemsLost,
+			"respawn_at": event.RespawnAt.Format(time.RFC3339),
+			"timestamp":  event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	h.broadcastAround(ctx, event.Position, event.UserID, notification)
+	return nil
+}
 
-	h.logger.Debug("Trainer created event handled and broadcast",
+// HandleTrainerRespawnedEvent tells the trainer and those around its spawn point that it is back
+func (h *SSEEventHandler) HandleTrainerRespawnedEvent(ctx context.Context, event *cqrsevents.TrainerRespawnedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling trainer respawned event",
 		zap.String("userId", event.UserID),
+		zap.String("spawnId", event.SpawnID),
 		zap.String("requestId", event.RequestID))
 
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "trainer.respawned",
+		Params: map[string]interface{}{
+			"user_id":   event.UserID,
+			"nickname":  event.Nickname,
+			"color":     event.Color,
+			"position":  event.Position,
+			"spawn_id":  event.SpawnID,
+			"hp":        event.HP,
+			"timestamp": event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	h.broadcastAround(ctx, event.Position, event.UserID, notification)
 	return nil
 }
 
 // HandleSSENotificationEvent handles SSENotificationEvent for distributed SSE messaging
 func (h *SSEEventHandler) HandleSSENotificationEvent(ctx context.Context, event *cqrsevents.SSENotificationEvent) error {
-	h.logger.Debug("Handling SSE notification event",
+	h.logger.WithContext(ctx).Debug("Handling SSE notification event",
 		zap.String("type", event.Type),
 		zap.Strings("targetUsers", event.TargetUsers),
 		zap.String("method", event.Method),
@@ -180,18 +413,607 @@ func (h *SSEEventHandler) HandleSSENotificationEvent(ctx context.Context, event
 	case cqrsevents.SSENotificationTypeUsers:
 		// Array-based user targeting - only send if users are connected to this server
 		if len(event.TargetUsers) > 0 {
-			h.sseBroadcaster.BroadcastToUsers(event.TargetUsers, notification)
+			h.gateway.BroadcastToUsers(ctx, event.TargetUsers, notification)
 		}
 	case cqrsevents.SSENotificationTypeBroadcast:
 		// Broadcast to all connected users on this server
-		h.sseBroadcaster.BroadcastToAll(notification)
+		h.gateway.BroadcastToAll(ctx, notification)
 	default:
-		h.logger.Warn("Unknown SSE notification type", zap.String("type", event.Type))
+		h.logger.WithContext(ctx).Warn("Unknown SSE notification type", zap.String("type", event.Type))
 	}
 
-	h.logger.Debug("SSE notification event handled",
+	h.logger.WithContext(ctx).Debug("SSE notification event handled",
 		zap.String("type", event.Type),
 		zap.String("requestId", event.RequestID))
 
 	return nil
 }
+
+// HandleAnimalSpawnedEvent notifies trainers near a newly spawned wild animal
+func (h *SSEEventHandler) HandleAnimalSpawnedEvent(ctx context.Context, event *cqrsevents.AnimalSpawnedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling animal spawned event",
+		zap.String("animalId", event.AnimalID),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "animal.spawned",
+		Params: map[string]interface{}{
+			"animal_id":   event.AnimalID,
+			"animal_type": event.AnimalType,
+			"level":       event.Level,
+			"position":    event.Position,
+			"timestamp":   event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	if h.interest == nil {
+		h.gateway.BroadcastToAll(ctx, notification)
+		return nil
+	}
+
+	audience, err := h.interest.UsersNear(ctx, event.Position)
+	if err != nil {
+		h.logger.WithContext(ctx).Warn("Failed to resolve spawn audience, broadcasting to all",
+			zap.String("animalId", event.AnimalID),
+			zap.Error(err))
+		h.gateway.BroadcastToAll(ctx, notification)
+		return nil
+	}
+
+	h.gateway.BroadcastToUsers(ctx, audience, notification)
+	return nil
+}
+
+// HandleAnimalMovedEvent notifies trainers near a wild animal that it moved
+func (h *SSEEventHandler) HandleAnimalMovedEvent(ctx context.Context, event *cqrsevents.AnimalMovedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling animal moved event",
+		zap.String("animalId", event.AnimalID),
+		zap.String("behavior", event.Behavior),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "animal.moved",
+		Params: map[string]interface{}{
+			"animal_id":   event.AnimalID,
+			"animal_type": event.AnimalType,
+			"from":        event.From,
+			"to":          event.To,
+			"behavior":    event.Behavior,
+			"target_id":   event.TargetID,
+			"timestamp":   event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	if h.interest == nil {
+		h.gateway.BroadcastToAll(ctx, notification)
+		return nil
+	}
+
+	audience, err := h.interest.UsersNear(ctx, event.To)
+	if err != nil {
+		h.logger.WithContext(ctx).Warn("Failed to resolve animal audience, broadcasting to all",
+			zap.String("animalId", event.AnimalID),
+			zap.Error(err))
+		h.gateway.BroadcastToAll(ctx, notification)
+		return nil
+	}
+
+	h.gateway.BroadcastToUsers(ctx, audience, notification)
+	return nil
+}
+
+// HandleAnimalCapturedEvent tells trainers near a captured animal that it left the wild
+func (h *SSEEventHandler) HandleAnimalCapturedEvent(ctx context.Context, event *cqrsevents.AnimalCapturedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling animal captured event",
+		zap.String("userId", event.UserID),
+		zap.String("animalId", event.AnimalID),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "animal.captured",
+		Params: map[string]interface{}{
+			"user_id":     event.UserID,
+			"animal_id":   event.AnimalID,
+			"animal_type": event.AnimalType,
+			"level":       event.Level,
+			"placement":   event.Placement,
+			"position":    event.Position,
+			"timestamp":   event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	// The capturer may have thrown from outside the radius
+	h.broadcastAround(ctx, event.Position, event.UserID, notification)
+	return nil
+}
+
+// HandleBattleStartedEvent lets trainers near a wild animal spectate a battle against it
+func (h *SSEEventHandler) HandleBattleStartedEvent(ctx context.Context, event *cqrsevents.BattleStartedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling battle started event",
+		zap.String("battleId", event.BattleID),
+		zap.String("userId", event.UserID),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "battle.started",
+		Params: map[string]interface{}{
+			"battle_id":   event.BattleID,
+			"user_id":     event.UserID,
+			"animal_id":   event.AnimalID,
+			"animal_type": event.AnimalType,
+			"wild_id":     event.WildID,
+			"wild_type":   event.WildType,
+			"wild_level":  event.WildLevel,
+			"position":    event.Position,
+			"timestamp":   event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	h.broadcastAround(ctx, event.Position, event.UserID, notification)
+	return nil
+}
+
+// HandleBattleTurnEvent streams each played turn to the battle's spectators
+func (h *SSEEventHandler) HandleBattleTurnEvent(ctx context.Context, event *cqrsevents.BattleTurnEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling battle turn event",
+		zap.String("battleId", event.BattleID),
+		zap.Int("turn", event.Turn.Number),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "battle.turn",
+		Params: map[string]interface{}{
+			"battle_id": event.BattleID,
+			"user_id":   event.UserID,
+			"turn":      event.Turn,
+			"status":    event.Status,
+			"timestamp": event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	h.broadcastAround(ctx, event.Position, event.UserID, notification)
+	return nil
+}
+
+// HandleBattleEndedEvent tells the battle's spectators how it finished
+func (h *SSEEventHandler) HandleBattleEndedEvent(ctx context.Context, event *cqrsevents.BattleEndedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling battle ended event",
+		zap.String("battleId", event.BattleID),
+		zap.String("status", event.Status),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "battle.ended",
+		Params: map[string]interface{}{
+			"battle_id":  event.BattleID,
+			"user_id":    event.UserID,
+			"animal_id":  event.AnimalID,
+			"wild_id":    event.WildID,
+			"status":     event.Status,
+			"experience": event.Experience,
+			"timestamp":  event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	h.broadcastAround(ctx, event.Position, event.UserID, notification)
+	return nil
+}
+
+// broadcastAround sends a notification to the users near a position and to the acting trainer
+func (h *SSEEventHandler) broadcastAround(ctx context.Context, position shared.Position, actorID string, notification jsonrpcx.JsonRpcNotification) {
+	if h.interest == nil {
+		h.gateway.BroadcastToAll(ctx, notification)
+		return
+	}
+
+	audience, err := h.interest.UsersNear(ctx, position)
+	if err != nil {
+		h.logger.WithContext(ctx).Warn("Failed to resolve nearby audience, broadcasting to all",
+			zap.String("method", notification.Method),
+			zap.Error(err))
+		h.gateway.BroadcastToAll(ctx, notification)
+		return
+	}
+
+	if !slices.Contains(audience, actorID) {
+		audience = append(audience, actorID)
+	}
+	h.gateway.BroadcastToUsers(ctx, audience, notification)
+}
+
+// HandleLootDroppedEvent notifies the trainer who defeated an animal about the rolled loot
+func (h *SSEEventHandler) HandleLootDroppedEvent(ctx context.Context, event *cqrsevents.LootDroppedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling loot dropped event",
+		zap.String("userId", event.UserID),
+		zap.String("animalId", event.AnimalID),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "loot.dropped",
+		Params: map[string]interface{}{
+			"animal_id":   event.AnimalID,
+			"animal_type": event.AnimalType,
+			"mode":        event.Mode,
+			"drops":       event.Drops,
+			"pickup_ids":  event.PickupIDs,
+			"position":    event.Position,
+			"timestamp":   event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	h.gateway.BroadcastToUsers(ctx, []string{event.UserID}, notification)
+
+	return nil
+}
+
+// HandleCraftCompletedEvent notifies the trainer that a crafting job is ready to collect
+func (h *SSEEventHandler) HandleCraftCompletedEvent(ctx context.Context, event *cqrsevents.CraftCompletedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling craft completed event",
+		zap.String("userId", event.UserID),
+		zap.String("jobId", event.JobID),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "craft.completed",
+		Params: map[string]interface{}{
+			"job_id":    event.JobID,
+			"recipe_id": event.RecipeID,
+			"succeeded": event.Succeeded,
+			"timestamp": event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	h.gateway.BroadcastToUsers(ctx, []string{event.UserID}, notification)
+
+	return nil
+}
+
+// HandleItemConsumedEvent notifies the trainer that a consumable took effect
+func (h *SSEEventHandler) HandleItemConsumedEvent(ctx context.Context, event *cqrsevents.ItemConsumedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling item consumed event",
+		zap.String("userId", event.UserID),
+		zap.String("itemType", event.ItemType.String()),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "item.consumed",
+		Params: map[string]interface{}{
+			"item_id":       event.ItemID,
+			"item_type":     event.ItemType,
+			"target_type":   event.TargetType,
+			"target_id":     event.TargetID,
+			"healed":        event.Healed,
+			"mana_restored": event.ManaRestored,
+			"effect":        event.Effect,
+			"cooldown_ends": event.CooldownEnds.Format(time.RFC3339),
+			"timestamp":     event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	h.gateway.BroadcastToUsers(ctx, []string{event.UserID}, notification)
+
+	return nil
+}
+
+// HandleInventoryChangedEvent notifies the trainer of items removed from their inventory
+func (h *SSEEventHandler) HandleInventoryChangedEvent(ctx context.Context, event *cqrsevents.InventoryChangedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling inventory changed event",
+		zap.String("userId", event.UserID),
+		zap.String("action", event.Action),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "inventory.changed",
+		Params: map[string]interface{}{
+			"action":     event.Action,
+			"items":      event.Items,
+			"used_slots": event.UsedSlots,
+			"timestamp":  event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	h.gateway.BroadcastToUsers(ctx, []string{event.UserID}, notification)
+
+	return nil
+}
+
+// HandleBulletFiredEvent broadcasts a fired bullet to all SSE clients for rendering, or to the
+// members of the room it was fired in
+func (h *SSEEventHandler) HandleBulletFiredEvent(ctx context.Context, event *cqrsevents.BulletFiredEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling bullet fired event",
+		zap.String("userId", event.UserID),
+		zap.String("bulletId", event.BulletID),
+		zap.String("requestId", event.RequestID))
+
+	params := map[string]interface{}{
+		"user_id":        event.UserID,
+		"bullet_id":      event.BulletID,
+		"weapon_type":    event.WeaponType,
+		"start_position": event.StartPos,
+		"velocity":       event.Velocity,
+		"max_range":      event.MaxRange,
+		"fired_at":       event.FiredAt.Format(time.RFC3339Nano),
+		"expires_at":     event.ExpiresAt.Format(time.RFC3339Nano),
+	}
+	if event.RoomID != "" {
+		params["room_id"] = event.RoomID
+	}
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "bullet.fired",
+		Params:  params,
+	}
+
+	h.broadcastToRecipients(ctx, event.Recipients, notification)
+
+	return nil
+}
+
+// HandleBulletHitEvent broadcasts a bullet hitting a trainer to the players its firing went to
+func (h *SSEEventHandler) HandleBulletHitEvent(ctx context.Context, event *cqrsevents.BulletHitEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling bullet hit event",
+		zap.String("userId", event.UserID),
+		zap.String("bulletId", event.BulletID),
+		zap.String("targetId", event.TargetID),
+		zap.String("requestId", event.RequestID))
+
+	params := map[string]interface{}{
+		"user_id":     event.UserID,
+		"bullet_id":   event.BulletID,
+		"weapon_type": event.WeaponType,
+		"target_id":   event.TargetID,
+		"position":    event.Position,
+		"damage":      event.Damage,
+		"killed":      event.Killed,
+		"hit_at":      event.HitAt.Format(time.RFC3339Nano),
+	}
+	if event.RoomID != "" {
+		params["room_id"] = event.RoomID
+	}
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "bullet.hit",
+		Params:  params,
+	}
+
+	h.broadcastToRecipients(ctx, event.Recipients, notification)
+
+	return nil
+}
+
+// HandleAmmoChangedEvent sends a trainer its ammo after a reload or purchase
+func (h *SSEEventHandler) HandleAmmoChangedEvent(ctx context.Context, event *cqrsevents.AmmoChangedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling ammo changed event",
+		zap.String("userId", event.UserID),
+		zap.String("reason", event.Reason),
+		zap.String("requestId", event.RequestID))
+
+	params := map[string]interface{}{
+		"weapon_type": event.WeaponType,
+		"magazine":    event.Magazine,
+		"reserve":     event.Reserve,
+		"reason":      event.Reason,
+		"boxes_used":  event.BoxesUsed,
+		"timestamp":   event.Timestamp.Format(time.RFC3339),
+	}
+	if event.ReloadingUntil != nil {
+		params["reloading_until"] = event.ReloadingUntil.Format(time.RFC3339Nano)
+	}
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "bullet.ammo_changed",
+		Params:  params,
+	}
+
+	h.gateway.BroadcastToUsers(ctx, []string{event.UserID}, notification)
+	return nil
+}
+
+// HandleChatMessageEvent delivers a chat message to its recipients, or to every player for
+// global chat
+func (h *SSEEventHandler) HandleChatMessageEvent(ctx context.Context, event *cqrsevents.ChatMessageEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling chat message event",
+		zap.String("userId", event.SenderID),
+		zap.String("channel", string(event.Message.Channel)),
+		zap.Int("recipients", len(event.Recipients)),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "chat.message",
+		Params: map[string]interface{}{
+			"message": event.Message,
+		},
+	}
+
+	h.broadcastToRecipients(ctx, event.Recipients, notification)
+
+	return nil
+}
+
+// HandleRoomJoinedEvent tells the members of a room, the player who joined included, who is
+// in it now
+func (h *SSEEventHandler) HandleRoomJoinedEvent(ctx context.Context, event *cqrsevents.RoomJoinedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling room joined event",
+		zap.String("userId", event.UserID),
+		zap.String("roomId", event.RoomID),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "room.joined",
+		Params: map[string]interface{}{
+			"room_id":   event.RoomID,
+			"user_id":   event.UserID,
+			"members":   event.Members,
+			"timestamp": event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	h.gateway.BroadcastToUsers(ctx, event.Members, notification)
+
+	return nil
+}
+
+// HandleRoomLeftEvent tells the player who left and the members still in the room
+func (h *SSEEventHandler) HandleRoomLeftEvent(ctx context.Context, event *cqrsevents.RoomLeftEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling room left event",
+		zap.String("userId", event.UserID),
+		zap.String("roomId", event.RoomID),
+		zap.Bool("closed", event.Closed),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "room.left",
+		Params: map[string]interface{}{
+			"room_id":   event.RoomID,
+			"user_id":   event.UserID,
+			"owner_id":  event.OwnerID,
+			"members":   event.Members,
+			"closed":    event.Closed,
+			"timestamp": event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	h.gateway.BroadcastToUsers(ctx, append([]string{event.UserID}, event.Members...), notification)
+
+	return nil
+}
+
+// HandleMatchStartedEvent tells the players of a match that it started, with their teams
+func (h *SSEEventHandler) HandleMatchStartedEvent(ctx context.Context, event *cqrsevents.MatchStartedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling match started event",
+		zap.String("matchId", event.MatchID),
+		zap.String("roomId", event.RoomID),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "match.started",
+		Params: map[string]interface{}{
+			"match_id":    event.MatchID,
+			"room_id":     event.RoomID,
+			"mode":        event.Mode,
+			"score_limit": event.ScoreLimit,
+			"players":     event.Players,
+			"ends_at":     event.EndsAt.Format(time.RFC3339),
+		},
+	}
+
+	h.gateway.BroadcastToUsers(ctx, matchPlayerIDs(event.Players), notification)
+
+	return nil
+}
+
+// HandleMatchScoreChangedEvent sends the players of a match its scoreboard after a kill
+func (h *SSEEventHandler) HandleMatchScoreChangedEvent(ctx context.Context, event *cqrsevents.MatchScoreChangedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling match score changed event",
+		zap.String("matchId", event.MatchID),
+		zap.String("killerId", event.KillerID),
+		zap.String("victimId", event.VictimID),
+		zap.String("requestId", event.RequestID))
+
+	params := map[string]interface{}{
+		"match_id":  event.MatchID,
+		"killer_id": event.KillerID,
+		"victim_id": event.VictimID,
+		"players":   event.Players,
+	}
+	if event.TeamScores != nil {
+		params["team_scores"] = event.TeamScores
+	}
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "match.score",
+		Params:  params,
+	}
+
+	h.gateway.BroadcastToUsers(ctx, matchPlayerIDs(event.Players), notification)
+
+	return nil
+}
+
+// HandleMatchEndedEvent sends the players of a match its results
+func (h *SSEEventHandler) HandleMatchEndedEvent(ctx context.Context, event *cqrsevents.MatchEndedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling match ended event",
+		zap.String("matchId", event.MatchID),
+		zap.String("roomId", event.RoomID),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "match.ended",
+		Params: map[string]interface{}{
+			"match_id": event.MatchID,
+			"room_id":  event.RoomID,
+			"mode":     event.Mode,
+			"result":   event.Result,
+		},
+	}
+
+	var players []string
+	if event.Result != nil {
+		for _, standing := range event.Result.Standings {
+			players = append(players, standing.UserID)
+		}
+	}
+	h.gateway.BroadcastToUsers(ctx, players, notification)
+
+	return nil
+}
+
+// matchPlayerIDs returns the users on a match scoreboard
+func matchPlayerIDs(players []*match.Player) []string {
+	ids := make([]string, len(players))
+	for i, p := range players {
+		ids[i] = p.UserID
+	}
+	return ids
+}
+
+// broadcastToRecipients sends a notification to its recipients, or to every player when it
+// has none
+func (h *SSEEventHandler) broadcastToRecipients(ctx context.Context, recipients []string, notification jsonrpcx.JsonRpcNotification) {
+	if len(recipients) == 0 {
+		h.gateway.BroadcastToAll(ctx, notification)
+	} else {
+		h.gateway.BroadcastToUsers(ctx, recipients, notification)
+	}
+}
+
+// HandleWorldTimeChangedEvent broadcasts the game time and weather to all SSE clients for
+// lighting
+func (h *SSEEventHandler) HandleWorldTimeChangedEvent(ctx context.Context, event *cqrsevents.WorldTimeChangedEvent) error {
+	h.logger.WithContext(ctx).Debug("Handling world time changed event",
+		zap.Int64("minute", event.Time.Minute),
+		zap.String("weather", string(event.Time.Weather)),
+		zap.String("requestId", event.RequestID))
+
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "world.time_changed",
+		Params: map[string]interface{}{
+			"time":      event.Time,
+			"timestamp": event.Timestamp.Format(time.RFC3339),
+		},
+	}
+
+	h.gateway.BroadcastToAll(ctx, notification)
+
+	return nil
+}
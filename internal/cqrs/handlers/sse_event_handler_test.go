@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	cqrsevents "github.com/danghamo/life/internal/cqrs"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// TestSSEEventHandler_Contracts checks that every event the SSE handler consumes has a
+// versioned contract, and that what publishers put on the bus decodes to the same event on
+// the handler's side
+func TestSSEEventHandler_Contracts(t *testing.T) {
+	schemas := cqrsevents.DefaultSchemas()
+	marshaler := cqrsevents.JSONMarshaler{Schemas: schemas}
+	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	handlerType := reflect.TypeOf(&SSEEventHandler{})
+	consumed := 0
+	for i := 0; i < handlerType.NumMethod(); i++ {
+		method := handlerType.Method(i)
+		if !strings.HasPrefix(method.Name, "Handle") || !strings.HasSuffix(method.Name, "Event") {
+			continue
+		}
+		// Receiver, context and the event
+		require.Equal(t, 3, method.Type.NumIn(), method.Name)
+		require.True(t, method.Type.In(1).Implements(contextType), method.Name)
+		eventType := method.Type.In(2)
+		consumed++
+
+		t.Run(method.Name, func(t *testing.T) {
+			published := reflect.New(eventType.Elem())
+			fillSample(published.Elem(), 0)
+
+			schema, ok := schemas.Lookup(published.Interface())
+			require.True(t, ok, "%s has no registered schema", eventType.Elem().Name())
+			assert.Equal(t, "Handle"+schema.Name, method.Name)
+
+			msg, err := marshaler.Marshal(published.Interface())
+			require.NoError(t, err)
+
+			received := reflect.New(eventType.Elem())
+			require.NoError(t, marshaler.Unmarshal(msg, received.Interface()))
+			assert.Equal(t, published.Interface(), received.Interface())
+		})
+	}
+	assert.NotZero(t, consumed)
+}
+
+// recordingGateway records who each notification was sent to; nil means everyone
+type recordingGateway struct {
+	sent [][]string
+}
+
+func (g *recordingGateway) BroadcastToUsers(ctx context.Context, targetUsers []string, notification jsonrpcx.JsonRpcNotification) {
+	g.sent = append(g.sent, targetUsers)
+}
+
+func (g *recordingGateway) BroadcastToAll(ctx context.Context, notification jsonrpcx.JsonRpcNotification) {
+	g.sent = append(g.sent, nil)
+}
+
+// fixedInterest returns the same audience for every position
+type fixedInterest struct {
+	users []string
+	err   error
+}
+
+func (f fixedInterest) Audience(ctx context.Context, userID string, position shared.Position) ([]string, error) {
+	return f.users, f.err
+}
+
+func (f fixedInterest) UsersNear(ctx context.Context, position shared.Position) ([]string, error) {
+	return f.users, f.err
+}
+
+func TestSSEEventHandler_BroadcastNearby(t *testing.T) {
+	tests := []struct {
+		name     string
+		interest InterestFilter
+		want     []string
+	}{
+		{name: "no interest filter", interest: nil, want: nil},
+		{name: "nearby users", interest: fixedInterest{users: []string{"a", "b"}}, want: []string{"a", "b"}},
+		{name: "failed lookup", interest: fixedInterest{err: errors.New("redis down")}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gateway := &recordingGateway{}
+			h := NewSSEEventHandler(gateway, tt.interest, nil, nil, logger.NewDefault())
+
+			h.broadcastNearby(context.Background(), "mover", shared.NewPosition(1, 2), jsonrpcx.JsonRpcNotification{})
+			assert.Equal(t, [][]string{tt.want}, gateway.sent)
+		})
+	}
+}
+
+var (
+	sampleTime      = time.Date(2026, 10, 15, 12, 0, 0, 0, time.UTC)
+	marshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+)
+
+// fillSample sets every field a JSON payload carries to a non-zero value. Interfaces and
+// types with their own JSON methods, other than times, are left zero since arbitrary values
+// don't decode back into the same value.
+func fillSample(v reflect.Value, depth int) {
+	t := v.Type()
+	if t == reflect.TypeOf(sampleTime) {
+		v.Set(reflect.ValueOf(sampleTime))
+		return
+	}
+	if depth > 4 || encodesItself(t) || (t.Kind() == reflect.Ptr && encodesItself(t.Elem())) {
+		return
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		v.Set(reflect.New(t.Elem()))
+		fillSample(v.Elem(), depth+1)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if v.Field(i).CanSet() && t.Field(i).Tag.Get("json") != "-" {
+				fillSample(v.Field(i), depth+1)
+			}
+		}
+	case reflect.String:
+		v.SetString("sample")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(7)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(7)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1.5)
+	case reflect.Slice:
+		slice := reflect.MakeSlice(t, 1, 1)
+		fillSample(slice.Index(0), depth+1)
+		v.Set(slice)
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String || t.Elem().Kind() == reflect.Interface {
+			return
+		}
+		key := reflect.New(t.Key()).Elem()
+		key.SetString("sample")
+		value := reflect.New(t.Elem()).Elem()
+		fillSample(value, depth+1)
+		m := reflect.MakeMap(t)
+		m.SetMapIndex(key, value)
+		v.Set(m)
+	}
+}
+
+// encodesItself reports whether a type has its own JSON methods
+func encodesItself(t reflect.Type) bool {
+	return reflect.PtrTo(t).Implements(marshalerType) || reflect.PtrTo(t).Implements(unmarshalerType)
+}
@@ -0,0 +1,66 @@
+package cqrs
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ThreeDotsLabs/watermill"
+	watermillcqrs "github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/danghamo/life/pkg/jsonx"
+)
+
+// JSONMarshaler is watermill's JSON marshaler encoding through jsonx, so events with generated
+// marshalers skip reflection in builds tagged easyjson. Messages are the same JSON either way.
+type JSONMarshaler struct {
+	watermillcqrs.JSONMarshaler
+
+	// Schemas versions the registered events: messages carry their schema version, and
+	// payloads of older versions are upcast when read. Nil leaves payloads unversioned.
+	Schemas *SchemaRegistry
+}
+
+// Marshal encodes a command or event into a message named after its type
+func (m JSONMarshaler) Marshal(v interface{}) (*message.Message, error) {
+	payload, err := jsonx.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	id := watermill.NewUUID()
+	if m.NewUUID != nil {
+		id = m.NewUUID()
+	}
+
+	msg := message.NewMessage(id, payload)
+	msg.Metadata.Set("name", m.Name(v))
+	if schema, ok := m.Schemas.Lookup(v); ok {
+		msg.Metadata.Set(SchemaVersionKey, strconv.Itoa(schema.Version))
+	}
+
+	return msg, nil
+}
+
+// Unmarshal decodes a message payload into a command or event, upcasting payloads of older
+// schema versions first
+func (m JSONMarshaler) Unmarshal(msg *message.Message, v interface{}) error {
+	payload := msg.Payload
+	if schema, ok := m.Schemas.Lookup(v); ok {
+		version := 1
+		if value := msg.Metadata.Get(SchemaVersionKey); value != "" {
+			parsed, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid schema version %q of %s: %w", value, schema.Name, err)
+			}
+			version = parsed
+		}
+
+		upcast, err := schema.Upcast(payload, version)
+		if err != nil {
+			return err
+		}
+		payload = upcast
+	}
+	return jsonx.Unmarshal(payload, v)
+}
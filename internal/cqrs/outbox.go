@@ -0,0 +1,86 @@
+package cqrs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// OutboxStream is the Redis stream events wait in until the outbox relay publishes them
+const OutboxStream = "outbox:events"
+
+// Outbox records events for publishing instead of publishing them directly. An event recorded
+// with a staged context (redisx.WithStage) is written in the same transaction as the document
+// write the context is used for, so it exists exactly when the state change does; the relay
+// then delivers it through Watermill at least once.
+type Outbox struct {
+	client        *redis.Client
+	marshaler     JSONMarshaler
+	generateTopic func(eventName string) string
+}
+
+// NewOutbox creates an outbox; generateTopic names the topic an event is published to, as the
+// event bus does
+func NewOutbox(client *redis.Client, marshaler JSONMarshaler, generateTopic func(eventName string) string) *Outbox {
+	return &Outbox{
+		client:        client,
+		marshaler:     marshaler,
+		generateTopic: generateTopic,
+	}
+}
+
+// Publish records an event. With a staged context the event is only written with the next
+// document write of that context; otherwise it's appended right away.
+func (o *Outbox) Publish(ctx context.Context, event interface{}) error {
+	msg, err := o.marshaler.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	SetRequestID(ctx, msg)
+	metadata, err := json.Marshal(msg.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event metadata: %w", err)
+	}
+
+	args := &redis.XAddArgs{
+		Stream: OutboxStream,
+		Values: map[string]interface{}{
+			"topic":    o.generateTopic(o.marshaler.Name(event)),
+			"uuid":     msg.UUID,
+			"payload":  string(msg.Payload),
+			"metadata": string(metadata),
+		},
+	}
+
+	if stage := redisx.StageFrom(ctx); stage != nil {
+		stage.Add(func(ctx context.Context, pipe redis.Pipeliner) {
+			pipe.XAdd(ctx, args)
+		})
+		return nil
+	}
+	return o.client.XAdd(ctx, args).Err()
+}
+
+// OutboxMessage decodes an outbox entry into the message to publish and its topic. The message
+// keeps the UUID it was recorded with, so redeliveries can be recognized.
+func OutboxMessage(entry redis.XMessage) (string, *message.Message, error) {
+	topic, _ := entry.Values["topic"].(string)
+	uuid, _ := entry.Values["uuid"].(string)
+	payload, _ := entry.Values["payload"].(string)
+	if topic == "" || uuid == "" {
+		return "", nil, fmt.Errorf("outbox entry %s has no topic or UUID", entry.ID)
+	}
+
+	msg := message.NewMessage(uuid, []byte(payload))
+	if metadata, _ := entry.Values["metadata"].(string); metadata != "" {
+		if err := json.Unmarshal([]byte(metadata), &msg.Metadata); err != nil {
+			return "", nil, fmt.Errorf("invalid metadata of outbox entry %s: %w", entry.ID, err)
+		}
+	}
+	return topic, msg, nil
+}
@@ -0,0 +1,35 @@
+package cqrs
+
+import (
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutboxMessage(t *testing.T) {
+	topic, msg, err := OutboxMessage(redis.XMessage{
+		ID: "1-0",
+		Values: map[string]interface{}{
+			"topic":    "game-events.TrainerMovedEvent",
+			"uuid":     "a4d7",
+			"payload":  `{"user_id":"u1"}`,
+			"metadata": `{"name":"TrainerMovedEvent","schema_version":"2"}`,
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "game-events.TrainerMovedEvent", topic)
+	assert.Equal(t, "a4d7", msg.UUID, "redeliveries keep the recorded UUID")
+	assert.Equal(t, `{"user_id":"u1"}`, string(msg.Payload))
+	assert.Equal(t, "TrainerMovedEvent", msg.Metadata.Get("name"))
+	assert.Equal(t, "2", msg.Metadata.Get("schema_version"))
+
+	// Entries that can't be published are reported
+	_, _, err = OutboxMessage(redis.XMessage{ID: "2-0", Values: map[string]interface{}{"payload": "{}"}})
+	assert.Error(t, err)
+	_, _, err = OutboxMessage(redis.XMessage{ID: "3-0", Values: map[string]interface{}{
+		"topic": "game-events.TrainerMovedEvent", "uuid": "b1", "metadata": "{",
+	}})
+	assert.Error(t, err)
+}
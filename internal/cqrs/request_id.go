@@ -0,0 +1,42 @@
+package cqrs
+
+import (
+	"context"
+
+	watermillcqrs "github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+// SetRequestID records the request ID ctx carries in a message's metadata
+func SetRequestID(ctx context.Context, msg *message.Message) {
+	if id := requestid.FromContext(ctx); id != "" {
+		msg.Metadata.Set(requestid.MetadataKey, id)
+	}
+}
+
+// EventRequestID is the event bus OnPublish hook recording the ID of the request that
+// published an event
+func EventRequestID(params watermillcqrs.OnEventSendParams) error {
+	SetRequestID(params.Message.Context(), params.Message)
+	return nil
+}
+
+// CommandRequestID is the command bus OnSend hook recording the ID of the request that sent
+// a command
+func CommandRequestID(params watermillcqrs.CommandBusOnSendParams) error {
+	SetRequestID(params.Message.Context(), params.Message)
+	return nil
+}
+
+// RequestIDMiddleware is router middleware handling every message with the ID of the request
+// that published it in the context, so handlers log it and pass it on to what they publish
+func RequestIDMiddleware(h message.HandlerFunc) message.HandlerFunc {
+	return func(msg *message.Message) ([]*message.Message, error) {
+		if id := msg.Metadata.Get(requestid.MetadataKey); id != "" {
+			msg.SetContext(requestid.With(msg.Context(), id))
+		}
+		return h(msg)
+	}
+}
@@ -0,0 +1,36 @@
+package cqrs
+
+import (
+	"context"
+	"testing"
+
+	watermillcqrs "github.com/ThreeDotsLabs/watermill/components/cqrs"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/pkg/requestid"
+)
+
+func TestRequestIDTravelsWithEvents(t *testing.T) {
+	msg := message.NewMessage("a4d7", []byte(`{}`))
+	msg.SetContext(requestid.With(context.Background(), "req-1"))
+	require.NoError(t, EventRequestID(watermillcqrs.OnEventSendParams{Message: msg}))
+	assert.Equal(t, "req-1", msg.Metadata.Get(requestid.MetadataKey))
+
+	// The handler sees the publisher's request ID, not its own
+	received := message.NewMessage(msg.UUID, msg.Payload)
+	received.Metadata = msg.Metadata
+	var handled string
+	_, err := RequestIDMiddleware(func(m *message.Message) ([]*message.Message, error) {
+		handled = requestid.FromContext(m.Context())
+		return nil, nil
+	})(received)
+	require.NoError(t, err)
+	assert.Equal(t, "req-1", handled)
+
+	// Events published outside requests carry none
+	background := message.NewMessage("b1", []byte(`{}`))
+	require.NoError(t, EventRequestID(watermillcqrs.OnEventSendParams{Message: background}))
+	assert.Empty(t, background.Metadata.Get(requestid.MetadataKey))
+}
@@ -0,0 +1,319 @@
+package cqrs
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// SchemaVersionKey is the message metadata holding the schema version of an event payload.
+// Payloads published without it are version 1.
+const SchemaVersionKey = "schema_version"
+
+// lockedContracts are the event contracts servers were released with; regenerate the file
+// with go test ./internal/cqrs -run TestEventContracts -update
+//
+//go:embed contracts.json
+var lockedContracts []byte
+
+// Upcaster rewrites an event payload of one schema version into the next
+type Upcaster func(payload map[string]interface{}) error
+
+// EventSchema is the current version of an event's contract
+type EventSchema struct {
+	Name    string
+	Version int
+	Type    reflect.Type
+	// Upcasters[i] upgrades a payload of version i+1 to version i+2
+	Upcasters []Upcaster
+}
+
+// Contract is the shape of an event payload: the JSON paths of its fields and their kinds.
+// Elements of arrays and maps are at path[] and path{}.
+type Contract struct {
+	Version int               `json:"version"`
+	Fields  map[string]string `json:"fields"`
+}
+
+// SchemaRegistry holds the versioned contracts of the events on the bus
+type SchemaRegistry struct {
+	schemas map[reflect.Type]*EventSchema
+}
+
+// NewSchemaRegistry creates an empty registry
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{
+		schemas: make(map[reflect.Type]*EventSchema),
+	}
+}
+
+// DefaultSchemas registers the events published by the game. Within a version fields may only
+// be added; any other change bumps the version and adds the upcaster from the previous one, so
+// payloads still in the streams can be read.
+func DefaultSchemas() *SchemaRegistry {
+	return NewSchemaRegistry().
+		Register(TrainerMovedEvent{}, 1).
+		Register(PositionsBatchEvent{}, 1).
+		Register(TrainerStoppedEvent{}, 1).
+		Register(TrainerCreatedEvent{}, 1).
+		Register(TrainerDiedEvent{}, 1).
+		Register(TrainerRespawnedEvent{}, 1).
+		Register(SSENotificationEvent{}, 1).
+		Register(AnimalSpawnedEvent{}, 1).
+		Register(AnimalMovedEvent{}, 1).
+		Register(AnimalCapturedEvent{}, 1).
+		Register(BattleStartedEvent{}, 1).
+		Register(BattleTurnEvent{}, 1).
+		Register(BattleEndedEvent{}, 1).
+		Register(LootDroppedEvent{}, 1).
+		Register(LootPickedUpEvent{}, 1).
+		Register(CraftStartedEvent{}, 1).
+		Register(CraftCompletedEvent{}, 1).
+		Register(ItemConsumedEvent{}, 1).
+		Register(InventoryChangedEvent{}, 1).
+		Register(BulletFiredEvent{}, 1).
+		Register(BulletHitEvent{}, 1).
+		Register(AmmoChangedEvent{}, 1).
+		Register(RoomJoinedEvent{}, 1).
+		Register(RoomLeftEvent{}, 1).
+		Register(MatchStartedEvent{}, 1).
+		Register(MatchScoreChangedEvent{}, 1).
+		Register(MatchEndedEvent{}, 1).
+		Register(ChatMessageEvent{}, 1).
+		Register(WorldUpdatedEvent{}, 1).
+		Register(WorldTimeChangedEvent{}, 1)
+}
+
+// Register adds the current version of an event's contract along with the upcasters from
+// every older version
+func (r *SchemaRegistry) Register(event interface{}, version int, upcasters ...Upcaster) *SchemaRegistry {
+	t := structType(reflect.TypeOf(event))
+	r.schemas[t] = &EventSchema{
+		Name:      t.Name(),
+		Version:   version,
+		Type:      t,
+		Upcasters: upcasters,
+	}
+	return r
+}
+
+// Lookup returns the schema of an event, given as a value or pointer
+func (r *SchemaRegistry) Lookup(event interface{}) (*EventSchema, bool) {
+	if r == nil {
+		return nil, false
+	}
+	schema, ok := r.schemas[structType(reflect.TypeOf(event))]
+	return schema, ok
+}
+
+// Contracts returns the current contracts of the registered events by name
+func (r *SchemaRegistry) Contracts() map[string]Contract {
+	contracts := make(map[string]Contract, len(r.schemas))
+	for _, schema := range r.schemas {
+		contracts[schema.Name] = schema.Contract()
+	}
+	return contracts
+}
+
+// Check verifies the registered events against the locked contracts: a version keeps every
+// field it was released with and their kinds, a version never goes back, and every version
+// has upcasters from all older ones
+func (r *SchemaRegistry) Check(locked map[string]Contract) error {
+	var problems []string
+	for _, schema := range r.schemas {
+		if len(schema.Upcasters) != schema.Version-1 {
+			problems = append(problems, fmt.Sprintf("%s v%d has %d upcasters, needs %d",
+				schema.Name, schema.Version, len(schema.Upcasters), schema.Version-1))
+		}
+
+		previous, ok := locked[schema.Name]
+		if !ok {
+			continue // New event
+		}
+		switch {
+		case schema.Version < previous.Version:
+			problems = append(problems, fmt.Sprintf("%s went back from v%d to v%d",
+				schema.Name, previous.Version, schema.Version))
+		case schema.Version == previous.Version:
+			current := schema.Contract()
+			for path, kind := range previous.Fields {
+				switch currentKind, exists := current.Fields[path]; {
+				case !exists:
+					problems = append(problems, fmt.Sprintf("%s v%d removed %s", schema.Name, schema.Version, path))
+				case currentKind != kind:
+					problems = append(problems, fmt.Sprintf("%s v%d changed %s from %s to %s",
+						schema.Name, schema.Version, path, kind, currentKind))
+				}
+			}
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+	return fmt.Errorf("incompatible event contracts, bump their versions with upcasters: %s",
+		strings.Join(problems, "; "))
+}
+
+// CheckLocked verifies the registered events against the contracts this build was released with
+func (r *SchemaRegistry) CheckLocked() error {
+	locked, err := LockedContracts()
+	if err != nil {
+		return err
+	}
+	return r.Check(locked)
+}
+
+// LockedContracts returns the contracts this build was released with
+func LockedContracts() (map[string]Contract, error) {
+	var locked map[string]Contract
+	if err := json.Unmarshal(lockedContracts, &locked); err != nil {
+		return nil, fmt.Errorf("failed to parse locked event contracts: %w", err)
+	}
+	return locked, nil
+}
+
+// Contract returns the contract of the event's current version
+func (s *EventSchema) Contract() Contract {
+	fields := make(map[string]string)
+	describeFields(s.Type, "", fields, make(map[reflect.Type]bool))
+	return Contract{Version: s.Version, Fields: fields}
+}
+
+// Upcast upgrades a payload of version from to the current version. Payloads of the current
+// or a newer version, published by servers deployed meanwhile, are returned as they are;
+// fields they added are ignored when decoding.
+func (s *EventSchema) Upcast(payload []byte, from int) ([]byte, error) {
+	if from >= s.Version {
+		return payload, nil
+	}
+	if from < 1 {
+		return nil, fmt.Errorf("invalid schema version %d of %s", from, s.Name)
+	}
+
+	// Numbers stay json.Number so IDs and counts aren't rounded through float64
+	var fields map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("failed to decode %s v%d payload: %w", s.Name, from, err)
+	}
+
+	for version := from; version < s.Version; version++ {
+		if err := s.Upcasters[version-1](fields); err != nil {
+			return nil, fmt.Errorf("failed to upcast %s from v%d: %w", s.Name, version, err)
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// structType returns the type a value or pointer of it has
+func structType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// describeFields adds the JSON fields of t under path to fields. Types are described once per
+// path, so recursive types end at their first repetition.
+func describeFields(t reflect.Type, path string, fields map[string]string, visiting map[reflect.Type]bool) {
+	t = structType(t)
+	if path != "" {
+		fields[path] = jsonKind(t)
+	}
+
+	switch {
+	case t == timeType || t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType):
+		return // Encodes itself
+	case t.Kind() == reflect.Struct:
+		if visiting[t] {
+			return
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			name, ok := jsonName(field)
+			if !ok {
+				continue
+			}
+			if name == "" {
+				describeFields(field.Type, path, fields, visiting) // Embedded fields are inlined
+				continue
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			describeFields(field.Type, name, fields, visiting)
+		}
+	case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && t.Elem().Kind() != reflect.Uint8:
+		describeFields(t.Elem(), path+"[]", fields, visiting)
+	case t.Kind() == reflect.Map:
+		describeFields(t.Elem(), path+"{}", fields, visiting)
+	}
+}
+
+// jsonName returns the JSON name of a struct field, "" for embedded structs encoding/json
+// inlines, and false for fields it doesn't encode
+func jsonName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name, _, _ := strings.Cut(tag, ",")
+
+	if field.Anonymous && name == "" && structType(field.Type).Kind() == reflect.Struct {
+		return "", true
+	}
+	if !field.IsExported() {
+		return "", false
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, true
+}
+
+// jsonKind names the JSON type a Go type encodes to
+func jsonKind(t reflect.Type) string {
+	switch {
+	case t == timeType:
+		return "time"
+	case t.Implements(marshalerType) || reflect.PtrTo(t).Implements(marshalerType):
+		return "custom"
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string" // Base64
+		}
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	default:
+		return "any"
+	}
+}
@@ -0,0 +1,130 @@
+package cqrs
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateContracts = flag.Bool("update", false, "rewrite contracts.json from the registered events")
+
+// TestEventContracts locks the contracts of the events on the bus. Adding fields passes the
+// compatibility check but still changes the contract; rerun with -update and commit the
+// file so the change is reviewed.
+func TestEventContracts(t *testing.T) {
+	schemas := DefaultSchemas()
+	current := schemas.Contracts()
+
+	if *updateContracts {
+		require.NoError(t, schemas.CheckLocked(), "incompatible changes need a version bump, not an update")
+		data, err := json.MarshalIndent(current, "", "  ")
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile("contracts.json", append(data, '\n'), 0o644))
+		return // The embedded copy is read at build time
+	}
+
+	require.NoError(t, schemas.CheckLocked())
+
+	locked, err := LockedContracts()
+	require.NoError(t, err)
+	assert.Equal(t, locked, current, "event contracts changed; rerun with -update")
+}
+
+type schemaTestEvent struct {
+	ID       string              `json:"id"`
+	Count    int                 `json:"count"`
+	Tags     []string            `json:"tags,omitempty"`
+	Position struct{ X float64 } `json:"position"`
+	At       time.Time           `json:"at"`
+	internal string
+}
+
+func TestEventSchema_Contract(t *testing.T) {
+	contract := NewSchemaRegistry().Register(&schemaTestEvent{}, 1).Contracts()["schemaTestEvent"]
+
+	assert.Equal(t, Contract{Version: 1, Fields: map[string]string{
+		"id":         "string",
+		"count":      "integer",
+		"tags":       "array",
+		"tags[]":     "string",
+		"position":   "object",
+		"position.X": "number",
+		"at":         "time",
+	}}, contract)
+}
+
+func TestSchemaRegistry_Check(t *testing.T) {
+	locked := map[string]Contract{
+		"schemaTestEvent": {Version: 1, Fields: map[string]string{"id": "string", "count": "integer"}},
+	}
+
+	// Added fields are compatible
+	assert.NoError(t, NewSchemaRegistry().Register(schemaTestEvent{}, 1).Check(locked))
+
+	// Removed and changed fields aren't within a version
+	locked["schemaTestEvent"].Fields["name"] = "string"
+	locked["schemaTestEvent"].Fields["count"] = "string"
+	err := NewSchemaRegistry().Register(schemaTestEvent{}, 1).Check(locked)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "removed name")
+	assert.Contains(t, err.Error(), "changed count from string to integer")
+
+	// A bumped version needs its upcaster
+	assert.Error(t, NewSchemaRegistry().Register(schemaTestEvent{}, 2).Check(locked))
+	assert.NoError(t, NewSchemaRegistry().Register(schemaTestEvent{}, 2, func(map[string]interface{}) error {
+		return nil
+	}).Check(locked))
+
+	// Versions never go back
+	assert.Error(t, NewSchemaRegistry().Register(schemaTestEvent{}, 1).Check(map[string]Contract{
+		"schemaTestEvent": {Version: 2},
+	}))
+}
+
+func TestJSONMarshaler_Upcast(t *testing.T) {
+	// v1 called the count "total", v2 kept it as a string, v3 is the struct
+	schemas := NewSchemaRegistry().Register(schemaTestEvent{}, 3,
+		func(payload map[string]interface{}) error {
+			payload["count"] = payload["total"].(json.Number).String()
+			delete(payload, "total")
+			return nil
+		},
+		func(payload map[string]interface{}) error {
+			payload["count"] = json.Number(payload["count"].(string))
+			return nil
+		},
+	)
+	marshaler := JSONMarshaler{Schemas: schemas}
+
+	// Messages published before versioning carry no version and are v1
+	old := message.NewMessage("1", []byte(`{"id":"a","total":9007199254740993}`))
+	var event schemaTestEvent
+	require.NoError(t, marshaler.Unmarshal(old, &event))
+	assert.Equal(t, "a", event.ID)
+	assert.Equal(t, 9007199254740993, event.Count, "numbers must not go through float64")
+
+	v2 := message.NewMessage("2", []byte(`{"id":"b","count":"7"}`))
+	v2.Metadata.Set(SchemaVersionKey, "2")
+	event = schemaTestEvent{}
+	require.NoError(t, marshaler.Unmarshal(v2, &event))
+	assert.Equal(t, 7, event.Count)
+
+	// Current messages carry their version and round-trip untouched
+	msg, err := marshaler.Marshal(&schemaTestEvent{ID: "c", Count: 3})
+	require.NoError(t, err)
+	assert.Equal(t, "3", msg.Metadata.Get(SchemaVersionKey))
+	event = schemaTestEvent{}
+	require.NoError(t, marshaler.Unmarshal(msg, &event))
+	assert.Equal(t, 3, event.Count)
+
+	// Unregistered types are left unversioned
+	msg, err = marshaler.Marshal(&struct{ Name string }{Name: "x"})
+	require.NoError(t, err)
+	assert.Empty(t, msg.Metadata.Get(SchemaVersionKey))
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"time"
 
-	"github.com/google/uuid"
+	"github.com/danghamo/life/pkg/requestid"
 )
 
 // SSEBroadcastHelper provides utility functions for sending SSE notifications
@@ -26,7 +26,7 @@ func (h *SSEBroadcastHelper) BroadcastToAll(ctx context.Context, method string,
 		Method:    method,
 		Params:    params,
 		Timestamp: time.Now(),
-		RequestID: uuid.New().String(),
+		RequestID: requestid.FromContextOrNew(ctx),
 	}
 
 	return h.eventPublisher.Publish(ctx, event)
@@ -45,7 +45,7 @@ func (h *SSEBroadcastHelper) BroadcastToUsers(ctx context.Context, userIDs []str
 		Method:      method,
 		Params:      params,
 		Timestamp:   time.Now(),
-		RequestID:   uuid.New().String(),
+		RequestID:   requestid.FromContextOrNew(ctx),
 	}
 
 	return h.eventPublisher.Publish(ctx, event)
@@ -7,6 +7,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+
+	"github.com/danghamo/life/pkg/requestid"
 )
 
 // MockEventPublisher for testing
@@ -58,7 +60,7 @@ func TestSSEBroadcastHelper_BroadcastToUsers(t *testing.T) {
 	mockPublisher.On("Publish", mock.Anything, mock.Anything).Return(nil)
 	
 	helper := NewSSEBroadcastHelper(mockPublisher)
-	ctx := context.Background()
+	ctx := requestid.With(context.Background(), "req-1")
 
 	// Test broadcast to specific users
 	targetUsers := []string{"alice", "bob", "charlie"}
@@ -80,7 +82,7 @@ func TestSSEBroadcastHelper_BroadcastToUsers(t *testing.T) {
 	assert.Equal(t, method, event.Method)
 	assert.Equal(t, params, event.Params)
 	assert.Equal(t, targetUsers, event.TargetUsers)
-	assert.NotEmpty(t, event.RequestID)
+	assert.Equal(t, "req-1", event.RequestID) // Carried over from the request
 	assert.WithinDuration(t, time.Now(), event.Timestamp, time.Second)
 }
 
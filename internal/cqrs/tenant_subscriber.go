@@ -0,0 +1,60 @@
+package cqrs
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// TenantSubscriber subscribes to a topic in every tenant. Events published for a tenant go to
+// its own stream, since the publisher's Redis client namespaces stream keys; each message is
+// handled with its tenant in the context, so handlers read and write that tenant's data.
+type TenantSubscriber struct {
+	message.Subscriber
+	tenants []*tenant.Tenant
+}
+
+// NewTenantSubscriber wraps a subscriber to receive the events of every tenant
+func NewTenantSubscriber(subscriber message.Subscriber, tenants []*tenant.Tenant) *TenantSubscriber {
+	return &TenantSubscriber{Subscriber: subscriber, tenants: tenants}
+}
+
+// Subscribe merges the tenants' streams of a topic into one channel
+func (s *TenantSubscriber) Subscribe(ctx context.Context, topic string) (<-chan *message.Message, error) {
+	if len(s.tenants) <= 1 {
+		return s.Subscriber.Subscribe(ctx, topic)
+	}
+
+	merged := make(chan *message.Message)
+	var wg sync.WaitGroup
+	for _, t := range s.tenants {
+		messages, err := s.Subscriber.Subscribe(ctx, t.ID.Key(topic))
+		if err != nil {
+			return nil, err
+		}
+
+		wg.Add(1)
+		go func(t *tenant.Tenant) {
+			defer wg.Done()
+			for msg := range messages {
+				msg.SetContext(tenant.WithTenant(msg.Context(), t))
+				select {
+				case merged <- msg:
+				case <-ctx.Done():
+					msg.Nack()
+					return
+				}
+			}
+		}(t)
+	}
+
+	go func() {
+		wg.Wait()
+		close(merged)
+	}()
+
+	return merged, nil
+}
@@ -0,0 +1,86 @@
+package accessibility
+
+import (
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// BroadcastRate is how often a player receives the positions of trainers moving around them
+type BroadcastRate string
+
+const (
+	RateFull    BroadcastRate = "full"    // Every frame of the movement simulation
+	RateReduced BroadcastRate = "reduced" // A frame every ReducedInterval, for less motion on screen and less traffic
+)
+
+// String returns string representation
+func (r BroadcastRate) String() string {
+	return string(r)
+}
+
+// IsValid checks if the broadcast rate is known
+func (r BroadcastRate) IsValid() bool {
+	return r == RateFull || r == RateReduced
+}
+
+const (
+	// ReducedInterval is the time between the position frames sent on the reduced rate
+	ReducedInterval = 250 * time.Millisecond
+
+	// MaxAimAssist is the strongest aim assistance level; 0 turns it off
+	MaxAimAssist = 3
+
+	// AimAssistTolerance is how far wide of a target a bullet may pass and still hit, in
+	// tiles, per aim assistance level
+	AimAssistTolerance = 0.25
+)
+
+// Settings are a player's accessibility options. They belong to the account, so every
+// character of the player is shown and plays the same way.
+type Settings struct {
+	BroadcastRate BroadcastRate       `json:"broadcast_rate"`
+	Palette       trainer.PaletteName `json:"palette"`    // Palette the player's trainers take their colors from
+	AimAssist     int                 `json:"aim_assist"` // 0 to MaxAimAssist
+	UpdatedAt     *time.Time          `json:"updated_at,omitempty"`
+}
+
+// Default returns the settings of players who never changed any
+func Default() Settings {
+	return Settings{
+		BroadcastRate: RateFull,
+		Palette:       trainer.StandardPalette,
+	}
+}
+
+// Validate checks every option has an allowed value
+func (s Settings) Validate() error {
+	if !s.BroadcastRate.IsValid() {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown broadcast rate: %s", s.BroadcastRate)
+	}
+	if _, ok := s.Palette.Colors(); !ok {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown palette: %s", s.Palette)
+	}
+	if s.AimAssist < 0 || s.AimAssist > MaxAimAssist {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Aim assist must be between 0 and %d", MaxAimAssist)
+	}
+	return nil
+}
+
+// Reduced checks if the player receives positions on the reduced rate
+func (s Settings) Reduced() bool {
+	return s.BroadcastRate == RateReduced
+}
+
+// HitTolerance returns how far wide of a target the player's bullets may pass and still hit
+func (s Settings) HitTolerance() float64 {
+	return float64(s.AimAssist) * AimAssistTolerance
+}
+
+// OnReducedBeat checks if a simulation frame taken at ts, frameInterval after the previous
+// one, is one sent on the reduced rate. The first frame of every ReducedInterval is, so
+// servers agree without keeping any state.
+func OnReducedBeat(ts time.Time, frameInterval time.Duration) bool {
+	return ts.UnixNano()%int64(ReducedInterval) < int64(frameInterval)
+}
@@ -0,0 +1,44 @@
+package accessibility
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+func TestSettings_Validate(t *testing.T) {
+	assert.NoError(t, Default().Validate())
+
+	valid := Settings{BroadcastRate: RateReduced, Palette: trainer.HighContrastPalette, AimAssist: MaxAimAssist}
+	assert.NoError(t, valid.Validate())
+	assert.True(t, valid.Reduced())
+	assert.InDelta(t, 0.75, valid.HitTolerance(), 1e-9)
+
+	for name, invalid := range map[string]Settings{
+		"rate":     {BroadcastRate: "slow", Palette: trainer.StandardPalette},
+		"palette":  {BroadcastRate: RateFull, Palette: "neon"},
+		"assist":   {BroadcastRate: RateFull, Palette: trainer.StandardPalette, AimAssist: MaxAimAssist + 1},
+		"negative": {BroadcastRate: RateFull, Palette: trainer.StandardPalette, AimAssist: -1},
+	} {
+		code, _ := shared.DomainErrorCode(invalid.Validate())
+		assert.Equal(t, shared.ErrCodeInvalidInput, code, name)
+	}
+}
+
+func TestOnReducedBeat(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	for _, rate := range []int{60, 30, 20} {
+		interval := time.Second / time.Duration(rate)
+		beats := 0
+		for frame := 0; frame < rate; frame++ {
+			if OnReducedBeat(start.Add(time.Duration(frame)*interval), interval) {
+				beats++
+			}
+		}
+		assert.Equal(t, int(time.Second/ReducedInterval), beats, "%dHz", rate)
+	}
+}
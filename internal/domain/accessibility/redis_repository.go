@@ -0,0 +1,92 @@
+package accessibility
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// reducedRateKey is the set of accounts on the reduced broadcast rate, read as a whole by
+// position broadcasts rather than looking up every recipient's settings
+const reducedRateKey = "accessibility:reduced"
+
+// RedisRepository implements Repository with a JSON value per account
+type RedisRepository struct {
+	client *redis.Client
+	docs   *redisx.Repository[Settings]
+}
+
+// NewRedisRepository creates a new Redis-based accessibility settings repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+		docs: redisx.NewRepository(client, "accessibility:", redisx.Options[Settings]{
+			// Settings added later keep their defaults for accounts that saved before
+			Codec: redisx.StringCodec[Settings]{
+				Unmarshal: func(data []byte, settings *Settings) error {
+					*settings = Default()
+					return json.Unmarshal(data, settings)
+				},
+			},
+			Indexes: []redisx.Index[Settings]{
+				redisx.SetIndex[Settings]{Key: func(settings *Settings) string {
+					if settings.Reduced() {
+						return reducedRateKey
+					}
+					return ""
+				}},
+			},
+		}),
+	}
+}
+
+// Get retrieves an account's settings
+func (r *RedisRepository) Get(ctx context.Context, accountID trainer.UserID) (Settings, error) {
+	settings, err := r.docs.GetByID(ctx, accountID.String())
+	if err != nil {
+		return Settings{}, err
+	}
+	if settings == nil {
+		return Default(), nil
+	}
+	return *settings, nil
+}
+
+// FindOneAndUpdate implements IoC pattern for update operations, keeping the reduced rate
+// set in step with the settings
+func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, accountID trainer.UserID, callback func(*Settings) error) error {
+	return r.docs.FindOneAndUpsert(ctx, accountID.String(), func(settings *Settings) (*Settings, error) {
+		if settings == nil {
+			defaults := Default()
+			settings = &defaults
+		}
+
+		if err := callback(settings); err != nil {
+			return nil, err
+		}
+		return settings, nil
+	})
+}
+
+// ReducedRate lists the accounts receiving positions on the reduced rate
+func (r *RedisRepository) ReducedRate(ctx context.Context) ([]string, error) {
+	return r.client.SMembers(ctx, reducedRateKey).Result()
+}
+
+// DeleteUser removes an account's settings, which restores the defaults
+func (r *RedisRepository) DeleteUser(ctx context.Context, accountID trainer.UserID) error {
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, r.docs.Key(accountID.String()))
+		pipe.SRem(ctx, reducedRateKey, accountID.String())
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete accessibility settings: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package accessibility
+
+import (
+	"context"
+
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// Repository stores players' accessibility settings by account with IoC pattern
+type Repository interface {
+	// Get retrieves an account's settings, the defaults if it never changed any (read-only)
+	Get(ctx context.Context, accountID trainer.UserID) (Settings, error)
+
+	// FindOneAndUpdate loads an account's settings and applies callback for atomic update
+	FindOneAndUpdate(ctx context.Context, accountID trainer.UserID, callback func(*Settings) error) error
+
+	// ReducedRate lists the accounts receiving positions on the reduced rate
+	ReducedRate(ctx context.Context) ([]string, error)
+
+	// DeleteUser removes an account's settings
+	DeleteUser(ctx context.Context, accountID trainer.UserID) error
+}
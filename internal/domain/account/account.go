@@ -77,6 +77,7 @@ type Account struct {
 	Profile     OAuthProfile     `json:"profile"`
 	DeviceID    string           `json:"device_id,omitempty"` // 게스트용 기기 식별자
 	LinkedAt    *shared.Timestamp `json:"linked_at,omitempty"` // 소셜 연동 시점
+	MFA         *MFA              `json:"mfa,omitempty"`       // Second factor of the user, on one of their accounts
 	CreatedAt   shared.Timestamp `json:"created_at"`
 	UpdatedAt   shared.Timestamp `json:"updated_at"`
 }
@@ -114,6 +115,11 @@ func NewAccountWithUserID(provider Provider, profile OAuthProfile, userID UserID
 
 // NewGuestAccount creates a new guest account with device identifier
 func NewGuestAccount(deviceID string) (*Account, error) {
+	return NewGuestAccountWithUserID(deviceID, NewUserID())
+}
+
+// NewGuestAccountWithUserID creates a guest account for a device paired to an existing UserID
+func NewGuestAccountWithUserID(deviceID string, userID UserID) (*Account, error) {
 	if deviceID == "" {
 		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Device ID cannot be empty for guest account")
 	}
@@ -130,7 +136,7 @@ func NewGuestAccount(deviceID string) (*Account, error) {
 
 	return &Account{
 		ID:        NewAccountID(),
-		UserID:    NewUserID(),
+		UserID:    userID,
 		Provider:  ProviderGuest,
 		Profile:   profile,
 		DeviceID:  deviceID,
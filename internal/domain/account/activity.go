@@ -0,0 +1,68 @@
+package account
+
+import (
+	"context"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// ActivityLogSize is how many recent activities are kept per user
+const ActivityLogSize = 100
+
+// ActivityType identifies a security-relevant account event
+type ActivityType string
+
+const (
+	ActivityLogin            ActivityType = "login"
+	ActivityLoginChallenged  ActivityType = "login_challenged"
+	ActivityDevicePaired     ActivityType = "device_paired"
+	ActivityProviderLinked   ActivityType = "provider_linked"
+	ActivityProviderUnlinked ActivityType = "provider_unlinked"
+	ActivityEmailChanged     ActivityType = "email_changed"
+	ActivityEmailVerified    ActivityType = "email_verified"
+	ActivityAccountsMerged   ActivityType = "accounts_merged"
+	ActivityMFAEnabled       ActivityType = "mfa_enabled"
+)
+
+// Activity is one entry of the timeline a player can review to spot account compromise.
+// It never holds raw device IDs, addresses or codes.
+type Activity struct {
+	Type        ActivityType     `json:"type"`
+	Provider    Provider         `json:"provider,omitempty"`
+	NewDevice   bool             `json:"new_device,omitempty"`
+	NewLocation bool             `json:"new_location,omitempty"`
+	Email       string           `json:"email,omitempty"` // Masked address
+	At          shared.Timestamp `json:"at"`
+}
+
+// NewActivity creates an activity that happened now
+func NewActivity(activityType ActivityType) *Activity {
+	return &Activity{
+		Type: activityType,
+		At:   shared.NewTimestamp(),
+	}
+}
+
+// WithRisk records why a login was considered suspicious
+func (a *Activity) WithRisk(risk LoginRisk) *Activity {
+	a.NewDevice = risk.NewDevice
+	a.NewLocation = risk.NewLocation
+	return a
+}
+
+// ActivityRepository stores each user's recent activities
+type ActivityRepository interface {
+	// Record appends an activity, dropping the oldest beyond ActivityLogSize
+	Record(ctx context.Context, userID UserID, activity *Activity) error
+
+	// ListByUserID returns up to limit activities, newest first
+	ListByUserID(ctx context.Context, userID UserID, limit int) ([]*Activity, error)
+
+	// DeleteByUserID removes a user's whole timeline
+	DeleteByUserID(ctx context.Context, userID UserID) error
+
+	// PurgeBefore drops activities older than before across all users, returning how many
+	// were (or, with dryRun, would be) removed
+	PurgeBefore(ctx context.Context, before time.Time, dryRun bool) (int64, error)
+}
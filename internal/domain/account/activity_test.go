@@ -0,0 +1,21 @@
+package account
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActivity_WithRisk(t *testing.T) {
+	activity := NewActivity(ActivityLogin).WithRisk(LoginRisk{NewLocation: true})
+	assert.Equal(t, ActivityLogin, activity.Type)
+	assert.False(t, activity.NewDevice)
+	assert.True(t, activity.NewLocation)
+
+	data, err := json.Marshal(NewActivity(ActivityDevicePaired))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "new_device", "unset details are left out of the timeline")
+	assert.NotContains(t, string(data), "email")
+}
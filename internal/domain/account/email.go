@@ -0,0 +1,131 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// EmailVerificationTTL is how long a verification link stays valid
+const EmailVerificationTTL = 24 * time.Hour
+
+// Email is a user's contact address and whether they proved they own it. It belongs to the
+// UserID rather than to one sign-in account, so every linked provider and device shares it.
+// Provider profile emails are only used to link accounts and are never treated as verified.
+type Email struct {
+	UserID     UserID            `json:"user_id"`
+	Address    string            `json:"address"`
+	VerifiedAt *shared.Timestamp `json:"verified_at,omitempty"`
+	UpdatedAt  shared.Timestamp  `json:"updated_at"`
+}
+
+// ParseEmailAddress normalizes an address entered by a player
+func ParseEmailAddress(s string) (string, error) {
+	parsed, err := mail.ParseAddress(strings.TrimSpace(s))
+	if err != nil || parsed.Name != "" {
+		return "", shared.NewDomainError(shared.ErrCodeInvalidInput, "Invalid email address")
+	}
+	return strings.ToLower(parsed.Address), nil
+}
+
+// IsVerified reports whether the current address has been verified
+func (e *Email) IsVerified() bool {
+	return e != nil && e.Address != "" && e.VerifiedAt != nil
+}
+
+// MaskedAddress hides most of the local part, for telling a signed-out player where a code went
+func (e *Email) MaskedAddress() string {
+	local, domain, ok := strings.Cut(e.Address, "@")
+	if !ok || local == "" {
+		return ""
+	}
+	return local[:1] + "***@" + domain
+}
+
+// Change sets a new unverified address and returns the previous one. The new address must be
+// verified again before features that require a verified email unlock.
+func (e *Email) Change(address string) (string, error) {
+	if address == e.Address {
+		return "", shared.NewDomainError(shared.ErrCodeInvalidOperation, "Email address is unchanged")
+	}
+
+	previous := e.Address
+	e.Address = address
+	e.VerifiedAt = nil
+	e.UpdatedAt = shared.NewTimestamp()
+
+	return previous, nil
+}
+
+// Verify marks the address as verified. Links sent to an address the player has since
+// changed away from no longer count.
+func (e *Email) Verify(address string) error {
+	if e.Address != address {
+		return shared.NewDomainError(shared.ErrCodeInvalidVerificationToken, "Verification link is for a previous email address")
+	}
+
+	if e.VerifiedAt == nil {
+		timestamp := shared.NewTimestamp()
+		e.VerifiedAt = &timestamp
+		e.UpdatedAt = timestamp
+	}
+
+	return nil
+}
+
+// RequireVerifiedEmail returns an error unless the user has verified an email address.
+// Trading and auction flows must check it before moving items between players.
+func RequireVerifiedEmail(e *Email) error {
+	if !e.IsVerified() {
+		return shared.NewDomainError(shared.ErrCodeEmailNotVerified, "A verified email address is required")
+	}
+	return nil
+}
+
+// EmailVerification is a pending verification link for one address
+type EmailVerification struct {
+	Token     string    `json:"token"`
+	UserID    UserID    `json:"user_id"`
+	Address   string    `json:"address"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewEmailVerification creates a verification with a random token
+func NewEmailVerification(userID UserID, address string) (*EmailVerification, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return nil, err
+	}
+
+	return &EmailVerification{
+		Token:     hex.EncodeToString(bytes),
+		UserID:    userID,
+		Address:   address,
+		ExpiresAt: time.Now().Add(EmailVerificationTTL),
+	}, nil
+}
+
+// EmailRepository stores email addresses and pending verifications
+type EmailRepository interface {
+	// FindOneAndUpdate applies callback atomically. The callback receives nil when the user
+	// has never set an address.
+	FindOneAndUpdate(ctx context.Context, userID UserID, callback func(*Email) (*Email, error)) error
+
+	// GetByUserID retrieves a user's email (read-only). It returns nil when none is set.
+	GetByUserID(ctx context.Context, userID UserID) (*Email, error)
+
+	// SaveVerification stores a verification until it expires
+	SaveVerification(ctx context.Context, verification *EmailVerification) error
+
+	// TakeVerification consumes a verification token. It returns nil when the token is
+	// unknown or expired.
+	TakeVerification(ctx context.Context, token string) (*EmailVerification, error)
+
+	// Delete removes a user's email. Pending verifications expire on their own.
+	Delete(ctx context.Context, userID UserID) error
+}
@@ -0,0 +1,42 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEmailAddress(t *testing.T) {
+	address, err := ParseEmailAddress(" Player@Example.com ")
+	require.NoError(t, err)
+	assert.Equal(t, "player@example.com", address)
+
+	_, err = ParseEmailAddress("not-an-email")
+	assert.Error(t, err)
+	_, err = ParseEmailAddress("Player <player@example.com>")
+	assert.Error(t, err, "display names are not part of the address")
+}
+
+func TestEmail_ChangeRequiresReverification(t *testing.T) {
+	email := &Email{UserID: "user-1"}
+	assert.Error(t, RequireVerifiedEmail(email))
+
+	previous, err := email.Change("old@example.com")
+	require.NoError(t, err)
+	assert.Empty(t, previous)
+	require.NoError(t, email.Verify("old@example.com"))
+	assert.NoError(t, RequireVerifiedEmail(email))
+
+	previous, err = email.Change("new@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "old@example.com", previous)
+	assert.Error(t, RequireVerifiedEmail(email), "a new address must be verified again")
+
+	assert.Error(t, email.Verify("old@example.com"), "links sent to the old address no longer count")
+	require.NoError(t, email.Verify("new@example.com"))
+	assert.True(t, email.IsVerified())
+
+	_, err = email.Change("new@example.com")
+	assert.Error(t, err)
+}
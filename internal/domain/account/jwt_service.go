@@ -1,9 +1,12 @@
 package account
 
 import (
+	"context"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/danghamo/life/pkg/tenant"
 )
 
 // JWTClaims represents the JWT token claims with UserID (not AccountID)
@@ -11,14 +14,38 @@ type JWTClaims struct {
 	UserID string `json:"user_id"` // Game domain identifier
 	Email  string `json:"email"`
 	Name   string `json:"name"`
+	// TenantID is the tenant whose account the token was issued for, empty for the default one
+	TenantID string `json:"tid,omitempty"`
+	// Role is the user's role when the token was issued
+	Role Role `json:"role,omitempty"`
+	// CharacterID is the trainer the user plays, empty for their first one
+	CharacterID string `json:"cid,omitempty"`
+	// SessionID is the sign-in the token was issued for, empty for tokens issued before
+	// sessions were registered
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
+// PlayerID returns the ID of the trainer the token plays
+func (c *JWTClaims) PlayerID() string {
+	if c.CharacterID == "" {
+		return c.UserID
+	}
+	return c.CharacterID
+}
+
+// CharacterResolver returns the character a user plays after signing in, empty for their
+// first one
+type CharacterResolver func(ctx context.Context, userID UserID) string
+
 // JWTService handles JWT token operations
 type JWTService struct {
 	secretKey      []byte
 	issuer         string
 	expiryDuration time.Duration
+	roles          map[string]Role // Users whose tokens get a role above RolePlayer
+	characters     CharacterResolver
+	sessions       SessionRegistry
 }
 
 // NewJWTService creates a new JWT service
@@ -30,16 +57,86 @@ func NewJWTService(secretKey string, issuer string, expiryDuration time.Duration
 	}
 }
 
-// GenerateToken generates a new JWT token for an account (but contains UserID)
-func (s *JWTService) GenerateToken(account *Account) (string, error) {
+// SetRoles sets the users whose tokens carry RoleAdmin and RoleModerator; everyone else
+// gets RolePlayer. A user listed twice gets the higher role. Tokens issued before keep their
+// role until they are refreshed or expire.
+func (s *JWTService) SetRoles(admins, moderators []string) {
+	s.roles = make(map[string]Role, len(admins)+len(moderators))
+	for _, userID := range moderators {
+		s.roles[userID] = RoleModerator
+	}
+	for _, userID := range admins {
+		s.roles[userID] = RoleAdmin
+	}
+}
+
+// SetCharacters sets how sign-ins pick the character to play; without it they play the
+// user's first one
+func (s *JWTService) SetCharacters(resolver CharacterResolver) {
+	s.characters = resolver
+}
+
+// SetSessions sets the registry of the sessions tokens are issued for; without it tokens
+// carry a session that isn't registered anywhere
+func (s *JWTService) SetSessions(registry SessionRegistry) {
+	s.sessions = registry
+}
+
+// roleOf returns the role a user's tokens are issued with
+func (s *JWTService) roleOf(userID string) Role {
+	if role, ok := s.roles[userID]; ok {
+		return role
+	}
+	return RolePlayer
+}
+
+// GenerateToken generates a new JWT token for an account (but contains UserID), signing the
+// user in with a new session. The token is bound to the context's tenant and plays the
+// character the user last selected.
+func (s *JWTService) GenerateToken(ctx context.Context, account *Account) (string, error) {
+	characterID := ""
+	if s.characters != nil {
+		characterID = s.characters(ctx, account.UserID)
+	}
+
+	session, err := NewSession(UserAgentFromContext(ctx), time.Now().Add(s.expiryDuration))
+	if err != nil {
+		return "", err
+	}
+	if s.sessions != nil {
+		if err := s.sessions.Start(ctx, account.UserID, session); err != nil {
+			return "", err
+		}
+	}
+
+	return s.GenerateCharacterToken(ctx, account.UserID, account.Profile.Email, account.Profile.Name, characterID, session.ID)
+}
+
+// GenerateCharacterToken generates a new JWT token for a user playing one of their
+// characters, the first one when characterID is empty, in an existing session, which is kept
+// registered as long as the token. The token is bound to the context's tenant.
+func (s *JWTService) GenerateCharacterToken(ctx context.Context, userID UserID, email, name, characterID, sessionID string) (string, error) {
+	if characterID == userID.String() {
+		characterID = ""
+	}
+
 	now := time.Now()
+	if s.sessions != nil && sessionID != "" {
+		if err := s.sessions.Extend(ctx, userID, sessionID, now.Add(s.expiryDuration)); err != nil {
+			return "", err
+		}
+	}
 	claims := JWTClaims{
-		UserID: account.UserID.String(), // Use UserID for game domain
-		Email:  account.Profile.Email,
-		Name:   account.Profile.Name,
+		UserID:      userID.String(), // Use UserID for game domain
+		Email:       email,
+		Name:        name,
+		TenantID:    tenant.IDFromContext(ctx).String(),
+		Role:        s.roleOf(userID.String()),
+		CharacterID: characterID,
+		SessionID:   sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.issuer,
-			Subject:   account.UserID.String(), // Subject is UserID
+			Subject:   userID.String(), // Subject is UserID
 			IssuedAt:  jwt.NewNumericDate(now),
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.expiryDuration)),
 		},
@@ -81,9 +178,13 @@ func (s *JWTService) RefreshToken(tokenString string) (string, error) {
 	// Create new token with fresh expiry
 	now := time.Now()
 	newClaims := JWTClaims{
-		UserID: claims.UserID,
-		Email:  claims.Email,
-		Name:   claims.Name,
+		UserID:      claims.UserID,
+		Email:       claims.Email,
+		Name:        claims.Name,
+		TenantID:    claims.TenantID,
+		Role:        s.roleOf(claims.UserID),
+		CharacterID: claims.CharacterID,
+		SessionID:   claims.SessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.issuer,
 			Subject:   claims.UserID,
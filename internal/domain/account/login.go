@@ -0,0 +1,234 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+const (
+	// LoginCodeLength is the number of digits in a step-up login code
+	LoginCodeLength = 6
+	// LoginChallengeTTL is how long a step-up login code can be entered
+	LoginChallengeTTL = 10 * time.Minute
+	// MaxLoginChallengeAttempts is how many wrong codes end a challenge
+	MaxLoginChallengeAttempts = 5
+)
+
+// LoginFingerprint describes where a login came from, as hashes so raw device IDs and
+// addresses are not stored. Without a geolocation database, the location is approximated by
+// the client's network (IPv4 /16, IPv6 /32).
+type LoginFingerprint struct {
+	Device  string `json:"device,omitempty"`
+	Network string `json:"network,omitempty"`
+}
+
+// NewLoginFingerprint hashes a login's device ID and client IP. Either may be empty.
+func NewLoginFingerprint(deviceID, ip string) LoginFingerprint {
+	var fingerprint LoginFingerprint
+	if deviceID != "" {
+		fingerprint.Device = hashLoginPart("device", deviceID)
+	}
+	if network := networkOf(ip); network != "" {
+		fingerprint.Network = hashLoginPart("network", network)
+	}
+	return fingerprint
+}
+
+// networkOf returns the network prefix an IP belongs to, or "" when it is not an IP
+func networkOf(ip string) string {
+	parsed := net.ParseIP(strings.TrimSpace(ip))
+	if parsed == nil {
+		return ""
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		return v4.Mask(net.CIDRMask(16, 32)).String()
+	}
+	return parsed.Mask(net.CIDRMask(32, 128)).String()
+}
+
+func hashLoginPart(kind, value string) string {
+	sum := sha256.Sum256([]byte(kind + ":" + value))
+	return hex.EncodeToString(sum[:])
+}
+
+// LoginHistory lists the devices and networks a user has signed in from
+type LoginHistory struct {
+	Devices  []string `json:"devices"`
+	Networks []string `json:"networks"`
+}
+
+// LoginRisk explains why a login looks suspicious
+type LoginRisk struct {
+	NewDevice   bool `json:"new_device"`
+	NewLocation bool `json:"new_location"`
+}
+
+// IsSuspicious reports whether the login needs step-up verification
+func (r LoginRisk) IsSuspicious() bool {
+	return r.NewDevice || r.NewLocation
+}
+
+// Assess compares a login against the history. A user's first login is never suspicious, and
+// parts the client didn't send are not counted as new.
+func (h *LoginHistory) Assess(fingerprint LoginFingerprint) LoginRisk {
+	if h == nil || (len(h.Devices) == 0 && len(h.Networks) == 0) {
+		return LoginRisk{}
+	}
+
+	return LoginRisk{
+		NewDevice:   fingerprint.Device != "" && !slices.Contains(h.Devices, fingerprint.Device),
+		NewLocation: fingerprint.Network != "" && !slices.Contains(h.Networks, fingerprint.Network),
+	}
+}
+
+// LoginMethod is how a step-up login is verified
+type LoginMethod string
+
+const (
+	LoginMethodEmail LoginMethod = "email" // A code sent to the user's verified email
+	LoginMethodTOTP  LoginMethod = "totp"  // A code from the user's authenticator app, or a backup code
+)
+
+// LoginChallenge is a login waiting for the code sent to the user's verified email, or for
+// their second factor
+type LoginChallenge struct {
+	ID          string           `json:"id"`
+	UserID      UserID           `json:"user_id"`
+	AccountID   AccountID        `json:"account_id"`
+	Method      LoginMethod      `json:"method,omitempty"` // Empty for challenges created before second factors
+	CodeHash    string           `json:"code_hash,omitempty"`
+	Fingerprint LoginFingerprint `json:"fingerprint"`
+	Risk        LoginRisk        `json:"risk"`
+	Attempts    int              `json:"attempts"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+}
+
+// NewLoginChallenge creates a challenge for a login and returns it with the code to send
+func NewLoginChallenge(acc *Account, fingerprint LoginFingerprint, risk LoginRisk) (*LoginChallenge, string, error) {
+	challenge, err := newLoginChallenge(acc, LoginMethodEmail, fingerprint, risk)
+	if err != nil {
+		return nil, "", err
+	}
+
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(LoginCodeLength), nil)
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return nil, "", err
+	}
+	code := fmt.Sprintf("%0*d", LoginCodeLength, n)
+	challenge.CodeHash = hashLoginPart("code", code)
+
+	return challenge, code, nil
+}
+
+// NewMFAChallenge creates a challenge for the login of a user with a second factor. Nothing
+// is sent; the user enters a code from their authenticator app.
+func NewMFAChallenge(acc *Account, fingerprint LoginFingerprint, risk LoginRisk) (*LoginChallenge, error) {
+	return newLoginChallenge(acc, LoginMethodTOTP, fingerprint, risk)
+}
+
+func newLoginChallenge(acc *Account, method LoginMethod, fingerprint LoginFingerprint, risk LoginRisk) (*LoginChallenge, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	return &LoginChallenge{
+		ID:          hex.EncodeToString(id),
+		UserID:      acc.UserID,
+		AccountID:   acc.ID,
+		Method:      method,
+		Fingerprint: fingerprint,
+		Risk:        risk,
+		ExpiresAt:   time.Now().Add(LoginChallengeTTL),
+	}, nil
+}
+
+// Attempt checks an entered code. Every attempt counts, so a challenge can't be brute-forced.
+func (c *LoginChallenge) Attempt(code string) error {
+	if err := c.count(); err != nil {
+		return err
+	}
+
+	entered := hashLoginPart("code", strings.TrimSpace(code))
+	if subtle.ConstantTimeCompare([]byte(entered), []byte(c.CodeHash)) != 1 {
+		return c.incorrect()
+	}
+
+	return nil
+}
+
+// AttemptMFA checks an entered authenticator or backup code against the user's second factor,
+// counting attempts like Attempt does. The second factor is left as it is; the caller consumes
+// the code with MFA.Verify once the attempt is saved.
+func (c *LoginChallenge) AttemptMFA(mfa *MFA, code string, now time.Time) error {
+	if err := c.count(); err != nil {
+		return err
+	}
+
+	trial := *mfa
+	trial.BackupCodes = slices.Clone(mfa.BackupCodes)
+	if !trial.Verify(code, now) {
+		return c.incorrect()
+	}
+
+	return nil
+}
+
+// count records an attempt, refusing it once the challenge expired or ran out of attempts
+func (c *LoginChallenge) count() error {
+	if time.Now().After(c.ExpiresAt) || c.Attempts >= MaxLoginChallengeAttempts {
+		return shared.NewDomainError(shared.ErrCodeInvalidLoginCode, "Login code has expired, sign in again")
+	}
+
+	c.Attempts++
+	return nil
+}
+
+// incorrect is the error for a wrong code, telling how many attempts are left
+func (c *LoginChallenge) incorrect() error {
+	return shared.NewDomainErrorf(shared.ErrCodeInvalidLoginCode, "Incorrect login code, %d attempts left", MaxLoginChallengeAttempts-c.Attempts)
+}
+
+// LoginStepUp tells a client that a login needs the code sent to the user's email, or one
+// from their authenticator app
+type LoginStepUp struct {
+	ChallengeID string      `json:"challenge_id"`
+	Method      LoginMethod `json:"method"`
+	Email       string      `json:"email,omitempty"` // Masked address the code was sent to
+	Risk        LoginRisk   `json:"risk"`
+	ExpiresIn   int64       `json:"expires_in"`
+}
+
+// LoginRepository stores login history and pending step-up challenges
+type LoginRepository interface {
+	// GetHistory retrieves the devices and networks a user signed in from
+	GetHistory(ctx context.Context, userID UserID) (*LoginHistory, error)
+
+	// Remember adds a login's device and network to the user's history
+	Remember(ctx context.Context, userID UserID, fingerprint LoginFingerprint) error
+
+	// SaveChallenge stores a challenge until it expires
+	SaveChallenge(ctx context.Context, challenge *LoginChallenge) error
+
+	// FindChallengeAndUpdate applies callback to a pending challenge atomically. It fails with
+	// a not-found error when the challenge is unknown or expired.
+	FindChallengeAndUpdate(ctx context.Context, id string, callback func(*LoginChallenge) (*LoginChallenge, error)) error
+
+	// DeleteChallenge removes a challenge once it is completed
+	DeleteChallenge(ctx context.Context, id string) error
+
+	// DeleteHistory forgets the devices and networks a user signed in from
+	DeleteHistory(ctx context.Context, userID UserID) error
+}
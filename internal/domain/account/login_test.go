@@ -0,0 +1,45 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginHistory_Assess(t *testing.T) {
+	home := NewLoginFingerprint("device-1", "203.0.113.7")
+	assert.False(t, (&LoginHistory{}).Assess(home).IsSuspicious(), "the first login is trusted")
+
+	history := &LoginHistory{Devices: []string{home.Device}, Networks: []string{home.Network}}
+	assert.False(t, history.Assess(NewLoginFingerprint("device-1", "203.0.200.1")).IsSuspicious(), "same /16 network")
+
+	risk := history.Assess(NewLoginFingerprint("device-2", "198.51.100.1"))
+	assert.True(t, risk.NewDevice)
+	assert.True(t, risk.NewLocation)
+
+	risk = history.Assess(NewLoginFingerprint("", "203.0.113.8"))
+	assert.False(t, risk.IsSuspicious(), "missing device IDs are not counted as new")
+}
+
+func TestLoginChallenge_Attempt(t *testing.T) {
+	acc, err := NewGuestAccount("device-1")
+	require.NoError(t, err)
+
+	challenge, code, err := NewLoginChallenge(acc, NewLoginFingerprint("device-2", ""), LoginRisk{NewDevice: true})
+	require.NoError(t, err)
+	assert.Len(t, code, LoginCodeLength)
+	assert.NotContains(t, challenge.CodeHash, code)
+
+	wrong := "000000"
+	if code == wrong {
+		wrong = "111111"
+	}
+	for i := 0; i < MaxLoginChallengeAttempts; i++ {
+		assert.Error(t, challenge.Attempt(wrong))
+	}
+	assert.Error(t, challenge.Attempt(code), "the challenge is locked after too many wrong codes")
+
+	challenge.Attempts = 0
+	assert.NoError(t, challenge.Attempt(code))
+}
@@ -0,0 +1,76 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// MergeTTL is how long a pending merge can be previewed and confirmed
+const MergeTTL = 15 * time.Minute
+
+// Merge is a pending merge of two users, started when a guest links a social account that
+// already belongs to another user. Linking it as is would leave one of the users' trainers
+// without a way to sign in, so the users are only joined once the player confirms the merge.
+type Merge struct {
+	Token       string       `json:"token"`
+	UserID      UserID       `json:"user_id"`       // The guest linking the social account
+	OtherUserID UserID       `json:"other_user_id"` // The user the social account belongs to
+	Provider    Provider     `json:"provider"`
+	Profile     OAuthProfile `json:"profile"`
+	ExpiresAt   time.Time    `json:"expires_at"`
+}
+
+// NewMerge creates a pending merge with a random token
+func NewMerge(userID, otherUserID UserID, provider Provider, profile OAuthProfile) (*Merge, error) {
+	if userID == "" || otherUserID == "" {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "User ID cannot be empty")
+	}
+
+	if userID == otherUserID {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidOperation, "Accounts already belong to the same user")
+	}
+
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return nil, err
+	}
+
+	return &Merge{
+		Token:       hex.EncodeToString(bytes),
+		UserID:      userID,
+		OtherUserID: otherUserID,
+		Provider:    provider,
+		Profile:     profile,
+		ExpiresAt:   time.Now().Add(MergeTTL),
+	}, nil
+}
+
+// MergeInto moves an account over to the user its own user is merged into. Unlike pairing,
+// social accounts move too: the user they belonged to stops existing.
+func (a *Account) MergeInto(userID UserID) error {
+	if userID == "" {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "User ID cannot be empty")
+	}
+
+	a.UserID = userID
+	a.UpdatedAt = shared.NewTimestamp()
+
+	return nil
+}
+
+// MergeRepository stores pending merges
+type MergeRepository interface {
+	// Save stores a merge until it expires
+	Save(ctx context.Context, merge *Merge) error
+
+	// Get retrieves a merge without consuming it. It returns nil when the token is unknown
+	// or expired.
+	Get(ctx context.Context, token string) (*Merge, error)
+
+	// Take consumes a merge token. It returns nil when the token is unknown or expired.
+	Take(ctx context.Context, token string) (*Merge, error)
+}
@@ -0,0 +1,30 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewMerge(t *testing.T) {
+	profile := NewOAuthProfile("g-1", "player@example.com", "Player")
+
+	merge, err := NewMerge("guest", "player", ProviderGoogle, profile)
+	require.NoError(t, err)
+	assert.Len(t, merge.Token, 64)
+	assert.Equal(t, UserID("guest"), merge.UserID)
+	assert.Equal(t, UserID("player"), merge.OtherUserID)
+
+	_, err = NewMerge("player", "player", ProviderGoogle, profile)
+	assert.Error(t, err, "a user can't merge with itself")
+}
+
+func TestAccount_MergeInto(t *testing.T) {
+	social, err := NewAccount(ProviderGoogle, NewOAuthProfile("g-1", "a@example.com", "A"))
+	require.NoError(t, err)
+	require.NoError(t, social.MergeInto("user-1"), "social accounts move in a merge")
+	assert.Equal(t, UserID("user-1"), social.UserID)
+
+	assert.Error(t, social.MergeInto(""))
+}
@@ -0,0 +1,270 @@
+package account
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+const (
+	// MFAIssuer names the game in authenticator apps
+	MFAIssuer = "Life"
+	// MFASecretSize is the number of random bytes in a TOTP secret (160 bits, as RFC 4226
+	// recommends)
+	MFASecretSize = 20
+	// MFAPeriod is how long each TOTP code is valid
+	MFAPeriod = 30 * time.Second
+	// MFASkew is how many periods before and after the current one are accepted, for clients
+	// whose clocks drift
+	MFASkew = 1
+	// BackupCodeCount is how many single-use backup codes an enrollment issues
+	BackupCodeCount = 10
+	// backupCodeAlphabet leaves out characters that are easily confused
+	backupCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+	backupCodeLength   = 10
+)
+
+// mfaEncoding is the unpadded base32 authenticator apps expect secrets in
+var mfaEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// MFA is a user's time-based one-time password (RFC 6238) second factor. It is stored on one
+// of the user's accounts and asked for on every sign-in once enabled. The secret is
+// encrypted at rest like the account's personal data; backup codes are only kept as hashes.
+type MFA struct {
+	Secret      string            `json:"secret"` // Base32 encoded
+	EnabledAt   *shared.Timestamp `json:"enabled_at,omitempty"`
+	BackupCodes []string          `json:"backup_codes,omitempty"` // Hashes of the unused backup codes
+	LastStep    int64             `json:"last_step,omitempty"`    // Time step of the last accepted code, so codes can't be replayed
+}
+
+// MFAEnrollment is what a player adds to their authenticator app and writes down. It is only
+// ever shown once.
+type MFAEnrollment struct {
+	Secret          string   `json:"secret"`
+	ProvisioningURI string   `json:"provisioning_uri"`
+	BackupCodes     []string `json:"backup_codes"`
+}
+
+// IsEnabled reports whether the second factor was confirmed and is asked for on sign-in
+func (m *MFA) IsEnabled() bool {
+	return m != nil && m.EnabledAt != nil
+}
+
+// MFAEnabled reports whether signing in to the account's user needs a second factor
+func (a *Account) MFAEnabled() bool {
+	return a.MFA.IsEnabled()
+}
+
+// EnrollMFA starts setting up a second factor with a new secret and backup codes. It stays
+// pending until ConfirmMFA; enrolling again before that replaces the pending secret.
+func (a *Account) EnrollMFA(label string) (*MFAEnrollment, error) {
+	if a.MFAEnabled() {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidOperation, "Two-factor authentication is already enabled")
+	}
+
+	secret := make([]byte, MFASecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	mfa := &MFA{Secret: mfaEncoding.EncodeToString(secret)}
+	enrollment := &MFAEnrollment{
+		Secret:          mfa.Secret,
+		ProvisioningURI: mfa.ProvisioningURI(label),
+		BackupCodes:     make([]string, 0, BackupCodeCount),
+	}
+
+	for i := 0; i < BackupCodeCount; i++ {
+		code, err := newBackupCode()
+		if err != nil {
+			return nil, err
+		}
+		enrollment.BackupCodes = append(enrollment.BackupCodes, code)
+		mfa.BackupCodes = append(mfa.BackupCodes, hashLoginPart("backup", code))
+	}
+
+	a.MFA = mfa
+	a.UpdatedAt = shared.NewTimestamp()
+
+	return enrollment, nil
+}
+
+// ConfirmMFA enables a pending second factor once the player proves their authenticator app
+// produces its codes. Backup codes don't confirm an enrollment.
+func (a *Account) ConfirmMFA(code string, now time.Time) error {
+	if a.MFA == nil {
+		return shared.NewDomainError(shared.ErrCodeInvalidOperation, "Enroll in two-factor authentication first")
+	}
+	if a.MFAEnabled() {
+		return shared.NewDomainError(shared.ErrCodeInvalidOperation, "Two-factor authentication is already enabled")
+	}
+
+	step, ok := a.MFA.matchTOTP(code, now)
+	if !ok {
+		return shared.NewDomainError(shared.ErrCodeInvalidLoginCode, "Incorrect authenticator code")
+	}
+
+	timestamp := shared.NewTimestamp()
+	a.MFA.LastStep = step
+	a.MFA.EnabledAt = &timestamp
+	a.UpdatedAt = timestamp
+
+	return nil
+}
+
+// Verify checks a code from the authenticator app or one of the backup codes. Accepted codes
+// can't be used again: a backup code is consumed and TOTP codes of that time step or earlier
+// are refused from then on.
+func (m *MFA) Verify(code string, now time.Time) bool {
+	if !m.IsEnabled() {
+		return false
+	}
+
+	if step, ok := m.matchTOTP(code, now); ok {
+		m.LastStep = step
+		return true
+	}
+
+	entered := hashLoginPart("backup", normalizeBackupCode(code))
+	for i, hash := range m.BackupCodes {
+		if subtle.ConstantTimeCompare([]byte(entered), []byte(hash)) == 1 {
+			m.BackupCodes = slices.Delete(m.BackupCodes, i, i+1)
+			return true
+		}
+	}
+
+	return false
+}
+
+// ProvisioningURI returns the otpauth:// URI authenticator apps scan as a QR code
+func (m *MFA) ProvisioningURI(label string) string {
+	params := url.Values{}
+	params.Set("secret", m.Secret)
+	params.Set("issuer", MFAIssuer)
+	params.Set("algorithm", "SHA1")
+	params.Set("digits", fmt.Sprint(LoginCodeLength))
+	params.Set("period", fmt.Sprint(int(MFAPeriod.Seconds())))
+
+	return "otpauth://totp/" + url.PathEscape(MFAIssuer+":"+label) + "?" + params.Encode()
+}
+
+// matchTOTP finds the time step within the allowed skew whose code was entered, skipping
+// steps at or before the last accepted one
+func (m *MFA) matchTOTP(code string, now time.Time) (int64, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != LoginCodeLength {
+		return 0, false
+	}
+
+	secret, err := mfaEncoding.DecodeString(m.Secret)
+	if err != nil {
+		return 0, false
+	}
+
+	current := now.Unix() / int64(MFAPeriod.Seconds())
+	for step := current - MFASkew; step <= current+MFASkew; step++ {
+		if step <= m.LastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(secret, step)), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+
+	return 0, false
+}
+
+// totpCode computes the code of a time step (RFC 6238 with HMAC-SHA1, RFC 4226 truncation)
+func totpCode(secret []byte, step int64) string {
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	modulus := uint32(1)
+	for i := 0; i < LoginCodeLength; i++ {
+		modulus *= 10
+	}
+	return fmt.Sprintf("%0*d", LoginCodeLength, value%modulus)
+}
+
+// newBackupCode returns a random backup code formatted as two groups of five characters
+func newBackupCode() (string, error) {
+	bytes := make([]byte, backupCodeLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, 0, backupCodeLength+1)
+	for i, b := range bytes {
+		if i == backupCodeLength/2 {
+			code = append(code, '-')
+		}
+		// The alphabet's size divides 256 unevenly; the bias is negligible for codes that are
+		// only tried a handful of times
+		code = append(code, backupCodeAlphabet[int(b)%len(backupCodeAlphabet)])
+	}
+	return string(code), nil
+}
+
+// normalizeBackupCode accepts backup codes typed without the dash or in upper case
+func normalizeBackupCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	if len(code) != backupCodeLength {
+		return code
+	}
+	return code[:backupCodeLength/2] + "-" + code[backupCodeLength/2:]
+}
+
+// FindMFA returns the account among a user's accounts that holds their second factor, or nil
+// when none does. Merged users may have brought one each; an enabled one wins over a pending
+// enrollment.
+func FindMFA(accounts []*Account) *Account {
+	var pending *Account
+	for _, acc := range accounts {
+		if acc.MFAEnabled() {
+			return acc
+		}
+		if acc.MFA != nil && pending == nil {
+			pending = acc
+		}
+	}
+	return pending
+}
+
+// MFAHolder returns the account a user's second factor is stored on: the one already holding
+// it, otherwise the oldest
+func MFAHolder(accounts []*Account) *Account {
+	if acc := FindMFA(accounts); acc != nil {
+		return acc
+	}
+
+	var oldest *Account
+	for _, acc := range accounts {
+		if oldest == nil || oldest.CreatedAt.IsAfter(acc.CreatedAt) {
+			oldest = acc
+		}
+	}
+	return oldest
+}
+
+// AdoptMFA keeps a user's second factor when the account holding it is unlinked
+func (a *Account) AdoptMFA(mfa *MFA) {
+	a.MFA = mfa
+	a.UpdatedAt = shared.NewTimestamp()
+}
@@ -0,0 +1,98 @@
+package account
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTOTPCode(t *testing.T) {
+	// RFC 6238 appendix B vectors for SHA1, truncated to six digits
+	secret := []byte("12345678901234567890")
+	period := int64(MFAPeriod.Seconds())
+	assert.Equal(t, "287082", totpCode(secret, 59/period))
+	assert.Equal(t, "081804", totpCode(secret, 1111111109/period))
+	assert.Equal(t, "050471", totpCode(secret, 1111111111/period))
+	assert.Equal(t, "005924", totpCode(secret, 1234567890/period))
+}
+
+func TestAccount_MFA(t *testing.T) {
+	acc, err := NewAccount(ProviderGoogle, NewOAuthProfile("g-1", "player@example.com", "Player"))
+	require.NoError(t, err)
+	now := time.Unix(1_700_000_000, 0)
+
+	assert.Error(t, acc.ConfirmMFA("123456", now), "nothing to confirm before enrolling")
+
+	enrollment, err := acc.EnrollMFA("player@example.com")
+	require.NoError(t, err)
+	assert.Len(t, enrollment.BackupCodes, BackupCodeCount)
+	assert.True(t, strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/Life:player@example.com?"))
+	assert.Contains(t, enrollment.ProvisioningURI, "secret="+enrollment.Secret)
+	assert.NotContains(t, acc.MFA.BackupCodes, enrollment.BackupCodes[0], "backup codes are stored hashed")
+	assert.False(t, acc.MFAEnabled(), "enrollment is pending until confirmed")
+
+	secret, err := mfaEncoding.DecodeString(enrollment.Secret)
+	require.NoError(t, err)
+	step := now.Unix() / int64(MFAPeriod.Seconds())
+
+	assert.Error(t, acc.ConfirmMFA(enrollment.BackupCodes[0], now), "backup codes don't confirm an enrollment")
+	require.NoError(t, acc.ConfirmMFA(totpCode(secret, step), now))
+	assert.True(t, acc.MFAEnabled())
+
+	_, err = acc.EnrollMFA("player@example.com")
+	assert.Error(t, err, "an enabled second factor isn't replaced")
+
+	assert.False(t, acc.MFA.Verify(totpCode(secret, step), now), "accepted codes can't be replayed")
+	assert.True(t, acc.MFA.Verify(totpCode(secret, step+1), now), "the next period is accepted for clock drift")
+	assert.False(t, acc.MFA.Verify(totpCode(secret, step+3), now))
+
+	backup := strings.ToUpper(strings.ReplaceAll(enrollment.BackupCodes[1], "-", ""))
+	assert.True(t, acc.MFA.Verify(backup, now), "backup codes are accepted without the dash or in upper case")
+	assert.False(t, acc.MFA.Verify(backup, now), "backup codes are single use")
+	assert.Len(t, acc.MFA.BackupCodes, BackupCodeCount-1)
+}
+
+func TestLoginChallenge_AttemptMFA(t *testing.T) {
+	acc, err := NewGuestAccount("device-1")
+	require.NoError(t, err)
+	enrollment, err := acc.EnrollMFA(acc.UserID.String())
+	require.NoError(t, err)
+	secret, err := mfaEncoding.DecodeString(enrollment.Secret)
+	require.NoError(t, err)
+
+	now := time.Now()
+	step := now.Unix() / int64(MFAPeriod.Seconds())
+	require.NoError(t, acc.ConfirmMFA(totpCode(secret, step-1), now))
+
+	challenge, err := NewMFAChallenge(acc, NewLoginFingerprint("device-1", ""), LoginRisk{})
+	require.NoError(t, err)
+	assert.Equal(t, LoginMethodTOTP, challenge.Method)
+	assert.Empty(t, challenge.CodeHash, "nothing is sent for authenticator challenges")
+
+	assert.Error(t, challenge.AttemptMFA(acc.MFA, "000000x", now))
+	assert.NoError(t, challenge.AttemptMFA(acc.MFA, totpCode(secret, step), now))
+	assert.Equal(t, 2, challenge.Attempts, "every attempt counts")
+	assert.Equal(t, step-1, acc.MFA.LastStep, "attempts don't consume the code")
+	assert.NoError(t, challenge.AttemptMFA(acc.MFA, enrollment.BackupCodes[0], now))
+	assert.Len(t, acc.MFA.BackupCodes, BackupCodeCount)
+}
+
+func TestMFASuccessor(t *testing.T) {
+	google, err := NewAccount(ProviderGoogle, NewOAuthProfile("g-1", "player@example.com", "Player"))
+	require.NoError(t, err)
+	guest, err := NewGuestAccountWithUserID("device-1", google.UserID)
+	require.NoError(t, err)
+	assert.Nil(t, MFASuccessor([]*Account{guest}, google), "nothing to move")
+
+	_, err = google.EnrollMFA("player@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, google, MFAHolder([]*Account{guest, google}), "the enrolled account keeps holding it")
+	assert.Equal(t, guest, MFASuccessor([]*Account{guest}, google))
+
+	_, err = guest.EnrollMFA("player@example.com")
+	require.NoError(t, err)
+	assert.Nil(t, MFASuccessor([]*Account{guest}, google), "pending enrollments don't replace each other")
+}
@@ -0,0 +1,106 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"strings"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+const (
+	// PairCodeLength is the number of characters in a pairing code
+	PairCodeLength = 6
+	// PairCodeTTL is how long a pairing code can be redeemed after it is generated
+	PairCodeTTL = 5 * time.Minute
+)
+
+// pairCodeAlphabet leaves out characters that are easy to confuse when typed on a gamepad or TV (0/O, 1/I)
+const pairCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// PairCode is a short-lived code that links another device to a signed-in user
+type PairCode string
+
+// NewPairCode generates a random pairing code
+func NewPairCode() (PairCode, error) {
+	bytes := make([]byte, PairCodeLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, PairCodeLength)
+	for i, b := range bytes {
+		code[i] = pairCodeAlphabet[int(b)%len(pairCodeAlphabet)]
+	}
+	return PairCode(code), nil
+}
+
+// ParsePairCode normalizes a code typed on the device being paired
+func ParsePairCode(s string) (PairCode, error) {
+	code := strings.ToUpper(strings.TrimSpace(s))
+	if len(code) != PairCodeLength || strings.Trim(code, pairCodeAlphabet) != "" {
+		return "", shared.NewDomainError(shared.ErrCodeInvalidPairCode, "Invalid pairing code")
+	}
+	return PairCode(code), nil
+}
+
+// String returns string representation of PairCode
+func (c PairCode) String() string {
+	return string(c)
+}
+
+// Pairing is a pending pairing code issued to a signed-in user
+type Pairing struct {
+	Code      PairCode  `json:"code"`
+	UserID    UserID    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewPairing issues a fresh pairing code for a user
+func NewPairing(userID UserID) (*Pairing, error) {
+	if userID == "" {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "User ID cannot be empty")
+	}
+
+	code, err := NewPairCode()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Pairing{
+		Code:      code,
+		UserID:    userID,
+		ExpiresAt: time.Now().Add(PairCodeTTL),
+	}, nil
+}
+
+// PairTo moves a device account over to the user that issued a pairing code. Social
+// accounts are identified by their provider and can't be moved this way.
+func (a *Account) PairTo(userID UserID) error {
+	if !a.IsGuest() {
+		return shared.NewDomainError(shared.ErrCodeInvalidOperation, "Only device accounts can be paired")
+	}
+
+	if userID == "" {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "User ID cannot be empty")
+	}
+
+	a.UserID = userID
+	a.UpdatedAt = shared.NewTimestamp()
+
+	return nil
+}
+
+// PairingRepository stores pending pairing codes
+type PairingRepository interface {
+	// Save stores a pairing until it expires, replacing any code the user was issued before.
+	// It fails with an already-exists error when the code is taken.
+	Save(ctx context.Context, pairing *Pairing) error
+
+	// Redeem consumes a pairing code. It returns nil when the code is unknown or expired.
+	Redeem(ctx context.Context, code PairCode) (*Pairing, error)
+
+	// DeleteByUserID removes the user's pending pairing code, if any
+	DeleteByUserID(ctx context.Context, userID UserID) error
+}
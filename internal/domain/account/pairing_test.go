@@ -0,0 +1,39 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePairCode(t *testing.T) {
+	pairing, err := NewPairing("user-1")
+	require.NoError(t, err)
+	assert.Len(t, pairing.Code.String(), PairCodeLength)
+
+	code, err := ParsePairCode(" " + string(pairing.Code) + " ")
+	require.NoError(t, err)
+	assert.Equal(t, pairing.Code, code)
+
+	code, err = ParsePairCode("abc234")
+	require.NoError(t, err)
+	assert.Equal(t, PairCode("ABC234"), code, "codes are case insensitive")
+
+	_, err = ParsePairCode("ABC10O")
+	assert.Error(t, err, "confusable characters are never issued")
+	_, err = ParsePairCode("ABC23")
+	assert.Error(t, err)
+}
+
+func TestAccount_PairTo(t *testing.T) {
+	guest, err := NewGuestAccount("device-1")
+	require.NoError(t, err)
+	require.NoError(t, guest.PairTo("user-1"))
+	assert.Equal(t, UserID("user-1"), guest.UserID)
+	assert.Equal(t, "device-1", guest.DeviceID)
+
+	social, err := NewAccount(ProviderGoogle, NewOAuthProfile("g-1", "a@example.com", "A"))
+	require.NoError(t, err)
+	assert.Error(t, social.PairTo("user-1"), "social accounts keep their user")
+}
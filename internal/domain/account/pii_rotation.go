@@ -0,0 +1,76 @@
+package account
+
+import (
+	"context"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/pkg/fieldcrypt"
+)
+
+// scanCount is how many keys each SCAN step asks Redis for
+const scanCount = 500
+
+// PIIRotationStats counts the records rewritten by RotatePII
+type PIIRotationStats struct {
+	Accounts int `json:"accounts"`
+	Emails   int `json:"emails"`
+}
+
+// RotatePII rewrites every account and email record with the cipher's current key and moves
+// plaintext lookup keys to blind indexes. Records are updated one at a time, so it is safe to
+// run while servers are up and to run again after an interruption. Retire an old key only
+// after a run completes and pending email verifications (EmailVerificationTTL) have expired.
+func RotatePII(ctx context.Context, client *redis.Client, cipher *fieldcrypt.Cipher) (PIIRotationStats, error) {
+	var stats PIIRotationStats
+
+	accounts := &RedisRepository{client: client, cipher: cipher}
+	err := scanKeys(ctx, client, "account:*", func(key string) error {
+		id := AccountID(strings.TrimPrefix(key, "account:"))
+		err := accounts.FindOneAndUpdate(ctx, id, func(current *Account) (*Account, error) {
+			return current, nil // Serializing again encrypts with the current key
+		})
+		if err != nil {
+			if deleted, getErr := accounts.GetByID(ctx, id); getErr == nil && deleted == nil {
+				return nil // Deleted since the scan
+			}
+			return err
+		}
+		stats.Accounts++
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	emails := &RedisEmailRepository{client: client, cipher: cipher}
+	err = scanKeys(ctx, client, "email:*", func(key string) error {
+		if strings.HasPrefix(key, "email:verify:") {
+			return nil // Short-lived; decrypted with the old key until they expire
+		}
+
+		userID := UserID(strings.TrimPrefix(key, "email:"))
+		err := emails.FindOneAndUpdate(ctx, userID, func(current *Email) (*Email, error) {
+			return current, nil
+		})
+		if err != nil {
+			return err
+		}
+		stats.Emails++
+		return nil
+	})
+
+	return stats, err
+}
+
+// scanKeys calls fn for each key matching pattern
+func scanKeys(ctx context.Context, client *redis.Client, pattern string, fn func(key string) error) error {
+	iter := client.Scan(ctx, 0, pattern, scanCount).Iterator()
+	for iter.Next(ctx) {
+		if err := fn(iter.Val()); err != nil {
+			return err
+		}
+	}
+	return iter.Err()
+}
@@ -0,0 +1,61 @@
+package account
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// CodeChallengeMethodS256 is the only PKCE method accepted. "plain" would send the verifier
+// itself through the browser, which defeats the purpose.
+const CodeChallengeMethodS256 = "S256"
+
+const (
+	minCodeVerifierLength = 43
+	maxCodeVerifierLength = 128
+)
+
+// ValidateCodeChallenge checks a PKCE code challenge (RFC 7636) sent by a public client when
+// it starts an OAuth flow. An empty method defaults to S256.
+func ValidateCodeChallenge(challenge, method string) error {
+	if method != "" && method != CodeChallengeMethodS256 {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "code_challenge_method must be S256")
+	}
+
+	// A base64url SHA-256 digest without padding is always 43 characters
+	decoded, err := base64.RawURLEncoding.DecodeString(challenge)
+	if err != nil || len(decoded) != sha256.Size {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "Invalid code_challenge")
+	}
+
+	return nil
+}
+
+// ValidateCodeVerifier checks the PKCE code verifier a client sends to complete an OAuth flow
+func ValidateCodeVerifier(verifier string) error {
+	if len(verifier) < minCodeVerifierLength || len(verifier) > maxCodeVerifierLength {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "Invalid code_verifier")
+	}
+
+	for _, c := range verifier {
+		if !isUnreservedChar(c) {
+			return shared.NewDomainError(shared.ErrCodeInvalidInput, "Invalid code_verifier")
+		}
+	}
+
+	return nil
+}
+
+// CodeChallengeS256 derives the S256 code challenge for a verifier
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// isUnreservedChar reports whether c is allowed in a code verifier
+func isUnreservedChar(c rune) bool {
+	return (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+		strings.ContainsRune("-._~", c)
+}
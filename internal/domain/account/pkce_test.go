@@ -0,0 +1,25 @@
+package account
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPKCE(t *testing.T) {
+	// Example from RFC 7636 appendix B
+	verifier := "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	challenge := CodeChallengeS256(verifier)
+	assert.Equal(t, "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM", challenge)
+
+	assert.NoError(t, ValidateCodeVerifier(verifier))
+	assert.Error(t, ValidateCodeVerifier("too-short"))
+	assert.Error(t, ValidateCodeVerifier(strings.Repeat("a", 129)))
+	assert.Error(t, ValidateCodeVerifier(strings.Repeat("a", 42)+"+"))
+
+	assert.NoError(t, ValidateCodeChallenge(challenge, ""))
+	assert.NoError(t, ValidateCodeChallenge(challenge, CodeChallengeMethodS256))
+	assert.Error(t, ValidateCodeChallenge(verifier, "plain"), "plain is rejected")
+	assert.Error(t, ValidateCodeChallenge("not-a-digest", CodeChallengeMethodS256))
+}
@@ -0,0 +1,98 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// PostgresActivityRepository implements ActivityRepository using the account_activities
+// table, the durable account audit log
+type PostgresActivityRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresActivityRepository creates a new Postgres-based activity repository
+func NewPostgresActivityRepository(pool *pgxpool.Pool) ActivityRepository {
+	return &PostgresActivityRepository{
+		pool: pool,
+	}
+}
+
+// Record appends an activity, dropping the oldest beyond ActivityLogSize
+func (r *PostgresActivityRepository) Record(ctx context.Context, userID UserID, activity *Activity) error {
+	data, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+
+	tenantID := tenant.IDFromContext(ctx).String()
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "INSERT INTO account_activities (tenant_id, user_id, at, data) VALUES ($1, $2, $3, $4)",
+			tenantID, userID.String(), activity.At.Value(), data)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, `DELETE FROM account_activities WHERE tenant_id = $1 AND user_id = $2 AND id NOT IN (
+			SELECT id FROM account_activities WHERE tenant_id = $1 AND user_id = $2 ORDER BY id DESC LIMIT $3)`,
+			tenantID, userID.String(), ActivityLogSize)
+		return err
+	})
+}
+
+// ListByUserID returns up to limit activities, newest first
+func (r *PostgresActivityRepository) ListByUserID(ctx context.Context, userID UserID, limit int) ([]*Activity, error) {
+	if limit <= 0 || limit > ActivityLogSize {
+		limit = ActivityLogSize
+	}
+
+	rows, err := r.pool.Query(ctx, "SELECT data FROM account_activities WHERE tenant_id = $1 AND user_id = $2 ORDER BY id DESC LIMIT $3",
+		tenant.IDFromContext(ctx).String(), userID.String(), limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*Activity, error) {
+		var data []byte
+		if err := row.Scan(&data); err != nil {
+			return nil, err
+		}
+
+		activity := &Activity{}
+		if err := json.Unmarshal(data, activity); err != nil {
+			return nil, err
+		}
+		return activity, nil
+	})
+}
+
+// DeleteByUserID removes a user's whole timeline
+func (r *PostgresActivityRepository) DeleteByUserID(ctx context.Context, userID UserID) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM account_activities WHERE tenant_id = $1 AND user_id = $2",
+		tenant.IDFromContext(ctx).String(), userID.String())
+	return err
+}
+
+// PurgeBefore drops the context tenant's activities older than before across all users
+func (r *PostgresActivityRepository) PurgeBefore(ctx context.Context, before time.Time, dryRun bool) (int64, error) {
+	tenantID := tenant.IDFromContext(ctx).String()
+
+	if dryRun {
+		var count int64
+		err := r.pool.QueryRow(ctx, "SELECT count(*) FROM account_activities WHERE tenant_id = $1 AND at < $2",
+			tenantID, before).Scan(&count)
+		return count, err
+	}
+
+	tag, err := r.pool.Exec(ctx, "DELETE FROM account_activities WHERE tenant_id = $1 AND at < $2", tenantID, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,240 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/fieldcrypt"
+	"github.com/danghamo/life/pkg/pgsqlx"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// PostgresRepository implements Repository using the accounts table. Personal data is
+// encrypted with cipher as in RedisRepository, and looked up through blind index columns.
+type PostgresRepository struct {
+	pool   *pgxpool.Pool
+	cipher *fieldcrypt.Cipher
+}
+
+// NewPostgresRepository creates a new Postgres-based account repository
+func NewPostgresRepository(pool *pgxpool.Pool, cipher *fieldcrypt.Cipher) Repository {
+	return &PostgresRepository{
+		pool:   pool,
+		cipher: cipher,
+	}
+}
+
+// FindOneAndInsert implements IoC pattern for insert operations
+func (r *PostgresRepository) FindOneAndInsert(ctx context.Context, id AccountID, callback func() (*Account, error)) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var exists bool
+		err := tx.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM accounts WHERE tenant_id = $1 AND id = $2)",
+			tenant.IDFromContext(ctx).String(), id.String()).Scan(&exists)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return shared.ErrAlreadyExists("account")
+		}
+
+		result, err := callback()
+		if err != nil {
+			return err
+		}
+
+		if result == nil {
+			return fmt.Errorf("callback returned nil account")
+		}
+
+		return r.save(ctx, tx, result)
+	})
+}
+
+// FindOneAndUpdate implements IoC pattern for update operations, locking the row until the
+// callback's result is stored
+func (r *PostgresRepository) FindOneAndUpdate(ctx context.Context, id AccountID, callback func(*Account) (*Account, error)) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		current, err := r.scanOne(tx.QueryRow(ctx, "SELECT data FROM accounts WHERE tenant_id = $1 AND id = $2 FOR UPDATE",
+			tenant.IDFromContext(ctx).String(), id.String()))
+		if err != nil {
+			return err
+		}
+		if current == nil {
+			return shared.ErrNotFound("account")
+		}
+
+		result, err := callback(current)
+		if err != nil {
+			return err
+		}
+
+		if result == nil {
+			return nil // No changes
+		}
+
+		return r.save(ctx, tx, result)
+	})
+}
+
+// GetByID retrieves an account by ID
+func (r *PostgresRepository) GetByID(ctx context.Context, id AccountID) (*Account, error) {
+	return r.scanOne(r.pool.QueryRow(ctx, "SELECT data FROM accounts WHERE tenant_id = $1 AND id = $2",
+		tenant.IDFromContext(ctx).String(), id.String()))
+}
+
+// GetByProvider retrieves an account by provider and provider user ID
+func (r *PostgresRepository) GetByProvider(ctx context.Context, provider Provider, providerUserID string) (*Account, error) {
+	providerKey := string(provider) + ":" + providerUserID
+
+	return r.scanOne(r.pool.QueryRow(ctx, "SELECT data FROM accounts WHERE tenant_id = $1 AND provider_index = $2",
+		tenant.IDFromContext(ctx).String(), r.cipher.Index(providerKey)))
+}
+
+// GetByDeviceID retrieves a guest account by device ID, the latest one a device signed up with
+func (r *PostgresRepository) GetByDeviceID(ctx context.Context, deviceID string) (*Account, error) {
+	return r.scanOne(r.pool.QueryRow(ctx, "SELECT data FROM accounts WHERE tenant_id = $1 AND device_index = $2 ORDER BY created_at DESC LIMIT 1",
+		tenant.IDFromContext(ctx).String(), r.cipher.Index(deviceID)))
+}
+
+// GetByUserID retrieves the primary account by user ID, the first one created
+func (r *PostgresRepository) GetByUserID(ctx context.Context, userID UserID) (*Account, error) {
+	return r.scanOne(r.pool.QueryRow(ctx, "SELECT data FROM accounts WHERE tenant_id = $1 AND user_id = $2 ORDER BY created_at LIMIT 1",
+		tenant.IDFromContext(ctx).String(), userID.String()))
+}
+
+// GetByEmail retrieves any account by email (for cross-provider UserID linking), the first
+// one created with the email
+func (r *PostgresRepository) GetByEmail(ctx context.Context, email string) (*Account, error) {
+	return r.scanOne(r.pool.QueryRow(ctx, "SELECT data FROM accounts WHERE tenant_id = $1 AND email_index = $2 ORDER BY created_at LIMIT 1",
+		tenant.IDFromContext(ctx).String(), r.cipher.Index(email)))
+}
+
+// ListByUserID retrieves all accounts for a specific UserID (N:1 relationship), oldest first
+func (r *PostgresRepository) ListByUserID(ctx context.Context, userID UserID) ([]*Account, error) {
+	rows, err := r.pool.Query(ctx, "SELECT data FROM accounts WHERE tenant_id = $1 AND user_id = $2 ORDER BY created_at",
+		tenant.IDFromContext(ctx).String(), userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*Account, error) {
+		return r.scanOne(row)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if accounts == nil {
+		accounts = []*Account{}
+	}
+
+	return accounts, nil
+}
+
+// Delete removes an account
+func (r *PostgresRepository) Delete(ctx context.Context, id AccountID) error {
+	tag, err := r.pool.Exec(ctx, "DELETE FROM accounts WHERE tenant_id = $1 AND id = $2",
+		tenant.IDFromContext(ctx).String(), id.String())
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return shared.ErrNotFound("account")
+	}
+
+	return nil
+}
+
+// save inserts or overwrites an account with its lookup indexes
+func (r *PostgresRepository) save(ctx context.Context, tx pgx.Tx, a *Account) error {
+	data, err := sealAccount(r.cipher, a)
+	if err != nil {
+		return err
+	}
+
+	// Only social accounts are found by email and only guests by device, as in Redis
+	var emailIndex, deviceIndex *string
+	if a.Profile.Email != "" && !a.IsGuest() {
+		index := r.cipher.Index(a.Profile.Email)
+		emailIndex = &index
+	}
+	if a.IsGuest() && a.DeviceID != "" {
+		index := r.cipher.Index(a.DeviceID)
+		deviceIndex = &index
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO accounts (tenant_id, id, user_id, provider_index, email_index, device_index, data, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (tenant_id, id) DO UPDATE SET
+			user_id = EXCLUDED.user_id,
+			provider_index = EXCLUDED.provider_index,
+			email_index = EXCLUDED.email_index,
+			device_index = EXCLUDED.device_index,
+			data = EXCLUDED.data`,
+		tenant.IDFromContext(ctx).String(), a.ID.String(), a.UserID.String(), r.cipher.Index(a.GetProviderKey()),
+		emailIndex, deviceIndex, data, a.CreatedAt.Value())
+	if pgsqlx.IsUniqueViolation(err) {
+		return shared.ErrAlreadyExists("account")
+	}
+
+	return err
+}
+
+// scanOne decodes the data column of a row, returning nil without a row
+func (r *PostgresRepository) scanOne(row pgx.Row) (*Account, error) {
+	var data []byte
+	if err := row.Scan(&data); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	a := &Account{}
+	if err := openAccount(r.cipher, data, a); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// RotatePostgresPII rewrites every account stored in Postgres with the cipher's current key,
+// one at a time like RotatePII
+func RotatePostgresPII(ctx context.Context, pool *pgxpool.Pool, cipher *fieldcrypt.Cipher) (PIIRotationStats, error) {
+	var stats PIIRotationStats
+
+	rows, err := pool.Query(ctx, "SELECT tenant_id, id FROM accounts ORDER BY tenant_id, id")
+	if err != nil {
+		return stats, err
+	}
+	type accountKey struct{ tenantID, id string }
+	keys, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (accountKey, error) {
+		var key accountKey
+		err := row.Scan(&key.tenantID, &key.id)
+		return key, err
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	accounts := &PostgresRepository{pool: pool, cipher: cipher}
+	for _, key := range keys {
+		tenantCtx := tenant.WithTenant(ctx, &tenant.Tenant{ID: tenant.ID(key.tenantID)})
+		err := accounts.FindOneAndUpdate(tenantCtx, AccountID(key.id), func(current *Account) (*Account, error) {
+			return current, nil // Serializing again encrypts with the current key
+		})
+		if err != nil {
+			if deleted, getErr := accounts.GetByID(tenantCtx, AccountID(key.id)); getErr == nil && deleted == nil {
+				continue // Deleted since the listing
+			}
+			return stats, err
+		}
+		stats.Accounts++
+	}
+
+	return stats, nil
+}
@@ -0,0 +1,131 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// activityLogTTL drops the timeline of users who stop playing
+const activityLogTTL = 180 * 24 * time.Hour
+
+// RedisActivityRepository implements ActivityRepository using a capped Redis list per user
+type RedisActivityRepository struct {
+	client *redis.Client
+}
+
+// NewRedisActivityRepository creates a new Redis-based activity repository
+func NewRedisActivityRepository(client *redis.Client) ActivityRepository {
+	return &RedisActivityRepository{
+		client: client,
+	}
+}
+
+// Record appends an activity, dropping the oldest beyond ActivityLogSize
+func (r *RedisActivityRepository) Record(ctx context.Context, userID UserID, activity *Activity) error {
+	data, err := json.Marshal(activity)
+	if err != nil {
+		return err
+	}
+
+	key := r.activityKey(userID)
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, data)
+		pipe.LTrim(ctx, key, 0, ActivityLogSize-1)
+		pipe.Expire(ctx, key, activityLogTTL)
+		return nil
+	})
+	return err
+}
+
+// ListByUserID returns up to limit activities, newest first
+func (r *RedisActivityRepository) ListByUserID(ctx context.Context, userID UserID, limit int) ([]*Activity, error) {
+	if limit <= 0 || limit > ActivityLogSize {
+		limit = ActivityLogSize
+	}
+
+	entries, err := r.client.LRange(ctx, r.activityKey(userID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	activities := make([]*Activity, 0, len(entries))
+	for _, entry := range entries {
+		activity := &Activity{}
+		if err := json.Unmarshal([]byte(entry), activity); err != nil {
+			return nil, err
+		}
+		activities = append(activities, activity)
+	}
+
+	return activities, nil
+}
+
+// DeleteByUserID removes a user's whole timeline
+func (r *RedisActivityRepository) DeleteByUserID(ctx context.Context, userID UserID) error {
+	return r.client.Del(ctx, r.activityKey(userID)).Err()
+}
+
+// PurgeBefore drops activities older than before across all users
+func (r *RedisActivityRepository) PurgeBefore(ctx context.Context, before time.Time, dryRun bool) (int64, error) {
+	return PurgeActivities(ctx, r.client, before, dryRun)
+}
+
+// activityKey returns the Redis key holding a user's activity timeline
+func (r *RedisActivityRepository) activityKey(userID UserID) string {
+	return fmt.Sprintf("activity:%s", userID.String())
+}
+
+// PurgeActivities drops timeline entries older than before across all users and returns how
+// many were (or, with dryRun, would be) removed. Entries are removed from the old end of each
+// list, so activities recorded meanwhile are never lost.
+func PurgeActivities(ctx context.Context, client *redis.Client, before time.Time, dryRun bool) (int64, error) {
+	var purged int64
+
+	iter := client.Scan(ctx, 0, "activity:*", scanCount).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+
+		// Watched so a concurrent Record trimming the list can't shift what gets popped
+		err := client.Watch(ctx, func(tx *redis.Tx) error {
+			entries, err := tx.LRange(ctx, key, 0, -1).Result()
+			if err != nil {
+				return err
+			}
+
+			// Entries are newest first; count the expired tail
+			expired := 0
+			for i := len(entries) - 1; i >= 0; i-- {
+				activity := &Activity{}
+				if err := json.Unmarshal([]byte(entries[i]), activity); err != nil {
+					return err
+				}
+				if !activity.At.Value().Before(before) {
+					break
+				}
+				expired++
+			}
+
+			if expired > 0 && !dryRun {
+				_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+					pipe.RPopCount(ctx, key, expired)
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			purged += int64(expired)
+			return nil
+		}, key)
+		if err != nil {
+			return purged, err
+		}
+	}
+
+	return purged, iter.Err()
+}
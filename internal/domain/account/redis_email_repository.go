@@ -0,0 +1,121 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/pkg/fieldcrypt"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// RedisEmailRepository implements EmailRepository using Redis. Addresses are encrypted with cipher.
+type RedisEmailRepository struct {
+	client *redis.Client
+	cipher *fieldcrypt.Cipher
+	docs   *redisx.Repository[Email]
+}
+
+// NewRedisEmailRepository creates a new Redis-based email repository
+func NewRedisEmailRepository(client *redis.Client, cipher *fieldcrypt.Cipher) EmailRepository {
+	r := &RedisEmailRepository{
+		client: client,
+		cipher: cipher,
+	}
+	r.docs = redisx.NewRepository(client, "email:", redisx.Options[Email]{
+		Codec: redisx.HashCodec[Email]{
+			Marshal:   r.encodeEmail,
+			Unmarshal: r.decodeEmail,
+		},
+	})
+	return r
+}
+
+// FindOneAndUpdate implements IoC pattern for update operations
+func (r *RedisEmailRepository) FindOneAndUpdate(ctx context.Context, userID UserID, callback func(*Email) (*Email, error)) error {
+	return r.docs.FindOneAndUpsert(ctx, userID.String(), callback)
+}
+
+// GetByUserID retrieves a user's email
+func (r *RedisEmailRepository) GetByUserID(ctx context.Context, userID UserID) (*Email, error) {
+	return r.docs.GetByID(ctx, userID.String())
+}
+
+// SaveVerification stores a verification until it expires
+func (r *RedisEmailRepository) SaveVerification(ctx context.Context, verification *EmailVerification) error {
+	stored := *verification
+
+	var err error
+	if stored.Address, err = r.cipher.Encrypt(verification.Address); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&stored)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(ctx, r.verificationKey(verification.Token), data, time.Until(verification.ExpiresAt)).Err()
+}
+
+// TakeVerification consumes a verification token
+func (r *RedisEmailRepository) TakeVerification(ctx context.Context, token string) (*EmailVerification, error) {
+	data, err := r.client.GetDel(ctx, r.verificationKey(token)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	verification := &EmailVerification{}
+	if err := json.Unmarshal([]byte(data), verification); err != nil {
+		return nil, err
+	}
+
+	if verification.Address, err = r.cipher.Decrypt(verification.Address); err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(verification.ExpiresAt) {
+		return nil, nil
+	}
+
+	return verification, nil
+}
+
+// Delete removes a user's email
+func (r *RedisEmailRepository) Delete(ctx context.Context, userID UserID) error {
+	return r.client.Del(ctx, r.docs.Key(userID.String())).Err()
+}
+
+// encodeEmail serializes an email with its address encrypted
+func (r *RedisEmailRepository) encodeEmail(email *Email) ([]byte, error) {
+	stored := *email
+
+	var err error
+	if stored.Address, err = r.cipher.Encrypt(email.Address); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&stored)
+}
+
+// decodeEmail deserializes an email and decrypts its address
+func (r *RedisEmailRepository) decodeEmail(data []byte, email *Email) error {
+	if err := json.Unmarshal(data, email); err != nil {
+		return err
+	}
+
+	var err error
+	email.Address, err = r.cipher.Decrypt(email.Address)
+	return err
+}
+
+// verificationKey returns the Redis key holding a pending verification
+func (r *RedisEmailRepository) verificationKey(token string) string {
+	return fmt.Sprintf("email:verify:%s", token)
+}
@@ -0,0 +1,100 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// loginHistoryTTL forgets devices and networks of users who stop signing in
+const loginHistoryTTL = 180 * 24 * time.Hour
+
+// RedisLoginRepository implements LoginRepository using Redis sets and expiring keys
+type RedisLoginRepository struct {
+	client     *redis.Client
+	challenges *redisx.Repository[LoginChallenge]
+}
+
+// NewRedisLoginRepository creates a new Redis-based login repository
+func NewRedisLoginRepository(client *redis.Client) LoginRepository {
+	return &RedisLoginRepository{
+		client: client,
+		challenges: redisx.NewRepository(client, "login:challenge:", redisx.Options[LoginChallenge]{
+			// Updates keep the expiry SaveChallenge set
+			Codec:    redisx.StringCodec[LoginChallenge]{},
+			NotFound: func() error { return shared.ErrNotFound("login challenge") },
+		}),
+	}
+}
+
+// GetHistory retrieves the devices and networks a user signed in from
+func (r *RedisLoginRepository) GetHistory(ctx context.Context, userID UserID) (*LoginHistory, error) {
+	devices, err := r.client.SMembers(ctx, r.devicesKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	networks, err := r.client.SMembers(ctx, r.networksKey(userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return &LoginHistory{Devices: devices, Networks: networks}, nil
+}
+
+// Remember adds a login's device and network to the user's history
+func (r *RedisLoginRepository) Remember(ctx context.Context, userID UserID, fingerprint LoginFingerprint) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		if fingerprint.Device != "" {
+			pipe.SAdd(ctx, r.devicesKey(userID), fingerprint.Device)
+			pipe.Expire(ctx, r.devicesKey(userID), loginHistoryTTL)
+		}
+		if fingerprint.Network != "" {
+			pipe.SAdd(ctx, r.networksKey(userID), fingerprint.Network)
+			pipe.Expire(ctx, r.networksKey(userID), loginHistoryTTL)
+		}
+		return nil
+	})
+	return err
+}
+
+// SaveChallenge stores a challenge until it expires
+func (r *RedisLoginRepository) SaveChallenge(ctx context.Context, challenge *LoginChallenge) error {
+	data, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(ctx, r.challenges.Key(challenge.ID), data, time.Until(challenge.ExpiresAt)).Err()
+}
+
+// FindChallengeAndUpdate implements IoC pattern for update operations
+func (r *RedisLoginRepository) FindChallengeAndUpdate(ctx context.Context, id string, callback func(*LoginChallenge) (*LoginChallenge, error)) error {
+	return r.challenges.FindOneAndUpdate(ctx, id, callback)
+}
+
+// DeleteChallenge removes a challenge
+func (r *RedisLoginRepository) DeleteChallenge(ctx context.Context, id string) error {
+	return r.client.Del(ctx, r.challenges.Key(id)).Err()
+}
+
+// DeleteHistory forgets the devices and networks a user signed in from
+func (r *RedisLoginRepository) DeleteHistory(ctx context.Context, userID UserID) error {
+	return r.client.Del(ctx, r.devicesKey(userID), r.networksKey(userID)).Err()
+}
+
+// devicesKey returns the Redis key holding a user's known device hashes
+func (r *RedisLoginRepository) devicesKey(userID UserID) string {
+	return fmt.Sprintf("login:devices:%s", userID.String())
+}
+
+// networksKey returns the Redis key holding a user's known network hashes
+func (r *RedisLoginRepository) networksKey(userID UserID) string {
+	return fmt.Sprintf("login:networks:%s", userID.String())
+}
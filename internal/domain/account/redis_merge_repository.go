@@ -0,0 +1,90 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/pkg/fieldcrypt"
+)
+
+// RedisMergeRepository implements MergeRepository using expiring Redis keys. The social
+// profile's email and provider user ID are encrypted with cipher.
+type RedisMergeRepository struct {
+	client *redis.Client
+	cipher *fieldcrypt.Cipher
+}
+
+// NewRedisMergeRepository creates a new Redis-based merge repository
+func NewRedisMergeRepository(client *redis.Client, cipher *fieldcrypt.Cipher) MergeRepository {
+	return &RedisMergeRepository{
+		client: client,
+		cipher: cipher,
+	}
+}
+
+// Save stores a merge until it expires
+func (r *RedisMergeRepository) Save(ctx context.Context, merge *Merge) error {
+	stored := *merge
+
+	var err error
+	if stored.Profile.Email, err = r.cipher.Encrypt(merge.Profile.Email); err != nil {
+		return err
+	}
+	if stored.Profile.ProviderUserID, err = r.cipher.Encrypt(merge.Profile.ProviderUserID); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&stored)
+	if err != nil {
+		return err
+	}
+
+	return r.client.Set(ctx, r.mergeKey(merge.Token), data, time.Until(merge.ExpiresAt)).Err()
+}
+
+// Get retrieves a merge without consuming it
+func (r *RedisMergeRepository) Get(ctx context.Context, token string) (*Merge, error) {
+	return r.decode(r.client.Get(ctx, r.mergeKey(token)).Result())
+}
+
+// Take consumes a merge token
+func (r *RedisMergeRepository) Take(ctx context.Context, token string) (*Merge, error) {
+	return r.decode(r.client.GetDel(ctx, r.mergeKey(token)).Result())
+}
+
+// decode deserializes a stored merge and decrypts the social profile
+func (r *RedisMergeRepository) decode(data string, err error) (*Merge, error) {
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	merge := &Merge{}
+	if err := json.Unmarshal([]byte(data), merge); err != nil {
+		return nil, err
+	}
+
+	if merge.Profile.Email, err = r.cipher.Decrypt(merge.Profile.Email); err != nil {
+		return nil, err
+	}
+	if merge.Profile.ProviderUserID, err = r.cipher.Decrypt(merge.Profile.ProviderUserID); err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(merge.ExpiresAt) {
+		return nil, nil
+	}
+
+	return merge, nil
+}
+
+// mergeKey returns the Redis key holding a pending merge
+func (r *RedisMergeRepository) mergeKey(token string) string {
+	return fmt.Sprintf("merge:token:%s", token)
+}
@@ -0,0 +1,104 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// RedisPairingRepository implements PairingRepository using expiring Redis keys
+type RedisPairingRepository struct {
+	client *redis.Client
+}
+
+// NewRedisPairingRepository creates a new Redis-based pairing repository
+func NewRedisPairingRepository(client *redis.Client) PairingRepository {
+	return &RedisPairingRepository{
+		client: client,
+	}
+}
+
+// Save stores a pairing until it expires, replacing any code the user was issued before
+func (r *RedisPairingRepository) Save(ctx context.Context, pairing *Pairing) error {
+	data, err := json.Marshal(pairing)
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Until(pairing.ExpiresAt)
+	if ttl <= 0 {
+		return shared.NewDomainError(shared.ErrCodeInvalidPairCode, "Pairing code has already expired")
+	}
+
+	stored, err := r.client.SetNX(ctx, r.codeKey(pairing.Code), data, ttl).Result()
+	if err != nil {
+		return err
+	}
+	if !stored {
+		return shared.ErrAlreadyExists("pairing code")
+	}
+
+	// Only the latest code of a user stays redeemable
+	userKey := r.userKey(pairing.UserID)
+	previous, err := r.client.SetArgs(ctx, userKey, pairing.Code.String(), redis.SetArgs{Get: true, TTL: ttl}).Result()
+	if err != nil && err != redis.Nil {
+		return err
+	}
+	if previous != "" && previous != pairing.Code.String() {
+		return r.client.Del(ctx, r.codeKey(PairCode(previous))).Err()
+	}
+
+	return nil
+}
+
+// Redeem consumes a pairing code
+func (r *RedisPairingRepository) Redeem(ctx context.Context, code PairCode) (*Pairing, error) {
+	data, err := r.client.GetDel(ctx, r.codeKey(code)).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	pairing := &Pairing{}
+	if err := json.Unmarshal([]byte(data), pairing); err != nil {
+		return nil, err
+	}
+
+	if time.Now().After(pairing.ExpiresAt) {
+		return nil, nil
+	}
+
+	return pairing, nil
+}
+
+// DeleteByUserID removes the user's pending pairing code, if any
+func (r *RedisPairingRepository) DeleteByUserID(ctx context.Context, userID UserID) error {
+	userKey := r.userKey(userID)
+
+	code, err := r.client.GetDel(ctx, userKey).Result()
+	if err == redis.Nil {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.client.Del(ctx, r.codeKey(PairCode(code))).Err()
+}
+
+// codeKey returns the Redis key holding a pending pairing
+func (r *RedisPairingRepository) codeKey(code PairCode) string {
+	return fmt.Sprintf("pair:code:%s", code.String())
+}
+
+// userKey returns the Redis key holding a user's latest pairing code
+func (r *RedisPairingRepository) userKey(userID UserID) string {
+	return fmt.Sprintf("idx:pair:user:%s", userID.String())
+}
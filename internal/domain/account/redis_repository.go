@@ -2,156 +2,62 @@ package account
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/redis/go-redis/v9"
 
 	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/fieldcrypt"
+	"github.com/danghamo/life/pkg/redisx"
 )
 
-// RedisRepository implements Repository using Redis
+// RedisRepository implements Repository using Redis. Emails, device IDs and provider user IDs
+// are encrypted with cipher and looked up through blind indexes.
 type RedisRepository struct {
 	client *redis.Client
+	cipher *fieldcrypt.Cipher
+	docs   *redisx.Repository[Account]
 }
 
 // NewRedisRepository creates a new Redis-based account repository
-func NewRedisRepository(client *redis.Client) Repository {
-	return &RedisRepository{
+func NewRedisRepository(client *redis.Client, cipher *fieldcrypt.Cipher) Repository {
+	r := &RedisRepository{
 		client: client,
-	}
+		cipher: cipher,
+	}
+	r.docs = redisx.NewRepository(client, "account:", redisx.Options[Account]{
+		// Personal data is encrypted in the stored document
+		Codec: redisx.HashCodec[Account]{
+			Marshal:   func(a *Account) ([]byte, error) { return sealAccount(cipher, a) },
+			Unmarshal: func(data []byte, a *Account) error { return openAccount(cipher, data, a) },
+		},
+		Indexes:       []redisx.Index[Account]{redisx.IndexFunc[Account](r.updateIndices)},
+		NotFound:      func() error { return shared.ErrNotFound("account") },
+		AlreadyExists: func() error { return shared.ErrAlreadyExists("account") },
+	})
+	return r
 }
 
 // FindOneAndInsert implements IoC pattern for insert operations
 func (r *RedisRepository) FindOneAndInsert(ctx context.Context, id AccountID, callback func() (*Account, error)) error {
-	key := fmt.Sprintf("account:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Check if already exists
-		exists := tx.Exists(ctx, key)
-		if exists.Err() != nil {
-			return exists.Err()
-		}
-
-		if exists.Val() > 0 {
-			return shared.ErrAlreadyExists("account")
-		}
-
-		// Execute callback
-		result, err := callback()
-		if err != nil {
-			return err
-		}
-
-		if result == nil {
-			return fmt.Errorf("callback returned nil account")
-		}
-
-		// Serialize and store
-		fields, err := r.serializeAccount(result)
-		if err != nil {
-			return err
-		}
-
-		// Execute transaction
-		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.HMSet(ctx, key, fields)
-
-			// Update indices
-			r.updateAccountIndices(ctx, pipe, result)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	return r.docs.FindOneAndInsert(ctx, id.String(), callback)
 }
 
 // FindOneAndUpdate implements IoC pattern for update operations
 func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, id AccountID, callback func(*Account) (*Account, error)) error {
-	key := fmt.Sprintf("account:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Get current account
-		data := tx.HGetAll(ctx, key)
-		if data.Err() != nil {
-			return data.Err()
-		}
-
-		if len(data.Val()) == 0 {
-			return shared.ErrNotFound("account")
-		}
-
-		current := &Account{}
-		if err := r.deserializeAccount(data.Val(), current); err != nil {
-			return err
-		}
-
-		// Execute callback
-		result, err := callback(current)
-		if err != nil {
-			return err
-		}
-
-		if result == nil {
-			return nil // No changes
-		}
-
-		// Serialize and store
-		fields, err := r.serializeAccount(result)
-		if err != nil {
-			return err
-		}
-
-		// Execute transaction
-		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.HMSet(ctx, key, fields)
-
-			// Update indices if needed
-			r.updateAccountIndices(ctx, pipe, result)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	return r.docs.FindOneAndUpdate(ctx, id.String(), callback)
 }
 
 // GetByID retrieves an account by ID
 func (r *RedisRepository) GetByID(ctx context.Context, id AccountID) (*Account, error) {
-	key := fmt.Sprintf("account:%s", id.String())
-
-	data, err := r.client.HGetAll(ctx, key).Result()
-	if err != nil {
-		return nil, err
-	}
-
-	if len(data) == 0 {
-		return nil, nil
-	}
-
-	a := &Account{}
-	if err := r.deserializeAccount(data, a); err != nil {
-		return nil, err
-	}
-
-	return a, nil
+	return r.docs.GetByID(ctx, id.String())
 }
 
 // GetByProvider retrieves an account by provider and provider user ID
 func (r *RedisRepository) GetByProvider(ctx context.Context, provider Provider, providerUserID string) (*Account, error) {
 	providerKey := string(provider) + ":" + providerUserID
-	indexKey := fmt.Sprintf("idx:account:provider:%s", providerKey)
 
-	id, err := r.client.Get(ctx, indexKey).Result()
-	if err == redis.Nil {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
-	}
-
-	return r.GetByID(ctx, AccountID(id))
+	return r.getByIndex(ctx, "provider", providerKey)
 }
 
 // GetByUserID retrieves the primary account by user ID (returns first account for N:1 relationship)
@@ -171,61 +77,28 @@ func (r *RedisRepository) GetByUserID(ctx context.Context, userID UserID) (*Acco
 
 // Delete removes an account
 func (r *RedisRepository) Delete(ctx context.Context, id AccountID) error {
-	key := fmt.Sprintf("account:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Get account for index cleanup
-		data := tx.HGetAll(ctx, key)
-		if data.Err() != nil || len(data.Val()) == 0 {
-			return shared.ErrNotFound("account")
-		}
-
-		a := &Account{}
-		if err := r.deserializeAccount(data.Val(), a); err != nil {
-			return err
-		}
-
-		// Execute transaction
-		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.Del(ctx, key)
-
-			// Clean up indices
-			r.cleanupAccountIndices(ctx, pipe, a)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	return r.docs.Delete(ctx, id.String())
 }
 
-// serializeAccount converts account to Redis hash fields
-func (r *RedisRepository) serializeAccount(a *Account) (map[string]interface{}, error) {
-	data, err := json.Marshal(a)
-	if err != nil {
-		return nil, err
+// updateIndices keeps the lookup indices in step with an account written or deleted
+func (r *RedisRepository) updateIndices(ctx context.Context, pipe redis.Pipeliner, id string, before, after *Account) {
+	if after == nil {
+		r.cleanupAccountIndices(ctx, pipe, before)
+		return
 	}
 
-	return map[string]interface{}{
-		"data": string(data),
-	}, nil
-}
-
-// deserializeAccount converts Redis hash fields to account
-func (r *RedisRepository) deserializeAccount(fields map[string]string, a *Account) error {
-	data, exists := fields["data"]
-	if !exists {
-		return fmt.Errorf("account data not found in hash")
+	// A paired account leaves its previous user's account set
+	if before != nil && before.UserID != after.UserID {
+		pipe.SRem(ctx, fmt.Sprintf("idx:account:user:%s", before.UserID.String()), id)
 	}
 
-	return json.Unmarshal([]byte(data), a)
+	r.updateAccountIndices(ctx, pipe, after)
 }
 
 // updateAccountIndices updates secondary indices
 func (r *RedisRepository) updateAccountIndices(ctx context.Context, pipe redis.Pipeliner, a *Account) {
 	// Provider index: provider:provider_user_id -> account_id
-	providerKey := a.GetProviderKey()
-	providerIndexKey := fmt.Sprintf("idx:account:provider:%s", providerKey)
+	providerIndexKey := r.indexKey("provider", a.GetProviderKey())
 	pipe.Set(ctx, providerIndexKey, a.ID.String(), 0)
 
 	// User ID index: user_id -> set of account_ids (N:1 relationship)
@@ -234,23 +107,24 @@ func (r *RedisRepository) updateAccountIndices(ctx context.Context, pipe redis.P
 
 	// Email index: email -> account_id (first account with this email for lookup)
 	if a.Profile.Email != "" && !a.IsGuest() {
-		emailIndexKey := fmt.Sprintf("idx:account:email:%s", a.Profile.Email)
+		emailIndexKey := r.indexKey("email", a.Profile.Email)
 		// Only set if no existing account found (SETNX behavior)
 		pipe.SetNX(ctx, emailIndexKey, a.ID.String(), 0)
 	}
 
 	// Device ID index (게스트 계정인 경우)
 	if a.IsGuest() && a.DeviceID != "" {
-		deviceIndexKey := fmt.Sprintf("idx:account:device:%s", a.DeviceID)
+		deviceIndexKey := r.indexKey("device", a.DeviceID)
 		pipe.Set(ctx, deviceIndexKey, a.ID.String(), 0)
 	}
+
+	r.dropLegacyIndices(ctx, pipe, a)
 }
 
 // cleanupAccountIndices cleans up secondary indices
 func (r *RedisRepository) cleanupAccountIndices(ctx context.Context, pipe redis.Pipeliner, a *Account) {
 	// Provider index
-	providerKey := a.GetProviderKey()
-	providerIndexKey := fmt.Sprintf("idx:account:provider:%s", providerKey)
+	providerIndexKey := r.indexKey("provider", a.GetProviderKey())
 	pipe.Del(ctx, providerIndexKey)
 
 	// User ID index: remove from set (N:1 relationship)
@@ -263,7 +137,7 @@ func (r *RedisRepository) cleanupAccountIndices(ctx context.Context, pipe redis.
 
 	// Email index: only delete if this account owns the email index
 	if a.Profile.Email != "" && !a.IsGuest() {
-		emailIndexKey := fmt.Sprintf("idx:account:email:%s", a.Profile.Email)
+		emailIndexKey := r.indexKey("email", a.Profile.Email)
 		// Check if this account ID matches the stored one
 		pipe.Get(ctx, emailIndexKey)
 		// Note: In a more robust implementation, we'd check the result and conditionally delete
@@ -272,31 +146,40 @@ func (r *RedisRepository) cleanupAccountIndices(ctx context.Context, pipe redis.
 
 	// Device ID index (게스트 계정인 경우)
 	if a.IsGuest() && a.DeviceID != "" {
-		deviceIndexKey := fmt.Sprintf("idx:account:device:%s", a.DeviceID)
+		deviceIndexKey := r.indexKey("device", a.DeviceID)
 		pipe.Del(ctx, deviceIndexKey)
 	}
+
+	r.dropLegacyIndices(ctx, pipe, a)
 }
 
-// GetByDeviceID retrieves a guest account by device ID
-func (r *RedisRepository) GetByDeviceID(ctx context.Context, deviceID string) (*Account, error) {
-	indexKey := fmt.Sprintf("idx:account:device:%s", deviceID)
+// indexKey returns the lookup key for a personal value, using its blind index
+func (r *RedisRepository) indexKey(kind, value string) string {
+	return fmt.Sprintf("idx:account:%s:%s", kind, r.cipher.Index(value))
+}
 
-	id, err := r.client.Get(ctx, indexKey).Result()
-	if err == redis.Nil {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, err
+// dropLegacyIndices removes the plaintext lookup keys written before encryption was enabled
+func (r *RedisRepository) dropLegacyIndices(ctx context.Context, pipe redis.Pipeliner, a *Account) {
+	if !r.cipher.Enabled() {
+		return // The plaintext keys are the current keys
 	}
 
-	return r.GetByID(ctx, AccountID(id))
+	pipe.Del(ctx, fmt.Sprintf("idx:account:provider:%s", a.GetProviderKey()))
+	if a.Profile.Email != "" {
+		pipe.Del(ctx, fmt.Sprintf("idx:account:email:%s", a.Profile.Email))
+	}
+	if a.DeviceID != "" {
+		pipe.Del(ctx, fmt.Sprintf("idx:account:device:%s", a.DeviceID))
+	}
 }
 
-// GetByEmail retrieves any account by email (for cross-provider UserID linking)
-func (r *RedisRepository) GetByEmail(ctx context.Context, email string) (*Account, error) {
-	indexKey := fmt.Sprintf("idx:account:email:%s", email)
-
-	id, err := r.client.Get(ctx, indexKey).Result()
+// getByIndex retrieves an account through a lookup index. Until every record has been
+// rewritten, accounts stored before encryption was enabled are found by their plaintext key.
+func (r *RedisRepository) getByIndex(ctx context.Context, kind, value string) (*Account, error) {
+	id, err := r.client.Get(ctx, r.indexKey(kind, value)).Result()
+	if err == redis.Nil && r.cipher.Enabled() {
+		id, err = r.client.Get(ctx, fmt.Sprintf("idx:account:%s:%s", kind, value)).Result()
+	}
 	if err == redis.Nil {
 		return nil, nil
 	}
@@ -307,6 +190,16 @@ func (r *RedisRepository) GetByEmail(ctx context.Context, email string) (*Accoun
 	return r.GetByID(ctx, AccountID(id))
 }
 
+// GetByDeviceID retrieves a guest account by device ID
+func (r *RedisRepository) GetByDeviceID(ctx context.Context, deviceID string) (*Account, error) {
+	return r.getByIndex(ctx, "device", deviceID)
+}
+
+// GetByEmail retrieves any account by email (for cross-provider UserID linking)
+func (r *RedisRepository) GetByEmail(ctx context.Context, email string) (*Account, error) {
+	return r.getByIndex(ctx, "email", email)
+}
+
 // ListByUserID retrieves all accounts for a specific UserID (N:1 relationship)
 func (r *RedisRepository) ListByUserID(ctx context.Context, userID UserID) ([]*Account, error) {
 	indexKey := fmt.Sprintf("idx:account:user:%s", userID.String())
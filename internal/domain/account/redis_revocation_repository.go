@@ -0,0 +1,53 @@
+package account
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisRevocationRepository implements RevocationRepository with one timestamp key per user
+type RedisRevocationRepository struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisRevocationRepository creates a new Redis-based revocation repository. ttl should be
+// the token lifetime: once it passes, every revoked token has expired anyway.
+func NewRedisRevocationRepository(client *redis.Client, ttl time.Duration) RevocationRepository {
+	return &RedisRevocationRepository{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+// RevokeTokens makes every token issued to the user up to at invalid
+func (r *RedisRevocationRepository) RevokeTokens(ctx context.Context, userID UserID, at time.Time) error {
+	return r.client.Set(ctx, r.revokedKey(userID), at.Unix(), r.ttl).Err()
+}
+
+// RevokedAt returns when the user's tokens were last revoked
+func (r *RedisRevocationRepository) RevokedAt(ctx context.Context, userID UserID) (time.Time, error) {
+	value, err := r.client.Get(ctx, r.revokedKey(userID)).Result()
+	if err == redis.Nil {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	unix, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid revocation time %q: %w", value, err)
+	}
+
+	return time.Unix(unix, 0), nil
+}
+
+// revokedKey returns the Redis key holding when a user's tokens were revoked
+func (r *RedisRevocationRepository) revokedKey(userID UserID) string {
+	return fmt.Sprintf("revoked:user:%s", userID.String())
+}
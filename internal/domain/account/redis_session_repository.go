@@ -0,0 +1,154 @@
+package account
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// RedisSessionRepository implements SessionRepository with a hash of sessions per user.
+// Expired sessions are pruned as the user's sessions are listed.
+type RedisSessionRepository struct {
+	client *redis.Client
+}
+
+// NewRedisSessionRepository creates a new Redis-based session repository
+func NewRedisSessionRepository(client *redis.Client) SessionRepository {
+	return &RedisSessionRepository{
+		client: client,
+	}
+}
+
+// Add registers a session of the user until it expires. The hash lives as long as its newest
+// session, which sessions of the same lifetime always extend.
+func (r *RedisSessionRepository) Add(ctx context.Context, userID UserID, session *Session) error {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	key := r.sessionsKey(userID)
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, session.ID, data)
+		pipe.ExpireAt(ctx, key, session.ExpiresAt)
+		return nil
+	})
+	return err
+}
+
+// List returns the user's unexpired sessions, oldest first
+func (r *RedisSessionRepository) List(ctx context.Context, userID UserID) ([]*Session, error) {
+	key := r.sessionsKey(userID)
+	values, err := r.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	sessions := make([]*Session, 0, len(values))
+	var expired []string
+	for id, value := range values {
+		var session Session
+		if err := json.Unmarshal([]byte(value), &session); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal session %s: %w", id, err)
+		}
+		if !now.Before(session.ExpiresAt) {
+			expired = append(expired, id)
+			continue
+		}
+		sessions = append(sessions, &session)
+	}
+
+	if len(expired) > 0 {
+		if err := r.client.HDel(ctx, key, expired...).Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.Before(sessions[j].CreatedAt)
+	})
+	return sessions, nil
+}
+
+// Extend keeps a session of the user registered until expiresAt
+func (r *RedisSessionRepository) Extend(ctx context.Context, userID UserID, sessionID string, expiresAt time.Time) error {
+	key := r.sessionsKey(userID)
+
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		value, err := tx.HGet(ctx, key, sessionID).Result()
+		if err == redis.Nil {
+			return shared.ErrNotFound("Session")
+		}
+		if err != nil {
+			return err
+		}
+
+		var session Session
+		if err := json.Unmarshal([]byte(value), &session); err != nil {
+			return fmt.Errorf("failed to unmarshal session %s: %w", sessionID, err)
+		}
+		if !expiresAt.After(session.ExpiresAt) {
+			return nil
+		}
+		session.ExpiresAt = expiresAt
+
+		data, err := json.Marshal(&session)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, sessionID, data)
+			pipe.ExpireGT(ctx, key, time.Until(expiresAt))
+			return nil
+		})
+		return err
+	}, key)
+}
+
+// Exists reports whether a session of the user is registered
+func (r *RedisSessionRepository) Exists(ctx context.Context, userID UserID, sessionID string) (bool, error) {
+	return r.client.HExists(ctx, r.sessionsKey(userID), sessionID).Result()
+}
+
+// RevokeOthers removes every session of the user but keep
+func (r *RedisSessionRepository) RevokeOthers(ctx context.Context, userID UserID, keep string) (int, error) {
+	key := r.sessionsKey(userID)
+	ids, err := r.client.HKeys(ctx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	others := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != keep {
+			others = append(others, id)
+		}
+	}
+	if len(others) == 0 {
+		return 0, nil
+	}
+
+	removed, err := r.client.HDel(ctx, key, others...).Result()
+	if err != nil {
+		return 0, err
+	}
+	return int(removed), nil
+}
+
+// DeleteUser removes every session of the user
+func (r *RedisSessionRepository) DeleteUser(ctx context.Context, userID UserID) error {
+	return r.client.Del(ctx, r.sessionsKey(userID)).Err()
+}
+
+// sessionsKey returns the Redis key holding a user's sessions
+func (r *RedisSessionRepository) sessionsKey(userID UserID) string {
+	return fmt.Sprintf("auth:sessions:%s", userID.String())
+}
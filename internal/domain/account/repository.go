@@ -2,6 +2,9 @@ package account
 
 import (
 	"context"
+	"encoding/json"
+
+	"github.com/danghamo/life/pkg/fieldcrypt"
 )
 
 // Repository defines the interface for account persistence operations
@@ -32,4 +35,54 @@ type Repository interface {
 
 	// Delete removes an account
 	Delete(ctx context.Context, id AccountID) error
-}
\ No newline at end of file
+}
+
+// sealAccount serializes an account for storage, encrypting personal data
+func sealAccount(cipher *fieldcrypt.Cipher, a *Account) ([]byte, error) {
+	stored := *a
+
+	var err error
+	if stored.Profile.Email, err = cipher.Encrypt(a.Profile.Email); err != nil {
+		return nil, err
+	}
+	if stored.Profile.ProviderUserID, err = cipher.Encrypt(a.Profile.ProviderUserID); err != nil {
+		return nil, err
+	}
+	if stored.DeviceID, err = cipher.Encrypt(a.DeviceID); err != nil {
+		return nil, err
+	}
+	if a.MFA != nil {
+		mfa := *a.MFA
+		if mfa.Secret, err = cipher.Encrypt(a.MFA.Secret); err != nil {
+			return nil, err
+		}
+		stored.MFA = &mfa
+	}
+
+	return json.Marshal(&stored)
+}
+
+// openAccount deserializes a stored account, decrypting personal data
+func openAccount(cipher *fieldcrypt.Cipher, data []byte, a *Account) error {
+	if err := json.Unmarshal(data, a); err != nil {
+		return err
+	}
+
+	var err error
+	if a.Profile.Email, err = cipher.Decrypt(a.Profile.Email); err != nil {
+		return err
+	}
+	if a.Profile.ProviderUserID, err = cipher.Decrypt(a.Profile.ProviderUserID); err != nil {
+		return err
+	}
+	if a.DeviceID, err = cipher.Decrypt(a.DeviceID); err != nil {
+		return err
+	}
+	if a.MFA != nil {
+		if a.MFA.Secret, err = cipher.Decrypt(a.MFA.Secret); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package account
+
+import (
+	"context"
+	"time"
+)
+
+// RevocationRepository defines the interface for remembering when a user's tokens were revoked
+type RevocationRepository interface {
+	// RevokeTokens makes every token issued to the user up to at invalid
+	RevokeTokens(ctx context.Context, userID UserID, at time.Time) error
+
+	// RevokedAt returns when the user's tokens were last revoked, or the zero time if never
+	RevokedAt(ctx context.Context, userID UserID) (time.Time, error)
+}
+
+// IsRevoked reports whether claims were issued at or before revokedAt. Token issue times
+// have second precision, so a token from the same second as the revocation is rejected too.
+func IsRevoked(claims *JWTClaims, revokedAt time.Time) bool {
+	if revokedAt.IsZero() {
+		return false
+	}
+	if claims.IssuedAt == nil {
+		return true
+	}
+	return !claims.IssuedAt.Time.After(revokedAt.Truncate(time.Second))
+}
@@ -0,0 +1,22 @@
+package account
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsRevoked(t *testing.T) {
+	revokedAt := time.Unix(1_700_000_000, 0)
+	issued := func(at time.Time) *JWTClaims {
+		return &JWTClaims{RegisteredClaims: jwt.RegisteredClaims{IssuedAt: jwt.NewNumericDate(at)}}
+	}
+
+	assert.False(t, IsRevoked(issued(revokedAt), time.Time{}), "never revoked")
+	assert.True(t, IsRevoked(issued(revokedAt.Add(-time.Hour)), revokedAt))
+	assert.True(t, IsRevoked(issued(revokedAt), revokedAt), "same second counts as before")
+	assert.False(t, IsRevoked(issued(revokedAt.Add(time.Second)), revokedAt))
+	assert.True(t, IsRevoked(&JWTClaims{}, revokedAt), "tokens without an issue time can't prove they are newer")
+}
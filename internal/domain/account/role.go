@@ -0,0 +1,25 @@
+package account
+
+// Role is what a user's tokens allow beyond playing. Each role includes those below it.
+type Role string
+
+const (
+	// RolePlayer is the role of every user; tokens issued before roles existed carry none
+	RolePlayer Role = "player"
+	// RoleModerator is the role of staff who watch players, kick and move them
+	RoleModerator Role = "moderator"
+	// RoleAdmin is the role of operators, allowed everything
+	RoleAdmin Role = "admin"
+)
+
+// roleRanks orders the roles; unknown and empty roles rank as RolePlayer
+var roleRanks = map[Role]int{
+	RolePlayer:    0,
+	RoleModerator: 1,
+	RoleAdmin:     2,
+}
+
+// Includes reports whether the role allows what required allows
+func (r Role) Includes(required Role) bool {
+	return roleRanks[r] >= roleRanks[required]
+}
@@ -0,0 +1,77 @@
+package account
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// Session is a sign-in of a user. The tokens issued for it carry its ID and stop being
+// accepted once it is revoked.
+type Session struct {
+	ID        string    `json:"id"`
+	UserAgent string    `json:"user_agent,omitempty"` // Client the user signed in with
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewSession creates a session for a sign-in whose token expires at expiresAt
+func NewSession(userAgent string, expiresAt time.Time) (*Session, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return nil, err
+	}
+
+	return &Session{
+		ID:        hex.EncodeToString(id),
+		UserAgent: userAgent,
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// SessionRepository defines the interface for the registry of users' active sessions
+type SessionRepository interface {
+	// Add registers a session of the user until it expires
+	Add(ctx context.Context, userID UserID, session *Session) error
+
+	// List returns the user's unexpired sessions, oldest first
+	List(ctx context.Context, userID UserID) ([]*Session, error)
+
+	// Extend keeps a session of the user registered until expiresAt, failing with a not found
+	// domain error when it isn't registered
+	Extend(ctx context.Context, userID UserID, sessionID string, expiresAt time.Time) error
+
+	// Exists reports whether a session of the user is registered
+	Exists(ctx context.Context, userID UserID, sessionID string) (bool, error)
+
+	// RevokeOthers removes every session of the user but keep and returns how many were removed
+	RevokeOthers(ctx context.Context, userID UserID, keep string) (int, error)
+
+	// DeleteUser removes every session of the user
+	DeleteUser(ctx context.Context, userID UserID) error
+}
+
+// SessionRegistry keeps track of the sessions tokens are issued for
+type SessionRegistry interface {
+	// Start registers the session of a sign-in before its token is issued
+	Start(ctx context.Context, userID UserID, session *Session) error
+
+	// Extend keeps a session registered as long as a token issued for it later
+	Extend(ctx context.Context, userID UserID, sessionID string, expiresAt time.Time) error
+}
+
+// userAgentContextKey stores the client a request came from, for the sessions it signs in
+type userAgentContextKey struct{}
+
+// WithUserAgent returns a context whose sign-ins record userAgent as their client
+func WithUserAgent(ctx context.Context, userAgent string) context.Context {
+	return context.WithValue(ctx, userAgentContextKey{}, userAgent)
+}
+
+// UserAgentFromContext returns the client set by WithUserAgent, or "" without one
+func UserAgentFromContext(ctx context.Context) string {
+	userAgent, _ := ctx.Value(userAgentContextKey{}).(string)
+	return userAgent
+}
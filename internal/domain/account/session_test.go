@@ -0,0 +1,52 @@
+package account
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingRegistry is a SessionRegistry remembering what it was asked
+type recordingRegistry struct {
+	started  []*Session
+	extended map[string]time.Time
+}
+
+func (r *recordingRegistry) Start(_ context.Context, _ UserID, session *Session) error {
+	r.started = append(r.started, session)
+	return nil
+}
+
+func (r *recordingRegistry) Extend(_ context.Context, _ UserID, sessionID string, expiresAt time.Time) error {
+	r.extended[sessionID] = expiresAt
+	return nil
+}
+
+func TestJWTService_Sessions(t *testing.T) {
+	service := NewJWTService("secret", "test", time.Hour)
+	registry := &recordingRegistry{extended: make(map[string]time.Time)}
+	service.SetSessions(registry)
+
+	ctx := WithUserAgent(context.Background(), "life-client/1.0")
+	token, err := service.GenerateToken(ctx, &Account{UserID: "user"})
+	require.NoError(t, err)
+
+	require.Len(t, registry.started, 1)
+	session := registry.started[0]
+	assert.Equal(t, "life-client/1.0", session.UserAgent)
+	claims, err := service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, claims.SessionID)
+
+	// Selecting a character stays in the session and keeps it registered as long as the token
+	token, err = service.GenerateCharacterToken(ctx, "user", "", "", "user.1", session.ID)
+	require.NoError(t, err)
+	claims, err = service.ValidateToken(token)
+	require.NoError(t, err)
+	assert.Equal(t, session.ID, claims.SessionID)
+	assert.Equal(t, claims.ExpiresAt.Time.Unix(), registry.extended[session.ID].Unix())
+	assert.Len(t, registry.started, 1)
+}
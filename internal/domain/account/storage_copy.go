@@ -0,0 +1,83 @@
+package account
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/fieldcrypt"
+)
+
+// StorageCopyStats counts the records CopyToPostgres copied and skipped
+type StorageCopyStats struct {
+	Accounts         int `json:"accounts"`
+	AccountsSkipped  int `json:"accounts_skipped"` // Already in Postgres
+	Timelines        int `json:"timelines"`
+	TimelinesSkipped int `json:"timelines_skipped"` // Already recorded in Postgres
+}
+
+// CopyToPostgres copies the context tenant's accounts and activity timelines from Redis to
+// Postgres. Records already in Postgres are left alone, so it is safe to run again after an
+// interruption; stop servers first so nothing is written to Redis meanwhile.
+func CopyToPostgres(ctx context.Context, client *redis.Client, pool *pgxpool.Pool, cipher *fieldcrypt.Cipher) (StorageCopyStats, error) {
+	var stats StorageCopyStats
+
+	source := &RedisRepository{client: client, cipher: cipher}
+	target := &PostgresRepository{pool: pool, cipher: cipher}
+	err := scanKeys(ctx, client, "account:*", func(key string) error {
+		a, err := source.GetByID(ctx, AccountID(strings.TrimPrefix(key, "account:")))
+		if err != nil || a == nil {
+			return err
+		}
+
+		err = target.FindOneAndInsert(ctx, a.ID, func() (*Account, error) {
+			return a, nil
+		})
+		if err != nil {
+			if code, ok := shared.DomainErrorCode(err); ok && code == shared.ErrCodeAlreadyExists {
+				stats.AccountsSkipped++
+				return nil
+			}
+			return err
+		}
+		stats.Accounts++
+		return nil
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	activities := &RedisActivityRepository{client: client}
+	timelines := &PostgresActivityRepository{pool: pool}
+	err = scanKeys(ctx, client, "activity:*", func(key string) error {
+		userID := UserID(strings.TrimPrefix(key, "activity:"))
+
+		copied, err := timelines.ListByUserID(ctx, userID, 1)
+		if err != nil {
+			return err
+		}
+		if len(copied) > 0 {
+			stats.TimelinesSkipped++
+			return nil
+		}
+
+		entries, err := activities.ListByUserID(ctx, userID, ActivityLogSize)
+		if err != nil {
+			return err
+		}
+
+		// Oldest first, so Postgres keeps the order
+		for i := len(entries) - 1; i >= 0; i-- {
+			if err := timelines.Record(ctx, userID, entries[i]); err != nil {
+				return err
+			}
+		}
+		stats.Timelines++
+		return nil
+	})
+
+	return stats, err
+}
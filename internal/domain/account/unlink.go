@@ -0,0 +1,49 @@
+package account
+
+import (
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// FindUnlinkable returns the account among a user's accounts that may be unlinked. Only social
+// accounts can be unlinked, and never the user's last one: every user keeps a way to sign in.
+func FindUnlinkable(accounts []*Account, accountID AccountID) (*Account, error) {
+	var target *Account
+	for _, acc := range accounts {
+		if acc.ID == accountID {
+			target = acc
+			break
+		}
+	}
+
+	if target == nil {
+		return nil, shared.ErrNotFound("linked account")
+	}
+
+	if target.IsGuest() {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidOperation, "Guest devices cannot be unlinked")
+	}
+
+	if len(accounts) < 2 {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidOperation, "Cannot unlink the last sign-in method")
+	}
+
+	return target, nil
+}
+
+// MFASuccessor returns the account among a user's remaining accounts that takes over the
+// second factor of an account being unlinked, or nil when it doesn't need to move: the
+// unlinked account holds none, or another account holds one that is at least as far along.
+func MFASuccessor(remaining []*Account, unlinked *Account) *Account {
+	if unlinked.MFA == nil {
+		return nil
+	}
+
+	holder := FindMFA(remaining)
+	if holder == nil {
+		return MFAHolder(remaining)
+	}
+	if holder.MFAEnabled() || !unlinked.MFAEnabled() {
+		return nil
+	}
+	return holder
+}
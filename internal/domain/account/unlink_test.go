@@ -0,0 +1,35 @@
+package account
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindUnlinkable(t *testing.T) {
+	google, err := NewAccount(ProviderGoogle, NewOAuthProfile("g-1", "player@example.com", "Player"))
+	require.NoError(t, err)
+
+	_, err = FindUnlinkable([]*Account{google}, google.ID)
+	assert.Error(t, err, "the last sign-in method stays")
+
+	github, err := NewAccountWithUserID(ProviderGitHub, NewOAuthProfile("gh-1", "player@example.com", "Player"), google.UserID)
+	require.NoError(t, err)
+
+	target, err := FindUnlinkable([]*Account{google, github}, github.ID)
+	require.NoError(t, err)
+	assert.Equal(t, github, target)
+
+	guest, err := NewGuestAccountWithUserID("device-1", google.UserID)
+	require.NoError(t, err)
+	_, err = FindUnlinkable([]*Account{google, guest}, guest.ID)
+	assert.Error(t, err, "guest devices are not social links")
+
+	target, err = FindUnlinkable([]*Account{google, guest}, google.ID)
+	require.NoError(t, err, "a paired device still signs in")
+	assert.Equal(t, google, target)
+
+	_, err = FindUnlinkable([]*Account{google, github}, "unknown")
+	assert.Error(t, err)
+}
@@ -0,0 +1,34 @@
+package admin
+
+import (
+	"encoding/json"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// MaxListedPlayers bounds how many online players one listing returns
+const MaxListedPlayers = 1000
+
+// OnlinePlayer is a connected player as admins see them
+type OnlinePlayer struct {
+	UserID   string          `json:"user_id"`
+	Nickname string          `json:"nickname"`
+	Level    int             `json:"level"`
+	Position shared.Position `json:"position"`
+	IsMoving bool            `json:"is_moving"`
+}
+
+// GrantItem is a stack of items granted by an admin
+type GrantItem struct {
+	ItemType string `json:"item_type"`
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+// ServerStats are the connection and simulation counters of the server answering
+type ServerStats struct {
+	SSEClients       int                        `json:"sse_clients"`
+	WebSocketClients int                        `json:"websocket_clients"`
+	OnlinePlayers    int                        `json:"online_players"` // Across every server
+	Movement         map[string]json.RawMessage `json:"movement"`
+}
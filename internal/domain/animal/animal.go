@@ -70,8 +70,9 @@ func (as AnimalState) String() string {
 
 // EquipmentSlot represents an equipment slot for animals
 type EquipmentSlot struct {
-	EquipmentID shared.ID `json:"equipment_id"`
-	Equipped    bool      `json:"equipped"`
+	EquipmentID shared.ID    `json:"equipment_id"`
+	Equipped    bool         `json:"equipped"`
+	Bonus       shared.Stats `json:"bonus"` // Stats the equipment adds to CurrentStats
 }
 
 // NewEquipmentSlot creates a new equipment slot
@@ -80,9 +81,10 @@ func NewEquipmentSlot() EquipmentSlot {
 }
 
 // Equip equips an equipment
-func (es *EquipmentSlot) Equip(equipmentID shared.ID) {
+func (es *EquipmentSlot) Equip(equipmentID shared.ID, bonus shared.Stats) {
 	es.EquipmentID = equipmentID
 	es.Equipped = true
+	es.Bonus = bonus
 }
 
 // Unequip unequips the equipment
@@ -90,6 +92,7 @@ func (es *EquipmentSlot) Unequip() shared.ID {
 	oldEquipmentID := es.EquipmentID
 	es.EquipmentID = shared.ID("")
 	es.Equipped = false
+	es.Bonus = shared.Stats{}
 	return oldEquipmentID
 }
 
@@ -120,6 +123,9 @@ type Animal struct {
 	LastActionAt shared.Timestamp  `json:"last_action_at"`
 	CreatedAt    shared.Timestamp  `json:"created_at"`
 	UpdatedAt    shared.Timestamp  `json:"updated_at"`
+	Version      int               `json:"version"` // Number of the last stored domain event
+
+	events []shared.Event // Domain events recorded since the animal was loaded
 }
 
 // NewWildAnimal creates a new wild animal
@@ -165,6 +171,7 @@ func NewWildAnimal(animalType AnimalType, level int, position shared.Position) (
 		CreatedAt:    timestamp,
 		UpdatedAt:    timestamp,
 	}
+	animal.record(NewAnimalSpawnedEvent(id.String(), animalType.String(), level, position))
 
 	return animal, nil
 }
@@ -179,6 +186,8 @@ func NewCapturedAnimal(wild *Animal, ownerID shared.ID) (*Animal, error) {
 	captured.State = Captured
 	captured.OwnerID = ownerID
 	captured.UpdatedAt = shared.NewTimestamp()
+	captured.events = append([]shared.Event(nil), wild.events...)
+	captured.record(NewAnimalCapturedEvent(captured.ID.String(), ownerID.String()))
 
 	return &captured, nil
 }
@@ -209,8 +218,10 @@ func (a *Animal) MoveTo(newPosition shared.Position) error {
 		return shared.NewDomainError(shared.ErrCodeInvalidOperation, "Only wild animals can move freely")
 	}
 
+	oldPosition := a.Position
 	a.Position = newPosition
 	a.UpdatedAt = shared.NewTimestamp()
+	a.record(NewAnimalMovedEvent(a.ID.String(), oldPosition, newPosition))
 
 	return nil
 }
@@ -238,6 +249,10 @@ func (a *Animal) TakeDamage(damage int) error {
 
 	a.LastActionAt = shared.NewTimestamp()
 	a.UpdatedAt = shared.NewTimestamp()
+	a.record(NewAnimalTookDamageEvent(a.ID.String(), actualDamage, a.CurrentHP))
+	if a.IsFainted() {
+		a.record(NewAnimalFaintedEvent(a.ID.String()))
+	}
 
 	return nil
 }
@@ -254,6 +269,7 @@ func (a *Animal) Heal(amount int) error {
 	}
 
 	a.UpdatedAt = shared.NewTimestamp()
+	a.record(NewAnimalHealedEvent(a.ID.String(), amount, a.CurrentHP))
 
 	return nil
 }
@@ -306,6 +322,7 @@ func (a *Animal) levelUp(requiredExp int) error {
 	a.CurrentHP = a.MaxHP
 
 	a.UpdatedAt = shared.NewTimestamp()
+	a.record(NewAnimalLeveledUpEvent(a.ID.String(), a.Level.Value()))
 
 	return nil
 }
@@ -330,20 +347,23 @@ func (a *Animal) calculateStatGrowth() shared.Stats {
 	}
 }
 
-// EquipItem equips an item to the necklace slot
-func (a *Animal) EquipItem(itemID shared.ID) error {
+// EquipItem equips an item to the necklace slot, replacing the item equipped before, and adds
+// its stat bonus to the current stats
+func (a *Animal) EquipItem(itemID shared.ID, bonus shared.Stats) error {
 	if !a.IsCaptured() {
 		return shared.NewDomainError(shared.ErrCodeNotCaptured, "Only captured animals can equip items")
 	}
 
+	var oldItemID shared.ID
 	if a.Equipment.IsEquipped() {
-		a.Equipment.Unequip()
+		a.removeBonus(a.Equipment.Bonus)
+		oldItemID = a.Equipment.Unequip()
 	}
 
-	a.Equipment.Equip(itemID)
+	a.Equipment.Equip(itemID, bonus)
+	a.applyBonus(bonus)
 	a.UpdatedAt = shared.NewTimestamp()
-
-	// TODO: Apply equipment stat bonuses when equipment domain is implemented
+	a.record(NewAnimalEquippedItemEvent(a.ID.String(), itemID.String(), oldItemID.String()))
 
 	return nil
 }
@@ -354,14 +374,33 @@ func (a *Animal) UnequipItem() (shared.ID, error) {
 		return shared.ID(""), shared.NewDomainError(shared.ErrCodeNoEquipment, "No item equipped")
 	}
 
+	a.removeBonus(a.Equipment.Bonus)
 	itemID := a.Equipment.Unequip()
 	a.UpdatedAt = shared.NewTimestamp()
-
-	// TODO: Remove equipment stat bonuses when equipment domain is implemented
+	a.record(NewAnimalUnequippedItemEvent(a.ID.String(), itemID.String()))
 
 	return itemID, nil
 }
 
+// applyBonus adds equipment stats. Bonus HP raises the maximum and heals by the same amount,
+// unless the animal has fainted.
+func (a *Animal) applyBonus(bonus shared.Stats) {
+	a.CurrentStats = a.CurrentStats.Add(bonus)
+	a.MaxHP = a.CurrentStats.HP
+	if !a.IsFainted() {
+		a.CurrentHP += bonus.HP
+	}
+}
+
+// removeBonus takes equipment stats away again, capping current HP at the lowered maximum
+func (a *Animal) removeBonus(bonus shared.Stats) {
+	a.CurrentStats = a.CurrentStats.Sub(bonus)
+	a.MaxHP = a.CurrentStats.HP
+	if a.CurrentHP > a.MaxHP {
+		a.CurrentHP = a.MaxHP
+	}
+}
+
 // ChangeState changes the animal state
 func (a *Animal) ChangeState(newState AnimalState) error {
 	if a.State == newState {
@@ -374,8 +413,10 @@ func (a *Animal) ChangeState(newState AnimalState) error {
 			fmt.Sprintf("Cannot transition from %s to %s", a.State, newState))
 	}
 
+	oldState := a.State
 	a.State = newState
 	a.UpdatedAt = shared.NewTimestamp()
+	a.record(NewAnimalStateChangedEvent(a.ID.String(), oldState.String(), newState.String()))
 
 	return nil
 }
@@ -386,21 +427,54 @@ func (a *Animal) isValidStateTransition(newState AnimalState) bool {
 	case Wild:
 		return newState == Captured
 	case Captured:
-		return newState == InParty || newState == InStorage
+		return newState == InParty || newState == InStorage || newState == Wild
 	case InParty:
-		return newState == InStorage
+		return newState == InStorage || newState == Wild
 	case InStorage:
-		return newState == InParty
+		return newState == InParty || newState == Wild
 	default:
 		return false
 	}
 }
 
+// Release returns a captured animal to the wild at the given position
+func (a *Animal) Release(position shared.Position) error {
+	if !a.IsCaptured() {
+		return shared.NewDomainError(shared.ErrCodeNotCaptured, "Only captured animals can be released")
+	}
+	if a.Equipment.IsEquipped() {
+		return shared.NewDomainError(shared.ErrCodeAlreadyEquipped, "Unequip the animal before releasing it")
+	}
+
+	if err := a.ChangeState(Wild); err != nil {
+		return err
+	}
+	a.OwnerID = shared.ID("")
+	a.Position = position
+
+	return nil
+}
+
+// TransferTo hands a captured animal over to another trainer, keeping where it is placed
+func (a *Animal) TransferTo(ownerID shared.ID) error {
+	if !a.IsCaptured() {
+		return shared.NewDomainError(shared.ErrCodeNotCaptured, "Only captured animals can be transferred")
+	}
+
+	a.OwnerID = ownerID
+	a.UpdatedAt = shared.NewTimestamp()
+
+	return nil
+}
+
 // CanBeCaptured checks if animal can be captured
 func (a *Animal) CanBeCaptured() bool {
 	return a.IsWild() && a.IsAlive()
 }
 
+// maxCaptureChance caps the chance of any capture at 95%
+const maxCaptureChance = 0.95
+
 // GetCaptureChance calculates capture chance based on current HP
 func (a *Animal) GetCaptureChance(captureToolEffectiveness float64) float64 {
 	if !a.CanBeCaptured() {
@@ -411,9 +485,8 @@ func (a *Animal) GetCaptureChance(captureToolEffectiveness float64) float64 {
 	hpRatio := 1.0 - (float64(a.CurrentHP) / float64(a.MaxHP))
 	baseChance := hpRatio * captureToolEffectiveness
 
-	// Cap at 95% max chance
-	if baseChance > 0.95 {
-		baseChance = 0.95
+	if baseChance > maxCaptureChance {
+		baseChance = maxCaptureChance
 	}
 
 	return baseChance
@@ -435,3 +508,21 @@ func (a *Animal) GetEquippedItemID() (shared.ID, bool) {
 	}
 	return shared.ID(""), false
 }
+
+// Events returns the domain events recorded since the animal was loaded
+func (a *Animal) Events() []shared.Event {
+	return a.events
+}
+
+// ClearEvents forgets the recorded domain events once they are stored
+func (a *Animal) ClearEvents() {
+	a.events = nil
+}
+
+// record keeps a domain event for storing with the animal. Building an event only fails when
+// its data can't be encoded, which the event data types rule out.
+func (a *Animal) record(event shared.Event, err error) {
+	if err == nil {
+		a.events = append(a.events, event)
+	}
+}
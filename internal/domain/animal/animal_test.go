@@ -0,0 +1,94 @@
+package animal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestAnimal_CaptureAndRelease(t *testing.T) {
+	wild, err := NewWildAnimal(Lion, 3, shared.NewPosition(5, 5))
+	require.NoError(t, err)
+	assert.Zero(t, wild.GetCaptureChance(1.0), "full health animals cannot be caught")
+
+	wild.CurrentHP = wild.MaxHP / 2
+	assert.InDelta(t, 0.75, wild.GetCaptureChance(1.5), 0.05)
+
+	captured, err := NewCapturedAnimal(wild, shared.ID("user-1"))
+	require.NoError(t, err)
+	require.NoError(t, captured.ChangeState(InParty))
+	assert.Zero(t, captured.GetCaptureChance(1.0))
+
+	require.NoError(t, captured.Release(shared.NewPosition(8, 2)))
+	assert.True(t, captured.IsWild())
+	assert.Empty(t, captured.OwnerID)
+	assert.Equal(t, shared.NewPosition(8, 2), captured.Position)
+
+	assert.Error(t, captured.Release(shared.NewPosition(0, 0)), "wild animals cannot be released")
+}
+
+func TestAnimal_EquipmentBonus(t *testing.T) {
+	wild, err := NewWildAnimal(Lion, 3, shared.NewPosition(5, 5))
+	require.NoError(t, err)
+	assert.Error(t, wild.EquipItem("necklace-1", shared.NewStats(10, 0, 3, 0, 0)), "wild animals cannot equip items")
+
+	a, err := NewCapturedAnimal(wild, shared.ID("user-1"))
+	require.NoError(t, err)
+	base := a.CurrentStats
+	a.CurrentHP = a.MaxHP - 5
+
+	require.NoError(t, a.EquipItem("necklace-1", shared.NewStats(10, 0, 3, 0, 0)))
+	assert.Equal(t, base.Add(shared.NewStats(10, 0, 3, 0, 0)), a.CurrentStats)
+	assert.Equal(t, base.HP+10, a.MaxHP)
+	assert.Equal(t, a.MaxHP-5, a.CurrentHP, "bonus HP heals by the same amount")
+
+	// Replacing the necklace swaps the bonus instead of stacking it
+	require.NoError(t, a.EquipItem("necklace-2", shared.NewStats(15, 5, 2, 2, 2)))
+	assert.Equal(t, base.Add(shared.NewStats(15, 5, 2, 2, 2)), a.CurrentStats)
+
+	id, err := a.UnequipItem()
+	require.NoError(t, err)
+	assert.Equal(t, shared.ID("necklace-2"), id)
+	assert.Equal(t, base, a.CurrentStats)
+	assert.Equal(t, base.HP, a.MaxHP)
+	assert.LessOrEqual(t, a.CurrentHP, a.MaxHP)
+
+	_, err = a.UnequipItem()
+	assert.Error(t, err)
+}
+
+func TestAnimal_Events(t *testing.T) {
+	wild, err := NewWildAnimal(Lion, 3, shared.NewPosition(5, 5))
+	require.NoError(t, err)
+	require.NoError(t, wild.MoveTo(shared.NewPosition(6, 5)))
+
+	a, err := NewCapturedAnimal(wild, shared.ID("user-1"))
+	require.NoError(t, err)
+	require.NoError(t, a.ChangeState(InParty))
+	require.NoError(t, a.TakeDamage(a.MaxHP*10))
+
+	types := func(events []shared.Event) []string {
+		names := make([]string, len(events))
+		for i, event := range events {
+			names[i] = event.EventType()
+			assert.Equal(t, a.ID.String(), event.AggregateID())
+		}
+		return names
+	}
+	assert.Equal(t, []string{AnimalSpawnedEventType, AnimalMovedEventType}, types(wild.Events()))
+	assert.Equal(t, []string{
+		AnimalSpawnedEventType,
+		AnimalMovedEventType,
+		AnimalCapturedEventType,
+		AnimalStateChangedEventType,
+		AnimalTookDamageEventType,
+		AnimalFaintedEventType,
+	}, types(a.Events()))
+
+	a.ClearEvents()
+	assert.Empty(t, a.Events())
+	assert.Len(t, wild.Events(), 2, "the captured copy records its own events")
+}
@@ -0,0 +1,90 @@
+package animal
+
+import (
+	"math/rand"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// aggroRadii is how close a trainer has to come for an aggressive type to chase them, in tiles.
+// Types without one never chase.
+var aggroRadii = map[AnimalType]float64{
+	Lion:    5,
+	Cheetah: 4,
+}
+
+// wanderChance is the chance a wild animal steps to a neighboring tile when it has nothing to
+// chase; otherwise it rests in place
+const wanderChance = 0.4
+
+// reach is how close a trainer has to be for an animal to get at it, in tiles: any of the
+// eight tiles around the animal
+const reach = 1.5
+
+// neighborSteps are the moves to the eight tiles around a tile
+var neighborSteps = []struct{ dx, dy float64 }{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// AggroRadius returns how close a trainer has to come for the type to chase them, or 0 for
+// types that leave trainers alone
+func (at AnimalType) AggroRadius() float64 {
+	return aggroRadii[at]
+}
+
+// IsAggressive checks if the type chases trainers coming near
+func (at AnimalType) IsAggressive() bool {
+	return at.AggroRadius() > 0
+}
+
+// Wander picks a random neighboring tile the animal may enter for a step of a random walk. It
+// returns false when the animal rests this time or has nowhere to go.
+func (a *Animal) Wander(canEnter func(shared.Position) bool, rng *rand.Rand) (shared.Position, bool) {
+	if rng.Float64() >= wanderChance {
+		return shared.Position{}, false
+	}
+
+	options := make([]shared.Position, 0, len(neighborSteps))
+	for _, next := range a.neighbors() {
+		if canEnter(next) {
+			options = append(options, next)
+		}
+	}
+	if len(options) == 0 {
+		return shared.Position{}, false
+	}
+	return options[rng.Intn(len(options))], true
+}
+
+// InReach checks if a position is close enough for the animal to get at it
+func (a *Animal) InReach(target shared.Position) bool {
+	return a.Position.WithinRange(target, reach)
+}
+
+// ChaseStep picks the neighboring tile the animal may enter that brings it closest to target.
+// It returns false when the animal is already next to the target or no step gets it closer.
+func (a *Animal) ChaseStep(target shared.Position, canEnter func(shared.Position) bool) (shared.Position, bool) {
+	if a.InReach(target) {
+		return shared.Position{}, false
+	}
+
+	best, bestDistance := shared.Position{}, a.Position.DistanceTo(target)
+
+	found := false
+	for _, next := range a.neighbors() {
+		if distance := next.DistanceTo(target); distance < bestDistance && canEnter(next) {
+			best, bestDistance, found = next, distance, true
+		}
+	}
+	return best, found
+}
+
+// neighbors returns the positions of the eight tiles around the animal
+func (a *Animal) neighbors() []shared.Position {
+	positions := make([]shared.Position, len(neighborSteps))
+	for i, step := range neighborSteps {
+		positions[i] = shared.NewPosition(a.Position.X+step.dx, a.Position.Y+step.dy)
+	}
+	return positions
+}
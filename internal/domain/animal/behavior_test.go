@@ -0,0 +1,63 @@
+package animal
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestAnimal_Wander(t *testing.T) {
+	wild, err := NewWildAnimal(Elephant, 1, shared.NewPosition(5, 5))
+	require.NoError(t, err)
+	rng := rand.New(rand.NewSource(1))
+
+	// Only the tile to the east may be entered
+	east := shared.NewPosition(6, 5)
+	canEnter := func(p shared.Position) bool { return p == east }
+
+	steps, rests := 0, 0
+	for i := 0; i < 100; i++ {
+		next, ok := wild.Wander(canEnter, rng)
+		if !ok {
+			rests++
+			continue
+		}
+		steps++
+		assert.Equal(t, east, next)
+	}
+	assert.NotZero(t, steps)
+	assert.NotZero(t, rests, "animals rest between steps")
+
+	for i := 0; i < 20; i++ {
+		_, ok := wild.Wander(func(shared.Position) bool { return false }, rng)
+		assert.False(t, ok, "boxed in animals stay put")
+	}
+}
+
+func TestAnimal_ChaseStep(t *testing.T) {
+	wild, err := NewWildAnimal(Lion, 1, shared.NewPosition(5, 5))
+	require.NoError(t, err)
+	open := func(shared.Position) bool { return true }
+
+	assert.True(t, Lion.IsAggressive())
+	assert.False(t, Elephant.IsAggressive())
+
+	next, ok := wild.ChaseStep(shared.NewPosition(8.5, 8.5), open)
+	require.True(t, ok)
+	assert.Equal(t, shared.NewPosition(6, 6), next)
+
+	// Blocked diagonally, the lion still closes in along the row
+	next, ok = wild.ChaseStep(shared.NewPosition(8.5, 8.5), func(p shared.Position) bool { return p.Y == 5 })
+	require.True(t, ok)
+	assert.Equal(t, shared.NewPosition(6, 5), next)
+
+	_, ok = wild.ChaseStep(shared.NewPosition(5.5, 5.5), open)
+	assert.False(t, ok, "animals stop next to their target")
+	_, ok = wild.ChaseStep(shared.NewPosition(6, 6), open)
+	assert.False(t, ok, "diagonal neighbors are in reach")
+	assert.False(t, wild.InReach(shared.NewPosition(7, 5)))
+}
@@ -0,0 +1,83 @@
+package animal
+
+import (
+	"github.com/danghamo/life/internal/domain/world"
+)
+
+// phaseWeights scales each type's spawn weight by the phase of the day, in percent: lions
+// hunt at night, cheetahs by day and elephants come out to drink at dawn and dusk
+var phaseWeights = map[world.DayPhase]map[AnimalType]int{
+	world.Dawn:  {Lion: 100, Elephant: 200, Cheetah: 100},
+	world.Day:   {Lion: 50, Elephant: 100, Cheetah: 200},
+	world.Dusk:  {Lion: 100, Elephant: 200, Cheetah: 100},
+	world.Night: {Lion: 250, Elephant: 50, Cheetah: 25},
+}
+
+// weatherSpawnRates scales the spawn rate by the weather; animals shelter from rain and storms
+var weatherSpawnRates = map[world.Weather]float64{
+	world.Rain:  0.75,
+	world.Storm: 0.5,
+}
+
+// phaseCaptureModifiers and weatherCaptureModifiers scale capture chances: animals are drowsy
+// at dawn and dusk, hard to find at night, and easier to sneak up on in rain and fog
+var (
+	phaseCaptureModifiers = map[world.DayPhase]float64{
+		world.Dawn:  1.1,
+		world.Dusk:  1.1,
+		world.Night: 0.9,
+	}
+	weatherCaptureModifiers = map[world.Weather]float64{
+		world.Rain:  1.1,
+		world.Storm: 0.8,
+		world.Fog:   1.15,
+	}
+)
+
+// During returns the config adjusted for the time of day and weather. A nil clock keeps the
+// config as it is.
+func (c SpawnConfig) During(clock *world.Clock) SpawnConfig {
+	if clock == nil {
+		return c
+	}
+
+	if percents, ok := phaseWeights[clock.Phase]; ok {
+		weights := make(map[AnimalType]int, len(spawnableTypes))
+		for _, animalType := range spawnableTypes {
+			weight := 1
+			if c.Weights != nil {
+				weight = c.Weights[animalType]
+			}
+			// Weights are relative, so scaling every one by its percent keeps the arithmetic whole
+			weights[animalType] = weight * percents[animalType]
+		}
+		c.Weights = weights
+	}
+
+	if rate, ok := weatherSpawnRates[clock.Weather]; ok {
+		c.Rate *= rate
+	}
+	return c
+}
+
+// CaptureModifier returns how much the time of day and weather scale capture chances. A nil
+// clock doesn't change them.
+func CaptureModifier(clock *world.Clock) float64 {
+	if clock == nil {
+		return 1
+	}
+
+	modifier := 1.0
+	if phase, ok := phaseCaptureModifiers[clock.Phase]; ok {
+		modifier *= phase
+	}
+	if weather, ok := weatherCaptureModifiers[clock.Weather]; ok {
+		modifier *= weather
+	}
+	return modifier
+}
+
+// CaptureChanceDuring calculates the capture chance under the time of day and weather
+func (a *Animal) CaptureChanceDuring(captureToolEffectiveness float64, clock *world.Clock) float64 {
+	return min(a.GetCaptureChance(captureToolEffectiveness)*CaptureModifier(clock), maxCaptureChance)
+}
@@ -0,0 +1,43 @@
+package animal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/world"
+)
+
+func TestSpawnConfig_During(t *testing.T) {
+	config := DefaultSpawnConfig(0.2)
+	assert.Equal(t, config, config.During(nil))
+
+	night := config.During(&world.Clock{Phase: world.Night, Weather: world.Storm})
+	assert.Greater(t, night.Weights[Lion], night.Weights[Cheetah])
+	assert.Greater(t, night.Weights[Cheetah], 0, "even rare types still spawn")
+	assert.InDelta(t, 0.1, night.Rate, 1e-9)
+
+	day := config.During(&world.Clock{Phase: world.Day, Weather: world.Clear})
+	assert.Greater(t, day.Weights[Cheetah], day.Weights[Lion])
+	assert.Equal(t, 0.2, day.Rate)
+
+	// Types a live event weighs out stay out whatever the time
+	config.Weights = map[AnimalType]int{Lion: 0, Elephant: 1, Cheetah: 1}
+	assert.Zero(t, config.During(&world.Clock{Phase: world.Night}).Weights[Lion])
+}
+
+func TestAnimal_CaptureChanceDuring(t *testing.T) {
+	wild, err := NewWildAnimal(Lion, 1, shared.NewPosition(0, 0))
+	require.NoError(t, err)
+	wild.CurrentHP = wild.MaxHP / 2
+
+	base := wild.GetCaptureChance(1)
+	assert.Equal(t, base, wild.CaptureChanceDuring(1, nil))
+	assert.Greater(t, wild.CaptureChanceDuring(1, &world.Clock{Phase: world.Dusk, Weather: world.Fog}), base)
+	assert.Less(t, wild.CaptureChanceDuring(1, &world.Clock{Phase: world.Night, Weather: world.Storm}), base)
+
+	wild.CurrentHP = 1
+	assert.LessOrEqual(t, wild.CaptureChanceDuring(2, &world.Clock{Phase: world.Dawn, Weather: world.Fog}), maxCaptureChance)
+}
@@ -4,187 +4,93 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 
 	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/redisx"
 )
 
-// RedisRepository implements Repository using Redis Hash
+// RedisRepository implements Repository using Redis Hash. The domain events an animal
+// recorded are appended to its event stream in the transaction storing the animal.
 type RedisRepository struct {
 	client *redis.Client
+	docs   *redisx.Repository[Animal]
+	events *shared.RedisEventStore
 }
 
 // NewRedisRepository creates a new Redis-based animal repository
 func NewRedisRepository(client *redis.Client) Repository {
 	return &RedisRepository{
 		client: client,
+		docs: redisx.NewRepository(client, "animal:", redisx.Options[Animal]{
+			Codec:         redisx.HashCodec[Animal]{},
+			Indexes:       animalIndexes(),
+			NotFound:      func() error { return shared.ErrNotFound("animal") },
+			AlreadyExists: func() error { return shared.ErrAlreadyExists("animal") },
+		}),
+		events: shared.NewRedisEventStore(client),
 	}
 }
 
 // FindOneAndUpsert implements IoC pattern with callback for concurrency control
 func (r *RedisRepository) FindOneAndUpsert(ctx context.Context, id AnimalID, callback func(*Animal) (*Animal, error)) error {
-	key := fmt.Sprintf("animal:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Get current animal
-		var current *Animal
-		data := tx.HGetAll(ctx, key)
-		if data.Err() != nil && data.Err() != redis.Nil {
-			return data.Err()
-		}
-
-		if len(data.Val()) > 0 {
-			current = &Animal{}
-			if err := r.deserializeAnimal(data.Val(), current); err != nil {
-				return err
-			}
-		}
-
-		// Execute callback
-		result, err := callback(current)
-		if err != nil {
-			return err
-		}
-
-		if result == nil {
-			return nil // No changes
-		}
-
-		// Serialize and store
-		fields, err := r.serializeAnimal(result)
-		if err != nil {
-			return err
-		}
-
-		// Execute transaction
-		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.HMSet(ctx, key, fields)
-
-			// Update indices
-			r.updateAnimalIndices(ctx, pipe, result)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	ctx = stagedContext(ctx)
+	return r.docs.FindOneAndUpsert(ctx, id.String(), func(a *Animal) (*Animal, error) {
+		result, err := callback(a)
+		return r.storeEvents(ctx, id, result, err)
+	})
 }
 
 // FindOneAndInsert implements IoC pattern for insert operations
 func (r *RedisRepository) FindOneAndInsert(ctx context.Context, id AnimalID, callback func() (*Animal, error)) error {
-	key := fmt.Sprintf("animal:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Check if already exists
-		exists := tx.Exists(ctx, key)
-		if exists.Err() != nil {
-			return exists.Err()
-		}
-
-		if exists.Val() > 0 {
-			return shared.ErrAlreadyExists("animal")
-		}
-
-		// Execute callback
+	ctx = stagedContext(ctx)
+	return r.docs.FindOneAndInsert(ctx, id.String(), func() (*Animal, error) {
 		result, err := callback()
-		if err != nil {
-			return err
-		}
-
-		if result == nil {
-			return fmt.Errorf("callback returned nil animal")
-		}
-
-		// Serialize and store
-		fields, err := r.serializeAnimal(result)
-		if err != nil {
-			return err
-		}
-
-		// Execute transaction
-		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.HMSet(ctx, key, fields)
-
-			// Update indices
-			r.updateAnimalIndices(ctx, pipe, result)
-
-			return nil
-		})
-
-		return err
-	}, key)
+		return r.storeEvents(ctx, id, result, err)
+	})
 }
 
 // FindOneAndUpdate implements IoC pattern for update operations
 func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, id AnimalID, callback func(*Animal) (*Animal, error)) error {
-	key := fmt.Sprintf("animal:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Get current animal
-		data := tx.HGetAll(ctx, key)
-		if data.Err() != nil {
-			return data.Err()
-		}
-
-		if len(data.Val()) == 0 {
-			return shared.ErrNotFound("animal")
-		}
-
-		current := &Animal{}
-		if err := r.deserializeAnimal(data.Val(), current); err != nil {
-			return err
-		}
-
-		// Execute callback
-		result, err := callback(current)
-		if err != nil {
-			return err
-		}
-
-		if result == nil {
-			return nil // No changes
-		}
-
-		// Serialize and store
-		fields, err := r.serializeAnimal(result)
-		if err != nil {
-			return err
-		}
-
-		// Execute transaction
-		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.HMSet(ctx, key, fields)
-
-			// Update indices if needed
-			r.updateAnimalIndices(ctx, pipe, result)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	ctx = stagedContext(ctx)
+	return r.docs.FindOneAndUpdate(ctx, id.String(), func(a *Animal) (*Animal, error) {
+		result, err := callback(a)
+		return r.storeEvents(ctx, id, result, err)
+	})
 }
 
-// GetByID retrieves an animal by ID
-func (r *RedisRepository) GetByID(ctx context.Context, id AnimalID) (*Animal, error) {
-	key := fmt.Sprintf("animal:%s", id.String())
-
-	data, err := r.client.HGetAll(ctx, key).Result()
-	if err != nil {
-		return nil, err
+// storeEvents stages the events the callback's result recorded into the write storing it,
+// numbered after the version it was read at. The document's watch keeps concurrent writes of
+// the animal from numbering events the same.
+func (r *RedisRepository) storeEvents(ctx context.Context, id AnimalID, result *Animal, err error) (*Animal, error) {
+	if err != nil || result == nil || len(result.Events()) == 0 {
+		return result, err
 	}
 
-	if len(data) == 0 {
-		return nil, nil
-	}
+	events, version := result.Events(), result.Version
+	redisx.StageFrom(ctx).Add(func(ctx context.Context, pipe redis.Pipeliner) {
+		// Animal events are built from encoded data, so queueing them can't fail
+		_ = r.events.QueueEvents(ctx, pipe, id.String(), events, version)
+	})
+	result.Version += len(events)
+	result.ClearEvents()
+	return result, nil
+}
 
-	a := &Animal{}
-	if err := r.deserializeAnimal(data, a); err != nil {
-		return nil, err
+// stagedContext returns ctx with a stage for the events of the next write, keeping a stage
+// the caller set up so its own staged writes commit too
+func stagedContext(ctx context.Context) context.Context {
+	if redisx.StageFrom(ctx) != nil {
+		return ctx
 	}
+	return redisx.WithStage(ctx)
+}
 
-	return a, nil
+// GetByID retrieves an animal by ID
+func (r *RedisRepository) GetByID(ctx context.Context, id AnimalID) (*Animal, error) {
+	return r.docs.GetByID(ctx, id.String())
 }
 
 // GetByPosition retrieves wild animals at a specific position
@@ -298,100 +204,94 @@ func (r *RedisRepository) GetByType(ctx context.Context, animalType AnimalType)
 	return animals, nil
 }
 
-// Delete removes an animal
+// Delete removes an animal together with its events, so defeated wild animals don't leave
+// their history behind
 func (r *RedisRepository) Delete(ctx context.Context, id AnimalID) error {
-	key := fmt.Sprintf("animal:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Get animal for index cleanup
-		data := tx.HGetAll(ctx, key)
-		if data.Err() != nil || len(data.Val()) == 0 {
-			return shared.ErrNotFound("animal")
-		}
-
-		a := &Animal{}
-		if err := r.deserializeAnimal(data.Val(), a); err != nil {
-			return err
-		}
-
-		// Execute transaction
-		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.Del(ctx, key)
-
-			// Clean up indices
-			r.cleanupAnimalIndices(ctx, pipe, a)
-
-			return nil
-		})
-
+	if err := r.docs.Delete(ctx, id.String()); err != nil {
 		return err
-	}, key)
+	}
+	return r.events.DeleteEvents(ctx, id.String())
 }
 
-// serializeAnimal converts animal to Redis hash fields
-func (r *RedisRepository) serializeAnimal(a *Animal) (map[string]interface{}, error) {
-	data, err := json.Marshal(a)
-	if err != nil {
-		return nil, err
+// animalIndexes are the secondary indices kept with each animal
+func animalIndexes() []redisx.Index[Animal] {
+	return []redisx.Index[Animal]{
+		// Position index (only for wild animals)
+		redisx.SetIndex[Animal]{Key: func(a *Animal) string {
+			if !a.IsWild() {
+				return ""
+			}
+			return fmt.Sprintf("idx:animal:wild_position:%.1f:%.1f", a.Position.X, a.Position.Y)
+		}},
+		// Owner index (only for captured animals)
+		redisx.SetIndex[Animal]{Key: func(a *Animal) string {
+			if !a.IsCaptured() {
+				return ""
+			}
+			return fmt.Sprintf("idx:animal:owner:%s", a.OwnerID.String())
+		}},
+		// State index
+		redisx.SetIndex[Animal]{Key: func(a *Animal) string {
+			return fmt.Sprintf("idx:animal:state:%s", a.State.String())
+		}},
+		// Type index
+		redisx.SetIndex[Animal]{Key: func(a *Animal) string {
+			return fmt.Sprintf("idx:animal:type:%s", a.AnimalType.String())
+		}},
 	}
-
-	return map[string]interface{}{
-		"data": string(data),
-	}, nil
 }
 
-// deserializeAnimal converts Redis hash fields to animal
-func (r *RedisRepository) deserializeAnimal(fields map[string]string, a *Animal) error {
-	data, exists := fields["data"]
-	if !exists {
-		return fmt.Errorf("animal data not found in hash")
-	}
+// spawnTableKey holds the spawn table override as JSON
+const spawnTableKey = "animal:spawn_table"
 
-	return json.Unmarshal([]byte(data), a)
+// RedisSpawnTableRepository implements SpawnTableRepository with a JSON value expiring
+// together with the override
+type RedisSpawnTableRepository struct {
+	client *redis.Client
 }
 
-// updateAnimalIndices updates secondary indices
-func (r *RedisRepository) updateAnimalIndices(ctx context.Context, pipe redis.Pipeliner, a *Animal) {
-	// Position index (only for wild animals)
-	if a.IsWild() {
-		positionKey := fmt.Sprintf("idx:animal:wild_position:%.1f:%.1f", a.Position.X, a.Position.Y)
-		pipe.SAdd(ctx, positionKey, a.ID.String())
+// NewRedisSpawnTableRepository creates a new Redis-based spawn table repository
+func NewRedisSpawnTableRepository(client *redis.Client) SpawnTableRepository {
+	return &RedisSpawnTableRepository{
+		client: client,
 	}
+}
 
-	// Owner index (only for captured animals)
-	if a.IsCaptured() {
-		ownerKey := fmt.Sprintf("idx:animal:owner:%s", a.OwnerID.String())
-		pipe.SAdd(ctx, ownerKey, a.ID.String())
+// Get retrieves the current override
+func (r *RedisSpawnTableRepository) Get(ctx context.Context) (*SpawnTable, error) {
+	data, err := r.client.Get(ctx, spawnTableKey).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	// State index
-	stateKey := fmt.Sprintf("idx:animal:state:%s", a.State.String())
-	pipe.SAdd(ctx, stateKey, a.ID.String())
-
-	// Type index
-	typeKey := fmt.Sprintf("idx:animal:type:%s", a.AnimalType.String())
-	pipe.SAdd(ctx, typeKey, a.ID.String())
+	table := &SpawnTable{}
+	if err := json.Unmarshal(data, table); err != nil {
+		return nil, fmt.Errorf("failed to decode spawn table: %w", err)
+	}
+	if time.Now().After(table.ExpiresAt) {
+		return nil, nil
+	}
+	return table, nil
 }
 
-// cleanupAnimalIndices cleans up secondary indices
-func (r *RedisRepository) cleanupAnimalIndices(ctx context.Context, pipe redis.Pipeliner, a *Animal) {
-	// Position index
-	if a.IsWild() {
-		positionKey := fmt.Sprintf("idx:animal:wild_position:%.1f:%.1f", a.Position.X, a.Position.Y)
-		pipe.SRem(ctx, positionKey, a.ID.String())
+// Set replaces the override
+func (r *RedisSpawnTableRepository) Set(ctx context.Context, table *SpawnTable) error {
+	data, err := json.Marshal(table)
+	if err != nil {
+		return err
 	}
 
-	// Owner index
-	if a.IsCaptured() {
-		ownerKey := fmt.Sprintf("idx:animal:owner:%s", a.OwnerID.String())
-		pipe.SRem(ctx, ownerKey, a.ID.String())
+	ttl := time.Until(table.ExpiresAt)
+	if ttl <= 0 {
+		return r.Clear(ctx)
 	}
+	return r.client.Set(ctx, spawnTableKey, data, ttl).Err()
+}
 
-	// State index
-	stateKey := fmt.Sprintf("idx:animal:state:%s", a.State.String())
-	pipe.SRem(ctx, stateKey, a.ID.String())
-
-	// Type index
-	typeKey := fmt.Sprintf("idx:animal:type:%s", a.AnimalType.String())
-	pipe.SRem(ctx, typeKey, a.ID.String())
+// Clear removes the override
+func (r *RedisSpawnTableRepository) Clear(ctx context.Context) error {
+	return r.client.Del(ctx, spawnTableKey).Err()
 }
@@ -32,3 +32,15 @@ type Repository interface {
 	// Delete removes an animal
 	Delete(ctx context.Context, id AnimalID) error
 }
+
+// SpawnTableRepository stores the spawn table override of live events
+type SpawnTableRepository interface {
+	// Get retrieves the current override, returning nil if there is none or it expired
+	Get(ctx context.Context) (*SpawnTable, error)
+
+	// Set replaces the override; it is dropped once it expires
+	Set(ctx context.Context, table *SpawnTable) error
+
+	// Clear removes the override so the configured settings apply again
+	Clear(ctx context.Context) error
+}
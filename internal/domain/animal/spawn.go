@@ -0,0 +1,209 @@
+package animal
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// SpawnConfig controls how wild animals spawn into the world
+type SpawnConfig struct {
+	Rate     float64 // Chance per tick for an area below its cap to spawn an animal
+	AreaSize int     // Side length of a spawn area in tiles
+	AreaCap  int     // Max wild animals per area
+	MinLevel int
+	MaxLevel int
+	Weights  map[AnimalType]int // Relative chance of each spawnable type; nil rolls them evenly
+}
+
+// DefaultSpawnConfig returns the spawn settings used by the game for a spawn rate
+func DefaultSpawnConfig(rate float64) SpawnConfig {
+	return SpawnConfig{
+		Rate:     rate,
+		AreaSize: 10,
+		AreaCap:  3,
+		MinLevel: 1,
+		MaxLevel: 5,
+	}
+}
+
+// spawnableTypes lists the animal types that appear in the wild, in roll order
+var spawnableTypes = []AnimalType{Lion, Elephant, Cheetah}
+
+// SpawnArea identifies one square area of the map with its own animal cap
+type SpawnArea struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// String returns string representation of SpawnArea
+func (a SpawnArea) String() string {
+	return fmt.Sprintf("%d:%d", a.X, a.Y)
+}
+
+// AreaOf returns the spawn area containing a position
+func (c SpawnConfig) AreaOf(position shared.Position) SpawnArea {
+	size := float64(c.AreaSize)
+	return SpawnArea{
+		X: int(math.Floor(position.X / size)),
+		Y: int(math.Floor(position.Y / size)),
+	}
+}
+
+// Areas returns every spawn area covering a map of the given size
+func (c SpawnConfig) Areas(width, height int) []SpawnArea {
+	areas := make([]SpawnArea, 0)
+	for x := 0; x*c.AreaSize < width; x++ {
+		for y := 0; y*c.AreaSize < height; y++ {
+			areas = append(areas, SpawnArea{X: x, Y: y})
+		}
+	}
+	return areas
+}
+
+// RollSpawn decides whether an area spawns an animal this tick and, if so, creates it on a
+// random walkable tile in the area. It returns nil when nothing spawns.
+func (c SpawnConfig) RollSpawn(area SpawnArea, population, width, height int, walkable func(shared.Position) bool, rng *rand.Rand) (*Animal, error) {
+	if population >= c.AreaCap {
+		return nil, nil
+	}
+
+	if rng.Float64() >= c.Rate {
+		return nil, nil
+	}
+
+	tiles := make([]shared.Position, 0)
+	for x := area.X * c.AreaSize; x < min((area.X+1)*c.AreaSize, width); x++ {
+		for y := area.Y * c.AreaSize; y < min((area.Y+1)*c.AreaSize, height); y++ {
+			position := shared.NewPosition(float64(x), float64(y))
+			if walkable(position) {
+				tiles = append(tiles, position)
+			}
+		}
+	}
+
+	if len(tiles) == 0 {
+		return nil, nil
+	}
+
+	position := tiles[rng.Intn(len(tiles))]
+	animalType := c.rollType(rng)
+	if animalType == "" {
+		return nil, nil
+	}
+	level := c.MinLevel + rng.Intn(c.MaxLevel-c.MinLevel+1)
+
+	return NewWildAnimal(animalType, level, position)
+}
+
+// rollType picks the type of a spawning animal, evenly or by Weights. It returns "" when
+// every type is weighted zero.
+func (c SpawnConfig) rollType(rng *rand.Rand) AnimalType {
+	if c.Weights == nil {
+		return spawnableTypes[rng.Intn(len(spawnableTypes))]
+	}
+
+	total := 0
+	for _, animalType := range spawnableTypes {
+		total += c.Weights[animalType]
+	}
+	if total <= 0 {
+		return ""
+	}
+
+	roll := rng.Intn(total)
+	for _, animalType := range spawnableTypes {
+		roll -= c.Weights[animalType]
+		if roll < 0 {
+			return animalType
+		}
+	}
+	return ""
+}
+
+// SpawnTable temporarily overrides spawn settings, e.g. to make a type common or stronger
+// during a live event. Zero fields keep the configured setting.
+type SpawnTable struct {
+	Rate      float64            `json:"rate,omitempty"`
+	MinLevel  int                `json:"min_level,omitempty"`
+	MaxLevel  int                `json:"max_level,omitempty"`
+	Weights   map[AnimalType]int `json:"weights,omitempty"`
+	SetBy     string             `json:"set_by"`
+	ExpiresAt time.Time          `json:"expires_at"`
+}
+
+// MaxSpawnTableDuration is the longest a spawn table override may last
+const MaxSpawnTableDuration = 14 * 24 * time.Hour
+
+// maxSpawnLevel is the highest level an animal can have
+const maxSpawnLevel = 100
+
+// NewSpawnTable creates a spawn table override lasting for duration
+func NewSpawnTable(rate float64, minLevel, maxLevel int, weights map[AnimalType]int, setBy string, duration time.Duration) (*SpawnTable, error) {
+	if rate < 0 || rate > 1 {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Spawn rate must be between 0 and 1")
+	}
+	if minLevel < 0 || maxLevel < 0 || minLevel > maxSpawnLevel || maxLevel > maxSpawnLevel {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Spawn levels must be between 1 and %d", maxSpawnLevel)
+	}
+	if minLevel > 0 && maxLevel > 0 && minLevel > maxLevel {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Minimum spawn level is above the maximum")
+	}
+	for animalType, weight := range weights {
+		if !isSpawnable(animalType) {
+			return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Animal type %q does not spawn in the wild", animalType)
+		}
+		if weight < 0 {
+			return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Spawn weights must not be negative")
+		}
+	}
+	if duration <= 0 || duration > MaxSpawnTableDuration {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Spawn table duration must be positive and at most %s", MaxSpawnTableDuration)
+	}
+
+	return &SpawnTable{
+		Rate:      rate,
+		MinLevel:  minLevel,
+		MaxLevel:  maxLevel,
+		Weights:   weights,
+		SetBy:     setBy,
+		ExpiresAt: time.Now().Add(duration),
+	}, nil
+}
+
+// Apply returns the config with the table's overrides. A table overriding only one level
+// bound keeps the other bound from crossing it.
+func (c SpawnConfig) Apply(table *SpawnTable) SpawnConfig {
+	if table == nil || time.Now().After(table.ExpiresAt) {
+		return c
+	}
+
+	if table.Rate > 0 {
+		c.Rate = table.Rate
+	}
+	if table.MinLevel > 0 {
+		c.MinLevel = table.MinLevel
+		c.MaxLevel = max(c.MaxLevel, c.MinLevel)
+	}
+	if table.MaxLevel > 0 {
+		c.MaxLevel = table.MaxLevel
+		c.MinLevel = min(c.MinLevel, c.MaxLevel)
+	}
+	if table.Weights != nil {
+		c.Weights = table.Weights
+	}
+	return c
+}
+
+// isSpawnable checks if an animal type appears in the wild
+func isSpawnable(animalType AnimalType) bool {
+	for _, spawnable := range spawnableTypes {
+		if animalType == spawnable {
+			return true
+		}
+	}
+	return false
+}
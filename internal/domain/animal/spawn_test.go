@@ -0,0 +1,69 @@
+package animal
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestSpawnConfig_RollSpawn(t *testing.T) {
+	config := DefaultSpawnConfig(1)
+	area := SpawnArea{X: 1, Y: 0}
+	rng := rand.New(rand.NewSource(1))
+
+	assert.Len(t, config.Areas(30, 20), 6)
+	assert.Equal(t, area, config.AreaOf(shared.NewPosition(12.5, 3)))
+
+	// Only tile (15, 5) is walkable in this area
+	walkable := func(p shared.Position) bool { return p.X == 15 && p.Y == 5 }
+
+	wild, err := config.RollSpawn(area, 0, 30, 20, walkable, rng)
+	require.NoError(t, err)
+	require.NotNil(t, wild)
+	assert.True(t, wild.IsWild())
+	assert.Equal(t, shared.NewPosition(15, 5), wild.Position)
+	assert.GreaterOrEqual(t, wild.Level.Value(), config.MinLevel)
+	assert.LessOrEqual(t, wild.Level.Value(), config.MaxLevel)
+
+	full, err := config.RollSpawn(area, config.AreaCap, 30, 20, walkable, rng)
+	require.NoError(t, err)
+	assert.Nil(t, full, "areas at their cap must not spawn")
+
+	blocked, err := config.RollSpawn(area, 0, 30, 20, func(shared.Position) bool { return false }, rng)
+	require.NoError(t, err)
+	assert.Nil(t, blocked, "animals must not spawn without walkable terrain")
+}
+
+func TestSpawnConfig_Apply(t *testing.T) {
+	config := DefaultSpawnConfig(0.1)
+
+	table, err := NewSpawnTable(0.5, 20, 0, map[AnimalType]int{Cheetah: 1}, "admin", time.Hour)
+	require.NoError(t, err)
+
+	applied := config.Apply(table)
+	assert.Equal(t, 0.5, applied.Rate)
+	assert.Equal(t, 20, applied.MinLevel)
+	assert.Equal(t, 20, applied.MaxLevel, "max level must not fall below the overridden min level")
+
+	// Only cheetahs carry weight, so every spawn is a cheetah
+	rng := rand.New(rand.NewSource(1))
+	walkable := func(shared.Position) bool { return true }
+	for range 20 {
+		applied.Rate = 1
+		wild, err := applied.RollSpawn(SpawnArea{}, 0, 30, 20, walkable, rng)
+		require.NoError(t, err)
+		require.NotNil(t, wild)
+		assert.Equal(t, Cheetah, wild.AnimalType)
+	}
+
+	table.ExpiresAt = time.Now().Add(-time.Second)
+	assert.Equal(t, config, config.Apply(table), "expired tables must not apply")
+
+	_, err = NewSpawnTable(0, 0, 0, map[AnimalType]int{"dragon": 1}, "admin", time.Hour)
+	assert.Error(t, err)
+}
@@ -0,0 +1,252 @@
+package battle
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/loot"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// MaxRange is how far a trainer can be from a wild animal to challenge it
+const MaxRange = 5.0
+
+// InRange checks if a trainer at a position is close enough to challenge a wild animal
+func InRange(trainerPosition, wildPosition shared.Position) bool {
+	return trainerPosition.WithinRange(wildPosition, MaxRange)
+}
+
+// BattleID uniquely identifies a battle
+type BattleID string
+
+// NewBattleID generates a new battle ID
+func NewBattleID() BattleID {
+	return BattleID(uuid.New().String())
+}
+
+// String returns string representation of BattleID
+func (id BattleID) String() string {
+	return string(id)
+}
+
+// Status represents how far a battle has progressed
+type Status string
+
+const (
+	StatusActive      Status = "active"
+	StatusWon         Status = "won"
+	StatusLost        Status = "lost"
+	StatusFled        Status = "fled"
+	StatusInterrupted Status = "interrupted" // The wild animal was captured or removed mid-battle
+)
+
+// String returns string representation of Status
+func (s Status) String() string {
+	return string(s)
+}
+
+// Action is what the trainer's animal does on its turn
+type Action string
+
+const (
+	ActionAttack Action = "attack"
+	ActionFlee   Action = "flee"
+)
+
+// Strike is one animal hitting another
+type Strike struct {
+	AttackerID string `json:"attacker_id"`
+	TargetID   string `json:"target_id"`
+	Damage     int    `json:"damage"`    // HP actually lost after defense
+	TargetHP   int    `json:"target_hp"` // Target HP after the strike
+}
+
+// Turn records one exchange in a battle
+type Turn struct {
+	Number  int      `json:"number"`
+	Action  Action   `json:"action"`
+	Strikes []Strike `json:"strikes"`
+	Fled    bool     `json:"fled,omitempty"`
+}
+
+// Battle is a fight between one of a trainer's party animals and a wild animal
+type Battle struct {
+	ID         BattleID        `json:"id"`
+	TrainerID  trainer.UserID  `json:"trainer_id"`
+	AnimalID   animal.AnimalID `json:"animal_id"` // Trainer's party animal
+	WildID     animal.AnimalID `json:"wild_id"`
+	Position   shared.Position `json:"position"` // Where the wild animal was challenged
+	Status     Status          `json:"status"`
+	Turns      int             `json:"turns"`
+	LastTurn   *Turn           `json:"last_turn,omitempty"`
+	Experience int             `json:"experience,omitempty"` // Awarded to the party animal on victory
+	StartedAt  time.Time       `json:"started_at"`
+	UpdatedAt  time.Time       `json:"updated_at"`
+}
+
+// Result is the state of a battle after an action
+type Result struct {
+	Battle  *Battle        `json:"battle"`
+	Animal  *animal.Animal `json:"animal"`            // Trainer's party animal
+	Wild    *animal.Animal `json:"wild,omitempty"`    // Empty once the wild animal is gone
+	Drops   []loot.Drop    `json:"drops,omitempty"`   // Loot rolled on victory
+	Pickups []*loot.Pickup `json:"pickups,omitempty"` // Set when loot is delivered as world pickups
+}
+
+// NewBattle starts a battle between a party animal and a wild animal
+func NewBattle(trainerID trainer.UserID, own, wild *animal.Animal) (*Battle, error) {
+	if own.OwnerID != shared.ID(trainerID) || own.State != animal.InParty {
+		return nil, shared.NewDomainError(shared.ErrCodeAnimalNotInParty, "Only animals in the trainer's party can battle")
+	}
+	if own.IsFainted() {
+		return nil, shared.NewDomainError(shared.ErrCodeAlreadyFainted, "Fainted animals cannot battle")
+	}
+	if !wild.CanBeCaptured() {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidState, "Only living wild animals can be challenged")
+	}
+
+	now := time.Now()
+	return &Battle{
+		ID:        NewBattleID(),
+		TrainerID: trainerID,
+		AnimalID:  own.ID,
+		WildID:    wild.ID,
+		Position:  wild.Position,
+		Status:    StatusActive,
+		StartedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// IsOver checks if the battle has ended
+func (b *Battle) IsOver() bool {
+	return b.Status != StatusActive
+}
+
+// Attack plays one turn where both animals strike, the faster one first. The animals are
+// updated in place; the wild animal fainting wins the battle and sets the experience earned.
+func (b *Battle) Attack(own, wild *animal.Animal, rng *rand.Rand) (*Turn, error) {
+	turn, err := b.nextTurn(ActionAttack)
+	if err != nil {
+		return nil, err
+	}
+
+	first, second := own, wild
+	if wild.CurrentStats.SPD > own.CurrentStats.SPD {
+		first, second = wild, own
+	}
+
+	for _, pair := range [][2]*animal.Animal{{first, second}, {second, first}} {
+		attacker, target := pair[0], pair[1]
+		if attacker.IsFainted() {
+			break
+		}
+
+		s, err := strike(attacker, target, rng)
+		if err != nil {
+			return nil, err
+		}
+		turn.Strikes = append(turn.Strikes, s)
+	}
+
+	switch {
+	case wild.IsFainted():
+		b.Status = StatusWon
+		b.Experience = ExperienceFor(wild)
+	case own.IsFainted():
+		b.Status = StatusLost
+	}
+
+	b.finishTurn(turn)
+	return turn, nil
+}
+
+// Flee tries to escape the battle. A failed attempt gives the wild animal a free strike.
+func (b *Battle) Flee(own, wild *animal.Animal, rng *rand.Rand) (*Turn, error) {
+	turn, err := b.nextTurn(ActionFlee)
+	if err != nil {
+		return nil, err
+	}
+
+	if rng.Float64() < FleeChance(own, wild) {
+		turn.Fled = true
+		b.Status = StatusFled
+	} else {
+		s, err := strike(wild, own, rng)
+		if err != nil {
+			return nil, err
+		}
+		turn.Strikes = append(turn.Strikes, s)
+
+		if own.IsFainted() {
+			b.Status = StatusLost
+		}
+	}
+
+	b.finishTurn(turn)
+	return turn, nil
+}
+
+// Interrupt ends the battle because the wild animal is no longer there to fight
+func (b *Battle) Interrupt() {
+	b.Status = StatusInterrupted
+	b.UpdatedAt = time.Now()
+}
+
+// FleeChance returns the chance of escaping, better for animals faster than their opponent
+func FleeChance(own, wild *animal.Animal) float64 {
+	chance := 0.5 + float64(own.CurrentStats.SPD-wild.CurrentStats.SPD)*0.05
+	if chance < 0.1 {
+		chance = 0.1
+	}
+	if chance > 0.95 {
+		chance = 0.95
+	}
+	return chance
+}
+
+// ExperienceFor returns the experience earned for defeating a wild animal
+func ExperienceFor(wild *animal.Animal) int {
+	return 40 * wild.Level.Value()
+}
+
+// nextTurn starts a new turn if the battle is still going
+func (b *Battle) nextTurn(action Action) (*Turn, error) {
+	if b.IsOver() {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeNotInBattle, "Battle is already %s", b.Status)
+	}
+
+	return &Turn{
+		Number:  b.Turns + 1,
+		Action:  action,
+		Strikes: make([]Strike, 0, 2),
+	}, nil
+}
+
+// finishTurn records a played turn
+func (b *Battle) finishTurn(turn *Turn) {
+	b.Turns = turn.Number
+	b.LastTurn = turn
+	b.UpdatedAt = time.Now()
+}
+
+// strike deals attack damage with a small random spread. Defense is applied by TakeDamage.
+func strike(attacker, target *animal.Animal, rng *rand.Rand) (Strike, error) {
+	damage := attacker.CurrentStats.ATK * (85 + rng.Intn(16)) / 100
+
+	before := target.CurrentHP
+	if err := target.TakeDamage(damage); err != nil {
+		return Strike{}, err
+	}
+
+	return Strike{
+		AttackerID: attacker.ID.String(),
+		TargetID:   target.ID.String(),
+		Damage:     before - target.CurrentHP,
+		TargetHP:   target.CurrentHP,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package battle
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+func newFighters(t *testing.T) (*animal.Animal, *animal.Animal) {
+	t.Helper()
+
+	caught, err := animal.NewWildAnimal(animal.Lion, 10, shared.NewPosition(0, 0))
+	require.NoError(t, err)
+	own, err := animal.NewCapturedAnimal(caught, shared.ID("user-1"))
+	require.NoError(t, err)
+	require.NoError(t, own.ChangeState(animal.InParty))
+
+	wild, err := animal.NewWildAnimal(animal.Lion, 1, shared.NewPosition(2, 2))
+	require.NoError(t, err)
+
+	return own, wild
+}
+
+func TestBattle_AttackUntilWon(t *testing.T) {
+	own, wild := newFighters(t)
+
+	b, err := NewBattle(trainer.UserID("user-1"), own, wild)
+	require.NoError(t, err)
+	assert.Equal(t, wild.Position, b.Position)
+
+	rng := rand.New(rand.NewSource(1))
+	for !b.IsOver() {
+		turn, err := b.Attack(own, wild, rng)
+		require.NoError(t, err)
+		require.NotEmpty(t, turn.Strikes)
+		require.LessOrEqual(t, b.Turns, 20, "battle should not drag on")
+	}
+
+	assert.Equal(t, StatusWon, b.Status)
+	assert.True(t, wild.IsFainted())
+	assert.Equal(t, ExperienceFor(wild), b.Experience)
+
+	_, err = b.Attack(own, wild, rng)
+	assert.Error(t, err, "finished battles cannot continue")
+}
+
+func TestBattle_FleeAndValidation(t *testing.T) {
+	own, wild := newFighters(t)
+
+	_, err := NewBattle(trainer.UserID("user-2"), own, wild)
+	assert.Error(t, err, "only the owner's party animals can battle")
+	_, err = NewBattle(trainer.UserID("user-1"), wild, own)
+	assert.Error(t, err, "wild animals cannot fight for a trainer")
+
+	b, err := NewBattle(trainer.UserID("user-1"), own, wild)
+	require.NoError(t, err)
+
+	rng := rand.New(rand.NewSource(1))
+	for !b.IsOver() {
+		_, err := b.Flee(own, wild, rng)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, StatusFled, b.Status)
+	assert.True(t, b.LastTurn.Fled)
+	assert.Zero(t, b.Experience)
+}
+
+func TestInRange(t *testing.T) {
+	wild := shared.NewPosition(10, 10)
+	assert.True(t, InRange(shared.NewPosition(10, 10), wild))
+	assert.True(t, InRange(shared.NewPosition(13, 14), wild), "exactly MaxRange away")
+	assert.True(t, InRange(shared.NewPosition(14, 12), wild), "beyond the squared distance of MaxRange")
+	assert.False(t, InRange(shared.NewPosition(13, 14.1), wild))
+	assert.False(t, InRange(shared.NewPosition(10, 16), wild))
+}
@@ -0,0 +1,170 @@
+package battle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+const (
+	// Active battles left idle this long are abandoned, freeing the trainer and the wild animal
+	activeBattleTTL = 10 * time.Minute
+	// Finished battles are kept briefly so clients can fetch the outcome
+	finishedBattleTTL = time.Hour
+)
+
+// RedisRepository implements Repository using Redis Hash
+type RedisRepository struct {
+	client *redis.Client
+	codec  redisx.HashCodec[Battle]
+	docs   *redisx.Repository[Battle]
+}
+
+// NewRedisRepository creates a new Redis-based battle repository
+func NewRedisRepository(client *redis.Client) Repository {
+	r := &RedisRepository{
+		client: client,
+	}
+	r.docs = redisx.NewRepository(client, "battle:", redisx.Options[Battle]{
+		Codec:    r.codec,
+		Indexes:  []redisx.Index[Battle]{redisx.IndexFunc[Battle](r.updateBattleIndices)},
+		NotFound: func() error { return shared.ErrNotFound("battle") },
+	})
+	return r
+}
+
+// FindOneAndInsert implements IoC pattern for insert operations. It watches the active
+// indices of both sides too, which redisx.Repository can't, so it writes the document itself.
+func (r *RedisRepository) FindOneAndInsert(ctx context.Context, id BattleID, callback func() (*Battle, error)) error {
+	key := r.docs.Key(id.String())
+
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		// Check if already exists
+		exists := tx.Exists(ctx, key)
+		if exists.Err() != nil {
+			return exists.Err()
+		}
+
+		if exists.Val() > 0 {
+			return shared.ErrAlreadyExists("battle")
+		}
+
+		// Execute callback
+		result, err := callback()
+		if err != nil {
+			return err
+		}
+
+		if result == nil {
+			return fmt.Errorf("callback returned nil battle")
+		}
+
+		// Neither side may already be fighting
+		trainerKey, wildKey := r.trainerKey(result.TrainerID), r.wildKey(result.WildID)
+		if err := tx.Watch(ctx, trainerKey, wildKey).Err(); err != nil {
+			return err
+		}
+
+		busy, err := tx.Exists(ctx, trainerKey, wildKey).Result()
+		if err != nil {
+			return err
+		}
+		if busy > 0 {
+			return shared.NewDomainError(shared.ErrCodeAlreadyInBattle, "Trainer or animal is already in a battle")
+		}
+
+		// Serialize and store
+		data, err := r.codec.Encode(result)
+		if err != nil {
+			return err
+		}
+
+		// Execute transaction
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			r.codec.Write(ctx, pipe, key, data)
+			r.updateBattleIndices(ctx, pipe, id.String(), nil, result)
+			return nil
+		})
+
+		return err
+	}, key)
+}
+
+// FindOneAndUpdate implements IoC pattern for update operations
+func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, id BattleID, callback func(*Battle) (*Battle, error)) error {
+	return r.docs.FindOneAndUpdate(ctx, id.String(), callback)
+}
+
+// GetByID retrieves a battle by ID
+func (r *RedisRepository) GetByID(ctx context.Context, id BattleID) (*Battle, error) {
+	return r.docs.GetByID(ctx, id.String())
+}
+
+// GetActiveByTrainer retrieves the trainer's active battle
+func (r *RedisRepository) GetActiveByTrainer(ctx context.Context, trainerID trainer.UserID) (*Battle, error) {
+	return r.getActive(ctx, r.trainerKey(trainerID))
+}
+
+// GetActiveByWild retrieves the active battle a wild animal is in
+func (r *RedisRepository) GetActiveByWild(ctx context.Context, wildID animal.AnimalID) (*Battle, error) {
+	return r.getActive(ctx, r.wildKey(wildID))
+}
+
+// getActive retrieves the battle an active index points at, if it is still running
+func (r *RedisRepository) getActive(ctx context.Context, indexKey string) (*Battle, error) {
+	id, err := r.client.Get(ctx, indexKey).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := r.GetByID(ctx, BattleID(id))
+	if err != nil {
+		return nil, err
+	}
+	if b == nil || b.IsOver() {
+		return nil, nil
+	}
+
+	return b, nil
+}
+
+// trainerKey returns the Redis key holding a trainer's active battle
+func (r *RedisRepository) trainerKey(trainerID trainer.UserID) string {
+	return fmt.Sprintf("idx:battle:trainer:%s", trainerID.String())
+}
+
+// wildKey returns the Redis key holding the active battle a wild animal is in
+func (r *RedisRepository) wildKey(wildID animal.AnimalID) string {
+	return fmt.Sprintf("idx:battle:wild:%s", wildID.String())
+}
+
+// updateBattleIndices keeps the active indices pointing at running battles only. Every turn
+// refreshes the idle timeout.
+func (r *RedisRepository) updateBattleIndices(ctx context.Context, pipe redis.Pipeliner, id string, before, after *Battle) {
+	if after == nil {
+		return
+	}
+
+	key := r.docs.Key(id)
+	trainerKey, wildKey := r.trainerKey(after.TrainerID), r.wildKey(after.WildID)
+
+	if after.IsOver() {
+		pipe.Del(ctx, trainerKey, wildKey)
+		pipe.Expire(ctx, key, finishedBattleTTL)
+		return
+	}
+
+	pipe.Set(ctx, trainerKey, after.ID.String(), activeBattleTTL)
+	pipe.Set(ctx, wildKey, after.ID.String(), activeBattleTTL)
+	pipe.Expire(ctx, key, activeBattleTTL+finishedBattleTTL)
+}
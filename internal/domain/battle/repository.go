@@ -0,0 +1,27 @@
+package battle
+
+import (
+	"context"
+
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// Repository defines the interface for battle persistence operations with IoC pattern
+type Repository interface {
+	// FindOneAndInsert inserts a new battle with callback for initialization. It fails if the
+	// trainer or the wild animal is already in an active battle.
+	FindOneAndInsert(ctx context.Context, id BattleID, callback func() (*Battle, error)) error
+
+	// FindOneAndUpdate finds a battle by ID and applies callback for atomic update
+	FindOneAndUpdate(ctx context.Context, id BattleID, callback func(*Battle) (*Battle, error)) error
+
+	// GetByID retrieves a battle by ID (read-only)
+	GetByID(ctx context.Context, id BattleID) (*Battle, error)
+
+	// GetActiveByTrainer retrieves the trainer's active battle, if any (read-only)
+	GetActiveByTrainer(ctx context.Context, trainerID trainer.UserID) (*Battle, error)
+
+	// GetActiveByWild retrieves the active battle a wild animal is in, if any (read-only)
+	GetActiveByWild(ctx context.Context, wildID animal.AnimalID) (*Battle, error)
+}
@@ -0,0 +1,112 @@
+package bullet
+
+import (
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+const (
+	// AmmoBoxRounds is the number of rounds in an ammo box, bought or picked up
+	AmmoBoxRounds = 30
+	// AmmoBoxPrice is the money an ammo box costs
+	AmmoBoxPrice = 50
+
+	// DefaultWeapon is the weapon players start with
+	DefaultWeapon = BasicPistol
+
+	// Magazines of reserve ammo a new player starts with
+	startingMagazines = 3
+	// Magazines of reserve ammo a player can carry at most
+	maxReserveMagazines = 8
+)
+
+// weaponMagazines holds each weapon's magazine size and the time it takes to reload it
+var weaponMagazines = map[WeaponType]struct {
+	size   int
+	reload time.Duration
+}{
+	BasicPistol:    {size: 12, reload: 1500 * time.Millisecond},
+	AdvancedPistol: {size: 15, reload: 1200 * time.Millisecond},
+	AssaultRifle:   {size: 30, reload: 2500 * time.Millisecond},
+	SniperRifle:    {size: 5, reload: 3 * time.Second},
+	PumpShotgun:    {size: 6, reload: 3500 * time.Millisecond},
+	AutoShotgun:    {size: 12, reload: 2800 * time.Millisecond},
+}
+
+// MagazineSize returns the number of rounds a magazine of the weapon holds
+func (wt WeaponType) MagazineSize() int {
+	if magazine, ok := weaponMagazines[wt]; ok {
+		return magazine.size
+	}
+	return 12
+}
+
+// ReloadTime returns how long reloading the weapon takes; it cannot fire meanwhile
+func (wt WeaponType) ReloadTime() time.Duration {
+	if magazine, ok := weaponMagazines[wt]; ok {
+		return magazine.reload
+	}
+	return 1500 * time.Millisecond
+}
+
+// MaxReserveAmmo returns the number of rounds for the weapon a player can carry besides the
+// loaded magazine
+func (wt WeaponType) MaxReserveAmmo() int {
+	return wt.MagazineSize() * maxReserveMagazines
+}
+
+// StartingReserveAmmo returns the reserve ammo a new player starts with
+func (wt WeaponType) StartingReserveAmmo() int {
+	return wt.MagazineSize() * startingMagazines
+}
+
+// IsReloading checks if the weapon is still being reloaded
+func (ps *PlayerStats) IsReloading(currentTime time.Time) bool {
+	return ps.ReloadingUntil != nil && currentTime.Before(*ps.ReloadingUntil)
+}
+
+// RoundsToReload returns how many rounds a reload would load from the reserve
+func (ps *PlayerStats) RoundsToReload() int {
+	return max(0, min(ps.WeaponType.MagazineSize()-ps.AmmoCount, ps.ReserveAmmo))
+}
+
+// Reload fills the magazine from the reserve ammo. The weapon cannot fire until the reload
+// time of the weapon has passed.
+func (ps *PlayerStats) Reload(reloadTime time.Time) error {
+	if ps.IsReloading(reloadTime) {
+		return shared.NewDomainError(shared.ErrCodeReloading, "Weapon is already reloading")
+	}
+	if ps.AmmoCount >= ps.WeaponType.MagazineSize() {
+		return shared.NewDomainError(shared.ErrCodeMagazineFull, "Magazine is full")
+	}
+
+	rounds := ps.RoundsToReload()
+	if rounds == 0 {
+		return shared.NewDomainError(shared.ErrCodeNoAmmo, "No reserve ammo")
+	}
+
+	ps.ReserveAmmo -= rounds
+	ps.AmmoCount += rounds
+	readyAt := reloadTime.Add(ps.WeaponType.ReloadTime())
+	ps.ReloadingUntil = &readyAt
+	ps.UpdatedAt = time.Now()
+
+	return nil
+}
+
+// ReserveSpace returns how many more rounds fit in the reserve
+func (ps *PlayerStats) ReserveSpace() int {
+	return max(0, ps.WeaponType.MaxReserveAmmo()-ps.ReserveAmmo)
+}
+
+// AddReserveAmmo adds rounds to the reserve up to what the player can carry and returns the
+// rounds added
+func (ps *PlayerStats) AddReserveAmmo(rounds int) int {
+	added := min(max(rounds, 0), ps.ReserveSpace())
+	if added > 0 {
+		ps.ReserveAmmo += added
+		ps.UpdatedAt = time.Now()
+	}
+	return added
+}
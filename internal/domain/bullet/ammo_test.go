@@ -0,0 +1,51 @@
+package bullet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestPlayerStats_Reload(t *testing.T) {
+	now := time.Now()
+	stats := NewPlayerStats("player", 2, SniperRifle)
+	require.Equal(t, 15, stats.ReserveAmmo)
+
+	code, _ := shared.DomainErrorCode(NewPlayerStats("player", 5, SniperRifle).Reload(now))
+	assert.Equal(t, shared.ErrCodeMagazineFull, code)
+
+	require.NoError(t, stats.Reload(now))
+	assert.Equal(t, 5, stats.AmmoCount)
+	assert.Equal(t, 12, stats.ReserveAmmo)
+
+	// The weapon neither fires nor reloads again until the reload time has passed
+	assert.True(t, stats.IsReloading(now.Add(time.Second)))
+	assert.False(t, stats.CanFire(now.Add(time.Second)))
+	code, _ = shared.DomainErrorCode(stats.Fire(now.Add(time.Second)))
+	assert.Equal(t, shared.ErrCodeReloading, code)
+
+	ready := now.Add(SniperRifle.ReloadTime())
+	assert.False(t, stats.IsReloading(ready))
+	require.NoError(t, stats.Fire(ready))
+	assert.Equal(t, 4, stats.AmmoCount)
+
+	// An empty reserve has nothing to load
+	stats.ReserveAmmo = 0
+	code, _ = shared.DomainErrorCode(stats.Reload(ready.Add(time.Second)))
+	assert.Equal(t, shared.ErrCodeNoAmmo, code)
+}
+
+func TestPlayerStats_AddReserveAmmo(t *testing.T) {
+	stats := NewPlayerStats("player", 12, BasicPistol)
+	space := stats.ReserveSpace()
+	require.Equal(t, BasicPistol.MaxReserveAmmo()-BasicPistol.StartingReserveAmmo(), space)
+
+	assert.Equal(t, AmmoBoxRounds, stats.AddReserveAmmo(AmmoBoxRounds))
+	assert.Equal(t, space-AmmoBoxRounds, stats.AddReserveAmmo(space))
+	assert.Equal(t, BasicPistol.MaxReserveAmmo(), stats.ReserveAmmo)
+	assert.Zero(t, stats.AddReserveAmmo(1), "reserve is capped")
+}
@@ -107,6 +107,26 @@ func (wt WeaponType) GetMaxRange() float64 {
 	}
 }
 
+// GetDamage returns the damage a bullet of the weapon deals on a hit
+func (wt WeaponType) GetDamage() int {
+	switch wt {
+	case BasicPistol:
+		return 15
+	case AdvancedPistol:
+		return 18
+	case AssaultRifle:
+		return 12
+	case SniperRifle:
+		return 70
+	case PumpShotgun:
+		return 45
+	case AutoShotgun:
+		return 20
+	default:
+		return 15 // Default damage
+	}
+}
+
 // Direction represents a 2D direction vector
 type Direction struct {
 	X float64 `json:"x"`
@@ -228,7 +248,7 @@ func NewBullet(playerID PlayerID, weaponType WeaponType, startPos shared.Positio
 	now := time.Now()
 	
 	// Calculate expiry time based on max range and speed
-	timeToExpiry := time.Duration(maxRange/speed) * time.Second
+	timeToExpiry := time.Duration(maxRange / speed * float64(time.Second))
 	expiresAt := now.Add(timeToExpiry)
 
 	bullet := &Bullet{
@@ -0,0 +1,34 @@
+package bullet
+
+import (
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// HitRadius is how close a bullet has to pass a trainer's position to hit it, in world units
+const HitRadius = 0.5
+
+// PositionAt returns where the bullet is at a point of its flight
+func (b *Bullet) PositionAt(at time.Time) shared.Position {
+	elapsed := max(at.Sub(b.FiredAt), 0).Seconds()
+	vx, vy := b.Velocity.GetVelocityComponents()
+	return shared.NewPosition(b.StartPos.X+vx*elapsed, b.StartPos.Y+vy*elapsed)
+}
+
+// HitBetween checks if the bullet passes within HitRadius of target while it flies from one
+// point of its flight to another. It returns how far along that stretch, from 0 to 1, the
+// bullet comes closest to the target.
+func (b *Bullet) HitBetween(from, to time.Time, target shared.Position) (float64, bool) {
+	start, end := b.PositionAt(from), b.PositionAt(to)
+	dx, dy := end.X-start.X, end.Y-start.Y
+
+	along := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		along = ((target.X-start.X)*dx + (target.Y-start.Y)*dy) / length
+		along = min(max(along, 0), 1)
+	}
+
+	closest := shared.NewPosition(start.X+dx*along, start.Y+dy*along)
+	return along, closest.WithinRange(target, HitRadius)
+}
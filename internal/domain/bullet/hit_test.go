@@ -0,0 +1,31 @@
+package bullet
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestBullet_HitBetween(t *testing.T) {
+	fired, err := NewBullet("player", BasicPistol, shared.NewPosition(0, 0), NewDirection(1, 0))
+	require.NoError(t, err)
+
+	// Pistol bullets fly 200 units a second
+	assert.InDelta(t, 20, fired.PositionAt(fired.FiredAt.Add(100*time.Millisecond)).X, 1e-6)
+
+	from, to := fired.FiredAt, fired.FiredAt.Add(100*time.Millisecond)
+	along, hit := fired.HitBetween(from, to, shared.NewPosition(10, 0.4))
+	assert.True(t, hit)
+	assert.InDelta(t, 0.5, along, 1e-6)
+
+	_, hit = fired.HitBetween(from, to, shared.NewPosition(10, 0.6))
+	assert.False(t, hit, "passes beside the target")
+	_, hit = fired.HitBetween(from, to, shared.NewPosition(21, 0))
+	assert.False(t, hit, "not there yet")
+	_, hit = fired.HitBetween(to, to.Add(10*time.Millisecond), shared.NewPosition(21, 0))
+	assert.True(t, hit)
+}
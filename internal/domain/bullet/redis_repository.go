@@ -3,23 +3,30 @@ package bullet
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 
+	"github.com/danghamo/life/internal/domain/search"
 	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/redisx"
 )
 
 // RedisRepository implements Repository using Redis
 type RedisRepository struct {
-	client *redis.Client
+	client  *redis.Client
+	docs    *redisx.Repository[Bullet]
+	indexed chan struct{}
 }
 
 // NewRedisRepository creates a new Redis-based bullet repository
 func NewRedisRepository(client *redis.Client) Repository {
 	repo := &RedisRepository{
-		client: client,
+		client:  client,
+		docs:    redisx.NewRepository(client, "bullet:", redisx.Options[Bullet]{}),
+		indexed: make(chan struct{}),
 	}
 
 	// Initialize JSON search index (non-blocking)
@@ -30,6 +37,7 @@ func NewRedisRepository(client *redis.Client) Repository {
 
 // initializeSearchIndex creates the FT.CREATE index for bullet JSON documents
 func (r *RedisRepository) initializeSearchIndex() {
+	defer close(r.indexed)
 	ctx := context.Background()
 
 	// Drop existing index if it exists (ignore errors)
@@ -57,6 +65,16 @@ func (r *RedisRepository) initializeSearchIndex() {
 	}
 }
 
+// WaitIndexed blocks until the search index is created and has indexed the existing bullets
+func (r *RedisRepository) WaitIndexed(ctx context.Context) error {
+	select {
+	case <-r.indexed:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return search.WaitIndexed(ctx, r.client, "idx:bullet")
+}
+
 // Save saves a bullet to Redis using JSONSet with TTL
 func (r *RedisRepository) Save(ctx context.Context, bullet *Bullet) error {
 	key := r.bulletKey(bullet.ID)
@@ -82,22 +100,10 @@ func (r *RedisRepository) Save(ctx context.Context, bullet *Bullet) error {
 
 // Load loads a bullet by ID using JSONGet
 func (r *RedisRepository) Load(ctx context.Context, bulletID BulletID) (*Bullet, error) {
-	key := r.bulletKey(bulletID)
-
-	result, err := r.client.JSONGet(ctx, key, "$").Result()
+	bullet, err := r.docs.GetByID(ctx, bulletID.String())
 	if err != nil {
-		if err == redis.Nil {
-			return nil, nil // Not found
-		}
 		return nil, fmt.Errorf("failed to load bullet: %w", err)
 	}
-
-	// JSONGet with "$" returns the root object as string
-	bullet := &Bullet{}
-	if err := json.Unmarshal([]byte(result), bullet); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal bullet: %w", err)
-	}
-
 	return bullet, nil
 }
 
@@ -243,39 +249,13 @@ func (r *RedisRepository) loadInAreaFallback(ctx context.Context, topLeft, botto
 	return bulletsInArea, nil
 }
 
-// Delete removes a bullet from the repository
+// Delete removes a bullet from the repository. The search index drops it by itself.
 func (r *RedisRepository) Delete(ctx context.Context, bulletID BulletID) error {
-	key := r.bulletKey(bulletID)
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Load bullet data for index cleanup
-		data := tx.HGetAll(ctx, key)
-		if data.Err() != nil && data.Err() != redis.Nil {
-			return data.Err()
-		}
-
-		if len(data.Val()) == 0 {
-			return nil // Already deleted
-		}
-
-		bullet := &Bullet{}
-		if dataStr, exists := data.Val()["data"]; exists {
-			if err := json.Unmarshal([]byte(dataStr), bullet); err != nil {
-				return err
-			}
-		} else {
-			return nil // No data to clean up
-		}
-
-		// Execute transaction
-		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.Del(ctx, key)
-			// Note: No manual index cleanup needed with Redis JSON search index
-			return nil
-		})
-
-		return err
-	}, key)
+	err := r.docs.Delete(ctx, bulletID.String())
+	if errors.Is(err, redisx.ErrNotFound) {
+		return nil // Already deleted
+	}
+	return err
 }
 
 // DeleteExpired removes all expired bullets
@@ -521,6 +501,46 @@ func (r *RedisPlayerStatsRepository) LoadStatsWithDefaults(ctx context.Context,
 	return stats, nil
 }
 
+// FindOneAndUpdate loads player stats in a WATCH transaction and stores what callback returns,
+// so concurrent shots, reloads and purchases cannot overwrite each other
+func (r *RedisPlayerStatsRepository) FindOneAndUpdate(ctx context.Context, playerID PlayerID, callback func(*PlayerStats) (*PlayerStats, error)) error {
+	key := r.playerStatsKey(playerID)
+
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		current := &PlayerStats{}
+		data, err := tx.HGet(ctx, key, "data").Result()
+		switch {
+		case err == redis.Nil:
+			current = NewPlayerStats(playerID, DefaultWeapon.MagazineSize(), DefaultWeapon)
+		case err != nil:
+			return fmt.Errorf("failed to load player stats: %w", err)
+		default:
+			if err := json.Unmarshal([]byte(data), current); err != nil {
+				return fmt.Errorf("failed to unmarshal player stats: %w", err)
+			}
+		}
+
+		result, err := callback(current)
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			return nil // No changes
+		}
+
+		serialized, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to marshal player stats: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HSet(ctx, key, "data", string(serialized))
+			return nil
+		})
+		return err
+	}, key)
+}
+
 // DeleteStats removes player stats
 func (r *RedisPlayerStatsRepository) DeleteStats(ctx context.Context, playerID PlayerID) error {
 	key := r.playerStatsKey(playerID)
@@ -49,34 +49,45 @@ type Repository interface {
 	
 	// LoadBatch loads multiple bullets by their IDs
 	LoadBatch(ctx context.Context, bulletIDs []BulletID) ([]*Bullet, error)
+
+	// WaitIndexed blocks until the search index is created and has indexed the existing bullets
+	WaitIndexed(ctx context.Context) error
 }
 
 // PlayerStats represents player firing statistics and state
 type PlayerStats struct {
 	PlayerID            PlayerID   `json:"player_id"`
-	AmmoCount          int        `json:"ammo_count"`
+	AmmoCount          int        `json:"ammo_count"`   // Rounds in the loaded magazine
+	ReserveAmmo        int        `json:"reserve_ammo"` // Rounds carried to reload with
 	WeaponType         WeaponType `json:"weapon_type"`
 	LastFireTime       time.Time  `json:"last_fire_time"`
 	FireSessionStarted *time.Time `json:"fire_session_started,omitempty"`
+	ReloadingUntil     *time.Time `json:"reloading_until,omitempty"`
 	UpdatedAt          time.Time  `json:"updated_at"`
 }
 
-// NewPlayerStats creates new player stats
+// NewPlayerStats creates new player stats with the starting reserve ammo of the weapon
 func NewPlayerStats(playerID PlayerID, ammoCount int, weaponType WeaponType) *PlayerStats {
 	return &PlayerStats{
-		PlayerID:   playerID,
-		AmmoCount:  ammoCount,
-		WeaponType: weaponType,
-		UpdatedAt:  time.Now(),
+		PlayerID:    playerID,
+		AmmoCount:   ammoCount,
+		ReserveAmmo: weaponType.StartingReserveAmmo(),
+		WeaponType:  weaponType,
+		UpdatedAt:   time.Now(),
 	}
 }
 
-// CanFire checks if player can fire based on ammo and cooldown
+// CanFire checks if player can fire based on ammo, reloading and cooldown
 func (ps *PlayerStats) CanFire(currentTime time.Time) bool {
 	// Check ammo
 	if ps.AmmoCount <= 0 {
 		return false
 	}
+
+	// Check reload
+	if ps.IsReloading(currentTime) {
+		return false
+	}
 	
 	// Check cooldown
 	cooldownMs := ps.WeaponType.GetCooldownMs()
@@ -89,7 +100,13 @@ func (ps *PlayerStats) CanFire(currentTime time.Time) bool {
 // Fire decrements ammo and updates last fire time
 func (ps *PlayerStats) Fire(fireTime time.Time) error {
 	if ps.AmmoCount <= 0 {
-		return shared.NewDomainError(shared.ErrCodeInsufficientFunds, "No ammo remaining")
+		return shared.NewDomainError(shared.ErrCodeNoAmmo, "No ammo remaining")
+	}
+	if ps.IsReloading(fireTime) {
+		return shared.NewDomainError(shared.ErrCodeReloading, "Weapon is reloading")
+	}
+	if !ps.CanFire(fireTime) {
+		return shared.NewDomainError(shared.ErrCodeCoolingDown, "Weapon is cooling down")
 	}
 	
 	ps.AmmoCount--
@@ -116,12 +133,6 @@ func (ps *PlayerStats) IsFiring() bool {
 	return ps.FireSessionStarted != nil
 }
 
-// ReloadAmmo reloads ammo to full capacity
-func (ps *PlayerStats) ReloadAmmo(maxAmmo int) {
-	ps.AmmoCount = maxAmmo
-	ps.UpdatedAt = time.Now()
-}
-
 
 // PlayerStatsRepository represents the player stats repository interface
 type PlayerStatsRepository interface {
@@ -133,6 +144,11 @@ type PlayerStatsRepository interface {
 	
 	// LoadStatsWithDefaults loads stats or creates default if not found
 	LoadStatsWithDefaults(ctx context.Context, playerID PlayerID, defaultAmmo int, defaultWeapon WeaponType) (*PlayerStats, error)
+
+	// FindOneAndUpdate loads player stats and applies callback for atomic update. Players
+	// without stats start with the default weapon and a full magazine; a nil result leaves
+	// the stats unchanged.
+	FindOneAndUpdate(ctx context.Context, playerID PlayerID, callback func(*PlayerStats) (*PlayerStats, error)) error
 	
 	// DeleteStats removes player stats
 	DeleteStats(ctx context.Context, playerID PlayerID) error
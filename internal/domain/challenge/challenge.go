@@ -0,0 +1,261 @@
+package challenge
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// ActivePerWeek is how many challenges each week's rotation holds
+const ActivePerWeek = 3
+
+// ChallengeID represents a unique challenge identifier
+type ChallengeID string
+
+// String returns string representation
+func (id ChallengeID) String() string {
+	return string(id)
+}
+
+// ObjectiveKind represents what a challenge counts
+type ObjectiveKind string
+
+const (
+	ObjectiveCapture   ObjectiveKind = "capture"    // Captured animals
+	ObjectiveWinBattle ObjectiveKind = "win_battle" // Battles won
+)
+
+// Objective is what a trainer must do to complete a challenge. Progress is only ever counted
+// by the server from domain events, never reported by clients.
+type Objective struct {
+	Kind ObjectiveKind `json:"kind"`
+	// AnimalType restricts captures to one type; empty counts every animal
+	AnimalType animal.AnimalType `json:"animal_type,omitempty"`
+	Count      int               `json:"count"`
+}
+
+// Matches reports whether an occurrence of kind, involving an animal of animalType, counts
+// toward the objective
+func (o Objective) Matches(kind ObjectiveKind, animalType animal.AnimalType) bool {
+	if o.Kind != kind {
+		return false
+	}
+	return o.AnimalType == "" || o.AnimalType == animalType
+}
+
+// Definition is a challenge as defined in the content registry
+type Definition struct {
+	ID          ChallengeID `json:"id"`
+	Name        string      `json:"name"`
+	Description string      `json:"description"`
+	Objective   Objective   `json:"objective"`
+	// PassXP is the battle pass XP granted when the challenge is claimed
+	PassXP int `json:"pass_xp"`
+}
+
+// Week is an ISO week in UTC; challenges rotate when a new one starts
+type Week struct {
+	Start time.Time // Monday 00:00 UTC
+}
+
+// WeekOf returns the week t falls in
+func WeekOf(t time.Time) Week {
+	t = t.UTC()
+	daysSinceMonday := (int(t.Weekday()) + 6) % 7
+	return Week{Start: time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, time.UTC)}
+}
+
+// End returns when the week is over and the next rotation starts
+func (w Week) End() time.Time {
+	return w.Start.AddDate(0, 0, 7)
+}
+
+// Previous returns the week before
+func (w Week) Previous() Week {
+	return Week{Start: w.Start.AddDate(0, 0, -7)}
+}
+
+// String returns the ISO week, e.g. 2026-W42
+func (w Week) String() string {
+	year, week := w.Start.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// Progress is a trainer's progress on the challenges of one week
+type Progress struct {
+	Counts  map[ChallengeID]int  `json:"counts"`
+	Claimed map[ChallengeID]bool `json:"claimed"`
+}
+
+// NewProgress creates empty progress
+func NewProgress() *Progress {
+	return &Progress{
+		Counts:  make(map[ChallengeID]int),
+		Claimed: make(map[ChallengeID]bool),
+	}
+}
+
+// Status is a challenge of the week as a trainer sees it
+type Status struct {
+	Definition
+	Progress  int  `json:"progress"` // Capped at the objective's count
+	Completed bool `json:"completed"`
+	Claimed   bool `json:"claimed"`
+}
+
+// NewStatus returns the status of a challenge given a trainer's progress
+func NewStatus(def *Definition, progress *Progress) Status {
+	count := progress.Counts[def.ID]
+	if count > def.Objective.Count {
+		count = def.Objective.Count
+	}
+	return Status{
+		Definition: *def,
+		Progress:   count,
+		Completed:  count >= def.Objective.Count,
+		Claimed:    progress.Claimed[def.ID],
+	}
+}
+
+// Board is a trainer's challenges of a week
+type Board struct {
+	Week       string    `json:"week"`
+	EndsAt     time.Time `json:"ends_at"`
+	Challenges []Status  `json:"challenges"`
+	PassXP     int       `json:"pass_xp"` // Battle pass XP earned from claims
+}
+
+// ClaimResult is the result of claiming a challenge
+type ClaimResult struct {
+	Challenge Status `json:"challenge"`
+	PassXP    int    `json:"pass_xp"` // Battle pass XP after the claim
+}
+
+// CheckClaimable returns a domain error unless the challenge can be claimed
+func (s Status) CheckClaimable() error {
+	if s.Claimed {
+		return shared.NewDomainErrorf(shared.ErrCodeChallengeClaimed, "Challenge already claimed: %s", s.ID)
+	}
+	if !s.Completed {
+		return shared.NewDomainErrorf(shared.ErrCodeChallengeIncomplete,
+			"Challenge not completed: %s (%d/%d)", s.ID, s.Progress, s.Objective.Count)
+	}
+	return nil
+}
+
+// Registry holds the known challenges
+type Registry struct {
+	definitions map[ChallengeID]*Definition
+}
+
+// NewRegistry creates a registry from the given definitions
+func NewRegistry(definitions ...*Definition) *Registry {
+	registry := &Registry{
+		definitions: make(map[ChallengeID]*Definition),
+	}
+	for _, def := range definitions {
+		registry.definitions[def.ID] = def
+	}
+	return registry
+}
+
+// NewDefaultRegistry creates a registry with the built-in challenges
+func NewDefaultRegistry() *Registry {
+	return NewRegistry(DefaultDefinitions()...)
+}
+
+// Get returns a challenge by ID
+func (r *Registry) Get(id ChallengeID) (*Definition, error) {
+	def, exists := r.definitions[id]
+	if !exists {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeUnknownChallenge, "Unknown challenge: %s", id)
+	}
+	return def, nil
+}
+
+// List returns all challenges sorted by ID
+func (r *Registry) List() []*Definition {
+	definitions := make([]*Definition, 0, len(r.definitions))
+	for _, def := range r.definitions {
+		definitions = append(definitions, def)
+	}
+	sort.Slice(definitions, func(i, j int) bool {
+		return definitions[i].ID < definitions[j].ID
+	})
+	return definitions
+}
+
+// Rotate picks the n challenges of a week. The pick only depends on the week and the
+// registry, so every server instance agrees on it.
+func (r *Registry) Rotate(week Week, n int) []ChallengeID {
+	definitions := r.List()
+
+	h := fnv.New64a()
+	h.Write([]byte(week.String()))
+	rng := rand.New(rand.NewSource(int64(h.Sum64())))
+	rng.Shuffle(len(definitions), func(i, j int) {
+		definitions[i], definitions[j] = definitions[j], definitions[i]
+	})
+
+	if n > len(definitions) {
+		n = len(definitions)
+	}
+	ids := make([]ChallengeID, n)
+	for i := range ids {
+		ids[i] = definitions[i].ID
+	}
+	return ids
+}
+
+// DefaultDefinitions returns the built-in challenges
+func DefaultDefinitions() []*Definition {
+	return []*Definition{
+		{
+			ID:          "capture_cheetahs",
+			Name:        "Fast Hands",
+			Description: "Capture 5 cheetahs",
+			Objective:   Objective{Kind: ObjectiveCapture, AnimalType: animal.Cheetah, Count: 5},
+			PassXP:      300,
+		},
+		{
+			ID:          "capture_lions",
+			Name:        "Pride Hunter",
+			Description: "Capture 5 lions",
+			Objective:   Objective{Kind: ObjectiveCapture, AnimalType: animal.Lion, Count: 5},
+			PassXP:      250,
+		},
+		{
+			ID:          "capture_elephants",
+			Name:        "Heavy Lifting",
+			Description: "Capture 3 elephants",
+			Objective:   Objective{Kind: ObjectiveCapture, AnimalType: animal.Elephant, Count: 3},
+			PassXP:      250,
+		},
+		{
+			ID:          "capture_any",
+			Name:        "Collector",
+			Description: "Capture 15 animals",
+			Objective:   Objective{Kind: ObjectiveCapture, Count: 15},
+			PassXP:      200,
+		},
+		{
+			ID:          "win_battles",
+			Name:        "Contender",
+			Description: "Win 3 battles",
+			Objective:   Objective{Kind: ObjectiveWinBattle, Count: 3},
+			PassXP:      200,
+		},
+		{
+			ID:          "win_battles_many",
+			Name:        "Champion",
+			Description: "Win 10 battles",
+			Objective:   Objective{Kind: ObjectiveWinBattle, Count: 10},
+			PassXP:      400,
+		},
+	}
+}
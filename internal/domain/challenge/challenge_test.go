@@ -0,0 +1,81 @@
+package challenge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestWeekOf(t *testing.T) {
+	// Thursday 2026-10-15 is in ISO week 42, which starts on Monday 2026-10-12
+	week := WeekOf(time.Date(2026, 10, 15, 18, 30, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC), week.Start)
+	assert.Equal(t, time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), week.End())
+	assert.Equal(t, "2026-W42", week.String())
+	assert.Equal(t, "2026-W41", week.Previous().String())
+
+	// Sunday belongs to the week that started the Monday before
+	assert.Equal(t, week, WeekOf(time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC)))
+	assert.Equal(t, week, WeekOf(week.Start))
+}
+
+func TestRegistry_Rotate(t *testing.T) {
+	registry := NewDefaultRegistry()
+	week := WeekOf(time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC))
+
+	ids := registry.Rotate(week, ActivePerWeek)
+	require.Len(t, ids, ActivePerWeek)
+	assert.Equal(t, ids, registry.Rotate(week, ActivePerWeek), "rotation must be deterministic")
+
+	seen := make(map[ChallengeID]bool)
+	for _, id := range ids {
+		_, err := registry.Get(id)
+		require.NoError(t, err)
+		assert.False(t, seen[id], "challenge picked twice: %s", id)
+		seen[id] = true
+	}
+
+	assert.Len(t, registry.Rotate(week, 100), len(registry.List()))
+}
+
+func TestRegistry_Get_Unknown(t *testing.T) {
+	_, err := NewDefaultRegistry().Get("nope")
+	code, ok := shared.DomainErrorCode(err)
+	require.True(t, ok)
+	assert.Equal(t, shared.ErrCodeUnknownChallenge, code)
+}
+
+func TestObjective_Matches(t *testing.T) {
+	cheetahs := Objective{Kind: ObjectiveCapture, AnimalType: animal.Cheetah, Count: 5}
+	assert.True(t, cheetahs.Matches(ObjectiveCapture, animal.Cheetah))
+	assert.False(t, cheetahs.Matches(ObjectiveCapture, animal.Lion))
+	assert.False(t, cheetahs.Matches(ObjectiveWinBattle, animal.Cheetah))
+
+	anyAnimal := Objective{Kind: ObjectiveCapture, Count: 10}
+	assert.True(t, anyAnimal.Matches(ObjectiveCapture, animal.Lion))
+}
+
+func TestStatus_CheckClaimable(t *testing.T) {
+	def := &Definition{ID: "wins", Objective: Objective{Kind: ObjectiveWinBattle, Count: 3}, PassXP: 100}
+	progress := NewProgress()
+
+	progress.Counts["wins"] = 2
+	status := NewStatus(def, progress)
+	assert.False(t, status.Completed)
+	code, _ := shared.DomainErrorCode(status.CheckClaimable())
+	assert.Equal(t, shared.ErrCodeChallengeIncomplete, code)
+
+	progress.Counts["wins"] = 7
+	status = NewStatus(def, progress)
+	assert.Equal(t, 3, status.Progress, "progress is capped at the objective")
+	assert.NoError(t, status.CheckClaimable())
+
+	progress.Claimed["wins"] = true
+	code, _ = shared.DomainErrorCode(NewStatus(def, progress).CheckClaimable())
+	assert.Equal(t, shared.ErrCodeChallengeClaimed, code)
+}
@@ -0,0 +1,219 @@
+package challenge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+const (
+	rotationKeyPrefix = "challenge:rotation:" // Challenge IDs of a week, JSON
+	progressKeyPrefix = "challenge:progress:" // Hash of challenge ID to count per week and trainer
+	claimedKeyPrefix  = "challenge:claimed:"  // Set of claimed challenge IDs per week and trainer
+	eventKeyPrefix    = "challenge:event:"    // Events already counted
+	passKeyPrefix     = "challenge:pass:"     // Battle pass XP per trainer
+
+	// weekRetention is how long a week's rotation and progress are kept after it ended, so
+	// events processed late and the last claims still find them
+	weekRetention = 7 * 24 * time.Hour
+	// eventRetention is how long counted events are remembered to drop redeliveries
+	eventRetention = 24 * time.Hour
+)
+
+// recordEvent marks the event KEYS[1] counted for ARGV[1] seconds and increments the fields
+// ARGV[3..] of the progress hash KEYS[2], which expires in ARGV[2] seconds. Returns the
+// counts, or nil if the event was counted before.
+var recordEvent = redis.NewScript(`
+if not redis.call('SET', KEYS[1], '1', 'NX', 'EX', ARGV[1]) then
+	return false
+end
+local counts = {}
+for i = 3, #ARGV do
+	counts[#counts + 1] = redis.call('HINCRBY', KEYS[2], ARGV[i], 1)
+end
+redis.call('EXPIRE', KEYS[2], ARGV[2])
+return counts
+`)
+
+// claimChallenge adds ARGV[1] to the claimed set KEYS[1], which expires in ARGV[3] seconds,
+// and adds ARGV[2] to the pass XP KEYS[2]. Returns the pass XP, or -1 if it was claimed before.
+var claimChallenge = redis.NewScript(`
+if redis.call('SADD', KEYS[1], ARGV[1]) == 0 then
+	return -1
+end
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+return redis.call('INCRBY', KEYS[2], ARGV[2])
+`)
+
+// RedisRepository implements Repository with per-week keys that expire after the week
+type RedisRepository struct {
+	client *redis.Client
+}
+
+// NewRedisRepository creates a new Redis-based challenge repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+	}
+}
+
+func rotationKey(week Week) string {
+	return rotationKeyPrefix + week.String()
+}
+
+func progressKey(week Week, userID trainer.UserID) string {
+	return progressKeyPrefix + week.String() + ":" + userID.String()
+}
+
+func claimedKey(week Week, userID trainer.UserID) string {
+	return claimedKeyPrefix + week.String() + ":" + userID.String()
+}
+
+func passKey(userID trainer.UserID) string {
+	return passKeyPrefix + userID.String()
+}
+
+// weekTTL returns how long keys of a week are kept from now
+func weekTTL(week Week) time.Duration {
+	ttl := time.Until(week.End().Add(weekRetention))
+	if ttl < time.Second {
+		ttl = time.Second
+	}
+	return ttl
+}
+
+// Rotation reads the week's rotation
+func (r *RedisRepository) Rotation(ctx context.Context, week Week) ([]ChallengeID, error) {
+	data, err := r.client.Get(ctx, rotationKey(week)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenge rotation: %w", err)
+	}
+
+	var ids []ChallengeID
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, fmt.Errorf("failed to deserialize challenge rotation: %w", err)
+	}
+	return ids, nil
+}
+
+// SetRotation stores the rotation with SETNX, so the first instance to rotate the week wins
+func (r *RedisRepository) SetRotation(ctx context.Context, week Week, ids []ChallengeID) ([]ChallengeID, error) {
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize challenge rotation: %w", err)
+	}
+
+	stored, err := r.client.SetNX(ctx, rotationKey(week), data, weekTTL(week)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set challenge rotation: %w", err)
+	}
+	if stored {
+		return ids, nil
+	}
+	return r.Rotation(ctx, week)
+}
+
+// Record counts the event with a script, so redeliveries are dropped atomically
+func (r *RedisRepository) Record(ctx context.Context, week Week, userID trainer.UserID, eventID string, ids []ChallengeID) (map[ChallengeID]int, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, 0, len(ids)+2)
+	args = append(args, int(eventRetention.Seconds()), int(weekTTL(week).Seconds()))
+	for _, id := range ids {
+		args = append(args, id.String())
+	}
+
+	counts, err := recordEvent.Run(ctx, r.client,
+		[]string{eventKeyPrefix + eventID, progressKey(week, userID)}, args...).Int64Slice()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to record challenge progress: %w", err)
+	}
+
+	result := make(map[ChallengeID]int, len(ids))
+	for i, id := range ids {
+		result[id] = int(counts[i])
+	}
+	return result, nil
+}
+
+// Progress reads the counts and claimed challenges in one pipeline
+func (r *RedisRepository) Progress(ctx context.Context, week Week, userID trainer.UserID) (*Progress, error) {
+	var counts *redis.MapStringStringCmd
+	var claimed *redis.StringSliceCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		counts = pipe.HGetAll(ctx, progressKey(week, userID))
+		claimed = pipe.SMembers(ctx, claimedKey(week, userID))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get challenge progress: %w", err)
+	}
+
+	progress := NewProgress()
+	for id, value := range counts.Val() {
+		if count, err := strconv.Atoi(value); err == nil {
+			progress.Counts[ChallengeID(id)] = count
+		}
+	}
+	for _, id := range claimed.Val() {
+		progress.Claimed[ChallengeID(id)] = true
+	}
+	return progress, nil
+}
+
+// Claim marks the challenge claimed and grants the XP with a script, so a challenge is only
+// ever rewarded once
+func (r *RedisRepository) Claim(ctx context.Context, week Week, userID trainer.UserID, id ChallengeID, xp int) (int, error) {
+	total, err := claimChallenge.Run(ctx, r.client,
+		[]string{claimedKey(week, userID), passKey(userID)},
+		id.String(), xp, int(weekTTL(week).Seconds())).Int()
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim challenge: %w", err)
+	}
+	if total < 0 {
+		return 0, shared.NewDomainErrorf(shared.ErrCodeChallengeClaimed, "Challenge already claimed: %s", id)
+	}
+	return total, nil
+}
+
+// PassXP reads the trainer's pass XP
+func (r *RedisRepository) PassXP(ctx context.Context, userID trainer.UserID) (int, error) {
+	xp, err := r.client.Get(ctx, passKey(userID)).Int()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pass XP: %w", err)
+	}
+	return xp, nil
+}
+
+// DeleteUser removes the pass XP and the progress of the current and previous week; older
+// progress has expired
+func (r *RedisRepository) DeleteUser(ctx context.Context, userID trainer.UserID) error {
+	week := WeekOf(time.Now())
+	keys := []string{passKey(userID)}
+	for _, w := range []Week{week, week.Previous()} {
+		keys = append(keys, progressKey(w, userID), claimedKey(w, userID))
+	}
+
+	if err := r.client.Del(ctx, keys...).Err(); err != nil {
+		return fmt.Errorf("failed to delete challenge progress: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+package challenge
+
+import (
+	"context"
+
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// Repository defines the interface for challenge rotation and progress persistence
+type Repository interface {
+	// Rotation returns the challenges of a week, or nil if the week wasn't rotated yet
+	Rotation(ctx context.Context, week Week) ([]ChallengeID, error)
+
+	// SetRotation stores the challenges of a week unless it has some, and returns the
+	// challenges the week has then
+	SetRotation(ctx context.Context, week Week, ids []ChallengeID) ([]ChallengeID, error)
+
+	// Record counts one occurrence toward the given challenges of a trainer and returns the
+	// counts after it. An event is only counted once, so redelivered events return nil.
+	Record(ctx context.Context, week Week, userID trainer.UserID, eventID string, ids []ChallengeID) (map[ChallengeID]int, error)
+
+	// Progress returns a trainer's progress on the challenges of a week
+	Progress(ctx context.Context, week Week, userID trainer.UserID) (*Progress, error)
+
+	// Claim marks a challenge claimed and adds xp to the trainer's battle pass XP, failing with
+	// ErrCodeChallengeClaimed if it was claimed before. Returns the pass XP after the claim.
+	Claim(ctx context.Context, week Week, userID trainer.UserID, id ChallengeID, xp int) (int, error)
+
+	// PassXP returns a trainer's battle pass XP
+	PassXP(ctx context.Context, userID trainer.UserID) (int, error)
+
+	// DeleteUser removes a trainer's progress and pass XP
+	DeleteUser(ctx context.Context, userID trainer.UserID) error
+}
@@ -0,0 +1,240 @@
+package character
+
+import (
+	"slices"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+const (
+	// DefaultSlots is how many trainers a user can have, their first one included, before
+	// buying more slots
+	DefaultSlots = 4
+
+	// MaxSlots caps the slots a user can own, bought ones included
+	MaxSlots = 8
+
+	// SlotPrice is the gems one more character slot costs
+	SlotPrice = 500
+
+	// DeletionGracePeriod is how long a deleted character can be restored before it is purged
+	DeletionGracePeriod = 7 * 24 * time.Hour
+)
+
+// Appearance holds the looks chosen for a new character; empty fields keep the defaults
+type Appearance struct {
+	Color string `json:"color,omitempty"` // One of the trainer palettes
+}
+
+// Validate checks the appearance can be applied to a trainer
+func (a Appearance) Validate() error {
+	if a.Color != "" && !trainer.IsPaletteColor(a.Color) {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown color: %s", a.Color)
+	}
+	return nil
+}
+
+// Apply gives a trainer the appearance
+func (a Appearance) Apply(t *trainer.Trainer) error {
+	if a.Color == "" {
+		return nil
+	}
+	return t.SetColor(a.Color)
+}
+
+// Roster lists the trainers a user plays. The first trainer has the user's own ID and exists
+// from the first sign-in; the others are created on demand. Characters keep their own
+// position, inventory and progress, while the vault, money, gems and slots belong to the
+// user, so the roster is where account-wide changes are made atomically.
+type Roster struct {
+	UserID     trainer.UserID               `json:"user_id"`
+	Alts       []trainer.UserID             `json:"alts"`                  // Additional characters in creation order
+	Created    int                          `json:"created"`               // Additional characters ever created, numbering the next one
+	Active     trainer.UserID               `json:"active,omitempty"`      // Character last selected, the first one when empty
+	ExtraSlots int                          `json:"extra_slots,omitempty"` // Slots bought on top of DefaultSlots
+	Gems       int                          `json:"gems"`                  // Premium currency
+	Deleting   map[trainer.UserID]time.Time `json:"deleting,omitempty"`    // Characters pending deletion and when they are purged
+	UpdatedAt  time.Time                    `json:"updated_at"`
+}
+
+// NewRoster creates the roster of a user who only has their first character
+func NewRoster(userID trainer.UserID) *Roster {
+	return &Roster{
+		UserID: userID,
+		Alts:   make([]trainer.UserID, 0),
+	}
+}
+
+// Characters returns the IDs of every character, the first one first
+func (r *Roster) Characters() []trainer.UserID {
+	return append([]trainer.UserID{r.UserID}, r.Alts...)
+}
+
+// Has reports whether a trainer is one of the user's characters
+func (r *Roster) Has(id trainer.UserID) bool {
+	return id == r.UserID || slices.Contains(r.Alts, id)
+}
+
+// ActiveCharacter returns the character new sessions play
+func (r *Roster) ActiveCharacter() trainer.UserID {
+	if r.Active == "" {
+		return r.UserID
+	}
+	return r.Active
+}
+
+// Slots returns how many characters the user can have
+func (r *Roster) Slots() int {
+	return DefaultSlots + r.ExtraSlots
+}
+
+// AddCharacter reserves the ID of a new character. Characters pending deletion keep their
+// slot, so they can always be restored.
+func (r *Roster) AddCharacter() (trainer.UserID, error) {
+	if len(r.Alts)+1 >= r.Slots() {
+		return "", shared.NewDomainErrorf(shared.ErrCodeCharacterLimit, "Cannot have more than %d characters", r.Slots())
+	}
+
+	r.Created++
+	id := trainer.CharacterID(r.UserID, r.Created)
+	r.Alts = append(r.Alts, id)
+	r.UpdatedAt = time.Now()
+	return id, nil
+}
+
+// RemoveCharacter drops an additional character; the first one can't be removed
+func (r *Roster) RemoveCharacter(id trainer.UserID) {
+	r.Alts = slices.DeleteFunc(r.Alts, func(alt trainer.UserID) bool {
+		return alt == id
+	})
+	delete(r.Deleting, id)
+	if r.Active == id {
+		r.Active = ""
+	}
+	r.UpdatedAt = time.Now()
+}
+
+// Select makes a character the active one and carries the user's money over to it from the
+// other characters. characters are the user's trainers.
+func (r *Roster) Select(id trainer.UserID, characters []*trainer.Trainer) (*trainer.Trainer, error) {
+	if !r.Has(id) {
+		return nil, shared.ErrNotFound("Character")
+	}
+	if _, ok := r.Deleting[id]; ok {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidOperation, "Character is pending deletion; restore it first")
+	}
+
+	selected, err := collectMoney(id, characters)
+	if err != nil {
+		return nil, err
+	}
+
+	r.Active = id
+	r.UpdatedAt = time.Now()
+	return selected, nil
+}
+
+// Delete schedules an additional character for deletion after DeletionGracePeriod and
+// returns when it is purged. Its money goes to the first character, which new sessions play
+// if the deleted character was the active one. Deleting a character again keeps its date.
+func (r *Roster) Delete(id trainer.UserID, characters []*trainer.Trainer, now time.Time) (time.Time, error) {
+	if id == r.UserID {
+		return time.Time{}, shared.NewDomainError(shared.ErrCodeInvalidOperation, "The first character can't be deleted")
+	}
+	if !r.Has(id) {
+		return time.Time{}, shared.ErrNotFound("Character")
+	}
+	if purgeAt, ok := r.Deleting[id]; ok {
+		return purgeAt, nil
+	}
+
+	if _, err := collectMoney(r.UserID, characters); err != nil {
+		return time.Time{}, err
+	}
+
+	purgeAt := now.Add(DeletionGracePeriod)
+	if r.Deleting == nil {
+		r.Deleting = make(map[trainer.UserID]time.Time)
+	}
+	r.Deleting[id] = purgeAt
+	if r.Active == id {
+		r.Active = ""
+	}
+	r.UpdatedAt = now
+	return purgeAt, nil
+}
+
+// Restore cancels the deletion of a character while its grace period lasts
+func (r *Roster) Restore(id trainer.UserID, now time.Time) error {
+	purgeAt, ok := r.Deleting[id]
+	if !ok {
+		return shared.ErrNotFound("Character pending deletion")
+	}
+	if !now.Before(purgeAt) {
+		return shared.NewDomainError(shared.ErrCodeInvalidOperation, "The grace period is over; the character is being deleted")
+	}
+
+	delete(r.Deleting, id)
+	r.UpdatedAt = now
+	return nil
+}
+
+// PurgeDue reports whether a character's grace period is over, so its data can go
+func (r *Roster) PurgeDue(id trainer.UserID, now time.Time) bool {
+	purgeAt, ok := r.Deleting[id]
+	return ok && !now.Before(purgeAt)
+}
+
+// BuySlot spends SlotPrice gems on one more character slot
+func (r *Roster) BuySlot() error {
+	if r.Slots() >= MaxSlots {
+		return shared.NewDomainErrorf(shared.ErrCodeCharacterLimit, "Cannot own more than %d character slots", MaxSlots)
+	}
+	if r.Gems < SlotPrice {
+		return shared.NewDomainErrorf(shared.ErrCodeInsufficientFunds, "Need %d gems, have %d", SlotPrice, r.Gems)
+	}
+
+	r.Gems -= SlotPrice
+	r.ExtraSlots++
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// AddGems credits the user with premium currency
+func (r *Roster) AddGems(amount int) error {
+	if amount <= 0 {
+		return shared.NewDomainError(shared.ErrCodeInvalidAmount, "Gems must be positive")
+	}
+
+	r.Gems += amount
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// collectMoney carries the money of every character over to the one with id, which covers
+// whatever the others earned while they were played on a session started before
+func collectMoney(id trainer.UserID, characters []*trainer.Trainer) (*trainer.Trainer, error) {
+	i := slices.IndexFunc(characters, func(t *trainer.Trainer) bool {
+		return t.ID == id
+	})
+	if i < 0 {
+		return nil, shared.ErrNotFound("Character")
+	}
+	collector := characters[i]
+
+	for _, other := range characters {
+		amount := other.Money.Amount()
+		if other == collector || amount == 0 {
+			continue
+		}
+		if err := other.SpendMoney(amount); err != nil {
+			return nil, err
+		}
+		if err := collector.EarnMoney(amount); err != nil {
+			return nil, err
+		}
+	}
+	return collector, nil
+}
@@ -0,0 +1,118 @@
+package character
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+func newTrainer(t *testing.T, id trainer.UserID, nickname string, money int) *trainer.Trainer {
+	t.Helper()
+	tr, err := trainer.NewTrainer(id, nickname)
+	require.NoError(t, err)
+	require.NoError(t, tr.SpendMoney(tr.Money.Amount()-money))
+	return tr
+}
+
+func TestRoster_AddCharacter(t *testing.T) {
+	roster := NewRoster("user")
+	assert.Equal(t, trainer.UserID("user"), roster.ActiveCharacter())
+
+	for i := 1; i < DefaultSlots; i++ {
+		id, err := roster.AddCharacter()
+		require.NoError(t, err)
+		assert.Equal(t, trainer.UserID("user"), id.AccountID())
+	}
+	assert.Len(t, roster.Characters(), DefaultSlots)
+
+	_, err := roster.AddCharacter()
+	code, ok := shared.DomainErrorCode(err)
+	require.True(t, ok)
+	assert.Equal(t, shared.ErrCodeCharacterLimit, code)
+
+	// Numbers aren't reused, so a removed character's data can't leak into a new one
+	roster.RemoveCharacter(roster.Alts[0])
+	id, err := roster.AddCharacter()
+	require.NoError(t, err)
+	assert.Equal(t, trainer.CharacterID("user", DefaultSlots), id)
+}
+
+func TestRoster_SelectCarriesMoney(t *testing.T) {
+	roster := NewRoster("user")
+	alt, err := roster.AddCharacter()
+	require.NoError(t, err)
+
+	first := newTrainer(t, "user", "First", 700)
+	second := newTrainer(t, alt, "Second", 50)
+
+	selected, err := roster.Select(alt, []*trainer.Trainer{first, second})
+	require.NoError(t, err)
+	assert.Same(t, second, selected)
+	assert.Equal(t, 750, second.Money.Amount())
+	assert.Equal(t, 0, first.Money.Amount())
+	assert.Equal(t, alt, roster.ActiveCharacter())
+
+	_, err = roster.Select("someone-else", []*trainer.Trainer{first, second})
+	code, ok := shared.DomainErrorCode(err)
+	require.True(t, ok)
+	assert.Equal(t, shared.ErrCodeNotFound, code)
+}
+
+func TestRoster_DeleteAndRestore(t *testing.T) {
+	roster := NewRoster("user")
+	alt, err := roster.AddCharacter()
+	require.NoError(t, err)
+
+	first := newTrainer(t, "user", "First", 0)
+	second := newTrainer(t, alt, "Second", 300)
+	_, err = roster.Select(alt, []*trainer.Trainer{first, second})
+	require.NoError(t, err)
+
+	_, err = roster.Delete("user", []*trainer.Trainer{first, second}, time.Now())
+	code, _ := shared.DomainErrorCode(err)
+	assert.Equal(t, shared.ErrCodeInvalidOperation, code)
+
+	now := time.Now()
+	purgeAt, err := roster.Delete(alt, []*trainer.Trainer{first, second}, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(DeletionGracePeriod), purgeAt)
+	assert.Equal(t, 300, first.Money.Amount(), "money goes to the first character")
+	assert.Equal(t, trainer.UserID("user"), roster.ActiveCharacter())
+
+	_, err = roster.Select(alt, []*trainer.Trainer{first, second})
+	code, _ = shared.DomainErrorCode(err)
+	assert.Equal(t, shared.ErrCodeInvalidOperation, code)
+
+	assert.False(t, roster.PurgeDue(alt, now))
+	assert.True(t, roster.PurgeDue(alt, purgeAt))
+	require.Error(t, roster.Restore(alt, purgeAt), "grace period is over")
+
+	require.NoError(t, roster.Restore(alt, now))
+	assert.False(t, roster.PurgeDue(alt, purgeAt))
+	_, err = roster.Select(alt, []*trainer.Trainer{first, second})
+	require.NoError(t, err)
+}
+
+func TestRoster_BuySlot(t *testing.T) {
+	roster := NewRoster("user")
+	code, _ := shared.DomainErrorCode(roster.BuySlot())
+	assert.Equal(t, shared.ErrCodeInsufficientFunds, code)
+
+	require.NoError(t, roster.AddGems(SlotPrice*MaxSlots))
+	for roster.Slots() < MaxSlots {
+		require.NoError(t, roster.BuySlot())
+	}
+	code, _ = shared.DomainErrorCode(roster.BuySlot())
+	assert.Equal(t, shared.ErrCodeCharacterLimit, code)
+	assert.Equal(t, SlotPrice*DefaultSlots, roster.Gems)
+
+	for i := 1; i < MaxSlots; i++ {
+		_, err := roster.AddCharacter()
+		require.NoError(t, err)
+	}
+}
@@ -0,0 +1,161 @@
+package character
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// RedisRepository implements Repository with a JSON value per user. Transfers also read and
+// write the trainer RedisJSON documents of the user's characters, so money moves between
+// them atomically.
+type RedisRepository struct {
+	client   *redis.Client
+	codec    redisx.StringCodec[Roster]
+	trainers redisx.JSONCodec[trainer.Trainer]
+	docs     *redisx.Repository[Roster]
+}
+
+// NewRedisRepository creates a new Redis-based character roster repository
+func NewRedisRepository(client *redis.Client) Repository {
+	r := &RedisRepository{
+		client: client,
+	}
+	r.docs = redisx.NewRepository(client, "characters:", redisx.Options[Roster]{Codec: r.codec})
+	return r
+}
+
+// Get retrieves a user's roster
+func (r *RedisRepository) Get(ctx context.Context, userID trainer.UserID) (*Roster, error) {
+	roster, err := r.docs.GetByID(ctx, userID.String())
+	if err != nil || roster != nil {
+		return roster, err
+	}
+	return NewRoster(userID), nil
+}
+
+// FindOneAndUpdate implements IoC pattern for update operations
+func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, userID trainer.UserID, callback func(*Roster) error) error {
+	return r.docs.FindOneAndUpsert(ctx, userID.String(), func(roster *Roster) (*Roster, error) {
+		if roster == nil {
+			roster = NewRoster(userID)
+		}
+
+		if err := callback(roster); err != nil {
+			return nil, err
+		}
+		return roster, nil
+	})
+}
+
+// Transfer implements IoC pattern across the roster and trainers of a user. The roster is
+// watched along with the trainers it listed when the transfer started, so a character created
+// meanwhile fails the transaction instead of being missed. redisx.Repository only watches one
+// document, so it reads and writes them itself.
+func (r *RedisRepository) Transfer(ctx context.Context, userID trainer.UserID, callback func(*Roster, []*trainer.Trainer) error) error {
+	listed, err := r.Get(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	key := r.docs.Key(userID.String())
+	keys := []string{key}
+	for _, id := range listed.Characters() {
+		keys = append(keys, r.trainerKey(id))
+	}
+
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		roster, err := r.load(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		if !slices.Equal(roster.Characters(), listed.Characters()) {
+			return redis.TxFailedErr
+		}
+
+		characters := make([]*trainer.Trainer, 0, len(keys)-1)
+		for _, id := range roster.Characters() {
+			t, err := r.loadTrainer(ctx, tx, id)
+			if err != nil {
+				return err
+			}
+			if t != nil {
+				characters = append(characters, t)
+			}
+		}
+
+		// Execute callback
+		if err := callback(roster, characters); err != nil {
+			return err
+		}
+
+		// Serialize everything
+		data, err := r.codec.Encode(roster)
+		if err != nil {
+			return fmt.Errorf("failed to marshal character roster: %w", err)
+		}
+
+		documents := make(map[string][]byte, len(characters))
+		for _, t := range characters {
+			trainerBytes, err := r.trainers.Encode(t)
+			if err != nil {
+				return fmt.Errorf("failed to serialize trainer: %w", err)
+			}
+			documents[r.trainerKey(t.ID)] = trainerBytes
+		}
+
+		// Execute transaction
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			r.codec.Write(ctx, pipe, key, data)
+			for trainerKey, document := range documents {
+				r.trainers.Write(ctx, pipe, trainerKey, document)
+			}
+			return nil
+		})
+		return err
+	}, keys...)
+}
+
+// DeleteUser removes a user's roster
+func (r *RedisRepository) DeleteUser(ctx context.Context, userID trainer.UserID) error {
+	if err := r.client.Del(ctx, r.docs.Key(userID.String())).Err(); err != nil {
+		return fmt.Errorf("failed to delete character roster: %w", err)
+	}
+	return nil
+}
+
+// load reads a user's roster inside a transaction
+func (r *RedisRepository) load(ctx context.Context, cmd redis.Cmdable, userID trainer.UserID) (*Roster, error) {
+	data, err := r.codec.Read(ctx, cmd, r.docs.Key(userID.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get character roster: %w", err)
+	}
+	if data == nil {
+		return NewRoster(userID), nil
+	}
+	return r.codec.Decode(data)
+}
+
+// loadTrainer reads a character's trainer, or nil if it doesn't exist
+func (r *RedisRepository) loadTrainer(ctx context.Context, cmd redis.Cmdable, id trainer.UserID) (*trainer.Trainer, error) {
+	data, err := r.trainers.Read(ctx, cmd, r.trainerKey(id))
+	if err != nil || data == nil {
+		return nil, err
+	}
+
+	t, err := r.trainers.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize trainer: %w", err)
+	}
+	return t, nil
+}
+
+// trainerKey returns the key of a character's trainer document
+func (r *RedisRepository) trainerKey(id trainer.UserID) string {
+	return fmt.Sprintf("trainer:%s", id.String())
+}
@@ -0,0 +1,23 @@
+package character
+
+import (
+	"context"
+
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// Repository defines the interface for character roster persistence operations with IoC pattern
+type Repository interface {
+	// Get retrieves a user's roster, returning one with only the first character if none exists yet
+	Get(ctx context.Context, userID trainer.UserID) (*Roster, error)
+
+	// FindOneAndUpdate loads a user's roster and applies callback for atomic update
+	FindOneAndUpdate(ctx context.Context, userID trainer.UserID, callback func(*Roster) error) error
+
+	// Transfer loads a user's roster along with the trainers of their characters and applies
+	// callback, saving them all atomically. Characters whose trainer doesn't exist are left out.
+	Transfer(ctx context.Context, userID trainer.UserID, callback func(*Roster, []*trainer.Trainer) error) error
+
+	// DeleteUser removes a user's roster; their trainers are left alone
+	DeleteUser(ctx context.Context, userID trainer.UserID) error
+}
@@ -0,0 +1,97 @@
+package chat
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+const (
+	// MaxLength is the longest message in characters
+	MaxLength = 200
+	// NearbyRadius is how far in world units nearby messages carry
+	NearbyRadius = 15.0
+	// FloodWindow is the period flood protection counts messages over
+	FloodWindow = 10 * time.Second
+	// FloodLimit is how many messages a player may send per FloodWindow
+	FloodLimit = 5
+)
+
+// Channel is who a message reaches
+type Channel string
+
+const (
+	ChannelGlobal  Channel = "global"  // Every connected player
+	ChannelNearby  Channel = "nearby"  // Players within NearbyRadius of the sender
+	ChannelWhisper Channel = "whisper" // One player
+)
+
+// Message is a chat message as players receive it
+type Message struct {
+	ID       string           `json:"id"`
+	Channel  Channel          `json:"channel"`
+	From     string           `json:"from"`         // Sender's nickname
+	To       string           `json:"to,omitempty"` // Recipient's nickname of a whisper
+	Text     string           `json:"text"`
+	Position *shared.Position `json:"position,omitempty"` // Where a nearby message was said
+	SentAt   time.Time        `json:"sent_at"`
+}
+
+// NewMessage creates a message on a channel, validating its text
+func NewMessage(channel Channel, from, text string) (*Message, error) {
+	text, err := NormalizeText(text)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Message{
+		ID:      shared.NewID().String(),
+		Channel: channel,
+		From:    from,
+		Text:    text,
+		SentAt:  time.Now(),
+	}, nil
+}
+
+// NormalizeText trims a message and checks its length, replacing control characters such as
+// line breaks with spaces
+func NormalizeText(text string) (string, error) {
+	text = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, text))
+
+	if text == "" {
+		return "", shared.NewDomainError(shared.ErrCodeInvalidInput, "Message cannot be empty")
+	}
+	if !utf8.ValidString(text) {
+		return "", shared.NewDomainError(shared.ErrCodeInvalidInput, "Message must be valid UTF-8")
+	}
+	if utf8.RuneCountInString(text) > MaxLength {
+		return "", shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Message cannot be longer than %d characters", MaxLength)
+	}
+
+	return text, nil
+}
+
+// FloodStatus is a player's recent sending, as recorded before their next message
+type FloodStatus struct {
+	Recent   int  // Messages sent in the current window, including this one
+	Repeated bool // Same text as the previous message within the window
+}
+
+// Check refuses a message that floods the chat
+func (s FloodStatus) Check() error {
+	if s.Recent > FloodLimit {
+		return shared.NewDomainErrorf(shared.ErrCodeChatFlood, "Too many messages, wait %s", FloodWindow)
+	}
+	if s.Repeated {
+		return shared.NewDomainError(shared.ErrCodeChatFlood, "Message repeats the previous one")
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/shared/sharedtest"
+)
+
+func TestNormalizeText(t *testing.T) {
+	text, err := NormalizeText("  hello\nthere\t ")
+	require.NoError(t, err)
+	assert.Equal(t, "hello there", text)
+
+	_, err = NormalizeText(" \n ")
+	assert.Equal(t, shared.ErrCodeInvalidInput, sharedtest.ErrorCode(t, err))
+
+	_, err = NormalizeText(strings.Repeat("가", MaxLength))
+	assert.NoError(t, err)
+
+	_, err = NormalizeText(strings.Repeat("a", MaxLength+1))
+	assert.Equal(t, shared.ErrCodeInvalidInput, sharedtest.ErrorCode(t, err))
+}
+
+func TestFloodStatus_Check(t *testing.T) {
+	assert.NoError(t, FloodStatus{Recent: FloodLimit}.Check())
+	assert.Equal(t, shared.ErrCodeChatFlood, sharedtest.ErrorCode(t, FloodStatus{Recent: FloodLimit + 1}.Check()))
+	assert.Equal(t, shared.ErrCodeChatFlood, sharedtest.ErrorCode(t, FloodStatus{Recent: 2, Repeated: true}.Check()))
+}
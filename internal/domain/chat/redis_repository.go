@@ -0,0 +1,53 @@
+package chat
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisFloodRepository implements FloodRepository with a counter and the last message per
+// player, both expiring after FloodWindow so nothing outlives a player's account for long
+type RedisFloodRepository struct {
+	client *redis.Client
+}
+
+// NewRedisFloodRepository creates a new Redis-based flood repository
+func NewRedisFloodRepository(client *redis.Client) FloodRepository {
+	return &RedisFloodRepository{
+		client: client,
+	}
+}
+
+// Record increments the player's counter, starting its window on the first message, and
+// swaps in the message as their last one
+func (r *RedisFloodRepository) Record(ctx context.Context, userID, text string) (FloodStatus, error) {
+	var count *redis.IntCmd
+	var previous *redis.StatusCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.Incr(ctx, r.countKey(userID))
+		pipe.ExpireNX(ctx, r.countKey(userID), FloodWindow)
+		previous = pipe.SetArgs(ctx, r.lastKey(userID), text, redis.SetArgs{TTL: FloodWindow, Get: true})
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return FloodStatus{}, fmt.Errorf("failed to record chat message: %w", err)
+	}
+
+	return FloodStatus{
+		Recent:   int(count.Val()),
+		Repeated: previous.Err() == nil && previous.Val() == text,
+	}, nil
+}
+
+// countKey returns the number of messages a player sent in the current window
+func (r *RedisFloodRepository) countKey(userID string) string {
+	return fmt.Sprintf("chat:flood:%s", userID)
+}
+
+// lastKey returns the last message a player sent
+func (r *RedisFloodRepository) lastKey(userID string) string {
+	return fmt.Sprintf("chat:last:%s", userID)
+}
@@ -0,0 +1,12 @@
+package chat
+
+import (
+	"context"
+)
+
+// FloodRepository counts the messages players send for flood protection
+type FloodRepository interface {
+	// Record counts a message a player is about to send, reporting their sending in the
+	// current FloodWindow including it
+	Record(ctx context.Context, userID, text string) (FloodStatus, error)
+}
@@ -0,0 +1,324 @@
+package crafting
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/equipment"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// RecipeID represents a unique recipe identifier
+type RecipeID string
+
+// String returns string representation
+func (id RecipeID) String() string {
+	return string(id)
+}
+
+// Ingredient represents a material consumed by a recipe
+type Ingredient struct {
+	ItemType trainer.ItemType `json:"item_type"`
+	Quantity int              `json:"quantity"`
+}
+
+// OutputKind represents what a recipe produces
+type OutputKind string
+
+const (
+	OutputItem      OutputKind = "item"      // Inventory item (consumables, nets)
+	OutputEquipment OutputKind = "equipment" // Equipment entity
+)
+
+// Output describes the result of a successful craft
+type Output struct {
+	Kind OutputKind `json:"kind"`
+	Name string     `json:"name"`
+
+	// Item output
+	ItemType trainer.ItemType `json:"item_type,omitempty"`
+	Quantity int              `json:"quantity,omitempty"`
+
+	// Equipment output
+	EquipmentType equipment.EquipmentType `json:"equipment_type,omitempty"`
+	Rarity        equipment.Rarity        `json:"rarity,omitempty"`
+	BaseStats     shared.Stats            `json:"base_stats,omitempty"`
+}
+
+// Recipe represents a crafting recipe
+type Recipe struct {
+	ID            RecipeID     `json:"id"`
+	Name          string       `json:"name"`
+	Ingredients   []Ingredient `json:"ingredients"`
+	Output        Output       `json:"output"`
+	CraftSeconds  int          `json:"craft_seconds"`
+	RequiredLevel int          `json:"required_level"`
+	// BaseSuccessChance is the success chance at the required level
+	BaseSuccessChance float64 `json:"base_success_chance"`
+}
+
+const (
+	// Each trainer level above the requirement adds this much success chance
+	successChancePerLevel = 0.02
+	maxSuccessChance      = 0.95
+)
+
+// CraftTime returns how long crafting the recipe takes
+func (r *Recipe) CraftTime() time.Duration {
+	return time.Duration(r.CraftSeconds) * time.Second
+}
+
+// SuccessChance returns the success chance for a trainer of the given level
+func (r *Recipe) SuccessChance(trainerLevel int) float64 {
+	if r.BaseSuccessChance >= 1 {
+		return 1 // Guaranteed recipes stay guaranteed
+	}
+
+	chance := r.BaseSuccessChance + float64(trainerLevel-r.RequiredLevel)*successChancePerLevel
+	if chance > maxSuccessChance {
+		chance = maxSuccessChance
+	}
+	return chance
+}
+
+// Registry holds the known recipes
+type Registry struct {
+	recipes map[RecipeID]*Recipe
+}
+
+// NewRegistry creates a registry from the given recipes
+func NewRegistry(recipes ...*Recipe) *Registry {
+	registry := &Registry{
+		recipes: make(map[RecipeID]*Recipe),
+	}
+	for _, recipe := range recipes {
+		registry.recipes[recipe.ID] = recipe
+	}
+	return registry
+}
+
+// NewDefaultRegistry creates a registry with the built-in recipes
+func NewDefaultRegistry() *Registry {
+	return NewRegistry(DefaultRecipes()...)
+}
+
+// Get returns a recipe by ID
+func (r *Registry) Get(id RecipeID) (*Recipe, error) {
+	recipe, exists := r.recipes[id]
+	if !exists {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeUnknownRecipe, "Unknown recipe: %s", id)
+	}
+	return recipe, nil
+}
+
+// List returns all recipes sorted by ID
+func (r *Registry) List() []*Recipe {
+	recipes := make([]*Recipe, 0, len(r.recipes))
+	for _, recipe := range r.recipes {
+		recipes = append(recipes, recipe)
+	}
+	sort.Slice(recipes, func(i, j int) bool {
+		return recipes[i].ID < recipes[j].ID
+	})
+	return recipes
+}
+
+// DefaultRecipes returns the built-in recipes turning loot materials into gear
+func DefaultRecipes() []*Recipe {
+	return []*Recipe{
+		{
+			ID:   "health_potion",
+			Name: "Brew Health Potion",
+			Ingredients: []Ingredient{
+				{ItemType: trainer.AnimalHide, Quantity: 1},
+			},
+			Output:            Output{Kind: OutputItem, Name: "Health Potion", ItemType: trainer.HealthPotion, Quantity: 1},
+			CraftSeconds:      10,
+			RequiredLevel:     1,
+			BaseSuccessChance: 1,
+		},
+		{
+			ID:   "advanced_net",
+			Name: "Weave Advanced Net",
+			Ingredients: []Ingredient{
+				{ItemType: trainer.AnimalHide, Quantity: 3},
+				{ItemType: trainer.BasicNet, Quantity: 1},
+			},
+			Output:            Output{Kind: OutputItem, Name: "Advanced Net", ItemType: trainer.AdvancedNet, Quantity: 1},
+			CraftSeconds:      30,
+			RequiredLevel:     3,
+			BaseSuccessChance: 0.8,
+		},
+		{
+			ID:   "master_net",
+			Name: "Weave Master Net",
+			Ingredients: []Ingredient{
+				{ItemType: trainer.AdvancedNet, Quantity: 1},
+				{ItemType: trainer.MagicCrystal, Quantity: 1},
+			},
+			Output:            Output{Kind: OutputItem, Name: "Master Net", ItemType: trainer.MasterNet, Quantity: 1},
+			CraftSeconds:      120,
+			RequiredLevel:     10,
+			BaseSuccessChance: 0.6,
+		},
+		{
+			ID:   "hide_necklace",
+			Name: "Craft Hide Necklace",
+			Ingredients: []Ingredient{
+				{ItemType: trainer.AnimalHide, Quantity: 5},
+			},
+			Output: Output{
+				Kind:          OutputEquipment,
+				Name:          "Hide Necklace",
+				EquipmentType: equipment.Necklace,
+				Rarity:        equipment.Common,
+				BaseStats:     shared.NewStats(10, 0, 3, 0, 0),
+			},
+			CraftSeconds:      60,
+			RequiredLevel:     2,
+			BaseSuccessChance: 0.9,
+		},
+		{
+			ID:   "gem_necklace",
+			Name: "Craft Gem Necklace",
+			Ingredients: []Ingredient{
+				{ItemType: trainer.AnimalHide, Quantity: 2},
+				{ItemType: trainer.RareGem, Quantity: 2},
+			},
+			Output: Output{
+				Kind:          OutputEquipment,
+				Name:          "Gem Necklace",
+				EquipmentType: equipment.Necklace,
+				Rarity:        equipment.Rare,
+				BaseStats:     shared.NewStats(15, 5, 2, 2, 2),
+			},
+			CraftSeconds:      300,
+			RequiredLevel:     5,
+			BaseSuccessChance: 0.7,
+		},
+		{
+			ID:   "crystal_necklace",
+			Name: "Craft Crystal Necklace",
+			Ingredients: []Ingredient{
+				{ItemType: trainer.RareGem, Quantity: 2},
+				{ItemType: trainer.MagicCrystal, Quantity: 2},
+			},
+			Output: Output{
+				Kind:          OutputEquipment,
+				Name:          "Crystal Necklace",
+				EquipmentType: equipment.Necklace,
+				Rarity:        equipment.Epic,
+				BaseStats:     shared.NewStats(20, 8, 4, 4, 4),
+			},
+			CraftSeconds:      900,
+			RequiredLevel:     10,
+			BaseSuccessChance: 0.5,
+		},
+	}
+}
+
+// JobID represents a unique crafting job identifier
+type JobID shared.ID
+
+// NewJobID creates a new job ID
+func NewJobID() JobID {
+	return JobID(shared.NewID())
+}
+
+// String returns string representation
+func (id JobID) String() string {
+	return string(id)
+}
+
+// JobStatus represents the state of a crafting job
+type JobStatus string
+
+const (
+	JobInProgress JobStatus = "in_progress" // Materials consumed, waiting for craft time
+	JobCompleted  JobStatus = "completed"   // Craft time elapsed, result rolled
+	JobCollected  JobStatus = "collected"   // Result handed to the trainer
+)
+
+// String returns string representation
+func (s JobStatus) String() string {
+	return string(s)
+}
+
+// Job represents a crafting job aggregate
+type Job struct {
+	ID            JobID          `json:"id"`
+	TrainerID     trainer.UserID `json:"trainer_id"`
+	RecipeID      RecipeID       `json:"recipe_id"`
+	Status        JobStatus      `json:"status"`
+	SuccessChance float64        `json:"success_chance"`
+	Succeeded     bool           `json:"succeeded"`
+	StartedAt     time.Time      `json:"started_at"`
+	ReadyAt       time.Time      `json:"ready_at"`
+	CompletedAt   time.Time      `json:"completed_at,omitempty"`
+	CollectedAt   time.Time      `json:"collected_at,omitempty"`
+}
+
+// NewJob creates a new crafting job for a trainer
+func NewJob(trainerID trainer.UserID, recipe *Recipe, trainerLevel int) (*Job, error) {
+	if trainerLevel < recipe.RequiredLevel {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeCraftLevelTooLow, "Recipe %s requires level %d", recipe.ID, recipe.RequiredLevel)
+	}
+
+	now := time.Now()
+
+	return &Job{
+		ID:            NewJobID(),
+		TrainerID:     trainerID,
+		RecipeID:      recipe.ID,
+		Status:        JobInProgress,
+		SuccessChance: recipe.SuccessChance(trainerLevel),
+		StartedAt:     now,
+		ReadyAt:       now.Add(recipe.CraftTime()),
+	}, nil
+}
+
+// IsReady checks if the craft time has elapsed
+func (j *Job) IsReady(now time.Time) bool {
+	return !now.Before(j.ReadyAt)
+}
+
+// Complete rolls the success chance once the craft time has elapsed
+func (j *Job) Complete(now time.Time, rng *rand.Rand) error {
+	if j.Status != JobInProgress {
+		return nil // Already completed, completion is idempotent
+	}
+
+	if !j.IsReady(now) {
+		return shared.NewDomainError(shared.ErrCodeCraftNotReady, "Craft is not finished yet")
+	}
+
+	j.Succeeded = rng.Float64() < j.SuccessChance
+	j.Status = JobCompleted
+	j.CompletedAt = now
+
+	return nil
+}
+
+// Collect marks the job result as handed to the trainer
+func (j *Job) Collect(now time.Time) error {
+	switch j.Status {
+	case JobInProgress:
+		return shared.NewDomainError(shared.ErrCodeCraftNotReady, "Craft is not finished yet")
+	case JobCollected:
+		return shared.NewDomainError(shared.ErrCodeCraftAlreadyClaimed, "Craft result already collected")
+	}
+
+	j.Status = JobCollected
+	j.CollectedAt = now
+
+	return nil
+}
+
+// Result describes what collecting a job handed to the trainer
+type Result struct {
+	Job       *Job                 `json:"job"`
+	Items     []*trainer.Item      `json:"items,omitempty"`
+	Equipment *equipment.Equipment `json:"equipment,omitempty"`
+}
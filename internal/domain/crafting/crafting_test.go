@@ -0,0 +1,68 @@
+package crafting
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+func TestRecipe_SuccessChance(t *testing.T) {
+	recipe := &Recipe{ID: "test", RequiredLevel: 5, BaseSuccessChance: 0.5}
+
+	assert.InDelta(t, 0.5, recipe.SuccessChance(5), 0.0001)
+	assert.InDelta(t, 0.6, recipe.SuccessChance(10), 0.0001)
+	assert.InDelta(t, maxSuccessChance, recipe.SuccessChance(100), 0.0001)
+
+	guaranteed := &Recipe{ID: "guaranteed", RequiredLevel: 1, BaseSuccessChance: 1}
+	assert.Equal(t, 1.0, guaranteed.SuccessChance(50))
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	recipe, err := registry.Get("health_potion")
+	require.NoError(t, err)
+	assert.Equal(t, trainer.HealthPotion, recipe.Output.ItemType)
+
+	_, err = registry.Get("unknown")
+	assert.Error(t, err)
+
+	recipes := registry.List()
+	require.Len(t, recipes, len(DefaultRecipes()))
+	for i := 1; i < len(recipes); i++ {
+		assert.Less(t, recipes[i-1].ID, recipes[i].ID)
+	}
+}
+
+func TestJob_Lifecycle(t *testing.T) {
+	recipe := &Recipe{ID: "test", CraftSeconds: 60, RequiredLevel: 3, BaseSuccessChance: 1}
+
+	t.Run("should reject trainers below the required level", func(t *testing.T) {
+		_, err := NewJob("trainer-1", recipe, 2)
+		assert.Error(t, err)
+	})
+
+	t.Run("should complete only after the craft time and collect once", func(t *testing.T) {
+		job, err := NewJob("trainer-1", recipe, 3)
+		require.NoError(t, err)
+		assert.Equal(t, JobInProgress, job.Status)
+		assert.Equal(t, time.Minute, job.ReadyAt.Sub(job.StartedAt))
+
+		rng := rand.New(rand.NewSource(1))
+		assert.Error(t, job.Complete(job.StartedAt, rng))
+		assert.Error(t, job.Collect(job.StartedAt))
+
+		require.NoError(t, job.Complete(job.ReadyAt, rng))
+		assert.Equal(t, JobCompleted, job.Status)
+		assert.True(t, job.Succeeded)
+
+		require.NoError(t, job.Collect(job.ReadyAt))
+		assert.Equal(t, JobCollected, job.Status)
+		assert.Error(t, job.Collect(job.ReadyAt))
+	})
+}
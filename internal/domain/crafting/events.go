@@ -0,0 +1,113 @@
+package crafting
+
+import (
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// Event types
+const (
+	CraftStartedEventType   = "crafting.started"
+	CraftCompletedEventType = "crafting.completed"
+	CraftCollectedEventType = "crafting.collected"
+)
+
+// CraftStartedEvent represents a trainer starting a craft
+type CraftStartedEvent struct {
+	shared.BaseEvent
+}
+
+// CraftStartedEventData holds the event data
+type CraftStartedEventData struct {
+	JobID     string `json:"job_id"`
+	TrainerID string `json:"trainer_id"`
+	RecipeID  string `json:"recipe_id"`
+}
+
+// NewCraftStartedEvent creates a new craft started event
+func NewCraftStartedEvent(jobID, trainerID, recipeID string) (CraftStartedEvent, error) {
+	data := CraftStartedEventData{
+		JobID:     jobID,
+		TrainerID: trainerID,
+		RecipeID:  recipeID,
+	}
+
+	baseEvent, err := shared.NewBaseEvent(
+		CraftStartedEventType,
+		jobID,
+		"crafting_job",
+		data,
+	)
+	if err != nil {
+		return CraftStartedEvent{}, err
+	}
+
+	return CraftStartedEvent{BaseEvent: baseEvent}, nil
+}
+
+// CraftCompletedEvent represents a craft finishing
+type CraftCompletedEvent struct {
+	shared.BaseEvent
+}
+
+// CraftCompletedEventData holds the event data
+type CraftCompletedEventData struct {
+	JobID     string `json:"job_id"`
+	TrainerID string `json:"trainer_id"`
+	RecipeID  string `json:"recipe_id"`
+	Succeeded bool   `json:"succeeded"`
+}
+
+// NewCraftCompletedEvent creates a new craft completed event
+func NewCraftCompletedEvent(jobID, trainerID, recipeID string, succeeded bool) (CraftCompletedEvent, error) {
+	data := CraftCompletedEventData{
+		JobID:     jobID,
+		TrainerID: trainerID,
+		RecipeID:  recipeID,
+		Succeeded: succeeded,
+	}
+
+	baseEvent, err := shared.NewBaseEvent(
+		CraftCompletedEventType,
+		jobID,
+		"crafting_job",
+		data,
+	)
+	if err != nil {
+		return CraftCompletedEvent{}, err
+	}
+
+	return CraftCompletedEvent{BaseEvent: baseEvent}, nil
+}
+
+// CraftCollectedEvent represents a trainer collecting a craft result
+type CraftCollectedEvent struct {
+	shared.BaseEvent
+}
+
+// CraftCollectedEventData holds the event data
+type CraftCollectedEventData struct {
+	JobID     string `json:"job_id"`
+	TrainerID string `json:"trainer_id"`
+	RecipeID  string `json:"recipe_id"`
+}
+
+// NewCraftCollectedEvent creates a new craft collected event
+func NewCraftCollectedEvent(jobID, trainerID, recipeID string) (CraftCollectedEvent, error) {
+	data := CraftCollectedEventData{
+		JobID:     jobID,
+		TrainerID: trainerID,
+		RecipeID:  recipeID,
+	}
+
+	baseEvent, err := shared.NewBaseEvent(
+		CraftCollectedEventType,
+		jobID,
+		"crafting_job",
+		data,
+	)
+	if err != nil {
+		return CraftCollectedEvent{}, err
+	}
+
+	return CraftCollectedEvent{BaseEvent: baseEvent}, nil
+}
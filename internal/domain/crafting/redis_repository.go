@@ -0,0 +1,103 @@
+package crafting
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// Collected jobs are kept around briefly for support lookups before expiring
+const collectedJobTTL = 24 * time.Hour
+
+// RedisRepository implements Repository using Redis Hash
+type RedisRepository struct {
+	client *redis.Client
+	docs   *redisx.Repository[Job]
+}
+
+// NewRedisRepository creates a new Redis-based crafting job repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+		docs: redisx.NewRepository(client, "craft:", redisx.Options[Job]{
+			Codec:         redisx.HashCodec[Job]{},
+			Indexes:       jobIndexes(),
+			NotFound:      func() error { return shared.ErrNotFound("crafting job") },
+			AlreadyExists: func() error { return shared.ErrAlreadyExists("crafting job") },
+		}),
+	}
+}
+
+// jobIndexes keeps the trainer index limited to uncollected jobs and lets collected jobs expire
+func jobIndexes() []redisx.Index[Job] {
+	return []redisx.Index[Job]{
+		redisx.SetIndex[Job]{Key: func(j *Job) string {
+			if j.Status == JobCollected {
+				return ""
+			}
+			return trainerIndexKey(j.TrainerID)
+		}},
+		redisx.IndexFunc[Job](func(ctx context.Context, pipe redis.Pipeliner, id string, before, after *Job) {
+			if after != nil && after.Status == JobCollected {
+				pipe.Expire(ctx, jobKey(after.ID), collectedJobTTL)
+			}
+		}),
+	}
+}
+
+// FindOneAndInsert implements IoC pattern for insert operations
+func (r *RedisRepository) FindOneAndInsert(ctx context.Context, id JobID, callback func() (*Job, error)) error {
+	return r.docs.FindOneAndInsert(ctx, id.String(), callback)
+}
+
+// FindOneAndUpdate implements IoC pattern for update operations
+func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, id JobID, callback func(*Job) (*Job, error)) error {
+	return r.docs.FindOneAndUpdate(ctx, id.String(), callback)
+}
+
+// GetByID retrieves a job by ID
+func (r *RedisRepository) GetByID(ctx context.Context, id JobID) (*Job, error) {
+	return r.docs.GetByID(ctx, id.String())
+}
+
+// GetByTrainer retrieves uncollected jobs of a trainer
+func (r *RedisRepository) GetByTrainer(ctx context.Context, trainerID trainer.UserID) ([]*Job, error) {
+	ids, err := r.client.SMembers(ctx, trainerIndexKey(trainerID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []*Job
+	for _, id := range ids {
+		j, err := r.GetByID(ctx, JobID(id))
+		if err != nil {
+			return nil, err
+		}
+		if j != nil && j.Status != JobCollected {
+			jobs = append(jobs, j)
+		}
+	}
+
+	return jobs, nil
+}
+
+// Delete removes a job
+func (r *RedisRepository) Delete(ctx context.Context, id JobID) error {
+	return r.docs.Delete(ctx, id.String())
+}
+
+// jobKey returns the Redis key for a job
+func jobKey(id JobID) string {
+	return fmt.Sprintf("craft:%s", id.String())
+}
+
+// trainerIndexKey returns the Redis set of a trainer's uncollected jobs
+func trainerIndexKey(trainerID trainer.UserID) string {
+	return fmt.Sprintf("idx:craft:trainer:%s", trainerID.String())
+}
@@ -0,0 +1,25 @@
+package crafting
+
+import (
+	"context"
+
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// Repository defines the interface for crafting job persistence operations with IoC pattern
+type Repository interface {
+	// FindOneAndInsert inserts a new job with callback for initialization
+	FindOneAndInsert(ctx context.Context, id JobID, callback func() (*Job, error)) error
+
+	// FindOneAndUpdate finds a job by ID and applies callback for atomic update
+	FindOneAndUpdate(ctx context.Context, id JobID, callback func(*Job) (*Job, error)) error
+
+	// GetByID retrieves a job by ID (read-only)
+	GetByID(ctx context.Context, id JobID) (*Job, error)
+
+	// GetByTrainer retrieves all uncollected jobs of a trainer (read-only)
+	GetByTrainer(ctx context.Context, trainerID trainer.UserID) ([]*Job, error)
+
+	// Delete removes a job
+	Delete(ctx context.Context, id JobID) error
+}
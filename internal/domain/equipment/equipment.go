@@ -77,7 +77,10 @@ type Equipment struct {
 	EquipmentType EquipmentType    `json:"equipment_type"`
 	Rarity        Rarity           `json:"rarity"`
 	BaseStats     shared.Stats     `json:"base_stats"`
-	OwnerID       shared.ID        `json:"owner_id"` // AnimalID when equipped, empty when not equipped
+	OwnerID       shared.ID        `json:"owner_id"`             // AnimalID when equipped, empty when not equipped
+	TrainerID     shared.ID        `json:"trainer_id,omitempty"` // Trainer holding the equipment
+	BindOnEquip   bool             `json:"bind_on_equip"`        // Epic and legendary equipment binds when first equipped
+	Bound         bool             `json:"bound"`                // Bound equipment stays with its trainer
 	CreatedAt     shared.Timestamp `json:"created_at"`
 	UpdatedAt     shared.Timestamp `json:"updated_at"`
 }
@@ -105,6 +108,7 @@ func NewEquipment(name string, equipmentType EquipmentType, rarity Rarity, baseS
 		EquipmentType: equipmentType,
 		Rarity:        rarity,
 		BaseStats:     baseStats,
+		BindOnEquip:   rarity == Epic || rarity == Legendary,
 		CreatedAt:     timestamp,
 		UpdatedAt:     timestamp,
 	}
@@ -112,6 +116,21 @@ func NewEquipment(name string, equipmentType EquipmentType, rarity Rarity, baseS
 	return equipment, nil
 }
 
+// AssignToTrainer gives the equipment to a trainer; bound equipment can't change hands
+func (e *Equipment) AssignToTrainer(trainerID shared.ID) error {
+	if trainerID == "" {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "Trainer ID cannot be empty")
+	}
+	if e.Bound && e.TrainerID != trainerID {
+		return shared.NewDomainErrorf(shared.ErrCodeItemBound, "%s is bound and cannot be transferred", e.Name)
+	}
+
+	e.TrainerID = trainerID
+	e.UpdatedAt = shared.NewTimestamp()
+
+	return nil
+}
+
 // IsEquipped checks if equipment is currently equipped
 func (e *Equipment) IsEquipped() bool {
 	return e.OwnerID != ""
@@ -130,7 +149,7 @@ func (e *Equipment) GetEffectiveStats() shared.Stats {
 	)
 }
 
-// EquipTo equips this equipment to an animal
+// EquipTo equips this equipment to an animal, binding it to its trainer if it binds on equip
 func (e *Equipment) EquipTo(animalID shared.ID) error {
 	if e.IsEquipped() {
 		return shared.NewDomainError(shared.ErrCodeAlreadyEquipped, "Equipment is already equipped to another animal")
@@ -141,6 +160,9 @@ func (e *Equipment) EquipTo(animalID shared.ID) error {
 	}
 
 	e.OwnerID = animalID
+	if e.BindOnEquip {
+		e.Bound = true
+	}
 	e.UpdatedAt = shared.NewTimestamp()
 
 	return nil
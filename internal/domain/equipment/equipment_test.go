@@ -0,0 +1,46 @@
+package equipment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestEquipment_BindOnEquip(t *testing.T) {
+	tests := []struct {
+		rarity Rarity
+		binds  bool
+	}{
+		{Common, false},
+		{Rare, false},
+		{Epic, true},
+		{Legendary, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rarity.String(), func(t *testing.T) {
+			e, err := NewEquipment("Necklace", Necklace, tt.rarity, shared.NewStats(1, 1, 1, 1, 1))
+			require.NoError(t, err)
+			require.NoError(t, e.AssignToTrainer("trainer-1"))
+			assert.False(t, e.Bound, "equipment binds when equipped, not when crafted")
+
+			require.NoError(t, e.EquipTo("animal-1"))
+			require.NoError(t, e.Unequip())
+			assert.Equal(t, tt.binds, e.Bound)
+
+			err = e.AssignToTrainer("trainer-2")
+			if !tt.binds {
+				assert.NoError(t, err)
+				return
+			}
+			code, ok := shared.DomainErrorCode(err)
+			require.True(t, ok)
+			assert.Equal(t, shared.ErrCodeItemBound, code)
+			assert.Equal(t, shared.ID("trainer-1"), e.TrainerID)
+			assert.NoError(t, e.AssignToTrainer("trainer-1"), "bound equipment stays with its trainer")
+		})
+	}
+}
@@ -2,194 +2,79 @@ package equipment
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 
 	"github.com/redis/go-redis/v9"
 
 	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/redisx"
 )
 
 // RedisRepository implements Repository using Redis Hash
 type RedisRepository struct {
 	client *redis.Client
+	docs   *redisx.Repository[Equipment]
 }
 
 // NewRedisRepository creates a new Redis-based equipment repository
 func NewRedisRepository(client *redis.Client) Repository {
 	return &RedisRepository{
 		client: client,
+		docs: redisx.NewRepository(client, "equipment:", redisx.Options[Equipment]{
+			Codec:         redisx.HashCodec[Equipment]{},
+			Indexes:       equipmentIndexes(),
+			NotFound:      func() error { return shared.ErrNotFound("equipment") },
+			AlreadyExists: func() error { return shared.ErrAlreadyExists("equipment") },
+		}),
 	}
 }
 
 // FindOneAndUpsert implements IoC pattern with callback for concurrency control
 func (r *RedisRepository) FindOneAndUpsert(ctx context.Context, id EquipmentID, callback func(*Equipment) (*Equipment, error)) error {
-	key := fmt.Sprintf("equipment:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Get current equipment
-		var current *Equipment
-		data := tx.HGetAll(ctx, key)
-		if data.Err() != nil && data.Err() != redis.Nil {
-			return data.Err()
-		}
-
-		if len(data.Val()) > 0 {
-			current = &Equipment{}
-			if err := r.deserializeEquipment(data.Val(), current); err != nil {
-				return err
-			}
-		}
-
-		// Execute callback
-		result, err := callback(current)
-		if err != nil {
-			return err
-		}
-
-		if result == nil {
-			return nil // No changes
-		}
-
-		// Serialize and store
-		fields, err := r.serializeEquipment(result)
-		if err != nil {
-			return err
-		}
-
-		// Execute transaction
-		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.HMSet(ctx, key, fields)
-
-			// Update indices
-			r.updateEquipmentIndices(ctx, pipe, result)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	return r.docs.FindOneAndUpsert(ctx, id.String(), callback)
 }
 
 // FindOneAndInsert implements IoC pattern for insert operations
 func (r *RedisRepository) FindOneAndInsert(ctx context.Context, id EquipmentID, callback func() (*Equipment, error)) error {
-	key := fmt.Sprintf("equipment:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Check if already exists
-		exists := tx.Exists(ctx, key)
-		if exists.Err() != nil {
-			return exists.Err()
-		}
-
-		if exists.Val() > 0 {
-			return shared.ErrAlreadyExists("equipment")
-		}
-
-		// Execute callback
-		result, err := callback()
-		if err != nil {
-			return err
-		}
-
-		if result == nil {
-			return fmt.Errorf("callback returned nil equipment")
-		}
-
-		// Serialize and store
-		fields, err := r.serializeEquipment(result)
-		if err != nil {
-			return err
-		}
-
-		// Execute transaction
-		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.HMSet(ctx, key, fields)
-
-			// Update indices
-			r.updateEquipmentIndices(ctx, pipe, result)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	return r.docs.FindOneAndInsert(ctx, id.String(), callback)
 }
 
 // FindOneAndUpdate implements IoC pattern for update operations
 func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, id EquipmentID, callback func(*Equipment) (*Equipment, error)) error {
-	key := fmt.Sprintf("equipment:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Get current equipment
-		data := tx.HGetAll(ctx, key)
-		if data.Err() != nil {
-			return data.Err()
-		}
-
-		if len(data.Val()) == 0 {
-			return shared.ErrNotFound("equipment")
-		}
-
-		current := &Equipment{}
-		if err := r.deserializeEquipment(data.Val(), current); err != nil {
-			return err
-		}
-
-		// Execute callback
-		result, err := callback(current)
-		if err != nil {
-			return err
-		}
-
-		if result == nil {
-			return nil // No changes
-		}
-
-		// Serialize and store
-		fields, err := r.serializeEquipment(result)
-		if err != nil {
-			return err
-		}
-
-		// Execute transaction
-		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.HMSet(ctx, key, fields)
-
-			// Update indices if needed
-			r.updateEquipmentIndices(ctx, pipe, result)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	return r.docs.FindOneAndUpdate(ctx, id.String(), callback)
 }
 
 // GetByID retrieves equipment by ID
 func (r *RedisRepository) GetByID(ctx context.Context, id EquipmentID) (*Equipment, error) {
-	key := fmt.Sprintf("equipment:%s", id.String())
+	return r.docs.GetByID(ctx, id.String())
+}
 
-	data, err := r.client.HGetAll(ctx, key).Result()
+// GetByOwner retrieves equipment owned by an animal
+func (r *RedisRepository) GetByOwner(ctx context.Context, ownerID shared.ID) ([]*Equipment, error) {
+	indexKey := fmt.Sprintf("idx:equipment:owner:%s", ownerID.String())
+
+	ids, err := r.client.SMembers(ctx, indexKey).Result()
 	if err != nil {
 		return nil, err
 	}
 
-	if len(data) == 0 {
-		return nil, nil
-	}
-
-	e := &Equipment{}
-	if err := r.deserializeEquipment(data, e); err != nil {
-		return nil, err
+	var equipments []*Equipment
+	for _, id := range ids {
+		e, err := r.GetByID(ctx, EquipmentID(id))
+		if err != nil {
+			return nil, err
+		}
+		if e != nil {
+			equipments = append(equipments, e)
+		}
 	}
 
-	return e, nil
+	return equipments, nil
 }
 
-// GetByOwner retrieves equipment owned by an animal
-func (r *RedisRepository) GetByOwner(ctx context.Context, ownerID shared.ID) ([]*Equipment, error) {
-	indexKey := fmt.Sprintf("idx:equipment:owner:%s", ownerID.String())
+// GetByTrainer retrieves equipment held by a trainer
+func (r *RedisRepository) GetByTrainer(ctx context.Context, trainerID shared.ID) ([]*Equipment, error) {
+	indexKey := fmt.Sprintf("idx:equipment:trainer:%s", trainerID.String())
 
 	ids, err := r.client.SMembers(ctx, indexKey).Result()
 	if err != nil {
@@ -202,7 +87,7 @@ func (r *RedisRepository) GetByOwner(ctx context.Context, ownerID shared.ID) ([]
 		if err != nil {
 			return nil, err
 		}
-		if e != nil {
+		if e != nil && e.TrainerID == trainerID {
 			equipments = append(equipments, e)
 		}
 	}
@@ -258,95 +143,40 @@ func (r *RedisRepository) GetUnequipped(ctx context.Context) ([]*Equipment, erro
 
 // Delete removes equipment
 func (r *RedisRepository) Delete(ctx context.Context, id EquipmentID) error {
-	key := fmt.Sprintf("equipment:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Get equipment for index cleanup
-		data := tx.HGetAll(ctx, key)
-		if data.Err() != nil || len(data.Val()) == 0 {
-			return shared.ErrNotFound("equipment")
-		}
-
-		e := &Equipment{}
-		if err := r.deserializeEquipment(data.Val(), e); err != nil {
-			return err
-		}
-
-		// Execute transaction
-		_, err := tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.Del(ctx, key)
-
-			// Clean up indices
-			r.cleanupEquipmentIndices(ctx, pipe, e)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	return r.docs.Delete(ctx, id.String())
 }
 
-// serializeEquipment converts equipment to Redis hash fields
-func (r *RedisRepository) serializeEquipment(e *Equipment) (map[string]interface{}, error) {
-	data, err := json.Marshal(e)
-	if err != nil {
-		return nil, err
-	}
-
-	return map[string]interface{}{
-		"data": string(data),
-	}, nil
-}
-
-// deserializeEquipment converts Redis hash fields to equipment
-func (r *RedisRepository) deserializeEquipment(fields map[string]string, e *Equipment) error {
-	data, exists := fields["data"]
-	if !exists {
-		return fmt.Errorf("equipment data not found in hash")
-	}
-
-	return json.Unmarshal([]byte(data), e)
-}
-
-// updateEquipmentIndices updates secondary indices
-func (r *RedisRepository) updateEquipmentIndices(ctx context.Context, pipe redis.Pipeliner, e *Equipment) {
-	// Owner index (only for equipped equipment)
-	if e.IsEquipped() {
-		ownerKey := fmt.Sprintf("idx:equipment:owner:%s", e.OwnerID.String())
-		pipe.SAdd(ctx, ownerKey, e.ID.String())
-
-		// Remove from unequipped index
-		pipe.SRem(ctx, "idx:equipment:unequipped", e.ID.String())
-	} else {
-		// Add to unequipped index
-		pipe.SAdd(ctx, "idx:equipment:unequipped", e.ID.String())
-	}
-
-	// Type index
-	typeKey := fmt.Sprintf("idx:equipment:type:%s", e.EquipmentType.String())
-	pipe.SAdd(ctx, typeKey, e.ID.String())
-
-	// Rarity index
-	rarityKey := fmt.Sprintf("idx:equipment:rarity:%s", e.Rarity.String())
-	pipe.SAdd(ctx, rarityKey, e.ID.String())
-}
-
-// cleanupEquipmentIndices cleans up secondary indices
-func (r *RedisRepository) cleanupEquipmentIndices(ctx context.Context, pipe redis.Pipeliner, e *Equipment) {
-	// Owner index
-	if e.IsEquipped() {
-		ownerKey := fmt.Sprintf("idx:equipment:owner:%s", e.OwnerID.String())
-		pipe.SRem(ctx, ownerKey, e.ID.String())
-	} else {
-		// Remove from unequipped index
-		pipe.SRem(ctx, "idx:equipment:unequipped", e.ID.String())
+// equipmentIndexes are the secondary indices kept with each equipment
+func equipmentIndexes() []redisx.Index[Equipment] {
+	return []redisx.Index[Equipment]{
+		// Owner index (only for equipped equipment)
+		redisx.SetIndex[Equipment]{Key: func(e *Equipment) string {
+			if !e.IsEquipped() {
+				return ""
+			}
+			return fmt.Sprintf("idx:equipment:owner:%s", e.OwnerID.String())
+		}},
+		// Unequipped index
+		redisx.SetIndex[Equipment]{Key: func(e *Equipment) string {
+			if e.IsEquipped() {
+				return ""
+			}
+			return "idx:equipment:unequipped"
+		}},
+		// Trainer index
+		redisx.SetIndex[Equipment]{Key: func(e *Equipment) string {
+			if e.TrainerID == "" {
+				return ""
+			}
+			return fmt.Sprintf("idx:equipment:trainer:%s", e.TrainerID.String())
+		}},
+		// Type index
+		redisx.SetIndex[Equipment]{Key: func(e *Equipment) string {
+			return fmt.Sprintf("idx:equipment:type:%s", e.EquipmentType.String())
+		}},
+		// Rarity index
+		redisx.SetIndex[Equipment]{Key: func(e *Equipment) string {
+			return fmt.Sprintf("idx:equipment:rarity:%s", e.Rarity.String())
+		}},
 	}
-
-	// Type index
-	typeKey := fmt.Sprintf("idx:equipment:type:%s", e.EquipmentType.String())
-	pipe.SRem(ctx, typeKey, e.ID.String())
-
-	// Rarity index
-	rarityKey := fmt.Sprintf("idx:equipment:rarity:%s", e.Rarity.String())
-	pipe.SRem(ctx, rarityKey, e.ID.String())
 }
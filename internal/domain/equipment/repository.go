@@ -23,6 +23,9 @@ type Repository interface {
 	// GetByOwner retrieves all equipment owned by an animal (read-only)
 	GetByOwner(ctx context.Context, ownerID shared.ID) ([]*Equipment, error)
 
+	// GetByTrainer retrieves all equipment held by a trainer (read-only)
+	GetByTrainer(ctx context.Context, trainerID shared.ID) ([]*Equipment, error)
+
 	// GetUnequipped retrieves all unequipped equipment (read-only)
 	GetUnequipped(ctx context.Context) ([]*Equipment, error)
 
@@ -0,0 +1,292 @@
+package fairness
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mathrand "math/rand"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// SeedPeriod is how long one server seed is used before it rotates and is revealed
+const SeedPeriod = time.Hour
+
+// Purpose identifies what a roll decided
+type Purpose string
+
+const (
+	PurposeLootDrop  Purpose = "loot.drop"
+	PurposeCraft     Purpose = "craft.complete"
+	PurposeDeathLoss Purpose = "vault.death_loss"
+	PurposeSpawn     Purpose = "animal.spawn"
+	PurposeCapture   Purpose = "animal.capture"
+	PurposeBattle    Purpose = "battle.turn"
+)
+
+// String returns string representation of Purpose
+func (p Purpose) String() string {
+	return string(p)
+}
+
+// SeedID identifies a seed by the start of its period
+type SeedID string
+
+// String returns string representation of SeedID
+func (id SeedID) String() string {
+	return string(id)
+}
+
+// SeedIDAt returns the ID of the seed in use at the given time
+func SeedIDAt(t time.Time) SeedID {
+	return SeedID(t.UTC().Truncate(SeedPeriod).Format("2006010215"))
+}
+
+// Start returns when the seed's period began
+func (id SeedID) Start() (time.Time, error) {
+	return time.Parse("2006010215", string(id))
+}
+
+// Seed is a server secret used for all rolls in one period. Its commitment is published
+// while the seed is in use and the secret is revealed once the period is over, so players
+// can check that no roll was changed after the fact.
+type Seed struct {
+	ID         SeedID    `json:"id"`
+	Secret     string    `json:"secret,omitempty"`
+	Commitment string    `json:"commitment"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// NewSeed creates a seed with a fresh random secret
+func NewSeed(id SeedID) (*Seed, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	encoded := hex.EncodeToString(secret)
+	return &Seed{
+		ID:         id,
+		Secret:     encoded,
+		Commitment: Commit(encoded),
+		CreatedAt:  time.Now(),
+	}, nil
+}
+
+// Commit returns the published commitment for a secret
+func Commit(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// IsRevealed checks if the seed's period is over and its secret may be published
+func (s *Seed) IsRevealed(now time.Time) bool {
+	start, err := s.ID.Start()
+	if err != nil {
+		return false
+	}
+	return !now.Before(start.Add(SeedPeriod))
+}
+
+// Public returns a copy of the seed that only includes the secret once it is revealed
+func (s *Seed) Public(now time.Time) *Seed {
+	public := *s
+	if !s.IsRevealed(now) {
+		public.Secret = ""
+	}
+	return &public
+}
+
+// Commitments lists the seed in use and the previous, revealed seed
+type Commitments struct {
+	Current  *Seed `json:"current"`
+	Previous *Seed `json:"previous,omitempty"`
+}
+
+// Stream is a deterministic source of random numbers derived from a seed secret and a stream key.
+// Draw n is HMAC-SHA256(secret, "<key>:<n>"), so anyone holding the revealed secret can
+// recompute every draw of a roll.
+type Stream struct {
+	secret  []byte
+	key     string
+	counter uint64
+	draws   []uint64
+}
+
+// NewStream creates a stream for one key
+func NewStream(secret string, key string) *Stream {
+	return &Stream{
+		secret: []byte(secret),
+		key:    key,
+	}
+}
+
+// EntityStreamKey returns the stream key for rolls about one entity. Streams keyed by
+// entity depend only on the seed and the entity, so re-simulating the same entities
+// against the same seed reproduces identical outcomes regardless of ordering.
+func EntityStreamKey(purpose Purpose, entityID string) string {
+	return purpose.String() + ":" + entityID
+}
+
+// Uint64 returns the next draw
+func (s *Stream) Uint64() uint64 {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(s.key + ":" + strconv.FormatUint(s.counter, 10)))
+	value := binary.BigEndian.Uint64(mac.Sum(nil)[:8])
+
+	s.counter++
+	s.draws = append(s.draws, value)
+	return value
+}
+
+// Int63 returns the next draw as a non-negative int64
+func (s *Stream) Int63() int64 {
+	return int64(s.Uint64() & (1<<63 - 1))
+}
+
+// Seed is a no-op; the stream is keyed by the seed secret and roll ID
+func (s *Stream) Seed(int64) {}
+
+// Draws returns the raw values drawn so far
+func (s *Stream) Draws() []uint64 {
+	return s.draws
+}
+
+// RollID uniquely identifies a roll
+type RollID string
+
+// NewRollID generates a new roll ID
+func NewRollID() RollID {
+	return RollID(uuid.New().String())
+}
+
+// String returns string representation of RollID
+func (id RollID) String() string {
+	return string(id)
+}
+
+// Roll is the audit record of one random outcome: what was rolled, with which seed,
+// every raw draw taken and the outcome they produced
+type Roll struct {
+	ID         RollID          `json:"id"`
+	Purpose    Purpose         `json:"purpose"`
+	UserID     string          `json:"user_id"`
+	SeedID     SeedID          `json:"seed_id"`
+	Commitment string          `json:"commitment"`
+	Stream     string          `json:"stream,omitempty"` // Stream key the draws came from; the roll ID when empty
+	Inputs     map[string]any  `json:"inputs,omitempty"`
+	Output     json.RawMessage `json:"output,omitempty"`
+	Draws      []string        `json:"draws"` // Hex encoded raw draws in order
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// StreamKey returns the key of the stream the roll drew from
+func (r *Roll) StreamKey() string {
+	if r.Stream == "" {
+		return r.ID.String() // Rolls recorded before entity streams
+	}
+	return r.Stream
+}
+
+// Session draws the random numbers for one roll and produces its audit record
+type Session struct {
+	roll   *Roll
+	secret string
+	stream *Stream
+}
+
+// NewSession starts a roll against a seed. When entityID is set the draws come from that
+// entity's stream; otherwise the roll gets a stream of its own.
+func NewSession(seed *Seed, purpose Purpose, userID string, entityID string, inputs map[string]any, now time.Time) *Session {
+	id := NewRollID()
+
+	key := id.String()
+	if entityID != "" {
+		key = EntityStreamKey(purpose, entityID)
+	}
+
+	return &Session{
+		roll: &Roll{
+			ID:         id,
+			Purpose:    purpose,
+			UserID:     userID,
+			SeedID:     seed.ID,
+			Commitment: seed.Commitment,
+			Stream:     key,
+			Inputs:     inputs,
+			CreatedAt:  now,
+		},
+		secret: seed.Secret,
+	}
+}
+
+// ID returns the ID of the roll being made
+func (s *Session) ID() RollID {
+	return s.roll.ID
+}
+
+// Rand returns a generator positioned at the start of the roll's stream. Calling it again
+// restarts the stream, so an update callback that is retried reproduces the same outcome.
+func (s *Session) Rand() *mathrand.Rand {
+	s.stream = NewStream(s.secret, s.roll.StreamKey())
+	return mathrand.New(s.stream)
+}
+
+// Finish records the outcome and the draws that produced it
+func (s *Session) Finish(output any) (*Roll, error) {
+	data, err := json.Marshal(output)
+	if err != nil {
+		return nil, err
+	}
+
+	roll := *s.roll
+	roll.Output = data
+	roll.Draws = make([]string, 0)
+	if s.stream != nil {
+		for _, draw := range s.stream.Draws() {
+			roll.Draws = append(roll.Draws, fmt.Sprintf("%016x", draw))
+		}
+	}
+
+	return &roll, nil
+}
+
+// Verify checks a roll against a revealed seed: the secret must match the commitment
+// published when the roll was made, and recomputing the stream must give the recorded draws
+func Verify(roll *Roll, seed *Seed) error {
+	if seed.ID != roll.SeedID {
+		return shared.NewDomainErrorf(shared.ErrCodeRollMismatch, "Roll was made with seed %s, not %s", roll.SeedID, seed.ID)
+	}
+	if seed.Secret == "" {
+		return shared.NewDomainErrorf(shared.ErrCodeSeedNotRevealed, "Seed %s has not been revealed yet", seed.ID)
+	}
+	if Commit(seed.Secret) != roll.Commitment {
+		return shared.NewDomainError(shared.ErrCodeRollMismatch, "Seed secret does not match the published commitment")
+	}
+
+	stream := NewStream(seed.Secret, roll.StreamKey())
+	for i, recorded := range roll.Draws {
+		if expected := fmt.Sprintf("%016x", stream.Uint64()); expected != recorded {
+			return shared.NewDomainErrorf(shared.ErrCodeRollMismatch, "Draw %d does not match the seed", i)
+		}
+	}
+
+	return nil
+}
+
+// Verification reports the result of checking a roll
+type Verification struct {
+	Roll     *Roll  `json:"roll"`
+	Seed     *Seed  `json:"seed"`
+	Revealed bool   `json:"revealed"`
+	Verified bool   `json:"verified"`
+	Reason   string `json:"reason,omitempty"`
+}
@@ -0,0 +1,186 @@
+package fairness
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// How long roll audit records are kept for verification
+	rollTTL = 30 * 24 * time.Hour
+	// Seeds outlive the rolls made with them so every stored roll stays verifiable
+	seedTTL = rollTTL + 24*time.Hour
+	// Number of recent rolls indexed per user
+	userRollHistory = 200
+)
+
+// RedisRepository implements Repository using Redis Hash for seeds and rolls
+type RedisRepository struct {
+	client *redis.Client
+}
+
+// NewRedisRepository creates a new Redis-based fairness repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+	}
+}
+
+// FindOrCreateSeed stores the seed only if no server has created it yet
+func (r *RedisRepository) FindOrCreateSeed(ctx context.Context, id SeedID, callback func() (*Seed, error)) (*Seed, error) {
+	existing, err := r.GetSeed(ctx, id)
+	if err != nil || existing != nil {
+		return existing, err
+	}
+
+	seed, err := callback()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	key := r.seedKey(id)
+	created, err := r.client.HSetNX(ctx, key, "data", string(data)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if !created {
+		// Another server won the race; use its seed
+		return r.GetSeed(ctx, id)
+	}
+
+	r.client.Expire(ctx, key, seedTTL)
+	return seed, nil
+}
+
+// GetSeed retrieves a seed by ID
+func (r *RedisRepository) GetSeed(ctx context.Context, id SeedID) (*Seed, error) {
+	data, err := r.client.HGet(ctx, r.seedKey(id), "data").Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	seed := &Seed{}
+	if err := json.Unmarshal([]byte(data), seed); err != nil {
+		return nil, err
+	}
+
+	return seed, nil
+}
+
+// SaveRoll stores a roll and pushes it onto the user's history index
+func (r *RedisRepository) SaveRoll(ctx context.Context, roll *Roll) error {
+	data, err := json.Marshal(roll)
+	if err != nil {
+		return err
+	}
+
+	key := r.rollKey(roll.ID)
+	indexKey := r.userIndexKey(roll.UserID)
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, "data", string(data))
+		pipe.Expire(ctx, key, rollTTL)
+
+		if roll.UserID != "" {
+			pipe.LPush(ctx, indexKey, roll.ID.String())
+			pipe.LTrim(ctx, indexKey, 0, userRollHistory-1)
+			pipe.Expire(ctx, indexKey, rollTTL)
+		}
+
+		return nil
+	})
+
+	return err
+}
+
+// GetRoll retrieves a roll by ID
+func (r *RedisRepository) GetRoll(ctx context.Context, id RollID) (*Roll, error) {
+	data, err := r.client.HGet(ctx, r.rollKey(id), "data").Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	roll := &Roll{}
+	if err := json.Unmarshal([]byte(data), roll); err != nil {
+		return nil, err
+	}
+
+	return roll, nil
+}
+
+// GetRollsByUser retrieves a user's most recent rolls
+func (r *RedisRepository) GetRollsByUser(ctx context.Context, userID string, limit int) ([]*Roll, error) {
+	indexKey := r.userIndexKey(userID)
+
+	ids, err := r.client.LRange(ctx, indexKey, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	rolls := make([]*Roll, 0, len(ids))
+	for _, id := range ids {
+		roll, err := r.GetRoll(ctx, RollID(id))
+		if err != nil {
+			return nil, err
+		}
+
+		// Roll keys expire on their own; drop the dangling index entry
+		if roll == nil {
+			r.client.LRem(ctx, indexKey, 0, id)
+			continue
+		}
+
+		rolls = append(rolls, roll)
+	}
+
+	return rolls, nil
+}
+
+// DeleteRollsByUser removes a user's rolls and their history index
+func (r *RedisRepository) DeleteRollsByUser(ctx context.Context, userID string) error {
+	indexKey := r.userIndexKey(userID)
+
+	ids, err := r.client.LRange(ctx, indexKey, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(ids)+1)
+	for _, id := range ids {
+		keys = append(keys, r.rollKey(RollID(id)))
+	}
+	keys = append(keys, indexKey)
+
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// seedKey returns the Redis key for a seed
+func (r *RedisRepository) seedKey(id SeedID) string {
+	return fmt.Sprintf("rng:seed:%s", id.String())
+}
+
+// rollKey returns the Redis key for a roll
+func (r *RedisRepository) rollKey(id RollID) string {
+	return fmt.Sprintf("rng:roll:%s", id.String())
+}
+
+// userIndexKey returns the roll history index key for a user
+func (r *RedisRepository) userIndexKey(userID string) string {
+	return fmt.Sprintf("idx:rng:user:%s", userID)
+}
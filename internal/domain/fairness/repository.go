@@ -0,0 +1,27 @@
+package fairness
+
+import (
+	"context"
+)
+
+// Repository defines the interface for seed and roll audit persistence
+type Repository interface {
+	// FindOrCreateSeed returns the seed with the given ID, creating it with callback if it does not
+	// exist yet. Concurrent callers on different servers all receive the seed that was stored first.
+	FindOrCreateSeed(ctx context.Context, id SeedID, callback func() (*Seed, error)) (*Seed, error)
+
+	// GetSeed retrieves a seed by ID (read-only), returning nil if it does not exist
+	GetSeed(ctx context.Context, id SeedID) (*Seed, error)
+
+	// SaveRoll stores a roll audit record and indexes it for its user
+	SaveRoll(ctx context.Context, roll *Roll) error
+
+	// GetRoll retrieves a roll by ID (read-only), returning nil if it does not exist
+	GetRoll(ctx context.Context, id RollID) (*Roll, error)
+
+	// GetRollsByUser retrieves a user's most recent rolls, newest first (read-only)
+	GetRollsByUser(ctx context.Context, userID string, limit int) ([]*Roll, error)
+
+	// DeleteRollsByUser removes a user's rolls and their history index
+	DeleteRollsByUser(ctx context.Context, userID string) error
+}
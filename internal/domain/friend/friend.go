@@ -0,0 +1,132 @@
+package friend
+
+import (
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+const (
+	// MaxFriends is how many friends a trainer can have
+	MaxFriends = 100
+	// MaxIncomingRequests is how many unanswered friend requests a trainer can receive
+	MaxIncomingRequests = 50
+)
+
+// RequestStatus is what sending a friend request did
+type RequestStatus string
+
+const (
+	// RequestPending waits for the other trainer to accept
+	RequestPending RequestStatus = "pending"
+	// RequestAccepted made the trainers friends, as the other trainer had already asked
+	RequestAccepted RequestStatus = "accepted"
+)
+
+// Link is the relation between a trainer and another trainer, as seen from the trainer
+type Link struct {
+	UserID  string
+	OtherID string
+
+	Friends  bool // Both trainers are friends
+	Sent     bool // The trainer asked the other trainer to be friends
+	Received bool // The other trainer asked the trainer to be friends
+
+	UserFriends   int // Friends the trainer has
+	OtherFriends  int // Friends the other trainer has
+	OtherIncoming int // Unanswered requests the other trainer has received
+}
+
+// Request asks the other trainer to be friends. If they already asked, the trainers become
+// friends right away.
+func (l *Link) Request() (RequestStatus, error) {
+	if l.UserID == l.OtherID {
+		return "", shared.ErrInvalidInput("Cannot befriend yourself")
+	}
+	if l.Friends {
+		return "", shared.ErrAlreadyExists("friendship")
+	}
+	if l.Received {
+		if err := l.Accept(); err != nil {
+			return "", err
+		}
+		return RequestAccepted, nil
+	}
+	if l.Sent {
+		return "", shared.ErrAlreadyExists("friend request")
+	}
+	if l.UserFriends >= MaxFriends {
+		return "", shared.NewDomainErrorf(shared.ErrCodeFriendLimit, "You already have %d friends", MaxFriends)
+	}
+	if l.OtherIncoming >= MaxIncomingRequests {
+		return "", shared.NewDomainError(shared.ErrCodeFriendRequestLimit, "This trainer has too many pending friend requests")
+	}
+
+	l.Sent = true
+	return RequestPending, nil
+}
+
+// Accept accepts the other trainer's friend request
+func (l *Link) Accept() error {
+	if l.Friends {
+		return shared.ErrAlreadyExists("friendship")
+	}
+	if !l.Received {
+		return shared.ErrNotFound("Friend request")
+	}
+	if l.UserFriends >= MaxFriends {
+		return shared.NewDomainErrorf(shared.ErrCodeFriendLimit, "You already have %d friends", MaxFriends)
+	}
+	if l.OtherFriends >= MaxFriends {
+		return shared.NewDomainError(shared.ErrCodeFriendLimit, "This trainer has too many friends")
+	}
+
+	l.Friends = true
+	l.Sent = false
+	l.Received = false
+	l.UserFriends++
+	l.OtherFriends++
+	return nil
+}
+
+// Remove ends the friendship, withdraws the trainer's request or declines the other
+// trainer's one
+func (l *Link) Remove() error {
+	if !l.Friends && !l.Sent && !l.Received {
+		return shared.ErrNotFound("Friend")
+	}
+
+	if l.Friends {
+		l.UserFriends--
+		l.OtherFriends--
+	}
+	l.Friends = false
+	l.Sent = false
+	l.Received = false
+	return nil
+}
+
+// List is a trainer's friends and pending friend requests by user ID
+type List struct {
+	Friends  []string
+	Incoming []string // Trainers who asked to be friends
+	Outgoing []string // Trainers the trainer asked to be friends
+}
+
+// Friend is a friend or a pending request as the player sees it
+type Friend struct {
+	Profile *trainer.PublicProfile `json:"profile"`
+	Online  bool                   `json:"online"`
+}
+
+// Friends is a trainer's friends and pending requests as the player sees them
+type Friends struct {
+	Friends  []Friend `json:"friends"`
+	Incoming []Friend `json:"incoming"` // Trainers who asked to be friends
+	Outgoing []Friend `json:"outgoing"` // Trainers the player asked to be friends
+}
+
+// PresenceChange tells a player that a friend came online or went offline
+type PresenceChange struct {
+	Nickname string `json:"nickname"`
+	Online   bool   `json:"online"`
+}
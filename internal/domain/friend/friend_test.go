@@ -0,0 +1,69 @@
+package friend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/shared/sharedtest"
+)
+
+func TestLink_Request(t *testing.T) {
+	link := &Link{UserID: "a", OtherID: "b"}
+	status, err := link.Request()
+	require.NoError(t, err)
+	assert.Equal(t, RequestPending, status)
+	assert.True(t, link.Sent)
+
+	_, err = link.Request()
+	assert.Equal(t, shared.ErrCodeAlreadyExists, sharedtest.ErrorCode(t, err))
+}
+
+func TestLink_RequestAcceptsReceivedRequest(t *testing.T) {
+	link := &Link{UserID: "a", OtherID: "b", Received: true}
+	status, err := link.Request()
+	require.NoError(t, err)
+	assert.Equal(t, RequestAccepted, status)
+	assert.True(t, link.Friends)
+	assert.False(t, link.Received)
+}
+
+func TestLink_RequestLimits(t *testing.T) {
+	_, err := (&Link{UserID: "a", OtherID: "a"}).Request()
+	assert.Equal(t, shared.ErrCodeInvalidInput, sharedtest.ErrorCode(t, err))
+
+	_, err = (&Link{UserID: "a", OtherID: "b", UserFriends: MaxFriends}).Request()
+	assert.Equal(t, shared.ErrCodeFriendLimit, sharedtest.ErrorCode(t, err))
+
+	_, err = (&Link{UserID: "a", OtherID: "b", OtherIncoming: MaxIncomingRequests}).Request()
+	assert.Equal(t, shared.ErrCodeFriendRequestLimit, sharedtest.ErrorCode(t, err))
+}
+
+func TestLink_Accept(t *testing.T) {
+	err := (&Link{UserID: "a", OtherID: "b"}).Accept()
+	assert.Equal(t, shared.ErrCodeNotFound, sharedtest.ErrorCode(t, err))
+
+	err = (&Link{UserID: "a", OtherID: "b", Received: true, OtherFriends: MaxFriends}).Accept()
+	assert.Equal(t, shared.ErrCodeFriendLimit, sharedtest.ErrorCode(t, err))
+
+	link := &Link{UserID: "a", OtherID: "b", Received: true}
+	require.NoError(t, link.Accept())
+	assert.True(t, link.Friends)
+	assert.Equal(t, 1, link.UserFriends)
+}
+
+func TestLink_Remove(t *testing.T) {
+	err := (&Link{UserID: "a", OtherID: "b"}).Remove()
+	assert.Equal(t, shared.ErrCodeNotFound, sharedtest.ErrorCode(t, err))
+
+	link := &Link{UserID: "a", OtherID: "b", Friends: true, UserFriends: 1, OtherFriends: 1}
+	require.NoError(t, link.Remove())
+	assert.False(t, link.Friends)
+	assert.Equal(t, 0, link.UserFriends)
+
+	link = &Link{UserID: "a", OtherID: "b", Received: true}
+	require.NoError(t, link.Remove())
+	assert.False(t, link.Received)
+}
@@ -0,0 +1,262 @@
+package friend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// PresenceTTL is how long a player stays online without their server refreshing it, so
+// players of a server that stopped go offline on their own
+const PresenceTTL = 90 * time.Second
+
+// movingKeyPrefix is the key the movement simulation keeps while a trainer moves
+const movingKeyPrefix = "moving:trainer:"
+
+// RedisRepository implements Repository with a Redis set per trainer for friends,
+// received requests and sent requests
+type RedisRepository struct {
+	client *redis.Client
+}
+
+// NewRedisRepository creates a new Redis-based friend repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+	}
+}
+
+// FindLinkAndUpdate implements IoC pattern for update operations on both trainers' sets
+func (r *RedisRepository) FindLinkAndUpdate(ctx context.Context, userID, otherID string, callback func(*Link) error) error {
+	keys := []string{
+		r.friendsKey(userID), r.incomingKey(userID), r.outgoingKey(userID),
+		r.friendsKey(otherID), r.incomingKey(otherID), r.outgoingKey(otherID),
+	}
+
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		var friends, sent, received *redis.BoolCmd
+		var userFriends, otherFriends, otherIncoming *redis.IntCmd
+		_, err := tx.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+			friends = pipe.SIsMember(ctx, r.friendsKey(userID), otherID)
+			sent = pipe.SIsMember(ctx, r.outgoingKey(userID), otherID)
+			received = pipe.SIsMember(ctx, r.incomingKey(userID), otherID)
+			userFriends = pipe.SCard(ctx, r.friendsKey(userID))
+			otherFriends = pipe.SCard(ctx, r.friendsKey(otherID))
+			otherIncoming = pipe.SCard(ctx, r.incomingKey(otherID))
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to get friend link: %w", err)
+		}
+
+		link := &Link{
+			UserID:        userID,
+			OtherID:       otherID,
+			Friends:       friends.Val(),
+			Sent:          sent.Val(),
+			Received:      received.Val(),
+			UserFriends:   int(userFriends.Val()),
+			OtherFriends:  int(otherFriends.Val()),
+			OtherIncoming: int(otherIncoming.Val()),
+		}
+		before := *link
+
+		if err := callback(link); err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if link.Friends != before.Friends {
+				r.setMember(ctx, pipe, r.friendsKey(userID), otherID, link.Friends)
+				r.setMember(ctx, pipe, r.friendsKey(otherID), userID, link.Friends)
+			}
+			if link.Sent != before.Sent {
+				r.setMember(ctx, pipe, r.outgoingKey(userID), otherID, link.Sent)
+				r.setMember(ctx, pipe, r.incomingKey(otherID), userID, link.Sent)
+			}
+			if link.Received != before.Received {
+				r.setMember(ctx, pipe, r.incomingKey(userID), otherID, link.Received)
+				r.setMember(ctx, pipe, r.outgoingKey(otherID), userID, link.Received)
+			}
+			return nil
+		})
+		return err
+	}, keys...)
+}
+
+// GetList retrieves a trainer's friends and pending requests
+func (r *RedisRepository) GetList(ctx context.Context, userID string) (*List, error) {
+	var friends, incoming, outgoing *redis.StringSliceCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		friends = pipe.SMembers(ctx, r.friendsKey(userID))
+		incoming = pipe.SMembers(ctx, r.incomingKey(userID))
+		outgoing = pipe.SMembers(ctx, r.outgoingKey(userID))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get friends: %w", err)
+	}
+
+	return &List{
+		Friends:  friends.Val(),
+		Incoming: incoming.Val(),
+		Outgoing: outgoing.Val(),
+	}, nil
+}
+
+// GetFriendIDs retrieves the user IDs of a trainer's friends
+func (r *RedisRepository) GetFriendIDs(ctx context.Context, userID string) ([]string, error) {
+	friendIDs, err := r.client.SMembers(ctx, r.friendsKey(userID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get friends: %w", err)
+	}
+	return friendIDs, nil
+}
+
+// DeleteUser removes a trainer's sets and their entries in the other trainers' sets
+func (r *RedisRepository) DeleteUser(ctx context.Context, userID string) error {
+	list, err := r.GetList(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, friendID := range list.Friends {
+			pipe.SRem(ctx, r.friendsKey(friendID), userID)
+		}
+		for _, requesterID := range list.Incoming {
+			pipe.SRem(ctx, r.outgoingKey(requesterID), userID)
+		}
+		for _, targetID := range list.Outgoing {
+			pipe.SRem(ctx, r.incomingKey(targetID), userID)
+		}
+		pipe.Del(ctx, r.friendsKey(userID), r.incomingKey(userID), r.outgoingKey(userID))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete friends: %w", err)
+	}
+
+	return nil
+}
+
+// setMember adds or removes a set member
+func (r *RedisRepository) setMember(ctx context.Context, pipe redis.Pipeliner, key, member string, present bool) {
+	if present {
+		pipe.SAdd(ctx, key, member)
+	} else {
+		pipe.SRem(ctx, key, member)
+	}
+}
+
+// friendsKey returns the set of a trainer's friends
+func (r *RedisRepository) friendsKey(userID string) string {
+	return fmt.Sprintf("friend:list:%s", userID)
+}
+
+// incomingKey returns the set of trainers who asked a trainer to be friends
+func (r *RedisRepository) incomingKey(userID string) string {
+	return fmt.Sprintf("friend:incoming:%s", userID)
+}
+
+// outgoingKey returns the set of trainers a trainer asked to be friends
+func (r *RedisRepository) outgoingKey(userID string) string {
+	return fmt.Sprintf("friend:outgoing:%s", userID)
+}
+
+// RedisPresenceRepository implements PresenceRepository with expiring keys
+type RedisPresenceRepository struct {
+	client *redis.Client
+}
+
+// NewRedisPresenceRepository creates a new Redis-based presence repository
+func NewRedisPresenceRepository(client *redis.Client) PresenceRepository {
+	return &RedisPresenceRepository{
+		client: client,
+	}
+}
+
+// SetOnline sets or refreshes the players' presence keys
+func (r *RedisPresenceRepository) SetOnline(ctx context.Context, userIDs []string) ([]bool, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	created := make([]*redis.BoolCmd, len(userIDs))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, userID := range userIDs {
+			created[i] = pipe.SetNX(ctx, r.presenceKey(userID), 1, PresenceTTL)
+			pipe.Expire(ctx, r.presenceKey(userID), PresenceTTL)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to set presence: %w", err)
+	}
+
+	cameOnline := make([]bool, len(userIDs))
+	for i, cmd := range created {
+		cameOnline[i] = cmd.Val()
+	}
+	return cameOnline, nil
+}
+
+// SetOffline deletes the player's presence key
+func (r *RedisPresenceRepository) SetOffline(ctx context.Context, userID string) error {
+	if err := r.client.Del(ctx, r.presenceKey(userID)).Err(); err != nil {
+		return fmt.Errorf("failed to clear presence: %w", err)
+	}
+	return nil
+}
+
+// AreOnline checks the players' presence keys and the movement simulation's moving keys
+func (r *RedisPresenceRepository) AreOnline(ctx context.Context, userIDs []string) ([]bool, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	exists := make([]*redis.IntCmd, len(userIDs))
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i, userID := range userIDs {
+			exists[i] = pipe.Exists(ctx, r.presenceKey(userID), movingKeyPrefix+userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presence: %w", err)
+	}
+
+	online := make([]bool, len(userIDs))
+	for i, cmd := range exists {
+		online[i] = cmd.Val() > 0
+	}
+	return online, nil
+}
+
+// ListOnline scans the presence keys and the movement simulation's moving keys
+func (r *RedisPresenceRepository) ListOnline(ctx context.Context) ([]string, error) {
+	seen := make(map[string]bool)
+	userIDs := make([]string, 0)
+	for _, prefix := range []string{"presence:", movingKeyPrefix} {
+		iter := r.client.Scan(ctx, 0, prefix+"*", 100).Iterator()
+		for iter.Next(ctx) {
+			userID := strings.TrimPrefix(iter.Val(), prefix)
+			if !seen[userID] {
+				seen[userID] = true
+				userIDs = append(userIDs, userID)
+			}
+		}
+		if err := iter.Err(); err != nil {
+			return nil, fmt.Errorf("failed to list presence: %w", err)
+		}
+	}
+	return userIDs, nil
+}
+
+// presenceKey returns the key that exists while a player is connected
+func (r *RedisPresenceRepository) presenceKey(userID string) string {
+	return fmt.Sprintf("presence:%s", userID)
+}
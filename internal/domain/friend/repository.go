@@ -0,0 +1,37 @@
+package friend
+
+import (
+	"context"
+)
+
+// Repository defines the interface for friendship persistence operations with IoC pattern
+type Repository interface {
+	// FindLinkAndUpdate loads the relation between two trainers and applies callback for
+	// atomic update of both trainers' friends and requests
+	FindLinkAndUpdate(ctx context.Context, userID, otherID string, callback func(*Link) error) error
+
+	// GetList retrieves a trainer's friends and pending requests (read-only)
+	GetList(ctx context.Context, userID string) (*List, error)
+
+	// GetFriendIDs retrieves the user IDs of a trainer's friends (read-only)
+	GetFriendIDs(ctx context.Context, userID string) ([]string, error)
+
+	// DeleteUser removes a trainer's friendships and requests, from the other trainers' lists too
+	DeleteUser(ctx context.Context, userID string) error
+}
+
+// PresenceRepository tracks which players are connected to any server instance
+type PresenceRepository interface {
+	// SetOnline marks players as connected until their presence expires, reporting
+	// for each whether they were offline before
+	SetOnline(ctx context.Context, userIDs []string) ([]bool, error)
+
+	// SetOffline marks a player as disconnected
+	SetOffline(ctx context.Context, userID string) error
+
+	// AreOnline reports for each player whether they are connected or moving in the world
+	AreOnline(ctx context.Context, userIDs []string) ([]bool, error)
+
+	// ListOnline returns every player connected or moving in the world
+	ListOnline(ctx context.Context) ([]string, error)
+}
@@ -0,0 +1,59 @@
+package idle
+
+import (
+	"context"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultTimeout is how long a player may go without input before they are AFK
+	DefaultTimeout = 5 * time.Minute
+	// ActivityResolution is how often a player's activity is recorded at most. It only needs
+	// to be fine next to the timeout.
+	ActivityResolution = 15 * time.Second
+	// AFKRetention is how long players stay AFK without coming back before they are
+	// forgotten, e.g. ones whose disconnect went unnoticed
+	AFKRetention = 24 * time.Hour
+)
+
+// readVerbs start the names of methods that only read. Clients call them on their own, e.g.
+// polling positions, so they don't show the player is there.
+var readVerbs = []string{"Get", "List", "Fetch", "Status", "Search"}
+
+// IsInput checks if calling a JSON-RPC method is something the player did
+func IsInput(method string) bool {
+	_, name, ok := strings.Cut(method, ".")
+	if !ok {
+		return false
+	}
+	for _, verb := range readVerbs {
+		if strings.HasPrefix(name, verb) {
+			return false
+		}
+	}
+	return true
+}
+
+// Change tells a player they went AFK or came back
+type Change struct {
+	AFK   bool       `json:"afk"`
+	Since *time.Time `json:"since,omitempty"` // When they went AFK
+}
+
+// Repository keeps when players were last active and who is AFK
+type Repository interface {
+	// Active records that a player did something at a time, taking them out of the AFK
+	// state; returned reports whether they were AFK
+	Active(ctx context.Context, userID string, at time.Time) (returned bool, err error)
+
+	// MarkIdle moves every player not active since before into the AFK state as of now and
+	// returns them. Players AFK longer than AFKRetention are forgotten.
+	MarkIdle(ctx context.Context, before, now time.Time) ([]string, error)
+
+	// AFK returns the players in the AFK state
+	AFK(ctx context.Context) ([]string, error)
+
+	// DeleteUser forgets a player's activity and AFK state
+	DeleteUser(ctx context.Context, userID string) error
+}
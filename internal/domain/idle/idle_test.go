@@ -0,0 +1,21 @@
+package idle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsInput(t *testing.T) {
+	assert.True(t, IsInput("trainer.Move"))
+	assert.True(t, IsInput("bullet.Fire"))
+	assert.True(t, IsInput("idle.Ack"))
+
+	// Reads clients make on their own
+	assert.False(t, IsInput("trainer.FetchPosition"))
+	assert.False(t, IsInput("trainer.Get"))
+	assert.False(t, IsInput("friend.List"))
+	assert.False(t, IsInput("trainer.Status"))
+
+	assert.False(t, IsInput("health"))
+}
@@ -0,0 +1,86 @@
+package idle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	activeKey = "idle:active" // Players by when they were last active, Unix milliseconds
+	afkKey    = "idle:afk"    // AFK players by when they went AFK, Unix milliseconds
+)
+
+// markIdle moves the members of KEYS[1] scored below ARGV[1] into KEYS[2] scored ARGV[2] and
+// drops members of KEYS[2] scored below ARGV[3]. Returns the members moved. Running it as a
+// script keeps players active meanwhile from being moved.
+var markIdle = redis.NewScript(`
+local idle = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', '(' .. ARGV[1])
+for _, id in ipairs(idle) do
+	redis.call('ZREM', KEYS[1], id)
+	redis.call('ZADD', KEYS[2], ARGV[2], id)
+end
+redis.call('ZREMRANGEBYSCORE', KEYS[2], '-inf', '(' .. ARGV[3])
+return idle
+`)
+
+// RedisRepository implements Repository with sorted sets of active and AFK players
+type RedisRepository struct {
+	client *redis.Client
+}
+
+// NewRedisRepository creates a new Redis-based idle repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+	}
+}
+
+// Active scores the player by at and takes them off the AFK set in one transaction
+func (r *RedisRepository) Active(ctx context.Context, userID string, at time.Time) (bool, error) {
+	var removed *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, activeKey, redis.Z{Score: float64(at.UnixMilli()), Member: userID})
+		removed = pipe.ZRem(ctx, afkKey, userID)
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to record activity: %w", err)
+	}
+	return removed.Val() > 0, nil
+}
+
+// MarkIdle moves the idle players with a script
+func (r *RedisRepository) MarkIdle(ctx context.Context, before, now time.Time) ([]string, error) {
+	forgotten := now.Add(-AFKRetention)
+	idle, err := markIdle.Run(ctx, r.client, []string{activeKey, afkKey},
+		before.UnixMilli(), now.UnixMilli(), forgotten.UnixMilli()).StringSlice()
+	if err != nil {
+		return nil, fmt.Errorf("failed to mark idle players: %w", err)
+	}
+	return idle, nil
+}
+
+// AFK returns the members of the AFK set
+func (r *RedisRepository) AFK(ctx context.Context) ([]string, error) {
+	userIDs, err := r.client.ZRange(ctx, afkKey, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get AFK players: %w", err)
+	}
+	return userIDs, nil
+}
+
+// DeleteUser removes the player from both sets
+func (r *RedisRepository) DeleteUser(ctx context.Context, userID string) error {
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, activeKey, userID)
+		pipe.ZRem(ctx, afkKey, userID)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete idle state: %w", err)
+	}
+	return nil
+}
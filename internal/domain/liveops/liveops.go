@@ -0,0 +1,101 @@
+package liveops
+
+import (
+	"regexp"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+const (
+	// MaxSourceSize is the largest script source accepted, in bytes
+	MaxSourceSize = 64 << 10
+	// MaxEffects is how many changes to the game a single run may make
+	MaxEffects = 1000
+	// MaxNoticeLength is the longest notice a script may send to players
+	MaxNoticeLength = 500
+	// MaxGrantQuantity is the most items a script may grant in one call
+	MaxGrantQuantity = 1000
+)
+
+// scriptNamePattern keeps script names short and safe to use in keys and logs
+var scriptNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,49}$`)
+
+// Script is a live-ops event script uploaded by an admin. Uploading a script under an
+// existing name replaces its source and bumps its version.
+type Script struct {
+	Name       string           `json:"name"`
+	Source     string           `json:"source"`
+	Version    int              `json:"version"`
+	UploadedBy string           `json:"uploaded_by"`
+	UploadedAt shared.Timestamp `json:"uploaded_at"`
+	LastRun    *Run             `json:"last_run,omitempty"`
+}
+
+// Run records one execution of a script. Effects describe the changes it made, in order;
+// a run that failed part way keeps the effects made before it stopped.
+type Run struct {
+	Script    string           `json:"script"`
+	Version   int              `json:"version"`
+	RunBy     string           `json:"run_by"`
+	RunAt     shared.Timestamp `json:"run_at"`
+	DryRun    bool             `json:"dry_run"`
+	Effects   []string         `json:"effects"`
+	Output    string           `json:"output"`
+	Truncated bool             `json:"truncated,omitempty"`
+	Error     string           `json:"error,omitempty"`
+}
+
+// NewScript creates a new script, validating its name and size. Whether the source
+// compiles is checked by the runtime that runs it.
+func NewScript(name, source, uploadedBy string) (*Script, error) {
+	if !scriptNamePattern.MatchString(name) {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Script name must be 1-50 lowercase letters, digits, '-' or '_'")
+	}
+	if len(source) == 0 {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Script source is empty")
+	}
+	if len(source) > MaxSourceSize {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Script source must be at most %d bytes", MaxSourceSize)
+	}
+
+	return &Script{
+		Name:       name,
+		Source:     source,
+		Version:    1,
+		UploadedBy: uploadedBy,
+		UploadedAt: shared.NewTimestamp(),
+	}, nil
+}
+
+// Replace swaps in the source of a newly uploaded script with the same name
+func (s *Script) Replace(uploaded *Script) {
+	s.Source = uploaded.Source
+	s.Version++
+	s.UploadedBy = uploaded.UploadedBy
+	s.UploadedAt = uploaded.UploadedAt
+}
+
+// NewRun starts the record of a run of the script's current version
+func (s *Script) NewRun(runBy string, dryRun bool) *Run {
+	return &Run{
+		Script:  s.Name,
+		Version: s.Version,
+		RunBy:   runBy,
+		RunAt:   shared.NewTimestamp(),
+		DryRun:  dryRun,
+		Effects: make([]string, 0),
+	}
+}
+
+// CheckLimit fails once the run made MaxEffects changes, before it makes another
+func (r *Run) CheckLimit() error {
+	if len(r.Effects) >= MaxEffects {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidOperation, "Script made more than %d changes", MaxEffects)
+	}
+	return nil
+}
+
+// AddEffect records a change the run made
+func (r *Run) AddEffect(effect string) {
+	r.Effects = append(r.Effects, effect)
+}
@@ -0,0 +1,41 @@
+package liveops
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewScript(t *testing.T) {
+	script, err := NewScript("halloween-2026", `notify("Boo!")`, "admin-1")
+	require.NoError(t, err)
+	assert.Equal(t, 1, script.Version)
+
+	_, err = NewScript("Halloween 2026", `notify("Boo!")`, "admin-1")
+	assert.Error(t, err, "names with spaces or capitals must be rejected")
+
+	_, err = NewScript("big", strings.Repeat("#", MaxSourceSize+1), "admin-1")
+	assert.Error(t, err)
+
+	replacement, err := NewScript("halloween-2026", `notify("Boo again!")`, "admin-2")
+	require.NoError(t, err)
+	script.Replace(replacement)
+	assert.Equal(t, 2, script.Version)
+	assert.Equal(t, "admin-2", script.UploadedBy)
+	assert.Equal(t, replacement.Source, script.Source)
+}
+
+func TestRun_AddEffect(t *testing.T) {
+	script, err := NewScript("grants", `pass`, "admin-1")
+	require.NoError(t, err)
+	run := script.NewRun("admin-1", false)
+
+	for range MaxEffects {
+		require.NoError(t, run.CheckLimit())
+		run.AddEffect("granted")
+	}
+	assert.Error(t, run.CheckLimit(), "runs must stop changing the game after MaxEffects")
+	assert.Len(t, run.Effects, MaxEffects)
+}
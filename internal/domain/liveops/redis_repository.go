@@ -0,0 +1,79 @@
+package liveops
+
+import (
+	"context"
+	"sort"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// scriptIndexKey holds the names of all scripts
+const scriptIndexKey = "idx:liveops:scripts"
+
+// RedisRepository implements Repository with one JSON value per script
+type RedisRepository struct {
+	client *redis.Client
+	docs   *redisx.Repository[Script]
+}
+
+// NewRedisRepository creates a new Redis-based script repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+		docs: redisx.NewRepository(client, "liveops:script:", redisx.Options[Script]{
+			Codec: redisx.StringCodec[Script]{},
+			Indexes: []redisx.Index[Script]{
+				// All scripts
+				redisx.SetIndex[Script]{Key: func(*Script) string { return scriptIndexKey }},
+			},
+			NotFound: func() error { return shared.ErrNotFound("Script") },
+		}),
+	}
+}
+
+// FindOneAndUpsert implements IoC pattern with callback for concurrency control
+func (r *RedisRepository) FindOneAndUpsert(ctx context.Context, name string, callback func(*Script) (*Script, error)) error {
+	return r.docs.FindOneAndUpsert(ctx, name, callback)
+}
+
+// FindOneAndUpdate implements IoC pattern for update operations
+func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, name string, callback func(*Script) (*Script, error)) error {
+	return r.docs.FindOneAndUpdate(ctx, name, callback)
+}
+
+// GetByName retrieves a script by name
+func (r *RedisRepository) GetByName(ctx context.Context, name string) (*Script, error) {
+	return r.docs.GetByID(ctx, name)
+}
+
+// List retrieves every script ordered by name
+func (r *RedisRepository) List(ctx context.Context) ([]*Script, error) {
+	names, err := r.client.SMembers(ctx, scriptIndexKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	scripts := make([]*Script, 0, len(names))
+	for _, name := range names {
+		script, err := r.GetByName(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if script == nil {
+			// Removed since the index was read
+			continue
+		}
+		scripts = append(scripts, script)
+	}
+
+	return scripts, nil
+}
+
+// Delete removes a script
+func (r *RedisRepository) Delete(ctx context.Context, name string) error {
+	return r.docs.Delete(ctx, name)
+}
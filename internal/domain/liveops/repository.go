@@ -0,0 +1,24 @@
+package liveops
+
+import (
+	"context"
+)
+
+// Repository defines the interface for script persistence operations with IoC pattern
+type Repository interface {
+	// FindOneAndUpsert finds a script by name and applies callback for atomic upsert;
+	// callback receives nil if there is no script with the name yet
+	FindOneAndUpsert(ctx context.Context, name string, callback func(*Script) (*Script, error)) error
+
+	// FindOneAndUpdate finds a script by name and applies callback for atomic update
+	FindOneAndUpdate(ctx context.Context, name string, callback func(*Script) (*Script, error)) error
+
+	// GetByName retrieves a script by name (read-only), returning nil if there is none
+	GetByName(ctx context.Context, name string) (*Script, error)
+
+	// List retrieves every script ordered by name (read-only)
+	List(ctx context.Context) ([]*Script, error)
+
+	// Delete removes a script
+	Delete(ctx context.Context, name string) error
+}
@@ -0,0 +1,83 @@
+package loot
+
+import (
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// Event types
+const (
+	LootDroppedEventType  = "loot.dropped"
+	LootPickedUpEventType = "loot.picked_up"
+)
+
+// LootDroppedEvent represents loot rolled from a defeated animal
+type LootDroppedEvent struct {
+	shared.BaseEvent
+}
+
+// LootDroppedEventData holds the event data
+type LootDroppedEventData struct {
+	AnimalID   string `json:"animal_id"`
+	AnimalType string `json:"animal_type"`
+	AnimalLvl  int    `json:"animal_level"`
+	TrainerID  string `json:"trainer_id"`
+	Mode       string `json:"mode"`
+	Drops      []Drop `json:"drops"`
+}
+
+// NewLootDroppedEvent creates a new loot dropped event
+func NewLootDroppedEvent(animalID, animalType string, animalLevel int, trainerID string, mode DeliveryMode, drops []Drop) (LootDroppedEvent, error) {
+	data := LootDroppedEventData{
+		AnimalID:   animalID,
+		AnimalType: animalType,
+		AnimalLvl:  animalLevel,
+		TrainerID:  trainerID,
+		Mode:       mode.String(),
+		Drops:      drops,
+	}
+
+	baseEvent, err := shared.NewBaseEvent(
+		LootDroppedEventType,
+		animalID,
+		"loot",
+		data,
+	)
+	if err != nil {
+		return LootDroppedEvent{}, err
+	}
+
+	return LootDroppedEvent{BaseEvent: baseEvent}, nil
+}
+
+// LootPickedUpEvent represents a trainer collecting a world pickup
+type LootPickedUpEvent struct {
+	shared.BaseEvent
+}
+
+// LootPickedUpEventData holds the event data
+type LootPickedUpEventData struct {
+	PickupID  string `json:"pickup_id"`
+	TrainerID string `json:"trainer_id"`
+	Drop      Drop   `json:"drop"`
+}
+
+// NewLootPickedUpEvent creates a new loot picked up event
+func NewLootPickedUpEvent(pickupID, trainerID string, drop Drop) (LootPickedUpEvent, error) {
+	data := LootPickedUpEventData{
+		PickupID:  pickupID,
+		TrainerID: trainerID,
+		Drop:      drop,
+	}
+
+	baseEvent, err := shared.NewBaseEvent(
+		LootPickedUpEventType,
+		pickupID,
+		"loot",
+		data,
+	)
+	if err != nil {
+		return LootPickedUpEvent{}, err
+	}
+
+	return LootPickedUpEvent{BaseEvent: baseEvent}, nil
+}
@@ -0,0 +1,30 @@
+package loot
+
+import (
+	"context"
+	"time"
+)
+
+// LedgerEntry records the drops rolled for one defeated animal
+type LedgerEntry struct {
+	UserID     string       `json:"user_id"`
+	AnimalID   string       `json:"animal_id"`
+	AnimalType string       `json:"animal_type"`
+	Level      int          `json:"level"`
+	Mode       DeliveryMode `json:"mode"`
+	Drops      []Drop       `json:"drops"`
+	PickupIDs  []string     `json:"pickup_ids,omitempty"`
+	At         time.Time    `json:"at"`
+}
+
+// LedgerRepository keeps the loot ledger in durable storage
+type LedgerRepository interface {
+	// Record appends an entry
+	Record(ctx context.Context, entry *LedgerEntry) error
+
+	// ListByUser returns up to limit entries of a trainer, newest first
+	ListByUser(ctx context.Context, userID string, limit int) ([]*LedgerEntry, error)
+
+	// DeleteByUser removes a trainer's entries
+	DeleteByUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,252 @@
+package loot
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// DeliveryMode represents how rolled drops reach the trainer
+type DeliveryMode string
+
+const (
+	DeliverToInventory DeliveryMode = "inventory" // Drops go straight into the trainer's inventory
+	DeliverAsPickup    DeliveryMode = "pickup"    // Drops are placed in the world for the trainer to collect
+)
+
+// String returns string representation
+func (m DeliveryMode) String() string {
+	return string(m)
+}
+
+// IsValid checks if delivery mode is valid
+func (m DeliveryMode) IsValid() bool {
+	return m == DeliverToInventory || m == DeliverAsPickup
+}
+
+// Entry represents a single possible drop in a loot table
+type Entry struct {
+	ItemType    trainer.ItemType `json:"item_type"`
+	Name        string           `json:"name"`
+	Chance      float64          `json:"chance"`       // Probability in [0, 1]
+	MinQuantity int              `json:"min_quantity"` // Inclusive
+	MaxQuantity int              `json:"max_quantity"` // Inclusive
+	MinLevel    int              `json:"min_level"`    // Animal level required for this entry
+}
+
+// Validate checks entry configuration
+func (e Entry) Validate() error {
+	if !e.ItemType.IsValid() {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidLootTable, "Invalid item type in loot entry: %s", e.ItemType)
+	}
+
+	if e.Chance < 0 || e.Chance > 1 {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidLootTable, "Drop chance must be between 0 and 1, got %.2f", e.Chance)
+	}
+
+	if e.MinQuantity < 1 || e.MaxQuantity < e.MinQuantity {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidLootTable, "Invalid quantity range: %d-%d", e.MinQuantity, e.MaxQuantity)
+	}
+
+	return nil
+}
+
+// Table represents the loot table for an animal type
+type Table struct {
+	AnimalType animal.AnimalType `json:"animal_type"`
+	Entries    []Entry           `json:"entries"`
+	// LevelBonus increases every entry's chance per animal level above 1
+	LevelBonus float64 `json:"level_bonus"`
+}
+
+// NewTable creates a validated loot table
+func NewTable(animalType animal.AnimalType, levelBonus float64, entries ...Entry) (*Table, error) {
+	if !animalType.IsValid() {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidAnimalType, fmt.Sprintf("Invalid animal type: %s", animalType))
+	}
+
+	for _, entry := range entries {
+		if err := entry.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Table{
+		AnimalType: animalType,
+		Entries:    entries,
+		LevelBonus: levelBonus,
+	}, nil
+}
+
+// Roll rolls the table for an animal of the given level
+func (t *Table) Roll(level int, rng *rand.Rand) []Drop {
+	drops := make([]Drop, 0)
+
+	for _, entry := range t.Entries {
+		if level < entry.MinLevel {
+			continue
+		}
+
+		chance := entry.Chance + t.LevelBonus*float64(level-1)
+		if chance > 1 {
+			chance = 1
+		}
+
+		if rng.Float64() >= chance {
+			continue
+		}
+
+		quantity := entry.MinQuantity
+		if entry.MaxQuantity > entry.MinQuantity {
+			quantity += rng.Intn(entry.MaxQuantity - entry.MinQuantity + 1)
+		}
+
+		drops = append(drops, Drop{
+			ItemType: entry.ItemType,
+			Name:     entry.Name,
+			Quantity: quantity,
+		})
+	}
+
+	return drops
+}
+
+// Drop represents a rolled drop
+type Drop struct {
+	ItemType trainer.ItemType `json:"item_type"`
+	Name     string           `json:"name"`
+	Quantity int              `json:"quantity"`
+}
+
+// Registry holds loot tables keyed by animal type
+type Registry struct {
+	tables map[animal.AnimalType]*Table
+}
+
+// NewRegistry creates a registry from the given tables
+func NewRegistry(tables ...*Table) *Registry {
+	registry := &Registry{
+		tables: make(map[animal.AnimalType]*Table),
+	}
+	for _, table := range tables {
+		registry.tables[table.AnimalType] = table
+	}
+	return registry
+}
+
+// NewDefaultRegistry creates a registry with the built-in loot tables
+func NewDefaultRegistry() *Registry {
+	return NewRegistry(DefaultTables()...)
+}
+
+// Get returns the loot table for an animal type
+func (r *Registry) Get(animalType animal.AnimalType) (*Table, bool) {
+	table, exists := r.tables[animalType]
+	return table, exists
+}
+
+// Roll rolls the loot table for a defeated animal
+func (r *Registry) Roll(defeated *animal.Animal, rng *rand.Rand) ([]Drop, error) {
+	table, exists := r.Get(defeated.AnimalType)
+	if !exists {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidLootTable, "No loot table for animal type: %s", defeated.AnimalType)
+	}
+
+	// Guard against animals built without a level
+	level := defeated.Level.Value()
+	if level < 1 {
+		level = 1
+	}
+
+	return table.Roll(level, rng), nil
+}
+
+// DefaultTables returns the built-in loot tables for every animal type
+func DefaultTables() []*Table {
+	lion, _ := NewTable(animal.Lion, 0.01,
+		Entry{ItemType: trainer.AnimalHide, Name: "Lion Hide", Chance: 0.8, MinQuantity: 1, MaxQuantity: 2, MinLevel: 1},
+		Entry{ItemType: trainer.AmmoBox, Name: "Ammo Box", Chance: 0.25, MinQuantity: 1, MaxQuantity: 1, MinLevel: 1},
+		Entry{ItemType: trainer.RareGem, Name: "Lion's Eye", Chance: 0.1, MinQuantity: 1, MaxQuantity: 1, MinLevel: 5},
+		Entry{ItemType: trainer.MagicCrystal, Name: "Pride Crystal", Chance: 0.02, MinQuantity: 1, MaxQuantity: 1, MinLevel: 10},
+	)
+	elephant, _ := NewTable(animal.Elephant, 0.01,
+		Entry{ItemType: trainer.AnimalHide, Name: "Elephant Hide", Chance: 0.9, MinQuantity: 2, MaxQuantity: 3, MinLevel: 1},
+		Entry{ItemType: trainer.AmmoBox, Name: "Ammo Box", Chance: 0.2, MinQuantity: 1, MaxQuantity: 2, MinLevel: 1},
+		Entry{ItemType: trainer.RareGem, Name: "Ivory Gem", Chance: 0.08, MinQuantity: 1, MaxQuantity: 1, MinLevel: 5},
+		Entry{ItemType: trainer.MagicCrystal, Name: "Ancient Crystal", Chance: 0.03, MinQuantity: 1, MaxQuantity: 1, MinLevel: 10},
+	)
+	cheetah, _ := NewTable(animal.Cheetah, 0.01,
+		Entry{ItemType: trainer.AnimalHide, Name: "Cheetah Hide", Chance: 0.7, MinQuantity: 1, MaxQuantity: 1, MinLevel: 1},
+		Entry{ItemType: trainer.AmmoBox, Name: "Ammo Box", Chance: 0.25, MinQuantity: 1, MaxQuantity: 1, MinLevel: 1},
+		Entry{ItemType: trainer.RareGem, Name: "Swift Gem", Chance: 0.12, MinQuantity: 1, MaxQuantity: 1, MinLevel: 5},
+		Entry{ItemType: trainer.MagicCrystal, Name: "Wind Crystal", Chance: 0.02, MinQuantity: 1, MaxQuantity: 1, MinLevel: 10},
+	)
+
+	return []*Table{lion, elephant, cheetah}
+}
+
+// PickupID represents a unique world pickup identifier
+type PickupID shared.ID
+
+// NewPickupID creates a new pickup ID
+func NewPickupID() PickupID {
+	return PickupID(shared.NewID())
+}
+
+// String returns string representation
+func (id PickupID) String() string {
+	return string(id)
+}
+
+// Pickup represents dropped loot lying in the world
+type Pickup struct {
+	ID       PickupID          `json:"id"`
+	Drop     Drop              `json:"drop"`
+	Position shared.Position   `json:"position"`
+	SourceID animal.AnimalID   `json:"source_id"` // Animal that dropped it
+	OwnerID  trainer.UserID    `json:"owner_id"`  // Trainer with pickup rights
+	Source   animal.AnimalType `json:"source_type"`
+	// OwnerExclusiveUntil is the moment pickup rights open to every trainer
+	OwnerExclusiveUntil time.Time `json:"owner_exclusive_until"`
+	ExpiresAt           time.Time `json:"expires_at"`
+	DroppedAt           time.Time `json:"dropped_at"`
+}
+
+// NewPickup creates a pickup for a drop at the animal's position
+func NewPickup(drop Drop, defeated *animal.Animal, ownerID trainer.UserID, exclusive, ttl time.Duration) *Pickup {
+	now := time.Now()
+
+	return &Pickup{
+		ID:                  NewPickupID(),
+		Drop:                drop,
+		Position:            defeated.Position,
+		SourceID:            defeated.ID,
+		OwnerID:             ownerID,
+		Source:              defeated.AnimalType,
+		OwnerExclusiveUntil: now.Add(exclusive),
+		ExpiresAt:           now.Add(ttl),
+		DroppedAt:           now,
+	}
+}
+
+// IsExpired checks if the pickup has despawned
+func (p *Pickup) IsExpired(now time.Time) bool {
+	return now.After(p.ExpiresAt)
+}
+
+// CanBeClaimedBy checks if a trainer may collect the pickup
+func (p *Pickup) CanBeClaimedBy(trainerID trainer.UserID, now time.Time) error {
+	if p.IsExpired(now) {
+		return shared.NewDomainError(shared.ErrCodePickupExpired, "Pickup has expired")
+	}
+
+	if p.OwnerID != trainerID && now.Before(p.OwnerExclusiveUntil) {
+		return shared.NewDomainError(shared.ErrCodePickupNotClaimable, "Pickup is reserved for another trainer")
+	}
+
+	return nil
+}
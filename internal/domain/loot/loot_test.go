@@ -0,0 +1,79 @@
+package loot
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/animal"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+func TestTable_Roll(t *testing.T) {
+	t.Run("should always drop guaranteed entries within quantity range", func(t *testing.T) {
+		table, err := NewTable(animal.Lion, 0,
+			Entry{ItemType: trainer.AnimalHide, Name: "Hide", Chance: 1, MinQuantity: 2, MaxQuantity: 4, MinLevel: 1},
+		)
+		require.NoError(t, err)
+
+		rng := rand.New(rand.NewSource(1))
+		for i := 0; i < 100; i++ {
+			drops := table.Roll(1, rng)
+			require.Len(t, drops, 1)
+			assert.GreaterOrEqual(t, drops[0].Quantity, 2)
+			assert.LessOrEqual(t, drops[0].Quantity, 4)
+		}
+	})
+
+	t.Run("should skip entries above the animal level", func(t *testing.T) {
+		table, err := NewTable(animal.Lion, 0,
+			Entry{ItemType: trainer.MagicCrystal, Name: "Crystal", Chance: 1, MinQuantity: 1, MaxQuantity: 1, MinLevel: 10},
+		)
+		require.NoError(t, err)
+
+		rng := rand.New(rand.NewSource(1))
+		assert.Empty(t, table.Roll(9, rng))
+		assert.Len(t, table.Roll(10, rng), 1)
+	})
+
+	t.Run("should reject invalid entries", func(t *testing.T) {
+		_, err := NewTable(animal.Lion, 0,
+			Entry{ItemType: trainer.AnimalHide, Name: "Hide", Chance: 1.5, MinQuantity: 1, MaxQuantity: 1},
+		)
+		assert.Error(t, err)
+	})
+}
+
+func TestRegistry_Roll(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	for _, animalType := range []animal.AnimalType{animal.Lion, animal.Elephant, animal.Cheetah} {
+		_, exists := registry.Get(animalType)
+		assert.True(t, exists, "missing default loot table for %s", animalType)
+	}
+
+	wild, err := animal.NewWildAnimal(animal.Elephant, 1, shared.NewPosition(5, 5))
+	require.NoError(t, err)
+
+	_, err = registry.Roll(wild, rand.New(rand.NewSource(1)))
+	assert.NoError(t, err)
+}
+
+func TestPickup_CanBeClaimedBy(t *testing.T) {
+	wild, err := animal.NewWildAnimal(animal.Lion, 1, shared.NewPosition(5, 5))
+	require.NoError(t, err)
+
+	owner := trainer.UserID("owner")
+	other := trainer.UserID("other")
+	pickup := NewPickup(Drop{ItemType: trainer.AnimalHide, Name: "Hide", Quantity: 1}, wild, owner, time.Minute, 5*time.Minute)
+
+	now := time.Now()
+	assert.NoError(t, pickup.CanBeClaimedBy(owner, now))
+	assert.Error(t, pickup.CanBeClaimedBy(other, now))
+	assert.NoError(t, pickup.CanBeClaimedBy(other, now.Add(2*time.Minute)))
+	assert.Error(t, pickup.CanBeClaimedBy(owner, now.Add(10*time.Minute)))
+}
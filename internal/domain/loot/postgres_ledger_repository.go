@@ -0,0 +1,77 @@
+package loot
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// PostgresLedgerRepository implements LedgerRepository using the loot_ledger table
+type PostgresLedgerRepository struct {
+	pool *pgxpool.Pool
+}
+
+// NewPostgresLedgerRepository creates a new Postgres-based loot ledger
+func NewPostgresLedgerRepository(pool *pgxpool.Pool) LedgerRepository {
+	return &PostgresLedgerRepository{
+		pool: pool,
+	}
+}
+
+// Record appends an entry
+func (r *PostgresLedgerRepository) Record(ctx context.Context, entry *LedgerEntry) error {
+	drops, err := json.Marshal(entry.Drops)
+	if err != nil {
+		return err
+	}
+	pickupIDs := entry.PickupIDs
+	if pickupIDs == nil {
+		pickupIDs = []string{}
+	}
+	pickups, err := json.Marshal(pickupIDs)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.pool.Exec(ctx, `INSERT INTO loot_ledger (tenant_id, user_id, animal_id, animal_type, level, mode, drops, pickup_ids, at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		tenant.IDFromContext(ctx).String(), entry.UserID, entry.AnimalID, entry.AnimalType, entry.Level,
+		entry.Mode.String(), drops, pickups, entry.At)
+	return err
+}
+
+// ListByUser returns up to limit entries of a trainer, newest first
+func (r *PostgresLedgerRepository) ListByUser(ctx context.Context, userID string, limit int) ([]*LedgerEntry, error) {
+	rows, err := r.pool.Query(ctx, `SELECT user_id, animal_id, animal_type, level, mode, drops, pickup_ids, at
+		FROM loot_ledger WHERE tenant_id = $1 AND user_id = $2 ORDER BY id DESC LIMIT $3`,
+		tenant.IDFromContext(ctx).String(), userID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return pgx.CollectRows(rows, func(row pgx.CollectableRow) (*LedgerEntry, error) {
+		entry := &LedgerEntry{}
+		var drops, pickups []byte
+		if err := row.Scan(&entry.UserID, &entry.AnimalID, &entry.AnimalType, &entry.Level, &entry.Mode, &drops, &pickups, &entry.At); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(drops, &entry.Drops); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(pickups, &entry.PickupIDs); err != nil {
+			return nil, err
+		}
+		return entry, nil
+	})
+}
+
+// DeleteByUser removes a trainer's entries
+func (r *PostgresLedgerRepository) DeleteByUser(ctx context.Context, userID string) error {
+	_, err := r.pool.Exec(ctx, "DELETE FROM loot_ledger WHERE tenant_id = $1 AND user_id = $2",
+		tenant.IDFromContext(ctx).String(), userID)
+	return err
+}
@@ -0,0 +1,111 @@
+package loot
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// RedisRepository implements Repository using Redis Hash
+type RedisRepository struct {
+	client *redis.Client
+	docs   *redisx.Repository[Pickup]
+}
+
+// NewRedisRepository creates a new Redis-based pickup repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+		docs: redisx.NewRepository(client, "pickup:", redisx.Options[Pickup]{
+			Codec:         redisx.HashCodec[Pickup]{},
+			Indexes:       pickupIndexes(),
+			NotFound:      func() error { return shared.ErrNotFound("pickup") },
+			AlreadyExists: func() error { return shared.ErrAlreadyExists("pickup") },
+		}),
+	}
+}
+
+func pickupIndexes() []redisx.Index[Pickup] {
+	return []redisx.Index[Pickup]{
+		// Position
+		redisx.SetIndex[Pickup]{Key: func(p *Pickup) string {
+			return cellKey(p.Position)
+		}},
+		// Pickups expire on their own; GetNearby drops their index entries
+		redisx.IndexFunc[Pickup](func(ctx context.Context, pipe redis.Pipeliner, id string, before, after *Pickup) {
+			if after != nil {
+				pipe.ExpireAt(ctx, pickupKey(after.ID), after.ExpiresAt)
+			}
+		}),
+	}
+}
+
+// FindOneAndInsert implements IoC pattern for insert operations
+func (r *RedisRepository) FindOneAndInsert(ctx context.Context, id PickupID, callback func() (*Pickup, error)) error {
+	return r.docs.FindOneAndInsert(ctx, id.String(), callback)
+}
+
+// FindOneAndDelete implements IoC pattern for claim-and-remove operations
+func (r *RedisRepository) FindOneAndDelete(ctx context.Context, id PickupID, callback func(*Pickup) error) error {
+	return r.docs.FindOneAndDelete(ctx, id.String(), callback)
+}
+
+// GetByID retrieves a pickup by ID
+func (r *RedisRepository) GetByID(ctx context.Context, id PickupID) (*Pickup, error) {
+	return r.docs.GetByID(ctx, id.String())
+}
+
+// GetNearby retrieves pickups within radius from position
+func (r *RedisRepository) GetNearby(ctx context.Context, center shared.Position, radius float64) ([]*Pickup, error) {
+	var pickups []*Pickup
+	now := time.Now()
+
+	minX, maxX := int(math.Floor(center.X-radius)), int(math.Floor(center.X+radius))
+	minY, maxY := int(math.Floor(center.Y-radius)), int(math.Floor(center.Y+radius))
+
+	for x := minX; x <= maxX; x++ {
+		for y := minY; y <= maxY; y++ {
+			indexKey := fmt.Sprintf("idx:pickup:cell:%d:%d", x, y)
+
+			ids, err := r.client.SMembers(ctx, indexKey).Result()
+			if err != nil {
+				return nil, err
+			}
+
+			for _, id := range ids {
+				p, err := r.GetByID(ctx, PickupID(id))
+				if err != nil {
+					return nil, err
+				}
+
+				// Pickup keys expire on their own; drop the dangling index entry
+				if p == nil || p.IsExpired(now) {
+					r.client.SRem(ctx, indexKey, id)
+					continue
+				}
+
+				if p.Position.WithinRange(center, radius) {
+					pickups = append(pickups, p)
+				}
+			}
+		}
+	}
+
+	return pickups, nil
+}
+
+// pickupKey returns the Redis key for a pickup
+func pickupKey(id PickupID) string {
+	return fmt.Sprintf("pickup:%s", id.String())
+}
+
+// cellKey returns the position index key for the 1x1 cell containing position
+func cellKey(position shared.Position) string {
+	return fmt.Sprintf("idx:pickup:cell:%d:%d", int(math.Floor(position.X)), int(math.Floor(position.Y)))
+}
@@ -0,0 +1,22 @@
+package loot
+
+import (
+	"context"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// Repository defines the interface for world pickup persistence operations with IoC pattern
+type Repository interface {
+	// FindOneAndInsert inserts a new pickup with callback for initialization
+	FindOneAndInsert(ctx context.Context, id PickupID, callback func() (*Pickup, error)) error
+
+	// FindOneAndDelete finds a pickup by ID and removes it if the callback succeeds
+	FindOneAndDelete(ctx context.Context, id PickupID, callback func(*Pickup) error) error
+
+	// GetByID retrieves a pickup by ID (read-only)
+	GetByID(ctx context.Context, id PickupID) (*Pickup, error)
+
+	// GetNearby retrieves pickups within radius from position (read-only)
+	GetNearby(ctx context.Context, center shared.Position, radius float64) ([]*Pickup, error)
+}
@@ -0,0 +1,350 @@
+package match
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+const (
+	// MinPlayers is the fewest players a match can start with
+	MinPlayers = 2
+	// MinDuration is the shortest match
+	MinDuration = time.Minute
+	// MaxDuration is the longest match
+	MaxDuration = 30 * time.Minute
+	// MaxScoreLimit is the highest score a match can be played to
+	MaxScoreLimit = 200
+)
+
+// Mode is how a match is played and won
+type Mode string
+
+const (
+	// ModeDeathmatch is every player for themselves; the player with the most kills wins
+	ModeDeathmatch Mode = "deathmatch"
+	// ModeTeam splits the players into two teams; the team with the most kills wins
+	ModeTeam Mode = "team"
+)
+
+// IsValid checks if the mode is valid
+func (m Mode) IsValid() bool {
+	return m == ModeDeathmatch || m == ModeTeam
+}
+
+// Team is a side of a team match
+type Team string
+
+const (
+	TeamRed  Team = "red"
+	TeamBlue Team = "blue"
+)
+
+// State is where a match is in its lifecycle
+type State string
+
+const (
+	StateRunning State = "running"
+	StateEnded   State = "ended"
+)
+
+// Settings configure a match. The score limit ends the match early once a player, or a team in
+// team mode, reaches it; zero plays the full duration.
+type Settings struct {
+	Mode       Mode          `json:"mode"`
+	Duration   time.Duration `json:"duration"`
+	ScoreLimit int           `json:"score_limit"`
+}
+
+// DefaultSettings returns the settings of a mode when nothing else is configured
+func DefaultSettings(mode Mode) Settings {
+	if mode == ModeTeam {
+		return Settings{Mode: ModeTeam, Duration: 10 * time.Minute, ScoreLimit: 50}
+	}
+	return Settings{Mode: ModeDeathmatch, Duration: 5 * time.Minute, ScoreLimit: 20}
+}
+
+// Validate checks the settings are within the limits
+func (s Settings) Validate() error {
+	if !s.Mode.IsValid() {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown match mode %q", s.Mode)
+	}
+	if s.Duration < MinDuration || s.Duration > MaxDuration {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Match duration must be between %s and %s", MinDuration, MaxDuration)
+	}
+	if s.ScoreLimit < 0 || s.ScoreLimit > MaxScoreLimit {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Score limit must be between 0 and %d", MaxScoreLimit)
+	}
+	return nil
+}
+
+// Player is a player's line on the scoreboard
+type Player struct {
+	UserID string `json:"user_id"`
+	Team   Team   `json:"team,omitempty"` // Only in team mode
+	Kills  int    `json:"kills"`
+	Deaths int    `json:"deaths"`
+}
+
+// Kill is a player killing another with a bullet
+type Kill struct {
+	BulletID string    `json:"bullet_id"`
+	KillerID string    `json:"killer_id"`
+	VictimID string    `json:"victim_id"`
+	At       time.Time `json:"at"`
+}
+
+// Match is a timed FPS match between the members of a room
+type Match struct {
+	ID        string     `json:"id"`
+	RoomID    string     `json:"room_id"`
+	Settings  Settings   `json:"settings"`
+	State     State      `json:"state"`
+	Players   []*Player  `json:"players"` // In the order they were in the room
+	Kills     []Kill     `json:"kills"`
+	StartedAt time.Time  `json:"started_at"`
+	EndsAt    time.Time  `json:"ends_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Result    *Result    `json:"result,omitempty"`
+}
+
+// NewMatch starts a match between the members of a room. In team mode members are dealt to the
+// teams in turn.
+func NewMatch(roomID string, members []string, settings Settings, now time.Time) (*Match, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	if len(members) < MinPlayers {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeNotEnoughPlayers, "A match needs at least %d players", MinPlayers)
+	}
+
+	players := make([]*Player, len(members))
+	for i, userID := range members {
+		players[i] = &Player{UserID: userID}
+		if settings.Mode == ModeTeam {
+			players[i].Team = []Team{TeamRed, TeamBlue}[i%2]
+		}
+	}
+
+	return &Match{
+		ID:        shared.NewID().String(),
+		RoomID:    roomID,
+		Settings:  settings,
+		State:     StateRunning,
+		Players:   players,
+		Kills:     []Kill{},
+		StartedAt: now,
+		EndsAt:    now.Add(settings.Duration),
+	}, nil
+}
+
+// IsRunning checks if the match is still being played
+func (m *Match) IsRunning() bool {
+	return m.State == StateRunning
+}
+
+// Player returns a player's line on the scoreboard, nil for players not in the match
+func (m *Match) Player(userID string) *Player {
+	for _, p := range m.Players {
+		if p.UserID == userID {
+			return p
+		}
+	}
+	return nil
+}
+
+// PlayerIDs returns the players of the match
+func (m *Match) PlayerIDs() []string {
+	ids := make([]string, len(m.Players))
+	for i, p := range m.Players {
+		ids[i] = p.UserID
+	}
+	return ids
+}
+
+// TeamScores returns the kills of each team, nil outside team mode
+func (m *Match) TeamScores() map[Team]int {
+	if m.Settings.Mode != ModeTeam {
+		return nil
+	}
+	scores := map[Team]int{TeamRed: 0, TeamBlue: 0}
+	for _, p := range m.Players {
+		scores[p.Team] += p.Kills
+	}
+	return scores
+}
+
+// RecordKill counts a bullet killing a player. It reports whether the scoreboard changed:
+// kills after the match ended, of players outside it or already counted are ignored, as
+// the same hit can be delivered twice. Killing a teammate costs the victim a death but earns
+// no kill. The match ends once the score limit is reached.
+func (m *Match) RecordKill(bulletID, killerID, victimID string, at time.Time) bool {
+	if !m.IsRunning() {
+		return false
+	}
+	killer, victim := m.Player(killerID), m.Player(victimID)
+	if killer == nil || victim == nil || killer == victim {
+		return false
+	}
+	if slices.ContainsFunc(m.Kills, func(k Kill) bool { return k.BulletID == bulletID }) {
+		return false
+	}
+
+	m.Kills = append(m.Kills, Kill{BulletID: bulletID, KillerID: killerID, VictimID: victimID, At: at})
+	victim.Deaths++
+	if m.Settings.Mode == ModeTeam && killer.Team == victim.Team {
+		return true
+	}
+	killer.Kills++
+
+	if m.reachedScoreLimit(killer) {
+		m.End(at)
+	}
+	return true
+}
+
+// reachedScoreLimit checks if the scorer's kill got them, or their team, to the score limit
+func (m *Match) reachedScoreLimit(scorer *Player) bool {
+	if m.Settings.ScoreLimit == 0 {
+		return false
+	}
+	if m.Settings.Mode == ModeTeam {
+		return m.TeamScores()[scorer.Team] >= m.Settings.ScoreLimit
+	}
+	return scorer.Kills >= m.Settings.ScoreLimit
+}
+
+// End finishes a running match and works out its result. It reports false when the match had
+// already ended.
+func (m *Match) End(at time.Time) bool {
+	if !m.IsRunning() {
+		return false
+	}
+
+	m.State = StateEnded
+	m.EndedAt = &at
+	m.Result = m.result()
+	return true
+}
+
+// Outcome is how a match went for a player
+type Outcome string
+
+const (
+	OutcomeWin  Outcome = "win"
+	OutcomeLoss Outcome = "loss"
+	OutcomeDraw Outcome = "draw"
+)
+
+// Standing is a player's place at the end of a match
+type Standing struct {
+	UserID  string  `json:"user_id"`
+	Team    Team    `json:"team,omitempty"`
+	Kills   int     `json:"kills"`
+	Deaths  int     `json:"deaths"`
+	Place   int     `json:"place"` // Players with the same kills and deaths share a place
+	Outcome Outcome `json:"outcome"`
+}
+
+// Result is how a match ended. A deathmatch is won by the player with the most kills and a
+// team match by the team with the most kills; a tie for the lead is a draw.
+type Result struct {
+	WinnerID   string       `json:"winner_id,omitempty"`
+	WinnerTeam Team         `json:"winner_team,omitempty"`
+	Draw       bool         `json:"draw"`
+	TeamScores map[Team]int `json:"team_scores,omitempty"`
+	Standings  []Standing   `json:"standings"` // Best first
+}
+
+// result ranks the players by kills, then by fewest deaths
+func (m *Match) result() *Result {
+	standings := make([]Standing, len(m.Players))
+	for i, p := range m.Players {
+		standings[i] = Standing{UserID: p.UserID, Team: p.Team, Kills: p.Kills, Deaths: p.Deaths}
+	}
+	sort.SliceStable(standings, func(i, j int) bool {
+		if standings[i].Kills != standings[j].Kills {
+			return standings[i].Kills > standings[j].Kills
+		}
+		return standings[i].Deaths < standings[j].Deaths
+	})
+	for i := range standings {
+		standings[i].Place = i + 1
+		if i > 0 && standings[i].Kills == standings[i-1].Kills && standings[i].Deaths == standings[i-1].Deaths {
+			standings[i].Place = standings[i-1].Place
+		}
+	}
+
+	result := &Result{TeamScores: m.TeamScores(), Standings: standings}
+	if m.Settings.Mode == ModeTeam {
+		switch red, blue := result.TeamScores[TeamRed], result.TeamScores[TeamBlue]; {
+		case red > blue:
+			result.WinnerTeam = TeamRed
+		case blue > red:
+			result.WinnerTeam = TeamBlue
+		default:
+			result.Draw = true
+		}
+	} else if len(standings) > 1 && standings[0].Kills == standings[1].Kills {
+		result.Draw = true
+	} else {
+		result.WinnerID = standings[0].UserID
+	}
+
+	for i := range standings {
+		standings[i].Outcome = result.outcome(standings[i])
+	}
+	return result
+}
+
+// outcome returns how the match went for a player
+func (r *Result) outcome(s Standing) Outcome {
+	switch {
+	case r.Draw:
+		return OutcomeDraw
+	case r.WinnerID == s.UserID, r.WinnerTeam != "" && r.WinnerTeam == s.Team:
+		return OutcomeWin
+	default:
+		return OutcomeLoss
+	}
+}
+
+// HistoryEntry is a match a player took part in, as kept in their match history
+type HistoryEntry struct {
+	MatchID string    `json:"match_id"`
+	RoomID  string    `json:"room_id"`
+	Mode    Mode      `json:"mode"`
+	Team    Team      `json:"team,omitempty"`
+	Kills   int       `json:"kills"`
+	Deaths  int       `json:"deaths"`
+	Place   int       `json:"place"`
+	Players int       `json:"players"`
+	Outcome Outcome   `json:"outcome"`
+	EndedAt time.Time `json:"ended_at"`
+}
+
+// History returns the history entry of every player of an ended match, by user ID
+func (m *Match) History() map[string]HistoryEntry {
+	if m.Result == nil || m.EndedAt == nil {
+		return nil
+	}
+
+	entries := make(map[string]HistoryEntry, len(m.Result.Standings))
+	for _, s := range m.Result.Standings {
+		entries[s.UserID] = HistoryEntry{
+			MatchID: m.ID,
+			RoomID:  m.RoomID,
+			Mode:    m.Settings.Mode,
+			Team:    s.Team,
+			Kills:   s.Kills,
+			Deaths:  s.Deaths,
+			Place:   s.Place,
+			Players: len(m.Players),
+			Outcome: s.Outcome,
+			EndedAt: *m.EndedAt,
+		}
+	}
+	return entries
+}
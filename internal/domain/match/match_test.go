@@ -0,0 +1,88 @@
+package match
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/shared/sharedtest"
+)
+
+func TestNewMatch(t *testing.T) {
+	now := time.Now()
+	m, err := NewMatch("room", []string{"a", "b", "c"}, DefaultSettings(ModeTeam), now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(10*time.Minute), m.EndsAt)
+	assert.Equal(t, TeamRed, m.Player("a").Team)
+	assert.Equal(t, TeamBlue, m.Player("b").Team)
+	assert.Equal(t, TeamRed, m.Player("c").Team)
+
+	_, err = NewMatch("room", []string{"a"}, DefaultSettings(ModeDeathmatch), now)
+	assert.Equal(t, shared.ErrCodeNotEnoughPlayers, sharedtest.ErrorCode(t, err))
+
+	settings := DefaultSettings(ModeDeathmatch)
+	settings.Duration = MaxDuration + time.Minute
+	_, err = NewMatch("room", []string{"a", "b"}, settings, now)
+	assert.Equal(t, shared.ErrCodeInvalidInput, sharedtest.ErrorCode(t, err))
+	_, err = NewMatch("room", []string{"a", "b"}, Settings{Mode: "capture", Duration: MinDuration}, now)
+	assert.Equal(t, shared.ErrCodeInvalidInput, sharedtest.ErrorCode(t, err))
+}
+
+func TestMatch_RecordKill(t *testing.T) {
+	now := time.Now()
+	settings := DefaultSettings(ModeDeathmatch)
+	settings.ScoreLimit = 2
+	m, err := NewMatch("room", []string{"a", "b", "c"}, settings, now)
+	require.NoError(t, err)
+
+	assert.True(t, m.RecordKill("bullet-1", "a", "b", now))
+	assert.False(t, m.RecordKill("bullet-1", "a", "b", now), "the same hit is counted once")
+	assert.False(t, m.RecordKill("bullet-2", "a", "stranger", now))
+	assert.Equal(t, 1, m.Player("a").Kills)
+	assert.Equal(t, 1, m.Player("b").Deaths)
+
+	assert.True(t, m.RecordKill("bullet-3", "a", "c", now))
+	assert.False(t, m.IsRunning(), "score limit reached")
+	require.NotNil(t, m.Result)
+	assert.Equal(t, "a", m.Result.WinnerID)
+	assert.False(t, m.RecordKill("bullet-4", "b", "a", now))
+}
+
+func TestMatch_TeamKill(t *testing.T) {
+	m, err := NewMatch("room", []string{"a", "b", "c", "d"}, DefaultSettings(ModeTeam), time.Now())
+	require.NoError(t, err)
+
+	// a and c are red
+	assert.True(t, m.RecordKill("bullet-1", "a", "c", time.Now()))
+	assert.Equal(t, 0, m.Player("a").Kills)
+	assert.Equal(t, 1, m.Player("c").Deaths)
+
+	assert.True(t, m.RecordKill("bullet-2", "b", "a", time.Now()))
+	assert.Equal(t, map[Team]int{TeamRed: 0, TeamBlue: 1}, m.TeamScores())
+}
+
+func TestMatch_EndResult(t *testing.T) {
+	now := time.Now()
+	m, err := NewMatch("room", []string{"a", "b", "c"}, DefaultSettings(ModeDeathmatch), now)
+	require.NoError(t, err)
+	m.RecordKill("bullet-1", "a", "c", now)
+	m.RecordKill("bullet-2", "b", "c", now)
+
+	require.True(t, m.End(now))
+	assert.False(t, m.End(now))
+	assert.True(t, m.Result.Draw)
+
+	standings := m.Result.Standings
+	assert.Equal(t, []int{1, 1, 3}, []int{standings[0].Place, standings[1].Place, standings[2].Place})
+	assert.Equal(t, OutcomeDraw, standings[2].Outcome)
+
+	history := m.History()
+	require.Len(t, history, 3)
+	assert.Equal(t, 2, history["c"].Deaths)
+	assert.Equal(t, 3, history["c"].Place)
+	assert.Equal(t, OutcomeDraw, history["c"].Outcome)
+	assert.Equal(t, 3, history["c"].Players)
+}
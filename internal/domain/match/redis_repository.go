@@ -0,0 +1,134 @@
+package match
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+const (
+	// MaxHistory is how many matches are kept in a player's history
+	MaxHistory = 50
+	// EndedMatchTTL is how long an ended match stays readable with its full scoreboard; the
+	// players' histories keep their part of it
+	EndedMatchTTL = 24 * time.Hour
+)
+
+// RedisRepository implements Repository with a JSON document per match, a key per room
+// pointing at its running match and a capped list per player for their history
+type RedisRepository struct {
+	client *redis.Client
+	docs   *redisx.Repository[Match]
+}
+
+// NewRedisRepository creates a new Redis-based match repository
+func NewRedisRepository(client *redis.Client) Repository {
+	r := &RedisRepository{client: client}
+	r.docs = redisx.NewRepository(client, "match:", redisx.Options[Match]{
+		Indexes: []redisx.Index[Match]{
+			// Running match of each room
+			redisx.UniqueIndex[Match]{Key: func(m *Match) string {
+				if !m.IsRunning() {
+					return ""
+				}
+				return roomKey(m.RoomID)
+			}},
+			redisx.IndexFunc[Match](r.recordHistory),
+		},
+		NotFound:      func() error { return shared.ErrNotFound("match") },
+		AlreadyExists: func() error { return shared.ErrAlreadyExists("match") },
+	})
+	return r
+}
+
+// recordHistory adds an ending match to its players' histories in the transaction ending it,
+// so each player gets it exactly once, and lets the match expire
+func (r *RedisRepository) recordHistory(ctx context.Context, pipe redis.Pipeliner, id string, before, after *Match) {
+	if before == nil || after == nil || !before.IsRunning() || after.IsRunning() {
+		return
+	}
+
+	for userID, entry := range after.History() {
+		data, err := json.Marshal(entry)
+		if err != nil {
+			continue
+		}
+		pipe.LPush(ctx, historyKey(userID), data)
+		pipe.LTrim(ctx, historyKey(userID), 0, MaxHistory-1)
+	}
+	pipe.Expire(ctx, r.docs.Key(id), EndedMatchTTL)
+}
+
+// roomKey returns the key holding the running match of a room
+func roomKey(roomID string) string {
+	return fmt.Sprintf("match:room:%s", roomID)
+}
+
+// historyKey returns the list of a player's past matches
+func historyKey(userID string) string {
+	return fmt.Sprintf("match:history:%s", userID)
+}
+
+// FindOneAndInsert implements IoC pattern for insert operations
+func (r *RedisRepository) FindOneAndInsert(ctx context.Context, id string, callback func() (*Match, error)) error {
+	return r.docs.FindOneAndInsert(ctx, id, callback)
+}
+
+// FindOneAndUpdate implements IoC pattern for update operations
+func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, id string, callback func(*Match) (*Match, error)) error {
+	return r.docs.FindOneAndUpdate(ctx, id, callback)
+}
+
+// GetByID retrieves a match by ID
+func (r *RedisRepository) GetByID(ctx context.Context, id string) (*Match, error) {
+	return r.docs.GetByID(ctx, id)
+}
+
+// RoomMatch returns the match the room's key points at
+func (r *RedisRepository) RoomMatch(ctx context.Context, roomID string) (string, error) {
+	id, err := r.client.Get(ctx, roomKey(roomID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get match of room: %w", err)
+	}
+	return id, nil
+}
+
+// History retrieves the head of the player's history list
+func (r *RedisRepository) History(ctx context.Context, userID string, limit int) ([]HistoryEntry, error) {
+	if limit <= 0 || limit > MaxHistory {
+		limit = MaxHistory
+	}
+
+	items, err := r.client.LRange(ctx, historyKey(userID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get match history: %w", err)
+	}
+
+	entries := make([]HistoryEntry, 0, len(items))
+	for _, item := range items {
+		var entry HistoryEntry
+		if err := json.Unmarshal([]byte(item), &entry); err != nil {
+			return nil, fmt.Errorf("failed to deserialize match history: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// DeleteUser removes the player's history list. Ended matches they played in expire on their
+// own.
+func (r *RedisRepository) DeleteUser(ctx context.Context, userID string) error {
+	if err := r.client.Del(ctx, historyKey(userID)).Err(); err != nil {
+		return fmt.Errorf("failed to delete match history: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package match
+
+import (
+	"context"
+)
+
+// Repository defines the interface for match persistence operations with IoC pattern
+type Repository interface {
+	// FindOneAndInsert inserts a new match with callback for initialization
+	FindOneAndInsert(ctx context.Context, id string, callback func() (*Match, error)) error
+
+	// FindOneAndUpdate finds a match by ID and applies callback for atomic update
+	FindOneAndUpdate(ctx context.Context, id string, callback func(*Match) (*Match, error)) error
+
+	// GetByID retrieves a match by ID, nil when there is none (read-only)
+	GetByID(ctx context.Context, id string) (*Match, error)
+
+	// RoomMatch returns the ID of the match running in a room, "" when there is none (read-only)
+	RoomMatch(ctx context.Context, roomID string) (string, error)
+
+	// History retrieves a player's most recent matches, newest first (read-only)
+	History(ctx context.Context, userID string, limit int) ([]HistoryEntry, error)
+
+	// DeleteUser removes a player's match history
+	DeleteUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,105 @@
+package notification
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// Category groups notifications players can turn on and off together
+type Category string
+
+const (
+	CategoryChat      Category = "chat"
+	CategoryTrade     Category = "trade"
+	CategoryGuild     Category = "guild"
+	CategoryCombat    Category = "combat"
+	CategoryMarketing Category = "marketing"
+)
+
+// Categories lists every category in display order
+var Categories = []Category{CategoryChat, CategoryTrade, CategoryGuild, CategoryCombat, CategoryMarketing}
+
+// Channel is a way of reaching a player
+type Channel string
+
+const (
+	ChannelSSE   Channel = "sse" // Live notifications to connected clients, over SSE or WebSocket
+	ChannelPush  Channel = "push"
+	ChannelEmail Channel = "email"
+)
+
+// Channels lists every channel in display order
+var Channels = []Channel{ChannelSSE, ChannelPush, ChannelEmail}
+
+// methodCategories maps JSON-RPC notification namespaces to their category. Notifications in
+// other namespaces, such as positions and system notices, are always delivered.
+var methodCategories = map[string]Category{
+	"chat":    CategoryChat,
+	"trade":   CategoryTrade,
+	"guild":   CategoryGuild,
+	"battle":  CategoryCombat,
+	"liveops": CategoryMarketing,
+}
+
+// CategoryOf returns the category of a notification method such as "chat.message"
+func CategoryOf(method string) (Category, bool) {
+	namespace, _, _ := strings.Cut(method, ".")
+	category, ok := methodCategories[namespace]
+	return category, ok
+}
+
+// Default reports whether a channel is on for a category until the player changes it.
+// Marketing only reaches players outside the game once they opt in.
+func Default(category Category, channel Channel) bool {
+	return category != CategoryMarketing || channel == ChannelSSE
+}
+
+// Preferences says for each category which channels may reach a player
+type Preferences map[Category]map[Channel]bool
+
+// DefaultPreferences returns the preferences of a player who never changed them
+func DefaultPreferences() Preferences {
+	preferences := make(Preferences, len(Categories))
+	for _, category := range Categories {
+		preferences[category] = make(map[Channel]bool, len(Channels))
+		for _, channel := range Channels {
+			preferences[category][channel] = Default(category, channel)
+		}
+	}
+	return preferences
+}
+
+// Allows reports whether a channel may carry notifications of a category
+func (p Preferences) Allows(category Category, channel Channel) bool {
+	if allowed, ok := p[category][channel]; ok {
+		return allowed
+	}
+	return Default(category, channel)
+}
+
+// Apply turns channels on and off as listed in changes, leaving the others as they are.
+// Nothing changes when changes name an unknown category or channel.
+func (p Preferences) Apply(changes Preferences) error {
+	for category, channels := range changes {
+		if !slices.Contains(Categories, category) {
+			return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown notification category: %s", category)
+		}
+		for channel := range channels {
+			if !slices.Contains(Channels, channel) {
+				return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown notification channel: %s", channel)
+			}
+		}
+	}
+
+	for category, channels := range changes {
+		if p[category] == nil {
+			p[category] = make(map[Channel]bool, len(Channels))
+		}
+		for channel, allowed := range channels {
+			p[category][channel] = allowed
+		}
+	}
+	return nil
+}
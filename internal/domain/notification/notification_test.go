@@ -0,0 +1,45 @@
+package notification
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCategoryOf(t *testing.T) {
+	category, ok := CategoryOf("chat.message")
+	assert.True(t, ok)
+	assert.Equal(t, CategoryChat, category)
+
+	category, ok = CategoryOf("battle.turn")
+	assert.True(t, ok)
+	assert.Equal(t, CategoryCombat, category)
+
+	_, ok = CategoryOf("trainer.position.updated")
+	assert.False(t, ok, "positions are always delivered")
+}
+
+func TestPreferences_Apply(t *testing.T) {
+	preferences := DefaultPreferences()
+	assert.True(t, preferences.Allows(CategoryChat, ChannelSSE))
+	assert.False(t, preferences.Allows(CategoryMarketing, ChannelEmail), "marketing email is opt-in")
+
+	err := preferences.Apply(Preferences{
+		CategoryChat:      {ChannelSSE: false},
+		CategoryMarketing: {ChannelEmail: true},
+	})
+	require.NoError(t, err)
+	assert.False(t, preferences.Allows(CategoryChat, ChannelSSE))
+	assert.True(t, preferences.Allows(CategoryChat, ChannelPush), "unlisted channels are left alone")
+	assert.True(t, preferences.Allows(CategoryMarketing, ChannelEmail))
+
+	err = preferences.Apply(Preferences{
+		CategoryCombat: {ChannelSSE: false},
+		"weather":      {ChannelSSE: false},
+	})
+	assert.Error(t, err)
+	assert.True(t, preferences.Allows(CategoryCombat, ChannelSSE), "nothing changes on invalid changes")
+
+	assert.Error(t, preferences.Apply(Preferences{CategoryChat: {"pigeon": true}}))
+}
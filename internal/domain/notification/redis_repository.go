@@ -0,0 +1,134 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisRepository implements Repository with a set per category and channel holding the
+// players who changed that setting from its default. Players who never changed anything
+// take no space, and a notification to many players is checked with one command.
+type RedisRepository struct {
+	client *redis.Client
+}
+
+// NewRedisRepository creates a new Redis-based notification preference repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+	}
+}
+
+// FindOneAndUpdate implements IoC pattern for update operations
+func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, userID string, callback func(Preferences) error) error {
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		preferences, err := r.load(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := callback(preferences); err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			for _, category := range Categories {
+				for _, channel := range Channels {
+					if preferences.Allows(category, channel) != Default(category, channel) {
+						pipe.SAdd(ctx, changedKey(category, channel), userID)
+					} else {
+						pipe.SRem(ctx, changedKey(category, channel), userID)
+					}
+				}
+			}
+			return nil
+		})
+		return err
+	}, allChangedKeys()...)
+}
+
+// Get retrieves a player's preferences
+func (r *RedisRepository) Get(ctx context.Context, userID string) (Preferences, error) {
+	return r.load(ctx, r.client, userID)
+}
+
+// load reads a player's preferences, from inside a transaction when cmd is one
+func (r *RedisRepository) load(ctx context.Context, cmd redis.Cmdable, userID string) (Preferences, error) {
+	changed := make(map[Category]map[Channel]*redis.BoolCmd, len(Categories))
+	_, err := cmd.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, category := range Categories {
+			changed[category] = make(map[Channel]*redis.BoolCmd, len(Channels))
+			for _, channel := range Channels {
+				changed[category][channel] = pipe.SIsMember(ctx, changedKey(category, channel), userID)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load notification preferences: %w", err)
+	}
+
+	preferences := DefaultPreferences()
+	for category, channels := range changed {
+		for channel, isChanged := range channels {
+			if isChanged.Val() {
+				preferences[category][channel] = !Default(category, channel)
+			}
+		}
+	}
+	return preferences, nil
+}
+
+// Allowed reports for each player whether a channel may carry notifications of a category
+func (r *RedisRepository) Allowed(ctx context.Context, category Category, channel Channel, userIDs []string) ([]bool, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	members := make([]interface{}, len(userIDs))
+	for i, userID := range userIDs {
+		members[i] = userID
+	}
+	changed, err := r.client.SMIsMember(ctx, changedKey(category, channel), members...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]bool, len(changed))
+	for i, isChanged := range changed {
+		allowed[i] = Default(category, channel) != isChanged
+	}
+	return allowed, nil
+}
+
+// Changed lists the players whose setting differs from the default
+func (r *RedisRepository) Changed(ctx context.Context, category Category, channel Channel) ([]string, error) {
+	return r.client.SMembers(ctx, changedKey(category, channel)).Result()
+}
+
+// DeleteUser removes a player from every set, which restores the defaults
+func (r *RedisRepository) DeleteUser(ctx context.Context, userID string) error {
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range allChangedKeys() {
+			pipe.SRem(ctx, key, userID)
+		}
+		return nil
+	})
+	return err
+}
+
+func changedKey(category Category, channel Channel) string {
+	return fmt.Sprintf("notification:changed:%s:%s", category, channel)
+}
+
+func allChangedKeys() []string {
+	keys := make([]string, 0, len(Categories)*len(Channels))
+	for _, category := range Categories {
+		for _, channel := range Channels {
+			keys = append(keys, changedKey(category, channel))
+		}
+	}
+	return keys
+}
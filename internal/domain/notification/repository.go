@@ -0,0 +1,23 @@
+package notification
+
+import (
+	"context"
+)
+
+// Repository stores players' notification preferences with IoC pattern
+type Repository interface {
+	// FindOneAndUpdate loads a player's preferences and applies callback for atomic update
+	FindOneAndUpdate(ctx context.Context, userID string, callback func(Preferences) error) error
+
+	// Get retrieves a player's preferences (read-only)
+	Get(ctx context.Context, userID string) (Preferences, error)
+
+	// Allowed reports for each player whether a channel may carry notifications of a category
+	Allowed(ctx context.Context, category Category, channel Channel, userIDs []string) ([]bool, error)
+
+	// Changed lists the players whose setting of a channel for a category differs from the default
+	Changed(ctx context.Context, category Category, channel Channel) ([]string, error)
+
+	// DeleteUser removes a player's preferences
+	DeleteUser(ctx context.Context, userID string) error
+}
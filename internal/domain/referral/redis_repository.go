@@ -0,0 +1,295 @@
+package referral
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// fingerprintTTL is how long login fingerprints are remembered for self-referral checks
+const fingerprintTTL = 30 * 24 * time.Hour
+
+// codeAttempts is how many generated codes are tried before giving up on collisions
+const codeAttempts = 5
+
+// RedisRepository implements Repository using Redis Hash
+type RedisRepository struct {
+	client *redis.Client
+	codec  redisx.HashCodec[Referral]
+	docs   *redisx.Repository[Referral]
+}
+
+// NewRedisRepository creates a new Redis-based referral repository
+func NewRedisRepository(client *redis.Client) Repository {
+	r := &RedisRepository{
+		client: client,
+	}
+	r.docs = redisx.NewRepository(client, "referral:", redisx.Options[Referral]{
+		Codec:    r.codec,
+		NotFound: func() error { return shared.ErrNotFound("referral") },
+	})
+	return r
+}
+
+// GetOrCreateCode returns the user's referral code, storing a generated one if they have none yet
+func (r *RedisRepository) GetOrCreateCode(ctx context.Context, userID string, generate func() (Code, error)) (Code, error) {
+	userKey := r.userCodeKey(userID)
+
+	existing, err := r.client.Get(ctx, userKey).Result()
+	if err == nil {
+		return Code(existing), nil
+	}
+	if err != redis.Nil {
+		return "", err
+	}
+
+	for range codeAttempts {
+		code, err := generate()
+		if err != nil {
+			return "", err
+		}
+
+		// Reserve the code first so two users never share one
+		reserved, err := r.client.SetNX(ctx, r.codeKey(code), userID, 0).Result()
+		if err != nil {
+			return "", err
+		}
+		if !reserved {
+			continue
+		}
+
+		stored, err := r.client.SetNX(ctx, userKey, code.String(), 0).Result()
+		if err != nil {
+			return "", err
+		}
+		if stored {
+			return code, nil
+		}
+
+		// A concurrent request created the user's code first
+		r.client.Del(ctx, r.codeKey(code))
+		existing, err := r.client.Get(ctx, userKey).Result()
+		if err != nil {
+			return "", err
+		}
+		return Code(existing), nil
+	}
+
+	return "", fmt.Errorf("failed to generate a unique referral code")
+}
+
+// GetReferrer returns the owner of a referral code, or "" if the code is unknown
+func (r *RedisRepository) GetReferrer(ctx context.Context, code Code) (string, error) {
+	userID, err := r.client.Get(ctx, r.codeKey(code)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return userID, err
+}
+
+// FindOneAndInsert implements IoC pattern for insert operations. It claims the referral's
+// fingerprints in the same transaction, which redisx.Repository can't, so it writes the
+// document itself.
+func (r *RedisRepository) FindOneAndInsert(ctx context.Context, referredID string, callback func() (*Referral, error)) error {
+	key := r.docs.Key(referredID)
+
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		// Check if already exists
+		exists := tx.Exists(ctx, key)
+		if exists.Err() != nil {
+			return exists.Err()
+		}
+
+		if exists.Val() > 0 {
+			return shared.ErrAlreadyExists("referral")
+		}
+
+		// Execute callback
+		result, err := callback()
+		if err != nil {
+			return err
+		}
+
+		if result == nil {
+			return fmt.Errorf("callback returned nil referral")
+		}
+
+		// Each device and address can only back one referral
+		claimKeys := make([]string, 0, len(result.Fingerprints))
+		for _, hash := range result.Fingerprints {
+			claimKeys = append(claimKeys, r.claimKey(hash))
+		}
+		if len(claimKeys) > 0 {
+			if err := tx.Watch(ctx, claimKeys...).Err(); err != nil {
+				return err
+			}
+
+			used, err := tx.Exists(ctx, claimKeys...).Result()
+			if err != nil {
+				return err
+			}
+			if used > 0 {
+				return shared.NewDomainError(shared.ErrCodeReferralAbuse, "This device or network was already used for a referral")
+			}
+		}
+
+		// Serialize and store
+		data, err := r.codec.Encode(result)
+		if err != nil {
+			return err
+		}
+
+		// Execute transaction
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			r.codec.Write(ctx, pipe, key, data)
+			pipe.SAdd(ctx, r.referrerKey(result.ReferrerID), result.ReferredID)
+			for _, claimKey := range claimKeys {
+				pipe.Set(ctx, claimKey, result.ReferredID, 0)
+			}
+			return nil
+		})
+
+		return err
+	}, key)
+}
+
+// FindOneAndUpdate implements IoC pattern for update operations
+func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, referredID string, callback func(*Referral) (*Referral, error)) error {
+	return r.docs.FindOneAndUpdate(ctx, referredID, callback)
+}
+
+// GetByReferred retrieves the referral of a referred user
+func (r *RedisRepository) GetByReferred(ctx context.Context, referredID string) (*Referral, error) {
+	return r.docs.GetByID(ctx, referredID)
+}
+
+// GetByReferrer retrieves the referrals credited to a referrer
+func (r *RedisRepository) GetByReferrer(ctx context.Context, referrerID string) ([]*Referral, error) {
+	referredIDs, err := r.client.SMembers(ctx, r.referrerKey(referrerID)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	referrals := make([]*Referral, 0, len(referredIDs))
+	for _, referredID := range referredIDs {
+		ref, err := r.GetByReferred(ctx, referredID)
+		if err != nil {
+			return nil, err
+		}
+		if ref != nil {
+			referrals = append(referrals, ref)
+		}
+	}
+
+	return referrals, nil
+}
+
+// RecordFingerprint remembers fingerprint hashes the user has logged in from
+func (r *RedisRepository) RecordFingerprint(ctx context.Context, userID string, hashes []string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+
+	key := r.fingerprintKey(userID)
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, hash := range hashes {
+			pipe.SAdd(ctx, key, hash)
+		}
+		pipe.Expire(ctx, key, fingerprintTTL)
+		return nil
+	})
+
+	return err
+}
+
+// SharesFingerprint checks if the user has logged in from any of the fingerprint hashes
+func (r *RedisRepository) SharesFingerprint(ctx context.Context, userID string, hashes []string) (bool, error) {
+	if len(hashes) == 0 {
+		return false, nil
+	}
+
+	members := make([]interface{}, 0, len(hashes))
+	for _, hash := range hashes {
+		members = append(members, hash)
+	}
+
+	known, err := r.client.SMIsMember(ctx, r.fingerprintKey(userID), members...).Result()
+	if err != nil {
+		return false, err
+	}
+
+	for _, ok := range known {
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// DeleteUser removes a user's referral data except the fingerprint claims
+func (r *RedisRepository) DeleteUser(ctx context.Context, userID string) error {
+	userKey := r.userCodeKey(userID)
+	key := r.docs.Key(userID)
+
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		code, err := tx.Get(ctx, userKey).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+
+		data, err := r.codec.Read(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+
+		var ref *Referral
+		if data != nil {
+			if ref, err = r.codec.Decode(data); err != nil {
+				return err
+			}
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			if code != "" {
+				pipe.Del(ctx, r.codeKey(Code(code)))
+			}
+			if ref != nil {
+				pipe.SRem(ctx, r.referrerKey(ref.ReferrerID), userID)
+			}
+			pipe.Del(ctx, userKey, key, r.referrerKey(userID), r.fingerprintKey(userID))
+			return nil
+		})
+
+		return err
+	}, userKey, key)
+}
+
+// codeKey returns the Redis key mapping a code to its owner
+func (r *RedisRepository) codeKey(code Code) string {
+	return fmt.Sprintf("idx:referral:code:%s", code.String())
+}
+
+// userCodeKey returns the Redis key holding a user's code
+func (r *RedisRepository) userCodeKey(userID string) string {
+	return fmt.Sprintf("idx:referral:user:%s", userID)
+}
+
+// referrerKey returns the Redis set of users a referrer brought in
+func (r *RedisRepository) referrerKey(referrerID string) string {
+	return fmt.Sprintf("idx:referral:referrer:%s", referrerID)
+}
+
+// claimKey returns the Redis key marking a fingerprint as used by a referral
+func (r *RedisRepository) claimKey(hash string) string {
+	return fmt.Sprintf("idx:referral:fingerprint:%s", hash)
+}
+
+// fingerprintKey returns the Redis set of fingerprints a user has logged in from
+func (r *RedisRepository) fingerprintKey(userID string) string {
+	return fmt.Sprintf("referral:fingerprints:%s", userID)
+}
@@ -0,0 +1,186 @@
+package referral
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// CodeLength is the number of characters in a referral code
+const CodeLength = 8
+
+// codeAlphabet leaves out characters that are easy to confuse when typed (0/O, 1/I)
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// Code is a trainer's shareable referral code
+type Code string
+
+// NewCode generates a random referral code
+func NewCode() (Code, error) {
+	bytes := make([]byte, CodeLength)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, CodeLength)
+	for i, b := range bytes {
+		code[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return Code(code), nil
+}
+
+// ParseCode normalizes a code typed or pasted by a player
+func ParseCode(s string) (Code, error) {
+	code := strings.ToUpper(strings.TrimSpace(s))
+	if len(code) != CodeLength || strings.Trim(code, codeAlphabet) != "" {
+		return "", shared.NewDomainError(shared.ErrCodeInvalidReferralCode, "Invalid referral code")
+	}
+	return Code(code), nil
+}
+
+// String returns string representation of Code
+func (c Code) String() string {
+	return string(c)
+}
+
+// Milestone rewards both players once the referred trainer reaches a level
+type Milestone struct {
+	Level         int `json:"level"`
+	ReferrerMoney int `json:"referrer_money"`
+	ReferredMoney int `json:"referred_money"`
+}
+
+// Milestones are the referral rewards in level order
+var Milestones = []Milestone{
+	{Level: 5, ReferrerMoney: 500, ReferredMoney: 250},
+	{Level: 10, ReferrerMoney: 1500, ReferredMoney: 750},
+	{Level: 20, ReferrerMoney: 5000, ReferredMoney: 2500},
+}
+
+// Fingerprint describes where a signup or login came from
+type Fingerprint struct {
+	DeviceID string
+	IP       string
+}
+
+// Hashes returns hashed identifiers for the fingerprint's known parts, so raw
+// device IDs and addresses are not stored with referrals
+func (f Fingerprint) Hashes() []string {
+	hashes := make([]string, 0, 2)
+	if f.DeviceID != "" {
+		hashes = append(hashes, hashPart("device", f.DeviceID))
+	}
+	if ip := normalizeIP(f.IP); ip != "" {
+		hashes = append(hashes, hashPart("ip", ip))
+	}
+	return hashes
+}
+
+// Referral records that a new player signed up with another player's code
+type Referral struct {
+	ReferredID    string    `json:"referred_id"`
+	ReferrerID    string    `json:"referrer_id"`
+	Code          Code      `json:"code"`
+	Fingerprints  []string  `json:"fingerprints"`   // Hashed device and IP of the signup, claimed so they cannot be reused
+	ClaimedLevels []int     `json:"claimed_levels"` // Milestones already rewarded
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// NewReferral attributes a new player's signup to the owner of a code
+func NewReferral(referrerID, referredID string, code Code, fingerprint Fingerprint) (*Referral, error) {
+	if referrerID == referredID {
+		return nil, shared.NewDomainError(shared.ErrCodeReferralAbuse, "Players cannot refer themselves")
+	}
+
+	now := time.Now()
+	return &Referral{
+		ReferredID:    referredID,
+		ReferrerID:    referrerID,
+		Code:          code,
+		Fingerprints:  fingerprint.Hashes(),
+		ClaimedLevels: make([]int, 0, len(Milestones)),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// Claim marks every milestone the referred trainer has reached as rewarded and returns
+// the ones that were not rewarded before
+func (r *Referral) Claim(level int) []Milestone {
+	claimed := make([]Milestone, 0)
+	for _, m := range Milestones {
+		if m.Level > level || slices.Contains(r.ClaimedLevels, m.Level) {
+			continue
+		}
+		r.ClaimedLevels = append(r.ClaimedLevels, m.Level)
+		claimed = append(claimed, m)
+	}
+
+	if len(claimed) > 0 {
+		r.UpdatedAt = time.Now()
+	}
+	return claimed
+}
+
+// hashPart hashes one part of a fingerprint
+func hashPart(kind, value string) string {
+	sum := sha256.Sum256([]byte(kind + ":" + value))
+	return kind + ":" + hex.EncodeToString(sum[:16])
+}
+
+// normalizeIP keeps the client address from a forwarded list and drops the port
+func normalizeIP(ip string) string {
+	ip, _, _ = strings.Cut(ip, ",")
+	ip = strings.TrimSpace(ip)
+	if host, _, ok := strings.Cut(ip, "]:"); ok && strings.HasPrefix(host, "[") {
+		return strings.TrimPrefix(host, "[") // [ipv6]:port
+	}
+	if strings.Count(ip, ":") == 1 {
+		ip, _, _ = strings.Cut(ip, ":") // ipv4:port
+	}
+	return ip
+}
+
+// Role is which side of a referral a player is on
+type Role string
+
+const (
+	RoleReferrer Role = "referrer"
+	RoleReferred Role = "referred"
+)
+
+// Invite is what a player shares to bring in new players
+type Invite struct {
+	Code Code   `json:"code"`
+	Link string `json:"link"`
+}
+
+// ReferredPlayer is a player someone brought in, as the referrer sees them
+type ReferredPlayer struct {
+	Profile       *trainer.PublicProfile `json:"profile"`
+	ClaimedLevels []int                  `json:"claimed_levels"`
+	JoinedAt      time.Time              `json:"joined_at"`
+}
+
+// Summary is a player's invite and the players they brought in
+type Summary struct {
+	Invite     Invite                 `json:"invite"`
+	ReferredBy *trainer.PublicProfile `json:"referred_by,omitempty"`
+	Referrals  []ReferredPlayer       `json:"referrals"`
+	Milestones []Milestone            `json:"milestones"`
+}
+
+// Reward is money paid to a player for a reached milestone
+type Reward struct {
+	Role     Role   `json:"role"`
+	Nickname string `json:"nickname"` // The referred player whose level earned the reward
+	Level    int    `json:"level"`
+	Money    int    `json:"money"`
+}
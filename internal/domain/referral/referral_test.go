@@ -0,0 +1,50 @@
+package referral
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCode(t *testing.T) {
+	code, err := NewCode()
+	require.NoError(t, err)
+
+	parsed, err := ParseCode(" " + string(code) + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, code, parsed)
+
+	lower, err := ParseCode("abcd2345")
+	require.NoError(t, err)
+	assert.Equal(t, Code("ABCD2345"), lower)
+
+	_, err = ParseCode("ABCD0OI1")
+	assert.Error(t, err, "confusable characters are never generated")
+	_, err = ParseCode("ABC")
+	assert.Error(t, err)
+}
+
+func TestReferral_Claim(t *testing.T) {
+	_, err := NewReferral("user-1", "user-1", "ABCD2345", Fingerprint{})
+	assert.Error(t, err, "players cannot refer themselves")
+
+	ref, err := NewReferral("user-1", "user-2", "ABCD2345", Fingerprint{DeviceID: "device-1"})
+	require.NoError(t, err)
+
+	assert.Empty(t, ref.Claim(4))
+	assert.Equal(t, Milestones[:2], ref.Claim(12))
+	assert.Empty(t, ref.Claim(12), "milestones are only rewarded once")
+	assert.Equal(t, Milestones[2:], ref.Claim(20))
+}
+
+func TestFingerprint_Hashes(t *testing.T) {
+	direct := Fingerprint{IP: "203.0.113.7:52100"}.Hashes()
+	forwarded := Fingerprint{IP: "203.0.113.7, 10.0.0.1"}.Hashes()
+	assert.Equal(t, direct, forwarded, "ports and proxy hops are ignored")
+
+	both := Fingerprint{DeviceID: "device-1", IP: "[2001:db8::1]:443"}.Hashes()
+	assert.Len(t, both, 2)
+	assert.Equal(t, Fingerprint{IP: "2001:db8::1"}.Hashes()[0], both[1])
+	assert.Empty(t, Fingerprint{}.Hashes())
+}
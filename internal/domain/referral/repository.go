@@ -0,0 +1,39 @@
+package referral
+
+import (
+	"context"
+)
+
+// Repository defines the interface for referral persistence operations with IoC pattern
+type Repository interface {
+	// GetOrCreateCode returns the user's referral code, storing a generated one if they have none yet
+	GetOrCreateCode(ctx context.Context, userID string, generate func() (Code, error)) (Code, error)
+
+	// GetReferrer returns the owner of a referral code, or "" if the code is unknown
+	GetReferrer(ctx context.Context, code Code) (string, error)
+
+	// FindOneAndInsert stores a referral for a referred user with callback for initialization.
+	// It fails if the user was already referred or any of the referral's fingerprints was
+	// already used by another referral.
+	FindOneAndInsert(ctx context.Context, referredID string, callback func() (*Referral, error)) error
+
+	// FindOneAndUpdate finds the referral of a referred user and applies callback for atomic update
+	FindOneAndUpdate(ctx context.Context, referredID string, callback func(*Referral) (*Referral, error)) error
+
+	// GetByReferred retrieves the referral of a referred user (read-only), returning nil if none
+	GetByReferred(ctx context.Context, referredID string) (*Referral, error)
+
+	// GetByReferrer retrieves the referrals credited to a referrer (read-only)
+	GetByReferrer(ctx context.Context, referrerID string) ([]*Referral, error)
+
+	// RecordFingerprint remembers fingerprint hashes the user has logged in from
+	RecordFingerprint(ctx context.Context, userID string, hashes []string) error
+
+	// SharesFingerprint checks if the user has logged in from any of the fingerprint hashes
+	SharesFingerprint(ctx context.Context, userID string, hashes []string) (bool, error)
+
+	// DeleteUser removes the user's code, the referral that brought them in and their
+	// fingerprints. Fingerprint claims stay so a deleted account's device or network can't
+	// back another referral.
+	DeleteUser(ctx context.Context, userID string) error
+}
@@ -0,0 +1,122 @@
+package room
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// roomsIndexKey is the set of all room IDs
+const roomsIndexKey = "idx:rooms"
+
+// RedisRepository implements Repository with a JSON document per room, a set of all rooms
+// and a key per member pointing at their room
+type RedisRepository struct {
+	client *redis.Client
+	docs   *redisx.Repository[Room]
+}
+
+// NewRedisRepository creates a new Redis-based room repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+		docs: redisx.NewRepository(client, "room:", redisx.Options[Room]{
+			Indexes:       roomIndexes(),
+			NotFound:      func() error { return shared.ErrNotFound("room") },
+			AlreadyExists: func() error { return shared.ErrAlreadyExists("room") },
+		}),
+	}
+}
+
+func roomIndexes() []redisx.Index[Room] {
+	return []redisx.Index[Room]{
+		// All rooms
+		redisx.SetIndex[Room]{Key: func(r *Room) string {
+			return roomsIndexKey
+		}},
+		redisx.IndexFunc[Room](updateMemberIndex),
+	}
+}
+
+// updateMemberIndex points the members a room has after a write at it and drops the ones
+// it had before
+func updateMemberIndex(ctx context.Context, pipe redis.Pipeliner, id string, before, after *Room) {
+	if before != nil {
+		for _, userID := range before.Members {
+			if after == nil || !after.HasMember(userID) {
+				pipe.Del(ctx, memberKey(userID))
+			}
+		}
+	}
+	if after != nil {
+		for _, userID := range after.Members {
+			pipe.Set(ctx, memberKey(userID), id, 0)
+		}
+	}
+}
+
+// memberKey returns the key holding the room a player is in
+func memberKey(userID string) string {
+	return fmt.Sprintf("room:member:%s", userID)
+}
+
+// FindOneAndInsert implements IoC pattern for insert operations
+func (r *RedisRepository) FindOneAndInsert(ctx context.Context, id ID, callback func() (*Room, error)) error {
+	return r.docs.FindOneAndInsert(ctx, id.String(), callback)
+}
+
+// FindOneAndUpdate implements IoC pattern for update operations
+func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, id ID, callback func(*Room) (*Room, error)) error {
+	return r.docs.FindOneAndUpdate(ctx, id.String(), callback)
+}
+
+// GetByID retrieves a room by ID
+func (r *RedisRepository) GetByID(ctx context.Context, id ID) (*Room, error) {
+	return r.docs.GetByID(ctx, id.String())
+}
+
+// Delete removes a room with its index entries
+func (r *RedisRepository) Delete(ctx context.Context, id ID) error {
+	return r.docs.Delete(ctx, id.String())
+}
+
+// List retrieves the rooms in the index, skipping entries whose room is gone
+func (r *RedisRepository) List(ctx context.Context) ([]*Room, error) {
+	ids, err := r.client.SMembers(ctx, roomsIndexKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rooms: %w", err)
+	}
+
+	rooms := make([]*Room, 0, len(ids))
+	for _, id := range ids {
+		room, err := r.GetByID(ctx, ID(id))
+		if err != nil {
+			return nil, err
+		}
+		if room != nil {
+			rooms = append(rooms, room)
+		}
+	}
+
+	sort.Slice(rooms, func(i, j int) bool {
+		return rooms[i].CreatedAt.Before(rooms[j].CreatedAt)
+	})
+	return rooms, nil
+}
+
+// MemberRoom returns the room a player's member key points at
+func (r *RedisRepository) MemberRoom(ctx context.Context, userID string) (ID, error) {
+	id, err := r.client.Get(ctx, memberKey(userID)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to get room of member: %w", err)
+	}
+	return ID(id), nil
+}
@@ -0,0 +1,26 @@
+package room
+
+import (
+	"context"
+)
+
+// Repository defines the interface for room persistence operations with IoC pattern
+type Repository interface {
+	// FindOneAndInsert inserts a new room with callback for initialization
+	FindOneAndInsert(ctx context.Context, id ID, callback func() (*Room, error)) error
+
+	// FindOneAndUpdate finds a room by ID and applies callback for atomic update
+	FindOneAndUpdate(ctx context.Context, id ID, callback func(*Room) (*Room, error)) error
+
+	// GetByID retrieves a room by ID, nil when there is none (read-only)
+	GetByID(ctx context.Context, id ID) (*Room, error)
+
+	// Delete removes a room
+	Delete(ctx context.Context, id ID) error
+
+	// List retrieves all rooms, oldest first (read-only)
+	List(ctx context.Context) ([]*Room, error)
+
+	// MemberRoom returns the room a player is in, "" when they are in none (read-only)
+	MemberRoom(ctx context.Context, userID string) (ID, error)
+}
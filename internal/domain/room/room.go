@@ -0,0 +1,130 @@
+package room
+
+import (
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/instance"
+)
+
+const (
+	// MinCapacity is the fewest players a room can be made for
+	MinCapacity = 2
+	// MaxCapacity is the most players a room can be made for
+	MaxCapacity = 16
+	// DefaultCapacity is the capacity of rooms created without one
+	DefaultCapacity = 8
+	// MaxNameLength is the longest room name in characters
+	MaxNameLength = 32
+)
+
+// ID represents a unique room identifier
+type ID shared.ID
+
+// NewID creates a new room ID
+func NewID() ID {
+	return ID(shared.NewID())
+}
+
+// String returns string representation
+func (id ID) String() string {
+	return string(id)
+}
+
+// Instance returns the instance the room's Redis keys are namespaced under
+func (id ID) Instance() instance.ID {
+	return instance.ID(id)
+}
+
+// Room is a group of players separated from the rest of the tenant, such as the players of an
+// arena match. The owner is its first member and hands the room over when leaving.
+type Room struct {
+	ID        ID        `json:"id"`
+	Name      string    `json:"name"`
+	OwnerID   string    `json:"owner_id"`
+	Capacity  int       `json:"capacity"`
+	Members   []string  `json:"members"` // User IDs in the order they joined
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewRoom creates a room with its owner as the only member. A zero capacity is
+// DefaultCapacity.
+func NewRoom(ownerID, name string, capacity int) (*Room, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, shared.ErrInvalidInput("Room name cannot be empty")
+	}
+	if utf8.RuneCountInString(name) > MaxNameLength {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Room name cannot be longer than %d characters", MaxNameLength)
+	}
+
+	if capacity == 0 {
+		capacity = DefaultCapacity
+	}
+	if capacity < MinCapacity || capacity > MaxCapacity {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Room capacity must be between %d and %d", MinCapacity, MaxCapacity)
+	}
+
+	now := time.Now()
+	return &Room{
+		ID:        NewID(),
+		Name:      name,
+		OwnerID:   ownerID,
+		Capacity:  capacity,
+		Members:   []string{ownerID},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+// HasMember checks if a player is in the room
+func (r *Room) HasMember(userID string) bool {
+	return slices.Contains(r.Members, userID)
+}
+
+// IsFull checks if the room has no place left
+func (r *Room) IsFull() bool {
+	return len(r.Members) >= r.Capacity
+}
+
+// IsEmpty checks if everyone left the room
+func (r *Room) IsEmpty() bool {
+	return len(r.Members) == 0
+}
+
+// Join adds a player to the room
+func (r *Room) Join(userID string) error {
+	if r.HasMember(userID) {
+		return shared.NewDomainError(shared.ErrCodeAlreadyInRoom, "Already in this room")
+	}
+	if r.IsFull() {
+		return shared.NewDomainErrorf(shared.ErrCodeRoomFull, "Room is full with %d players", r.Capacity)
+	}
+
+	r.Members = append(r.Members, userID)
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// Leave removes a player from the room. When the owner leaves, the member who joined next
+// owns the room.
+func (r *Room) Leave(userID string) error {
+	i := slices.Index(r.Members, userID)
+	if i < 0 {
+		return shared.NewDomainError(shared.ErrCodeNotInRoom, "Not in this room")
+	}
+
+	r.Members = slices.Delete(r.Members, i, i+1)
+	if r.OwnerID == userID {
+		r.OwnerID = ""
+		if len(r.Members) > 0 {
+			r.OwnerID = r.Members[0]
+		}
+	}
+	r.UpdatedAt = time.Now()
+	return nil
+}
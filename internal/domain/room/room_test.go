@@ -0,0 +1,53 @@
+package room
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/shared/sharedtest"
+)
+
+func TestNewRoom(t *testing.T) {
+	r, err := NewRoom("owner", "  Arena  ", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Arena", r.Name)
+	assert.Equal(t, DefaultCapacity, r.Capacity)
+	assert.Equal(t, []string{"owner"}, r.Members)
+
+	_, err = NewRoom("owner", " ", 0)
+	assert.Equal(t, shared.ErrCodeInvalidInput, sharedtest.ErrorCode(t, err))
+	_, err = NewRoom("owner", "Arena", MinCapacity-1)
+	assert.Equal(t, shared.ErrCodeInvalidInput, sharedtest.ErrorCode(t, err))
+	_, err = NewRoom("owner", "Arena", MaxCapacity+1)
+	assert.Equal(t, shared.ErrCodeInvalidInput, sharedtest.ErrorCode(t, err))
+}
+
+func TestRoom_Join(t *testing.T) {
+	r, err := NewRoom("owner", "Arena", 2)
+	require.NoError(t, err)
+
+	assert.Equal(t, shared.ErrCodeAlreadyInRoom, sharedtest.ErrorCode(t, r.Join("owner")))
+	require.NoError(t, r.Join("guest"))
+	assert.True(t, r.IsFull())
+	assert.Equal(t, shared.ErrCodeRoomFull, sharedtest.ErrorCode(t, r.Join("late")))
+}
+
+func TestRoom_LeaveHandsOverOwnership(t *testing.T) {
+	r, err := NewRoom("owner", "Arena", 3)
+	require.NoError(t, err)
+	require.NoError(t, r.Join("first"))
+	require.NoError(t, r.Join("second"))
+
+	require.NoError(t, r.Leave("owner"))
+	assert.Equal(t, "first", r.OwnerID)
+	assert.Equal(t, shared.ErrCodeNotInRoom, sharedtest.ErrorCode(t, r.Leave("owner")))
+
+	require.NoError(t, r.Leave("second"))
+	assert.Equal(t, "first", r.OwnerID)
+	require.NoError(t, r.Leave("first"))
+	assert.True(t, r.IsEmpty())
+	assert.Empty(t, r.OwnerID)
+}
@@ -0,0 +1,197 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// indexPollInterval is how often Wait checks whether an index finished scanning existing documents
+const indexPollInterval = 100 * time.Millisecond
+
+// RedisIndex finds document keys through a RediSearch index over JSON documents
+type RedisIndex struct {
+	client  *redis.Client
+	name    string
+	prefix  string
+	created chan struct{}
+}
+
+// NewRedisIndex creates a search index over the JSON documents stored under prefix.
+// Schema is the FT.CREATE field list, e.g. "$.nickname", "AS", "nickname", "TEXT".
+func NewRedisIndex(client *redis.Client, name, prefix string, schema ...any) *RedisIndex {
+	index := &RedisIndex{
+		client:  client,
+		name:    name,
+		prefix:  prefix,
+		created: make(chan struct{}),
+	}
+
+	// Initialize search index (non-blocking)
+	go index.initialize(schema)
+
+	return index
+}
+
+// initialize recreates the index so schema changes apply. Existing documents are re-indexed.
+func (i *RedisIndex) initialize(schema []any) {
+	defer close(i.created)
+	ctx := context.Background()
+
+	// Drop existing index if it exists (ignore errors)
+	i.client.Do(ctx, "FT.DROPINDEX", i.name)
+
+	args := []any{"FT.CREATE", i.name, "ON", "JSON", "PREFIX", "1", i.prefix, "SCHEMA"}
+	if _, err := i.client.Do(ctx, append(args, schema...)...).Result(); err != nil {
+		fmt.Printf("Warning: Failed to create %s search index: %v\n", i.name, err)
+	}
+}
+
+// Wait blocks until the index is created and has indexed the documents stored before it
+func (i *RedisIndex) Wait(ctx context.Context) error {
+	select {
+	case <-i.created:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return WaitIndexed(ctx, i.client, i.name)
+}
+
+// WaitIndexed polls FT.INFO until an index has finished its scan of existing documents.
+// Searches made during the scan miss documents it hasn't reached yet.
+func WaitIndexed(ctx context.Context, client *redis.Client, name string) error {
+	ticker := time.NewTicker(indexPollInterval)
+	defer ticker.Stop()
+
+	for {
+		reply, err := client.Do(ctx, "FT.INFO", name).Result()
+		if err != nil {
+			return fmt.Errorf("failed to get %s index info: %w", name, err)
+		}
+
+		indexing, err := parseIndexingInfo(reply)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s index info: %w", name, err)
+		}
+		if !indexing {
+			return nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Find returns the total number of matches and the IDs of one page of them
+func (i *RedisIndex) Find(ctx context.Context, expression string, offset, limit int) (int, []string, error) {
+	return i.FindSorted(ctx, expression, "", offset, limit)
+}
+
+// FindSorted is Find with the matches ordered by a sortable field, ascending. An empty field
+// leaves them in relevance order.
+func (i *RedisIndex) FindSorted(ctx context.Context, expression, field string, offset, limit int) (int, []string, error) {
+	args := []any{"FT.SEARCH", i.name, expression, "NOCONTENT"}
+	if field != "" {
+		args = append(args, "SORTBY", field, "ASC")
+	}
+	args = append(args, "LIMIT", offset, limit)
+
+	reply, err := i.client.Do(ctx, args...).Result()
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to search %s: %w", i.name, err)
+	}
+
+	total, keys, err := parseSearchReply(reply)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to parse %s search results: %w", i.name, err)
+	}
+
+	ids := make([]string, 0, len(keys))
+	for _, key := range keys {
+		ids = append(ids, strings.TrimPrefix(key, i.prefix))
+	}
+
+	return total, ids, nil
+}
+
+// parseSearchReply reads a NOCONTENT FT.SEARCH reply. RESP2 replies are [total, key, key, ...];
+// RESP3 replies are a map with total_results and a results list of {id: key}.
+func parseSearchReply(reply any) (int, []string, error) {
+	switch r := reply.(type) {
+	case []any:
+		if len(r) == 0 {
+			return 0, nil, fmt.Errorf("empty reply")
+		}
+		total, ok := r[0].(int64)
+		if !ok {
+			return 0, nil, fmt.Errorf("unexpected total %v", r[0])
+		}
+
+		keys := make([]string, 0, len(r)-1)
+		for _, key := range r[1:] {
+			if s, ok := key.(string); ok {
+				keys = append(keys, s)
+			}
+		}
+		return int(total), keys, nil
+
+	case map[any]any:
+		total, ok := r["total_results"].(int64)
+		if !ok {
+			return 0, nil, fmt.Errorf("unexpected total %v", r["total_results"])
+		}
+
+		results, _ := r["results"].([]any)
+		keys := make([]string, 0, len(results))
+		for _, result := range results {
+			doc, ok := result.(map[any]any)
+			if !ok {
+				continue
+			}
+			if s, ok := doc["id"].(string); ok {
+				keys = append(keys, s)
+			}
+		}
+		return int(total), keys, nil
+
+	default:
+		return 0, nil, fmt.Errorf("unexpected reply type %T", reply)
+	}
+}
+
+// parseIndexingInfo reads the indexing flag of an FT.INFO reply, which is set while the index scans
+// existing documents. RESP2 replies are a flat [name, value, ...] list; RESP3 replies are a map.
+func parseIndexingInfo(reply any) (bool, error) {
+	var value any
+	switch r := reply.(type) {
+	case []any:
+		for i := 0; i+1 < len(r); i += 2 {
+			if r[i] == "indexing" {
+				value = r[i+1]
+				break
+			}
+		}
+	case map[any]any:
+		value = r["indexing"]
+	default:
+		return false, fmt.Errorf("unexpected reply type %T", reply)
+	}
+
+	// Depending on the module version the flag is an integer, a double or a string
+	switch v := value.(type) {
+	case int64:
+		return v != 0, nil
+	case float64:
+		return v != 0, nil
+	case string:
+		return v != "0", nil
+	default:
+		return false, fmt.Errorf("unexpected indexing flag %v", value)
+	}
+}
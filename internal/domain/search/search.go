@@ -0,0 +1,129 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// Result limits applied to each searched type
+const (
+	DefaultLimit  = 10
+	MaxLimit      = 50
+	MaxTextLength = 64
+)
+
+// Type is a kind of searchable document, each backed by its own index
+type Type string
+
+const (
+	TypeTrainer Type = "trainer"
+)
+
+// String returns string representation of Type
+func (t Type) String() string {
+	return string(t)
+}
+
+// Index searches one type of document
+type Index interface {
+	Type() Type
+	// Search returns the hits matching a query expression, skipping offset hits
+	Search(ctx context.Context, expression string, offset, limit int) (*Group, error)
+}
+
+// Query is a search across one or more indexes. Offset and Limit apply to each type separately.
+type Query struct {
+	Text   string `json:"text"`
+	Types  []Type `json:"types,omitempty"` // Empty searches every type
+	Offset int    `json:"offset,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+// Validate checks the query against the searchable types and fills in defaults
+func (q *Query) Validate(searchable []Type) error {
+	q.Text = strings.TrimSpace(q.Text)
+	if q.Text == "" {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "Search text is required")
+	}
+	if utf8.RuneCountInString(q.Text) > MaxTextLength {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Search text cannot exceed %d characters", MaxTextLength)
+	}
+
+	if len(q.Types) == 0 {
+		q.Types = slices.Clone(searchable)
+	}
+	for _, t := range q.Types {
+		if !slices.Contains(searchable, t) {
+			return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unsupported search type: %s", t)
+		}
+	}
+	q.Types = slices.Compact(q.Types)
+
+	if q.Offset < 0 {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "Offset cannot be negative")
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultLimit
+	}
+	if q.Limit > MaxLimit {
+		q.Limit = MaxLimit
+	}
+
+	return nil
+}
+
+// Hit is one matching document
+type Hit struct {
+	Type   Type   `json:"type"`
+	ID     string `json:"id"`
+	Title  string `json:"title"`
+	Detail any    `json:"detail,omitempty"` // Public view of the document, shaped by its type
+}
+
+// Group is one page of hits from a single index
+type Group struct {
+	Type   Type  `json:"type"`
+	Hits   []Hit `json:"hits"`
+	Total  int   `json:"total"`
+	Offset int   `json:"offset"`
+	Limit  int   `json:"limit"`
+}
+
+// Result holds the hits for each searched type, in the order the types were requested
+type Result struct {
+	Text   string   `json:"text"`
+	Groups []*Group `json:"groups"`
+}
+
+// MatchExpression turns free text into a RediSearch query. Every word must match,
+// either as a prefix or within a small edit distance so typos still find nicknames.
+func MatchExpression(text string) (string, error) {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return "", shared.NewDomainError(shared.ErrCodeInvalidInput, "Search text must contain letters or digits")
+	}
+
+	terms := make([]string, 0, len(words))
+	for _, word := range words {
+		switch n := utf8.RuneCountInString(word); {
+		case n < 2:
+			terms = append(terms, word) // Prefix and fuzzy matching need at least two characters
+		case n < 4:
+			terms = append(terms, fmt.Sprintf("(%s*)", word))
+		case n < 7:
+			terms = append(terms, fmt.Sprintf("(%s*|%%%s%%)", word, word))
+		default:
+			terms = append(terms, fmt.Sprintf("(%s*|%%%%%s%%%%)", word, word))
+		}
+	}
+
+	return strings.Join(terms, " "), nil
+}
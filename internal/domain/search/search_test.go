@@ -0,0 +1,64 @@
+package search
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuery_Validate(t *testing.T) {
+	q := Query{Text: "  ash  ", Limit: 500}
+	require.NoError(t, q.Validate([]Type{TypeTrainer}))
+	assert.Equal(t, "ash", q.Text)
+	assert.Equal(t, []Type{TypeTrainer}, q.Types)
+	assert.Equal(t, MaxLimit, q.Limit)
+
+	assert.Error(t, (&Query{Text: " "}).Validate([]Type{TypeTrainer}))
+	assert.Error(t, (&Query{Text: "ash", Types: []Type{"guild"}}).Validate([]Type{TypeTrainer}))
+	assert.Error(t, (&Query{Text: "ash", Offset: -1}).Validate([]Type{TypeTrainer}))
+}
+
+func TestMatchExpression(t *testing.T) {
+	expr, err := MatchExpression("A ash Misty-Waterflower")
+	require.NoError(t, err)
+	assert.Equal(t, "a (ash*) (misty*|%misty%) (waterflower*|%%waterflower%%)", expr)
+
+	_, err = MatchExpression("@@ --")
+	assert.Error(t, err, "query syntax characters are dropped")
+}
+
+func TestParseSearchReply(t *testing.T) {
+	total, keys, err := parseSearchReply([]any{int64(12), "trainer:a", "trainer:b"})
+	require.NoError(t, err)
+	assert.Equal(t, 12, total)
+	assert.Equal(t, []string{"trainer:a", "trainer:b"}, keys)
+
+	total, keys, err = parseSearchReply(map[any]any{
+		"total_results": int64(3),
+		"results":       []any{map[any]any{"id": "trainer:c"}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, total)
+	assert.Equal(t, []string{"trainer:c"}, keys)
+
+	_, _, err = parseSearchReply("OK")
+	assert.Error(t, err)
+}
+
+func TestParseIndexingInfo(t *testing.T) {
+	indexing, err := parseIndexingInfo([]any{"index_name", "idx:trainer", "indexing", int64(1)})
+	require.NoError(t, err)
+	assert.True(t, indexing)
+
+	indexing, err = parseIndexingInfo(map[any]any{"index_name": "idx:trainer", "indexing": float64(0)})
+	require.NoError(t, err)
+	assert.False(t, indexing)
+
+	indexing, err = parseIndexingInfo([]any{"indexing", "0"})
+	require.NoError(t, err)
+	assert.False(t, indexing)
+
+	_, err = parseIndexingInfo([]any{"index_name", "idx:trainer"})
+	assert.Error(t, err, "a reply without the flag")
+}
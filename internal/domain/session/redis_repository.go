@@ -0,0 +1,63 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisRepository implements Repository with an expiring JSON value per player
+type RedisRepository struct {
+	client *redis.Client
+}
+
+// NewRedisRepository creates a new Redis-based session snapshot repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+	}
+}
+
+// Save stores the snapshot for SnapshotTTL
+func (r *RedisRepository) Save(ctx context.Context, userID string, snapshot *Snapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session snapshot: %w", err)
+	}
+	if err := r.client.Set(ctx, r.snapshotKey(userID), data, SnapshotTTL).Err(); err != nil {
+		return fmt.Errorf("failed to save session snapshot: %w", err)
+	}
+	return nil
+}
+
+// Take reads and deletes the snapshot in one command
+func (r *RedisRepository) Take(ctx context.Context, userID string) (*Snapshot, error) {
+	data, err := r.client.GetDel(ctx, r.snapshotKey(userID)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to take session snapshot: %w", err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// DeleteUser removes the player's snapshot
+func (r *RedisRepository) DeleteUser(ctx context.Context, userID string) error {
+	if err := r.client.Del(ctx, r.snapshotKey(userID)).Err(); err != nil {
+		return fmt.Errorf("failed to delete session snapshot: %w", err)
+	}
+	return nil
+}
+
+// snapshotKey returns the key holding a player's last session
+func (r *RedisRepository) snapshotKey(userID string) string {
+	return fmt.Sprintf("session:snapshot:%s", userID)
+}
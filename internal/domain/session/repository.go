@@ -0,0 +1,18 @@
+package session
+
+import (
+	"context"
+)
+
+// Repository keeps the snapshot of each player's last session
+type Repository interface {
+	// Save stores the snapshot of a session that ended, replacing the previous one
+	Save(ctx context.Context, userID string, snapshot *Snapshot) error
+
+	// Take returns and removes a player's snapshot, or nil if there is none. Only one of
+	// concurrent callers gets it.
+	Take(ctx context.Context, userID string) (*Snapshot, error)
+
+	// DeleteUser removes a player's snapshot
+	DeleteUser(ctx context.Context, userID string) error
+}
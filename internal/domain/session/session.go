@@ -0,0 +1,99 @@
+package session
+
+import (
+	"slices"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/crafting"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// SnapshotTTL is how long the end of a session is remembered; players away longer get no
+// summary when they come back
+const SnapshotTTL = 90 * 24 * time.Hour
+
+// Snapshot is a player's progress when their last session ended
+type Snapshot struct {
+	EndedAt    time.Time `json:"ended_at"`
+	Level      int       `json:"level"`
+	Experience int       `json:"experience"` // Total experience, which only grows
+	Money      int       `json:"money"`
+	Incoming   []string  `json:"incoming"` // Trainers who had asked to be friends
+}
+
+// TakeSnapshot records a trainer's progress along with its pending friend requests
+func TakeSnapshot(t *trainer.Trainer, incoming []string, at time.Time) *Snapshot {
+	return &Snapshot{
+		EndedAt:    at,
+		Level:      t.Level.Value(),
+		Experience: t.Experience.Total(),
+		Money:      t.Money.Amount(),
+		Incoming:   incoming,
+	}
+}
+
+// CompletedCraft is a crafting job that finished while the player was away
+type CompletedCraft struct {
+	JobID       string    `json:"job_id"`
+	RecipeID    string    `json:"recipe_id"`
+	Succeeded   bool      `json:"succeeded"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+// FriendRequest is a trainer who asked to be friends while the player was away
+type FriendRequest struct {
+	UserID   string `json:"user_id"`
+	Nickname string `json:"nickname"`
+}
+
+// Summary is what changed for a player since their last session
+type Summary struct {
+	Since            time.Time        `json:"since"`
+	LevelsGained     int              `json:"levels_gained"`
+	ExperienceGained int              `json:"experience_gained"`
+	MoneyChange      int              `json:"money_change"` // Negative when money was taken
+	CompletedCrafts  []CompletedCraft `json:"completed_crafts"`
+	FriendRequests   []FriendRequest  `json:"friend_requests"`
+}
+
+// Summarize compares the last snapshot with the current one. Jobs are the trainer's
+// uncollected crafting jobs; those completed after the session ended are listed. Friend
+// requests are listed without nicknames.
+func Summarize(last, current *Snapshot, jobs []*crafting.Job) *Summary {
+	summary := &Summary{
+		Since:            last.EndedAt,
+		LevelsGained:     current.Level - last.Level,
+		ExperienceGained: current.Experience - last.Experience,
+		MoneyChange:      current.Money - last.Money,
+		CompletedCrafts:  make([]CompletedCraft, 0),
+		FriendRequests:   make([]FriendRequest, 0),
+	}
+
+	for _, job := range jobs {
+		if job.Status == crafting.JobCompleted && job.CompletedAt.After(last.EndedAt) {
+			summary.CompletedCrafts = append(summary.CompletedCrafts, CompletedCraft{
+				JobID:       job.ID.String(),
+				RecipeID:    job.RecipeID.String(),
+				Succeeded:   job.Succeeded,
+				CompletedAt: job.CompletedAt,
+			})
+		}
+	}
+	slices.SortFunc(summary.CompletedCrafts, func(a, b CompletedCraft) int {
+		return a.CompletedAt.Compare(b.CompletedAt)
+	})
+
+	for _, userID := range current.Incoming {
+		if !slices.Contains(last.Incoming, userID) {
+			summary.FriendRequests = append(summary.FriendRequests, FriendRequest{UserID: userID})
+		}
+	}
+
+	return summary
+}
+
+// IsEmpty reports whether nothing worth showing changed
+func (s *Summary) IsEmpty() bool {
+	return s.LevelsGained == 0 && s.ExperienceGained == 0 && s.MoneyChange == 0 &&
+		len(s.CompletedCrafts) == 0 && len(s.FriendRequests) == 0
+}
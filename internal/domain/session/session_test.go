@@ -0,0 +1,43 @@
+package session
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/crafting"
+)
+
+func TestSummarize(t *testing.T) {
+	ended := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	last := &Snapshot{EndedAt: ended, Level: 3, Experience: 400, Money: 1000, Incoming: []string{"a"}}
+	current := &Snapshot{EndedAt: ended.Add(time.Hour), Level: 4, Experience: 550, Money: 900, Incoming: []string{"a", "b"}}
+	jobs := []*crafting.Job{
+		{ID: "late", RecipeID: "potion", Status: crafting.JobCompleted, Succeeded: true, CompletedAt: ended.Add(30 * time.Minute)},
+		{ID: "early", RecipeID: "rope", Status: crafting.JobCompleted, CompletedAt: ended.Add(10 * time.Minute)},
+		{ID: "before", RecipeID: "rope", Status: crafting.JobCompleted, CompletedAt: ended.Add(-time.Minute)},
+		{ID: "running", RecipeID: "rope", Status: crafting.JobInProgress},
+	}
+
+	summary := Summarize(last, current, jobs)
+
+	assert.Equal(t, ended, summary.Since)
+	assert.Equal(t, 1, summary.LevelsGained)
+	assert.Equal(t, 150, summary.ExperienceGained)
+	assert.Equal(t, -100, summary.MoneyChange)
+	require.Len(t, summary.CompletedCrafts, 2)
+	assert.Equal(t, "early", summary.CompletedCrafts[0].JobID)
+	assert.Equal(t, "late", summary.CompletedCrafts[1].JobID)
+	assert.True(t, summary.CompletedCrafts[1].Succeeded)
+	assert.Equal(t, []FriendRequest{{UserID: "b"}}, summary.FriendRequests)
+	assert.False(t, summary.IsEmpty())
+}
+
+func TestSummarize_NothingChanged(t *testing.T) {
+	last := &Snapshot{EndedAt: time.Now(), Level: 2, Experience: 100, Money: 50}
+	current := &Snapshot{EndedAt: time.Now(), Level: 2, Experience: 100, Money: 50}
+
+	assert.True(t, Summarize(last, current, nil).IsEmpty())
+}
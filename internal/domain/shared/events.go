@@ -1,6 +1,7 @@
 package shared
 
 import (
+	"context"
 	"encoding/json"
 	"time"
 
@@ -89,14 +90,16 @@ func NewBaseEvent(eventType, aggregateID, aggregateType string, data interface{}
 	}, nil
 }
 
-// EventStore represents an event store interface
+// EventStore represents an event store interface. An aggregate's events are numbered from 1
+// in the order they were saved, and an aggregate's version is the number of its last event.
 type EventStore interface {
-	// SaveEvents saves events for an aggregate
-	SaveEvents(aggregateID string, events []Event, expectedVersion int) error
+	// SaveEvents saves events for an aggregate, failing with a version conflict unless the
+	// aggregate is at expectedVersion
+	SaveEvents(ctx context.Context, aggregateID string, events []Event, expectedVersion int) error
 	// LoadEvents loads all events for an aggregate
-	LoadEvents(aggregateID string) ([]Event, error)
-	// LoadEventsFromVersion loads events from a specific version
-	LoadEventsFromVersion(aggregateID string, fromVersion int) ([]Event, error)
+	LoadEvents(ctx context.Context, aggregateID string) ([]Event, error)
+	// LoadEventsFromVersion loads the events numbered fromVersion and later
+	LoadEventsFromVersion(ctx context.Context, aggregateID string, fromVersion int) ([]Event, error)
 }
 
 // EventBus represents an event bus interface
@@ -176,6 +179,7 @@ const (
 	ErrCodeAlreadyExists     = 1003
 	ErrCodeInvalidOperation  = 1004
 	ErrCodeInsufficientFunds = 1005
+	ErrCodeVersionConflict   = 1006
 
 	// Trainer specific errors (2000-2999)
 	ErrCodeInvalidNickname      = 2001
@@ -189,6 +193,12 @@ const (
 	ErrCodeInvalidAmount        = 2009
 	ErrCodeInvalidItemType      = 2010
 	ErrCodeItemNotFound         = 2011
+	ErrCodeItemBound            = 2012
+	ErrCodeItemNotConsumable    = 2013
+	ErrCodeItemOnCooldown       = 2014
+	ErrCodeMoveThrottled        = 2015
+	ErrCodeMoveRejected         = 2016
+	ErrCodeTrainerDead          = 2017
 
 	// Animal specific errors (3000-3999)
 	ErrCodeInvalidAnimalType      = 3001
@@ -213,6 +223,78 @@ const (
 	ErrCodeEntityAlreadyOnTile = 5004
 	ErrCodeEntityNotOnTile     = 5005
 	ErrCodeInvalidMove         = 5006
+
+	// Loot specific errors (6000-6999)
+	ErrCodeInvalidLootTable   = 6001
+	ErrCodePickupNotClaimable = 6002
+	ErrCodePickupExpired      = 6003
+
+	// Crafting specific errors (7000-7999)
+	ErrCodeUnknownRecipe       = 7001
+	ErrCodeCraftLevelTooLow    = 7002
+	ErrCodeCraftNotReady       = 7003
+	ErrCodeCraftAlreadyClaimed = 7004
+
+	// Vault specific errors (8000-8999)
+	ErrCodeVaultFull      = 8001
+	ErrCodeNotAtVault     = 8002
+	ErrCodeItemNotInVault = 8003
+
+	// Fairness specific errors (9000-9999)
+	ErrCodeRollMismatch    = 9001
+	ErrCodeSeedNotRevealed = 9002
+
+	// Battle specific errors (10000-10999)
+	ErrCodeAlreadyInBattle = 10001
+	ErrCodeNotInBattle     = 10002
+	ErrCodeOutOfRange      = 10003
+
+	// Referral specific errors (11000-11999)
+	ErrCodeInvalidReferralCode = 11001
+	ErrCodeReferralAbuse       = 11002
+
+	// Account specific errors (12000-12999)
+	ErrCodeInvalidPairCode          = 12001
+	ErrCodeEmailNotVerified         = 12002
+	ErrCodeInvalidVerificationToken = 12003
+	ErrCodeInvalidLoginCode         = 12004
+	ErrCodeInvalidMergeToken        = 12005
+	ErrCodeCaptchaFailed            = 12006
+
+	// Friend specific errors (13000-13999)
+	ErrCodeFriendLimit        = 13001
+	ErrCodeFriendRequestLimit = 13002
+
+	// Chat specific errors (14000-14999)
+	ErrCodeChatFlood = 14001
+
+	// Live-ops specific errors (15000-15999)
+	ErrCodeScriptInvalid = 15001
+
+	// Character specific errors (16000-16999)
+	ErrCodeCharacterLimit = 16001
+
+	// Challenge specific errors (17000-17999)
+	ErrCodeUnknownChallenge    = 17001
+	ErrCodeChallengeIncomplete = 17002
+	ErrCodeChallengeClaimed    = 17003
+
+	// Weapon specific errors (18000-18999)
+	ErrCodeNoAmmo       = 18001
+	ErrCodeReloading    = 18002
+	ErrCodeMagazineFull = 18003
+	ErrCodeReserveFull  = 18004
+	ErrCodeCoolingDown  = 18005
+
+	// Room specific errors (19000-19999)
+	ErrCodeRoomFull      = 19001
+	ErrCodeAlreadyInRoom = 19002
+	ErrCodeNotInRoom     = 19003
+
+	// Match specific errors (20000-20999)
+	ErrCodeMatchInProgress  = 20001
+	ErrCodeNotEnoughPlayers = 20002
+	ErrCodeNoMatch          = 20003
 )
 
 // NewDomainError creates a new domain error using oops
@@ -242,6 +324,16 @@ func WrapDomainError(err error, code int, message string) error {
 		Wrapf(err, message)
 }
 
+// DomainErrorCode returns the code of a domain error, or false if err is not one
+func DomainErrorCode(err error) (int, bool) {
+	oopsErr, ok := oops.AsOops(err)
+	if !ok {
+		return 0, false
+	}
+	code, ok := oopsErr.Context()["error_code"].(int)
+	return code, ok
+}
+
 // codeToString converts int error code to string
 func codeToString(code int) string {
 	switch code {
@@ -255,6 +347,8 @@ func codeToString(code int) string {
 		return "INVALID_OPERATION"
 	case ErrCodeInsufficientFunds:
 		return "INSUFFICIENT_FUNDS"
+	case ErrCodeVersionConflict:
+		return "VERSION_CONFLICT"
 	case ErrCodeInvalidNickname:
 		return "INVALID_NICKNAME"
 	case ErrCodeInventoryFull:
@@ -277,6 +371,12 @@ func codeToString(code int) string {
 		return "INVALID_ITEM_TYPE"
 	case ErrCodeItemNotFound:
 		return "ITEM_NOT_FOUND"
+	case ErrCodeItemBound:
+		return "ITEM_BOUND"
+	case ErrCodeItemNotConsumable:
+		return "ITEM_NOT_CONSUMABLE"
+	case ErrCodeItemOnCooldown:
+		return "ITEM_ON_COOLDOWN"
 	case ErrCodeInvalidAnimalType:
 		return "INVALID_ANIMAL_TYPE"
 	case ErrCodeInvalidState:
@@ -313,6 +413,96 @@ func codeToString(code int) string {
 		return "ENTITY_NOT_ON_TILE"
 	case ErrCodeInvalidMove:
 		return "INVALID_MOVE"
+	case ErrCodeInvalidLootTable:
+		return "INVALID_LOOT_TABLE"
+	case ErrCodePickupNotClaimable:
+		return "PICKUP_NOT_CLAIMABLE"
+	case ErrCodePickupExpired:
+		return "PICKUP_EXPIRED"
+	case ErrCodeUnknownRecipe:
+		return "UNKNOWN_RECIPE"
+	case ErrCodeCraftLevelTooLow:
+		return "CRAFT_LEVEL_TOO_LOW"
+	case ErrCodeCraftNotReady:
+		return "CRAFT_NOT_READY"
+	case ErrCodeCraftAlreadyClaimed:
+		return "CRAFT_ALREADY_CLAIMED"
+	case ErrCodeVaultFull:
+		return "VAULT_FULL"
+	case ErrCodeNotAtVault:
+		return "NOT_AT_VAULT"
+	case ErrCodeItemNotInVault:
+		return "ITEM_NOT_IN_VAULT"
+	case ErrCodeRollMismatch:
+		return "ROLL_MISMATCH"
+	case ErrCodeSeedNotRevealed:
+		return "SEED_NOT_REVEALED"
+	case ErrCodeAlreadyInBattle:
+		return "ALREADY_IN_BATTLE"
+	case ErrCodeNotInBattle:
+		return "NOT_IN_BATTLE"
+	case ErrCodeOutOfRange:
+		return "OUT_OF_RANGE"
+	case ErrCodeInvalidReferralCode:
+		return "INVALID_REFERRAL_CODE"
+	case ErrCodeReferralAbuse:
+		return "REFERRAL_ABUSE"
+	case ErrCodeInvalidPairCode:
+		return "INVALID_PAIR_CODE"
+	case ErrCodeEmailNotVerified:
+		return "EMAIL_NOT_VERIFIED"
+	case ErrCodeInvalidVerificationToken:
+		return "INVALID_VERIFICATION_TOKEN"
+	case ErrCodeInvalidLoginCode:
+		return "INVALID_LOGIN_CODE"
+	case ErrCodeInvalidMergeToken:
+		return "INVALID_MERGE_TOKEN"
+	case ErrCodeCaptchaFailed:
+		return "CAPTCHA_FAILED"
+	case ErrCodeFriendLimit:
+		return "FRIEND_LIMIT"
+	case ErrCodeFriendRequestLimit:
+		return "FRIEND_REQUEST_LIMIT"
+	case ErrCodeChatFlood:
+		return "CHAT_FLOOD"
+	case ErrCodeMoveThrottled:
+		return "MOVE_THROTTLED"
+	case ErrCodeMoveRejected:
+		return "MOVE_REJECTED"
+	case ErrCodeTrainerDead:
+		return "TRAINER_DEAD"
+	case ErrCodeScriptInvalid:
+		return "SCRIPT_INVALID"
+	case ErrCodeCharacterLimit:
+		return "CHARACTER_LIMIT"
+	case ErrCodeUnknownChallenge:
+		return "UNKNOWN_CHALLENGE"
+	case ErrCodeChallengeIncomplete:
+		return "CHALLENGE_INCOMPLETE"
+	case ErrCodeChallengeClaimed:
+		return "CHALLENGE_CLAIMED"
+	case ErrCodeNoAmmo:
+		return "NO_AMMO"
+	case ErrCodeReloading:
+		return "RELOADING"
+	case ErrCodeMagazineFull:
+		return "MAGAZINE_FULL"
+	case ErrCodeReserveFull:
+		return "RESERVE_FULL"
+	case ErrCodeCoolingDown:
+		return "COOLING_DOWN"
+	case ErrCodeRoomFull:
+		return "ROOM_FULL"
+	case ErrCodeAlreadyInRoom:
+		return "ALREADY_IN_ROOM"
+	case ErrCodeNotInRoom:
+		return "NOT_IN_ROOM"
+	case ErrCodeMatchInProgress:
+		return "MATCH_IN_PROGRESS"
+	case ErrCodeNotEnoughPlayers:
+		return "NOT_ENOUGH_PLAYERS"
+	case ErrCodeNoMatch:
+		return "NO_MATCH"
 	default:
 		return "UNKNOWN_ERROR"
 	}
@@ -0,0 +1,140 @@
+package shared
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// eventStreamPrefix prefixes the stream holding an aggregate's events
+const eventStreamPrefix = "events:"
+
+// RedisEventStore implements EventStore with a Redis stream per aggregate. Entries are added
+// with the event's number as their ID (0-1, 0-2, ...), so Redis itself refuses to number two
+// events the same, and events from a version are read by ID without scanning.
+type RedisEventStore struct {
+	client *redis.Client
+}
+
+// NewRedisEventStore creates a new Redis-based event store
+func NewRedisEventStore(client *redis.Client) *RedisEventStore {
+	return &RedisEventStore{client: client}
+}
+
+// SaveEvents appends events to the aggregate's stream if no other events were saved since
+// expectedVersion
+func (s *RedisEventStore) SaveEvents(ctx context.Context, aggregateID string, events []Event, expectedVersion int) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	key := eventStreamPrefix + aggregateID
+	err := s.client.Watch(ctx, func(tx *redis.Tx) error {
+		version, err := tx.XLen(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		if int(version) != expectedVersion {
+			return NewDomainErrorf(ErrCodeVersionConflict, "%s is at version %d, not %d", aggregateID, version, expectedVersion)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			return s.QueueEvents(ctx, pipe, aggregateID, events, expectedVersion)
+		})
+		return err
+	}, key)
+	if err == redis.TxFailedErr {
+		return NewDomainErrorf(ErrCodeVersionConflict, "%s changed while saving events", aggregateID)
+	}
+	return err
+}
+
+// QueueEvents queues appending events numbered after version into a pipeline, for callers
+// saving them in the transaction of another write that already guards the aggregate. An event
+// numbered at or below one in the stream fails its XADD instead of being stored twice.
+func (s *RedisEventStore) QueueEvents(ctx context.Context, pipe redis.Pipeliner, aggregateID string, events []Event, version int) error {
+	key := eventStreamPrefix + aggregateID
+	for i, event := range events {
+		data, err := event.Data()
+		if err != nil {
+			return fmt.Errorf("failed to encode %s event: %w", event.EventType(), err)
+		}
+
+		pipe.XAdd(ctx, &redis.XAddArgs{
+			Stream: key,
+			ID:     fmt.Sprintf("0-%d", version+i+1),
+			Values: map[string]interface{}{
+				"id":             event.EventID(),
+				"type":           event.EventType(),
+				"aggregate_type": event.AggregateType(),
+				"occurred_at":    event.OccurredAt().Format(time.RFC3339Nano),
+				"data":           string(data),
+			},
+		})
+	}
+	return nil
+}
+
+// LoadEvents loads all events for an aggregate
+func (s *RedisEventStore) LoadEvents(ctx context.Context, aggregateID string) ([]Event, error) {
+	return s.LoadEventsFromVersion(ctx, aggregateID, 1)
+}
+
+// LoadEventsFromVersion loads the events numbered fromVersion and later
+func (s *RedisEventStore) LoadEventsFromVersion(ctx context.Context, aggregateID string, fromVersion int) ([]Event, error) {
+	if fromVersion < 1 {
+		fromVersion = 1
+	}
+
+	entries, err := s.client.XRange(ctx, eventStreamPrefix+aggregateID, fmt.Sprintf("0-%d", fromVersion), "+").Result()
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]Event, 0, len(entries))
+	for _, entry := range entries {
+		event, err := storedEvent(aggregateID, entry)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// DeleteEvents removes all events of an aggregate
+func (s *RedisEventStore) DeleteEvents(ctx context.Context, aggregateID string) error {
+	return s.client.Del(ctx, eventStreamPrefix+aggregateID).Err()
+}
+
+// storedEvent decodes a stream entry into the event it holds, versioned by its number
+func storedEvent(aggregateID string, entry redis.XMessage) (Event, error) {
+	_, number, _ := strings.Cut(entry.ID, "-")
+	version, err := strconv.Atoi(number)
+	if err != nil {
+		return nil, fmt.Errorf("invalid event entry %s of %s: %w", entry.ID, aggregateID, err)
+	}
+
+	field := func(name string) string {
+		value, _ := entry.Values[name].(string)
+		return value
+	}
+	occurredAt, err := time.Parse(time.RFC3339Nano, field("occurred_at"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid time of event %d of %s: %w", version, aggregateID, err)
+	}
+
+	return BaseEvent{
+		ID:           field("id"),
+		Type:         field("type"),
+		AggrID:       aggregateID,
+		AggrType:     field("aggregate_type"),
+		Timestamp:    occurredAt,
+		EventVersion: version,
+		EventData:    []byte(field("data")),
+	}, nil
+}
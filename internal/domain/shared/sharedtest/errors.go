@@ -0,0 +1,18 @@
+// Package sharedtest holds test helpers shared by the domain packages
+package sharedtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// ErrorCode returns the code of a domain error, failing the test if err is not one
+func ErrorCode(t testing.TB, err error) int {
+	t.Helper()
+	code, ok := shared.DomainErrorCode(err)
+	require.True(t, ok, "expected a domain error, got %v", err)
+	return code
+}
@@ -1,6 +1,7 @@
 package shared
 
 import (
+	"encoding/json"
 	"fmt"
 	"time"
 
@@ -47,6 +48,11 @@ func (p Position) DistanceTo(other Position) float64 {
 	return dx*dx + dy*dy // Using squared distance for performance
 }
 
+// WithinRange checks if another position is at most r away
+func (p Position) WithinRange(other Position, r float64) bool {
+	return p.DistanceTo(other) <= r*r
+}
+
 // IsAdjacent checks if this position is adjacent to another (within 1 unit)
 func (p Position) IsAdjacent(other Position) bool {
 	dx := p.X - other.X
@@ -95,6 +101,17 @@ func (s Stats) Add(other Stats) Stats {
 	}
 }
 
+// Sub subtracts another stats from this stats
+func (s Stats) Sub(other Stats) Stats {
+	return Stats{
+		HP:  s.HP - other.HP,
+		ATK: s.ATK - other.ATK,
+		DEF: s.DEF - other.DEF,
+		SPD: s.SPD - other.SPD,
+		AS:  s.AS - other.AS,
+	}
+}
+
 // IsValid checks if stats are valid (all positive)
 func (s Stats) IsValid() bool {
 	return s.HP > 0 && s.ATK >= 0 && s.DEF >= 0 && s.SPD >= 0 && s.AS >= 0
@@ -118,6 +135,25 @@ func (l Level) Value() int {
 	return l.value
 }
 
+// MarshalJSON serializes the level as a plain number
+func (l Level) MarshalJSON() ([]byte, error) {
+	return json.Marshal(l.value)
+}
+
+// UnmarshalJSON restores the level, treating legacy empty objects as level 1
+func (l *Level) UnmarshalJSON(data []byte) error {
+	var value int
+	if err := json.Unmarshal(data, &value); err != nil || value < 1 {
+		l.value = 1
+		return nil
+	}
+	if value > 100 {
+		return fmt.Errorf("level must be between 1 and 100, got %d", value)
+	}
+	l.value = value
+	return nil
+}
+
 // CanLevelUp checks if can level up (not max level)
 func (l Level) CanLevelUp() bool {
 	return l.value < 100
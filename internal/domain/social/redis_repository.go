@@ -0,0 +1,74 @@
+package social
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisRepository implements Repository using Redis sorted sets scored by meeting time
+type RedisRepository struct {
+	client *redis.Client
+}
+
+// NewRedisRepository creates a new Redis-based encounter repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+	}
+}
+
+// RecordEncounter stores that userID met otherID, keeping only the most recent players
+func (r *RedisRepository) RecordEncounter(ctx context.Context, userID, otherID string, at time.Time) error {
+	key := r.recentKey(userID)
+
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZAdd(ctx, key, redis.Z{Score: float64(at.UnixMilli()), Member: otherID})
+		pipe.ZRemRangeByRank(ctx, key, 0, -MaxRecentPlayers-1)
+		pipe.Expire(ctx, key, RecentPlayersTTL)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record encounter: %w", err)
+	}
+
+	return nil
+}
+
+// GetRecent retrieves the players a trainer met most recently, newest first
+func (r *RedisRepository) GetRecent(ctx context.Context, userID string, limit int) ([]Encounter, error) {
+	if limit <= 0 || limit > MaxRecentPlayers {
+		limit = MaxRecentPlayers
+	}
+
+	members, err := r.client.ZRevRangeWithScores(ctx, r.recentKey(userID), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent players: %w", err)
+	}
+
+	encounters := make([]Encounter, 0, len(members))
+	for _, m := range members {
+		otherID, ok := m.Member.(string)
+		if !ok {
+			continue
+		}
+		encounters = append(encounters, Encounter{
+			UserID: otherID,
+			MetAt:  time.UnixMilli(int64(m.Score)),
+		})
+	}
+
+	return encounters, nil
+}
+
+// DeleteRecent forgets the players a trainer met
+func (r *RedisRepository) DeleteRecent(ctx context.Context, userID string) error {
+	return r.client.Del(ctx, r.recentKey(userID)).Err()
+}
+
+// recentKey returns the sorted set of players a trainer recently met
+func (r *RedisRepository) recentKey(userID string) string {
+	return fmt.Sprintf("social:recent:%s", userID)
+}
@@ -0,0 +1,71 @@
+package social
+
+import (
+	"context"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+const (
+	// EncounterMinDuration is how long two trainers must share an interest chunk to have met
+	EncounterMinDuration = 5 * time.Second
+	// MaxRecentPlayers is how many recently met players are kept per trainer
+	MaxRecentPlayers = 20
+	// RecentPlayersTTL is how long a trainer's recent players list lives without new encounters
+	RecentPlayersTTL = 7 * 24 * time.Hour
+)
+
+// Presence is a trainer in an interest chunk and when they entered it
+type Presence struct {
+	UserID string
+	Since  time.Time
+}
+
+// Encounter records that a trainer recently met another trainer
+type Encounter struct {
+	UserID string    `json:"user_id"`
+	MetAt  time.Time `json:"met_at"`
+}
+
+// RecentPlayer is a recently met trainer as the player sees them
+type RecentPlayer struct {
+	Profile *trainer.PublicProfile `json:"profile"` // Nickname identifies the trainer, e.g. for friend requests
+	MetAt   time.Time              `json:"met_at"`
+}
+
+// Repository defines the interface for recent encounter persistence
+type Repository interface {
+	// RecordEncounter stores that userID met otherID, keeping only the most recent players
+	RecordEncounter(ctx context.Context, userID, otherID string, at time.Time) error
+
+	// GetRecent retrieves the players a trainer met most recently, newest first (read-only)
+	GetRecent(ctx context.Context, userID string, limit int) ([]Encounter, error)
+
+	// DeleteRecent forgets the players a trainer met
+	DeleteRecent(ctx context.Context, userID string) error
+}
+
+// MetPairs returns every pair of trainers in the same chunk who have both been there
+// for at least EncounterMinDuration
+func MetPairs(present []Presence, now time.Time) [][2]string {
+	pairs := make([][2]string, 0)
+	for i := range present {
+		for j := i + 1; j < len(present); j++ {
+			a, b := present[i], present[j]
+			if a.UserID == b.UserID {
+				continue
+			}
+
+			// They have shared the chunk since the later of the two arrived
+			shared := a.Since
+			if b.Since.After(shared) {
+				shared = b.Since
+			}
+			if now.Sub(shared) >= EncounterMinDuration {
+				pairs = append(pairs, [2]string{a.UserID, b.UserID})
+			}
+		}
+	}
+	return pairs
+}
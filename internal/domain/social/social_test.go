@@ -0,0 +1,21 @@
+package social
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetPairs(t *testing.T) {
+	now := time.Now()
+	present := []Presence{
+		{UserID: "a", Since: now.Add(-time.Minute)},
+		{UserID: "b", Since: now.Add(-EncounterMinDuration)},
+		{UserID: "c", Since: now.Add(-time.Second)}, // Just arrived
+	}
+
+	assert.Equal(t, [][2]string{{"a", "b"}}, MetPairs(present, now))
+	assert.Len(t, MetPairs(present, now.Add(EncounterMinDuration)), 3, "everyone has met once c stays")
+	assert.Empty(t, MetPairs(present[:1], now))
+}
@@ -0,0 +1,70 @@
+package trainer
+
+import (
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// CaptureRange is how far a trainer can throw a capture net
+const CaptureRange = 5.0
+
+// CaptureNet describes how well a capture tool catches animals
+type CaptureNet struct {
+	Effectiveness float64 `json:"effectiveness"` // Multiplier on the animal's capture chance
+	Guaranteed    bool    `json:"guaranteed"`    // Always catches a capturable animal
+}
+
+// CaptureNet returns the capture stats of an item type, if it is a capture tool
+func (it ItemType) CaptureNet() (CaptureNet, bool) {
+	switch it {
+	case BasicNet:
+		return CaptureNet{Effectiveness: 1.0}, true
+	case AdvancedNet:
+		return CaptureNet{Effectiveness: 1.5}, true
+	case MasterNet:
+		return CaptureNet{Effectiveness: 1.0, Guaranteed: true}, true
+	default:
+		return CaptureNet{}, false
+	}
+}
+
+// InCaptureRange checks if the trainer, where its movement has taken it by now, is close
+// enough to throw a net at a position
+func (t *Trainer) InCaptureRange(target shared.Position) bool {
+	return t.Movement.CalculateCurrentPosition().WithinRange(target, CaptureRange)
+}
+
+// UseCaptureNet removes one capture net from the inventory to throw at a target position and
+// returns it with its stats. Targets out of capture range keep the net in the inventory.
+func (t *Trainer) UseCaptureNet(itemID ItemID, target shared.Position) (*Item, CaptureNet, error) {
+	if !t.InCaptureRange(target) {
+		return nil, CaptureNet{}, shared.NewDomainError(shared.ErrCodeOutOfRange, "Animal is too far away to capture")
+	}
+
+	item, exists := t.Inventory.GetItem(itemID)
+	if !exists {
+		return nil, CaptureNet{}, shared.NewDomainError(shared.ErrCodeItemNotFound, "Item not found in inventory")
+	}
+
+	net, ok := item.Type.CaptureNet()
+	if !ok {
+		return nil, CaptureNet{}, shared.NewDomainErrorf(shared.ErrCodeInvalidItemType, "%s is not a capture net", item.Name)
+	}
+
+	used, err := t.Inventory.RemoveQuantity(itemID, 1)
+	if err != nil {
+		return nil, CaptureNet{}, err
+	}
+	t.UpdatedAt = shared.NewTimestamp()
+
+	return used, net, nil
+}
+
+// CaptureResult summarizes a capture attempt
+type CaptureResult struct {
+	AnimalID  string  `json:"animal_id"`
+	Item      *Item   `json:"item"`   // Net used up by the attempt
+	Chance    float64 `json:"chance"` // Chance the attempt had to succeed
+	Success   bool    `json:"success"`
+	Placement string  `json:"placement,omitempty"` // "party" or "storage" when captured
+	RollID    string  `json:"roll_id,omitempty"`
+}
@@ -0,0 +1,91 @@
+package trainer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// MovementCheckpoint is where a trainer was when its last accepted movement command was
+// applied. Checkpoints are shared by every server instance, so the movement rules hold
+// whichever instance a command reaches.
+type MovementCheckpoint struct {
+	At       time.Time       `json:"at"`
+	Position shared.Position `json:"position"`
+}
+
+// CheckpointRepository stores movement checkpoints
+type CheckpointRepository interface {
+	// FindOneAndUpsert applies callback to the trainer's checkpoint, nil when it has none, and
+	// stores the result atomically for ttl; a nil result leaves the checkpoint unchanged
+	FindOneAndUpsert(ctx context.Context, id UserID, ttl time.Duration, callback func(*MovementCheckpoint) (*MovementCheckpoint, error)) error
+
+	// Delete removes a trainer's checkpoint
+	Delete(ctx context.Context, id UserID) error
+}
+
+// RedisCheckpointRepository implements CheckpointRepository with one JSON string per trainer
+type RedisCheckpointRepository struct {
+	client *redis.Client
+}
+
+// NewRedisCheckpointRepository creates a new Redis movement checkpoint repository
+func NewRedisCheckpointRepository(client *redis.Client) CheckpointRepository {
+	return &RedisCheckpointRepository{client: client}
+}
+
+// checkpointKey is kept next to the trainer's position snapshot
+func checkpointKey(id UserID) string {
+	return positionKey(id) + ":checkpoint"
+}
+
+// FindOneAndUpsert reads the checkpoint in a WATCH transaction, so two commands of a trainer
+// reaching different instances at once can't both pass; the later one fails with
+// redis.TxFailedErr
+func (r *RedisCheckpointRepository) FindOneAndUpsert(ctx context.Context, id UserID, ttl time.Duration, callback func(*MovementCheckpoint) (*MovementCheckpoint, error)) error {
+	key := checkpointKey(id)
+
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		var current *MovementCheckpoint
+		data, err := tx.Get(ctx, key).Result()
+		switch {
+		case err == redis.Nil:
+		case err != nil:
+			return fmt.Errorf("failed to get movement checkpoint: %w", err)
+		default:
+			current = &MovementCheckpoint{}
+			if err := json.Unmarshal([]byte(data), current); err != nil {
+				return fmt.Errorf("failed to deserialize movement checkpoint: %w", err)
+			}
+		}
+
+		result, err := callback(current)
+		if err != nil {
+			return err
+		}
+		if result == nil {
+			return nil // No changes
+		}
+
+		serialized, err := json.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to serialize movement checkpoint: %w", err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, serialized, ttl)
+			return nil
+		})
+		return err
+	}, key)
+}
+
+// Delete removes a trainer's checkpoint
+func (r *RedisCheckpointRepository) Delete(ctx context.Context, id UserID) error {
+	return r.client.Del(ctx, checkpointKey(id)).Err()
+}
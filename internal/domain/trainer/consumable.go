@@ -0,0 +1,251 @@
+package trainer
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// DefaultMaxMana is the mana pool of a new trainer
+const DefaultMaxMana = 50
+
+// EffectType represents a kind of status effect
+type EffectType string
+
+const (
+	EffectClarity EffectType = "clarity" // Faster attacks
+)
+
+// StatusEffect represents a temporary stat modifier on a trainer
+type StatusEffect struct {
+	Type      EffectType   `json:"type"`
+	Bonus     shared.Stats `json:"bonus"`
+	Source    ItemType     `json:"source"`
+	AppliedAt time.Time    `json:"applied_at"`
+	ExpiresAt time.Time    `json:"expires_at"`
+}
+
+// IsActive checks if the effect has not expired
+func (e StatusEffect) IsActive(now time.Time) bool {
+	return now.Before(e.ExpiresAt)
+}
+
+// Condition tracks a trainer's current health, mana, active effects and item cooldowns
+type Condition struct {
+	HP        int                    `json:"hp"`
+	Mana      int                    `json:"mana"`
+	MaxMana   int                    `json:"max_mana"`
+	Effects   []StatusEffect         `json:"effects"`
+	Cooldowns map[ItemType]time.Time `json:"cooldowns"` // Item type -> ready at
+}
+
+// NewCondition creates a full-health condition for the given max HP
+func NewCondition(maxHP int) Condition {
+	return Condition{
+		HP:        maxHP,
+		Mana:      DefaultMaxMana,
+		MaxMana:   DefaultMaxMana,
+		Effects:   make([]StatusEffect, 0),
+		Cooldowns: make(map[ItemType]time.Time),
+	}
+}
+
+// UnmarshalJSON restores the trainer, giving trainers stored before conditions existed a full condition
+func (t *Trainer) UnmarshalJSON(data []byte) error {
+	type trainerJSON Trainer
+
+	var raw trainerJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*t = Trainer(raw)
+	if t.Condition.MaxMana == 0 {
+		t.Condition = NewCondition(t.Stats.HP)
+	}
+	if t.Condition.Cooldowns == nil {
+		t.Condition.Cooldowns = make(map[ItemType]time.Time)
+	}
+
+	return nil
+}
+
+// BuffSpec describes a status effect granted by a consumable
+type BuffSpec struct {
+	Type     EffectType
+	Bonus    shared.Stats
+	Duration time.Duration
+}
+
+// ConsumableEffect describes what using one item of a type does
+type ConsumableEffect struct {
+	Heal          int
+	RestoreMana   int
+	Buff          *BuffSpec
+	Cooldown      time.Duration
+	TargetsAnimal bool // Can be used on an owned animal instead of the trainer
+}
+
+// consumableEffects maps item types to their effects when used
+var consumableEffects = map[ItemType]ConsumableEffect{
+	HealthPotion: {
+		Heal:          50,
+		Cooldown:      10 * time.Second,
+		TargetsAnimal: true,
+	},
+	ManaPotion: {
+		RestoreMana: 30,
+		Buff: &BuffSpec{
+			Type:     EffectClarity,
+			Bonus:    shared.NewStats(0, 0, 0, 0, 3),
+			Duration: time.Minute,
+		},
+		Cooldown: 15 * time.Second,
+	},
+}
+
+// ConsumableEffect returns the effect of using an item of this type, if it is consumable
+func (it ItemType) ConsumableEffect() (ConsumableEffect, bool) {
+	effect, ok := consumableEffects[it]
+	return effect, ok
+}
+
+// EffectTarget is anything a consumable can heal
+type EffectTarget interface {
+	Heal(amount int) error
+}
+
+// ManaTarget is an effect target with a mana pool
+type ManaTarget interface {
+	RestoreMana(amount int)
+}
+
+// BuffTarget is an effect target that can carry status effects
+type BuffTarget interface {
+	ApplyStatusEffect(effect StatusEffect)
+}
+
+// EffectOutcome reports what an effect changed on its target
+type EffectOutcome struct {
+	Healed       int           `json:"healed"`
+	ManaRestored int           `json:"mana_restored"`
+	Effect       *StatusEffect `json:"effect,omitempty"`
+}
+
+// DispatchEffect applies each part of a consumable effect the target supports
+func DispatchEffect(effect ConsumableEffect, source ItemType, target EffectTarget, now time.Time) (EffectOutcome, error) {
+	var outcome EffectOutcome
+
+	if effect.Heal > 0 {
+		if err := target.Heal(effect.Heal); err != nil {
+			return outcome, err
+		}
+		outcome.Healed = effect.Heal
+	}
+
+	if mt, ok := target.(ManaTarget); ok && effect.RestoreMana > 0 {
+		mt.RestoreMana(effect.RestoreMana)
+		outcome.ManaRestored = effect.RestoreMana
+	}
+
+	if bt, ok := target.(BuffTarget); ok && effect.Buff != nil {
+		applied := StatusEffect{
+			Type:      effect.Buff.Type,
+			Bonus:     effect.Buff.Bonus,
+			Source:    source,
+			AppliedAt: now,
+			ExpiresAt: now.Add(effect.Buff.Duration),
+		}
+		bt.ApplyStatusEffect(applied)
+		outcome.Effect = &applied
+	}
+
+	return outcome, nil
+}
+
+// Heal restores trainer HP up to the effective max HP
+func (t *Trainer) Heal(amount int) error {
+	if amount < 0 {
+		return shared.NewDomainError(shared.ErrCodeInvalidAmount, "Heal amount cannot be negative")
+	}
+	if t.IsDead() {
+		return shared.NewDomainError(shared.ErrCodeTrainerDead, "Dead trainers cannot be healed")
+	}
+
+	t.Condition.HP = min(t.Condition.HP+amount, t.EffectiveStats(time.Now()).HP)
+	t.UpdatedAt = shared.NewTimestamp()
+	return nil
+}
+
+// RestoreMana restores trainer mana up to the max
+func (t *Trainer) RestoreMana(amount int) {
+	t.Condition.Mana = min(t.Condition.Mana+amount, t.Condition.MaxMana)
+	t.UpdatedAt = shared.NewTimestamp()
+}
+
+// ApplyStatusEffect adds an effect, replacing any active effect of the same type
+func (t *Trainer) ApplyStatusEffect(effect StatusEffect) {
+	effects := make([]StatusEffect, 0, len(t.Condition.Effects)+1)
+	for _, existing := range t.Condition.Effects {
+		if existing.Type != effect.Type && existing.IsActive(effect.AppliedAt) {
+			effects = append(effects, existing)
+		}
+	}
+
+	t.Condition.Effects = append(effects, effect)
+	t.UpdatedAt = shared.NewTimestamp()
+}
+
+// EffectiveStats returns the trainer stats including active status effects
+func (t *Trainer) EffectiveStats(now time.Time) shared.Stats {
+	stats := t.Stats
+	for _, effect := range t.Condition.Effects {
+		if effect.IsActive(now) {
+			stats = stats.Add(effect.Bonus)
+		}
+	}
+	return stats
+}
+
+// ConsumeItem takes one item from a stack for use, checking it is consumable and off cooldown.
+// The cooldown starts immediately; the caller applies the returned effect to its target.
+func (t *Trainer) ConsumeItem(itemID ItemID, now time.Time) (*Item, ConsumableEffect, error) {
+	item, exists := t.Inventory.GetItem(itemID)
+	if !exists {
+		return nil, ConsumableEffect{}, shared.NewDomainError(shared.ErrCodeItemNotFound, "Item not found in inventory")
+	}
+
+	effect, ok := item.Type.ConsumableEffect()
+	if !ok {
+		return nil, ConsumableEffect{}, shared.NewDomainErrorf(shared.ErrCodeItemNotConsumable, "%s cannot be used", item.Name)
+	}
+
+	if readyAt, onCooldown := t.Condition.Cooldowns[item.Type]; onCooldown && now.Before(readyAt) {
+		return nil, ConsumableEffect{}, shared.NewDomainErrorf(shared.ErrCodeItemOnCooldown,
+			"%s is on cooldown for %s", item.Name, readyAt.Sub(now).Round(time.Second))
+	}
+
+	used, err := t.Inventory.RemoveQuantity(itemID, 1)
+	if err != nil {
+		return nil, ConsumableEffect{}, err
+	}
+
+	if t.Condition.Cooldowns == nil {
+		t.Condition.Cooldowns = make(map[ItemType]time.Time)
+	}
+	t.Condition.Cooldowns[item.Type] = now.Add(effect.Cooldown)
+	t.UpdatedAt = shared.NewTimestamp()
+
+	return used, effect, nil
+}
+
+// ItemUseResult summarizes a consumable being used
+type ItemUseResult struct {
+	Item       *Item      `json:"item"`
+	TargetType string     `json:"target_type"`
+	TargetID   string     `json:"target_id"`
+	Condition  *Condition `json:"condition,omitempty"` // Trainer condition after use on the trainer
+	EffectOutcome
+	CooldownEnds time.Time `json:"cooldown_ends"`
+}
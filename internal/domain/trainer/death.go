@@ -0,0 +1,88 @@
+package trainer
+
+import (
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// DeathCause represents what brought a trainer's HP down to zero
+type DeathCause string
+
+const (
+	DeathByAnimal DeathCause = "animal"
+	DeathByBullet DeathCause = "bullet"
+)
+
+// String returns string representation
+func (c DeathCause) String() string {
+	return string(c)
+}
+
+// Death records a trainer's death until it respawns
+type Death struct {
+	Cause     DeathCause `json:"cause"`
+	SourceID  string     `json:"source_id,omitempty"` // Animal or player that dealt the final blow
+	MoneyLost int        `json:"money_lost"`
+	DiedAt    time.Time  `json:"died_at"`
+	RespawnAt time.Time  `json:"respawn_at"`
+}
+
+// IsDead checks if the trainer is waiting to respawn
+func (t *Trainer) IsDead() bool {
+	return t.Death != nil
+}
+
+// TakeDamage lowers the trainer's HP by an attack's damage, softened by its defense. Every
+// hit takes at least one HP. The result reports whether the trainer's HP ran out.
+func (t *Trainer) TakeDamage(damage int) (bool, error) {
+	if t.IsDead() {
+		return false, shared.NewDomainError(shared.ErrCodeTrainerDead, "Trainer is dead")
+	}
+	if damage < 0 {
+		return false, shared.NewDomainError(shared.ErrCodeInvalidAmount, "Damage cannot be negative")
+	}
+
+	taken := max(1, damage-t.EffectiveStats(time.Now()).DEF/2)
+	t.Condition.HP = max(0, t.Condition.HP-taken)
+	t.UpdatedAt = shared.NewTimestamp()
+
+	return t.Condition.HP == 0, nil
+}
+
+// Die marks the trainer dead where it stands, taking a money penalty of at most what it
+// carries, and returns the death. The trainer can respawn once respawnDelay has passed.
+func (t *Trainer) Die(cause DeathCause, sourceID string, penalty int, respawnDelay time.Duration, now time.Time) *Death {
+	lost := min(max(penalty, 0), t.Money.Amount())
+	if lost > 0 {
+		t.Money, _ = t.Money.Add(-lost)
+	}
+
+	t.Condition.HP = 0
+	t.Death = &Death{
+		Cause:     cause,
+		SourceID:  sourceID,
+		MoneyLost: lost,
+		DiedAt:    now,
+		RespawnAt: now.Add(respawnDelay),
+	}
+	t.UpdatedAt = shared.NewTimestamp()
+
+	return t.Death
+}
+
+// Respawn brings a dead trainer back at a position, stopped and at full health, once its
+// respawn time has come
+func (t *Trainer) Respawn(position shared.Position, now time.Time) error {
+	if !t.IsDead() {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "Trainer is not dead")
+	}
+	if now.Before(t.Death.RespawnAt) {
+		return shared.NewDomainErrorf(shared.ErrCodeTrainerDead,
+			"Trainer respawns in %s", t.Death.RespawnAt.Sub(now).Round(time.Second))
+	}
+
+	t.Death = nil
+	t.Condition.HP = t.EffectiveStats(now).HP
+	return t.MoveTo(position)
+}
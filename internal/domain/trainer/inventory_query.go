@@ -0,0 +1,334 @@
+package trainer
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// ItemCategory groups item types by purpose
+type ItemCategory string
+
+const (
+	CategoryConsumable ItemCategory = "consumable"
+	CategoryCapture    ItemCategory = "capture"
+	CategoryMaterial   ItemCategory = "material"
+)
+
+// String returns string representation
+func (c ItemCategory) String() string {
+	return string(c)
+}
+
+// IsValid checks if item category is valid
+func (c ItemCategory) IsValid() bool {
+	return c == CategoryConsumable || c == CategoryCapture || c == CategoryMaterial
+}
+
+// Category returns the category of the item type
+func (it ItemType) Category() ItemCategory {
+	switch it {
+	case HealthPotion, ManaPotion, AmmoBox:
+		return CategoryConsumable
+	case BasicNet, AdvancedNet, MasterNet:
+		return CategoryCapture
+	default:
+		return CategoryMaterial
+	}
+}
+
+// SellPrice returns the money received for selling one item of this type
+func (it ItemType) SellPrice() int {
+	switch it {
+	case HealthPotion, ManaPotion:
+		return 10
+	case BasicNet:
+		return 5
+	case AdvancedNet:
+		return 25
+	case MasterNet:
+		return 100
+	case AnimalHide:
+		return 3
+	case RareGem:
+		return 40
+	case MagicCrystal:
+		return 80
+	case AmmoBox:
+		return 10
+	default:
+		return 0
+	}
+}
+
+// InventorySort represents the order of inventory query results
+type InventorySort string
+
+const (
+	SortByType      InventorySort = "type"
+	SortByName      InventorySort = "name"
+	SortByQuantity  InventorySort = "quantity"
+	SortByCreatedAt InventorySort = "created_at"
+)
+
+// IsValid checks if sort order is valid
+func (s InventorySort) IsValid() bool {
+	return s == SortByType || s == SortByName || s == SortByQuantity || s == SortByCreatedAt
+}
+
+// Page size limits for inventory queries
+const (
+	DefaultQueryLimit = 50
+	MaxQueryLimit     = 200
+)
+
+// InventoryQuery filters, sorts and paginates item stacks
+type InventoryQuery struct {
+	Types      []ItemType    `json:"types,omitempty"`
+	Category   ItemCategory  `json:"category,omitempty"`
+	Rarities   []ItemRarity  `json:"rarities,omitempty"`
+	SortBy     InventorySort `json:"sort_by,omitempty"`
+	Descending bool          `json:"descending,omitempty"`
+	Offset     int           `json:"offset,omitempty"`
+	Limit      int           `json:"limit,omitempty"`
+}
+
+// Validate checks the query parameters and fills in defaults
+func (q *InventoryQuery) Validate() error {
+	for _, itemType := range q.Types {
+		if !itemType.IsValid() {
+			return shared.NewDomainErrorf(shared.ErrCodeInvalidItemType, "Invalid item type: %s", itemType)
+		}
+	}
+
+	for _, rarity := range q.Rarities {
+		if !rarity.IsValid() {
+			return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Invalid item rarity: %s", rarity)
+		}
+	}
+
+	if q.Category != "" && !q.Category.IsValid() {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Invalid item category: %s", q.Category)
+	}
+
+	if q.SortBy == "" {
+		q.SortBy = SortByType
+	}
+	if !q.SortBy.IsValid() {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Invalid sort order: %s", q.SortBy)
+	}
+
+	if q.Offset < 0 {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "Offset cannot be negative")
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultQueryLimit
+	}
+	if q.Limit > MaxQueryLimit {
+		q.Limit = MaxQueryLimit
+	}
+
+	return nil
+}
+
+// Matches checks if an item passes the query filters
+func (q *InventoryQuery) Matches(item *Item) bool {
+	if q.Category != "" && item.Type.Category() != q.Category {
+		return false
+	}
+
+	if len(q.Rarities) > 0 && !containsRarity(q.Rarities, item.Rarity) {
+		return false
+	}
+
+	if len(q.Types) == 0 {
+		return true
+	}
+	for _, itemType := range q.Types {
+		if item.Type == itemType {
+			return true
+		}
+	}
+	return false
+}
+
+// containsRarity checks if a rarity is in the list
+func containsRarity(rarities []ItemRarity, rarity ItemRarity) bool {
+	for _, r := range rarities {
+		if r == rarity {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns one page of matching items and the total number of matches
+func (q *InventoryQuery) Apply(items []*Item) ([]*Item, int) {
+	matched := make([]*Item, 0, len(items))
+	for _, item := range items {
+		if q.Matches(item) {
+			matched = append(matched, item)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, j int) bool {
+		a, b := matched[i], matched[j]
+		if q.Descending {
+			a, b = b, a
+		}
+		return q.less(a, b)
+	})
+
+	total := len(matched)
+	if q.Offset >= total {
+		return []*Item{}, total
+	}
+
+	end := min(q.Offset+q.Limit, total)
+	return matched[q.Offset:end], total
+}
+
+// less orders two items by the query sort key, falling back to ID for stable pages
+func (q *InventoryQuery) less(a, b *Item) bool {
+	switch q.SortBy {
+	case SortByName:
+		if c := strings.Compare(a.Name, b.Name); c != 0 {
+			return c < 0
+		}
+	case SortByQuantity:
+		if a.Quantity != b.Quantity {
+			return a.Quantity < b.Quantity
+		}
+	case SortByCreatedAt:
+		if !a.CreatedAt.Value().Equal(b.CreatedAt.Value()) {
+			return a.CreatedAt.Value().Before(b.CreatedAt.Value())
+		}
+	default:
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+	}
+	return a.ID < b.ID
+}
+
+// SellItems removes the given item stacks and pays their sell price, all or nothing
+func (t *Trainer) SellItems(itemIDs []ItemID) ([]*Item, int, error) {
+	sold := make([]*Item, 0, len(itemIDs))
+	seen := make(map[ItemID]bool, len(itemIDs))
+	earned := 0
+
+	for _, itemID := range itemIDs {
+		if seen[itemID] {
+			continue
+		}
+		seen[itemID] = true
+
+		item, exists := t.Inventory.GetItem(itemID)
+		if !exists {
+			return nil, 0, shared.NewDomainError(shared.ErrCodeItemNotFound, "Item not found in inventory")
+		}
+		sold = append(sold, item)
+		earned += item.Type.SellPrice() * item.Quantity
+	}
+
+	money, err := t.Money.Add(earned)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, item := range sold {
+		delete(t.Inventory.Items, item.ID.String())
+	}
+	t.Money = money
+	t.UpdatedAt = shared.NewTimestamp()
+
+	return sold, earned, nil
+}
+
+// ItemDrop selects items to drop: part of a stack, or all of it when Quantity is zero
+type ItemDrop struct {
+	ItemID   ItemID `json:"item_id"`
+	Quantity int    `json:"quantity,omitempty"`
+}
+
+// DropItems discards the selected items, all or nothing. Bound items stay with their owner and
+// can't be dropped.
+func (t *Trainer) DropItems(drops []ItemDrop) ([]*Item, error) {
+	seen := make(map[ItemID]bool, len(drops))
+	for _, drop := range drops {
+		if seen[drop.ItemID] {
+			return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Item %s is selected more than once", drop.ItemID)
+		}
+		seen[drop.ItemID] = true
+
+		item, exists := t.Inventory.GetItem(drop.ItemID)
+		if !exists {
+			return nil, shared.NewDomainError(shared.ErrCodeItemNotFound, "Item not found in inventory")
+		}
+		if err := item.CheckTransferable(); err != nil {
+			return nil, err
+		}
+		if drop.Quantity < 0 {
+			return nil, shared.NewDomainError(shared.ErrCodeInvalidAmount, "Quantity must be positive")
+		}
+		if drop.Quantity > item.Quantity {
+			return nil, shared.NewDomainErrorf(shared.ErrCodeInsufficientItems, "Not enough %s in stack", item.Type)
+		}
+	}
+
+	dropped := make([]*Item, 0, len(drops))
+	for _, drop := range drops {
+		var item *Item
+		var err error
+		if drop.Quantity == 0 {
+			item, err = t.Inventory.RemoveItem(drop.ItemID)
+		} else {
+			item, err = t.Inventory.RemoveQuantity(drop.ItemID, drop.Quantity)
+		}
+		if err != nil {
+			return nil, err
+		}
+		dropped = append(dropped, item)
+	}
+	t.UpdatedAt = shared.NewTimestamp()
+
+	return dropped, nil
+}
+
+// InventoryPage is one page of an inventory query
+type InventoryPage struct {
+	Items  []*Item `json:"items"`
+	Total  int     `json:"total"`
+	Offset int     `json:"offset"`
+	Limit  int     `json:"limit"`
+}
+
+// BulkResult summarizes the changes made by a bulk inventory action
+type BulkResult struct {
+	Action         string  `json:"action"`
+	Items          []*Item `json:"items"`
+	StacksAffected int     `json:"stacks_affected"`
+	Quantity       int     `json:"quantity"`
+	MoneyEarned    int     `json:"money_earned"`
+	Money          int     `json:"money"`
+	UsedSlots      int     `json:"used_slots"`
+	VaultUsedSlots int     `json:"vault_used_slots,omitempty"`
+}
+
+// NewBulkResult builds a summary for the given affected items
+func NewBulkResult(action string, t *Trainer, items []*Item) *BulkResult {
+	quantity := 0
+	for _, item := range items {
+		quantity += item.Quantity
+	}
+
+	return &BulkResult{
+		Action:         action,
+		Items:          items,
+		StacksAffected: len(items),
+		Quantity:       quantity,
+		Money:          t.Money.Amount(),
+		UsedSlots:      t.Inventory.GetUsedSlots(),
+	}
+}
@@ -0,0 +1,179 @@
+package trainer
+
+import (
+	"encoding/json"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// ItemRarity represents item rarity
+type ItemRarity string
+
+const (
+	RarityCommon    ItemRarity = "common"
+	RarityUncommon  ItemRarity = "uncommon"
+	RarityRare      ItemRarity = "rare"
+	RarityEpic      ItemRarity = "epic"
+	RarityLegendary ItemRarity = "legendary"
+)
+
+// String returns string representation
+func (r ItemRarity) String() string {
+	return string(r)
+}
+
+// IsValid checks if item rarity is valid
+func (r ItemRarity) IsValid() bool {
+	switch r {
+	case RarityCommon, RarityUncommon, RarityRare, RarityEpic, RarityLegendary:
+		return true
+	default:
+		return false
+	}
+}
+
+// ItemDefinition is the content registry entry describing an item type
+type ItemDefinition struct {
+	Type         ItemType   `json:"type"`
+	Rarity       ItemRarity `json:"rarity"`
+	Description  string     `json:"description"`
+	Icon         string     `json:"icon"`       // Client asset path
+	ContentID    string     `json:"content_id"` // Content registry reference
+	BindOnPickup bool       `json:"bind_on_pickup"`
+	BindOnEquip  bool       `json:"bind_on_equip"`
+}
+
+// itemDefinitions is the built-in content registry for items
+var itemDefinitions = map[ItemType]ItemDefinition{
+	HealthPotion: {
+		Rarity:      RarityCommon,
+		Description: "Restores a portion of health.",
+	},
+	ManaPotion: {
+		Rarity:      RarityCommon,
+		Description: "Restores a portion of mana.",
+	},
+	BasicNet: {
+		Rarity:      RarityCommon,
+		Description: "A simple net for capturing weakened animals.",
+	},
+	AdvancedNet: {
+		Rarity:      RarityUncommon,
+		Description: "A sturdier net with a better capture rate.",
+	},
+	MasterNet: {
+		Rarity:       RarityLegendary,
+		Description:  "A net that never fails. Binds to its owner when picked up.",
+		BindOnPickup: true,
+	},
+	AnimalHide: {
+		Rarity:      RarityCommon,
+		Description: "Tanned hide used in crafting.",
+	},
+	RareGem: {
+		Rarity:      RarityRare,
+		Description: "A polished gem prized by crafters.",
+	},
+	MagicCrystal: {
+		Rarity:       RarityEpic,
+		Description:  "A crystal humming with energy. Binds to its owner when picked up.",
+		BindOnPickup: true,
+	},
+	AmmoBox: {
+		Rarity:      RarityCommon,
+		Description: "A box of rounds, loaded into the reserve when reloading.",
+	},
+}
+
+// Definition returns the content registry entry for the item type
+func (it ItemType) Definition() ItemDefinition {
+	def, exists := itemDefinitions[it]
+	if !exists {
+		def = ItemDefinition{Rarity: RarityCommon}
+	}
+
+	def.Type = it
+	def.Icon = "items/" + it.String() + ".png"
+	def.ContentID = "item." + it.String()
+	return def
+}
+
+// ItemDefinitions returns every registered item definition
+func ItemDefinitions() []ItemDefinition {
+	defs := make([]ItemDefinition, 0, len(itemDefinitions))
+	for _, it := range []ItemType{
+		HealthPotion, ManaPotion, BasicNet, AdvancedNet, MasterNet,
+		AnimalHide, RareGem, MagicCrystal, AmmoBox,
+	} {
+		defs = append(defs, it.Definition())
+	}
+	return defs
+}
+
+// UnmarshalJSON restores an item and refreshes its metadata from the content registry,
+// so items stored before metadata existed, or before content changed, stay current
+func (item *Item) UnmarshalJSON(data []byte) error {
+	type itemJSON Item
+
+	var raw itemJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*item = Item(raw)
+	item.applyDefinition()
+	return nil
+}
+
+// applyDefinition copies the registry metadata onto the item
+func (item *Item) applyDefinition() {
+	def := item.Type.Definition()
+
+	item.Rarity = def.Rarity
+	item.Description = def.Description
+	item.Icon = def.Icon
+	item.ContentID = def.ContentID
+	item.BindOnPickup = def.BindOnPickup
+	item.BindOnEquip = def.BindOnEquip
+}
+
+// Bind binds the item to its current owner
+func (item *Item) Bind() {
+	item.Bound = true
+}
+
+// CheckTransferable returns an error if the item cannot leave its owner: be given to another
+// player, stored in the vault the user's characters share, or dropped
+func (item *Item) CheckTransferable() error {
+	if item.Bound {
+		return shared.NewDomainErrorf(shared.ErrCodeItemBound, "%s is bound and cannot be transferred", item.Name)
+	}
+	return nil
+}
+
+// TakeTransferable removes the given stacks for a transfer out of the carried inventory, all or
+// nothing. Bound items are rejected; gifts and vault deposits move items through here.
+func (inv *Inventory) TakeTransferable(itemIDs []ItemID) ([]*Item, error) {
+	items := make([]*Item, 0, len(itemIDs))
+	seen := make(map[ItemID]bool, len(itemIDs))
+	for _, itemID := range itemIDs {
+		if seen[itemID] {
+			continue
+		}
+		seen[itemID] = true
+
+		item, exists := inv.GetItem(itemID)
+		if !exists {
+			return nil, shared.NewDomainError(shared.ErrCodeItemNotFound, "Item not found in inventory")
+		}
+		if err := item.CheckTransferable(); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+
+	for _, item := range items {
+		delete(inv.Items, item.ID.String())
+	}
+	return items, nil
+}
@@ -0,0 +1,219 @@
+package trainer
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// ListSort is an order trainers can be listed in
+type ListSort string
+
+const (
+	ListByNickname ListSort = "nickname" // Nickname, case-insensitive
+	ListByLevel    ListSort = "level"
+)
+
+const (
+	// DefaultListLimit is the page size when none is requested
+	DefaultListLimit = 50
+	// MaxListLimit caps the page size
+	MaxListLimit = 200
+)
+
+// ListQuery asks for one page of trainers. Pages continue after Cursor, which is opaque and
+// taken from the previous page.
+type ListQuery struct {
+	Sort       ListSort
+	Descending bool
+	Cursor     string
+	Limit      int
+
+	after string // Index member the cursor points at
+}
+
+// ListPage is one page of listed trainers. NextCursor is empty on the last page.
+type ListPage struct {
+	Trainers   []*Trainer
+	NextCursor string
+	Total      int
+}
+
+// NewListQuery validates a list request. An empty sort lists by nickname; a zero limit gets
+// the default page size.
+func NewListQuery(sort, order, cursor string, limit int) (ListQuery, error) {
+	query := ListQuery{Sort: ListSort(sort), Cursor: cursor, Limit: limit}
+
+	switch query.Sort {
+	case "":
+		query.Sort = ListByNickname
+	case ListByNickname, ListByLevel:
+	default:
+		return ListQuery{}, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "invalid sort: %s (must be 'nickname' or 'level')", sort)
+	}
+
+	switch order {
+	case "", "asc":
+	case "desc":
+		query.Descending = true
+	default:
+		return ListQuery{}, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "invalid order: %s (must be 'asc' or 'desc')", order)
+	}
+
+	if query.Limit == 0 {
+		query.Limit = DefaultListLimit
+	}
+	if query.Limit < 0 || query.Limit > MaxListLimit {
+		return ListQuery{}, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "invalid limit: %d (must be 1 to %d)", limit, MaxListLimit)
+	}
+
+	if cursor != "" {
+		after, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || !strings.Contains(string(after), listMemberSeparator) {
+			return ListQuery{}, shared.NewDomainError(shared.ErrCodeInvalidInput, "invalid cursor")
+		}
+		query.after = string(after)
+	}
+
+	return query, nil
+}
+
+// listMemberSeparator ends the sort value of a list index member; the trainer ID follows it
+const listMemberSeparator = "\x00"
+
+// listIndexKey is the sorted set listing trainers in an order. All members score 0, so they
+// sort by the member, which starts with the sort value and ends with the ID to break ties.
+func listIndexKey(sort ListSort) string {
+	return fmt.Sprintf("idx:trainer:list:%s", sort)
+}
+
+// listMembers returns a trainer's members in each list index
+func listMembers(t *Trainer) map[ListSort]string {
+	return map[ListSort]string{
+		ListByNickname: strings.ToLower(t.Nickname) + listMemberSeparator + t.ID.String(),
+		ListByLevel:    fmt.Sprintf("%06d", t.Level.Value()) + listMemberSeparator + t.ID.String(),
+	}
+}
+
+// updateListIndex moves a trainer's list index members from the ones it had before, which is
+// nil for a new trainer, to the ones it has after, which is nil once it is deleted
+func updateListIndex(ctx context.Context, pipe redis.Pipeliner, id string, before, after *Trainer) {
+	var old, current map[ListSort]string
+	if before != nil {
+		old = listMembers(before)
+	}
+	if after != nil {
+		current = listMembers(after)
+	}
+
+	for sort, member := range old {
+		if member != current[sort] {
+			pipe.ZRem(ctx, listIndexKey(sort), member)
+		}
+	}
+	for sort, member := range current {
+		pipe.ZAdd(ctx, listIndexKey(sort), redis.Z{Member: member})
+	}
+}
+
+// List returns one page of trainers from the list index, without scanning the keyspace
+func (r *RedisRepository) List(ctx context.Context, query ListQuery) (*ListPage, error) {
+	if query.Sort == "" || query.Limit <= 0 {
+		return nil, fmt.Errorf("list query must be created with NewListQuery")
+	}
+
+	key := listIndexKey(query.Sort)
+	// Fetch one more member than needed to tell whether another page follows
+	by := &redis.ZRangeBy{Min: "-", Max: "+", Count: int64(query.Limit + 1)}
+	if query.after != "" {
+		if query.Descending {
+			by.Max = "(" + query.after
+		} else {
+			by.Min = "(" + query.after
+		}
+	}
+
+	var members *redis.StringSliceCmd
+	var total *redis.IntCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		if query.Descending {
+			members = pipe.ZRevRangeByLex(ctx, key, by)
+		} else {
+			members = pipe.ZRangeByLex(ctx, key, by)
+		}
+		total = pipe.ZCard(ctx, key)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read trainer list index: %w", err)
+	}
+
+	page := &ListPage{Total: int(total.Val())}
+	found := members.Val()
+	if len(found) > query.Limit {
+		found = found[:query.Limit]
+		page.NextCursor = base64.RawURLEncoding.EncodeToString([]byte(found[len(found)-1]))
+	}
+	if len(found) == 0 {
+		return page, nil
+	}
+
+	keys := make([]string, len(found))
+	for i, member := range found {
+		keys[i] = fmt.Sprintf("trainer:%s", member[strings.LastIndex(member, listMemberSeparator)+1:])
+	}
+
+	docs, err := r.client.JSONMGet(ctx, "$", keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get listed trainers: %w", err)
+	}
+
+	for _, doc := range docs {
+		data, ok := doc.(string)
+		if !ok {
+			continue // Deleted since the index was read
+		}
+
+		var jsonArray []json.RawMessage
+		if err := json.Unmarshal([]byte(data), &jsonArray); err != nil || len(jsonArray) == 0 {
+			continue
+		}
+
+		t := &Trainer{}
+		if err := json.Unmarshal(jsonArray[0], t); err != nil {
+			return nil, fmt.Errorf("failed to deserialize trainer: %w", err)
+		}
+		page.Trainers = append(page.Trainers, t)
+	}
+
+	return page, nil
+}
+
+// RebuildListIndex adds every stored trainer to the list indexes. Trainers written since the
+// indexes were introduced are already in them; run it once for those stored before.
+func RebuildListIndex(ctx context.Context, client *redis.Client) (int, error) {
+	r := &RedisRepository{client: client}
+
+	trainers, err := r.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	_, err = client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, t := range trainers {
+			updateListIndex(ctx, pipe, t.ID.String(), nil, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to rebuild trainer list index: %w", err)
+	}
+
+	return len(trainers), nil
+}
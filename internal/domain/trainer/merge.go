@@ -0,0 +1,138 @@
+package trainer
+
+import (
+	"fmt"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// MergeConflictKind names something of an absorbed trainer that doesn't carry over as is when
+// two users are merged
+type MergeConflictKind string
+
+const (
+	ConflictProgress      MergeConflictKind = "progress"       // Level and experience are not added up
+	ConflictNickname      MergeConflictKind = "nickname"       // The absorbed trainer's nickname is released
+	ConflictInventoryFull MergeConflictKind = "inventory_full" // Items that don't fit go to the vault
+	ConflictVaultFull     MergeConflictKind = "vault_full"     // Items that don't fit in the vault either are lost
+	ConflictPartyFull     MergeConflictKind = "party_full"     // Party members that don't fit go to storage
+	ConflictCharacters    MergeConflictKind = "characters"     // Additional characters are deleted
+)
+
+// MergeConflict describes one thing the player gives up or that moves elsewhere in a merge
+type MergeConflict struct {
+	Kind   MergeConflictKind `json:"kind"`
+	Detail string            `json:"detail"`
+}
+
+// MergeResult reports how an absorbed trainer was folded into the kept one
+type MergeResult struct {
+	Money     int         // Money added to the kept trainer
+	Items     []*Item     // Item stacks added to the inventory
+	Overflow  []*Item     // Item stacks that didn't fit in the inventory
+	Joined    []shared.ID // Animals that joined the party
+	Benched   []shared.ID // Party members that didn't fit in the party and go to storage
+	Conflicts []MergeConflict
+}
+
+// KeepOnMerge picks which of two trainers is kept when their users merge: the one with the
+// higher level, then the one with more experience, then the older one. Either may be nil for
+// a user who never created a trainer.
+func KeepOnMerge(a, b *Trainer) (kept, absorbed *Trainer) {
+	switch {
+	case b == nil:
+		return a, b
+	case a == nil:
+		return b, a
+	case a.Level.Value() != b.Level.Value():
+		if a.Level.Value() > b.Level.Value() {
+			return a, b
+		}
+		return b, a
+	case a.Experience.Total() != b.Experience.Total():
+		if a.Experience.Total() > b.Experience.Total() {
+			return a, b
+		}
+		return b, a
+	case b.CreatedAt.IsAfter(a.CreatedAt):
+		return a, b
+	default:
+		return b, a
+	}
+}
+
+// Absorb folds another trainer's money, inventory and party into this one. Item stacks and
+// party members that don't fit are left for the caller to store elsewhere, as reported in
+// the result's Overflow and Benched.
+func (t *Trainer) Absorb(other *Trainer) (*MergeResult, error) {
+	result := &MergeResult{}
+
+	if amount := other.Money.Amount(); amount > 0 {
+		if err := t.EarnMoney(amount); err != nil {
+			return nil, err
+		}
+		result.Money = amount
+	}
+
+	for _, item := range sortedItems(other.Inventory.Items) {
+		if !t.Inventory.CanAdd(item) {
+			result.Overflow = append(result.Overflow, item)
+			continue
+		}
+		if err := t.Inventory.AddItem(item); err != nil {
+			return nil, err
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	for _, animalID := range other.Party.GetAnimals() {
+		if t.Party.IsFull() {
+			result.Benched = append(result.Benched, animalID)
+			continue
+		}
+		if err := t.Party.AddAnimal(animalID); err != nil {
+			return nil, err
+		}
+		result.Joined = append(result.Joined, animalID)
+	}
+
+	result.Conflicts = append(result.Conflicts, MergeConflict{
+		Kind:   ConflictProgress,
+		Detail: fmt.Sprintf("%s keeps level %d; the level %d of %s is not added", t.Nickname, t.Level.Value(), other.Level.Value(), other.Nickname),
+	})
+	if other.Nickname != t.Nickname {
+		result.Conflicts = append(result.Conflicts, MergeConflict{
+			Kind:   ConflictNickname,
+			Detail: fmt.Sprintf("The nickname %s is released", other.Nickname),
+		})
+	}
+	if len(result.Overflow) > 0 {
+		result.Conflicts = append(result.Conflicts, MergeConflict{
+			Kind:   ConflictInventoryFull,
+			Detail: fmt.Sprintf("%d item stacks don't fit in the inventory and go to the vault", len(result.Overflow)),
+		})
+	}
+	if len(result.Benched) > 0 {
+		result.Conflicts = append(result.Conflicts, MergeConflict{
+			Kind:   ConflictPartyFull,
+			Detail: fmt.Sprintf("%d party animals don't fit in the party and go to storage", len(result.Benched)),
+		})
+	}
+
+	t.UpdatedAt = shared.NewTimestamp()
+
+	return result, nil
+}
+
+// MergePlan describes what merging two users does: which user and trainer are kept, what
+// moves over and what the player gives up
+type MergePlan struct {
+	KeptUserID     UserID
+	AbsorbedUserID UserID
+	Kept           *Trainer     // As it is after the merge; nil when neither user has a trainer
+	Absorbed       *Trainer     // Nil when the absorbed user has no trainer
+	Result         *MergeResult // Nil without two trainers to merge
+	Vaulted        int          // Item stacks moved into the kept user's vault
+	Lost           int          // Item stacks that fit neither in the inventory nor the vault
+	Conflicts      []MergeConflict
+}
@@ -0,0 +1,60 @@
+package trainer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestKeepOnMerge(t *testing.T) {
+	veteran, err := NewTrainer("user-1", "Veteran")
+	require.NoError(t, err)
+	require.NoError(t, veteran.GainExperience(100))
+	rookie, err := NewTrainer("user-2", "Rookie")
+	require.NoError(t, err)
+
+	kept, absorbed := KeepOnMerge(rookie, veteran)
+	assert.Equal(t, veteran, kept, "the higher level trainer is kept")
+	assert.Equal(t, rookie, absorbed)
+
+	kept, absorbed = KeepOnMerge(nil, rookie)
+	assert.Equal(t, rookie, kept)
+	assert.Nil(t, absorbed)
+}
+
+func TestTrainer_Absorb(t *testing.T) {
+	kept, err := NewTrainer("user-1", "Keeper")
+	require.NoError(t, err)
+	kept.Inventory = NewInventory(2)
+	kept.Party = NewAnimalParty(2)
+	require.NoError(t, kept.AddAnimalToParty("animal-1"))
+
+	other, err := NewTrainer("user-2", "Other")
+	require.NoError(t, err)
+	for _, itemType := range []ItemType{HealthPotion, BasicNet, RareGem} {
+		item, err := NewItem(itemType, string(itemType))
+		require.NoError(t, err)
+		require.NoError(t, other.Inventory.AddItem(item))
+	}
+	require.NoError(t, other.AddAnimalToParty("animal-2"))
+	require.NoError(t, other.AddAnimalToParty("animal-3"))
+
+	result, err := kept.Absorb(other)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2000, kept.Money.Amount())
+	assert.Equal(t, 1000, result.Money)
+	assert.Len(t, result.Items, 2)
+	assert.Len(t, result.Overflow, 1, "the third stack doesn't fit in two slots")
+	assert.Equal(t, []shared.ID{"animal-1", "animal-2"}, kept.Party.GetAnimals())
+	assert.Equal(t, []shared.ID{"animal-3"}, result.Benched)
+
+	var kinds []MergeConflictKind
+	for _, conflict := range result.Conflicts {
+		kinds = append(kinds, conflict.Kind)
+	}
+	assert.Equal(t, []MergeConflictKind{ConflictProgress, ConflictNickname, ConflictInventoryFull, ConflictPartyFull}, kinds)
+}
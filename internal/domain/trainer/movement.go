@@ -1,11 +1,26 @@
 package trainer
 
 import (
+	"math"
+	"slices"
 	"time"
 
 	"github.com/danghamo/life/internal/domain/shared"
 )
 
+// movementProbeStep is the distance between the points checked along a movement path.
+// It is kept well below a tile so a path can't skip over a single blocking tile.
+const movementProbeStep = 0.1
+
+// DefaultSpeed is how fast trainers walk, in units per second along each axis; diagonal
+// movement covers √2 times as much ground
+const DefaultSpeed = 5.0
+
+// Terrain reports whether a trainer may stand at a position
+type Terrain interface {
+	IsWalkablePosition(position shared.Position) bool
+}
+
 // MovementDirection represents movement direction
 type MovementDirection struct {
 	X float64 `json:"x"` // Direction vector (-1, 0, 1)
@@ -19,23 +34,38 @@ type MovementState struct {
 	StartTime time.Time         `json:"start_time"` // When movement started
 	StartPos  shared.Position   `json:"start_pos"`  // Position when movement started
 	IsMoving  bool              `json:"is_moving"`  // Whether currently moving
+
+	// Waypoints left to walk through when moving to a destination, the first being where the
+	// current direction leads; movement stops at the last one
+	Waypoints []shared.Position `json:"waypoints,omitempty"`
 }
 
 // NewMovementState creates a new movement state
 func NewMovementState() MovementState {
 	return MovementState{
 		Direction: MovementDirection{X: 0, Y: 0},
-		Speed:     5.0, // Default 2 units per second
+		Speed:     DefaultSpeed,
 		IsMoving:  false,
 	}
 }
 
+// Equal reports whether two movement states are the same
+func (ms MovementState) Equal(other MovementState) bool {
+	return ms.Direction == other.Direction &&
+		ms.Speed == other.Speed &&
+		ms.StartTime.Equal(other.StartTime) &&
+		ms.StartPos == other.StartPos &&
+		ms.IsMoving == other.IsMoving &&
+		slices.Equal(ms.Waypoints, other.Waypoints)
+}
+
 // StartMovement starts movement in given direction
 func (ms *MovementState) StartMovement(direction MovementDirection, currentPos shared.Position) {
 	ms.Direction = direction
 	ms.StartTime = time.Now()
 	ms.StartPos = currentPos
 	ms.IsMoving = true
+	ms.Waypoints = nil
 }
 
 // StopMovement stops current movement
@@ -43,6 +73,65 @@ func (ms *MovementState) StopMovement(currentPos shared.Position) {
 	ms.IsMoving = false
 	ms.StartPos = currentPos
 	ms.Direction = MovementDirection{X: 0, Y: 0}
+	ms.Waypoints = nil
+}
+
+// FollowWaypoints starts moving from currentPos through waypoints in a straight line each,
+// stopping at the last one
+func (ms *MovementState) FollowWaypoints(waypoints []shared.Position, currentPos shared.Position) {
+	ms.StopMovement(currentPos)
+	if len(waypoints) == 0 {
+		return
+	}
+
+	ms.Waypoints = slices.Clone(waypoints)
+	ms.startLeg(currentPos, time.Now())
+}
+
+// AdvanceWaypoints turns towards the next waypoint for each one reached by now, as if the turn
+// was made when the trainer got there. The result reports whether the last one was reached,
+// which stops the movement there.
+func (ms *MovementState) AdvanceWaypoints(now time.Time) bool {
+	for ms.IsMoving && len(ms.Waypoints) > 0 {
+		arrival := ms.StartTime.Add(legDuration(ms.StartPos, ms.Waypoints[0], ms.Speed))
+		if now.Before(arrival) {
+			return false
+		}
+
+		reached := ms.Waypoints[0]
+		if len(ms.Waypoints) == 1 {
+			ms.StopMovement(reached)
+			return true
+		}
+		ms.Waypoints = ms.Waypoints[1:]
+		ms.startLeg(reached, arrival)
+	}
+	return false
+}
+
+// startLeg heads from a position towards the first waypoint, scaling the direction so the
+// longer axis is covered at full speed like a direction command
+func (ms *MovementState) startLeg(from shared.Position, at time.Time) {
+	target := ms.Waypoints[0]
+	dx, dy := target.X-from.X, target.Y-from.Y
+	direction := MovementDirection{}
+	if longest := math.Max(math.Abs(dx), math.Abs(dy)); longest > 0 {
+		direction = MovementDirection{X: dx / longest, Y: dy / longest}
+	}
+
+	ms.Direction = direction
+	ms.StartTime = at
+	ms.StartPos = from
+	ms.IsMoving = true
+}
+
+// legDuration returns how long walking straight between two positions takes at speed
+func legDuration(from, to shared.Position, speed float64) time.Duration {
+	if speed <= 0 {
+		return 0
+	}
+	longest := math.Max(math.Abs(to.X-from.X), math.Abs(to.Y-from.Y))
+	return time.Duration(longest / speed * float64(time.Second))
 }
 
 // CalculateCurrentPosition calculates current position based on time elapsed
@@ -59,3 +148,49 @@ func (ms *MovementState) CalculateCurrentPosition() shared.Position {
 
 	return shared.Position{X: newX, Y: newY}
 }
+
+// CalculateWalkablePosition calculates current position like CalculateCurrentPosition, but
+// stops at the last walkable point of the path. The second result reports whether the path
+// was blocked before reaching the unclamped position.
+func (ms *MovementState) CalculateWalkablePosition(terrain Terrain) (shared.Position, bool) {
+	target := ms.CalculateCurrentPosition()
+	if !ms.IsMoving {
+		return target, false
+	}
+
+	distance := math.Hypot(target.X-ms.StartPos.X, target.Y-ms.StartPos.Y)
+	if distance == 0 {
+		return target, false
+	}
+
+	stepX := (target.X - ms.StartPos.X) / distance * movementProbeStep
+	stepY := (target.Y - ms.StartPos.Y) / distance * movementProbeStep
+
+	last := ms.StartPos
+	for travelled := movementProbeStep; travelled < distance; travelled += movementProbeStep {
+		next := shared.Position{X: last.X + stepX, Y: last.Y + stepY}
+		if !terrain.IsWalkablePosition(next) {
+			return last, true
+		}
+		last = next
+	}
+
+	if !terrain.IsWalkablePosition(target) {
+		return last, true
+	}
+
+	return target, false
+}
+
+// NextStep returns the first point a movement in the given direction would reach
+func NextStep(from shared.Position, direction MovementDirection) shared.Position {
+	length := math.Hypot(direction.X, direction.Y)
+	if length == 0 {
+		return from
+	}
+
+	return shared.Position{
+		X: from.X + direction.X/length*movementProbeStep,
+		Y: from.Y + direction.Y/length*movementProbeStep,
+	}
+}
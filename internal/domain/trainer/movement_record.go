@@ -0,0 +1,173 @@
+package trainer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// MovementRecord is the part of a trainer the movement simulation needs: where it is, how it
+// moves and how it looks on the map. Simulating and broadcasting trainers from their records
+// keeps the inventory, party and the rest of the trainer out of the 60Hz loop.
+type MovementRecord struct {
+	ID       UserID            `json:"id"`
+	Position shared.Position   `json:"position"`
+	Movement MovementState     `json:"movement"`
+	Color    string            `json:"color"`
+	Showcase []ShowcasedAnimal `json:"showcase,omitempty"` // As shown on the nameplate
+	SavedAt  time.Time         `json:"saved_at"`
+}
+
+// MovementRecord captures the trainer's movement and looks
+func (t *Trainer) MovementRecord() MovementRecord {
+	return MovementRecord{
+		ID:       t.ID,
+		Position: t.Position,
+		Movement: t.Movement,
+		Color:    t.Color,
+		Showcase: t.NameplateShowcase(),
+	}
+}
+
+// UpdatePositionWithin advances the position along the movement like the trainer's
+// UpdatePositionWithin, stopping at blocked terrain; the result reports whether it did
+func (m *MovementRecord) UpdatePositionWithin(terrain Terrain) bool {
+	m.Movement.AdvanceWaypoints(time.Now())
+	position, blocked := m.Movement.CalculateWalkablePosition(terrain)
+	m.Position = position
+
+	if blocked {
+		m.Movement.StopMovement(position)
+	}
+	return blocked
+}
+
+// Arrive stops a movement to a destination whose last waypoint was reached by now, putting
+// the record there; the result reports whether it did
+func (m *MovementRecord) Arrive(now time.Time) bool {
+	if !m.Movement.AdvanceWaypoints(now) {
+		return false
+	}
+	m.Position = m.Movement.StartPos
+	return true
+}
+
+// StopWithin stops the movement at the last walkable point of the path
+func (m *MovementRecord) StopWithin(terrain Terrain) {
+	m.UpdatePositionWithin(terrain)
+	m.Movement.StopMovement(m.Position)
+}
+
+// PositionSnapshot returns the record's position and movement as a snapshot
+func (m MovementRecord) PositionSnapshot() PositionSnapshot {
+	return PositionSnapshot{
+		ID:       m.ID,
+		Position: m.Position,
+		Movement: m.Movement,
+		SavedAt:  m.SavedAt,
+	}
+}
+
+// ApplyPosition replaces the record's position and movement with a snapshot's
+func (m *MovementRecord) ApplyPosition(snapshot PositionSnapshot) {
+	m.Position = snapshot.Position
+	m.Movement = snapshot.Movement
+	m.SavedAt = snapshot.SavedAt
+}
+
+// HashFields encodes the record as the fields of a Redis hash
+func (m MovementRecord) HashFields() (map[string]interface{}, error) {
+	showcase, err := json.Marshal(m.Showcase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize showcase: %w", err)
+	}
+	waypoints, err := json.Marshal(m.Movement.Waypoints)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize waypoints: %w", err)
+	}
+
+	moving := "0"
+	if m.Movement.IsMoving {
+		moving = "1"
+	}
+	return map[string]interface{}{
+		"x":          formatCoordinate(m.Position.X),
+		"y":          formatCoordinate(m.Position.Y),
+		"start_x":    formatCoordinate(m.Movement.StartPos.X),
+		"start_y":    formatCoordinate(m.Movement.StartPos.Y),
+		"dir_x":      formatCoordinate(m.Movement.Direction.X),
+		"dir_y":      formatCoordinate(m.Movement.Direction.Y),
+		"speed":      formatCoordinate(m.Movement.Speed),
+		"started_at": formatTime(m.Movement.StartTime),
+		"moving":     moving,
+		"color":      m.Color,
+		"showcase":   string(showcase),
+		"waypoints":  string(waypoints),
+		"saved_at":   formatTime(m.SavedAt),
+	}, nil
+}
+
+// ParseMovementRecord decodes a record from the fields of a Redis hash written by HashFields
+func ParseMovementRecord(id UserID, fields map[string]string) (MovementRecord, error) {
+	record := MovementRecord{ID: id, Color: fields["color"]}
+
+	floats := map[string]*float64{
+		"x":       &record.Position.X,
+		"y":       &record.Position.Y,
+		"start_x": &record.Movement.StartPos.X,
+		"start_y": &record.Movement.StartPos.Y,
+		"dir_x":   &record.Movement.Direction.X,
+		"dir_y":   &record.Movement.Direction.Y,
+		"speed":   &record.Movement.Speed,
+	}
+	for field, value := range floats {
+		parsed, err := strconv.ParseFloat(fields[field], 64)
+		if err != nil {
+			return MovementRecord{}, fmt.Errorf("invalid movement record field %s: %w", field, err)
+		}
+		*value = parsed
+	}
+
+	times := map[string]*time.Time{
+		"started_at": &record.Movement.StartTime,
+		"saved_at":   &record.SavedAt,
+	}
+	for field, value := range times {
+		nanos, err := strconv.ParseInt(fields[field], 10, 64)
+		if err != nil {
+			return MovementRecord{}, fmt.Errorf("invalid movement record field %s: %w", field, err)
+		}
+		if nanos != 0 {
+			*value = time.Unix(0, nanos)
+		}
+	}
+
+	record.Movement.IsMoving = fields["moving"] == "1"
+	if showcase := fields["showcase"]; showcase != "" {
+		if err := json.Unmarshal([]byte(showcase), &record.Showcase); err != nil {
+			return MovementRecord{}, fmt.Errorf("invalid movement record field showcase: %w", err)
+		}
+	}
+	if waypoints := fields["waypoints"]; waypoints != "" {
+		if err := json.Unmarshal([]byte(waypoints), &record.Movement.Waypoints); err != nil {
+			return MovementRecord{}, fmt.Errorf("invalid movement record field waypoints: %w", err)
+		}
+	}
+	return record, nil
+}
+
+// formatTime formats a time as Unix nanoseconds, the zero time as 0
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// formatCoordinate formats a float with the fewest digits that parse back to it
+func formatCoordinate(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
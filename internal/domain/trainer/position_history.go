@@ -0,0 +1,91 @@
+package trainer
+
+import (
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+const (
+	// PositionHistoryWindow is how far back a trainer's positions are kept, the most a hit
+	// check can be rewound for a lagging shooter
+	PositionHistoryWindow = 500 * time.Millisecond
+
+	// positionHistorySize holds the window at the 60Hz simulation rate with room for the
+	// extra samples movement commands add
+	positionHistorySize = 64
+)
+
+// PositionSample is where a trainer was at a point in time
+type PositionSample struct {
+	At       time.Time
+	Position shared.Position
+}
+
+// PositionHistory is a ring buffer of a trainer's recent positions. Positions between samples
+// are interpolated; after the newest sample the trainer stays where it was, as trainers
+// standing still are not sampled.
+type PositionHistory struct {
+	samples [positionHistorySize]PositionSample
+	next    int // Slot the next sample goes to
+	count   int
+}
+
+// Record adds the trainer's position at a point in time, overwriting the oldest sample when
+// the buffer is full. Samples older than the newest are ignored.
+func (h *PositionHistory) Record(at time.Time, position shared.Position) {
+	if h.count > 0 && at.Before(h.newest().At) {
+		return
+	}
+
+	h.samples[h.next] = PositionSample{At: at, Position: position}
+	h.next = (h.next + 1) % positionHistorySize
+	h.count = min(h.count+1, positionHistorySize)
+}
+
+// At returns where the trainer was at a point in time. Before its first sample the trainer is
+// taken to have stood where it was first sampled. It returns false when nothing was recorded
+// or the time is older than the samples kept once the oldest were overwritten.
+func (h *PositionHistory) At(at time.Time) (shared.Position, bool) {
+	if h.count == 0 {
+		return shared.Position{}, false
+	}
+
+	later := h.newest()
+	if !at.Before(later.At) {
+		return later.Position, true
+	}
+
+	// Walk back from the newest sample to the first one recorded no later than at
+	for i := 1; i < h.count; i++ {
+		earlier := h.sample(i)
+		if earlier.At.After(at) {
+			later = earlier
+			continue
+		}
+
+		span := later.At.Sub(earlier.At)
+		if span <= 0 {
+			return later.Position, true
+		}
+		ratio := float64(at.Sub(earlier.At)) / float64(span)
+		return shared.NewPosition(
+			earlier.Position.X+(later.Position.X-earlier.Position.X)*ratio,
+			earlier.Position.Y+(later.Position.Y-earlier.Position.Y)*ratio,
+		), true
+	}
+	if h.count < positionHistorySize {
+		return later.Position, true
+	}
+	return shared.Position{}, false
+}
+
+// newest returns the most recent sample
+func (h *PositionHistory) newest() PositionSample {
+	return h.sample(0)
+}
+
+// sample returns the sample recorded back samples before the newest
+func (h *PositionHistory) sample(back int) PositionSample {
+	return h.samples[(h.next-1-back+2*positionHistorySize)%positionHistorySize]
+}
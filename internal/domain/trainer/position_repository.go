@@ -0,0 +1,175 @@
+package trainer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// PositionSnapshot is a trainer's position and movement as of SavedAt. Snapshots are stored
+// apart from the trainer so the movement simulation can persist them without contending with
+// transactional updates of inventory or money.
+type PositionSnapshot struct {
+	ID       UserID          `json:"id"`
+	Position shared.Position `json:"position"`
+	Movement MovementState   `json:"movement"`
+	SavedAt  time.Time       `json:"saved_at"`
+}
+
+// PositionSnapshot captures the trainer's current position and movement
+func (t *Trainer) PositionSnapshot() PositionSnapshot {
+	return PositionSnapshot{
+		ID:       t.ID,
+		Position: t.Position,
+		Movement: t.Movement,
+		SavedAt:  time.Now(),
+	}
+}
+
+// ApplyPosition replaces the trainer's position and movement with a snapshot's
+func (t *Trainer) ApplyPosition(snapshot PositionSnapshot) {
+	t.Position = snapshot.Position
+	t.Movement = snapshot.Movement
+}
+
+// PositionRepository stores position snapshots
+type PositionRepository interface {
+	// SaveAll stores snapshots, replacing earlier ones of the same trainers
+	SaveAll(ctx context.Context, snapshots []PositionSnapshot) error
+
+	// GetMany returns the snapshots of the given trainers; trainers without one are left out
+	GetMany(ctx context.Context, ids []UserID) (map[UserID]PositionSnapshot, error)
+
+	// GetByPosition returns the trainers whose snapshot was stored at a position, to the
+	// precision of the trainer position index. Trainers can be listed shortly after they moved
+	// on, so callers check the snapshot.
+	GetByPosition(ctx context.Context, position shared.Position) ([]UserID, error)
+
+	// Delete removes a trainer's snapshot
+	Delete(ctx context.Context, id UserID) error
+}
+
+// RedisPositionRepository implements PositionRepository with one JSON string per trainer
+type RedisPositionRepository struct {
+	client *redis.Client
+}
+
+// NewRedisPositionRepository creates a new Redis position snapshot repository
+func NewRedisPositionRepository(client *redis.Client) PositionRepository {
+	return &RedisPositionRepository{client: client}
+}
+
+// positionKey is kept outside the trainer:* keyspace that GetAll scans
+func positionKey(id UserID) string {
+	return fmt.Sprintf("position:trainer:%s", id.String())
+}
+
+// positionIndexKey lists the trainers whose snapshot is at a position, rounded like the
+// trainer document's position index
+func positionIndexKey(position shared.Position) string {
+	return fmt.Sprintf("idx:position:trainer:%.1f:%.1f", position.X, position.Y)
+}
+
+// SaveAll stores snapshots and moves their trainers in the position index, reading the
+// snapshots they replace first
+func (r *RedisPositionRepository) SaveAll(ctx context.Context, snapshots []PositionSnapshot) error {
+	if len(snapshots) == 0 {
+		return nil
+	}
+
+	ids := make([]UserID, len(snapshots))
+	for i, snapshot := range snapshots {
+		ids[i] = snapshot.ID
+	}
+	previous, err := r.GetMany(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, snapshot := range snapshots {
+			data, err := json.Marshal(snapshot)
+			if err != nil {
+				return fmt.Errorf("failed to serialize position snapshot: %w", err)
+			}
+			pipe.Set(ctx, positionKey(snapshot.ID), data, 0)
+
+			indexKey := positionIndexKey(snapshot.Position)
+			if before, ok := previous[snapshot.ID]; ok && positionIndexKey(before.Position) != indexKey {
+				pipe.SRem(ctx, positionIndexKey(before.Position), snapshot.ID.String())
+			}
+			pipe.SAdd(ctx, indexKey, snapshot.ID.String())
+		}
+		return nil
+	})
+	return err
+}
+
+// GetMany returns stored snapshots by trainer
+func (r *RedisPositionRepository) GetMany(ctx context.Context, ids []UserID) (map[UserID]PositionSnapshot, error) {
+	snapshots := make(map[UserID]PositionSnapshot, len(ids))
+	if len(ids) == 0 {
+		return snapshots, nil
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = positionKey(id)
+	}
+
+	values, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get position snapshots: %w", err)
+	}
+
+	for i, value := range values {
+		data, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		var snapshot PositionSnapshot
+		if err := json.Unmarshal([]byte(data), &snapshot); err != nil {
+			return nil, fmt.Errorf("failed to deserialize position snapshot: %w", err)
+		}
+		snapshots[ids[i]] = snapshot
+	}
+
+	return snapshots, nil
+}
+
+// GetByPosition returns the trainers indexed at a position
+func (r *RedisPositionRepository) GetByPosition(ctx context.Context, position shared.Position) ([]UserID, error) {
+	members, err := r.client.SMembers(ctx, positionIndexKey(position)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get indexed positions: %w", err)
+	}
+
+	ids := make([]UserID, len(members))
+	for i, member := range members {
+		ids[i] = UserID(member)
+	}
+	return ids, nil
+}
+
+// Delete removes a trainer's snapshot and its position index entry
+func (r *RedisPositionRepository) Delete(ctx context.Context, id UserID) error {
+	snapshots, err := r.GetMany(ctx, []UserID{id})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, positionKey(id))
+		if snapshot, ok := snapshots[id]; ok {
+			pipe.SRem(ctx, positionIndexKey(snapshot.Position), id.String())
+		}
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,48 @@
+package trainer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestRedisPositionRepository(t *testing.T) {
+	client := setupTestRedis(t)
+	defer client.Close()
+
+	repo := NewRedisPositionRepository(client)
+	ctx := context.Background()
+	id := UserID("position-test-user")
+	defer repo.Delete(ctx, id)
+
+	older := shared.NewPosition(31, 41)
+	newer := shared.NewPosition(59, 26)
+	now := time.Now()
+	require.NoError(t, repo.SaveAll(ctx, []PositionSnapshot{{ID: id, Position: older, SavedAt: now}}))
+	require.NoError(t, repo.SaveAll(ctx, []PositionSnapshot{{ID: id, Position: newer, SavedAt: now.Add(time.Second)}}))
+
+	snapshots, err := repo.GetMany(ctx, []UserID{id, "position-test-missing"})
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1, "trainers without a snapshot are left out")
+	assert.Equal(t, newer, snapshots[id].Position, "newer snapshots replace older ones")
+
+	ids, err := repo.GetByPosition(ctx, newer)
+	require.NoError(t, err)
+	assert.Contains(t, ids, id)
+	ids, err = repo.GetByPosition(ctx, older)
+	require.NoError(t, err)
+	assert.NotContains(t, ids, id, "the index follows the snapshot")
+
+	require.NoError(t, repo.Delete(ctx, id))
+	snapshots, err = repo.GetMany(ctx, []UserID{id})
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+	ids, err = repo.GetByPosition(ctx, newer)
+	require.NoError(t, err)
+	assert.NotContains(t, ids, id)
+}
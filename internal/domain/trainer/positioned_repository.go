@@ -0,0 +1,284 @@
+package trainer
+
+import (
+	"context"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// PositionedRepository reads and writes trainers through a base repository, with position and
+// movement taken from their snapshots. Everything else keeps the base repository's
+// transactional path.
+type PositionedRepository struct {
+	base      Repository
+	positions PositionRepository
+}
+
+// NewPositionedRepository wraps a trainer repository so trainers carry their snapshot position
+func NewPositionedRepository(base Repository, positions PositionRepository) Repository {
+	return &PositionedRepository{base: base, positions: positions}
+}
+
+// FindOneAndUpsert applies the callback to the trainer with its snapshot position
+func (r *PositionedRepository) FindOneAndUpsert(ctx context.Context, id UserID, callback func(*Trainer) (*Trainer, error)) error {
+	return r.update(ctx, id, callback, r.base.FindOneAndUpsert)
+}
+
+// FindOneAndInsert inserts a new trainer and records its starting position
+func (r *PositionedRepository) FindOneAndInsert(ctx context.Context, id UserID, callback func() (*Trainer, error)) error {
+	var inserted *Trainer
+	err := r.base.FindOneAndInsert(ctx, id, func() (*Trainer, error) {
+		t, err := callback()
+		inserted = t
+		return t, err
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.positions.SaveAll(ctx, []PositionSnapshot{inserted.PositionSnapshot()})
+}
+
+// FindOneAndUpdate applies the callback to the trainer with its snapshot position
+func (r *PositionedRepository) FindOneAndUpdate(ctx context.Context, id UserID, callback func(*Trainer) (*Trainer, error)) error {
+	return r.update(ctx, id, callback, r.base.FindOneAndUpdate)
+}
+
+// update runs a transactional update on the positioned trainer. A callback that moves the
+// trainer also updates its snapshot once the update committed.
+func (r *PositionedRepository) update(ctx context.Context, id UserID, callback func(*Trainer) (*Trainer, error), apply func(context.Context, UserID, func(*Trainer) (*Trainer, error)) error) error {
+	var moved *PositionSnapshot
+	err := apply(ctx, id, func(current *Trainer) (*Trainer, error) {
+		moved = nil
+		if current != nil {
+			if err := r.position(ctx, current); err != nil {
+				return nil, err
+			}
+		}
+
+		var before PositionSnapshot
+		if current != nil {
+			before = current.PositionSnapshot()
+		}
+
+		result, err := callback(current)
+		if err != nil || result == nil {
+			return result, err
+		}
+
+		if after := result.PositionSnapshot(); current == nil || after.Position != before.Position || !after.Movement.Equal(before.Movement) {
+			moved = &after
+		}
+		return result, nil
+	})
+	if err != nil || moved == nil {
+		return err
+	}
+
+	return r.positions.SaveAll(ctx, []PositionSnapshot{*moved})
+}
+
+// GetByID retrieves a trainer with its snapshot position
+func (r *PositionedRepository) GetByID(ctx context.Context, id UserID) (*Trainer, error) {
+	t, err := r.base.GetByID(ctx, id)
+	if err != nil || t == nil {
+		return t, err
+	}
+
+	if err := r.position(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// GetMany retrieves several trainers with their snapshot positions, in two round trips
+func (r *PositionedRepository) GetMany(ctx context.Context, ids []UserID) (map[UserID]*Trainer, error) {
+	trainers, err := r.base.GetMany(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	found := make([]*Trainer, 0, len(trainers))
+	for _, t := range trainers {
+		found = append(found, t)
+	}
+	return trainers, r.positionAll(ctx, found)
+}
+
+// GetByPosition retrieves trainers at a position by their snapshot positions. Candidates come
+// from the snapshot index and, for trainers saved before snapshots existed, the trainer
+// document's index; both can list trainers that have since moved, so each is checked against
+// its snapshot.
+func (r *PositionedRepository) GetByPosition(ctx context.Context, position shared.Position) ([]*Trainer, error) {
+	trainers, err := r.base.GetByPosition(ctx, position)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := r.positions.GetByPosition(ctx, position)
+	if err != nil {
+		return nil, err
+	}
+
+	indexed := make(map[UserID]bool, len(trainers))
+	for _, t := range trainers {
+		indexed[t.ID] = true
+	}
+	var moved []UserID
+	for _, id := range ids {
+		if !indexed[id] {
+			moved = append(moved, id)
+		}
+	}
+	if len(moved) > 0 {
+		found, err := r.base.GetMany(ctx, moved)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range moved {
+			if t, ok := found[id]; ok {
+				trainers = append(trainers, t)
+			}
+		}
+	}
+
+	if err := r.positionAll(ctx, trainers); err != nil {
+		return nil, err
+	}
+
+	at := trainers[:0]
+	for _, t := range trainers {
+		if positionIndexKey(t.Position) == positionIndexKey(position) {
+			at = append(at, t)
+		}
+	}
+	return at, nil
+}
+
+// Delete removes a trainer and its snapshot
+func (r *PositionedRepository) Delete(ctx context.Context, id UserID) error {
+	if err := r.base.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return r.positions.Delete(ctx, id)
+}
+
+// FindByNickname finds a trainer by nickname, with its snapshot position
+func (r *PositionedRepository) FindByNickname(ctx context.Context, nickname string) (*Trainer, error) {
+	t, err := r.base.FindByNickname(ctx, nickname)
+	if err != nil || t == nil {
+		return t, err
+	}
+
+	if err := r.position(ctx, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// GetAll retrieves all trainers with their snapshot positions
+func (r *PositionedRepository) GetAll(ctx context.Context) ([]*Trainer, error) {
+	trainers, err := r.base.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return trainers, r.positionAll(ctx, trainers)
+}
+
+// List retrieves one page of trainers with their snapshot positions
+func (r *PositionedRepository) List(ctx context.Context, query ListQuery) (*ListPage, error) {
+	page, err := r.base.List(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, r.positionAll(ctx, page.Trainers)
+}
+
+// SearchByArea retrieves trainers inside a rectangle by their snapshot positions. Trainers are
+// indexed by the position last saved with them, so one that has since moved into the area
+// without a transactional update isn't found; one that moved out is left out.
+func (r *PositionedRepository) SearchByArea(ctx context.Context, topLeft, bottomRight shared.Position) ([]*Trainer, error) {
+	trainers, err := r.base.SearchByArea(ctx, topLeft, bottomRight)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.positionAll(ctx, trainers); err != nil {
+		return nil, err
+	}
+
+	inside := trainers[:0]
+	for _, t := range trainers {
+		if t.Position.X >= topLeft.X && t.Position.X <= bottomRight.X &&
+			t.Position.Y >= topLeft.Y && t.Position.Y <= bottomRight.Y {
+			inside = append(inside, t)
+		}
+	}
+	return inside, nil
+}
+
+// SearchByLevelRange retrieves trainers in a level range with their snapshot positions
+func (r *PositionedRepository) SearchByLevelRange(ctx context.Context, min, max int) ([]*Trainer, error) {
+	trainers, err := r.base.SearchByLevelRange(ctx, min, max)
+	if err != nil {
+		return nil, err
+	}
+
+	return trainers, r.positionAll(ctx, trainers)
+}
+
+// SearchByNicknamePrefix retrieves trainers by nickname prefix with their snapshot positions
+func (r *PositionedRepository) SearchByNicknamePrefix(ctx context.Context, prefix string) ([]*Trainer, error) {
+	trainers, err := r.base.SearchByNicknamePrefix(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	return trainers, r.positionAll(ctx, trainers)
+}
+
+// SearchNicknames retrieves one page of trainers matching a nickname expression, with their
+// snapshot positions
+func (r *PositionedRepository) SearchNicknames(ctx context.Context, expression string, offset, limit int) (*SearchPage, error) {
+	page, err := r.base.SearchNicknames(ctx, expression, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return page, r.positionAll(ctx, page.Trainers)
+}
+
+// WaitIndexed waits for the base repository's search index
+func (r *PositionedRepository) WaitIndexed(ctx context.Context) error {
+	return r.base.WaitIndexed(ctx)
+}
+
+// position applies a trainer's snapshot; trainers saved before snapshots existed keep theirs
+func (r *PositionedRepository) position(ctx context.Context, t *Trainer) error {
+	return r.positionAll(ctx, []*Trainer{t})
+}
+
+// positionAll applies the snapshots of several trainers in one round trip
+func (r *PositionedRepository) positionAll(ctx context.Context, trainers []*Trainer) error {
+	if len(trainers) == 0 {
+		return nil
+	}
+
+	ids := make([]UserID, len(trainers))
+	for i, t := range trainers {
+		ids[i] = t.ID
+	}
+
+	snapshots, err := r.positions.GetMany(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, t := range trainers {
+		if snapshot, ok := snapshots[t.ID]; ok {
+			t.ApplyPosition(snapshot)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package trainer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// memoryRepository serves trainer documents from a map, indexed by the position saved with
+// them like the Redis repository. Methods the tests don't use panic.
+type memoryRepository struct {
+	Repository
+	trainers map[UserID]*Trainer
+}
+
+func (m *memoryRepository) GetByID(ctx context.Context, id UserID) (*Trainer, error) {
+	t, ok := m.trainers[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *t
+	return &copied, nil
+}
+
+func (m *memoryRepository) GetMany(ctx context.Context, ids []UserID) (map[UserID]*Trainer, error) {
+	found := make(map[UserID]*Trainer)
+	for _, id := range ids {
+		if t, _ := m.GetByID(ctx, id); t != nil {
+			found[id] = t
+		}
+	}
+	return found, nil
+}
+
+func (m *memoryRepository) GetByPosition(ctx context.Context, position shared.Position) ([]*Trainer, error) {
+	var at []*Trainer
+	for id, t := range m.trainers {
+		if positionIndexKey(t.Position) == positionIndexKey(position) {
+			copied, _ := m.GetByID(ctx, id)
+			at = append(at, copied)
+		}
+	}
+	return at, nil
+}
+
+func (m *memoryRepository) FindOneAndUpdate(ctx context.Context, id UserID, callback func(*Trainer) (*Trainer, error)) error {
+	t, err := m.GetByID(ctx, id)
+	if err != nil || t == nil {
+		return shared.ErrNotFound("Trainer")
+	}
+	updated, err := callback(t)
+	if err != nil || updated == nil {
+		return err
+	}
+	m.trainers[id] = updated
+	return nil
+}
+
+// memorySnapshots keeps position snapshots in a map and counts the saves
+type memorySnapshots struct {
+	snapshots map[UserID]PositionSnapshot
+	saves     int
+}
+
+func (m *memorySnapshots) SaveAll(ctx context.Context, snapshots []PositionSnapshot) error {
+	m.saves++
+	for _, snapshot := range snapshots {
+		m.snapshots[snapshot.ID] = snapshot
+	}
+	return nil
+}
+
+func (m *memorySnapshots) GetMany(ctx context.Context, ids []UserID) (map[UserID]PositionSnapshot, error) {
+	found := make(map[UserID]PositionSnapshot)
+	for _, id := range ids {
+		if snapshot, ok := m.snapshots[id]; ok {
+			found[id] = snapshot
+		}
+	}
+	return found, nil
+}
+
+func (m *memorySnapshots) GetByPosition(ctx context.Context, position shared.Position) ([]UserID, error) {
+	var ids []UserID
+	for id, snapshot := range m.snapshots {
+		if positionIndexKey(snapshot.Position) == positionIndexKey(position) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (m *memorySnapshots) Delete(ctx context.Context, id UserID) error {
+	delete(m.snapshots, id)
+	return nil
+}
+
+// newPositionedTest stores trainers at their positions without snapshots
+func newPositionedTest(t *testing.T, positions ...shared.Position) (Repository, *memoryRepository, *memorySnapshots) {
+	t.Helper()
+
+	base := &memoryRepository{trainers: map[UserID]*Trainer{}}
+	for i, position := range positions {
+		tr, err := NewTrainer(UserID(string(rune('a'+i))), "Trainer")
+		require.NoError(t, err)
+		tr.Position = position
+		base.trainers[tr.ID] = tr
+	}
+	snapshots := &memorySnapshots{snapshots: map[UserID]PositionSnapshot{}}
+	return NewPositionedRepository(base, snapshots), base, snapshots
+}
+
+func TestPositionedRepository_GetByID(t *testing.T) {
+	repo, _, snapshots := newPositionedTest(t, shared.NewPosition(1, 1))
+	ctx := context.Background()
+
+	tr, err := repo.GetByID(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, shared.NewPosition(1, 1), tr.Position, "trainers without a snapshot keep their document's position")
+
+	now := time.Now()
+	require.NoError(t, snapshots.SaveAll(ctx, []PositionSnapshot{{ID: "a", Position: shared.NewPosition(2, 2), SavedAt: now}}))
+	require.NoError(t, snapshots.SaveAll(ctx, []PositionSnapshot{{ID: "a", Position: shared.NewPosition(3, 3), SavedAt: now.Add(time.Second)}}))
+
+	tr, err = repo.GetByID(ctx, "a")
+	require.NoError(t, err)
+	assert.Equal(t, shared.NewPosition(3, 3), tr.Position, "the newest snapshot wins over the document")
+
+	tr, err = repo.GetByID(ctx, "missing")
+	assert.NoError(t, err)
+	assert.Nil(t, tr)
+}
+
+func TestPositionedRepository_FindOneAndUpdate(t *testing.T) {
+	repo, _, snapshots := newPositionedTest(t, shared.NewPosition(1, 1))
+	ctx := context.Background()
+	require.NoError(t, snapshots.SaveAll(ctx, []PositionSnapshot{{ID: "a", Position: shared.NewPosition(5, 5), SavedAt: time.Now()}}))
+	snapshots.saves = 0
+
+	err := repo.FindOneAndUpdate(ctx, "a", func(tr *Trainer) (*Trainer, error) {
+		assert.Equal(t, shared.NewPosition(5, 5), tr.Position, "updates see the snapshot position")
+		tr.Nickname = "Renamed"
+		return tr, nil
+	})
+	require.NoError(t, err)
+	assert.Zero(t, snapshots.saves, "updates that don't move the trainer leave the snapshot alone")
+
+	err = repo.FindOneAndUpdate(ctx, "a", func(tr *Trainer) (*Trainer, error) {
+		tr.Position = shared.NewPosition(6, 5)
+		return tr, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, snapshots.saves)
+	assert.Equal(t, shared.NewPosition(6, 5), snapshots.snapshots["a"].Position, "moves are stored as a new snapshot")
+}
+
+func TestPositionedRepository_GetByPosition(t *testing.T) {
+	// a stands where its document says, b moved away from a and c moved onto it since their
+	// documents were saved
+	repo, _, snapshots := newPositionedTest(t, shared.NewPosition(1, 1), shared.NewPosition(1, 1), shared.NewPosition(8, 8))
+	ctx := context.Background()
+	now := time.Now()
+	require.NoError(t, snapshots.SaveAll(ctx, []PositionSnapshot{
+		{ID: "a", Position: shared.NewPosition(1.02, 1), SavedAt: now},
+		{ID: "b", Position: shared.NewPosition(4, 4), SavedAt: now},
+		{ID: "c", Position: shared.NewPosition(1, 1), SavedAt: now},
+	}))
+
+	trainers, err := repo.GetByPosition(ctx, shared.NewPosition(1, 1))
+	require.NoError(t, err)
+	var ids []UserID
+	for _, tr := range trainers {
+		ids = append(ids, tr.ID)
+	}
+	assert.ElementsMatch(t, []UserID{"a", "c"}, ids)
+
+	trainers, err = repo.GetByPosition(ctx, shared.NewPosition(8, 8))
+	require.NoError(t, err)
+	assert.Empty(t, trainers, "trainers that moved off their document's position are left out")
+}
+
+func TestPositionedRepository_GetMany(t *testing.T) {
+	repo, _, snapshots := newPositionedTest(t, shared.NewPosition(1, 1), shared.NewPosition(2, 2))
+	ctx := context.Background()
+	require.NoError(t, snapshots.SaveAll(ctx, []PositionSnapshot{{ID: "b", Position: shared.NewPosition(7, 7), SavedAt: time.Now()}}))
+
+	tests := []struct {
+		name string
+		ids  []UserID
+		want map[UserID]shared.Position
+	}{
+		{name: "no ids", ids: nil, want: map[UserID]shared.Position{}},
+		{name: "document and snapshot positions", ids: []UserID{"a", "b"}, want: map[UserID]shared.Position{"a": shared.NewPosition(1, 1), "b": shared.NewPosition(7, 7)}},
+		{name: "missing trainers left out", ids: []UserID{"b", "missing"}, want: map[UserID]shared.Position{"b": shared.NewPosition(7, 7)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trainers, err := repo.GetMany(ctx, tt.ids)
+			require.NoError(t, err)
+
+			positions := make(map[UserID]shared.Position, len(trainers))
+			for id, tr := range trainers {
+				positions[id] = tr.Position
+			}
+			assert.Equal(t, tt.want, positions)
+		})
+	}
+}
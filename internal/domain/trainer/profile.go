@@ -0,0 +1,162 @@
+package trainer
+
+import (
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// MaxShowcaseAnimals is how many animals a trainer can feature on their public profile
+const MaxShowcaseAnimals = 3
+
+// ProfileField is a part of the public profile that the trainer can hide
+type ProfileField string
+
+const (
+	ProfileFieldLevel   ProfileField = "level"
+	ProfileFieldAnimals ProfileField = "animals" // Showcased animals and animal count
+)
+
+// IsValid checks if profile field is valid
+func (f ProfileField) IsValid() bool {
+	return f == ProfileFieldLevel || f == ProfileFieldAnimals
+}
+
+// ProfileSettings holds the trainer's public profile preferences. Fields are public
+// unless hidden, so trainers stored before profiles existed show everything.
+type ProfileSettings struct {
+	HiddenFields []ProfileField    `json:"hidden_fields"`
+	Showcase     []ShowcasedAnimal `json:"showcase"` // Pinned animals in display order, as they were when pinned
+}
+
+// IsVisible checks if a field is shown to other players
+func (s ProfileSettings) IsVisible(field ProfileField) bool {
+	for _, hidden := range s.HiddenFields {
+		if hidden == field {
+			return false
+		}
+	}
+	return true
+}
+
+// UpdateProfileSettings replaces the profile visibility and showcase. The caller checks
+// that showcased animals belong to the trainer.
+func (t *Trainer) UpdateProfileSettings(hidden []ProfileField, showcase []ShowcasedAnimal) error {
+	settings := ProfileSettings{
+		HiddenFields: make([]ProfileField, 0, len(hidden)),
+		Showcase:     make([]ShowcasedAnimal, 0, len(showcase)),
+	}
+
+	for _, field := range hidden {
+		if !field.IsValid() {
+			return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown profile field: %s", field)
+		}
+		if !settings.IsVisible(field) {
+			continue // Already hidden
+		}
+		settings.HiddenFields = append(settings.HiddenFields, field)
+	}
+
+	for _, pinned := range showcase {
+		if err := settings.pin(pinned); err != nil {
+			return err
+		}
+	}
+
+	t.Profile = settings
+	t.UpdatedAt = shared.NewTimestamp()
+	return nil
+}
+
+// PinAnimal adds an animal to the end of the showcase, or refreshes it if already pinned.
+// The caller checks that the animal belongs to the trainer.
+func (t *Trainer) PinAnimal(pinned ShowcasedAnimal) error {
+	if err := t.Profile.pin(pinned); err != nil {
+		return err
+	}
+	t.UpdatedAt = shared.NewTimestamp()
+	return nil
+}
+
+// UnpinAnimal removes an animal from the showcase, reporting whether it was pinned
+func (t *Trainer) UnpinAnimal(animalID string) bool {
+	for i, pinned := range t.Profile.Showcase {
+		if pinned.ID == animalID {
+			t.Profile.Showcase = append(t.Profile.Showcase[:i], t.Profile.Showcase[i+1:]...)
+			t.UpdatedAt = shared.NewTimestamp()
+			return true
+		}
+	}
+	return false
+}
+
+// NameplateShowcase returns the pinned animals shown above the trainer to nearby players
+func (t *Trainer) NameplateShowcase() []ShowcasedAnimal {
+	if !t.Profile.IsVisible(ProfileFieldAnimals) {
+		return nil
+	}
+	return t.Profile.Showcase
+}
+
+// pin adds or refreshes a showcased animal
+func (s *ProfileSettings) pin(pinned ShowcasedAnimal) error {
+	if pinned.ID == "" {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "Showcased animal ID is required")
+	}
+
+	for i, existing := range s.Showcase {
+		if existing.ID == pinned.ID {
+			s.Showcase[i] = pinned
+			return nil
+		}
+	}
+
+	if len(s.Showcase) >= MaxShowcaseAnimals {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidOperation, "At most %d animals can be showcased", MaxShowcaseAnimals)
+	}
+
+	s.Showcase = append(s.Showcase, pinned)
+	return nil
+}
+
+// ShowcasedAnimal is the public view of an animal on a trainer profile
+type ShowcasedAnimal struct {
+	ID    string `json:"id"`
+	Type  string `json:"type"`
+	Level int    `json:"level"`
+}
+
+// PublicProfile is the privacy-filtered view of a trainer shown to other players
+type PublicProfile struct {
+	Nickname     string            `json:"nickname"`
+	Color        string            `json:"color"`
+	Level        *int              `json:"level,omitempty"`
+	Showcase     []ShowcasedAnimal `json:"showcase,omitempty"`
+	AnimalsOwned *int              `json:"animals_owned,omitempty"`
+}
+
+// PublicProfile builds the profile other players see, leaving out hidden fields.
+// Showcase and animalsOwned are only used when animals are visible.
+func (t *Trainer) PublicProfile(showcase []ShowcasedAnimal, animalsOwned int) *PublicProfile {
+	profile := &PublicProfile{
+		Nickname: t.Nickname,
+		Color:    t.Color,
+	}
+
+	if t.Profile.IsVisible(ProfileFieldLevel) {
+		level := t.Level.Value()
+		profile.Level = &level
+	}
+
+	if t.Profile.IsVisible(ProfileFieldAnimals) {
+		profile.Showcase = showcase
+		profile.AnimalsOwned = &animalsOwned
+	}
+
+	return profile
+}
+
+// PublicNameplate is the public profile without animals, for lists of other players
+func (t *Trainer) PublicNameplate() *PublicProfile {
+	profile := t.PublicProfile(nil, 0)
+	profile.AnimalsOwned = nil
+	return profile
+}
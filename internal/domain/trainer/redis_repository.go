@@ -7,225 +7,90 @@ import (
 
 	"github.com/redis/go-redis/v9"
 
+	"github.com/danghamo/life/internal/domain/search"
 	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/redisx"
 )
 
 // RedisRepository implements Repository using Redis JSON
 type RedisRepository struct {
 	client *redis.Client
+	docs   *redisx.Repository[Trainer]
+	index  *search.RedisIndex
 }
 
 // NewRedisRepository creates a new Redis JSON-based trainer repository
 func NewRedisRepository(client *redis.Client) Repository {
 	return &RedisRepository{
 		client: client,
+		docs: redisx.NewRepository(client, "trainer:", redisx.Options[Trainer]{
+			Indexes:       trainerIndexes(),
+			NotFound:      func() error { return shared.ErrNotFound("trainer") },
+			AlreadyExists: func() error { return shared.ErrAlreadyExists("trainer") },
+		}),
+		index: newSearchIndex(client),
 	}
 }
 
 // FindOneAndUpsert implements IoC pattern with callback for concurrency control
 func (r *RedisRepository) FindOneAndUpsert(ctx context.Context, id UserID, callback func(*Trainer) (*Trainer, error)) error {
-	key := fmt.Sprintf("trainer:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Get current trainer using JSON.GET
-		var current *Trainer
-		jsonData, err := tx.JSONGet(ctx, key, "$").Result()
-		if err == redis.Nil {
-			// Key doesn't exist, current remains nil
-			current = nil
-		} else if err != nil {
-			return err
-		} else if jsonData == "" || jsonData == "null" {
-			// JSON exists but is null
-			current = nil
-		} else {
-			// Key exists, parse JSON array result
-			var jsonArray []json.RawMessage
-			if err := json.Unmarshal([]byte(jsonData), &jsonArray); err != nil {
-				return fmt.Errorf("failed to parse JSON array from Redis: %w", err)
-			}
-			
-			if len(jsonArray) > 0 {
-				current = &Trainer{}
-				if err := json.Unmarshal(jsonArray[0], current); err != nil {
-					return fmt.Errorf("failed to deserialize trainer: %w", err)
-				}
-			}
-		}
-
-		// Execute callback
-		result, err := callback(current)
-		if err != nil {
-			return err
-		}
-
-		if result == nil {
-			return nil // No changes
-		}
-
-		// Serialize and store using JSON.SET
-		jsonBytes, err := json.Marshal(result)
-		if err != nil {
-			return fmt.Errorf("failed to serialize trainer: %w", err)
-		}
-
-		// Execute transaction
-		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.JSONSet(ctx, key, "$", string(jsonBytes))
-
-			// Update indices
-			r.updateTrainerIndices(ctx, pipe, result)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	return r.docs.FindOneAndUpsert(ctx, id.String(), callback)
 }
 
 // FindOneAndInsert implements IoC pattern for insert operations
 func (r *RedisRepository) FindOneAndInsert(ctx context.Context, id UserID, callback func() (*Trainer, error)) error {
-	key := fmt.Sprintf("trainer:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Check if already exists using JSON.GET
-		jsonData, err := tx.JSONGet(ctx, key, "$").Result()
-		if err == redis.Nil {
-			// Key doesn't exist, proceed with insert
-		} else if err != nil {
-			return err
-		} else if jsonData != "" && jsonData != "null" {
-			// Key exists and has valid data, return error
-			return shared.ErrAlreadyExists("trainer")
-		}
-
-		// Execute callback
-		result, err := callback()
-		if err != nil {
-			return err
-		}
-
-		if result == nil {
-			return fmt.Errorf("callback returned nil trainer")
-		}
-
-		// Serialize and store using JSON.SET
-		jsonBytes, err := json.Marshal(result)
-		if err != nil {
-			return fmt.Errorf("failed to serialize trainer: %w", err)
-		}
-
-		// Execute transaction
-		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.JSONSet(ctx, key, "$", string(jsonBytes))
-
-			// Update indices
-			r.updateTrainerIndices(ctx, pipe, result)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	return r.docs.FindOneAndInsert(ctx, id.String(), callback)
 }
 
 // FindOneAndUpdate implements IoC pattern for update operations
 func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, id UserID, callback func(*Trainer) (*Trainer, error)) error {
-	key := fmt.Sprintf("trainer:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Get current trainer using JSON.GET
-		jsonData, err := tx.JSONGet(ctx, key, "$").Result()
-		if err == redis.Nil {
-			return shared.ErrNotFound("trainer")
-		}
-		if err != nil {
-			return err
-		}
-
-		// Check for null response
-		if jsonData == "" || jsonData == "null" {
-			return shared.ErrNotFound("trainer")
-		}
-
-		// Parse JSON array result
-		var jsonArray []json.RawMessage
-		if err := json.Unmarshal([]byte(jsonData), &jsonArray); err != nil {
-			return fmt.Errorf("failed to parse JSON array from Redis: %w", err)
-		}
-		
-		if len(jsonArray) == 0 {
-			return shared.ErrNotFound("trainer")
-		}
-
-		current := &Trainer{}
-		if err := json.Unmarshal(jsonArray[0], current); err != nil {
-			return fmt.Errorf("failed to deserialize trainer: %w", err)
-		}
-
-		// Execute callback
-		updateResult, err := callback(current)
-		if err != nil {
-			return err
-		}
-
-		if updateResult == nil {
-			return nil // No changes
-		}
-
-		// Serialize and store using JSON.SET
-		jsonBytes, err := json.Marshal(updateResult)
-		if err != nil {
-			return fmt.Errorf("failed to serialize trainer: %w", err)
-		}
-
-		// Execute transaction
-		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.JSONSet(ctx, key, "$", string(jsonBytes))
-
-			// Update indices if needed
-			r.updateTrainerIndices(ctx, pipe, updateResult)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	return r.docs.FindOneAndUpdate(ctx, id.String(), callback)
 }
 
 // GetByID retrieves a trainer by UserID using Redis JSON
 func (r *RedisRepository) GetByID(ctx context.Context, id UserID) (*Trainer, error) {
-	key := fmt.Sprintf("trainer:%s", id.String())
+	return r.docs.GetByID(ctx, id.String())
+}
 
-	jsonData, err := r.client.JSONGet(ctx, key, "$").Result()
-	if err == redis.Nil {
-		return nil, nil
-	}
-	if err != nil {
-		return nil, fmt.Errorf("failed to get trainer from Redis: %w", err)
+// GetMany retrieves several trainers with a single JSON.MGET
+func (r *RedisRepository) GetMany(ctx context.Context, ids []UserID) (map[UserID]*Trainer, error) {
+	trainers := make(map[UserID]*Trainer, len(ids))
+	if len(ids) == 0 {
+		return trainers, nil
 	}
 
-	// Check for null response
-	if jsonData == "" || jsonData == "null" {
-		return nil, nil
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = fmt.Sprintf("trainer:%s", id.String())
 	}
 
-	// Parse JSON array result
-	var jsonArray []json.RawMessage
-	if err := json.Unmarshal([]byte(jsonData), &jsonArray); err != nil {
-		return nil, fmt.Errorf("failed to parse JSON array from Redis: %w", err)
-	}
-	
-	if len(jsonArray) == 0 {
-		return nil, nil // Path doesn't exist
+	values, err := r.client.JSONMGet(ctx, "$", keys...).Result()
+	if err != nil && err != redis.Nil {
+		return nil, fmt.Errorf("failed to get trainers from Redis: %w", err)
 	}
 
-	t := &Trainer{}
-	if err := json.Unmarshal(jsonArray[0], t); err != nil {
-		return nil, fmt.Errorf("failed to deserialize trainer: %w", err)
+	for i, value := range values {
+		jsonData, ok := value.(string)
+		if !ok || jsonData == "" || jsonData == "null" {
+			continue // Missing key
+		}
+
+		var jsonArray []json.RawMessage
+		if err := json.Unmarshal([]byte(jsonData), &jsonArray); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON array from Redis: %w", err)
+		}
+		if len(jsonArray) == 0 {
+			continue
+		}
+
+		t := &Trainer{}
+		if err := json.Unmarshal(jsonArray[0], t); err != nil {
+			return nil, fmt.Errorf("failed to deserialize trainer: %w", err)
+		}
+		trainers[ids[i]] = t
 	}
 
-	return t, nil
+	return trainers, nil
 }
 
 // GetByPosition retrieves trainers at a specific position
@@ -253,50 +118,7 @@ func (r *RedisRepository) GetByPosition(ctx context.Context, position shared.Pos
 
 // Delete removes a trainer
 func (r *RedisRepository) Delete(ctx context.Context, id UserID) error {
-	key := fmt.Sprintf("trainer:%s", id.String())
-
-	return r.client.Watch(ctx, func(tx *redis.Tx) error {
-		// Get trainer for index cleanup using JSON.GET
-		jsonData, err := tx.JSONGet(ctx, key, "$").Result()
-		if err == redis.Nil {
-			return shared.ErrNotFound("trainer")
-		}
-		if err != nil {
-			return err
-		}
-
-		// Check for null response
-		if jsonData == "" || jsonData == "null" {
-			return shared.ErrNotFound("trainer")
-		}
-
-		// Parse JSON array result
-		var jsonArray []json.RawMessage
-		if err := json.Unmarshal([]byte(jsonData), &jsonArray); err != nil {
-			return fmt.Errorf("failed to parse JSON array from Redis: %w", err)
-		}
-		
-		if len(jsonArray) == 0 {
-			return shared.ErrNotFound("trainer")
-		}
-
-		t := &Trainer{}
-		if err := json.Unmarshal(jsonArray[0], t); err != nil {
-			return fmt.Errorf("failed to deserialize trainer: %w", err)
-		}
-
-		// Execute transaction
-		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
-			pipe.JSONDel(ctx, key, "$")
-
-			// Clean up indices
-			r.cleanupTrainerIndices(ctx, pipe, t)
-
-			return nil
-		})
-
-		return err
-	}, key)
+	return r.docs.Delete(ctx, id.String())
 }
 
 // FindByNickname finds a trainer by nickname
@@ -314,26 +136,19 @@ func (r *RedisRepository) FindByNickname(ctx context.Context, nickname string) (
 	return r.GetByID(ctx, UserID(id))
 }
 
-// updateTrainerIndices updates secondary indices
-func (r *RedisRepository) updateTrainerIndices(ctx context.Context, pipe redis.Pipeliner, t *Trainer) {
-	// Position index
-	positionKey := fmt.Sprintf("idx:trainer:position:%.1f:%.1f", t.Position.X, t.Position.Y)
-	pipe.SAdd(ctx, positionKey, t.ID.String())
-
-	// Nickname index
-	nicknameKey := fmt.Sprintf("idx:trainer:nickname:%s", t.Nickname)
-	pipe.Set(ctx, nicknameKey, t.ID.String(), 0)
-}
-
-// cleanupTrainerIndices cleans up secondary indices
-func (r *RedisRepository) cleanupTrainerIndices(ctx context.Context, pipe redis.Pipeliner, t *Trainer) {
-	// Position index
-	positionKey := fmt.Sprintf("idx:trainer:position:%.1f:%.1f", t.Position.X, t.Position.Y)
-	pipe.SRem(ctx, positionKey, t.ID.String())
-
-	// Nickname index
-	nicknameKey := fmt.Sprintf("idx:trainer:nickname:%s", t.Nickname)
-	pipe.Del(ctx, nicknameKey)
+// trainerIndexes are the secondary indices kept with each trainer
+func trainerIndexes() []redisx.Index[Trainer] {
+	return []redisx.Index[Trainer]{
+		// Position index
+		redisx.SetIndex[Trainer]{Key: func(t *Trainer) string {
+			return fmt.Sprintf("idx:trainer:position:%.1f:%.1f", t.Position.X, t.Position.Y)
+		}},
+		// Nickname index
+		redisx.UniqueIndex[Trainer]{Key: func(t *Trainer) string {
+			return fmt.Sprintf("idx:trainer:nickname:%s", t.Nickname)
+		}},
+		redisx.IndexFunc[Trainer](updateListIndex),
+	}
 }
 
 // GetAll retrieves all trainers from Redis using JSON
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/redis/go-redis/v9"
@@ -242,4 +243,136 @@ func TestRedisRepository_Delete(t *testing.T) {
 		require.Error(t, err)
 		assert.Contains(t, err.Error(), "not found")
 	})
-}
\ No newline at end of file
+}
+
+// Test for List method
+func TestRedisRepository_List(t *testing.T) {
+	client := setupTestRedis(t)
+	defer client.Close()
+
+	repo := NewRedisRepository(client)
+	ctx := context.Background()
+
+	nicknames := []string{"ListTestC", "ListTestA", "ListTestB"}
+	for i, nickname := range nicknames {
+		trainer, err := NewTrainer(UserID(fmt.Sprintf("test-list-%d", i)), nickname)
+		require.NoError(t, err)
+		require.NoError(t, repo.FindOneAndInsert(ctx, trainer.ID, func() (*Trainer, error) {
+			return trainer, nil
+		}))
+		defer repo.Delete(ctx, trainer.ID)
+	}
+
+	// Walk every page two at a time, keeping the test trainers in the order they came
+	var listed []string
+	query, err := NewListQuery("nickname", "asc", "", 2)
+	require.NoError(t, err)
+	for {
+		page, err := repo.List(ctx, query)
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(page.Trainers), 2)
+		assert.GreaterOrEqual(t, page.Total, len(nicknames))
+
+		for _, trainer := range page.Trainers {
+			if strings.HasPrefix(trainer.Nickname, "ListTest") {
+				listed = append(listed, trainer.Nickname)
+			}
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		query, err = NewListQuery("nickname", "asc", page.NextCursor, 2)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"ListTestA", "ListTestB", "ListTestC"}, listed)
+}
+
+func TestRedisRepository_Search(t *testing.T) {
+	client := setupTestRedis(t)
+	defer client.Close()
+
+	repo := NewRedisRepository(client)
+	ctx := context.Background()
+
+	for i, nickname := range []string{"SearchTestB", "SearchTestA", "Other-Search"} {
+		trainer, err := NewTrainer(UserID(fmt.Sprintf("test-search-%d", i)), nickname)
+		require.NoError(t, err)
+		trainer.Level, err = shared.NewLevel(90 + i)
+		require.NoError(t, err)
+		trainer.Position = shared.Position{X: 5000 + float64(i), Y: 5000}
+		require.NoError(t, repo.FindOneAndInsert(ctx, trainer.ID, func() (*Trainer, error) {
+			return trainer, nil
+		}))
+		defer repo.Delete(ctx, trainer.ID)
+	}
+	require.NoError(t, repo.WaitIndexed(ctx))
+
+	nicknames := func(trainers []*Trainer) []string {
+		var found []string
+		for _, trainer := range trainers {
+			if strings.Contains(trainer.Nickname, "Search") {
+				found = append(found, trainer.Nickname)
+			}
+		}
+		return found
+	}
+
+	byPrefix, err := repo.SearchByNicknamePrefix(ctx, "searchtest")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SearchTestA", "SearchTestB"}, nicknames(byPrefix))
+
+	byLevel, err := repo.SearchByLevelRange(ctx, 90, 91)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"SearchTestB", "SearchTestA"}, nicknames(byLevel))
+
+	byArea, err := repo.SearchByArea(ctx, shared.Position{X: 5001, Y: 4999}, shared.Position{X: 5002, Y: 5001})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"SearchTestA", "Other-Search"}, nicknames(byArea))
+
+	_, err = repo.SearchByLevelRange(ctx, 10, 5)
+	assert.Error(t, err)
+}
+
+func TestRedisRepository_GetMany(t *testing.T) {
+	client := setupTestRedis(t)
+	defer client.Close()
+
+	repo := NewRedisRepository(client)
+	ctx := context.Background()
+
+	var ids []UserID
+	for _, name := range []string{"GetManyA", "GetManyB"} {
+		trainer, err := NewTrainer(UserID("test-get-many-"+name), name)
+		require.NoError(t, err)
+		require.NoError(t, repo.FindOneAndInsert(ctx, trainer.ID, func() (*Trainer, error) {
+			return trainer, nil
+		}))
+		defer repo.Delete(ctx, trainer.ID)
+		ids = append(ids, trainer.ID)
+	}
+
+	tests := []struct {
+		name string
+		ids  []UserID
+		want map[UserID]string
+	}{
+		{name: "no ids", ids: nil, want: map[UserID]string{}},
+		{name: "existing trainers", ids: ids, want: map[UserID]string{ids[0]: "GetManyA", ids[1]: "GetManyB"}},
+		{name: "missing trainers left out", ids: []UserID{ids[0], "test-get-many-missing"}, want: map[UserID]string{ids[0]: "GetManyA"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trainers, err := repo.GetMany(ctx, tt.ids)
+			require.NoError(t, err)
+
+			nicknames := make(map[UserID]string, len(trainers))
+			for id, trainer := range trainers {
+				nicknames[id] = trainer.Nickname
+			}
+			assert.Equal(t, tt.want, nicknames)
+		})
+	}
+}
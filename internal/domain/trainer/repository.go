@@ -20,6 +20,10 @@ type Repository interface {
 	// GetByID retrieves a trainer by UserID (read-only)
 	GetByID(ctx context.Context, id UserID) (*Trainer, error)
 
+	// GetMany retrieves several trainers in one round trip, leaving out those that don't
+	// exist (read-only)
+	GetMany(ctx context.Context, ids []UserID) (map[UserID]*Trainer, error)
+
 	// GetByPosition retrieves trainers at a specific position (read-only)
 	GetByPosition(ctx context.Context, position shared.Position) ([]*Trainer, error)
 
@@ -31,4 +35,24 @@ type Repository interface {
 
 	// GetAll retrieves all trainers (read-only)
 	GetAll(ctx context.Context) ([]*Trainer, error)
+
+	// List retrieves one page of trainers in a sort order (read-only)
+	List(ctx context.Context, query ListQuery) (*ListPage, error)
+
+	// SearchByArea retrieves trainers inside a rectangle, edges included (read-only)
+	SearchByArea(ctx context.Context, topLeft, bottomRight shared.Position) ([]*Trainer, error)
+
+	// SearchByLevelRange retrieves trainers from level min to max, ordered by level (read-only)
+	SearchByLevelRange(ctx context.Context, min, max int) ([]*Trainer, error)
+
+	// SearchByNicknamePrefix retrieves trainers whose nickname starts with prefix, ignoring
+	// case, ordered by nickname (read-only)
+	SearchByNicknamePrefix(ctx context.Context, prefix string) ([]*Trainer, error)
+
+	// SearchNicknames retrieves one page of trainers whose nickname matches a
+	// search.MatchExpression (read-only)
+	SearchNicknames(ctx context.Context, expression string, offset, limit int) (*SearchPage, error)
+
+	// WaitIndexed blocks until the search index is created and has indexed the existing trainers
+	WaitIndexed(ctx context.Context) error
 }
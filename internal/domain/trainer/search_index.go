@@ -0,0 +1,54 @@
+package trainer
+
+import (
+	"context"
+
+	"github.com/danghamo/life/internal/domain/search"
+)
+
+// SearchIndex finds trainers by nickname
+type SearchIndex struct {
+	repo Repository
+}
+
+// NewSearchIndex searches nicknames through the repository's search index
+func NewSearchIndex(repo Repository) *SearchIndex {
+	return &SearchIndex{repo: repo}
+}
+
+// Wait blocks until the index is created and has indexed the existing trainers
+func (s *SearchIndex) Wait(ctx context.Context) error {
+	return s.repo.WaitIndexed(ctx)
+}
+
+// Type returns the searched document type
+func (s *SearchIndex) Type() search.Type {
+	return search.TypeTrainer
+}
+
+// Search returns trainers whose nickname matches the expression. Hits are keyed by
+// nickname like public profiles, and only carry what the trainer's profile shows.
+func (s *SearchIndex) Search(ctx context.Context, expression string, offset, limit int) (*search.Group, error) {
+	page, err := s.repo.SearchNicknames(ctx, expression, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([]search.Hit, 0, len(page.Trainers))
+	for _, t := range page.Trainers {
+		hits = append(hits, search.Hit{
+			Type:   search.TypeTrainer,
+			ID:     t.Nickname,
+			Title:  t.Nickname,
+			Detail: t.PublicNameplate(),
+		})
+	}
+
+	return &search.Group{
+		Type:   search.TypeTrainer,
+		Hits:   hits,
+		Total:  page.Total,
+		Offset: offset,
+		Limit:  limit,
+	}, nil
+}
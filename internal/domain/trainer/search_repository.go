@@ -0,0 +1,116 @@
+package trainer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/search"
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// MaxSearchResults caps how many trainers one area, level range or nickname prefix search returns
+const MaxSearchResults = 1000
+
+// SearchPage is one page of trainers matching a search and the number of matches in total
+type SearchPage struct {
+	Trainers []*Trainer
+	Total    int
+}
+
+// newSearchIndex creates the index over trainer JSON documents. Nicknames are indexed twice:
+// as text for search.MatchExpression and as a tag for prefix matching and sorting.
+func newSearchIndex(client *redis.Client) *search.RedisIndex {
+	return search.NewRedisIndex(client, "idx:trainer", "trainer:",
+		"$.nickname", "AS", "nickname", "TEXT", "NOSTEM",
+		"$.nickname", "AS", "nickname_tag", "TAG", "SORTABLE",
+		"$.level", "AS", "level", "NUMERIC", "SORTABLE",
+		"$.position.x", "AS", "x", "NUMERIC",
+		"$.position.y", "AS", "y", "NUMERIC",
+	)
+}
+
+// WaitIndexed blocks until the search index is created and has indexed the existing trainers
+func (r *RedisRepository) WaitIndexed(ctx context.Context) error {
+	return r.index.Wait(ctx)
+}
+
+// SearchByArea finds trainers by their stored position with FT.SEARCH
+func (r *RedisRepository) SearchByArea(ctx context.Context, topLeft, bottomRight shared.Position) ([]*Trainer, error) {
+	expression := fmt.Sprintf("@x:[%f %f] @y:[%f %f]", topLeft.X, bottomRight.X, topLeft.Y, bottomRight.Y)
+	return r.searchAll(ctx, expression, "")
+}
+
+// SearchByLevelRange finds trainers by level with FT.SEARCH
+func (r *RedisRepository) SearchByLevelRange(ctx context.Context, min, max int) ([]*Trainer, error) {
+	if min > max {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "invalid level range: %d to %d", min, max)
+	}
+
+	return r.searchAll(ctx, fmt.Sprintf("@level:[%d %d]", min, max), "level")
+}
+
+// SearchByNicknamePrefix finds trainers by a prefix of their nickname tag with FT.SEARCH
+func (r *RedisRepository) SearchByNicknamePrefix(ctx context.Context, prefix string) ([]*Trainer, error) {
+	if strings.TrimSpace(prefix) == "" {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Nickname prefix must not be empty")
+	}
+
+	return r.searchAll(ctx, fmt.Sprintf("@nickname_tag:{%s*}", escapeTag(prefix)), "nickname_tag")
+}
+
+// SearchNicknames finds trainers by nickname text with FT.SEARCH
+func (r *RedisRepository) SearchNicknames(ctx context.Context, expression string, offset, limit int) (*SearchPage, error) {
+	return r.search(ctx, expression, "", offset, limit)
+}
+
+// searchAll returns up to MaxSearchResults matches, ordered by a sortable field unless it is empty
+func (r *RedisRepository) searchAll(ctx context.Context, expression, sortBy string) ([]*Trainer, error) {
+	page, err := r.search(ctx, expression, sortBy, 0, MaxSearchResults)
+	if err != nil {
+		return nil, err
+	}
+	return page.Trainers, nil
+}
+
+// search reads the matching trainers with a single JSON.MGET, in the order the index returned
+// them. Trainers deleted since they were indexed are left out.
+func (r *RedisRepository) search(ctx context.Context, expression, sortBy string, offset, limit int) (*SearchPage, error) {
+	total, ids, err := r.index.FindSorted(ctx, expression, sortBy, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]UserID, len(ids))
+	for i, id := range ids {
+		userIDs[i] = UserID(id)
+	}
+
+	found, err := r.GetMany(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &SearchPage{Trainers: make([]*Trainer, 0, len(found)), Total: total}
+	for _, id := range userIDs {
+		if t, ok := found[id]; ok {
+			page.Trainers = append(page.Trainers, t)
+		}
+	}
+	return page, nil
+}
+
+// escapeTag escapes the characters a tag query would read as syntax
+func escapeTag(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
@@ -1,11 +1,20 @@
 package trainer
 
 import (
-	"math/rand"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/danghamo/life/internal/domain/shared"
 )
 
+// Hot types get generated marshalers in builds tagged easyjson; see pkg/jsonx
+//go:generate go run github.com/mailru/easyjson/easyjson -build_tags easyjson -no_std_marshalers trainer.go
+
 // UserID represents a unique user identifier from Account domain
 type UserID shared.ID
 
@@ -14,6 +23,22 @@ func (id UserID) String() string {
 	return string(id)
 }
 
+// characterSeparator separates the user from the number in the ID of an additional character
+const characterSeparator = "."
+
+// CharacterID returns the trainer ID of a user's nth additional character. A user's first
+// trainer has the user's own ID.
+func CharacterID(userID UserID, n int) UserID {
+	return UserID(fmt.Sprintf("%s%s%d", userID, characterSeparator, n))
+}
+
+// AccountID returns the user a trainer belongs to, which is the trainer's own ID unless it is
+// an additional character
+func (id UserID) AccountID() UserID {
+	account, _, _ := strings.Cut(string(id), characterSeparator)
+	return UserID(account)
+}
+
 // ValidateNickname validates a nickname string
 func ValidateNickname(value string) error {
 	if len(value) < 3 || len(value) > 20 {
@@ -52,6 +77,9 @@ const (
 	AnimalHide   ItemType = "animal_hide"
 	RareGem      ItemType = "rare_gem"
 	MagicCrystal ItemType = "magic_crystal"
+
+	// Ammunition, unpacked into reserve ammo on reload
+	AmmoBox ItemType = "ammo_box"
 )
 
 // String returns string representation
@@ -63,7 +91,7 @@ func (it ItemType) String() string {
 func (it ItemType) IsValid() bool {
 	validTypes := []ItemType{
 		HealthPotion, ManaPotion, BasicNet, AdvancedNet, MasterNet,
-		AnimalHide, RareGem, MagicCrystal,
+		AnimalHide, RareGem, MagicCrystal, AmmoBox,
 	}
 	for _, validType := range validTypes {
 		if it == validType {
@@ -73,16 +101,48 @@ func (it ItemType) IsValid() bool {
 	return false
 }
 
-// Item represents an individual item instance
-type Item struct {
-	ID        ItemID           `json:"id"`
-	Type      ItemType         `json:"type"`
-	Name      string           `json:"name"`
-	CreatedAt shared.Timestamp `json:"created_at"`
+// MaxStack returns how many items of this type fit in a single inventory slot
+func (it ItemType) MaxStack() int {
+	switch it {
+	case HealthPotion, ManaPotion:
+		return 20
+	case BasicNet, AdvancedNet, MasterNet:
+		return 10
+	case AnimalHide:
+		return 50
+	case RareGem, MagicCrystal:
+		return 25
+	case AmmoBox:
+		return 20
+	default:
+		return 1
+	}
 }
 
-// NewItem creates a new item
+// Item represents a stack of items of the same type occupying one slot
+type Item struct {
+	ID           ItemID           `json:"id"`
+	Type         ItemType         `json:"type"`
+	Name         string           `json:"name"`
+	Quantity     int              `json:"quantity"`
+	Rarity       ItemRarity       `json:"rarity"`
+	Description  string           `json:"description"`
+	Icon         string           `json:"icon"`
+	ContentID    string           `json:"content_id"`
+	BindOnPickup bool             `json:"bind_on_pickup"`
+	BindOnEquip  bool             `json:"bind_on_equip"`
+	Bound        bool             `json:"bound"` // Bound items cannot be given, vaulted or dropped
+	CreatedAt    shared.Timestamp `json:"created_at"`
+}
+
+// NewItem creates a new single item
 func NewItem(itemType ItemType, name string) (*Item, error) {
+	return NewItemStack(itemType, name, 1)
+}
+
+// NewItemStack creates a new item with the given quantity.
+// Quantities above the max stack size are split into several stacks when added to an inventory.
+func NewItemStack(itemType ItemType, name string, quantity int) (*Item, error) {
 	if !itemType.IsValid() {
 		return nil, shared.NewDomainError(shared.ErrCodeInvalidItemType, "Invalid item type")
 	}
@@ -91,17 +151,25 @@ func NewItem(itemType ItemType, name string) (*Item, error) {
 		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Item name must be between 1 and 50 characters")
 	}
 
-	return &Item{
+	if quantity < 1 {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidAmount, "Item quantity must be positive")
+	}
+
+	item := &Item{
 		ID:        NewItemID(),
 		Type:      itemType,
 		Name:      name,
+		Quantity:  quantity,
 		CreatedAt: shared.NewTimestamp(),
-	}, nil
+	}
+	item.applyDefinition()
+
+	return item, nil
 }
 
-// Inventory represents trainer's inventory with unique items
+// Inventory represents trainer's inventory of item stacks, one stack per slot
 type Inventory struct {
-	Items    map[string]*Item `json:"items"` // itemID -> Item
+	Items    map[string]*Item `json:"items"` // itemID -> Item stack
 	MaxSlots int              `json:"max_slots"`
 }
 
@@ -113,22 +181,70 @@ func NewInventory(maxSlots int) Inventory {
 	}
 }
 
-// AddItem adds an item to inventory
+// UnmarshalJSON restores the inventory, migrating legacy one-item-per-slot data into stacks
+func (inv *Inventory) UnmarshalJSON(data []byte) error {
+	type inventoryJSON Inventory
+
+	var raw inventoryJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	*inv = Inventory(raw)
+	if inv.Items == nil {
+		inv.Items = make(map[string]*Item)
+	}
+	inv.Compact()
+
+	return nil
+}
+
+// Compact merges partial stacks of the same item. Items stored before stacking
+// existed have no quantity and count as one each.
+func (inv *Inventory) Compact() {
+	items := sortedItems(inv.Items)
+
+	inv.Items = make(map[string]*Item, len(items))
+	for _, item := range items {
+		if item.Quantity <= 0 {
+			item.Quantity = 1
+		}
+		inv.placeItem(item)
+	}
+}
+
+// AddItem adds an item to inventory, filling existing stacks before using new slots.
+// The whole quantity is added or nothing is.
 func (inv *Inventory) AddItem(item *Item) error {
 	if item == nil {
 		return shared.NewDomainError(shared.ErrCodeInvalidInput, "Item cannot be nil")
 	}
 
-	currentItems := len(inv.Items)
-	if currentItems >= inv.MaxSlots {
+	if item.Quantity <= 0 {
+		item.Quantity = 1
+	}
+
+	if !inv.CanAdd(item) {
 		return shared.NewDomainError(shared.ErrCodeInventoryFull, "Inventory is full")
 	}
 
-	inv.Items[item.ID.String()] = item
+	inv.placeItem(item)
 	return nil
 }
 
-// RemoveItem removes an item from inventory by ID
+// CanAdd checks if the whole item stack fits in the inventory
+func (inv *Inventory) CanAdd(item *Item) bool {
+	maxStack := item.Type.MaxStack()
+
+	room := (inv.MaxSlots - len(inv.Items)) * maxStack
+	for _, stack := range inv.stacksWith(item) {
+		room += maxStack - stack.Quantity
+	}
+
+	return item.Quantity <= room
+}
+
+// RemoveItem removes a whole item stack from inventory by ID
 func (inv *Inventory) RemoveItem(itemID ItemID) (*Item, error) {
 	item, exists := inv.Items[itemID.String()]
 	if !exists {
@@ -139,6 +255,30 @@ func (inv *Inventory) RemoveItem(itemID ItemID) (*Item, error) {
 	return item, nil
 }
 
+// RemoveQuantity removes part of an item stack, returning the removed portion as its own item
+func (inv *Inventory) RemoveQuantity(itemID ItemID, quantity int) (*Item, error) {
+	if quantity <= 0 {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidAmount, "Quantity must be positive")
+	}
+
+	item, exists := inv.Items[itemID.String()]
+	if !exists {
+		return nil, shared.NewDomainError(shared.ErrCodeItemNotFound, "Item not found in inventory")
+	}
+
+	if item.Quantity < quantity {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInsufficientItems, "Not enough %s in stack", item.Type)
+	}
+
+	if item.Quantity == quantity {
+		delete(inv.Items, itemID.String())
+		return item, nil
+	}
+
+	item.Quantity -= quantity
+	return item.split(quantity), nil
+}
+
 // HasItem checks if inventory has a specific item
 func (inv *Inventory) HasItem(itemID ItemID) bool {
 	_, exists := inv.Items[itemID.String()]
@@ -171,17 +311,55 @@ func (inv *Inventory) GetItemsByType(itemType ItemType) []*Item {
 	return items
 }
 
-// CountItemsByType returns the count of items of a specific type
+// CountItemsByType returns the total quantity of items of a specific type
 func (inv *Inventory) CountItemsByType(itemType ItemType) int {
 	count := 0
 	for _, item := range inv.Items {
 		if item.Type == itemType {
-			count++
+			count += item.Quantity
 		}
 	}
 	return count
 }
 
+// RemoveItemsByType removes count items of a specific type, all or nothing.
+// Smaller stacks are used up first; the removed portions are returned.
+func (inv *Inventory) RemoveItemsByType(itemType ItemType, count int) ([]*Item, error) {
+	if count <= 0 {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidAmount, "Count must be positive")
+	}
+
+	if inv.CountItemsByType(itemType) < count {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInsufficientItems, "Not enough %s in inventory", itemType)
+	}
+
+	stacks := inv.stacksOf(itemType)
+	sort.SliceStable(stacks, func(i, j int) bool {
+		return stacks[i].Quantity < stacks[j].Quantity
+	})
+
+	removed := make([]*Item, 0)
+	remaining := count
+	for _, stack := range stacks {
+		if remaining == 0 {
+			break
+		}
+
+		if stack.Quantity <= remaining {
+			remaining -= stack.Quantity
+			delete(inv.Items, stack.ID.String())
+			removed = append(removed, stack)
+			continue
+		}
+
+		stack.Quantity -= remaining
+		removed = append(removed, stack.split(remaining))
+		remaining = 0
+	}
+
+	return removed, nil
+}
+
 // IsFull checks if inventory is full
 func (inv *Inventory) IsFull() bool {
 	return len(inv.Items) >= inv.MaxSlots
@@ -192,12 +370,129 @@ func (inv *Inventory) GetUsedSlots() int {
 	return len(inv.Items)
 }
 
+// placeItem merges an item into existing stacks and stores any remainder as new stacks
+func (inv *Inventory) placeItem(item *Item) {
+	if item.BindOnPickup {
+		item.Bind()
+	}
+
+	maxStack := item.Type.MaxStack()
+	remaining := item.Quantity
+
+	for _, stack := range inv.stacksWith(item) {
+		if remaining == 0 {
+			break
+		}
+		take := min(maxStack-stack.Quantity, remaining)
+		if take <= 0 {
+			continue
+		}
+		stack.Quantity += take
+		remaining -= take
+	}
+
+	// The item itself becomes the first new stack so its ID is kept
+	for first := true; remaining > 0; first = false {
+		size := min(remaining, maxStack)
+		stack := item
+		if !first {
+			stack = item.split(size)
+		}
+		stack.Quantity = size
+		inv.Items[stack.ID.String()] = stack
+		remaining -= size
+	}
+}
+
+// stacksOf returns the stacks of an item type in a stable order
+func (inv *Inventory) stacksOf(itemType ItemType) []*Item {
+	stacks := make([]*Item, 0)
+	for _, item := range sortedItems(inv.Items) {
+		if item.Type == itemType {
+			stacks = append(stacks, item)
+		}
+	}
+	return stacks
+}
+
+// stacksWith returns the stacks an item merges into in a stable order: those of the same
+// type and name that are bound alike
+func (inv *Inventory) stacksWith(item *Item) []*Item {
+	bound := item.Bound || item.BindOnPickup
+	stacks := make([]*Item, 0)
+	for _, stack := range inv.stacksOf(item.Type) {
+		if stack.Name == item.Name && stack.Bound == bound {
+			stacks = append(stacks, stack)
+		}
+	}
+	return stacks
+}
+
+// split returns a new item of the same kind with the given quantity
+func (item *Item) split(quantity int) *Item {
+	portion := *item
+	portion.ID = NewItemID()
+	portion.Quantity = quantity
+	return &portion
+}
+
+// sortedItems returns the items ordered by ID
+func sortedItems(items map[string]*Item) []*Item {
+	result := make([]*Item, 0, len(items))
+	for _, item := range items {
+		result = append(result, item)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID < result[j].ID
+	})
+	return result
+}
+
+// DefaultPartySize is how many animals a trainer can keep in their party
+const DefaultPartySize = 6
+
 // AnimalParty represents trainer's animal party
 type AnimalParty struct {
 	animalIDs []shared.ID
 	maxSize   int
 }
 
+// partyJSON is the stored form of a party
+type partyJSON struct {
+	AnimalIDs []shared.ID `json:"animal_ids"`
+	MaxSize   int         `json:"max_size"`
+}
+
+// MarshalJSON serializes the party members and size
+func (party AnimalParty) MarshalJSON() ([]byte, error) {
+	return json.Marshal(partyJSON{AnimalIDs: party.GetAnimals(), MaxSize: party.maxSize})
+}
+
+// UnmarshalJSON restores the party, giving parties stored as empty objects the default size
+func (party *AnimalParty) UnmarshalJSON(data []byte) error {
+	var raw partyJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw.MaxSize <= 0 {
+		raw.MaxSize = DefaultPartySize
+	}
+	*party = NewAnimalParty(raw.MaxSize)
+	party.animalIDs = append(party.animalIDs, raw.AnimalIDs...)
+	return nil
+}
+
+// Contains checks if an animal is in the party
+func (party *AnimalParty) Contains(animalID shared.ID) bool {
+	for _, id := range party.animalIDs {
+		if id == animalID {
+			return true
+		}
+	}
+	return false
+}
+
 // NewAnimalParty creates a new animal party
 func NewAnimalParty(maxSize int) AnimalParty {
 	return AnimalParty{
@@ -234,6 +529,21 @@ func (party *AnimalParty) RemoveAnimal(animalID shared.ID) error {
 	return shared.NewDomainError(shared.ErrCodeAnimalNotInParty, "Animal is not in party")
 }
 
+// SwapAnimal replaces a party member with another animal, keeping its position in the party
+func (party *AnimalParty) SwapAnimal(outID, inID shared.ID) error {
+	if party.Contains(inID) {
+		return shared.NewDomainError(shared.ErrCodeAnimalAlreadyInParty, "Animal is already in party")
+	}
+
+	for i, id := range party.animalIDs {
+		if id == outID {
+			party.animalIDs[i] = inID
+			return nil
+		}
+	}
+	return shared.NewDomainError(shared.ErrCodeAnimalNotInParty, "Animal is not in party")
+}
+
 // GetAnimals returns all animal IDs in the party
 func (party *AnimalParty) GetAnimals() []shared.ID {
 	result := make([]shared.ID, len(party.animalIDs))
@@ -252,6 +562,8 @@ func (party *AnimalParty) Size() int {
 }
 
 // Trainer represents a trainer aggregate
+//
+//easyjson:json
 type Trainer struct {
 	ID         UserID            `json:"id"` // UserID from Account domain
 	Nickname   string            `json:"nickname"`
@@ -259,32 +571,105 @@ type Trainer struct {
 	Level      shared.Level      `json:"level"`
 	Experience shared.Experience `json:"experience"`
 	Stats      shared.Stats      `json:"stats"`
+	Condition  Condition         `json:"condition"`
 	Position   shared.Position   `json:"position"`
 	Movement   MovementState     `json:"movement"`
 	Money      shared.Money      `json:"money"`
 	Inventory  Inventory         `json:"inventory"`
 	Party      AnimalParty       `json:"party"`
+	Profile    ProfileSettings   `json:"profile"`
+	Death      *Death            `json:"death,omitempty"` // Set while the trainer waits to respawn
 	CreatedAt  shared.Timestamp  `json:"created_at"`
 	UpdatedAt  shared.Timestamp  `json:"updated_at"`
 }
 
-// generateRandomColor generates a random hex color from predefined palette
-func generateRandomColor() string {
-	// 50 pre-defined attractive colors for better visual distinction
-	colors := []string{
-		"#4444ff", "#ff4444", "#44ff44", "#ffaa44", "#ff44aa",
-		"#44aaff", "#aaff44", "#aa44ff", "#ffaa88", "#88aaff",
-		"#aaffaa", "#ffaabb", "#ff8844", "#44ff88", "#8844ff",
-		"#ff4488", "#88ff44", "#4488ff", "#aa8844", "#44aa88",
-		"#ff6644", "#6644ff", "#44ff66", "#ff44cc", "#cc44ff",
-		"#44ffcc", "#ffcc44", "#cc44aa", "#44ccff", "#aaccff",
-		"#ffaacc", "#ccffaa", "#aaffcc", "#ffccaa", "#ccaaff",
-		"#ff7755", "#7755ff", "#55ff77", "#ff5599", "#9955ff",
-		"#55ff99", "#ff9955", "#9955aa", "#55aaff", "#aa55ff",
-		"#ff8866", "#8866ff", "#66ff88", "#ff6699", "#9966ff",
-		"#66ff99", "#ff9966", "#9966aa", "#66aaff", "#aa66ff",
+// Palette holds the colors trainers can have, picked for visual distinction
+var Palette = []string{
+	"#4444ff", "#ff4444", "#44ff44", "#ffaa44", "#ff44aa",
+	"#44aaff", "#aaff44", "#aa44ff", "#ffaa88", "#88aaff",
+	"#aaffaa", "#ffaabb", "#ff8844", "#44ff88", "#8844ff",
+	"#ff4488", "#88ff44", "#4488ff", "#aa8844", "#44aa88",
+	"#ff6644", "#6644ff", "#44ff66", "#ff44cc", "#cc44ff",
+	"#44ffcc", "#ffcc44", "#cc44aa", "#44ccff", "#aaccff",
+	"#ffaacc", "#ccffaa", "#aaffcc", "#ffccaa", "#ccaaff",
+	"#ff7755", "#7755ff", "#55ff77", "#ff5599", "#9955ff",
+	"#55ff99", "#ff9955", "#9955aa", "#55aaff", "#aa55ff",
+	"#ff8866", "#8866ff", "#66ff88", "#ff6699", "#9966ff",
+	"#66ff99", "#ff9966", "#9966aa", "#66aaff", "#aa66ff",
+}
+
+// HighContrast holds colors that stay apart for players with low vision or color blindness,
+// the Okabe-Ito set and white
+var HighContrast = []string{
+	"#e69f00", "#56b4e9", "#009e73", "#f0e442",
+	"#0072b2", "#d55e00", "#cc79a7", "#ffffff",
+}
+
+// PaletteName names a set of colors trainers can have
+type PaletteName string
+
+const (
+	StandardPalette     PaletteName = "standard"      // Palette
+	HighContrastPalette PaletteName = "high_contrast" // HighContrast
+)
+
+// String returns string representation
+func (p PaletteName) String() string {
+	return string(p)
+}
+
+// Colors returns the colors of a palette, false if there is no palette of that name
+func (p PaletteName) Colors() ([]string, bool) {
+	switch p {
+	case StandardPalette:
+		return Palette, true
+	case HighContrastPalette:
+		return HighContrast, true
 	}
-	return colors[rand.Intn(len(colors))]
+	return nil, false
+}
+
+// IsPaletteColor checks if a color is in one of the palettes
+func IsPaletteColor(color string) bool {
+	return slices.Contains(Palette, color) || slices.Contains(HighContrast, color)
+}
+
+// colorForUser picks a hex color from the palette based on the user ID, so a trainer keeps
+// the same color if it is ever recreated
+func colorForUser(userID UserID) string {
+	return paletteColor(Palette, userID)
+}
+
+// paletteColor picks the color of a palette a user ID hashes to
+func paletteColor(palette []string, userID UserID) string {
+	h := fnv.New32a()
+	h.Write([]byte(userID.String()))
+	return palette[h.Sum32()%uint32(len(palette))]
+}
+
+// Recolor moves the trainer's color into a palette, picking the palette's color for the
+// trainer unless the color they have is in it already. It reports whether the color changed.
+func (t *Trainer) Recolor(palette PaletteName) (bool, error) {
+	colors, ok := palette.Colors()
+	if !ok {
+		return false, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown palette: %s", palette)
+	}
+	if slices.Contains(colors, t.Color) {
+		return false, nil
+	}
+	t.Color = paletteColor(colors, t.ID)
+	t.UpdatedAt = shared.NewTimestamp()
+	return true, nil
+}
+
+// SetColor changes the trainer's color to one from the palettes
+func (t *Trainer) SetColor(color string) error {
+	if !IsPaletteColor(color) {
+		return shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown color: %s", color)
+	}
+	t.Color = color
+	t.UpdatedAt = shared.NewTimestamp()
+	return nil
 }
 
 // NewTrainer creates a new trainer with UserID from Account domain
@@ -301,9 +686,9 @@ func NewTrainer(userID UserID, nickname string) (*Trainer, error) {
 	movement.StartPos = position                 // Set initial position
 	money, _ := shared.NewMoney(1000)            // Starting money
 	inventory := NewInventory(50)                // 50 inventory slots
-	party := NewAnimalParty(6)                   // Max 6 animals
+	party := NewAnimalParty(DefaultPartySize)
 	timestamp := shared.NewTimestamp()
-	color := generateRandomColor() // Assign random color
+	color := colorForUser(userID) // Assign palette color
 
 	trainer := &Trainer{
 		ID:         userID, // Use UserID from Account domain
@@ -312,6 +697,7 @@ func NewTrainer(userID UserID, nickname string) (*Trainer, error) {
 		Level:      level,
 		Experience: experience,
 		Stats:      stats,
+		Condition:  NewCondition(stats.HP),
 		Position:   position,
 		Movement:   movement,
 		Money:      money,
@@ -349,9 +735,90 @@ func (t *Trainer) StopMovement() error {
 
 // UpdatePositionFromMovement updates position based on movement state
 func (t *Trainer) UpdatePositionFromMovement() {
+	t.Movement.AdvanceWaypoints(time.Now())
 	t.Position = t.Movement.CalculateCurrentPosition()
 }
 
+// UpdatePositionWithin updates position like UpdatePositionFromMovement, clamped to walkable
+// terrain. A trainer that runs into blocked terrain stops there; the result reports whether
+// that happened.
+func (t *Trainer) UpdatePositionWithin(terrain Terrain) bool {
+	t.Movement.AdvanceWaypoints(time.Now())
+	position, blocked := t.Movement.CalculateWalkablePosition(terrain)
+	t.Position = position
+
+	if blocked {
+		t.Movement.StopMovement(position)
+		t.UpdatedAt = shared.NewTimestamp()
+	}
+
+	return blocked
+}
+
+// StartMovementWithin starts movement like StartMovement, rejecting directions whose first
+// step leaves walkable terrain
+func (t *Trainer) StartMovementWithin(dirX, dirY float64, terrain Terrain) error {
+	if t.IsDead() {
+		return shared.NewDomainError(shared.ErrCodeTrainerDead, "Dead trainers cannot move")
+	}
+	t.UpdatePositionWithin(terrain)
+
+	direction := MovementDirection{X: dirX, Y: dirY}
+	if !terrain.IsWalkablePosition(NextStep(t.Position, direction)) {
+		return shared.NewDomainError(shared.ErrCodeInvalidMove, "Destination is not walkable")
+	}
+
+	return t.StartMovement(dirX, dirY)
+}
+
+// StopMovementWithin stops movement like StopMovement at the last walkable point of the path
+func (t *Trainer) StopMovementWithin(terrain Terrain) error {
+	t.UpdatePositionWithin(terrain)
+
+	return t.StopMovement()
+}
+
+// MoveAlong starts walking the trainer through the positions of a path, e.g. one found by the
+// world's pathfinding, stopping at its end. Positions the trainer would pass walking straight
+// on are dropped, so it only turns where the path does.
+func (t *Trainer) MoveAlong(path []shared.Position, terrain Terrain) error {
+	if len(path) == 0 {
+		return shared.NewDomainError(shared.ErrCodeInvalidMove, "Path is empty")
+	}
+	if t.IsDead() {
+		return shared.NewDomainError(shared.ErrCodeTrainerDead, "Dead trainers cannot move")
+	}
+	t.UpdatePositionWithin(terrain)
+
+	t.Movement.FollowWaypoints(turningPoints(t.Position, path), t.Position)
+	t.UpdatedAt = shared.NewTimestamp()
+
+	return nil
+}
+
+// turningPoints returns the positions of a path walked from a position where its direction
+// changes, and its end
+func turningPoints(from shared.Position, path []shared.Position) []shared.Position {
+	points := make([]shared.Position, 0, len(path))
+	last := from
+	for i, point := range path {
+		if point == last {
+			continue
+		}
+		if i+1 < len(path) {
+			next := path[i+1]
+			ax, ay := point.X-last.X, point.Y-last.Y
+			bx, by := next.X-point.X, next.Y-point.Y
+			if ax*by-ay*bx == 0 && ax*bx+ay*by > 0 {
+				continue // Straight on
+			}
+		}
+		points = append(points, point)
+		last = point
+	}
+	return points
+}
+
 // MoveTo moves the trainer to a new position (legacy support)
 func (t *Trainer) MoveTo(newPosition shared.Position) error {
 	// Stop any current movement and set position directly
@@ -469,6 +936,18 @@ func (t *Trainer) RemoveAnimalFromParty(animalID shared.ID) error {
 	return nil
 }
 
+// SwapPartyAnimal replaces a party member with an animal from storage
+func (t *Trainer) SwapPartyAnimal(outID, inID shared.ID) error {
+	err := t.Party.SwapAnimal(outID, inID)
+	if err != nil {
+		return err
+	}
+
+	t.UpdatedAt = shared.NewTimestamp()
+
+	return nil
+}
+
 // CanAfford checks if trainer can afford something
 func (t *Trainer) CanAfford(cost int) bool {
 	return t.Money.CanAfford(cost)
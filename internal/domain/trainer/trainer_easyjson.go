@@ -0,0 +1,1240 @@
+//go:build easyjson
+// +build easyjson
+
+// Code generated by easyjson for marshaling/unmarshaling. DO NOT EDIT.
+
+package trainer
+
+import (
+	json "encoding/json"
+	shared "github.com/danghamo/life/internal/domain/shared"
+	easyjson "github.com/mailru/easyjson"
+	jlexer "github.com/mailru/easyjson/jlexer"
+	jwriter "github.com/mailru/easyjson/jwriter"
+	time "time"
+)
+
+// suppress unused package warning
+var (
+	_ *json.RawMessage
+	_ *jlexer.Lexer
+	_ *jwriter.Writer
+	_ easyjson.Marshaler
+)
+
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer(in *jlexer.Lexer, out *Trainer) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = UserID(in.String())
+		case "nickname":
+			out.Nickname = string(in.String())
+		case "color":
+			out.Color = string(in.String())
+		case "level":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Level).UnmarshalJSON(data))
+			}
+		case "experience":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared(in, &out.Experience)
+		case "stats":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared1(in, &out.Stats)
+		case "condition":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer1(in, &out.Condition)
+		case "position":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared2(in, &out.Position)
+		case "movement":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer2(in, &out.Movement)
+		case "money":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared3(in, &out.Money)
+		case "inventory":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Inventory).UnmarshalJSON(data))
+			}
+		case "party":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.Party).UnmarshalJSON(data))
+			}
+		case "profile":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer3(in, &out.Profile)
+		case "death":
+			if in.IsNull() {
+				in.Skip()
+				out.Death = nil
+			} else {
+				if out.Death == nil {
+					out.Death = new(Death)
+				}
+				easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer4(in, out.Death)
+			}
+		case "created_at":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared4(in, &out.CreatedAt)
+		case "updated_at":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared4(in, &out.UpdatedAt)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer(out *jwriter.Writer, in Trainer) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"nickname\":"
+		out.RawString(prefix)
+		out.String(string(in.Nickname))
+	}
+	{
+		const prefix string = ",\"color\":"
+		out.RawString(prefix)
+		out.String(string(in.Color))
+	}
+	{
+		const prefix string = ",\"level\":"
+		out.RawString(prefix)
+		out.Raw((in.Level).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"experience\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared(out, in.Experience)
+	}
+	{
+		const prefix string = ",\"stats\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared1(out, in.Stats)
+	}
+	{
+		const prefix string = ",\"condition\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer1(out, in.Condition)
+	}
+	{
+		const prefix string = ",\"position\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared2(out, in.Position)
+	}
+	{
+		const prefix string = ",\"movement\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer2(out, in.Movement)
+	}
+	{
+		const prefix string = ",\"money\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared3(out, in.Money)
+	}
+	{
+		const prefix string = ",\"inventory\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer3(out, in.Inventory)
+	}
+	{
+		const prefix string = ",\"party\":"
+		out.RawString(prefix)
+		out.Raw((in.Party).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"profile\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer4(out, in.Profile)
+	}
+	if in.Death != nil {
+		const prefix string = ",\"death\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer5(out, *in.Death)
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared4(out, in.CreatedAt)
+	}
+	{
+		const prefix string = ",\"updated_at\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared4(out, in.UpdatedAt)
+	}
+	out.RawByte('}')
+}
+
+// MarshalEasyJSON supports easyjson.Marshaler interface
+func (v Trainer) MarshalEasyJSON(w *jwriter.Writer) {
+	easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer(w, v)
+}
+
+// UnmarshalEasyJSON supports easyjson.Unmarshaler interface
+func (v *Trainer) UnmarshalEasyJSON(l *jlexer.Lexer) {
+	easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer(l, v)
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer5(in *jlexer.Lexer, out *Inventory) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "items":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.Items = make(map[string]*Item)
+				for !in.IsDelim('}') {
+					key := string(in.String())
+					in.WantColon()
+					var v1 *Item
+					if in.IsNull() {
+						in.Skip()
+						v1 = nil
+					} else {
+						if v1 == nil {
+							v1 = new(Item)
+						}
+						if data := in.Raw(); in.Ok() {
+							in.AddError((*v1).UnmarshalJSON(data))
+						}
+					}
+					(out.Items)[key] = v1
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		case "max_slots":
+			out.MaxSlots = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer3(out *jwriter.Writer, in Inventory) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"items\":"
+		out.RawString(prefix[1:])
+		if in.Items == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v2First := true
+			for v2Name, v2Value := range in.Items {
+				if v2First {
+					v2First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v2Name))
+				out.RawByte(':')
+				if v2Value == nil {
+					out.RawString("null")
+				} else {
+					easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer6(out, *v2Value)
+				}
+			}
+			out.RawByte('}')
+		}
+	}
+	{
+		const prefix string = ",\"max_slots\":"
+		out.RawString(prefix)
+		out.Int(int(in.MaxSlots))
+	}
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer6(in *jlexer.Lexer, out *Item) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = ItemID(in.String())
+		case "type":
+			out.Type = ItemType(in.String())
+		case "name":
+			out.Name = string(in.String())
+		case "quantity":
+			out.Quantity = int(in.Int())
+		case "rarity":
+			out.Rarity = ItemRarity(in.String())
+		case "description":
+			out.Description = string(in.String())
+		case "icon":
+			out.Icon = string(in.String())
+		case "content_id":
+			out.ContentID = string(in.String())
+		case "bind_on_pickup":
+			out.BindOnPickup = bool(in.Bool())
+		case "bind_on_equip":
+			out.BindOnEquip = bool(in.Bool())
+		case "bound":
+			out.Bound = bool(in.Bool())
+		case "created_at":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared4(in, &out.CreatedAt)
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer6(out *jwriter.Writer, in Item) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"name\":"
+		out.RawString(prefix)
+		out.String(string(in.Name))
+	}
+	{
+		const prefix string = ",\"quantity\":"
+		out.RawString(prefix)
+		out.Int(int(in.Quantity))
+	}
+	{
+		const prefix string = ",\"rarity\":"
+		out.RawString(prefix)
+		out.String(string(in.Rarity))
+	}
+	{
+		const prefix string = ",\"description\":"
+		out.RawString(prefix)
+		out.String(string(in.Description))
+	}
+	{
+		const prefix string = ",\"icon\":"
+		out.RawString(prefix)
+		out.String(string(in.Icon))
+	}
+	{
+		const prefix string = ",\"content_id\":"
+		out.RawString(prefix)
+		out.String(string(in.ContentID))
+	}
+	{
+		const prefix string = ",\"bind_on_pickup\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.BindOnPickup))
+	}
+	{
+		const prefix string = ",\"bind_on_equip\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.BindOnEquip))
+	}
+	{
+		const prefix string = ",\"bound\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.Bound))
+	}
+	{
+		const prefix string = ",\"created_at\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared4(out, in.CreatedAt)
+	}
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared4(in *jlexer.Lexer, out *shared.Timestamp) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared4(out *jwriter.Writer, in shared.Timestamp) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer4(in *jlexer.Lexer, out *Death) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "cause":
+			out.Cause = DeathCause(in.String())
+		case "source_id":
+			out.SourceID = string(in.String())
+		case "money_lost":
+			out.MoneyLost = int(in.Int())
+		case "died_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.DiedAt).UnmarshalJSON(data))
+			}
+		case "respawn_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.RespawnAt).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer5(out *jwriter.Writer, in Death) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"cause\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Cause))
+	}
+	if in.SourceID != "" {
+		const prefix string = ",\"source_id\":"
+		out.RawString(prefix)
+		out.String(string(in.SourceID))
+	}
+	{
+		const prefix string = ",\"money_lost\":"
+		out.RawString(prefix)
+		out.Int(int(in.MoneyLost))
+	}
+	{
+		const prefix string = ",\"died_at\":"
+		out.RawString(prefix)
+		out.Raw((in.DiedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"respawn_at\":"
+		out.RawString(prefix)
+		out.Raw((in.RespawnAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer3(in *jlexer.Lexer, out *ProfileSettings) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "hidden_fields":
+			if in.IsNull() {
+				in.Skip()
+				out.HiddenFields = nil
+			} else {
+				in.Delim('[')
+				if out.HiddenFields == nil {
+					if !in.IsDelim(']') {
+						out.HiddenFields = make([]ProfileField, 0, 4)
+					} else {
+						out.HiddenFields = []ProfileField{}
+					}
+				} else {
+					out.HiddenFields = (out.HiddenFields)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v3 ProfileField
+					v3 = ProfileField(in.String())
+					out.HiddenFields = append(out.HiddenFields, v3)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "showcase":
+			if in.IsNull() {
+				in.Skip()
+				out.Showcase = nil
+			} else {
+				in.Delim('[')
+				if out.Showcase == nil {
+					if !in.IsDelim(']') {
+						out.Showcase = make([]ShowcasedAnimal, 0, 1)
+					} else {
+						out.Showcase = []ShowcasedAnimal{}
+					}
+				} else {
+					out.Showcase = (out.Showcase)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v4 ShowcasedAnimal
+					easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer7(in, &v4)
+					out.Showcase = append(out.Showcase, v4)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer4(out *jwriter.Writer, in ProfileSettings) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"hidden_fields\":"
+		out.RawString(prefix[1:])
+		if in.HiddenFields == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v5, v6 := range in.HiddenFields {
+				if v5 > 0 {
+					out.RawByte(',')
+				}
+				out.String(string(v6))
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"showcase\":"
+		out.RawString(prefix)
+		if in.Showcase == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v7, v8 := range in.Showcase {
+				if v7 > 0 {
+					out.RawByte(',')
+				}
+				easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer7(out, v8)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer7(in *jlexer.Lexer, out *ShowcasedAnimal) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "id":
+			out.ID = string(in.String())
+		case "type":
+			out.Type = string(in.String())
+		case "level":
+			out.Level = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer7(out *jwriter.Writer, in ShowcasedAnimal) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"id\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.ID))
+	}
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix)
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"level\":"
+		out.RawString(prefix)
+		out.Int(int(in.Level))
+	}
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared3(in *jlexer.Lexer, out *shared.Money) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared3(out *jwriter.Writer, in shared.Money) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer2(in *jlexer.Lexer, out *MovementState) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "direction":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer8(in, &out.Direction)
+		case "speed":
+			out.Speed = float64(in.Float64())
+		case "start_time":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.StartTime).UnmarshalJSON(data))
+			}
+		case "start_pos":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared2(in, &out.StartPos)
+		case "is_moving":
+			out.IsMoving = bool(in.Bool())
+		case "waypoints":
+			if in.IsNull() {
+				in.Skip()
+				out.Waypoints = nil
+			} else {
+				in.Delim('[')
+				if out.Waypoints == nil {
+					if !in.IsDelim(']') {
+						out.Waypoints = make([]shared.Position, 0, 4)
+					} else {
+						out.Waypoints = []shared.Position{}
+					}
+				} else {
+					out.Waypoints = (out.Waypoints)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v9 shared.Position
+					easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared2(in, &v9)
+					out.Waypoints = append(out.Waypoints, v9)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer2(out *jwriter.Writer, in MovementState) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"direction\":"
+		out.RawString(prefix[1:])
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer8(out, in.Direction)
+	}
+	{
+		const prefix string = ",\"speed\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Speed))
+	}
+	{
+		const prefix string = ",\"start_time\":"
+		out.RawString(prefix)
+		out.Raw((in.StartTime).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"start_pos\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared2(out, in.StartPos)
+	}
+	{
+		const prefix string = ",\"is_moving\":"
+		out.RawString(prefix)
+		out.Bool(bool(in.IsMoving))
+	}
+	if len(in.Waypoints) != 0 {
+		const prefix string = ",\"waypoints\":"
+		out.RawString(prefix)
+		{
+			out.RawByte('[')
+			for v10, v11 := range in.Waypoints {
+				if v10 > 0 {
+					out.RawByte(',')
+				}
+				easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared2(out, v11)
+			}
+			out.RawByte(']')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer8(in *jlexer.Lexer, out *MovementDirection) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "x":
+			out.X = float64(in.Float64())
+		case "y":
+			out.Y = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer8(out *jwriter.Writer, in MovementDirection) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"x\":"
+		out.RawString(prefix[1:])
+		out.Float64(float64(in.X))
+	}
+	{
+		const prefix string = ",\"y\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Y))
+	}
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared2(in *jlexer.Lexer, out *shared.Position) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "x":
+			out.X = float64(in.Float64())
+		case "y":
+			out.Y = float64(in.Float64())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared2(out *jwriter.Writer, in shared.Position) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"x\":"
+		out.RawString(prefix[1:])
+		out.Float64(float64(in.X))
+	}
+	{
+		const prefix string = ",\"y\":"
+		out.RawString(prefix)
+		out.Float64(float64(in.Y))
+	}
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer1(in *jlexer.Lexer, out *Condition) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "hp":
+			out.HP = int(in.Int())
+		case "mana":
+			out.Mana = int(in.Int())
+		case "max_mana":
+			out.MaxMana = int(in.Int())
+		case "effects":
+			if in.IsNull() {
+				in.Skip()
+				out.Effects = nil
+			} else {
+				in.Delim('[')
+				if out.Effects == nil {
+					if !in.IsDelim(']') {
+						out.Effects = make([]StatusEffect, 0, 0)
+					} else {
+						out.Effects = []StatusEffect{}
+					}
+				} else {
+					out.Effects = (out.Effects)[:0]
+				}
+				for !in.IsDelim(']') {
+					var v12 StatusEffect
+					easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer9(in, &v12)
+					out.Effects = append(out.Effects, v12)
+					in.WantComma()
+				}
+				in.Delim(']')
+			}
+		case "cooldowns":
+			if in.IsNull() {
+				in.Skip()
+			} else {
+				in.Delim('{')
+				out.Cooldowns = make(map[ItemType]time.Time)
+				for !in.IsDelim('}') {
+					key := ItemType(in.String())
+					in.WantColon()
+					var v13 time.Time
+					if data := in.Raw(); in.Ok() {
+						in.AddError((v13).UnmarshalJSON(data))
+					}
+					(out.Cooldowns)[key] = v13
+					in.WantComma()
+				}
+				in.Delim('}')
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer1(out *jwriter.Writer, in Condition) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"hp\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.HP))
+	}
+	{
+		const prefix string = ",\"mana\":"
+		out.RawString(prefix)
+		out.Int(int(in.Mana))
+	}
+	{
+		const prefix string = ",\"max_mana\":"
+		out.RawString(prefix)
+		out.Int(int(in.MaxMana))
+	}
+	{
+		const prefix string = ",\"effects\":"
+		out.RawString(prefix)
+		if in.Effects == nil && (out.Flags&jwriter.NilSliceAsEmpty) == 0 {
+			out.RawString("null")
+		} else {
+			out.RawByte('[')
+			for v14, v15 := range in.Effects {
+				if v14 > 0 {
+					out.RawByte(',')
+				}
+				easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer9(out, v15)
+			}
+			out.RawByte(']')
+		}
+	}
+	{
+		const prefix string = ",\"cooldowns\":"
+		out.RawString(prefix)
+		if in.Cooldowns == nil && (out.Flags&jwriter.NilMapAsEmpty) == 0 {
+			out.RawString(`null`)
+		} else {
+			out.RawByte('{')
+			v16First := true
+			for v16Name, v16Value := range in.Cooldowns {
+				if v16First {
+					v16First = false
+				} else {
+					out.RawByte(',')
+				}
+				out.String(string(v16Name))
+				out.RawByte(':')
+				out.Raw((v16Value).MarshalJSON())
+			}
+			out.RawByte('}')
+		}
+	}
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainTrainer9(in *jlexer.Lexer, out *StatusEffect) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "type":
+			out.Type = EffectType(in.String())
+		case "bonus":
+			easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared1(in, &out.Bonus)
+		case "source":
+			out.Source = ItemType(in.String())
+		case "applied_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.AppliedAt).UnmarshalJSON(data))
+			}
+		case "expires_at":
+			if data := in.Raw(); in.Ok() {
+				in.AddError((out.ExpiresAt).UnmarshalJSON(data))
+			}
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainTrainer9(out *jwriter.Writer, in StatusEffect) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"type\":"
+		out.RawString(prefix[1:])
+		out.String(string(in.Type))
+	}
+	{
+		const prefix string = ",\"bonus\":"
+		out.RawString(prefix)
+		easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared1(out, in.Bonus)
+	}
+	{
+		const prefix string = ",\"source\":"
+		out.RawString(prefix)
+		out.String(string(in.Source))
+	}
+	{
+		const prefix string = ",\"applied_at\":"
+		out.RawString(prefix)
+		out.Raw((in.AppliedAt).MarshalJSON())
+	}
+	{
+		const prefix string = ",\"expires_at\":"
+		out.RawString(prefix)
+		out.Raw((in.ExpiresAt).MarshalJSON())
+	}
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared1(in *jlexer.Lexer, out *shared.Stats) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		case "hp":
+			out.HP = int(in.Int())
+		case "atk":
+			out.ATK = int(in.Int())
+		case "def":
+			out.DEF = int(in.Int())
+		case "spd":
+			out.SPD = int(in.Int())
+		case "as":
+			out.AS = int(in.Int())
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared1(out *jwriter.Writer, in shared.Stats) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	{
+		const prefix string = ",\"hp\":"
+		out.RawString(prefix[1:])
+		out.Int(int(in.HP))
+	}
+	{
+		const prefix string = ",\"atk\":"
+		out.RawString(prefix)
+		out.Int(int(in.ATK))
+	}
+	{
+		const prefix string = ",\"def\":"
+		out.RawString(prefix)
+		out.Int(int(in.DEF))
+	}
+	{
+		const prefix string = ",\"spd\":"
+		out.RawString(prefix)
+		out.Int(int(in.SPD))
+	}
+	{
+		const prefix string = ",\"as\":"
+		out.RawString(prefix)
+		out.Int(int(in.AS))
+	}
+	out.RawByte('}')
+}
+func easyjson7c03a10dDecodeGithubComDanghamoLifeInternalDomainShared(in *jlexer.Lexer, out *shared.Experience) {
+	isTopLevel := in.IsStart()
+	if in.IsNull() {
+		if isTopLevel {
+			in.Consumed()
+		}
+		in.Skip()
+		return
+	}
+	in.Delim('{')
+	for !in.IsDelim('}') {
+		key := in.UnsafeFieldName(false)
+		in.WantColon()
+		if in.IsNull() {
+			in.Skip()
+			in.WantComma()
+			continue
+		}
+		switch key {
+		default:
+			in.SkipRecursive()
+		}
+		in.WantComma()
+	}
+	in.Delim('}')
+	if isTopLevel {
+		in.Consumed()
+	}
+}
+func easyjson7c03a10dEncodeGithubComDanghamoLifeInternalDomainShared(out *jwriter.Writer, in shared.Experience) {
+	out.RawByte('{')
+	first := true
+	_ = first
+	out.RawByte('}')
+}
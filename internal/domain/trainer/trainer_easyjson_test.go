@@ -0,0 +1,41 @@
+//go:build easyjson
+
+package trainer
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/jsonx"
+)
+
+// TestTrainer_EasyJSONMatchesStdlib checks the generated marshaler against encoding/json.
+// Object keys may come out in another order, so documents are compared as JSON values.
+func TestTrainer_EasyJSONMatchesStdlib(t *testing.T) {
+	tr, err := NewTrainer("user-1", "Tester <&>")
+	require.NoError(t, err)
+	for _, itemType := range []ItemType{HealthPotion, ManaPotion, BasicNet, AnimalHide, RareGem} {
+		item, err := NewItemStack(itemType, string(itemType), 5)
+		require.NoError(t, err)
+		require.NoError(t, tr.Inventory.AddItem(item))
+	}
+	require.NoError(t, tr.Party.AddAnimal(shared.ID("animal-1")))
+	require.NoError(t, tr.StartMovement(1, 0))
+	require.True(t, jsonx.Generated(tr))
+
+	want, err := json.Marshal(tr)
+	require.NoError(t, err)
+	got, err := jsonx.Marshal(tr)
+	require.NoError(t, err)
+	assert.JSONEq(t, string(want), string(got))
+
+	// Decoding stays with the hand-written UnmarshalJSON, which migrates old documents
+	var fromStdlib, fromJsonx Trainer
+	require.NoError(t, json.Unmarshal(got, &fromStdlib))
+	require.NoError(t, jsonx.Unmarshal(got, &fromJsonx))
+	assert.Equal(t, fromStdlib, fromJsonx)
+}
@@ -0,0 +1,666 @@
+package trainer
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/pkg/jsonx"
+)
+
+func TestInventory_Stacking(t *testing.T) {
+	t.Run("should fill existing stacks before using new slots", func(t *testing.T) {
+		inv := NewInventory(2)
+
+		for i := 0; i < 20; i++ {
+			item, err := NewItem(HealthPotion, "Health Potion")
+			require.NoError(t, err)
+			require.NoError(t, inv.AddItem(item))
+		}
+		assert.Equal(t, 1, inv.GetUsedSlots())
+		assert.Equal(t, 20, inv.CountItemsByType(HealthPotion))
+
+		item, err := NewItemStack(HealthPotion, "Health Potion", 5)
+		require.NoError(t, err)
+		require.NoError(t, inv.AddItem(item))
+		assert.Equal(t, 2, inv.GetUsedSlots())
+		assert.Equal(t, 25, inv.CountItemsByType(HealthPotion))
+	})
+
+	t.Run("should only stack items with the same name and binding", func(t *testing.T) {
+		inv := NewInventory(5)
+
+		potion, err := NewItemStack(HealthPotion, "Health Potion", 5)
+		require.NoError(t, err)
+		require.NoError(t, inv.AddItem(potion))
+
+		elixir, err := NewItemStack(HealthPotion, "Elixir", 5)
+		require.NoError(t, err)
+		require.NoError(t, inv.AddItem(elixir))
+
+		bound, err := NewItemStack(HealthPotion, "Health Potion", 5)
+		require.NoError(t, err)
+		bound.BindOnPickup = true
+		require.NoError(t, inv.AddItem(bound))
+
+		assert.Equal(t, 3, inv.GetUsedSlots())
+		assert.Equal(t, 5, potion.Quantity)
+		assert.Equal(t, "Elixir", elixir.Name)
+		assert.Equal(t, 5, elixir.Quantity)
+		assert.True(t, bound.Bound)
+		assert.False(t, potion.Bound)
+	})
+
+	t.Run("should add all or nothing", func(t *testing.T) {
+		inv := NewInventory(1)
+
+		item, err := NewItemStack(BasicNet, "Basic Net", 11)
+		require.NoError(t, err)
+		assert.Error(t, inv.AddItem(item))
+		assert.Equal(t, 0, inv.GetUsedSlots())
+	})
+
+	t.Run("should remove quantities across stacks", func(t *testing.T) {
+		inv := NewInventory(5)
+
+		item, err := NewItemStack(AnimalHide, "Animal Hide", 60)
+		require.NoError(t, err)
+		require.NoError(t, inv.AddItem(item))
+		assert.Equal(t, 2, inv.GetUsedSlots())
+
+		removed, err := inv.RemoveItemsByType(AnimalHide, 15)
+		require.NoError(t, err)
+
+		total := 0
+		for _, r := range removed {
+			total += r.Quantity
+		}
+		assert.Equal(t, 15, total)
+		assert.Equal(t, 45, inv.CountItemsByType(AnimalHide))
+
+		_, err = inv.RemoveItemsByType(AnimalHide, 46)
+		assert.Error(t, err)
+		assert.Equal(t, 45, inv.CountItemsByType(AnimalHide))
+	})
+
+	t.Run("should split a stack on partial removal", func(t *testing.T) {
+		inv := NewInventory(5)
+
+		item, err := NewItemStack(RareGem, "Rare Gem", 10)
+		require.NoError(t, err)
+		require.NoError(t, inv.AddItem(item))
+
+		portion, err := inv.RemoveQuantity(item.ID, 4)
+		require.NoError(t, err)
+		assert.Equal(t, 4, portion.Quantity)
+		assert.NotEqual(t, item.ID, portion.ID)
+		assert.Equal(t, 6, inv.CountItemsByType(RareGem))
+	})
+}
+
+func TestInventory_MigratesLegacyItems(t *testing.T) {
+	legacy := `{
+		"items": {
+			"a": {"id": "a", "type": "health_potion", "name": "Health Potion"},
+			"b": {"id": "b", "type": "health_potion", "name": "Health Potion"},
+			"c": {"id": "c", "type": "animal_hide", "name": "Animal Hide"}
+		},
+		"max_slots": 50
+	}`
+
+	var inv Inventory
+	require.NoError(t, json.Unmarshal([]byte(legacy), &inv))
+
+	assert.Equal(t, 2, inv.GetUsedSlots())
+	assert.Equal(t, 2, inv.CountItemsByType(HealthPotion))
+	assert.Equal(t, 1, inv.CountItemsByType(AnimalHide))
+}
+
+func TestInventoryQuery_Apply(t *testing.T) {
+	inv := NewInventory(10)
+	for _, stack := range []struct {
+		itemType ItemType
+		quantity int
+	}{
+		{AnimalHide, 30},
+		{RareGem, 5},
+		{HealthPotion, 12},
+		{MagicCrystal, 1},
+	} {
+		item, err := NewItemStack(stack.itemType, string(stack.itemType), stack.quantity)
+		require.NoError(t, err)
+		require.NoError(t, inv.AddItem(item))
+	}
+
+	q := InventoryQuery{Category: CategoryMaterial, SortBy: SortByQuantity, Descending: true, Limit: 2}
+	require.NoError(t, q.Validate())
+
+	page, total := q.Apply(inv.GetAllItems())
+	assert.Equal(t, 3, total)
+	require.Len(t, page, 2)
+	assert.Equal(t, AnimalHide, page[0].Type)
+	assert.Equal(t, RareGem, page[1].Type)
+
+	q.Offset = 2
+	page, _ = q.Apply(inv.GetAllItems())
+	require.Len(t, page, 1)
+	assert.Equal(t, MagicCrystal, page[0].Type)
+
+	assert.Error(t, (&InventoryQuery{SortBy: "price"}).Validate())
+}
+
+func TestTrainer_SellItems(t *testing.T) {
+	tr := &Trainer{Inventory: NewInventory(10)}
+
+	hide, err := NewItemStack(AnimalHide, "Animal Hide", 10)
+	require.NoError(t, err)
+	require.NoError(t, tr.Inventory.AddItem(hide))
+
+	_, _, err = tr.SellItems([]ItemID{hide.ID, "missing"})
+	assert.Error(t, err)
+	assert.Equal(t, 10, tr.Inventory.CountItemsByType(AnimalHide), "failed sale must not remove items")
+
+	sold, earned, err := tr.SellItems([]ItemID{hide.ID, hide.ID})
+	require.NoError(t, err)
+	assert.Len(t, sold, 1)
+	assert.Equal(t, 10*AnimalHide.SellPrice(), earned)
+	assert.Equal(t, earned, tr.Money.Amount())
+	assert.Equal(t, 0, tr.Inventory.GetUsedSlots())
+}
+
+func TestTrainer_DropItems(t *testing.T) {
+	tr := &Trainer{Inventory: NewInventory(10)}
+
+	hide, err := NewItemStack(AnimalHide, "Animal Hide", 10)
+	require.NoError(t, err)
+	require.NoError(t, tr.Inventory.AddItem(hide))
+
+	for _, drops := range [][]ItemDrop{
+		{{ItemID: hide.ID, Quantity: 3}, {ItemID: "missing"}},
+		{{ItemID: hide.ID, Quantity: 11}},
+		{{ItemID: hide.ID, Quantity: 1}, {ItemID: hide.ID, Quantity: 1}},
+	} {
+		_, err := tr.DropItems(drops)
+		assert.Error(t, err)
+		assert.Equal(t, 10, tr.Inventory.CountItemsByType(AnimalHide), "failed drop must not remove items")
+	}
+
+	dropped, err := tr.DropItems([]ItemDrop{{ItemID: hide.ID, Quantity: 3}})
+	require.NoError(t, err)
+	require.Len(t, dropped, 1)
+	assert.Equal(t, 3, dropped[0].Quantity)
+	assert.Equal(t, 7, tr.Inventory.CountItemsByType(AnimalHide))
+
+	dropped, err = tr.DropItems([]ItemDrop{{ItemID: hide.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, 7, dropped[0].Quantity)
+	assert.Equal(t, 0, tr.Inventory.GetUsedSlots())
+
+	crystal, err := NewItem(MagicCrystal, "Magic Crystal")
+	require.NoError(t, err)
+	require.NoError(t, tr.Inventory.AddItem(crystal))
+	_, err = tr.DropItems([]ItemDrop{{ItemID: crystal.ID}})
+	code, ok := shared.DomainErrorCode(err)
+	require.True(t, ok)
+	assert.Equal(t, shared.ErrCodeItemBound, code, "bound items can't be dropped")
+	assert.Equal(t, 1, tr.Inventory.GetUsedSlots())
+}
+
+func TestItem_Binding(t *testing.T) {
+	inv := NewInventory(5)
+
+	crystal, err := NewItemStack(MagicCrystal, "Magic Crystal", 3)
+	require.NoError(t, err)
+	assert.Equal(t, RarityEpic, crystal.Rarity)
+	assert.False(t, crystal.Bound)
+	require.NoError(t, inv.AddItem(crystal))
+	assert.True(t, crystal.Bound, "bind-on-pickup items bind when added")
+
+	gem, err := NewItem(RareGem, "Rare Gem")
+	require.NoError(t, err)
+	require.NoError(t, inv.AddItem(gem))
+
+	_, err = inv.TakeTransferable([]ItemID{gem.ID, crystal.ID})
+	assert.Error(t, err)
+	assert.Equal(t, 2, inv.GetUsedSlots(), "rejected transfer must not remove items")
+
+	taken, err := inv.TakeTransferable([]ItemID{gem.ID})
+	require.NoError(t, err)
+	assert.Len(t, taken, 1)
+
+	var restored Item
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"x","type":"rare_gem","name":"Gem","quantity":1}`), &restored))
+	assert.Equal(t, RarityRare, restored.Rarity)
+	assert.Equal(t, "item.rare_gem", restored.ContentID)
+}
+
+func TestTrainer_ConsumeItem(t *testing.T) {
+	tr, err := NewTrainer("user-1", "Tester")
+	require.NoError(t, err)
+	tr.Condition.HP = 20
+	tr.Condition.Mana = 0
+
+	potions, err := NewItemStack(HealthPotion, "Health Potion", 2)
+	require.NoError(t, err)
+	require.NoError(t, tr.Inventory.AddItem(potions))
+	mana, err := NewItem(ManaPotion, "Mana Potion")
+	require.NoError(t, err)
+	require.NoError(t, tr.Inventory.AddItem(mana))
+
+	now := time.Now()
+	_, effect, err := tr.ConsumeItem(potions.ID, now)
+	require.NoError(t, err)
+	_, err = DispatchEffect(effect, HealthPotion, tr, now)
+	require.NoError(t, err)
+	assert.Equal(t, 70, tr.Condition.HP)
+	assert.Equal(t, 1, tr.Inventory.CountItemsByType(HealthPotion))
+
+	_, _, err = tr.ConsumeItem(potions.ID, now.Add(time.Second))
+	assert.Error(t, err, "potion should be on cooldown")
+
+	_, effect, err = tr.ConsumeItem(mana.ID, now)
+	require.NoError(t, err)
+	outcome, err := DispatchEffect(effect, ManaPotion, tr, now)
+	require.NoError(t, err)
+	require.NotNil(t, outcome.Effect)
+	assert.Equal(t, 30, tr.Condition.Mana)
+	assert.Equal(t, tr.Stats.AS+3, tr.EffectiveStats(now).AS)
+	assert.Equal(t, tr.Stats.AS, tr.EffectiveStats(now.Add(2*time.Minute)).AS)
+
+	hide, err := NewItem(AnimalHide, "Animal Hide")
+	require.NoError(t, err)
+	require.NoError(t, tr.Inventory.AddItem(hide))
+	_, _, err = tr.ConsumeItem(hide.ID, now)
+	assert.Error(t, err)
+}
+
+func TestTrainer_PublicProfile(t *testing.T) {
+	tr, err := NewTrainer("user-1", "Tester")
+	require.NoError(t, err)
+
+	lion := ShowcasedAnimal{ID: "a1", Type: "lion", Level: 3}
+	profile := tr.PublicProfile([]ShowcasedAnimal{lion}, 2)
+	require.NotNil(t, profile.Level)
+	assert.Equal(t, 2, *profile.AnimalsOwned)
+	assert.Equal(t, []ShowcasedAnimal{lion}, profile.Showcase)
+
+	err = tr.UpdateProfileSettings([]ProfileField{ProfileFieldAnimals, ProfileFieldAnimals}, []ShowcasedAnimal{lion, lion})
+	require.NoError(t, err)
+	assert.Equal(t, []ProfileField{ProfileFieldAnimals}, tr.Profile.HiddenFields)
+	assert.Equal(t, []ShowcasedAnimal{lion}, tr.Profile.Showcase)
+	assert.Nil(t, tr.NameplateShowcase(), "hidden animals stay off the nameplate")
+
+	profile = tr.PublicProfile([]ShowcasedAnimal{lion}, 2)
+	assert.NotNil(t, profile.Level)
+	assert.Nil(t, profile.AnimalsOwned)
+	assert.Empty(t, profile.Showcase)
+
+	assert.Error(t, tr.UpdateProfileSettings([]ProfileField{"guild"}, nil))
+}
+
+func TestTrainer_PinAnimal(t *testing.T) {
+	tr, err := NewTrainer("user-1", "Tester")
+	require.NoError(t, err)
+
+	for _, id := range []string{"a1", "a2", "a3"} {
+		require.NoError(t, tr.PinAnimal(ShowcasedAnimal{ID: id, Type: "lion", Level: 1}))
+	}
+	assert.Error(t, tr.PinAnimal(ShowcasedAnimal{ID: "a4", Type: "lion", Level: 1}), "showcase is full")
+
+	// Pinning again refreshes the snapshot in place
+	require.NoError(t, tr.PinAnimal(ShowcasedAnimal{ID: "a2", Type: "lion", Level: 5}))
+	assert.Equal(t, 5, tr.NameplateShowcase()[1].Level)
+
+	assert.True(t, tr.UnpinAnimal("a1"))
+	assert.False(t, tr.UnpinAnimal("a1"))
+	assert.Len(t, tr.NameplateShowcase(), 2)
+	assert.Equal(t, "a2", tr.NameplateShowcase()[0].ID)
+}
+
+func TestTrainer_Recolor(t *testing.T) {
+	tr, err := NewTrainer("user-1", "Tester")
+	require.NoError(t, err)
+	standard := tr.Color
+
+	changed, err := tr.Recolor(HighContrastPalette)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Contains(t, HighContrast, tr.Color)
+
+	// A color the player picked from the palette is kept
+	require.NoError(t, tr.SetColor(HighContrast[0]))
+	changed, err = tr.Recolor(HighContrastPalette)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Equal(t, HighContrast[0], tr.Color)
+
+	changed, err = tr.Recolor(StandardPalette)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, standard, tr.Color, "back to the color the trainer was created with")
+
+	_, err = tr.Recolor("neon")
+	assert.Error(t, err)
+}
+
+func TestTrainer_UseCaptureNet(t *testing.T) {
+	tr, err := NewTrainer("user-1", "Tester")
+	require.NoError(t, err)
+
+	nets, err := NewItemStack(AdvancedNet, "Advanced Net", 2)
+	require.NoError(t, err)
+	require.NoError(t, tr.Inventory.AddItem(nets))
+	potion, err := NewItem(HealthPotion, "Health Potion")
+	require.NoError(t, err)
+	require.NoError(t, tr.Inventory.AddItem(potion))
+
+	standing := tr.Movement.CalculateCurrentPosition()
+	used, net, err := tr.UseCaptureNet(nets.ID, standing)
+	require.NoError(t, err)
+	assert.Equal(t, 1, used.Quantity)
+	assert.Equal(t, 1.5, net.Effectiveness)
+	assert.Equal(t, 1, tr.Inventory.CountItemsByType(AdvancedNet))
+
+	_, _, err = tr.UseCaptureNet(potion.ID, standing)
+	assert.Error(t, err, "potions are not capture nets")
+
+	_, _, err = tr.UseCaptureNet(nets.ID, shared.NewPosition(standing.X+CaptureRange+1, standing.Y))
+	code, ok := shared.DomainErrorCode(err)
+	require.True(t, ok)
+	assert.Equal(t, shared.ErrCodeOutOfRange, code)
+	assert.Equal(t, 1, tr.Inventory.CountItemsByType(AdvancedNet), "nets aren't thrown out of range")
+}
+
+func TestTrainer_InCaptureRange(t *testing.T) {
+	tr, err := NewTrainer("user-1", "Tester")
+	require.NoError(t, err)
+	tr.Movement.StopMovement(shared.NewPosition(10, 10))
+
+	assert.True(t, tr.InCaptureRange(shared.NewPosition(10, 10)))
+	assert.True(t, tr.InCaptureRange(shared.NewPosition(13, 14)), "exactly CaptureRange away")
+	assert.True(t, tr.InCaptureRange(shared.NewPosition(14, 12)), "beyond the squared distance of CaptureRange")
+	assert.False(t, tr.InCaptureRange(shared.NewPosition(13, 14.1)))
+
+	// Moving trainers are judged where they are now, not where their movement started
+	tr.Movement.StartMovement(MovementDirection{X: 1}, shared.NewPosition(10, 10))
+	tr.Movement.StartTime = time.Now().Add(-10 * time.Second)
+	assert.False(t, tr.InCaptureRange(shared.NewPosition(10, 10)))
+}
+
+func TestAnimalParty_JSON(t *testing.T) {
+	tr, err := NewTrainer("user-1", "Tester")
+	require.NoError(t, err)
+	require.NoError(t, tr.AddAnimalToParty("a1"))
+
+	data, err := json.Marshal(tr)
+	require.NoError(t, err)
+
+	var restored Trainer
+	require.NoError(t, json.Unmarshal(data, &restored))
+	assert.True(t, restored.Party.Contains("a1"))
+	assert.False(t, restored.Party.IsFull())
+
+	var legacy AnimalParty
+	require.NoError(t, json.Unmarshal([]byte(`{}`), &legacy))
+	assert.Equal(t, 0, legacy.Size())
+	assert.False(t, legacy.IsFull(), "parties stored as empty objects get the default size")
+}
+
+func TestAnimalParty_SwapAnimal(t *testing.T) {
+	party := NewAnimalParty(2)
+	require.NoError(t, party.AddAnimal("a1"))
+	require.NoError(t, party.AddAnimal("a2"))
+
+	require.NoError(t, party.SwapAnimal("a1", "a3"))
+	assert.Equal(t, []shared.ID{"a3", "a2"}, party.GetAnimals(), "the swapped in animal takes the slot")
+
+	err := party.SwapAnimal("a1", "a4")
+	code, ok := shared.DomainErrorCode(err)
+	require.True(t, ok)
+	assert.Equal(t, shared.ErrCodeAnimalNotInParty, code)
+
+	err = party.SwapAnimal("a3", "a2")
+	code, _ = shared.DomainErrorCode(err)
+	assert.Equal(t, shared.ErrCodeAnimalAlreadyInParty, code)
+	assert.Equal(t, []shared.ID{"a3", "a2"}, party.GetAnimals())
+}
+
+// wallTerrain blocks everything at or beyond x = 20
+type wallTerrain struct{}
+
+func (wallTerrain) IsWalkablePosition(position shared.Position) bool {
+	return position.X < 20
+}
+
+func TestTrainer_MovementWithinTerrain(t *testing.T) {
+	tr, err := NewTrainer("user-1", "Tester")
+	require.NoError(t, err)
+	tr.Position = shared.NewPosition(18, 10)
+	tr.Movement.StopMovement(tr.Position)
+
+	require.NoError(t, tr.StartMovementWithin(1, 0, wallTerrain{}))
+	tr.Movement.StartTime = time.Now().Add(-2 * time.Second)
+
+	assert.True(t, tr.UpdatePositionWithin(wallTerrain{}), "the path runs into the wall")
+	assert.False(t, tr.Movement.IsMoving)
+	assert.Less(t, tr.Position.X, 20.0)
+	assert.Greater(t, tr.Position.X, 19.8)
+
+	err = tr.StartMovementWithin(1, 0, wallTerrain{})
+	assert.Error(t, err, "moving into the wall is rejected")
+	require.NoError(t, tr.StartMovementWithin(-1, 0, wallTerrain{}))
+	assert.True(t, tr.Movement.IsMoving)
+}
+
+func TestTrainer_DeathAndRespawn(t *testing.T) {
+	tr, err := NewTrainer("user-1", "Tester")
+	require.NoError(t, err)
+	tr.Position = shared.NewPosition(5, 5)
+	tr.Movement.StopMovement(tr.Position)
+	tr.Condition.HP = 10
+
+	died, err := tr.TakeDamage(0)
+	require.NoError(t, err)
+	assert.False(t, died)
+	assert.Equal(t, 9, tr.Condition.HP, "every hit takes at least one HP")
+
+	died, err = tr.TakeDamage(1000)
+	require.NoError(t, err)
+	require.True(t, died)
+	assert.Zero(t, tr.Condition.HP)
+
+	now := time.Now()
+	death := tr.Die(DeathByAnimal, "lion-1", 100, 10*time.Second, now)
+	assert.True(t, tr.IsDead())
+	assert.Equal(t, 100, death.MoneyLost)
+	assert.Equal(t, 900, tr.Money.Amount())
+	assert.Equal(t, now.Add(10*time.Second), death.RespawnAt)
+
+	_, err = tr.TakeDamage(10)
+	assert.Error(t, err, "the dead take no damage")
+	assert.Error(t, tr.StartMovementWithin(1, 0, wallTerrain{}), "the dead cannot move")
+	assert.Error(t, tr.Heal(10))
+
+	spawn := shared.NewPosition(15, 10)
+	assert.Error(t, tr.Respawn(spawn, now), "respawning waits for the delay")
+	require.NoError(t, tr.Respawn(spawn, now.Add(10*time.Second)))
+	assert.False(t, tr.IsDead())
+	assert.Equal(t, spawn, tr.Position)
+	assert.Equal(t, tr.Stats.HP, tr.Condition.HP)
+	assert.Error(t, tr.Respawn(spawn, now), "only the dead respawn")
+
+	// The penalty never takes more than the trainer carries
+	tr.Money, _ = shared.NewMoney(30)
+	death = tr.Die(DeathByBullet, "", 100, time.Second, now)
+	assert.Equal(t, 30, death.MoneyLost)
+	assert.Zero(t, tr.Money.Amount())
+}
+
+func TestTrainer_MoveAlong(t *testing.T) {
+	tr, err := NewTrainer("user-1", "Tester")
+	require.NoError(t, err)
+	tr.Position = shared.NewPosition(2.5, 2.5)
+	tr.Movement.StopMovement(tr.Position)
+
+	path := []shared.Position{{X: 2.5, Y: 2.5}, {X: 3.5, Y: 2.5}, {X: 4.5, Y: 2.5}, {X: 5.5, Y: 3.5}, {X: 6.5, Y: 4.5}}
+	require.NoError(t, tr.MoveAlong(path, wallTerrain{}))
+	assert.Equal(t, []shared.Position{{X: 4.5, Y: 2.5}, {X: 6.5, Y: 4.5}}, tr.Movement.Waypoints, "only turns are kept")
+	assert.Equal(t, MovementDirection{X: 1, Y: 0}, tr.Movement.Direction)
+
+	// Halfway through the second leg the trainer has turned diagonally, as of reaching the turn
+	started := tr.Movement.StartTime
+	assert.False(t, tr.Movement.AdvanceWaypoints(started.Add(600*time.Millisecond)))
+	assert.Equal(t, MovementDirection{X: 1, Y: 1}, tr.Movement.Direction)
+	assert.Equal(t, shared.NewPosition(4.5, 2.5), tr.Movement.StartPos)
+	assert.Equal(t, started.Add(400*time.Millisecond), tr.Movement.StartTime)
+
+	// Past the last waypoint it stops there
+	assert.True(t, tr.Movement.AdvanceWaypoints(started.Add(time.Second)))
+	assert.False(t, tr.Movement.IsMoving)
+	assert.Equal(t, shared.NewPosition(6.5, 4.5), tr.Movement.StartPos)
+	assert.Empty(t, tr.Movement.Waypoints)
+
+	// A direction command replaces the walk
+	require.NoError(t, tr.MoveAlong(path[3:], wallTerrain{}))
+	require.NoError(t, tr.StartMovementWithin(0, 1, wallTerrain{}))
+	assert.Empty(t, tr.Movement.Waypoints)
+
+	assert.Error(t, tr.MoveAlong(nil, wallTerrain{}))
+}
+
+// benchmarkTrainer returns a trainer with a moderately filled inventory, as sent in move changes
+func benchmarkTrainer(b *testing.B) *Trainer {
+	tr, err := NewTrainer("user-1", "Tester")
+	require.NoError(b, err)
+
+	for _, itemType := range []ItemType{HealthPotion, ManaPotion, BasicNet, AnimalHide, RareGem} {
+		item, err := NewItemStack(itemType, string(itemType), 5)
+		require.NoError(b, err)
+		require.NoError(b, tr.Inventory.AddItem(item))
+	}
+	require.NoError(b, tr.StartMovement(1, 0))
+	return tr
+}
+
+func BenchmarkTrainer_Marshal(b *testing.B) {
+	tr := benchmarkTrainer(b)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := jsonx.Marshal(tr); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkTrainer_Unmarshal(b *testing.B) {
+	data, err := json.Marshal(benchmarkTrainer(b))
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var tr Trainer
+		if err := json.Unmarshal(data, &tr); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMovementRecord_HashFields(t *testing.T) {
+	tr, err := NewTrainer("user-1", "Tester")
+	require.NoError(t, err)
+	require.NoError(t, tr.PinAnimal(ShowcasedAnimal{ID: "a1", Type: "lion", Level: 3}))
+	require.NoError(t, tr.StartMovement(1, -1))
+
+	record := tr.MovementRecord()
+	record.Movement.Waypoints = []shared.Position{{X: 4.5, Y: 2.5}, {X: 6.5, Y: 4.5}}
+	record.SavedAt = time.Now()
+	fields, err := record.HashFields()
+	require.NoError(t, err)
+
+	stored := make(map[string]string, len(fields))
+	for field, value := range fields {
+		stored[field] = value.(string)
+	}
+	parsed, err := ParseMovementRecord("user-1", stored)
+	require.NoError(t, err)
+	assert.Equal(t, record.Position, parsed.Position)
+	assert.Equal(t, record.Movement.Direction, parsed.Movement.Direction)
+	assert.True(t, record.Movement.StartTime.Equal(parsed.Movement.StartTime))
+	assert.True(t, record.SavedAt.Equal(parsed.SavedAt))
+	assert.True(t, parsed.Movement.IsMoving)
+	assert.Equal(t, record.Movement.Waypoints, parsed.Movement.Waypoints)
+	assert.Equal(t, tr.Color, parsed.Color)
+	assert.Equal(t, tr.NameplateShowcase(), parsed.Showcase)
+
+	_, err = ParseMovementRecord("user-1", map[string]string{"color": tr.Color})
+	assert.Error(t, err)
+}
+
+func TestNewListQuery(t *testing.T) {
+	query, err := NewListQuery("", "", "", 0)
+	require.NoError(t, err)
+	assert.Equal(t, ListByNickname, query.Sort)
+	assert.False(t, query.Descending)
+	assert.Equal(t, DefaultListLimit, query.Limit)
+
+	query, err = NewListQuery("level", "desc", "", 10)
+	require.NoError(t, err)
+	assert.Equal(t, ListByLevel, query.Sort)
+	assert.True(t, query.Descending)
+
+	for name, args := range map[string]struct {
+		sort, order, cursor string
+		limit               int
+	}{
+		"unknown sort":       {sort: "experience"},
+		"unknown order":      {order: "up"},
+		"limit over max":     {limit: MaxListLimit + 1},
+		"negative limit":     {limit: -1},
+		"undecodable cursor": {cursor: "%%%"},
+		"foreign cursor":     {cursor: "bm90LWEtbWVtYmVy"},
+	} {
+		_, err := NewListQuery(args.sort, args.order, args.cursor, args.limit)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestPositionHistory(t *testing.T) {
+	var history PositionHistory
+	start := time.Now()
+
+	_, ok := history.At(start)
+	assert.False(t, ok, "nothing recorded yet")
+
+	history.Record(start, shared.NewPosition(0, 0))
+	history.Record(start.Add(100*time.Millisecond), shared.NewPosition(10, 0))
+	history.Record(start.Add(50*time.Millisecond), shared.NewPosition(99, 99)) // Out of order
+
+	position, ok := history.At(start.Add(25 * time.Millisecond))
+	require.True(t, ok)
+	assert.InDelta(t, 2.5, position.X, 1e-9)
+
+	// Trainers stay where they were last sampled
+	position, ok = history.At(start.Add(time.Second))
+	require.True(t, ok)
+	assert.Equal(t, shared.NewPosition(10, 0), position)
+
+	// Trainers stood where they were first sampled
+	position, ok = history.At(start.Add(-time.Second))
+	require.True(t, ok)
+	assert.Equal(t, shared.NewPosition(0, 0), position)
+
+	// A full buffer forgets the oldest samples
+	for i := 0; i < positionHistorySize; i++ {
+		history.Record(start.Add(time.Second+time.Duration(i)*time.Millisecond), shared.NewPosition(float64(i), 0))
+	}
+	_, ok = history.At(start.Add(100 * time.Millisecond))
+	assert.False(t, ok, "older than the samples kept")
+	position, ok = history.At(start.Add(time.Second + 10*time.Millisecond))
+	require.True(t, ok)
+	assert.Equal(t, shared.NewPosition(10, 0), position)
+}
@@ -0,0 +1,60 @@
+package tutorial
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// RedisRepository implements Repository with a JSON value per trainer
+type RedisRepository struct {
+	client *redis.Client
+	docs   *redisx.Repository[Progress]
+}
+
+// NewRedisRepository creates a new Redis-based tutorial progress repository
+func NewRedisRepository(client *redis.Client) Repository {
+	return &RedisRepository{
+		client: client,
+		docs: redisx.NewRepository(client, "tutorial:", redisx.Options[Progress]{
+			Codec:         redisx.StringCodec[Progress]{},
+			NotFound:      func() error { return shared.ErrNotFound("Tutorial progress") },
+			AlreadyExists: func() error { return shared.ErrAlreadyExists("Tutorial progress") },
+		}),
+	}
+}
+
+// Get retrieves a trainer's progress
+func (r *RedisRepository) Get(ctx context.Context, userID trainer.UserID) (*Progress, error) {
+	return r.docs.GetByID(ctx, userID.String())
+}
+
+// Insert stores new progress unless the trainer has some already
+func (r *RedisRepository) Insert(ctx context.Context, progress *Progress) error {
+	return r.docs.FindOneAndInsert(ctx, progress.UserID.String(), func() (*Progress, error) {
+		return progress, nil
+	})
+}
+
+// FindOneAndUpdate implements IoC pattern for update operations
+func (r *RedisRepository) FindOneAndUpdate(ctx context.Context, userID trainer.UserID, callback func(*Progress) error) error {
+	return r.docs.FindOneAndUpdate(ctx, userID.String(), func(progress *Progress) (*Progress, error) {
+		if err := callback(progress); err != nil {
+			return nil, err
+		}
+		return progress, nil
+	})
+}
+
+// DeleteUser removes a trainer's progress
+func (r *RedisRepository) DeleteUser(ctx context.Context, userID trainer.UserID) error {
+	if err := r.client.Del(ctx, r.docs.Key(userID.String())).Err(); err != nil {
+		return fmt.Errorf("failed to delete tutorial progress: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package tutorial
+
+import (
+	"context"
+
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// Repository defines the interface for tutorial progress persistence operations with IoC pattern
+type Repository interface {
+	// Get retrieves a trainer's progress, or nil if they never started the tutorial
+	Get(ctx context.Context, userID trainer.UserID) (*Progress, error)
+
+	// Insert stores the progress of a trainer starting the tutorial, failing with
+	// ErrCodeAlreadyExists if they started it before
+	Insert(ctx context.Context, progress *Progress) error
+
+	// FindOneAndUpdate loads a trainer's progress and applies callback for atomic update,
+	// failing with ErrCodeNotFound if they never started the tutorial
+	FindOneAndUpdate(ctx context.Context, userID trainer.UserID, callback func(*Progress) error) error
+
+	// DeleteUser removes a trainer's progress
+	DeleteUser(ctx context.Context, userID trainer.UserID) error
+}
@@ -0,0 +1,177 @@
+package tutorial
+
+import (
+	"math"
+
+	"github.com/danghamo/life/internal/domain/bullet"
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// RoomType is the kind of a room, a private space laid over the world for one player
+type RoomType string
+
+const (
+	// PracticeRange is a room with stationary targets to practice moving and firing on
+	PracticeRange RoomType = "practice_range"
+)
+
+// String returns string representation
+func (rt RoomType) String() string {
+	return string(rt)
+}
+
+const (
+	TargetCount    = 3
+	TargetHP       = 2    // Hits that knock a target down
+	TargetRadius   = 0.75 // How close to its center a bullet must pass to hit a target
+	TargetSpacing  = 3.0  // Distance between neighbouring targets
+	TargetDistance = 8.0  // Distance from the marker to the row of targets
+	MarkerDistance = 4.0  // Distance from where the range opened to its marker
+	MarkerRadius   = 1.5  // How close to the marker counts as having reached it
+
+	// BotName is what the tutorial coach is called
+	BotName = "Coach"
+)
+
+// script is what the coach says at the start of each step
+var script = map[Step]string{
+	StepMove:     "Welcome! Walk over to the flag and stop on it.",
+	StepFire:     "Nice. Now aim at the targets ahead and fire until all of them are down.",
+	StepCapture:  "Great shooting! Here is a net. Find the animal nearby and throw it to catch it.",
+	StepComplete: "That's everything. The world is yours, good luck out there!",
+}
+
+// Line returns what the coach says at the start of a step
+func Line(step Step) string {
+	return script[step]
+}
+
+// Bot is the coach walking the player through the tutorial. It is scripted: at each step it
+// goes to where the step happens and says the step's line.
+type Bot struct {
+	Name     string          `json:"name"`
+	Position shared.Position `json:"position"`
+	Line     string          `json:"line"`
+}
+
+// Target is a stationary dummy of a practice range
+type Target struct {
+	ID       shared.ID       `json:"id"`
+	Position shared.Position `json:"position"`
+	HP       int             `json:"hp"`
+}
+
+// IsDown checks if the target has been knocked down
+func (t *Target) IsDown() bool {
+	return t.HP <= 0
+}
+
+// Room is a private space of a player's own laid over the world around them. Its targets and
+// bot exist only for its owner, so practicing doesn't get in other players' way.
+type Room struct {
+	ID        shared.ID       `json:"id"`
+	Type      RoomType        `json:"type"`
+	Origin    shared.Position `json:"origin"`
+	Direction shared.Position `json:"direction"` // Unit vector from the origin towards the targets
+	Marker    shared.Position `json:"marker"`
+	Targets   []*Target       `json:"targets"`
+	Bot       Bot             `json:"bot"`
+	AnimalID  string          `json:"animal_id,omitempty"` // Animal placed for the capture step
+}
+
+// NewPracticeRange lays out a practice range from origin along direction: the marker a few
+// tiles away and a row of targets further on, so players fire the way they walked
+func NewPracticeRange(origin, direction shared.Position) *Room {
+	room := &Room{
+		ID:        shared.NewID(),
+		Type:      PracticeRange,
+		Origin:    origin,
+		Direction: direction,
+		Marker:    rangePoint(origin, direction, MarkerDistance, 0),
+		Targets:   make([]*Target, 0, TargetCount),
+		Bot:       Bot{Name: BotName, Position: origin},
+	}
+
+	for i := 0; i < TargetCount; i++ {
+		offset := (float64(i) - float64(TargetCount-1)/2) * TargetSpacing
+		room.Targets = append(room.Targets, &Target{
+			ID:       shared.NewID(),
+			Position: rangePoint(origin, direction, MarkerDistance+TargetDistance, offset),
+			HP:       TargetHP,
+		})
+	}
+	return room
+}
+
+// AtMarker checks if a position is close enough to the marker
+func (r *Room) AtMarker(position shared.Position) bool {
+	return position.WithinRange(r.Marker, MarkerRadius)
+}
+
+// Standing returns how many targets haven't been knocked down
+func (r *Room) Standing() int {
+	standing := 0
+	for _, target := range r.Targets {
+		if !target.IsDown() {
+			standing++
+		}
+	}
+	return standing
+}
+
+// Hit finds the nearest standing target in a bullet's path within its range and takes a hit
+// point off it, returning nil if the bullet misses them all. Tolerance widens the targets
+// for bullets of players with aim assistance.
+func (r *Room) Hit(fired *bullet.Bullet, tolerance float64) *Target {
+	var hit *Target
+	nearest := math.Inf(1)
+	for _, target := range r.Targets {
+		if target.IsDown() {
+			continue
+		}
+
+		// Distance along the path to the point closest to the target, then off the path
+		dx := target.Position.X - fired.StartPos.X
+		dy := target.Position.Y - fired.StartPos.Y
+		along := dx*fired.Velocity.Direction.X + dy*fired.Velocity.Direction.Y
+		if along < 0 || along > fired.MaxRange {
+			continue
+		}
+		across := math.Abs(dx*fired.Velocity.Direction.Y - dy*fired.Velocity.Direction.X)
+		if across <= TargetRadius+tolerance && along < nearest {
+			hit, nearest = target, along
+		}
+	}
+
+	if hit != nil {
+		hit.HP--
+	}
+	return hit
+}
+
+// AnimalSpot returns where an animal is placed for the capture step, across from the bot
+func (r *Room) AnimalSpot() shared.Position {
+	return rangePoint(r.Origin, r.Direction, 0, -2)
+}
+
+// coach sends the bot to where a step happens and has it say the step's line
+func (r *Room) coach(step Step) {
+	switch step {
+	case StepMove:
+		r.Bot.Position = rangePoint(r.Origin, r.Direction, MarkerDistance, 1)
+	case StepFire:
+		r.Bot.Position = rangePoint(r.Origin, r.Direction, MarkerDistance-1, -1)
+	case StepCapture:
+		r.Bot.Position = rangePoint(r.Origin, r.Direction, 0, 1)
+	}
+	r.Bot.Line = Line(step)
+}
+
+// rangePoint returns the point a distance along the range from origin and across it to the
+// side
+func rangePoint(origin, direction shared.Position, along, across float64) shared.Position {
+	return shared.NewPosition(
+		origin.X+direction.X*along-direction.Y*across,
+		origin.Y+direction.Y*along+direction.X*across,
+	)
+}
@@ -0,0 +1,129 @@
+package tutorial
+
+import (
+	"time"
+
+	"github.com/danghamo/life/internal/domain/bullet"
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// Step is a stage of the onboarding tutorial
+type Step string
+
+const (
+	StepMove     Step = "move"     // Walk to the marker of the practice range
+	StepFire     Step = "fire"     // Knock down the practice targets
+	StepCapture  Step = "capture"  // Catch a wild animal
+	StepComplete Step = "complete" // Tutorial finished or skipped
+)
+
+// String returns string representation
+func (s Step) String() string {
+	return string(s)
+}
+
+// Next returns the step players go on to after this one
+func (s Step) Next() Step {
+	switch s {
+	case StepMove:
+		return StepFire
+	case StepFire:
+		return StepCapture
+	default:
+		return StepComplete
+	}
+}
+
+// Progress is where a trainer is in the onboarding tutorial. It moves through the steps in
+// order, each ending when the player does what it teaches; the practice range is open until
+// the last step ends.
+type Progress struct {
+	UserID        trainer.UserID `json:"user_id"`
+	Step          Step           `json:"step"`
+	Room          *Room          `json:"room,omitempty"`
+	StartedAt     time.Time      `json:"started_at"`
+	StepStartedAt time.Time      `json:"step_started_at"`
+	CompletedAt   *time.Time     `json:"completed_at,omitempty"`
+	Skipped       bool           `json:"skipped,omitempty"`
+}
+
+// NewProgress starts a trainer on the first step in a practice range
+func NewProgress(userID trainer.UserID, room *Room, now time.Time) *Progress {
+	room.coach(StepMove)
+	return &Progress{
+		UserID:        userID,
+		Step:          StepMove,
+		Room:          room,
+		StartedAt:     now,
+		StepStartedAt: now,
+	}
+}
+
+// IsComplete checks if the tutorial is over, finished or skipped
+func (p *Progress) IsComplete() bool {
+	return p.Step == StepComplete
+}
+
+// Stopped records the trainer coming to a stop. During the move step, stopping at the marker
+// ends the step. It reports whether the step ended.
+func (p *Progress) Stopped(position shared.Position, now time.Time) bool {
+	if p.Step != StepMove || !p.Room.AtMarker(position) {
+		return false
+	}
+	p.advance(now)
+	return true
+}
+
+// Fired records a bullet the trainer fired. During the fire step it hits the first standing
+// target in its path, passing up to tolerance wider of targets for players with aim
+// assistance; knocking the last one down ends the step. It returns the target hit, if any,
+// and whether the step ended.
+func (p *Progress) Fired(fired *bullet.Bullet, tolerance float64, now time.Time) (*Target, bool) {
+	if p.Step != StepFire {
+		return nil, false
+	}
+
+	target := p.Room.Hit(fired, tolerance)
+	if target == nil || p.Room.Standing() > 0 {
+		return target, false
+	}
+	p.advance(now)
+	return target, true
+}
+
+// Captured records a net the trainer threw. During the capture step, catching any wild
+// animal ends the step and the tutorial. It reports whether the step ended.
+func (p *Progress) Captured(success bool, now time.Time) bool {
+	if p.Step != StepCapture || !success {
+		return false
+	}
+	p.advance(now)
+	return true
+}
+
+// Skip ends the tutorial without going through the remaining steps
+func (p *Progress) Skip(now time.Time) error {
+	if p.IsComplete() {
+		return shared.NewDomainError(shared.ErrCodeInvalidOperation, "Tutorial is already over")
+	}
+	p.Skipped = true
+	p.Step = StepComplete
+	p.StepStartedAt = now
+	p.CompletedAt = &now
+	p.Room = nil
+	return nil
+}
+
+// advance moves on to the next step and has the coach explain it, closing the practice
+// range once the tutorial is over
+func (p *Progress) advance(now time.Time) {
+	p.Step = p.Step.Next()
+	p.StepStartedAt = now
+	if p.IsComplete() {
+		p.CompletedAt = &now
+		p.Room = nil
+		return
+	}
+	p.Room.coach(p.Step)
+}
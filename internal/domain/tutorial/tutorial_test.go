@@ -0,0 +1,80 @@
+package tutorial
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/bullet"
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func fire(t *testing.T, from shared.Position, x, y float64) *bullet.Bullet {
+	t.Helper()
+	fired, err := bullet.NewBullet("user", bullet.BasicPistol, from, bullet.NewDirection(x, y))
+	require.NoError(t, err)
+	return fired
+}
+
+func TestProgress_Steps(t *testing.T) {
+	now := time.Now()
+	room := NewPracticeRange(shared.NewPosition(10, 10), shared.NewPosition(1, 0))
+	progress := NewProgress("user", room, now)
+	assert.Equal(t, StepMove, progress.Step)
+	assert.Equal(t, Line(StepMove), room.Bot.Line)
+
+	// Firing and catching count for nothing before their steps
+	_, advanced := progress.Fired(fire(t, room.Origin, 1, 0), 0, now)
+	assert.False(t, advanced)
+	assert.False(t, progress.Captured(true, now))
+
+	assert.False(t, progress.Stopped(room.Origin, now))
+	assert.True(t, progress.Stopped(shared.NewPosition(14.5, 10.5), now))
+	assert.Equal(t, StepFire, progress.Step)
+	assert.Equal(t, Line(StepFire), room.Bot.Line)
+
+	// Wide of the row, then through the middle target until it's down
+	target, _ := progress.Fired(fire(t, room.Marker, 0, 1), 0, now)
+	assert.Nil(t, target)
+	for i := 0; i < TargetHP; i++ {
+		target, advanced = progress.Fired(fire(t, room.Marker, 1, 0), 0, now)
+		require.NotNil(t, target)
+		assert.Equal(t, shared.NewPosition(22, 10), target.Position)
+		assert.False(t, advanced)
+	}
+	assert.Nil(t, room.Hit(fire(t, room.Marker, 1, 0), 0), "targets that are down don't stop bullets")
+	assert.Equal(t, TargetCount-1, room.Standing())
+
+	// Passing just wide of a target only hits with aim assistance
+	wide := shared.NewPosition(room.Marker.X, room.Targets[0].Position.Y+TargetRadius+0.2)
+	assert.Nil(t, room.Hit(fire(t, wide, 1, 0), 0))
+	assert.NotNil(t, room.Hit(fire(t, wide, 1, 0), 0.25))
+
+	for _, standing := range room.Targets {
+		for !standing.IsDown() {
+			from := shared.NewPosition(room.Marker.X, standing.Position.Y)
+			_, advanced = progress.Fired(fire(t, from, 1, 0), 0, now)
+		}
+	}
+	assert.True(t, advanced)
+	assert.Equal(t, StepCapture, progress.Step)
+
+	assert.False(t, progress.Captured(false, now))
+	assert.True(t, progress.Captured(true, now))
+	assert.True(t, progress.IsComplete())
+	assert.Nil(t, progress.Room)
+	assert.NotNil(t, progress.CompletedAt)
+
+	code, _ := shared.DomainErrorCode(progress.Skip(now))
+	assert.Equal(t, shared.ErrCodeInvalidOperation, code)
+}
+
+func TestProgress_Skip(t *testing.T) {
+	progress := NewProgress("user", NewPracticeRange(shared.NewPosition(0, 0), shared.NewPosition(0, -1)), time.Now())
+	require.NoError(t, progress.Skip(time.Now()))
+	assert.True(t, progress.IsComplete())
+	assert.True(t, progress.Skipped)
+	assert.Nil(t, progress.Room)
+}
@@ -0,0 +1,70 @@
+package vault
+
+import (
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// Event types
+const (
+	ItemsDepositedEventType = "vault.items_deposited"
+	ItemsWithdrawnEventType = "vault.items_withdrawn"
+)
+
+// ItemsDepositedEvent represents items moved from the carried inventory into the vault
+type ItemsDepositedEvent struct {
+	shared.BaseEvent
+}
+
+// ItemsTransferredEventData holds the event data for vault transfers
+type ItemsTransferredEventData struct {
+	UserID     string   `json:"user_id"`
+	LocationID string   `json:"location_id"`
+	ItemIDs    []string `json:"item_ids"`
+}
+
+// NewItemsDepositedEvent creates a new items deposited event
+func NewItemsDepositedEvent(userID, locationID string, itemIDs []string) (ItemsDepositedEvent, error) {
+	data := ItemsTransferredEventData{
+		UserID:     userID,
+		LocationID: locationID,
+		ItemIDs:    itemIDs,
+	}
+
+	baseEvent, err := shared.NewBaseEvent(
+		ItemsDepositedEventType,
+		userID,
+		"vault",
+		data,
+	)
+	if err != nil {
+		return ItemsDepositedEvent{}, err
+	}
+
+	return ItemsDepositedEvent{BaseEvent: baseEvent}, nil
+}
+
+// ItemsWithdrawnEvent represents items moved from the vault into the carried inventory
+type ItemsWithdrawnEvent struct {
+	shared.BaseEvent
+}
+
+// NewItemsWithdrawnEvent creates a new items withdrawn event
+func NewItemsWithdrawnEvent(userID, locationID string, itemIDs []string) (ItemsWithdrawnEvent, error) {
+	data := ItemsTransferredEventData{
+		UserID:     userID,
+		LocationID: locationID,
+		ItemIDs:    itemIDs,
+	}
+
+	baseEvent, err := shared.NewBaseEvent(
+		ItemsWithdrawnEventType,
+		userID,
+		"vault",
+		data,
+	)
+	if err != nil {
+		return ItemsWithdrawnEvent{}, err
+	}
+
+	return ItemsWithdrawnEvent{BaseEvent: baseEvent}, nil
+}
@@ -0,0 +1,120 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// RedisRepository implements Repository using Redis Hash for vaults.
+// Transfers also read and write the trainer RedisJSON document so both
+// sides of a deposit or withdrawal commit in one transaction.
+type RedisRepository struct {
+	client   *redis.Client
+	codec    redisx.HashCodec[Vault]
+	trainers redisx.JSONCodec[trainer.Trainer]
+	docs     *redisx.Repository[Vault]
+}
+
+// NewRedisRepository creates a new Redis-based vault repository
+func NewRedisRepository(client *redis.Client) Repository {
+	r := &RedisRepository{
+		client: client,
+		codec:  redisx.HashCodec[Vault]{Unmarshal: unmarshalVault},
+	}
+	r.docs = redisx.NewRepository(client, "vault:", redisx.Options[Vault]{Codec: r.codec})
+	return r
+}
+
+// GetByUserID retrieves a user's vault
+func (r *RedisRepository) GetByUserID(ctx context.Context, userID trainer.UserID) (*Vault, error) {
+	v, err := r.docs.GetByID(ctx, userID.AccountID().String())
+	if err != nil || v != nil {
+		return v, err
+	}
+	return NewVault(userID.AccountID()), nil
+}
+
+// Transfer implements IoC pattern across the trainer and vault of a user. Both documents are
+// watched, which redisx.Repository can't, so it reads and writes them itself.
+func (r *RedisRepository) Transfer(ctx context.Context, userID trainer.UserID, callback func(*trainer.Trainer, *Vault) error) error {
+	vaultKey := r.docs.Key(userID.AccountID().String())
+	trainerKey := fmt.Sprintf("trainer:%s", userID.String())
+
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		// Get current trainer
+		trainerData, err := r.trainers.Read(ctx, tx, trainerKey)
+		if err != nil {
+			return err
+		}
+		if trainerData == nil {
+			return shared.ErrNotFound("trainer")
+		}
+
+		t, err := r.trainers.Decode(trainerData)
+		if err != nil {
+			return fmt.Errorf("failed to deserialize trainer: %w", err)
+		}
+
+		// Get current vault, creating it on first use
+		data, err := r.codec.Read(ctx, tx, vaultKey)
+		if err != nil {
+			return err
+		}
+
+		v := NewVault(userID.AccountID())
+		if data != nil {
+			if v, err = r.codec.Decode(data); err != nil {
+				return err
+			}
+		}
+
+		// Execute callback
+		if err := callback(t, v); err != nil {
+			return err
+		}
+
+		// Serialize both sides
+		trainerBytes, err := r.trainers.Encode(t)
+		if err != nil {
+			return fmt.Errorf("failed to serialize trainer: %w", err)
+		}
+
+		vaultBytes, err := r.codec.Encode(v)
+		if err != nil {
+			return err
+		}
+
+		// Execute transaction
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			r.trainers.Write(ctx, pipe, trainerKey, trainerBytes)
+			r.codec.Write(ctx, pipe, vaultKey, vaultBytes)
+			return nil
+		})
+
+		return err
+	}, trainerKey, vaultKey)
+}
+
+// Delete removes a user's vault
+func (r *RedisRepository) Delete(ctx context.Context, userID trainer.UserID) error {
+	return r.client.Del(ctx, r.docs.Key(userID.AccountID().String())).Err()
+}
+
+// unmarshalVault decodes a vault, giving vaults stored without items an empty item map
+func unmarshalVault(data []byte, v *Vault) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return err
+	}
+
+	if v.Items == nil {
+		v.Items = make(map[string]*trainer.Item)
+	}
+	return nil
+}
@@ -0,0 +1,21 @@
+package vault
+
+import (
+	"context"
+
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// Repository defines the interface for vault persistence operations with IoC pattern. A user's
+// characters share one vault, so any of their trainer IDs finds it.
+type Repository interface {
+	// GetByUserID retrieves a user's vault (read-only), returning an empty vault if none exists yet
+	GetByUserID(ctx context.Context, userID trainer.UserID) (*Vault, error)
+
+	// Transfer loads the trainer and vault of a user and applies callback, saving both
+	// atomically so items are never duplicated or lost between them
+	Transfer(ctx context.Context, userID trainer.UserID, callback func(*trainer.Trainer, *Vault) error) error
+
+	// Delete removes a user's vault and everything stored in it
+	Delete(ctx context.Context, userID trainer.UserID) error
+}
@@ -0,0 +1,134 @@
+package vault
+
+import (
+	"math/rand"
+	"sort"
+	"time"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+// DefaultCapacity is the number of item slots in a new vault
+const DefaultCapacity = 200
+
+// Vault represents account-level item storage kept apart from the carried inventory
+type Vault struct {
+	UserID    trainer.UserID           `json:"user_id"` // UserID from Account domain, shared by linked accounts
+	Items     map[string]*trainer.Item `json:"items"`   // itemID -> Item
+	Capacity  int                      `json:"capacity"`
+	CreatedAt time.Time                `json:"created_at"`
+	UpdatedAt time.Time                `json:"updated_at"`
+}
+
+// NewVault creates an empty vault for a user
+func NewVault(userID trainer.UserID) *Vault {
+	now := time.Now()
+
+	return &Vault{
+		UserID:    userID,
+		Items:     make(map[string]*trainer.Item),
+		Capacity:  DefaultCapacity,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Deposit stores an item in the vault. Bound items are refused, since every character of the
+// user can withdraw them.
+func (v *Vault) Deposit(item *trainer.Item) error {
+	if item == nil {
+		return shared.NewDomainError(shared.ErrCodeInvalidInput, "Item cannot be nil")
+	}
+	if err := item.CheckTransferable(); err != nil {
+		return err
+	}
+
+	if v.IsFull() {
+		return shared.NewDomainError(shared.ErrCodeVaultFull, "Vault is full")
+	}
+
+	v.Items[item.ID.String()] = item
+	v.UpdatedAt = time.Now()
+	return nil
+}
+
+// Withdraw removes an item from the vault by ID
+func (v *Vault) Withdraw(itemID trainer.ItemID) (*trainer.Item, error) {
+	item, exists := v.Items[itemID.String()]
+	if !exists {
+		return nil, shared.NewDomainError(shared.ErrCodeItemNotInVault, "Item not found in vault")
+	}
+
+	delete(v.Items, itemID.String())
+	v.UpdatedAt = time.Now()
+	return item, nil
+}
+
+// IsFull checks if the vault is full
+func (v *Vault) IsFull() bool {
+	return len(v.Items) >= v.Capacity
+}
+
+// GetUsedSlots returns the number of used slots
+func (v *Vault) GetUsedSlots() int {
+	return len(v.Items)
+}
+
+// Location represents a designated place where the vault can be accessed
+type Location struct {
+	ID       string          `json:"id"`
+	Name     string          `json:"name"`
+	Position shared.Position `json:"position"`
+	Radius   float64         `json:"radius"` // Access range around the position
+}
+
+// InRange checks if a position is close enough to use this location
+func (l Location) InRange(position shared.Position) bool {
+	return l.Position.WithinRange(position, l.Radius)
+}
+
+// DefaultLocations returns the built-in vault access points
+func DefaultLocations() []Location {
+	return []Location{
+		{ID: "central_base", Name: "Central Base", Position: shared.NewPosition(15.0, 10.0), Radius: 3.0},
+		{ID: "west_outpost", Name: "West Outpost", Position: shared.NewPosition(3.0, 4.0), Radius: 2.0},
+		{ID: "east_outpost", Name: "East Outpost", Position: shared.NewPosition(27.0, 16.0), Radius: 2.0},
+	}
+}
+
+// FindLocation returns the vault location in range of a position, if any
+func FindLocation(locations []Location, position shared.Position) (Location, bool) {
+	for _, location := range locations {
+		if location.InRange(position) {
+			return location, true
+		}
+	}
+	return Location{}, false
+}
+
+// DeathLossChance is the chance for each carried item stack to be lost when a trainer dies
+const DeathLossChance = 0.3
+
+// ApplyDeathLoss removes a random share of the carried inventory. Vaulted items are
+// never at risk since they are not part of the trainer inventory, and bound items stay with
+// their owner. Stacks are rolled in item ID order so the same draws always lose the same
+// stacks.
+func ApplyDeathLoss(inv *trainer.Inventory, lossChance float64, rng *rand.Rand) []*trainer.Item {
+	items := inv.GetAllItems()
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].ID < items[j].ID
+	})
+
+	lost := make([]*trainer.Item, 0)
+	for _, item := range items {
+		if item.Bound {
+			continue
+		}
+		if rng.Float64() < lossChance {
+			delete(inv.Items, item.ID.String())
+			lost = append(lost, item)
+		}
+	}
+	return lost
+}
@@ -0,0 +1,78 @@
+package vault
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+	"github.com/danghamo/life/internal/domain/trainer"
+)
+
+func TestVault_DepositWithdraw(t *testing.T) {
+	v := NewVault("user-1")
+	v.Capacity = 1
+
+	item, err := trainer.NewItem(trainer.AnimalHide, "Hide")
+	require.NoError(t, err)
+	require.NoError(t, v.Deposit(item))
+
+	other, err := trainer.NewItem(trainer.RareGem, "Gem")
+	require.NoError(t, err)
+	assert.Error(t, v.Deposit(other), "vault should be full")
+
+	withdrawn, err := v.Withdraw(item.ID)
+	require.NoError(t, err)
+	assert.Equal(t, item, withdrawn)
+
+	_, err = v.Withdraw(item.ID)
+	assert.Error(t, err)
+
+	bound, err := trainer.NewItem(trainer.RareGem, "Gem")
+	require.NoError(t, err)
+	bound.Bind()
+	err = v.Deposit(bound)
+	code, ok := shared.DomainErrorCode(err)
+	require.True(t, ok)
+	assert.Equal(t, shared.ErrCodeItemBound, code, "the user's other characters could withdraw bound items")
+	assert.Equal(t, 0, v.GetUsedSlots())
+}
+
+func TestFindLocation(t *testing.T) {
+	locations := []Location{
+		{ID: "base", Position: shared.NewPosition(10, 10), Radius: 2},
+	}
+
+	location, ok := FindLocation(locations, shared.NewPosition(11.5, 10))
+	assert.True(t, ok)
+	assert.Equal(t, "base", location.ID)
+
+	_, ok = FindLocation(locations, shared.NewPosition(12.5, 10))
+	assert.False(t, ok)
+}
+
+func TestApplyDeathLoss(t *testing.T) {
+	inv := trainer.NewInventory(10)
+	for i := 0; i < 5; i++ {
+		item, err := trainer.NewItem(trainer.AnimalHide, "Hide")
+		require.NoError(t, err)
+		require.NoError(t, inv.AddItem(item))
+	}
+
+	v := NewVault("user-1")
+	vaulted, err := trainer.NewItem(trainer.RareGem, "Gem")
+	require.NoError(t, err)
+	require.NoError(t, v.Deposit(vaulted))
+
+	crystal, err := trainer.NewItem(trainer.MagicCrystal, "Crystal")
+	require.NoError(t, err)
+	require.NoError(t, inv.AddItem(crystal)) // Binds on pickup
+
+	lost := ApplyDeathLoss(&inv, 1, rand.New(rand.NewSource(1)))
+	require.Len(t, lost, 1, "hides share a single stack")
+	assert.Equal(t, 5, lost[0].Quantity)
+	assert.Equal(t, 1, inv.GetUsedSlots(), "bound items are never lost")
+	assert.Equal(t, 1, v.GetUsedSlots(), "vaulted items are never lost")
+}
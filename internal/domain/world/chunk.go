@@ -0,0 +1,84 @@
+package world
+
+import (
+	"math"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// MaxSubscribedChunks caps the chunks a player streams at once; a 5x5 block around the
+// trainer covers any screen
+const MaxSubscribedChunks = 25
+
+// Entity kinds reported when entities enter or leave a chunk
+const (
+	EntityTrainer = "trainer"
+	EntityAnimal  = "animal"
+)
+
+// ChunkTiles is the terrain of one chunk, as clients stream it. Terrain always holds
+// ChunkSize rows of ChunkSize tiles; tiles past the edge of the world have no terrain.
+type ChunkTiles struct {
+	Chunk
+	Size    int                     `json:"size"`
+	Terrain [][]TerrainType         `json:"terrain"`           // Indexed [y][x] from the chunk's corner
+	Statics map[string]StaticEntity `json:"statics,omitempty"` // Position key -> static entity
+}
+
+// ChunkEntityChange tells subscribers of a chunk that an entity entered or left it
+type ChunkEntityChange struct {
+	Chunk    Chunk           `json:"chunk"`
+	EntityID string          `json:"entity_id"`
+	Kind     string          `json:"kind"`
+	Position shared.Position `json:"position"`
+}
+
+// ChunkAt returns the chunk holding a position
+func ChunkAt(position shared.Position) Chunk {
+	return Chunk{
+		X: int(math.Floor(position.X / ChunkSize)),
+		Y: int(math.Floor(position.Y / ChunkSize)),
+	}
+}
+
+// Contains checks if the world has tiles in a chunk
+func (w *World) Contains(chunk Chunk) bool {
+	return chunk.X >= 0 && chunk.Y >= 0 && chunk.X*ChunkSize < w.Width && chunk.Y*ChunkSize < w.Height
+}
+
+// GetChunk returns the tiles of the chunk at cx, cy
+func (w *World) GetChunk(cx, cy int) (*ChunkTiles, error) {
+	chunk := Chunk{X: cx, Y: cy}
+	if !w.Contains(chunk) {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidPosition, "Chunk %d,%d is outside the world", cx, cy)
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	tiles := &ChunkTiles{
+		Chunk:   chunk,
+		Size:    ChunkSize,
+		Terrain: make([][]TerrainType, ChunkSize),
+	}
+	for dy := 0; dy < ChunkSize; dy++ {
+		row := make([]TerrainType, ChunkSize)
+		for dx := 0; dx < ChunkSize; dx++ {
+			position := shared.NewPosition(float64(cx*ChunkSize+dx), float64(cy*ChunkSize+dy))
+			tile, ok := w.Tiles[position.Key()]
+			if !ok {
+				continue
+			}
+
+			row[dx] = tile.Terrain
+			if tile.Static != nil {
+				if tiles.Statics == nil {
+					tiles.Statics = make(map[string]StaticEntity)
+				}
+				tiles.Statics[position.Key()] = *tile.Static
+			}
+		}
+		tiles.Terrain[dy] = row
+	}
+	return tiles, nil
+}
@@ -0,0 +1,44 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestWorld_GetChunk(t *testing.T) {
+	w, err := NewWorld("Test", 20, 20)
+	require.NoError(t, err)
+	_, err = w.PlaceStatic(shared.NewPosition(17, 2), Tree)
+	require.NoError(t, err)
+
+	// The chunk at the world's edge keeps its size, without terrain past the edge
+	chunk, err := w.GetChunk(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, Chunk{X: 1, Y: 0}, chunk.Chunk)
+	require.Len(t, chunk.Terrain, ChunkSize)
+	require.Len(t, chunk.Terrain[0], ChunkSize)
+	assert.Equal(t, Mountain, chunk.Terrain[0][0])
+	assert.Equal(t, Mountain, chunk.Terrain[5][3], "the world's last column is border")
+	assert.Empty(t, chunk.Terrain[5][4])
+	corner, err := w.GetChunk(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, Mountain, corner.Terrain[3][3])
+	assert.Empty(t, corner.Terrain[4][0])
+	assert.Equal(t, Tree, chunk.Statics[shared.NewPosition(17, 2).Key()].Kind)
+
+	_, err = w.GetChunk(2, 0)
+	code, _ := shared.DomainErrorCode(err)
+	assert.Equal(t, shared.ErrCodeInvalidPosition, code)
+	_, err = w.GetChunk(0, -1)
+	assert.Error(t, err)
+}
+
+func TestChunkAt(t *testing.T) {
+	assert.Equal(t, Chunk{X: 0, Y: 0}, ChunkAt(shared.NewPosition(15.9, 0)))
+	assert.Equal(t, Chunk{X: 1, Y: 2}, ChunkAt(shared.NewPosition(16, 32.5)))
+	assert.Equal(t, Chunk{X: -1, Y: 0}, ChunkAt(shared.NewPosition(-0.5, 3)))
+}
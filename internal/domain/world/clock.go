@@ -0,0 +1,177 @@
+package world
+
+import (
+	"math/rand"
+	"time"
+)
+
+// MinutesPerDay is the length of a game day in game minutes
+const MinutesPerDay = 24 * 60
+
+// DefaultDayLength is how long a game day lasts in real time
+const DefaultDayLength = 48 * time.Minute
+
+// DayPhase is the part of the game day, which sets the lighting
+type DayPhase string
+
+const (
+	Dawn  DayPhase = "dawn"  // 05:00-07:00
+	Day   DayPhase = "day"   // 07:00-18:00
+	Dusk  DayPhase = "dusk"  // 18:00-20:00
+	Night DayPhase = "night" // 20:00-05:00
+)
+
+// PhaseAt returns the phase of the day at an hour
+func PhaseAt(hour int) DayPhase {
+	switch {
+	case hour >= 5 && hour < 7:
+		return Dawn
+	case hour >= 7 && hour < 18:
+		return Day
+	case hour >= 18 && hour < 20:
+		return Dusk
+	default:
+		return Night
+	}
+}
+
+// Weather is the weather across the world
+type Weather string
+
+const (
+	Clear  Weather = "clear"
+	Cloudy Weather = "cloudy"
+	Rain   Weather = "rain"
+	Storm  Weather = "storm"
+	Fog    Weather = "fog"
+)
+
+// weatherTransitions weighs the weather that may follow each weather, so it changes
+// gradually: storms only build from rain and clear up through rain or clouds
+var weatherTransitions = map[Weather][]struct {
+	weather Weather
+	weight  int
+}{
+	Clear:  {{Clear, 5}, {Cloudy, 3}, {Fog, 1}, {Rain, 1}},
+	Cloudy: {{Clear, 3}, {Cloudy, 2}, {Rain, 3}, {Fog, 1}},
+	Rain:   {{Clear, 1}, {Cloudy, 3}, {Rain, 2}, {Storm, 2}},
+	Storm:  {{Cloudy, 2}, {Rain, 4}},
+	Fog:    {{Clear, 3}, {Cloudy, 2}, {Fog, 1}},
+}
+
+const (
+	// minWeatherSpell and maxWeatherSpell bound how long a weather lasts, in game minutes
+	minWeatherSpell = 60
+	maxWeatherSpell = 4 * 60
+	// clockStartMinute starts new worlds on the morning of their first day
+	clockStartMinute = 8 * 60
+)
+
+// Clock is the world's game time and weather. A game day lasts a configured length of real
+// time; the clock only moves forward when advanced, so every server reads the same stored time.
+type Clock struct {
+	Minute       int64     `json:"minute"`        // Game minutes since the world began
+	Phase        DayPhase  `json:"phase"`
+	Weather      Weather   `json:"weather"`
+	WeatherUntil int64     `json:"weather_until"` // Game minute the weather changes at
+	UpdatedAt    time.Time `json:"updated_at"`    // Real time Minute began at
+}
+
+// ClockReading is the clock as clients see it, with the day length to interpolate lighting
+// between readings
+type ClockReading struct {
+	Minute           int64    `json:"minute"`
+	Day              int64    `json:"day"` // Starting at 1
+	Hour             int      `json:"hour"`
+	MinuteOfHour     int      `json:"minute_of_hour"`
+	Phase            DayPhase `json:"phase"`
+	Weather          Weather  `json:"weather"`
+	DayLengthSeconds float64  `json:"day_length_seconds"`
+}
+
+// NewClock starts a clock on the morning of the first day in clear weather
+func NewClock(now time.Time, rng *rand.Rand) *Clock {
+	clock := &Clock{
+		Minute:    clockStartMinute,
+		Weather:   Clear,
+		UpdatedAt: now,
+	}
+	clock.Phase = PhaseAt(clock.Hour())
+	clock.WeatherUntil = clock.Minute + weatherSpell(rng)
+	return clock
+}
+
+// Day returns the game day, starting at 1
+func (c *Clock) Day() int64 {
+	return c.Minute/MinutesPerDay + 1
+}
+
+// Hour returns the hour of the game day
+func (c *Clock) Hour() int {
+	return int(c.Minute % MinutesPerDay / 60)
+}
+
+// Advance moves game time forward to now and rolls the weather for every spell that ended
+// on the way. The result reports whether the hour or the weather changed, which clients are
+// told about.
+func (c *Clock) Advance(now time.Time, dayLength time.Duration, rng *rand.Rand) bool {
+	perMinute := dayLength / MinutesPerDay
+	if perMinute <= 0 {
+		perMinute = DefaultDayLength / MinutesPerDay
+	}
+
+	minutes := int64(now.Sub(c.UpdatedAt) / perMinute)
+	if minutes <= 0 {
+		return false
+	}
+
+	hour, weather := c.Minute/60, c.Weather
+	c.Minute += minutes
+	c.UpdatedAt = c.UpdatedAt.Add(time.Duration(minutes) * perMinute)
+	c.Phase = PhaseAt(c.Hour())
+	for c.Minute >= c.WeatherUntil {
+		c.Weather = nextWeather(c.Weather, rng)
+		c.WeatherUntil += weatherSpell(rng)
+	}
+
+	return c.Minute/60 != hour || c.Weather != weather
+}
+
+// Reading returns the clock as clients see it
+func (c *Clock) Reading(dayLength time.Duration) ClockReading {
+	return ClockReading{
+		Minute:           c.Minute,
+		Day:              c.Day(),
+		Hour:             c.Hour(),
+		MinuteOfHour:     int(c.Minute % 60),
+		Phase:            c.Phase,
+		Weather:          c.Weather,
+		DayLengthSeconds: dayLength.Seconds(),
+	}
+}
+
+// nextWeather rolls the weather following the current one
+func nextWeather(current Weather, rng *rand.Rand) Weather {
+	transitions, ok := weatherTransitions[current]
+	if !ok {
+		return Clear
+	}
+
+	total := 0
+	for _, t := range transitions {
+		total += t.weight
+	}
+	roll := rng.Intn(total)
+	for _, t := range transitions {
+		roll -= t.weight
+		if roll < 0 {
+			return t.weather
+		}
+	}
+	return current
+}
+
+// weatherSpell rolls how long a weather lasts, in game minutes
+func weatherSpell(rng *rand.Rand) int64 {
+	return minWeatherSpell + rng.Int63n(maxWeatherSpell-minWeatherSpell+1)
+}
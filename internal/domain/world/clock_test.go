@@ -0,0 +1,67 @@
+package world
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClock_Advance(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	dayLength := 24 * time.Minute // A game hour per real minute
+
+	clock := NewClock(start, rng)
+	assert.Equal(t, int64(1), clock.Day())
+	assert.Equal(t, 8, clock.Hour())
+	assert.Equal(t, Day, clock.Phase)
+	assert.Equal(t, Clear, clock.Weather)
+
+	// Less than a game minute doesn't move the clock
+	assert.False(t, clock.Advance(start.Add(500*time.Millisecond), dayLength, rng))
+	assert.Equal(t, int64(clockStartMinute), clock.Minute)
+
+	// Half an hour passes within the same hour, unless the weather turned
+	weatherUntil := clock.WeatherUntil
+	changed := clock.Advance(start.Add(30*time.Second), dayLength, rng)
+	assert.Equal(t, int64(clockStartMinute+30), clock.Minute)
+	assert.Equal(t, clock.Minute >= weatherUntil, changed)
+
+	// Twelve hours later it is night, with the weather rolled for every spell that ended
+	assert.True(t, clock.Advance(start.Add(12*time.Minute+30*time.Second), dayLength, rng))
+	assert.Equal(t, 20, clock.Hour())
+	assert.Equal(t, Night, clock.Phase)
+	assert.Greater(t, clock.WeatherUntil, clock.Minute)
+	assert.Equal(t, start.Add(12*time.Minute+30*time.Second), clock.UpdatedAt)
+
+	// Past midnight the day turns
+	clock.Advance(start.Add(17*time.Minute), dayLength, rng)
+	assert.Equal(t, int64(2), clock.Day())
+	assert.Equal(t, 1, clock.Hour())
+
+	reading := clock.Reading(dayLength)
+	assert.Equal(t, int64(2), reading.Day)
+	assert.Equal(t, 1, reading.Hour)
+	assert.Equal(t, float64(24*60), reading.DayLengthSeconds)
+}
+
+func TestPhaseAt(t *testing.T) {
+	assert.Equal(t, Night, PhaseAt(0))
+	assert.Equal(t, Dawn, PhaseAt(5))
+	assert.Equal(t, Day, PhaseAt(7))
+	assert.Equal(t, Day, PhaseAt(17))
+	assert.Equal(t, Dusk, PhaseAt(18))
+	assert.Equal(t, Night, PhaseAt(20))
+}
+
+func TestNextWeather(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+
+	// Storms only follow rain and only clear up through rain or clouds
+	for i := 0; i < 100; i++ {
+		assert.NotEqual(t, Storm, nextWeather(Clear, rng))
+		assert.Contains(t, []Weather{Cloudy, Rain}, nextWeather(Storm, rng))
+	}
+}
@@ -0,0 +1,372 @@
+package world
+
+import (
+	"sort"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// DefaultID and DefaultName identify the world servers load on start; it is created on the
+// first start
+const (
+	DefaultID   WorldID = "default"
+	DefaultName         = "Savanna"
+)
+
+// ChunkSize is the width and height in tiles of the chunks servers reload after edits
+const ChunkSize = 16
+
+// MaxEditTiles caps the tiles one ApplyEdits call may cover
+const MaxEditTiles = 4096
+
+// NoStatic as the static kind of a TileEdit removes static entities
+const NoStatic StaticKind = "none"
+
+// maxUnreachable caps the unreachable tiles a connectivity check lists
+const maxUnreachable = 20
+
+// Chunk identifies a square of ChunkSize tiles
+type Chunk struct {
+	X int `json:"x"`
+	Y int `json:"y"`
+}
+
+// ChunkOf returns the chunk holding the tile at x, y
+func ChunkOf(x, y int) Chunk {
+	return Chunk{X: x / ChunkSize, Y: y / ChunkSize}
+}
+
+// Region is a rectangle of tiles including both corners
+type Region struct {
+	MinX int `json:"min_x"`
+	MinY int `json:"min_y"`
+	MaxX int `json:"max_x"`
+	MaxY int `json:"max_y"`
+}
+
+// NewRegion creates the region between two corners given in any order
+func NewRegion(x1, y1, x2, y2 int) Region {
+	return Region{MinX: min(x1, x2), MinY: min(y1, y2), MaxX: max(x1, x2), MaxY: max(y1, y2)}
+}
+
+// Tiles returns the number of tiles in the region
+func (r Region) Tiles() int {
+	return (r.MaxX - r.MinX + 1) * (r.MaxY - r.MinY + 1)
+}
+
+// Chunks returns the chunks the region overlaps
+func (r Region) Chunks() []Chunk {
+	first, last := ChunkOf(r.MinX, r.MinY), ChunkOf(r.MaxX, r.MaxY)
+
+	chunks := make([]Chunk, 0, (last.X-first.X+1)*(last.Y-first.Y+1))
+	for x := first.X; x <= last.X; x++ {
+		for y := first.Y; y <= last.Y; y++ {
+			chunks = append(chunks, Chunk{X: x, Y: y})
+		}
+	}
+	return chunks
+}
+
+// TileEdit changes the tiles of a region: their terrain, their static entities, or both
+type TileEdit struct {
+	Region  Region      `json:"region"`
+	Terrain TerrainType `json:"terrain,omitempty"` // New terrain of every tile
+	Static  StaticKind  `json:"static,omitempty"`  // Placed on every walkable tile, or NoStatic to remove them
+}
+
+// EditResult reports what ApplyEdits changed
+type EditResult struct {
+	Changed      int          `json:"changed"` // Tiles that changed
+	Chunks       []Chunk      `json:"chunks"`  // Chunks holding changed tiles
+	Connectivity Connectivity `json:"connectivity"`
+}
+
+// ChunksInvalidated tells clients to refetch the tiles of chunks an edit changed
+type ChunksInvalidated struct {
+	Chunks    []Chunk `json:"chunks"`
+	ChunkSize int     `json:"chunk_size"`
+}
+
+// Connectivity describes how the walkable tiles of a world hang together
+type Connectivity struct {
+	Walkable    int               `json:"walkable"` // Walkable tiles
+	Regions     int               `json:"regions"`  // Groups of walkable tiles reachable from each other
+	Largest     int               `json:"largest"`  // Walkable tiles in the largest group
+	Unreachable []shared.Position `json:"unreachable,omitempty"`
+}
+
+// Connected checks if every walkable tile can be reached from every other
+func (c Connectivity) Connected() bool {
+	return c.Regions <= 1
+}
+
+// Paint sets the terrain of every tile in a region, returning how many tiles changed
+func (w *World) Paint(region Region, terrain TerrainType) (int, error) {
+	if !terrain.IsValid() {
+		return 0, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown terrain %q", terrain)
+	}
+	if err := w.checkRegion(region); err != nil {
+		return 0, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	changed := 0
+	for x := region.MinX; x <= region.MaxX; x++ {
+		for y := region.MinY; y <= region.MaxY; y++ {
+			tile, err := w.getTile(shared.NewPosition(float64(x), float64(y)))
+			if err != nil {
+				return changed, err
+			}
+			if tile.Terrain == terrain {
+				continue
+			}
+
+			updated := *tile
+			updated.Terrain = terrain
+			w.Tiles[tile.Position.Key()] = &updated
+			changed++
+		}
+	}
+
+	if changed > 0 {
+		w.UpdatedAt = shared.NewTimestamp()
+	}
+	return changed, nil
+}
+
+// PlaceStatic places a fixed object on the walkable tile at position
+func (w *World) PlaceStatic(position shared.Position, kind StaticKind) (*StaticEntity, error) {
+	if !kind.IsValid() {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown static entity kind %q", kind)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	tile, err := w.getTile(position)
+	if err != nil {
+		return nil, err
+	}
+	if tile.Static != nil {
+		return nil, shared.ErrAlreadyExists("static entity")
+	}
+	if !tile.Terrain.IsWalkable() {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidPosition, "Static entities can only be placed on walkable terrain")
+	}
+
+	static := &StaticEntity{ID: shared.NewID(), Kind: kind}
+	updated := *tile
+	updated.Static = static
+	w.Tiles[tile.Position.Key()] = &updated
+	w.UpdatedAt = shared.NewTimestamp()
+
+	return static, nil
+}
+
+// RemoveStatic removes the fixed object on the tile at position
+func (w *World) RemoveStatic(position shared.Position) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	tile, err := w.getTile(position)
+	if err != nil {
+		return err
+	}
+	if tile.Static == nil {
+		return shared.ErrNotFound("static entity")
+	}
+
+	updated := *tile
+	updated.Static = nil
+	w.Tiles[tile.Position.Key()] = &updated
+	w.UpdatedAt = shared.NewTimestamp()
+
+	return nil
+}
+
+// ReplaceChunks swaps the tiles of chunks for those of another copy of the world, returning
+// how many tiles were replaced
+func (w *World) ReplaceChunks(from *World, chunks []Chunk) int {
+	from.mu.RLock()
+	defer from.mu.RUnlock()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	replaced := 0
+	for _, chunk := range chunks {
+		for x := chunk.X * ChunkSize; x < (chunk.X+1)*ChunkSize && x < w.Width; x++ {
+			for y := chunk.Y * ChunkSize; y < (chunk.Y+1)*ChunkSize && y < w.Height; y++ {
+				key := shared.NewPosition(float64(x), float64(y)).Key()
+				if tile, ok := from.Tiles[key]; ok {
+					w.Tiles[key] = tile
+					replaced++
+				}
+			}
+		}
+	}
+
+	if replaced > 0 {
+		w.UpdatedAt = shared.NewTimestamp()
+	}
+	return replaced
+}
+
+// ApplyEdits validates edits and applies them in order. Placing replaces static entities
+// already on a tile and skips tiles whose terrain isn't walkable after the edit.
+func (w *World) ApplyEdits(edits []TileEdit) (*EditResult, error) {
+	if len(edits) == 0 {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "No edits given")
+	}
+
+	tiles := 0
+	for _, edit := range edits {
+		if edit.Terrain == "" && edit.Static == "" {
+			return nil, shared.NewDomainError(shared.ErrCodeInvalidInput, "Edit changes neither terrain nor static entities")
+		}
+		if edit.Terrain != "" && !edit.Terrain.IsValid() {
+			return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown terrain %q", edit.Terrain)
+		}
+		if edit.Static != "" && edit.Static != NoStatic && !edit.Static.IsValid() {
+			return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Unknown static entity kind %q", edit.Static)
+		}
+		if err := w.checkRegion(edit.Region); err != nil {
+			return nil, err
+		}
+		tiles += edit.Region.Tiles()
+	}
+	if tiles > MaxEditTiles {
+		return nil, shared.NewDomainErrorf(shared.ErrCodeInvalidInput, "Edits cannot cover more than %d tiles", MaxEditTiles)
+	}
+
+	w.mu.Lock()
+	changed := make(map[string]shared.Position)
+	for _, edit := range edits {
+		for x := edit.Region.MinX; x <= edit.Region.MaxX; x++ {
+			for y := edit.Region.MinY; y <= edit.Region.MaxY; y++ {
+				tile, err := w.getTile(shared.NewPosition(float64(x), float64(y)))
+				if err != nil {
+					w.mu.Unlock()
+					return nil, err
+				}
+
+				updated := *tile
+				if edit.Terrain != "" {
+					updated.Terrain = edit.Terrain
+				}
+				switch {
+				case edit.Static == NoStatic:
+					updated.Static = nil
+				case edit.Static != "" && updated.Terrain.IsWalkable():
+					if tile.Static == nil || tile.Static.Kind != edit.Static {
+						updated.Static = &StaticEntity{ID: shared.NewID(), Kind: edit.Static}
+					}
+				}
+
+				if updated.Terrain != tile.Terrain || updated.Static != tile.Static {
+					w.Tiles[tile.Position.Key()] = &updated
+					changed[tile.Position.Key()] = tile.Position
+				}
+			}
+		}
+	}
+	if len(changed) > 0 {
+		w.UpdatedAt = shared.NewTimestamp()
+	}
+	w.mu.Unlock()
+
+	seen := make(map[Chunk]bool)
+	result := &EditResult{Changed: len(changed), Chunks: make([]Chunk, 0)}
+	for _, position := range changed {
+		chunk := ChunkOf(int(position.X), int(position.Y))
+		if !seen[chunk] {
+			seen[chunk] = true
+			result.Chunks = append(result.Chunks, chunk)
+		}
+	}
+	sort.Slice(result.Chunks, func(i, j int) bool {
+		a, b := result.Chunks[i], result.Chunks[j]
+		return a.Y < b.Y || (a.Y == b.Y && a.X < b.X)
+	})
+	result.Connectivity = w.Connectivity()
+
+	return result, nil
+}
+
+// Connectivity groups walkable tiles by whether trainers can walk between them, moving the
+// same way as GetNeighbors, and lists tiles outside the largest group
+func (w *World) Connectivity() Connectivity {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	group := make(map[string]int)
+	var sizes []int
+	var walkable []shared.Position
+
+	for x := 0; x < w.Width; x++ {
+		for y := 0; y < w.Height; y++ {
+			start := shared.NewPosition(float64(x), float64(y))
+			if !w.isWalkablePosition(start) {
+				continue
+			}
+			walkable = append(walkable, start)
+			if _, seen := group[start.Key()]; seen {
+				continue
+			}
+
+			// Flood fill the group of tiles reachable from start
+			id := len(sizes)
+			sizes = append(sizes, 0)
+			group[start.Key()] = id
+			queue := []shared.Position{start}
+			for len(queue) > 0 {
+				current := queue[0]
+				queue = queue[1:]
+				sizes[id]++
+
+				for dx := -1; dx <= 1; dx++ {
+					for dy := -1; dy <= 1; dy++ {
+						next := shared.NewPosition(current.X+float64(dx), current.Y+float64(dy))
+						if _, seen := group[next.Key()]; seen || !w.isWalkablePosition(next) {
+							continue
+						}
+						group[next.Key()] = id
+						queue = append(queue, next)
+					}
+				}
+			}
+		}
+	}
+
+	result := Connectivity{Walkable: len(walkable), Regions: len(sizes)}
+	largest := 0
+	for id, size := range sizes {
+		if size > result.Largest {
+			result.Largest, largest = size, id
+		}
+	}
+
+	for _, position := range walkable {
+		if group[position.Key()] != largest {
+			result.Unreachable = append(result.Unreachable, position)
+		}
+	}
+	sort.Slice(result.Unreachable, func(i, j int) bool {
+		a, b := result.Unreachable[i], result.Unreachable[j]
+		return a.Y < b.Y || (a.Y == b.Y && a.X < b.X)
+	})
+	if len(result.Unreachable) > maxUnreachable {
+		result.Unreachable = result.Unreachable[:maxUnreachable]
+	}
+
+	return result
+}
+
+// checkRegion checks that a region lies within the world
+func (w *World) checkRegion(region Region) error {
+	if region.MinX < 0 || region.MinY < 0 || region.MaxX >= w.Width || region.MaxY >= w.Height {
+		return shared.NewDomainError(shared.ErrCodeInvalidPosition, "Region is outside world boundaries")
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestWorld_PaintAndConnectivity(t *testing.T) {
+	w, err := NewWorld("Test", 20, 20)
+	require.NoError(t, err)
+	require.True(t, w.Connectivity().Connected())
+
+	// A wall of water splits the world in two
+	_, err = w.Paint(NewRegion(10, 0, 10, 19), Water)
+	require.NoError(t, err)
+	connectivity := w.Connectivity()
+	assert.Equal(t, 2, connectivity.Regions)
+	assert.NotEmpty(t, connectivity.Unreachable)
+
+	_, err = w.Paint(NewRegion(15, 15, 25, 25), Water)
+	code, _ := shared.DomainErrorCode(err)
+	assert.Equal(t, shared.ErrCodeInvalidPosition, code)
+}
+
+func TestWorld_PlaceStatic(t *testing.T) {
+	w, err := NewWorld("Test", 20, 20)
+	require.NoError(t, err)
+	position := shared.NewPosition(2, 3)
+
+	_, err = w.PlaceStatic(position, Rock)
+	require.NoError(t, err)
+	assert.False(t, w.IsWalkablePosition(position))
+
+	_, err = w.PlaceStatic(position, Sign)
+	code, _ := shared.DomainErrorCode(err)
+	assert.Equal(t, shared.ErrCodeAlreadyExists, code)
+
+	require.NoError(t, w.RemoveStatic(position))
+	assert.True(t, w.IsWalkablePosition(position))
+}
+
+func TestWorld_ReplaceChunks(t *testing.T) {
+	live, err := NewWorld("Test", 40, 20)
+	require.NoError(t, err)
+	edited, err := NewWorld("Test", 40, 20)
+	require.NoError(t, err)
+
+	_, err = edited.Paint(NewRegion(1, 1, 30, 1), Mountain)
+	require.NoError(t, err)
+
+	region := NewRegion(1, 1, 5, 1)
+	assert.Equal(t, []Chunk{{X: 0, Y: 0}}, region.Chunks())
+	assert.Equal(t, ChunkSize*ChunkSize, live.ReplaceChunks(edited, region.Chunks()))
+	assert.False(t, live.IsWalkablePosition(shared.NewPosition(5, 1)))
+	assert.True(t, live.IsWalkablePosition(shared.NewPosition(20, 1))) // Outside the replaced chunk
+}
+
+func TestWorld_ApplyEdits(t *testing.T) {
+	w, err := NewWorld("Test", 40, 20)
+	require.NoError(t, err)
+
+	// Close a gate: a fence across the world, except on the water it crosses
+	result, err := w.ApplyEdits([]TileEdit{
+		{Region: NewRegion(20, 1, 20, 5), Terrain: Water},
+		{Region: NewRegion(20, 1, 20, 18), Static: Fence},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 16, result.Changed) // (20, 2) and (20, 13) are water already
+	assert.Equal(t, []Chunk{{X: 1, Y: 0}, {X: 1, Y: 1}}, result.Chunks)
+	assert.False(t, result.Connectivity.Connected())
+	assert.Nil(t, w.Tiles[shared.NewPosition(20, 3).Key()].Static)
+
+	// Open it again
+	result, err = w.ApplyEdits([]TileEdit{{Region: NewRegion(20, 10, 20, 12), Static: NoStatic}})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Changed)
+	assert.True(t, result.Connectivity.Connected())
+
+	_, err = w.ApplyEdits([]TileEdit{{Region: NewRegion(0, 0, 39, 19)}})
+	code, _ := shared.DomainErrorCode(err)
+	assert.Equal(t, shared.ErrCodeInvalidInput, code)
+}
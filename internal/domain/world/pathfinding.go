@@ -0,0 +1,132 @@
+package world
+
+import (
+	"container/heap"
+	"math"
+	"slices"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+// pathStep is a move to one of the eight tiles around a tile
+type pathStep struct {
+	dx, dy int
+}
+
+// pathSteps lists the orthogonal steps before the diagonal ones, so ties prefer straight lines
+var pathSteps = []pathStep{
+	{1, 0}, {-1, 0}, {0, 1}, {0, -1},
+	{1, 1}, {1, -1}, {-1, 1}, {-1, -1},
+}
+
+// pathNode is a tile waiting in the open set of the search
+type pathNode struct {
+	tile     shared.Position
+	cost     float64 // Cost of the cheapest known path from the start
+	estimate float64 // cost plus the heuristic to the goal
+}
+
+// pathQueue orders the open set by estimate, cheapest first
+type pathQueue []pathNode
+
+func (q pathQueue) Len() int            { return len(q) }
+func (q pathQueue) Less(i, j int) bool  { return q[i].estimate < q[j].estimate }
+func (q pathQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *pathQueue) Push(x interface{}) { *q = append(*q, x.(pathNode)) }
+func (q *pathQueue) Pop() interface{} {
+	old := *q
+	node := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return node
+}
+
+// FindPath finds the cheapest path between the tiles holding from and to with A*. Each step
+// costs the movement cost of the tile it enters, √2 times that for diagonal steps. Diagonal
+// steps are only taken when both tiles beside them are walkable too, so walking straight from
+// tile center to tile center never cuts across a blocked corner. The path is returned as the
+// centers of its tiles, from from's tile to to's.
+func (w *World) FindPath(from, to shared.Position) ([]shared.Position, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	start := shared.NewPosition(math.Floor(from.X), math.Floor(from.Y))
+	goal := shared.NewPosition(math.Floor(to.X), math.Floor(to.Y))
+	if !w.isWalkablePosition(start) {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidMove, "Start is not walkable")
+	}
+	if !w.isWalkablePosition(goal) {
+		return nil, shared.NewDomainError(shared.ErrCodeInvalidMove, "Destination is not walkable")
+	}
+
+	costs := map[string]float64{start.Key(): 0}
+	previous := make(map[string]shared.Position)
+	open := &pathQueue{{tile: start, estimate: octileDistance(start, goal)}}
+
+	for open.Len() > 0 {
+		node := heap.Pop(open).(pathNode)
+		if node.tile == goal {
+			return tracePath(previous, start, goal), nil
+		}
+		if node.cost > costs[node.tile.Key()] {
+			continue // A cheaper path to the tile was found after this one was queued
+		}
+
+		for _, step := range pathSteps {
+			stepCost, ok := w.stepCost(node.tile, step)
+			if !ok {
+				continue
+			}
+
+			next := shared.NewPosition(node.tile.X+float64(step.dx), node.tile.Y+float64(step.dy))
+			cost := node.cost + stepCost
+			if known, seen := costs[next.Key()]; seen && known <= cost {
+				continue
+			}
+			costs[next.Key()] = cost
+			previous[next.Key()] = node.tile
+			heap.Push(open, pathNode{tile: next, cost: cost, estimate: cost + octileDistance(next, goal)})
+		}
+	}
+
+	return nil, shared.NewDomainError(shared.ErrCodeInvalidMove, "Destination can't be reached")
+}
+
+// stepCost returns the cost of a step from a tile, or false when the step is blocked. Callers
+// hold the lock.
+func (w *World) stepCost(tile shared.Position, step pathStep) (float64, bool) {
+	next, err := w.getTile(shared.NewPosition(tile.X+float64(step.dx), tile.Y+float64(step.dy)))
+	if err != nil || !next.IsWalkable() {
+		return 0, false
+	}
+
+	cost := next.Terrain.GetMovementCost()
+	if step.dx != 0 && step.dy != 0 {
+		if !w.isWalkablePosition(shared.NewPosition(tile.X+float64(step.dx), tile.Y)) ||
+			!w.isWalkablePosition(shared.NewPosition(tile.X, tile.Y+float64(step.dy))) {
+			return 0, false
+		}
+		cost *= math.Sqrt2
+	}
+	return cost, true
+}
+
+// octileDistance estimates the cost between two tiles as if every tile cost the least, which
+// keeps the estimate from exceeding the real cost
+func octileDistance(a, b shared.Position) float64 {
+	dx, dy := math.Abs(a.X-b.X), math.Abs(a.Y-b.Y)
+	return math.Max(dx, dy) + (math.Sqrt2-1)*math.Min(dx, dy)
+}
+
+// tracePath walks the search's links back from goal to start, returning the centers of the
+// tiles in walking order
+func tracePath(previous map[string]shared.Position, start, goal shared.Position) []shared.Position {
+	var path []shared.Position
+	for tile := goal; ; tile = previous[tile.Key()] {
+		path = append(path, shared.NewPosition(tile.X+0.5, tile.Y+0.5))
+		if tile == start {
+			break
+		}
+	}
+	slices.Reverse(path)
+	return path
+}
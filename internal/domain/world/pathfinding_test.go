@@ -0,0 +1,60 @@
+package world
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/domain/shared"
+)
+
+func TestWorld_FindPath(t *testing.T) {
+	w, err := NewWorld("Test", 12, 12)
+	require.NoError(t, err)
+	_, err = w.Paint(NewRegion(1, 1, 10, 10), Grassland)
+	require.NoError(t, err)
+
+	// A straight line across open ground, from tile center to tile center
+	path, err := w.FindPath(shared.NewPosition(2.3, 2.8), shared.NewPosition(5, 2))
+	require.NoError(t, err)
+	assert.Equal(t, []shared.Position{{X: 2.5, Y: 2.5}, {X: 3.5, Y: 2.5}, {X: 4.5, Y: 2.5}, {X: 5.5, Y: 2.5}}, path)
+
+	// A wall with one gap is walked around through the gap
+	_, err = w.Paint(NewRegion(6, 1, 6, 10), Water)
+	require.NoError(t, err)
+	_, err = w.Paint(NewRegion(6, 8, 6, 8), Grassland)
+	require.NoError(t, err)
+	path, err = w.FindPath(shared.NewPosition(4, 4), shared.NewPosition(8, 4))
+	require.NoError(t, err)
+	assert.Contains(t, path, shared.NewPosition(6.5, 8.5))
+	for _, step := range path {
+		assert.True(t, w.IsWalkablePosition(step))
+	}
+
+	// Diagonal steps never cut a blocked corner
+	for i := 1; i < len(path); i++ {
+		dx, dy := path[i].X-path[i-1].X, path[i].Y-path[i-1].Y
+		if dx != 0 && dy != 0 {
+			assert.True(t, w.IsWalkablePosition(shared.NewPosition(path[i-1].X+dx, path[i-1].Y)))
+			assert.True(t, w.IsWalkablePosition(shared.NewPosition(path[i-1].X, path[i-1].Y+dy)))
+		}
+	}
+
+	// Forest costs more than grassland, so a short detour around it is cheaper
+	_, err = w.Paint(NewRegion(2, 2, 4, 2), Forest)
+	require.NoError(t, err)
+	path, err = w.FindPath(shared.NewPosition(1, 2), shared.NewPosition(5, 2))
+	require.NoError(t, err)
+	assert.NotContains(t, path, shared.NewPosition(3.5, 2.5))
+
+	// Closing the gap leaves no path
+	_, err = w.Paint(NewRegion(6, 8, 6, 8), Mountain)
+	require.NoError(t, err)
+	_, err = w.FindPath(shared.NewPosition(4, 4), shared.NewPosition(8, 4))
+	code, _ := shared.DomainErrorCode(err)
+	assert.Equal(t, shared.ErrCodeInvalidMove, code)
+
+	_, err = w.FindPath(shared.NewPosition(4, 4), shared.NewPosition(6, 4))
+	assert.Error(t, err, "water is not walkable")
+}
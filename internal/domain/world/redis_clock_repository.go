@@ -0,0 +1,36 @@
+package world
+
+import (
+	"context"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/pkg/redisx"
+)
+
+// clockID is the ID the world clock is stored under, making its key "world:clock"
+const clockID = "clock"
+
+// RedisClockRepository implements ClockRepository using a Redis string
+type RedisClockRepository struct {
+	docs *redisx.Repository[Clock]
+}
+
+// NewRedisClockRepository creates a new Redis-based clock repository
+func NewRedisClockRepository(client *redis.Client) ClockRepository {
+	return &RedisClockRepository{
+		docs: redisx.NewRepository(client, "world:", redisx.Options[Clock]{
+			Codec: redisx.StringCodec[Clock]{},
+		}),
+	}
+}
+
+// Get retrieves the clock
+func (r *RedisClockRepository) Get(ctx context.Context) (*Clock, error) {
+	return r.docs.GetByID(ctx, clockID)
+}
+
+// FindOneAndUpsert implements IoC pattern with callback for concurrency control
+func (r *RedisClockRepository) FindOneAndUpsert(ctx context.Context, callback func(*Clock) (*Clock, error)) error {
+	return r.docs.FindOneAndUpsert(ctx, clockID, callback)
+}
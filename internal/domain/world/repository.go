@@ -26,3 +26,13 @@ type Repository interface {
 	// Delete removes world
 	Delete(ctx context.Context, id WorldID) error
 }
+
+// ClockRepository stores the world clock
+type ClockRepository interface {
+	// Get retrieves the clock, returning nil if it was never started
+	Get(ctx context.Context) (*Clock, error)
+
+	// FindOneAndUpsert applies callback to the stored clock atomically, passing nil when
+	// there is none; a nil result leaves it as it was
+	FindOneAndUpsert(ctx context.Context, callback func(*Clock) (*Clock, error)) error
+}
@@ -1,6 +1,9 @@
 package world
 
 import (
+	"math"
+	"sync"
+
 	"github.com/danghamo/life/internal/domain/shared"
 )
 
@@ -56,10 +59,37 @@ func (tt TerrainType) GetMovementCost() float64 {
 	}
 }
 
+// StaticKind represents the kinds of fixed objects placed in the world
+type StaticKind string
+
+const (
+	Tree  StaticKind = "tree"
+	Rock  StaticKind = "rock"
+	Fence StaticKind = "fence"
+	Sign  StaticKind = "sign"
+)
+
+// IsValid checks if static kind is valid
+func (k StaticKind) IsValid() bool {
+	return k == Tree || k == Rock || k == Fence || k == Sign
+}
+
+// Blocks checks if objects of this kind stop movement
+func (k StaticKind) Blocks() bool {
+	return k != Sign
+}
+
+// StaticEntity is a fixed object placed on a tile by world editing
+type StaticEntity struct {
+	ID   shared.ID  `json:"id"`
+	Kind StaticKind `json:"kind"`
+}
+
 // Tile represents a single tile in the world
 type Tile struct {
 	Position shared.Position `json:"position"`
 	Terrain  TerrainType     `json:"terrain"`
+	Static   *StaticEntity   `json:"static,omitempty"`
 	Entities []shared.ID     `json:"entities"` // IDs of entities on this tile (trainers, animals)
 }
 
@@ -108,11 +138,14 @@ func (t *Tile) HasEntity(entityID shared.ID) bool {
 
 // IsWalkable checks if tile is walkable
 func (t *Tile) IsWalkable() bool {
-	return t.Terrain.IsWalkable()
+	return t.Terrain.IsWalkable() && (t.Static == nil || !t.Static.Kind.Blocks())
 }
 
-// World represents the game world aggregate
+// World represents the game world aggregate. Servers share one World across goroutines, so
+// tiles are read under its lock and edits replace tiles rather than changing them.
 type World struct {
+	mu sync.RWMutex
+
 	ID        WorldID          `json:"id"`
 	Name      string           `json:"name"`
 	Width     int              `json:"width"`
@@ -186,11 +219,21 @@ func (w *World) Dimensions() (int, int) {
 
 // GetTile returns tile at position
 func (w *World) GetTile(position shared.Position) (*Tile, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.getTile(position)
+}
+
+// getTile returns tile at position; callers hold the lock
+func (w *World) getTile(position shared.Position) (*Tile, error) {
 	if !w.IsValidPosition(position) {
 		return nil, shared.NewDomainError(shared.ErrCodeInvalidPosition, "Position is outside world boundaries")
 	}
 
-	tile, exists := w.Tiles[position.Key()]
+	// Entities move continuously; the tile under a position is the one containing it
+	tilePosition := shared.NewPosition(math.Floor(position.X), math.Floor(position.Y))
+	tile, exists := w.Tiles[tilePosition.Key()]
 	if !exists {
 		return nil, shared.NewDomainError(shared.ErrCodeTileNotFound, "Tile not found")
 	}
@@ -205,7 +248,15 @@ func (w *World) IsValidPosition(position shared.Position) bool {
 
 // IsWalkablePosition checks if position is walkable
 func (w *World) IsWalkablePosition(position shared.Position) bool {
-	tile, err := w.GetTile(position)
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	return w.isWalkablePosition(position)
+}
+
+// isWalkablePosition checks if position is walkable; callers hold the lock
+func (w *World) isWalkablePosition(position shared.Position) bool {
+	tile, err := w.getTile(position)
 	if err != nil {
 		return false
 	}
@@ -214,20 +265,23 @@ func (w *World) IsWalkablePosition(position shared.Position) bool {
 
 // MoveEntity moves an entity from one position to another
 func (w *World) MoveEntity(entityID shared.ID, fromPos, toPos shared.Position) error {
-	if !w.IsWalkablePosition(toPos) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.isWalkablePosition(toPos) {
 		return shared.NewDomainError(shared.ErrCodeInvalidMove, "Destination is not walkable")
 	}
 
 	// Remove from old tile (if valid position)
 	if w.IsValidPosition(fromPos) {
-		fromTile, err := w.GetTile(fromPos)
+		fromTile, err := w.getTile(fromPos)
 		if err == nil {
 			fromTile.RemoveEntity(entityID) // Ignore error if not found
 		}
 	}
 
 	// Add to new tile
-	toTile, err := w.GetTile(toPos)
+	toTile, err := w.getTile(toPos)
 	if err != nil {
 		return err
 	}
@@ -244,7 +298,10 @@ func (w *World) MoveEntity(entityID shared.ID, fromPos, toPos shared.Position) e
 
 // GetEntitiesAt returns all entities at a position
 func (w *World) GetEntitiesAt(position shared.Position) ([]shared.ID, error) {
-	tile, err := w.GetTile(position)
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	tile, err := w.getTile(position)
 	if err != nil {
 		return nil, err
 	}
@@ -255,6 +312,9 @@ func (w *World) GetEntitiesAt(position shared.Position) ([]shared.ID, error) {
 
 // GetNeighbors returns walkable neighboring positions
 func (w *World) GetNeighbors(position shared.Position) []shared.Position {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
 	neighbors := make([]shared.Position, 0, 8)
 
 	// Check all 8 directions (including diagonals)
@@ -265,7 +325,7 @@ func (w *World) GetNeighbors(position shared.Position) []shared.Position {
 			}
 
 			neighborPos := shared.NewPosition(position.X+float64(dx), position.Y+float64(dy))
-			if w.IsWalkablePosition(neighborPos) {
+			if w.isWalkablePosition(neighborPos) {
 				neighbors = append(neighbors, neighborPos)
 			}
 		}
@@ -0,0 +1,18 @@
+-- Accounts with their personal data encrypted inside data and looked up through blind indexes.
+-- tenant_id is empty for the default tenant.
+CREATE TABLE accounts (
+    tenant_id      TEXT        NOT NULL DEFAULT '',
+    id             TEXT        NOT NULL,
+    user_id        TEXT        NOT NULL,
+    provider_index TEXT        NOT NULL,
+    email_index    TEXT,
+    device_index   TEXT,
+    data           JSONB       NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (tenant_id, id)
+);
+
+CREATE UNIQUE INDEX accounts_provider_idx ON accounts (tenant_id, provider_index);
+CREATE INDEX accounts_user_idx ON accounts (tenant_id, user_id, created_at);
+CREATE INDEX accounts_email_idx ON accounts (tenant_id, email_index) WHERE email_index IS NOT NULL;
+CREATE INDEX accounts_device_idx ON accounts (tenant_id, device_index) WHERE device_index IS NOT NULL;
@@ -0,0 +1,11 @@
+-- The account audit log: security-relevant events players review to spot compromise
+CREATE TABLE account_activities (
+    tenant_id TEXT        NOT NULL DEFAULT '',
+    id        BIGSERIAL   PRIMARY KEY,
+    user_id   TEXT        NOT NULL,
+    at        TIMESTAMPTZ NOT NULL,
+    data      JSONB       NOT NULL
+);
+
+CREATE INDEX account_activities_user_idx ON account_activities (tenant_id, user_id, id DESC);
+CREATE INDEX account_activities_at_idx ON account_activities (at);
@@ -0,0 +1,15 @@
+-- Every loot drop rolled for a defeated animal, kept for support and economy audits
+CREATE TABLE loot_ledger (
+    tenant_id   TEXT        NOT NULL DEFAULT '',
+    id          BIGSERIAL   PRIMARY KEY,
+    user_id     TEXT        NOT NULL,
+    animal_id   TEXT        NOT NULL,
+    animal_type TEXT        NOT NULL,
+    level       INTEGER     NOT NULL,
+    mode        TEXT        NOT NULL,
+    drops       JSONB       NOT NULL,
+    pickup_ids  JSONB       NOT NULL DEFAULT '[]',
+    at          TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX loot_ledger_user_idx ON loot_ledger (tenant_id, user_id, id DESC);
@@ -0,0 +1,10 @@
+// Package migrations holds the Postgres schema of durable data, applied in file name order
+// by pgsqlx.Migrate. Applied files must never change; add a new file instead.
+package migrations
+
+import "embed"
+
+// FS holds the migration files
+//
+//go:embed *.sql
+var FS embed.FS
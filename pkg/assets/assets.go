@@ -0,0 +1,217 @@
+// Package assets serves a bundle of static client files. Every file is also served under a
+// name carrying a hash of its content, e.g. app.3f9a1c2b7d40.js, which browsers may cache
+// forever; HTML pages, served under their own names and revalidated on every load, are
+// rewritten to reference those names, so a new build is picked up on the next page load.
+package assets
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"maps"
+	"mime"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// immutableCache is the Cache-Control of content hashed names, which never change
+	immutableCache = "public, max-age=31536000, immutable"
+
+	// revalidateCache is the Cache-Control of plain names, which change with every build
+	revalidateCache = "no-cache"
+
+	// hashLength is how many hex digits of a file's SHA-256 its hashed name carries
+	hashLength = 12
+)
+
+// asset is a file of the bundle
+type asset struct {
+	name        string // Name under the bundle root
+	content     []byte
+	contentType string
+	etag        string
+	immutable   bool // Served under its content hashed name
+}
+
+// bundle is the files of a bundle, by the URL paths they are served at
+type bundle struct {
+	assets map[string]*asset
+	hashed map[string]string // Name under the bundle root -> content hashed URL path
+}
+
+// Handler serves a bundle of static files: the index page at /, every file at its path and
+// at its content hashed path, and 404 for anything else
+type Handler struct {
+	fsys   fs.FS
+	index  string
+	live   bool
+	loaded *bundle // Read once for handlers that aren't live
+}
+
+// New creates a handler serving the files of fsys with index served at /. A live handler
+// rereads the files on every request, for serving a directory being edited; others read them
+// once, here.
+func New(fsys fs.FS, index string, live bool) (*Handler, error) {
+	b, err := load(fsys, index)
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{fsys: fsys, index: index, live: live, loaded: b}, nil
+}
+
+// ServeHTTP serves a file of the bundle
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	b, err := h.bundle()
+	if err != nil {
+		http.Error(w, "Static files unavailable", http.StatusInternalServerError)
+		return
+	}
+
+	urlPath := r.URL.Path
+	if urlPath == "/" {
+		urlPath = "/" + h.index
+	}
+	a, ok := b.assets[urlPath]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", a.contentType)
+	header.Set("ETag", a.etag)
+	if a.immutable {
+		header.Set("Cache-Control", immutableCache)
+	} else {
+		header.Set("Cache-Control", revalidateCache)
+	}
+	http.ServeContent(w, r, a.name, time.Time{}, bytes.NewReader(a.content))
+}
+
+// bundle returns the files to serve, rereading them for live handlers
+func (h *Handler) bundle() (*bundle, error) {
+	if !h.live {
+		return h.loaded, nil
+	}
+	return load(h.fsys, h.index)
+}
+
+// load reads the files of fsys, hashing every file and rewriting the references of HTML
+// pages to the hashed names. Files and directories starting with a dot are skipped.
+func load(fsys fs.FS, index string) (*bundle, error) {
+	files := make(map[string][]byte)
+	err := fs.WalkDir(fsys, ".", func(name string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if name != "." && strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		content, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		files[name] = content
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read static files: %w", err)
+	}
+	if _, ok := files[index]; !ok {
+		return nil, fmt.Errorf("static files have no index page %s", index)
+	}
+
+	b := &bundle{
+		assets: make(map[string]*asset, 2*len(files)),
+		hashed: make(map[string]string, len(files)),
+	}
+
+	// Pages reference the other files, so those are hashed first. Every page is rewritten
+	// before any is added, so pages never depend on each other's hashes.
+	var pages []string
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		if isPage(name) {
+			pages = append(pages, name)
+			continue
+		}
+		b.add(name, files[name])
+	}
+	rewritten := make([][]byte, len(pages))
+	for i, name := range pages {
+		rewritten[i] = b.rewrite(name, files[name])
+	}
+	for i, name := range pages {
+		b.add(name, rewritten[i])
+	}
+	return b, nil
+}
+
+// add serves a file at its path and its content hashed path
+func (b *bundle) add(name string, content []byte) {
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])[:hashLength]
+	ext := path.Ext(name)
+	hashedPath := "/" + strings.TrimSuffix(name, ext) + "." + hash + ext
+
+	contentType := mime.TypeByExtension(ext)
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+
+	plain := &asset{name: name, content: content, contentType: contentType, etag: `"` + hash + `"`}
+	immutable := *plain
+	immutable.immutable = true
+
+	b.assets["/"+name] = plain
+	b.assets[hashedPath] = &immutable
+	b.hashed[name] = hashedPath
+}
+
+// rewrite points the references of a page to the files hashed so far at their hashed paths.
+// References are the quoted paths of files, absolute or relative to the page.
+func (b *bundle) rewrite(page string, content []byte) []byte {
+	dir := path.Dir(page)
+
+	var replacements []string
+	for _, name := range slices.Sorted(maps.Keys(b.hashed)) {
+		hashed := b.hashed[name]
+		references := []string{"/" + name}
+		relative, ok := name, dir == "."
+		if !ok {
+			relative, ok = strings.CutPrefix(name, dir+"/")
+		}
+		if ok {
+			references = append(references, relative, "./"+relative)
+		}
+		for _, reference := range references {
+			for _, quote := range []string{`"`, `'`} {
+				replacements = append(replacements, quote+reference+quote, quote+hashed+quote)
+			}
+		}
+	}
+	return []byte(strings.NewReplacer(replacements...).Replace(string(content)))
+}
+
+// isPage reports whether a file is an HTML page
+func isPage(name string) bool {
+	ext := path.Ext(name)
+	return ext == ".html" || ext == ".htm"
+}
@@ -0,0 +1,93 @@
+package assets
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFiles() fstest.MapFS {
+	return fstest.MapFS{
+		"index.html":     {Data: []byte(`<link href="css/app.css"><script src="app.js"></script><a href="other.html">`)},
+		"app.js":         {Data: []byte(`console.log("life")`)},
+		"css/app.css":    {Data: []byte(`body { margin: 0 }`)},
+		".env":           {Data: []byte(`SECRET=1`)},
+		".git/HEAD":      {Data: []byte(`ref: refs/heads/main`)},
+		"other.html":     {Data: []byte(`<p>other</p>`)},
+		"docs/page.html": {Data: []byte(`<script src="../app.js"></script><script src="/app.js"></script>`)},
+	}
+}
+
+func get(h http.Handler, path string, header ...string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	for i := 0; i+1 < len(header); i += 2 {
+		r.Header.Set(header[i], header[i+1])
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	h, err := New(testFiles(), "index.html", false)
+	require.NoError(t, err)
+
+	index := get(h, "/")
+	require.Equal(t, http.StatusOK, index.Code)
+	assert.Equal(t, "text/html; charset=utf-8", index.Header().Get("Content-Type"))
+	assert.Equal(t, "no-cache", index.Header().Get("Cache-Control"))
+
+	// Pages reference the hashed names, which are cached forever
+	script := regexp.MustCompile(`src="(/app\.[0-9a-f]{12}\.js)"`).FindStringSubmatch(index.Body.String())
+	require.Len(t, script, 2, index.Body.String())
+	assert.Regexp(t, `href="/css/app\.[0-9a-f]{12}\.css"`, index.Body.String())
+	assert.Contains(t, index.Body.String(), `href="other.html"`, "links between pages keep their names")
+
+	hashed := get(h, script[1])
+	require.Equal(t, http.StatusOK, hashed.Code)
+	assert.Equal(t, `console.log("life")`, hashed.Body.String())
+	assert.Equal(t, "public, max-age=31536000, immutable", hashed.Header().Get("Cache-Control"))
+	assert.Contains(t, hashed.Header().Get("Content-Type"), "javascript")
+
+	// Plain names revalidate against the content hash
+	plain := get(h, "/app.js")
+	require.Equal(t, http.StatusOK, plain.Code)
+	assert.Equal(t, "no-cache", plain.Header().Get("Cache-Control"))
+	assert.Equal(t, http.StatusNotModified, get(h, "/app.js", "If-None-Match", plain.Header().Get("ETag")).Code)
+
+	// References relative to a page in a directory are left alone, absolute ones rewritten
+	page := get(h, "/docs/page.html").Body.String()
+	assert.Contains(t, page, `src="../app.js"`)
+	assert.Contains(t, page, `src="`+script[1]+`"`)
+
+	assert.Equal(t, http.StatusNotFound, get(h, "/.env").Code)
+	assert.Equal(t, http.StatusNotFound, get(h, "/.git/HEAD").Code)
+	assert.Equal(t, http.StatusNotFound, get(h, "/missing.js").Code)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/", nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+}
+
+func TestLiveHandler(t *testing.T) {
+	files := testFiles()
+	h, err := New(files, "index.html", true)
+	require.NoError(t, err)
+
+	files["app.js"] = &fstest.MapFile{Data: []byte(`console.log("edited")`)}
+	assert.Equal(t, `console.log("edited")`, get(h, "/app.js").Body.String(), "live handlers reread files")
+
+	static, err := New(testFiles(), "index.html", false)
+	require.NoError(t, err)
+	assert.Equal(t, `console.log("life")`, get(static, "/app.js").Body.String())
+}
+
+func TestNewRequiresIndex(t *testing.T) {
+	_, err := New(testFiles(), "missing.html", false)
+	assert.Error(t, err)
+}
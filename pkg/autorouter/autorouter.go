@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"net/http"
 	"reflect"
+	"sort"
 	"strings"
+	"sync"
 )
 
 // HandlerFunc represents the expected handler function signature
@@ -18,6 +20,10 @@ type RegistrationOptions struct {
 	Prefix       string       // URL prefix (e.g., "/api/v1/")
 	MethodPrefix string       // Method prefix (e.g., "trainer." -> "trainer.Create")
 	Middleware   []Middleware // Middleware chain to apply
+	Registry     *Registry    // Records registered methods by JSON-RPC name, if set
+	// Guard returns middleware for a single method, run inside the chain; nil adds none. It
+	// lets a registration protect some methods more than others, e.g. by role.
+	Guard func(methodName string) Middleware
 }
 
 // AutoRouter handles automatic registration of HTTP handlers using reflection
@@ -35,8 +41,9 @@ func NewAutoRouter(mux *http.ServeMux, options RegistrationOptions) *AutoRouter
 }
 
 // RegisterHandlers automatically registers all methods that match HandlerFunc signature
-// from the given handler struct
+// from the given handler struct. Pass Bind(handler) to have requests skip reflection.
 func (ar *AutoRouter) RegisterHandlers(handler interface{}) error {
+	handler, bound := unbind(handler)
 	handlerType := reflect.TypeOf(handler)
 	handlerValue := reflect.ValueOf(handler)
 
@@ -73,7 +80,7 @@ func (ar *AutoRouter) RegisterHandlers(handler interface{}) error {
 		}
 
 		// Register the handler
-		if err := ar.registerMethod(handler, methodName, method); err != nil {
+		if err := ar.registerMethod(methodName, ar.handlerFunc(bound, methodName, method)); err != nil {
 			return fmt.Errorf("failed to register method %s: %w", methodName, err)
 		}
 	}
@@ -98,6 +105,7 @@ func (ar *AutoRouter) RegisterHandlersWithAuth(handler interface{}, authMiddlewa
 
 // RegisterSingleMethod registers a single method with custom path
 func (ar *AutoRouter) RegisterSingleMethod(handler interface{}, methodName string, customPath string) error {
+	handler, bound := unbind(handler)
 	handlerValue := reflect.ValueOf(handler)
 	method := handlerValue.MethodByName(methodName)
 	
@@ -110,8 +118,8 @@ func (ar *AutoRouter) RegisterSingleMethod(handler interface{}, methodName strin
 	}
 	
 	// Use custom path instead of auto-generated one
-	handlerFunc := ar.createHandlerFunc(method)
-	finalHandler := ar.applyMiddleware(handlerFunc)
+	handlerFunc := ar.handlerFunc(bound, methodName, method)
+	finalHandler := ar.applyMiddleware(ar.applyGuard(methodName, handlerFunc))
 	
 	fullPath := ar.options.Prefix + customPath
 	ar.mux.HandleFunc(fullPath, finalHandler)
@@ -120,6 +128,12 @@ func (ar *AutoRouter) RegisterSingleMethod(handler interface{}, methodName strin
 	return nil
 }
 
+// RegisterFunc registers a handler function as a method, for methods that aren't backed by
+// a handler struct
+func (ar *AutoRouter) RegisterFunc(methodName string, handlerFunc http.HandlerFunc) error {
+	return ar.registerMethod(methodName, handlerFunc)
+}
+
 // isValidHandlerFunc checks if a method matches the HandlerFunc signature
 // Expected signature: func(http.ResponseWriter, *http.Request)
 func (ar *AutoRouter) isValidHandlerFunc(method reflect.Value) bool {
@@ -166,18 +180,23 @@ func (ar *AutoRouter) isValidHandlerFunc(method reflect.Value) bool {
 }
 
 // registerMethod registers a single method as an HTTP handler
-func (ar *AutoRouter) registerMethod(handler interface{}, methodName string, method reflect.Value) error {
+func (ar *AutoRouter) registerMethod(methodName string, handlerFunc http.HandlerFunc) error {
 	// Build the URL path
 	urlPath := ar.buildURLPath(methodName)
 	
-	// Create the handler function
-	handlerFunc := ar.createHandlerFunc(method)
-	
 	// Apply middleware if any
-	finalHandler := ar.applyMiddleware(handlerFunc)
+	finalHandler := ar.applyMiddleware(ar.applyGuard(methodName, handlerFunc))
 	
 	// Register with the mux
 	ar.mux.HandleFunc(urlPath, finalHandler)
+
+	if ar.options.Registry != nil && ar.options.MethodPrefix != "" {
+		ar.options.Registry.add(ar.options.MethodPrefix+methodName, HandlerInfo{
+			URLPath:    urlPath,
+			MethodName: methodName,
+			HasAuth:    len(ar.options.Middleware) > 0,
+		})
+	}
 	
 	fmt.Printf("Auto-registered: %s -> %s\n", urlPath, methodName)
 	return nil
@@ -198,8 +217,32 @@ func (ar *AutoRouter) buildURLPath(methodName string) string {
 	return path
 }
 
-// createHandlerFunc creates an http.HandlerFunc from a reflect.Value
+// handlerFunc returns the function serving a method: its bound function if the handler was
+// bound, otherwise one created from the method value
+func (ar *AutoRouter) handlerFunc(bound *Bound, methodName string, method reflect.Value) http.HandlerFunc {
+	if fn, ok := bound.lookup(methodName); ok {
+		return fn
+	}
+	return ar.createHandlerFunc(method)
+}
+
+// createHandlerFunc creates an http.HandlerFunc from a reflect.Value. The method value is
+// converted to a typed func once here, which spares requests building arguments for
+// reflect.Call; calling it still goes through reflect's method trampoline (see Bind).
 func (ar *AutoRouter) createHandlerFunc(method reflect.Value) http.HandlerFunc {
+	switch fn := method.Interface().(type) {
+	case func(http.ResponseWriter, *http.Request):
+		return fn
+	case func(http.ResponseWriter, *http.Request) error:
+		return func(w http.ResponseWriter, r *http.Request) {
+			if err := fn(w, r); err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+			}
+		}
+	}
+
+	// Signatures a type assertion can't name, such as a concrete error type in the result,
+	// still go through reflection
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Call the method with the parameters
 		args := []reflect.Value{
@@ -234,6 +277,18 @@ func (ar *AutoRouter) applyMiddleware(handler http.HandlerFunc) http.HandlerFunc
 	return h.ServeHTTP
 }
 
+// applyGuard applies the guard of a method to its handler
+func (ar *AutoRouter) applyGuard(methodName string, handler http.HandlerFunc) http.HandlerFunc {
+	if ar.options.Guard == nil {
+		return handler
+	}
+	guard := ar.options.Guard(methodName)
+	if guard == nil {
+		return handler
+	}
+	return guard(handler).ServeHTTP
+}
+
 // HandlerInfo provides information about registered handlers
 type HandlerInfo struct {
 	URLPath    string
@@ -246,6 +301,7 @@ type HandlerInfo struct {
 func (ar *AutoRouter) GetRegisteredHandlers(handler interface{}) []HandlerInfo {
 	var handlers []HandlerInfo
 	
+	handler, _ = unbind(handler)
 	handlerType := reflect.TypeOf(handler)
 	handlerValue := reflect.ValueOf(handler)
 
@@ -297,6 +353,46 @@ func (ar *AutoRouter) PrintRegisteredHandlers(handler interface{}) {
 	fmt.Println("=================================")
 }
 
+// Registry records the methods routers registered under their JSON-RPC names
+// (e.g. "trainer.Create"), so requests can be dispatched by the name alone
+type Registry struct {
+	mutex   sync.RWMutex
+	methods map[string]HandlerInfo
+}
+
+// NewRegistry creates an empty method registry
+func NewRegistry() *Registry {
+	return &Registry{methods: make(map[string]HandlerInfo)}
+}
+
+// add records a registered method
+func (reg *Registry) add(name string, info HandlerInfo) {
+	reg.mutex.Lock()
+	defer reg.mutex.Unlock()
+	reg.methods[name] = info
+}
+
+// Lookup returns the handler registered under a JSON-RPC method name
+func (reg *Registry) Lookup(name string) (HandlerInfo, bool) {
+	reg.mutex.RLock()
+	defer reg.mutex.RUnlock()
+	info, ok := reg.methods[name]
+	return info, ok
+}
+
+// Methods returns the registered JSON-RPC method names in order
+func (reg *Registry) Methods() []string {
+	reg.mutex.RLock()
+	defer reg.mutex.RUnlock()
+
+	names := make([]string, 0, len(reg.methods))
+	for name := range reg.methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 // isExported reports whether name is an exported Go symbol
 func isExported(name string) bool {
 	r := rune(name[0])
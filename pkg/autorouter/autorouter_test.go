@@ -1,9 +1,11 @@
 package autorouter
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 )
@@ -141,6 +143,43 @@ func TestMiddleware(t *testing.T) {
 	}
 }
 
+// TestGuard tests that a guard protects only the methods it returns middleware for
+func TestGuard(t *testing.T) {
+	mux := http.NewServeMux()
+	handler := &TestHandler{name: "guard"}
+
+	forbid := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+	}
+
+	router := NewAutoRouter(mux, RegistrationOptions{
+		Prefix:       "/api/v1/",
+		MethodPrefix: "test.",
+		Guard: func(methodName string) Middleware {
+			if methodName == "Delete" {
+				return forbid
+			}
+			return nil
+		},
+	})
+	if err := router.RegisterHandlers(handler); err != nil {
+		t.Fatalf("Registration with guard failed: %v", err)
+	}
+
+	for path, want := range map[string]int{
+		"/api/v1/test.Get":    http.StatusOK,
+		"/api/v1/test.Delete": http.StatusForbidden,
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, httptest.NewRequest("POST", path, nil))
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
 // TestRegistrationWithAuth tests auth middleware registration
 func TestRegistrationWithAuth(t *testing.T) {
 	mux := http.NewServeMux()
@@ -258,4 +297,132 @@ func TestSingleMethodRegistration(t *testing.T) {
 	if !strings.Contains(w.Body.String(), "Got data from single") {
 		t.Errorf("Unexpected response body: %s", w.Body.String())
 	}
-}
\ No newline at end of file
+}
+
+// failingHandler has methods returning an error
+type failingHandler struct{}
+
+// Fail always fails
+func (h *failingHandler) Fail(w http.ResponseWriter, r *http.Request) error {
+	return errors.New("handler failed")
+}
+
+// Succeed writes a response and returns no error
+func (h *failingHandler) Succeed(w http.ResponseWriter, r *http.Request) error {
+	fmt.Fprint(w, "ok")
+	return nil
+}
+
+// TestErrorReturningHandlers tests that a returned error becomes a 500 response, whether the
+// handler is bound or not
+func TestErrorReturningHandlers(t *testing.T) {
+	for name, handler := range map[string]interface{}{
+		"unbound": &failingHandler{},
+		"bound":   Bind(&failingHandler{}),
+	} {
+		mux := http.NewServeMux()
+
+		if err := QuickRegister(mux, "/api/", "", handler); err != nil {
+			t.Fatalf("%s: registration failed: %v", name, err)
+		}
+
+		req := httptest.NewRequest("POST", "/api/fail", nil)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		if w.Code != http.StatusInternalServerError {
+			t.Errorf("%s: expected status 500, got %d", name, w.Code)
+		}
+		if !strings.Contains(w.Body.String(), "handler failed") {
+			t.Errorf("%s: unexpected response body: %s", name, w.Body.String())
+		}
+
+		req = httptest.NewRequest("POST", "/api/succeed", nil)
+		w = httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK || w.Body.String() != "ok" {
+			t.Errorf("%s: expected 200 ok, got %d %s", name, w.Code, w.Body.String())
+		}
+	}
+}
+
+// TestBindRegistration tests that a bound handler registers the same routes as the handler
+func TestBindRegistration(t *testing.T) {
+	mux := http.NewServeMux()
+	handler := &TestHandler{name: "bound"}
+	router := NewAutoRouter(mux, RegistrationOptions{Prefix: "/api/"})
+
+	if err := router.RegisterHandlers(Bind(handler)); err != nil {
+		t.Fatalf("Registration failed: %v", err)
+	}
+
+	if got, want := len(router.GetRegisteredHandlers(Bind(handler))), len(router.GetRegisteredHandlers(handler)); got != want {
+		t.Errorf("Expected %d bound handlers, got %d", want, got)
+	}
+
+	req := httptest.NewRequest("POST", "/api/create", nil)
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated || w.Body.String() != "Created by bound" {
+		t.Errorf("Expected 201 from the bound handler, got %d %s", w.Code, w.Body.String())
+	}
+
+	req = httptest.NewRequest("POST", "/api/handlesomething", nil)
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Handle* methods should stay unregistered when bound, got %d", w.Code)
+	}
+}
+
+// benchHandler does no work, so only the dispatch is measured
+type benchHandler struct{}
+
+// Noop handles a request by doing nothing
+func (h *benchHandler) Noop(w http.ResponseWriter, r *http.Request) {}
+
+// BenchmarkDispatch compares the ways a registered method can be called. "bound" is how the
+// server registers handlers and should cost about what "direct" does, with no allocations;
+// "method-value" is the unbound path and "reflect" a reflect.Call per request.
+func BenchmarkDispatch(b *testing.B) {
+	handler := &benchHandler{}
+	router := NewAutoRouter(http.NewServeMux(), RegistrationOptions{})
+	method := reflect.ValueOf(handler).MethodByName("Noop")
+
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "/noop", nil)
+
+	b.Run("direct", func(b *testing.B) {
+		var dispatch http.HandlerFunc = handler.Noop
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dispatch(w, r)
+		}
+	})
+
+	b.Run("bound", func(b *testing.B) {
+		dispatch := router.handlerFunc(Bind(handler), "Noop", method)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dispatch(w, r)
+		}
+	})
+
+	b.Run("method-value", func(b *testing.B) {
+		dispatch := router.createHandlerFunc(method)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			dispatch(w, r)
+		}
+	})
+
+	b.Run("reflect", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			method.Call([]reflect.Value{reflect.ValueOf(w), reflect.ValueOf(r)})
+		}
+	})
+}
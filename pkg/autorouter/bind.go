@@ -0,0 +1,58 @@
+package autorouter
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// Bound is a handler whose methods were resolved to typed functions when it was bound. Routers
+// registering it call those functions directly; an unbound handler's methods are called
+// through reflect method values, which cost a reflective call and an allocation per request.
+type Bound struct {
+	handler interface{}
+	funcs   map[string]http.HandlerFunc
+}
+
+// Bind resolves the handler methods of handler for RegisterHandlers. Each method is taken as a
+// method expression of *T, which, unlike a reflect method value, calls the method's code
+// directly once it has its static type.
+func Bind[T any](handler *T) *Bound {
+	handlerType := reflect.TypeOf(handler)
+	bound := &Bound{handler: handler, funcs: make(map[string]http.HandlerFunc)}
+
+	for i := 0; i < handlerType.NumMethod(); i++ {
+		method := handlerType.Method(i)
+
+		switch fn := method.Func.Interface().(type) {
+		case func(*T, http.ResponseWriter, *http.Request):
+			bound.funcs[method.Name] = func(w http.ResponseWriter, r *http.Request) {
+				fn(handler, w, r)
+			}
+		case func(*T, http.ResponseWriter, *http.Request) error:
+			bound.funcs[method.Name] = func(w http.ResponseWriter, r *http.Request) {
+				if err := fn(handler, w, r); err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+				}
+			}
+		}
+	}
+
+	return bound
+}
+
+// unbind returns the handler to enumerate methods of and its bound functions, if any
+func unbind(handler interface{}) (interface{}, *Bound) {
+	if bound, ok := handler.(*Bound); ok {
+		return bound.handler, bound
+	}
+	return handler, nil
+}
+
+// lookup returns the bound function of a method
+func (b *Bound) lookup(methodName string) (http.HandlerFunc, bool) {
+	if b == nil {
+		return nil, false
+	}
+	fn, ok := b.funcs[methodName]
+	return fn, ok
+}
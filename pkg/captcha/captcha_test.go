@@ -0,0 +1,54 @@
+package captcha
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	verifier, err := New(Config{})
+	require.NoError(t, err)
+	assert.NoError(t, verifier.Verify(context.Background(), "", ""), "development setups accept every token")
+	assert.Nil(t, verifier.Widget())
+
+	_, err = New(Config{Provider: "recaptcha", Secret: "secret"})
+	assert.Error(t, err)
+
+	_, err = New(Config{Provider: ProviderTurnstile})
+	assert.Error(t, err, "providers need a secret")
+}
+
+func TestSiteVerify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "secret", r.PostForm.Get("secret"))
+		switch r.PostForm.Get("response") {
+		case "solved":
+			assert.Equal(t, "203.0.113.7", r.PostForm.Get("remoteip"))
+			w.Write([]byte(`{"success":true}`))
+		case "broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+		}
+	}))
+	defer server.Close()
+
+	verifier, err := New(Config{Provider: ProviderHCaptcha, Secret: "secret", SiteKey: "site", VerifyURL: server.URL})
+	require.NoError(t, err)
+	assert.Equal(t, &Widget{Provider: ProviderHCaptcha, SiteKey: "site"}, verifier.Widget())
+
+	ctx := context.Background()
+	assert.NoError(t, verifier.Verify(ctx, "solved", "203.0.113.7"))
+	assert.ErrorIs(t, verifier.Verify(ctx, "forged", ""), ErrRejected)
+	assert.ErrorIs(t, verifier.Verify(ctx, " ", ""), ErrRejected, "missing tokens aren't sent")
+
+	err = verifier.Verify(ctx, "broken", "")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrRejected, "provider outages are not rejections")
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+
+	"github.com/danghamo/life/pkg/secrets"
+)
+
+const (
+	// devJWTSecret is the JWT secret of development setups, refused in production
+	devJWTSecret = "dev-jwt-secret-change-in-production"
+
+	// secretsTimeout bounds reading every secret from the provider
+	secretsTimeout = 30 * time.Second
+)
+
+// secretField is a config value the secrets provider fills in. Its secret is named like its
+// config key.
+type secretField struct {
+	key   string
+	value *string
+	list  *[]string // Instead of value for comma separated lists
+}
+
+// secretFields lists the config values that are secrets
+func secretFields(cfg *Config) []secretField {
+	return []secretField{
+		{key: "auth.jwt_secret", value: &cfg.Auth.JWTSecret},
+		{key: "auth.oauth.google.client_secret", value: &cfg.Auth.OAuth.Google.ClientSecret},
+		{key: "auth.oauth.github.client_secret", value: &cfg.Auth.OAuth.GitHub.ClientSecret},
+		{key: "auth.oauth.discord.client_secret", value: &cfg.Auth.OAuth.Discord.ClientSecret},
+		{key: "auth.captcha.secret", value: &cfg.Auth.Captcha.Secret},
+		{key: "storage.postgres_url", value: &cfg.Storage.PostgresURL},
+		{key: "mail.smtp_password", value: &cfg.Mail.SMTPPassword},
+		{key: "crypto.pii_keys", list: &cfg.Crypto.PIIKeys},
+		{key: "crypto.pii_index_key", value: &cfg.Crypto.PIIIndexKey},
+		{key: "analytics.pseudonym_secret", value: &cfg.Analytics.PseudonymSecret},
+		{key: "archive.s3.access_key", value: &cfg.Archive.S3.AccessKey},
+		{key: "archive.s3.secret_key", value: &cfg.Archive.S3.SecretKey},
+	}
+}
+
+// requiredSecrets returns the secrets the configured features need
+func requiredSecrets(cfg *Config) []string {
+	required := []string{"auth.jwt_secret"}
+	if cfg.Auth.OAuth.Google.ClientID != "" {
+		required = append(required, "auth.oauth.google.client_secret")
+	}
+	if cfg.Auth.OAuth.GitHub.ClientID != "" {
+		required = append(required, "auth.oauth.github.client_secret")
+	}
+	if cfg.Auth.OAuth.Discord.ClientID != "" {
+		required = append(required, "auth.oauth.discord.client_secret")
+	}
+	if cfg.Auth.Captcha.Provider != "" {
+		required = append(required, "auth.captcha.secret")
+	}
+	if cfg.Storage.UsesPostgres() {
+		required = append(required, "storage.postgres_url")
+	}
+	if cfg.Mail.SMTPUsername != "" {
+		required = append(required, "mail.smtp_password")
+	}
+	if len(cfg.Crypto.PIIKeys) > 0 {
+		required = append(required, "crypto.pii_index_key")
+	}
+	if len(cfg.Analytics.Sinks) > 0 {
+		required = append(required, "analytics.pseudonym_secret")
+	}
+	if cfg.Archive.Store == "s3" {
+		required = append(required, "archive.s3.access_key", "archive.s3.secret_key")
+	}
+	return required
+}
+
+// resolveSecrets fills in the secrets of cfg from the configured provider, keeping configured
+// values of secrets the provider doesn't have, and checks the required secrets resolved. In
+// production secrets may not be written in the config file, and the development JWT secret
+// is refused.
+func resolveSecrets(cfg *Config) error {
+	provider, err := secrets.New(secrets.Config{
+		Kind: cfg.Secrets.Provider,
+		Dir:  cfg.Secrets.Dir,
+		Vault: secrets.VaultConfig{
+			Address: cfg.Secrets.Vault.Address,
+			Mount:   cfg.Secrets.Vault.Mount,
+			Path:    cfg.Secrets.Vault.Path,
+		},
+		AWS: secrets.AWSConfig{
+			Region:   cfg.Secrets.AWS.Region,
+			SecretID: cfg.Secrets.AWS.SecretID,
+			Endpoint: cfg.Secrets.AWS.Endpoint,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), secretsTimeout)
+	defer cancel()
+
+	set := make(map[string]bool)
+	for _, field := range secretFields(cfg) {
+		if cfg.Server.IsProduction() && viper.InConfig(field.key) {
+			return fmt.Errorf("secret %s is set in the config file; production reads it from the %s secrets provider", field.key, cfg.Secrets.Provider)
+		}
+
+		value, err := provider.Get(ctx, field.key)
+		switch {
+		case errors.Is(err, secrets.ErrNotFound):
+		case err != nil:
+			return fmt.Errorf("failed to read secret %s: %w", field.key, err)
+		case field.list != nil:
+			*field.list = strings.Split(value, ",")
+		default:
+			*field.value = value
+		}
+
+		if field.list != nil {
+			set[field.key] = len(*field.list) > 0
+		} else {
+			set[field.key] = *field.value != ""
+		}
+	}
+
+	var missing []string
+	for _, key := range requiredSecrets(cfg) {
+		if !set[key] {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("required secrets are missing: %s", strings.Join(missing, ", "))
+	}
+
+	if cfg.Server.IsProduction() && cfg.Auth.JWTSecret == devJWTSecret {
+		return fmt.Errorf("auth.jwt_secret must be set in production")
+	}
+	return nil
+}
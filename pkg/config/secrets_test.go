@@ -0,0 +1,38 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveSecrets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "auth.jwt_secret"), []byte("from-file\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "crypto.pii_keys"), []byte("k2:bmV3,k1:b2xk"), 0o600))
+
+	cfg := &Config{Secrets: SecretsConfig{Provider: "file", Dir: dir}}
+	cfg.Auth.JWTSecret = devJWTSecret
+	cfg.Crypto.PIIIndexKey = "index"
+	require.NoError(t, resolveSecrets(cfg))
+	assert.Equal(t, "from-file", cfg.Auth.JWTSecret, "secrets override configured values")
+	assert.Equal(t, []string{"k2:bmV3", "k1:b2xk"}, cfg.Crypto.PIIKeys)
+
+	// Enabled features need their secrets
+	cfg.Auth.OAuth.GitHub.ClientID = "github-app"
+	cfg.Auth.Captcha.Provider = "turnstile"
+	cfg.Storage.Driver = "postgres"
+	assert.ErrorContains(t, resolveSecrets(cfg), "auth.oauth.github.client_secret, auth.captcha.secret, storage.postgres_url")
+
+	// Production refuses the development JWT secret
+	production := &Config{Server: ServerConfig{Environment: "production"}}
+	production.Auth.JWTSecret = devJWTSecret
+	assert.ErrorContains(t, resolveSecrets(production), "must be set in production")
+
+	t.Setenv("AUTH_JWT_SECRET", "from-env")
+	require.NoError(t, resolveSecrets(production))
+	assert.Equal(t, "from-env", production.Auth.JWTSecret)
+}
@@ -0,0 +1,242 @@
+// Package eventarchive lays out game events archived to object storage. Events are stored as
+// gzipped JSON lines, partitioned by stream, day and hour:
+//
+//	<base>/events/<stream>/dt=2026-10-15/hour=13/<first ID>_<last ID>.jsonl.gz
+//
+// and every day of a stream has a manifest listing its files, so readers find the files of
+// a time range without listing the bucket:
+//
+//	<base>/manifests/<stream>/dt=2026-10-15.json
+package eventarchive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/danghamo/life/pkg/objstore"
+)
+
+// Event is one archived stream entry, with the fields needed to publish it again
+type Event struct {
+	Stream     string `json:"stream"`
+	ID         string `json:"id"`          // Redis stream entry ID
+	OccurredAt int64  `json:"occurred_at"` // Unix milliseconds, from the entry ID
+	UUID       string `json:"uuid"`        // Message UUID
+	Metadata   []byte `json:"metadata,omitempty"`
+	Payload    string `json:"payload"`
+}
+
+// Time returns when the event was added to its stream
+func (e Event) Time() time.Time {
+	return time.UnixMilli(e.OccurredAt).UTC()
+}
+
+// File describes one archived file in a manifest
+type File struct {
+	Key     string    `json:"key"`
+	FirstID string    `json:"first_id"`
+	LastID  string    `json:"last_id"`
+	Count   int       `json:"count"`
+	FirstAt time.Time `json:"first_at"`
+	LastAt  time.Time `json:"last_at"`
+	Bytes   int       `json:"bytes"`
+}
+
+// Manifest lists the files archived for one day of a stream
+type Manifest struct {
+	Stream string `json:"stream"`
+	Date   string `json:"date"` // YYYY-MM-DD, UTC
+	Files  []File `json:"files"`
+}
+
+// Add records a file, replacing an earlier record of the same key
+func (m *Manifest) Add(file File) {
+	for i, existing := range m.Files {
+		if existing.Key == file.Key {
+			m.Files[i] = file
+			return
+		}
+	}
+	m.Files = append(m.Files, file)
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].FirstAt.Before(m.Files[j].FirstAt) })
+}
+
+// Partition groups consecutive events of the same hour; events must be in stream order
+func Partition(events []Event) [][]Event {
+	var groups [][]Event
+	for i, event := range events {
+		if i == 0 || !sameHour(events[i-1].Time(), event.Time()) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], event)
+	}
+	return groups
+}
+
+// FileKey returns the object key of a group of one hour's events
+func FileKey(base string, events []Event) string {
+	first, last := events[0], events[len(events)-1]
+	at := first.Time()
+	return path.Join(base, "events", first.Stream,
+		"dt="+at.Format("2006-01-02"), fmt.Sprintf("hour=%02d", at.Hour()),
+		fmt.Sprintf("%s_%s.jsonl.gz", first.ID, last.ID))
+}
+
+// ManifestKey returns the object key of a stream's manifest for the day of at
+func ManifestKey(base, stream string, at time.Time) string {
+	return path.Join(base, "manifests", stream, "dt="+at.UTC().Format("2006-01-02")+".json")
+}
+
+// Encode writes events as gzipped JSON lines
+func Encode(events []Event) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	encoder := json.NewEncoder(zw)
+	for _, event := range events {
+		if err := encoder.Encode(event); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Decode reads gzipped JSON lines
+func Decode(data []byte) ([]Event, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(zr)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// Write archives one hour's events of a stream and records the file in the day's manifest.
+// Writing the same events again replaces the file and its record, so retries are safe.
+func Write(ctx context.Context, store objstore.Store, base string, events []Event) (File, error) {
+	data, err := Encode(events)
+	if err != nil {
+		return File{}, err
+	}
+
+	first, last := events[0], events[len(events)-1]
+	file := File{
+		Key:     FileKey(base, events),
+		FirstID: first.ID,
+		LastID:  last.ID,
+		Count:   len(events),
+		FirstAt: first.Time(),
+		LastAt:  last.Time(),
+		Bytes:   len(data),
+	}
+	if err := store.Put(ctx, file.Key, data); err != nil {
+		return File{}, err
+	}
+
+	manifest, err := ReadManifest(ctx, store, base, first.Stream, first.Time())
+	if err != nil {
+		return File{}, err
+	}
+	manifest.Add(file)
+
+	encoded, err := json.Marshal(manifest)
+	if err != nil {
+		return File{}, err
+	}
+	if err := store.Put(ctx, ManifestKey(base, first.Stream, first.Time()), encoded); err != nil {
+		return File{}, err
+	}
+
+	return file, nil
+}
+
+// ReadManifest reads a stream's manifest for the day of at, empty if nothing was archived
+func ReadManifest(ctx context.Context, store objstore.Store, base, stream string, at time.Time) (*Manifest, error) {
+	manifest := &Manifest{Stream: stream, Date: at.UTC().Format("2006-01-02")}
+
+	data, err := store.Get(ctx, ManifestKey(base, stream, at))
+	if errors.Is(err, objstore.ErrNotFound) {
+		return manifest, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest for %s on %s: %w", stream, manifest.Date, err)
+	}
+	return manifest, nil
+}
+
+// Read calls fn with a stream's archived events that occurred in [from, to), in order
+func Read(ctx context.Context, store objstore.Store, base, stream string, from, to time.Time, fn func(Event) error) error {
+	from, to = from.UTC(), to.UTC()
+
+	for day := from.Truncate(24 * time.Hour); day.Before(to); day = day.Add(24 * time.Hour) {
+		manifest, err := ReadManifest(ctx, store, base, stream, day)
+		if err != nil {
+			return err
+		}
+
+		for _, file := range manifest.Files {
+			if file.LastAt.Before(from) || !file.FirstAt.Before(to) {
+				continue
+			}
+
+			data, err := store.Get(ctx, file.Key)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", file.Key, err)
+			}
+			events, err := Decode(data)
+			if err != nil {
+				return fmt.Errorf("failed to decode %s: %w", file.Key, err)
+			}
+
+			for _, event := range events {
+				if at := event.Time(); at.Before(from) || !at.Before(to) {
+					continue
+				}
+				if err := fn(event); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// TenantBase returns the base path of a tenant's archive; the default tenant's is base itself
+func TenantBase(base, tenantID string) string {
+	if tenantID == "" {
+		return strings.TrimSuffix(base, "/")
+	}
+	return path.Join(base, "tenants", tenantID)
+}
+
+// sameHour reports whether two times fall in the same UTC hour
+func sameHour(a, b time.Time) bool {
+	return a.Truncate(time.Hour).Equal(b.Truncate(time.Hour))
+}
@@ -0,0 +1,58 @@
+package eventarchive
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/pkg/objstore"
+)
+
+func event(id string, at time.Time) Event {
+	return Event{Stream: "game-events.LootDroppedEvent", ID: id, OccurredAt: at.UnixMilli(), UUID: id, Payload: `{"user_id":"u1"}`}
+}
+
+func TestPartition_ByHour(t *testing.T) {
+	base := time.Date(2026, 10, 15, 13, 58, 0, 0, time.UTC)
+	events := []Event{
+		event("1-0", base),
+		event("2-0", base.Add(time.Minute)),
+		event("3-0", base.Add(3*time.Minute)),
+	}
+
+	groups := Partition(events)
+	require.Len(t, groups, 2)
+	assert.Len(t, groups[0], 2)
+	assert.Equal(t, "archive/events/game-events.LootDroppedEvent/dt=2026-10-15/hour=14/3-0_3-0.jsonl.gz", FileKey("archive", groups[1]))
+}
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	store, err := objstore.NewDirStore(t.TempDir())
+	require.NoError(t, err)
+
+	day := time.Date(2026, 10, 15, 23, 30, 0, 0, time.UTC)
+	events := []Event{event("1-0", day), event("2-0", day.Add(time.Minute)), event("3-0", day.Add(time.Hour))}
+	for _, group := range Partition(events) {
+		_, err := Write(ctx, store, "", group)
+		require.NoError(t, err)
+	}
+	// Retrying a write doesn't list the file twice
+	_, err = Write(ctx, store, "", events[:2])
+	require.NoError(t, err)
+
+	manifest, err := ReadManifest(ctx, store, "", events[0].Stream, day)
+	require.NoError(t, err)
+	assert.Len(t, manifest.Files, 1)
+
+	var read []string
+	err = Read(ctx, store, "", events[0].Stream, day.Add(30*time.Second), day.Add(2*time.Hour), func(e Event) error {
+		read = append(read, e.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2-0", "3-0"}, read)
+}
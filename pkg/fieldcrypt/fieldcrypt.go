@@ -0,0 +1,158 @@
+// Package fieldcrypt encrypts individual stored fields, such as emails and device IDs, with
+// AES-256-GCM. Values are tagged with the ID of the key that encrypted them so keys can be
+// rotated, and lookups go through keyed hashes (blind indexes) instead of the plaintext.
+package fieldcrypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// prefix marks encrypted values: "enc:<key id>:<base64 nonce and ciphertext>"
+const prefix = "enc:"
+
+// Config holds field encryption keys. Each key is "<id>:<base64 32-byte key>"; the first one
+// encrypts new values and the rest only decrypt values written before a rotation. The index
+// key derives blind indexes and must never change, or lookups of existing records fail.
+type Config struct {
+	Keys     []string `json:"keys"`
+	IndexKey string   `json:"index_key"`
+}
+
+// Cipher encrypts and decrypts fields. A nil or disabled Cipher stores plaintext, so
+// encryption can be switched on for an existing deployment.
+type Cipher struct {
+	current  string
+	keys     map[string]cipher.AEAD
+	indexKey []byte
+}
+
+// New creates a cipher from config. Without keys it returns a disabled cipher.
+func New(cfg Config) (*Cipher, error) {
+	if len(cfg.Keys) == 0 {
+		return &Cipher{}, nil
+	}
+
+	indexKey, err := base64.StdEncoding.DecodeString(cfg.IndexKey)
+	if err != nil || len(indexKey) < 32 {
+		return nil, fmt.Errorf("fieldcrypt: index key must be at least 32 base64-encoded bytes")
+	}
+
+	c := &Cipher{
+		keys:     make(map[string]cipher.AEAD, len(cfg.Keys)),
+		indexKey: indexKey,
+	}
+
+	for i, entry := range cfg.Keys {
+		id, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("fieldcrypt: key %d must be formatted as <id>:<base64 key>", i)
+		}
+		if _, exists := c.keys[id]; exists {
+			return nil, fmt.Errorf("fieldcrypt: duplicate key id %q", id)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("fieldcrypt: key %q must be 32 base64-encoded bytes", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+
+		c.keys[id] = aead
+		if i == 0 {
+			c.current = id
+		}
+	}
+
+	return c, nil
+}
+
+// Enabled reports whether fields are encrypted
+func (c *Cipher) Enabled() bool {
+	return c != nil && c.current != ""
+}
+
+// Encrypt encrypts a value with the current key. Empty values stay empty.
+func (c *Cipher) Encrypt(plaintext string) (string, error) {
+	if !c.Enabled() || plaintext == "" {
+		return plaintext, nil
+	}
+
+	aead := c.keys[c.current]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), []byte(c.current))
+	return prefix + c.current + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a value from Encrypt. Values without the encryption prefix were stored
+// before encryption was enabled and are returned unchanged.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if !strings.HasPrefix(value, prefix) {
+		return value, nil
+	}
+	if c == nil {
+		return "", fmt.Errorf("fieldcrypt: value is encrypted but no keys are configured")
+	}
+
+	id, encoded, ok := strings.Cut(strings.TrimPrefix(value, prefix), ":")
+	if !ok {
+		return "", fmt.Errorf("fieldcrypt: malformed encrypted value")
+	}
+
+	aead, exists := c.keys[id]
+	if !exists {
+		return "", fmt.Errorf("fieldcrypt: unknown key id %q", id)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("fieldcrypt: malformed encrypted value")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return "", fmt.Errorf("fieldcrypt: decrypt with key %q: %w", id, err)
+	}
+
+	return string(plaintext), nil
+}
+
+// NeedsRotation reports whether a stored value is plaintext or encrypted with an older key
+func (c *Cipher) NeedsRotation(value string) bool {
+	if !c.Enabled() || value == "" {
+		return false
+	}
+	return !strings.HasPrefix(value, prefix+c.current+":")
+}
+
+// Index returns the blind index of a value for use in lookup keys. A disabled cipher returns
+// the value itself, matching the keys written before encryption was enabled.
+func (c *Cipher) Index(value string) string {
+	if !c.Enabled() {
+		return value
+	}
+
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,69 @@
+package fieldcrypt
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testKey(b byte) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(b), 32)))
+}
+
+func TestCipher_EncryptDecrypt(t *testing.T) {
+	c, err := New(Config{Keys: []string{"k1:" + testKey('a')}, IndexKey: testKey('i')})
+	require.NoError(t, err)
+
+	encrypted, err := c.Encrypt("player@example.com")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "enc:k1:"))
+	assert.NotContains(t, encrypted, "player")
+
+	decrypted, err := c.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "player@example.com", decrypted)
+
+	legacy, err := c.Decrypt("plain@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "plain@example.com", legacy, "values stored before encryption are readable")
+	assert.True(t, c.NeedsRotation("plain@example.com"))
+	assert.False(t, c.NeedsRotation(encrypted))
+}
+
+func TestCipher_Rotation(t *testing.T) {
+	old, err := New(Config{Keys: []string{"k1:" + testKey('a')}, IndexKey: testKey('i')})
+	require.NoError(t, err)
+	encrypted, err := old.Encrypt("device-1")
+	require.NoError(t, err)
+
+	rotated, err := New(Config{Keys: []string{"k2:" + testKey('b'), "k1:" + testKey('a')}, IndexKey: testKey('i')})
+	require.NoError(t, err)
+
+	decrypted, err := rotated.Decrypt(encrypted)
+	require.NoError(t, err)
+	assert.Equal(t, "device-1", decrypted)
+	assert.True(t, rotated.NeedsRotation(encrypted))
+	assert.Equal(t, old.Index("device-1"), rotated.Index("device-1"), "indexes survive key rotation")
+
+	retired, err := New(Config{Keys: []string{"k2:" + testKey('b')}, IndexKey: testKey('i')})
+	require.NoError(t, err)
+	_, err = retired.Decrypt(encrypted)
+	assert.Error(t, err)
+}
+
+func TestCipher_Disabled(t *testing.T) {
+	c, err := New(Config{})
+	require.NoError(t, err)
+	assert.False(t, c.Enabled())
+
+	value, err := c.Encrypt("device-1")
+	require.NoError(t, err)
+	assert.Equal(t, "device-1", value)
+	assert.Equal(t, "device-1", c.Index("device-1"))
+
+	_, err = New(Config{Keys: []string{"k1:" + testKey('a')}})
+	assert.Error(t, err, "an index key is required with encryption")
+}
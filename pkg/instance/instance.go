@@ -0,0 +1,44 @@
+// Package instance separates the rooms of a tenant. Data scoped to a room, such as the bullets
+// flying in it, has its Redis keys under the room's prefix within the tenant's, so players
+// in different rooms never meet.
+package instance
+
+import "context"
+
+// ID identifies an instance
+type ID string
+
+// None is the instance of data shared by the whole tenant. Its keys are not prefixed.
+const None ID = ""
+
+// String returns the ID as a string
+func (id ID) String() string {
+	return string(id)
+}
+
+// Prefix returns the prefix of the instance's Redis keys, following the tenant's
+func (id ID) Prefix() string {
+	if id == None {
+		return ""
+	}
+	return "i:" + string(id) + ":"
+}
+
+// Key namespaces a Redis key for the instance
+func (id ID) Key(key string) string {
+	return id.Prefix() + key
+}
+
+// contextKey is the context key of the instance
+type contextKey struct{}
+
+// WithInstance returns a context whose Redis commands belong to the instance id
+func WithInstance(ctx context.Context, id ID) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// IDFromContext returns the instance a context was scoped to, None if it has none
+func IDFromContext(ctx context.Context) ID {
+	id, _ := ctx.Value(contextKey{}).(ID)
+	return id
+}
@@ -0,0 +1,57 @@
+// Package jsonx encodes hot-path types with generated marshalers when the binary is built with
+// the easyjson tag, and with encoding/json otherwise.
+//
+// Generated marshalers live in *_easyjson.go files carrying the easyjson build constraint and
+// are regenerated with go generate. They only add MarshalEasyJSON and UnmarshalEasyJSON, so
+// encoding/json behaves the same with or without the tag; only calls through this package
+// take the fast path.
+package jsonx
+
+import (
+	"encoding/json"
+	"io"
+
+	"github.com/mailru/easyjson"
+)
+
+// Marshal encodes v. A hand-written MarshalJSON always wins over a generated marshaler.
+func Marshal(v any) ([]byte, error) {
+	if _, ok := v.(json.Marshaler); !ok {
+		if m, ok := v.(easyjson.Marshaler); ok {
+			return easyjson.Marshal(m)
+		}
+	}
+	return json.Marshal(v)
+}
+
+// Unmarshal decodes data into v. A hand-written UnmarshalJSON, such as one migrating old
+// documents, always wins over a generated unmarshaler.
+func Unmarshal(data []byte, v any) error {
+	if _, ok := v.(json.Unmarshaler); !ok {
+		if u, ok := v.(easyjson.Unmarshaler); ok {
+			return easyjson.Unmarshal(data, u)
+		}
+	}
+	return json.Unmarshal(data, v)
+}
+
+// Encode writes v followed by a newline, like json.Encoder.Encode
+func Encode(w io.Writer, v any) error {
+	if _, ok := v.(json.Marshaler); !ok {
+		if m, ok := v.(easyjson.Marshaler); ok {
+			if _, err := easyjson.MarshalToWriter(m, w); err != nil {
+				return err
+			}
+			_, err := w.Write([]byte{'\n'})
+			return err
+		}
+	}
+	return json.NewEncoder(w).Encode(v)
+}
+
+// Generated reports whether v has a generated marshaler, i.e. whether the binary was built
+// with the easyjson tag
+func Generated(v any) bool {
+	_, ok := v.(easyjson.Marshaler)
+	return ok
+}
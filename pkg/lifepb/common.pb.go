@@ -0,0 +1,132 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v5.28.3
+// source: lifepb/common.proto
+
+package lifepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Position is a point in the world, in tiles
+type Position struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             float64                `protobuf:"fixed64,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             float64                `protobuf:"fixed64,2,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Position) Reset() {
+	*x = Position{}
+	mi := &file_lifepb_common_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Position) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Position) ProtoMessage() {}
+
+func (x *Position) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_common_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Position.ProtoReflect.Descriptor instead.
+func (*Position) Descriptor() ([]byte, []int) {
+	return file_lifepb_common_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Position) GetX() float64 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Position) GetY() float64 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+var File_lifepb_common_proto protoreflect.FileDescriptor
+
+const file_lifepb_common_proto_rawDesc = "" +
+	"\n" +
+	"\x13lifepb/common.proto\x12\alife.v1\"&\n" +
+	"\bPosition\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x01R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x01R\x01yB%Z#github.com/danghamo/life/pkg/lifepbb\x06proto3"
+
+var (
+	file_lifepb_common_proto_rawDescOnce sync.Once
+	file_lifepb_common_proto_rawDescData []byte
+)
+
+func file_lifepb_common_proto_rawDescGZIP() []byte {
+	file_lifepb_common_proto_rawDescOnce.Do(func() {
+		file_lifepb_common_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lifepb_common_proto_rawDesc), len(file_lifepb_common_proto_rawDesc)))
+	})
+	return file_lifepb_common_proto_rawDescData
+}
+
+var file_lifepb_common_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_lifepb_common_proto_goTypes = []any{
+	(*Position)(nil), // 0: life.v1.Position
+}
+var file_lifepb_common_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_lifepb_common_proto_init() }
+func file_lifepb_common_proto_init() {
+	if File_lifepb_common_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lifepb_common_proto_rawDesc), len(file_lifepb_common_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_lifepb_common_proto_goTypes,
+		DependencyIndexes: file_lifepb_common_proto_depIdxs,
+		MessageInfos:      file_lifepb_common_proto_msgTypes,
+	}.Build()
+	File_lifepb_common_proto = out.File
+	file_lifepb_common_proto_goTypes = nil
+	file_lifepb_common_proto_depIdxs = nil
+}
@@ -0,0 +1,5 @@
+// Package lifepb holds the protobuf definitions of the gRPC API the game serves to other
+// backend services, and the Go code generated from them.
+package lifepb
+
+//go:generate protoc -I .. --go_out=.. --go_opt=module=github.com/danghamo/life/pkg --go-grpc_out=.. --go-grpc_opt=module=github.com/danghamo/life/pkg lifepb/common.proto lifepb/trainer.proto lifepb/world.proto lifepb/account.proto
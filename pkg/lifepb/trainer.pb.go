@@ -0,0 +1,751 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v5.28.3
+// source: lifepb/trainer.proto
+
+package lifepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Trainer struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	UserId          string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Nickname        string                 `protobuf:"bytes,2,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	Level           int32                  `protobuf:"varint,3,opt,name=level,proto3" json:"level,omitempty"`
+	Experience      int64                  `protobuf:"varint,4,opt,name=experience,proto3" json:"experience,omitempty"` // Toward the next level
+	TotalExperience int64                  `protobuf:"varint,5,opt,name=total_experience,json=totalExperience,proto3" json:"total_experience,omitempty"`
+	Hp              int32                  `protobuf:"varint,6,opt,name=hp,proto3" json:"hp,omitempty"`
+	Position        *Position              `protobuf:"bytes,7,opt,name=position,proto3" json:"position,omitempty"`
+	Money           int64                  `protobuf:"varint,8,opt,name=money,proto3" json:"money,omitempty"`
+	RespawnAt       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=respawn_at,json=respawnAt,proto3" json:"respawn_at,omitempty"` // Set while the trainer is dead
+	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *Trainer) Reset() {
+	*x = Trainer{}
+	mi := &file_lifepb_trainer_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trainer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trainer) ProtoMessage() {}
+
+func (x *Trainer) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_trainer_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trainer.ProtoReflect.Descriptor instead.
+func (*Trainer) Descriptor() ([]byte, []int) {
+	return file_lifepb_trainer_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Trainer) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Trainer) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+func (x *Trainer) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *Trainer) GetExperience() int64 {
+	if x != nil {
+		return x.Experience
+	}
+	return 0
+}
+
+func (x *Trainer) GetTotalExperience() int64 {
+	if x != nil {
+		return x.TotalExperience
+	}
+	return 0
+}
+
+func (x *Trainer) GetHp() int32 {
+	if x != nil {
+		return x.Hp
+	}
+	return 0
+}
+
+func (x *Trainer) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *Trainer) GetMoney() int64 {
+	if x != nil {
+		return x.Money
+	}
+	return 0
+}
+
+func (x *Trainer) GetRespawnAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RespawnAt
+	}
+	return nil
+}
+
+func (x *Trainer) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Trainer) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type GetTrainerRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTrainerRequest) Reset() {
+	*x = GetTrainerRequest{}
+	mi := &file_lifepb_trainer_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTrainerRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTrainerRequest) ProtoMessage() {}
+
+func (x *GetTrainerRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_trainer_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTrainerRequest.ProtoReflect.Descriptor instead.
+func (*GetTrainerRequest) Descriptor() ([]byte, []int) {
+	return file_lifepb_trainer_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetTrainerRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type OnlinePlayer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Nickname      string                 `protobuf:"bytes,2,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	Level         int32                  `protobuf:"varint,3,opt,name=level,proto3" json:"level,omitempty"`
+	Position      *Position              `protobuf:"bytes,4,opt,name=position,proto3" json:"position,omitempty"`
+	IsMoving      bool                   `protobuf:"varint,5,opt,name=is_moving,json=isMoving,proto3" json:"is_moving,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OnlinePlayer) Reset() {
+	*x = OnlinePlayer{}
+	mi := &file_lifepb_trainer_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OnlinePlayer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OnlinePlayer) ProtoMessage() {}
+
+func (x *OnlinePlayer) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_trainer_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OnlinePlayer.ProtoReflect.Descriptor instead.
+func (*OnlinePlayer) Descriptor() ([]byte, []int) {
+	return file_lifepb_trainer_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *OnlinePlayer) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *OnlinePlayer) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+func (x *OnlinePlayer) GetLevel() int32 {
+	if x != nil {
+		return x.Level
+	}
+	return 0
+}
+
+func (x *OnlinePlayer) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *OnlinePlayer) GetIsMoving() bool {
+	if x != nil {
+		return x.IsMoving
+	}
+	return false
+}
+
+type ListOnlinePlayersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOnlinePlayersRequest) Reset() {
+	*x = ListOnlinePlayersRequest{}
+	mi := &file_lifepb_trainer_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOnlinePlayersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOnlinePlayersRequest) ProtoMessage() {}
+
+func (x *ListOnlinePlayersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_trainer_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOnlinePlayersRequest.ProtoReflect.Descriptor instead.
+func (*ListOnlinePlayersRequest) Descriptor() ([]byte, []int) {
+	return file_lifepb_trainer_proto_rawDescGZIP(), []int{3}
+}
+
+type ListOnlinePlayersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Players       []*OnlinePlayer        `protobuf:"bytes,1,rep,name=players,proto3" json:"players,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"` // Across every server, counted even when the list is capped
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOnlinePlayersResponse) Reset() {
+	*x = ListOnlinePlayersResponse{}
+	mi := &file_lifepb_trainer_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOnlinePlayersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOnlinePlayersResponse) ProtoMessage() {}
+
+func (x *ListOnlinePlayersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_trainer_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOnlinePlayersResponse.ProtoReflect.Descriptor instead.
+func (*ListOnlinePlayersResponse) Descriptor() ([]byte, []int) {
+	return file_lifepb_trainer_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ListOnlinePlayersResponse) GetPlayers() []*OnlinePlayer {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *ListOnlinePlayersResponse) GetTotal() int32 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+type TeleportRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Position      *Position              `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TeleportRequest) Reset() {
+	*x = TeleportRequest{}
+	mi := &file_lifepb_trainer_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeleportRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeleportRequest) ProtoMessage() {}
+
+func (x *TeleportRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_trainer_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeleportRequest.ProtoReflect.Descriptor instead.
+func (*TeleportRequest) Descriptor() ([]byte, []int) {
+	return file_lifepb_trainer_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TeleportRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *TeleportRequest) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+type GrantItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ItemType      string                 `protobuf:"bytes,1,opt,name=item_type,json=itemType,proto3" json:"item_type,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Quantity      int32                  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantItem) Reset() {
+	*x = GrantItem{}
+	mi := &file_lifepb_trainer_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantItem) ProtoMessage() {}
+
+func (x *GrantItem) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_trainer_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantItem.ProtoReflect.Descriptor instead.
+func (*GrantItem) Descriptor() ([]byte, []int) {
+	return file_lifepb_trainer_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GrantItem) GetItemType() string {
+	if x != nil {
+		return x.ItemType
+	}
+	return ""
+}
+
+func (x *GrantItem) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GrantItem) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type GrantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Money         int64                  `protobuf:"varint,2,opt,name=money,proto3" json:"money,omitempty"`
+	Items         []*GrantItem           `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantRequest) Reset() {
+	*x = GrantRequest{}
+	mi := &file_lifepb_trainer_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantRequest) ProtoMessage() {}
+
+func (x *GrantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_trainer_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantRequest.ProtoReflect.Descriptor instead.
+func (*GrantRequest) Descriptor() ([]byte, []int) {
+	return file_lifepb_trainer_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GrantRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GrantRequest) GetMoney() int64 {
+	if x != nil {
+		return x.Money
+	}
+	return 0
+}
+
+func (x *GrantRequest) GetItems() []*GrantItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type KickRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KickRequest) Reset() {
+	*x = KickRequest{}
+	mi := &file_lifepb_trainer_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KickRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KickRequest) ProtoMessage() {}
+
+func (x *KickRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_trainer_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KickRequest.ProtoReflect.Descriptor instead.
+func (*KickRequest) Descriptor() ([]byte, []int) {
+	return file_lifepb_trainer_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *KickRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *KickRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type KickResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KickResponse) Reset() {
+	*x = KickResponse{}
+	mi := &file_lifepb_trainer_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KickResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KickResponse) ProtoMessage() {}
+
+func (x *KickResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_trainer_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KickResponse.ProtoReflect.Descriptor instead.
+func (*KickResponse) Descriptor() ([]byte, []int) {
+	return file_lifepb_trainer_proto_rawDescGZIP(), []int{9}
+}
+
+var File_lifepb_trainer_proto protoreflect.FileDescriptor
+
+const file_lifepb_trainer_proto_rawDesc = "" +
+	"\n" +
+	"\x14lifepb/trainer.proto\x12\alife.v1\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x13lifepb/common.proto\"\xa5\x03\n" +
+	"\aTrainer\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bnickname\x18\x02 \x01(\tR\bnickname\x12\x14\n" +
+	"\x05level\x18\x03 \x01(\x05R\x05level\x12\x1e\n" +
+	"\n" +
+	"experience\x18\x04 \x01(\x03R\n" +
+	"experience\x12)\n" +
+	"\x10total_experience\x18\x05 \x01(\x03R\x0ftotalExperience\x12\x0e\n" +
+	"\x02hp\x18\x06 \x01(\x05R\x02hp\x12-\n" +
+	"\bposition\x18\a \x01(\v2\x11.life.v1.PositionR\bposition\x12\x14\n" +
+	"\x05money\x18\b \x01(\x03R\x05money\x129\n" +
+	"\n" +
+	"respawn_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\trespawnAt\x129\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\",\n" +
+	"\x11GetTrainerRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xa5\x01\n" +
+	"\fOnlinePlayer\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bnickname\x18\x02 \x01(\tR\bnickname\x12\x14\n" +
+	"\x05level\x18\x03 \x01(\x05R\x05level\x12-\n" +
+	"\bposition\x18\x04 \x01(\v2\x11.life.v1.PositionR\bposition\x12\x1b\n" +
+	"\tis_moving\x18\x05 \x01(\bR\bisMoving\"\x1a\n" +
+	"\x18ListOnlinePlayersRequest\"b\n" +
+	"\x19ListOnlinePlayersResponse\x12/\n" +
+	"\aplayers\x18\x01 \x03(\v2\x15.life.v1.OnlinePlayerR\aplayers\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\"Y\n" +
+	"\x0fTeleportRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12-\n" +
+	"\bposition\x18\x02 \x01(\v2\x11.life.v1.PositionR\bposition\"X\n" +
+	"\tGrantItem\x12\x1b\n" +
+	"\titem_type\x18\x01 \x01(\tR\bitemType\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x05R\bquantity\"g\n" +
+	"\fGrantRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05money\x18\x02 \x01(\x03R\x05money\x12(\n" +
+	"\x05items\x18\x03 \x03(\v2\x12.life.v1.GrantItemR\x05items\">\n" +
+	"\vKickRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\x0e\n" +
+	"\fKickResponse2\xc7\x02\n" +
+	"\x0eTrainerService\x12:\n" +
+	"\n" +
+	"GetTrainer\x12\x1a.life.v1.GetTrainerRequest\x1a\x10.life.v1.Trainer\x12Z\n" +
+	"\x11ListOnlinePlayers\x12!.life.v1.ListOnlinePlayersRequest\x1a\".life.v1.ListOnlinePlayersResponse\x126\n" +
+	"\bTeleport\x12\x18.life.v1.TeleportRequest\x1a\x10.life.v1.Trainer\x120\n" +
+	"\x05Grant\x12\x15.life.v1.GrantRequest\x1a\x10.life.v1.Trainer\x123\n" +
+	"\x04Kick\x12\x14.life.v1.KickRequest\x1a\x15.life.v1.KickResponseB%Z#github.com/danghamo/life/pkg/lifepbb\x06proto3"
+
+var (
+	file_lifepb_trainer_proto_rawDescOnce sync.Once
+	file_lifepb_trainer_proto_rawDescData []byte
+)
+
+func file_lifepb_trainer_proto_rawDescGZIP() []byte {
+	file_lifepb_trainer_proto_rawDescOnce.Do(func() {
+		file_lifepb_trainer_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lifepb_trainer_proto_rawDesc), len(file_lifepb_trainer_proto_rawDesc)))
+	})
+	return file_lifepb_trainer_proto_rawDescData
+}
+
+var file_lifepb_trainer_proto_msgTypes = make([]protoimpl.MessageInfo, 10)
+var file_lifepb_trainer_proto_goTypes = []any{
+	(*Trainer)(nil),                   // 0: life.v1.Trainer
+	(*GetTrainerRequest)(nil),         // 1: life.v1.GetTrainerRequest
+	(*OnlinePlayer)(nil),              // 2: life.v1.OnlinePlayer
+	(*ListOnlinePlayersRequest)(nil),  // 3: life.v1.ListOnlinePlayersRequest
+	(*ListOnlinePlayersResponse)(nil), // 4: life.v1.ListOnlinePlayersResponse
+	(*TeleportRequest)(nil),           // 5: life.v1.TeleportRequest
+	(*GrantItem)(nil),                 // 6: life.v1.GrantItem
+	(*GrantRequest)(nil),              // 7: life.v1.GrantRequest
+	(*KickRequest)(nil),               // 8: life.v1.KickRequest
+	(*KickResponse)(nil),              // 9: life.v1.KickResponse
+	(*Position)(nil),                  // 10: life.v1.Position
+	(*timestamppb.Timestamp)(nil),     // 11: google.protobuf.Timestamp
+}
+var file_lifepb_trainer_proto_depIdxs = []int32{
+	10, // 0: life.v1.Trainer.position:type_name -> life.v1.Position
+	11, // 1: life.v1.Trainer.respawn_at:type_name -> google.protobuf.Timestamp
+	11, // 2: life.v1.Trainer.created_at:type_name -> google.protobuf.Timestamp
+	11, // 3: life.v1.Trainer.updated_at:type_name -> google.protobuf.Timestamp
+	10, // 4: life.v1.OnlinePlayer.position:type_name -> life.v1.Position
+	2,  // 5: life.v1.ListOnlinePlayersResponse.players:type_name -> life.v1.OnlinePlayer
+	10, // 6: life.v1.TeleportRequest.position:type_name -> life.v1.Position
+	6,  // 7: life.v1.GrantRequest.items:type_name -> life.v1.GrantItem
+	1,  // 8: life.v1.TrainerService.GetTrainer:input_type -> life.v1.GetTrainerRequest
+	3,  // 9: life.v1.TrainerService.ListOnlinePlayers:input_type -> life.v1.ListOnlinePlayersRequest
+	5,  // 10: life.v1.TrainerService.Teleport:input_type -> life.v1.TeleportRequest
+	7,  // 11: life.v1.TrainerService.Grant:input_type -> life.v1.GrantRequest
+	8,  // 12: life.v1.TrainerService.Kick:input_type -> life.v1.KickRequest
+	0,  // 13: life.v1.TrainerService.GetTrainer:output_type -> life.v1.Trainer
+	4,  // 14: life.v1.TrainerService.ListOnlinePlayers:output_type -> life.v1.ListOnlinePlayersResponse
+	0,  // 15: life.v1.TrainerService.Teleport:output_type -> life.v1.Trainer
+	0,  // 16: life.v1.TrainerService.Grant:output_type -> life.v1.Trainer
+	9,  // 17: life.v1.TrainerService.Kick:output_type -> life.v1.KickResponse
+	13, // [13:18] is the sub-list for method output_type
+	8,  // [8:13] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_lifepb_trainer_proto_init() }
+func file_lifepb_trainer_proto_init() {
+	if File_lifepb_trainer_proto != nil {
+		return
+	}
+	file_lifepb_common_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lifepb_trainer_proto_rawDesc), len(file_lifepb_trainer_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   10,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lifepb_trainer_proto_goTypes,
+		DependencyIndexes: file_lifepb_trainer_proto_depIdxs,
+		MessageInfos:      file_lifepb_trainer_proto_msgTypes,
+	}.Build()
+	File_lifepb_trainer_proto = out.File
+	file_lifepb_trainer_proto_goTypes = nil
+	file_lifepb_trainer_proto_depIdxs = nil
+}
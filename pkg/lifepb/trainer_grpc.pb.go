@@ -0,0 +1,289 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: lifepb/trainer.proto
+
+package lifepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	TrainerService_GetTrainer_FullMethodName        = "/life.v1.TrainerService/GetTrainer"
+	TrainerService_ListOnlinePlayers_FullMethodName = "/life.v1.TrainerService/ListOnlinePlayers"
+	TrainerService_Teleport_FullMethodName          = "/life.v1.TrainerService/Teleport"
+	TrainerService_Grant_FullMethodName             = "/life.v1.TrainerService/Grant"
+	TrainerService_Kick_FullMethodName              = "/life.v1.TrainerService/Kick"
+)
+
+// TrainerServiceClient is the client API for TrainerService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// TrainerService reads and intervenes on players' trainers. Queries, Teleport and Kick need the
+// moderator role; Grant needs the admin role.
+type TrainerServiceClient interface {
+	// GetTrainer returns a trainer
+	GetTrainer(ctx context.Context, in *GetTrainerRequest, opts ...grpc.CallOption) (*Trainer, error)
+	// ListOnlinePlayers returns the players online on any server
+	ListOnlinePlayers(ctx context.Context, in *ListOnlinePlayersRequest, opts ...grpc.CallOption) (*ListOnlinePlayersResponse, error)
+	// Teleport moves a trainer to a walkable position
+	Teleport(ctx context.Context, in *TeleportRequest, opts ...grpc.CallOption) (*Trainer, error)
+	// Grant gives a trainer money and items
+	Grant(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*Trainer, error)
+	// Kick signs a player out everywhere, revoking their tokens and closing their connections
+	Kick(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*KickResponse, error)
+}
+
+type trainerServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTrainerServiceClient(cc grpc.ClientConnInterface) TrainerServiceClient {
+	return &trainerServiceClient{cc}
+}
+
+func (c *trainerServiceClient) GetTrainer(ctx context.Context, in *GetTrainerRequest, opts ...grpc.CallOption) (*Trainer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Trainer)
+	err := c.cc.Invoke(ctx, TrainerService_GetTrainer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainerServiceClient) ListOnlinePlayers(ctx context.Context, in *ListOnlinePlayersRequest, opts ...grpc.CallOption) (*ListOnlinePlayersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOnlinePlayersResponse)
+	err := c.cc.Invoke(ctx, TrainerService_ListOnlinePlayers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainerServiceClient) Teleport(ctx context.Context, in *TeleportRequest, opts ...grpc.CallOption) (*Trainer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Trainer)
+	err := c.cc.Invoke(ctx, TrainerService_Teleport_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainerServiceClient) Grant(ctx context.Context, in *GrantRequest, opts ...grpc.CallOption) (*Trainer, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Trainer)
+	err := c.cc.Invoke(ctx, TrainerService_Grant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *trainerServiceClient) Kick(ctx context.Context, in *KickRequest, opts ...grpc.CallOption) (*KickResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(KickResponse)
+	err := c.cc.Invoke(ctx, TrainerService_Kick_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TrainerServiceServer is the server API for TrainerService service.
+// All implementations must embed UnimplementedTrainerServiceServer
+// for forward compatibility.
+//
+// TrainerService reads and intervenes on players' trainers. Queries, Teleport and Kick need the
+// moderator role; Grant needs the admin role.
+type TrainerServiceServer interface {
+	// GetTrainer returns a trainer
+	GetTrainer(context.Context, *GetTrainerRequest) (*Trainer, error)
+	// ListOnlinePlayers returns the players online on any server
+	ListOnlinePlayers(context.Context, *ListOnlinePlayersRequest) (*ListOnlinePlayersResponse, error)
+	// Teleport moves a trainer to a walkable position
+	Teleport(context.Context, *TeleportRequest) (*Trainer, error)
+	// Grant gives a trainer money and items
+	Grant(context.Context, *GrantRequest) (*Trainer, error)
+	// Kick signs a player out everywhere, revoking their tokens and closing their connections
+	Kick(context.Context, *KickRequest) (*KickResponse, error)
+	mustEmbedUnimplementedTrainerServiceServer()
+}
+
+// UnimplementedTrainerServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedTrainerServiceServer struct{}
+
+func (UnimplementedTrainerServiceServer) GetTrainer(context.Context, *GetTrainerRequest) (*Trainer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTrainer not implemented")
+}
+func (UnimplementedTrainerServiceServer) ListOnlinePlayers(context.Context, *ListOnlinePlayersRequest) (*ListOnlinePlayersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListOnlinePlayers not implemented")
+}
+func (UnimplementedTrainerServiceServer) Teleport(context.Context, *TeleportRequest) (*Trainer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Teleport not implemented")
+}
+func (UnimplementedTrainerServiceServer) Grant(context.Context, *GrantRequest) (*Trainer, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Grant not implemented")
+}
+func (UnimplementedTrainerServiceServer) Kick(context.Context, *KickRequest) (*KickResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Kick not implemented")
+}
+func (UnimplementedTrainerServiceServer) mustEmbedUnimplementedTrainerServiceServer() {}
+func (UnimplementedTrainerServiceServer) testEmbeddedByValue()                        {}
+
+// UnsafeTrainerServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TrainerServiceServer will
+// result in compilation errors.
+type UnsafeTrainerServiceServer interface {
+	mustEmbedUnimplementedTrainerServiceServer()
+}
+
+func RegisterTrainerServiceServer(s grpc.ServiceRegistrar, srv TrainerServiceServer) {
+	// If the following call pancis, it indicates UnimplementedTrainerServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&TrainerService_ServiceDesc, srv)
+}
+
+func _TrainerService_GetTrainer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTrainerRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainerServiceServer).GetTrainer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainerService_GetTrainer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainerServiceServer).GetTrainer(ctx, req.(*GetTrainerRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainerService_ListOnlinePlayers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOnlinePlayersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainerServiceServer).ListOnlinePlayers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainerService_ListOnlinePlayers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainerServiceServer).ListOnlinePlayers(ctx, req.(*ListOnlinePlayersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainerService_Teleport_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TeleportRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainerServiceServer).Teleport(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainerService_Teleport_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainerServiceServer).Teleport(ctx, req.(*TeleportRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainerService_Grant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainerServiceServer).Grant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainerService_Grant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainerServiceServer).Grant(ctx, req.(*GrantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TrainerService_Kick_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(KickRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TrainerServiceServer).Kick(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TrainerService_Kick_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TrainerServiceServer).Kick(ctx, req.(*KickRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TrainerService_ServiceDesc is the grpc.ServiceDesc for TrainerService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TrainerService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "life.v1.TrainerService",
+	HandlerType: (*TrainerServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetTrainer",
+			Handler:    _TrainerService_GetTrainer_Handler,
+		},
+		{
+			MethodName: "ListOnlinePlayers",
+			Handler:    _TrainerService_ListOnlinePlayers_Handler,
+		},
+		{
+			MethodName: "Teleport",
+			Handler:    _TrainerService_Teleport_Handler,
+		},
+		{
+			MethodName: "Grant",
+			Handler:    _TrainerService_Grant_Handler,
+		},
+		{
+			MethodName: "Kick",
+			Handler:    _TrainerService_Kick_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "lifepb/trainer.proto",
+}
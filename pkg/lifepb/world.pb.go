@@ -0,0 +1,529 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.8
+// 	protoc        v5.28.3
+// source: lifepb/world.proto
+
+package lifepb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetClockRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetClockRequest) Reset() {
+	*x = GetClockRequest{}
+	mi := &file_lifepb_world_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetClockRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetClockRequest) ProtoMessage() {}
+
+func (x *GetClockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_world_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetClockRequest.ProtoReflect.Descriptor instead.
+func (*GetClockRequest) Descriptor() ([]byte, []int) {
+	return file_lifepb_world_proto_rawDescGZIP(), []int{0}
+}
+
+type Clock struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Minute           int64                  `protobuf:"varint,1,opt,name=minute,proto3" json:"minute,omitempty"`
+	Day              int64                  `protobuf:"varint,2,opt,name=day,proto3" json:"day,omitempty"` // Starting at 1
+	Hour             int32                  `protobuf:"varint,3,opt,name=hour,proto3" json:"hour,omitempty"`
+	MinuteOfHour     int32                  `protobuf:"varint,4,opt,name=minute_of_hour,json=minuteOfHour,proto3" json:"minute_of_hour,omitempty"`
+	Phase            string                 `protobuf:"bytes,5,opt,name=phase,proto3" json:"phase,omitempty"`
+	Weather          string                 `protobuf:"bytes,6,opt,name=weather,proto3" json:"weather,omitempty"`
+	DayLengthSeconds float64                `protobuf:"fixed64,7,opt,name=day_length_seconds,json=dayLengthSeconds,proto3" json:"day_length_seconds,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Clock) Reset() {
+	*x = Clock{}
+	mi := &file_lifepb_world_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Clock) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Clock) ProtoMessage() {}
+
+func (x *Clock) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_world_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Clock.ProtoReflect.Descriptor instead.
+func (*Clock) Descriptor() ([]byte, []int) {
+	return file_lifepb_world_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Clock) GetMinute() int64 {
+	if x != nil {
+		return x.Minute
+	}
+	return 0
+}
+
+func (x *Clock) GetDay() int64 {
+	if x != nil {
+		return x.Day
+	}
+	return 0
+}
+
+func (x *Clock) GetHour() int32 {
+	if x != nil {
+		return x.Hour
+	}
+	return 0
+}
+
+func (x *Clock) GetMinuteOfHour() int32 {
+	if x != nil {
+		return x.MinuteOfHour
+	}
+	return 0
+}
+
+func (x *Clock) GetPhase() string {
+	if x != nil {
+		return x.Phase
+	}
+	return ""
+}
+
+func (x *Clock) GetWeather() string {
+	if x != nil {
+		return x.Weather
+	}
+	return ""
+}
+
+func (x *Clock) GetDayLengthSeconds() float64 {
+	if x != nil {
+		return x.DayLengthSeconds
+	}
+	return 0
+}
+
+type GetChunkRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	X             int32                  `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                  `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetChunkRequest) Reset() {
+	*x = GetChunkRequest{}
+	mi := &file_lifepb_world_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetChunkRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetChunkRequest) ProtoMessage() {}
+
+func (x *GetChunkRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_world_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetChunkRequest.ProtoReflect.Descriptor instead.
+func (*GetChunkRequest) Descriptor() ([]byte, []int) {
+	return file_lifepb_world_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetChunkRequest) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *GetChunkRequest) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+type StaticEntity struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind          string                 `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StaticEntity) Reset() {
+	*x = StaticEntity{}
+	mi := &file_lifepb_world_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StaticEntity) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StaticEntity) ProtoMessage() {}
+
+func (x *StaticEntity) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_world_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StaticEntity.ProtoReflect.Descriptor instead.
+func (*StaticEntity) Descriptor() ([]byte, []int) {
+	return file_lifepb_world_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StaticEntity) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *StaticEntity) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+type Chunk struct {
+	state         protoimpl.MessageState   `protogen:"open.v1"`
+	X             int32                    `protobuf:"varint,1,opt,name=x,proto3" json:"x,omitempty"`
+	Y             int32                    `protobuf:"varint,2,opt,name=y,proto3" json:"y,omitempty"`
+	Size          int32                    `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Terrain       []string                 `protobuf:"bytes,4,rep,name=terrain,proto3" json:"terrain,omitempty"`                                                                           // size*size tiles, row by row from the chunk's corner
+	Statics       map[string]*StaticEntity `protobuf:"bytes,5,rep,name=statics,proto3" json:"statics,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Position key -> static entity
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Chunk) Reset() {
+	*x = Chunk{}
+	mi := &file_lifepb_world_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Chunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Chunk) ProtoMessage() {}
+
+func (x *Chunk) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_world_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Chunk.ProtoReflect.Descriptor instead.
+func (*Chunk) Descriptor() ([]byte, []int) {
+	return file_lifepb_world_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Chunk) GetX() int32 {
+	if x != nil {
+		return x.X
+	}
+	return 0
+}
+
+func (x *Chunk) GetY() int32 {
+	if x != nil {
+		return x.Y
+	}
+	return 0
+}
+
+func (x *Chunk) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Chunk) GetTerrain() []string {
+	if x != nil {
+		return x.Terrain
+	}
+	return nil
+}
+
+func (x *Chunk) GetStatics() map[string]*StaticEntity {
+	if x != nil {
+		return x.Statics
+	}
+	return nil
+}
+
+type GetStatsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatsRequest) Reset() {
+	*x = GetStatsRequest{}
+	mi := &file_lifepb_world_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatsRequest) ProtoMessage() {}
+
+func (x *GetStatsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_world_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatsRequest.ProtoReflect.Descriptor instead.
+func (*GetStatsRequest) Descriptor() ([]byte, []int) {
+	return file_lifepb_world_proto_rawDescGZIP(), []int{5}
+}
+
+type ServerStats struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SseClients       int32                  `protobuf:"varint,1,opt,name=sse_clients,json=sseClients,proto3" json:"sse_clients,omitempty"`
+	WebsocketClients int32                  `protobuf:"varint,2,opt,name=websocket_clients,json=websocketClients,proto3" json:"websocket_clients,omitempty"`
+	OnlinePlayers    int32                  `protobuf:"varint,3,opt,name=online_players,json=onlinePlayers,proto3" json:"online_players,omitempty"` // Across every server
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ServerStats) Reset() {
+	*x = ServerStats{}
+	mi := &file_lifepb_world_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServerStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServerStats) ProtoMessage() {}
+
+func (x *ServerStats) ProtoReflect() protoreflect.Message {
+	mi := &file_lifepb_world_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServerStats.ProtoReflect.Descriptor instead.
+func (*ServerStats) Descriptor() ([]byte, []int) {
+	return file_lifepb_world_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ServerStats) GetSseClients() int32 {
+	if x != nil {
+		return x.SseClients
+	}
+	return 0
+}
+
+func (x *ServerStats) GetWebsocketClients() int32 {
+	if x != nil {
+		return x.WebsocketClients
+	}
+	return 0
+}
+
+func (x *ServerStats) GetOnlinePlayers() int32 {
+	if x != nil {
+		return x.OnlinePlayers
+	}
+	return 0
+}
+
+var File_lifepb_world_proto protoreflect.FileDescriptor
+
+const file_lifepb_world_proto_rawDesc = "" +
+	"\n" +
+	"\x12lifepb/world.proto\x12\alife.v1\"\x11\n" +
+	"\x0fGetClockRequest\"\xc9\x01\n" +
+	"\x05Clock\x12\x16\n" +
+	"\x06minute\x18\x01 \x01(\x03R\x06minute\x12\x10\n" +
+	"\x03day\x18\x02 \x01(\x03R\x03day\x12\x12\n" +
+	"\x04hour\x18\x03 \x01(\x05R\x04hour\x12$\n" +
+	"\x0eminute_of_hour\x18\x04 \x01(\x05R\fminuteOfHour\x12\x14\n" +
+	"\x05phase\x18\x05 \x01(\tR\x05phase\x12\x18\n" +
+	"\aweather\x18\x06 \x01(\tR\aweather\x12,\n" +
+	"\x12day_length_seconds\x18\a \x01(\x01R\x10dayLengthSeconds\"-\n" +
+	"\x0fGetChunkRequest\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x05R\x01y\"2\n" +
+	"\fStaticEntity\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04kind\x18\x02 \x01(\tR\x04kind\"\xdb\x01\n" +
+	"\x05Chunk\x12\f\n" +
+	"\x01x\x18\x01 \x01(\x05R\x01x\x12\f\n" +
+	"\x01y\x18\x02 \x01(\x05R\x01y\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x05R\x04size\x12\x18\n" +
+	"\aterrain\x18\x04 \x03(\tR\aterrain\x125\n" +
+	"\astatics\x18\x05 \x03(\v2\x1b.life.v1.Chunk.StaticsEntryR\astatics\x1aQ\n" +
+	"\fStaticsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12+\n" +
+	"\x05value\x18\x02 \x01(\v2\x15.life.v1.StaticEntityR\x05value:\x028\x01\"\x11\n" +
+	"\x0fGetStatsRequest\"\x82\x01\n" +
+	"\vServerStats\x12\x1f\n" +
+	"\vsse_clients\x18\x01 \x01(\x05R\n" +
+	"sseClients\x12+\n" +
+	"\x11websocket_clients\x18\x02 \x01(\x05R\x10websocketClients\x12%\n" +
+	"\x0eonline_players\x18\x03 \x01(\x05R\ronlinePlayers2\xb6\x01\n" +
+	"\fWorldService\x124\n" +
+	"\bGetClock\x12\x18.life.v1.GetClockRequest\x1a\x0e.life.v1.Clock\x124\n" +
+	"\bGetChunk\x12\x18.life.v1.GetChunkRequest\x1a\x0e.life.v1.Chunk\x12:\n" +
+	"\bGetStats\x12\x18.life.v1.GetStatsRequest\x1a\x14.life.v1.ServerStatsB%Z#github.com/danghamo/life/pkg/lifepbb\x06proto3"
+
+var (
+	file_lifepb_world_proto_rawDescOnce sync.Once
+	file_lifepb_world_proto_rawDescData []byte
+)
+
+func file_lifepb_world_proto_rawDescGZIP() []byte {
+	file_lifepb_world_proto_rawDescOnce.Do(func() {
+		file_lifepb_world_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_lifepb_world_proto_rawDesc), len(file_lifepb_world_proto_rawDesc)))
+	})
+	return file_lifepb_world_proto_rawDescData
+}
+
+var file_lifepb_world_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
+var file_lifepb_world_proto_goTypes = []any{
+	(*GetClockRequest)(nil), // 0: life.v1.GetClockRequest
+	(*Clock)(nil),           // 1: life.v1.Clock
+	(*GetChunkRequest)(nil), // 2: life.v1.GetChunkRequest
+	(*StaticEntity)(nil),    // 3: life.v1.StaticEntity
+	(*Chunk)(nil),           // 4: life.v1.Chunk
+	(*GetStatsRequest)(nil), // 5: life.v1.GetStatsRequest
+	(*ServerStats)(nil),     // 6: life.v1.ServerStats
+	nil,                     // 7: life.v1.Chunk.StaticsEntry
+}
+var file_lifepb_world_proto_depIdxs = []int32{
+	7, // 0: life.v1.Chunk.statics:type_name -> life.v1.Chunk.StaticsEntry
+	3, // 1: life.v1.Chunk.StaticsEntry.value:type_name -> life.v1.StaticEntity
+	0, // 2: life.v1.WorldService.GetClock:input_type -> life.v1.GetClockRequest
+	2, // 3: life.v1.WorldService.GetChunk:input_type -> life.v1.GetChunkRequest
+	5, // 4: life.v1.WorldService.GetStats:input_type -> life.v1.GetStatsRequest
+	1, // 5: life.v1.WorldService.GetClock:output_type -> life.v1.Clock
+	4, // 6: life.v1.WorldService.GetChunk:output_type -> life.v1.Chunk
+	6, // 7: life.v1.WorldService.GetStats:output_type -> life.v1.ServerStats
+	5, // [5:8] is the sub-list for method output_type
+	2, // [2:5] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_lifepb_world_proto_init() }
+func file_lifepb_world_proto_init() {
+	if File_lifepb_world_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_lifepb_world_proto_rawDesc), len(file_lifepb_world_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   8,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_lifepb_world_proto_goTypes,
+		DependencyIndexes: file_lifepb_world_proto_depIdxs,
+		MessageInfos:      file_lifepb_world_proto_msgTypes,
+	}.Build()
+	File_lifepb_world_proto = out.File
+	file_lifepb_world_proto_goTypes = nil
+	file_lifepb_world_proto_depIdxs = nil
+}
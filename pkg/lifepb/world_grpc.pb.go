@@ -0,0 +1,207 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.28.3
+// source: lifepb/world.proto
+
+package lifepb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	WorldService_GetClock_FullMethodName = "/life.v1.WorldService/GetClock"
+	WorldService_GetChunk_FullMethodName = "/life.v1.WorldService/GetChunk"
+	WorldService_GetStats_FullMethodName = "/life.v1.WorldService/GetStats"
+)
+
+// WorldServiceClient is the client API for WorldService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// WorldService reads the world. GetStats needs the moderator role.
+type WorldServiceClient interface {
+	// GetClock returns the game time and weather
+	GetClock(ctx context.Context, in *GetClockRequest, opts ...grpc.CallOption) (*Clock, error)
+	// GetChunk returns the tiles of a chunk
+	GetChunk(ctx context.Context, in *GetChunkRequest, opts ...grpc.CallOption) (*Chunk, error)
+	// GetStats returns the connection counters of the server answering
+	GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*ServerStats, error)
+}
+
+type worldServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewWorldServiceClient(cc grpc.ClientConnInterface) WorldServiceClient {
+	return &worldServiceClient{cc}
+}
+
+func (c *worldServiceClient) GetClock(ctx context.Context, in *GetClockRequest, opts ...grpc.CallOption) (*Clock, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Clock)
+	err := c.cc.Invoke(ctx, WorldService_GetClock_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *worldServiceClient) GetChunk(ctx context.Context, in *GetChunkRequest, opts ...grpc.CallOption) (*Chunk, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Chunk)
+	err := c.cc.Invoke(ctx, WorldService_GetChunk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *worldServiceClient) GetStats(ctx context.Context, in *GetStatsRequest, opts ...grpc.CallOption) (*ServerStats, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServerStats)
+	err := c.cc.Invoke(ctx, WorldService_GetStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// WorldServiceServer is the server API for WorldService service.
+// All implementations must embed UnimplementedWorldServiceServer
+// for forward compatibility.
+//
+// WorldService reads the world. GetStats needs the moderator role.
+type WorldServiceServer interface {
+	// GetClock returns the game time and weather
+	GetClock(context.Context, *GetClockRequest) (*Clock, error)
+	// GetChunk returns the tiles of a chunk
+	GetChunk(context.Context, *GetChunkRequest) (*Chunk, error)
+	// GetStats returns the connection counters of the server answering
+	GetStats(context.Context, *GetStatsRequest) (*ServerStats, error)
+	mustEmbedUnimplementedWorldServiceServer()
+}
+
+// UnimplementedWorldServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedWorldServiceServer struct{}
+
+func (UnimplementedWorldServiceServer) GetClock(context.Context, *GetClockRequest) (*Clock, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClock not implemented")
+}
+func (UnimplementedWorldServiceServer) GetChunk(context.Context, *GetChunkRequest) (*Chunk, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetChunk not implemented")
+}
+func (UnimplementedWorldServiceServer) GetStats(context.Context, *GetStatsRequest) (*ServerStats, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetStats not implemented")
+}
+func (UnimplementedWorldServiceServer) mustEmbedUnimplementedWorldServiceServer() {}
+func (UnimplementedWorldServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeWorldServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to WorldServiceServer will
+// result in compilation errors.
+type UnsafeWorldServiceServer interface {
+	mustEmbedUnimplementedWorldServiceServer()
+}
+
+func RegisterWorldServiceServer(s grpc.ServiceRegistrar, srv WorldServiceServer) {
+	// If the following call pancis, it indicates UnimplementedWorldServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&WorldService_ServiceDesc, srv)
+}
+
+func _WorldService_GetClock_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClockRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorldServiceServer).GetClock(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorldService_GetClock_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorldServiceServer).GetClock(ctx, req.(*GetClockRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorldService_GetChunk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetChunkRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorldServiceServer).GetChunk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorldService_GetChunk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorldServiceServer).GetChunk(ctx, req.(*GetChunkRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _WorldService_GetStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(WorldServiceServer).GetStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: WorldService_GetStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(WorldServiceServer).GetStats(ctx, req.(*GetStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// WorldService_ServiceDesc is the grpc.ServiceDesc for WorldService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var WorldService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "life.v1.WorldService",
+	HandlerType: (*WorldServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetClock",
+			Handler:    _WorldService_GetClock_Handler,
+		},
+		{
+			MethodName: "GetChunk",
+			Handler:    _WorldService_GetChunk_Handler,
+		},
+		{
+			MethodName: "GetStats",
+			Handler:    _WorldService_GetStats_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "lifepb/world.proto",
+}
@@ -1,16 +1,20 @@
 package logger
 
 import (
+	"context"
 	"os"
 	"strings"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
+
+	"github.com/danghamo/life/pkg/requestid"
 )
 
 // Logger wraps zap.Logger to provide our application-specific logging interface
 type Logger struct {
 	*zap.Logger
+	level *zap.AtomicLevel // Shared by every logger derived from the one New created
 }
 
 // LogLevel represents the logging level
@@ -47,20 +51,8 @@ func New(cfg Config) (*Logger, error) {
 		}
 	}
 
-	// Configure zap level
-	var zapLevel zapcore.Level
-	switch cfg.Level {
-	case DebugLevel:
-		zapLevel = zapcore.DebugLevel
-	case InfoLevel:
-		zapLevel = zapcore.InfoLevel
-	case WarnLevel:
-		zapLevel = zapcore.WarnLevel
-	case ErrorLevel:
-		zapLevel = zapcore.ErrorLevel
-	default:
-		zapLevel = zapcore.InfoLevel
-	}
+	// Configure zap level; it can be changed while running with SetLevel
+	level := zap.NewAtomicLevelAt(zapLevel(cfg.Level))
 
 	// Configure encoder
 	var encoderConfig zapcore.EncoderConfig
@@ -87,13 +79,35 @@ func New(cfg Config) (*Logger, error) {
 	core := zapcore.NewCore(
 		encoder,
 		zapcore.AddSync(os.Stdout),
-		zapLevel,
+		level,
 	)
 
 	// Create logger with caller info
 	zapLogger := zap.New(core, zap.AddCaller(), zap.AddCallerSkip(1))
 
-	return &Logger{Logger: zapLogger}, nil
+	return &Logger{Logger: zapLogger, level: &level}, nil
+}
+
+// zapLevel converts a LogLevel to the zap level, defaulting to info
+func zapLevel(level LogLevel) zapcore.Level {
+	switch level {
+	case DebugLevel:
+		return zapcore.DebugLevel
+	case WarnLevel:
+		return zapcore.WarnLevel
+	case ErrorLevel:
+		return zapcore.ErrorLevel
+	default:
+		return zapcore.InfoLevel
+	}
+}
+
+// SetLevel changes the level of the logger and every logger derived from it, e.g. to turn on
+// debug logs of a running server. Loggers not created by New keep their level.
+func (l *Logger) SetLevel(level LogLevel) {
+	if l.level != nil {
+		l.level.SetLevel(zapLevel(level))
+	}
 }
 
 // NewDefault creates a logger with default development settings
@@ -118,7 +132,7 @@ func NewProduction() *Logger {
 
 // WithField adds a field to the logger context
 func (l *Logger) WithField(key string, value interface{}) *Logger {
-	return &Logger{Logger: l.Logger.With(zap.Any(key, value))}
+	return &Logger{Logger: l.Logger.With(zap.Any(key, value)), level: l.level}
 }
 
 // WithFields adds multiple fields to the logger context
@@ -127,7 +141,7 @@ func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
 	for k, v := range fields {
 		zapFields = append(zapFields, zap.Any(k, v))
 	}
-	return &Logger{Logger: l.Logger.With(zapFields...)}
+	return &Logger{Logger: l.Logger.With(zapFields...), level: l.level}
 }
 
 // WithComponent adds a component field to help identify log sources
@@ -140,6 +154,16 @@ func (l *Logger) WithRequestID(requestID string) *Logger {
 	return l.WithField("request_id", requestID)
 }
 
+// WithContext adds the request ID a context carries, so the logs of one user action can be
+// traced across the API, event handlers and broadcasters
+func (l *Logger) WithContext(ctx context.Context) *Logger {
+	id := requestid.FromContext(ctx)
+	if id == "" {
+		return l
+	}
+	return l.WithRequestID(id)
+}
+
 // WithUserID adds a user ID for user-specific operations
 func (l *Logger) WithUserID(userID string) *Logger {
 	return l.WithField("user_id", userID)
@@ -0,0 +1,107 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// Config holds SMTP settings. An empty Host selects the log mailer.
+type Config struct {
+	Host     string `json:"host"`
+	Port     int    `json:"port"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	From     string `json:"from"`
+}
+
+// Message is a plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer sends emails
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New creates an SMTP mailer, or a log mailer when no SMTP host is configured
+func New(cfg Config, log *logger.Logger) Mailer {
+	if cfg.Host == "" {
+		return NewLogMailer(log)
+	}
+	return NewSMTPMailer(cfg)
+}
+
+// SMTPMailer sends emails through an SMTP server
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+// NewSMTPMailer creates a new SMTP mailer. Credentials are optional for relays that don't require them.
+func NewSMTPMailer(cfg Config) *SMTPMailer {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+
+	return &SMTPMailer{
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		from: cfg.From,
+		auth: auth,
+	}
+}
+
+// Send sends a message. net/smtp has no context support, so ctx only guards against sending
+// after the caller has given up.
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if strings.ContainsAny(msg.To, "\r\n") || strings.ContainsAny(msg.Subject, "\r\n") {
+		return fmt.Errorf("mail headers must not contain line breaks")
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", m.from)
+	fmt.Fprintf(&body, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&body, "Subject: %s\r\n", msg.Subject)
+	body.WriteString("MIME-Version: 1.0\r\n")
+	body.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	body.WriteString(msg.Body)
+
+	return smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, []byte(body.String()))
+}
+
+// LogMailer writes emails to the log instead of sending them, for local development
+type LogMailer struct {
+	logger *logger.Logger
+}
+
+// NewLogMailer creates a new log mailer
+func NewLogMailer(log *logger.Logger) *LogMailer {
+	return &LogMailer{
+		logger: log.WithComponent("mailer"),
+	}
+}
+
+// Send logs the message
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	m.logger.Info("Email not sent, no SMTP host configured",
+		zap.String("to", msg.To),
+		zap.String("subject", msg.Subject),
+		zap.String("body", msg.Body))
+	return nil
+}
@@ -0,0 +1,43 @@
+// Package objstore stores whole objects by key in a bucket-like store: a local directory or
+// any S3-compatible service (AWS S3, Google Cloud Storage interoperability, MinIO, R2).
+package objstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrNotFound is returned when an object does not exist
+var ErrNotFound = errors.New("object not found")
+
+// Store reads and writes objects. Keys are slash-separated paths.
+type Store interface {
+	// Put creates or replaces an object
+	Put(ctx context.Context, key string, data []byte) error
+
+	// Get reads an object, returning ErrNotFound if it doesn't exist
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// List returns the keys starting with prefix in lexical order
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// Config selects and configures a store
+type Config struct {
+	Kind string   // dir or s3
+	Dir  string   // Root directory of the dir store
+	S3   S3Config // Bucket of the s3 store
+}
+
+// New creates the store a config selects
+func New(cfg Config) (Store, error) {
+	switch cfg.Kind {
+	case "dir":
+		return NewDirStore(cfg.Dir)
+	case "s3":
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("unknown object store %q", cfg.Kind)
+	}
+}
@@ -0,0 +1,63 @@
+package pgsqlx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// uniqueViolation is the Postgres error code of a duplicate key
+const uniqueViolation = "23505"
+
+// NewPool creates a Postgres connection pool from URL and checks that the database answers
+func NewPool(ctx context.Context, postgresURL string, maxConns int32, log *logger.Logger) (*pgxpool.Pool, error) {
+	if postgresURL == "" {
+		return nil, fmt.Errorf("postgres URL cannot be empty")
+	}
+
+	if log == nil {
+		log = logger.GetGlobalLogger()
+	}
+
+	poolConfig, err := pgxpool.ParseConfig(postgresURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Postgres URL: %w", err)
+	}
+	if maxConns > 0 {
+		poolConfig.MaxConns = maxConns
+	}
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Postgres pool: %w", err)
+	}
+
+	// Test connection
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := pool.Ping(pingCtx); err != nil {
+		pool.Close()
+		return nil, fmt.Errorf("failed to connect to Postgres: %w", err)
+	}
+
+	log.WithComponent("pgsqlx").Info("Postgres pool connected successfully",
+		zap.String("host", poolConfig.ConnConfig.Host),
+		zap.String("database", poolConfig.ConnConfig.Database),
+		zap.Int32("max_conns", poolConfig.MaxConns))
+
+	return pool, nil
+}
+
+// IsUniqueViolation returns true if err reports a duplicate key
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolation
+}
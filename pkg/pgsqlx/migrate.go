@@ -0,0 +1,149 @@
+package pgsqlx
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// migrationLockID serializes migrations of servers starting together
+const migrationLockID = 0x6c696665 // "life"
+
+// Migration is one schema change, named "<version>_<description>.sql"
+type Migration struct {
+	Version string
+	SQL     string
+}
+
+// LoadMigrations reads the .sql files of migrations in version order
+func LoadMigrations(migrations fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(migrations, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+
+	loaded := make([]Migration, 0, len(names))
+	for _, name := range names {
+		data, err := fs.ReadFile(migrations, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+		loaded = append(loaded, Migration{
+			Version: strings.TrimSuffix(path.Base(name), ".sql"),
+			SQL:     string(data),
+		})
+	}
+	return loaded, nil
+}
+
+// Pending returns the migrations not yet applied
+func Pending(ctx context.Context, pool *pgxpool.Pool, migrations fs.FS) ([]Migration, error) {
+	all, err := LoadMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureMigrationsTable(ctx, pool); err != nil {
+		return nil, err
+	}
+	applied, err := appliedVersions(ctx, pool)
+	if err != nil {
+		return nil, err
+	}
+	return pendingMigrations(all, applied), nil
+}
+
+// Migrate applies pending migrations in one transaction and returns their versions. Servers
+// migrating at the same time wait for each other, so each migration runs once.
+func Migrate(ctx context.Context, pool *pgxpool.Pool, migrations fs.FS) ([]string, error) {
+	all, err := LoadMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ensureMigrationsTable(ctx, pool); err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	err = pgx.BeginFunc(ctx, pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+			return fmt.Errorf("failed to lock migrations: %w", err)
+		}
+
+		applied, err := appliedVersions(ctx, tx)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range pendingMigrations(all, applied) {
+			if _, err := tx.Exec(ctx, migration.SQL); err != nil {
+				return fmt.Errorf("migration %s failed: %w", migration.Version, err)
+			}
+			if _, err := tx.Exec(ctx, "INSERT INTO schema_migrations (version) VALUES ($1)", migration.Version); err != nil {
+				return err
+			}
+			versions = append(versions, migration.Version)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// pendingMigrations returns the migrations whose version is not in applied
+func pendingMigrations(all []Migration, applied map[string]bool) []Migration {
+	var pending []Migration
+	for _, migration := range all {
+		if !applied[migration.Version] {
+			pending = append(pending, migration)
+		}
+	}
+	return pending
+}
+
+// ensureMigrationsTable creates the table recording applied migrations
+func ensureMigrationsTable(ctx context.Context, pool *pgxpool.Pool) error {
+	_, err := pool.Exec(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    TEXT PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+	return nil
+}
+
+// querier runs queries on a pool or in a transaction
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// appliedVersions returns the versions recorded as applied
+func appliedVersions(ctx context.Context, q querier) (map[string]bool, error) {
+	rows, err := q.Query(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	versions, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	applied := make(map[string]bool, len(versions))
+	for _, version := range versions {
+		applied[version] = true
+	}
+	return applied, nil
+}
@@ -0,0 +1,34 @@
+package pgsqlx
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations_VersionOrder(t *testing.T) {
+	migrations := fstest.MapFS{
+		"0002_ledger.sql":   {Data: []byte("CREATE TABLE ledger ();")},
+		"0001_accounts.sql": {Data: []byte("CREATE TABLE accounts ();")},
+		"README.md":         {Data: []byte("not a migration")},
+	}
+
+	loaded, err := LoadMigrations(migrations)
+	require.NoError(t, err)
+	require.Len(t, loaded, 2)
+	assert.Equal(t, "0001_accounts", loaded[0].Version)
+	assert.Equal(t, "CREATE TABLE accounts ();", loaded[0].SQL)
+	assert.Equal(t, "0002_ledger", loaded[1].Version)
+}
+
+func TestPendingMigrations(t *testing.T) {
+	all := []Migration{{Version: "0001_accounts"}, {Version: "0002_ledger"}, {Version: "0003_audit"}}
+
+	pending := pendingMigrations(all, map[string]bool{"0001_accounts": true, "0003_audit": true})
+	require.Len(t, pending, 1)
+	assert.Equal(t, "0002_ledger", pending[0].Version)
+
+	assert.Empty(t, pendingMigrations(all, map[string]bool{"0001_accounts": true, "0002_ledger": true, "0003_audit": true}))
+}
@@ -0,0 +1,286 @@
+// Package plugin lets gameplay modules extend the server without changing its handlers.
+// Modules are compiled in: a module registers itself from an init function, and the server
+// binary imports it for that side effect in cmd/server/plugins.go:
+//
+//	import _ "example.com/lifemods/treasurehunt"
+//
+// When the server starts it calls every module's Setup, which subscribes to the extension
+// points the module needs and adds JSON-RPC methods under namespaces of its own. Hooks run
+// in the request that triggered them, so they should return quickly and leave slow work to
+// goroutines of their own.
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// Module is a gameplay module compiled into the server
+type Module struct {
+	Name  string
+	Setup func(r *Registrar) error
+}
+
+var (
+	modulesMu sync.Mutex
+	modules   []Module
+)
+
+// Register makes a module available to the server. It is meant to be called from the
+// module's init function and panics on an unnamed or duplicate module.
+func Register(module Module) {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	if module.Name == "" || module.Setup == nil {
+		panic("plugin: Register needs a module name and Setup")
+	}
+	for _, registered := range modules {
+		if registered.Name == module.Name {
+			panic(fmt.Sprintf("plugin: Register called twice for module %s", module.Name))
+		}
+	}
+	modules = append(modules, module)
+}
+
+// Modules returns the registered modules in registration order
+func Modules() []Module {
+	modulesMu.Lock()
+	defer modulesMu.Unlock()
+
+	return append([]Module(nil), modules...)
+}
+
+// Position is a point on the world map
+type Position struct {
+	X float64 `json:"x"`
+	Y float64 `json:"y"`
+}
+
+// PlayerMove describes a trainer starting or stopping to move
+type PlayerMove struct {
+	UserID     string    `json:"user_id"`
+	Action     string    `json:"action"` // "start" or "stop"
+	Position   Position  `json:"position"`
+	DirectionX float64   `json:"direction_x"`
+	DirectionY float64   `json:"direction_y"`
+	At         time.Time `json:"at"`
+}
+
+// Capture describes a net thrown at a wild animal, caught or not
+type Capture struct {
+	UserID     string    `json:"user_id"`
+	AnimalID   string    `json:"animal_id"`
+	AnimalType string    `json:"animal_type"`
+	Level      int       `json:"level"`
+	NetType    string    `json:"net_type"`
+	Success    bool      `json:"success"`
+	Placement  string    `json:"placement,omitempty"` // "party" or "storage" when caught
+	Position   Position  `json:"position"`
+	At         time.Time `json:"at"`
+}
+
+// Purchase describes a player about to buy something
+type Purchase struct {
+	UserID   string `json:"user_id"`
+	ItemType string `json:"item_type"`
+	Quantity int    `json:"quantity"`
+	Price    int    `json:"price"`
+}
+
+// MethodFunc serves a JSON-RPC method for an authenticated user. Its result is the
+// response's result; return an error from InvalidParams to reject the request, any other
+// error is answered as an internal error.
+type MethodFunc func(ctx context.Context, userID string, params json.RawMessage) (any, error)
+
+// Method is a JSON-RPC method added by a module
+type Method struct {
+	Module string
+	Name   string // Full method name, e.g. "treasure.Dig"
+	Func   MethodFunc
+}
+
+// ParamsError rejects a method call for its params
+type ParamsError struct {
+	Message string
+}
+
+// Error returns the message sent to the client
+func (e *ParamsError) Error() string {
+	return e.Message
+}
+
+// InvalidParams returns an error answering a method call with invalid params
+func InvalidParams(format string, args ...any) error {
+	return &ParamsError{Message: fmt.Sprintf(format, args...)}
+}
+
+// methodNamePattern requires a lowercase namespace and an exported-style method name
+var methodNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*\.[A-Z][A-Za-z0-9]*$`)
+
+// Registrar is what a module's Setup registers its extension points with
+type Registrar struct {
+	module string
+	logger *logger.Logger
+	hooks  *Hooks
+	errs   []error
+}
+
+// Logger returns a logger tagged with the module's name
+func (r *Registrar) Logger() *logger.Logger {
+	return r.logger
+}
+
+// OnPlayerMove calls fn after a trainer starts or stops moving
+func (r *Registrar) OnPlayerMove(fn func(ctx context.Context, move PlayerMove)) {
+	r.hooks.moves = append(r.hooks.moves, hook[PlayerMove]{module: r.module, fn: fn})
+}
+
+// OnCapture calls fn after a net is thrown at a wild animal
+func (r *Registrar) OnCapture(fn func(ctx context.Context, capture Capture)) {
+	r.hooks.captures = append(r.hooks.captures, hook[Capture]{module: r.module, fn: fn})
+}
+
+// OnPurchase calls fn before a purchase goes through; an error cancels the purchase and
+// is shown to the player
+func (r *Registrar) OnPurchase(fn func(ctx context.Context, purchase Purchase) error) {
+	r.hooks.purchases = append(r.hooks.purchases, vetoHook[Purchase]{module: r.module, fn: fn})
+}
+
+// Handle adds a JSON-RPC method such as "treasure.Dig" for signed-in players. Its
+// namespace must not be used by the server or another module.
+func (r *Registrar) Handle(name string, fn MethodFunc) {
+	if !methodNamePattern.MatchString(name) || fn == nil {
+		r.errs = append(r.errs, fmt.Errorf("invalid method %q: names look like namespace.Method", name))
+		return
+	}
+	for _, method := range r.hooks.methods {
+		if method.Name == name {
+			r.errs = append(r.errs, fmt.Errorf("method %s is already added by module %s", name, method.Module))
+			return
+		}
+	}
+	r.hooks.methods = append(r.hooks.methods, Method{Module: r.module, Name: name, Func: fn})
+}
+
+// hook is an observer of an extension point registered by a module
+type hook[T any] struct {
+	module string
+	fn     func(ctx context.Context, event T)
+}
+
+// vetoHook is a hook that can cancel what it observes
+type vetoHook[T any] struct {
+	module string
+	fn     func(ctx context.Context, event T) error
+}
+
+// Hooks holds the extension points every loaded module registered. The server calls them
+// where the extension points happen; a nil Hooks has no modules.
+type Hooks struct {
+	logger    *logger.Logger
+	modules   []string
+	moves     []hook[PlayerMove]
+	captures  []hook[Capture]
+	purchases []vetoHook[Purchase]
+	methods   []Method
+}
+
+// Load runs the Setup of every module, returning the hooks they registered
+func Load(log *logger.Logger, modules []Module) (*Hooks, error) {
+	log = log.WithComponent("plugins")
+	hooks := &Hooks{logger: log}
+
+	for _, module := range modules {
+		registrar := &Registrar{
+			module: module.Name,
+			logger: log.WithField("module", module.Name),
+			hooks:  hooks,
+		}
+		if err := module.Setup(registrar); err != nil {
+			return nil, fmt.Errorf("failed to set up plugin %s: %w", module.Name, err)
+		}
+		if err := errors.Join(registrar.errs...); err != nil {
+			return nil, fmt.Errorf("failed to set up plugin %s: %w", module.Name, err)
+		}
+		hooks.modules = append(hooks.modules, module.Name)
+	}
+
+	return hooks, nil
+}
+
+// Modules returns the names of the loaded modules
+func (h *Hooks) Modules() []string {
+	if h == nil {
+		return nil
+	}
+	return h.modules
+}
+
+// Methods returns the JSON-RPC methods the modules added
+func (h *Hooks) Methods() []Method {
+	if h == nil {
+		return nil
+	}
+	return h.methods
+}
+
+// PlayerMoved calls the OnPlayerMove hooks
+func (h *Hooks) PlayerMoved(ctx context.Context, move PlayerMove) {
+	if h == nil {
+		return
+	}
+	for _, hook := range h.moves {
+		h.observe(hook.module, "OnPlayerMove", func() { hook.fn(ctx, move) })
+	}
+}
+
+// Captured calls the OnCapture hooks
+func (h *Hooks) Captured(ctx context.Context, capture Capture) {
+	if h == nil {
+		return
+	}
+	for _, hook := range h.captures {
+		h.observe(hook.module, "OnCapture", func() { hook.fn(ctx, capture) })
+	}
+}
+
+// Purchasing calls the OnPurchase hooks, returning the first error. A hook that panics
+// cancels the purchase too.
+func (h *Hooks) Purchasing(ctx context.Context, purchase Purchase) (err error) {
+	if h == nil {
+		return nil
+	}
+	for _, hook := range h.purchases {
+		// Stays set when the hook panics
+		err = fmt.Errorf("plugin %s failed", hook.module)
+		h.observe(hook.module, "OnPurchase", func() { err = hook.fn(ctx, purchase) })
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// observe runs a hook, logging rather than propagating a panic so a faulty module can't
+// take requests down with it
+func (h *Hooks) observe(module, point string, run func()) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			h.logger.Error("Plugin hook panicked",
+				zap.String("module", module),
+				zap.String("hook", point),
+				zap.Any("panic", recovered))
+		}
+	}()
+	run()
+}
@@ -0,0 +1,79 @@
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/pkg/logger"
+)
+
+func TestLoadCallsHooks(t *testing.T) {
+	var moves []PlayerMove
+	hooks, err := Load(logger.GetGlobalLogger(), []Module{{
+		Name: "treasure",
+		Setup: func(r *Registrar) error {
+			r.OnPlayerMove(func(ctx context.Context, move PlayerMove) {
+				moves = append(moves, move)
+			})
+			r.OnCapture(func(ctx context.Context, capture Capture) {
+				panic("broken module")
+			})
+			r.OnPurchase(func(ctx context.Context, purchase Purchase) error {
+				if purchase.Quantity > 10 {
+					return errors.New("too many")
+				}
+				return nil
+			})
+			r.Handle("treasure.Dig", func(ctx context.Context, userID string, params json.RawMessage) (any, error) {
+				return nil, nil
+			})
+			return nil
+		},
+	}})
+	require.NoError(t, err)
+
+	hooks.PlayerMoved(context.Background(), PlayerMove{UserID: "user-1", Action: "start"})
+	assert.Len(t, moves, 1)
+
+	assert.NotPanics(t, func() { hooks.Captured(context.Background(), Capture{UserID: "user-1"}) },
+		"a panicking hook must not take the request down")
+
+	assert.NoError(t, hooks.Purchasing(context.Background(), Purchase{Quantity: 1}))
+	assert.Error(t, hooks.Purchasing(context.Background(), Purchase{Quantity: 11}))
+
+	require.Len(t, hooks.Methods(), 1)
+	assert.Equal(t, "treasure.Dig", hooks.Methods()[0].Name)
+	assert.Equal(t, []string{"treasure"}, hooks.Modules())
+}
+
+func TestLoadRejectsInvalidMethods(t *testing.T) {
+	noop := func(ctx context.Context, userID string, params json.RawMessage) (any, error) { return nil, nil }
+
+	_, err := Load(logger.GetGlobalLogger(), []Module{{
+		Name: "bad",
+		Setup: func(r *Registrar) error {
+			r.Handle("Dig", noop)
+			return nil
+		},
+	}})
+	assert.Error(t, err)
+
+	_, err = Load(logger.GetGlobalLogger(), []Module{
+		{Name: "one", Setup: func(r *Registrar) error { r.Handle("treasure.Dig", noop); return nil }},
+		{Name: "two", Setup: func(r *Registrar) error { r.Handle("treasure.Dig", noop); return nil }},
+	})
+	assert.Error(t, err, "two modules must not add the same method")
+}
+
+func TestNilHooks(t *testing.T) {
+	var hooks *Hooks
+
+	assert.NotPanics(t, func() { hooks.PlayerMoved(context.Background(), PlayerMove{}) })
+	assert.NoError(t, hooks.Purchasing(context.Background(), Purchase{}))
+	assert.Empty(t, hooks.Methods())
+}
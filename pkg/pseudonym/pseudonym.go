@@ -0,0 +1,180 @@
+// Package pseudonym replaces identifiers in outgoing data with keyed hashes so analytics can
+// count and join activity without learning who it belongs to. Hashes use a salt derived from a
+// secret, the sink name and the time period, so the same ID maps to the same pseudonym only
+// within one sink and one salt period.
+package pseudonym
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Length is the number of hex characters kept from each hash
+const Length = 32
+
+// DefaultHashFields are the payload fields that hold user identifiers in game events
+var DefaultHashFields = []string{
+	"user_id", "trainer_id", "player_id", "owner_id", "previous_owner_id", "new_owner_id",
+	"target_id", "entity_id", "aggregate_id", "referrer_id", "referred_id",
+}
+
+// DefaultDropFields are the payload fields that directly identify a person
+var DefaultDropFields = []string{
+	"email", "name", "nickname", "device_id", "ip", "ip_address", "user_agent",
+}
+
+// Config holds how a sink's identifiers are hashed
+type Config struct {
+	Secret     string        // Base64 key of at least 32 bytes, shared by all sinks
+	Sink       string        // Sink name; pseudonyms of different sinks can't be joined
+	Rotation   time.Duration // How often the salt changes; zero keeps one salt forever
+	HashFields []string      // Fields replaced by pseudonyms, defaults to DefaultHashFields
+	DropFields []string      // Fields removed, defaults to DefaultDropFields
+}
+
+// Scrubber pseudonymizes identifiers and strips direct identifiers from JSON payloads
+type Scrubber struct {
+	secret   []byte
+	sink     string
+	rotation time.Duration
+	hash     map[string]bool
+	drop     map[string]bool
+}
+
+// New creates a scrubber from config
+func New(cfg Config) (*Scrubber, error) {
+	secret, err := base64.StdEncoding.DecodeString(cfg.Secret)
+	if err != nil || len(secret) < 32 {
+		return nil, fmt.Errorf("pseudonym: secret must be at least 32 base64-encoded bytes")
+	}
+	if cfg.Sink == "" {
+		return nil, fmt.Errorf("pseudonym: sink name is required")
+	}
+	if cfg.Rotation < 0 {
+		return nil, fmt.Errorf("pseudonym: rotation must not be negative")
+	}
+
+	hashFields := cfg.HashFields
+	if len(hashFields) == 0 {
+		hashFields = DefaultHashFields
+	}
+	dropFields := cfg.DropFields
+	if len(dropFields) == 0 {
+		dropFields = DefaultDropFields
+	}
+
+	return &Scrubber{
+		secret:   secret,
+		sink:     cfg.Sink,
+		rotation: cfg.Rotation,
+		hash:     toSet(hashFields),
+		drop:     toSet(dropFields),
+	}, nil
+}
+
+// ID returns the pseudonym of id for data from time at. Empty IDs stay empty.
+func (s *Scrubber) ID(id string, at time.Time) string {
+	if id == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, s.salt(at))
+	mac.Write([]byte(id))
+	return hex.EncodeToString(mac.Sum(nil))[:Length]
+}
+
+// Scrub returns a copy of a JSON payload with hash fields pseudonymized and drop fields
+// removed, at any depth. String values and arrays of strings are hashed; other values of a
+// hash field are dropped since they can't be hashed consistently.
+func (s *Scrubber) Scrub(payload []byte, at time.Time) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, fmt.Errorf("pseudonym: invalid JSON payload: %w", err)
+	}
+
+	return json.Marshal(s.scrubValue(value, at))
+}
+
+// salt derives the salt of the period containing at
+func (s *Scrubber) salt(at time.Time) []byte {
+	var period int64
+	if s.rotation > 0 {
+		period = at.UnixNano() / int64(s.rotation)
+	}
+
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(s.sink))
+	mac.Write([]byte{0})
+	mac.Write([]byte(strconv.FormatInt(period, 10)))
+	return mac.Sum(nil)
+}
+
+// scrubValue walks a decoded JSON value
+func (s *Scrubber) scrubValue(value interface{}, at time.Time) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			switch {
+			case s.drop[key]:
+				delete(v, key)
+			case s.hash[key]:
+				if hashed, ok := s.hashValue(field, at); ok {
+					v[key] = hashed
+				} else {
+					delete(v, key)
+				}
+			default:
+				v[key] = s.scrubValue(field, at)
+			}
+		}
+		return v
+	case []interface{}:
+		for i := range v {
+			v[i] = s.scrubValue(v[i], at)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// hashValue pseudonymizes a hash field's value if it is a string or a list of strings
+func (s *Scrubber) hashValue(value interface{}, at time.Time) (interface{}, bool) {
+	switch v := value.(type) {
+	case string:
+		return s.ID(v, at), true
+	case []interface{}:
+		hashed := make([]interface{}, 0, len(v))
+		for _, item := range v {
+			id, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			hashed = append(hashed, s.ID(id, at))
+		}
+		return hashed, true
+	case nil:
+		return nil, true
+	default:
+		return nil, false
+	}
+}
+
+// toSet builds a lookup set from field names
+func toSet(fields []string) map[string]bool {
+	set := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		set[field] = true
+	}
+	return set
+}
@@ -0,0 +1,61 @@
+package pseudonym
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = base64.StdEncoding.EncodeToString([]byte(strings.Repeat("s", 32)))
+
+func TestScrubber_ID(t *testing.T) {
+	daily, err := New(Config{Secret: testSecret, Sink: "warehouse", Rotation: 24 * time.Hour})
+	require.NoError(t, err)
+	other, err := New(Config{Secret: testSecret, Sink: "dashboards", Rotation: 24 * time.Hour})
+	require.NoError(t, err)
+
+	morning := time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC)
+	evening := morning.Add(12 * time.Hour)
+	nextDay := morning.Add(24 * time.Hour)
+
+	id := daily.ID("user-1", morning)
+	assert.Len(t, id, Length)
+	assert.NotContains(t, id, "user-1")
+	assert.Equal(t, id, daily.ID("user-1", evening), "stable within a salt period")
+	assert.NotEqual(t, id, daily.ID("user-1", nextDay), "unlinkable across salt periods")
+	assert.NotEqual(t, id, other.ID("user-1", morning), "unlinkable across sinks")
+	assert.Empty(t, daily.ID("", morning))
+
+	_, err = New(Config{Secret: "short", Sink: "warehouse"})
+	assert.Error(t, err)
+}
+
+func TestScrubber_Scrub(t *testing.T) {
+	s, err := New(Config{Secret: testSecret, Sink: "warehouse"})
+	require.NoError(t, err)
+	at := time.Now()
+
+	payload := `{"trainer_id":"user-1","name":"Ash","position":{"x":1.5,"y":2},
+		"items":[{"owner_id":"user-1","email":"ash@example.com"}],"target_id":42}`
+
+	scrubbed, err := s.Scrub([]byte(payload), at)
+	require.NoError(t, err)
+	assert.NotContains(t, string(scrubbed), "user-1")
+	assert.NotContains(t, string(scrubbed), "Ash")
+	assert.NotContains(t, string(scrubbed), "ash@example.com")
+
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(scrubbed, &out))
+	assert.Equal(t, s.ID("user-1", at), out["trainer_id"])
+	assert.Equal(t, map[string]interface{}{"x": 1.5, "y": 2.0}, out["position"], "other fields are kept")
+	assert.Equal(t, s.ID("user-1", at), out["items"].([]interface{})[0].(map[string]interface{})["owner_id"])
+	assert.NotContains(t, out, "target_id", "identifiers that can't be hashed are dropped")
+
+	_, err = s.Scrub([]byte("not json"), at)
+	assert.Error(t, err)
+}
@@ -70,6 +70,9 @@ func NewClient(redisURL string, log *logger.Logger, opts ...ClientOption) (*Clie
 		return nil, fmt.Errorf("failed to parse Redis URL: %w", err)
 	}
 
+	// Commands stop at the caller's context deadline instead of waiting out the read timeout
+	redisOptions.ContextTimeoutEnabled = true
+
 	rdb := redis.NewClient(redisOptions)
 
 	client := &Client{
@@ -146,6 +149,31 @@ func (c *Client) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// Warm opens up to n pooled connections ahead of the first requests, which would otherwise each
+// pay for a dial and handshake. The connections stay in the pool as idle connections.
+func (c *Client) Warm(ctx context.Context, n int) error {
+	n = min(n, c.Options().PoolSize)
+
+	// Hold every connection until the end so each ping dials a new one
+	conns := make([]*redis.Conn, 0, n)
+	defer func() {
+		for _, conn := range conns {
+			conn.Close()
+		}
+	}()
+
+	for i := 0; i < n; i++ {
+		conn := c.Conn()
+		conns = append(conns, conn)
+		if err := conn.Ping(ctx).Err(); err != nil {
+			return fmt.Errorf("failed to open connection %d of %d: %w", i+1, n, err)
+		}
+	}
+
+	c.logger.Debug("Redis connection pool warmed", zap.Int("connections", n))
+	return nil
+}
+
 // SetWithExpiration sets a key-value pair with expiration
 func (c *Client) SetWithExpiration(ctx context.Context, key string, value any, expiration time.Duration) error {
 	start := time.Now()
@@ -0,0 +1,314 @@
+package redisx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/pkg/instance"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+// keySpec says where a command's keys are
+type keySpec int
+
+const (
+	noKeys       keySpec = iota // Connection and server commands
+	firstKey                    // CMD key ...
+	allKeys                     // CMD key [key ...]
+	firstTwo                    // CMD source destination ...
+	allButLast                  // CMD key [key ...] path
+	numKeys                     // CMD script numkeys key [key ...] arg ...
+	streamsKeys                 // CMD ... STREAMS key [key ...] id [id ...]
+	subcommand                  // CMD SUBCOMMAND key ...
+	scanMatch                   // SCAN cursor [MATCH pattern] ...
+	keysPattern                 // KEYS pattern
+	searchIndex                 // FT.* index ...
+	searchCreate                // FT.CREATE index ... PREFIX n prefix [prefix ...] ...
+)
+
+// commandKeys maps the commands the server sends to where their keys are. Commands missing
+// here are refused for tenants rather than risk reading another tenant's keys.
+var commandKeys = map[string]keySpec{
+	"ping": noKeys, "echo": noKeys, "hello": noKeys, "auth": noKeys, "select": noKeys,
+	"client": noKeys, "info": noKeys, "time": noKeys, "command": noKeys, "script": noKeys,
+	"multi": noKeys, "exec": noKeys, "discard": noKeys, "unwatch": noKeys, "quit": noKeys,
+	"readonly": noKeys, "readwrite": noKeys,
+	// Pub/sub channels are shared; notifications are addressed to users, not keys
+	"publish": noKeys,
+
+	"get": firstKey, "set": firstKey, "setnx": firstKey, "setex": firstKey, "psetex": firstKey,
+	"getdel": firstKey, "getex": firstKey, "getset": firstKey, "append": firstKey, "strlen": firstKey,
+	"incr": firstKey, "incrby": firstKey, "incrbyfloat": firstKey, "decr": firstKey, "decrby": firstKey,
+	"expire": firstKey, "pexpire": firstKey, "expireat": firstKey, "pexpireat": firstKey,
+	"ttl": firstKey, "pttl": firstKey, "persist": firstKey, "type": firstKey,
+	"hget": firstKey, "hset": firstKey, "hsetnx": firstKey, "hmset": firstKey, "hgetall": firstKey,
+	"hdel": firstKey, "hincrby": firstKey, "hincrbyfloat": firstKey, "hmget": firstKey,
+	"hexists": firstKey, "hlen": firstKey, "hkeys": firstKey, "hvals": firstKey,
+	"sadd": firstKey, "srem": firstKey, "smembers": firstKey, "sismember": firstKey,
+	"smismember": firstKey, "scard": firstKey, "spop": firstKey, "srandmember": firstKey,
+	"zadd": firstKey, "zrem": firstKey, "zrange": firstKey, "zrangebyscore": firstKey,
+	"zrangebylex": firstKey, "zrevrange": firstKey, "zrevrangebyscore": firstKey,
+	"zrevrangebylex": firstKey, "zscore": firstKey, "zcard": firstKey, "zincrby": firstKey,
+	"zcount": firstKey, "zrank": firstKey, "zrevrank": firstKey, "zremrangebyscore": firstKey,
+	"zremrangebyrank": firstKey, "zremrangebylex": firstKey, "zpopmin": firstKey, "zpopmax": firstKey,
+	"lpush": firstKey, "rpush": firstKey, "lrange": firstKey, "ltrim": firstKey, "llen": firstKey,
+	"lpop": firstKey, "rpop": firstKey, "lrem": firstKey, "lindex": firstKey, "lset": firstKey,
+	"xadd": firstKey, "xrange": firstKey, "xrevrange": firstKey, "xlen": firstKey, "xtrim": firstKey,
+	"xdel": firstKey, "xack": firstKey, "xpending": firstKey, "xclaim": firstKey, "xautoclaim": firstKey,
+	"json.set": firstKey, "json.get": firstKey, "json.del": firstKey, "json.forget": firstKey,
+	"json.type": firstKey, "json.numincrby": firstKey, "json.arrappend": firstKey,
+	"json.arrlen": firstKey, "json.merge": firstKey, "json.clear": firstKey,
+	"geoadd": firstKey, "geopos": firstKey, "geodist": firstKey, "geosearch": firstKey,
+
+	"del": allKeys, "unlink": allKeys, "exists": allKeys, "touch": allKeys, "mget": allKeys,
+	"watch": allKeys, "sunion": allKeys, "sinter": allKeys, "sdiff": allKeys,
+	"rename": firstTwo, "renamenx": firstTwo, "smove": firstTwo, "lmove": firstTwo,
+	"json.mget": allButLast,
+	"eval":      numKeys, "evalsha": numKeys, "eval_ro": numKeys, "evalsha_ro": numKeys,
+	"xread": streamsKeys, "xreadgroup": streamsKeys,
+	"xgroup": subcommand, "xinfo": subcommand,
+	"scan":      scanMatch,
+	"keys":      keysPattern,
+	"ft.search": searchIndex, "ft.aggregate": searchIndex, "ft.info": searchIndex,
+	"ft.dropindex": searchIndex, "ft.alter": searchIndex,
+	"ft.create": searchCreate,
+}
+
+// namespaceHook prefixes the keys of commands run for a tenant, see Namespace
+type namespaceHook struct {
+	tenants []tenant.ID
+}
+
+// Namespace returns a hook that moves each tenant's keys under its prefix: commands run with
+// a tenant in their context have their keys, key patterns and search indexes prefixed, and
+// the prefix stripped from the keys SCAN, KEYS and FT.SEARCH return. Creating or dropping a
+// search index without a tenant does the same for every tenant listed, so each tenant gets
+// its own index over its own documents. Commands run in an instance have its prefix added
+// after the tenant's.
+func Namespace(tenants ...tenant.ID) redis.Hook {
+	return namespaceHook{tenants: tenants}
+}
+
+// scopePrefix returns the prefix of the keys of the context's tenant and instance
+func scopePrefix(ctx context.Context) string {
+	return tenant.IDFromContext(ctx).Prefix() + instance.IDFromContext(ctx).Prefix()
+}
+
+// DialHook leaves connections alone
+func (h namespaceHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+// ProcessHook namespaces a single command
+func (h namespaceHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		prefix := scopePrefix(ctx)
+		if prefix == "" {
+			err := next(ctx, cmd)
+			if isIndexSchemaCommand(cmd) {
+				h.replicateIndexCommand(ctx, next, cmd)
+			}
+			return err
+		}
+
+		if err := namespaceCommand(prefix, cmd); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		err := next(ctx, cmd)
+		stripReply(prefix, cmd)
+		return err
+	}
+}
+
+// ProcessPipelineHook namespaces every command of a pipeline or transaction
+func (h namespaceHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		prefix := scopePrefix(ctx)
+		if prefix == "" {
+			return next(ctx, cmds)
+		}
+
+		for _, cmd := range cmds {
+			if err := namespaceCommand(prefix, cmd); err != nil {
+				for _, c := range cmds {
+					c.SetErr(err)
+				}
+				return err
+			}
+		}
+		err := next(ctx, cmds)
+		for _, cmd := range cmds {
+			stripReply(prefix, cmd)
+		}
+		return err
+	}
+}
+
+// replicateIndexCommand repeats an index creation or removal for every tenant, whether or not
+// it succeeded for the default tenant: dropping an index the default tenant doesn't have yet
+// must still drop the tenants' ones. Failures show up when the tenant searches.
+func (h namespaceHook) replicateIndexCommand(ctx context.Context, next redis.ProcessHook, cmd redis.Cmder) {
+	for _, id := range h.tenants {
+		if id == tenant.Default {
+			continue
+		}
+
+		args := make([]interface{}, len(cmd.Args()))
+		copy(args, cmd.Args())
+		replica := redis.NewCmd(ctx, args...)
+		if err := namespaceCommand(id.Prefix(), replica); err != nil {
+			continue
+		}
+		_ = next(ctx, replica)
+	}
+}
+
+// isIndexSchemaCommand reports whether a command creates or drops a search index
+func isIndexSchemaCommand(cmd redis.Cmder) bool {
+	name := cmd.Name()
+	return name == "ft.create" || name == "ft.dropindex"
+}
+
+// namespaceCommand prefixes the keys of a command in place
+func namespaceCommand(prefix string, cmd redis.Cmder) error {
+	spec, ok := commandKeys[cmd.Name()]
+	if !ok {
+		return fmt.Errorf("redis command %q cannot be scoped to a tenant", cmd.Name())
+	}
+
+	args := cmd.Args()
+	prefixAt := func(i int) {
+		if i < len(args) {
+			args[i] = prefix + argString(args[i])
+		}
+	}
+
+	switch spec {
+	case firstKey, searchIndex:
+		prefixAt(1)
+	case allKeys:
+		for i := 1; i < len(args); i++ {
+			prefixAt(i)
+		}
+	case firstTwo:
+		prefixAt(1)
+		prefixAt(2)
+	case allButLast:
+		for i := 1; i < len(args)-1; i++ {
+			prefixAt(i)
+		}
+	case numKeys:
+		if len(args) < 3 {
+			return nil
+		}
+		n, err := strconv.Atoi(argString(args[2]))
+		if err != nil {
+			return fmt.Errorf("redis command %q has an invalid key count", cmd.Name())
+		}
+		for i := 3; i < 3+n; i++ {
+			prefixAt(i)
+		}
+	case streamsKeys:
+		for i := 1; i < len(args); i++ {
+			if strings.EqualFold(argString(args[i]), "streams") {
+				streams := (len(args) - i - 1) / 2
+				for j := i + 1; j <= i+streams; j++ {
+					prefixAt(j)
+				}
+				break
+			}
+		}
+	case subcommand:
+		prefixAt(2)
+	case scanMatch:
+		for i := 2; i < len(args)-1; i++ {
+			if strings.EqualFold(argString(args[i]), "match") {
+				prefixAt(i + 1)
+				return nil
+			}
+		}
+		// Without a pattern SCAN would walk every tenant's keys
+		return fmt.Errorf("redis SCAN needs a MATCH pattern to be scoped to a tenant")
+	case keysPattern:
+		prefixAt(1)
+	case searchCreate:
+		prefixAt(1)
+		for i := 2; i < len(args)-1; i++ {
+			if strings.EqualFold(argString(args[i]), "prefix") {
+				n, err := strconv.Atoi(argString(args[i+1]))
+				if err != nil {
+					return fmt.Errorf("redis FT.CREATE has an invalid prefix count")
+				}
+				for j := i + 2; j < i+2+n; j++ {
+					prefixAt(j)
+				}
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// stripReply removes the prefix from the keys a command returned
+func stripReply(prefix string, cmd redis.Cmder) {
+	switch c := cmd.(type) {
+	case *redis.ScanCmd:
+		keys, cursor := c.Val()
+		c.SetVal(stripKeys(prefix, keys), cursor)
+	case *redis.StringSliceCmd:
+		if cmd.Name() == "keys" {
+			c.SetVal(stripKeys(prefix, c.Val()))
+		}
+	case *redis.Cmd:
+		if cmd.Name() == "ft.search" {
+			c.SetVal(stripSearchReply(prefix, c.Val()))
+		}
+	}
+}
+
+// stripKeys removes the prefix from keys
+func stripKeys(prefix string, keys []string) []string {
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, prefix)
+	}
+	return keys
+}
+
+// stripSearchReply removes the prefix from the document keys in an FT.SEARCH reply, in
+// either the RESP2 list or RESP3 map form
+func stripSearchReply(prefix string, reply interface{}) interface{} {
+	switch r := reply.(type) {
+	case string:
+		return strings.TrimPrefix(r, prefix)
+	case []interface{}:
+		for i := range r {
+			r[i] = stripSearchReply(prefix, r[i])
+		}
+	case map[interface{}]interface{}:
+		for k, v := range r {
+			r[k] = stripSearchReply(prefix, v)
+		}
+	case map[string]interface{}:
+		for k, v := range r {
+			r[k] = stripSearchReply(prefix, v)
+		}
+	}
+	return reply
+}
+
+// argString returns a command argument as a string
+func argString(arg interface{}) string {
+	switch a := arg.(type) {
+	case string:
+		return a
+	case []byte:
+		return string(a)
+	default:
+		return fmt.Sprint(a)
+	}
+}
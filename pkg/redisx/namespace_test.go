@@ -0,0 +1,75 @@
+package redisx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/pkg/instance"
+	"github.com/danghamo/life/pkg/tenant"
+)
+
+func TestNamespaceCommand(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		args []interface{}
+		want []interface{}
+	}{
+		{"first key", []interface{}{"hset", "trainer:1", "name", "a"}, []interface{}{"hset", "t:acme:trainer:1", "name", "a"}},
+		{"all keys", []interface{}{"del", "a", "b"}, []interface{}{"del", "t:acme:a", "t:acme:b"}},
+		{"script keys", []interface{}{"evalsha", "sha", 1, "bucket", "10"}, []interface{}{"evalsha", "sha", 1, "t:acme:bucket", "10"}},
+		{"streams", []interface{}{"xreadgroup", "group", "g", "c", "streams", "s1", "s2", ">", ">"}, []interface{}{"xreadgroup", "group", "g", "c", "streams", "t:acme:s1", "t:acme:s2", ">", ">"}},
+		{"scan match", []interface{}{"scan", 0, "match", "game-events.*", "count", 100}, []interface{}{"scan", 0, "match", "t:acme:game-events.*", "count", 100}},
+		{"index prefixes", []interface{}{"FT.CREATE", "idx:trainer", "ON", "JSON", "PREFIX", 1, "trainer:", "SCHEMA"}, []interface{}{"FT.CREATE", "t:acme:idx:trainer", "ON", "JSON", "PREFIX", 1, "t:acme:trainer:", "SCHEMA"}},
+		{"no keys", []interface{}{"publish", "notifications", "hello"}, []interface{}{"publish", "notifications", "hello"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := redis.NewCmd(ctx, tt.args...)
+			require.NoError(t, namespaceCommand("t:acme:", cmd))
+			assert.Equal(t, tt.want, cmd.Args())
+		})
+	}
+}
+
+func TestScopePrefix(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, scopePrefix(ctx))
+
+	roomCtx := instance.WithInstance(ctx, "room1")
+	assert.Equal(t, "i:room1:", scopePrefix(roomCtx))
+
+	acme := &tenant.Tenant{ID: "acme"}
+	assert.Equal(t, "t:acme:", scopePrefix(tenant.WithTenant(ctx, acme)))
+	assert.Equal(t, "t:acme:i:room1:", scopePrefix(tenant.WithTenant(roomCtx, acme)))
+}
+
+func TestNamespaceCommand_Refused(t *testing.T) {
+	ctx := context.Background()
+
+	// Unknown commands could touch any key
+	assert.Error(t, namespaceCommand("t:acme:", redis.NewCmd(ctx, "flushall")))
+
+	// SCAN without a pattern walks every tenant's keys
+	assert.Error(t, namespaceCommand("t:acme:", redis.NewCmd(ctx, "scan", 0)))
+}
+
+func TestStripReply(t *testing.T) {
+	ctx := context.Background()
+
+	scan := redis.NewScanCmd(ctx, nil, "scan", 0, "match", "t:acme:*")
+	scan.SetVal([]string{"t:acme:trainer:1"}, 0)
+	stripReply("t:acme:", scan)
+	keys, _ := scan.Val()
+	assert.Equal(t, []string{"trainer:1"}, keys)
+
+	search := redis.NewCmd(ctx, "ft.search", "t:acme:idx:trainer", "*")
+	search.SetVal([]interface{}{int64(1), "t:acme:trainer:1", []interface{}{"$", "{}"}})
+	stripReply("t:acme:", search)
+	assert.Equal(t, []interface{}{int64(1), "trainer:1", []interface{}{"$", "{}"}}, search.Val())
+}
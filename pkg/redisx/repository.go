@@ -0,0 +1,417 @@
+package redisx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/pkg/jsonx"
+)
+
+var (
+	// ErrNotFound is returned for a missing document unless Options.NotFound is set
+	ErrNotFound = errors.New("document not found")
+	// ErrAlreadyExists is returned when inserting over a document unless Options.AlreadyExists is set
+	ErrAlreadyExists = errors.New("document already exists")
+)
+
+// Codec encodes documents and stores the encoding under a key
+type Codec[T any] interface {
+	// Read returns the encoding stored under key, or nil when there is none
+	Read(ctx context.Context, c redis.Cmdable, key string) ([]byte, error)
+	// Write queues storing data under key
+	Write(ctx context.Context, pipe redis.Pipeliner, key string, data []byte)
+	Encode(value *T) ([]byte, error)
+	Decode(data []byte) (*T, error)
+}
+
+// JSONCodec stores documents as RedisJSON documents, so they can be indexed with FT.CREATE ON JSON
+type JSONCodec[T any] struct{}
+
+// Read reads the root of the JSON document
+func (JSONCodec[T]) Read(ctx context.Context, c redis.Cmdable, key string) ([]byte, error) {
+	data, err := c.JSONGet(ctx, key, "$").Result()
+	if err != nil && err != redis.Nil {
+		return nil, err
+	}
+	if data == "" || data == "null" {
+		return nil, nil
+	}
+
+	// A "$" path returns the matches as an array
+	var matches []json.RawMessage
+	if err := json.Unmarshal([]byte(data), &matches); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON array from Redis: %w", err)
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	return matches[0], nil
+}
+
+// Write replaces the JSON document
+func (JSONCodec[T]) Write(ctx context.Context, pipe redis.Pipeliner, key string, data []byte) {
+	pipe.JSONSet(ctx, key, "$", string(data))
+}
+
+// Encode marshals the document with jsonx
+func (JSONCodec[T]) Encode(value *T) ([]byte, error) {
+	return jsonx.Marshal(value)
+}
+
+// Decode unmarshals the document
+func (JSONCodec[T]) Decode(data []byte) (*T, error) {
+	value := new(T)
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// HashCodec stores documents in the data field of a hash. Marshal and Unmarshal default to
+// encoding/json; set them to transform the document, e.g. to encrypt personal data.
+type HashCodec[T any] struct {
+	Marshal   func(value *T) ([]byte, error)
+	Unmarshal func(data []byte, value *T) error
+}
+
+// hashDataField is the hash field holding the encoded document
+const hashDataField = "data"
+
+// Read reads the data field
+func (HashCodec[T]) Read(ctx context.Context, c redis.Cmdable, key string) ([]byte, error) {
+	data, err := c.HGet(ctx, key, hashDataField).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Write sets the data field
+func (HashCodec[T]) Write(ctx context.Context, pipe redis.Pipeliner, key string, data []byte) {
+	pipe.HSet(ctx, key, hashDataField, string(data))
+}
+
+// Encode marshals the document
+func (c HashCodec[T]) Encode(value *T) ([]byte, error) {
+	if c.Marshal != nil {
+		return c.Marshal(value)
+	}
+	return json.Marshal(value)
+}
+
+// Decode unmarshals the document
+func (c HashCodec[T]) Decode(data []byte) (*T, error) {
+	value := new(T)
+	unmarshal := c.Unmarshal
+	if unmarshal == nil {
+		unmarshal = func(data []byte, value *T) error { return json.Unmarshal(data, value) }
+	}
+	if err := unmarshal(data, value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// StringCodec stores documents as string values. Writes keep the key's expiry, so documents
+// stored with a TTL outside the repository still expire. Marshal and Unmarshal default to
+// encoding/json like HashCodec's.
+type StringCodec[T any] struct {
+	Marshal   func(value *T) ([]byte, error)
+	Unmarshal func(data []byte, value *T) error
+}
+
+// Read gets the value
+func (StringCodec[T]) Read(ctx context.Context, c redis.Cmdable, key string) ([]byte, error) {
+	data, err := c.Get(ctx, key).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	return data, err
+}
+
+// Write sets the value
+func (StringCodec[T]) Write(ctx context.Context, pipe redis.Pipeliner, key string, data []byte) {
+	pipe.Set(ctx, key, data, redis.KeepTTL)
+}
+
+// Encode marshals the document
+func (c StringCodec[T]) Encode(value *T) ([]byte, error) {
+	return HashCodec[T](c).Encode(value)
+}
+
+// Decode unmarshals the document
+func (c StringCodec[T]) Decode(data []byte) (*T, error) {
+	return HashCodec[T](c).Decode(data)
+}
+
+// Index keeps a secondary index in step with the documents. Update is queued in the
+// transaction writing the document; before is nil when the document is inserted and after
+// is nil when it is deleted.
+type Index[T any] interface {
+	Update(ctx context.Context, pipe redis.Pipeliner, id string, before, after *T)
+}
+
+// IndexFunc adapts a function to an Index
+type IndexFunc[T any] func(ctx context.Context, pipe redis.Pipeliner, id string, before, after *T)
+
+// Update calls f
+func (f IndexFunc[T]) Update(ctx context.Context, pipe redis.Pipeliner, id string, before, after *T) {
+	f(ctx, pipe, id, before, after)
+}
+
+// SetIndex adds document IDs to the set named by Key. Documents for which Key returns "" are
+// not indexed.
+type SetIndex[T any] struct {
+	Key func(value *T) string
+}
+
+// Update moves the ID to the document's current set. The ID is added again even when the set
+// didn't change, which repairs entries lost before.
+func (i SetIndex[T]) Update(ctx context.Context, pipe redis.Pipeliner, id string, before, after *T) {
+	newKey := i.key(after)
+	if oldKey := i.key(before); oldKey != "" && oldKey != newKey {
+		pipe.SRem(ctx, oldKey, id)
+	}
+	if newKey != "" {
+		pipe.SAdd(ctx, newKey, id)
+	}
+}
+
+func (i SetIndex[T]) key(value *T) string {
+	if value == nil {
+		return ""
+	}
+	return i.Key(value)
+}
+
+// UniqueIndex points the string key named by Key at the document ID. Documents for which Key
+// returns "" are not indexed.
+type UniqueIndex[T any] struct {
+	Key func(value *T) string
+}
+
+// Update drops the document's previous key and sets its current one
+func (i UniqueIndex[T]) Update(ctx context.Context, pipe redis.Pipeliner, id string, before, after *T) {
+	newKey := i.key(after)
+	if oldKey := i.key(before); oldKey != "" && oldKey != newKey {
+		pipe.Del(ctx, oldKey)
+	}
+	if newKey != "" {
+		pipe.Set(ctx, newKey, id, 0)
+	}
+}
+
+func (i UniqueIndex[T]) key(value *T) string {
+	if value == nil {
+		return ""
+	}
+	return i.Key(value)
+}
+
+// Options configure a Repository
+type Options[T any] struct {
+	// Codec defaults to JSONCodec
+	Codec   Codec[T]
+	Indexes []Index[T]
+	// NotFound and AlreadyExists build the errors for missing and existing documents, usually
+	// domain errors; they default to ErrNotFound and ErrAlreadyExists
+	NotFound      func() error
+	AlreadyExists func() error
+}
+
+// Repository stores documents of type T under prefix+ID. Writes run in optimistic WATCH
+// transactions that also update the secondary indexes, following the IoC pattern: callbacks
+// get the current document and return the one to store, or nil to leave it unchanged.
+type Repository[T any] struct {
+	client  *redis.Client
+	prefix  string
+	name    string
+	codec   Codec[T]
+	indexes []Index[T]
+
+	notFound      func() error
+	alreadyExists func() error
+}
+
+// NewRepository creates a repository for the documents stored under prefix, e.g. "trainer:"
+func NewRepository[T any](client *redis.Client, prefix string, opts Options[T]) *Repository[T] {
+	r := &Repository[T]{
+		client:        client,
+		prefix:        prefix,
+		name:          strings.TrimSuffix(prefix, ":"),
+		codec:         opts.Codec,
+		indexes:       opts.Indexes,
+		notFound:      opts.NotFound,
+		alreadyExists: opts.AlreadyExists,
+	}
+	if r.codec == nil {
+		r.codec = JSONCodec[T]{}
+	}
+	if r.notFound == nil {
+		r.notFound = func() error { return ErrNotFound }
+	}
+	if r.alreadyExists == nil {
+		r.alreadyExists = func() error { return ErrAlreadyExists }
+	}
+	return r
+}
+
+// Key returns the key a document is stored under
+func (r *Repository[T]) Key(id string) string {
+	return r.prefix + id
+}
+
+// FindOneAndUpsert applies callback to the document, or to nil when there is none, and stores
+// the result
+func (r *Repository[T]) FindOneAndUpsert(ctx context.Context, id string, callback func(*T) (*T, error)) error {
+	return r.write(ctx, id, callback)
+}
+
+// FindOneAndInsert stores the document callback creates, failing when one exists
+func (r *Repository[T]) FindOneAndInsert(ctx context.Context, id string, callback func() (*T, error)) error {
+	return r.write(ctx, id, func(current *T) (*T, error) {
+		if current != nil {
+			return nil, r.alreadyExists()
+		}
+
+		result, err := callback()
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			return nil, fmt.Errorf("callback returned nil %s", r.name)
+		}
+		return result, nil
+	})
+}
+
+// FindOneAndUpdate applies callback to the document and stores the result, failing when there
+// is none
+func (r *Repository[T]) FindOneAndUpdate(ctx context.Context, id string, callback func(*T) (*T, error)) error {
+	return r.write(ctx, id, func(current *T) (*T, error) {
+		if current == nil {
+			return nil, r.notFound()
+		}
+		return callback(current)
+	})
+}
+
+// GetByID returns the document, or nil when there is none
+func (r *Repository[T]) GetByID(ctx context.Context, id string) (*T, error) {
+	data, err := r.codec.Read(ctx, r.client, r.Key(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s from Redis: %w", r.name, err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	return r.decode(data)
+}
+
+// Delete removes the document and its index entries, failing when there is none
+func (r *Repository[T]) Delete(ctx context.Context, id string) error {
+	return r.FindOneAndDelete(ctx, id, func(*T) error { return nil })
+}
+
+// FindOneAndDelete applies callback to the document and removes it with its index entries
+// unless callback fails, e.g. to claim a document only once. It fails when there is none.
+// Indexes get the document as it was read.
+func (r *Repository[T]) FindOneAndDelete(ctx context.Context, id string, callback func(*T) error) error {
+	key := r.Key(id)
+
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		data, err := r.codec.Read(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+		if data == nil {
+			return r.notFound()
+		}
+
+		current, err := r.decode(data)
+		if err != nil {
+			return err
+		}
+		before := current
+		if len(r.indexes) > 0 {
+			if before, err = r.decode(data); err != nil {
+				return err
+			}
+		}
+
+		if err := callback(current); err != nil {
+			return err
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Del(ctx, key)
+			for _, index := range r.indexes {
+				index.Update(ctx, pipe, id, before, nil)
+			}
+			return nil
+		})
+		return err
+	}, key)
+}
+
+// write reads the document in a WATCH transaction, applies change and stores the result
+// together with the index updates and the writes staged in ctx. Indexes get the document as
+// it was read even when change modifies it in place.
+func (r *Repository[T]) write(ctx context.Context, id string, change func(*T) (*T, error)) error {
+	key := r.Key(id)
+
+	return r.client.Watch(ctx, func(tx *redis.Tx) error {
+		data, err := r.codec.Read(ctx, tx, key)
+		if err != nil {
+			return err
+		}
+
+		var current, before *T
+		if data != nil {
+			if current, err = r.decode(data); err != nil {
+				return err
+			}
+			if len(r.indexes) > 0 {
+				if before, err = r.decode(data); err != nil {
+					return err
+				}
+			}
+		}
+
+		result, err := change(current)
+		staged := StageFrom(ctx).take()
+		if err != nil || result == nil {
+			return err
+		}
+
+		encoded, err := r.codec.Encode(result)
+		if err != nil {
+			return fmt.Errorf("failed to serialize %s: %w", r.name, err)
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			r.codec.Write(ctx, pipe, key, encoded)
+			for _, index := range r.indexes {
+				index.Update(ctx, pipe, id, before, result)
+			}
+			for _, write := range staged {
+				write(ctx, pipe)
+			}
+			return nil
+		})
+		return err
+	}, key)
+}
+
+func (r *Repository[T]) decode(data []byte) (*T, error) {
+	value, err := r.codec.Decode(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to deserialize %s: %w", r.name, err)
+	}
+	return value, nil
+}
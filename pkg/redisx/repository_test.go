@@ -0,0 +1,178 @@
+package redisx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testDocument struct {
+	Name  string `json:"name"`
+	Group string `json:"group"`
+}
+
+func TestHashCodec_Defaults(t *testing.T) {
+	codec := HashCodec[testDocument]{}
+
+	data, err := codec.Encode(&testDocument{Name: "a", Group: "g"})
+	require.NoError(t, err)
+
+	decoded, err := codec.Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, &testDocument{Name: "a", Group: "g"}, decoded)
+}
+
+func TestStringCodec_Unmarshal(t *testing.T) {
+	codec := StringCodec[testDocument]{
+		Unmarshal: func(data []byte, d *testDocument) error {
+			d.Group = "default"
+			return json.Unmarshal(data, d)
+		},
+	}
+
+	data, err := codec.Encode(&testDocument{Name: "a"})
+	require.NoError(t, err)
+
+	decoded, err := codec.Decode([]byte(`{"name":"a"}`))
+	require.NoError(t, err)
+	assert.Equal(t, &testDocument{Name: "a", Group: "default"}, decoded, "missing fields keep their defaults")
+	assert.JSONEq(t, `{"name":"a","group":""}`, string(data))
+}
+
+func TestRepository(t *testing.T) {
+	if !isRedisAvailable() {
+		t.Skip("Redis is not available, skipping test")
+	}
+
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379", DB: 0})
+	defer client.Close()
+
+	repo := NewRepository(client, "redisx-test:", Options[testDocument]{
+		Codec: HashCodec[testDocument]{},
+		Indexes: []Index[testDocument]{
+			SetIndex[testDocument]{Key: func(d *testDocument) string { return "redisx-test-group:" + d.Group }},
+			UniqueIndex[testDocument]{Key: func(d *testDocument) string { return "redisx-test-name:" + d.Name }},
+		},
+	})
+	defer client.Del(ctx, "redisx-test:1", "redisx-test-group:a", "redisx-test-group:b", "redisx-test-name:x", "redisx-test-name:y")
+
+	require.NoError(t, repo.FindOneAndInsert(ctx, "1", func() (*testDocument, error) {
+		return &testDocument{Name: "x", Group: "a"}, nil
+	}))
+	assert.ErrorIs(t, repo.FindOneAndInsert(ctx, "1", func() (*testDocument, error) {
+		return &testDocument{}, nil
+	}), ErrAlreadyExists)
+
+	// Changing the document in place still moves its index entries
+	require.NoError(t, repo.FindOneAndUpdate(ctx, "1", func(d *testDocument) (*testDocument, error) {
+		d.Name, d.Group = "y", "b"
+		return d, nil
+	}))
+	assert.Empty(t, client.SMembers(ctx, "redisx-test-group:a").Val())
+	assert.Equal(t, []string{"1"}, client.SMembers(ctx, "redisx-test-group:b").Val())
+	assert.Zero(t, client.Exists(ctx, "redisx-test-name:x").Val())
+	assert.Equal(t, "1", client.Get(ctx, "redisx-test-name:y").Val())
+
+	got, err := repo.GetByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, &testDocument{Name: "y", Group: "b"}, got)
+
+	require.NoError(t, repo.Delete(ctx, "1"))
+	assert.Empty(t, client.SMembers(ctx, "redisx-test-group:b").Val())
+	assert.Zero(t, client.Exists(ctx, "redisx-test-name:y").Val())
+	assert.ErrorIs(t, repo.Delete(ctx, "1"), ErrNotFound)
+	assert.ErrorIs(t, repo.FindOneAndUpdate(ctx, "1", func(d *testDocument) (*testDocument, error) {
+		return d, nil
+	}), ErrNotFound)
+
+	got, err = repo.GetByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestRepository_Stage(t *testing.T) {
+	if !isRedisAvailable() {
+		t.Skip("Redis is not available, skipping test")
+	}
+
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379", DB: 0})
+	defer client.Close()
+
+	repo := NewRepository(client, "redisx-test:", Options[testDocument]{Codec: HashCodec[testDocument]{}})
+	defer client.Del(ctx, "redisx-test:1", "redisx-test-staged")
+
+	stage := func(ctx context.Context) {
+		StageFrom(ctx).Add(func(ctx context.Context, pipe redis.Pipeliner) {
+			pipe.Incr(ctx, "redisx-test-staged")
+		})
+	}
+
+	// Staged writes commit with the document
+	stagedCtx := WithStage(ctx)
+	require.NoError(t, repo.FindOneAndUpsert(stagedCtx, "1", func(d *testDocument) (*testDocument, error) {
+		stage(stagedCtx)
+		return &testDocument{Name: "x"}, nil
+	}))
+	assert.Equal(t, "1", client.Get(ctx, "redisx-test-staged").Val())
+
+	// and are dropped when it doesn't change
+	require.ErrorIs(t, repo.FindOneAndUpdate(stagedCtx, "1", func(d *testDocument) (*testDocument, error) {
+		stage(stagedCtx)
+		return nil, ErrAlreadyExists
+	}), ErrAlreadyExists)
+	require.NoError(t, repo.FindOneAndUpdate(stagedCtx, "1", func(d *testDocument) (*testDocument, error) {
+		stage(stagedCtx)
+		return nil, nil
+	}))
+	require.NoError(t, repo.FindOneAndUpdate(stagedCtx, "1", func(d *testDocument) (*testDocument, error) {
+		return d, nil
+	}))
+	assert.Equal(t, "1", client.Get(ctx, "redisx-test-staged").Val())
+}
+
+func TestRepository_StringCodec(t *testing.T) {
+	if !isRedisAvailable() {
+		t.Skip("Redis is not available, skipping test")
+	}
+
+	ctx := context.Background()
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379", DB: 0})
+	defer client.Close()
+
+	repo := NewRepository(client, "redisx-test:", Options[testDocument]{
+		Codec:   StringCodec[testDocument]{},
+		Indexes: []Index[testDocument]{SetIndex[testDocument]{Key: func(d *testDocument) string { return "redisx-test-group:" + d.Group }}},
+	})
+	defer client.Del(ctx, "redisx-test:1", "redisx-test-group:a")
+
+	// Writes keep an expiry set outside the repository
+	require.NoError(t, client.Set(ctx, "redisx-test:1", `{"name":"x","group":"a"}`, time.Minute).Err())
+	client.SAdd(ctx, "redisx-test-group:a", "1")
+	require.NoError(t, repo.FindOneAndUpdate(ctx, "1", func(d *testDocument) (*testDocument, error) {
+		d.Name = "y"
+		return d, nil
+	}))
+	assert.Positive(t, client.TTL(ctx, "redisx-test:1").Val())
+
+	// A failing callback keeps the document
+	claimed := errors.New("claimed")
+	assert.ErrorIs(t, repo.FindOneAndDelete(ctx, "1", func(d *testDocument) error { return claimed }), claimed)
+
+	var deleted *testDocument
+	require.NoError(t, repo.FindOneAndDelete(ctx, "1", func(d *testDocument) error {
+		deleted = d
+		return nil
+	}))
+	assert.Equal(t, &testDocument{Name: "y", Group: "a"}, deleted)
+	assert.Zero(t, client.Exists(ctx, "redisx-test:1").Val())
+	assert.Empty(t, client.SMembers(ctx, "redisx-test-group:a").Val())
+	assert.ErrorIs(t, repo.FindOneAndDelete(ctx, "1", func(d *testDocument) error { return nil }), ErrNotFound)
+}
@@ -0,0 +1,53 @@
+package redisx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// StagedWrite queues a write into the transaction of a document write
+type StagedWrite func(ctx context.Context, pipe redis.Pipeliner)
+
+// Stage collects writes that must commit together with a document, such as events recorded
+// in an outbox. Writes staged before or while a Repository writes with the stage's context
+// are queued into that write's transaction: they commit with the document, or are dropped
+// when the callback fails or leaves the document unchanged.
+type Stage struct {
+	mu     sync.Mutex
+	writes []StagedWrite
+}
+
+type stageKey struct{}
+
+// WithStage returns a context carrying a new stage
+func WithStage(ctx context.Context) context.Context {
+	return context.WithValue(ctx, stageKey{}, &Stage{})
+}
+
+// StageFrom returns the stage of a context, or nil when it has none
+func StageFrom(ctx context.Context) *Stage {
+	stage, _ := ctx.Value(stageKey{}).(*Stage)
+	return stage
+}
+
+// Add stages a write
+func (s *Stage) Add(write StagedWrite) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.writes = append(s.writes, write)
+}
+
+// take removes and returns the staged writes
+func (s *Stage) take() []StagedWrite {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	writes := s.writes
+	s.writes = nil
+	return writes
+}
@@ -0,0 +1,54 @@
+// Package requestid correlates what a single user action sets off. The ID a request is
+// assigned travels in its context, the metadata of the events it publishes and the
+// notifications those events send, and every log line on the way carries it.
+package requestid
+
+import (
+	"context"
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+// Header is the HTTP header requests bring their ID in and responses echo it back in
+const Header = "X-Request-ID"
+
+// MetadataKey is the message metadata key events carry the ID of the request that published
+// them in
+const MetadataKey = "request_id"
+
+// validID keeps IDs clients choose short and safe to log
+var validID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// New returns a new request ID
+func New() string {
+	return uuid.New().String()
+}
+
+// Valid reports whether a client chosen ID can be accepted
+func Valid(id string) bool {
+	return validID.MatchString(id)
+}
+
+// contextKey is the context key of the request ID
+type contextKey struct{}
+
+// With returns a context carrying a request ID
+func With(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the request ID a context carries, "" if it has none
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
+
+// FromContextOrNew returns the request ID a context carries, or a new one for work no
+// request set off, such as ticks and scheduled tasks
+func FromContextOrNew(ctx context.Context) string {
+	if id := FromContext(ctx); id != "" {
+		return id
+	}
+	return New()
+}
@@ -0,0 +1,27 @@
+package requestid
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	assert.True(t, Valid(New()))
+	assert.True(t, Valid("client-42:retry.1"))
+	assert.False(t, Valid(""))
+	assert.False(t, Valid("spaces are not allowed"))
+	assert.False(t, Valid("line\nbreak"))
+	assert.False(t, Valid(strings.Repeat("a", 129)))
+}
+
+func TestContext(t *testing.T) {
+	assert.Empty(t, FromContext(context.Background()))
+
+	ctx := With(context.Background(), "abc")
+	assert.Equal(t, "abc", FromContext(ctx))
+	assert.Equal(t, "abc", FromContextOrNew(ctx))
+	assert.True(t, Valid(FromContextOrNew(context.Background())), "contexts without an ID get a new one")
+}
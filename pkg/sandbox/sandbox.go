@@ -0,0 +1,136 @@
+// Package sandbox runs untrusted Starlark scripts. A script can only call the builtins it is
+// given: it has no file, network or clock access, cannot load other modules, and is stopped
+// once it runs too many steps or for too long. Whatever it prints is captured as its output.
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// Limits bound what a single script run may use
+type Limits struct {
+	MaxSteps  uint64        // Starlark computation steps before the script is stopped
+	Timeout   time.Duration // Wall-clock time before the script is stopped, including builtin calls
+	MaxOutput int           // Bytes of printed output kept; the rest is dropped
+}
+
+// DefaultLimits returns limits suited to short event scripts run from an API request
+func DefaultLimits() Limits {
+	return Limits{
+		MaxSteps:  1_000_000,
+		Timeout:   10 * time.Second,
+		MaxOutput: 16 << 10,
+	}
+}
+
+// ErrLimitExceeded is returned when a script is stopped for running too long
+var ErrLimitExceeded = errors.New("script exceeded its execution limits")
+
+// fileOptions allows loops and ifs at the top level, where event scripts do most of their
+// work, but keeps while loops and recursion out so scripts stay simple and terminate
+var fileOptions = &syntax.FileOptions{TopLevelControl: true}
+
+// Check compiles a script against the names its builtins provide, reporting syntax errors
+// and references to undefined names without running it
+func Check(name, source string, builtins starlark.StringDict) error {
+	_, _, err := starlark.SourceProgramOptions(fileOptions, name, source, builtins.Has)
+	return err
+}
+
+// Result is the outcome of a script run
+type Result struct {
+	Output    string `json:"output"`
+	Truncated bool   `json:"truncated,omitempty"`
+	Steps     uint64 `json:"steps"`
+}
+
+// Run executes a script with the given builtins. Builtins receive the thread the script
+// runs on, whose context is available through Context. The result holds the output
+// printed up to the point the script stopped, also when it fails.
+func Run(ctx context.Context, name, source string, builtins starlark.StringDict, limits Limits) (*Result, error) {
+	if limits.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, limits.Timeout)
+		defer cancel()
+	}
+
+	output := &boundedBuffer{limit: limits.MaxOutput}
+	thread := &starlark.Thread{
+		Name: name,
+		Print: func(_ *starlark.Thread, msg string) {
+			output.WriteLine(msg)
+		},
+	}
+	thread.SetLocal(contextKey, ctx)
+	if limits.MaxSteps > 0 {
+		thread.SetMaxExecutionSteps(limits.MaxSteps)
+	}
+
+	// Builtins that block honour ctx themselves; pure Starlark code is interrupted here
+	stop := context.AfterFunc(ctx, func() {
+		thread.Cancel(ctx.Err().Error())
+	})
+	defer stop()
+
+	_, err := starlark.ExecFileOptions(fileOptions, thread, name, source, builtins)
+
+	result := &Result{
+		Output:    output.String(),
+		Truncated: output.truncated,
+		Steps:     thread.ExecutionSteps(),
+	}
+	if err != nil {
+		if ctx.Err() != nil || (limits.MaxSteps > 0 && result.Steps >= limits.MaxSteps) {
+			return result, fmt.Errorf("%w: %v", ErrLimitExceeded, err)
+		}
+		var evalErr *starlark.EvalError
+		if errors.As(err, &evalErr) {
+			return result, errors.New(evalErr.Backtrace())
+		}
+		return result, err
+	}
+	return result, nil
+}
+
+// contextKey is the thread-local holding the context of a run
+const contextKey = "sandbox.context"
+
+// Context returns the context of the run a builtin was called from
+func Context(thread *starlark.Thread) context.Context {
+	if ctx, ok := thread.Local(contextKey).(context.Context); ok {
+		return ctx
+	}
+	return context.Background()
+}
+
+// boundedBuffer collects printed lines up to a size limit
+type boundedBuffer struct {
+	builder   strings.Builder
+	limit     int
+	truncated bool
+}
+
+// WriteLine appends a line, dropping whatever does not fit
+func (b *boundedBuffer) WriteLine(line string) {
+	if b.truncated {
+		return
+	}
+	line += "\n"
+	if b.limit > 0 && b.builder.Len()+len(line) > b.limit {
+		line = line[:b.limit-b.builder.Len()]
+		b.truncated = true
+	}
+	b.builder.WriteString(line)
+}
+
+// String returns the collected output
+func (b *boundedBuffer) String() string {
+	return b.builder.String()
+}
@@ -0,0 +1,73 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.starlark.net/starlark"
+)
+
+func TestRunCallsBuiltinsAndCapturesOutput(t *testing.T) {
+	var granted []string
+	builtins := starlark.StringDict{
+		"grant": starlark.NewBuiltin("grant", func(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+			var name string
+			if err := starlark.UnpackArgs(fn.Name(), args, kwargs, "name", &name); err != nil {
+				return nil, err
+			}
+			granted = append(granted, name)
+			return starlark.None, nil
+		}),
+	}
+
+	result, err := Run(context.Background(), "event.star", `
+for name in ["ann", "bob"]:
+    grant(name)
+print("granted", len(["ann", "bob"]))
+`, builtins, DefaultLimits())
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ann", "bob"}, granted)
+	assert.Equal(t, "granted 2\n", result.Output)
+}
+
+func TestRunStopsRunawayScripts(t *testing.T) {
+	limits := Limits{MaxSteps: 10_000, Timeout: time.Second}
+
+	_, err := Run(context.Background(), "loop.star", `
+def spin():
+    for i in range(100000000):
+        pass
+spin()
+`, nil, limits)
+
+	assert.True(t, errors.Is(err, ErrLimitExceeded))
+}
+
+func TestRunHasNoLoad(t *testing.T) {
+	_, err := Run(context.Background(), "load.star", `load("other.star", "x")`, nil, DefaultLimits())
+
+	assert.Error(t, err)
+}
+
+func TestRunTruncatesOutput(t *testing.T) {
+	limits := DefaultLimits()
+	limits.MaxOutput = 8
+
+	result, err := Run(context.Background(), "print.star", `print("0123456789")`, nil, limits)
+
+	require.NoError(t, err)
+	assert.Equal(t, "01234567", result.Output)
+	assert.True(t, result.Truncated)
+}
+
+func TestCheckReportsUndefinedNames(t *testing.T) {
+	err := Check("bad.star", `grant("ann")`, starlark.StringDict{})
+
+	assert.Error(t, err)
+	assert.NoError(t, Check("good.star", `grant("ann")`, starlark.StringDict{"grant": starlark.None}))
+}
@@ -0,0 +1,121 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/danghamo/life/pkg/awssig"
+)
+
+// AWSConfig holds the AWS Secrets Manager secret holding the server's secrets, a JSON object
+// whose keys are secret names. Empty credentials and region are read from the standard AWS_*
+// environment variables.
+type AWSConfig struct {
+	Region       string // Empty uses AWS_REGION
+	SecretID     string // Name or ARN of the secret
+	Endpoint     string // Empty is https://secretsmanager.<region>.amazonaws.com
+	AccessKey    string // Empty uses AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and AWS_SESSION_TOKEN
+	SecretKey    string
+	SessionToken string
+}
+
+// AWS reads secrets from an AWS Secrets Manager secret
+type AWS struct {
+	document
+	cfg    AWSConfig
+	signer awssig.Signer
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewAWS creates a provider reading the secret cfg points at
+func NewAWS(cfg AWSConfig) (*AWS, error) {
+	if cfg.Region == "" {
+		cfg.Region = os.Getenv("AWS_REGION")
+	}
+	if cfg.AccessKey == "" {
+		cfg.AccessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		cfg.SecretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		cfg.SessionToken = os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if cfg.Region == "" || cfg.SecretID == "" {
+		return nil, fmt.Errorf("AWS region and secret ID are required")
+	}
+	if cfg.AccessKey == "" || cfg.SecretKey == "" {
+		return nil, fmt.Errorf("AWS access key and secret key are required")
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://secretsmanager." + cfg.Region + ".amazonaws.com"
+	}
+	if endpoint, err := url.Parse(cfg.Endpoint); err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid AWS endpoint %q", cfg.Endpoint)
+	}
+
+	a := &AWS{
+		cfg: cfg,
+		signer: awssig.Signer{
+			AccessKey:    cfg.AccessKey,
+			SecretKey:    cfg.SecretKey,
+			SessionToken: cfg.SessionToken,
+			Region:       cfg.Region,
+			Service:      "secretsmanager",
+		},
+		client: &http.Client{Timeout: 10 * time.Second},
+		now:    time.Now,
+	}
+	a.fetch = a.read
+	return a, nil
+}
+
+// Get returns a key of the secret
+func (a *AWS) Get(ctx context.Context, name string) (string, error) {
+	return a.get(ctx, name)
+}
+
+// read reads the current version of the secret with GetSecretValue
+func (a *AWS) read(ctx context.Context) (map[string]string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": a.cfg.SecretID})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(a.cfg.Endpoint, "/")+"/", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	a.signer.Sign(req, body, a.now())
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach AWS Secrets Manager: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("AWS Secrets Manager read of %s failed with status %d: %s", a.cfg.SecretID, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	var result struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode AWS Secrets Manager response: %w", err)
+	}
+
+	var values map[string]string
+	if err := json.Unmarshal([]byte(result.SecretString), &values); err != nil {
+		return nil, fmt.Errorf("AWS secret %s must be a JSON object of strings: %w", a.cfg.SecretID, err)
+	}
+	return values, nil
+}
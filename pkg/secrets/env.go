@@ -0,0 +1,25 @@
+package secrets
+
+import (
+	"context"
+	"os"
+	"strings"
+)
+
+// Env reads secrets from environment variables named like the secret in upper case with dots
+// replaced by underscores, e.g. AUTH_JWT_SECRET, the same variables the config reads
+type Env struct{}
+
+// Get returns the variable of a secret; set but empty counts as missing
+func (Env) Get(ctx context.Context, name string) (string, error) {
+	value := os.Getenv(EnvName(name))
+	if value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
+
+// EnvName returns the environment variable of a secret
+func EnvName(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, ".", "_"))
+}
@@ -0,0 +1,48 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// File reads secrets from a directory holding a file per secret, named like the secret, as
+// Docker and Kubernetes mount them, e.g. /run/secrets/auth.jwt_secret
+type File struct {
+	dir string
+}
+
+// NewFile creates a provider reading the secret files of dir
+func NewFile(dir string) (*File, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("secrets directory is required")
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open secrets directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("secrets path %s is not a directory", dir)
+	}
+	return &File{dir: dir}, nil
+}
+
+// Get returns the content of a secret's file without its trailing newline
+func (f *File) Get(ctx context.Context, name string) (string, error) {
+	if !fs.ValidPath(name) || strings.Contains(name, "/") {
+		return "", fmt.Errorf("invalid secret name %q", name)
+	}
+
+	content, err := os.ReadFile(filepath.Join(f.dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read secret %s: %w", name, err)
+	}
+	return strings.TrimRight(string(content), "\r\n"), nil
+}
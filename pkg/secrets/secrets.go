@@ -0,0 +1,73 @@
+// Package secrets reads secrets such as signing keys and passwords from where a deployment
+// keeps them: environment variables, files mounted by the orchestrator, HashiCorp Vault or
+// AWS Secrets Manager. Secrets are named like the config keys they fill in, e.g.
+// auth.jwt_secret.
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotFound is returned when a provider has no secret by a name
+var ErrNotFound = errors.New("secret not found")
+
+// Provider reads secrets by name
+type Provider interface {
+	// Get returns a secret, or ErrNotFound if the provider doesn't have it
+	Get(ctx context.Context, name string) (string, error)
+}
+
+// Config selects and configures a provider
+type Config struct {
+	Kind  string      // env, file, vault or aws; empty is env
+	Dir   string      // Directory of the file provider
+	Vault VaultConfig // Secret document of the vault provider
+	AWS   AWSConfig   // Secret document of the aws provider
+}
+
+// New creates the provider a config selects
+func New(cfg Config) (Provider, error) {
+	switch cfg.Kind {
+	case "", "env":
+		return Env{}, nil
+	case "file":
+		return NewFile(cfg.Dir)
+	case "vault":
+		return NewVault(cfg.Vault)
+	case "aws":
+		return NewAWS(cfg.AWS)
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %q", cfg.Kind)
+	}
+}
+
+// document is a JSON object of secrets kept in a remote store, fetched on first use so a
+// provider makes one request however many secrets are read
+type document struct {
+	fetch  func(ctx context.Context) (map[string]string, error)
+	mutex  sync.Mutex
+	values map[string]string
+}
+
+// get returns a secret of the document, fetching it unless fetched before
+func (d *document) get(ctx context.Context, name string) (string, error) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if d.values == nil {
+		values, err := d.fetch(ctx)
+		if err != nil {
+			return "", err
+		}
+		d.values = values
+	}
+
+	value, ok := d.values[name]
+	if !ok || value == "" {
+		return "", ErrNotFound
+	}
+	return value, nil
+}
@@ -0,0 +1,120 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnv(t *testing.T) {
+	t.Setenv("AUTH_JWT_SECRET", "from-env")
+
+	value, err := Env{}.Get(context.Background(), "auth.jwt_secret")
+	require.NoError(t, err)
+	assert.Equal(t, "from-env", value)
+
+	_, err = Env{}.Get(context.Background(), "mail.smtp_password")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "auth.jwt_secret"), []byte("from-file\n"), 0o600))
+
+	provider, err := NewFile(dir)
+	require.NoError(t, err)
+
+	value, err := provider.Get(context.Background(), "auth.jwt_secret")
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", value, "trailing newlines are trimmed")
+
+	_, err = provider.Get(context.Background(), "mail.smtp_password")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = provider.Get(context.Background(), "../etc/passwd")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	_, err = NewFile(filepath.Join(dir, "missing"))
+	assert.Error(t, err)
+}
+
+func TestVault(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-Vault-Token") != "root" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		assert.Equal(t, "/v1/kv/data/life/production", r.URL.Path)
+		w.Write([]byte(`{"data":{"data":{"auth.jwt_secret":"from-vault"},"metadata":{"version":3}}}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewVault(VaultConfig{Address: server.URL, Token: "root", Mount: "kv", Path: "life/production"})
+	require.NoError(t, err)
+
+	value, err := provider.Get(context.Background(), "auth.jwt_secret")
+	require.NoError(t, err)
+	assert.Equal(t, "from-vault", value)
+
+	_, err = provider.Get(context.Background(), "mail.smtp_password")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.Equal(t, 1, requests, "the secret is read once")
+
+	denied, err := NewVault(VaultConfig{Address: server.URL, Token: "wrong", Path: "life/production"})
+	require.NoError(t, err)
+	_, err = denied.Get(context.Background(), "auth.jwt_secret")
+	assert.ErrorContains(t, err, "403")
+}
+
+func TestAWS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secretsmanager.GetSecretValue", r.Header.Get("X-Amz-Target"))
+		assert.True(t, strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/"))
+		assert.Contains(t, r.Header.Get("Authorization"), "/eu-west-1/secretsmanager/aws4_request")
+
+		body, _ := io.ReadAll(r.Body)
+		var input map[string]string
+		require.NoError(t, json.Unmarshal(body, &input))
+		assert.Equal(t, "life/production", input["SecretId"])
+
+		w.Write([]byte(`{"Name":"life/production","SecretString":"{\"auth.jwt_secret\":\"from-aws\"}"}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewAWS(AWSConfig{
+		Region:    "eu-west-1",
+		SecretID:  "life/production",
+		Endpoint:  server.URL,
+		AccessKey: "AKID",
+		SecretKey: "secret",
+	})
+	require.NoError(t, err)
+
+	value, err := provider.Get(context.Background(), "auth.jwt_secret")
+	require.NoError(t, err)
+	assert.Equal(t, "from-aws", value)
+
+	_, err = provider.Get(context.Background(), "mail.smtp_password")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestNew(t *testing.T) {
+	provider, err := New(Config{})
+	require.NoError(t, err)
+	assert.Equal(t, Env{}, provider, "env is the default")
+
+	_, err = New(Config{Kind: "keychain"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,94 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// VaultConfig holds the HashiCorp Vault secret holding the server's secrets, a KV version 2
+// secret whose keys are secret names
+type VaultConfig struct {
+	Address string // e.g. https://vault.internal:8200; empty uses VAULT_ADDR
+	Token   string // Empty uses VAULT_TOKEN
+	Mount   string // Path of the KV secrets engine; empty is secret
+	Path    string // Path of the secret in the engine, e.g. life/production
+}
+
+// Vault reads secrets from a HashiCorp Vault KV version 2 secret
+type Vault struct {
+	document
+	cfg    VaultConfig
+	client *http.Client
+}
+
+// NewVault creates a provider reading the secret cfg points at
+func NewVault(cfg VaultConfig) (*Vault, error) {
+	if cfg.Address == "" {
+		cfg.Address = os.Getenv("VAULT_ADDR")
+	}
+	if cfg.Token == "" {
+		cfg.Token = os.Getenv("VAULT_TOKEN")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = "secret"
+	}
+	if cfg.Address == "" || cfg.Token == "" {
+		return nil, fmt.Errorf("vault address and token are required")
+	}
+	if cfg.Path == "" {
+		return nil, fmt.Errorf("vault secret path is required")
+	}
+	if _, err := url.Parse(cfg.Address); err != nil {
+		return nil, fmt.Errorf("invalid vault address %q", cfg.Address)
+	}
+
+	v := &Vault{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+	v.fetch = v.read
+	return v, nil
+}
+
+// Get returns a key of the secret
+func (v *Vault) Get(ctx context.Context, name string) (string, error) {
+	return v.get(ctx, name)
+}
+
+// vaultSecret is the response of reading a KV version 2 secret
+type vaultSecret struct {
+	Data struct {
+		Data map[string]string `json:"data"`
+	} `json:"data"`
+}
+
+// read reads the latest version of the secret
+func (v *Vault) read(ctx context.Context) (map[string]string, error) {
+	target := strings.TrimSuffix(v.cfg.Address, "/") + "/v1/" + strings.Trim(v.cfg.Mount, "/") + "/data/" + strings.Trim(v.cfg.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.cfg.Token)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("vault read of %s failed with status %d: %s", v.cfg.Path, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+
+	var secret vaultSecret
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("vault secret %s must map names to strings: %w", v.cfg.Path, err)
+	}
+	return secret.Data.Data, nil
+}
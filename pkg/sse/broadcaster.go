@@ -1,9 +1,12 @@
 package sse
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"sync"
 	"time"
 
@@ -23,6 +26,8 @@ type SSEClient struct {
 	Done     chan bool
 	LastSeen time.Time
 	mutex    sync.Mutex // Protects concurrent writes to this client
+
+	lastEventID uint64 // Latest numbered event the client has, guarded by mutex
 }
 
 // UserMessage represents a message targeted to a specific user
@@ -37,10 +42,14 @@ type SSEBroadcaster struct {
 	clients       map[string]*SSEClient
 	userClients   map[string][]*SSEClient // Map userID to their clients
 	mutex         sync.RWMutex
-	broadcast     chan []byte
+	broadcast     chan *bytes.Buffer // Encoded frames, released once sent to every client
 	userBroadcast chan UserMessage
 	cleanup       *time.Ticker
 	shutdown      chan struct{} // Global shutdown signal
+	onConnect     func(userID string) // Called when a user's first client connects
+	onDisconnect  func(userID string) // Called when a user's last client leaves
+	replay        *ReplayBuffer       // Where reconnecting clients catch up from; nil disables replay
+	latency       func(userID string) any // What heartbeats tell a user about their connection
 }
 
 // NewSSEBroadcaster creates a new SSE broadcaster
@@ -49,7 +58,7 @@ func NewSSEBroadcaster(logger *logger.Logger) *SSEBroadcaster {
 		logger:        logger.WithComponent("sse-broadcaster"),
 		clients:       make(map[string]*SSEClient),
 		userClients:   make(map[string][]*SSEClient),
-		broadcast:     make(chan []byte, 1000),
+		broadcast:     make(chan *bytes.Buffer, 1000),
 		userBroadcast: make(chan UserMessage, 1000),
 		cleanup:       time.NewTicker(30 * time.Second), // Cleanup every 30 seconds
 		shutdown:      make(chan struct{}),
@@ -75,12 +84,67 @@ func (b *SSEBroadcaster) AddClient(client *SSEClient) {
 		b.userClients[client.UserID] = make([]*SSEClient, 0)
 	}
 	b.userClients[client.UserID] = append(b.userClients[client.UserID], client)
+	if len(b.userClients[client.UserID]) == 1 && b.onConnect != nil {
+		go b.onConnect(client.UserID)
+	}
 	
 	b.logger.Debug("SSE client connected",
 		zap.String("clientId", client.ID),
 		zap.String("userId", client.UserID))
 }
 
+// OnConnect registers a function called when a user's first client connects
+func (b *SSEBroadcaster) OnConnect(fn func(userID string)) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.onConnect = fn
+}
+
+// OnDisconnect registers a function called when a user's last client disconnects
+func (b *SSEBroadcaster) OnDisconnect(fn func(userID string)) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.onDisconnect = fn
+}
+
+// SetReplayBuffer sets where clients reconnecting with a Last-Event-ID catch up from
+func (b *SSEBroadcaster) SetReplayBuffer(replay *ReplayBuffer) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.replay = replay
+}
+
+// SetHeartbeatLatency sets what heartbeats tell a user about their connection, sent as the
+// heartbeat's latency field
+func (b *SSEBroadcaster) SetHeartbeatLatency(fn func(userID string) any) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.latency = fn
+}
+
+// IsConnected reports whether a user has a connected client
+func (b *SSEBroadcaster) IsConnected(userID string) bool {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+	return len(b.userClients[userID]) > 0
+}
+
+// Disconnect closes the connections of the users to this server
+func (b *SSEBroadcaster) Disconnect(userIDs []string) {
+	b.mutex.RLock()
+	clientIDs := make([]string, 0)
+	for _, userID := range userIDs {
+		for _, client := range b.userClients[userID] {
+			clientIDs = append(clientIDs, client.ID)
+		}
+	}
+	b.mutex.RUnlock()
+
+	for _, clientID := range clientIDs {
+		b.RemoveClient(clientID)
+	}
+}
+
 // RemoveClient removes an SSE client
 func (b *SSEBroadcaster) RemoveClient(clientID string) {
 	b.mutex.Lock()
@@ -107,6 +171,9 @@ func (b *SSEBroadcaster) RemoveClient(clientID string) {
 			// Clean up empty user client slice
 			if len(b.userClients[client.UserID]) == 0 {
 				delete(b.userClients, client.UserID)
+				if b.onDisconnect != nil {
+					go b.onDisconnect(client.UserID)
+				}
 			}
 		}
 		
@@ -127,22 +194,47 @@ func (b *SSEBroadcaster) broadcastToUser(userID string, notification jsonrpcx.Js
 	case b.userBroadcast <- msg:
 	default:
 		b.logger.Warn("User broadcast channel full, dropping message",
-			zap.String("userId", userID))
+			zap.String("userId", userID),
+			zap.String("request_id", notification.RequestID))
 	}
 }
 
 // BroadcastToAll sends a JSON-RPC notification to all connected clients
 func (b *SSEBroadcaster) BroadcastToAll(notification jsonrpcx.JsonRpcNotification) {
-	data, err := json.Marshal(notification)
+	frame, err := encodeFrame(0, notification)
 	if err != nil {
 		b.logger.Error("Failed to marshal JSON-RPC notification", zap.Error(err))
 		return
 	}
 
 	select {
-	case b.broadcast <- data:
+	case b.broadcast <- frame:
 	default:
-		b.logger.Warn("Broadcast channel full, dropping message")
+		releaseFrame(frame)
+		b.logger.Warn("Broadcast channel full, dropping message",
+			zap.String("request_id", notification.RequestID))
+	}
+}
+
+// BroadcastToAllExcept sends a JSON-RPC notification to all connected clients but those of the
+// excluded users
+func (b *SSEBroadcaster) BroadcastToAllExcept(excludedUsers []string, notification jsonrpcx.JsonRpcNotification) {
+	if len(excludedUsers) == 0 {
+		b.BroadcastToAll(notification)
+		return
+	}
+
+	b.mutex.RLock()
+	targetUsers := make([]string, 0, len(b.userClients))
+	for userID := range b.userClients {
+		if !slices.Contains(excludedUsers, userID) {
+			targetUsers = append(targetUsers, userID)
+		}
+	}
+	b.mutex.RUnlock()
+
+	for _, userID := range targetUsers {
+		b.broadcastToUser(userID, notification)
 	}
 }
 
@@ -164,7 +256,8 @@ func (b *SSEBroadcaster) BroadcastToUsers(targetUsers []string, notification jso
 
 	if len(localTargetUsers) == 0 {
 		b.logger.Debug("No target users connected to this server", 
-			zap.Strings("targetUsers", targetUsers))
+			zap.Strings("targetUsers", targetUsers),
+			zap.String("request_id", notification.RequestID))
 		return
 	}
 
@@ -175,7 +268,8 @@ func (b *SSEBroadcaster) BroadcastToUsers(targetUsers []string, notification jso
 
 	b.logger.Debug("Broadcast sent to local target users",
 		zap.Strings("targetUsers", targetUsers),
-		zap.Strings("localTargetUsers", localTargetUsers))
+		zap.Strings("localTargetUsers", localTargetUsers),
+		zap.String("request_id", notification.RequestID))
 }
 
 // userBroadcastLoop handles broadcasting messages to specific users
@@ -206,7 +300,7 @@ func (b *SSEBroadcaster) userBroadcastLoop() {
 				continue
 			}
 
-			data, err := json.Marshal(msg.Notification)
+			frame, err := encodeFrame(msg.Notification.EventID, msg.Notification)
 			if err != nil {
 				b.logger.Error("Failed to marshal user notification", zap.Error(err))
 				continue
@@ -225,7 +319,7 @@ func (b *SSEBroadcaster) userBroadcastLoop() {
 				case <-client.Done:
 					toRemove = append(toRemove, client.ID)
 				default:
-					if err := b.sendToClient(client, data); err != nil {
+					if err := b.sendToClient(client, frame.Bytes(), msg.Notification.EventID); err != nil {
 						b.logger.Warn("Failed to send to user client",
 							zap.String("clientId", client.ID),
 							zap.String("userId", client.UserID),
@@ -234,6 +328,7 @@ func (b *SSEBroadcaster) userBroadcastLoop() {
 					}
 				}
 			}
+			releaseFrame(frame)
 			
 			// Remove failed clients
 			for _, clientID := range toRemove {
@@ -253,14 +348,20 @@ func (b *SSEBroadcaster) broadcastLoop() {
 		}
 	}()
 	
+	// Reused between events so fanning out doesn't allocate
+	var clients []*SSEClient
+
 	for {
 		select {
 		case <-b.shutdown:
 			b.logger.Info("Broadcast loop shutting down")
 			return
-		case data := <-b.broadcast:
+		case frame, ok := <-b.broadcast:
+			if !ok {
+				return // Closed by Close
+			}
 			b.mutex.RLock()
-			clients := make([]*SSEClient, 0, len(b.clients))
+			clients = clients[:0]
 			for _, client := range b.clients {
 				clients = append(clients, client)
 			}
@@ -271,7 +372,7 @@ func (b *SSEBroadcaster) broadcastLoop() {
 				case <-client.Done:
 					b.RemoveClient(client.ID)
 				default:
-					if err := b.sendToClient(client, data); err != nil {
+					if err := b.sendToClient(client, frame.Bytes(), 0); err != nil {
 						b.logger.Warn("Failed to send to client",
 							zap.String("clientId", client.ID),
 							zap.Error(err))
@@ -279,12 +380,16 @@ func (b *SSEBroadcaster) broadcastLoop() {
 					}
 				}
 			}
+			releaseFrame(frame)
+			clear(clients) // Don't keep disconnected clients reachable
 		}
 	}
 }
 
-// sendToClient sends data to a specific SSE client
-func (b *SSEBroadcaster) sendToClient(client *SSEClient, data []byte) (err error) {
+// sendToClient writes an encoded frame to a specific SSE client. The frame is shared by every
+// client receiving the event, so it is written as is and never retained. A numbered event the
+// client already got from replay is skipped.
+func (b *SSEBroadcaster) sendToClient(client *SSEClient, frame []byte, eventID uint64) (err error) {
 	// Recover from any panic
 	defer func() {
 		if r := recover(); r != nil {
@@ -321,21 +426,26 @@ func (b *SSEBroadcaster) sendToClient(client *SSEClient, data []byte) (err error
 		return fmt.Errorf("client connection closed")
 	default:
 	}
+
+	if eventID != 0 && eventID <= client.lastEventID {
+		return nil
+	}
 	
-	// Write SSE data with proper formatting
 	// Use a single write operation to reduce chunking issues
-	sseData := fmt.Sprintf("data: %s\n\n", data)
-	n, err := client.Writer.Write([]byte(sseData))
+	n, err := client.Writer.Write(frame)
 	if err != nil {
 		return fmt.Errorf("write failed: %w", err)
 	}
-	if n != len(sseData) {
-		return fmt.Errorf("incomplete write: wrote %d/%d bytes", n, len(sseData))
+	if n != len(frame) {
+		return fmt.Errorf("incomplete write: wrote %d/%d bytes", n, len(frame))
 	}
 
 	// Force flush immediately
 	client.Flusher.Flush()
 	client.LastSeen = time.Now()
+	if eventID != 0 {
+		client.lastEventID = eventID
+	}
 	return nil
 }
 
@@ -450,6 +560,9 @@ func (b *SSEBroadcaster) HandleSSE(w http.ResponseWriter, r *http.Request) {
 	
 	b.logger.Debug("SSE: Client created", zap.String("clientID", clientID))
 
+	// Hold live events back until the client caught up on what it missed
+	client.mutex.Lock()
+
 	// Add client to broadcaster
 	b.AddClient(client)
 	defer b.RemoveClient(clientID)
@@ -459,7 +572,12 @@ func (b *SSEBroadcaster) HandleSSE(w http.ResponseWriter, r *http.Request) {
 	// Send initial connection message
 	initialMsg := fmt.Sprintf("data: {\"type\":\"connected\",\"client_id\":\"%s\"}\n\n", clientID)
 	w.Write([]byte(initialMsg))
+
+	if lastEventID, ok := requestedLastEventID(r); ok {
+		b.replayMissed(r.Context(), client, lastEventID)
+	}
 	flusher.Flush()
+	client.mutex.Unlock()
 	
 	b.logger.Debug("SSE: Initial message sent and flushed")
 	
@@ -484,7 +602,7 @@ func (b *SSEBroadcaster) HandleSSE(w http.ResponseWriter, r *http.Request) {
 			b.logger.Debug("SSE broadcaster shutdown signal received", zap.String("clientId", clientID))
 			return
 		case <-heartbeat.C:
-			if err := b.sendHeartbeat(w, flusher); err != nil {
+			if err := b.sendHeartbeat(w, flusher, userID); err != nil {
 				b.logger.Warn("Failed to send heartbeat", 
 					zap.String("clientId", clientID),
 					zap.Error(err))
@@ -494,16 +612,101 @@ func (b *SSEBroadcaster) HandleSSE(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// sendHeartbeat sends a heartbeat message to the SSE client
-func (b *SSEBroadcaster) sendHeartbeat(w http.ResponseWriter, flusher http.Flusher) error {
-	heartbeatData := fmt.Sprintf("data: {\"type\":\"heartbeat\",\"timestamp\":\"%s\"}\n\n", time.Now().Format(time.RFC3339))
-	n, err := w.Write([]byte(heartbeatData))
+// sendHeartbeat sends a heartbeat message to the SSE client of a user
+func (b *SSEBroadcaster) sendHeartbeat(w http.ResponseWriter, flusher http.Flusher, userID string) error {
+	b.mutex.RLock()
+	latencyOf := b.latency
+	b.mutex.RUnlock()
+
+	var latency any
+	if latencyOf != nil {
+		latency = latencyOf(userID)
+	}
+	frame, err := heartbeatFrame(time.Now(), latency)
+	if err != nil {
+		return fmt.Errorf("heartbeat encoding failed: %w", err)
+	}
+	defer releaseFrame(frame)
+
+	n, err := w.Write(frame.Bytes())
 	if err != nil {
 		return fmt.Errorf("heartbeat write failed: %w", err)
 	}
-	if n != len(heartbeatData) {
-		return fmt.Errorf("incomplete heartbeat write: wrote %d/%d bytes", n, len(heartbeatData))
+	if n != frame.Len() {
+		return fmt.Errorf("incomplete heartbeat write: wrote %d/%d bytes", n, frame.Len())
 	}
 	flusher.Flush()
 	return nil
-}
\ No newline at end of file
+}
+
+// resyncFrame tells a client that some of the events it missed are gone, so it should reload
+// its state rather than rely on the replayed ones
+const resyncFrame = "data: {\"type\":\"resync_required\"}\n\n"
+
+// requestedLastEventID returns the ID of the last event a reconnecting client received. Browsers
+// send it in the Last-Event-ID header when they reconnect on their own; clients opening a new
+// stream pass it as the last_event_id query parameter.
+func requestedLastEventID(r *http.Request) (uint64, bool) {
+	value := r.Header.Get("Last-Event-ID")
+	if value == "" {
+		value = r.URL.Query().Get("last_event_id")
+	}
+	if value == "" {
+		return 0, false
+	}
+
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return id, true
+}
+
+// replayMissed writes the buffered events after lastEventID to a client whose mutex is held, so
+// it resumes where it left off. Live events the replay already covered are skipped afterwards.
+func (b *SSEBroadcaster) replayMissed(ctx context.Context, client *SSEClient, lastEventID uint64) {
+	b.mutex.RLock()
+	replay := b.replay
+	b.mutex.RUnlock()
+	if replay == nil {
+		return
+	}
+
+	// Bounded because live events to this client wait for the replay
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	events, latest, complete, err := replay.Since(ctx, client.UserID, lastEventID)
+	if err != nil {
+		b.logger.Warn("Failed to read missed events, asking client to resync",
+			zap.String("clientId", client.ID),
+			zap.Uint64("lastEventId", lastEventID),
+			zap.Error(err))
+		client.Writer.Write([]byte(resyncFrame))
+		return
+	}
+
+	for _, event := range events {
+		frame, err := encodeFrame(event.ID, event.Data)
+		if err != nil {
+			b.logger.Error("Failed to encode missed event", zap.Uint64("eventId", event.ID), zap.Error(err))
+			complete = false
+			continue
+		}
+		_, err = client.Writer.Write(frame.Bytes())
+		releaseFrame(frame)
+		if err != nil {
+			return
+		}
+	}
+	if !complete {
+		client.Writer.Write([]byte(resyncFrame))
+	}
+	client.lastEventID = latest
+
+	b.logger.Debug("Replayed missed events",
+		zap.String("clientId", client.ID),
+		zap.Uint64("lastEventId", lastEventID),
+		zap.Int("replayed", len(events)),
+		zap.Bool("complete", complete))
+}
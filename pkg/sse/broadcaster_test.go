@@ -1,6 +1,10 @@
 package sse
 
 import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
 	"testing"
 	"time"
 
@@ -181,3 +185,125 @@ func TestSSEBroadcaster_EmptyTargetUsers(t *testing.T) {
 	// Should complete without issues
 	assert.Equal(t, 0, broadcaster.GetClientCount())
 }
+
+func TestEncodeFrame(t *testing.T) {
+	frame, err := encodeFrame(0, jsonrpcx.JsonRpcNotification{Jsonrpc: "2.0", Method: "trainer.moved"})
+	assert.NoError(t, err)
+	assert.Equal(t, "data: {\"jsonrpc\":\"2.0\",\"method\":\"trainer.moved\"}\n\n", frame.String())
+	releaseFrame(frame)
+
+	// Numbered events carry their ID for the client to resume from
+	frame, err = encodeFrame(42, jsonrpcx.JsonRpcNotification{Jsonrpc: "2.0", Method: "chat.message"})
+	assert.NoError(t, err)
+	assert.Equal(t, "id: 42\ndata: {\"jsonrpc\":\"2.0\",\"method\":\"chat.message\"}\n\n", frame.String())
+	releaseFrame(frame)
+
+	// A reused buffer starts empty
+	frame, err = heartbeatFrame(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "data: {\"type\":\"heartbeat\",\"timestamp\":\"2026-01-02T03:04:05Z\"}\n\n", frame.String())
+	releaseFrame(frame)
+
+	frame, err = heartbeatFrame(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC), map[string]float64{"rtt_estimate_ms": 42.5})
+	assert.NoError(t, err)
+	assert.Equal(t, "data: {\"type\":\"heartbeat\",\"timestamp\":\"2026-01-02T03:04:05Z\",\"latency\":{\"rtt_estimate_ms\":42.5}}\n\n", frame.String())
+	releaseFrame(frame)
+}
+
+func TestSSEBroadcaster_SkipsReplayedEvents(t *testing.T) {
+	broadcaster := NewSSEBroadcaster(logger.NewDefault())
+	defer broadcaster.Close()
+
+	var delivered sync.WaitGroup
+	client := &SSEClient{
+		ID:          "client-1",
+		UserID:      "alice",
+		Writer:      &countingWriter{header: http.Header{}, delivered: &delivered},
+		Flusher:     &countingWriter{},
+		Done:        make(chan bool),
+		LastSeen:    time.Now(),
+		lastEventID: 7, // Replayed up to event 7
+	}
+
+	// Replayed events are skipped, later and unnumbered ones are sent
+	delivered.Add(2)
+	for _, id := range []uint64{6, 7, 8, 0} {
+		assert.NoError(t, broadcaster.sendToClient(client, []byte("data: {}\n\n"), id))
+	}
+	delivered.Wait()
+	assert.Equal(t, uint64(8), client.lastEventID)
+}
+
+func TestRequestedLastEventID(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/stream/positions?last_event_id=12", nil)
+	id, ok := requestedLastEventID(r)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(12), id)
+
+	// The header browsers send on reconnect wins over the query parameter
+	r.Header.Set("Last-Event-ID", "15")
+	id, ok = requestedLastEventID(r)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(15), id)
+
+	_, ok = requestedLastEventID(httptest.NewRequest(http.MethodGet, "/api/v1/stream/positions", nil))
+	assert.False(t, ok)
+}
+
+// countingWriter stands in for a client connection and reports each delivered event
+type countingWriter struct {
+	header    http.Header
+	delivered *sync.WaitGroup
+}
+
+func (w *countingWriter) Header() http.Header { return w.header }
+func (w *countingWriter) WriteHeader(int)     {}
+func (w *countingWriter) Flush()              {}
+func (w *countingWriter) Write(data []byte) (int, error) {
+	w.delivered.Done()
+	return len(data), nil
+}
+
+func BenchmarkSSEBroadcaster_BroadcastToAll(b *testing.B) {
+	notification := jsonrpcx.JsonRpcNotification{
+		Jsonrpc: "2.0",
+		Method:  "trainer.moved",
+		Params: map[string]interface{}{
+			"user_id":  "user-1",
+			"position": map[string]float64{"x": 12.5, "y": 7.25},
+		},
+	}
+
+	for _, clients := range []int{1, 100, 1000} {
+		b.Run(fmt.Sprintf("clients=%d", clients), func(b *testing.B) {
+			// Logging to stdout would interleave with the benchmark results
+			quiet, err := logger.New(logger.Config{Level: logger.ErrorLevel})
+			if err != nil {
+				b.Fatal(err)
+			}
+			broadcaster := NewSSEBroadcaster(quiet)
+			defer broadcaster.Close()
+
+			var delivered sync.WaitGroup
+			for i := 0; i < clients; i++ {
+				writer := &countingWriter{header: http.Header{}, delivered: &delivered}
+				broadcaster.AddClient(&SSEClient{
+					ID:       fmt.Sprintf("client-%d", i),
+					UserID:   fmt.Sprintf("user-%d", i),
+					Writer:   writer,
+					Flusher:  writer,
+					Done:     make(chan bool),
+					LastSeen: time.Now(),
+				})
+			}
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				delivered.Add(clients)
+				broadcaster.BroadcastToAll(notification)
+				delivered.Wait()
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// FanoutChannel is the Redis pub/sub channel shared by all server instances
+const FanoutChannel = "sse:fanout"
+
+// publishTimeout bounds how long a broadcast may wait on Redis
+const publishTimeout = 2 * time.Second
+
+// LocalBroadcaster delivers notifications to clients connected to this server
+type LocalBroadcaster interface {
+	BroadcastToUsers(targetUsers []string, notification jsonrpcx.JsonRpcNotification)
+	BroadcastToAll(notification jsonrpcx.JsonRpcNotification)
+	BroadcastToAllExcept(excludedUsers []string, notification jsonrpcx.JsonRpcNotification)
+	Disconnect(userIDs []string)
+}
+
+// fanoutMessage is the payload published on the fan-out channel
+type fanoutMessage struct {
+	TargetUsers  []string                     `json:"target_users,omitempty"` // Empty means all users
+	Notification jsonrpcx.JsonRpcNotification `json:"notification"`
+	EventIDs     map[string]uint64            `json:"event_ids,omitempty"` // The notification's event ID for each target user
+	ExcludeUsers []string                     `json:"exclude_users,omitempty"` // Users left out of a broadcast to all
+	Disconnect   bool                         `json:"disconnect,omitempty"`    // Close the target users' connections instead
+}
+
+// RedisFanout delivers notifications to clients on every server instance through Redis pub/sub.
+// Broadcasts are published once and each instance forwards them to its local clients.
+type RedisFanout struct {
+	logger *logger.Logger
+	client *redis.Client
+	local  LocalBroadcaster
+	replay *ReplayBuffer
+	pubsub *redis.PubSub
+}
+
+// NewRedisFanout creates a fan-out layer in front of the local broadcaster. Notifications to
+// specific users are numbered and buffered in replay, which may be nil, before publishing.
+func NewRedisFanout(logger *logger.Logger, client *redis.Client, local LocalBroadcaster, replay *ReplayBuffer) *RedisFanout {
+	return &RedisFanout{
+		logger: logger.WithComponent("sse-fanout"),
+		client: client,
+		local:  local,
+		replay: replay,
+	}
+}
+
+// BroadcastToUsers sends a notification to specific users on whichever server they are connected to
+func (f *RedisFanout) BroadcastToUsers(targetUsers []string, notification jsonrpcx.JsonRpcNotification) {
+	if len(targetUsers) == 0 {
+		return
+	}
+
+	msg := fanoutMessage{TargetUsers: targetUsers, Notification: notification}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	// Numbered once here so every instance sends the same IDs
+	eventIDs, err := f.replay.Record(ctx, targetUsers, notification)
+	if err != nil {
+		f.logger.Warn("Failed to buffer notification for replay, sending it unnumbered",
+			zap.String("method", notification.Method),
+			zap.String("request_id", notification.RequestID),
+			zap.Error(err))
+	}
+	msg.EventIDs = eventIDs
+
+	f.publish(msg)
+}
+
+// BroadcastToAll sends a notification to all users on every server
+func (f *RedisFanout) BroadcastToAll(notification jsonrpcx.JsonRpcNotification) {
+	f.publish(fanoutMessage{Notification: notification})
+}
+
+// BroadcastToAllExcept sends a notification to all users on every server but the excluded ones
+func (f *RedisFanout) BroadcastToAllExcept(excludedUsers []string, notification jsonrpcx.JsonRpcNotification) {
+	f.publish(fanoutMessage{Notification: notification, ExcludeUsers: excludedUsers})
+}
+
+// Disconnect closes the users' connections on every server
+func (f *RedisFanout) Disconnect(userIDs []string) {
+	if len(userIDs) == 0 {
+		return
+	}
+	f.publish(fanoutMessage{TargetUsers: userIDs, Disconnect: true})
+}
+
+// Start subscribes to the fan-out channel and forwards messages until the context is done
+func (f *RedisFanout) Start(ctx context.Context) error {
+	f.pubsub = f.client.Subscribe(ctx, FanoutChannel)
+
+	// Wait for the subscription to be confirmed so no broadcast is missed after startup
+	if _, err := f.pubsub.Receive(ctx); err != nil {
+		f.pubsub.Close()
+		return err
+	}
+
+	go f.receiveLoop(ctx)
+
+	f.logger.Info("SSE fan-out subscribed", zap.String("channel", FanoutChannel))
+	return nil
+}
+
+// Close stops the subscription
+func (f *RedisFanout) Close() {
+	if f.pubsub != nil {
+		f.pubsub.Close()
+	}
+}
+
+// publish sends a message to all instances, falling back to local delivery if Redis is unavailable
+func (f *RedisFanout) publish(msg fanoutMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		f.logger.Error("Failed to marshal fan-out message", zap.Error(err))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	if err := f.client.Publish(ctx, FanoutChannel, data).Err(); err != nil {
+		f.logger.Warn("Failed to publish fan-out message, delivering locally only",
+			zap.String("method", msg.Notification.Method),
+			zap.String("request_id", msg.Notification.RequestID),
+			zap.Error(err))
+		f.deliver(msg)
+	}
+}
+
+// receiveLoop forwards published messages to local clients
+func (f *RedisFanout) receiveLoop(ctx context.Context) {
+	ch := f.pubsub.Channel()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-ch:
+			if !ok {
+				return
+			}
+
+			var msg fanoutMessage
+			if err := json.Unmarshal([]byte(m.Payload), &msg); err != nil {
+				f.logger.Warn("Invalid fan-out message", zap.Error(err))
+				continue
+			}
+			f.deliver(msg)
+		}
+	}
+}
+
+// deliver hands a message to the local broadcaster
+func (f *RedisFanout) deliver(msg fanoutMessage) {
+	if msg.Disconnect {
+		f.local.Disconnect(msg.TargetUsers)
+		return
+	}
+	if len(msg.TargetUsers) == 0 {
+		if len(msg.ExcludeUsers) > 0 {
+			f.local.BroadcastToAllExcept(msg.ExcludeUsers, msg.Notification)
+			return
+		}
+		f.local.BroadcastToAll(msg.Notification)
+		return
+	}
+	if len(msg.EventIDs) == 0 {
+		f.local.BroadcastToUsers(msg.TargetUsers, msg.Notification)
+		return
+	}
+
+	// Each user's copy carries the user's own event ID
+	for _, userID := range msg.TargetUsers {
+		notification := msg.Notification
+		notification.EventID = msg.EventIDs[userID]
+		f.local.BroadcastToUsers([]string{userID}, notification)
+	}
+}
@@ -0,0 +1,179 @@
+package sse
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// recordingBroadcaster records what the fan-out hands to local clients
+type recordingBroadcaster struct {
+	mutex sync.Mutex
+	calls []string
+}
+
+func (b *recordingBroadcaster) record(format string, args ...any) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.calls = append(b.calls, fmt.Sprintf(format, args...))
+}
+
+func (b *recordingBroadcaster) BroadcastToUsers(targetUsers []string, notification jsonrpcx.JsonRpcNotification) {
+	b.record("users %v %s", targetUsers, notification.Method)
+}
+
+func (b *recordingBroadcaster) BroadcastToAll(notification jsonrpcx.JsonRpcNotification) {
+	b.record("all %s", notification.Method)
+}
+
+func (b *recordingBroadcaster) BroadcastToAllExcept(excludedUsers []string, notification jsonrpcx.JsonRpcNotification) {
+	b.record("all except %v %s", excludedUsers, notification.Method)
+}
+
+func (b *recordingBroadcaster) Disconnect(userIDs []string) {
+	b.record("disconnect %v", userIDs)
+}
+
+// fakeRedis answers the commands of a go-redis client over in-memory connections, recording
+// the messages published
+type fakeRedis struct {
+	mutex     sync.Mutex
+	published map[string][]string
+}
+
+func (f *fakeRedis) client() *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Protocol: 2,
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			server, client := net.Pipe()
+			go f.serve(server)
+			return client, nil
+		},
+	})
+}
+
+func (f *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+
+	for {
+		args, err := readCommand(reader)
+		if err != nil {
+			return
+		}
+
+		reply := "+OK\r\n"
+		switch strings.ToUpper(args[0]) {
+		case "HELLO":
+			reply = "-ERR unknown command 'HELLO'\r\n"
+		case "PUBLISH":
+			f.mutex.Lock()
+			f.published[args[1]] = append(f.published[args[1]], args[2])
+			f.mutex.Unlock()
+			reply = ":1\r\n"
+		}
+		if _, err := io.WriteString(conn, reply); err != nil {
+			return
+		}
+	}
+}
+
+// readCommand reads one command sent as a RESP array of bulk strings
+func readCommand(reader *bufio.Reader) ([]string, error) {
+	header, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(header, "*")))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		length, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(length, "$")))
+		if err != nil {
+			return nil, err
+		}
+		data := make([]byte, size+2) // With the trailing CRLF
+		if _, err := io.ReadFull(reader, data); err != nil {
+			return nil, err
+		}
+		args[i] = string(data[:size])
+	}
+	return args, nil
+}
+
+func TestRedisFanout_Publish(t *testing.T) {
+	server := &fakeRedis{published: map[string][]string{}}
+	client := server.client()
+	defer client.Close()
+	local := &recordingBroadcaster{}
+	fanout := NewRedisFanout(logger.NewDefault(), client, local, nil)
+
+	fanout.BroadcastToUsers([]string{"alice"}, jsonrpcx.JsonRpcNotification{Method: "trainer.moved"})
+	fanout.BroadcastToAllExcept([]string{"bob"}, jsonrpcx.JsonRpcNotification{Method: "chat.message"})
+	fanout.Disconnect([]string{"carol"})
+
+	assert.Empty(t, local.calls, "instances deliver what they receive from the channel, this one included")
+	require.Len(t, server.published[FanoutChannel], 3)
+
+	var messages []fanoutMessage
+	for _, payload := range server.published[FanoutChannel] {
+		var msg fanoutMessage
+		require.NoError(t, json.Unmarshal([]byte(payload), &msg))
+		messages = append(messages, msg)
+	}
+	assert.Equal(t, []string{"alice"}, messages[0].TargetUsers)
+	assert.Equal(t, "trainer.moved", messages[0].Notification.Method)
+	assert.Equal(t, []string{"bob"}, messages[1].ExcludeUsers)
+	assert.True(t, messages[2].Disconnect)
+
+	// What the receiving instances hand to their clients
+	for _, msg := range messages {
+		fanout.deliver(msg)
+	}
+	assert.Equal(t, []string{
+		"users [alice] trainer.moved",
+		"all except [bob] chat.message",
+		"disconnect [carol]",
+	}, local.calls)
+}
+
+func TestRedisFanout_LocalFallback(t *testing.T) {
+	client := redis.NewClient(&redis.Options{
+		Dialer: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return nil, fmt.Errorf("connection refused")
+		},
+		MaxRetries: -1,
+	})
+	defer client.Close()
+	local := &recordingBroadcaster{}
+	fanout := NewRedisFanout(logger.NewDefault(), client, local, nil)
+
+	fanout.BroadcastToUsers([]string{"alice"}, jsonrpcx.JsonRpcNotification{Method: "trainer.moved"})
+	fanout.BroadcastToAll(jsonrpcx.JsonRpcNotification{Method: "world.time"})
+
+	assert.Equal(t, []string{
+		"users [alice] trainer.moved",
+		"all world.time",
+	}, local.calls, "clients on this instance are still reached while Redis is down")
+}
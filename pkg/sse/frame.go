@@ -0,0 +1,70 @@
+package sse
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/danghamo/life/pkg/jsonx"
+)
+
+// maxPooledFrame is the largest buffer returned to the pool, so one huge event doesn't pin
+// its memory for the life of the process
+const maxPooledFrame = 64 << 10
+
+var framePool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// encodeFrame encodes a value once as a complete SSE event ("data: <json>\n\n"), ready to be
+// written as is to every client. A non-zero id is sent as the event's ID for the client to
+// resume from. Release it with releaseFrame after the last write.
+func encodeFrame(id uint64, v any) (*bytes.Buffer, error) {
+	frame := framePool.Get().(*bytes.Buffer)
+	frame.Reset()
+	if id != 0 {
+		frame.WriteString("id: ")
+		frame.Write(strconv.AppendUint(frame.AvailableBuffer(), id, 10))
+		frame.WriteByte('\n')
+	}
+	frame.WriteString("data: ")
+
+	// Encode ends the JSON with a newline; a second one ends the event
+	if err := jsonx.Encode(frame, v); err != nil {
+		releaseFrame(frame)
+		return nil, err
+	}
+	frame.WriteByte('\n')
+
+	return frame, nil
+}
+
+// releaseFrame returns a frame's buffer to the pool. Its bytes must not be used afterwards.
+func releaseFrame(frame *bytes.Buffer) {
+	if frame.Cap() > maxPooledFrame {
+		return
+	}
+	framePool.Put(frame)
+}
+
+// heartbeatFrame encodes a heartbeat event into a pooled buffer, with latency as its latency
+// field unless it is nil
+func heartbeatFrame(now time.Time, latency any) (*bytes.Buffer, error) {
+	frame := framePool.Get().(*bytes.Buffer)
+	frame.Reset()
+	frame.WriteString(`data: {"type":"heartbeat","timestamp":"`)
+	frame.Write(now.AppendFormat(frame.AvailableBuffer(), time.RFC3339))
+	frame.WriteByte('"')
+	if latency != nil {
+		data, err := jsonx.Marshal(latency)
+		if err != nil {
+			releaseFrame(frame)
+			return nil, err
+		}
+		frame.WriteString(`,"latency":`)
+		frame.Write(data)
+	}
+	frame.WriteString("}\n\n")
+	return frame, nil
+}
@@ -0,0 +1,165 @@
+package sse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+)
+
+// ReplayConfig holds how many notifications are kept for users to catch up on after a
+// reconnect
+type ReplayConfig struct {
+	Size        int           // Notifications kept per user; 0 disables replay
+	TTL         time.Duration // How long a user's notifications are kept after the last one
+	SkipMethods []string      // Notifications that are stale by the time a client reconnects
+}
+
+// ReplayEvent is a buffered notification with its event ID
+type ReplayEvent struct {
+	ID   uint64
+	Data json.RawMessage // The encoded notification
+}
+
+// appendEvent numbers a notification with the next ID of KEYS[1] and appends it to the stream
+// at KEYS[2], trimmed to ARGV[2] entries and kept for ARGV[3] milliseconds. A counter that
+// starts over means the stream outlived it, so its entries are dropped first.
+var appendEvent = redis.NewScript(`
+local seq = redis.call('INCR', KEYS[1])
+if seq == 1 then
+	redis.call('DEL', KEYS[2])
+end
+redis.call('XADD', KEYS[2], 'MAXLEN', ARGV[2], seq .. '-0', 'n', ARGV[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+redis.call('PEXPIRE', KEYS[2], ARGV[3])
+return seq
+`)
+
+// ReplayBuffer numbers the notifications sent to each user and keeps the latest of them in a
+// Redis stream per user, so a client that reconnects with the last event ID it saw gets what
+// it missed. Only notifications sent to specific users are kept; broadcasts to everyone carry
+// no ID.
+type ReplayBuffer struct {
+	client *redis.Client
+	config ReplayConfig
+	skip   map[string]bool
+}
+
+// NewReplayBuffer creates a replay buffer, or returns nil when replay is disabled. A nil
+// buffer records nothing and has nothing to replay.
+func NewReplayBuffer(client *redis.Client, config ReplayConfig) *ReplayBuffer {
+	if config.Size <= 0 {
+		return nil
+	}
+
+	skip := make(map[string]bool, len(config.SkipMethods))
+	for _, method := range config.SkipMethods {
+		skip[method] = true
+	}
+	return &ReplayBuffer{client: client, config: config, skip: skip}
+}
+
+// Record assigns the notification the next event ID of each user and buffers it, returning
+// the IDs by user. Notifications of skipped methods get no ID.
+func (b *ReplayBuffer) Record(ctx context.Context, userIDs []string, notification jsonrpcx.JsonRpcNotification) (map[string]uint64, error) {
+	if b == nil || len(userIDs) == 0 || b.skip[notification.Method] {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(notification)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal notification: %w", err)
+	}
+
+	pipe := b.client.Pipeline()
+	results := make([]*redis.Cmd, len(userIDs))
+	for i, userID := range userIDs {
+		keys := []string{seqKey(userID), streamKey(userID)}
+		results[i] = appendEvent.Eval(ctx, pipe, keys, data, b.config.Size, b.config.TTL.Milliseconds())
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to buffer notification: %w", err)
+	}
+
+	ids := make(map[string]uint64, len(userIDs))
+	for i, userID := range userIDs {
+		id, err := results[i].Uint64()
+		if err != nil {
+			return nil, fmt.Errorf("failed to buffer notification: %w", err)
+		}
+		ids[userID] = id
+	}
+	return ids, nil
+}
+
+// Since returns the buffered notifications of a user after lastID, in order, along with the
+// user's latest event ID. Complete is false when some notifications after lastID are no
+// longer buffered, in which case the client should reload its state.
+func (b *ReplayBuffer) Since(ctx context.Context, userID string, lastID uint64) (events []ReplayEvent, latest uint64, complete bool, err error) {
+	if b == nil {
+		return nil, 0, false, nil
+	}
+
+	pipe := b.client.TxPipeline()
+	latestCmd := pipe.Get(ctx, seqKey(userID))
+	rangeCmd := pipe.XRange(ctx, streamKey(userID), fmt.Sprintf("%d-0", lastID+1), "+")
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return nil, 0, false, fmt.Errorf("failed to read buffered notifications: %w", err)
+	}
+
+	latest, err = latestCmd.Uint64()
+	if err != nil && err != redis.Nil {
+		return nil, 0, false, fmt.Errorf("failed to read latest event ID: %w", err)
+	}
+
+	from := lastID + 1
+	messages := rangeCmd.Val()
+	restarted := lastID > latest
+	if restarted {
+		// The buffer expired since and the user's IDs started over, so everything buffered
+		// is newer than lastID and what came in between is gone
+		from = 1
+		messages, err = b.client.XRange(ctx, streamKey(userID), "-", "+").Result()
+		if err != nil {
+			return nil, 0, false, fmt.Errorf("failed to read buffered notifications: %w", err)
+		}
+	}
+
+	events = make([]ReplayEvent, 0, len(messages))
+	for _, message := range messages {
+		id, err := eventID(message.ID)
+		if err != nil {
+			return nil, 0, false, err
+		}
+		data, _ := message.Values["n"].(string)
+		events = append(events, ReplayEvent{ID: id, Data: json.RawMessage(data)})
+	}
+
+	// Nothing is missing when the buffer picks up right where the client left off
+	complete = !restarted && (latest < from || (len(events) > 0 && events[0].ID == from))
+	return events, latest, complete, nil
+}
+
+// eventID returns the event ID of a stream entry ID ("<id>-0")
+func eventID(streamID string) (uint64, error) {
+	streamID, _, _ = strings.Cut(streamID, "-")
+	id, err := strconv.ParseUint(streamID, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid buffered event ID %q: %w", streamID, err)
+	}
+	return id, nil
+}
+
+func seqKey(userID string) string {
+	return "sse:replay:seq:" + userID
+}
+
+func streamKey(userID string) string {
+	return "sse:replay:events:" + userID
+}
@@ -0,0 +1,213 @@
+// Package tenant separates the logical games served by one server. Every tenant but the
+// default one has its Redis keys and event streams under its own prefix, and can override
+// the server's branding and balance settings.
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ID identifies a tenant
+type ID string
+
+// Default is the tenant of requests no other tenant claims. Its keys are not prefixed, so a
+// server without tenants stores data exactly as before.
+const Default ID = ""
+
+// validID keeps IDs safe to embed in Redis keys and hostnames
+var validID = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,31}$`)
+
+// String returns the ID as a string
+func (id ID) String() string {
+	return string(id)
+}
+
+// Prefix returns the prefix of the tenant's Redis keys and event topics
+func (id ID) Prefix() string {
+	if id == Default {
+		return ""
+	}
+	return "t:" + string(id) + ":"
+}
+
+// Key namespaces a Redis key or topic for the tenant
+func (id ID) Key(key string) string {
+	return id.Prefix() + key
+}
+
+// Split separates a namespaced key into the tenant it belongs to and the key within the
+// tenant. Keys without a tenant prefix belong to the default tenant.
+func Split(key string) (ID, string) {
+	rest, ok := strings.CutPrefix(key, "t:")
+	if !ok {
+		return Default, key
+	}
+	id, key, ok := strings.Cut(rest, ":")
+	if !ok || !validID.MatchString(id) {
+		return Default, "t:" + rest
+	}
+	return ID(id), key
+}
+
+// Branding is how a tenant presents itself to players
+type Branding struct {
+	Name         string `json:"name"`
+	LogoURL      string `json:"logo_url,omitempty"`
+	PrimaryColor string `json:"primary_color,omitempty"`
+	SupportEmail string `json:"support_email,omitempty"`
+}
+
+// Balance holds the game settings a tenant may override
+type Balance struct {
+	LootDeliveryMode string // inventory or pickup
+	InviteBaseURL    string // Page referral invitation links point at
+}
+
+// Tenant is one logical game
+type Tenant struct {
+	ID       ID
+	Hosts    []string // Hostnames whose requests belong to the tenant
+	Branding Branding
+	Balance  Balance
+}
+
+// Registry holds the tenants a server serves
+type Registry struct {
+	base    Tenant
+	tenants map[ID]*Tenant
+	hosts   map[string]*Tenant
+}
+
+// NewRegistry creates a registry of tenants on top of the default tenant. Branding and
+// balance settings a tenant leaves empty are taken from the default tenant.
+func NewRegistry(base Tenant, tenants []Tenant) (*Registry, error) {
+	base.ID = Default
+	r := &Registry{
+		base:    base,
+		tenants: make(map[ID]*Tenant, len(tenants)+1),
+		hosts:   make(map[string]*Tenant),
+	}
+	r.tenants[Default] = &r.base
+
+	for i := range tenants {
+		t := tenants[i]
+		if !validID.MatchString(t.ID.String()) {
+			return nil, fmt.Errorf("tenant ID %q must be 1-32 lowercase letters, digits or dashes", t.ID)
+		}
+		if _, ok := r.tenants[t.ID]; ok {
+			return nil, fmt.Errorf("tenant %q is configured twice", t.ID)
+		}
+		t.Branding = inheritBranding(t.Branding, base.Branding)
+		t.Balance = inheritBalance(t.Balance, base.Balance)
+		r.tenants[t.ID] = &t
+	}
+
+	for _, t := range r.tenants {
+		for _, host := range t.Hosts {
+			host = normalizeHost(host)
+			if other, ok := r.hosts[host]; ok {
+				return nil, fmt.Errorf("host %q belongs to both tenant %q and %q", host, other.ID, t.ID)
+			}
+			r.hosts[host] = t
+		}
+	}
+
+	return r, nil
+}
+
+// Get returns a tenant by ID
+func (r *Registry) Get(id ID) (*Tenant, bool) {
+	if r == nil {
+		return nil, false
+	}
+	t, ok := r.tenants[id]
+	return t, ok
+}
+
+// Resolve returns the tenant a hostname belongs to. The port, if any, is ignored.
+func (r *Registry) Resolve(host string) (*Tenant, bool) {
+	if r == nil {
+		return nil, false
+	}
+	t, ok := r.hosts[normalizeHost(host)]
+	return t, ok
+}
+
+// List returns every tenant, the default tenant first and the others by ID
+func (r *Registry) List() []*Tenant {
+	if r == nil {
+		return nil
+	}
+
+	list := make([]*Tenant, 0, len(r.tenants))
+	for _, t := range r.tenants {
+		list = append(list, t)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// normalizeHost lowercases a hostname and strips its port
+func normalizeHost(host string) string {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+// inheritBranding fills the fields a tenant left empty from the default branding
+func inheritBranding(b, base Branding) Branding {
+	if b.Name == "" {
+		b.Name = base.Name
+	}
+	if b.LogoURL == "" {
+		b.LogoURL = base.LogoURL
+	}
+	if b.PrimaryColor == "" {
+		b.PrimaryColor = base.PrimaryColor
+	}
+	if b.SupportEmail == "" {
+		b.SupportEmail = base.SupportEmail
+	}
+	return b
+}
+
+// inheritBalance fills the settings a tenant left empty from the default balance
+func inheritBalance(b, base Balance) Balance {
+	if b.LootDeliveryMode == "" {
+		b.LootDeliveryMode = base.LootDeliveryMode
+	}
+	if b.InviteBaseURL == "" {
+		b.InviteBaseURL = base.InviteBaseURL
+	}
+	return b
+}
+
+// contextKey is the context key of the request's tenant
+type contextKey struct{}
+
+// WithTenant returns a context whose Redis commands and events belong to t
+func WithTenant(ctx context.Context, t *Tenant) context.Context {
+	return context.WithValue(ctx, contextKey{}, t)
+}
+
+// FromContext returns the tenant a context was scoped to, if any
+func FromContext(ctx context.Context) (*Tenant, bool) {
+	t, ok := ctx.Value(contextKey{}).(*Tenant)
+	return t, ok && t != nil
+}
+
+// IDFromContext returns the ID of the context's tenant, Default if it has none
+func IDFromContext(ctx context.Context) ID {
+	if t, ok := FromContext(ctx); ok {
+		return t.ID
+	}
+	return Default
+}
@@ -0,0 +1,71 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRegistry(t *testing.T) {
+	base := Tenant{
+		Branding: Branding{Name: "Life", SupportEmail: "help@life.example"},
+		Balance:  Balance{LootDeliveryMode: "inventory", InviteBaseURL: "https://life.example/"},
+	}
+	r, err := NewRegistry(base, []Tenant{{
+		ID:       "acme",
+		Hosts:    []string{"Play.Acme.example"},
+		Branding: Branding{Name: "Acme Safari"},
+		Balance:  Balance{LootDeliveryMode: "pickup"},
+	}})
+	require.NoError(t, err)
+
+	acme, ok := r.Resolve("play.acme.example:8080")
+	require.True(t, ok)
+	assert.Equal(t, ID("acme"), acme.ID)
+	assert.Equal(t, "Acme Safari", acme.Branding.Name)
+	assert.Equal(t, "help@life.example", acme.Branding.SupportEmail)
+	assert.Equal(t, "pickup", acme.Balance.LootDeliveryMode)
+	assert.Equal(t, "https://life.example/", acme.Balance.InviteBaseURL)
+
+	_, ok = r.Resolve("life.example")
+	assert.False(t, ok)
+
+	list := r.List()
+	require.Len(t, list, 2)
+	assert.Equal(t, Default, list[0].ID)
+	assert.Equal(t, ID("acme"), list[1].ID)
+}
+
+func TestNewRegistry_Invalid(t *testing.T) {
+	_, err := NewRegistry(Tenant{}, []Tenant{{ID: "Acme:1"}})
+	assert.Error(t, err)
+
+	_, err = NewRegistry(Tenant{}, []Tenant{{ID: "acme"}, {ID: "acme"}})
+	assert.Error(t, err)
+
+	_, err = NewRegistry(Tenant{}, []Tenant{
+		{ID: "acme", Hosts: []string{"play.example"}},
+		{ID: "zoo", Hosts: []string{"PLAY.example"}},
+	})
+	assert.Error(t, err)
+}
+
+func TestSplit(t *testing.T) {
+	id, key := Split(ID("acme").Key("trainer:1"))
+	assert.Equal(t, ID("acme"), id)
+	assert.Equal(t, "trainer:1", key)
+
+	id, key = Split("trainer:1")
+	assert.Equal(t, Default, id)
+	assert.Equal(t, "trainer:1", key)
+}
+
+func TestIDFromContext(t *testing.T) {
+	assert.Equal(t, Default, IDFromContext(context.Background()))
+
+	ctx := WithTenant(context.Background(), &Tenant{ID: "acme"})
+	assert.Equal(t, ID("acme"), IDFromContext(ctx))
+	assert.Equal(t, "t:acme:", IDFromContext(ctx).Prefix())
+}
@@ -0,0 +1,137 @@
+// Package wal provides a local append-only write-ahead log of JSON records. It holds work that
+// can't reach its store yet, e.g. writes queued while Redis is down, so it survives a restart.
+package wal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Log is a write-ahead log backed by a file of newline-delimited JSON records
+type Log struct {
+	mu    sync.Mutex
+	file  *os.File
+	count int
+}
+
+// Open opens the log at path, creating it and its directory if needed
+func Open(path string) (*Log, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create WAL directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open WAL: %w", err)
+	}
+
+	l := &Log{file: file}
+	records, end, err := l.read()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	// Cut off an append that never completed so the next record starts on its own line
+	if err := file.Truncate(end); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to repair WAL: %w", err)
+	}
+	l.count = len(records)
+
+	return l, nil
+}
+
+// Append writes a record and syncs it to disk before returning
+func (l *Log) Append(record any) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal WAL record: %w", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write WAL record: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync WAL: %w", err)
+	}
+
+	l.count++
+	return nil
+}
+
+// Len returns the number of records in the log
+func (l *Log) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.count
+}
+
+// Drain returns every record in append order and empties the log. A record cut short by a
+// crash mid-append is dropped.
+func (l *Log) Drain() ([]json.RawMessage, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	records, _, err := l.read()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := l.file.Truncate(0); err != nil {
+		return nil, fmt.Errorf("failed to truncate WAL: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return nil, fmt.Errorf("failed to sync WAL: %w", err)
+	}
+
+	l.count = 0
+	return records, nil
+}
+
+// Close closes the log file
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.file.Close()
+}
+
+// read parses the log from the start and returns its records with the offset after the last
+// complete line. Callers hold the lock or own the log exclusively.
+func (l *Log) read() ([]json.RawMessage, int64, error) {
+	if _, err := l.file.Seek(0, io.SeekStart); err != nil {
+		return nil, 0, fmt.Errorf("failed to read WAL: %w", err)
+	}
+
+	var records []json.RawMessage
+	var end int64
+	reader := bufio.NewReader(l.file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// Anything after the last newline is an append that never completed
+			return records, end, nil
+		}
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to read WAL: %w", err)
+		}
+		end += int64(len(line))
+
+		line = bytes.TrimSpace(line)
+		if !json.Valid(line) {
+			continue
+		}
+		records = append(records, json.RawMessage(line))
+	}
+}
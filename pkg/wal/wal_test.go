@@ -0,0 +1,50 @@
+package wal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "queue", "writes.wal")
+
+	log, err := Open(path)
+	require.NoError(t, err)
+	require.NoError(t, log.Append(map[string]int{"n": 1}))
+	require.NoError(t, log.Append(map[string]int{"n": 2}))
+	require.NoError(t, log.Close())
+
+	// A crash halfway through an append leaves a partial last line
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"n":`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	log, err = Open(path)
+	require.NoError(t, err)
+	defer log.Close()
+	assert.Equal(t, 2, log.Len(), "records survive a restart")
+
+	records, err := log.Drain()
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.JSONEq(t, `{"n":1}`, string(records[0]))
+	assert.JSONEq(t, `{"n":2}`, string(records[1]))
+
+	assert.Equal(t, 0, log.Len())
+	require.NoError(t, log.Append(map[string]int{"n": 3}))
+	require.NoError(t, log.Close())
+
+	log, err = Open(path)
+	require.NoError(t, err)
+	defer log.Close()
+	records, err = log.Drain()
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.JSONEq(t, `{"n":3}`, string(records[0]))
+}
@@ -0,0 +1,438 @@
+package ws
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+const (
+	sendBufferSize    = 256              // Queued outgoing messages per client
+	heartbeatInterval = 30 * time.Second // Keeps idle connections and proxies alive
+)
+
+// Client represents a connected WebSocket client
+type Client struct {
+	ID        string
+	UserID    string
+	conn      *websocket.Conn
+	send      chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// close stops the client's write loop and closes the connection once
+func (c *Client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// Hub manages WebSocket connections. It pushes the same JSON-RPC notifications as the
+// SSE broadcaster and dispatches incoming JSON-RPC commands to registered HTTP handlers.
+type Hub struct {
+	logger       *logger.Logger
+	clients      map[string]*Client
+	userClients  map[string][]*Client // Map userID to their clients
+	methods      map[string]http.Handler
+	onConnect    func(userID string)     // Called when a user's first client connects
+	onDisconnect func(userID string)     // Called when a user's last client leaves
+	latency      func(userID string) any // What heartbeats tell a user about their connection
+	mutex        sync.RWMutex
+	shutdown     chan struct{}
+	closeOnce    sync.Once
+}
+
+// NewHub creates a new WebSocket hub
+func NewHub(logger *logger.Logger) *Hub {
+	return &Hub{
+		logger:      logger.WithComponent("ws-hub"),
+		clients:     make(map[string]*Client),
+		userClients: make(map[string][]*Client),
+		methods:     make(map[string]http.Handler),
+		shutdown:    make(chan struct{}),
+	}
+}
+
+// Handle registers a JSON-RPC method that clients may call over the socket.
+// The handler is the same one served over HTTP; errors it reports are converted
+// to JSON-RPC error responses like on the HTTP path.
+func (h *Hub) Handle(method string, handler http.HandlerFunc) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.methods[method] = middleware.ErrorAdapter(h.logger)(handler)
+}
+
+// OnConnect registers a function called when a user's first client connects
+func (h *Hub) OnConnect(fn func(userID string)) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.onConnect = fn
+}
+
+// OnDisconnect registers a function called when a user's last client disconnects
+func (h *Hub) OnDisconnect(fn func(userID string)) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.onDisconnect = fn
+}
+
+// SetHeartbeatLatency sets what heartbeats tell a user about their connection, sent as the
+// heartbeat's latency field
+func (h *Hub) SetHeartbeatLatency(fn func(userID string) any) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.latency = fn
+}
+
+// IsConnected reports whether a user has a connected client
+func (h *Hub) IsConnected(userID string) bool {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return len(h.userClients[userID]) > 0
+}
+
+// BroadcastToAll sends a JSON-RPC notification to all connected clients
+func (h *Hub) BroadcastToAll(notification jsonrpcx.JsonRpcNotification) {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		h.logger.Error("Failed to marshal JSON-RPC notification", zap.Error(err))
+		return
+	}
+
+	h.mutex.RLock()
+	clients := make([]*Client, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.mutex.RUnlock()
+
+	for _, client := range clients {
+		h.enqueue(client, data)
+	}
+}
+
+// BroadcastToAllExcept sends a JSON-RPC notification to all connected clients but those of
+// the excluded users
+func (h *Hub) BroadcastToAllExcept(excludedUsers []string, notification jsonrpcx.JsonRpcNotification) {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		h.logger.Error("Failed to marshal JSON-RPC notification", zap.Error(err))
+		return
+	}
+
+	h.mutex.RLock()
+	clients := make([]*Client, 0, len(h.clients))
+	for _, client := range h.clients {
+		if !slices.Contains(excludedUsers, client.UserID) {
+			clients = append(clients, client)
+		}
+	}
+	h.mutex.RUnlock()
+
+	for _, client := range clients {
+		h.enqueue(client, data)
+	}
+}
+
+// BroadcastToUsers sends a JSON-RPC notification to specific users (only if they are connected to this server)
+func (h *Hub) BroadcastToUsers(targetUsers []string, notification jsonrpcx.JsonRpcNotification) {
+	if len(targetUsers) == 0 {
+		return
+	}
+
+	h.mutex.RLock()
+	clients := make([]*Client, 0)
+	for _, userID := range targetUsers {
+		clients = append(clients, h.userClients[userID]...)
+	}
+	h.mutex.RUnlock()
+
+	if len(clients) == 0 {
+		return
+	}
+
+	data, err := json.Marshal(notification)
+	if err != nil {
+		h.logger.Error("Failed to marshal user notification", zap.Error(err))
+		return
+	}
+
+	for _, client := range clients {
+		h.enqueue(client, data)
+	}
+}
+
+// Disconnect closes the connections of the users to this hub; their read loops then
+// unregister them
+func (h *Hub) Disconnect(userIDs []string) {
+	h.mutex.RLock()
+	clients := make([]*Client, 0)
+	for _, userID := range userIDs {
+		clients = append(clients, h.userClients[userID]...)
+	}
+	h.mutex.RUnlock()
+
+	for _, client := range clients {
+		client.close()
+	}
+}
+
+// GetClientCount returns the number of connected clients
+func (h *Hub) GetClientCount() int {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	return len(h.clients)
+}
+
+// Close disconnects all clients
+func (h *Hub) Close() {
+	h.closeOnce.Do(func() {
+		close(h.shutdown)
+	})
+
+	h.mutex.Lock()
+	clients := make([]*Client, 0, len(h.clients))
+	for _, client := range h.clients {
+		clients = append(clients, client)
+	}
+	h.clients = make(map[string]*Client)
+	h.userClients = make(map[string][]*Client)
+	h.mutex.Unlock()
+
+	for _, client := range clients {
+		client.close()
+	}
+
+	h.logger.Debug("WebSocket hub shutdown complete")
+}
+
+// HandleWebSocket upgrades an authenticated request to a WebSocket connection
+func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
+	userID, ok := middleware.GetUserID(r.Context())
+	if !ok {
+		http.Error(w, "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	server := websocket.Server{
+		// Origin is not checked; the connection is authenticated by a token the client sends
+		// itself, never by cookie, so other sites can't connect as the player
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			h.serveConn(conn, r, userID)
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+// serveConn runs a connected client until it disconnects
+func (h *Hub) serveConn(conn *websocket.Conn, r *http.Request, userID string) {
+	// The HTTP server's read/write timeouts must not apply to a long-lived socket
+	conn.SetDeadline(time.Time{})
+
+	client := &Client{
+		ID:     fmt.Sprintf("%s-%d", userID, time.Now().UnixNano()),
+		UserID: userID,
+		conn:   conn,
+		send:   make(chan []byte, sendBufferSize),
+		done:   make(chan struct{}),
+	}
+
+	h.addClient(client)
+	defer h.removeClient(client)
+
+	go h.writeLoop(client)
+
+	connected, _ := json.Marshal(map[string]string{"type": "connected", "client_id": client.ID})
+	h.enqueue(client, connected)
+
+	for {
+		var data []byte
+		if err := websocket.Message.Receive(conn, &data); err != nil {
+			h.logger.Debug("WebSocket client disconnected",
+				zap.String("clientId", client.ID),
+				zap.Error(err))
+			return
+		}
+
+		if response := h.dispatch(r, data); response != nil {
+			h.enqueue(client, response)
+		}
+	}
+}
+
+// dispatch runs a JSON-RPC command through its registered handler and returns the response
+func (h *Hub) dispatch(r *http.Request, data []byte) []byte {
+	var req jsonrpcx.Request
+	if err := json.Unmarshal(data, &req); err != nil || req.JSONRPC != "2.0" {
+		return h.errorResponse(nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+	}
+
+	h.mutex.RLock()
+	handler, exists := h.methods[req.Method]
+	h.mutex.RUnlock()
+
+	if !exists {
+		return h.errorResponse(req.ID, jsonrpcx.MethodNotFound, "Method not found")
+	}
+
+	// Run the HTTP handler in-process with the socket's authenticated context
+	cmd, err := http.NewRequestWithContext(r.Context(), http.MethodPost, "/api/v1/"+req.Method, bytes.NewReader(data))
+	if err != nil {
+		return h.errorResponse(req.ID, jsonrpcx.InternalError, "Internal error")
+	}
+	cmd.Header.Set("Content-Type", "application/json")
+
+	rec := newResponseBuffer()
+	handler.ServeHTTP(rec, cmd)
+
+	return bytes.TrimSpace(rec.body.Bytes())
+}
+
+// errorResponse builds a JSON-RPC error response
+func (h *Hub) errorResponse(id any, code int, message string) []byte {
+	data, _ := json.Marshal(jsonrpcx.Response{
+		JSONRPC: "2.0",
+		Error:   &jsonrpcx.JSONRPCError{Code: code, Message: message},
+		ID:      id,
+	})
+	return data
+}
+
+// writeLoop is the only writer to the connection
+func (h *Hub) writeLoop(client *Client) {
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-client.done:
+			return
+		case <-h.shutdown:
+			client.close()
+			return
+		case data := <-client.send:
+			if err := websocket.Message.Send(client.conn, string(data)); err != nil {
+				h.logger.Warn("Failed to send to WebSocket client",
+					zap.String("clientId", client.ID),
+					zap.Error(err))
+				client.close()
+				return
+			}
+		case <-heartbeat.C:
+			if err := websocket.Message.Send(client.conn, h.heartbeat(client.UserID)); err != nil {
+				client.close()
+				return
+			}
+		}
+	}
+}
+
+// heartbeat returns the heartbeat message for a user's client
+func (h *Hub) heartbeat(userID string) string {
+	h.mutex.RLock()
+	latencyOf := h.latency
+	h.mutex.RUnlock()
+
+	msg := map[string]any{"type": "heartbeat", "timestamp": time.Now().Format(time.RFC3339)}
+	if latencyOf != nil {
+		msg["latency"] = latencyOf(userID)
+	}
+	data, err := json.Marshal(msg)
+	if err != nil {
+		h.logger.Warn("Failed to marshal heartbeat", zap.String("userId", userID), zap.Error(err))
+		data, _ = json.Marshal(map[string]any{"type": "heartbeat", "timestamp": msg["timestamp"]})
+	}
+	return string(data)
+}
+
+// enqueue queues data for a client without blocking the caller
+func (h *Hub) enqueue(client *Client, data []byte) {
+	select {
+	case <-client.done:
+	case client.send <- data:
+	default:
+		h.logger.Warn("WebSocket send buffer full, dropping message",
+			zap.String("clientId", client.ID))
+	}
+}
+
+// addClient registers a connected client
+func (h *Hub) addClient(client *Client) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	h.clients[client.ID] = client
+	h.userClients[client.UserID] = append(h.userClients[client.UserID], client)
+	if len(h.userClients[client.UserID]) == 1 && h.onConnect != nil {
+		go h.onConnect(client.UserID)
+	}
+
+	h.logger.Debug("WebSocket client connected",
+		zap.String("clientId", client.ID),
+		zap.String("userId", client.UserID))
+}
+
+// removeClient unregisters and closes a client
+func (h *Hub) removeClient(client *Client) {
+	h.mutex.Lock()
+	delete(h.clients, client.ID)
+
+	userClients := h.userClients[client.UserID]
+	for i, uc := range userClients {
+		if uc.ID == client.ID {
+			h.userClients[client.UserID] = append(userClients[:i], userClients[i+1:]...)
+			break
+		}
+	}
+	if len(h.userClients[client.UserID]) == 0 {
+		delete(h.userClients, client.UserID)
+		if h.onDisconnect != nil {
+			go h.onDisconnect(client.UserID)
+		}
+	}
+	h.mutex.Unlock()
+
+	client.close()
+}
+
+// responseBuffer captures a handler's response for sending over the socket
+type responseBuffer struct {
+	header http.Header
+	body   bytes.Buffer
+	status int
+}
+
+// newResponseBuffer creates an empty response buffer
+func newResponseBuffer() *responseBuffer {
+	return &responseBuffer{header: make(http.Header), status: http.StatusOK}
+}
+
+// Header returns the response headers
+func (b *responseBuffer) Header() http.Header {
+	return b.header
+}
+
+// Write appends to the response body
+func (b *responseBuffer) Write(data []byte) (int, error) {
+	return b.body.Write(data)
+}
+
+// WriteHeader records the status code
+func (b *responseBuffer) WriteHeader(status int) {
+	b.status = status
+}
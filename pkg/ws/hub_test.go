@@ -0,0 +1,72 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/websocket"
+
+	"github.com/danghamo/life/internal/api/jsonrpcx"
+	"github.com/danghamo/life/internal/api/middleware"
+	"github.com/danghamo/life/pkg/logger"
+)
+
+// receive reads the next non-heartbeat message from the socket
+func receive(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	var data string
+	require.NoError(t, websocket.Message.Receive(conn, &data))
+
+	var msg map[string]any
+	require.NoError(t, json.Unmarshal([]byte(data), &msg))
+	return msg
+}
+
+func TestHub_CommandsAndNotifications(t *testing.T) {
+	hub := NewHub(logger.NewDefault())
+	defer hub.Close()
+
+	hub.Handle("test.Echo", func(w http.ResponseWriter, r *http.Request) {
+		userID, _ := middleware.GetUserID(r.Context())
+		req, err := jsonrpcx.ParseRequest(r)
+		if err != nil {
+			jsonrpcx.WithError(r, nil, jsonrpcx.ParseError, "Invalid JSON-RPC request")
+			return
+		}
+		jsonrpcx.Success(w, req.ID, map[string]string{"user_id": userID})
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), middleware.UserIDContextKey, "alice")
+		hub.HandleWebSocket(w, r.WithContext(ctx))
+	}))
+	defer srv.Close()
+
+	conn, err := websocket.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), "", srv.URL)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	assert.Equal(t, "connected", receive(t, conn)["type"])
+
+	require.NoError(t, websocket.Message.Send(conn, `{"jsonrpc":"2.0","method":"test.Echo","id":1}`))
+	resp := receive(t, conn)
+	assert.Equal(t, float64(1), resp["id"])
+	assert.Equal(t, "alice", resp["result"].(map[string]any)["user_id"])
+
+	require.NoError(t, websocket.Message.Send(conn, `{"jsonrpc":"2.0","method":"test.Missing","id":2}`))
+	resp = receive(t, conn)
+	assert.Equal(t, float64(jsonrpcx.MethodNotFound), resp["error"].(map[string]any)["code"])
+
+	hub.BroadcastToUsers([]string{"bob"}, jsonrpcx.JsonRpcNotification{Jsonrpc: "2.0", Method: "not.for.alice"})
+	hub.BroadcastToUsers([]string{"alice"}, jsonrpcx.JsonRpcNotification{Jsonrpc: "2.0", Method: "for.alice"})
+	assert.Equal(t, "for.alice", receive(t, conn)["method"])
+}
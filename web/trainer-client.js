@@ -44,6 +44,7 @@ let isCurrentlyMoving = false;
 // Movement update debouncing
 let movementUpdateTimeout = null;
 let serverNextAllowedAt = 0; // Server-provided timestamp when next request is allowed
+let lastMoveEcho = null; // server_time of the last move response and when it arrived, echoed to measure round trips
 const MOVEMENT_UPDATE_DELAY = 100; // 100ms debounce for smoother diagonal movement and reduced server load
 
 // Pressed keys tracking
@@ -269,7 +270,8 @@ async function move(dirX, dirY) {
                 params: {
                     direction_x: dirX,
                     direction_y: dirY,
-                    action: 'start'
+                    action: 'start',
+                    ...moveEchoParams()
                 },
                 id: Date.now()
             })
@@ -294,6 +296,7 @@ async function move(dirX, dirY) {
         if (result.result.next_request_allowed_at) {
             serverNextAllowedAt = result.result.next_request_allowed_at;
         }
+        recordMoveLatency(result.result.latency);
         
         showMessage(`Started moving ${dirX},${dirY}`, 'success');
         
@@ -302,6 +305,27 @@ async function move(dirX, dirY) {
     }
 }
 
+// Round trip measurement for the next move: the last response's server_time and how long we held it
+function moveEchoParams() {
+    if (!lastMoveEcho) return {};
+    return { echo: lastMoveEcho.serverTime, held_ms: Date.now() - lastMoveEcho.receivedAt };
+}
+
+// Remember a move response's latency report for the next move to echo, and show it
+function recordMoveLatency(latency) {
+    if (!latency) return;
+    lastMoveEcho = { serverTime: latency.server_time, receivedAt: Date.now() };
+    updateLatencyDisplay(latency);
+}
+
+// Show connection quality from a move response or heartbeat
+function updateLatencyDisplay(latency) {
+    const element = document.getElementById('latency');
+    if (!latency || !element) return;
+    const rtt = latency.rtt_estimate_ms ? `${Math.round(latency.rtt_estimate_ms)}ms` : '-';
+    element.textContent = `${rtt} (server ${latency.server_processing_ms.toFixed(1)}ms, broadcast age ${Math.round(latency.broadcast_age_ms)}ms)`;
+}
+
 async function stopMovement() {
     if (!authToken) {
         showMessage('Please authenticate first', 'error');
@@ -336,7 +360,8 @@ async function stopMovement() {
                 params: {
                     direction_x: 0,
                     direction_y: 0,
-                    action: 'stop'
+                    action: 'stop',
+                    ...moveEchoParams()
                 },
                 id: Date.now()
             })
@@ -361,6 +386,7 @@ async function stopMovement() {
         if (result.result.next_request_allowed_at) {
             serverNextAllowedAt = result.result.next_request_allowed_at;
         }
+        recordMoveLatency(result.result.latency);
         
         showMessage('Movement stopped', 'success');
         
@@ -369,6 +395,30 @@ async function stopMovement() {
     }
 }
 
+// Tell the server the player is watching, so they don't go AFK. Only sent while the game has
+// focus; a tab left in the background goes AFK after a while.
+async function acknowledgeNotifications() {
+    if (!authToken || !document.hasFocus()) return;
+    
+    try {
+        await fetch(buildApiUrl('/api/v1/idle.Ack'), {
+            method: 'POST',
+            headers: {
+                'Content-Type': 'application/json',
+                'Authorization': `Bearer ${authToken}`
+            },
+            body: JSON.stringify({
+                jsonrpc: '2.0',
+                method: 'idle.Ack',
+                params: {},
+                id: Date.now()
+            })
+        });
+    } catch (error) {
+        console.debug('Failed to acknowledge notifications:', error.message);
+    }
+}
+
 async function fetchPosition() {
     if (!authToken) {
         showMessage('Please authenticate first', 'error');
@@ -1108,6 +1158,8 @@ function handleSSEMessage(notification) {
                 return;
             case 'heartbeat':
                 console.debug('SSE heartbeat received at:', notification.timestamp);
+                updateLatencyDisplay(notification.latency);
+                acknowledgeNotifications();
                 return;
             default:
                 console.debug('Unknown SSE system message:', notification);
@@ -1138,6 +1190,18 @@ function handleSSEMessage(notification) {
             handleTrainerMovementStopped(notification.params, false); // isOwnUpdate = false
             break;
             
+        case 'trainer.positions.batch':
+            handlePositionsBatch(notification.params);
+            break;
+            
+        case 'trainer.afk':
+            if (notification.params.afk) {
+                showMessage('You are AFK until you play again.', 'info');
+            } else {
+                showMessage('Welcome back!', 'success');
+            }
+            break;
+            
         case 'trainer.created':
             handleTrainerCreated(notification.params);
             break;
@@ -1208,6 +1272,28 @@ function handleTrainerMovementStopped(params, isOwnUpdate) {
     }
 }
 
+// Handle a batch of moving trainers' positions from SSE. Movement and appearance are only
+// sent when they changed since the last batch, or in keyframes.
+function handlePositionsBatch(params) {
+    if (!params.trainers) return;
+    
+    params.trainers.forEach(delta => {
+        if (delta.u === getCurrentUserId()) return; // Our own position comes from our moves
+        
+        let otherTrainer = otherTrainers.get(delta.u) || {};
+        otherTrainer.position = { x: delta.x, y: delta.y };
+        if (delta.m) otherTrainer.movement = delta.m;
+        if (delta.a) {
+            otherTrainer.color = delta.a.color;
+            otherTrainer.showcase = delta.a.showcase || [];
+        }
+        otherTrainer.lastSeen = Date.now();
+        otherTrainers.set(delta.u, otherTrainer);
+    });
+    
+    updateOtherTrainersDisplay();
+}
+
 // Handle new trainer created
 function handleTrainerCreated(params) {
     if (!params.user_id || params.user_id === getCurrentUserId()) return;
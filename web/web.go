@@ -0,0 +1,14 @@
+// Package web holds the browser client, embedded so the server binary serves it without
+// the files next to it. Operators can serve another build from a directory instead; see
+// server.static_dir.
+package web
+
+import "embed"
+
+// FS holds the client assets
+//
+//go:embed trainer-client.html trainer-client.js
+var FS embed.FS
+
+// Index is the page served at /
+const Index = "trainer-client.html"